@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/bench"
+	"github.com/goclaw/goclaw/pkg/engine"
+	memstorage "github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// runBenchCommand handles "goclaw bench" and returns the exit code. Without
+// -in-process it loads the server at -url; workflows submitted over the API
+// have no task executors, so they only exercise submission and persistence.
+func runBenchCommand(args []string, stdout, stderr io.Writer) int {
+	defaults := bench.DefaultConfig()
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", *configPath, "Path to configuration file for -in-process (YAML, JSON or TOML)")
+	url := fs.String("url", "http://localhost:8080", "Address of the running server")
+	inProcess := fs.Bool("in-process", false, "Run workflows on an in-process engine instead of a server")
+	taskTime := fs.Duration("task-time", 10*time.Millisecond, "Duration of every task with -in-process")
+	layers := fs.Int("layers", defaults.Layers, "DAG layers per workflow")
+	width := fs.Int("width", defaults.Width, "Tasks per layer")
+	fanIn := fs.Int("fan-in", defaults.FanIn, "Dependencies of each task on the previous layer")
+	rate := fs.Float64("rate", defaults.Rate, "Workflows submitted per second")
+	duration := fs.Duration("duration", defaults.Duration, "How long to submit workflows")
+	maxInFlight := fs.Int("max-in-flight", defaults.MaxInFlight, "Maximum outstanding submissions (0 for unbounded)")
+	sampleInterval := fs.Duration("sample-interval", defaults.SampleInterval, "How often lane queues are sampled")
+	seed := fs.Int64("seed", defaults.Seed, "Seed for the generated dependencies")
+	timeout := fs.Duration("timeout", 0, "Per-request timeout (0 for none)")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := bench.Config{
+		Layers:         *layers,
+		Width:          *width,
+		FanIn:          *fanIn,
+		Rate:           *rate,
+		Duration:       *duration,
+		MaxInFlight:    *maxInFlight,
+		SampleInterval: *sampleInterval,
+		Seed:           *seed,
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "Invalid benchmark: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var target bench.Target = bench.NewHTTPTarget(*url, &http.Client{Timeout: *timeout})
+	if *inProcess {
+		appCfg, err := config.NewLoader().Load(*path, buildOverrides())
+		if err != nil {
+			fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+			return 1
+		}
+		eng, err := engine.New(appCfg, nil, memstorage.NewMemoryStorage())
+		if err == nil {
+			err = eng.Start(ctx)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Failed to start engine: %v\n", err)
+			return 1
+		}
+		defer eng.Stop(context.Background())
+		target = bench.NewEngineTarget(eng, *taskTime)
+	}
+
+	report, err := bench.Run(ctx, cfg, target)
+	if err != nil {
+		fmt.Fprintf(stderr, "Benchmark failed: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "Failed to encode report: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printBenchReport(stdout, cfg, report)
+	return 0
+}
+
+// printBenchReport writes report in human-readable form.
+func printBenchReport(w io.Writer, cfg bench.Config, report *bench.Report) {
+	fmt.Fprintf(w, "Workflows:  %d layers x %d tasks, fan-in %d, %.1f/s for %s\n", cfg.Layers, cfg.Width, cfg.FanIn, cfg.Rate, cfg.Duration)
+	fmt.Fprintf(w, "Submitted:  %d (completed %d, failed %d, accepted %d, errors %d, skipped %d)\n",
+		report.Submitted, report.Completed, report.Failed, report.Accepted, report.Errors, report.Skipped)
+	fmt.Fprintf(w, "Elapsed:    %.2fs\n", report.ElapsedSeconds)
+	fmt.Fprintf(w, "Throughput: %.2f workflows/s, %.2f tasks/s\n", report.Throughput, report.TaskThroughput)
+	fmt.Fprintf(w, "Latency:    mean %.1fms  p50 %.1fms  p90 %.1fms  p99 %.1fms  max %.1fms\n",
+		report.Latency.Mean, report.Latency.P50, report.Latency.P90, report.Latency.P99, report.Latency.Max)
+	for _, lane := range report.Lanes {
+		fmt.Fprintf(w, "Lane %-12s pending %d -> %d (peak %d), growth %+.2f/s\n",
+			lane.Name, lane.StartPending, lane.EndPending, lane.PeakPending, lane.GrowthPerSec)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+// runConfigCommand handles "goclaw config <schema|doctor>" and returns the exit code.
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "Usage: goclaw config <schema|doctor> [options]\n")
+		return 2
+	}
+
+	switch args[0] {
+	case "schema":
+		data, err := config.JSONSchema()
+		if err != nil {
+			fmt.Fprintf(stderr, "Failed to build config schema: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(data))
+		return 0
+	case "doctor":
+		return runConfigDoctor(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "Unknown config command %q (expected schema or doctor)\n", args[0])
+		return 2
+	}
+}
+
+// runConfigDoctor loads the configuration and checks it against live dependencies.
+func runConfigDoctor(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config doctor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", *configPath, "Path to configuration file (YAML, JSON or TOML)")
+	env := fs.String("env-file", *envFile, "Path to a .env file with GOCLAW_ variables")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	loader := config.NewLoader()
+	if *env != "" {
+		loader.SetEnvFile(*env)
+	}
+
+	report := &config.DoctorReport{}
+	cfg, err := loader.Load(*path, buildOverrides())
+	if err != nil {
+		report.Checks = append(report.Checks, config.DoctorCheck{
+			Name:    "config",
+			Status:  config.CheckFail,
+			Message: err.Error(),
+		})
+	} else {
+		report = config.Doctor(context.Background(), cfg)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "Failed to encode report: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, check := range report.Checks {
+			fmt.Fprintf(stdout, "[%s] %s: %s\n", check.Status, check.Name, check.Message)
+		}
+	}
+
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// runDrainCommand handles "goclaw drain", the pre-stop hook of a pod: it
+// asks the local server to drain and waits for the answer. It returns 0
+// once the server answered, even if workflows were still running.
+func runDrainCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("drain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	url := fs.String("url", "http://127.0.0.1:8080", "Loopback address of the local server")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// The server bounds the drain with server.http.drain_timeout and the
+	// kubelet with the termination grace period, so no client timeout.
+	resp, err := http.Post(strings.TrimSuffix(*url, "/")+"/drain", "application/json", nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "Drain failed: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		fmt.Fprintf(stderr, "Drain failed: status %d: %s\n", resp.StatusCode, strings.TrimSpace(string(body)))
+		return 1
+	}
+
+	var result models.DrainResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		fmt.Fprintf(stderr, "Drain failed: decode response: %v\n", err)
+		return 1
+	}
+	if result.Drained {
+		fmt.Fprintf(stdout, "Drained\n")
+	} else {
+		fmt.Fprintf(stdout, "Drain timed out with %d workflows running\n", result.RunningWorkflows)
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/importer"
+)
+
+// runImportCommand handles "goclaw import <file>": it converts an Argo
+// manifest or Temporal history into a workflow template and prints it, or
+// stores it through the management API of the server at -server.
+func runImportCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "", "Source format, argo or temporal (default: from the file extension)")
+	name := fs.String("name", "", "Template name (default: the imported workflow name)")
+	server := fs.String("server", "", "Store the template on this server instead of printing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(stderr, "Usage: goclaw import [-format argo|temporal] [-name N] [-server URL] <file>\n")
+		return 2
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		var err error
+		if *format, err = importer.DetectFormat(path); err != nil {
+			fmt.Fprintf(stderr, "%v; set -format\n", err)
+			return 2
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to read %s: %v\n", path, err)
+		return 1
+	}
+	result, err := importer.Import(*format, data)
+	if err != nil {
+		fmt.Fprintf(stderr, "Import failed: %v\n", err)
+		return 1
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(stderr, "Warning: %s\n", w)
+	}
+	if *name == "" {
+		*name = result.Workflow.Name
+	}
+
+	body, err := json.MarshalIndent(result.Workflow, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to encode template: %v\n", err)
+		return 1
+	}
+	if *server == "" {
+		fmt.Fprintf(stdout, "%s\n", body)
+		return 0
+	}
+
+	target := strings.TrimSuffix(*server, "/") + "/api/v1/manage/templates/" + url.PathEscape(*name)
+	req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to store template: %v\n", err)
+		return 1
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to store template: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		fmt.Fprintf(stderr, "Failed to store template: status %d: %s\n", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Stored template %s (version %s)\n", *name, strings.Trim(resp.Header.Get("ETag"), `"`))
+	return 0
+}
@@ -1,48 +1,47 @@
 package main
 
-// @title Goclaw API
-// @version 1.0
-// @description Production-grade, high-performance, distributed-ready multi-agent orchestration engine
-// @termsOfService http://swagger.io/terms/
-
-// @contact.name API Support
-// @contact.url https://github.com/goclaw/goclaw
-// @contact.email support@goclaw.io
-
-// @license.name Apache 2.0
-// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
-
-// @host localhost:8080
-// @BasePath /
-// @schemes http https
-
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	ossignal "os/signal"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/goclaw/goclaw"
 	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/agent"
 	"github.com/goclaw/goclaw/pkg/api"
 	"github.com/goclaw/goclaw/pkg/api/events"
 	"github.com/goclaw/goclaw/pkg/api/handlers"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/awsauth"
+	"github.com/goclaw/goclaw/pkg/container"
+	"github.com/goclaw/goclaw/pkg/dag"
 	"github.com/goclaw/goclaw/pkg/engine"
 	grpcpkg "github.com/goclaw/goclaw/pkg/grpc"
 	grpchandlers "github.com/goclaw/goclaw/pkg/grpc/handlers"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	grpcstreaming "github.com/goclaw/goclaw/pkg/grpc/streaming"
+	"github.com/goclaw/goclaw/pkg/kube"
 	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/llmcache"
 	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/manage"
 	memorypkg "github.com/goclaw/goclaw/pkg/memory"
 	"github.com/goclaw/goclaw/pkg/metrics"
+	"github.com/goclaw/goclaw/pkg/operator"
+	"github.com/goclaw/goclaw/pkg/rediskey"
+	"github.com/goclaw/goclaw/pkg/scrub"
 	signalpkg "github.com/goclaw/goclaw/pkg/signal"
+	"github.com/goclaw/goclaw/pkg/snapshot"
 	"github.com/goclaw/goclaw/pkg/storage"
-	badgerstorage "github.com/goclaw/goclaw/pkg/storage/badger"
-	memstorage "github.com/goclaw/goclaw/pkg/storage/memory"
 	tracingpkg "github.com/goclaw/goclaw/pkg/telemetry/tracing"
 	"github.com/goclaw/goclaw/pkg/version"
 
@@ -51,9 +50,11 @@ import (
 )
 
 var (
-	configPath  = flag.String("config", "", "Path to configuration file")
+	configPath  = flag.String("config", "", "Path to configuration file (YAML, JSON or TOML)")
+	envFile     = flag.String("env-file", "", "Path to a .env file with GOCLAW_ variables")
 	versionFlag = flag.Bool("version", false, "Print version information")
 	helpFlag    = flag.Bool("help", false, "Print help information")
+	role        = flag.String("role", roleAll, "Process role: all (API, UI and execution), api (API and UI only) or worker (execution only)")
 
 	// CLI overrides
 	appName    = flag.String("app-name", "", "Override app name")
@@ -77,11 +78,63 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Run config subcommands (goclaw config schema|doctor)
+	if flag.NArg() > 0 && flag.Arg(0) == "config" {
+		os.Exit(runConfigCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Run redis maintenance subcommands (goclaw redis migrate-prefix)
+	if flag.NArg() > 0 && flag.Arg(0) == "redis" {
+		os.Exit(runRedisCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Replay a recorded workflow run (goclaw replay <run-id>)
+	if flag.NArg() > 0 && flag.Arg(0) == "replay" {
+		os.Exit(runReplayCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Generate load against a running server (goclaw bench)
+	if flag.NArg() > 0 && flag.Arg(0) == "bench" {
+		os.Exit(runBenchCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Drain the local server before it stops (goclaw drain)
+	if flag.NArg() > 0 && flag.Arg(0) == "drain" {
+		os.Exit(runDrainCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Generate monitoring configuration from a running server (goclaw monitoring)
+	if flag.NArg() > 0 && flag.Arg(0) == "monitoring" {
+		os.Exit(runMonitoringCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Export or import a snapshot of a running server (goclaw snapshot)
+	if flag.NArg() > 0 && flag.Arg(0) == "snapshot" {
+		os.Exit(runSnapshotCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	// Convert Argo or Temporal workflows into templates (goclaw import)
+	if flag.NArg() > 0 && flag.Arg(0) == "import" {
+		os.Exit(runImportCommand(flag.Args()[1:], os.Stdout, os.Stderr))
+	}
+
+	if err := validateRole(*role); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	serveAPI := *role != roleWorker
+
 	// Build CLI overrides map
 	overrides := buildOverrides()
 
 	// Load configuration
-	cfg, err := config.Load(*configPath, overrides)
+	cfg, err := newConfigLoader().Load(*configPath, overrides)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration:\n%s\n", err)
+		os.Exit(1)
+	}
+
+	scrubber, err := newScrubber(cfg.Scrub)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration:\n%s\n", err)
 		os.Exit(1)
@@ -92,6 +145,28 @@ func main() {
 		Level:  logger.ParseLevel(cfg.Log.Level),
 		Format: cfg.Log.Format,
 		Output: cfg.Log.Output,
+		Rotation: logger.Rotation{
+			MaxSize:    int64(cfg.Log.File.MaxSizeMB) << 20,
+			MaxAge:     cfg.Log.File.MaxAge,
+			MaxBackups: cfg.Log.File.MaxBackups,
+			Compress:   cfg.Log.File.Compress,
+		},
+		Syslog: logger.Syslog{
+			Network:  cfg.Log.Syslog.Network,
+			Address:  cfg.Log.Syslog.Address,
+			Tag:      cfg.Log.Syslog.Tag,
+			Facility: cfg.Log.Syslog.Facility,
+		},
+		Components: make(map[string]logger.Level, len(cfg.Log.Components)),
+		Sampling: logger.Sampling{
+			Initial:    cfg.Log.Sampling.Initial,
+			Thereafter: cfg.Log.Sampling.Thereafter,
+			Tick:       cfg.Log.Sampling.Tick,
+		},
+		Scrubber: scrubber,
+	}
+	for component, level := range cfg.Log.Components {
+		logCfg.Components[component] = logger.ParseLevel(level)
 	}
 	if cfg.App.Debug || *debugMode {
 		logCfg.Level = logger.DebugLevel
@@ -105,6 +180,7 @@ func main() {
 		"gitCommit", version.GitCommit,
 		"app", cfg.App.Name,
 		"environment", cfg.App.Environment,
+		"role", *role,
 	)
 
 	log.Debug("Configuration loaded", "config", cfg.String())
@@ -124,26 +200,24 @@ func main() {
 	defer stopShutdownSignals(sigChan)
 
 	// Initialize storage backend
-	var store storage.Storage
-	switch cfg.Storage.Type {
+	storageCfg := cfg.Storage
+	if storageCfg.Type != "badger" && storageCfg.Type != "memory" {
+		log.Warn("Unknown storage type, using memory storage", "type", storageCfg.Type)
+		storageCfg.Type = "memory"
+	}
+	store, err := goclaw.OpenStorage(storageCfg)
+	if err != nil {
+		log.Error("Failed to create storage", "type", storageCfg.Type, "error", err)
+		os.Exit(1)
+	}
+	switch storageCfg.Type {
 	case "badger":
-		badgerCfg := &badgerstorage.Config{
-			Path:             cfg.Storage.Badger.Path,
-			SyncWrites:       cfg.Storage.Badger.SyncWrites,
-			ValueLogFileSize: cfg.Storage.Badger.ValueLogFileSize,
-		}
-		store, err = badgerstorage.NewBadgerStorage(badgerCfg)
-		if err != nil {
-			log.Error("Failed to create Badger storage", "error", err)
-			os.Exit(1)
+		log.Info("Initialized Badger storage", "path", storageCfg.Badger.Path)
+		if storageCfg.CacheSize > 0 {
+			log.Info("Enabled storage cache", "size", storageCfg.CacheSize)
 		}
-		log.Info("Initialized Badger storage", "path", badgerCfg.Path)
-	case "memory":
-		store = memstorage.NewMemoryStorage()
-		log.Info("Initialized memory storage")
 	default:
-		store = memstorage.NewMemoryStorage()
-		log.Warn("Unknown storage type, using memory storage", "type", cfg.Storage.Type)
+		log.Info("Initialized memory storage")
 	}
 	defer func() {
 		if err := store.Close(); err != nil {
@@ -160,6 +234,9 @@ func main() {
 		TaskDurationBuckets:     metrics.DefaultConfig().TaskDurationBuckets,
 		LaneWaitBuckets:         metrics.DefaultConfig().LaneWaitBuckets,
 		HTTPDurationBuckets:     metrics.DefaultConfig().HTTPDurationBuckets,
+		WorkflowLabel:           cfg.Metrics.WorkflowLabel,
+		TenantLabel:             cfg.Metrics.TenantLabel,
+		MaxLabelValues:          cfg.Metrics.MaxLabelValues,
 	}
 	metricsManager := metrics.NewManager(metricsCfg)
 	signalpkg.SetMetricsRecorder(metricsManager)
@@ -176,49 +253,69 @@ func main() {
 
 	// Initialize and start the orchestration engine.
 	eventBroadcaster := events.NewBroadcaster()
+	eventBroadcaster.SetScrubber(scrubber)
 	var streamingRegistry *grpcstreaming.SubscriberRegistry
 	var streamObserver *grpcstreaming.WorkflowStreamObserver
-	if cfg.Server.GRPC.Enabled {
+	grpcEnabled := serveAPI && cfg.Server.GRPC.Enabled
+	if grpcEnabled {
 		streamingRegistry = grpcstreaming.NewSubscriberRegistry()
 		streamObserver = grpcstreaming.NewWorkflowStreamObserver(streamingRegistry)
 	}
-	runtimeBroadcaster := newRuntimeEventBroadcaster(eventBroadcaster, streamObserver)
+	runtimeBroadcaster := newRuntimeEventBroadcaster(eventBroadcaster, streamObserver, scrubber)
 	wsHandler := handlers.NewWebSocketHandler(log, handlers.WebSocketConfig{
 		AllowedOrigins: cfg.Server.CORS.AllowedOrigins,
 		MaxConnections: cfg.UI.MaxWebSocketConnections,
 		PingInterval:   30 * time.Second,
 		PongTimeout:    10 * time.Second,
 	})
-	eventSubscription := eventBroadcaster.Subscribe(256)
-	defer eventBroadcaster.Unsubscribe(eventSubscription)
-	go func() {
-		for event := range eventSubscription {
-			_ = wsHandler.Broadcast(handlers.EventMessage{
-				Type:      event.Type,
-				Timestamp: event.Timestamp,
-				Payload:   event.Payload,
-			})
-		}
-	}()
+	if serveAPI {
+		eventSubscription := eventBroadcaster.Subscribe(256)
+		defer eventBroadcaster.Unsubscribe(eventSubscription)
+		go func() {
+			for event := range eventSubscription {
+				_ = wsHandler.Broadcast(handlers.EventMessage{
+					Type:      event.Type,
+					Timestamp: event.Timestamp,
+					Payload:   event.Payload,
+				})
+			}
+		}()
+	}
 
 	engineOpts := []engine.Option{
+		engine.WithLaneLogger(log.Component("lane")),
 		engine.WithMetrics(metricsManager),
 		engine.WithEventBroadcaster(runtimeBroadcaster),
 	}
+	// The management service delivers the webhooks but needs the engine, so
+	// the outbox notifier is pointed at it once it exists.
+	var notifier *outboxNotifier
+	if cfg.Orchestration.Outbox.Enabled && cfg.Manage.Enabled && serveAPI {
+		notifier = &outboxNotifier{scrubber: scrubber}
+		engineOpts = append(engineOpts, engine.WithNotifier(notifier))
+	}
 
-	needsRedis := cfg.Redis.Enabled || cfg.Orchestration.Queue.Type == "redis" || cfg.Signal.Mode == "redis"
-	var redisClient *redis.Client
+	needsRedis := cfg.Redis.Enabled || cfg.Orchestration.Queue.Type == "redis" || cfg.Signal.Mode == "redis" ||
+		(cfg.Orchestration.LLMCache.Enabled && cfg.Orchestration.LLMCache.Backend == "redis")
+	var redisClient redis.UniversalClient
 	if needsRedis {
 		redisClient, err = initializeRedisClient(ctx, cfg)
 		if err != nil {
 			log.Warn("Redis initialization failed; distributed Redis features will fall back to local mode", "error", err)
 		} else {
 			engineOpts = append(engineOpts, engine.WithRedisClient(redisClient))
-			log.Info("Redis client initialized", "address", cfg.Redis.Address, "db", cfg.Redis.DB, "sentinel", cfg.Redis.Sentinel.Enabled)
+			log.Info("Redis client initialized", "mode", cfg.Redis.Mode(), "address", cfg.Redis.Address, "db", cfg.Redis.DB)
 		}
 	}
 
 	signalBus, effectiveSignalMode := initializeSignalBus(cfg, redisClient, log)
+	signalBus, signalHistory := initializeSignalHistory(cfg, redisClient, signalBus)
+	signalSchemas, err := initializeSignalSchemas(cfg)
+	if err != nil {
+		log.Error("Failed to load signal schemas", "error", err)
+		os.Exit(1)
+	}
+	signalBus = signalpkg.NewValidatingBus(signalBus, signalSchemas)
 	engineOpts = append(engineOpts, engine.WithSignalBus(signalBus))
 
 	// Initialize memory hub if enabled
@@ -255,6 +352,77 @@ func main() {
 		log.Info("Memory hub disabled")
 	}
 
+	if llmCfg := cfg.Orchestration.LLMCache; llmCfg.Enabled {
+		var llmStore llmcache.Store
+		switch {
+		case llmCfg.Backend == "badger":
+			llmBadgerOpts := dgbadger.DefaultOptions(llmCfg.Path)
+			llmBadgerOpts.Logger = nil
+			llmDB, err := dgbadger.Open(llmBadgerOpts)
+			if err != nil {
+				log.Error("Failed to open LLM cache Badger DB", "error", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := llmDB.Close(); err != nil {
+					log.Error("Error closing LLM cache Badger DB", "error", err)
+				}
+			}()
+			llmStore = llmcache.NewBadgerStore(llmDB, "")
+		case llmCfg.Backend == "redis" && redisClient != nil:
+			llmStore = llmcache.NewRedisStore(redisClient, rediskey.Prefix(cfg.Redis.KeyPrefix, "llmcache"))
+		default:
+			if llmCfg.Backend == "redis" {
+				log.Warn("Redis unavailable; LLM cache falls back to memory")
+			}
+			llmStore = llmcache.NewMemoryStore(llmCfg.MaxEntries)
+		}
+		llmCache := llmcache.New(llmStore, llmCfg.TTL, llmcache.WithRecorder(metricsManager))
+		engineOpts = append(engineOpts, engine.WithLLMCache(llmCache))
+		log.Info("LLM cache initialized", "backend", llmCfg.Backend, "ttl", llmCfg.TTL)
+	}
+
+	var artifactStore *artifact.Store
+	if cfg.Artifacts.Enabled {
+		artifactStore, err = initializeArtifactStore(cfg.Artifacts, log)
+		if err != nil {
+			log.Error("Failed to initialize artifact store", "error", err)
+			os.Exit(1)
+		}
+		engineOpts = append(engineOpts, engine.WithArtifactStore(artifactStore))
+		if cfg.Artifacts.Retention > 0 {
+			go artifactStore.Run(ctx, cfg.Artifacts.SweepInterval)
+		}
+		log.Info("Artifact store initialized",
+			"backend", cfg.Artifacts.Backend,
+			"retention", cfg.Artifacts.Retention,
+		)
+	} else {
+		log.Info("Artifact store disabled")
+	}
+
+	if cfg.Containers.Enabled {
+		rt, err := initializeContainerRuntime(cfg.Containers)
+		if err != nil {
+			log.Error("Failed to initialize container runtime", "error", err)
+			os.Exit(1)
+		}
+		engineOpts = append(engineOpts, engine.WithTaskExecutor(dag.AgentContainer, container.NewExecutor(rt)))
+		log.Info("Container tasks enabled", "runtime", cfg.Containers.Runtime)
+	}
+
+	// Tasks of the agent task types are dispatched to the agents connected
+	// to the AgentService.
+	var agentRegistry *agent.Registry
+	if grpcEnabled && cfg.Server.GRPC.Agents.Enabled {
+		agentRegistry = newAgentRegistry(cfg.Server.GRPC.Agents, log)
+		for _, taskType := range cfg.Server.GRPC.Agents.TaskTypes {
+			engineOpts = append(engineOpts, engine.WithTaskExecutor(taskType, agentRegistry))
+		}
+		go agentRegistry.Run(ctx)
+		log.Info("Remote agents enabled", "task_types", cfg.Server.GRPC.Agents.TaskTypes)
+	}
+
 	effectiveQueueType := cfg.Orchestration.Queue.Type
 	if effectiveQueueType == "redis" && redisClient == nil {
 		effectiveQueueType = "memory(fallback)"
@@ -264,6 +432,16 @@ func main() {
 		"signal_mode", effectiveSignalMode,
 		"redis_connected", redisClient != nil,
 	)
+	switch {
+	case *role == roleWorker && effectiveQueueType != "redis":
+		log.Warn("Worker role without a shared Redis queue; this process only runs workflows it recovers from storage",
+			"queue_type", effectiveQueueType)
+	case *role == roleAPI && effectiveQueueType != "redis":
+		log.Warn("API role without a shared Redis queue; tasks run on this process",
+			"queue_type", effectiveQueueType)
+	case *role == roleAPI:
+		engineOpts = append(engineOpts, engine.WithEnqueueOnly())
+	}
 
 	eng, err := engine.New(cfg, log, store, engineOpts...)
 	if err != nil {
@@ -275,6 +453,89 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cfg.Operator.Enabled {
+		kubeClient, err := kube.NewClient(kube.Options{
+			APIServer: cfg.Operator.APIServer,
+			TokenFile: cfg.Operator.TokenFile,
+			CAFile:    cfg.Operator.CAFile,
+		})
+		if err != nil {
+			log.Error("Failed to initialize Kubernetes client", "error", err)
+			os.Exit(1)
+		}
+		controller := operator.NewController(kubeClient, eng, cfg.Operator.Namespace,
+			operator.WithResyncInterval(cfg.Operator.ResyncInterval),
+			operator.WithLogger(log),
+		)
+		go controller.Run(ctx)
+		log.Info("Kubernetes operator started", "namespace", cfg.Operator.Namespace)
+	}
+
+	var manageService *manage.Service
+	var manageHandler *handlers.ManageHandler
+	if cfg.Manage.Enabled && serveAPI {
+		manageOpts := []manage.Option{
+			manage.WithLogger(log),
+			manage.WithMaxParallelBackfill(cfg.Manage.MaxParallelBackfill),
+			manage.WithCallbackSecret(cfg.Manage.CallbackSecret),
+			manage.WithCallbackAllowedHosts(cfg.Manage.CallbackAllowedHosts),
+			manage.WithScrubber(scrubber),
+		}
+		if notifier != nil {
+			manageOpts = append(manageOpts, manage.WithOutboxDelivery())
+		}
+		if store, ok := eng.Storage().(storage.ResourceStore); ok {
+			manageOpts = append(manageOpts, manage.WithStore(store))
+		}
+		manageService = manage.New(eng, manageOpts...)
+		if err := manageService.Load(ctx); err != nil {
+			log.Error("Failed to load managed resources", "error", err)
+			os.Exit(1)
+		}
+		if err := manageService.SyncWebhooks(webhookSpecs(cfg.Manage.Webhooks)); err != nil {
+			log.Error("Invalid configured webhooks", "error", err)
+			os.Exit(1)
+		}
+		eng.ConfigReloader().Register(func(next *config.Config, changes []config.FieldChange) error {
+			for _, change := range changes {
+				if change.Key == "manage.webhooks" {
+					return manageService.SyncWebhooks(webhookSpecs(next.Manage.Webhooks))
+				}
+			}
+			return nil
+		})
+		if notifier != nil {
+			notifier.service.Store(manageService)
+		}
+		webhookEvents := eventBroadcaster.Subscribe(256)
+		defer eventBroadcaster.Unsubscribe(webhookEvents)
+		go manageService.DeliverEvents(webhookEvents)
+		go manageService.Run(ctx)
+		manageHandler = handlers.NewManageHandler(eng, manageService, log)
+		log.Info("Management API enabled")
+	} else if serveAPI {
+		// Without the management API, the service only posts the callbacks
+		// of finished runs.
+		callbacks := manage.New(eng,
+			manage.WithLogger(log),
+			manage.WithCallbackSecret(cfg.Manage.CallbackSecret),
+			manage.WithCallbackAllowedHosts(cfg.Manage.CallbackAllowedHosts),
+			manage.WithScrubber(scrubber),
+		)
+		callbackEvents := eventBroadcaster.Subscribe(256)
+		defer eventBroadcaster.Unsubscribe(callbackEvents)
+		go callbacks.DeliverEvents(callbackEvents)
+	}
+
+	reloadSignals := setupReloadSignals()
+	defer stopShutdownSignals(reloadSignals)
+	configWatcher := startConfigReload(ctx, *configPath, overrides, eng.ConfigReloader(), log, reloadSignals)
+	if configWatcher != nil {
+		defer func() {
+			_ = configWatcher.Stop()
+		}()
+	}
+
 	var sagaHandler *handlers.SagaHandler
 	var sagaGRPCService *grpchandlers.SagaServiceServer
 	if cfg.Saga.Enabled {
@@ -284,7 +545,7 @@ func main() {
 		} else {
 			sagaCheckpointStore := eng.GetSagaCheckpointStore()
 			sagaRecoveryManager := eng.GetSagaRecoveryManager()
-			sagaHandler = handlers.NewSagaHandler(sagaOrchestrator, sagaCheckpointStore, sagaRecoveryManager, log)
+			sagaHandler = handlers.NewSagaHandler(sagaOrchestrator, sagaCheckpointStore, sagaRecoveryManager, eng.GetSagaArchiveStore(), log)
 			sagaGRPCService = grpchandlers.NewSagaServiceServer(sagaOrchestrator, sagaCheckpointStore)
 			log.Info("Saga orchestrator initialized",
 				"max_concurrent", cfg.Saga.MaxConcurrent,
@@ -298,46 +559,105 @@ func main() {
 
 	// Initialize HTTP server with handlers
 	workflowHandler := handlers.NewWorkflowHandler(eng, log)
-	healthHandler := handlers.NewHealthHandler(eng)
-
-	apiHandlers := &api.Handlers{
-		Workflow:  workflowHandler,
-		Health:    healthHandler,
-		Memory:    memoryHandler,
-		Saga:      sagaHandler,
-		Metrics:   metricsManager,
-		WebSocket: wsHandler,
+	healthHandler := handlers.NewHealthHandler(eng,
+		handlers.WithDrainTiming(cfg.Server.HTTP.DrainDelay, cfg.Server.HTTP.DrainTimeout))
+	signalHandler := handlers.NewSignalHandler(signalHistory, signalSchemas, log)
+	laneHandler := handlers.NewLaneHandler(eng, log)
+	toolHandler := handlers.NewToolHandler(eng, log)
+	var insightsHandler *handlers.InsightsHandler
+	if detector := eng.Insights(); detector != nil {
+		insightsHandler = handlers.NewInsightsHandler(detector, log)
 	}
-
-	httpServer := api.NewHTTPServer(cfg, log, apiHandlers)
-
-	// Start HTTP server in a separate goroutine
-	serverErrChan := make(chan error, 2) // Increased buffer for both HTTP and gRPC
-	go func() {
-		log.Info("Starting HTTP server", "address", fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port))
-		if err := httpServer.Start(); err != nil {
-			serverErrChan <- err
-		}
-	}()
-
-	// Initialize and start gRPC server if enabled
+	var costHandler *handlers.CostHandler
+	if cfg.Orchestration.Costs.Enabled {
+		costHandler = handlers.NewCostHandler(eng, log)
+	}
+	var monitoringHandler *handlers.MonitoringHandler
+	if metricsManager.Enabled() {
+		monitoringHandler = handlers.NewMonitoringHandler(metricsManager, eng, metrics.MonitoringTarget{
+			SagaEnabled:            cfg.Saga.Enabled,
+			SagaTimeout:            cfg.Saga.DefaultTimeout,
+			CompensationMaxRetries: cfg.Saga.CompensationMaxRetries,
+		}, log)
+	}
+	var logLevelHandler *handlers.LogLevelHandler
+	if slogLogger, ok := log.(*logger.SlogLogger); ok {
+		logLevelHandler = handlers.NewLogLevelHandler(slogLogger.Levels(), log)
+	}
+	var artifactHandler *handlers.ArtifactHandler
+	if artifactStore != nil {
+		artifactHandler = handlers.NewArtifactHandler(eng, artifactStore, log)
+	}
+	snapshotHandler := handlers.NewSnapshotHandler(snapshot.Source{
+		Storage:   eng.Storage(),
+		Sagas:     eng.GetSagaOrchestrator(),
+		Memory:    memoryHub,
+		Resources: manageService,
+		Flush:     eng.FlushTaskWrites,
+	}, log)
+
+	// Create the gRPC server before the HTTP server, which serves its
+	// services to gRPC-Web clients when enabled.
 	var grpcServer *grpcpkg.Server
-	if cfg.Server.GRPC.Enabled {
-		grpcCfg := cfg.Server.GRPC.ToGRPCConfig()
+	if grpcEnabled {
+		grpcCfg := grpcpkg.FromConfig(&cfg.Server.GRPC)
 		grpcCfg.EnableTracing = cfg.Server.GRPC.EnableTracing && cfg.Tracing.Enabled
+		grpcCfg.Logger = log.Component("grpc")
+		grpcCfg.RoleMappings = cfg.Server.Auth.IdentityMappings()
 		grpcServer, err = grpcpkg.New(grpcCfg)
 		if err != nil {
 			log.Error("Failed to create gRPC server", "error", err)
 			os.Exit(1)
 		}
-		if err := registerGRPCServices(grpcServer, eng, signalBus, streamingRegistry, sagaGRPCService); err != nil {
+		if err := registerGRPCServices(grpcServer, eng, signalBus, streamingRegistry, sagaGRPCService, agentRegistry); err != nil {
 			log.Error("Failed to register gRPC services", "error", err)
 			os.Exit(1)
 		}
+	}
+	var grpcWebHandler http.Handler
+	if grpcServer != nil && cfg.Server.GRPC.Web.Enabled {
+		grpcWebHandler = grpcServer.WebHandler()
+	}
+
+	apiHandlers := &api.Handlers{
+		Workflow:   workflowHandler,
+		Health:     healthHandler,
+		Memory:     memoryHandler,
+		Saga:       sagaHandler,
+		Signal:     signalHandler,
+		Lane:       laneHandler,
+		Insights:   insightsHandler,
+		Cost:       costHandler,
+		Tool:       toolHandler,
+		Monitoring: monitoringHandler,
+		LogLevel:   logLevelHandler,
+		Artifact:   artifactHandler,
+		Manage:     manageHandler,
+		Snapshot:   snapshotHandler,
+		Metrics:    metricsManager,
+		WebSocket:  wsHandler,
+		GRPCWeb:    grpcWebHandler,
+	}
+
+	// Start HTTP server in a separate goroutine; workers serve no API or UI
+	serverErrChan := make(chan error, 2) // Increased buffer for both HTTP and gRPC
+	var httpServer *api.HTTPServer
+	if serveAPI {
+		httpServer = api.NewHTTPServer(cfg, log.Component("http"), apiHandlers)
+		go func() {
+			log.Info("Starting HTTP server", "address", fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port))
+			if err := httpServer.Start(); err != nil {
+				serverErrChan <- err
+			}
+		}()
+	} else {
+		log.Info("HTTP server disabled", "role", *role)
+	}
 
-		// Start gRPC server in a separate goroutine
+	// Start gRPC server if enabled
+	if grpcServer != nil {
 		go func() {
-			log.Info("Starting gRPC server", "address", grpcCfg.Address)
+			log.Info("Starting gRPC server", "address", grpcServer.Address())
 			if err := grpcServer.Start(); err != nil {
 				serverErrChan <- fmt.Errorf("gRPC server error: %w", err)
 			}
@@ -347,12 +667,14 @@ func main() {
 	}
 
 	log.Info("Goclaw is running",
+		"role", *role,
+		"http_enabled", serveAPI,
 		"http_port", cfg.Server.Port,
 		"grpc_port", cfg.Server.GRPC.Port,
-		"grpc_enabled", cfg.Server.GRPC.Enabled,
+		"grpc_enabled", grpcEnabled,
 		"metrics_port", cfg.Metrics.Port,
 	)
-	if cfg.UI.Enabled {
+	if cfg.UI.Enabled && serveAPI {
 		basePath := strings.TrimSpace(cfg.UI.BasePath)
 		if basePath == "" {
 			basePath = "/ui"
@@ -380,8 +702,10 @@ func main() {
 	log.Info("Closing websocket connections")
 	wsHandler.Close()
 	eventBroadcaster.Close()
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Error("Error shutting down HTTP server", "error", err)
+	if httpServer != nil {
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("Error shutting down HTTP server", "error", err)
+		}
 	}
 
 	// Shutdown gRPC server if it was started
@@ -415,44 +739,12 @@ func main() {
 	log.Info("Goclaw stopped gracefully")
 }
 
-func initializeRedisClient(ctx context.Context, cfg *config.Config) (*redis.Client, error) {
+func initializeRedisClient(ctx context.Context, cfg *config.Config) (redis.UniversalClient, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	if cfg.Redis.Sentinel.Enabled {
-		client := lane.NewRedisSentinelClient(&redis.FailoverOptions{
-			MasterName:    cfg.Redis.Sentinel.MasterName,
-			SentinelAddrs: cfg.Redis.Sentinel.Addresses,
-			Password:      cfg.Redis.Password,
-			DB:            cfg.Redis.DB,
-			MaxRetries:    cfg.Redis.MaxRetries,
-			PoolSize:      cfg.Redis.PoolSize,
-			MinIdleConns:  cfg.Redis.MinIdleConns,
-			DialTimeout:   cfg.Redis.DialTimeout,
-			ReadTimeout:   cfg.Redis.ReadTimeout,
-			WriteTimeout:  cfg.Redis.WriteTimeout,
-		})
-		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-		defer cancel()
-		if err := lane.PingRedis(pingCtx, client); err != nil {
-			_ = client.Close()
-			return nil, err
-		}
-		return client, nil
-	}
-
-	client := lane.NewRedisClient(&redis.Options{
-		Addr:         cfg.Redis.Address,
-		Password:     cfg.Redis.Password,
-		DB:           cfg.Redis.DB,
-		MaxRetries:   cfg.Redis.MaxRetries,
-		PoolSize:     cfg.Redis.PoolSize,
-		MinIdleConns: cfg.Redis.MinIdleConns,
-		DialTimeout:  cfg.Redis.DialTimeout,
-		ReadTimeout:  cfg.Redis.ReadTimeout,
-		WriteTimeout: cfg.Redis.WriteTimeout,
-	})
+	client := cfg.Redis.NewClient()
 	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 	if err := lane.PingRedis(pingCtx, client); err != nil {
@@ -462,6 +754,49 @@ func initializeRedisClient(ctx context.Context, cfg *config.Config) (*redis.Clie
 	return client, nil
 }
 
+func initializeArtifactStore(cfg config.ArtifactsConfig, log logger.Logger) (*artifact.Store, error) {
+	var backend artifact.Backend
+	switch cfg.Backend {
+	case "s3":
+		backend = artifact.NewS3Backend(artifact.S3Options{
+			Bucket:   cfg.S3.Bucket,
+			Region:   cfg.S3.Region,
+			Endpoint: cfg.S3.Endpoint,
+			Prefix:   cfg.S3.Prefix,
+			Credentials: awsauth.Credentials{
+				AccessKeyID:     cfg.S3.AccessKeyID,
+				SecretAccessKey: cfg.S3.SecretAccessKey,
+				SessionToken:    cfg.S3.SessionToken,
+			}.WithEnv(),
+		})
+	default:
+		local, err := artifact.NewLocalBackend(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		backend = local
+	}
+	return artifact.NewStore(backend,
+		artifact.WithRetention(cfg.Retention),
+		artifact.WithMaxSize(cfg.MaxSize),
+		artifact.WithLogger(log),
+	), nil
+}
+
+func initializeContainerRuntime(cfg config.ContainersConfig) (container.Runtime, error) {
+	if cfg.Runtime == "kubernetes" {
+		return container.NewKubernetesRuntime(container.KubernetesOptions{
+			APIServer:        cfg.Kubernetes.APIServer,
+			Namespace:        cfg.Kubernetes.Namespace,
+			TokenFile:        cfg.Kubernetes.TokenFile,
+			CAFile:           cfg.Kubernetes.CAFile,
+			ServiceAccount:   cfg.Kubernetes.ServiceAccount,
+			TTLAfterFinished: cfg.Kubernetes.TTLAfterFinished,
+		})
+	}
+	return container.NewDockerRuntime(cfg.Docker.Host, cfg.Docker.Pull)
+}
+
 func initializeSignalBus(cfg *config.Config, redisClient redis.UniversalClient, log logger.Logger) (signalpkg.Bus, string) {
 	if cfg != nil && cfg.Signal.Mode == "redis" {
 		if redisClient == nil {
@@ -471,7 +806,15 @@ func initializeSignalBus(cfg *config.Config, redisClient redis.UniversalClient,
 			return signalpkg.NewLocalBus(cfg.Signal.BufferSize), "local(fallback)"
 		}
 
-		bus := signalpkg.NewRedisBus(redisClient, cfg.Signal.ChannelPrefix, cfg.Signal.BufferSize)
+		channelPrefix := cfg.Signal.ChannelPrefix
+		if channelPrefix == "" {
+			channelPrefix = signalpkg.DefaultChannelPrefix
+		}
+		var opts []signalpkg.RedisBusOption
+		if cfg.Signal.Delivery == "at_least_once" {
+			opts = append(opts, signalpkg.WithAckDelivery(cfg.Signal.AckTimeout, cfg.Signal.MaxDeliveries))
+		}
+		bus := signalpkg.NewRedisBus(redisClient, cfg.Redis.KeyPrefix+channelPrefix, cfg.Signal.BufferSize, opts...)
 		if !bus.Healthy() {
 			if log != nil {
 				log.Warn("Redis signal bus health check failed; falling back to local bus")
@@ -479,6 +822,9 @@ func initializeSignalBus(cfg *config.Config, redisClient redis.UniversalClient,
 			_ = bus.Close()
 			return signalpkg.NewLocalBus(cfg.Signal.BufferSize), "local(fallback)"
 		}
+		if bus.AckEnabled() {
+			return bus, "redis(at_least_once)"
+		}
 		return bus, "redis"
 	}
 
@@ -489,6 +835,56 @@ func initializeSignalBus(cfg *config.Config, redisClient redis.UniversalClient,
 	return signalpkg.NewLocalBus(bufferSize), "local"
 }
 
+// initializeSignalHistory wraps the bus so published signals are recorded.
+// Redis buses keep the history in Redis so every instance sees it; other
+// buses keep it in memory.
+func initializeSignalHistory(cfg *config.Config, redisClient redis.UniversalClient, bus signalpkg.Bus) (signalpkg.Bus, signalpkg.History) {
+	if cfg == nil || !cfg.Signal.History.Enabled || bus == nil {
+		return bus, nil
+	}
+
+	historyCfg := cfg.Signal.History
+	var history signalpkg.History
+	if _, ok := bus.(*signalpkg.RedisBus); ok && redisClient != nil {
+		channelPrefix := cfg.Signal.ChannelPrefix
+		if channelPrefix == "" {
+			channelPrefix = signalpkg.DefaultChannelPrefix
+		}
+		history = signalpkg.NewRedisHistory(redisClient, cfg.Redis.KeyPrefix+channelPrefix+"history:", historyCfg.Size, historyCfg.TTL)
+	} else {
+		history = signalpkg.NewMemoryHistory(historyCfg.Size, historyCfg.MaxChannels)
+	}
+	return signalpkg.NewHistoryBus(bus, history), history
+}
+
+// initializeSignalSchemas registers the payload schemas configured under
+// signal.schemas.
+func initializeSignalSchemas(cfg *config.Config) (*signalpkg.SchemaRegistry, error) {
+	registry := signalpkg.NewSchemaRegistry()
+	if cfg == nil {
+		return registry, nil
+	}
+
+	for i, sc := range cfg.Signal.Schemas {
+		var (
+			raw []byte
+			err error
+		)
+		if sc.File != "" {
+			raw, err = os.ReadFile(sc.File)
+		} else {
+			raw, err = json.Marshal(sc.Schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("signal schema %d (%s): %w", i, sc.Channel, err)
+		}
+		if _, err := registry.Register(sc.Channel, signalpkg.SignalType(sc.Type), raw); err != nil {
+			return nil, fmt.Errorf("signal schema %d (%s): %w", i, sc.Channel, err)
+		}
+	}
+	return registry, nil
+}
+
 func initTracing(
 	ctx context.Context,
 	cfg *config.Config,
@@ -573,25 +969,61 @@ func summarizeTracingEndpoint(endpoint string) string {
 	return raw
 }
 
+// outboxNotifier delivers the engine's outbox to the webhooks of the
+// management service, scrubbing the payloads as the event broadcaster does.
+// Entries fail until the service is set, and stay in the outbox.
+type outboxNotifier struct {
+	service  atomic.Pointer[manage.Service]
+	scrubber *scrub.Scrubber
+}
+
+func (n *outboxNotifier) Notify(ctx context.Context, entry *storage.OutboxEntry) error {
+	service := n.service.Load()
+	if service == nil {
+		return fmt.Errorf("management service not started")
+	}
+	var payload any
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return fmt.Errorf("decode outbox entry %s: %w", entry.ID, err)
+	}
+	accepted, err := service.Notify(ctx, events.Event{
+		ID:        entry.ID,
+		Type:      entry.Type,
+		Timestamp: entry.CreatedAt.UTC(),
+		Payload:   n.scrubber.Value(nil, payload),
+	}, entry.Delivered)
+	entry.Delivered = append(entry.Delivered, accepted...)
+	return err
+}
+
 type runtimeEventBroadcaster struct {
 	web      *events.Broadcaster
 	observer *grpcstreaming.WorkflowStreamObserver
+	// scrubber scrubs the task errors streamed to the observer; web scrubs
+	// its own payloads.
+	scrubber *scrub.Scrubber
 }
 
-func newRuntimeEventBroadcaster(web *events.Broadcaster, observer *grpcstreaming.WorkflowStreamObserver) *runtimeEventBroadcaster {
+func newRuntimeEventBroadcaster(web *events.Broadcaster, observer *grpcstreaming.WorkflowStreamObserver, scrubber *scrub.Scrubber) *runtimeEventBroadcaster {
 	return &runtimeEventBroadcaster{
 		web:      web,
 		observer: observer,
+		scrubber: scrubber,
 	}
 }
 
 func (b *runtimeEventBroadcaster) BroadcastWorkflowStateChanged(workflowID, name, oldState, newState string, updatedAt time.Time) {
+	b.BroadcastWorkflowStateChangedFor("", workflowID, name, oldState, newState, updatedAt)
+}
+
+func (b *runtimeEventBroadcaster) BroadcastWorkflowStateChangedFor(requestID, workflowID, name, oldState, newState string, updatedAt time.Time) {
 	if b.web != nil {
-		b.web.BroadcastWorkflowStateChanged(workflowID, name, oldState, newState, updatedAt)
+		b.web.BroadcastWorkflowStateChangedFor(requestID, workflowID, name, oldState, newState, updatedAt)
 	}
 	if b.observer != nil {
 		b.observer.OnWorkflowEvent(engine.WorkflowEvent{
 			WorkflowID: workflowID,
+			RequestID:  requestID,
 			EventType:  mapWorkflowEventType(newState),
 			Status:     strings.ToUpper(newState),
 			Message:    "workflow state changed",
@@ -604,17 +1036,26 @@ func (b *runtimeEventBroadcaster) BroadcastTaskStateChanged(
 	workflowID, taskID, taskName, oldState, newState, errorMessage string,
 	result any,
 	updatedAt time.Time,
+) {
+	b.BroadcastTaskStateChangedFor("", workflowID, taskID, taskName, oldState, newState, errorMessage, result, updatedAt)
+}
+
+func (b *runtimeEventBroadcaster) BroadcastTaskStateChangedFor(
+	requestID, workflowID, taskID, taskName, oldState, newState, errorMessage string,
+	result any,
+	updatedAt time.Time,
 ) {
 	if b.web != nil {
-		b.web.BroadcastTaskStateChanged(workflowID, taskID, taskName, oldState, newState, errorMessage, result, updatedAt)
+		b.web.BroadcastTaskStateChangedFor(requestID, workflowID, taskID, taskName, oldState, newState, errorMessage, result, updatedAt)
 	}
 	if b.observer != nil {
 		message := "task state changed"
 		if errorMessage != "" {
-			message = errorMessage
+			message, _ = b.scrubber.Value([]string{"error"}, errorMessage).(string)
 		}
 		b.observer.OnTaskEvent(engine.TaskEvent{
 			WorkflowID: workflowID,
+			RequestID:  requestID,
 			TaskID:     taskID,
 			EventType:  mapTaskEventType(newState),
 			Status:     strings.ToUpper(newState),
@@ -624,6 +1065,43 @@ func (b *runtimeEventBroadcaster) BroadcastTaskStateChanged(
 	}
 }
 
+func (b *runtimeEventBroadcaster) BroadcastTaskOutput(workflowID, taskID string, seq int64, chunk string) {
+	if b.web != nil {
+		b.web.BroadcastTaskOutput(workflowID, taskID, seq, chunk)
+	}
+	if b.observer != nil {
+		chunk, _ = b.scrubber.Value([]string{"chunk"}, chunk).(string)
+		b.observer.OnTaskEvent(engine.TaskEvent{
+			WorkflowID: workflowID,
+			TaskID:     taskID,
+			EventType:  engine.TaskEventProgress,
+			Status:     "RUNNING",
+			Message:    "task output",
+			Timestamp:  time.Now().Unix(),
+			Chunk:      chunk,
+			ChunkSeq:   seq,
+		})
+	}
+}
+
+func (b *runtimeEventBroadcaster) BroadcastTaskDeadlineMissed(workflowID, taskID, taskName string, deadline, finishedAt time.Time) {
+	if b.web != nil {
+		b.web.BroadcastTaskDeadlineMissed(workflowID, taskID, taskName, deadline, finishedAt)
+	}
+}
+
+func (b *runtimeEventBroadcaster) BroadcastWorkflowSLABreached(workflowID, name, status string, metadata map[string]string, deadline, breachedAt time.Time) {
+	if b.web != nil {
+		b.web.BroadcastWorkflowSLABreached(workflowID, name, status, metadata, deadline, breachedAt)
+	}
+}
+
+func (b *runtimeEventBroadcaster) BroadcastDurationAnomaly(workflowID, workflowName, taskID string, duration, mean time.Duration, zScore, percentile float64, reason string) {
+	if b.web != nil {
+		b.web.BroadcastDurationAnomaly(workflowID, workflowName, taskID, duration, mean, zScore, percentile, reason)
+	}
+}
+
 func mapWorkflowEventType(state string) engine.WorkflowEventType {
 	switch strings.ToLower(state) {
 	case "pending":
@@ -656,6 +1134,164 @@ func mapTaskEventType(state string) engine.TaskEventType {
 	}
 }
 
+// newScrubber compiles the configured scrubbing rules.
+func newScrubber(cfg config.ScrubConfig) (*scrub.Scrubber, error) {
+	rules := make([]scrub.Rule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rules[i] = scrub.Rule{
+			Name:        rule.Name,
+			Pattern:     rule.Pattern,
+			Fields:      rule.Fields,
+			Replacement: rule.Replacement,
+		}
+	}
+	return scrub.New(rules)
+}
+
+func setupReloadSignals() chan os.Signal {
+	sigChan := make(chan os.Signal, 1)
+	ossignal.Notify(sigChan, syscall.SIGHUP)
+	return sigChan
+}
+
+// startConfigReload wires config hot reload: the log level is applied here,
+// orchestration settings are applied by the engine. Reloads are triggered by
+// config file changes and by SIGHUP.
+func startConfigReload(
+	ctx context.Context,
+	path string,
+	overrides map[string]interface{},
+	reloader *config.Reloader,
+	log logger.Logger,
+	reloadSignals <-chan os.Signal,
+) *config.Watcher {
+	if reloader == nil || log == nil {
+		return nil
+	}
+
+	reloader.Register(func(next *config.Config, changes []config.FieldChange) error {
+		for _, change := range changes {
+			if change.Key == "log.level" {
+				log.SetLevel(logger.ParseLevel(next.Log.Level))
+			}
+		}
+		return nil
+	})
+
+	apply := func(next *config.Config) {
+		result, err := reloader.Apply(next)
+		if err != nil {
+			log.Error("Config reload failed", "error", err)
+			return
+		}
+		logReloadResult(log, result)
+	}
+
+	var watcher *config.Watcher
+	if path != "" {
+		var err error
+		watcher, err = config.NewWatcher(path, newConfigLoader(), config.WithOverrides(overrides))
+		if err != nil {
+			log.Warn("Config file watching disabled", "error", err)
+		} else {
+			watcher.OnChange(apply)
+			go func() {
+				if err := watcher.Watch(ctx); err != nil && ctx.Err() == nil {
+					log.Warn("Config watcher stopped", "error", err)
+				}
+			}()
+		}
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-reloadSignals:
+				if !ok {
+					return
+				}
+				log.Info("Received SIGHUP, reloading configuration")
+				if watcher != nil {
+					watcher.Reload(ctx)
+					continue
+				}
+				next, err := newConfigLoader().Load(path, overrides)
+				if err != nil {
+					log.Error("Config reload failed", "error", err)
+					continue
+				}
+				apply(next)
+			}
+		}
+	}()
+
+	return watcher
+}
+
+// newConfigLoader creates a config loader honouring the -env-file flag.
+func newConfigLoader() *config.Loader {
+	loader := config.NewLoader()
+	if *envFile != "" {
+		loader.SetEnvFile(*envFile)
+	}
+	return loader
+}
+
+func logReloadResult(log logger.Logger, result *config.ReloadResult) {
+	if !result.HasChanges() {
+		log.Info("Config reloaded with no changes")
+		return
+	}
+	for _, change := range result.Applied {
+		oldValue, newValue := printableChange(change)
+		log.Info("Config change applied", "key", change.Key, "old", oldValue, "new", newValue)
+	}
+	for _, change := range result.Rejected {
+		oldValue, newValue := printableChange(change)
+		log.Warn("Config change rejected", "key", change.Key, "old", oldValue, "new", newValue, "reason", change.Reason)
+	}
+}
+
+func printableChange(change config.FieldChange) (interface{}, interface{}) {
+	if config.IsSensitiveKey(change.Key) {
+		return config.MaskSecret(fmt.Sprint(change.Old)), config.MaskSecret(fmt.Sprint(change.New))
+	}
+	if change.Key == "manage.webhooks" {
+		return maskWebhookSecrets(change.Old), maskWebhookSecrets(change.New)
+	}
+	return change.Old, change.New
+}
+
+// maskWebhookSecrets masks the secrets of the configured webhooks v.
+func maskWebhookSecrets(v interface{}) interface{} {
+	webhooks, ok := v.(map[string]config.WebhookConfig)
+	if !ok {
+		return v
+	}
+	masked := make(map[string]config.WebhookConfig, len(webhooks))
+	for name, webhook := range webhooks {
+		webhook.Secret = config.MaskSecret(webhook.Secret)
+		masked[name] = webhook
+	}
+	return masked
+}
+
+// webhookSpecs returns the managed webhooks of the configured webhooks.
+func webhookSpecs(webhooks map[string]config.WebhookConfig) map[string]models.WebhookSpec {
+	specs := make(map[string]models.WebhookSpec, len(webhooks))
+	for name, webhook := range webhooks {
+		specs[name] = models.WebhookSpec{
+			URL:    webhook.URL,
+			Events: webhook.Events,
+			Format: webhook.Format,
+			Secret: webhook.Secret,
+		}
+	}
+	return specs
+}
+
 func setupShutdownSignals() chan os.Signal {
 	sigChan := make(chan os.Signal, 1)
 	ossignal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -675,6 +1311,7 @@ func registerGRPCServices(
 	signalBus signalpkg.Bus,
 	streamingRegistry *grpcstreaming.SubscriberRegistry,
 	sagaSvc *grpchandlers.SagaServiceServer,
+	agentRegistry *agent.Registry,
 ) error {
 	if grpcServer == nil {
 		return fmt.Errorf("grpc server is nil")
@@ -709,10 +1346,49 @@ func registerGRPCServices(
 	grpcServer.RegisterService(&pb.AdminService_ServiceDesc, adminSvc)
 	grpcServer.RegisterService(&pb.SignalService_ServiceDesc, signalSvc)
 	grpcServer.RegisterService(&pb.SagaService_ServiceDesc, sagaSvc)
+	grpcServer.RegisterService(&pb.SagaBatchService_ServiceDesc, grpchandlers.NewSagaBatchServiceServer(sagaSvc))
+	if agentRegistry != nil {
+		grpcServer.RegisterService(&pb.AgentService_ServiceDesc, grpchandlers.NewAgentServiceServer(agentRegistry))
+	}
 
 	return nil
 }
 
+// newAgentRegistry creates the registry tracking remote agents connected to
+// the AgentService
+func newAgentRegistry(cfg config.GRPCAgentsConfig, log logger.Logger) *agent.Registry {
+	return agent.NewRegistry(
+		agent.WithHeartbeatInterval(time.Duration(cfg.HeartbeatIntervalSeconds)*time.Second),
+		agent.WithHeartbeatTimeout(time.Duration(cfg.HeartbeatTimeoutSeconds)*time.Second),
+		agent.WithProgressHandler(func(p agent.Progress) {
+			log.Debug("Agent task progress", "agent_id", p.AgentID, "assignment_id", p.AssignmentID, "percent", p.Percent, "message", p.Message)
+		}),
+	)
+}
+
+// Process roles selected with -role.
+const (
+	// roleAll runs the API, the UI and the engine in one process.
+	roleAll = "all"
+	// roleAPI serves the API and the UI and enqueues tasks onto the shared
+	// Redis lanes without consuming them, leaving execution to workers.
+	roleAPI = "api"
+	// roleWorker runs only the engine: it connects to the shared storage and
+	// Redis, registers lanes and executes tasks, so execution can be scaled
+	// separately from API nodes.
+	roleWorker = "worker"
+)
+
+// validateRole checks the value of the -role flag.
+func validateRole(role string) error {
+	switch role {
+	case roleAll, roleAPI, roleWorker:
+		return nil
+	default:
+		return fmt.Errorf("invalid -role %q: must be %q, %q or %q", role, roleAll, roleAPI, roleWorker)
+	}
+}
+
 func buildOverrides() map[string]interface{} {
 	overrides := make(map[string]interface{})
 
@@ -742,12 +1418,29 @@ func printVersion() {
 
 func printHelp() {
 	fmt.Printf("Goclaw - Production-grade, high-performance, distributed-ready multi-Agent orchestration engine\n\n")
-	fmt.Printf("Usage: goclaw [options]\n\n")
+	fmt.Printf("Usage: goclaw [options]\n")
+	fmt.Printf("       goclaw [options] config schema           # Print the config JSON Schema\n")
+	fmt.Printf("       goclaw [options] config doctor [-json]   # Check config against live dependencies\n")
+	fmt.Printf("       goclaw [options] redis migrate-prefix [-from P] [-to P] [-dry-run]\n")
+	fmt.Printf("                                                # Move goclaw keys to a new redis.key_prefix\n")
+	fmt.Printf("       goclaw [options] replay [-json] <run-id>  # Replay a recorded run with stubbed executors\n")
+	fmt.Printf("       goclaw bench [-url U | -in-process] [-rate N] [-duration D] [-layers N] [-width N]\n")
+	fmt.Printf("                                                # Benchmark with synthetic DAGs\n")
+	fmt.Printf("       goclaw drain [-url U]                     # Drain the local server (pre-stop hook)\n")
+	fmt.Printf("       goclaw monitoring [-url U] <dashboard|alerts>\n")
+	fmt.Printf("                                                # Print a Grafana dashboard or Prometheus alert rules\n")
+	fmt.Printf("       goclaw snapshot [-url U] export [-o FILE] | import <file>\n")
+	fmt.Printf("                                                # Back up or restore the state of a server\n")
+	fmt.Printf("       goclaw import [-format F] [-name N] [-server U] <file>\n")
+	fmt.Printf("                                                # Import an Argo or Temporal workflow as a template\n\n")
 	fmt.Printf("Options:\n")
 	flag.PrintDefaults()
 	fmt.Printf("\nExamples:\n")
 	fmt.Printf("  goclaw                                    # Run with default config\n")
 	fmt.Printf("  goclaw -config config.yaml                # Use specific config file\n")
+	fmt.Printf("  goclaw -config config.toml -env-file .env # TOML config plus .env variables\n")
 	fmt.Printf("  goclaw -port 9090 -log-level debug        # Override specific options\n")
+	fmt.Printf("  goclaw -config config.yaml -role api      # Serve the API, leave execution to workers\n")
+	fmt.Printf("  goclaw -config config.yaml -role worker   # Execute tasks only, no HTTP/gRPC API\n")
 	fmt.Printf("  goclaw -version                           # Print version info\n")
 }
@@ -5,23 +5,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/agent"
 	"github.com/goclaw/goclaw/pkg/api"
 	"github.com/goclaw/goclaw/pkg/api/handlers"
 	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/engine"
 	grpcpkg "github.com/goclaw/goclaw/pkg/grpc"
+	grpcclient "github.com/goclaw/goclaw/pkg/grpc/client"
 	grpchandlers "github.com/goclaw/goclaw/pkg/grpc/handlers"
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	grpcstreaming "github.com/goclaw/goclaw/pkg/grpc/streaming"
 	"github.com/goclaw/goclaw/pkg/logger"
 	signalpkg "github.com/goclaw/goclaw/pkg/signal"
 	"github.com/goclaw/goclaw/pkg/storage"
+	badgerstorage "github.com/goclaw/goclaw/pkg/storage/badger"
+	memstorage "github.com/goclaw/goclaw/pkg/storage/memory"
 )
 
 // mockStorage is a minimal mock implementation for testing
@@ -242,6 +252,7 @@ func TestServerStartup_WithSagaEnabled(t *testing.T) {
 		sagaOrchestrator,
 		eng.GetSagaCheckpointStore(),
 		eng.GetSagaRecoveryManager(),
+		eng.GetSagaArchiveStore(),
 		log,
 	)
 	workflowHandler := handlers.NewWorkflowHandler(eng, log)
@@ -290,14 +301,14 @@ func TestServerStartup_WithSagaEnabled(t *testing.T) {
 		t.Fatalf("Submit saga status = %d, want %d", resp.StatusCode, http.StatusCreated)
 	}
 
-	grpcServer, err := grpcpkg.New(cfg.Server.GRPC.ToGRPCConfig())
+	grpcServer, err := grpcpkg.New(grpcpkg.FromConfig(&cfg.Server.GRPC))
 	if err != nil {
 		t.Fatalf("failed to create grpc server: %v", err)
 	}
 	bus := signalpkg.NewLocalBus(16)
 	defer bus.Close()
 	sagaSvc := grpchandlers.NewSagaServiceServer(sagaOrchestrator, eng.GetSagaCheckpointStore())
-	if err := registerGRPCServices(grpcServer, eng, bus, grpcstreaming.NewSubscriberRegistry(), sagaSvc); err != nil {
+	if err := registerGRPCServices(grpcServer, eng, bus, grpcstreaming.NewSubscriberRegistry(), sagaSvc, nil); err != nil {
 		t.Fatalf("registerGRPCServices() error = %v", err)
 	}
 
@@ -359,6 +370,17 @@ func TestBuildOverrides(t *testing.T) {
 	}
 }
 
+func TestValidateRole(t *testing.T) {
+	for _, role := range []string{roleAll, roleAPI, roleWorker} {
+		if err := validateRole(role); err != nil {
+			t.Errorf("validateRole(%q) error = %v", role, err)
+		}
+	}
+	if err := validateRole("scheduler"); err == nil {
+		t.Error("Expected error for unknown role")
+	}
+}
+
 func TestPrintVersion(t *testing.T) {
 	// Redirect stdout to capture output
 	oldStdout := os.Stdout
@@ -438,6 +460,52 @@ func TestInitializeSignalBus_LocalMode(t *testing.T) {
 	}
 }
 
+func TestInitializeSignalHistory(t *testing.T) {
+	cfg := config.DefaultConfig()
+	local := signalpkg.NewLocalBus(4)
+	defer local.Close()
+
+	bus, history := initializeSignalHistory(cfg, nil, local)
+	if _, ok := bus.(*signalpkg.HistoryBus); !ok {
+		t.Fatalf("expected HistoryBus, got %T", bus)
+	}
+	if _, ok := history.(*signalpkg.MemoryHistory); !ok {
+		t.Fatalf("expected MemoryHistory for local bus, got %T", history)
+	}
+
+	cfg.Signal.History.Enabled = false
+	bus, history = initializeSignalHistory(cfg, nil, local)
+	if bus != signalpkg.Bus(local) || history != nil {
+		t.Fatalf("expected bus to be unchanged when history is disabled, got %T, %v", bus, history)
+	}
+}
+
+func TestInitializeSignalSchemas(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "interrupt.json")
+	if err := os.WriteFile(file, []byte(`{"type":"object","required":["reason"]}`), 0o600); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Signal.Schemas = []config.SignalSchemaConfig{
+		{Channel: "*", Type: "steer", Schema: map[string]interface{}{"type": "object", "required": []interface{}{"parameters"}}},
+		{Channel: "job-*", Type: "interrupt", File: file},
+	}
+
+	registry, err := initializeSignalSchemas(cfg)
+	if err != nil {
+		t.Fatalf("initializeSignalSchemas() error = %v", err)
+	}
+	if got := registry.Match("job-1", ""); len(got) != 2 {
+		t.Fatalf("expected 2 schemas for job-1, got %+v", got)
+	}
+
+	cfg.Signal.Schemas = []config.SignalSchemaConfig{{Channel: "*", File: filepath.Join(t.TempDir(), "missing.json")}}
+	if _, err := initializeSignalSchemas(cfg); err == nil {
+		t.Fatal("expected error for missing schema file")
+	}
+}
+
 func TestInitializeSignalBus_RedisFallback(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Signal.Mode = "redis"
@@ -547,7 +615,7 @@ func TestEngineStartupShutdown_WithDistributedFallback(t *testing.T) {
 func TestRegisterGRPCServices_MissingWiring(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Server.GRPC.Enabled = true
-	grpcServer, err := grpcpkg.New(cfg.Server.GRPC.ToGRPCConfig())
+	grpcServer, err := grpcpkg.New(grpcpkg.FromConfig(&cfg.Server.GRPC))
 	if err != nil {
 		t.Fatalf("failed to create grpc server: %v", err)
 	}
@@ -557,7 +625,7 @@ func TestRegisterGRPCServices_MissingWiring(t *testing.T) {
 		t.Fatalf("failed to create engine: %v", err)
 	}
 
-	err = registerGRPCServices(grpcServer, eng, signalpkg.NewLocalBus(16), nil, nil)
+	err = registerGRPCServices(grpcServer, eng, signalpkg.NewLocalBus(16), nil, nil, nil)
 	if err == nil {
 		t.Fatal("expected missing streaming registry error")
 	}
@@ -566,7 +634,7 @@ func TestRegisterGRPCServices_MissingWiring(t *testing.T) {
 func TestRegisterGRPCServices_Success(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Server.GRPC.Enabled = true
-	grpcServer, err := grpcpkg.New(cfg.Server.GRPC.ToGRPCConfig())
+	grpcServer, err := grpcpkg.New(grpcpkg.FromConfig(&cfg.Server.GRPC))
 	if err != nil {
 		t.Fatalf("failed to create grpc server: %v", err)
 	}
@@ -579,9 +647,103 @@ func TestRegisterGRPCServices_Success(t *testing.T) {
 	bus := signalpkg.NewLocalBus(16)
 	defer bus.Close()
 
-	if err := registerGRPCServices(grpcServer, eng, bus, grpcstreaming.NewSubscriberRegistry(), nil); err != nil {
+	if err := registerGRPCServices(grpcServer, eng, bus, grpcstreaming.NewSubscriberRegistry(), nil, agent.NewRegistry()); err != nil {
+		t.Fatalf("registerGRPCServices() error = %v", err)
+	}
+}
+
+func TestAgentTaskTypes_RunOnConnectedAgents(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Server.GRPC.Enabled = true
+	cfg.Server.GRPC.Agents.Enabled = true
+	log := logger.New(&logger.Config{Level: logger.InfoLevel, Format: "json", Output: "stdout"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentRegistry := newAgentRegistry(cfg.Server.GRPC.Agents, log)
+	go agentRegistry.Run(ctx)
+	var opts []engine.Option
+	for _, taskType := range cfg.Server.GRPC.Agents.TaskTypes {
+		opts = append(opts, engine.WithTaskExecutor(taskType, agentRegistry))
+	}
+	eng, err := engine.New(cfg, log, memstorage.NewMemoryStorage(), opts...)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	grpcCfg := grpcpkg.FromConfig(&cfg.Server.GRPC)
+	grpcCfg.Address = "127.0.0.1:0"
+	grpcServer, err := grpcpkg.New(grpcCfg)
+	if err != nil {
+		t.Fatalf("failed to create grpc server: %v", err)
+	}
+	bus := signalpkg.NewLocalBus(16)
+	defer bus.Close()
+	if err := registerGRPCServices(grpcServer, eng, bus, grpcstreaming.NewSubscriberRegistry(), nil, agentRegistry); err != nil {
 		t.Fatalf("registerGRPCServices() error = %v", err)
 	}
+	if err := grpcServer.Start(); err != nil {
+		t.Fatalf("failed to start grpc server: %v", err)
+	}
+	defer grpcServer.Stop(context.Background())
+
+	c, err := grpcclient.NewClient(grpcclient.DefaultOptions(grpcServer.Address()))
+	if err != nil {
+		t.Fatalf("failed to create grpc client: %v", err)
+	}
+	defer c.Close()
+	payloads := make(chan agent.TaskPayload, 2)
+	go func() {
+		reg := &pb.AgentRegistration{AgentId: "worker-1", AgentTypes: []string{"script"}, Capacity: 1}
+		_ = c.Agents().Serve(ctx, reg, func(_ context.Context, task *pb.TaskAssignment, _ func(float64, string)) ([]byte, error) {
+			var payload agent.TaskPayload
+			if err := json.Unmarshal(task.Payload, &payload); err != nil {
+				return nil, err
+			}
+			payloads <- payload
+			return json.Marshal(map[string]string{"ran": task.TaskId})
+		})
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(agentRegistry.Agents()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("agent did not connect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	body, _ := json.Marshal(models.WorkflowRequest{
+		Name: "remote",
+		Tasks: []models.TaskDefinition{
+			{ID: "compile", Name: "compile", Type: "script", Config: map[string]interface{}{"target": "linux"}},
+			{ID: "test", Name: "test", Type: "script", DependsOn: []string{"compile"}},
+		},
+	})
+	w := httptest.NewRecorder()
+	handlers.NewWorkflowHandler(eng, log).ExecuteWorkflow(w, httptest.NewRequest(http.MethodPost, "/api/v1/workflows:execute", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ExecuteWorkflow() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	var resp models.WorkflowExecuteResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Status != "completed" {
+		t.Fatalf("ExecuteWorkflow() = %+v, want completed", resp)
+	}
+	if output, ok := resp.Outputs["test"].(map[string]interface{}); !ok || output["ran"] != "test" {
+		t.Fatalf("output of test = %#v", resp.Outputs["test"])
+	}
+	if compile := <-payloads; compile.Config["target"] != "linux" {
+		t.Fatalf("payload of compile = %+v", compile)
+	}
+	if test := <-payloads; !reflect.DeepEqual(test.Upstream["compile"], map[string]interface{}{"ran": "compile"}) {
+		t.Fatalf("payload of test = %+v", test)
+	}
 }
 
 func TestInitTracing_DisabledByConfig(t *testing.T) {
@@ -636,3 +798,277 @@ func TestSummarizeTracingEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestRunConfigCommand_Schema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runConfigCommand([]string{"schema"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("schema output is not JSON: %v", err)
+	}
+	if _, ok := schema["properties"]; !ok {
+		t.Error("expected schema properties")
+	}
+}
+
+func TestRunConfigCommand_DoctorInvalidConfig(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	if err := os.WriteFile(path, []byte("log:\n  level: verbose\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runConfigCommand([]string{"doctor", "-config", path, "-json"}, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	var report config.DoctorReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("doctor output is not JSON: %v", err)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Status != config.CheckFail {
+		t.Errorf("expected single failed config check, got %+v", report.Checks)
+	}
+}
+
+func TestRunConfigCommand_Unknown(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runConfigCommand([]string{"bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+}
+
+func TestRunRedisCommand_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runRedisCommand(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 without subcommand, got %d", code)
+	}
+	if code := runRedisCommand([]string{"bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for unknown subcommand, got %d", code)
+	}
+}
+
+func TestMigratedKeyPrefixes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if got := migratedKeyPrefixes(cfg); !reflect.DeepEqual(got, []string{"goclaw:"}) {
+		t.Fatalf("prefixes with the default channel prefix = %v", got)
+	}
+	cfg.Signal.ChannelPrefix = "signals:"
+	if got := migratedKeyPrefixes(cfg); !reflect.DeepEqual(got, []string{"goclaw:", "signals:"}) {
+		t.Fatalf("prefixes with a custom channel prefix = %v", got)
+	}
+}
+
+func TestRunReplayCommand(t *testing.T) {
+	dir := t.TempDir()
+	store, err := badgerstorage.NewBadgerStorage(&badgerstorage.Config{Path: filepath.Join(dir, "badger"), ValueLogFileSize: 1 << 20})
+	if err != nil {
+		t.Fatalf("failed to open badger: %v", err)
+	}
+	err = store.SaveWorkflow(context.Background(), &storage.WorkflowState{
+		ID:        "run-1",
+		Name:      "nightly",
+		Status:    "completed",
+		Tasks:     []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		CreatedAt: time.Now(),
+		Journal: []storage.JournalEntry{
+			{Seq: 1, TaskID: "a", From: "pending", To: "scheduled"},
+			{Seq: 2, TaskID: "a", From: "scheduled", To: "running"},
+			{Seq: 3, TaskID: "a", From: "running", To: "completed"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveWorkflow() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("failed to close badger: %v", err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	cfgYAML := fmt.Sprintf("storage:\n  type: badger\n  badger:\n    path: %q\n", filepath.Join(dir, "badger"))
+	if err := os.WriteFile(path, []byte(cfgYAML), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runReplayCommand([]string{"-config", path, "-json", "run-1"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var report engine.ReplayReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("replay output is not JSON: %v", err)
+	}
+	if len(report.Steps) != 3 || report.ReplayedStatus != "completed" {
+		t.Errorf("unexpected replay report: %+v", report)
+	}
+
+	stdout.Reset()
+	if code := runReplayCommand([]string{"-config", path}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 without run ID, got %d", code)
+	}
+	if code := runReplayCommand([]string{"-config", path, "missing"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 for unknown run, got %d", code)
+	}
+}
+
+func TestRunBenchCommand_InvalidFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runBenchCommand([]string{"-rate", "0"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for zero rate, got %d", code)
+	}
+	if code := runBenchCommand([]string{"-width", "2", "-fan-in", "3"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for fan-in above width, got %d", code)
+	}
+}
+
+func TestRunDrainCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/drain" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(models.DrainResponse{RunningWorkflows: 2})
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDrainCommand([]string{"-url", server.URL}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if want := "Drain timed out with 2 workflows running\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if code := runDrainCommand([]string{"-url", server.URL + "/missing"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 for a failed drain, got %d", code)
+	}
+}
+
+func TestRunMonitoringCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/monitoring/alerts" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("groups: []\n"))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := runMonitoringCommand([]string{"-url", server.URL, "alerts"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stdout.String() != "groups: []\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if code := runMonitoringCommand([]string{"-url", server.URL, "dashboard"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 for a failed fetch, got %d", code)
+	}
+	if code := runMonitoringCommand([]string{"panels"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown kind, got %d", code)
+	}
+}
+
+func TestRunSnapshotCommand(t *testing.T) {
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != snapshotPath:
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet:
+			w.Write([]byte("archive"))
+		default:
+			uploaded, _ = io.ReadAll(r.Body)
+			w.Write([]byte(`{"format_version":1,"workflows":2,"tasks":3}`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	var stdout, stderr bytes.Buffer
+	if code := runSnapshotCommand([]string{"-url", server.URL, "export", "-o", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "archive" {
+		t.Fatalf("exported file = %q, %v", data, err)
+	}
+
+	if code := runSnapshotCommand([]string{"-url", server.URL, "import", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if string(uploaded) != "archive" {
+		t.Errorf("uploaded = %q", uploaded)
+	}
+	if !strings.Contains(stdout.String(), "2 workflows, 3 tasks") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if code := runSnapshotCommand([]string{"import"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 without a file, got %d", code)
+	}
+}
+
+func TestRunImportCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.yaml")
+	manifest := "kind: Workflow\nmetadata: {name: hello}\nspec:\n  entrypoint: say\n  templates:\n    - name: say\n      container: {image: alpine:3}\n"
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runImportCommand([]string{path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var printed models.WorkflowRequest
+	if err := json.Unmarshal(stdout.Bytes(), &printed); err != nil {
+		t.Fatalf("printed template is not JSON: %v", err)
+	}
+	if printed.Name != "hello" || len(printed.Tasks) != 1 || printed.Tasks[0].Container.Image != "alpine:3" {
+		t.Fatalf("unexpected template: %+v", printed)
+	}
+
+	var stored models.WorkflowRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/manage/templates/greeting" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&stored)
+		w.Header().Set("ETag", `"7"`)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	stdout.Reset()
+	if code := runImportCommand([]string{"-name", "greeting", "-server", server.URL, path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stored.Name != "hello" {
+		t.Fatalf("server received %+v", stored)
+	}
+	if want := "Stored template greeting (version 7)\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if code := runImportCommand([]string{"workflow.txt"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an undetectable format, got %d", code)
+	}
+}
+
+func TestInitializeRedisClient_ClusterUnreachable(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redis.Cluster.Enabled = true
+	cfg.Redis.Cluster.Addresses = []string{"127.0.0.1:0"}
+	cfg.Redis.DialTimeout = 10 * time.Millisecond
+	cfg.Redis.ReadTimeout = 10 * time.Millisecond
+	cfg.Redis.WriteTimeout = 10 * time.Millisecond
+
+	client, err := initializeRedisClient(context.Background(), cfg)
+	if err == nil {
+		if client != nil {
+			_ = client.Close()
+		}
+		t.Fatal("expected redis cluster initialization error")
+	}
+}
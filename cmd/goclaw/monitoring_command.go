@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// monitoringPaths maps the "goclaw monitoring" kinds to the API paths that
+// generate them.
+var monitoringPaths = map[string]string{
+	"dashboard": "/api/v1/monitoring/dashboard",
+	"alerts":    "/api/v1/monitoring/alerts",
+}
+
+// runMonitoringCommand handles "goclaw monitoring dashboard|alerts": it
+// fetches a Grafana dashboard or a Prometheus alerting rule file generated
+// by a running server, so they match its lanes, saga settings and metrics.
+func runMonitoringCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("monitoring", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	url := fs.String("url", "http://127.0.0.1:8080", "Address of the server")
+	out := fs.String("o", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path, ok := monitoringPaths[fs.Arg(0)]
+	if fs.NArg() != 1 || !ok {
+		fmt.Fprintf(stderr, "Usage: goclaw monitoring [-url U] [-o FILE] <dashboard|alerts>\n")
+		return 2
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(*url, "/") + path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Fetch %s failed: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		fmt.Fprintf(stderr, "Fetch %s failed: status %d: %s\n", fs.Arg(0), resp.StatusCode, strings.TrimSpace(string(body)))
+		return 1
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "Create %s failed: %v\n", *out, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		fmt.Fprintf(stderr, "Write %s failed: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	return 0
+}
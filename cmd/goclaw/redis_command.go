@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/rediskey"
+)
+
+// runRedisCommand handles "goclaw redis <migrate-prefix>" and returns the exit code.
+func runRedisCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "Usage: goclaw redis migrate-prefix [options]\n")
+		return 2
+	}
+
+	switch args[0] {
+	case "migrate-prefix":
+		return runRedisMigratePrefix(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "Unknown redis command %q (expected migrate-prefix)\n", args[0])
+		return 2
+	}
+}
+
+// runRedisMigratePrefix moves the goclaw keys from one redis.key_prefix
+// namespace to another.
+func runRedisMigratePrefix(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("redis migrate-prefix", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", *configPath, "Path to configuration file (YAML, JSON or TOML)")
+	env := fs.String("env-file", *envFile, "Path to a .env file with GOCLAW_ variables")
+	from := fs.String("from", "", "Namespace the keys currently use (empty for none)")
+	to := fs.String("to", "", "Namespace to move the keys to (defaults to redis.key_prefix)")
+	dryRun := fs.Bool("dry-run", false, "List the keys that would be moved without changing them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	loader := config.NewLoader()
+	if *env != "" {
+		loader.SetEnvFile(*env)
+	}
+	cfg, err := loader.Load(*path, buildOverrides())
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	target := *to
+	if target == "" {
+		target = cfg.Redis.KeyPrefix
+	}
+	if target == *from {
+		fmt.Fprintf(stderr, "Source and destination namespace are both %q\n", target)
+		return 2
+	}
+
+	ctx := context.Background()
+	client, err := initializeRedisClient(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to connect to redis: %v\n", err)
+		return 1
+	}
+	defer client.Close()
+
+	keys, err := lane.MigrateNamespace(ctx, client, lane.NamespaceMigration{
+		From:        *from,
+		To:          target,
+		KeyPrefixes: migratedKeyPrefixes(cfg),
+		DryRun:      *dryRun,
+	})
+	for _, key := range keys {
+		fmt.Fprintf(stdout, "%s -> %s%s\n", key, target, key[len(*from):])
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Migration failed: %v\n", err)
+		return 1
+	}
+
+	verb := "Moved"
+	if *dryRun {
+		verb = "Would move"
+	}
+	fmt.Fprintf(stdout, "%s %d keys from %q to %q\n", verb, len(keys), *from, target)
+	return 0
+}
+
+// migratedKeyPrefixes returns the prefixes of the keys goclaw writes inside
+// a namespace: the shared root, and the signal channel prefix when it was
+// configured outside of it.
+func migratedKeyPrefixes(cfg *config.Config) []string {
+	prefixes := []string{rediskey.Root}
+	if channelPrefix := cfg.Signal.ChannelPrefix; channelPrefix != "" && !strings.HasPrefix(channelPrefix, rediskey.Root) {
+		prefixes = append(prefixes, channelPrefix)
+	}
+	return prefixes
+}
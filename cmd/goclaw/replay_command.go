@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/engine"
+	badgerstorage "github.com/goclaw/goclaw/pkg/storage/badger"
+)
+
+// runReplayCommand handles "goclaw replay <run-id>" and returns the exit code:
+// 0 when the replay reproduced the recorded run, 1 on errors or divergence.
+func runReplayCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", *configPath, "Path to configuration file (YAML, JSON or TOML)")
+	env := fs.String("env-file", *envFile, "Path to a .env file with GOCLAW_ variables")
+	stepTimeout := fs.Duration("step-timeout", engine.DefaultReplayStepTimeout, "How long a transition waits for its recorded turn")
+	asJSON := fs.Bool("json", false, "Print the replay report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(stderr, "Usage: goclaw replay [options] <run-id>\n")
+		return 2
+	}
+	runID := fs.Arg(0)
+
+	loader := config.NewLoader()
+	if *env != "" {
+		loader.SetEnvFile(*env)
+	}
+	cfg, err := loader.Load(*path, buildOverrides())
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	if cfg.Storage.Type != "badger" {
+		fmt.Fprintf(stderr, "Replay reads recorded runs from badger storage, but storage.type is %q\n", cfg.Storage.Type)
+		return 1
+	}
+
+	store, err := badgerstorage.NewBadgerStorage(&badgerstorage.Config{
+		Path:             cfg.Storage.Badger.Path,
+		SyncWrites:       cfg.Storage.Badger.SyncWrites,
+		ValueLogFileSize: cfg.Storage.Badger.ValueLogFileSize,
+		Encoding:         cfg.Storage.Encoding,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to open storage: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	eng, err := engine.New(cfg, nil, store)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to create engine: %v\n", err)
+		return 1
+	}
+	report, err := eng.ReplayWorkflow(context.Background(), runID, *stepTimeout)
+	if err != nil {
+		fmt.Fprintf(stderr, "Replay failed: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "Failed to encode report: %v\n", err)
+			return 1
+		}
+	} else {
+		printReplayReport(stdout, report)
+	}
+	if !report.Reproduced() {
+		return 1
+	}
+	return 0
+}
+
+// printReplayReport writes report in human-readable form.
+func printReplayReport(w io.Writer, report *engine.ReplayReport) {
+	fmt.Fprintf(w, "Replaying %s (%s)\n", report.WorkflowID, report.Name)
+	for _, step := range report.Steps {
+		fmt.Fprintf(w, "%4d  %-24s %s -> %s", step.Seq, step.TaskID, step.From, step.To)
+		if step.Error != "" {
+			fmt.Fprintf(w, "  (%s)", step.Error)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, d := range report.Divergences {
+		switch {
+		case d.Observed == "":
+			fmt.Fprintf(w, "DIVERGED at #%d: %s never happened\n", d.Seq, d.Expected)
+		case d.Expected == "":
+			fmt.Fprintf(w, "DIVERGED: %s is not in the journal\n", d.Observed)
+		default:
+			fmt.Fprintf(w, "DIVERGED at #%d: expected %s, got %s\n", d.Seq, d.Expected, d.Observed)
+		}
+	}
+	fmt.Fprintf(w, "Recorded status: %s, replayed status: %s\n", report.RecordedStatus, report.ReplayedStatus)
+	if report.Reproduced() {
+		fmt.Fprintf(w, "Replay reproduced the recorded run\n")
+	} else {
+		fmt.Fprintf(w, "Replay diverged from the recorded run\n")
+	}
+}
@@ -0,0 +1,98 @@
+// Command protoc-gen-goclaw generates typed task contracts from proto
+// services. Every method of a service is a task taking its request message
+// as input and returning its response message as output:
+//
+//	service ReportPipeline {
+//	  rpc Extract(ExtractRequest) returns (Rows);
+//	  rpc Load(Rows) returns (LoadSummary);
+//	}
+//
+// For each service the generated file declares a contract.Contract per
+// method, an interface the task functions implement and a function returning
+// them keyed by method name. Run it next to protoc-gen-go:
+//
+//	protoc --go_out=. --goclaw_out=. pipeline.proto
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const (
+	contextPackage  = protogen.GoImportPath("context")
+	contractPackage = protogen.GoImportPath("github.com/goclaw/goclaw/pkg/contract")
+)
+
+func main() {
+	protogen.Options{}.Run(generate)
+}
+
+// generate generates the contracts of the services of the files to generate.
+func generate(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	for _, f := range gen.Files {
+		if !f.Generate || len(f.Services) == 0 {
+			continue
+		}
+		if err := generateFile(gen, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateFile(gen *protogen.Plugin, f *protogen.File) error {
+	g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_goclaw.pb.go", f.GoImportPath)
+	g.P("// Code generated by protoc-gen-goclaw. DO NOT EDIT.")
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	g.P("package ", f.GoPackageName)
+	for _, service := range f.Services {
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				return fmt.Errorf("%s: streaming methods cannot be tasks", method.Desc.FullName())
+			}
+		}
+		generateService(g, service)
+	}
+	return nil
+}
+
+func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
+	name := service.GoName
+	newContract := g.QualifiedGoIdent(contractPackage.Ident("New"))
+	ctx := g.QualifiedGoIdent(contextPackage.Ident("Context"))
+
+	for _, method := range service.Methods {
+		in, out := g.QualifiedGoIdent(method.Input.GoIdent), g.QualifiedGoIdent(method.Output.GoIdent)
+		g.P()
+		g.P("// ", name, method.GoName, " is the contract of the ", method.GoName, " task of ", name, ".")
+		g.P("var ", name, method.GoName, " = ", newContract, "(")
+		g.P(fmt.Sprintf("%q,", method.Desc.FullName()))
+		g.P("func() *", in, " { return new(", in, ") },")
+		g.P("func() *", out, " { return new(", out, ") },")
+		g.P(")")
+	}
+
+	g.P()
+	g.P("// ", name, "Tasks is implemented by the task functions of ", name, ".")
+	g.P("type ", name, "Tasks interface {")
+	for _, method := range service.Methods {
+		g.P(method.GoName, "(", ctx, ", *", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error)")
+	}
+	g.P("}")
+
+	g.P()
+	g.P("// ", name, "TaskFuncs returns the task functions of impl keyed by method")
+	g.P("// name, for tasks whose IDs are the method names.")
+	g.P("func ", name, "TaskFuncs(impl ", name, "Tasks) map[string]func(", ctx, ") error {")
+	g.P("return map[string]func(", ctx, ") error{")
+	for _, method := range service.Methods {
+		g.P(fmt.Sprintf("%q", method.GoName), ": ", name, method.GoName, ".TaskFunc(impl.", method.GoName, "),")
+	}
+	g.P("}")
+	g.P("}")
+}
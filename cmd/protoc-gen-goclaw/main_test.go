@@ -0,0 +1,82 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func pipelineRequest(streaming bool) *pluginpb.CodeGeneratorRequest {
+	message := func(name string) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	}
+	method := func(name, in, out string) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(".reports.v1." + in), OutputType: proto.String(".reports.v1." + out)}
+	}
+	load := method("Load", "Rows", "LoadSummary")
+	load.ServerStreaming = proto.Bool(streaming)
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("reports/v1/pipeline.proto"),
+		Package:     proto.String("reports.v1"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/reports/v1;reportsv1")},
+		MessageType: []*descriptorpb.DescriptorProto{message("ExtractRequest"), message("Rows"), message("LoadSummary")},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:   proto.String("ReportPipeline"),
+			Method: []*descriptorpb.MethodDescriptorProto{method("Extract", "ExtractRequest", "Rows"), load},
+		}},
+	}
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	}
+}
+
+func TestGenerate_ServiceContracts(t *testing.T) {
+	gen, err := protogen.Options{}.New(pipelineRequest(false))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := generate(gen); err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	resp := gen.Response()
+	if resp.Error != nil || len(resp.File) != 1 {
+		t.Fatalf("response = %v, want one file", resp)
+	}
+	out := resp.File[0]
+	if out.GetName() != "example.com/reports/v1/pipeline_goclaw.pb.go" {
+		t.Fatalf("file name = %s", out.GetName())
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), out.GetName(), out.GetContent(), 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, out.GetContent())
+	}
+	for _, want := range []string{
+		"package reportsv1",
+		`contract "github.com/goclaw/goclaw/pkg/contract"`,
+		"var ReportPipelineExtract = contract.New(\n\t\"reports.v1.ReportPipeline.Extract\",",
+		"func() *Rows { return new(Rows) },",
+		"Load(context.Context, *Rows) (*LoadSummary, error)",
+		`"Load":    ReportPipelineLoad.TaskFunc(impl.Load),`,
+	} {
+		if !strings.Contains(out.GetContent(), want) {
+			t.Errorf("generated code misses %q:\n%s", want, out.GetContent())
+		}
+	}
+}
+
+func TestGenerate_RejectsStreamingMethods(t *testing.T) {
+	gen, err := protogen.Options{}.New(pipelineRequest(true))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := generate(gen); err == nil || !strings.Contains(err.Error(), "reports.v1.ReportPipeline.Load") {
+		t.Fatalf("generate() error = %v, want the streaming method rejected", err)
+	}
+}
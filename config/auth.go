@@ -0,0 +1,18 @@
+package config
+
+import "github.com/goclaw/goclaw/pkg/identity"
+
+// IdentityMappings converts the configured role mappings for pkg/identity.
+func (a *AuthConfig) IdentityMappings() []identity.RoleMapping {
+	if len(a.RoleMappings) == 0 {
+		return nil
+	}
+	mappings := make([]identity.RoleMapping, 0, len(a.RoleMappings))
+	for _, m := range a.RoleMappings {
+		mappings = append(mappings, identity.RoleMapping{
+			SAN:   m.SAN,
+			Roles: append([]string(nil), m.Roles...),
+		})
+	}
+	return mappings
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // Config is the global configuration for Goclaw.
@@ -47,6 +49,25 @@ type Config struct {
 
 	// Saga is the distributed transaction configuration.
 	Saga SagaConfig `mapstructure:"saga"`
+
+	// Artifacts is the task artifact store configuration.
+	Artifacts ArtifactsConfig `mapstructure:"artifacts"`
+
+	// Containers is the container task executor configuration.
+	Containers ContainersConfig `mapstructure:"containers"`
+
+	// Operator is the Kubernetes operator configuration.
+	Operator OperatorConfig `mapstructure:"operator"`
+
+	// Manage is the declarative management API configuration.
+	Manage ManageConfig `mapstructure:"manage"`
+
+	// Policy is the submission-time policy check configuration.
+	Policy PolicyConfig `mapstructure:"policy"`
+
+	// Scrub is the configuration of the rules removing customer data from
+	// events, webhook bodies and logs.
+	Scrub ScrubConfig `mapstructure:"scrub"`
 }
 
 // AppConfig holds application metadata and settings.
@@ -80,6 +101,32 @@ type ServerConfig struct {
 
 	// CORS is the CORS configuration.
 	CORS CORSConfig `mapstructure:"cors"`
+
+	// Auth configures how callers identified by mTLS client certificates are
+	// mapped to roles, for both the HTTP and gRPC servers.
+	Auth AuthConfig `mapstructure:"auth"`
+}
+
+// AuthConfig holds caller identity settings.
+type AuthConfig struct {
+	// RoleMappings grant roles to clients whose certificate has a matching
+	// SAN. Mappings apply only when client certificates are required.
+	RoleMappings []RoleMappingConfig `mapstructure:"role_mappings" validate:"dive"`
+
+	// SensitiveDataRoles are the roles allowed to read the configs and
+	// results of sensitive tasks through the API. Other callers, including
+	// those without a client certificate, get them redacted.
+	SensitiveDataRoles []string `mapstructure:"sensitive_data_roles"`
+}
+
+// RoleMappingConfig grants roles to client certificates by SAN.
+type RoleMappingConfig struct {
+	// SAN is a path.Match pattern matched against the certificate's URI, DNS,
+	// email and IP SANs, e.g. "spiffe://goclaw/admin/*".
+	SAN string `mapstructure:"san" validate:"required"`
+
+	// Roles are the roles granted to matching clients, e.g. "admin".
+	Roles []string `mapstructure:"roles" validate:"required,min=1"`
 }
 
 // GRPCConfig holds gRPC-specific settings.
@@ -114,6 +161,95 @@ type GRPCConfig struct {
 
 	// Keepalive is the keepalive configuration.
 	Keepalive GRPCKeepaliveConfig `mapstructure:"keepalive"`
+
+	// Interceptors configures the server's interceptor chain.
+	Interceptors GRPCInterceptorsConfig `mapstructure:"interceptors"`
+
+	// Agents configures the AgentService control channel for remote workers.
+	Agents GRPCAgentsConfig `mapstructure:"agents"`
+
+	// Web serves the gRPC services to gRPC-Web clients on the HTTP port.
+	Web GRPCWebConfig `mapstructure:"web"`
+}
+
+// GRPCWebConfig holds the settings of gRPC-Web.
+type GRPCWebConfig struct {
+	// Enabled serves gRPC-Web calls, including server streams, on the HTTP
+	// port so that browsers can call the services without a proxy.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// GRPCAgentsConfig holds the settings of the AgentService.
+type GRPCAgentsConfig struct {
+	// Enabled registers the AgentService.
+	Enabled bool `mapstructure:"enabled"`
+
+	// HeartbeatIntervalSeconds is how often agents are asked to heartbeat.
+	HeartbeatIntervalSeconds int `mapstructure:"heartbeat_interval_seconds" validate:"min=0"`
+
+	// HeartbeatTimeoutSeconds is how long an agent may stay silent before it
+	// is evicted and the tasks assigned to it fail.
+	HeartbeatTimeoutSeconds int `mapstructure:"heartbeat_timeout_seconds" validate:"min=0"`
+
+	// TaskTypes are the task types the engine dispatches to connected
+	// agents, e.g. "script".
+	TaskTypes []string `mapstructure:"task_types" validate:"dive,oneof=http script function container"`
+}
+
+// GRPCInterceptorsConfig holds the settings of the optional gRPC interceptors.
+// Request IDs and panic recovery are always enabled.
+type GRPCInterceptorsConfig struct {
+	// Auth configures token authentication.
+	Auth GRPCAuthConfig `mapstructure:"auth"`
+
+	// RateLimit configures per-client rate limiting.
+	RateLimit GRPCRateLimitConfig `mapstructure:"rate_limit"`
+
+	// Logging configures request logging.
+	Logging GRPCLoggingConfig `mapstructure:"logging"`
+}
+
+// GRPCAuthConfig holds gRPC token authentication settings.
+type GRPCAuthConfig struct {
+	// Enabled requires every call except health checks to carry a valid token.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Tokens are the accepted tokens, sent in the "authorization" metadata key
+	// with or without a "Bearer " prefix.
+	Tokens []string `mapstructure:"tokens"`
+}
+
+// GRPCRateLimitConfig holds gRPC rate limiting settings.
+type GRPCRateLimitConfig struct {
+	// Enabled enables rate limiting.
+	Enabled bool `mapstructure:"enabled"`
+
+	// RequestsPerSecond is the default per-client call rate.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second" validate:"min=0"`
+
+	// Burst is the default per-client burst size.
+	Burst int `mapstructure:"burst" validate:"min=0"`
+
+	// Methods overrides the default limit for individual methods.
+	Methods []GRPCMethodRateLimitConfig `mapstructure:"methods" validate:"dive"`
+}
+
+// GRPCMethodRateLimitConfig holds the rate limit of a single gRPC method.
+type GRPCMethodRateLimitConfig struct {
+	// Method is the full method name, e.g. "/goclaw.v1.WorkflowService/SubmitWorkflow".
+	Method string `mapstructure:"method" validate:"required,startswith=/"`
+
+	// RequestsPerSecond is the per-client call rate of the method.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second" validate:"gt=0"`
+
+	// Burst is the per-client burst size of the method.
+	Burst int `mapstructure:"burst" validate:"min=1"`
+}
+
+// GRPCLoggingConfig holds gRPC request logging settings.
+type GRPCLoggingConfig struct {
+	// Enabled logs every call with its method, status code, duration and request ID.
+	Enabled bool `mapstructure:"enabled"`
 }
 
 // GRPCTLSConfig holds gRPC TLS/mTLS settings.
@@ -177,6 +313,51 @@ type HTTPConfig struct {
 
 	// MaxHeaderBytes limits the size of request headers.
 	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
+
+	// DrainDelay is the minimum time POST /drain keeps the node out of
+	// rotation before answering, so endpoints controllers notice it is not
+	// ready before the process stops.
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+
+	// DrainTimeout bounds how long POST /drain waits for running workflows.
+	// It should stay below the pod's termination grace period.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+
+	// Compression configures gzip/deflate response compression.
+	Compression CompressionConfig `mapstructure:"compression"`
+
+	// TLS is the TLS/mTLS configuration.
+	TLS HTTPTLSConfig `mapstructure:"tls"`
+}
+
+// HTTPTLSConfig holds HTTP TLS/mTLS settings.
+type HTTPTLSConfig struct {
+	// Enabled serves HTTPS instead of HTTP.
+	Enabled bool `mapstructure:"enabled"`
+
+	// CertFile is the path to the server certificate file.
+	CertFile string `mapstructure:"cert_file"`
+
+	// KeyFile is the path to the server private key file.
+	KeyFile string `mapstructure:"key_file"`
+
+	// CAFile is the path to the CA certificate file for mTLS.
+	CAFile string `mapstructure:"ca_file"`
+
+	// ClientAuth indicates whether to require client certificates (mTLS).
+	ClientAuth bool `mapstructure:"client_auth"`
+}
+
+// CompressionConfig holds HTTP response compression settings.
+type CompressionConfig struct {
+	// Enabled enables response compression for clients that accept it.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Level is the compression level (1 = fastest, 9 = smallest).
+	Level int `mapstructure:"level" validate:"omitempty,min=1,max=9"`
+
+	// MinSize is the smallest response body, in bytes, that is compressed.
+	MinSize int `mapstructure:"min_size" validate:"min=0"`
 }
 
 // CORSConfig holds CORS settings.
@@ -226,8 +407,70 @@ type LogConfig struct {
 	// Format is the output format (json, text).
 	Format string `mapstructure:"format" validate:"oneof=json text"`
 
-	// Output is the output destination (stdout, stderr, or file path).
+	// Output is the output destination (stdout, stderr, syslog, journald,
+	// or file path).
 	Output string `mapstructure:"output"`
+
+	// File configures the rotation of file output.
+	File LogFileConfig `mapstructure:"file"`
+
+	// Syslog configures the syslog and journald outputs.
+	Syslog LogSyslogConfig `mapstructure:"syslog"`
+
+	// Components sets the levels of component loggers, such as lane or
+	// http, overriding Level for them. Levels can also be changed at
+	// runtime with PUT /admin/loglevel.
+	Components map[string]string `mapstructure:"components"`
+
+	// Sampling thins out high-volume debug logs.
+	Sampling LogSamplingConfig `mapstructure:"sampling"`
+}
+
+// LogFileConfig holds the rotation settings of file log output.
+type LogFileConfig struct {
+	// MaxSizeMB is the size in megabytes past which the file is rotated; 0
+	// never rotates.
+	MaxSizeMB int `mapstructure:"max_size_mb" validate:"min=0"`
+
+	// MaxAge removes rotated files older than this; 0 keeps them.
+	MaxAge time.Duration `mapstructure:"max_age" validate:"min=0"`
+
+	// MaxBackups is the number of rotated files kept; 0 keeps all.
+	MaxBackups int `mapstructure:"max_backups" validate:"min=0"`
+
+	// Compress gzips rotated files.
+	Compress bool `mapstructure:"compress"`
+}
+
+// LogSyslogConfig holds the syslog and journald output settings.
+type LogSyslogConfig struct {
+	// Network is udp, tcp or unix for a remote syslog server; empty uses the
+	// local syslog daemon.
+	Network string `mapstructure:"network" validate:"omitempty,oneof=udp tcp unix unixgram"`
+
+	// Address is the address of the remote syslog server.
+	Address string `mapstructure:"address"`
+
+	// Tag identifies goclaw in syslog records and the journal.
+	Tag string `mapstructure:"tag"`
+
+	// Facility is the syslog facility.
+	Facility string `mapstructure:"facility" validate:"oneof=kern user mail daemon auth syslog lpr news uucp cron authpriv ftp local0 local1 local2 local3 local4 local5 local6 local7"`
+}
+
+// LogSamplingConfig holds debug log sampling settings. Per tick, the first
+// Initial debug records with the same message are logged, then every
+// Thereafter-th. Info and higher levels are never sampled.
+type LogSamplingConfig struct {
+	// Initial is the number of records per message logged each tick; 0
+	// disables sampling.
+	Initial int `mapstructure:"initial" validate:"min=0"`
+
+	// Thereafter logs every Thereafter-th record past Initial; 0 drops them.
+	Thereafter int `mapstructure:"thereafter" validate:"min=0"`
+
+	// Tick is the sampling period.
+	Tick time.Duration `mapstructure:"tick"`
 }
 
 // OrchestrationConfig holds workflow engine settings.
@@ -238,8 +481,89 @@ type OrchestrationConfig struct {
 	// Queue is the task queue configuration.
 	Queue QueueConfig `mapstructure:"queue"`
 
+	// Lanes are named memory lanes created on start besides the default
+	// lane, e.g. {"gpu": {capacity: 100, max_concurrency: 2}}. Tasks run in
+	// them with the "lane" config field. Their concurrency and rate limits
+	// follow config reloads.
+	Lanes map[string]NamedLaneConfig `mapstructure:"lanes" validate:"dive"`
+
 	// Scheduler is the task scheduler configuration.
 	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+
+	// Workflows limits how many workflows execute at the same time.
+	Workflows WorkflowLimitsConfig `mapstructure:"workflows"`
+
+	// Secrets maps secret names tasks may request to secret references,
+	// e.g. {"db_password": "vault://secret/data/app#db_password"}. Unlike
+	// other secret references they are resolved each time a task runs.
+	Secrets map[string]string `mapstructure:"secrets"`
+
+	// TaskLogs bounds the output lines kept per task, e.g. of container
+	// tasks.
+	TaskLogs TaskLogsConfig `mapstructure:"task_logs"`
+
+	// Locks configures the named locks tasks hold with engine.AcquireLock or
+	// their locks field.
+	Locks LocksConfig `mapstructure:"locks"`
+
+	// RateLimits are named request budgets of external endpoints, e.g.
+	// {"github-api": {limit: 5000, per: 1h}}, shared by all tasks naming
+	// them and, with a Redis client, by all nodes using it.
+	RateLimits map[string]RateLimitConfig `mapstructure:"rate_limits" validate:"dive"`
+
+	// Environments are named environment variable bundles tasks may request.
+	// Values may be secret references, resolved each time a task runs.
+	Environments map[string]map[string]string `mapstructure:"environments"`
+
+	// Tools describes the tools available to agent tasks by name, for
+	// planners to introspect and operators to enable per tenant.
+	Tools map[string]ToolConfig `mapstructure:"tools" validate:"dive"`
+
+	// SLACheckInterval is how often running workflows are checked against
+	// their SLA deadline. Zero uses the default of 10s.
+	SLACheckInterval time.Duration `mapstructure:"sla_check_interval" validate:"min=0"`
+
+	// Insights learns typical workflow and task durations and flags
+	// unusually slow runs.
+	Insights InsightsConfig `mapstructure:"insights"`
+
+	// Costs accounts the usage of each workflow run for charge-back.
+	Costs CostsConfig `mapstructure:"costs"`
+
+	// Budgets caps the usage of the runs of each tenant. It requires cost
+	// accounting.
+	Budgets BudgetsConfig `mapstructure:"budgets"`
+
+	// LLMCache caches the LLM calls tasks make through
+	// engine.CachedLLMCall.
+	LLMCache LLMCacheConfig `mapstructure:"llm_cache"`
+
+	// ModelRoutes route the LLM calls tasks make through
+	// engine.RouteLLMCall by route name, so that providers and models are
+	// chosen here rather than in each workflow spec.
+	ModelRoutes map[string]ModelRouteConfig `mapstructure:"model_routes" validate:"dive"`
+
+	// Outbox delivers workflow notifications to webhooks through an outbox
+	// written with each state change.
+	Outbox OutboxConfig `mapstructure:"outbox"`
+
+	// DedupeTTL is how long the execution of a task dedupe key is
+	// remembered, so that tasks of later runs with the same key share it
+	// instead of executing. Zero uses the default of 24h.
+	DedupeTTL time.Duration `mapstructure:"dedupe_ttl" validate:"min=0"`
+}
+
+// WorkflowLimitsConfig holds workflow-level concurrency limits.
+type WorkflowLimitsConfig struct {
+	// MaxConcurrent is the maximum number of workflows executing at once (0 = unlimited).
+	MaxConcurrent int `mapstructure:"max_concurrent" validate:"min=0"`
+
+	// PerName limits concurrent runs per workflow name, e.g. {"nightly-report": 1}.
+	PerName map[string]int `mapstructure:"per_name"`
+
+	// Policy decides what happens to a submission when a limit is reached:
+	// wait queues it as pending, reject fails it.
+	Policy string `mapstructure:"policy" validate:"oneof=wait reject"`
 }
 
 // QueueConfig holds task queue settings.
@@ -249,6 +573,70 @@ type QueueConfig struct {
 
 	// Size is the maximum queue size.
 	Size int `mapstructure:"size" validate:"min=1"`
+
+	// RateLimit limits task admission on the default lane (tasks per second, 0 = unlimited).
+	RateLimit float64 `mapstructure:"rate_limit" validate:"min=0"`
+
+	// Adaptive tunes the default lane's concurrency from task latency and errors.
+	Adaptive AdaptiveConcurrencyConfig `mapstructure:"adaptive"`
+
+	// Dispatch is the order the default lane hands tasks to workers:
+	// fifo, or edf (earliest task/workflow deadline first).
+	Dispatch string `mapstructure:"dispatch" validate:"omitempty,oneof=fifo edf"`
+
+	// Resources is the CPU, memory and GPU capacity of the default lane.
+	// Tasks requesting resources start only once they fit; all zero disables
+	// resource tracking.
+	Resources LaneResourcesConfig `mapstructure:"resources"`
+}
+
+// LaneResourcesConfig holds the resource capacity of a lane.
+type LaneResourcesConfig struct {
+	// CPU is the number of CPU cores.
+	CPU float64 `mapstructure:"cpu" validate:"min=0"`
+
+	// MemoryMB is the amount of memory in megabytes.
+	MemoryMB int64 `mapstructure:"memory_mb" validate:"min=0"`
+
+	// GPU is the number of GPUs.
+	GPU int `mapstructure:"gpu" validate:"min=0"`
+}
+
+// IsZero returns true if no capacity is configured.
+func (c LaneResourcesConfig) IsZero() bool {
+	return c == LaneResourcesConfig{}
+}
+
+// NamedLaneConfig holds the settings of a named lane.
+type NamedLaneConfig struct {
+	// Capacity is the number of tasks the lane queues. Changing it requires
+	// a restart.
+	Capacity int `mapstructure:"capacity" validate:"min=1"`
+
+	// MaxConcurrency is the number of tasks the lane runs at once.
+	MaxConcurrency int `mapstructure:"max_concurrency" validate:"min=1"`
+
+	// RateLimit limits task admission (tasks per second, 0 = unlimited).
+	RateLimit float64 `mapstructure:"rate_limit" validate:"min=0"`
+}
+
+// AdaptiveConcurrencyConfig holds AIMD concurrency settings for the default lane.
+// Concurrency moves between MinConcurrency and Orchestration.MaxAgents.
+type AdaptiveConcurrencyConfig struct {
+	// Enabled turns on adaptive concurrency.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MinConcurrency is the lowest concurrency the lane backs off to.
+	MinConcurrency int `mapstructure:"min_concurrency" validate:"min=0"`
+
+	// TargetLatency is the average task latency above which concurrency is reduced (0 = ignore latency).
+	TargetLatency time.Duration `mapstructure:"target_latency"`
+
+	// MaxErrorRate is the failed task ratio above which concurrency is reduced (0 = ignore errors).
+	MaxErrorRate float64 `mapstructure:"max_error_rate" validate:"min=0,max=1"`
+
+	// Interval is how often concurrency is adjusted.
+	Interval time.Duration `mapstructure:"interval"`
 }
 
 // SchedulerConfig holds scheduler settings.
@@ -298,11 +686,55 @@ type StorageConfig struct {
 	// Type is the storage backend (memory, badger, redis).
 	Type string `mapstructure:"type" validate:"oneof=memory badger redis"`
 
+	// Encoding is how badger stores workflow and task records: json or
+	// protobuf. Protobuf records are smaller and faster to decode; existing
+	// records are detected and migrated to the configured encoding on start.
+	Encoding string `mapstructure:"encoding" validate:"omitempty,oneof=json protobuf"`
+
 	// Badger is the BadgerDB configuration.
 	Badger BadgerConfig `mapstructure:"badger"`
 
 	// Redis is the Redis configuration.
 	Redis RedisConfig `mapstructure:"redis"`
+
+	// TaskWrites controls how task state transitions are persisted.
+	TaskWrites TaskWritesConfig `mapstructure:"task_writes"`
+
+	// CacheSize is the number of workflow and task records kept in the
+	// read-through LRU cache in front of the badger backend. Zero disables
+	// it.
+	CacheSize int `mapstructure:"cache_size" validate:"min=0"`
+
+	// Encryption seals the configs and results of sensitive tasks before
+	// they are persisted.
+	Encryption StorageEncryptionConfig `mapstructure:"encryption"`
+}
+
+// StorageEncryptionConfig holds the envelope encryption of sensitive task
+// data: every value is encrypted with its own data key, which is stored
+// encrypted with the master key next to it.
+type StorageEncryptionConfig struct {
+	// MasterKey is the base64 encoded 32-byte AES-256 master key, usually a
+	// secret reference such as env://GOCLAW_MASTER_KEY. Empty stores
+	// sensitive data unencrypted.
+	MasterKey string `mapstructure:"master_key"`
+}
+
+// TaskWritesConfig holds the write mode of task state transitions.
+type TaskWritesConfig struct {
+	// Mode is sync to persist every transition before the task moves on, or
+	// batch to buffer transitions and commit them per workflow in groups.
+	// A crash loses at most the transitions of the last flush interval; the
+	// workflow record is never persisted ahead of its tasks, and task reads
+	// may lag behind by up to FlushInterval.
+	Mode string `mapstructure:"mode" validate:"omitempty,oneof=sync batch"`
+
+	// FlushInterval is the longest a transition stays buffered in batch mode.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// MaxBatch flushes a workflow early once this many of its tasks are
+	// buffered in batch mode.
+	MaxBatch int `mapstructure:"max_batch"`
 }
 
 // BadgerConfig holds BadgerDB-specific settings.
@@ -342,6 +774,18 @@ type MetricsConfig struct {
 
 	// Port is the metrics server port.
 	Port int `mapstructure:"port" validate:"min=1,max=65535"`
+
+	// WorkflowLabel labels the workflow and task submission and duration
+	// metrics with the workflow name.
+	WorkflowLabel bool `mapstructure:"workflow_label"`
+
+	// TenantLabel labels the same metrics with the tenant, read from the
+	// workflow metadata key orchestration.costs.tenant_key.
+	TenantLabel bool `mapstructure:"tenant_label"`
+
+	// MaxLabelValues bounds the distinct workflow names and tenants labelled;
+	// later ones are reported as "other". Zero uses the default of 100.
+	MaxLabelValues int `mapstructure:"max_label_values" validate:"min=0"`
 }
 
 // TracingConfig holds distributed tracing settings (Phase 3).
@@ -444,6 +888,11 @@ type RedisLaneConfig struct {
 	// DB is the Redis database number.
 	DB int `mapstructure:"db" validate:"min=0,max=15"`
 
+	// KeyPrefix namespaces every Redis key and channel (lanes, signals,
+	// locks, rate limits and the LLM cache) so several environments can
+	// share one Redis, e.g. "staging:".
+	KeyPrefix string `mapstructure:"key_prefix" validate:"max=64"`
+
 	// MaxRetries is the maximum number of retries before giving up.
 	MaxRetries int `mapstructure:"max_retries" validate:"min=0"`
 
@@ -464,6 +913,67 @@ type RedisLaneConfig struct {
 
 	// Sentinel holds Redis Sentinel configuration.
 	Sentinel RedisSentinelConfig `mapstructure:"sentinel"`
+
+	// Cluster holds Redis Cluster configuration.
+	Cluster RedisClusterConfig `mapstructure:"cluster"`
+}
+
+// Mode returns the Redis deployment mode: "cluster", "sentinel" or "single".
+func (c RedisLaneConfig) Mode() string {
+	switch {
+	case c.Cluster.Enabled:
+		return "cluster"
+	case c.Sentinel.Enabled:
+		return "sentinel"
+	default:
+		return "single"
+	}
+}
+
+// NewClient returns a client for the configured deployment mode. The server
+// and the doctor both connect through it, so they agree on the options.
+func (c RedisLaneConfig) NewClient() redis.UniversalClient {
+	switch c.Mode() {
+	case "cluster":
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:          c.Cluster.Addresses,
+			Password:       c.Password,
+			MaxRedirects:   c.Cluster.MaxRedirects,
+			ReadOnly:       c.Cluster.ReadOnly,
+			RouteByLatency: c.Cluster.RouteByLatency,
+			MaxRetries:     c.MaxRetries,
+			PoolSize:       c.PoolSize,
+			MinIdleConns:   c.MinIdleConns,
+			DialTimeout:    c.DialTimeout,
+			ReadTimeout:    c.ReadTimeout,
+			WriteTimeout:   c.WriteTimeout,
+		})
+	case "sentinel":
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    c.Sentinel.MasterName,
+			SentinelAddrs: c.Sentinel.Addresses,
+			Password:      c.Password,
+			DB:            c.DB,
+			MaxRetries:    c.MaxRetries,
+			PoolSize:      c.PoolSize,
+			MinIdleConns:  c.MinIdleConns,
+			DialTimeout:   c.DialTimeout,
+			ReadTimeout:   c.ReadTimeout,
+			WriteTimeout:  c.WriteTimeout,
+		})
+	default:
+		return redis.NewClient(&redis.Options{
+			Addr:         c.Address,
+			Password:     c.Password,
+			DB:           c.DB,
+			MaxRetries:   c.MaxRetries,
+			PoolSize:     c.PoolSize,
+			MinIdleConns: c.MinIdleConns,
+			DialTimeout:  c.DialTimeout,
+			ReadTimeout:  c.ReadTimeout,
+			WriteTimeout: c.WriteTimeout,
+		})
+	}
 }
 
 // RedisSentinelConfig holds Redis Sentinel settings.
@@ -478,6 +988,24 @@ type RedisSentinelConfig struct {
 	Addresses []string `mapstructure:"addresses"`
 }
 
+// RedisClusterConfig holds Redis Cluster settings.
+type RedisClusterConfig struct {
+	// Enabled enables Cluster mode. Address and DB are ignored when set.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Addresses is the list of seed node addresses.
+	Addresses []string `mapstructure:"addresses"`
+
+	// MaxRedirects is the maximum number of MOVED/ASK redirects to follow.
+	MaxRedirects int `mapstructure:"max_redirects" validate:"min=0"`
+
+	// ReadOnly allows read commands to be served by replica nodes.
+	ReadOnly bool `mapstructure:"read_only"`
+
+	// RouteByLatency routes read-only commands to the closest node.
+	RouteByLatency bool `mapstructure:"route_by_latency"`
+}
+
 // SignalConfig holds Signal Bus configuration.
 type SignalConfig struct {
 	// Mode is the signal bus backend (local or redis).
@@ -488,6 +1016,55 @@ type SignalConfig struct {
 
 	// ChannelPrefix is the Redis channel prefix for signals.
 	ChannelPrefix string `mapstructure:"channel_prefix"`
+
+	// Delivery is the delivery guarantee of the redis bus: at_most_once
+	// (Pub/Sub) or at_least_once (Redis Streams with consumer acks).
+	Delivery string `mapstructure:"delivery" validate:"omitempty,oneof=at_most_once at_least_once"`
+
+	// AckTimeout is how long a delivered signal may stay unacknowledged
+	// before it is redelivered (at_least_once only).
+	AckTimeout time.Duration `mapstructure:"ack_timeout"`
+
+	// MaxDeliveries is the number of delivery attempts before an
+	// unacknowledged signal is dropped; 0 means unlimited (at_least_once only).
+	MaxDeliveries int `mapstructure:"max_deliveries" validate:"min=0"`
+
+	// History configures the per-channel signal history.
+	History SignalHistoryConfig `mapstructure:"history"`
+
+	// Schemas are JSON Schemas that published signal payloads must match.
+	Schemas []SignalSchemaConfig `mapstructure:"schemas"`
+}
+
+// SignalSchemaConfig registers a payload schema for a set of channels.
+type SignalSchemaConfig struct {
+	// Channel is a glob pattern for the channels (task IDs) the schema applies to.
+	Channel string `mapstructure:"channel"`
+
+	// Type restricts the schema to one signal type (steer, interrupt or
+	// collect); empty applies it to every type.
+	Type string `mapstructure:"type"`
+
+	// Schema is the inline JSON Schema document.
+	Schema map[string]interface{} `mapstructure:"schema"`
+
+	// File is the path of a JSON Schema document, used instead of Schema.
+	File string `mapstructure:"file"`
+}
+
+// SignalHistoryConfig holds signal history settings.
+type SignalHistoryConfig struct {
+	// Enabled controls whether published signals are recorded.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Size is the number of signals kept per channel.
+	Size int `mapstructure:"size" validate:"min=0"`
+
+	// MaxChannels is the number of channels kept in memory (local mode).
+	MaxChannels int `mapstructure:"max_channels" validate:"min=0"`
+
+	// TTL expires the history of idle channels (redis mode).
+	TTL time.Duration `mapstructure:"ttl"`
 }
 
 // SagaConfig holds Saga orchestration settings.
@@ -513,6 +1090,10 @@ type SagaConfig struct {
 	// WALCleanupInterval controls how often background cleanup runs.
 	WALCleanupInterval time.Duration `mapstructure:"wal_cleanup_interval"`
 
+	// ArchiveEnabled makes cleanup archive terminal sagas older than
+	// WALRetention, with their WAL, before pruning them.
+	ArchiveEnabled bool `mapstructure:"archive_enabled"`
+
 	// CompensationPolicy controls behavior on failure: auto, manual, or skip.
 	CompensationPolicy string `mapstructure:"compensation_policy" validate:"oneof=auto manual skip"`
 
@@ -527,6 +1108,19 @@ type SagaConfig struct {
 
 	// CompensationBackoffFactor is the exponential multiplier for retries.
 	CompensationBackoffFactor float64 `mapstructure:"compensation_backoff_factor" validate:"min=1"`
+
+	// StuckRunningTimeout is how long a running saga may go without
+	// completing a step before the watchdog moves it to pending-compensation.
+	// Zero disables the check.
+	StuckRunningTimeout time.Duration `mapstructure:"stuck_running_timeout"`
+
+	// StuckCompensatingTimeout is how long a compensating saga may go without
+	// compensating a step before the watchdog reports it. Zero disables the
+	// check.
+	StuckCompensatingTimeout time.Duration `mapstructure:"stuck_compensating_timeout"`
+
+	// WatchdogInterval is how often the watchdog looks for stuck sagas.
+	WatchdogInterval time.Duration `mapstructure:"watchdog_interval"`
 }
 
 // Validate performs validation on the configuration.
@@ -568,6 +1162,435 @@ func normalizeTracingConfig(cfg *Config) {
 
 // String returns a string representation of the configuration (without sensitive data).
 func (c *Config) String() string {
-	return fmt.Sprintf("Config{App: %s, Server: :%d, Env: %s}",
-		c.App.Name, c.Server.Port, c.App.Environment)
+	return fmt.Sprintf("Config{App: %s, Server: :%d, Env: %s, Redis: %s, RedisPassword: %s}",
+		c.App.Name, c.Server.Port, c.App.Environment, c.Redis.Address, MaskSecret(c.Redis.Password))
+}
+
+// ArtifactsConfig holds the store of files produced by tasks.
+type ArtifactsConfig struct {
+	// Enabled controls whether tasks can upload and download artifacts.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Backend is where artifact contents are kept: local or s3.
+	Backend string `mapstructure:"backend" validate:"omitempty,oneof=local s3"`
+
+	// Dir is the root directory of the local backend.
+	Dir string `mapstructure:"dir"`
+
+	// S3 is the S3 backend configuration.
+	S3 ArtifactsS3Config `mapstructure:"s3"`
+
+	// Retention is how long artifacts are kept after upload. Zero keeps
+	// them until their workflow's artifacts are deleted.
+	Retention time.Duration `mapstructure:"retention"`
+
+	// SweepInterval is how often expired artifacts are deleted.
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+
+	// MaxSize is the largest artifact accepted, in bytes. Zero means no
+	// limit.
+	MaxSize int64 `mapstructure:"max_size" validate:"min=0"`
+}
+
+// ArtifactsS3Config holds the S3 bucket artifacts are stored in. Any
+// S3-compatible service, such as MinIO, works through Endpoint.
+type ArtifactsS3Config struct {
+	// Bucket is the bucket name.
+	Bucket string `mapstructure:"bucket"`
+
+	// Region is the bucket's region.
+	Region string `mapstructure:"region"`
+
+	// Endpoint overrides the AWS endpoint, e.g. http://localhost:9000.
+	// Requests use path-style addressing.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Prefix is prepended to every object key.
+	Prefix string `mapstructure:"prefix"`
+
+	// AccessKeyID is the access key. When empty, the AWS_ACCESS_KEY_ID
+	// environment credentials are used.
+	AccessKeyID string `mapstructure:"access_key_id"`
+
+	// SecretAccessKey is the secret key. Supports secret references.
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+
+	// SessionToken is the session token of temporary credentials.
+	SessionToken string `mapstructure:"session_token"`
+}
+
+// TaskLogsConfig bounds the in-memory store of task output.
+type TaskLogsConfig struct {
+	// MaxLines is the number of most recent lines kept per task. Zero uses
+	// the default of 1000.
+	MaxLines int `mapstructure:"max_lines" validate:"min=0"`
+
+	// MaxTasks is the number of tasks whose output is kept; the oldest are
+	// dropped first. Zero uses the default of 1000.
+	MaxTasks int `mapstructure:"max_tasks" validate:"min=0"`
+}
+
+// LocksConfig configures task locks. With a Redis client the locks are
+// shared by every node using it; otherwise they are local to the process.
+type LocksConfig struct {
+	// TTL is how long a lock outlives a task, or node, that stopped renewing
+	// it. Locks of running tasks are renewed every third of it. Zero uses the
+	// default of 30s.
+	TTL time.Duration `mapstructure:"ttl" validate:"min=0"`
+
+	// RetryInterval is how often a task waiting for a lock tries to take it.
+	// Zero uses the default of 100ms.
+	RetryInterval time.Duration `mapstructure:"retry_interval" validate:"min=0"`
+}
+
+// RateLimitConfig is the budget of one external endpoint, refilled evenly
+// over its period.
+type RateLimitConfig struct {
+	// Limit is the number of requests allowed per Per.
+	Limit int `mapstructure:"limit" validate:"min=1"`
+
+	// Per is the period Limit applies to. Zero means one second.
+	Per time.Duration `mapstructure:"per" validate:"min=0"`
+
+	// Burst is the number of requests that may be made at once. Zero means
+	// Limit.
+	Burst int `mapstructure:"burst" validate:"min=0"`
+}
+
+// ToolConfig describes a tool agent tasks may call.
+type ToolConfig struct {
+	// Description tells planners what the tool does.
+	Description string `mapstructure:"description"`
+
+	// InputSchema is the JSON Schema of the tool's arguments.
+	InputSchema map[string]any `mapstructure:"input_schema"`
+
+	// Auth is how the tool authenticates: none, api_key, bearer or oauth2.
+	// Empty means none.
+	Auth string `mapstructure:"auth" validate:"omitempty,oneof=none api_key bearer oauth2"`
+
+	// Secrets name the orchestration.secrets the tool's credentials come
+	// from.
+	Secrets []string `mapstructure:"secrets"`
+
+	// RateLimit names the orchestration.rate_limits budget the tool's calls
+	// are taken from.
+	RateLimit string `mapstructure:"rate_limit"`
+
+	// Cost is the usage one call is expected to record per cost dimension,
+	// e.g. {"api_calls": 1, "llm_tokens": 2000}.
+	Cost map[string]float64 `mapstructure:"cost"`
+
+	// Disabled makes the tool unavailable to tenants not enabled for it
+	// through the API.
+	Disabled bool `mapstructure:"disabled"`
+
+	// Tenants, when set, are the only tenants the tool is available to
+	// unless enabled for others through the API.
+	Tenants []string `mapstructure:"tenants"`
+}
+
+// InsightsConfig holds the duration anomaly detector. A completed run is
+// flagged when it exceeds the z-score or the percentile of the recent runs of
+// its workflow or task.
+type InsightsConfig struct {
+	// Enabled learns durations and serves /api/v1/insights.
+	Enabled bool `mapstructure:"enabled"`
+
+	// ZScore is the number of standard deviations above the mean a run must
+	// exceed. Zero disables the check.
+	ZScore float64 `mapstructure:"z_score" validate:"min=0"`
+
+	// Percentile flags runs slower than this percentile of the recent runs,
+	// e.g. 0.99. Zero disables the check.
+	Percentile float64 `mapstructure:"percentile" validate:"min=0,max=1"`
+
+	// MinSamples is the number of runs learned before any is flagged.
+	MinSamples int `mapstructure:"min_samples" validate:"min=0"`
+
+	// Window is the number of most recent runs kept per workflow and task.
+	// Zero uses the default of 500.
+	Window int `mapstructure:"window" validate:"min=0"`
+
+	// MaxAnomalies is the number of most recent anomalies kept. Zero uses
+	// the default of 100.
+	MaxAnomalies int `mapstructure:"max_anomalies" validate:"min=0"`
+}
+
+// CostsConfig holds the cost accounting of workflow runs. Runs accumulate
+// usage per dimension: task_seconds:<lane> for the time tasks ran in each
+// lane, and whatever tasks report through engine.RecordUsage, such as
+// llm_tokens and api_calls.
+type CostsConfig struct {
+	// Enabled accounts usage and serves /api/v1/costs.
+	Enabled bool `mapstructure:"enabled"`
+
+	// TenantKey is the workflow metadata key naming the tenant a run is
+	// charged to.
+	TenantKey string `mapstructure:"tenant_key"`
+
+	// Currency labels the costs, e.g. USD.
+	Currency string `mapstructure:"currency"`
+
+	// Rates is the price of one unit per dimension. A dimension without a
+	// rate of its own, such as task_seconds:gpu, uses the rate of the part
+	// before the colon, such as task_seconds; unpriced usage costs nothing.
+	Rates map[string]float64 `mapstructure:"rates"`
+}
+
+// BudgetsConfig holds the tenant budgets. A run whose tenant's runs created
+// in the last Period exceed its budget fails and its unfinished tasks are
+// cancelled, as are runs exceeding a budget of their own.
+type BudgetsConfig struct {
+	// Tenants is the budget of each tenant, by the tenant named under
+	// costs.tenant_key.
+	Tenants map[string]BudgetConfig `mapstructure:"tenants" validate:"dive"`
+
+	// Period is the rolling window tenant budgets apply to. Zero uses the
+	// default of 24h.
+	Period time.Duration `mapstructure:"period" validate:"min=0"`
+
+	// CheckInterval is how often running runs with a budget are checked
+	// besides whenever their tasks record usage. Zero uses the default of
+	// 1s.
+	CheckInterval time.Duration `mapstructure:"check_interval" validate:"min=0"`
+}
+
+// BudgetConfig caps usage. Zero fields are unlimited.
+type BudgetConfig struct {
+	// Tokens caps the llm_tokens recorded.
+	Tokens float64 `mapstructure:"tokens" validate:"min=0"`
+
+	// Cost caps the usage priced with costs.rates.
+	Cost float64 `mapstructure:"cost" validate:"min=0"`
+
+	// TaskSeconds caps the wall-clock seconds tasks spend running.
+	TaskSeconds float64 `mapstructure:"task_seconds" validate:"min=0"`
+}
+
+// LLMCacheConfig holds the cache of LLM calls, keyed by their model, prompt
+// and parameters.
+type LLMCacheConfig struct {
+	// Enabled caches LLM calls.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Backend keeps the responses: memory, badger, or redis, which shares
+	// them across nodes.
+	Backend string `mapstructure:"backend" validate:"omitempty,oneof=memory badger redis"`
+
+	// Path is the directory of the badger backend.
+	Path string `mapstructure:"path"`
+
+	// TTL is how long a response is reused. Zero uses the default of 24h.
+	TTL time.Duration `mapstructure:"ttl" validate:"min=0"`
+
+	// MaxEntries bounds the memory backend. Zero uses the default of
+	// 10000.
+	MaxEntries int `mapstructure:"max_entries" validate:"min=0"`
+}
+
+// ModelRouteConfig holds a route of LLM calls: the target calls are made
+// with and the targets they fall back to.
+type ModelRouteConfig struct {
+	// Primary is the target calls are made with first.
+	Primary ModelTargetConfig `mapstructure:"primary"`
+
+	// Fallbacks are tried in order when the target before fails.
+	Fallbacks []ModelTargetConfig `mapstructure:"fallbacks" validate:"dive"`
+
+	// Retry, when set, replaces Primary for the retries of a task, e.g. with
+	// a cheaper model.
+	Retry *ModelTargetConfig `mapstructure:"retry"`
+
+	// FallbackOn is which failures move a call to the next target: error,
+	// any failure but cancellation, or rate_limit, only rate limit errors.
+	// Empty means error.
+	FallbackOn string `mapstructure:"fallback_on" validate:"omitempty,oneof=error rate_limit"`
+}
+
+// ModelTargetConfig names a provider and a model of it.
+type ModelTargetConfig struct {
+	Provider string `mapstructure:"provider" validate:"required"`
+	Model    string `mapstructure:"model" validate:"required"`
+}
+
+// OutboxConfig holds the outbox of workflow notifications. Workflow state
+// changes and SLA breaches are recorded in the storage in the same write as
+// the change, and a dispatcher delivers them to the webhooks until they are
+// accepted, so none is lost when the process crashes or a receiver is down.
+type OutboxConfig struct {
+	// Enabled delivers workflow.state_changed and workflow.sla_breached
+	// webhooks through the outbox. It requires the management API.
+	Enabled bool `mapstructure:"enabled"`
+
+	// PollInterval is how often the dispatcher looks for due entries
+	// besides right after a state change. Zero uses the default of 1s.
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"min=0"`
+
+	// MaxBackoff caps the delay between the delivery attempts of an entry,
+	// which doubles from PollInterval. Zero uses the default of 5m.
+	MaxBackoff time.Duration `mapstructure:"max_backoff" validate:"min=0"`
+
+	// Retention is how long undelivered entries are retried before they are
+	// dropped. Zero keeps them until they are delivered.
+	Retention time.Duration `mapstructure:"retention" validate:"min=0"`
+}
+
+// ContainersConfig holds the executor of "container" tasks.
+type ContainersConfig struct {
+	// Enabled controls whether container tasks can run.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Runtime runs the containers: docker or kubernetes.
+	Runtime string `mapstructure:"runtime" validate:"omitempty,oneof=docker kubernetes"`
+
+	// Docker is the Docker Engine configuration.
+	Docker DockerConfig `mapstructure:"docker"`
+
+	// Kubernetes is the Kubernetes Jobs configuration.
+	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
+}
+
+// DockerConfig holds the Docker Engine API endpoint.
+type DockerConfig struct {
+	// Host is the Docker daemon address, unix:///path or tcp://host:port.
+	Host string `mapstructure:"host"`
+
+	// Pull pulls images missing on the daemon before creating containers.
+	Pull bool `mapstructure:"pull"`
+}
+
+// KubernetesConfig holds where container tasks run as Kubernetes Jobs.
+type KubernetesConfig struct {
+	// APIServer is the API server URL. When empty, the in-cluster address
+	// from KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT is used.
+	APIServer string `mapstructure:"api_server"`
+
+	// Namespace is the namespace Jobs are created in.
+	Namespace string `mapstructure:"namespace"`
+
+	// TokenFile holds the bearer token of the service account.
+	TokenFile string `mapstructure:"token_file"`
+
+	// CAFile is the CA bundle verifying the API server.
+	CAFile string `mapstructure:"ca_file"`
+
+	// ServiceAccount is the service account Job pods run as.
+	ServiceAccount string `mapstructure:"service_account"`
+
+	// TTLAfterFinished is how long finished Jobs are kept before
+	// Kubernetes deletes them.
+	TTLAfterFinished time.Duration `mapstructure:"ttl_after_finished"`
+}
+
+// OperatorConfig holds the controller reconciling goclaw custom resources
+// (Workflow and Schedule) into workflow submissions.
+type OperatorConfig struct {
+	// Enabled controls whether the controller runs.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Namespace is the namespace whose resources are reconciled. Empty
+	// reconciles all namespaces.
+	Namespace string `mapstructure:"namespace"`
+
+	// ResyncInterval is how often resources are reconciled.
+	ResyncInterval time.Duration `mapstructure:"resync_interval"`
+
+	// APIServer is the API server URL. When empty, the in-cluster address
+	// from KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT is used.
+	APIServer string `mapstructure:"api_server"`
+
+	// TokenFile holds the bearer token of the service account.
+	TokenFile string `mapstructure:"token_file"`
+
+	// CAFile is the CA bundle verifying the API server.
+	CAFile string `mapstructure:"ca_file"`
+}
+
+// ManageConfig holds the declarative management API for workflow templates,
+// schedules, webhooks and lanes.
+type ManageConfig struct {
+	// Enabled serves /api/v1/manage and runs the managed schedules and
+	// webhooks. Managed resources are saved in the storage of the node that
+	// received them and reloaded on startup.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxParallelBackfill is the number of runs of a schedule backfill
+	// unfinished at once; requests may lower it.
+	MaxParallelBackfill int `mapstructure:"max_parallel_backfill"`
+
+	// CallbackSecret signs the POSTs of finished runs' status to the
+	// callback_url they were submitted with. Callbacks are posted whether or
+	// not the management API is enabled.
+	CallbackSecret string `mapstructure:"callback_secret"`
+
+	// CallbackAllowedHosts are the host names of callback URLs that may
+	// resolve to loopback, link-local or private addresses. Callbacks to
+	// other hosts are only posted to public addresses.
+	CallbackAllowedHosts []string `mapstructure:"callback_allowed_hosts"`
+
+	// Webhooks are webhooks declared by name in the configuration rather
+	// than through the API. They are registered with the managed webhooks
+	// and follow config reloads.
+	Webhooks map[string]WebhookConfig `mapstructure:"webhooks" validate:"dive"`
+}
+
+// WebhookConfig is a webhook receiving workflow events.
+type WebhookConfig struct {
+	// URL receives a POST for every matching event.
+	URL string `mapstructure:"url" validate:"required,url"`
+
+	// Events are the event types to deliver; empty delivers every event.
+	Events []string `mapstructure:"events"`
+
+	// Format is json (the default) or slack.
+	Format string `mapstructure:"format" validate:"omitempty,oneof=json slack"`
+
+	// Secret signs deliveries.
+	Secret string `mapstructure:"secret"`
+}
+
+// PolicyConfig holds the Open Policy Agent check of workflow and saga
+// submissions and config updates. Requests the policy denies are rejected
+// and every decision is logged as an audit record.
+type PolicyConfig struct {
+	// Enabled evaluates submissions and config updates against the policy.
+	Enabled bool `mapstructure:"enabled"`
+
+	// URL is the OPA data API URL of the decision document, e.g.
+	// http://localhost:8181/v1/data/goclaw/admission.
+	URL string `mapstructure:"url"`
+
+	// Timeout bounds each policy query. Zero uses the default of 2s.
+	Timeout time.Duration `mapstructure:"timeout" validate:"min=0"`
+
+	// FailOpen admits requests when the policy cannot be evaluated instead
+	// of rejecting them.
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// ScrubConfig holds the rules removing customer data from event payloads,
+// webhook bodies and structured logs before they leave the process.
+type ScrubConfig struct {
+	// Rules are applied in order.
+	Rules []ScrubRule `mapstructure:"rules"`
+}
+
+// ScrubRule removes the matches of a regular expression, or the values at
+// field paths, or the matches of the expression at the field paths.
+type ScrubRule struct {
+	// Name identifies the rule in validation errors.
+	Name string `mapstructure:"name"`
+
+	// Pattern is a regular expression whose matches are replaced.
+	Pattern string `mapstructure:"pattern"`
+
+	// Fields are dot separated field paths, where "*" matches any key, of
+	// event payloads and log attributes, e.g. "result.email" or
+	// "metadata.*". Without a pattern their values are replaced whole.
+	Fields []string `mapstructure:"fields"`
+
+	// Replacement replaces the removed data, "[REDACTED]" by default.
+	// Submatches of the pattern can be referred to as $1.
+	Replacement string `mapstructure:"replacement"`
 }
@@ -5,6 +5,12 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -391,7 +397,7 @@ func TestLoader_LoadInvalidFile(t *testing.T) {
 
 func TestLoader_LoadUnsupportedFormat(t *testing.T) {
 	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "config.toml")
+	configPath := filepath.Join(tmpDir, "config.ini")
 
 	if err := os.WriteFile(configPath, []byte("app = 'test'"), 0644); err != nil {
 		t.Fatalf("failed to write config: %v", err)
@@ -404,6 +410,202 @@ func TestLoader_LoadUnsupportedFormat(t *testing.T) {
 	}
 }
 
+func TestLoader_LoadTOMLFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	tomlContent := `
+[app]
+name = "toml-test"
+environment = "staging"
+
+[server]
+port = 7070
+
+[server.grpc]
+port = 9595
+
+[log]
+level = "warn"
+
+[orchestration]
+max_agents = 42
+
+[orchestration.queue]
+rate_limit = 2.5
+
+[saga]
+default_timeout = "2m"
+`
+	if err := os.WriteFile(configPath, []byte(tomlContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.App.Name != "toml-test" {
+		t.Errorf("expected 'toml-test', got '%s'", cfg.App.Name)
+	}
+	if cfg.Server.Port != 7070 {
+		t.Errorf("expected 7070, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.GRPC.Port != 9595 {
+		t.Errorf("expected grpc port 9595, got %d", cfg.Server.GRPC.Port)
+	}
+	if cfg.Log.Level != "warn" {
+		t.Errorf("expected 'warn', got '%s'", cfg.Log.Level)
+	}
+	if cfg.Orchestration.MaxAgents != 42 {
+		t.Errorf("expected max_agents 42, got %d", cfg.Orchestration.MaxAgents)
+	}
+	if cfg.Orchestration.Queue.RateLimit != 2.5 {
+		t.Errorf("expected rate_limit 2.5, got %v", cfg.Orchestration.Queue.RateLimit)
+	}
+	if cfg.Saga.DefaultTimeout != 2*time.Minute {
+		t.Errorf("expected saga.default_timeout 2m, got %s", cfg.Saga.DefaultTimeout)
+	}
+}
+
+func TestLoader_FormatRoundTrip(t *testing.T) {
+	want := DefaultConfig()
+	want.App.Name = "round-trip"
+	want.Server.Port = 8181
+	want.Log.Level = "debug"
+	want.Orchestration.MaxAgents = 7
+	want.Saga.DefaultTimeout = 90 * time.Second
+
+	parsers := map[string]koanf.Parser{
+		"yaml": yaml.Parser(),
+		"json": json.Parser(),
+		"toml": toml.Parser(),
+	}
+
+	for ext, parser := range parsers {
+		t.Run(ext, func(t *testing.T) {
+			k := koanf.New(Delimiter)
+			if err := k.Load(confmap.Provider(structToMap(want, ""), Delimiter), nil); err != nil {
+				t.Fatalf("failed to load config map: %v", err)
+			}
+			data, err := k.Marshal(parser)
+			if err != nil {
+				t.Fatalf("failed to marshal %s: %v", ext, err)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config."+ext)
+			if err := os.WriteFile(configPath, data, 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			got, err := NewLoader().Load(configPath, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// Compare flattened values: decoders yield empty rather than nil slices.
+			for _, change := range Diff(want, got) {
+				t.Errorf("%s: expected %v, got %v", change.Key, change.Old, change.New)
+			}
+		})
+	}
+}
+
+func TestLoader_EnvFile(t *testing.T) {
+	envPath := filepath.Join(t.TempDir(), ".env")
+	envContent := `# deployment settings
+GOCLAW_APP_NAME=dotenv-test
+GOCLAW_ORCHESTRATION_MAX_AGENTS=25
+GOCLAW_SERVER_GRPC_PORT=9393
+UNRELATED_VAR=ignored
+`
+	if err := os.WriteFile(envPath, []byte(envContent), 0644); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	loader := NewLoader()
+	loader.SetEnvFile(envPath)
+	cfg, err := loader.Load("", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.App.Name != "dotenv-test" {
+		t.Errorf("expected 'dotenv-test', got '%s'", cfg.App.Name)
+	}
+	if cfg.Orchestration.MaxAgents != 25 {
+		t.Errorf("expected max_agents 25, got %d", cfg.Orchestration.MaxAgents)
+	}
+	if cfg.Server.GRPC.Port != 9393 {
+		t.Errorf("expected grpc port 9393, got %d", cfg.Server.GRPC.Port)
+	}
+}
+
+func TestLoader_EnvFileMissing(t *testing.T) {
+	loader := NewLoader()
+	loader.SetEnvFile(filepath.Join(t.TempDir(), "missing.env"))
+	if _, err := loader.Load("", nil); err == nil {
+		t.Error("expected error for missing env file")
+	}
+}
+
+func TestLoader_Precedence(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	tomlContent := `
+[app]
+name = "from-file"
+
+[server]
+port = 7001
+
+[log]
+level = "warn"
+
+[orchestration]
+max_agents = 3
+`
+	if err := os.WriteFile(configPath, []byte(tomlContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	envPath := filepath.Join(tmpDir, ".env")
+	envContent := "GOCLAW_SERVER_PORT=7002\nGOCLAW_LOG_LEVEL=error\nGOCLAW_ORCHESTRATION_MAX_AGENTS=4\n"
+	if err := os.WriteFile(envPath, []byte(envContent), 0644); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	t.Setenv("GOCLAW_LOG_LEVEL", "debug")
+	t.Setenv("GOCLAW_ORCHESTRATION_MAX_AGENTS", "5")
+
+	loader := NewLoader()
+	loader.SetEnvFile(envPath)
+	cfg, err := loader.Load(configPath, map[string]interface{}{
+		"orchestration.max_agents": 6,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// defaults < file
+	if cfg.App.Name != "from-file" {
+		t.Errorf("expected app name from file, got '%s'", cfg.App.Name)
+	}
+	// file < .env file
+	if cfg.Server.Port != 7002 {
+		t.Errorf("expected port from env file, got %d", cfg.Server.Port)
+	}
+	// .env file < process env
+	if cfg.Log.Level != "debug" {
+		t.Errorf("expected log level from env, got '%s'", cfg.Log.Level)
+	}
+	// process env < CLI
+	if cfg.Orchestration.MaxAgents != 6 {
+		t.Errorf("expected max_agents from overrides, got %d", cfg.Orchestration.MaxAgents)
+	}
+}
+
 func TestLoader_EnvVars(t *testing.T) {
 	// Set environment variables
 	if err := os.Setenv("GOCLAW_APP_NAME", "env-test"); err != nil {
@@ -440,61 +642,131 @@ func TestLoader_EnvVars(t *testing.T) {
 	}
 }
 
-func TestGRPCConfig_ToGRPCConfig(t *testing.T) {
+func TestValidation_GRPCAuthRequiresTokens(t *testing.T) {
 	cfg := DefaultConfig()
-	grpcCfg := cfg.Server.GRPC.ToGRPCConfig()
+	cfg.Server.GRPC.Enabled = true
+	cfg.Server.GRPC.Interceptors.Auth.Enabled = true
 
-	if grpcCfg == nil {
-		t.Fatal("expected non-nil grpc config")
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
+	}
+	if details[0].Field != "Config.Server.GRPC.Interceptors.Auth.Tokens" {
+		t.Fatalf("expected error on auth tokens, got %s", details[0].Field)
 	}
+}
+
+func TestValidation_GRPCKeepaliveTimeout(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.GRPC.Enabled = true
+	cfg.Server.GRPC.Keepalive.TimeSeconds = 10
+	cfg.Server.GRPC.Keepalive.TimeoutSeconds = 10
 
-	// Check address format
-	if grpcCfg.Address != ":9090" {
-		t.Errorf("expected ':9090', got '%s'", grpcCfg.Address)
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
 	}
+	if details[0].Field != "Config.Server.GRPC.Keepalive.TimeoutSeconds" {
+		t.Fatalf("expected error on keepalive timeout, got %s", details[0].Field)
+	}
+}
 
-	// Check default values
-	if !grpcCfg.EnableTracing {
-		t.Error("expected gRPC tracing to be enabled by default")
+func TestValidation_GRPCAgentsHeartbeat(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.GRPC.Enabled = true
+	cfg.Server.GRPC.Agents.Enabled = true
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected default agent settings to be valid, got %v", err)
 	}
-	if grpcCfg.MaxConnections != 1000 {
-		t.Errorf("expected 1000, got %d", grpcCfg.MaxConnections)
+
+	cfg.Server.GRPC.Agents.HeartbeatTimeoutSeconds = 5
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
 	}
-	if grpcCfg.MaxRecvMsgSize != 4*1024*1024 {
-		t.Errorf("expected %d, got %d", 4*1024*1024, grpcCfg.MaxRecvMsgSize)
+	if details[0].Field != "Config.Server.GRPC.Agents.HeartbeatTimeoutSeconds" {
+		t.Fatalf("expected error on heartbeat timeout, got %s", details[0].Field)
 	}
 }
 
-func TestGRPCConfig_ToGRPCConfig_WithTLS(t *testing.T) {
+func TestValidation_TaskEnvironments(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.Server.GRPC.TLS = GRPCTLSConfig{
-		Enabled:    true,
-		CertFile:   "/path/to/cert.pem",
-		KeyFile:    "/path/to/key.pem",
-		CAFile:     "/path/to/ca.pem",
-		ClientAuth: true,
+	cfg.Orchestration.Environments = map[string]map[string]string{
+		"reporting": {"DB_HOST": "db.internal", "1BAD": "x"},
+	}
+
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
+	}
+	if details[0].Field != "Config.Orchestration.Environments[reporting]" || details[0].Value != "1BAD" {
+		t.Fatalf("unexpected validation error %+v", details[0])
 	}
+}
 
-	grpcCfg := cfg.Server.GRPC.ToGRPCConfig()
+func TestValidation_ArtifactsS3RequiresBucket(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Artifacts.Enabled = true
+	cfg.Artifacts.Backend = "s3"
+	cfg.Artifacts.S3.Region = "us-east-1"
 
-	if grpcCfg.TLS == nil {
-		t.Fatal("expected non-nil TLS config")
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
 	}
-	if !grpcCfg.TLS.Enabled {
-		t.Error("expected TLS to be enabled")
+	if details[0].Field != "Config.Artifacts.S3.Bucket" {
+		t.Fatalf("unexpected validation error %+v", details[0])
 	}
-	if grpcCfg.TLS.CertFile != "/path/to/cert.pem" {
-		t.Errorf("expected '/path/to/cert.pem', got '%s'", grpcCfg.TLS.CertFile)
+
+	cfg.Artifacts.S3.Bucket = "goclaw-artifacts"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
 	}
 }
 
-func TestGRPCConfig_ToGRPCConfig_TracingToggle(t *testing.T) {
+func TestValidation_AuthRoleMappings(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.Server.GRPC.EnableTracing = false
+	cfg.Server.Auth.RoleMappings = []RoleMappingConfig{
+		{SAN: "spiffe://goclaw/admin/*", Roles: []string{"admin"}},
+		{SAN: "spiffe://[", Roles: []string{"admin"}},
+	}
 
-	grpcCfg := cfg.Server.GRPC.ToGRPCConfig()
-	if grpcCfg.EnableTracing {
-		t.Fatal("expected gRPC tracing to be disabled")
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
+	}
+	if details[0].Field != "Config.Server.Auth.RoleMappings[1].SAN" {
+		t.Fatalf("expected error on second mapping, got %s", details[0].Field)
+	}
+
+	mappings := cfg.Server.Auth.IdentityMappings()
+	if len(mappings) != 2 || mappings[0].SAN != "spiffe://goclaw/admin/*" || mappings[0].Roles[0] != "admin" {
+		t.Fatalf("unexpected identity mappings %+v", mappings)
+	}
+}
+
+func TestValidation_HTTPTLSRequiresCAForClientAuth(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.HTTP.TLS = HTTPTLSConfig{
+		Enabled:    true,
+		CertFile:   "/path/to/cert.pem",
+		KeyFile:    "/path/to/key.pem",
+		ClientAuth: true,
+	}
+
+	err := ValidateWithDetails(cfg)
+	details, ok := err.(ValidationErrors)
+	if !ok || len(details) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
+	}
+	if details[0].Field != "Config.Server.HTTP.TLS.CAFile" {
+		t.Fatalf("expected error on CA file, got %s", details[0].Field)
 	}
 }
 
@@ -616,6 +888,19 @@ func TestValidateWithDetails_InvalidSagaConfig(t *testing.T) {
 	}
 }
 
+func TestValidate_SagaWatchdogNeedsInterval(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Saga.Enabled = true
+	cfg.Saga.StuckRunningTimeout = 10 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Saga.WatchdogInterval = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a stuck timeout without a watchdog interval")
+	}
+}
+
 func TestValidation_InvalidPort(t *testing.T) {
 	tests := []struct {
 		name    string
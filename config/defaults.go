@@ -1,6 +1,10 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/rediskey"
+)
 
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
@@ -32,12 +36,41 @@ func DefaultConfig() *Config {
 					MinTimeSeconds:      30,
 					PermitWithoutStream: false,
 				},
+				Interceptors: GRPCInterceptorsConfig{
+					RateLimit: GRPCRateLimitConfig{
+						Enabled:           false,
+						RequestsPerSecond: 100,
+						Burst:             200,
+					},
+					Logging: GRPCLoggingConfig{
+						Enabled: true,
+					},
+				},
+				Agents: GRPCAgentsConfig{
+					Enabled:                  false,
+					HeartbeatIntervalSeconds: 10,
+					HeartbeatTimeoutSeconds:  30,
+					TaskTypes:                []string{"script"},
+				},
+				Web: GRPCWebConfig{
+					Enabled: false,
+				},
 			},
 			HTTP: HTTPConfig{
 				ReadTimeout:    30 * time.Second,
 				WriteTimeout:   30 * time.Second,
 				IdleTimeout:    120 * time.Second,
 				MaxHeaderBytes: 1 << 20, // 1MB
+				DrainDelay:     5 * time.Second,
+				DrainTimeout:   20 * time.Second,
+				Compression: CompressionConfig{
+					Enabled: true,
+					Level:   5,
+					MinSize: 1024,
+				},
+			},
+			Auth: AuthConfig{
+				SensitiveDataRoles: []string{"admin"},
 			},
 		},
 		UI: UIConfig{
@@ -50,17 +83,86 @@ func DefaultConfig() *Config {
 			Level:  "info",
 			Format: "json",
 			Output: "stdout",
+			File: LogFileConfig{
+				MaxSizeMB:  100,
+				MaxBackups: 5,
+			},
+			Syslog: LogSyslogConfig{
+				Tag:      "goclaw",
+				Facility: "daemon",
+			},
+			Components: map[string]string{},
+			Sampling: LogSamplingConfig{
+				Initial:    100,
+				Thereafter: 100,
+				Tick:       time.Second,
+			},
 		},
 		Orchestration: OrchestrationConfig{
 			MaxAgents: 1000,
 			Queue: QueueConfig{
 				Type: "memory",
 				Size: 10000,
+				Adaptive: AdaptiveConcurrencyConfig{
+					MinConcurrency: 1,
+					Interval:       time.Second,
+				},
+				Dispatch: "fifo",
 			},
+			Lanes: map[string]NamedLaneConfig{},
 			Scheduler: SchedulerConfig{
 				Type:          "round_robin",
 				CheckInterval: 5 * time.Second,
 			},
+			Workflows: WorkflowLimitsConfig{
+				PerName: map[string]int{},
+				Policy:  "wait",
+			},
+			TaskLogs: TaskLogsConfig{
+				MaxLines: 1000,
+				MaxTasks: 1000,
+			},
+			Locks: LocksConfig{
+				TTL:           30 * time.Second,
+				RetryInterval: 100 * time.Millisecond,
+			},
+			RateLimits:       map[string]RateLimitConfig{},
+			Secrets:          map[string]string{},
+			Environments:     map[string]map[string]string{},
+			Tools:            map[string]ToolConfig{},
+			SLACheckInterval: 10 * time.Second,
+			Insights: InsightsConfig{
+				Enabled:      true,
+				ZScore:       3,
+				MinSamples:   20,
+				Window:       500,
+				MaxAnomalies: 100,
+			},
+			Costs: CostsConfig{
+				Enabled:   false,
+				TenantKey: "tenant",
+				Currency:  "USD",
+				Rates:     map[string]float64{},
+			},
+			Budgets: BudgetsConfig{
+				Tenants:       map[string]BudgetConfig{},
+				Period:        24 * time.Hour,
+				CheckInterval: time.Second,
+			},
+			LLMCache: LLMCacheConfig{
+				Enabled:    false,
+				Backend:    "memory",
+				Path:       "./data/llmcache",
+				TTL:        24 * time.Hour,
+				MaxEntries: 10000,
+			},
+			ModelRoutes: map[string]ModelRouteConfig{},
+			Outbox: OutboxConfig{
+				Enabled:      false,
+				PollInterval: time.Second,
+				MaxBackoff:   5 * time.Minute,
+				Retention:    72 * time.Hour,
+			},
 		},
 		Cluster: ClusterConfig{
 			Enabled: false,
@@ -75,7 +177,8 @@ func DefaultConfig() *Config {
 			},
 		},
 		Storage: StorageConfig{
-			Type: "memory",
+			Type:     "memory",
+			Encoding: "json",
 			Badger: BadgerConfig{
 				Path:              "./data/badger",
 				SyncWrites:        true,
@@ -87,11 +190,20 @@ func DefaultConfig() *Config {
 				Password: "",
 				DB:       0,
 			},
+			TaskWrites: TaskWritesConfig{
+				Mode:          "sync",
+				FlushInterval: 50 * time.Millisecond,
+				MaxBatch:      256,
+			},
+			CacheSize: 1024,
 		},
 		Metrics: MetricsConfig{
-			Enabled: true,
-			Path:    "/metrics",
-			Port:    9091,
+			Enabled:        true,
+			Path:           "/metrics",
+			Port:           9091,
+			WorkflowLabel:  false,
+			TenantLabel:    false,
+			MaxLabelValues: 100,
 		},
 		Tracing: TracingConfig{
 			Enabled:    false,
@@ -134,11 +246,23 @@ func DefaultConfig() *Config {
 			DialTimeout:  5 * time.Second,
 			ReadTimeout:  3 * time.Second,
 			WriteTimeout: 3 * time.Second,
+			Cluster: RedisClusterConfig{
+				MaxRedirects: 3,
+			},
 		},
 		Signal: SignalConfig{
 			Mode:          "local",
 			BufferSize:    16,
-			ChannelPrefix: "goclaw:signal:",
+			ChannelPrefix: rediskey.Root + "signal:",
+			Delivery:      "at_most_once",
+			AckTimeout:    30 * time.Second,
+			MaxDeliveries: 5,
+			History: SignalHistoryConfig{
+				Enabled:     true,
+				Size:        100,
+				MaxChannels: 1000,
+				TTL:         24 * time.Hour,
+			},
 		},
 		Saga: SagaConfig{
 			Enabled:                    false,
@@ -148,11 +272,53 @@ func DefaultConfig() *Config {
 			WALSyncMode:                "sync",
 			WALRetention:               7 * 24 * time.Hour,
 			WALCleanupInterval:         1 * time.Hour,
+			ArchiveEnabled:             true,
 			CompensationPolicy:         "auto",
 			CompensationMaxRetries:     3,
 			CompensationInitialBackoff: 100 * time.Millisecond,
 			CompensationMaxBackoff:     5 * time.Second,
 			CompensationBackoffFactor:  2.0,
+			StuckCompensatingTimeout:   15 * time.Minute,
+			WatchdogInterval:           30 * time.Second,
+		},
+		Artifacts: ArtifactsConfig{
+			Enabled:       false,
+			Backend:       "local",
+			Dir:           "./data/artifacts",
+			Retention:     7 * 24 * time.Hour,
+			SweepInterval: 1 * time.Hour,
+			MaxSize:       1 << 30,
+		},
+		Containers: ContainersConfig{
+			Enabled: false,
+			Runtime: "docker",
+			Docker: DockerConfig{
+				Host: "unix:///var/run/docker.sock",
+				Pull: true,
+			},
+			Kubernetes: KubernetesConfig{
+				Namespace:        "default",
+				TokenFile:        "/var/run/secrets/kubernetes.io/serviceaccount/token",
+				CAFile:           "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+				TTLAfterFinished: 10 * time.Minute,
+			},
+		},
+		Operator: OperatorConfig{
+			Enabled:        false,
+			Namespace:      "default",
+			ResyncInterval: 10 * time.Second,
+			TokenFile:      "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			CAFile:         "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		},
+		Manage: ManageConfig{
+			Enabled:             false,
+			MaxParallelBackfill: 4,
+			Webhooks:            map[string]WebhookConfig{},
+		},
+		Policy: PolicyConfig{
+			Enabled: false,
+			URL:     "http://localhost:8181/v1/data/goclaw/admission",
+			Timeout: 2 * time.Second,
 		},
 	}
 }
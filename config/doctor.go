@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CheckStatus is the outcome of a single doctor check.
+type CheckStatus string
+
+const (
+	// CheckPass means the check succeeded.
+	CheckPass CheckStatus = "pass"
+	// CheckFail means startup is expected to fail.
+	CheckFail CheckStatus = "fail"
+)
+
+// DoctorCheck is the result of one preflight check.
+type DoctorCheck struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// DoctorReport collects the results of all preflight checks.
+type DoctorReport struct {
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// OK reports whether no check failed.
+func (r *DoctorReport) OK() bool {
+	for _, check := range r.Checks {
+		if check.Status == CheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *DoctorReport) add(name string, status CheckStatus, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, DoctorCheck{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// doctorDialTimeout bounds each network probe performed by Doctor.
+const doctorDialTimeout = 3 * time.Second
+
+// Doctor validates cfg against the live environment before starting:
+// listen ports must be free, Redis must be reachable when required and
+// data directories must be writable.
+func Doctor(ctx context.Context, cfg *Config) *DoctorReport {
+	report := &DoctorReport{}
+	if cfg == nil {
+		report.add("config", CheckFail, "config is nil")
+		return report
+	}
+
+	if err := ValidateWithDetails(cfg); err != nil {
+		report.add("config", CheckFail, "%s", strings.TrimSpace(err.Error()))
+	} else {
+		report.add("config", CheckPass, "configuration is valid")
+	}
+
+	checkPorts(report, cfg)
+	checkRedis(ctx, report, cfg)
+	checkPaths(report, cfg)
+	return report
+}
+
+// checkPorts verifies that every enabled listener can bind its port.
+func checkPorts(report *DoctorReport, cfg *Config) {
+	listeners := []struct {
+		name    string
+		port    int
+		enabled bool
+	}{
+		{"server.port", cfg.Server.Port, true},
+		{"server.grpc.port", cfg.Server.GRPC.Port, cfg.Server.GRPC.Enabled},
+		{"metrics.port", cfg.Metrics.Port, cfg.Metrics.Enabled},
+	}
+
+	seen := make(map[int]string)
+	for _, l := range listeners {
+		if !l.enabled {
+			continue
+		}
+		if other, ok := seen[l.port]; ok {
+			report.add(l.name, CheckFail, "port %d is also used by %s", l.port, other)
+			continue
+		}
+		seen[l.port] = l.name
+
+		addr := net.JoinHostPort(cfg.Server.Host, strconv.Itoa(l.port))
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			report.add(l.name, CheckFail, "cannot listen on %s: %v", addr, err)
+			continue
+		}
+		_ = ln.Close()
+		report.add(l.name, CheckPass, "%s is free", addr)
+	}
+}
+
+// checkRedis verifies that Redis answers PING when any component needs it.
+// The shared connection is checked with the client the server builds, so the
+// deployment mode, credentials and timeouts apply alike.
+func checkRedis(ctx context.Context, report *DoctorReport, cfg *Config) {
+	if cfg.Redis.Enabled || cfg.Orchestration.Queue.Type == "redis" || cfg.Signal.Mode == "redis" {
+		target := redisTarget(cfg.Redis)
+		if err := pingRedis(ctx, cfg.Redis.NewClient()); err != nil {
+			report.add("redis", CheckFail, "%s is unreachable: %v", target, err)
+		} else {
+			report.add("redis", CheckPass, "%s is reachable", target)
+		}
+	}
+
+	if cfg.Storage.Type == "redis" {
+		addr := cfg.Storage.Redis.Address
+		client := redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: cfg.Storage.Redis.Password,
+			DB:       cfg.Storage.Redis.DB,
+		})
+		if err := pingRedis(ctx, client); err != nil {
+			report.add("storage.redis", CheckFail, "redis at %s is unreachable: %v", addr, err)
+		} else {
+			report.add("storage.redis", CheckPass, "redis at %s is reachable", addr)
+		}
+	}
+}
+
+// redisTarget describes the Redis deployment cfg connects to.
+func redisTarget(cfg RedisLaneConfig) string {
+	switch cfg.Mode() {
+	case "cluster":
+		return fmt.Sprintf("redis cluster at %s", strings.Join(cfg.Cluster.Addresses, ", "))
+	case "sentinel":
+		return fmt.Sprintf("redis sentinel master %q at %s", cfg.Sentinel.MasterName, strings.Join(cfg.Sentinel.Addresses, ", "))
+	default:
+		return fmt.Sprintf("redis at %s", cfg.Address)
+	}
+}
+
+// pingRedis issues PING through client, bounded by doctorDialTimeout, and
+// closes it.
+func pingRedis(ctx context.Context, client redis.UniversalClient) error {
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(ctx, doctorDialTimeout)
+	defer cancel()
+	return client.Ping(ctx).Err()
+}
+
+// checkPaths verifies that configured data directories are writable.
+func checkPaths(report *DoctorReport, cfg *Config) {
+	if cfg.Storage.Type == "badger" {
+		checkWritableDir(report, "storage.badger.path", cfg.Storage.Badger.Path)
+	}
+	if cfg.Memory.Enabled && cfg.Memory.StoragePath != "" {
+		checkWritableDir(report, "memory.storage_path", cfg.Memory.StoragePath)
+	}
+}
+
+func checkWritableDir(report *DoctorReport, name, dir string) {
+	if dir == "" {
+		report.add(name, CheckFail, "path is empty")
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		report.add(name, CheckFail, "cannot create %s: %v", dir, err)
+		return
+	}
+	f, err := os.CreateTemp(dir, ".goclaw-doctor-*")
+	if err != nil {
+		report.add(name, CheckFail, "%s is not writable: %v", dir, err)
+		return
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	report.add(name, CheckPass, "%s is writable", dir)
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func findCheck(report *DoctorReport, name string) *DoctorCheck {
+	for i := range report.Checks {
+		if report.Checks[i].Name == name {
+			return &report.Checks[i]
+		}
+	}
+	return nil
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestDoctor_PortInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = ln.Addr().(*net.TCPAddr).Port
+	cfg.Metrics.Port = freePort(t)
+
+	report := Doctor(context.Background(), cfg)
+	if report.OK() {
+		t.Fatal("expected report to fail")
+	}
+	if check := findCheck(report, "server.port"); check == nil || check.Status != CheckFail {
+		t.Errorf("expected server.port to fail, got %+v", check)
+	}
+}
+
+func TestDoctor_DuplicatePorts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.Port = cfg.Server.Port
+
+	report := Doctor(context.Background(), cfg)
+	check := findCheck(report, "metrics.port")
+	if check == nil || check.Status != CheckFail || !strings.Contains(check.Message, "server.port") {
+		t.Errorf("expected metrics.port conflict, got %+v", check)
+	}
+}
+
+// fakeRedis starts a listener that answers PING with PONG and, as the only
+// node of a cluster, CLUSTER SLOTS. Other commands are rejected, so clients
+// fall back from RESP3.
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	slots := fmt.Sprintf("*1\r\n*3\r\n:0\r\n:16383\r\n*2\r\n$%d\r\n%s\r\n:%s\r\n", len(host), host, port)
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			command, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+			reply := "-ERR unknown command\r\n"
+			switch strings.ToUpper(strings.Join(command, " ")) {
+			case "PING":
+				reply = "+PONG\r\n"
+			case "CLUSTER SLOTS":
+				reply = slots
+			}
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// readCommand reads one RESP command sent by a client.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	command := make([]string, 0, n)
+	for range n {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		command = append(command, strings.TrimSpace(arg))
+	}
+	return command, nil
+}
+
+func TestDoctor_RedisReachable(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Metrics.Port = freePort(t)
+	cfg.Redis.Enabled = true
+	cfg.Redis.Address = fakeRedis(t)
+
+	report := Doctor(context.Background(), cfg)
+	if check := findCheck(report, "redis"); check == nil || check.Status != CheckPass {
+		t.Fatalf("expected redis to pass, got %+v", check)
+	}
+}
+
+func TestDoctor_RedisCluster(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Metrics.Port = freePort(t)
+	cfg.Redis.Enabled = true
+	cfg.Redis.Cluster.Enabled = true
+	cfg.Redis.Cluster.Addresses = []string{fakeRedis(t), fmt.Sprintf("127.0.0.1:%d", freePort(t))}
+
+	report := Doctor(context.Background(), cfg)
+	check := findCheck(report, "redis")
+	if check == nil || check.Status != CheckPass {
+		t.Fatalf("expected the cluster to be reachable through one seed node, got %+v", check)
+	}
+
+	cfg.Redis.Cluster.Addresses = []string{fmt.Sprintf("127.0.0.1:%d", freePort(t))}
+	report = Doctor(context.Background(), cfg)
+	if check := findCheck(report, "redis"); check == nil || check.Status != CheckFail {
+		t.Fatalf("expected unreachable cluster to fail, got %+v", check)
+	}
+}
+
+func TestDoctor_RedisUnreachable(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Metrics.Port = freePort(t)
+	cfg.Signal.Mode = "redis"
+	cfg.Redis.Address = fmt.Sprintf("127.0.0.1:%d", freePort(t))
+
+	report := Doctor(context.Background(), cfg)
+	if check := findCheck(report, "redis"); check == nil || check.Status != CheckFail {
+		t.Fatalf("expected redis to fail, got %+v", check)
+	}
+}
+
+func TestDoctor_WritablePaths(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Metrics.Port = freePort(t)
+	cfg.Storage.Type = "badger"
+	cfg.Storage.Badger.Path = filepath.Join(t.TempDir(), "data")
+
+	report := Doctor(context.Background(), cfg)
+	if !report.OK() {
+		t.Fatalf("expected report to pass, got %+v", report.Checks)
+	}
+	if check := findCheck(report, "storage.badger.path"); check == nil || check.Status != CheckPass {
+		t.Errorf("expected storage path to pass, got %+v", check)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/joho/godotenv"
 	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env"
@@ -25,20 +28,40 @@ const (
 // Loader handles configuration loading from various sources.
 type Loader struct {
 	k *koanf.Koanf
+	// envFile is an optional .env file loaded between the config file and the process environment.
+	envFile string
+	// secretKeys records keys whose values were resolved from secret references.
+	secretKeys map[string]bool
 }
 
 // NewLoader creates a new configuration loader.
 func NewLoader() *Loader {
 	return &Loader{
-		k: koanf.New(Delimiter),
+		k:          koanf.New(Delimiter),
+		secretKeys: make(map[string]bool),
 	}
 }
 
+// SetEnvFile sets a .env file whose GOCLAW_ variables are loaded on Load.
+// Process environment variables take precedence over the file.
+func (l *Loader) SetEnvFile(path string) {
+	l.envFile = path
+}
+
+// clone returns a Loader with the settings of l but none of its loaded
+// values, so that keys removed from a source do not survive a reload.
+func (l *Loader) clone() *Loader {
+	fresh := NewLoader()
+	fresh.envFile = l.envFile
+	return fresh
+}
+
 // Load loads configuration from all sources with the following priority:
 // 1. Command line flags (highest)
 // 2. Environment variables
-// 3. Configuration files
-// 4. Defaults (lowest)
+// 3. .env file (see SetEnvFile)
+// 4. Configuration file (YAML, JSON or TOML)
+// 5. Defaults (lowest)
 func (l *Loader) Load(configPath string, overrides map[string]interface{}) (*Config, error) {
 	// 1. Load defaults
 	if err := l.loadDefaults(); err != nil {
@@ -55,12 +78,19 @@ func (l *Loader) Load(configPath string, overrides map[string]interface{}) (*Con
 		l.loadDefaultFiles()
 	}
 
-	// 3. Load from environment variables
+	// 3. Load from .env file if specified
+	if l.envFile != "" {
+		if err := l.loadEnvFile(l.envFile); err != nil {
+			return nil, fmt.Errorf("failed to load env file: %w", err)
+		}
+	}
+
+	// 4. Load from environment variables
 	if err := l.loadEnv(); err != nil {
 		return nil, fmt.Errorf("failed to load env vars: %w", err)
 	}
 
-	// 4. Apply command line overrides (merge, not replace)
+	// 5. Apply command line overrides (merge, not replace)
 	if len(overrides) > 0 {
 		if err := l.k.Load(confmap.Provider(overrides, Delimiter), nil); err != nil {
 			return nil, fmt.Errorf("failed to apply overrides: %w", err)
@@ -74,6 +104,11 @@ func (l *Loader) Load(configPath string, overrides map[string]interface{}) (*Con
 		return nil, fmt.Errorf("failed to fill defaults: %w", err)
 	}
 
+	// Resolve secret references (env://, file://, vault://, awssm://).
+	if err := l.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	// Unmarshal to struct
 	var cfg Config
 	if err := l.k.UnmarshalWithConf("", &cfg, koanf.UnmarshalConf{
@@ -120,6 +155,8 @@ func (l *Loader) loadFile(path string) error {
 		parser = yaml.Parser()
 	case ".json":
 		parser = json.Parser()
+	case ".toml":
+		parser = toml.Parser()
 	default:
 		return fmt.Errorf("unsupported config file format: %s", ext)
 	}
@@ -139,6 +176,7 @@ func (l *Loader) loadDefaultFiles() {
 		"config.yaml",
 		"config.yml",
 		"config.json",
+		"config.toml",
 		"configs/config.yaml",
 		"/etc/goclaw/config.yaml",
 	}
@@ -153,12 +191,46 @@ func (l *Loader) loadDefaultFiles() {
 
 // loadEnv loads configuration from environment variables.
 func (l *Loader) loadEnv() error {
-	return l.k.Load(env.Provider(EnvPrefix, Delimiter, func(s string) string {
-		// Transform environment variable names
-		// GOCLAW_SERVER_PORT -> server.port
-		// GOCLAW_LOG_LEVEL -> log.level
-		return strings.ToLower(strings.TrimPrefix(s, EnvPrefix))
-	}), nil)
+	return l.k.Load(env.Provider(EnvPrefix, Delimiter, envKey), nil)
+}
+
+// loadEnvFile loads GOCLAW_ variables from a .env file.
+func (l *Loader) loadEnvFile(path string) error {
+	vars, err := godotenv.Read(path)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		values[envKey(name)] = value
+	}
+	return l.k.Load(confmap.Provider(values, Delimiter), nil)
+}
+
+// envKeys maps flattened env-style names (server_grpc_port) to config keys
+// (server.grpc.port). Config keys themselves contain underscores, so the
+// mapping is derived from the known schema instead of a blind replacement.
+var envKeys = func() map[string]string {
+	keys := make(map[string]string)
+	for key := range structToMap(DefaultConfig(), "") {
+		keys[strings.ReplaceAll(key, Delimiter, "_")] = key
+	}
+	return keys
+}()
+
+// envKey transforms an environment variable name into a config key.
+// GOCLAW_SERVER_PORT -> server.port
+// GOCLAW_ORCHESTRATION_MAX_AGENTS -> orchestration.max_agents
+func envKey(name string) string {
+	flat := strings.ToLower(strings.TrimPrefix(name, EnvPrefix))
+	if key, ok := envKeys[flat]; ok {
+		return key
+	}
+	return flat
 }
 
 // Get returns a configuration value by key.
@@ -292,8 +364,24 @@ func structToMap(v interface{}, prefix string) map[string]interface{} {
 }
 
 // Print prints the loaded configuration for debugging.
+// Resolved secrets and sensitive keys are masked.
 func (l *Loader) Print() string {
-	return l.k.Sprint()
+	all := l.k.All()
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := all[key]
+		if l.secretKeys[key] || IsSensitiveKey(key) || isLiteralDeferredSecret(key, value) {
+			value = MaskSecret(fmt.Sprint(value))
+		}
+		b.WriteString(fmt.Sprintf("%s -> %v\n", key, value))
+	}
+	return b.String()
 }
 
 // Load is a convenience function to load configuration.
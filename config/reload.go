@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+// hotReloadableKeys lists the configuration keys that can be applied to a
+// running process without a restart. Changes to any other key are rejected.
+var hotReloadableKeys = map[string]bool{
+	"log.level":                      true,
+	"manage.webhooks":                true,
+	"orchestration.lanes":            true,
+	"orchestration.max_agents":       true,
+	"orchestration.queue.rate_limit": true,
+}
+
+// IsHotReloadable reports whether the given config key can be changed at runtime.
+func IsHotReloadable(key string) bool {
+	return hotReloadableKeys[key]
+}
+
+// FieldChange describes a single configuration value change.
+type FieldChange struct {
+	// Key is the dot-separated configuration key (e.g. "log.level").
+	Key string
+	// Old is the previous value.
+	Old interface{}
+	// New is the requested value.
+	New interface{}
+	// Reason explains why a change was rejected. Empty for applied changes.
+	Reason string
+}
+
+// ReloadResult reports the outcome of a configuration reload.
+type ReloadResult struct {
+	// Applied contains changes that were applied to the running process.
+	Applied []FieldChange
+	// Rejected contains changes that require a restart or failed to apply.
+	Rejected []FieldChange
+}
+
+// HasChanges reports whether the reload detected any difference.
+func (r *ReloadResult) HasChanges() bool {
+	return r != nil && (len(r.Applied) > 0 || len(r.Rejected) > 0)
+}
+
+// AppliedKeys returns the applied keys mapped to their new values as strings.
+func (r *ReloadResult) AppliedKeys() map[string]string {
+	applied := make(map[string]string, len(r.Applied))
+	for _, change := range r.Applied {
+		applied[change.Key] = fmt.Sprint(change.New)
+	}
+	return applied
+}
+
+// RejectedError summarises rejected changes as an error, or nil if none were rejected.
+func (r *ReloadResult) RejectedError() error {
+	if r == nil || len(r.Rejected) == 0 {
+		return nil
+	}
+	parts := make([]string, 0, len(r.Rejected))
+	for _, change := range r.Rejected {
+		parts = append(parts, fmt.Sprintf("%s: %s", change.Key, change.Reason))
+	}
+	return fmt.Errorf("rejected config changes: %s", strings.Join(parts, "; "))
+}
+
+// Diff returns the changes between two configurations, sorted by key.
+func Diff(oldCfg, newCfg *Config) []FieldChange {
+	oldMap := structToMap(oldCfg, "")
+	newMap := structToMap(newCfg, "")
+
+	keys := make(map[string]struct{}, len(oldMap)+len(newMap))
+	for k := range oldMap {
+		keys[k] = struct{}{}
+	}
+	for k := range newMap {
+		keys[k] = struct{}{}
+	}
+
+	changes := make([]FieldChange, 0)
+	for key := range keys {
+		oldVal, newVal := oldMap[key], newMap[key]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		changes = append(changes, FieldChange{Key: key, Old: oldVal, New: newVal})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// ReloadApplier applies hot-reloadable changes to a running component.
+// It receives the candidate configuration and the applicable changes of one
+// key at a time, so that an error only rejects the change it was given.
+type ReloadApplier func(cfg *Config, changes []FieldChange) error
+
+// Reloader tracks the active configuration and applies safe changes at runtime.
+type Reloader struct {
+	mu       sync.Mutex
+	current  *Config
+	appliers []ReloadApplier
+}
+
+// NewReloader creates a Reloader seeded with the active configuration.
+func NewReloader(initial *Config) *Reloader {
+	if initial == nil {
+		initial = DefaultConfig()
+	}
+	snapshot := *initial
+	return &Reloader{current: &snapshot}
+}
+
+// Register adds an applier that is invoked on every reload with applicable changes.
+func (r *Reloader) Register(applier ReloadApplier) {
+	if applier == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, applier)
+}
+
+// Current returns a copy of the active configuration.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := *r.current
+	return &snapshot
+}
+
+// Apply diffs next against the active configuration and applies the
+// hot-reloadable changes through the registered appliers, one key at a
+// time. The result lists the keys that were applied and, with the reason,
+// those that were skipped because they require a restart or failed to
+// apply; only applied keys update the active configuration.
+func (r *Reloader) Apply(next *Config) (*ReloadResult, error) {
+	if next == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := ValidateWithDetails(next); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &ReloadResult{}
+	for _, change := range Diff(r.current, next) {
+		if !IsHotReloadable(change.Key) {
+			change.Reason = "requires restart"
+			result.Rejected = append(result.Rejected, change)
+			continue
+		}
+		if err := r.applyChange(next, change); err != nil {
+			change.Reason = err.Error()
+			result.Rejected = append(result.Rejected, change)
+			continue
+		}
+		result.Applied = append(result.Applied, change)
+	}
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	updated, err := mergeKeys(r.current, result.Applied)
+	if err != nil {
+		return nil, err
+	}
+	r.current = updated
+	return result, nil
+}
+
+// applyChange runs the appliers on change, stopping at the first error. The
+// caller holds r.mu.
+func (r *Reloader) applyChange(next *Config, change FieldChange) error {
+	for _, applier := range r.appliers {
+		if err := applier(next, []FieldChange{change}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyUpdates applies key/value overrides (as received from the admin API)
+// on top of the active configuration. Unknown keys are rejected.
+func (r *Reloader) ApplyUpdates(updates map[string]string) (*ReloadResult, error) {
+	current := r.Current()
+	base := structToMap(current, "")
+
+	var unknown []FieldChange
+	overrides := make(map[string]interface{}, len(updates))
+	for key, value := range updates {
+		if _, ok := base[key]; !ok {
+			unknown = append(unknown, FieldChange{Key: key, New: value, Reason: "unknown config key"})
+			continue
+		}
+		overrides[key] = value
+	}
+
+	next, err := overlay(base, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Apply(next)
+	if err != nil {
+		return nil, err
+	}
+	result.Rejected = append(result.Rejected, unknown...)
+	return result, nil
+}
+
+// mergeKeys applies only the given changes onto a copy of dst.
+func mergeKeys(dst *Config, changes []FieldChange) (*Config, error) {
+	overrides := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		overrides[change.Key] = change.New
+	}
+	return overlay(structToMap(dst, ""), overrides)
+}
+
+// overlay unmarshals a flat key map with overrides applied into a Config.
+func overlay(base, overrides map[string]interface{}) (*Config, error) {
+	k := koanf.New(Delimiter)
+	if err := k.Load(confmap.Provider(base, Delimiter), nil); err != nil {
+		return nil, fmt.Errorf("failed to load base config: %w", err)
+	}
+	if err := k.Load(confmap.Provider(overrides, Delimiter), nil); err != nil {
+		return nil, fmt.Errorf("failed to apply overrides: %w", err)
+	}
+
+	var cfg Config
+	if err := k.UnmarshalWithConf("", &cfg, koanf.UnmarshalConf{
+		Tag: "mapstructure",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
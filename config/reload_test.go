@@ -0,0 +1,200 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldCfg := DefaultConfig()
+	newCfg := DefaultConfig()
+	newCfg.Log.Level = "debug"
+	newCfg.Server.Port = 9999
+
+	changes := Diff(oldCfg, newCfg)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Key != "log.level" || changes[0].Old != "info" || changes[0].New != "debug" {
+		t.Errorf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Key != "server.port" {
+		t.Errorf("expected server.port change, got %s", changes[1].Key)
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	if changes := Diff(DefaultConfig(), DefaultConfig()); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestReloader_AppliesSafeAndRejectsUnsafe(t *testing.T) {
+	reloader := NewReloader(DefaultConfig())
+
+	var applied []FieldChange
+	reloader.Register(func(cfg *Config, changes []FieldChange) error {
+		applied = append(applied, changes...)
+		return nil
+	})
+
+	next := DefaultConfig()
+	next.Log.Level = "warn"
+	next.Orchestration.MaxAgents = 16
+	next.Server.Port = 9000
+
+	result, err := reloader.Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != 2 {
+		t.Fatalf("expected 2 applied changes, got %+v", result.Applied)
+	}
+	if len(result.Rejected) != 1 || result.Rejected[0].Key != "server.port" {
+		t.Fatalf("expected server.port to be rejected, got %+v", result.Rejected)
+	}
+	if result.Rejected[0].Reason == "" {
+		t.Error("expected rejection reason")
+	}
+	if len(applied) != 2 {
+		t.Errorf("expected applier to receive 2 changes, got %d", len(applied))
+	}
+
+	current := reloader.Current()
+	if current.Log.Level != "warn" {
+		t.Errorf("expected log level warn, got %s", current.Log.Level)
+	}
+	if current.Orchestration.MaxAgents != 16 {
+		t.Errorf("expected max agents 16, got %d", current.Orchestration.MaxAgents)
+	}
+	if current.Server.Port != 8080 {
+		t.Errorf("rejected change must not be applied, got port %d", current.Server.Port)
+	}
+}
+
+func TestReloader_ApplierErrorRejectsChanges(t *testing.T) {
+	reloader := NewReloader(DefaultConfig())
+	reloader.Register(func(cfg *Config, changes []FieldChange) error {
+		return errTestApply
+	})
+
+	next := DefaultConfig()
+	next.Log.Level = "error"
+
+	result, err := reloader.Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != 0 || len(result.Rejected) != 1 {
+		t.Fatalf("expected change to be rejected, got %+v", result)
+	}
+	if reloader.Current().Log.Level != "info" {
+		t.Error("failed change must not update the active config")
+	}
+}
+
+func TestReloader_ApplierErrorRejectsOnlyItsKey(t *testing.T) {
+	reloader := NewReloader(DefaultConfig())
+	reloader.Register(func(cfg *Config, changes []FieldChange) error {
+		for _, change := range changes {
+			if change.Key == "manage.webhooks" {
+				return errTestApply
+			}
+		}
+		return nil
+	})
+
+	next := DefaultConfig()
+	next.Manage.Enabled = true
+	next.Manage.Webhooks = map[string]WebhookConfig{"ops": {URL: "https://ops.example.com"}}
+	next.Log.Level = "debug"
+
+	result, err := reloader.Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0].Key != "log.level" {
+		t.Fatalf("expected log.level to be applied, got %+v", result.Applied)
+	}
+	rejected := make(map[string]string)
+	for _, change := range result.Rejected {
+		rejected[change.Key] = change.Reason
+	}
+	if rejected["manage.enabled"] != "requires restart" || rejected["manage.webhooks"] != errTestApply.Error() {
+		t.Fatalf("unexpected rejected changes %+v", result.Rejected)
+	}
+	current := reloader.Current()
+	if current.Log.Level != "debug" || len(current.Manage.Webhooks) != 0 {
+		t.Fatalf("active config = log level %s, webhooks %v", current.Log.Level, current.Manage.Webhooks)
+	}
+}
+
+func TestReloader_AppliesWebhooks(t *testing.T) {
+	initial := DefaultConfig()
+	initial.Manage.Enabled = true
+	reloader := NewReloader(initial)
+
+	next := DefaultConfig()
+	next.Manage.Enabled = true
+	next.Manage.Webhooks = map[string]WebhookConfig{"ops": {URL: "https://ops.example.com", Events: []string{"workflow.sla_breached"}}}
+
+	result, err := reloader.Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0].Key != "manage.webhooks" || len(result.Rejected) != 0 {
+		t.Fatalf("expected manage.webhooks to be applied, got %+v", result)
+	}
+	if got := reloader.Current().Manage.Webhooks["ops"]; got.URL != "https://ops.example.com" || len(got.Events) != 1 {
+		t.Fatalf("active webhook = %+v", got)
+	}
+}
+
+func TestReloader_InvalidConfig(t *testing.T) {
+	reloader := NewReloader(DefaultConfig())
+
+	next := DefaultConfig()
+	next.Log.Level = "verbose"
+
+	if _, err := reloader.Apply(next); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+func TestReloader_ApplyUpdates(t *testing.T) {
+	reloader := NewReloader(DefaultConfig())
+
+	result, err := reloader.ApplyUpdates(map[string]string{
+		"orchestration.max_agents":       "32",
+		"orchestration.queue.rate_limit": "12.5",
+		"server.port":                    "9000",
+		"no.such.key":                    "x",
+	})
+	if err != nil {
+		t.Fatalf("ApplyUpdates failed: %v", err)
+	}
+
+	applied := result.AppliedKeys()
+	if applied["orchestration.max_agents"] != "32" {
+		t.Errorf("expected max_agents applied, got %v", applied)
+	}
+	if applied["orchestration.queue.rate_limit"] != "12.5" {
+		t.Errorf("expected rate_limit applied, got %v", applied)
+	}
+	if len(result.Rejected) != 2 {
+		t.Fatalf("expected 2 rejected changes, got %+v", result.Rejected)
+	}
+	if result.RejectedError() == nil {
+		t.Error("expected rejected error")
+	}
+
+	current := reloader.Current()
+	if current.Orchestration.MaxAgents != 32 {
+		t.Errorf("expected max agents 32, got %d", current.Orchestration.MaxAgents)
+	}
+	if current.Orchestration.Queue.RateLimit != 12.5 {
+		t.Errorf("expected rate limit 12.5, got %v", current.Orchestration.Queue.RateLimit)
+	}
+}
+
+var errTestApply = errors.New("apply failed")
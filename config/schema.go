@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the JSON Schema dialect emitted by JSONSchema.
+const SchemaVersion = "https://json-schema.org/draft/2020-12/schema"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Schema returns the configuration schema as a JSON Schema document.
+// Every field carries its type, default value and validation rules; rules
+// without a JSON Schema equivalent are preserved in "x-validate".
+func Schema() map[string]interface{} {
+	schema := objectSchema(reflect.TypeOf(Config{}), reflect.ValueOf(*DefaultConfig()))
+	schema["$schema"] = SchemaVersion
+	schema["title"] = "Goclaw configuration"
+	return schema
+}
+
+// JSONSchema returns the configuration schema encoded as indented JSON.
+func JSONSchema() ([]byte, error) {
+	return json.MarshalIndent(Schema(), "", "  ")
+}
+
+// objectSchema builds the schema for a struct type using its mapstructure tags.
+func objectSchema(typ reflect.Type, defaults reflect.Value) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := field.Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		prop := fieldSchema(field.Type, defaults.Field(i))
+		rules := field.Tag.Get("validate")
+		if applyValidationRules(prop, field.Type, rules) {
+			required = append(required, key)
+		}
+		properties[key] = prop
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// fieldSchema builds the schema for a single field type with its default value.
+func fieldSchema(typ reflect.Type, def reflect.Value) map[string]interface{} {
+	if typ == durationType {
+		return map[string]interface{}{
+			"type":    "string",
+			"format":  "duration",
+			"default": time.Duration(def.Int()).String(),
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		return objectSchema(typ, def)
+	case reflect.Ptr:
+		if def.IsNil() {
+			return fieldSchema(typ.Elem(), reflect.Zero(typ.Elem()))
+		}
+		return fieldSchema(typ.Elem(), def.Elem())
+	case reflect.Slice, reflect.Array:
+		prop := map[string]interface{}{
+			"type":  "array",
+			"items": fieldSchema(typ.Elem(), reflect.Zero(typ.Elem())),
+		}
+		if def.Len() > 0 {
+			prop["default"] = def.Interface()
+		}
+		return prop
+	case reflect.Map:
+		prop := map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": fieldSchema(typ.Elem(), reflect.Zero(typ.Elem())),
+		}
+		if def.Len() > 0 {
+			prop["default"] = def.Interface()
+		}
+		return prop
+	}
+
+	prop := map[string]interface{}{"type": jsonType(typ.Kind())}
+	if def.IsValid() && def.CanInterface() {
+		prop["default"] = def.Interface()
+	}
+	return prop
+}
+
+// jsonType maps a scalar Go kind to its JSON Schema type.
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// applyValidationRules translates validator tags into JSON Schema keywords.
+// It reports whether the field is required.
+func applyValidationRules(prop map[string]interface{}, typ reflect.Type, rules string) bool {
+	if rules == "" {
+		return false
+	}
+	prop["x-validate"] = rules
+
+	required, omitEmpty := false, false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			omitEmpty = true
+		case "required":
+			required = true
+			if typ.Kind() == reflect.String {
+				prop["minLength"] = 1
+			}
+		case "oneof":
+			enum := make([]interface{}, 0)
+			if omitEmpty {
+				enum = append(enum, "")
+			}
+			for _, v := range strings.Fields(param) {
+				enum = append(enum, v)
+			}
+			prop["enum"] = enum
+		case "min", "max":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			prop[boundKeyword(name, typ.Kind())] = n
+		}
+	}
+	return required
+}
+
+// boundKeyword returns the JSON Schema keyword for a min/max rule on the given kind.
+func boundKeyword(rule string, kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		if rule == "min" {
+			return "minLength"
+		}
+		return "maxLength"
+	case reflect.Slice, reflect.Array:
+		if rule == "min" {
+			return "minItems"
+		}
+		return "maxItems"
+	case reflect.Map:
+		if rule == "min" {
+			return "minProperties"
+		}
+		return "maxProperties"
+	}
+	if rule == "min" {
+		return "minimum"
+	}
+	return "maximum"
+}
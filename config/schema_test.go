@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func schemaProperty(t *testing.T, schema map[string]interface{}, path ...string) map[string]interface{} {
+	t.Helper()
+	node := schema
+	for _, key := range path {
+		props, ok := node["properties"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("node has no properties at %q", key)
+		}
+		next, ok := props[key].(map[string]interface{})
+		if !ok {
+			t.Fatalf("property %q not found", key)
+		}
+		node = next
+	}
+	return node
+}
+
+func TestSchema_FieldsTypesAndDefaults(t *testing.T) {
+	schema := Schema()
+	if schema["$schema"] != SchemaVersion {
+		t.Errorf("expected $schema %q, got %v", SchemaVersion, schema["$schema"])
+	}
+
+	port := schemaProperty(t, schema, "server", "port")
+	if port["type"] != "integer" {
+		t.Errorf("expected server.port type integer, got %v", port["type"])
+	}
+	if port["default"] != 8080 {
+		t.Errorf("expected server.port default 8080, got %v", port["default"])
+	}
+	if port["minimum"] != float64(1) || port["maximum"] != float64(65535) {
+		t.Errorf("expected server.port bounds 1..65535, got %v..%v", port["minimum"], port["maximum"])
+	}
+
+	level := schemaProperty(t, schema, "log", "level")
+	enum, ok := level["enum"].([]interface{})
+	if !ok || len(enum) != 4 {
+		t.Fatalf("expected log.level enum with 4 values, got %v", level["enum"])
+	}
+
+	timeout := schemaProperty(t, schema, "saga", "default_timeout")
+	if timeout["type"] != "string" || timeout["format"] != "duration" {
+		t.Errorf("expected duration string for saga.default_timeout, got %v/%v", timeout["type"], timeout["format"])
+	}
+
+	sampler := schemaProperty(t, schema, "tracing", "sampler")
+	if enum := sampler["enum"].([]interface{}); enum[0] != "" {
+		t.Errorf("expected omitempty enum to allow empty value, got %v", enum)
+	}
+
+	app := schemaProperty(t, schema, "app")
+	required, ok := app["required"].([]string)
+	if !ok || len(required) != 1 || required[0] != "name" {
+		t.Errorf("expected app.required [name], got %v", app["required"])
+	}
+}
+
+func TestJSONSchema_Valid(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if decoded["type"] != "object" {
+		t.Errorf("expected root type object, got %v", decoded["type"])
+	}
+}
@@ -0,0 +1,371 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/awsauth"
+)
+
+// secretMask replaces sensitive values in printable output.
+const secretMask = "******"
+
+// defaultSecretTimeout bounds how long a single secret lookup may take at load time.
+const defaultSecretTimeout = 10 * time.Second
+
+// SecretProvider resolves secret references for a single URI scheme.
+// The ref passed to Resolve has the scheme prefix stripped, e.g. for
+// "vault://secret/data/goclaw#redis_password" it is "secret/data/goclaw#redis_password".
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve implements SecretProvider.
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":   SecretProviderFunc(resolveEnvSecret),
+		"file":  SecretProviderFunc(resolveFileSecret),
+		"vault": &VaultSecretProvider{},
+		"awssm": &AWSSecretsManagerProvider{},
+	}
+)
+
+// RegisterSecretProvider registers or replaces the provider for a URI scheme.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	if provider == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = provider
+}
+
+// splitSecretRef splits a value into scheme and reference if it is a secret reference.
+func splitSecretRef(value string) (string, string, bool) {
+	scheme, ref, ok := strings.Cut(value, "://")
+	if !ok || ref == "" {
+		return "", "", false
+	}
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	if _, registered := secretProviders[scheme]; !registered {
+		return "", "", false
+	}
+	return scheme, ref, true
+}
+
+// IsSecretRef reports whether value uses a registered secret reference scheme.
+func IsSecretRef(value string) bool {
+	_, _, ok := splitSecretRef(value)
+	return ok
+}
+
+// ResolveSecret resolves a secret reference such as "env://REDIS_PASSWORD".
+// Values that are not secret references are returned unchanged.
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := splitSecretRef(value)
+	if !ok {
+		return value, nil
+	}
+	secretProvidersMu.RLock()
+	provider := secretProviders[scheme]
+	secretProvidersMu.RUnlock()
+
+	resolved, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s secret: %w", scheme, err)
+	}
+	return resolved, nil
+}
+
+// sensitiveKeySuffixes identifies config keys whose values must never be printed.
+var sensitiveKeySuffixes = []string{
+	"password",
+	"secret",
+	"token",
+	"tokens",
+	"api_key",
+	"signing_key",
+	"private_key",
+	"master_key",
+	"secret_access_key",
+}
+
+// IsSensitiveKey reports whether a config key holds a secret value by name.
+func IsSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	if idx := strings.LastIndex(lower, Delimiter); idx >= 0 {
+		lower = lower[idx+1:]
+	}
+	for _, suffix := range sensitiveKeySuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecret returns a fixed mask for non-empty values.
+func MaskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// deferredSecretPrefixes are the config keys whose secret references are
+// resolved when a task runs instead of at load time.
+var deferredSecretPrefixes = []string{
+	"orchestration.secrets" + Delimiter,
+	"orchestration.environments" + Delimiter,
+}
+
+// isDeferredSecretKey reports whether key is resolved at task execution time.
+func isDeferredSecretKey(key string) bool {
+	for _, prefix := range deferredSecretPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLiteralDeferredSecret reports whether key is a named task secret given as
+// a literal value rather than a reference, which must be masked when printed.
+func isLiteralDeferredSecret(key string, value any) bool {
+	raw, ok := value.(string)
+	return ok && strings.HasPrefix(key, deferredSecretPrefixes[0]) && !IsSecretRef(raw)
+}
+
+// resolveSecrets replaces every secret reference in the loaded config with its value
+// and records the affected keys so they can be masked when printing. References
+// under deferredSecretPrefixes are left for the engine to resolve per task.
+func (l *Loader) resolveSecrets() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSecretTimeout)
+	defer cancel()
+
+	all := l.k.All()
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw, ok := all[key].(string)
+		if !ok || !IsSecretRef(raw) || isDeferredSecretKey(key) {
+			continue
+		}
+		resolved, err := ResolveSecret(ctx, raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if err := l.k.Set(key, resolved); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		l.secretKeys[key] = true
+	}
+	return nil
+}
+
+func resolveEnvSecret(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+func resolveFileSecret(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// splitSecretField splits "path#field" into path and optional field.
+func splitSecretField(ref string) (string, string) {
+	path, field, _ := strings.Cut(ref, "#")
+	return path, field
+}
+
+// VaultSecretProvider reads secrets from HashiCorp Vault's HTTP API.
+// References take the form "vault://<mount>/<path>#<field>". Both KV v1 and
+// KV v2 responses are supported. Address and Token default to the standard
+// VAULT_ADDR and VAULT_TOKEN environment variables.
+type VaultSecretProvider struct {
+	Address    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// Resolve implements SecretProvider.
+func (p *VaultSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field := splitSecretField(ref)
+	if field == "" {
+		return "", fmt.Errorf("vault reference %q must include a #field", ref)
+	}
+
+	address := firstNonEmpty(p.Address, os.Getenv("VAULT_ADDR"))
+	if address == "" {
+		return "", fmt.Errorf("vault address is not configured (set VAULT_ADDR)")
+	}
+	token := firstNonEmpty(p.Token, os.Getenv("VAULT_TOKEN"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimRight(address, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	body, err := doSecretRequest(p.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+
+	data := payload.Data
+	// KV v2 nests the secret under data.data.
+	if nested, ok := data["data"]; ok {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &inner); err == nil {
+			data = inner
+		}
+	}
+	return lookupJSONField(data, field)
+}
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager.
+// References take the form "awssm://<secret-id>" or "awssm://<secret-id>#<json-field>".
+// Credentials and region come from the standard AWS_* environment variables;
+// Endpoint may be set (or AWS_ENDPOINT_URL_SECRETS_MANAGER) to target a compatible service.
+type AWSSecretsManagerProvider struct {
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	HTTPClient      *http.Client
+}
+
+// Resolve implements SecretProvider.
+func (p *AWSSecretsManagerProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, field := splitSecretField(ref)
+
+	region := firstNonEmpty(p.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	if region == "" {
+		return "", fmt.Errorf("aws region is not configured (set AWS_REGION)")
+	}
+	creds := awsauth.Credentials{
+		AccessKeyID:     p.AccessKeyID,
+		SecretAccessKey: p.SecretAccessKey,
+		SessionToken:    p.SessionToken,
+	}.WithEnv()
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return "", fmt.Errorf("aws credentials are not configured")
+	}
+	endpoint := firstNonEmpty(p.Endpoint, os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER"),
+		fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region))
+
+	reqBody, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsauth.Sign(req, awsauth.PayloadHash(reqBody), region, "secretsmanager", creds, time.Now())
+
+	body, err := doSecretRequest(p.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decode secrets manager response: %w", err)
+	}
+	if field == "" {
+		return payload.SecretString, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", secretID, err)
+	}
+	return lookupJSONField(fields, field)
+}
+
+func doSecretRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secret request failed with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+func lookupJSONField(fields map[string]json.RawMessage, field string) (string, error) {
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str, nil
+	}
+	return string(raw), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret_Env(t *testing.T) {
+	t.Setenv("GOCLAW_TEST_SECRET", "s3cret")
+
+	got, err := ResolveSecret(context.Background(), "env://GOCLAW_TEST_SECRET")
+	if err != nil {
+		t.Fatalf("ResolveSecret failed: %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("expected s3cret, got %q", got)
+	}
+
+	if _, err := ResolveSecret(context.Background(), "env://GOCLAW_TEST_SECRET_MISSING"); err == nil {
+		t.Error("expected error for missing env var")
+	}
+}
+
+func TestResolveSecret_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+
+	got, err := ResolveSecret(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("ResolveSecret failed: %v", err)
+	}
+	if got != "from-file" {
+		t.Errorf("expected trailing newline trimmed, got %q", got)
+	}
+}
+
+func TestResolveSecret_PlainValue(t *testing.T) {
+	for _, value := range []string{"plain", "http://example.com", ""} {
+		got, err := ResolveSecret(context.Background(), value)
+		if err != nil || got != value {
+			t.Errorf("expected %q unchanged, got %q (err=%v)", value, got, err)
+		}
+	}
+}
+
+func TestVaultSecretProvider_KVv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/goclaw" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"redis_password":"vault-pass"},"metadata":{}}}`))
+	}))
+	defer server.Close()
+
+	provider := &VaultSecretProvider{Address: server.URL, Token: "root"}
+	got, err := provider.Resolve(context.Background(), "secret/data/goclaw#redis_password")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got != "vault-pass" {
+		t.Errorf("expected vault-pass, got %q", got)
+	}
+
+	if _, err := provider.Resolve(context.Background(), "secret/data/goclaw"); err == nil {
+		t.Error("expected error when field is missing from reference")
+	}
+	if _, err := provider.Resolve(context.Background(), "secret/data/goclaw#missing"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["SecretId"] != "goclaw/prod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"SecretString":"{\"llm_api_key\":\"sk-test\"}"}`))
+	}))
+	defer server.Close()
+
+	provider := &AWSSecretsManagerProvider{
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "SECRET",
+	}
+
+	got, err := provider.Resolve(context.Background(), "goclaw/prod#llm_api_key")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got != "sk-test" {
+		t.Errorf("expected sk-test, got %q", got)
+	}
+
+	raw, err := provider.Resolve(context.Background(), "goclaw/prod")
+	if err != nil {
+		t.Fatalf("Resolve without field failed: %v", err)
+	}
+	if !strings.Contains(raw, "llm_api_key") {
+		t.Errorf("expected full secret string, got %q", raw)
+	}
+}
+
+func TestLoader_ResolvesAndMasksSecrets(t *testing.T) {
+	t.Setenv("GOCLAW_TEST_REDIS_PASSWORD", "hunter2")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "redis:\n  address: localhost:6379\n  password: env://GOCLAW_TEST_REDIS_PASSWORD\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Redis.Password != "hunter2" {
+		t.Errorf("expected resolved password, got %q", cfg.Redis.Password)
+	}
+
+	if out := loader.Print(); strings.Contains(out, "hunter2") {
+		t.Errorf("Print leaked secret: %s", out)
+	}
+	if out := cfg.String(); strings.Contains(out, "hunter2") {
+		t.Errorf("String leaked secret: %s", out)
+	}
+}
+
+func TestLoader_UnresolvableSecret(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "redis:\n  password: env://GOCLAW_TEST_UNSET_SECRET\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := NewLoader().Load(configPath, nil); err == nil {
+		t.Fatal("expected error for unresolvable secret")
+	}
+}
+
+func TestLoader_DefersTaskSecrets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "orchestration:\n" +
+		"  secrets:\n    db_password: env://GOCLAW_TEST_UNSET_SECRET\n    api_key: literal-value\n" +
+		"  environments:\n    reporting:\n      DB_HOST: db.internal\n      DB_USER: env://GOCLAW_TEST_UNSET_USER\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	// Unset variables do not fail loading: task secrets resolve when tasks run.
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Orchestration.Secrets["db_password"]; got != "env://GOCLAW_TEST_UNSET_SECRET" {
+		t.Errorf("expected unresolved reference, got %q", got)
+	}
+	if got := cfg.Orchestration.Environments["reporting"]["DB_USER"]; got != "env://GOCLAW_TEST_UNSET_USER" {
+		t.Errorf("expected unresolved reference, got %q", got)
+	}
+	if out := loader.Print(); strings.Contains(out, "literal-value") {
+		t.Errorf("Print leaked literal task secret: %s", out)
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	cases := map[string]bool{
+		"redis.password":           true,
+		"storage.redis.password":   true,
+		"webhook.signing_key":      true,
+		"llm.openai.api_key":       true,
+		"redis.address":            false,
+		"server.grpc.tls.key_file": false,
+	}
+	for key, want := range cases {
+		if got := IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
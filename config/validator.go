@@ -1,11 +1,16 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/jsonschema"
 )
 
 // validate is the global validator instance.
@@ -83,6 +88,288 @@ func ValidateWithDetails(cfg *Config) error {
 			},
 		}
 	}
+	if cfg != nil && cfg.Redis.Cluster.Enabled {
+		var details ValidationErrors
+		if len(cfg.Redis.Cluster.Addresses) == 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Redis.Cluster.Addresses",
+				Message: "must list at least one node when cluster mode is enabled",
+				Value:   cfg.Redis.Cluster.Addresses,
+			})
+		}
+		if cfg.Redis.Sentinel.Enabled {
+			details = append(details, ConfigError{
+				Field:   "Config.Redis.Sentinel.Enabled",
+				Message: "cannot be combined with cluster mode",
+				Value:   cfg.Redis.Sentinel.Enabled,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Orchestration.Queue.Adaptive.Enabled {
+		adaptive := cfg.Orchestration.Queue.Adaptive
+		var details ValidationErrors
+		if cfg.Orchestration.Queue.Type != "memory" {
+			details = append(details, ConfigError{
+				Field:   "Config.Orchestration.Queue.Adaptive.Enabled",
+				Message: "adaptive concurrency requires queue type memory",
+				Value:   cfg.Orchestration.Queue.Type,
+			})
+		}
+		if adaptive.MinConcurrency < 1 || adaptive.MinConcurrency > cfg.Orchestration.MaxAgents {
+			details = append(details, ConfigError{
+				Field:   "Config.Orchestration.Queue.Adaptive.MinConcurrency",
+				Message: "must be between 1 and orchestration.max_agents",
+				Value:   adaptive.MinConcurrency,
+			})
+		}
+		if adaptive.TargetLatency <= 0 && adaptive.MaxErrorRate <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Orchestration.Queue.Adaptive.TargetLatency",
+				Message: "target_latency or max_error_rate must be set when adaptive concurrency is enabled",
+				Value:   adaptive.TargetLatency,
+			})
+		}
+		if adaptive.Interval < 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Orchestration.Queue.Adaptive.Interval",
+				Message: "cannot be negative",
+				Value:   adaptive.Interval,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Storage.TaskWrites.Mode == "batch" {
+		taskWrites := cfg.Storage.TaskWrites
+		var details ValidationErrors
+		if taskWrites.FlushInterval <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Storage.TaskWrites.FlushInterval",
+				Message: "must be positive in batch mode",
+				Value:   taskWrites.FlushInterval,
+			})
+		}
+		if taskWrites.MaxBatch < 1 {
+			details = append(details, ConfigError{
+				Field:   "Config.Storage.TaskWrites.MaxBatch",
+				Message: "must be at least 1 in batch mode",
+				Value:   taskWrites.MaxBatch,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Storage.Encryption.MasterKey != "" && !IsSecretRef(cfg.Storage.Encryption.MasterKey) {
+		if key, err := base64.StdEncoding.DecodeString(cfg.Storage.Encryption.MasterKey); err != nil || len(key) != 32 {
+			return ValidationErrors{ConfigError{
+				Field:   "Config.Storage.Encryption.MasterKey",
+				Message: "must be a base64 encoded 32-byte key",
+				Value:   MaskSecret(cfg.Storage.Encryption.MasterKey),
+			}}
+		}
+	}
+	if cfg != nil && cfg.Orchestration.Queue.Dispatch == "edf" && cfg.Orchestration.Queue.Type != "memory" {
+		return ValidationErrors{
+			{
+				Field:   "Config.Orchestration.Queue.Dispatch",
+				Message: "edf dispatch requires queue type memory",
+				Value:   cfg.Orchestration.Queue.Type,
+			},
+		}
+	}
+	if cfg != nil && !cfg.Orchestration.Queue.Resources.IsZero() && cfg.Orchestration.Queue.Type != "memory" {
+		return ValidationErrors{
+			{
+				Field:   "Config.Orchestration.Queue.Resources",
+				Message: "resource capacity requires queue type memory",
+				Value:   cfg.Orchestration.Queue.Type,
+			},
+		}
+	}
+	if cfg != nil && cfg.Orchestration.Lanes != nil {
+		if named, ok := cfg.Orchestration.Lanes["default"]; ok {
+			return ValidationErrors{
+				{
+					Field:   "Config.Orchestration.Lanes[default]",
+					Message: "the default lane is configured by max_agents and queue",
+					Value:   named,
+				},
+			}
+		}
+	}
+	if cfg != nil && len(cfg.Orchestration.Workflows.PerName) > 0 {
+		var details ValidationErrors
+		for name, limit := range cfg.Orchestration.Workflows.PerName {
+			if limit < 1 {
+				details = append(details, ConfigError{
+					Field:   fmt.Sprintf("Config.Orchestration.Workflows.PerName[%s]", name),
+					Message: "must be at least 1",
+					Value:   limit,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && len(cfg.Orchestration.Environments) > 0 {
+		var details ValidationErrors
+		for bundle, vars := range cfg.Orchestration.Environments {
+			for name := range vars {
+				if !isEnvVarName(name) {
+					details = append(details, ConfigError{
+						Field:   fmt.Sprintf("Config.Orchestration.Environments[%s]", bundle),
+						Message: "environment variable names must be letters, digits and underscores, not starting with a digit",
+						Value:   name,
+					})
+				}
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Server.HTTP.TLS.Enabled {
+		var details ValidationErrors
+		tlsCfg := cfg.Server.HTTP.TLS
+		if tlsCfg.CertFile == "" || tlsCfg.KeyFile == "" {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.HTTP.TLS",
+				Message: "cert_file and key_file are required when TLS is enabled",
+			})
+		}
+		if tlsCfg.ClientAuth && tlsCfg.CAFile == "" {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.HTTP.TLS.CAFile",
+				Message: "is required when client_auth is enabled",
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil {
+		var details ValidationErrors
+		for i, m := range cfg.Server.Auth.RoleMappings {
+			if _, err := path.Match(m.SAN, ""); err != nil {
+				details = append(details, ConfigError{
+					Field:   fmt.Sprintf("Config.Server.Auth.RoleMappings[%d].SAN", i),
+					Message: "must be a valid glob pattern",
+					Value:   m.SAN,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Server.GRPC.Enabled {
+		var details ValidationErrors
+		keepalive := cfg.Server.GRPC.Keepalive
+		if keepalive.TimeSeconds > 0 && keepalive.TimeoutSeconds >= keepalive.TimeSeconds {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Keepalive.TimeoutSeconds",
+				Message: "must be less than time_seconds",
+				Value:   keepalive.TimeoutSeconds,
+			})
+		}
+		interceptors := cfg.Server.GRPC.Interceptors
+		if interceptors.Auth.Enabled && len(interceptors.Auth.Tokens) == 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Interceptors.Auth.Tokens",
+				Message: "at least one token is required when auth is enabled",
+			})
+		}
+		if interceptors.RateLimit.Enabled && interceptors.RateLimit.RequestsPerSecond <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Interceptors.RateLimit.RequestsPerSecond",
+				Message: "must be greater than 0 when rate limiting is enabled",
+				Value:   interceptors.RateLimit.RequestsPerSecond,
+			})
+		}
+		if interceptors.RateLimit.Enabled && interceptors.RateLimit.Burst <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Interceptors.RateLimit.Burst",
+				Message: "must be greater than 0 when rate limiting is enabled",
+				Value:   interceptors.RateLimit.Burst,
+			})
+		}
+		agents := cfg.Server.GRPC.Agents
+		if agents.Enabled && agents.HeartbeatTimeoutSeconds <= agents.HeartbeatIntervalSeconds {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Agents.HeartbeatTimeoutSeconds",
+				Message: "must be greater than heartbeat_interval_seconds",
+				Value:   agents.HeartbeatTimeoutSeconds,
+			})
+		}
+		if agents.Enabled && cfg.Containers.Enabled && slices.Contains(agents.TaskTypes, "container") {
+			details = append(details, ConfigError{
+				Field:   "Config.Server.GRPC.Agents.TaskTypes",
+				Message: "cannot include container while containers.enabled runs container tasks",
+				Value:   agents.TaskTypes,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Signal.Delivery == "at_least_once" {
+		var details ValidationErrors
+		if cfg.Signal.Mode != "redis" {
+			details = append(details, ConfigError{
+				Field:   "Config.Signal.Delivery",
+				Message: "at_least_once requires signal mode redis",
+				Value:   cfg.Signal.Delivery,
+			})
+		}
+		if cfg.Signal.AckTimeout <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Signal.AckTimeout",
+				Message: "must be greater than 0 for at_least_once delivery",
+				Value:   cfg.Signal.AckTimeout,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && len(cfg.Signal.Schemas) > 0 {
+		var details ValidationErrors
+		for i, sc := range cfg.Signal.Schemas {
+			field := fmt.Sprintf("Config.Signal.Schemas[%d]", i)
+			if sc.Channel == "" {
+				details = append(details, ConfigError{
+					Field:   field + ".Channel",
+					Message: "is required",
+					Value:   sc.Channel,
+				})
+			}
+			switch sc.Type {
+			case "", "steer", "interrupt", "collect":
+			default:
+				details = append(details, ConfigError{
+					Field:   field + ".Type",
+					Message: "must be one of [steer interrupt collect]",
+					Value:   sc.Type,
+				})
+			}
+			if (len(sc.Schema) == 0) == (sc.File == "") {
+				details = append(details, ConfigError{
+					Field:   field + ".Schema",
+					Message: "exactly one of schema or file must be set",
+					Value:   sc.File,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
 	if cfg != nil && cfg.Saga.Enabled {
 		var details ValidationErrors
 		if cfg.Saga.WALRetention <= 0 {
@@ -113,6 +400,237 @@ func ValidateWithDetails(cfg *Config) error {
 				Value:   cfg.Saga.DefaultStepTimeout,
 			})
 		}
+		if cfg.Saga.StuckRunningTimeout < 0 || cfg.Saga.StuckCompensatingTimeout < 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Saga.StuckRunningTimeout",
+				Message: "stuck timeouts must not be negative",
+				Value:   cfg.Saga.StuckRunningTimeout,
+			})
+		}
+		if (cfg.Saga.StuckRunningTimeout > 0 || cfg.Saga.StuckCompensatingTimeout > 0) && cfg.Saga.WatchdogInterval <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Saga.WatchdogInterval",
+				Message: "must be greater than 0 when a stuck timeout is set",
+				Value:   cfg.Saga.WatchdogInterval,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Artifacts.Enabled {
+		var details ValidationErrors
+		switch cfg.Artifacts.Backend {
+		case "s3":
+			if strings.TrimSpace(cfg.Artifacts.S3.Bucket) == "" {
+				details = append(details, ConfigError{
+					Field:   "Config.Artifacts.S3.Bucket",
+					Message: "is required when the artifact backend is s3",
+					Value:   cfg.Artifacts.S3.Bucket,
+				})
+			}
+			if strings.TrimSpace(cfg.Artifacts.S3.Region) == "" {
+				details = append(details, ConfigError{
+					Field:   "Config.Artifacts.S3.Region",
+					Message: "is required when the artifact backend is s3",
+					Value:   cfg.Artifacts.S3.Region,
+				})
+			}
+		default:
+			if strings.TrimSpace(cfg.Artifacts.Dir) == "" {
+				details = append(details, ConfigError{
+					Field:   "Config.Artifacts.Dir",
+					Message: "is required when the artifact backend is local",
+					Value:   cfg.Artifacts.Dir,
+				})
+			}
+		}
+		if cfg.Artifacts.Retention > 0 && cfg.Artifacts.SweepInterval <= 0 {
+			details = append(details, ConfigError{
+				Field:   "Config.Artifacts.SweepInterval",
+				Message: "must be greater than 0 when artifacts have a retention",
+				Value:   cfg.Artifacts.SweepInterval,
+			})
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Containers.Enabled {
+		var details ValidationErrors
+		switch cfg.Containers.Runtime {
+		case "kubernetes":
+			if strings.TrimSpace(cfg.Containers.Kubernetes.Namespace) == "" {
+				details = append(details, ConfigError{
+					Field:   "Config.Containers.Kubernetes.Namespace",
+					Message: "is required when the container runtime is kubernetes",
+					Value:   cfg.Containers.Kubernetes.Namespace,
+				})
+			}
+		default:
+			host := cfg.Containers.Docker.Host
+			if !strings.HasPrefix(host, "unix://") && !strings.HasPrefix(host, "tcp://") {
+				details = append(details, ConfigError{
+					Field:   "Config.Containers.Docker.Host",
+					Message: "must be a unix:// or tcp:// address",
+					Value:   host,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Operator.Enabled && cfg.Operator.ResyncInterval <= 0 {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Operator.ResyncInterval",
+			Message: "must be positive when the operator is enabled",
+			Value:   cfg.Operator.ResyncInterval,
+		}}
+	}
+	if cfg != nil && cfg.Log.Syslog.Network != "" && cfg.Log.Syslog.Address == "" {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Log.Syslog.Address",
+			Message: "is required when network is set",
+			Value:   cfg.Log.Syslog.Address,
+		}}
+	}
+	if cfg != nil && len(cfg.Log.Components) > 0 {
+		var details ValidationErrors
+		for component, level := range cfg.Log.Components {
+			switch level {
+			case "debug", "info", "warn", "error":
+			default:
+				details = append(details, ConfigError{
+					Field:   fmt.Sprintf("Config.Log.Components[%s]", component),
+					Message: "must be one of debug, info, warn, error",
+					Value:   level,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && cfg.Orchestration.Costs.Enabled {
+		var details ValidationErrors
+		for dimension, rate := range cfg.Orchestration.Costs.Rates {
+			if rate < 0 {
+				details = append(details, ConfigError{
+					Field:   fmt.Sprintf("Config.Orchestration.Costs.Rates[%s]", dimension),
+					Message: "must not be negative",
+					Value:   rate,
+				})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && len(cfg.Orchestration.Tools) > 0 {
+		var details ValidationErrors
+		for name, tool := range cfg.Orchestration.Tools {
+			if _, ok := cfg.Orchestration.RateLimits[tool.RateLimit]; tool.RateLimit != "" && !ok {
+				details = append(details, ConfigError{
+					Field:   fmt.Sprintf("Config.Orchestration.Tools[%s].RateLimit", name),
+					Message: "must name an orchestration.rate_limits entry",
+					Value:   tool.RateLimit,
+				})
+			}
+			if tool.InputSchema != nil {
+				if _, err := jsonschema.Compile(tool.InputSchema); err != nil {
+					details = append(details, ConfigError{
+						Field:   fmt.Sprintf("Config.Orchestration.Tools[%s].InputSchema", name),
+						Message: err.Error(),
+					})
+				}
+			}
+			for _, secret := range tool.Secrets {
+				if _, ok := cfg.Orchestration.Secrets[secret]; !ok {
+					details = append(details, ConfigError{
+						Field:   fmt.Sprintf("Config.Orchestration.Tools[%s].Secrets", name),
+						Message: "must name orchestration.secrets entries",
+						Value:   secret,
+					})
+				}
+			}
+			for dimension, amount := range tool.Cost {
+				if amount < 0 {
+					details = append(details, ConfigError{
+						Field:   fmt.Sprintf("Config.Orchestration.Tools[%s].Cost[%s]", name, dimension),
+						Message: "must not be negative",
+						Value:   amount,
+					})
+				}
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	if cfg != nil && len(cfg.Orchestration.Budgets.Tenants) > 0 && !cfg.Orchestration.Costs.Enabled {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Orchestration.Budgets.Tenants",
+			Message: "requires cost accounting, which meters the usage budgets cap",
+			Value:   len(cfg.Orchestration.Budgets.Tenants),
+		}}
+	}
+	if cfg != nil && cfg.Orchestration.LLMCache.Enabled && cfg.Orchestration.LLMCache.Backend == "badger" && strings.TrimSpace(cfg.Orchestration.LLMCache.Path) == "" {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Orchestration.LLMCache.Path",
+			Message: "is required for the badger backend",
+			Value:   cfg.Orchestration.LLMCache.Path,
+		}}
+	}
+	if cfg != nil && cfg.Manage.Enabled && cfg.Manage.MaxParallelBackfill <= 0 {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Manage.MaxParallelBackfill",
+			Message: "must be positive when the management API is enabled",
+			Value:   cfg.Manage.MaxParallelBackfill,
+		}}
+	}
+	if cfg != nil && len(cfg.Manage.Webhooks) > 0 && !cfg.Manage.Enabled {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Manage.Webhooks",
+			Message: "requires the management API, which delivers the webhooks",
+		}}
+	}
+	if cfg != nil && cfg.Orchestration.Outbox.Enabled && !cfg.Manage.Enabled {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Orchestration.Outbox.Enabled",
+			Message: "requires the management API, which delivers the webhooks",
+			Value:   cfg.Orchestration.Outbox.Enabled,
+		}}
+	}
+	if cfg != nil && cfg.Policy.Enabled && strings.TrimSpace(cfg.Policy.URL) == "" {
+		return ValidationErrors{ConfigError{
+			Field:   "Config.Policy.URL",
+			Message: "is required when policy checks are enabled",
+			Value:   cfg.Policy.URL,
+		}}
+	}
+	if cfg != nil && len(cfg.Scrub.Rules) > 0 {
+		var details ValidationErrors
+		for i, rule := range cfg.Scrub.Rules {
+			field := fmt.Sprintf("Config.Scrub.Rules[%d]", i)
+			if rule.Pattern == "" && len(rule.Fields) == 0 {
+				details = append(details, ConfigError{
+					Field:   field,
+					Message: "must set a pattern or fields",
+					Value:   rule.Name,
+				})
+				continue
+			}
+			if rule.Pattern != "" {
+				if _, err := regexp.Compile(rule.Pattern); err != nil {
+					details = append(details, ConfigError{
+						Field:   field + ".Pattern",
+						Message: fmt.Sprintf("must be a valid regular expression: %v", err),
+						Value:   rule.Pattern,
+					})
+				}
+			}
+		}
 		if len(details) > 0 {
 			return details
 		}
@@ -240,3 +758,16 @@ func isValidHostChar(r rune) bool {
 		(r >= '0' && r <= '9') ||
 		r == '-' || r == '.' || r == ':' || r == '_' // Allow colon for IPv6, underscore for some cases
 }
+
+// isEnvVarName reports whether name is a portable environment variable name.
+func isEnvVarName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
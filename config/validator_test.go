@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Test structs for validating custom validators
@@ -160,3 +162,285 @@ func TestValidateWithDetails_UIBasePath(t *testing.T) {
 		t.Fatal("expected validation error for ui.base_path")
 	}
 }
+
+func TestValidateWithDetails_RedisCluster(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Redis.Cluster.Enabled = true
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Redis.Cluster.Addresses") {
+		t.Fatalf("expected cluster addresses error, got %v", err)
+	}
+
+	cfg.Redis.Cluster.Addresses = []string{"localhost:7000"}
+	cfg.Redis.Sentinel.Enabled = true
+	err = ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Redis.Sentinel.Enabled") {
+		t.Fatalf("expected sentinel/cluster conflict error, got %v", err)
+	}
+
+	cfg.Redis.Sentinel.Enabled = false
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid cluster config, got %v", err)
+	}
+	if mode := cfg.Redis.Mode(); mode != "cluster" {
+		t.Fatalf("expected cluster mode, got %q", mode)
+	}
+}
+
+func TestValidateWithDetails_AdaptiveConcurrency(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Queue.Adaptive.Enabled = true
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Queue.Adaptive.TargetLatency") {
+		t.Fatalf("expected missing signal error, got %v", err)
+	}
+
+	cfg.Orchestration.Queue.Adaptive.TargetLatency = 500 * time.Millisecond
+	cfg.Orchestration.Queue.Adaptive.MinConcurrency = cfg.Orchestration.MaxAgents + 1
+	err = ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Queue.Adaptive.MinConcurrency") {
+		t.Fatalf("expected min concurrency error, got %v", err)
+	}
+
+	cfg.Orchestration.Queue.Adaptive.MinConcurrency = 2
+	cfg.Orchestration.Queue.Type = "redis"
+	err = ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Queue.Adaptive.Enabled") {
+		t.Fatalf("expected queue type error, got %v", err)
+	}
+
+	cfg.Orchestration.Queue.Type = "memory"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid adaptive config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_EDFDispatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Queue.Dispatch = "lifo"
+	if err := ValidateWithDetails(cfg); err == nil {
+		t.Fatal("expected invalid dispatch error")
+	}
+
+	cfg.Orchestration.Queue.Dispatch = "edf"
+	cfg.Orchestration.Queue.Type = "redis"
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Queue.Dispatch") {
+		t.Fatalf("expected queue type error, got %v", err)
+	}
+
+	cfg.Orchestration.Queue.Type = "memory"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid edf config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_QueueResources(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Queue.Resources.GPU = -1
+	if err := ValidateWithDetails(cfg); err == nil {
+		t.Fatal("expected negative gpu error")
+	}
+
+	cfg.Orchestration.Queue.Resources = LaneResourcesConfig{CPU: 8, MemoryMB: 16384, GPU: 2}
+	cfg.Orchestration.Queue.Type = "redis"
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Queue.Resources") {
+		t.Fatalf("expected queue type error, got %v", err)
+	}
+
+	cfg.Orchestration.Queue.Type = "memory"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid resource config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_CostRates(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Costs.Enabled = true
+	cfg.Orchestration.Costs.Rates = map[string]float64{"task_seconds:gpu": -1}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Costs.Rates[task_seconds:gpu]") {
+		t.Fatalf("expected cost rate error, got %v", err)
+	}
+
+	cfg.Orchestration.Costs.Rates["task_seconds:gpu"] = 0.002
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid cost config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_TenantBudgets(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Budgets.Tenants = map[string]BudgetConfig{"team-a": {Tokens: 1e6}}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Budgets.Tenants") {
+		t.Fatalf("expected cost accounting error, got %v", err)
+	}
+
+	cfg.Orchestration.Costs.Enabled = true
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid budget config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_ToolReferences(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Tools = map[string]ToolConfig{
+		"search": {
+			Auth:        "api_key",
+			Secrets:     []string{"search_key"},
+			RateLimit:   "search-api",
+			InputSchema: map[string]any{"type": "query"},
+		},
+	}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Tools[search].RateLimit") ||
+		!strings.Contains(err.Error(), "Config.Orchestration.Tools[search].Secrets") ||
+		!strings.Contains(err.Error(), "Config.Orchestration.Tools[search].InputSchema") {
+		t.Fatalf("expected tool reference errors, got %v", err)
+	}
+	search := cfg.Orchestration.Tools["search"]
+	search.InputSchema = map[string]any{"type": "object"}
+	cfg.Orchestration.Tools["search"] = search
+
+	cfg.Orchestration.RateLimits = map[string]RateLimitConfig{"search-api": {Limit: 10}}
+	cfg.Orchestration.Secrets = map[string]string{"search_key": "env://SEARCH_KEY"}
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid tool config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_ModelRoutes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.ModelRoutes = map[string]ModelRouteConfig{
+		"chat": {
+			Primary:    ModelTargetConfig{Provider: "openai", Model: "gpt-4o"},
+			Retry:      &ModelTargetConfig{Provider: "openai"},
+			FallbackOn: "timeout",
+		},
+	}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.ModelRoutes[chat].Retry.Model") ||
+		!strings.Contains(err.Error(), "Config.Orchestration.ModelRoutes[chat].FallbackOn") {
+		t.Fatalf("expected model route errors, got %v", err)
+	}
+
+	route := cfg.Orchestration.ModelRoutes["chat"]
+	route.Retry.Model = "gpt-4o-mini"
+	route.FallbackOn = "rate_limit"
+	route.Fallbacks = []ModelTargetConfig{{Provider: "anthropic", Model: "claude-haiku"}}
+	cfg.Orchestration.ModelRoutes["chat"] = route
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid model routes, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_LogComponents(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Log.Components = map[string]string{"lane": "verbose"}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Log.Components[lane]") {
+		t.Fatalf("expected component level error, got %v", err)
+	}
+
+	cfg.Log.Components["lane"] = "debug"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid log config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_LogSyslog(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Log.Output = "syslog"
+	cfg.Log.Syslog.Network = "udp"
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Log.Syslog.Address") {
+		t.Fatalf("expected syslog address error, got %v", err)
+	}
+
+	cfg.Log.Syslog.Address = "logs.example.com:514"
+	cfg.Log.Syslog.Facility = "local9"
+	if err := ValidateWithDetails(cfg); err == nil {
+		t.Fatal("expected invalid facility error")
+	}
+
+	cfg.Log.Syslog.Facility = "local0"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid syslog config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_WorkflowLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Orchestration.Workflows.PerName = map[string]int{"nightly-report": 0}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Orchestration.Workflows.PerName[nightly-report]") {
+		t.Fatalf("expected per-name limit error, got %v", err)
+	}
+
+	cfg.Orchestration.Workflows.PerName["nightly-report"] = 1
+	cfg.Orchestration.Workflows.Policy = "drop"
+	if err := ValidateWithDetails(cfg); err == nil {
+		t.Fatal("expected invalid policy error")
+	}
+
+	cfg.Orchestration.Workflows.Policy = "reject"
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid workflow limits, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_SignalAtLeastOnce(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Signal.Delivery = "at_least_once"
+
+	err := ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Signal.Delivery") {
+		t.Fatalf("expected redis mode error, got %v", err)
+	}
+
+	cfg.Signal.Mode = "redis"
+	cfg.Signal.AckTimeout = 0
+	err = ValidateWithDetails(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Config.Signal.AckTimeout") {
+		t.Fatalf("expected ack timeout error, got %v", err)
+	}
+
+	cfg.Signal.AckTimeout = 10 * time.Second
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid at_least_once config, got %v", err)
+	}
+}
+
+func TestValidateWithDetails_SignalSchemas(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Signal.Schemas = []SignalSchemaConfig{
+		{Type: "resume", File: "steer.json"},
+		{Channel: "task-*", Schema: map[string]interface{}{"type": "object"}, File: "steer.json"},
+	}
+
+	err := ValidateWithDetails(cfg)
+	if err == nil {
+		t.Fatal("expected signal schema errors")
+	}
+	for _, field := range []string{"Config.Signal.Schemas[0].Channel", "Config.Signal.Schemas[0].Type", "Config.Signal.Schemas[1].Schema"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("expected error for %s, got %v", field, err)
+		}
+	}
+
+	cfg.Signal.Schemas = []SignalSchemaConfig{{Channel: "task-*", Type: "steer", File: "steer.json"}}
+	if err := ValidateWithDetails(cfg); err != nil {
+		t.Fatalf("expected valid signal schema config, got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ type Watcher struct {
 	watcher    *fsnotify.Watcher
 	loader     *Loader
 	configPath string
+	overrides  map[string]interface{}
 	callbacks  []func(*Config)
 	debounce   time.Duration
 	stopCh     chan struct{}
@@ -31,6 +32,13 @@ func WithDebounce(d time.Duration) WatcherOption {
 	}
 }
 
+// WithOverrides sets CLI overrides that are re-applied on every reload.
+func WithOverrides(overrides map[string]interface{}) WatcherOption {
+	return func(w *Watcher) {
+		w.overrides = overrides
+	}
+}
+
 // NewWatcher creates a new configuration file watcher.
 func NewWatcher(configPath string, loader *Loader, opts ...WatcherOption) (*Watcher, error) {
 	if configPath == "" {
@@ -129,9 +137,17 @@ func (w *Watcher) Watch(ctx context.Context) error {
 	}
 }
 
-// reloadConfig reloads the configuration and notifies callbacks.
+// Reload reloads the configuration immediately and notifies callbacks.
+// It is intended for explicit triggers such as SIGHUP.
+func (w *Watcher) Reload(ctx context.Context) {
+	w.reloadConfig(ctx)
+}
+
+// reloadConfig reloads the configuration and notifies callbacks. Each
+// reload starts from a fresh loader, so that keys deleted from the file fall
+// back to their defaults.
 func (w *Watcher) reloadConfig(ctx context.Context) {
-	cfg, err := w.loader.Load(w.configPath, nil)
+	cfg, err := w.loader.clone().Load(w.configPath, w.overrides)
 	if err != nil {
 		fmt.Printf("failed to reload config: %v\n", err)
 		return
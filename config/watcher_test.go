@@ -244,6 +244,47 @@ func TestWatcher_OnChange(t *testing.T) {
 	mu.Unlock()
 }
 
+func TestWatcher_ReloadDropsDeletedKeys(t *testing.T) {
+	loader := NewLoader()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("manage:\n  enabled: true\n  webhooks:\n    ops:\n      url: https://ops.example.com\n"), 0644); err != nil {
+		t.Fatalf("failed to create temp config: %v", err)
+	}
+	cfg, err := loader.Load(configPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := cfg.Manage.Webhooks["ops"]; !ok {
+		t.Fatalf("expected the ops webhook to load, got %v", cfg.Manage.Webhooks)
+	}
+
+	watcher, err := NewWatcher(configPath, loader)
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+	reloaded := make(chan *Config, 1)
+	watcher.OnChange(func(cfg *Config) {
+		reloaded <- cfg
+	})
+
+	if err := os.WriteFile(configPath, []byte("manage:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatalf("failed to update temp config: %v", err)
+	}
+	watcher.Reload(context.Background())
+
+	select {
+	case cfg := <-reloaded:
+		if len(cfg.Manage.Webhooks) != 0 {
+			t.Errorf("expected the deleted webhook to be gone, got %v", cfg.Manage.Webhooks)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the callback to be called")
+	}
+}
+
 func TestWatcher_Stop(t *testing.T) {
 	loader := NewLoader()
 
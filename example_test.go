@@ -0,0 +1,87 @@
+package goclaw_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/goclaw/goclaw"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/engine"
+)
+
+// greetExecutor runs "greet" tasks.
+type greetExecutor struct{}
+
+func (greetExecutor) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		fmt.Printf("hello from %s\n", task.ID)
+		return nil
+	}
+}
+
+// workflowPrinter prints workflow state changes.
+type workflowPrinter struct{}
+
+func (workflowPrinter) BroadcastWorkflowStateChanged(workflowID, name, oldState, newState string, updatedAt time.Time) {
+	fmt.Printf("workflow %s: %s\n", name, newState)
+}
+
+func (workflowPrinter) BroadcastTaskStateChanged(workflowID, taskID, taskName, oldState, newState, errorMessage string, result any, updatedAt time.Time) {
+}
+
+func ExampleNew() {
+	ctx := context.Background()
+	eng, err := goclaw.New(
+		goclaw.WithExecutor("greet", greetExecutor{}),
+		goclaw.WithEventBroadcaster(workflowPrinter{}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := eng.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	defer eng.Stop(ctx)
+
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "hello",
+		Tasks: []models.TaskDefinition{{ID: "greeting", Name: "greeting", Type: "greet"}},
+	}, engine.SubmitWorkflowOptions{Mode: engine.SubmissionModeSync})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("status:", resp.Status)
+	// Output:
+	// workflow hello: pending
+	// workflow hello: scheduled
+	// workflow hello: running
+	// hello from greeting
+	// workflow hello: completed
+	// status: completed
+}
+
+func ExampleWithEngineOptions() {
+	ctx := context.Background()
+	eng, err := goclaw.New(goclaw.WithEngineOptions(
+		engine.WithOnStart(func(ctx context.Context, e *engine.Engine) error {
+			return e.PutLane("reports", 100, 2, 0)
+		}),
+	))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := eng.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	defer eng.Stop(ctx)
+
+	stats, err := eng.LaneStatsByName("reports")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(stats.Name, stats.Capacity)
+	// Output: reports 100
+}
@@ -0,0 +1,193 @@
+// Package goclaw embeds the Goclaw orchestration engine in another Go
+// service. It wires pkg/engine with storage, executors and event listeners
+// but none of the HTTP, gRPC, metrics or config reload servers that
+// cmd/goclaw adds around it, so a service can run workflows in-process:
+//
+//	eng, err := goclaw.New(
+//		goclaw.WithExecutor("shell", shellExecutor{}),
+//		goclaw.WithEventBroadcaster(listener),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	if err := eng.Start(ctx); err != nil {
+//		return err
+//	}
+//	defer eng.Stop(context.Background())
+//
+// The returned Engine is a *engine.Engine; see that package for submitting
+// workflows and reading their state.
+package goclaw
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/storage"
+	badgerstorage "github.com/goclaw/goclaw/pkg/storage/badger"
+	cachestorage "github.com/goclaw/goclaw/pkg/storage/cache"
+	encryptedstorage "github.com/goclaw/goclaw/pkg/storage/encrypted"
+	memstorage "github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	cfg        *config.Config
+	log        logger.Logger
+	store      storage.Storage
+	engineOpts []engine.Option
+}
+
+// WithConfig sets the configuration. It defaults to config.DefaultConfig;
+// only the engine, orchestration and storage settings apply.
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) {
+		if cfg != nil {
+			o.cfg = cfg
+		}
+	}
+}
+
+// WithLogger sets the logger of the engine. The engine logs nothing by
+// default.
+func WithLogger(log logger.Logger) Option {
+	return func(o *options) {
+		if log != nil {
+			o.log = log
+		}
+	}
+}
+
+// WithStorage sets the storage of workflow and task state. The caller keeps
+// ownership: Stop does not close it. By default the storage configured in
+// the config is opened with OpenStorage and closed by Stop.
+func WithStorage(store storage.Storage) Option {
+	return func(o *options) {
+		if store != nil {
+			o.store = store
+		}
+	}
+}
+
+// WithExecutor runs tasks of agent type taskType that have no task function
+// with exec.
+func WithExecutor(taskType string, exec engine.TaskExecutor) Option {
+	return WithEngineOptions(engine.WithTaskExecutor(taskType, exec))
+}
+
+// WithEventBroadcaster receives workflow and task state changes.
+func WithEventBroadcaster(broadcaster engine.EventBroadcaster) Option {
+	return WithEngineOptions(engine.WithEventBroadcaster(broadcaster))
+}
+
+// WithEngineOptions passes options, such as lifecycle hooks or a metrics
+// recorder, to engine.New.
+func WithEngineOptions(opts ...engine.Option) Option {
+	return func(o *options) {
+		o.engineOpts = append(o.engineOpts, opts...)
+	}
+}
+
+// Engine is an embedded engine. It is an *engine.Engine whose Stop also
+// closes the storage New opened.
+type Engine struct {
+	*engine.Engine
+
+	store     storage.Storage
+	ownsStore bool
+	closeOnce sync.Once
+}
+
+// New creates an engine that is not started yet.
+func New(opts ...Option) (*Engine, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.cfg == nil {
+		o.cfg = config.DefaultConfig()
+	}
+
+	store, ownsStore := o.store, false
+	if store == nil {
+		var err error
+		if store, err = OpenStorage(o.cfg.Storage); err != nil {
+			return nil, err
+		}
+		ownsStore = true
+	}
+
+	eng, err := engine.New(o.cfg, o.log, store, o.engineOpts...)
+	if err != nil {
+		if ownsStore {
+			store.Close()
+		}
+		return nil, err
+	}
+	return &Engine{Engine: eng, store: store, ownsStore: ownsStore}, nil
+}
+
+// Storage returns the storage of the engine.
+func (e *Engine) Storage() storage.Storage {
+	return e.store
+}
+
+// Stop stops the engine and closes the storage New opened.
+func (e *Engine) Stop(ctx context.Context) error {
+	err := e.Engine.Stop(ctx)
+	if e.ownsStore {
+		e.closeOnce.Do(func() {
+			if closeErr := e.store.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		})
+	}
+	return err
+}
+
+// OpenStorage opens the storage backend of cfg: "memory", or "badger" with
+// the read-through cache in front when CacheSize is set. When an encryption
+// master key is configured, sensitive task data is sealed before it reaches
+// the backend.
+func OpenStorage(cfg config.StorageConfig) (storage.Storage, error) {
+	var sealer *encryptedstorage.Sealer
+	if cfg.Encryption.MasterKey != "" {
+		var err error
+		if sealer, err = encryptedstorage.NewSealerFromBase64(cfg.Encryption.MasterKey); err != nil {
+			return nil, fmt.Errorf("storage encryption: %w", err)
+		}
+	}
+	seal := func(store storage.Storage) storage.Storage {
+		if sealer == nil {
+			return store
+		}
+		return encryptedstorage.NewEncryptedStorage(store, sealer)
+	}
+
+	switch cfg.Type {
+	case "", "memory":
+		return seal(memstorage.NewMemoryStorage()), nil
+	case "badger":
+		store, err := badgerstorage.NewBadgerStorage(&badgerstorage.Config{
+			Path:             cfg.Badger.Path,
+			SyncWrites:       cfg.Badger.SyncWrites,
+			ValueLogFileSize: cfg.Badger.ValueLogFileSize,
+			Encoding:         cfg.Encoding,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if cfg.CacheSize > 0 {
+			return cachestorage.NewCachedStorage(seal(store), cfg.CacheSize), nil
+		}
+		return seal(store), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage type %q", cfg.Type)
+	}
+}
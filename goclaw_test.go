@@ -0,0 +1,58 @@
+package goclaw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	cachestorage "github.com/goclaw/goclaw/pkg/storage/cache"
+)
+
+func TestOpenStorage(t *testing.T) {
+	cfg := config.DefaultConfig().Storage
+	cfg.Type = "badger"
+	cfg.Badger.Path = t.TempDir()
+	cfg.CacheSize = 16
+
+	store, err := OpenStorage(cfg)
+	if err != nil {
+		t.Fatalf("OpenStorage() error = %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*cachestorage.CachedStorage); !ok {
+		t.Fatalf("expected cached badger storage, got %T", store)
+	}
+
+	cfg.Type = "redis"
+	if _, err := OpenStorage(cfg); err == nil {
+		t.Fatal("expected error for unsupported storage type")
+	}
+}
+
+func TestEngine_StopClosesOwnedStorage(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.Type = "badger"
+	cfg.Storage.Badger.Path = t.TempDir()
+
+	eng, err := New(WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := eng.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := eng.Stop(ctx); err != nil {
+		t.Fatalf("second Stop() error = %v", err)
+	}
+
+	// The Badger directory is free again once the storage is closed.
+	store, err := OpenStorage(cfg.Storage)
+	if err != nil {
+		t.Fatalf("reopening storage after Stop: %v", err)
+	}
+	store.Close()
+}
@@ -0,0 +1,549 @@
+// Package agent tracks remote workers connected over a persistent control
+// channel and dispatches tasks to them. The registry is transport agnostic:
+// the gRPC AgentService feeds it agent messages and relays its commands.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often agents are asked to heartbeat.
+	DefaultHeartbeatInterval = 10 * time.Second
+
+	// DefaultHeartbeatTimeout is how long an agent may stay silent before it
+	// is considered lost.
+	DefaultHeartbeatTimeout = 30 * time.Second
+
+	// commandBuffer is the number of commands queued per agent before
+	// senders block.
+	commandBuffer = 16
+)
+
+var (
+	// ErrClosed is returned once the registry has been closed.
+	ErrClosed = errs.New(errs.ServiceUnavailable, "agent: registry is closed")
+
+	// ErrHeartbeatTimeout fails the tasks of an agent that stopped heartbeating.
+	ErrHeartbeatTimeout = errs.New(errs.ServiceUnavailable, "agent: heartbeat timed out")
+
+	// ErrDisconnected fails the tasks of an agent whose channel closed.
+	ErrDisconnected = errs.New(errs.ServiceUnavailable, "agent: disconnected")
+
+	// ErrUnknownAssignment is returned for progress or completions of
+	// assignments the agent does not hold.
+	ErrUnknownAssignment = errs.New(errs.NotFound, "agent: unknown assignment")
+)
+
+// Registration announces an agent.
+type Registration struct {
+	ID string
+
+	// Types are the task agent types the agent executes. Empty accepts any.
+	Types []string
+
+	// Capacity is the maximum number of tasks the agent runs at once.
+	Capacity int
+
+	Labels map[string]string
+}
+
+// Info is a snapshot of a connected agent.
+type Info struct {
+	Registration
+	ActiveTasks   int
+	ConnectedAt   time.Time
+	LastHeartbeat time.Time
+}
+
+// Assignment is a task handed to an agent.
+type Assignment struct {
+	ID         string
+	WorkflowID string
+	TaskID     string
+	Name       string
+	AgentType  string
+	Payload    []byte
+	Deadline   time.Time
+	// Env is the task's environment, including resolved secrets.
+	Env map[string]string
+}
+
+// Cancellation asks an agent to stop an assignment.
+type Cancellation struct {
+	AssignmentID string
+	Reason       string
+}
+
+// Command is sent to an agent. Exactly one field is set.
+type Command struct {
+	Assign *Assignment
+	Cancel *Cancellation
+}
+
+// Progress is reported by an agent for a running assignment.
+type Progress struct {
+	AgentID      string
+	AssignmentID string
+	Percent      float64
+	Message      string
+}
+
+// Option configures a Registry.
+type Option func(*Registry)
+
+// WithHeartbeatInterval sets the interval agents are asked to heartbeat at,
+// which is also how often lost agents are swept by Run.
+func WithHeartbeatInterval(d time.Duration) Option {
+	return func(r *Registry) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// WithHeartbeatTimeout sets how long an agent may stay silent before it is
+// evicted and its tasks fail with ErrHeartbeatTimeout.
+func WithHeartbeatTimeout(d time.Duration) Option {
+	return func(r *Registry) {
+		if d > 0 {
+			r.timeout = d
+		}
+	}
+}
+
+// WithProgressHandler sets a function called for every progress report.
+func WithProgressHandler(fn func(Progress)) Option {
+	return func(r *Registry) {
+		r.onProgress = fn
+	}
+}
+
+// Registry tracks connected agents, their capacity and liveness.
+type Registry struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	// changed is closed and replaced whenever an agent joins or frees
+	// capacity, waking dispatchers waiting for one.
+	changed chan struct{}
+	closed  bool
+
+	interval   time.Duration
+	timeout    time.Duration
+	onProgress func(Progress)
+	now        func() time.Time
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		sessions: make(map[string]*Session),
+		changed:  make(chan struct{}),
+		interval: DefaultHeartbeatInterval,
+		timeout:  DefaultHeartbeatTimeout,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HeartbeatInterval returns the interval agents must heartbeat at.
+func (r *Registry) HeartbeatInterval() time.Duration {
+	return r.interval
+}
+
+// Register adds an agent. An agent ID can only be connected once.
+func (r *Registry) Register(reg Registration) (*Session, error) {
+	if reg.ID == "" {
+		return nil, errs.New(errs.BadRequest, "agent: id is required")
+	}
+	if reg.Capacity < 1 {
+		return nil, errs.New(errs.BadRequest, "agent: capacity must be at least 1")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, ErrClosed
+	}
+	if _, ok := r.sessions[reg.ID]; ok {
+		return nil, errs.Newf(errs.AlreadyExists, "agent: %s is already connected", reg.ID)
+	}
+
+	now := r.now()
+	reg.Types = slices.Clone(reg.Types)
+	reg.Labels = maps.Clone(reg.Labels)
+	s := &Session{
+		registry: r,
+		info:     Info{Registration: reg, ConnectedAt: now, LastHeartbeat: now},
+		commands: make(chan Command, commandBuffer),
+		pending:  make(map[string]*outstanding),
+		done:     make(chan struct{}),
+	}
+	r.sessions[reg.ID] = s
+	r.notifyLocked()
+	return s, nil
+}
+
+// Unregister removes an agent and fails its outstanding assignments with
+// cause. It is safe to call more than once.
+func (r *Registry) Unregister(s *Session, cause error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeLocked(s, cause)
+}
+
+func (r *Registry) removeLocked(s *Session, cause error) {
+	if s.err != nil {
+		return
+	}
+	if cause == nil {
+		cause = ErrDisconnected
+	}
+	if r.sessions[s.info.ID] == s {
+		delete(r.sessions, s.info.ID)
+	}
+	s.err = cause
+	close(s.done)
+	for id, o := range s.pending {
+		o.result <- result{err: cause}
+		delete(s.pending, id)
+	}
+	s.info.ActiveTasks = 0
+	r.notifyLocked()
+}
+
+// Agents returns the connected agents ordered by ID.
+func (r *Registry) Agents() []Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]Info, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		info := s.info
+		info.Types = slices.Clone(info.Types)
+		info.Labels = maps.Clone(info.Labels)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Sweep evicts agents that have not been heard from within the heartbeat
+// timeout.
+func (r *Registry) Sweep() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := r.now().Add(-r.timeout)
+	for _, s := range r.sessions {
+		if s.info.LastHeartbeat.Before(cutoff) {
+			r.removeLocked(s, ErrHeartbeatTimeout)
+		}
+	}
+}
+
+// Run sweeps lost agents every heartbeat interval until ctx is done.
+func (r *Registry) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Sweep()
+		}
+	}
+}
+
+// Close disconnects every agent and fails all waiting and outstanding
+// dispatches with ErrClosed.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	for _, s := range r.sessions {
+		r.removeLocked(s, ErrClosed)
+	}
+	r.notifyLocked()
+}
+
+// Dispatch assigns a to the least loaded agent accepting its type, waiting
+// for capacity if every such agent is busy, and returns the agent's result.
+// Cancelling ctx sends the agent a cancellation. While the agent holds the
+// assignment, its heartbeats and progress reports are forwarded to
+// engine.Heartbeat(ctx), so engine tasks dispatched to agents need no
+// heartbeats of their own.
+func (r *Registry) Dispatch(ctx context.Context, a Assignment) ([]byte, error) {
+	if a.ID == "" {
+		a.ID = uuid.NewString()
+	}
+	if deadline, ok := ctx.Deadline(); ok && a.Deadline.IsZero() {
+		a.Deadline = deadline
+	}
+
+	s, resultCh, err := r.reserve(ctx, a, func() { engine.Heartbeat(ctx) })
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.commands <- Command{Assign: &a}:
+	case <-s.done:
+	case <-ctx.Done():
+		s.release(a.ID)
+		return nil, ctx.Err()
+	}
+
+	select {
+	case res := <-resultCh:
+		return res.data, res.err
+	case <-ctx.Done():
+		if s.release(a.ID) {
+			s.send(Command{Cancel: &Cancellation{AssignmentID: a.ID, Reason: ctx.Err().Error()}})
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// TaskPayload is the JSON encoded Payload of the assignments of TaskFunc.
+type TaskPayload struct {
+	// Config is the task's config, with its placeholders evaluated.
+	Config map[string]any `json:"config,omitempty"`
+
+	// Upstream holds the results of the completed tasks the task depends
+	// on, by task ID.
+	Upstream map[string]any `json:"upstream,omitempty"`
+}
+
+// TaskFunc returns a function running task on an agent, which makes the
+// registry an engine.TaskExecutor. The task's Agent field selects the agent
+// type; its config and the results of its dependencies are sent as a
+// TaskPayload, and its environment from engine.TaskEnv along with it. A JSON
+// result of the agent becomes the task's result.
+func (r *Registry) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		payload, err := taskPayload(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := r.Dispatch(ctx, Assignment{
+			WorkflowID: workflowID,
+			TaskID:     task.ID,
+			Name:       task.Name,
+			AgentType:  task.Agent,
+			Payload:    payload,
+			Env:        engine.TaskEnv(ctx),
+		})
+		if err != nil || !json.Valid(data) {
+			return err
+		}
+		return engine.SetTaskResult(ctx, json.RawMessage(data))
+	}
+}
+
+// taskPayload encodes the TaskPayload of the task running with ctx. Outside
+// of tasks run by the engine it is empty.
+func taskPayload(ctx context.Context) ([]byte, error) {
+	var payload TaskPayload
+	if config, err := engine.TaskConfig(ctx); err == nil && len(config) > 0 {
+		payload.Config = config
+	}
+	deps, _ := engine.TaskDependencies(ctx)
+	for _, dep := range deps {
+		// Dependencies that were skipped have no result.
+		result, err := engine.UpstreamResult(ctx, dep)
+		if err != nil {
+			continue
+		}
+		if payload.Upstream == nil {
+			payload.Upstream = make(map[string]any, len(deps))
+		}
+		payload.Upstream[dep] = result
+	}
+	return json.Marshal(payload)
+}
+
+// reserve waits for an agent with free capacity and records a on it
+func (r *Registry) reserve(ctx context.Context, a Assignment, beat func()) (*Session, chan result, error) {
+	for {
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			return nil, nil, ErrClosed
+		}
+		if s := r.pickLocked(a.AgentType); s != nil {
+			ch := make(chan result, 1)
+			s.pending[a.ID] = &outstanding{result: ch, beat: beat}
+			s.info.ActiveTasks++
+			r.mu.Unlock()
+			return s, ch, nil
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// pickLocked returns the least loaded agent accepting agentType with free
+// capacity, or nil
+func (r *Registry) pickLocked(agentType string) *Session {
+	var best *Session
+	for _, s := range r.sessions {
+		if s.info.ActiveTasks >= s.info.Capacity || !s.accepts(agentType) {
+			continue
+		}
+		if best == nil || s.load() < best.load() || (s.load() == best.load() && s.info.ID < best.info.ID) {
+			best = s
+		}
+	}
+	return best
+}
+
+func (r *Registry) notifyLocked() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+type result struct {
+	data []byte
+	err  error
+}
+
+// outstanding is an assignment held by an agent.
+type outstanding struct {
+	result chan result
+	// beat forwards the agent's liveness to the dispatching task.
+	beat func()
+}
+
+// Session is the registry's side of one agent's control channel.
+type Session struct {
+	registry *Registry
+	commands chan Command
+	done     chan struct{}
+
+	// Guarded by registry.mu.
+	info    Info
+	pending map[string]*outstanding
+	err     error
+}
+
+// ID returns the agent ID.
+func (s *Session) ID() string { return s.info.ID }
+
+// Commands returns the commands to relay to the agent.
+func (s *Session) Commands() <-chan Command { return s.commands }
+
+// Done is closed when the agent has been unregistered.
+func (s *Session) Done() <-chan struct{} { return s.done }
+
+// Err returns why the agent was unregistered, or nil while it is connected.
+func (s *Session) Err() error {
+	s.registry.mu.Lock()
+	defer s.registry.mu.Unlock()
+	return s.err
+}
+
+// Heartbeat records that the agent, and so every task it holds, is alive.
+func (s *Session) Heartbeat() {
+	s.registry.mu.Lock()
+	defer s.registry.mu.Unlock()
+	s.info.LastHeartbeat = s.registry.now()
+	for _, o := range s.pending {
+		o.beat()
+	}
+}
+
+// Progress records a progress report, which also counts as a heartbeat.
+func (s *Session) Progress(p Progress) error {
+	r := s.registry
+	r.mu.Lock()
+	o, ok := s.pending[p.AssignmentID]
+	if ok {
+		o.beat()
+	}
+	s.info.LastHeartbeat = r.now()
+	r.mu.Unlock()
+
+	if !ok {
+		return ErrUnknownAssignment
+	}
+	if r.onProgress != nil {
+		p.AgentID = s.info.ID
+		r.onProgress(p)
+	}
+	return nil
+}
+
+// Complete delivers the outcome of an assignment to its dispatcher and frees
+// the capacity it held. A non-nil taskErr fails the task.
+func (s *Session) Complete(assignmentID string, data []byte, taskErr error) error {
+	r := s.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := s.pending[assignmentID]
+	if !ok {
+		return ErrUnknownAssignment
+	}
+	delete(s.pending, assignmentID)
+	s.info.ActiveTasks--
+	s.info.LastHeartbeat = r.now()
+	o.result <- result{data: data, err: taskErr}
+	r.notifyLocked()
+	return nil
+}
+
+// release drops an assignment without a result, reporting whether it was
+// still outstanding
+func (s *Session) release(assignmentID string) bool {
+	r := s.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := s.pending[assignmentID]; !ok {
+		return false
+	}
+	delete(s.pending, assignmentID)
+	s.info.ActiveTasks--
+	r.notifyLocked()
+	return true
+}
+
+// send queues cmd unless the agent has gone away
+func (s *Session) send(cmd Command) {
+	select {
+	case s.commands <- cmd:
+	case <-s.done:
+	}
+}
+
+func (s *Session) accepts(agentType string) bool {
+	return len(s.info.Types) == 0 || slices.Contains(s.info.Types, agentType)
+}
+
+func (s *Session) load() float64 {
+	return float64(s.info.ActiveTasks) / float64(s.info.Capacity)
+}
@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestRegistry_DispatchToLeastLoadedAgent(t *testing.T) {
+	r := NewRegistry()
+	busy, err := r.Register(Registration{ID: "busy", Types: []string{"shell"}, Capacity: 2})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	idle, _ := r.Register(Registration{ID: "idle", Capacity: 2})
+	if _, err := r.Register(Registration{ID: "busy", Capacity: 1}); err == nil {
+		t.Fatal("expected duplicate agent ID to be rejected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// First shell task goes to "busy" (tie broken by ID), the second to "idle".
+	first, second := make(chan error, 1), make(chan error, 1)
+	go func() {
+		data, err := r.Dispatch(ctx, Assignment{ID: "a1", AgentType: "shell"})
+		if err == nil && string(data) != "ok" {
+			err = errors.New("unexpected result " + string(data))
+		}
+		first <- err
+	}()
+	cmd := <-busy.Commands()
+	if cmd.Assign == nil || cmd.Assign.ID != "a1" {
+		t.Fatalf("expected a1 on busy agent, got %+v", cmd)
+	}
+
+	go func() {
+		_, err := r.Dispatch(ctx, Assignment{ID: "a2", AgentType: "shell"})
+		second <- err
+	}()
+	cmd = <-idle.Commands()
+	if cmd.Assign == nil || cmd.Assign.ID != "a2" {
+		t.Fatalf("expected a2 on idle agent, got %+v", cmd)
+	}
+
+	for _, info := range r.Agents() {
+		if info.ActiveTasks != 1 {
+			t.Fatalf("expected one active task per agent, got %+v", info)
+		}
+	}
+
+	if err := busy.Complete("a1", []byte("ok"), nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := idle.Complete("a2", nil, errors.New("exit status 1")); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := <-first; err != nil {
+		t.Fatalf("expected a1 to succeed, got %v", err)
+	}
+	if err := <-second; err == nil {
+		t.Fatal("expected a2 to fail")
+	}
+	if err := busy.Complete("a1", nil, nil); !errors.Is(err, ErrUnknownAssignment) {
+		t.Fatalf("expected ErrUnknownAssignment for repeated completion, got %v", err)
+	}
+}
+
+func TestRegistry_DispatchWaitsForCapacity(t *testing.T) {
+	r := NewRegistry()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := r.Dispatch(ctx, Assignment{ID: "a1", AgentType: "gpu"})
+		results <- err
+	}()
+
+	// An agent of another type does not take the task.
+	other, _ := r.Register(Registration{ID: "cpu", Types: []string{"cpu"}, Capacity: 1})
+	s, _ := r.Register(Registration{ID: "gpu", Types: []string{"gpu"}, Capacity: 1})
+
+	cmd := <-s.Commands()
+	if cmd.Assign == nil || cmd.Assign.ID != "a1" {
+		t.Fatalf("expected a1 on gpu agent, got %+v", cmd)
+	}
+	select {
+	case cmd := <-other.Commands():
+		t.Fatalf("expected no command for cpu agent, got %+v", cmd)
+	default:
+	}
+
+	_ = s.Complete("a1", nil, nil)
+	if err := <-results; err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+}
+
+func TestRegistry_DispatchCancelled(t *testing.T) {
+	r := NewRegistry()
+	s, _ := r.Register(Registration{ID: "agent-1", Capacity: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan error, 1)
+	go func() {
+		_, err := r.Dispatch(ctx, Assignment{ID: "a1"})
+		results <- err
+	}()
+
+	<-s.Commands()
+	cancel()
+	if err := <-results; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	cmd := <-s.Commands()
+	if cmd.Cancel == nil || cmd.Cancel.AssignmentID != "a1" {
+		t.Fatalf("expected cancellation of a1, got %+v", cmd)
+	}
+	if info := r.Agents()[0]; info.ActiveTasks != 0 {
+		t.Fatalf("expected capacity to be released, got %d active", info.ActiveTasks)
+	}
+}
+
+func TestRegistry_SweepEvictsSilentAgents(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry(WithHeartbeatTimeout(time.Minute))
+	r.now = func() time.Time { return now }
+
+	silent, _ := r.Register(Registration{ID: "silent", Capacity: 1})
+	alive, _ := r.Register(Registration{ID: "alive", Types: []string{"other"}, Capacity: 1})
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := r.Dispatch(context.Background(), Assignment{ID: "a1", AgentType: "shell"})
+		results <- err
+	}()
+	<-silent.Commands()
+
+	now = now.Add(45 * time.Second)
+	alive.Heartbeat()
+	now = now.Add(30 * time.Second)
+	r.Sweep()
+
+	if err := <-results; !errors.Is(err, ErrHeartbeatTimeout) {
+		t.Fatalf("expected ErrHeartbeatTimeout, got %v", err)
+	}
+	select {
+	case <-silent.Done():
+	default:
+		t.Fatal("expected silent agent to be unregistered")
+	}
+	if agents := r.Agents(); len(agents) != 1 || agents[0].ID != "alive" {
+		t.Fatalf("expected only alive agent to remain, got %+v", agents)
+	}
+}
+
+func TestRegistry_Close(t *testing.T) {
+	r := NewRegistry()
+	s, _ := r.Register(Registration{ID: "agent-1", Capacity: 1})
+
+	r.Close()
+
+	if !errors.Is(s.Err(), ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", s.Err())
+	}
+	if _, err := r.Dispatch(context.Background(), Assignment{}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from Dispatch, got %v", err)
+	}
+	if _, err := r.Register(Registration{ID: "agent-2", Capacity: 1}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from Register, got %v", err)
+	}
+}
+
+func TestRegistry_TaskFuncRunsEngineTasks(t *testing.T) {
+	r := NewRegistry()
+	eng, err := engine.New(&config.Config{
+		Orchestration: config.OrchestrationConfig{MaxAgents: 10},
+	}, nil, memory.NewMemoryStorage(), engine.WithTaskExecutor("script", r))
+	if err != nil {
+		t.Fatalf("engine.New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	s, _ := r.Register(Registration{ID: "worker", Types: []string{"script"}, Capacity: 1})
+	payloads := make(chan TaskPayload, 2)
+	go func() {
+		for cmd := range s.Commands() {
+			var payload TaskPayload
+			if err := json.Unmarshal(cmd.Assign.Payload, &payload); err != nil {
+				_ = s.Complete(cmd.Assign.ID, nil, err)
+				continue
+			}
+			payloads <- payload
+			_ = s.Complete(cmd.Assign.ID, []byte(`{"ran":"`+cmd.Assign.TaskID+`"}`), nil)
+		}
+	}()
+
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "build",
+		Tasks: []models.TaskDefinition{
+			{ID: "compile", Name: "compile", Type: "script", Config: map[string]interface{}{"target": "linux"}},
+			{ID: "test", Name: "test", Type: "script", DependsOn: []string{"compile"}},
+		},
+	}, engine.SubmitWorkflowOptions{Mode: engine.SubmissionModeSync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != "completed" {
+		t.Fatalf("workflow status = %s, want completed", resp.Status)
+	}
+
+	compile, test := <-payloads, <-payloads
+	if compile.Config["target"] != "linux" || compile.Upstream != nil {
+		t.Fatalf("compile payload = %+v, want its config", compile)
+	}
+	if upstream, ok := test.Upstream["compile"].(map[string]any); !ok || upstream["ran"] != "compile" {
+		t.Fatalf("test payload = %+v, want the result of compile", test)
+	}
+	result, err := eng.GetTaskResultResponse(ctx, resp.ID, "test")
+	if err != nil {
+		t.Fatalf("GetTaskResultResponse() error = %v", err)
+	}
+	if got, ok := result.Result.(map[string]any); !ok || got["ran"] != "test" {
+		t.Fatalf("result of test = %#v, want the agent's result", result.Result)
+	}
+}
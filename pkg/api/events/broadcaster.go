@@ -3,10 +3,21 @@ package events
 import (
 	"sync"
 	"time"
+
+	"github.com/goclaw/goclaw/pkg/scrub"
+)
+
+// Event types of workflow notifications.
+const (
+	TypeWorkflowStateChanged = "workflow.state_changed"
+	TypeWorkflowSLABreached  = "workflow.sla_breached"
 )
 
 // Event is the canonical event payload broadcast to websocket subscribers.
 type Event struct {
+	// ID identifies events delivered from the outbox, so that receivers can
+	// deduplicate redeliveries. It is empty for broadcast events.
+	ID        string    `json:"id,omitempty"`
 	Type      string    `json:"type"`
 	Timestamp time.Time `json:"timestamp"`
 	Payload   any       `json:"payload"`
@@ -16,6 +27,7 @@ type Event struct {
 type Broadcaster struct {
 	mu          sync.RWMutex
 	subscribers map[chan Event]struct{}
+	scrubber    *scrub.Scrubber
 }
 
 // NewBroadcaster creates a broadcaster instance.
@@ -48,6 +60,15 @@ func (b *Broadcaster) Unsubscribe(ch chan Event) {
 	close(ch)
 }
 
+// SetScrubber scrubs the payloads of the events broadcast from now on with
+// s, so that subscribers such as websocket clients and webhooks never see
+// what it removes.
+func (b *Broadcaster) SetScrubber(s *scrub.Scrubber) {
+	b.mu.Lock()
+	b.scrubber = s
+	b.mu.Unlock()
+}
+
 // Broadcast broadcasts a generic event to all subscribers.
 func (b *Broadcaster) Broadcast(event Event) {
 	if event.Timestamp.IsZero() {
@@ -55,11 +76,13 @@ func (b *Broadcaster) Broadcast(event Event) {
 	}
 
 	b.mu.RLock()
+	scrubber := b.scrubber
 	subs := make([]chan Event, 0, len(b.subscribers))
 	for ch := range b.subscribers {
 		subs = append(subs, ch)
 	}
 	b.mu.RUnlock()
+	event.Payload = scrubber.Value(nil, event.Payload)
 
 	for _, ch := range subs {
 		select {
@@ -74,24 +97,56 @@ func (b *Broadcaster) Broadcast(event Event) {
 func (b *Broadcaster) BroadcastWorkflowStateChanged(
 	workflowID, name, oldState, newState string,
 	updatedAt time.Time,
+) {
+	b.BroadcastWorkflowStateChangedFor("", workflowID, name, oldState, newState, updatedAt)
+}
+
+// BroadcastWorkflowStateChangedFor emits a workflow state change event of a
+// run submitted by the request with the given ID.
+func (b *Broadcaster) BroadcastWorkflowStateChangedFor(
+	requestID, workflowID, name, oldState, newState string,
+	updatedAt time.Time,
 ) {
 	b.Broadcast(Event{
-		Type: "workflow.state_changed",
-		Payload: map[string]any{
-			"workflow_id": workflowID,
-			"name":        name,
-			"old_state":   oldState,
-			"new_state":   newState,
-			"updated_at":  updatedAt.UTC().Format(time.RFC3339Nano),
-		},
+		Type:    TypeWorkflowStateChanged,
+		Payload: WorkflowStateChangedPayload(requestID, workflowID, name, oldState, newState, updatedAt),
 	})
 }
 
+// WorkflowStateChangedPayload returns the payload of a workflow state
+// change event.
+func WorkflowStateChangedPayload(
+	requestID, workflowID, name, oldState, newState string,
+	updatedAt time.Time,
+) map[string]any {
+	payload := map[string]any{
+		"workflow_id": workflowID,
+		"name":        name,
+		"old_state":   oldState,
+		"new_state":   newState,
+		"updated_at":  updatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if requestID != "" {
+		payload["request_id"] = requestID
+	}
+	return payload
+}
+
 // BroadcastTaskStateChanged emits a task state change event.
 func (b *Broadcaster) BroadcastTaskStateChanged(
 	workflowID, taskID, taskName, oldState, newState, errorMessage string,
 	result any,
 	updatedAt time.Time,
+) {
+	b.BroadcastTaskStateChangedFor("", workflowID, taskID, taskName, oldState, newState, errorMessage, result, updatedAt)
+}
+
+// BroadcastTaskStateChangedFor emits a task state change event of a run
+// submitted by the request with the given ID.
+func (b *Broadcaster) BroadcastTaskStateChangedFor(
+	requestID, workflowID, taskID, taskName, oldState, newState, errorMessage string,
+	result any,
+	updatedAt time.Time,
 ) {
 	payload := map[string]any{
 		"workflow_id": workflowID,
@@ -101,6 +156,9 @@ func (b *Broadcaster) BroadcastTaskStateChanged(
 		"new_state":   newState,
 		"updated_at":  updatedAt.UTC().Format(time.RFC3339Nano),
 	}
+	if requestID != "" {
+		payload["request_id"] = requestID
+	}
 	if errorMessage != "" {
 		payload["error"] = errorMessage
 	}
@@ -114,6 +172,122 @@ func (b *Broadcaster) BroadcastTaskStateChanged(
 	})
 }
 
+// BroadcastTaskDeadlineMissed emits an event for a task that finished after
+// its deadline.
+func (b *Broadcaster) BroadcastTaskDeadlineMissed(
+	workflowID, taskID, taskName string,
+	deadline, finishedAt time.Time,
+) {
+	b.Broadcast(Event{
+		Type: "task.deadline_missed",
+		Payload: map[string]any{
+			"workflow_id": workflowID,
+			"task_id":     taskID,
+			"task_name":   taskName,
+			"deadline":    deadline.UTC().Format(time.RFC3339Nano),
+			"finished_at": finishedAt.UTC().Format(time.RFC3339Nano),
+			"late_ms":     finishedAt.Sub(deadline).Milliseconds(),
+		},
+	})
+}
+
+// BroadcastWorkflowSLABreached emits an event for a workflow run unfinished
+// at its SLA deadline. status is the run's status when the breach was
+// detected: a terminal status means it finished late.
+func (b *Broadcaster) BroadcastWorkflowSLABreached(
+	workflowID, name, status string,
+	metadata map[string]string,
+	deadline, breachedAt time.Time,
+) {
+	b.Broadcast(Event{
+		Type:    TypeWorkflowSLABreached,
+		Payload: WorkflowSLABreachedPayload(workflowID, name, status, metadata, deadline, breachedAt),
+	})
+}
+
+// WorkflowSLABreachedPayload returns the payload of an SLA breach event.
+func WorkflowSLABreachedPayload(
+	workflowID, name, status string,
+	metadata map[string]string,
+	deadline, breachedAt time.Time,
+) map[string]any {
+	payload := map[string]any{
+		"workflow_id": workflowID,
+		"name":        name,
+		"status":      status,
+		"deadline":    deadline.UTC().Format(time.RFC3339Nano),
+		"breached_at": breachedAt.UTC().Format(time.RFC3339Nano),
+		"late_ms":     breachedAt.Sub(deadline).Milliseconds(),
+	}
+	if len(metadata) > 0 {
+		payload["metadata"] = metadata
+	}
+	return payload
+}
+
+// BroadcastTaskStalled emits an event for a running task that went without a
+// heartbeat for longer than its heartbeat timeout.
+func (b *Broadcaster) BroadcastTaskStalled(
+	workflowID, taskID, taskName string,
+	heartbeatTimeout time.Duration,
+	stalls int,
+) {
+	b.Broadcast(Event{
+		Type: "task.stalled",
+		Payload: map[string]any{
+			"workflow_id":          workflowID,
+			"task_id":              taskID,
+			"task_name":            taskName,
+			"heartbeat_timeout_ms": heartbeatTimeout.Milliseconds(),
+			"stalls":               stalls,
+			"stalled_at":           time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	})
+}
+
+// BroadcastTaskOutput emits a chunk of the output a running task streams,
+// such as LLM tokens. seq numbers the chunks of an attempt from 1.
+func (b *Broadcaster) BroadcastTaskOutput(workflowID, taskID string, seq int64, chunk string) {
+	b.Broadcast(Event{
+		Type: "task.output",
+		Payload: map[string]any{
+			"workflow_id": workflowID,
+			"task_id":     taskID,
+			"seq":         seq,
+			"chunk":       chunk,
+		},
+	})
+}
+
+// BroadcastDurationAnomaly emits an event for a workflow run, or a task of it
+// when taskID is set, that took unusually long compared with its recent runs.
+func (b *Broadcaster) BroadcastDurationAnomaly(
+	workflowID, workflowName, taskID string,
+	duration, mean time.Duration,
+	zScore, percentile float64,
+	reason string,
+) {
+	payload := map[string]any{
+		"workflow_id": workflowID,
+		"name":        workflowName,
+		"duration_ms": duration.Milliseconds(),
+		"mean_ms":     mean.Milliseconds(),
+		"z_score":     zScore,
+		"percentile":  percentile,
+		"reason":      reason,
+		"detected_at": time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	eventType := "workflow.duration_anomaly"
+	if taskID != "" {
+		payload["task_id"] = taskID
+		eventType = "task.duration_anomaly"
+	}
+	b.Broadcast(Event{
+		Type:    eventType,
+		Payload: payload,
+	})
+}
+
 // Close closes all subscriber channels.
 func (b *Broadcaster) Close() {
 	b.mu.Lock()
@@ -3,6 +3,8 @@ package events
 import (
 	"testing"
 	"time"
+
+	"github.com/goclaw/goclaw/pkg/scrub"
 )
 
 func TestBroadcaster_SubscribeBroadcastUnsubscribe(t *testing.T) {
@@ -45,3 +47,154 @@ func TestBroadcaster_WorkflowAndTaskHelpers(t *testing.T) {
 		}
 	}
 }
+
+func TestBroadcaster_RequestID(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(3)
+
+	b.BroadcastWorkflowStateChangedFor("req-1", "wf-1", "demo", "pending", "running", time.Now().UTC())
+	b.BroadcastTaskStateChangedFor("req-1", "wf-1", "task-1", "Task 1", "pending", "running", "", nil, time.Now().UTC())
+	b.BroadcastWorkflowStateChanged("wf-2", "demo", "pending", "running", time.Now().UTC())
+
+	for i, want := range []any{"req-1", "req-1", nil} {
+		select {
+		case event := <-ch:
+			payload := event.Payload.(map[string]any)
+			if payload["request_id"] != want {
+				t.Fatalf("event %d request_id = %v, want %v", i, payload["request_id"], want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for event %d", i)
+		}
+	}
+}
+
+func TestBroadcaster_TaskDeadlineMissed(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(1)
+
+	deadline := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	b.BroadcastTaskDeadlineMissed("wf-1", "task-1", "Task 1", deadline, deadline.Add(1500*time.Millisecond))
+
+	select {
+	case event := <-ch:
+		if event.Type != "task.deadline_missed" {
+			t.Fatalf("type = %q, want task.deadline_missed", event.Type)
+		}
+		payload := event.Payload.(map[string]any)
+		if payload["late_ms"] != int64(1500) {
+			t.Fatalf("late_ms = %v, want 1500", payload["late_ms"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for deadline missed event")
+	}
+}
+
+func TestBroadcaster_WorkflowSLABreached(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(1)
+
+	deadline := time.Date(2026, 1, 2, 6, 0, 0, 0, time.UTC)
+	b.BroadcastWorkflowSLABreached("wf-1", "nightly", "running", map[string]string{"goclaw.io/schedule": "nightly"}, deadline, deadline.Add(2*time.Second))
+
+	select {
+	case event := <-ch:
+		if event.Type != "workflow.sla_breached" {
+			t.Fatalf("type = %q, want workflow.sla_breached", event.Type)
+		}
+		payload := event.Payload.(map[string]any)
+		if payload["late_ms"] != int64(2000) || payload["status"] != "running" || payload["metadata"] == nil {
+			t.Fatalf("payload = %v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for sla breached event")
+	}
+}
+
+func TestBroadcaster_DurationAnomaly(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(2)
+
+	b.BroadcastDurationAnomaly("wf-1", "etl", "", 9*time.Second, 3*time.Second, 4.2, 1, "z_score")
+	b.BroadcastDurationAnomaly("wf-1", "etl", "load", 5*time.Second, time.Second, 0, 0.99, "percentile")
+
+	for _, want := range []string{"workflow.duration_anomaly", "task.duration_anomaly"} {
+		select {
+		case event := <-ch:
+			if event.Type != want {
+				t.Fatalf("type = %q, want %s", event.Type, want)
+			}
+			payload := event.Payload.(map[string]any)
+			if payload["workflow_id"] != "wf-1" || payload["reason"] == nil {
+				t.Fatalf("payload = %v", payload)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for duration anomaly event")
+		}
+	}
+}
+
+func TestBroadcaster_TaskStalled(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(1)
+
+	b.BroadcastTaskStalled("wf-1", "task-1", "Task 1", 30*time.Second, 2)
+
+	select {
+	case event := <-ch:
+		if event.Type != "task.stalled" {
+			t.Fatalf("type = %q, want task.stalled", event.Type)
+		}
+		payload := event.Payload.(map[string]any)
+		if payload["heartbeat_timeout_ms"] != int64(30000) || payload["stalls"] != 2 {
+			t.Fatalf("payload = %v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for task stalled event")
+	}
+}
+
+func TestBroadcaster_TaskOutput(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe(1)
+
+	b.BroadcastTaskOutput("wf-1", "task-1", 3, "Hello")
+
+	select {
+	case event := <-ch:
+		if event.Type != "task.output" {
+			t.Fatalf("type = %q, want task.output", event.Type)
+		}
+		payload := event.Payload.(map[string]any)
+		if payload["task_id"] != "task-1" || payload["seq"] != int64(3) || payload["chunk"] != "Hello" {
+			t.Fatalf("payload = %v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for task output event")
+	}
+}
+
+func TestBroadcaster_ScrubsPayloads(t *testing.T) {
+	scrubber, err := scrub.New([]scrub.Rule{{Fields: []string{"result.email"}}, {Pattern: `\d{3}-\d{2}-\d{4}`}})
+	if err != nil {
+		t.Fatalf("scrub.New failed: %v", err)
+	}
+	b := NewBroadcaster()
+	b.SetScrubber(scrubber)
+	ch := b.Subscribe(1)
+
+	result := map[string]any{"email": "jane@example.com", "id": 7}
+	b.BroadcastTaskStateChanged("wf-1", "task-1", "Task 1", "running", "failed", "invalid ssn 123-45-6789", result, time.Now().UTC())
+
+	event := <-ch
+	payload := event.Payload.(map[string]any)
+	if payload["error"] != "invalid ssn "+scrub.DefaultReplacement {
+		t.Fatalf("error = %v", payload["error"])
+	}
+	if got := payload["result"].(map[string]any); got["email"] != scrub.DefaultReplacement || got["id"] != 7 {
+		t.Fatalf("result = %v", got)
+	}
+	if result["email"] != "jane@example.com" {
+		t.Fatal("broadcast changed the caller's result")
+	}
+}
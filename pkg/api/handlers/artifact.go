@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// HeaderArtifactSHA256 carries the hex SHA-256 checksum of a downloaded
+// artifact.
+const HeaderArtifactSHA256 = "X-Artifact-Sha256"
+
+// ArtifactHandler handles task artifact endpoints.
+type ArtifactHandler struct {
+	engine *engine.Engine
+	store  *artifact.Store
+	logger logger.Logger
+}
+
+// NewArtifactHandler creates a new artifact handler.
+func NewArtifactHandler(eng *engine.Engine, store *artifact.Store, log logger.Logger) *ArtifactHandler {
+	return &ArtifactHandler{
+		engine: eng,
+		store:  store,
+		logger: log,
+	}
+}
+
+// ListArtifacts handles GET /api/v1/workflows/{id}/artifacts
+func (h *ArtifactHandler) ListArtifacts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if _, err := h.engine.GetWorkflowSummaryResponse(ctx, workflowID); err != nil {
+		writeError(w, ctx, err, "Failed to get workflow")
+		return
+	}
+	artifacts, err := h.store.List(ctx, workflowID)
+	if err != nil {
+		h.logger.Error("Failed to list artifacts", "workflow_id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to list artifacts")
+		return
+	}
+
+	resp := models.ArtifactListResponse{Artifacts: make([]models.Artifact, 0, len(artifacts))}
+	for _, a := range artifacts {
+		resp.Artifacts = append(resp.Artifacts, artifactToModel(a))
+	}
+	resp.Count = len(resp.Artifacts)
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// UploadArtifact handles PUT /api/v1/workflows/{id}/tasks/{tid}/artifacts/{name}
+func (h *ArtifactHandler) UploadArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	taskID := chi.URLParam(r, "tid")
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid artifact name", getRequestID(ctx))
+		return
+	}
+
+	status, err := h.engine.GetWorkflowStatusResponse(ctx, workflowID)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to get workflow")
+		return
+	}
+	if !hasTask(status, taskID) {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Task not found", getRequestID(ctx))
+		return
+	}
+
+	a, err := h.store.Put(ctx, workflowID, taskID, name, r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		h.logger.Error("Failed to store artifact", "workflow_id", workflowID, "task_id", taskID, "name", name, "error", err)
+		writeError(w, ctx, err, "Failed to store artifact")
+		return
+	}
+	response.JSON(w, http.StatusCreated, artifactToModel(a))
+}
+
+// DownloadArtifact handles GET /api/v1/workflows/{id}/tasks/{tid}/artifacts/{name}
+func (h *ArtifactHandler) DownloadArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	taskID := chi.URLParam(r, "tid")
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid artifact name", getRequestID(ctx))
+		return
+	}
+
+	a, rc, err := h.store.Open(ctx, workflowID, taskID, name)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to open artifact")
+		return
+	}
+	defer rc.Close()
+
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set(HeaderArtifactSHA256, a.SHA256)
+	w.Header().Set("ETag", `"`+a.SHA256+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, rc); err != nil {
+		// The status is already sent; a corrupt artifact can only be logged.
+		if errors.Is(err, artifact.ErrChecksumMismatch) {
+			h.logger.Error("Artifact failed checksum verification", "workflow_id", workflowID, "task_id", taskID, "name", name)
+			return
+		}
+		h.logger.Warn("Artifact download interrupted", "workflow_id", workflowID, "task_id", taskID, "name", name, "error", err)
+	}
+}
+
+func hasTask(status *models.WorkflowStatusResponse, taskID string) bool {
+	for _, task := range status.Tasks {
+		if task.ID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+func artifactToModel(a *artifact.Artifact) models.Artifact {
+	return models.Artifact{
+		WorkflowID:  a.WorkflowID,
+		TaskID:      a.TaskID,
+		Name:        a.Name,
+		Size:        a.Size,
+		SHA256:      a.SHA256,
+		ContentType: a.ContentType,
+		CreatedAt:   a.CreatedAt,
+		ExpiresAt:   a.ExpiresAt,
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+func TestArtifactHandler_UploadListDownload(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	backend, err := artifact.NewLocalBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBackend() error = %v", err)
+	}
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewArtifactHandler(eng, artifact.NewStore(backend), log)
+
+	wf, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "release",
+		Tasks: []models.TaskDefinition{{ID: "build", Name: "build", Type: "function"}},
+	}, engine.SubmitWorkflowOptions{Mode: engine.SubmissionModeSync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/workflows/{id}/artifacts", handler.ListArtifacts)
+	r.Put("/workflows/{id}/tasks/{tid}/artifacts/{name}", handler.UploadArtifact)
+	r.Get("/workflows/{id}/tasks/{tid}/artifacts/{name}", handler.DownloadArtifact)
+
+	req := httptest.NewRequest(http.MethodPut, "/workflows/"+wf.ID+"/tasks/build/artifacts/app%20v1.tar", strings.NewReader("binary"))
+	req.Header.Set("Content-Type", "application/x-tar")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/workflows/"+wf.ID+"/tasks/missing/artifacts/app.tar", strings.NewReader("binary"))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for unknown task, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/workflows/"+wf.ID+"/artifacts", nil))
+	var list models.ArtifactListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if list.Count != 1 || list.Artifacts[0].Name != "app v1.tar" || list.Artifacts[0].Size != 6 {
+		t.Fatalf("unexpected artifact list %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/workflows/"+wf.ID+"/tasks/build/artifacts/app%20v1.tar", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "binary" {
+		t.Fatalf("unexpected download %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "application/x-tar" || w.Header().Get(HeaderArtifactSHA256) != list.Artifacts[0].SHA256 {
+		t.Fatalf("unexpected download headers %v", w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/workflows/unknown/artifacts", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for unknown workflow, got %d", w.Code)
+	}
+}
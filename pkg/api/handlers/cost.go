@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// CostHandler handles the cost report endpoint.
+type CostHandler struct {
+	engine *engine.Engine
+	logger logger.Logger
+}
+
+// NewCostHandler creates a new cost handler.
+func NewCostHandler(eng *engine.Engine, log logger.Logger) *CostHandler {
+	return &CostHandler{
+		engine: eng,
+		logger: log,
+	}
+}
+
+// GetCostReport handles GET /api/v1/costs
+func (h *CostHandler) GetCostReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var bounds [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, name+" must be an RFC 3339 timestamp", getRequestID(ctx))
+			return
+		}
+		bounds[i] = v.UTC()
+	}
+
+	report, err := h.engine.CostReport(ctx, query.Get("group_by"), bounds[0], bounds[1])
+	if err != nil {
+		writeError(w, ctx, err, "Failed to build cost report")
+		return
+	}
+	response.JSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+func TestCostHandler_GetCostReport(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewCostHandler(eng, log)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/costs?from=yesterday", nil)
+	w := httptest.NewRecorder()
+	handler.GetCostReport(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an invalid from, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The test engine does not account costs.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/costs?group_by=workflow", nil)
+	w = httptest.NewRecorder()
+	handler.GetCostReport(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 without cost accounting, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -2,32 +2,48 @@
 package handlers
 
 import (
+	"context"
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/api/response"
 	"github.com/goclaw/goclaw/pkg/engine"
 )
 
 // HealthHandler handles health check endpoints.
 type HealthHandler struct {
-	engine *engine.Engine
+	engine       *engine.Engine
+	drainDelay   time.Duration
+	drainTimeout time.Duration
+}
+
+// HealthOption configures a HealthHandler.
+type HealthOption func(*HealthHandler)
+
+// WithDrainTiming sets how long /drain keeps the node out of rotation
+// before answering, so endpoints controllers see it unready, and how long
+// it waits for running workflows.
+func WithDrainTiming(delay, timeout time.Duration) HealthOption {
+	return func(h *HealthHandler) {
+		h.drainDelay = delay
+		h.drainTimeout = timeout
+	}
 }
 
 // NewHealthHandler creates a new health handler.
-func NewHealthHandler(eng *engine.Engine) *HealthHandler {
-	return &HealthHandler{
+func NewHealthHandler(eng *engine.Engine, opts ...HealthOption) *HealthHandler {
+	h := &HealthHandler{
 		engine: eng,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Health handles the /health endpoint (liveness probe).
-// @Summary Health check
-// @Description Check if the service is alive and running
-// @Tags health
-// @Produce json
-// @Success 200 {object} map[string]string "Service is healthy"
-// @Failure 503 {object} map[string]string "Service is unhealthy"
-// @Router /health [get]
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	if h.engine.IsHealthy() {
 		response.JSON(w, http.StatusOK, map[string]string{
@@ -40,33 +56,59 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Ready handles the /ready endpoint (readiness probe).
-// @Summary Readiness check
-// @Description Check if the service is ready to accept requests
-// @Tags health
-// @Produce json
-// @Success 200 {object} map[string]bool "Service is ready"
-// @Failure 503 {object} map[string]bool "Service is not ready"
-// @Router /ready [get]
+// Ready handles the /ready endpoint (readiness probe). It reports the
+// startup phases, so a node stuck in one can be told apart from a slow one.
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	if h.engine.IsReady() {
-		response.JSON(w, http.StatusOK, map[string]bool{
-			"ready": true,
-		})
-	} else {
-		response.JSON(w, http.StatusServiceUnavailable, map[string]bool{
-			"ready": false,
-		})
+	readiness := h.engine.Readiness()
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	response.JSON(w, status, readiness)
+}
+
+// Drain handles the /drain endpoint (pre-stop hook). It marks the node not
+// ready, waits for running workflows up to the drain timeout and at least
+// the drain delay, then answers. Health routes are not authenticated, so
+// only loopback callers may drain a node.
+func (h *HealthHandler) Drain(w http.ResponseWriter, r *http.Request) {
+	if !isLoopback(r.RemoteAddr) {
+		response.Error(w, http.StatusForbidden, response.ErrCodeForbidden, "drain is only accepted from loopback addresses", getRequestID(r.Context()))
+		return
+	}
+
+	start := time.Now()
+	ctx := r.Context()
+	if h.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.drainTimeout)
+		defer cancel()
+	}
+	running := h.engine.Drain(ctx)
+
+	if wait := h.drainDelay - time.Since(start); wait > 0 {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(wait):
+		}
+	}
+
+	response.JSON(w, http.StatusOK, models.DrainResponse{
+		Drained:          running == 0,
+		RunningWorkflows: running,
+	})
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
 // Status handles the /status endpoint (detailed status).
-// @Summary Detailed status
-// @Description Get detailed status information about the service and engine
-// @Tags health
-// @Produce json
-// @Success 200 {object} engine.EngineStatus "Detailed status information"
-// @Router /status [get]
 func (h *HealthHandler) Status(w http.ResponseWriter, r *http.Request) {
 	status := h.engine.GetStatus()
 	response.JSON(w, http.StatusOK, status)
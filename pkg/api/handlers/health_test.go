@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/goclaw/goclaw/config"
 	"github.com/goclaw/goclaw/pkg/engine"
@@ -84,4 +86,56 @@ func TestHealthHandler_Ready(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("Ready() status = %v, want %v", w.Code, http.StatusOK)
 	}
+	var readiness engine.Readiness
+	if err := json.Unmarshal(w.Body.Bytes(), &readiness); err != nil {
+		t.Fatalf("failed to decode readiness: %v", err)
+	}
+	if !readiness.Ready || len(readiness.Phases) != 3 {
+		t.Errorf("Ready() body = %+v, want ready with 3 phases", readiness)
+	}
+}
+
+func TestHealthHandler_Drain(t *testing.T) {
+	cfg := &config.Config{
+		App: config.AppConfig{
+			Name:        "test",
+			Environment: "development",
+		},
+		Orchestration: config.OrchestrationConfig{
+			MaxAgents: 10,
+		},
+	}
+
+	eng, _ := engine.New(cfg, nil, memory.NewMemoryStorage())
+	ctx := context.Background()
+	eng.Start(ctx)
+	defer eng.Stop(ctx)
+
+	handler := NewHealthHandler(eng, WithDrainTiming(20*time.Millisecond, time.Second))
+
+	// httptest requests come from 192.0.2.1, which may not drain.
+	req := httptest.NewRequest(http.MethodPost, "/drain", nil)
+	w := httptest.NewRecorder()
+	handler.Drain(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Drain() from remote status = %v, want %v", w.Code, http.StatusForbidden)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/drain", nil)
+	req.RemoteAddr = "127.0.0.1:41000"
+	w = httptest.NewRecorder()
+	start := time.Now()
+	handler.Drain(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Drain() status = %v, want %v", w.Code, http.StatusOK)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Drain() answered after %v, want at least the drain delay", elapsed)
+	}
+
+	w = httptest.NewRecorder()
+	handler.Ready(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Ready() after drain status = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
 }
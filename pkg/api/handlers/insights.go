@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/insights"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// InsightsHandler handles the duration insights endpoint.
+type InsightsHandler struct {
+	detector *insights.Detector
+	logger   logger.Logger
+}
+
+// NewInsightsHandler creates a new insights handler.
+func NewInsightsHandler(detector *insights.Detector, log logger.Logger) *InsightsHandler {
+	return &InsightsHandler{
+		detector: detector,
+		logger:   log,
+	}
+}
+
+// GetInsights handles GET /api/v1/insights
+func (h *InsightsHandler) GetInsights(w http.ResponseWriter, r *http.Request) {
+	workflow := r.URL.Query().Get("workflow")
+
+	stats := h.detector.Stats(workflow)
+	durations := make([]models.DurationStats, 0, len(stats))
+	for _, s := range stats {
+		durations = append(durations, models.DurationStats{
+			Workflow:  s.Workflow,
+			Task:      s.Task,
+			Samples:   s.Samples,
+			MeanMS:    durationMS(s.Mean),
+			StdDevMS:  durationMS(s.StdDev),
+			P50MS:     durationMS(s.P50),
+			P95MS:     durationMS(s.P95),
+			P99MS:     durationMS(s.P99),
+			Anomalies: s.Anomalies,
+		})
+	}
+
+	found := h.detector.Anomalies(workflow)
+	anomalies := make([]models.DurationAnomaly, 0, len(found))
+	for _, a := range found {
+		anomalies = append(anomalies, models.DurationAnomaly{
+			WorkflowID: a.WorkflowID,
+			Workflow:   a.Workflow,
+			Task:       a.Task,
+			DurationMS: durationMS(a.Duration),
+			MeanMS:     durationMS(a.Mean),
+			StdDevMS:   durationMS(a.StdDev),
+			ZScore:     a.ZScore,
+			Percentile: a.Percentile,
+			Reason:     a.Reason,
+			DetectedAt: a.DetectedAt,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, models.InsightsResponse{
+		Durations:   durations,
+		Anomalies:   anomalies,
+		CollectedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/insights"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+func TestInsightsHandler_GetInsights(t *testing.T) {
+	detector := insights.New(config.InsightsConfig{ZScore: 3, MinSamples: 5})
+	now := time.Now().UTC()
+	for i := 0; i < 10; i++ {
+		detector.Observe("wf", "etl", "", time.Second+time.Duration(i)*time.Millisecond, now)
+		detector.Observe("wf", "report", "", time.Second, now)
+	}
+	detector.Observe("wf-slow", "etl", "", time.Minute, now)
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewInsightsHandler(detector, log)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/insights?workflow=etl", nil)
+	w := httptest.NewRecorder()
+	handler.GetInsights(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.InsightsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Durations) != 1 || resp.Durations[0].Workflow != "etl" || resp.Durations[0].Samples != 11 {
+		t.Fatalf("durations = %+v", resp.Durations)
+	}
+	if len(resp.Anomalies) != 1 || resp.Anomalies[0].WorkflowID != "wf-slow" || resp.Anomalies[0].Reason != insights.ReasonZScore {
+		t.Fatalf("anomalies = %+v", resp.Anomalies)
+	}
+}
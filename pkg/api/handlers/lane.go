@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// LaneHandler handles lane statistics endpoints.
+type LaneHandler struct {
+	engine *engine.Engine
+	logger logger.Logger
+}
+
+// NewLaneHandler creates a new lane handler.
+func NewLaneHandler(eng *engine.Engine, log logger.Logger) *LaneHandler {
+	return &LaneHandler{
+		engine: eng,
+		logger: log,
+	}
+}
+
+// ListLanes handles GET /api/v1/lanes
+func (h *LaneHandler) ListLanes(w http.ResponseWriter, r *http.Request) {
+	stats := h.engine.LaneStats()
+
+	lanes := make([]models.LaneStats, 0, len(stats))
+	for _, s := range stats {
+		lanes = append(lanes, laneStatsToModel(s))
+	}
+
+	response.JSON(w, http.StatusOK, models.LaneListResponse{
+		Lanes:       lanes,
+		Count:       len(lanes),
+		CollectedAt: time.Now().UTC(),
+	})
+}
+
+// GetLane handles GET /api/v1/lanes/{name}
+func (h *LaneHandler) GetLane(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Lane name is required", getRequestID(ctx))
+		return
+	}
+
+	stats, err := h.engine.LaneStatsByName(name)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to get lane statistics")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, laneStatsToModel(stats))
+}
+
+func laneStatsToModel(s lane.Stats) models.LaneStats {
+	out := models.LaneStats{
+		Name:            s.Name,
+		Pending:         s.Pending,
+		Running:         s.Running,
+		Completed:       s.Completed,
+		Failed:          s.Failed,
+		Dropped:         s.Dropped,
+		Accepted:        s.Accepted,
+		Rejected:        s.Rejected,
+		Redirected:      s.Redirected,
+		Capacity:        s.Capacity,
+		MaxConcurrency:  s.MaxConcurrency,
+		Utilization:     s.Utilization(),
+		WaitTimeMS:      durationMS(s.WaitTime),
+		WaitP50MS:       durationMS(s.WaitP50),
+		WaitP95MS:       durationMS(s.WaitP95),
+		WaitP99MS:       durationMS(s.WaitP99),
+		ProcessTimeMS:   durationMS(s.ProcessTime),
+		RateLimit:       s.RateLimit,
+		RateLimitTokens: s.RateLimitTokens,
+		Throttled:       s.Throttled,
+		DeadlineMissed:  s.DeadlineMissed,
+	}
+	if !s.Resources.IsZero() {
+		out.Resources = resourcesToModel(s.Resources)
+		out.ResourcesInUse = resourcesToModel(s.ResourcesInUse)
+	}
+	return out
+}
+
+func resourcesToModel(r lane.Resources) *models.TaskResources {
+	return &models.TaskResources{CPU: r.CPU, MemoryMB: r.MemoryMB, GPU: r.GPU}
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+func TestLaneHandler_ListLanes(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewLaneHandler(eng, log)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lanes", nil)
+	w := httptest.NewRecorder()
+	handler.ListLanes(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp models.LaneListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Count != len(resp.Lanes) || resp.Count == 0 {
+		t.Fatalf("expected at least the default lane, got %+v", resp)
+	}
+	if resp.CollectedAt.IsZero() {
+		t.Fatal("expected collected_at to be set")
+	}
+
+	var found bool
+	for _, l := range resp.Lanes {
+		if l.Name == "default" {
+			found = true
+			if l.Capacity <= 0 || l.MaxConcurrency <= 0 {
+				t.Fatalf("expected default lane limits, got %+v", l)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("default lane missing from %+v", resp.Lanes)
+	}
+}
+
+func TestLaneHandler_GetLane(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewLaneHandler(eng, log)
+
+	get := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lanes/"+name, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("name", name)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetLane(w, req)
+		return w
+	}
+
+	w := get("default")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var stats models.LaneStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if stats.Name != "default" || stats.Capacity <= 0 {
+		t.Fatalf("unexpected lane stats: %+v", stats)
+	}
+
+	if w := get("missing"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for unknown lane, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// LogLevelHandler handles the runtime log level endpoints.
+type LogLevelHandler struct {
+	levels *logger.Levels
+	logger logger.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler for levels.
+func NewLogLevelHandler(levels *logger.Levels, log logger.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		levels: levels,
+		logger: log,
+	}
+}
+
+// GetLogLevels handles GET /admin/loglevel
+func (h *LogLevelHandler) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, h.snapshot())
+}
+
+// SetLogLevel handles PUT /admin/loglevel?component=...
+// Without a component it sets the server level.
+func (h *LogLevelHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req models.LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "invalid request body", getRequestID(r.Context()))
+		return
+	}
+	level, ok := logger.LookupLevel(req.Level)
+	if !ok {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "level must be one of debug, info, warn, error", getRequestID(r.Context()))
+		return
+	}
+
+	component := r.URL.Query().Get("component")
+	if component == "" {
+		h.levels.SetLevel(level)
+	} else {
+		h.levels.SetComponent(component, level)
+	}
+	if h.logger != nil {
+		h.logger.Info("Log level changed", "component", component, "level", level.String())
+	}
+	response.JSON(w, http.StatusOK, h.snapshot())
+}
+
+// ResetLogLevel handles DELETE /admin/loglevel?component=...
+// The component follows the server level again.
+func (h *LogLevelHandler) ResetLogLevel(w http.ResponseWriter, r *http.Request) {
+	component := r.URL.Query().Get("component")
+	if component == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "component is required", getRequestID(r.Context()))
+		return
+	}
+	h.levels.ResetComponent(component)
+	if h.logger != nil {
+		h.logger.Info("Log level reset", "component", component)
+	}
+	response.JSON(w, http.StatusOK, h.snapshot())
+}
+
+func (h *LogLevelHandler) snapshot() models.LogLevelsResponse {
+	components := h.levels.Components()
+	resp := models.LogLevelsResponse{
+		Level:      h.levels.Level().String(),
+		Components: make([]models.ComponentLogLevel, 0, len(components)),
+	}
+	for _, c := range components {
+		resp.Components = append(resp.Components, models.ComponentLogLevel{
+			Component:  c.Name,
+			Level:      c.Level.String(),
+			Overridden: c.Overridden,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	log := logger.New(&logger.Config{Level: logger.InfoLevel, Format: "json", Output: "stdout"}).(*logger.SlogLogger)
+	lane := log.Component("lane")
+	handler := NewLogLevelHandler(log.Levels(), nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/loglevel?component=lane", strings.NewReader(`{"level":"verbose"}`))
+	w := httptest.NewRecorder()
+	handler.SetLogLevel(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unknown level, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/loglevel?component=lane", strings.NewReader(`{"level":"debug"}`))
+	w = httptest.NewRecorder()
+	handler.SetLogLevel(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var levels models.LogLevelsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &levels); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := models.ComponentLogLevel{Component: "lane", Level: "debug", Overridden: true}
+	if levels.Level != "info" || len(levels.Components) != 1 || levels.Components[0] != want {
+		t.Fatalf("unexpected levels %+v", levels)
+	}
+	if lane.GetLevel() != logger.DebugLevel {
+		t.Fatalf("lane logger level = %v, want debug", lane.GetLevel())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ResetLogLevel(w, httptest.NewRequest(http.MethodDelete, "/admin/loglevel", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without a component, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.ResetLogLevel(w, httptest.NewRequest(http.MethodDelete, "/admin/loglevel?component=lane", nil))
+	if w.Code != http.StatusOK || lane.GetLevel() != logger.InfoLevel {
+		t.Fatalf("expected lane to follow the info level after a reset, got %d %v", w.Code, lane.GetLevel())
+	}
+}
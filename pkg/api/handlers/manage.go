@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/manage"
+)
+
+// resourceNamePattern restricts managed resource names to URL-safe names.
+var resourceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// ManageHandler handles the declarative management endpoints. PUT replaces
+// a resource and answers 201 when it created it; every response carries the
+// resource version as ETag, and PUT and DELETE honour If-Match with a
+// version or *, and PUT also If-None-Match: * to only create.
+type ManageHandler struct {
+	engine    *engine.Engine
+	service   *manage.Service
+	logger    logger.Logger
+	validator *validator.Validate
+}
+
+// NewManageHandler creates a new management handler.
+func NewManageHandler(eng *engine.Engine, service *manage.Service, log logger.Logger) *ManageHandler {
+	return &ManageHandler{
+		engine:    eng,
+		service:   service,
+		logger:    log,
+		validator: newRequestValidator(),
+	}
+}
+
+// ListTemplates handles GET /api/v1/manage/templates
+func (h *ManageHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	list := h.service.ListTemplates()
+	resp := models.TemplateListResponse{Items: make([]models.TemplateResource, 0, len(list)), Count: len(list)}
+	for _, res := range list {
+		resp.Items = append(resp.Items, toTemplateModel(res))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetTemplate handles GET /api/v1/manage/templates/{name}
+func (h *ManageHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetTemplate(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get template")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toTemplateModel(res))
+}
+
+// PutTemplate handles PUT /api/v1/manage/templates/{name}. The workflow is
+// checked like a submission, without running it, before it is stored.
+func (h *ManageHandler) PutTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var spec models.WorkflowRequest
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	if err := h.engine.ValidateWorkflowRequest(ctx, &spec); err != nil {
+		writeError(w, ctx, err, "Failed to compile template")
+		return
+	}
+	res, created, err := h.service.PutTemplate(name, spec, cond)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to store template")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toTemplateModel(res))
+}
+
+// DeleteTemplate handles DELETE /api/v1/manage/templates/{name}
+func (h *ManageHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteTemplate(chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete template")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// DiffTemplate handles GET /api/v1/manage/templates/{name}/diff. It compares
+// the tasks of the workflow in the workflow query parameter, such as a
+// planned one awaiting approval, with those of the template.
+func (h *ManageHandler) DiffTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := r.URL.Query().Get("workflow")
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "workflow query parameter is required", getRequestID(ctx))
+		return
+	}
+	diff, err := h.service.DiffTemplate(ctx, chi.URLParam(r, "name"), workflowID)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to diff template")
+		return
+	}
+	response.JSON(w, http.StatusOK, diff)
+}
+
+// ListSchedules handles GET /api/v1/manage/schedules
+func (h *ManageHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	list, statuses := h.service.ListSchedules()
+	resp := models.ScheduleListResponse{Items: make([]models.ScheduleResource, 0, len(list)), Count: len(list)}
+	for i, res := range list {
+		resp.Items = append(resp.Items, toScheduleModel(res, statuses[i]))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetSchedule handles GET /api/v1/manage/schedules/{name}
+func (h *ManageHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetSchedule(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get schedule")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toScheduleModel(res, status))
+}
+
+// PutSchedule handles PUT /api/v1/manage/schedules/{name}
+func (h *ManageHandler) PutSchedule(w http.ResponseWriter, r *http.Request) {
+	var spec models.ScheduleSpec
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	res, status, created, err := h.service.PutSchedule(name, spec, cond)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to store schedule")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toScheduleModel(res, status))
+}
+
+// DeleteSchedule handles DELETE /api/v1/manage/schedules/{name}
+func (h *ManageHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteSchedule(chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete schedule")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// StartBackfill handles POST /api/v1/manage/schedules/{name}/backfills. The
+// runs are submitted in the background; the response lists them pending.
+func (h *ManageHandler) StartBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req models.BackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		writeValidationError(w, ctx, err)
+		return
+	}
+	bf, err := h.service.StartBackfill(chi.URLParam(r, "name"), req)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to start backfill")
+		return
+	}
+	response.JSON(w, http.StatusAccepted, bf)
+}
+
+// ListBackfills handles GET /api/v1/manage/schedules/{name}/backfills
+func (h *ManageHandler) ListBackfills(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListBackfills(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to list backfills")
+		return
+	}
+	if list == nil {
+		list = []models.Backfill{}
+	}
+	response.JSON(w, http.StatusOK, models.BackfillListResponse{Items: list, Count: len(list)})
+}
+
+// CancelBackfill handles DELETE /api/v1/manage/schedules/{name}/backfills/{id}.
+// Submitted runs keep running.
+func (h *ManageHandler) CancelBackfill(w http.ResponseWriter, r *http.Request) {
+	bf, err := h.service.CancelBackfill(chi.URLParam(r, "name"), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to cancel backfill")
+		return
+	}
+	response.JSON(w, http.StatusOK, bf)
+}
+
+// ListTriggers handles GET /api/v1/manage/triggers
+func (h *ManageHandler) ListTriggers(w http.ResponseWriter, r *http.Request) {
+	list, statuses := h.service.ListTriggers()
+	resp := models.TriggerListResponse{Items: make([]models.TriggerResource, 0, len(list)), Count: len(list)}
+	for i, res := range list {
+		resp.Items = append(resp.Items, toTriggerModel(res, statuses[i]))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetTrigger handles GET /api/v1/manage/triggers/{name}
+func (h *ManageHandler) GetTrigger(w http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetTrigger(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get trigger")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toTriggerModel(res, status))
+}
+
+// PutTrigger handles PUT /api/v1/manage/triggers/{name}
+func (h *ManageHandler) PutTrigger(w http.ResponseWriter, r *http.Request) {
+	var spec models.TriggerSpec
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	res, status, created, err := h.service.PutTrigger(name, spec, cond)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to store trigger")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toTriggerModel(res, status))
+}
+
+// DeleteTrigger handles DELETE /api/v1/manage/triggers/{name}
+func (h *ManageHandler) DeleteTrigger(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteTrigger(chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete trigger")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// ListCalendars handles GET /api/v1/manage/calendars
+func (h *ManageHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	list := h.service.ListCalendars()
+	resp := models.CalendarListResponse{Items: make([]models.CalendarResource, 0, len(list)), Count: len(list)}
+	for _, res := range list {
+		resp.Items = append(resp.Items, toCalendarModel(res))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetCalendar handles GET /api/v1/manage/calendars/{name}
+func (h *ManageHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetCalendar(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get calendar")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toCalendarModel(res))
+}
+
+// PutCalendar handles PUT /api/v1/manage/calendars/{name}
+func (h *ManageHandler) PutCalendar(w http.ResponseWriter, r *http.Request) {
+	var spec models.CalendarSpec
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	res, created, err := h.service.PutCalendar(name, spec, cond)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to store calendar")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toCalendarModel(res))
+}
+
+// DeleteCalendar handles DELETE /api/v1/manage/calendars/{name}
+func (h *ManageHandler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteCalendar(chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete calendar")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// ListWebhooks handles GET /api/v1/manage/webhooks
+func (h *ManageHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	list := h.service.ListWebhooks()
+	resp := models.WebhookListResponse{Items: make([]models.WebhookResource, 0, len(list)), Count: len(list)}
+	for _, res := range list {
+		resp.Items = append(resp.Items, toWebhookModel(res))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetWebhook handles GET /api/v1/manage/webhooks/{name}
+func (h *ManageHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetWebhook(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get webhook")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toWebhookModel(res))
+}
+
+// PutWebhook handles PUT /api/v1/manage/webhooks/{name}
+func (h *ManageHandler) PutWebhook(w http.ResponseWriter, r *http.Request) {
+	var spec models.WebhookSpec
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	res, created, err := h.service.PutWebhook(name, spec, cond)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to store webhook")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toWebhookModel(res))
+}
+
+// DeleteWebhook handles DELETE /api/v1/manage/webhooks/{name}
+func (h *ManageHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteWebhook(chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete webhook")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// ListLanes handles GET /api/v1/manage/lanes
+func (h *ManageHandler) ListLanes(w http.ResponseWriter, r *http.Request) {
+	list := h.service.ListLanes()
+	resp := models.LaneResourceListResponse{Items: make([]models.LaneResource, 0, len(list)), Count: len(list)}
+	for _, res := range list {
+		resp.Items = append(resp.Items, toLaneResourceModel(res))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// GetLane handles GET /api/v1/manage/lanes/{name}
+func (h *ManageHandler) GetLane(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetLane(chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to get lane")
+		return
+	}
+	writeResource(w, http.StatusOK, res.Version, toLaneResourceModel(res))
+}
+
+// PutLane handles PUT /api/v1/manage/lanes/{name}
+func (h *ManageHandler) PutLane(w http.ResponseWriter, r *http.Request) {
+	var spec models.LaneSpec
+	name, cond, ok := h.decodePut(w, r, &spec)
+	if !ok {
+		return
+	}
+	res, created, err := h.service.PutLane(name, spec, cond)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to apply lane")
+		return
+	}
+	writeResource(w, putStatus(created), res.Version, toLaneResourceModel(res))
+}
+
+// DeleteLane handles DELETE /api/v1/manage/lanes/{name}
+func (h *ManageHandler) DeleteLane(w http.ResponseWriter, r *http.Request) {
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteLane(r.Context(), chi.URLParam(r, "name"), cond); err != nil {
+		writeError(w, r.Context(), err, "Failed to delete lane")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.ResourceDeleteResponse{Deleted: true})
+}
+
+// decodePut reads the name, preconditions and validated spec of a PUT.
+func (h *ManageHandler) decodePut(w http.ResponseWriter, r *http.Request, spec interface{}) (string, manage.Precondition, bool) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+	if !resourceNamePattern.MatchString(name) {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest,
+			"Name must be 1-100 letters, digits, '.', '_' or '-' and start with a letter or digit", getRequestID(ctx))
+		return "", manage.Precondition{}, false
+	}
+	cond, ok := parsePrecondition(w, r)
+	if !ok {
+		return "", cond, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(spec); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return "", cond, false
+	}
+	if err := validateRequest(h.validator, spec); err != nil {
+		writeValidationError(w, ctx, err)
+		return "", cond, false
+	}
+	return name, cond, true
+}
+
+// parsePrecondition reads If-Match and If-None-Match. If-Match takes a
+// version ETag, as sent by the server, or *; If-None-Match only *.
+func parsePrecondition(w http.ResponseWriter, r *http.Request) (manage.Precondition, bool) {
+	var cond manage.Precondition
+	if match := strings.TrimSpace(r.Header.Get("If-Match")); match == "*" {
+		cond.Exists = true
+	} else if match != "" {
+		version, err := strconv.ParseInt(strings.Trim(match, `"`), 10, 64)
+		if err != nil || version <= 0 {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "If-Match must be a resource version or *", getRequestID(r.Context()))
+			return cond, false
+		}
+		cond.Version = version
+	}
+	if noneMatch := strings.TrimSpace(r.Header.Get("If-None-Match")); noneMatch == "*" {
+		cond.Absent = true
+	} else if noneMatch != "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "If-None-Match must be *", getRequestID(r.Context()))
+		return cond, false
+	}
+	return cond, true
+}
+
+func writeResource(w http.ResponseWriter, status int, version int64, body interface{}) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+	response.JSON(w, status, body)
+}
+
+func putStatus(created bool) int {
+	if created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func resourceMeta[T any](res manage.Resource[T]) models.ResourceMeta {
+	return models.ResourceMeta{
+		Name:      res.Name,
+		Version:   res.Version,
+		CreatedAt: res.CreatedAt,
+		UpdatedAt: res.UpdatedAt,
+	}
+}
+
+func toTemplateModel(res manage.Resource[models.WorkflowRequest]) models.TemplateResource {
+	return models.TemplateResource{ResourceMeta: resourceMeta(res), Spec: res.Spec}
+}
+
+func toScheduleModel(res manage.Resource[models.ScheduleSpec], status models.ScheduleStatus) models.ScheduleResource {
+	return models.ScheduleResource{ResourceMeta: resourceMeta(res), Spec: res.Spec, Status: status}
+}
+
+func toTriggerModel(res manage.Resource[models.TriggerSpec], status models.TriggerStatus) models.TriggerResource {
+	return models.TriggerResource{ResourceMeta: resourceMeta(res), Spec: res.Spec, Status: status}
+}
+
+func toCalendarModel(res manage.Resource[models.CalendarSpec]) models.CalendarResource {
+	return models.CalendarResource{ResourceMeta: resourceMeta(res), Spec: res.Spec}
+}
+
+func toWebhookModel(res manage.Resource[models.WebhookSpec]) models.WebhookResource {
+	spec := res.Spec
+	spec.Secret = ""
+	return models.WebhookResource{ResourceMeta: resourceMeta(res), Spec: spec, HasSecret: res.Spec.Secret != ""}
+}
+
+func toLaneResourceModel(res manage.Resource[models.LaneSpec]) models.LaneResource {
+	return models.LaneResource{ResourceMeta: resourceMeta(res), Spec: res.Spec}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/manage"
+)
+
+func newManageRouter(t *testing.T) http.Handler {
+	eng, cleanup := createTestEngine(t)
+	t.Cleanup(cleanup)
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	handler := NewManageHandler(eng, manage.New(eng, manage.WithLogger(log)), log)
+	r := chi.NewRouter()
+	r.Get("/templates/{name}", handler.GetTemplate)
+	r.Put("/templates/{name}", handler.PutTemplate)
+	r.Delete("/templates/{name}", handler.DeleteTemplate)
+	r.Put("/schedules/{name}", handler.PutSchedule)
+	r.Post("/schedules/{name}/backfills", handler.StartBackfill)
+	r.Get("/schedules/{name}/backfills", handler.ListBackfills)
+	r.Delete("/schedules/{name}/backfills/{id}", handler.CancelBackfill)
+	r.Put("/lanes/{name}", handler.PutLane)
+	r.Get("/lanes", handler.ListLanes)
+	return r
+}
+
+func serveManage(h http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestManageHandler_PutTemplate(t *testing.T) {
+	h := newManageRouter(t)
+	body := `{"name":"report","tasks":[{"id":"t1","name":"t1","type":"function"}]}`
+
+	w := serveManage(h, http.MethodPut, "/templates/report", body, map[string]string{"If-None-Match": "*"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PUT status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	var res models.TemplateResource
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if res.Name != "report" || etag != `"1"` || res.Version != 1 {
+		t.Fatalf("PUT = %+v with ETag %s, want version 1", res, etag)
+	}
+
+	// Re-applying is idempotent.
+	w = serveManage(h, http.MethodPut, "/templates/report", body, map[string]string{"If-Match": etag})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != etag {
+		t.Fatalf("repeated PUT status = %d, ETag %s, want 200 with %s", w.Code, w.Header().Get("ETag"), etag)
+	}
+
+	changed := strings.Replace(body, `"report"`, `"report-v2"`, 1)
+	w = serveManage(h, http.MethodPut, "/templates/report", changed, map[string]string{"If-Match": `"7"`})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT with a stale If-Match status = %d, want 412", w.Code)
+	}
+	w = serveManage(h, http.MethodPut, "/templates/report", changed, map[string]string{"If-Match": "yesterday"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with a malformed If-Match status = %d, want 400", w.Code)
+	}
+
+	w = serveManage(h, http.MethodGet, "/templates/report", "", nil)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != etag {
+		t.Fatalf("GET status = %d, ETag %s, want 200 with %s", w.Code, w.Header().Get("ETag"), etag)
+	}
+
+	w = serveManage(h, http.MethodDelete, "/templates/report", "", map[string]string{"If-Match": etag})
+	if w.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if w = serveManage(h, http.MethodGet, "/templates/report", "", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("GET after DELETE status = %d, want 404", w.Code)
+	}
+}
+
+func TestManageHandler_PutTemplateRejectsInvalidWorkflows(t *testing.T) {
+	h := newManageRouter(t)
+	cyclic := `{"name":"loop","tasks":[` +
+		`{"id":"a","name":"a","type":"function","depends_on":["b"]},` +
+		`{"id":"b","name":"b","type":"function","depends_on":["a"]}]}`
+	if w := serveManage(h, http.MethodPut, "/templates/loop", cyclic, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("PUT of a cyclic workflow status = %d, want 400: %s", w.Code, w.Body.String())
+	}
+	if w := serveManage(h, http.MethodPut, "/templates/bad%20name", `{}`, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with an invalid name status = %d, want 400", w.Code)
+	}
+}
+
+func TestManageHandler_PutLane(t *testing.T) {
+	h := newManageRouter(t)
+	w := serveManage(h, http.MethodPut, "/lanes/gpu", `{"capacity":10,"max_concurrency":2}`, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PUT status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	if w = serveManage(h, http.MethodPut, "/lanes/gpu", `{"capacity":20,"max_concurrency":2}`, nil); w.Code != http.StatusConflict {
+		t.Fatalf("PUT with a new capacity status = %d, want 409: %s", w.Code, w.Body.String())
+	}
+	if w = serveManage(h, http.MethodPut, "/lanes/default", `{"capacity":10,"max_concurrency":2}`, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("PUT of the default lane status = %d, want 400: %s", w.Code, w.Body.String())
+	}
+
+	w = serveManage(h, http.MethodGet, "/lanes", "", nil)
+	var list models.LaneResourceListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if list.Count != 1 || list.Items[0].Name != "gpu" {
+		t.Fatalf("managed lanes = %+v, want gpu", list)
+	}
+}
+
+func TestManageHandler_Backfill(t *testing.T) {
+	h := newManageRouter(t)
+	serveManage(h, http.MethodPut, "/templates/report", `{"name":"report","tasks":[{"id":"t1","name":"t1","type":"function"}]}`, nil)
+	w := serveManage(h, http.MethodPut, "/schedules/daily", `{"cron":"@daily","template":"report","suspend":true}`, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PUT schedule status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveManage(h, http.MethodPost, "/schedules/daily/backfills", `{"start":"2026-01-03T00:00:00Z","end":"2026-01-01T00:00:00Z"}`, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("backfill ending before its start status = %d, want 400", w.Code)
+	}
+	w = serveManage(h, http.MethodPost, "/schedules/weekly/backfills", `{"start":"2026-01-01T00:00:00Z","end":"2026-01-03T00:00:00Z"}`, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("backfill of an unknown schedule status = %d, want 404", w.Code)
+	}
+
+	w = serveManage(h, http.MethodPost, "/schedules/daily/backfills", `{"start":"2026-01-01T00:00:00Z","end":"2026-01-03T00:00:00Z","max_parallel":1}`, nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("POST backfill status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+	var bf models.Backfill
+	if err := json.NewDecoder(w.Body).Decode(&bf); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if bf.Total != 3 || bf.MaxParallel != 1 || bf.State != manage.BackfillRunning {
+		t.Fatalf("backfill = %+v, want 3 runs one at a time", bf)
+	}
+
+	w = serveManage(h, http.MethodGet, "/schedules/daily/backfills", "", nil)
+	var list models.BackfillListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || list.Count != 1 || list.Items[0].ID != bf.ID {
+		t.Fatalf("GET backfills = %+v, %v, want the started backfill", list, err)
+	}
+
+	if w = serveManage(h, http.MethodDelete, "/schedules/daily/backfills/"+bf.ID, "", nil); w.Code != http.StatusOK {
+		t.Fatalf("DELETE backfill status = %d, want 200", w.Code)
+	}
+	if w = serveManage(h, http.MethodDelete, "/schedules/daily/backfills/missing", "", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("DELETE unknown backfill status = %d, want 404", w.Code)
+	}
+}
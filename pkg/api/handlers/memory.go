@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/api/response"
 	"github.com/goclaw/goclaw/pkg/memory"
 )
@@ -31,26 +32,6 @@ func NewMemoryHandler(hub *memory.MemoryHub, log memoryLogger) *MemoryHandler {
 	}
 }
 
-// --- Request/Response types ---
-
-type memorizeRequest struct {
-	Content  string            `json:"content"`
-	Vector   []float32         `json:"vector,omitempty"`
-	Metadata map[string]string `json:"metadata,omitempty"`
-}
-
-type memorizeResponse struct {
-	ID string `json:"id"`
-}
-
-type deleteRequest struct {
-	IDs []string `json:"ids"`
-}
-
-type deleteResponse struct {
-	Deleted int `json:"deleted"`
-}
-
 // StoreMemory handles POST /api/v1/memory/{sessionID}
 func (h *MemoryHandler) StoreMemory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -61,7 +42,7 @@ func (h *MemoryHandler) StoreMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req memorizeRequest
+	var req models.MemoryStoreRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
 		return
@@ -79,7 +60,7 @@ func (h *MemoryHandler) StoreMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusCreated, memorizeResponse{ID: id})
+	response.JSON(w, http.StatusCreated, models.MemoryStoreResponse{ID: id})
 }
 
 // QueryMemory handles GET /api/v1/memory/{sessionID}
@@ -143,7 +124,7 @@ func (h *MemoryHandler) DeleteMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req deleteRequest
+	var req models.MemoryDeleteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
 		return
@@ -160,7 +141,7 @@ func (h *MemoryHandler) DeleteMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusOK, deleteResponse{Deleted: len(req.IDs)})
+	response.JSON(w, http.StatusOK, models.MemoryDeleteResponse{Deleted: len(req.IDs)})
 }
 
 // ListMemory handles GET /api/v1/memory/{sessionID}/list
@@ -193,11 +174,11 @@ func (h *MemoryHandler) ListMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusOK, map[string]interface{}{
-		"entries": entries,
-		"total":   total,
-		"limit":   limit,
-		"offset":  offset,
+	response.JSON(w, http.StatusOK, models.MemoryListResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
 	})
 }
 
@@ -238,7 +219,7 @@ func (h *MemoryHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusOK, deleteResponse{Deleted: count})
+	response.JSON(w, http.StatusOK, models.MemoryDeleteResponse{Deleted: count})
 }
 
 // DeleteWeakMemories handles DELETE /api/v1/memory/{sessionID}/weak
@@ -265,5 +246,5 @@ func (h *MemoryHandler) DeleteWeakMemories(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	response.JSON(w, http.StatusOK, deleteResponse{Deleted: count})
+	response.JSON(w, http.StatusOK, models.MemoryDeleteResponse{Deleted: count})
 }
@@ -12,6 +12,7 @@ import (
 	dgbadger "github.com/dgraph-io/badger/v4"
 	"github.com/go-chi/chi/v5"
 	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/memory"
 )
 
@@ -80,7 +81,7 @@ func TestMemoryHandler_StoreMemory(t *testing.T) {
 		t.Errorf("StoreMemory() status = %d, want %d, body: %s", w.Code, http.StatusCreated, w.Body.String())
 	}
 
-	var resp memorizeResponse
+	var resp models.MemoryStoreResponse
 	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
@@ -188,11 +189,11 @@ func TestMemoryHandler_DeleteMemory(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.StoreMemory(w, req)
 
-	var storeResp memorizeResponse
+	var storeResp models.MemoryStoreResponse
 	_ = json.NewDecoder(w.Body).Decode(&storeResp)
 
 	// Delete it
-	delBody, _ := json.Marshal(deleteRequest{IDs: []string{storeResp.ID}})
+	delBody, _ := json.Marshal(models.MemoryDeleteRequest{IDs: []string{storeResp.ID}})
 	req = httptest.NewRequest(http.MethodDelete, "/api/v1/memory/session-1", bytes.NewBuffer(delBody))
 	req.Header.Set("Content-Type", "application/json")
 	req = withChiURLParam(req, "sessionID", "session-1")
@@ -203,7 +204,7 @@ func TestMemoryHandler_DeleteMemory(t *testing.T) {
 		t.Errorf("DeleteMemory() status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	var delResp deleteResponse
+	var delResp models.MemoryDeleteResponse
 	_ = json.NewDecoder(w.Body).Decode(&delResp)
 	if delResp.Deleted != 1 {
 		t.Errorf("expected 1 deleted, got %d", delResp.Deleted)
@@ -311,7 +312,7 @@ func TestMemoryHandler_DeleteSession(t *testing.T) {
 		t.Errorf("DeleteSession() status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	var resp deleteResponse
+	var resp models.MemoryDeleteResponse
 	_ = json.NewDecoder(w.Body).Decode(&resp)
 	if resp.Deleted != 3 {
 		t.Errorf("expected 3 deleted, got %d", resp.Deleted)
@@ -340,7 +341,7 @@ func TestMemoryHandler_DeleteWeakMemories(t *testing.T) {
 		t.Errorf("DeleteWeakMemories() status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	var resp deleteResponse
+	var resp models.MemoryDeleteResponse
 	_ = json.NewDecoder(w.Body).Decode(&resp)
 	if resp.Deleted != 1 {
 		t.Errorf("expected 1 deleted (threshold=2.0 should delete all), got %d", resp.Deleted)
@@ -369,7 +370,7 @@ func TestMemoryHandler_Integration_StoreQueryDelete(t *testing.T) {
 		if w.Code != http.StatusCreated {
 			t.Fatalf("store failed: %s", w.Body.String())
 		}
-		var resp memorizeResponse
+		var resp models.MemoryStoreResponse
 		_ = json.NewDecoder(w.Body).Decode(&resp)
 		storedIDs = append(storedIDs, resp.ID)
 	}
@@ -395,7 +396,7 @@ func TestMemoryHandler_Integration_StoreQueryDelete(t *testing.T) {
 	}
 
 	// Delete one entry
-	delBody, _ := json.Marshal(deleteRequest{IDs: []string{storedIDs[0]}})
+	delBody, _ := json.Marshal(models.MemoryDeleteRequest{IDs: []string{storedIDs[0]}})
 	req = httptest.NewRequest(http.MethodDelete, "/api/v1/memory/session-1", bytes.NewBuffer(delBody))
 	req.Header.Set("Content-Type", "application/json")
 	req = withChiURLParam(req, "sessionID", "session-1")
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/metrics"
+	"gopkg.in/yaml.v3"
+)
+
+// ContentTypeYAML is the media type of the generated alerting rules.
+const ContentTypeYAML = "application/yaml"
+
+// MonitoringHandler handles the Grafana dashboard and Prometheus alert rule
+// generation endpoints.
+type MonitoringHandler struct {
+	metrics *metrics.Manager
+	engine  *engine.Engine
+	target  metrics.MonitoringTarget
+	logger  logger.Logger
+}
+
+// NewMonitoringHandler creates a new monitoring handler. The lanes of target
+// are replaced by the lanes registered with eng at request time.
+func NewMonitoringHandler(manager *metrics.Manager, eng *engine.Engine, target metrics.MonitoringTarget, log logger.Logger) *MonitoringHandler {
+	return &MonitoringHandler{
+		metrics: manager,
+		engine:  eng,
+		target:  target,
+		logger:  log,
+	}
+}
+
+// GetDashboard handles GET /api/v1/monitoring/dashboard
+func (h *MonitoringHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, err := h.metrics.Dashboard(h.currentTarget())
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to generate dashboard")
+		return
+	}
+	response.JSON(w, http.StatusOK, dashboard)
+}
+
+// GetAlertRules handles GET /api/v1/monitoring/alerts
+func (h *MonitoringHandler) GetAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.metrics.AlertRules(h.currentTarget())
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to generate alert rules")
+		return
+	}
+	out, err := yaml.Marshal(rules)
+	if err != nil {
+		writeError(w, r.Context(), err, "Failed to encode alert rules")
+		return
+	}
+	w.Header().Set("Content-Type", ContentTypeYAML)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+}
+
+func (h *MonitoringHandler) currentTarget() metrics.MonitoringTarget {
+	target := h.target
+	target.Lanes = nil
+	for _, s := range h.engine.LaneStats() {
+		target.Lanes = append(target.Lanes, metrics.LaneTarget{Name: s.Name, Capacity: s.Capacity})
+	}
+	return target
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/metrics"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMonitoringHandler(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	cfg := metrics.DefaultConfig()
+	cfg.Enabled = true
+	handler := NewMonitoringHandler(metrics.NewManager(cfg), eng, metrics.MonitoringTarget{SagaEnabled: true}, log)
+
+	w := httptest.NewRecorder()
+	handler.GetDashboard(w, httptest.NewRequest(http.MethodGet, "/api/v1/monitoring/dashboard", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var dashboard metrics.GrafanaImport
+	if err := json.Unmarshal(w.Body.Bytes(), &dashboard); err != nil {
+		t.Fatalf("decode dashboard: %v", err)
+	}
+	if dashboard.Dashboard.UID == "" || len(dashboard.Dashboard.Templating.List) == 0 {
+		t.Fatalf("unexpected dashboard %+v", dashboard.Dashboard)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetAlertRules(w, httptest.NewRequest(http.MethodGet, "/api/v1/monitoring/alerts", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != ContentTypeYAML {
+		t.Fatalf("expected a 200 YAML response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var rules metrics.RuleFile
+	if err := yaml.Unmarshal(w.Body.Bytes(), &rules); err != nil {
+		t.Fatalf("decode rules: %v", err)
+	}
+	if !strings.Contains(w.Body.String(), "goclaw_sagas") || len(rules.Groups) == 0 {
+		t.Fatalf("unexpected rules:\n%s", w.Body.String())
+	}
+
+	disabled := NewMonitoringHandler(metrics.NewManager(metrics.Config{}), eng, metrics.MonitoringTarget{}, log)
+	w = httptest.NewRecorder()
+	disabled.GetDashboard(w, httptest.NewRequest(http.MethodGet, "/api/v1/monitoring/dashboard", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 with metrics disabled, got %d", w.Code)
+	}
+}
@@ -25,6 +25,7 @@ type SagaHandler struct {
 	orchestrator    *saga.SagaOrchestrator
 	checkpointStore saga.CheckpointStore
 	recoveryManager *saga.RecoveryManager
+	archive         saga.ArchiveStore
 	logger          logger.Logger
 	validator       *validator.Validate
 
@@ -32,34 +33,27 @@ type SagaHandler struct {
 	definitions map[string]*saga.SagaDefinition
 }
 
-// NewSagaHandler creates a Saga handler.
+// NewSagaHandler creates a Saga handler. archive may be nil when terminal
+// sagas are not archived.
 func NewSagaHandler(
 	orchestrator *saga.SagaOrchestrator,
 	checkpointStore saga.CheckpointStore,
 	recoveryManager *saga.RecoveryManager,
+	archive saga.ArchiveStore,
 	log logger.Logger,
 ) *SagaHandler {
 	return &SagaHandler{
 		orchestrator:    orchestrator,
 		checkpointStore: checkpointStore,
 		recoveryManager: recoveryManager,
+		archive:         archive,
 		logger:          log,
-		validator:       validator.New(),
+		validator:       newRequestValidator(),
 		definitions:     make(map[string]*saga.SagaDefinition),
 	}
 }
 
 // SubmitSaga handles POST /api/v1/sagas.
-// @Summary Submit a saga
-// @Description Submit a saga definition for asynchronous execution
-// @Tags sagas
-// @Accept json
-// @Produce json
-// @Param saga body models.SagaSubmitRequest true "Saga submit request"
-// @Success 201 {object} models.SagaSubmitResponse "Saga accepted"
-// @Failure 400 {object} response.ErrorResponse "Invalid request"
-// @Failure 503 {object} response.ErrorResponse "Saga runtime unavailable"
-// @Router /api/v1/sagas [post]
 func (h *SagaHandler) SubmitSaga(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil {
 		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga orchestrator unavailable", getRequestID(r.Context()))
@@ -71,8 +65,8 @@ func (h *SagaHandler) SubmitSaga(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "invalid request body", getRequestID(r.Context()))
 		return
 	}
-	if err := h.validator.Struct(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, response.ErrCodeValidationFailed, err.Error(), getRequestID(r.Context()))
+	if err := validateRequest(h.validator, &req); err != nil {
+		writeValidationError(w, r.Context(), err)
 		return
 	}
 
@@ -82,12 +76,21 @@ func (h *SagaHandler) SubmitSaga(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input := any(req.Input)
+	if err := h.orchestrator.Admit(r.Context(), saga.Submission{
+		Definition: definition,
+		Metadata:   req.Metadata,
+		Input:      input,
+	}); err != nil {
+		writeError(w, r.Context(), err, "Failed to submit saga")
+		return
+	}
+
 	sagaID := uuid.NewString()
 	h.defMu.Lock()
 	h.definitions[sagaID] = definition
 	h.defMu.Unlock()
 
-	input := any(req.Input)
 	go func() {
 		_, execErr := h.orchestrator.ExecuteWithID(context.Background(), sagaID, definition, input)
 		if execErr != nil && h.logger != nil {
@@ -105,16 +108,6 @@ func (h *SagaHandler) SubmitSaga(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetSaga handles GET /api/v1/sagas/{id}.
-// @Summary Get saga status
-// @Description Get runtime status for a saga instance
-// @Tags sagas
-// @Produce json
-// @Param id path string true "Saga ID"
-// @Success 200 {object} models.SagaStatusResponse "Saga status"
-// @Failure 400 {object} response.ErrorResponse "Invalid saga ID"
-// @Failure 404 {object} response.ErrorResponse "Saga not found"
-// @Failure 503 {object} response.ErrorResponse "Saga runtime unavailable"
-// @Router /api/v1/sagas/{id} [get]
 func (h *SagaHandler) GetSaga(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil {
 		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga orchestrator unavailable", getRequestID(r.Context()))
@@ -129,57 +122,107 @@ func (h *SagaHandler) GetSaga(w http.ResponseWriter, r *http.Request) {
 
 	instance, err := h.orchestrator.GetInstance(sagaID)
 	if err != nil {
+		if h.archive != nil {
+			if archived, archiveErr := h.archive.Get(r.Context(), sagaID); archiveErr == nil {
+				resp := sagaStatus(archived.Instance)
+				resp.Archived = true
+				response.JSON(w, http.StatusOK, resp)
+				return
+			}
+		}
 		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "saga not found", getRequestID(r.Context()))
 		return
 	}
 
-	resp := models.SagaStatusResponse{
-		SagaID:         instance.ID,
-		Name:           instance.DefinitionName,
-		State:          instance.State.String(),
-		CompletedSteps: append([]string(nil), instance.CompletedSteps...),
-		Compensated:    append([]string(nil), instance.Compensated...),
-		FailedStep:     instance.FailedStep,
-		FailureReason:  instance.FailureReason,
-		StepResults:    sagaResultMap(instance.StepResults),
-		CreatedAt:      instance.CreatedAt,
-		UpdatedAt:      instance.UpdatedAt,
-		StartedAt:      instance.StartedAt,
-		CompletedAt:    instance.CompletedAt,
+	response.JSON(w, http.StatusOK, sagaStatus(instance))
+}
+
+// GetArchivedSaga handles GET /api/v1/sagas/{id}/archive.
+func (h *SagaHandler) GetArchivedSaga(w http.ResponseWriter, r *http.Request) {
+	if h.archive == nil {
+		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga archive unavailable", getRequestID(r.Context()))
+		return
+	}
+
+	sagaID := chi.URLParam(r, "id")
+	if sagaID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "saga id is required", getRequestID(r.Context()))
+		return
+	}
+
+	archived, err := h.archive.Get(r.Context(), sagaID)
+	if err != nil {
+		writeError(w, r.Context(), err, "failed to get archived saga")
+		return
 	}
-	response.JSON(w, http.StatusOK, resp)
+
+	wal := make([]models.SagaWALEntry, 0, len(archived.WAL))
+	for _, entry := range archived.WAL {
+		wal = append(wal, models.SagaWALEntry{
+			Sequence:  entry.Sequence,
+			StepID:    entry.StepID,
+			Type:      string(entry.Type),
+			Data:      string(entry.Data),
+			Timestamp: entry.Timestamp,
+		})
+	}
+	status := sagaStatus(archived.Instance)
+	status.Archived = true
+	response.JSON(w, http.StatusOK, models.SagaArchiveResponse{
+		SagaStatusResponse: status,
+		WAL:                wal,
+		ArchivedAt:         archived.ArchivedAt,
+	})
+}
+
+// ListArchivedSagas handles GET /api/v1/sagas/archive.
+func (h *SagaHandler) ListArchivedSagas(w http.ResponseWriter, r *http.Request) {
+	if h.archive == nil {
+		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga archive unavailable", getRequestID(r.Context()))
+		return
+	}
+
+	limit, offset := sagaPage(r)
+	archived, total, err := h.archive.List(r.Context(), saga.SagaListFilter{
+		State:  strings.TrimSpace(r.URL.Query().Get("state")),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(w, r.Context(), err, "failed to list archived sagas")
+		return
+	}
+
+	items := make([]models.SagaArchiveSummary, 0, len(archived))
+	for _, entry := range archived {
+		items = append(items, models.SagaArchiveSummary{
+			SagaSummary: models.SagaSummary{
+				SagaID:      entry.Instance.ID,
+				Name:        entry.Instance.DefinitionName,
+				State:       entry.Instance.State.String(),
+				CreatedAt:   entry.Instance.CreatedAt,
+				CompletedAt: entry.Instance.CompletedAt,
+			},
+			ArchivedAt: entry.ArchivedAt,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, models.SagaArchiveListResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
 }
 
 // ListSagas handles GET /api/v1/sagas.
-// @Summary List sagas
-// @Description List saga instances with optional state filter and pagination
-// @Tags sagas
-// @Produce json
-// @Param state query string false "Filter by saga state"
-// @Param limit query int false "Maximum number of results" default(20)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Success 200 {object} models.SagaListResponse "Saga list"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
-// @Failure 503 {object} response.ErrorResponse "Saga runtime unavailable"
-// @Router /api/v1/sagas [get]
 func (h *SagaHandler) ListSagas(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil {
 		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga orchestrator unavailable", getRequestID(r.Context()))
 		return
 	}
 
-	limit := 20
-	offset := 0
-	if raw := r.URL.Query().Get("limit"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
-			limit = parsed
-		}
-	}
-	if raw := r.URL.Query().Get("offset"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
-			offset = parsed
-		}
-	}
+	limit, offset := sagaPage(r)
 	state := strings.TrimSpace(r.URL.Query().Get("state"))
 
 	instances, total, err := h.orchestrator.ListInstancesFiltered(r.Context(), saga.SagaListFilter{
@@ -188,7 +231,7 @@ func (h *SagaHandler) ListSagas(w http.ResponseWriter, r *http.Request) {
 		Offset: offset,
 	})
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, err.Error(), getRequestID(r.Context()))
+		writeError(w, r.Context(), err, "failed to list sagas")
 		return
 	}
 
@@ -212,20 +255,6 @@ func (h *SagaHandler) ListSagas(w http.ResponseWriter, r *http.Request) {
 }
 
 // CompensateSaga handles POST /api/v1/sagas/{id}/compensate.
-// @Summary Trigger saga compensation
-// @Description Manually trigger compensation for a saga in pending-compensation state
-// @Tags sagas
-// @Accept json
-// @Produce json
-// @Param id path string true "Saga ID"
-// @Param request body models.SagaCompensateRequest false "Compensation reason"
-// @Success 202 {object} models.SagaActionResponse "Compensation started"
-// @Failure 400 {object} response.ErrorResponse "Invalid saga ID"
-// @Failure 404 {object} response.ErrorResponse "Saga not found"
-// @Failure 409 {object} response.ErrorResponse "Invalid saga state"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
-// @Failure 503 {object} response.ErrorResponse "Saga runtime unavailable"
-// @Router /api/v1/sagas/{id}/compensate [post]
 func (h *SagaHandler) CompensateSaga(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil {
 		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga orchestrator unavailable", getRequestID(r.Context()))
@@ -261,7 +290,7 @@ func (h *SagaHandler) CompensateSaga(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusConflict, response.ErrCodeConflict, err.Error(), getRequestID(r.Context()))
 			return
 		}
-		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, err.Error(), getRequestID(r.Context()))
+		writeError(w, r.Context(), err, "failed to trigger compensation")
 		return
 	}
 
@@ -271,19 +300,48 @@ func (h *SagaHandler) CompensateSaga(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// PreviewCompensation handles POST /api/v1/sagas/{id}/compensate:preview.
+func (h *SagaHandler) PreviewCompensation(w http.ResponseWriter, r *http.Request) {
+	if h.orchestrator == nil {
+		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga orchestrator unavailable", getRequestID(r.Context()))
+		return
+	}
+
+	sagaID := chi.URLParam(r, "id")
+	if sagaID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "saga id is required", getRequestID(r.Context()))
+		return
+	}
+
+	definition := h.getDefinition(sagaID)
+	if definition == nil {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "saga definition not found", getRequestID(r.Context()))
+		return
+	}
+
+	plan, err := h.orchestrator.PreviewCompensation(sagaID, definition)
+	if err != nil {
+		if errors.Is(err, saga.ErrSagaNotFound) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "saga not found", getRequestID(r.Context()))
+			return
+		}
+		writeError(w, r.Context(), err, "failed to plan compensation")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, models.SagaCompensationPreview{
+		SagaID:     plan.SagaID,
+		State:      plan.State.String(),
+		Policy:     plan.Policy.String(),
+		FailedStep: plan.FailedStep,
+		CanTrigger: plan.State == saga.SagaStatePendingCompensation,
+		MaxRetries: plan.Retry.MaxRetries,
+		Steps:      sagaCompensationSteps(plan.Steps),
+		Skipped:    sagaCompensationSteps(plan.Skipped),
+	})
+}
+
 // RecoverSaga handles POST /api/v1/sagas/{id}/recover.
-// @Summary Recover saga from checkpoint
-// @Description Resume a non-terminal saga from its latest checkpoint
-// @Tags sagas
-// @Produce json
-// @Param id path string true "Saga ID"
-// @Success 202 {object} models.SagaActionResponse "Recovery executed"
-// @Failure 400 {object} response.ErrorResponse "Invalid saga ID"
-// @Failure 404 {object} response.ErrorResponse "Definition or checkpoint not found"
-// @Failure 409 {object} response.ErrorResponse "Saga already terminal"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
-// @Failure 503 {object} response.ErrorResponse "Saga recovery unavailable"
-// @Router /api/v1/sagas/{id}/recover [post]
 func (h *SagaHandler) RecoverSaga(w http.ResponseWriter, r *http.Request) {
 	if h.orchestrator == nil || h.checkpointStore == nil {
 		response.Error(w, http.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, "saga recovery unavailable", getRequestID(r.Context()))
@@ -313,7 +371,7 @@ func (h *SagaHandler) RecoverSaga(w http.ResponseWriter, r *http.Request) {
 
 	instance, err := h.orchestrator.ResumeFromCheckpoint(r.Context(), definition, checkpoint, nil)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, err.Error(), getRequestID(r.Context()))
+		writeError(w, r.Context(), err, "failed to recover saga")
 		return
 	}
 
@@ -396,6 +454,55 @@ func buildSagaDefinition(req models.SagaSubmitRequest) (*saga.SagaDefinition, er
 	return builder.Build()
 }
 
+func sagaStatus(instance *saga.SagaInstance) models.SagaStatusResponse {
+	return models.SagaStatusResponse{
+		SagaID:         instance.ID,
+		Name:           instance.DefinitionName,
+		State:          instance.State.String(),
+		CompletedSteps: append([]string(nil), instance.CompletedSteps...),
+		Compensated:    append([]string(nil), instance.Compensated...),
+		FailedStep:     instance.FailedStep,
+		FailureReason:  instance.FailureReason,
+		StepResults:    sagaResultMap(instance.StepResults),
+		CreatedAt:      instance.CreatedAt,
+		UpdatedAt:      instance.UpdatedAt,
+		StartedAt:      instance.StartedAt,
+		CompletedAt:    instance.CompletedAt,
+	}
+}
+
+// sagaPage returns the limit and offset query parameters of a saga list
+// request, 20 and 0 by default.
+func sagaPage(r *http.Request) (int, int) {
+	limit := 20
+	offset := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			limit = parsed
+		}
+	}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	return limit, offset
+}
+
+func sagaCompensationSteps(planned []saga.PlannedCompensation) []models.SagaCompensationStep {
+	steps := make([]models.SagaCompensationStep, 0, len(planned))
+	for _, step := range planned {
+		steps = append(steps, models.SagaCompensationStep{
+			StepID:     step.StepID,
+			Stage:      step.Stage,
+			Policy:     step.Policy.String(),
+			TimeoutMS:  step.Timeout.Milliseconds(),
+			SkipReason: step.SkipReason,
+		})
+	}
+	return steps
+}
+
 func sagaResultMap(source map[string]any) map[string]any {
 	if len(source) == 0 {
 		return map[string]any{}
@@ -53,7 +53,7 @@ func newSagaHandlerForTest(t *testing.T) (*SagaHandler, *saga.BadgerCheckpointSt
 		Format: "json",
 		Output: "stdout",
 	})
-	handler := NewSagaHandler(orchestrator, checkpointStore, recovery, log)
+	handler := NewSagaHandler(orchestrator, checkpointStore, recovery, nil, log)
 	cleanup := func() {
 		_ = wal.Close()
 		_ = db.Close()
@@ -185,6 +185,32 @@ func TestSagaHandlerCompensateAndRecoverValidation(t *testing.T) {
 	_ = json.NewDecoder(w.Body).Decode(&submitResp)
 	time.Sleep(80 * time.Millisecond)
 
+	preview := func() models.SagaCompensationPreview {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/sagas/"+submitResp.SagaID+"/compensate:preview", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", submitResp.SagaID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.PreviewCompensation(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("PreviewCompensation() status = %d, want %d, body=%s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp models.SagaCompensationPreview
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+
+	// preview lists the compensation without running it
+	plan := preview()
+	if !plan.CanTrigger || plan.Policy != "manual" || plan.FailedStep != "b" ||
+		len(plan.Steps) != 1 || plan.Steps[0].StepID != "a" || plan.Steps[0].Policy != "auto" || len(plan.Skipped) != 0 {
+		t.Fatalf("PreviewCompensation() = %+v, want a to be compensated", plan)
+	}
+	if plan = preview(); !plan.CanTrigger {
+		t.Fatalf("PreviewCompensation() changed the saga: %+v", plan)
+	}
+
 	// manual compensate should be accepted
 	compReq := httptest.NewRequest(http.MethodPost, "/api/v1/sagas/"+submitResp.SagaID+"/compensate", bytes.NewReader([]byte(`{"reason":"manual"}`)))
 	compCtx := chi.NewRouteContext()
@@ -196,6 +222,11 @@ func TestSagaHandlerCompensateAndRecoverValidation(t *testing.T) {
 		t.Fatalf("CompensateSaga() status = %d, want %d, body=%s", compW.Code, http.StatusAccepted, compW.Body.String())
 	}
 
+	plan = preview()
+	if plan.CanTrigger || len(plan.Steps) != 0 || len(plan.Skipped) != 1 || plan.Skipped[0].SkipReason != saga.SkipReasonAlreadyCompensated {
+		t.Fatalf("PreviewCompensation() after compensation = %+v, want a skipped as compensated", plan)
+	}
+
 	// recover terminal saga should return conflict
 	_ = checkpointStore.Save(context.Background(), &saga.Checkpoint{
 		DefinitionName: "manual-compensate",
@@ -213,3 +244,79 @@ func TestSagaHandlerCompensateAndRecoverValidation(t *testing.T) {
 		t.Fatalf("RecoverSaga() status = %d, want %d", recW.Code, http.StatusConflict)
 	}
 }
+
+func TestSagaHandlerArchive(t *testing.T) {
+	handler, _, cleanup := newSagaHandlerForTest(t)
+	defer cleanup()
+
+	withID := func(req *http.Request, id string) *http.Request {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	w := httptest.NewRecorder()
+	handler.ListArchivedSagas(w, httptest.NewRequest(http.MethodGet, "/api/v1/sagas/archive", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("ListArchivedSagas() without archive status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	opts := dgbadger.DefaultOptions(t.TempDir())
+	opts.Logger = nil
+	db, err := dgbadger.Open(opts)
+	if err != nil {
+		t.Fatalf("open badger: %v", err)
+	}
+	defer db.Close()
+	archive, err := saga.NewBadgerArchiveStore(db)
+	if err != nil {
+		t.Fatalf("new archive store: %v", err)
+	}
+	handler.archive = archive
+
+	instance := saga.NewSagaInstance("archived-1", &saga.SagaDefinition{Name: "archived"})
+	_ = instance.TransitionTo(saga.SagaStateRunning)
+	_ = instance.TransitionTo(saga.SagaStateCompleted)
+	if err := archive.Put(context.Background(), &saga.ArchivedSaga{
+		Instance:   instance,
+		WAL:        []saga.WALEntry{{Sequence: 1, SagaID: "archived-1", StepID: "a", Type: saga.WALEntryTypeStepCompleted}},
+		ArchivedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("archive.Put() error = %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetSaga(w, withID(httptest.NewRequest(http.MethodGet, "/api/v1/sagas/archived-1", nil), "archived-1"))
+	var status models.SagaStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil || w.Code != http.StatusOK || !status.Archived {
+		t.Fatalf("GetSaga() = %d %+v, want the archived saga", w.Code, status)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetArchivedSaga(w, withID(httptest.NewRequest(http.MethodGet, "/api/v1/sagas/archived-1/archive", nil), "archived-1"))
+	var archived models.SagaArchiveResponse
+	if err := json.NewDecoder(w.Body).Decode(&archived); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("GetArchivedSaga() status = %d, err = %v", w.Code, err)
+	}
+	if archived.State != "completed" || len(archived.WAL) != 1 || archived.WAL[0].StepID != "a" {
+		t.Fatalf("GetArchivedSaga() = %+v", archived)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetArchivedSaga(w, withID(httptest.NewRequest(http.MethodGet, "/api/v1/sagas/missing/archive", nil), "missing"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("GetArchivedSaga(missing) status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ListArchivedSagas(w, httptest.NewRequest(http.MethodGet, "/api/v1/sagas/archive?state=compensated", nil))
+	var list models.SagaArchiveListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || w.Code != http.StatusOK || list.Total != 0 {
+		t.Fatalf("ListArchivedSagas(compensated) = %d %+v", w.Code, list)
+	}
+	w = httptest.NewRecorder()
+	handler.ListArchivedSagas(w, httptest.NewRequest(http.MethodGet, "/api/v1/sagas/archive?state=completed", nil))
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || list.Total != 1 || list.Items[0].SagaID != "archived-1" {
+		t.Fatalf("ListArchivedSagas(completed) = %d %+v", w.Code, list)
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/signal"
+)
+
+// defaultSignalHistoryLimit is the number of signals returned when no limit is given.
+const defaultSignalHistoryLimit = 50
+
+// SignalHandler handles signal inspection and schema endpoints.
+type SignalHandler struct {
+	history   signal.History
+	schemas   *signal.SchemaRegistry
+	logger    logger.Logger
+	validator *validator.Validate
+}
+
+// NewSignalHandler creates a signal handler reading from history and
+// managing schemas. Either may be nil when the feature is disabled.
+func NewSignalHandler(history signal.History, schemas *signal.SchemaRegistry, log logger.Logger) *SignalHandler {
+	return &SignalHandler{
+		history:   history,
+		schemas:   schemas,
+		logger:    log,
+		validator: newRequestValidator(),
+	}
+}
+
+// GetHistory handles GET /api/v1/signals/{channel}/history
+func (h *SignalHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	channel := chi.URLParam(r, "channel")
+	if channel == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Channel is required", getRequestID(ctx))
+		return
+	}
+
+	if h.history == nil {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Signal history is disabled", getRequestID(ctx))
+		return
+	}
+
+	limit := defaultSignalHistoryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "limit must be a positive integer", getRequestID(ctx))
+			return
+		}
+		limit = v
+	}
+
+	var since time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "since must be an RFC 3339 timestamp", getRequestID(ctx))
+			return
+		}
+		since = v
+	}
+
+	signals, err := h.history.List(ctx, channel, since, limit)
+	if err != nil {
+		if h.logger != nil {
+			h.logger.Error("Failed to read signal history", "channel", channel, "error", err)
+		}
+		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, "Failed to read signal history", getRequestID(ctx))
+		return
+	}
+
+	resp := models.SignalHistoryResponse{
+		Channel: channel,
+		Signals: make([]models.SignalRecord, 0, len(signals)),
+	}
+	for _, sig := range signals {
+		resp.Signals = append(resp.Signals, models.SignalRecord{
+			Type:    string(sig.Type),
+			TaskID:  sig.TaskID,
+			Payload: sig.Payload,
+			SentAt:  sig.SentAt,
+		})
+	}
+	resp.Count = len(resp.Signals)
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// ListSchemas handles GET /api/v1/signals/schemas. With a channel query
+// parameter only the schemas applying to that channel (and type) are listed.
+func (h *SignalHandler) ListSchemas(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSchemas(w, r) {
+		return
+	}
+
+	var regs []signal.SchemaRegistration
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		regs = h.schemas.Match(channel, signal.SignalType(r.URL.Query().Get("type")))
+	} else {
+		regs = h.schemas.List()
+	}
+
+	resp := models.SignalSchemaListResponse{
+		Schemas: make([]models.SignalSchema, 0, len(regs)),
+	}
+	for _, reg := range regs {
+		resp.Schemas = append(resp.Schemas, toSignalSchemaModel(reg))
+	}
+	resp.Count = len(resp.Schemas)
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// RegisterSchema handles PUT /api/v1/signals/schemas
+func (h *SignalHandler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSchemas(w, r) {
+		return
+	}
+
+	var req models.SignalSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "invalid request body", getRequestID(r.Context()))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		writeValidationError(w, r.Context(), err)
+		return
+	}
+
+	reg, err := h.schemas.Register(req.Channel, signal.SignalType(req.Type), req.Schema)
+	if err != nil {
+		response.HandleError(w, err, getRequestID(r.Context()))
+		return
+	}
+	if h.logger != nil {
+		h.logger.Info("Signal schema registered", "channel", reg.Channel, "type", reg.Type)
+	}
+	response.JSON(w, http.StatusOK, toSignalSchemaModel(reg))
+}
+
+// DeleteSchema handles DELETE /api/v1/signals/schemas?channel=...&type=...
+func (h *SignalHandler) DeleteSchema(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSchemas(w, r) {
+		return
+	}
+
+	channel := r.URL.Query().Get("channel")
+	if channel == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "channel is required", getRequestID(r.Context()))
+		return
+	}
+	if !h.schemas.Unregister(channel, signal.SignalType(r.URL.Query().Get("type"))) {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Signal schema not found", getRequestID(r.Context()))
+		return
+	}
+	response.JSON(w, http.StatusOK, models.SignalSchemaDeleteResponse{Deleted: true})
+}
+
+func (h *SignalHandler) requireSchemas(w http.ResponseWriter, r *http.Request) bool {
+	if h.schemas == nil {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Signal schemas are disabled", getRequestID(r.Context()))
+		return false
+	}
+	return true
+}
+
+func toSignalSchemaModel(reg signal.SchemaRegistration) models.SignalSchema {
+	return models.SignalSchema{
+		Channel:   reg.Channel,
+		Type:      string(reg.Type),
+		Schema:    reg.Schema,
+		UpdatedAt: reg.UpdatedAt,
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/signal"
+)
+
+func TestSignalHandler_GetHistory(t *testing.T) {
+	history := signal.NewMemoryHistory(10, 10)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		_ = history.Append(context.Background(), &signal.Signal{
+			Type:    signal.SignalSteer,
+			TaskID:  "task-1",
+			Payload: json.RawMessage(`{"parameters":{"n":1}}`),
+			SentAt:  base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	handler := NewSignalHandler(history, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/signals/task-1/history?limit=2", nil)
+	req = withChiURLParam(req, "channel", "task-1")
+	w := httptest.NewRecorder()
+	handler.GetHistory(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.SignalHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Channel != "task-1" || resp.Count != 2 || len(resp.Signals) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if !resp.Signals[1].SentAt.Equal(base.Add(2 * time.Minute)) {
+		t.Fatalf("expected newest signal last, got %v", resp.Signals[1].SentAt)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/signals/task-1/history?since="+base.Add(time.Minute).Format(time.RFC3339), nil)
+	req = withChiURLParam(req, "channel", "task-1")
+	w = httptest.NewRecorder()
+	handler.GetHistory(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Count != 2 {
+		t.Fatalf("expected 2 signals since t+1m, got %+v (%v)", resp, err)
+	}
+}
+
+func TestSignalHandler_GetHistory_InvalidQuery(t *testing.T) {
+	handler := NewSignalHandler(signal.NewMemoryHistory(10, 10), nil, nil)
+
+	for _, query := range []string{"limit=0", "limit=abc", "since=yesterday"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/signals/task-1/history?"+query, nil)
+		req = withChiURLParam(req, "channel", "task-1")
+		w := httptest.NewRecorder()
+		handler.GetHistory(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestSignalHandler_Schemas(t *testing.T) {
+	handler := NewSignalHandler(nil, signal.NewSchemaRegistry(), nil)
+
+	body := `{"channel":"ingest-*","type":"steer","schema":{"type":"object","required":["parameters"]}}`
+	w := httptest.NewRecorder()
+	handler.RegisterSchema(w, httptest.NewRequest(http.MethodPut, "/api/v1/signals/schemas", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ListSchemas(w, httptest.NewRequest(http.MethodGet, "/api/v1/signals/schemas?channel=ingest-7&type=steer", nil))
+	var list models.SignalSchemaListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if list.Count != 1 || list.Schemas[0].Channel != "ingest-*" || list.Schemas[0].Type != "steer" {
+		t.Fatalf("unexpected schemas: %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ListSchemas(w, httptest.NewRequest(http.MethodGet, "/api/v1/signals/schemas?channel=other", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 0 {
+		t.Fatalf("expected no schemas for other channel, got %+v (%v)", list, err)
+	}
+
+	w = httptest.NewRecorder()
+	handler.DeleteSchema(w, httptest.NewRequest(http.MethodDelete, "/api/v1/signals/schemas?channel=ingest-*&type=steer", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	handler.DeleteSchema(w, httptest.NewRequest(http.MethodDelete, "/api/v1/signals/schemas?channel=ingest-*&type=steer", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for second delete, got %d", w.Code)
+	}
+}
+
+func TestSignalHandler_RegisterSchema_Invalid(t *testing.T) {
+	handler := NewSignalHandler(nil, signal.NewSchemaRegistry(), nil)
+
+	for _, body := range []string{
+		`{"type":"steer","schema":{"type":"object"}}`,
+		`{"channel":"*","type":"resume","schema":{"type":"object"}}`,
+		`{"channel":"*","schema":{"type":"object","if":{}}}`,
+	} {
+		w := httptest.NewRecorder()
+		handler.RegisterSchema(w, httptest.NewRequest(http.MethodPut, "/api/v1/signals/schemas", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler = NewSignalHandler(nil, nil, nil)
+	handler.GetHistory(w, withChiURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/signals/task-1/history", nil), "channel", "task-1"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with history disabled, got %d", w.Code)
+	}
+}
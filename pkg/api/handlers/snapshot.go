@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/snapshot"
+)
+
+// ContentTypeSnapshot is the media type of snapshot archives.
+const ContentTypeSnapshot = "application/gzip"
+
+// SnapshotHandler handles the snapshot export and import endpoints.
+type SnapshotHandler struct {
+	source snapshot.Source
+	logger logger.Logger
+}
+
+// NewSnapshotHandler creates a new snapshot handler for the state of source.
+func NewSnapshotHandler(source snapshot.Source, log logger.Logger) *SnapshotHandler {
+	return &SnapshotHandler{
+		source: source,
+		logger: log,
+	}
+}
+
+// ExportSnapshot handles GET /admin/snapshot. The archive is built before
+// the response starts, so a failed export answers with an error status
+// rather than a truncated archive.
+func (h *SnapshotHandler) ExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var buf bytes.Buffer
+	manifest, err := snapshot.Export(ctx, &buf, h.source)
+	if err != nil {
+		h.logger.Error("Failed to export snapshot", "error", err)
+		writeError(w, ctx, err, "Failed to export snapshot")
+		return
+	}
+	h.logger.Info("Exported snapshot",
+		"workflows", manifest.Workflows,
+		"sagas", manifest.Sagas,
+		"memory_entries", manifest.MemoryEntries,
+		"resources", manifest.Resources,
+	)
+
+	filename := fmt.Sprintf("goclaw-snapshot-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", ContentTypeSnapshot)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.logger.Warn("Snapshot download interrupted", "error", err)
+	}
+}
+
+// ImportSnapshot handles POST /admin/snapshot. The body is an archive
+// returned by ExportSnapshot; it is only restored into an empty instance.
+func (h *SnapshotHandler) ImportSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	manifest, err := snapshot.Import(ctx, r.Body, h.source)
+	if err != nil {
+		h.logger.Error("Failed to import snapshot", "error", err)
+		writeError(w, ctx, err, "Failed to import snapshot")
+		return
+	}
+	h.logger.Info("Imported snapshot",
+		"created_at", manifest.CreatedAt,
+		"workflows", manifest.Workflows,
+		"sagas", manifest.Sagas,
+		"memory_entries", manifest.MemoryEntries,
+		"resources", manifest.Resources,
+	)
+	response.JSON(w, http.StatusOK, manifest)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+// ToolHandler handles the tool registry endpoints.
+type ToolHandler struct {
+	engine    *engine.Engine
+	logger    logger.Logger
+	validator *validator.Validate
+}
+
+// NewToolHandler creates a new tool handler.
+func NewToolHandler(eng *engine.Engine, log logger.Logger) *ToolHandler {
+	return &ToolHandler{
+		engine:    eng,
+		logger:    log,
+		validator: newRequestValidator(),
+	}
+}
+
+// ListTools handles GET /api/v1/tools
+func (h *ToolHandler) ListTools(w http.ResponseWriter, r *http.Request) {
+	tools := h.engine.ListTools(r.URL.Query().Get("tenant"))
+	response.JSON(w, http.StatusOK, models.ToolListResponse{
+		Tools: tools,
+		Count: len(tools),
+	})
+}
+
+// GetTool handles GET /api/v1/tools/{name}
+func (h *ToolHandler) GetTool(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tool, err := h.engine.GetTool(chi.URLParam(r, "name"), r.URL.Query().Get("tenant"))
+	if err != nil {
+		writeError(w, ctx, err, "Failed to get tool")
+		return
+	}
+	response.JSON(w, http.StatusOK, tool)
+}
+
+// SetToolTenant handles PUT /api/v1/tools/{name}/tenants/{tenant}
+func (h *ToolHandler) SetToolTenant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+	tenant := chi.URLParam(r, "tenant")
+
+	var req models.ToolTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		writeValidationError(w, ctx, err)
+		return
+	}
+
+	tool, err := h.engine.SetToolEnabled(ctx, name, tenant, *req.Enabled)
+	if err != nil {
+		writeError(w, ctx, err, "Failed to set tool tenant")
+		return
+	}
+	h.logger.Info("Tool tenant updated", "tool", name, "tenant", tenant, "enabled", *req.Enabled)
+	response.JSON(w, http.StatusOK, tool)
+}
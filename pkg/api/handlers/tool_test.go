@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestToolHandler_EnableForTenant(t *testing.T) {
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	eng, err := engine.New(&config.Config{
+		Orchestration: config.OrchestrationConfig{
+			MaxAgents: 1,
+			Tools: map[string]config.ToolConfig{
+				"web-search": {Description: "Search the web", Tenants: []string{"acme"}},
+			},
+		},
+	}, log, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	handler := NewToolHandler(eng, log)
+
+	put := func(name, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tools/"+name+"/tenants/globex", strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("name", name)
+		rctx.URLParams.Add("tenant", "globex")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.SetToolTenant(w, req)
+		return w
+	}
+	if w := put("web-search", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without enabled, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put("browser", `{"enabled": true}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for an unknown tool, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put("web-search", `{"enabled": true}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tools?tenant=globex", nil)
+	w := httptest.NewRecorder()
+	handler.ListTools(w, req)
+	var resp models.ToolListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Count != 1 || !resp.Tools[0].Enabled || !resp.Tools[0].Tenants["globex"] {
+		t.Fatalf("unexpected tools %+v", resp.Tools)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/api/response"
+)
+
+// FieldError describes a single request field that failed validation.
+type FieldError struct {
+	// Path is the JSON path of the field (e.g. "tasks[0].type").
+	Path string `json:"path"`
+	// Constraint is the violated rule, with its parameter if any (e.g. "oneof=http script function").
+	Constraint string `json:"constraint"`
+	// Value is the rejected value.
+	Value interface{} `json:"value"`
+	// Message is a human-readable description of the violation.
+	Message string `json:"message"`
+}
+
+// FieldErrors is a collection of field validation errors.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	if len(e) == 0 {
+		return "request validation failed"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("request validation failed:\n")
+	for _, fe := range e {
+		sb.WriteString(fmt.Sprintf("  - %s: %s (got %v)\n", fe.Path, fe.Message, fe.Value))
+	}
+	return sb.String()
+}
+
+// newRequestValidator creates a validator that reports fields by their JSON names.
+func newRequestValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// validateRequest validates req and converts failures to FieldErrors.
+func validateRequest(v *validator.Validate, req interface{}) error {
+	err := v.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrs := make(FieldErrors, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		constraint := fe.Tag()
+		if fe.Param() != "" {
+			constraint += "=" + fe.Param()
+		}
+		fieldErrs = append(fieldErrs, FieldError{
+			Path:       fieldPath(fe.Namespace()),
+			Constraint: constraint,
+			Value:      fe.Value(),
+			Message:    fieldErrorMessage(fe),
+		})
+	}
+	return fieldErrs
+}
+
+// fieldPath strips the root struct name from a validator namespace.
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// fieldErrorMessage returns a human-readable message for a validation failure.
+func fieldErrorMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	switch fe.Tag() {
+	case "required":
+		return "this field is required"
+	case "min":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fe.Param())
+	default:
+		return fmt.Sprintf("failed validation: %s", fe.Tag())
+	}
+}
+
+// writeValidationError writes a 400 response. Field errors are listed under
+// details.fields; any other error is reported as a single message.
+func writeValidationError(w http.ResponseWriter, ctx context.Context, err error) {
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeValidationFailed, err.Error(), getRequestID(ctx))
+		return
+	}
+
+	response.ErrorWithDetails(w, http.StatusBadRequest, response.ErrCodeValidationFailed,
+		"request validation failed", map[string]interface{}{"fields": fieldErrs}, getRequestID(ctx))
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+type validationErrorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details struct {
+			Fields []FieldError `json:"fields"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func decodeValidationError(t *testing.T, w *httptest.ResponseRecorder) validationErrorBody {
+	t.Helper()
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var body validationErrorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Error.Code != response.ErrCodeValidationFailed {
+		t.Fatalf("code = %s, want %s", body.Error.Code, response.ErrCodeValidationFailed)
+	}
+	return body
+}
+
+func findFieldError(fields []FieldError, path string) *FieldError {
+	for i := range fields {
+		if fields[i].Path == path {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateRequest_FieldErrors(t *testing.T) {
+	v := newRequestValidator()
+	req := models.WorkflowRequest{
+		Name: "wf",
+		Tasks: []models.TaskDefinition{
+			{ID: "t1", Name: "first", Type: "http"},
+			{ID: "t2", Name: "second", Type: "ftp", Retries: 9},
+		},
+	}
+
+	err := validateRequest(v, &req)
+	fieldErrs, ok := err.(FieldErrors)
+	if !ok {
+		t.Fatalf("expected FieldErrors, got %T: %v", err, err)
+	}
+	if len(fieldErrs) != 2 {
+		t.Fatalf("expected 2 field errors, got %d: %v", len(fieldErrs), fieldErrs)
+	}
+
+	typeErr := findFieldError(fieldErrs, "tasks[1].type")
+	if typeErr == nil {
+		t.Fatalf("expected tasks[1].type error, got %v", fieldErrs)
+	}
+	if typeErr.Constraint != "oneof=http script function container" {
+		t.Errorf("constraint = %q", typeErr.Constraint)
+	}
+	if typeErr.Value != "ftp" {
+		t.Errorf("value = %v, want ftp", typeErr.Value)
+	}
+
+	retriesErr := findFieldError(fieldErrs, "tasks[1].retries")
+	if retriesErr == nil || retriesErr.Constraint != "max=5" || retriesErr.Message != "must be at most 5" {
+		t.Errorf("unexpected retries error: %+v", retriesErr)
+	}
+
+	if validateRequest(v, &models.WorkflowRequest{Name: "ok", Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "script"}}}) != nil {
+		t.Error("expected valid request to pass")
+	}
+}
+
+func TestWorkflowHandler_SubmitWorkflow_FieldErrors(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	handler := NewWorkflowHandler(eng, logger.New(&logger.Config{Level: logger.InfoLevel, Format: "json", Output: "stdout"}))
+	body := []byte(`{"description":"missing name","tasks":[{"id":"t1","name":"task","type":"bogus"}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.SubmitWorkflow(w, req)
+
+	resp := decodeValidationError(t, w)
+	if field := findFieldError(resp.Error.Details.Fields, "name"); field == nil || field.Constraint != "required" {
+		t.Errorf("expected required error on name, got %+v", resp.Error.Details.Fields)
+	}
+	if field := findFieldError(resp.Error.Details.Fields, "tasks[0].type"); field == nil || field.Value != "bogus" {
+		t.Errorf("expected oneof error on tasks[0].type, got %+v", resp.Error.Details.Fields)
+	}
+}
+
+func TestSagaHandler_SubmitSaga_FieldErrors(t *testing.T) {
+	handler, _, cleanup := newSagaHandlerForTest(t)
+	defer cleanup()
+
+	body := []byte(`{"name":"s","policy":"retry","steps":[{"id":""}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sagas", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.SubmitSaga(w, req)
+
+	resp := decodeValidationError(t, w)
+	if field := findFieldError(resp.Error.Details.Fields, "policy"); field == nil || field.Constraint != "oneof=auto manual skip" {
+		t.Errorf("expected oneof error on policy, got %+v", resp.Error.Details.Fields)
+	}
+	if field := findFieldError(resp.Error.Details.Fields, "steps[0].id"); field == nil || field.Constraint != "required" {
+		t.Errorf("expected required error on steps[0].id, got %+v", resp.Error.Details.Fields)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/api/response"
 	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/errs"
 	"github.com/goclaw/goclaw/pkg/logger"
 	"github.com/goclaw/goclaw/pkg/storage"
 )
@@ -29,21 +31,11 @@ func NewWorkflowHandler(eng *engine.Engine, log logger.Logger) *WorkflowHandler
 	return &WorkflowHandler{
 		engine:    eng,
 		logger:    log,
-		validator: validator.New(),
+		validator: newRequestValidator(),
 	}
 }
 
 // SubmitWorkflow handles POST /api/v1/workflows
-// @Summary Submit a new workflow
-// @Description Submit a new workflow for execution with tasks and dependencies
-// @Tags workflows
-// @Accept json
-// @Produce json
-// @Param workflow body models.WorkflowRequest true "Workflow definition"
-// @Success 201 {object} models.WorkflowResponse "Workflow created successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid request body or validation error"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
-// @Router /api/v1/workflows [post]
 func (h *WorkflowHandler) SubmitWorkflow(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -56,9 +48,9 @@ func (h *WorkflowHandler) SubmitWorkflow(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate request
-	if err := h.validator.Struct(&req); err != nil {
+	if err := validateRequest(h.validator, &req); err != nil {
 		h.logger.Error("Validation failed", "error", err)
-		response.Error(w, http.StatusBadRequest, response.ErrCodeValidationFailed, err.Error(), getRequestID(ctx))
+		writeValidationError(w, ctx, err)
 		return
 	}
 
@@ -66,6 +58,9 @@ func (h *WorkflowHandler) SubmitWorkflow(w http.ResponseWriter, r *http.Request)
 	if req.Async {
 		mode = engine.SubmissionModeAsync
 	}
+	if req.Simulate {
+		mode = engine.SubmissionModeSimulate
+	}
 
 	// Submit workflow to runtime engine with explicit mode mapping.
 	statusResp, err := h.engine.SubmitWorkflowRuntime(ctx, &req, engine.SubmitWorkflowOptions{
@@ -77,7 +72,11 @@ func (h *WorkflowHandler) SubmitWorkflow(w http.ResponseWriter, r *http.Request)
 			return
 		}
 		h.logger.Error("Failed to submit workflow", "error", err)
-		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, "Failed to submit workflow", getRequestID(ctx))
+		writeError(w, ctx, err, "Failed to submit workflow")
+		return
+	}
+	if mode == engine.SubmissionModeSimulate {
+		response.JSON(w, http.StatusOK, statusResp)
 		return
 	}
 
@@ -93,16 +92,89 @@ func (h *WorkflowHandler) SubmitWorkflow(w http.ResponseWriter, r *http.Request)
 	response.JSON(w, http.StatusCreated, resp)
 }
 
-// GetWorkflow handles GET /api/v1/workflows/{id}
-// @Summary Get workflow status
-// @Description Get the current status and details of a specific workflow
-// @Tags workflows
-// @Produce json
-// @Param id path string true "Workflow ID"
-// @Success 200 {object} models.WorkflowStatusResponse "Workflow status"
-// @Failure 400 {object} response.ErrorResponse "Invalid workflow ID"
-// @Failure 404 {object} response.ErrorResponse "Workflow not found"
-// @Router /api/v1/workflows/{id} [get]
+// ExecuteWorkflow handles POST /api/v1/workflows:execute. It runs the
+// workflow like a synchronous submission and responds with the results of its
+// tasks inline, for short request-response workflows. A run still going when
+// the timeout query parameter (default 30s, at most 5m) elapses is cancelled
+// and answered with 504.
+func (h *WorkflowHandler) ExecuteWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.WorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request", "error", err)
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		h.logger.Error("Validation failed", "error", err)
+		writeValidationError(w, ctx, err)
+		return
+	}
+	if req.Async || req.Simulate {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "async and simulate do not apply to executed workflows", getRequestID(ctx))
+		return
+	}
+	timeout, ok := waitTimeout(w, r)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	status, err := h.engine.SubmitWorkflowRuntime(execCtx, &req, engine.SubmitWorkflowOptions{
+		Mode: engine.SubmissionModeSync,
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			details := map[string]interface{}{}
+			if status != nil {
+				// Nobody is left to read the results of the run.
+				if cancelErr := h.engine.CancelWorkflowRequest(context.Background(), status.ID); cancelErr != nil {
+					h.logger.Warn("Failed to cancel timed out workflow", "id", status.ID, "error", cancelErr)
+				}
+				details["workflow_id"] = status.ID
+			}
+			response.ErrorWithDetails(w, http.StatusGatewayTimeout, response.ErrCodeGatewayTimeout,
+				"Workflow did not finish within "+timeout.String(), details, getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to execute workflow", "error", err)
+		writeError(w, ctx, err, "Failed to execute workflow")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, executeResponse(status, time.Since(start)))
+}
+
+// executeResponse collects the task results of the finished run status.
+func executeResponse(status *models.WorkflowStatusResponse, elapsed time.Duration) models.WorkflowExecuteResponse {
+	resp := models.WorkflowExecuteResponse{
+		ID:         status.ID,
+		Name:       status.Name,
+		Status:     status.Status,
+		Outputs:    make(map[string]interface{}),
+		Error:      status.Error,
+		DurationMS: elapsed.Milliseconds(),
+		RequestID:  status.RequestID,
+	}
+	for _, task := range status.Tasks {
+		switch task.Status {
+		case "completed":
+			resp.Outputs[task.ID] = task.Result
+		case "failed":
+			if resp.Errors == nil {
+				resp.Errors = make(map[string]string)
+			}
+			resp.Errors[task.ID] = task.Error
+		}
+	}
+	return resp
+}
+
+// GetWorkflow handles GET /api/v1/workflows/{id}. With view=summary the task
+// list is replaced by counts per status and the most recent failures.
 func (h *WorkflowHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workflowID := chi.URLParam(r, "id")
@@ -113,7 +185,17 @@ func (h *WorkflowHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get workflow status from engine
-	status, err := h.engine.GetWorkflowStatusResponse(ctx, workflowID)
+	var status *models.WorkflowStatusResponse
+	var err error
+	switch view := r.URL.Query().Get("view"); view {
+	case "", "full":
+		status, err = h.engine.GetWorkflowStatusResponse(ctx, workflowID)
+	case "summary":
+		status, err = h.engine.GetWorkflowSummaryResponse(ctx, workflowID)
+	default:
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "view must be full or summary", getRequestID(ctx))
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get workflow", "id", workflowID, "error", err)
 		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
@@ -123,19 +205,87 @@ func (h *WorkflowHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, status)
 }
 
-// ListWorkflows handles GET /api/v1/workflows
-// @Summary List workflows
-// @Description List all workflows with optional filtering and pagination
-// @Tags workflows
-// @Produce json
-// @Param status query string false "Filter by status"
-// @Param limit query int false "Maximum number of results" default(10)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Success 200 {object} models.WorkflowListResponse "List of workflows"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
-// @Router /api/v1/workflows [get]
+// Bounds of the timeout of the requests waiting for workflows to finish:
+// GET /api/v1/workflows/{id}/wait and POST /api/v1/workflows:execute.
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 5 * time.Minute
+)
+
+// waitWriteSlack is the time allowed for writing a wait response after its
+// timeout elapsed.
+const waitWriteSlack = 10 * time.Second
+
+// waitTimeout returns the timeout query parameter of r, or the default. It
+// writes a 400 response and returns false if the parameter is invalid.
+// Otherwise it extends the write deadline of w past the timeout, as the
+// wait outlasts the server's write timeout for ordinary requests.
+func waitTimeout(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	timeout := defaultWaitTimeout
+	if raw := r.URL.Query().Get("timeout"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 || d > maxWaitTimeout {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest,
+				"timeout must be a positive duration of at most "+maxWaitTimeout.String()+", e.g. 60s", getRequestID(r.Context()))
+			return 0, false
+		}
+		timeout = d
+	}
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + waitWriteSlack))
+	return timeout, true
+}
+
+// WaitWorkflow handles GET /api/v1/workflows/{id}/wait. It responds with the
+// status of the workflow once it completes, fails or is cancelled, or with
+// its current status when the timeout query parameter (default 30s, at most
+// 5m) elapses first.
+func (h *WorkflowHandler) WaitWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Workflow ID is required", getRequestID(ctx))
+		return
+	}
+	timeout, ok := waitTimeout(w, r)
+	if !ok {
+		return
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status, err := h.engine.WaitWorkflow(waitCtx, workflowID)
+	if err != nil {
+		if ctx.Err() != nil {
+			// The client went away.
+			return
+		}
+		if status != nil && errors.Is(err, context.DeadlineExceeded) {
+			response.JSON(w, http.StatusOK, status)
+			return
+		}
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to wait for workflow", "id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to wait for workflow")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, status)
+}
+
+// workflowStreamChunk is the number of NDJSON lines written between flushes
+// of a workflow stream.
+const workflowStreamChunk = 100
+
+// ListWorkflows handles GET /api/v1/workflows. Requests that accept
+// application/x-ndjson get every matching workflow streamed instead of a page.
 func (h *WorkflowHandler) ListWorkflows(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	stream := response.WantsNDJSON(r)
 
 	// Parse query parameters
 	filter := models.WorkflowFilter{
@@ -143,6 +293,9 @@ func (h *WorkflowHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 		Limit:  10,
 		Offset: 0,
 	}
+	if stream {
+		filter.Limit = 0
+	}
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil {
@@ -156,6 +309,11 @@ func (h *WorkflowHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if stream {
+		h.streamWorkflows(w, r, filter)
+		return
+	}
+
 	// Get workflows from engine
 	workflows, total, err := h.engine.ListWorkflowsResponse(ctx, filter)
 	if err != nil {
@@ -167,14 +325,7 @@ func (h *WorkflowHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 	// Build response
 	summaries := make([]models.WorkflowSummary, 0, len(workflows))
 	for _, wf := range workflows {
-		summaries = append(summaries, models.WorkflowSummary{
-			ID:          wf.ID,
-			Name:        wf.Name,
-			Status:      wf.Status,
-			CreatedAt:   wf.CreatedAt,
-			CompletedAt: wf.CompletedAt,
-			TaskCount:   len(wf.Tasks),
-		})
+		summaries = append(summaries, workflowSummary(wf))
 	}
 
 	resp := models.WorkflowListResponse{
@@ -187,16 +338,40 @@ func (h *WorkflowHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// streamWorkflows writes the workflows matching filter as NDJSON, one
+// WorkflowSummary per line. The status is sent before the first workflow is
+// read, so a failure is reported as a final error line.
+func (h *WorkflowHandler) streamWorkflows(w http.ResponseWriter, r *http.Request, filter models.WorkflowFilter) {
+	ctx := r.Context()
+	stream := response.NewNDJSONWriter(w, workflowStreamChunk)
+
+	err := h.engine.StreamWorkflows(ctx, filter, func(wf *models.WorkflowStatusResponse) error {
+		return stream.Encode(workflowSummary(wf))
+	})
+	if err == nil {
+		_ = stream.Flush()
+		return
+	}
+	if ctx.Err() != nil {
+		// The client went away; there is nobody left to tell.
+		return
+	}
+	h.logger.Error("Failed to stream workflows", "error", err)
+	_ = stream.Error(response.ErrCodeInternalServer, "Failed to stream workflows", getRequestID(ctx))
+}
+
+func workflowSummary(wf *models.WorkflowStatusResponse) models.WorkflowSummary {
+	return models.WorkflowSummary{
+		ID:          wf.ID,
+		Name:        wf.Name,
+		Status:      wf.Status,
+		CreatedAt:   wf.CreatedAt,
+		CompletedAt: wf.CompletedAt,
+		TaskCount:   len(wf.Tasks),
+	}
+}
+
 // CancelWorkflow handles POST /api/v1/workflows/{id}/cancel
-// @Summary Cancel a workflow
-// @Description Cancel a running or pending workflow
-// @Tags workflows
-// @Produce json
-// @Param id path string true "Workflow ID"
-// @Success 200 {object} map[string]string "Workflow cancelled successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid workflow ID"
-// @Failure 409 {object} response.ErrorResponse "Workflow cannot be cancelled"
-// @Router /api/v1/workflows/{id}/cancel [post]
 func (h *WorkflowHandler) CancelWorkflow(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workflowID := chi.URLParam(r, "id")
@@ -223,17 +398,185 @@ func (h *WorkflowHandler) CancelWorkflow(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// RetryWorkflow handles POST /api/v1/workflows/{id}/retry
+func (h *WorkflowHandler) RetryWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Workflow ID is required", getRequestID(ctx))
+		return
+	}
+
+	statusResp, err := h.engine.RetryWorkflowRequest(ctx, workflowID)
+	if err != nil {
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to retry workflow", "id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to retry workflow")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, models.WorkflowResponse{
+		ID:        statusResp.ID,
+		Name:      statusResp.Name,
+		Status:    statusResp.Status,
+		CreatedAt: statusResp.CreatedAt,
+		Message:   "Workflow resubmitted from " + workflowID,
+	})
+}
+
+// AppendTasks handles PATCH /api/v1/workflows/{id}/tasks
+func (h *WorkflowHandler) AppendTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Workflow ID is required", getRequestID(ctx))
+		return
+	}
+
+	var req models.WorkflowTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request", "error", err)
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		h.logger.Error("Validation failed", "error", err)
+		writeValidationError(w, ctx, err)
+		return
+	}
+
+	statusResp, err := h.engine.AppendWorkflowTasks(ctx, workflowID, req.Tasks)
+	if err != nil {
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to append workflow tasks", "id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to append workflow tasks")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, statusResp)
+}
+
+// PlanWorkflow handles POST /api/v1/workflows:plan. It submits the planner
+// task, whose proposed workflow is stored in the planned status for approval.
+func (h *WorkflowHandler) PlanWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.PlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request", "error", err)
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		h.logger.Error("Validation failed", "error", err)
+		writeValidationError(w, ctx, err)
+		return
+	}
+
+	statusResp, err := h.engine.PlanWorkflow(ctx, &req)
+	if err != nil {
+		h.logger.Error("Failed to submit planner", "error", err)
+		writeError(w, ctx, err, "Failed to submit planner")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, models.WorkflowResponse{
+		ID:        statusResp.ID,
+		Name:      statusResp.Name,
+		Status:    statusResp.Status,
+		CreatedAt: statusResp.CreatedAt,
+		Message:   "Planner submitted successfully",
+	})
+}
+
+// ApproveWorkflow handles POST /api/v1/workflows/{id}/approve. It starts a
+// planned workflow; rejecting one is cancelling it.
+func (h *WorkflowHandler) ApproveWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Workflow ID is required", getRequestID(ctx))
+		return
+	}
+
+	statusResp, err := h.engine.ApproveWorkflow(ctx, workflowID)
+	if err != nil {
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to approve workflow", "id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to approve workflow")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, statusResp)
+}
+
+// Task page size bounds for ListTasks.
+const (
+	defaultTaskPageSize = 50
+	maxTaskPageSize     = 1000
+)
+
+// ListTasks handles GET /api/v1/workflows/{id}/tasks
+func (h *WorkflowHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	if workflowID == "" {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Workflow ID is required", getRequestID(ctx))
+		return
+	}
+
+	filter := models.TaskFilter{
+		Status: r.URL.Query().Get("status"),
+		Limit:  defaultTaskPageSize,
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = min(limit, maxTaskPageSize)
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset > 0 {
+			filter.Offset = offset
+		}
+	}
+
+	tasks, total, err := h.engine.ListWorkflowTasksResponse(ctx, workflowID, filter)
+	if err != nil {
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to list tasks", "workflow_id", workflowID, "error", err)
+		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, "Failed to list tasks", getRequestID(ctx))
+		return
+	}
+
+	response.JSON(w, http.StatusOK, models.TaskListResponse{
+		Tasks:  tasks,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
 // GetTaskResult handles GET /api/v1/workflows/{id}/tasks/{tid}/result
-// @Summary Get task result
-// @Description Get the result of a specific task within a workflow
-// @Tags workflows
-// @Produce json
-// @Param id path string true "Workflow ID"
-// @Param tid path string true "Task ID"
-// @Success 200 {object} models.TaskResultResponse "Task result"
-// @Failure 400 {object} response.ErrorResponse "Invalid workflow ID or task ID"
-// @Failure 404 {object} response.ErrorResponse "Task result not found"
-// @Router /api/v1/workflows/{id}/tasks/{tid}/result [get]
 func (h *WorkflowHandler) GetTaskResult(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workflowID := chi.URLParam(r, "id")
@@ -260,7 +603,149 @@ func (h *WorkflowHandler) GetTaskResult(w http.ResponseWriter, r *http.Request)
 	response.JSON(w, http.StatusOK, result)
 }
 
+// GetTaskLogs handles GET /api/v1/workflows/{id}/tasks/{tid}/logs
+func (h *WorkflowHandler) GetTaskLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	taskID := chi.URLParam(r, "tid")
+
+	after := 0
+	if afterStr := r.URL.Query().Get("after"); afterStr != "" {
+		n, err := strconv.Atoi(afterStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "after must be a non-negative integer", getRequestID(ctx))
+			return
+		}
+		after = n
+	}
+
+	lines, err := h.engine.TaskLogs(ctx, workflowID, taskID, after)
+	if err != nil {
+		var notFoundErr *storage.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Task not found", getRequestID(ctx))
+			return
+		}
+		h.logger.Error("Failed to get task logs", "workflow_id", workflowID, "task_id", taskID, "error", err)
+		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, "Failed to get task logs", getRequestID(ctx))
+		return
+	}
+
+	resp := models.TaskLogsResponse{Lines: make([]models.TaskLogLine, 0, len(lines)), Next: after}
+	for _, line := range lines {
+		resp.Lines = append(resp.Lines, models.TaskLogLine{Seq: line.Seq, Time: line.Time, Stream: line.Stream, Text: line.Text})
+		resp.Next = line.Seq
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// ListWorkflowValues handles GET /api/v1/workflows/{id}/values
+func (h *WorkflowHandler) ListWorkflowValues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	values, err := h.engine.WorkflowValues(ctx, workflowID)
+	if err != nil {
+		h.logger.Error("Failed to get workflow values", "workflow_id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to get workflow values")
+		return
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	response.JSON(w, http.StatusOK, models.WorkflowValuesResponse{WorkflowID: workflowID, Values: values})
+}
+
+// GetWorkflowValue handles GET /api/v1/workflows/{id}/values/{key}
+func (h *WorkflowHandler) GetWorkflowValue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	key := chi.URLParam(r, "key")
+
+	values, err := h.engine.WorkflowValues(ctx, workflowID)
+	if err != nil {
+		h.logger.Error("Failed to get workflow values", "workflow_id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to get workflow value")
+		return
+	}
+	value, ok := values[key]
+	if !ok {
+		response.Error(w, http.StatusNotFound, response.ErrCodeNotFound, "Workflow value not found", getRequestID(ctx))
+		return
+	}
+	response.JSON(w, http.StatusOK, models.WorkflowValue{Key: key, Value: value})
+}
+
+// PutWorkflowValue handles PUT /api/v1/workflows/{id}/values/{key}
+func (h *WorkflowHandler) PutWorkflowValue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	key := chi.URLParam(r, "key")
+
+	var req models.WorkflowValueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, response.ErrCodeBadRequest, "Invalid request body", getRequestID(ctx))
+		return
+	}
+	if err := validateRequest(h.validator, &req); err != nil {
+		writeValidationError(w, ctx, err)
+		return
+	}
+
+	if err := h.engine.PutWorkflowValue(ctx, workflowID, key, req.Value); err != nil {
+		h.logger.Error("Failed to set workflow value", "workflow_id", workflowID, "key", key, "error", err)
+		writeError(w, ctx, err, "Failed to set workflow value")
+		return
+	}
+	response.JSON(w, http.StatusOK, models.WorkflowValue{Key: key, Value: req.Value})
+}
+
+// DeleteWorkflowValue handles DELETE /api/v1/workflows/{id}/values/{key}
+func (h *WorkflowHandler) DeleteWorkflowValue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+	key := chi.URLParam(r, "key")
+
+	if err := h.engine.DeleteWorkflowValue(ctx, workflowID, key); err != nil {
+		h.logger.Error("Failed to delete workflow value", "workflow_id", workflowID, "key", key, "error", err)
+		writeError(w, ctx, err, "Failed to delete workflow value")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWorkflowLineage handles GET /api/v1/workflows/{id}/lineage
+func (h *WorkflowHandler) GetWorkflowLineage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workflowID := chi.URLParam(r, "id")
+
+	depth := 0
+	if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+		if d, err := strconv.Atoi(depthStr); err == nil && d > 0 {
+			depth = d
+		}
+	}
+
+	graph, err := h.engine.WorkflowLineage(ctx, workflowID, depth)
+	if err != nil {
+		h.logger.Error("Failed to get workflow lineage", "workflow_id", workflowID, "error", err)
+		writeError(w, ctx, err, "Failed to get workflow lineage")
+		return
+	}
+	response.JSON(w, http.StatusOK, graph)
+}
+
 // getRequestID extracts request ID from context
+// writeError writes err with its errs code. Unclassified errors are reported
+// as internal errors with the given message so their text is not exposed.
+func writeError(w http.ResponseWriter, ctx context.Context, err error, internalMessage string) {
+	e := errs.From(err)
+	if e.Code == errs.Internal {
+		e = errs.Wrap(err, errs.Internal, internalMessage)
+	}
+	response.HandleError(w, e, getRequestID(ctx))
+}
+
 func getRequestID(ctx context.Context) string {
 	if reqID, ok := ctx.Value("request_id").(string); ok {
 		return reqID
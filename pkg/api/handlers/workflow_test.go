@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/goclaw/goclaw/config"
 	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/dag"
 	"github.com/goclaw/goclaw/pkg/engine"
 	"github.com/goclaw/goclaw/pkg/logger"
 )
@@ -149,6 +153,49 @@ func TestWorkflowHandler_SubmitWorkflow_AsyncFlag(t *testing.T) {
 	}
 }
 
+func TestWorkflowHandler_SubmitWorkflow_SimulateFlag(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	reqBody := models.WorkflowRequest{
+		Name:     "simulated-workflow",
+		Simulate: true,
+		Tasks: []models.TaskDefinition{
+			{ID: "task-1", Name: "First task", Type: "function"},
+			{ID: "task-2", Name: "Second task", Type: "function", DependsOn: []string{"task-1"}},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SubmitWorkflow(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("SubmitWorkflow() status = %v, want %v, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp models.WorkflowStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Status != "simulated" || resp.Simulation == nil || len(resp.Simulation.Layers) != 2 {
+		t.Fatalf("expected simulation report with two layers, got %+v", resp)
+	}
+	if _, err := eng.GetWorkflowStatusResponse(context.Background(), resp.ID); err == nil {
+		t.Fatal("expected simulated workflow not to be persisted")
+	}
+}
+
 func TestWorkflowHandler_SubmitWorkflow_InvalidJSON(t *testing.T) {
 	eng, cleanup := createTestEngine(t)
 	defer cleanup()
@@ -279,6 +326,75 @@ func TestWorkflowHandler_GetWorkflow_NotFound(t *testing.T) {
 	}
 }
 
+func TestWorkflowHandler_GetWorkflow_SummaryAndTaskPages(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	reqBody := models.WorkflowRequest{Name: "test-workflow"}
+	for _, id := range []string{"task-1", "task-2", "task-3"} {
+		reqBody.Tasks = append(reqBody.Tasks, models.TaskDefinition{ID: id, Name: id, Type: "http"})
+	}
+	workflowID, err := eng.SubmitWorkflowRequest(context.Background(), &reqBody)
+	if err != nil {
+		t.Fatalf("Failed to submit workflow: %v", err)
+	}
+
+	serve := func(fn http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", workflowID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w
+	}
+
+	w := serve(handler.GetWorkflow, "/api/v1/workflows/"+workflowID+"?view=summary")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetWorkflow(summary) status = %v, body: %s", w.Code, w.Body.String())
+	}
+	var status models.WorkflowStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(status.Tasks) != 0 || status.TaskSummary == nil || status.TaskSummary.Counts["pending"] != 3 {
+		t.Fatalf("expected a summary of 3 pending tasks, got %+v", status)
+	}
+
+	if w := serve(handler.GetWorkflow, "/api/v1/workflows/"+workflowID+"?view=compact"); w.Code != http.StatusBadRequest {
+		t.Errorf("GetWorkflow(compact) status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	w = serve(handler.ListTasks, "/api/v1/workflows/"+workflowID+"/tasks?limit=2&offset=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListTasks() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	var page models.TaskListResponse
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if page.Total != 3 || len(page.Tasks) != 2 || page.Tasks[0].ID != "task-2" || page.Limit != 2 || page.Offset != 1 {
+		t.Fatalf("unexpected task page: %+v", page)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows/missing/tasks", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "missing")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	handler.ListTasks(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("ListTasks(missing) status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
 func TestWorkflowHandler_GetWorkflow_MissingID(t *testing.T) {
 	eng, cleanup := createTestEngine(t)
 	defer cleanup()
@@ -446,6 +562,249 @@ func TestWorkflowHandler_ListWorkflows_WithPagination(t *testing.T) {
 	}
 }
 
+func TestWorkflowHandler_ListWorkflows_NDJSON(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	// More workflows than the default page size of 10
+	ctx := context.Background()
+	for i := 0; i < 12; i++ {
+		reqBody := models.WorkflowRequest{
+			Name:  "test-workflow",
+			Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "http"}},
+		}
+		if _, err := eng.SubmitWorkflowRequest(ctx, &reqBody); err != nil {
+			t.Fatalf("Failed to submit workflow: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{query: "", want: 12},
+		{query: "?limit=5&offset=2", want: 5},
+		{query: "?status=completed", want: 0},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows"+tc.query, nil)
+		req.Header.Set("Accept", response.ContentTypeNDJSON)
+		w := httptest.NewRecorder()
+
+		handler.ListWorkflows(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("ListWorkflows(%q) status = %v, want %v", tc.query, w.Code, http.StatusOK)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != response.ContentTypeNDJSON {
+			t.Fatalf("ListWorkflows(%q) content type = %q", tc.query, ct)
+		}
+
+		dec := json.NewDecoder(w.Body)
+		count := 0
+		for dec.More() {
+			var summary models.WorkflowSummary
+			if err := dec.Decode(&summary); err != nil {
+				t.Fatalf("Failed to decode line %d: %v", count, err)
+			}
+			if summary.ID == "" || summary.TaskCount != 1 {
+				t.Fatalf("unexpected summary line: %+v", summary)
+			}
+			count++
+		}
+		if count != tc.want {
+			t.Errorf("ListWorkflows(%q) streamed %d workflows, want %d", tc.query, count, tc.want)
+		}
+	}
+}
+
+func TestWorkflowHandler_WaitWorkflow(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	ctx := context.Background()
+	workflowID, err := eng.SubmitWorkflowRequest(ctx, &models.WorkflowRequest{
+		Name:  "test-workflow",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "http"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit workflow: %v", err)
+	}
+
+	wait := func(id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows/"+id+"/wait"+query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.WaitWorkflow(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) models.WorkflowStatusResponse {
+		t.Helper()
+		var resp models.WorkflowStatusResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	// The current status is returned when the timeout elapses first.
+	w := wait(workflowID, "?timeout=50ms")
+	if w.Code != http.StatusOK {
+		t.Fatalf("WaitWorkflow() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	if resp := decode(w); resp.Status != "pending" {
+		t.Fatalf("WaitWorkflow() after timeout status = %q, want pending", resp.Status)
+	}
+
+	// A waiting request returns as soon as the workflow finishes.
+	done := make(chan *httptest.ResponseRecorder, 1)
+	start := time.Now()
+	go func() { done <- wait(workflowID, "?timeout=60s") }()
+	time.Sleep(50 * time.Millisecond)
+	if err := eng.CancelWorkflowRequest(ctx, workflowID); err != nil {
+		t.Fatalf("Failed to cancel workflow: %v", err)
+	}
+	select {
+	case w := <-done:
+		if resp := decode(w); w.Code != http.StatusOK || resp.Status != "cancelled" {
+			t.Fatalf("WaitWorkflow() = %v %q, want 200 cancelled", w.Code, resp.Status)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("WaitWorkflow() returned after %v", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitWorkflow() did not return after the workflow was cancelled")
+	}
+
+	if w := wait("nonexistent", ""); w.Code != http.StatusNotFound {
+		t.Errorf("WaitWorkflow() with nonexistent ID status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+	for _, query := range []string{"?timeout=soon", "?timeout=-1s", "?timeout=1h"} {
+		if w := wait(workflowID, query); w.Code != http.StatusBadRequest {
+			t.Errorf("WaitWorkflow(%s) status = %v, want %v", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// executeTestExecutor fails the task "fail", runs the task "slow" until it
+// is cancelled, and has the other tasks return their IDs.
+type executeTestExecutor struct{}
+
+func (executeTestExecutor) TaskFunc(_ string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		switch task.ID {
+		case "fail":
+			return errors.New("boom")
+		case "slow":
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return engine.SetTaskResult(ctx, map[string]string{"task": task.ID})
+	}
+}
+
+func TestWorkflowHandler_ExecuteWorkflow(t *testing.T) {
+	cfg := &config.Config{
+		Orchestration: config.OrchestrationConfig{MaxAgents: 10},
+	}
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	eng, err := engine.New(cfg, log, memory.NewMemoryStorage(), engine.WithTaskExecutor("script", executeTestExecutor{}))
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+	handler := NewWorkflowHandler(eng, log)
+
+	execute := func(query string, req models.WorkflowRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		handler.ExecuteWorkflow(w, httptest.NewRequest(http.MethodPost, "/api/v1/workflows:execute"+query, bytes.NewReader(body)))
+		return w
+	}
+
+	w := execute("", models.WorkflowRequest{
+		Name: "composite",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "script"},
+			{ID: "b", Name: "b", Type: "script", DependsOn: []string{"a"}},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("ExecuteWorkflow() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	var resp models.WorkflowExecuteResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Status != "completed" || len(resp.Outputs) != 2 || len(resp.Errors) != 0 {
+		t.Fatalf("ExecuteWorkflow() = %+v, want completed with two outputs", resp)
+	}
+	if output, ok := resp.Outputs["b"].(map[string]interface{}); !ok || output["task"] != "b" {
+		t.Fatalf("output of b = %#v", resp.Outputs["b"])
+	}
+
+	w = execute("", models.WorkflowRequest{
+		Name:  "failing",
+		Tasks: []models.TaskDefinition{{ID: "fail", Name: "fail", Type: "script"}},
+	})
+	resp = models.WorkflowExecuteResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Status != "failed" || resp.Errors["fail"] == "" {
+		t.Fatalf("ExecuteWorkflow() of a failing workflow = %+v", resp)
+	}
+
+	// A run outlasting the timeout is cancelled.
+	w = execute("?timeout=100ms", models.WorkflowRequest{
+		Name:  "slow",
+		Tasks: []models.TaskDefinition{{ID: "slow", Name: "slow", Type: "script"}},
+	})
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("ExecuteWorkflow() of a slow workflow status = %v, body: %s", w.Code, w.Body.String())
+	}
+	var errResp response.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("Failed to decode error: %v", err)
+	}
+	workflowID, _ := errResp.Error.Details["workflow_id"].(string)
+	status, err := eng.WaitWorkflow(ctx, workflowID)
+	if err != nil || status.Status != "cancelled" {
+		t.Fatalf("timed out workflow = %+v, %v, want cancelled", status, err)
+	}
+
+	if w := execute("", models.WorkflowRequest{
+		Name:  "async",
+		Async: true,
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "script"}},
+	}); w.Code != http.StatusBadRequest {
+		t.Errorf("ExecuteWorkflow() of an async workflow status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestWorkflowHandler_CancelWorkflow_Success(t *testing.T) {
 	eng, cleanup := createTestEngine(t)
 	defer cleanup()
@@ -537,6 +896,56 @@ func TestWorkflowHandler_CancelWorkflow_MissingID(t *testing.T) {
 	}
 }
 
+func TestWorkflowHandler_RetryWorkflow(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	retry := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/"+id+"/retry", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.RetryWorkflow(w, req)
+		return w
+	}
+
+	if w := retry("nonexistent"); w.Code != http.StatusNotFound {
+		t.Fatalf("RetryWorkflow() status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+
+	reqBody := models.WorkflowRequest{
+		Name:  "test-workflow",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "http"}},
+	}
+	workflowID, err := eng.SubmitWorkflowRequest(context.Background(), &reqBody)
+	if err != nil {
+		t.Fatalf("Failed to submit workflow: %v", err)
+	}
+	if err := eng.CancelWorkflowRequest(context.Background(), workflowID); err != nil {
+		t.Fatalf("Failed to cancel workflow: %v", err)
+	}
+
+	w := retry(workflowID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("RetryWorkflow() status = %v, want %v, body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var resp models.WorkflowResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if resp.ID == "" || resp.ID == workflowID || resp.Name != "test-workflow" {
+		t.Fatalf("unexpected retry response: %+v", resp)
+	}
+}
+
 func TestWorkflowHandler_GetTaskResult_NotFound(t *testing.T) {
 	eng, cleanup := createTestEngine(t)
 	defer cleanup()
@@ -651,3 +1060,239 @@ func TestWorkflowHandler_GetTaskResult_NonTerminalPending(t *testing.T) {
 		t.Fatalf("expected nil result for non-terminal task, got %#v", resp.Result)
 	}
 }
+
+func TestWorkflowHandler_GetTaskLogs(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	workflowID, err := eng.SubmitWorkflowRequest(context.Background(), &models.WorkflowRequest{
+		Name:  "task-logs",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "function"}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		taskID string
+		after  string
+		want   int
+	}{
+		{name: "no output yet", taskID: "task-1", after: "", want: http.StatusOK},
+		{name: "invalid after", taskID: "task-1", after: "-1", want: http.StatusBadRequest},
+		{name: "unknown task", taskID: "missing", after: "", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows/"+workflowID+"/tasks/"+tt.taskID+"/logs?after="+tt.after, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", workflowID)
+			rctx.URLParams.Add("tid", tt.taskID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.GetTaskLogs(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("GetTaskLogs() status = %v, want %v, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp models.TaskLogsResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Lines == nil || len(resp.Lines) != 0 || resp.Next != 0 {
+				t.Fatalf("GetTaskLogs() = %+v, want no lines", resp)
+			}
+		})
+	}
+}
+
+func TestWorkflowHandler_WorkflowValues(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	release := make(chan struct{})
+	status, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "values",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "function"}},
+	}, engine.SubmitWorkflowOptions{
+		Mode: engine.SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"task-1": func(context.Context) error {
+				<-release
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	serve := func(method, key, body string, fn http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/workflows/"+status.ID+"/values/"+key, bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", status.ID)
+		rctx.URLParams.Add("key", key)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodPut, "ready", `{"value":"yes"}`, handler.PutWorkflowValue); w.Code != http.StatusOK {
+		t.Fatalf("PutWorkflowValue() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	w := serve(http.MethodGet, "ready", "", handler.GetWorkflowValue)
+	var value models.WorkflowValue
+	if err := json.NewDecoder(w.Body).Decode(&value); err != nil || value.Value != "yes" {
+		t.Fatalf("GetWorkflowValue() = %+v, %v", value, err)
+	}
+	if w := serve(http.MethodDelete, "ready", "", handler.DeleteWorkflowValue); w.Code != http.StatusNoContent {
+		t.Fatalf("DeleteWorkflowValue() status = %v, body: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "ready", "", handler.GetWorkflowValue); w.Code != http.StatusNotFound {
+		t.Fatalf("GetWorkflowValue() after delete status = %v", w.Code)
+	}
+	if w := serve(http.MethodPut, "ready", `{`, handler.PutWorkflowValue); w.Code != http.StatusBadRequest {
+		t.Fatalf("PutWorkflowValue() with invalid body status = %v", w.Code)
+	}
+
+	close(release)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := eng.GetWorkflowStatusResponse(context.Background(), status.ID)
+		if err == nil && resp.Status == "completed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("workflow did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if w := serve(http.MethodPut, "ready", `{"value":"no"}`, handler.PutWorkflowValue); w.Code != http.StatusConflict {
+		t.Fatalf("expected values of a finished run to be read-only, got status %v", w.Code)
+	}
+	w = serve(http.MethodGet, "", "", handler.ListWorkflowValues)
+	var list models.WorkflowValuesResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || list.Values == nil || len(list.Values) != 0 {
+		t.Fatalf("ListWorkflowValues() = %+v, %v", list, err)
+	}
+}
+
+func TestWorkflowHandler_AppendTasks(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	ctx := context.Background()
+	workflowID, err := eng.SubmitWorkflowRequest(ctx, &models.WorkflowRequest{
+		Name:  "test-workflow",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "http"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit workflow: %v", err)
+	}
+
+	appendTasks := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/workflows/"+id+"/tasks", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.AppendTasks(w, req)
+		return w
+	}
+
+	task := `{"tasks": [{"id": "task-2", "name": "Second task", "type": "http", "depends_on": ["task-1"]}]}`
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{name: "invalid body", id: workflowID, body: "{", want: http.StatusBadRequest},
+		{name: "no tasks", id: workflowID, body: `{"tasks": []}`, want: http.StatusBadRequest},
+		{name: "unknown workflow", id: "missing", body: task, want: http.StatusNotFound},
+		{name: "workflow not running", id: workflowID, body: task, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := appendTasks(tt.id, tt.body); w.Code != tt.want {
+				t.Fatalf("AppendTasks() status = %v, want %v, body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkflowHandler_PlanAndApprove(t *testing.T) {
+	eng, cleanup := createTestEngine(t)
+	defer cleanup()
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+	handler := NewWorkflowHandler(eng, log)
+
+	plan := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows:plan", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		handler.PlanWorkflow(w, req)
+		return w
+	}
+	if w := plan("{"); w.Code != http.StatusBadRequest {
+		t.Fatalf("PlanWorkflow() invalid body status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+	// The test engine has no executor to run the planner.
+	if w := plan(`{"name": "research", "planner": {"id": "plan", "name": "plan", "type": "http"}}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("PlanWorkflow() status = %v, want %v, body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+
+	workflowID, err := eng.SubmitWorkflowRequest(context.Background(), &models.WorkflowRequest{
+		Name:  "test-workflow",
+		Tasks: []models.TaskDefinition{{ID: "task-1", Name: "First task", Type: "http"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit workflow: %v", err)
+	}
+	approve := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/"+id+"/approve", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.ApproveWorkflow(w, req)
+		return w
+	}
+	if w := approve("missing"); w.Code != http.StatusNotFound {
+		t.Fatalf("ApproveWorkflow() unknown workflow status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+	if w := approve(workflowID); w.Code != http.StatusConflict {
+		t.Fatalf("ApproveWorkflow() of a workflow not planned status = %v, want %v, body: %s", w.Code, http.StatusConflict, w.Body.String())
+	}
+}
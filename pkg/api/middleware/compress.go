@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+
+	defaultCompressionLevel = 5
+)
+
+// compressibleTypes lists the media types worth compressing.
+var compressibleTypes = []string{
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/",
+}
+
+// Compress returns a middleware that gzip- or deflate-encodes responses for
+// clients that advertise support in Accept-Encoding. Responses smaller than
+// cfg.MinSize, non-text responses and protocol upgrades are sent unchanged.
+func Compress(cfg *config.CompressionConfig) func(http.Handler) http.Handler {
+	level := defaultCompressionLevel
+	minSize := 0
+	enabled := cfg != nil && cfg.Enabled
+	if cfg != nil {
+		if cfg.Level != 0 {
+			level = cfg.Level
+		}
+		minSize = cfg.MinSize
+	}
+
+	pools := map[string]*sync.Pool{
+		encodingGzip: {New: func() interface{} {
+			zw, _ := gzip.NewWriterLevel(io.Discard, level)
+			return zw
+		}},
+		encodingDeflate: {New: func() interface{} {
+			zw, _ := zlib.NewWriterLevel(io.Discard, level)
+			return zw
+		}},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				minSize:        minSize,
+				code:           http.StatusOK,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// honouring q-values. gzip wins ties.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	best, bestQ := "", 0.0
+	wildcardQ := 0.0
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if q <= 0 {
+			seen[name] = true
+			continue
+		}
+
+		switch name {
+		case "*":
+			wildcardQ = q
+			continue
+		case encodingGzip, encodingDeflate:
+			seen[name] = true
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == encodingGzip) {
+			best, bestQ = name, q
+		}
+	}
+
+	if best == "" && wildcardQ > 0 {
+		for _, name := range []string{encodingGzip, encodingDeflate} {
+			if !seen[name] {
+				return name
+			}
+		}
+	}
+	return best
+}
+
+// encoder is the common interface of gzip.Writer and zlib.Writer.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// compressWriter buffers output until it can decide whether to compress,
+// then streams either compressed or identity bytes to the client.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	code        int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	encoder     encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.code = code
+	// Body-less responses are sent immediately.
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		_ = cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf.Write(p)
+		if cw.buf.Len() >= cw.minSize {
+			if err := cw.start(cw.compressible()); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered data to the client, committing to compression if the
+// response is eligible.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		_ = cw.start(cw.compressible())
+	}
+	if cw.encoder != nil {
+		_ = cw.encoder.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// start writes the status line and any buffered bytes, compressed or not.
+func (cw *compressWriter) start(compress bool) error {
+	cw.decided = true
+
+	h := cw.Header()
+	// The ETag describes the identity representation; a compressed
+	// variant is only semantically equivalent.
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	if compress {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		cw.encoder = cw.pool.Get().(encoder)
+		cw.encoder.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.code)
+
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(cw.buf.Bytes())
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+	cw.buf.Reset()
+	return err
+}
+
+// close flushes any undecided buffer uncompressed and finishes the stream.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// The handler wrote nothing; leave the response to net/http.
+			return
+		}
+		_ = cw.start(false)
+	}
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+		cw.encoder.Reset(io.Discard)
+		cw.pool.Put(cw.encoder)
+		cw.encoder = nil
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+func jsonHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "999")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestCompress(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("x", 2048) + `"}`
+	cfg := &config.CompressionConfig{Enabled: true, Level: 5, MinSize: 1024}
+
+	tests := []struct {
+		name           string
+		cfg            *config.CompressionConfig
+		acceptEncoding string
+		body           string
+		wantEncoding   string
+	}{
+		{name: "gzip", cfg: cfg, acceptEncoding: "gzip, deflate", body: large, wantEncoding: "gzip"},
+		{name: "deflate preferred by q-value", cfg: cfg, acceptEncoding: "gzip;q=0.5, deflate", body: large, wantEncoding: "deflate"},
+		{name: "wildcard", cfg: cfg, acceptEncoding: "*", body: large, wantEncoding: "gzip"},
+		{name: "gzip refused", cfg: cfg, acceptEncoding: "gzip;q=0", body: large, wantEncoding: ""},
+		{name: "no accept-encoding", cfg: cfg, body: large, wantEncoding: ""},
+		{name: "below min size", cfg: cfg, acceptEncoding: "gzip", body: `{"ok":true}`, wantEncoding: ""},
+		{name: "disabled", cfg: &config.CompressionConfig{}, acceptEncoding: "gzip", body: large, wantEncoding: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+
+			Compress(tt.cfg)(jsonHandler(tt.body)).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+
+			var reader io.Reader = w.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				zr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader() error = %v", err)
+				}
+				reader = zr
+			case "deflate":
+				zr, err := zlib.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("zlib.NewReader() error = %v", err)
+				}
+				reader = zr
+			}
+			if tt.wantEncoding != "" && w.Header().Get("Content-Length") != "" {
+				t.Fatal("expected Content-Length to be removed from compressed response")
+			}
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(got) != tt.body {
+				t.Fatalf("body mismatch: got %d bytes, want %d", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompress_SkipsNonTextAndEncoded(t *testing.T) {
+	payload := strings.Repeat("x", 4096)
+	handlers := map[string]http.HandlerFunc{
+		"binary": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte(payload))
+		},
+		"already encoded": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte(payload))
+		},
+	}
+
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+
+			Compress(&config.CompressionConfig{Enabled: true})(h).ServeHTTP(w, req)
+
+			if got := w.Header().Get("Content-Encoding"); got == "gzip" {
+				t.Fatal("expected response not to be gzip-encoded")
+			}
+			if w.Body.String() != payload {
+				t.Fatal("expected body to pass through unchanged")
+			}
+		})
+	}
+}
+
+func TestCompress_WeakensETag(t *testing.T) {
+	h := ETag()(jsonHandler(strings.Repeat("y", 2048)))
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	Compress(&config.CompressionConfig{Enabled: true, MinSize: 1024})(h).ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("expected gzip response")
+	}
+	etag := w.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected weak ETag on compressed response, got %q", etag)
+	}
+	if vary := w.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Fatalf("Vary = %q, want Accept-Encoding", vary)
+	}
+
+	// The weak validator still matches on revalidation.
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+
+	Compress(&config.CompressionConfig{Enabled: true, MinSize: 1024})(h).ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Fatal("expected empty body on 304")
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/api/response"
+)
+
+// etagWriter captures a response so its ETag can be computed from the body.
+type etagWriter struct {
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+}
+
+func (ew *etagWriter) Header() http.Header {
+	return ew.header
+}
+
+func (ew *etagWriter) WriteHeader(statusCode int) {
+	if ew.wroteHeader {
+		return
+	}
+	ew.code = statusCode
+	ew.wroteHeader = true
+}
+
+func (ew *etagWriter) Write(p []byte) (int, error) {
+	if !ew.wroteHeader {
+		ew.WriteHeader(http.StatusOK)
+	}
+	return ew.buf.Write(p)
+}
+
+// ETag returns a middleware that adds a strong ETag, derived from the body,
+// to successful GET and HEAD responses and answers requests whose
+// If-None-Match matches it with 304 Not Modified. Streaming requests (see
+// response.WantsNDJSON) are passed through without an ETag.
+func ETag() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || response.WantsNDJSON(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ew := &etagWriter{header: make(http.Header), code: http.StatusOK}
+			next.ServeHTTP(ew, r)
+
+			for key, values := range ew.header {
+				w.Header()[key] = values
+			}
+			if ew.code != http.StatusOK {
+				w.WriteHeader(ew.code)
+				_, _ = w.Write(ew.buf.Bytes())
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				sum := sha256.Sum256(ew.buf.Bytes())
+				etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+				w.Header().Set("ETag", etag)
+			}
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(ew.code)
+			_, _ = w.Write(ew.buf.Bytes())
+		})
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using
+// the weak comparison required by RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
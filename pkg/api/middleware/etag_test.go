@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/response"
+)
+
+func TestETag(t *testing.T) {
+	body := `{"id":"wf-1","status":"running"}`
+	handler := ETag()(jsonHandler(body))
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/wf-1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if len(etag) < 3 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Fatalf("expected quoted strong ETag, got %q", etag)
+	}
+	if w.Body.String() != body {
+		t.Fatalf("body = %q, want %q", w.Body.String(), body)
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "matching", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "weak matching", ifNoneMatch: "W/" + etag, wantStatus: http.StatusNotModified},
+		{name: "list", ifNoneMatch: `"other", ` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "stale", ifNoneMatch: `"other"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/workflows/wf-1", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Header().Get("ETag") != etag {
+				t.Fatalf("ETag = %q, want %q", w.Header().Get("ETag"), etag)
+			}
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Fatal("expected empty body on 304")
+			}
+		})
+	}
+}
+
+func TestETag_SkipsErrorsAndWrites(t *testing.T) {
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("missing"))
+	})
+	req := httptest.NewRequest(http.MethodGet, "/workflows/missing", nil)
+	w := httptest.NewRecorder()
+	ETag()(notFound).ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Fatalf("expected 404 without ETag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/workflows", nil)
+	w = httptest.NewRecorder()
+	ETag()(jsonHandler(`{}`)).ServeHTTP(w, req)
+
+	if w.Header().Get("ETag") != "" {
+		t.Fatal("expected no ETag on POST")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/workflows", nil)
+	req.Header.Set("Accept", response.ContentTypeNDJSON)
+	w = httptest.NewRecorder()
+	ETag()(jsonHandler(`{}`)).ServeHTTP(w, req)
+
+	if w.Header().Get("ETag") != "" {
+		t.Fatal("expected no ETag on a streamed response")
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/goclaw/goclaw/pkg/identity"
+)
+
+// ClientIdentity returns a middleware that stores the identity of the client's
+// verified mTLS certificate in the request context, for attribution by later
+// middleware and handlers. Requests without one pass through unchanged.
+func ClientIdentity(mapper *identity.Mapper) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id := mapper.FromTLS(r.TLS); id != nil {
+				r = r.WithContext(identity.NewContext(r.Context(), id))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
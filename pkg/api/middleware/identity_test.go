@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/identity"
+)
+
+func TestClientIdentity(t *testing.T) {
+	mapper, err := identity.NewMapper([]identity.RoleMapping{
+		{SAN: "spiffe://goclaw/admin/*", Roles: []string{"admin"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMapper() error = %v", err)
+	}
+
+	var got *identity.Identity
+	handler := ClientIdentity(mapper)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = identity.FromContext(r.Context())
+	}))
+
+	// Plain HTTP requests carry no identity.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != nil {
+		t.Fatalf("expected no identity without TLS, got %+v", got)
+	}
+
+	uri, _ := url.Parse("spiffe://goclaw/admin/ops")
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "ops"}, URIs: []*url.URL{uri}}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got == nil || got.Subject != "ops" {
+		t.Fatalf("expected identity ops, got %+v", got)
+	}
+	if !got.HasRole("admin") {
+		t.Fatalf("expected admin role, got %v", got.Roles)
+	}
+}
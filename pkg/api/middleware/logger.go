@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/identity"
 	"github.com/goclaw/goclaw/pkg/logger"
 )
 
@@ -26,6 +27,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// Unwrap returns the underlying writer for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logger returns a middleware that logs HTTP requests.
 func Logger(log logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -43,7 +49,8 @@ func Logger(log logger.Logger) func(http.Handler) http.Handler {
 
 			// Log request details
 			duration := time.Since(start)
-			log.Info("HTTP request",
+			attrs := []any{
+				"request_id", GetRequestID(r.Context()),
 				"method", r.Method,
 				"path", r.URL.Path,
 				"status", wrapped.statusCode,
@@ -51,7 +58,11 @@ func Logger(log logger.Logger) func(http.Handler) http.Handler {
 				"size", wrapped.size,
 				"remote_addr", r.RemoteAddr,
 				"user_agent", r.UserAgent(),
-			)
+			}
+			if id, ok := identity.FromContext(r.Context()); ok {
+				attrs = append(attrs, "client", id.Subject)
+			}
+			log.Info("HTTP request", attrs...)
 		})
 	}
 }
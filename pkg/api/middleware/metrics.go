@@ -92,6 +92,11 @@ func (rw *metricsResponseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+// Unwrap returns the underlying writer for http.ResponseController.
+func (rw *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // normalizePath normalizes URL paths to reduce cardinality.
 // Replaces UUIDs and numeric IDs with placeholders.
 func normalizePath(path string) string {
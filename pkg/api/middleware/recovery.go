@@ -17,19 +17,21 @@ func Recovery(log logger.Logger) func(http.Handler) http.Handler {
 				if err := recover(); err != nil {
 					// Log the panic with stack trace
 					stack := debug.Stack()
+
+					// Get request ID from context if available
+					requestID := GetRequestID(r.Context())
+					if requestID == "" {
+						requestID = "unknown"
+					}
+
 					log.Error("Panic recovered",
+						"request_id", requestID,
 						"error", err,
 						"path", r.URL.Path,
 						"method", r.Method,
 						"stack", string(stack),
 					)
 
-					// Get request ID from context if available
-					requestID := r.Header.Get("X-Request-ID")
-					if requestID == "" {
-						requestID = "unknown"
-					}
-
 					// Return 500 error
 					response.Error(w,
 						http.StatusInternalServerError,
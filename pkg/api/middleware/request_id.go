@@ -4,32 +4,26 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/google/uuid"
+	"github.com/goclaw/goclaw/pkg/requestid"
 )
 
-// contextKey is a custom type for context keys to avoid collisions.
-type contextKey string
-
-const requestIDKey contextKey = "request_id"
-
 // RequestID returns a middleware that generates or extracts request IDs.
 func RequestID() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Try to get request ID from header
-			requestID := r.Header.Get("X-Request-ID")
+			requestID := r.Header.Get(requestid.Header)
 
 			// Generate new ID if not present
 			if requestID == "" {
-				requestID = uuid.New().String()
+				requestID = requestid.New()
 			}
 
 			// Add to context
-			ctx := context.WithValue(r.Context(), requestIDKey, requestID)
-			r = r.WithContext(ctx)
+			r = r.WithContext(requestid.NewContext(r.Context(), requestID))
 
 			// Add to response header
-			w.Header().Set("X-Request-ID", requestID)
+			w.Header().Set(requestid.Header, requestID)
 
 			next.ServeHTTP(w, r)
 		})
@@ -38,8 +32,5 @@ func RequestID() func(http.Handler) http.Handler {
 
 // GetRequestID extracts the request ID from context.
 func GetRequestID(ctx context.Context) string {
-	if id, ok := ctx.Value(requestIDKey).(string); ok {
-		return id
-	}
-	return ""
+	return requestid.FromContext(ctx)
 }
@@ -74,10 +74,18 @@ func (tw *timeoutWriter) writeTo(w http.ResponseWriter) {
 	_, _ = w.Write(tw.buf.Bytes())
 }
 
-// Timeout returns a middleware that enforces request timeouts.
-func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+// Timeout returns a middleware that enforces request timeouts. Streaming
+// requests (see response.WantsNDJSON), and requests for which one of exempt
+// returns true, such as long polls bounding their own wait, are not buffered
+// and run until the client goes away.
+func Timeout(timeout time.Duration, exempt ...func(*http.Request) bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if response.WantsNDJSON(r) || exempted(r, exempt) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			// Create context with timeout
 			ctx, cancel := context.WithTimeout(r.Context(), timeout)
 			defer cancel()
@@ -117,3 +125,13 @@ func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// exempted reports whether one of exempt returns true for r.
+func exempted(r *http.Request, exempt []func(*http.Request) bool) bool {
+	for _, fn := range exempt {
+		if fn(r) {
+			return true
+		}
+	}
+	return false
+}
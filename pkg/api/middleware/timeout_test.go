@@ -74,3 +74,44 @@ func TestTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeout_StreamsNDJSON(t *testing.T) {
+	handler := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream := response.NewNDJSONWriter(w, 1)
+		_ = stream.Encode(map[string]int{"n": 1})
+		time.Sleep(50 * time.Millisecond)
+		_ = stream.Encode(map[string]int{"n": 2})
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", response.ContentTypeNDJSON)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || !rec.Flushed {
+		t.Fatalf("expected a flushed 200 stream, got %d (flushed %v)", rec.Code, rec.Flushed)
+	}
+	if got, want := rec.Body.String(), "{\"n\":1}\n{\"n\":2}\n"; got != want {
+		t.Fatalf("body = %q, want %q", got, want)
+	}
+}
+
+func TestTimeout_Exempt(t *testing.T) {
+	longPoll := func(r *http.Request) bool { return r.URL.Path == "/wait" }
+	handler := Timeout(20*time.Millisecond, longPoll)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wait", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("exempt request status = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("other request status = %d, want 504", rec.Code)
+	}
+}
@@ -143,3 +143,8 @@ func (rw *tracingResponseWriter) Write(b []byte) (int, error) {
 	}
 	return rw.ResponseWriter.Write(b)
 }
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (rw *tracingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
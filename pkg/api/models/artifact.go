@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Artifact describes a file produced by a task.
+type Artifact struct {
+	// WorkflowID is the workflow the artifact belongs to.
+	WorkflowID string `json:"workflow_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+
+	// TaskID is the task that produced the artifact.
+	TaskID string `json:"task_id" example:"build"`
+
+	// Name is the file name, unique per task.
+	Name string `json:"name" example:"app.tar.gz"`
+
+	// Size is the content length in bytes.
+	Size int64 `json:"size" example:"1048576"`
+
+	// SHA256 is the hex SHA-256 checksum of the contents.
+	SHA256 string `json:"sha256"`
+
+	// ContentType is the media type given at upload.
+	ContentType string `json:"content_type,omitempty" example:"application/gzip"`
+
+	// CreatedAt is the upload time.
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the retention policy deletes the artifact.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ArtifactListResponse lists the artifacts of a workflow.
+type ArtifactListResponse struct {
+	// Artifacts are sorted by task ID and name.
+	Artifacts []Artifact `json:"artifacts"`
+
+	// Count is the number of artifacts.
+	Count int `json:"count"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// RunCost is the usage of a workflow run and its price.
+type RunCost struct {
+	// Tenant is the tenant the run is charged to, from its metadata.
+	Tenant string `json:"tenant,omitempty" example:"team-data"`
+
+	// Usage is the amount used per dimension, e.g. task_seconds:default,
+	// llm_tokens or api_calls.
+	Usage map[string]float64 `json:"usage,omitempty"`
+
+	// Total is the usage priced with the configured rates.
+	Total float64 `json:"total"`
+
+	// Currency labels Total.
+	Currency string `json:"currency" example:"USD"`
+}
+
+// Budget caps the usage of a workflow run, or of the runs of a tenant. Zero
+// fields are unlimited.
+type Budget struct {
+	// Tokens caps the llm_tokens the tasks record.
+	Tokens float64 `json:"tokens,omitempty" validate:"min=0" example:"100000"`
+
+	// Cost caps the usage priced with the configured rates.
+	Cost float64 `json:"cost,omitempty" validate:"min=0" example:"5"`
+
+	// TaskSeconds caps the wall-clock seconds tasks spend running, counting
+	// the tasks still running.
+	TaskSeconds float64 `json:"task_seconds,omitempty" validate:"min=0" example:"600"`
+}
+
+// CostGroup is the usage and cost of the runs of one tenant or workflow.
+type CostGroup struct {
+	// Key is the tenant or workflow name; runs without a tenant are grouped
+	// under an empty key.
+	Key string `json:"key"`
+
+	// Runs is the number of runs in the group.
+	Runs int `json:"runs"`
+
+	// Usage is the amount used per dimension.
+	Usage map[string]float64 `json:"usage"`
+
+	// Total is the usage priced with the configured rates.
+	Total float64 `json:"total"`
+}
+
+// CostReport aggregates the usage and cost of workflow runs for charge-back.
+type CostReport struct {
+	// GroupBy is tenant or workflow.
+	GroupBy string `json:"group_by" example:"tenant"`
+
+	// From and To bound the creation time of the runs covered, when set.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Currency labels the totals.
+	Currency string `json:"currency" example:"USD"`
+
+	// Groups are sorted by descending total.
+	Groups []CostGroup `json:"groups"`
+
+	// Total is the cost of every run covered.
+	Total float64 `json:"total"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// DrainResponse is the result of a pre-stop drain.
+type DrainResponse struct {
+	// Drained is true when no workflows were running when the drain ended.
+	Drained bool `json:"drained"`
+
+	// RunningWorkflows is the number of workflows still running.
+	RunningWorkflows int `json:"running_workflows"`
+}
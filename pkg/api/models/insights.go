@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// DurationStats summarises the recent durations of a workflow, or of one of
+// its tasks when Task is set.
+type DurationStats struct {
+	// Workflow is the workflow name.
+	Workflow string `json:"workflow" example:"nightly-etl"`
+
+	// Task is the task ID, empty for whole workflow runs.
+	Task string `json:"task,omitempty"`
+
+	// Samples is the number of recent completed runs the figures cover.
+	Samples int `json:"samples"`
+
+	// MeanMS and StdDevMS are the mean and standard deviation in milliseconds.
+	MeanMS   float64 `json:"mean_ms"`
+	StdDevMS float64 `json:"stddev_ms"`
+
+	// P50MS, P95MS and P99MS are duration percentiles in milliseconds.
+	P50MS float64 `json:"p50_ms"`
+	P95MS float64 `json:"p95_ms"`
+	P99MS float64 `json:"p99_ms"`
+
+	// Anomalies is the number of runs flagged since the node started.
+	Anomalies int `json:"anomalies"`
+}
+
+// DurationAnomaly is a completed run that took unusually long compared with
+// the recent runs of its workflow or task.
+type DurationAnomaly struct {
+	// WorkflowID is the ID of the workflow run.
+	WorkflowID string `json:"workflow_id"`
+
+	// Workflow is the workflow name.
+	Workflow string `json:"workflow"`
+
+	// Task is the task ID, empty when the whole workflow run was slow.
+	Task string `json:"task,omitempty"`
+
+	// DurationMS is how long the run took in milliseconds.
+	DurationMS float64 `json:"duration_ms"`
+
+	// MeanMS and StdDevMS describe the runs before it in milliseconds.
+	MeanMS   float64 `json:"mean_ms"`
+	StdDevMS float64 `json:"stddev_ms"`
+
+	// ZScore is the number of standard deviations above the mean.
+	ZScore float64 `json:"z_score"`
+
+	// Percentile is the share of recent runs that were faster, from 0 to 1.
+	Percentile float64 `json:"percentile"`
+
+	// Reason is the check that flagged the run: z_score or percentile.
+	Reason string `json:"reason" example:"z_score"`
+
+	// DetectedAt is when the run finished.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// InsightsResponse holds the learned durations and the recent anomalies.
+type InsightsResponse struct {
+	// Durations are sorted by workflow then task.
+	Durations []DurationStats `json:"durations"`
+
+	// Anomalies are the most recent anomalies, newest first.
+	Anomalies []DurationAnomaly `json:"anomalies"`
+
+	// CollectedAt is when the snapshot was taken.
+	CollectedAt time.Time `json:"collected_at"`
+}
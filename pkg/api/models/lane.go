@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// LaneStats is a snapshot of one lane's queue and execution statistics.
+type LaneStats struct {
+	// Name is the lane name.
+	Name string `json:"name" example:"default"`
+
+	// Pending is the number of tasks waiting in the queue.
+	Pending int `json:"pending"`
+
+	// Running is the number of tasks currently executing.
+	Running int `json:"running"`
+
+	// Completed is the total number of tasks that finished successfully.
+	Completed int64 `json:"completed"`
+
+	// Failed is the total number of tasks that returned an error.
+	Failed int64 `json:"failed"`
+
+	// Dropped is the total number of tasks dropped by backpressure.
+	Dropped int64 `json:"dropped"`
+
+	// Accepted is the total number of submissions admitted to the queue.
+	Accepted int64 `json:"accepted"`
+
+	// Rejected is the total number of submissions rejected before admission.
+	Rejected int64 `json:"rejected"`
+
+	// Redirected is the total number of submissions sent to another lane.
+	Redirected int64 `json:"redirected"`
+
+	// Capacity is the queue capacity.
+	Capacity int `json:"capacity"`
+
+	// MaxConcurrency is the number of tasks the lane runs at once.
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// Utilization is (pending + running) / (capacity + max_concurrency).
+	Utilization float64 `json:"utilization"`
+
+	// WaitTimeMS is the average queue wait in milliseconds.
+	WaitTimeMS float64 `json:"wait_time_ms"`
+
+	// WaitP50MS, WaitP95MS and WaitP99MS are recent queue wait percentiles in milliseconds.
+	WaitP50MS float64 `json:"wait_p50_ms"`
+	WaitP95MS float64 `json:"wait_p95_ms"`
+	WaitP99MS float64 `json:"wait_p99_ms"`
+
+	// ProcessTimeMS is the average execution time in milliseconds.
+	ProcessTimeMS float64 `json:"process_time_ms"`
+
+	// RateLimit is the admission rate in tasks per second, 0 when unlimited.
+	RateLimit float64 `json:"rate_limit"`
+
+	// RateLimitTokens is the number of submissions admitted right now without waiting.
+	RateLimitTokens float64 `json:"rate_limit_tokens"`
+
+	// Throttled is the total number of submissions delayed or rejected by the rate limiter.
+	Throttled int64 `json:"throttled"`
+
+	// DeadlineMissed is the total number of tasks that finished after their deadline.
+	DeadlineMissed int64 `json:"deadline_missed"`
+
+	// Resources is the lane's resource capacity, omitted when the lane does
+	// not track resources.
+	Resources *TaskResources `json:"resources,omitempty"`
+
+	// ResourcesInUse is the share of Resources held by running tasks.
+	ResourcesInUse *TaskResources `json:"resources_in_use,omitempty"`
+}
+
+// LaneListResponse lists the statistics of every lane.
+type LaneListResponse struct {
+	// Lanes are the lane statistics, sorted by name.
+	Lanes []LaneStats `json:"lanes"`
+
+	// Count is the number of lanes.
+	Count int `json:"count"`
+
+	// CollectedAt is when the statistics were read, for computing rates
+	// between successive polls.
+	CollectedAt time.Time `json:"collected_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+// Lineage edge kinds.
+const (
+	// LineageDependency links a task to a task depending on it in the same
+	// workflow.
+	LineageDependency = "dependency"
+	// LineageInput links a task to a task that read its output, such as by
+	// downloading its artifact.
+	LineageInput = "input"
+	// LineageTrigger links an upstream workflow to the workflow a trigger
+	// submitted after it completed.
+	LineageTrigger = "trigger"
+)
+
+// TaskInput is an output of another task that a task read.
+type TaskInput struct {
+	// WorkflowID is the workflow run of the task that produced the output.
+	WorkflowID string `json:"workflow_id"`
+
+	// TaskID is the task that produced the output.
+	TaskID string `json:"task_id" example:"extract"`
+
+	// Artifact is the artifact read, empty for an output read otherwise.
+	Artifact string `json:"artifact,omitempty" example:"rows.csv"`
+}
+
+// LineageGraph is where the data of a workflow run came from: its tasks,
+// the tasks and workflows whose outputs they read, transitively, and the
+// edges the data flowed along.
+type LineageGraph struct {
+	// WorkflowID is the workflow run the graph traces.
+	WorkflowID string `json:"workflow_id"`
+
+	// Nodes are the workflows and tasks of the graph.
+	Nodes []LineageNode `json:"nodes"`
+
+	// Edges lead from producers to consumers.
+	Edges []LineageEdge `json:"edges"`
+}
+
+// LineageNode is a workflow run, or one of its tasks when TaskID is set.
+type LineageNode struct {
+	// ID identifies the node in edges: the workflow ID, followed by a slash
+	// and the task ID for tasks.
+	ID string `json:"id"`
+
+	// WorkflowID is the workflow run.
+	WorkflowID string `json:"workflow_id"`
+
+	// Workflow is the workflow name, empty if the run no longer exists.
+	Workflow string `json:"workflow,omitempty"`
+
+	// TaskID is the task, empty for workflow nodes.
+	TaskID string `json:"task_id,omitempty"`
+
+	// Status is the workflow or task status, empty if the run no longer
+	// exists.
+	Status string `json:"status,omitempty"`
+
+	// Depth is how many workflows upstream of the traced one the node is.
+	Depth int `json:"depth"`
+}
+
+// LineageEdge is data flowing from one node to another.
+type LineageEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Kind is dependency, input or trigger.
+	Kind string `json:"kind" example:"input"`
+
+	// Artifact is the artifact read along input edges, if any.
+	Artifact string `json:"artifact,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// LogLevelRequest sets a log level.
+type LogLevelRequest struct {
+	// Level is debug, info, warn or error.
+	Level string `json:"level" example:"debug"`
+}
+
+// ComponentLogLevel is the level of a component logger.
+type ComponentLogLevel struct {
+	// Component is the component name.
+	Component string `json:"component" example:"lane"`
+
+	// Level is the effective level of the component.
+	Level string `json:"level" example:"debug"`
+
+	// Overridden is true when the level was set for the component rather
+	// than inherited from the server level.
+	Overridden bool `json:"overridden"`
+}
+
+// LogLevelsResponse lists the server log level and the component levels.
+type LogLevelsResponse struct {
+	// Level is the server log level.
+	Level string `json:"level" example:"info"`
+
+	// Components lists the known components, sorted by name.
+	Components []ComponentLogLevel `json:"components"`
+}
@@ -0,0 +1,373 @@
+package models
+
+import "time"
+
+// ResourceMeta identifies a managed resource and its version. Every change
+// of a resource gives it a higher version, which is also sent as the ETag
+// header, so writes can be made conditional with If-Match.
+type ResourceMeta struct {
+	// Name is the resource name, unique per kind.
+	Name string `json:"name" example:"nightly-report"`
+
+	// Version increases on every change of the resource.
+	Version int64 `json:"version" example:"42"`
+
+	// CreatedAt is when the resource was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the resource last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ResourceDeleteResponse is the response of deleting a managed resource.
+type ResourceDeleteResponse struct {
+	// Deleted is true when the resource was removed.
+	Deleted bool `json:"deleted"`
+}
+
+// TemplateResource is a named workflow that schedules submit.
+type TemplateResource struct {
+	ResourceMeta
+
+	// Spec is the workflow, in the body format of POST /api/v1/workflows.
+	Spec WorkflowRequest `json:"spec"`
+}
+
+// TemplateListResponse lists the workflow templates, sorted by name.
+type TemplateListResponse struct {
+	Items []TemplateResource `json:"items"`
+	Count int                `json:"count"`
+}
+
+// TemplateDiff compares the tasks of a workflow, typically one proposed by a
+// planner, with those of a template.
+type TemplateDiff struct {
+	// Template is the name of the template.
+	Template string `json:"template"`
+
+	// WorkflowID is the ID of the workflow.
+	WorkflowID string `json:"workflow_id"`
+
+	// Added lists the IDs of the workflow's tasks the template does not have.
+	Added []string `json:"added"`
+
+	// Removed lists the IDs of the template's tasks the workflow does not have.
+	Removed []string `json:"removed"`
+
+	// Changed lists the tasks both have that differ.
+	Changed []TaskDiff `json:"changed"`
+}
+
+// TaskDiff lists the fields in which a task of a workflow differs from the
+// task of a template with the same ID.
+type TaskDiff struct {
+	// ID is the task ID.
+	ID string `json:"id"`
+
+	// Fields are the JSON names of the differing fields, such as depends_on
+	// or config.
+	Fields []string `json:"fields"`
+}
+
+// ScheduleSpec submits a workflow template on a cron schedule.
+type ScheduleSpec struct {
+	// Cron is a five-field cron expression or an @ shorthand such as @daily.
+	Cron string `json:"cron" validate:"required" example:"0 2 * * *"`
+
+	// TimeZone is the IANA time zone Cron is evaluated in. Defaults to UTC.
+	TimeZone string `json:"time_zone,omitempty" example:"Europe/Berlin"`
+
+	// Template is the name of the workflow template to submit.
+	Template string `json:"template" validate:"required" example:"nightly-report"`
+
+	// Suspend stops submissions without deleting the schedule.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ConcurrencyPolicy is allow (the default) to submit even while the
+	// previous run is unfinished, or forbid to skip the run.
+	ConcurrencyPolicy string `json:"concurrency_policy,omitempty" validate:"omitempty,oneof=allow forbid" example:"forbid"`
+
+	// Calendars name the calendars whose holidays and blackout windows the
+	// schedule does not run in.
+	Calendars []string `json:"calendars,omitempty" example:"holidays-de"`
+
+	// CatchUpPolicy decides which runs missed while the server was not
+	// running still run: skip, run-once (the default) for the latest, or
+	// run-all, oldest first.
+	CatchUpPolicy string `json:"catch_up_policy,omitempty" validate:"omitempty,oneof=skip run-once run-all" example:"run-all"`
+
+	// SLA is the service level of every run, replacing the SLA of the
+	// template.
+	SLA *ScheduleSLA `json:"sla,omitempty"`
+}
+
+// ScheduleSLA bounds when the runs of a schedule must finish.
+type ScheduleSLA struct {
+	// MaxDuration is the number of seconds after submission by which a run
+	// must finish.
+	MaxDuration int `json:"max_duration,omitempty" validate:"omitempty,min=1,max=604800" example:"3600"`
+
+	// FinishWithin is the number of seconds after its logical date by which
+	// a run must finish, such as 21600 for a nightly run due by 06:00.
+	FinishWithin int `json:"finish_within,omitempty" validate:"omitempty,min=1,max=2678400" example:"21600"`
+}
+
+// ScheduleStatus is the observed state of a schedule.
+type ScheduleStatus struct {
+	// NextRunAt is when the schedule submits next; unset while suspended.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+
+	// LastRunAt is when the schedule last submitted its template.
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+
+	// LastWorkflowID is the workflow of the last submission.
+	LastWorkflowID string `json:"last_workflow_id,omitempty"`
+
+	// Message explains a skipped or failed submission.
+	Message string `json:"message,omitempty"`
+
+	// SLABreaches counts the runs that breached the SLA.
+	SLABreaches int `json:"sla_breaches,omitempty"`
+
+	// LastSLABreachAt is when a run last breached the SLA.
+	LastSLABreachAt *time.Time `json:"last_sla_breach_at,omitempty"`
+}
+
+// ScheduleResource is a managed schedule.
+type ScheduleResource struct {
+	ResourceMeta
+
+	Spec   ScheduleSpec   `json:"spec"`
+	Status ScheduleStatus `json:"status"`
+}
+
+// ScheduleListResponse lists the schedules, sorted by name.
+type ScheduleListResponse struct {
+	Items []ScheduleResource `json:"items"`
+	Count int                `json:"count"`
+}
+
+// TriggerSpec submits a workflow template once upstream workflows have
+// completed.
+type TriggerSpec struct {
+	// After lists the upstream workflows. The template is submitted once a
+	// run matching each of them has completed successfully since the last
+	// submission.
+	After []TriggerDependency `json:"after" validate:"required,min=1,max=10,dive"`
+
+	// Template is the name of the workflow template to submit.
+	Template string `json:"template" validate:"required" example:"publish-report"`
+
+	// Suspend stops submissions without deleting the trigger; completions
+	// while suspended are not recorded.
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// TriggerDependency matches the runs of an upstream workflow by name, by
+// labels or by both.
+type TriggerDependency struct {
+	// Workflow is the name of the upstream workflow.
+	Workflow string `json:"workflow,omitempty" validate:"required_without=Labels" example:"nightly-report"`
+
+	// Labels are metadata pairs the upstream run must all have.
+	Labels map[string]string `json:"labels,omitempty" validate:"required_without=Workflow" example:"team:data-engineering"`
+}
+
+// TriggerStatus is the observed state of a trigger.
+type TriggerStatus struct {
+	// Satisfied holds, for each dependency in After, the completed run
+	// matching it, or an empty string while it is awaited.
+	Satisfied []string `json:"satisfied"`
+
+	// LastRunAt is when the trigger last submitted its template.
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+
+	// LastWorkflowID is the workflow of the last submission.
+	LastWorkflowID string `json:"last_workflow_id,omitempty"`
+
+	// Message explains a failed submission.
+	Message string `json:"message,omitempty"`
+}
+
+// TriggerResource is a managed trigger.
+type TriggerResource struct {
+	ResourceMeta
+
+	Spec   TriggerSpec   `json:"spec"`
+	Status TriggerStatus `json:"status"`
+}
+
+// TriggerListResponse lists the triggers, sorted by name.
+type TriggerListResponse struct {
+	Items []TriggerResource `json:"items"`
+	Count int               `json:"count"`
+}
+
+// CalendarSpec lists times schedules using the calendar do not run at.
+type CalendarSpec struct {
+	// Holidays are whole days, as YYYY-MM-DD in the time zone of each
+	// schedule.
+	Holidays []string `json:"holidays,omitempty" example:"2026-12-25"`
+
+	// Blackouts are maintenance windows.
+	Blackouts []BlackoutWindow `json:"blackouts,omitempty" validate:"dive"`
+}
+
+// BlackoutWindow is a window no runs start in: once, from Start to End, or
+// for Duration from every time Cron matches.
+type BlackoutWindow struct {
+	// Start and End bound a one-off window.
+	Start *time.Time `json:"start,omitempty" validate:"required_without=Cron"`
+	End   *time.Time `json:"end,omitempty" validate:"required_with=Start"`
+
+	// Cron starts a recurring window, evaluated in the schedule's time zone.
+	Cron string `json:"cron,omitempty" example:"0 2 * * 0"`
+
+	// Duration is the length of a recurring window, such as 2h.
+	Duration string `json:"duration,omitempty" validate:"required_with=Cron" example:"2h"`
+}
+
+// CalendarResource is a managed calendar.
+type CalendarResource struct {
+	ResourceMeta
+
+	Spec CalendarSpec `json:"spec"`
+}
+
+// CalendarListResponse lists the calendars, sorted by name.
+type CalendarListResponse struct {
+	Items []CalendarResource `json:"items"`
+	Count int                `json:"count"`
+}
+
+// WebhookSpec delivers workflow events to a URL.
+type WebhookSpec struct {
+	// URL receives a POST with the JSON event for every matching event.
+	URL string `json:"url" validate:"required,url" example:"https://hooks.example.com/goclaw"`
+
+	// Events are the event types to deliver, such as
+	// workflow.state_changed; empty delivers every event.
+	Events []string `json:"events,omitempty" example:"workflow.state_changed"`
+
+	// Format is json (the default) to post the event, or slack to post a
+	// message for a Slack incoming webhook.
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=json slack" example:"slack"`
+
+	// Secret signs deliveries: the X-Goclaw-Signature header carries
+	// sha256= and the hex HMAC-SHA256 of the X-Goclaw-Timestamp and
+	// X-Goclaw-Nonce headers and the body, joined with dots. It is never
+	// returned.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookResource is a managed webhook. Its spec never contains the secret.
+type WebhookResource struct {
+	ResourceMeta
+
+	Spec WebhookSpec `json:"spec"`
+
+	// HasSecret is true when deliveries are signed.
+	HasSecret bool `json:"has_secret"`
+}
+
+// WebhookListResponse lists the webhooks, sorted by name.
+type WebhookListResponse struct {
+	Items []WebhookResource `json:"items"`
+	Count int               `json:"count"`
+}
+
+// LaneSpec configures a memory lane. The capacity of an existing lane
+// cannot change.
+type LaneSpec struct {
+	// Capacity is the queue size of the lane.
+	Capacity int `json:"capacity" validate:"required,min=1" example:"100"`
+
+	// MaxConcurrency is the number of tasks the lane runs at once.
+	MaxConcurrency int `json:"max_concurrency" validate:"required,min=1" example:"4"`
+
+	// RateLimit is the number of tasks per second the lane starts; 0 is
+	// unlimited.
+	RateLimit float64 `json:"rate_limit,omitempty" validate:"min=0" example:"10"`
+}
+
+// LaneResource is a managed lane.
+type LaneResource struct {
+	ResourceMeta
+
+	Spec LaneSpec `json:"spec"`
+}
+
+// LaneResourceListResponse lists the managed lanes, sorted by name.
+type LaneResourceListResponse struct {
+	Items []LaneResource `json:"items"`
+	Count int            `json:"count"`
+}
+
+// BackfillRequest submits a schedule's template for every time its cron
+// expression matched in a past range.
+type BackfillRequest struct {
+	// Start is the first logical date of the range.
+	Start time.Time `json:"start" validate:"required" example:"2026-01-01T00:00:00Z"`
+
+	// End is the last logical date of the range, inclusive.
+	End time.Time `json:"end" validate:"required" example:"2026-01-31T00:00:00Z"`
+
+	// MaxParallel is the number of runs unfinished at once. Defaults to,
+	// and cannot exceed, manage.max_parallel_backfill.
+	MaxParallel int `json:"max_parallel,omitempty" validate:"omitempty,min=1" example:"2"`
+}
+
+// BackfillRun is one run of a backfill.
+type BackfillRun struct {
+	// LogicalDate is the schedule time the run stands for.
+	LogicalDate time.Time `json:"logical_date"`
+
+	// WorkflowID is the submitted workflow; unset until submitted.
+	WorkflowID string `json:"workflow_id,omitempty"`
+
+	// Status is pending until the run is submitted, then the workflow
+	// status; failed also covers failed submissions.
+	Status string `json:"status"`
+
+	// Error explains a failed submission.
+	Error string `json:"error,omitempty"`
+}
+
+// Backfill is a backfill of a schedule and the state of its runs.
+type Backfill struct {
+	// ID identifies the backfill.
+	ID string `json:"id"`
+
+	// Schedule is the backfilled schedule.
+	Schedule string `json:"schedule"`
+
+	// Start and End are the range of logical dates, inclusive.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// MaxParallel is the number of runs unfinished at once.
+	MaxParallel int `json:"max_parallel"`
+
+	// State is running, completed once every run finished, or cancelled.
+	State string `json:"state"`
+
+	// Total, Succeeded and Failed count the runs.
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	// Runs are the runs in logical date order.
+	Runs []BackfillRun `json:"runs"`
+
+	// CreatedAt is when the backfill started.
+	CreatedAt time.Time `json:"created_at"`
+
+	// CompletedAt is when the backfill completed or was cancelled.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// BackfillListResponse lists the backfills of a schedule, oldest first.
+type BackfillListResponse struct {
+	Items []Backfill `json:"items"`
+	Count int        `json:"count"`
+}
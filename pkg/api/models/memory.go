@@ -0,0 +1,48 @@
+package models
+
+import "github.com/goclaw/goclaw/pkg/memory"
+
+// MemoryStoreRequest represents a request to store a memory entry.
+type MemoryStoreRequest struct {
+	// Content is the text content to memorize.
+	Content string `json:"content" validate:"required" example:"The customer prefers email contact"`
+
+	// Vector is an optional precomputed embedding.
+	Vector []float32 `json:"vector,omitempty"`
+
+	// Metadata holds optional key-value pairs used for filtering.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MemoryStoreResponse is returned when a memory entry is stored.
+type MemoryStoreResponse struct {
+	// ID is the identifier of the stored entry.
+	ID string `json:"id"`
+}
+
+// MemoryDeleteRequest represents a request to delete memory entries.
+type MemoryDeleteRequest struct {
+	// IDs lists the entries to delete.
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// MemoryDeleteResponse reports how many entries were deleted.
+type MemoryDeleteResponse struct {
+	// Deleted is the number of deleted entries.
+	Deleted int `json:"deleted"`
+}
+
+// MemoryListResponse represents a paginated list of memory entries.
+type MemoryListResponse struct {
+	// Entries is the current page of entries.
+	Entries []*memory.MemoryEntry `json:"entries"`
+
+	// Total is the total number of entries in the session.
+	Total int `json:"total"`
+
+	// Limit is the maximum number of results returned.
+	Limit int `json:"limit"`
+
+	// Offset is the starting position in the result set.
+	Offset int `json:"offset"`
+}
@@ -46,6 +46,38 @@ type SagaStatusResponse struct {
 	UpdatedAt      time.Time      `json:"updated_at"`
 	StartedAt      *time.Time     `json:"started_at,omitempty"`
 	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	// Archived reports that the saga was read from the archive.
+	Archived bool `json:"archived,omitempty"`
+}
+
+// SagaArchiveResponse is an archived saga with its WAL.
+type SagaArchiveResponse struct {
+	SagaStatusResponse
+	WAL        []SagaWALEntry `json:"wal"`
+	ArchivedAt time.Time      `json:"archived_at"`
+}
+
+// SagaWALEntry is one WAL entry of an archived saga.
+type SagaWALEntry struct {
+	Sequence  uint64    `json:"sequence"`
+	StepID    string    `json:"step_id,omitempty"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SagaArchiveSummary is one row in the archive list response.
+type SagaArchiveSummary struct {
+	SagaSummary
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// SagaArchiveListResponse is a paginated list of archived sagas.
+type SagaArchiveListResponse struct {
+	Items  []SagaArchiveSummary `json:"items"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
 }
 
 // SagaSummary is one row in list response.
@@ -70,6 +102,33 @@ type SagaCompensateRequest struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// SagaCompensationPreview is the compensation plan of a saga, returned
+// without running it.
+type SagaCompensationPreview struct {
+	SagaID     string `json:"saga_id"`
+	State      string `json:"state"`
+	Policy     string `json:"policy"`
+	FailedStep string `json:"failed_step,omitempty"`
+	// CanTrigger reports whether POST /compensate would run the plan now.
+	CanTrigger bool `json:"can_trigger"`
+	MaxRetries int  `json:"max_retries"`
+	// Steps are the steps that would be compensated, in order.
+	Steps []SagaCompensationStep `json:"steps"`
+	// Skipped are the completed steps that would not be compensated.
+	Skipped []SagaCompensationStep `json:"skipped"`
+}
+
+// SagaCompensationStep is one completed step of a compensation preview.
+type SagaCompensationStep struct {
+	StepID string `json:"step_id"`
+	// Stage orders the compensations; the steps of one stage run
+	// concurrently.
+	Stage      int    `json:"stage"`
+	Policy     string `json:"policy"`
+	TimeoutMS  int64  `json:"timeout_ms"`
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
 // SagaRecoverRequest is used for manual recovery trigger.
 type SagaRecoverRequest struct{}
 
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SignalRecord is a signal recorded in a channel's history.
+type SignalRecord struct {
+	// Type is the signal type (steer, interrupt or collect).
+	Type string `json:"type" example:"steer"`
+
+	// TaskID is the task the signal was sent to.
+	TaskID string `json:"task_id" example:"task-1"`
+
+	// Payload is the signal-specific data.
+	Payload json.RawMessage `json:"payload,omitempty"`
+
+	// SentAt is when the signal was sent.
+	SentAt time.Time `json:"sent_at"`
+}
+
+// SignalHistoryResponse lists the recent signals of a channel, oldest first.
+type SignalHistoryResponse struct {
+	// Channel is the channel (task ID) the history belongs to.
+	Channel string `json:"channel" example:"task-1"`
+
+	// Signals are the recorded signals, oldest first.
+	Signals []SignalRecord `json:"signals"`
+
+	// Count is the number of returned signals.
+	Count int `json:"count"`
+}
+
+// SignalSchema is a JSON Schema that payloads of matching signals must satisfy.
+type SignalSchema struct {
+	// Channel is a glob pattern for the channels (task IDs) the schema applies to.
+	Channel string `json:"channel" example:"ingest-*"`
+
+	// Type restricts the schema to one signal type; empty applies to every type.
+	Type string `json:"type,omitempty" example:"steer"`
+
+	// Schema is the JSON Schema document.
+	Schema json.RawMessage `json:"schema"`
+
+	// UpdatedAt is when the schema was registered.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SignalSchemaRequest registers or replaces a signal payload schema.
+type SignalSchemaRequest struct {
+	// Channel is a glob pattern for the channels (task IDs) the schema applies to.
+	Channel string `json:"channel" validate:"required" example:"ingest-*"`
+
+	// Type restricts the schema to one signal type; empty applies to every type.
+	Type string `json:"type,omitempty" validate:"omitempty,oneof=steer interrupt collect" example:"steer"`
+
+	// Schema is the JSON Schema document.
+	Schema json.RawMessage `json:"schema" validate:"required"`
+}
+
+// SignalSchemaListResponse lists registered signal payload schemas.
+type SignalSchemaListResponse struct {
+	// Schemas are the schemas, ordered by channel pattern and type.
+	Schemas []SignalSchema `json:"schemas"`
+
+	// Count is the number of returned schemas.
+	Count int `json:"count"`
+}
+
+// SignalSchemaDeleteResponse reports a removed signal payload schema.
+type SignalSchemaDeleteResponse struct {
+	// Deleted is true when a schema was removed.
+	Deleted bool `json:"deleted"`
+}
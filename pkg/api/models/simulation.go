@@ -0,0 +1,69 @@
+package models
+
+// SimulationReport predicts how a workflow would execute, without running it.
+type SimulationReport struct {
+	// Layers is the layer schedule in execution order.
+	Layers []SimulatedLayer `json:"layers"`
+
+	// Tasks is the predicted schedule of every task.
+	Tasks []SimulatedTask `json:"tasks"`
+
+	// CriticalPath is the longest dependency chain of task IDs.
+	CriticalPath []string `json:"critical_path"`
+
+	// EstimatedDurationMS is the predicted wall-clock duration of the workflow.
+	EstimatedDurationMS float64 `json:"estimated_duration_ms"`
+
+	// PeakResources is the largest resource request of any layer.
+	PeakResources *TaskResources `json:"peak_resources,omitempty"`
+}
+
+// SimulatedLayer is one layer of a simulated workflow.
+type SimulatedLayer struct {
+	// Index is the layer index, starting at 0.
+	Index int `json:"index"`
+
+	// Tasks are the IDs of the tasks in the layer.
+	Tasks []string `json:"tasks"`
+
+	// StartMS is the predicted start of the layer, relative to the workflow start.
+	StartMS float64 `json:"start_ms"`
+
+	// DurationMS is the predicted duration of the layer.
+	DurationMS float64 `json:"duration_ms"`
+
+	// Resources is the sum of the resource requests of the layer's tasks.
+	Resources *TaskResources `json:"resources,omitempty"`
+}
+
+// SimulatedTask is the predicted schedule of one task.
+type SimulatedTask struct {
+	// ID is the task identifier.
+	ID string `json:"id"`
+
+	// Name is the task name.
+	Name string `json:"name"`
+
+	// Lane is the lane the task would run in.
+	Lane string `json:"lane"`
+
+	// Layer is the index of the task's layer.
+	Layer int `json:"layer"`
+
+	// StartMS is the predicted start of the task, relative to the workflow start.
+	StartMS float64 `json:"start_ms"`
+
+	// DurationMS is the estimated duration of the task.
+	DurationMS float64 `json:"duration_ms"`
+
+	// Estimate is the source of DurationMS: "history" (past runs of the task
+	// in workflows of the same name), "lane" (the lane's average processing
+	// time) or "none".
+	Estimate string `json:"estimate" example:"history"`
+
+	// Samples is the number of past runs the history estimate averages.
+	Samples int `json:"samples,omitempty"`
+
+	// Resources is the task's resource request.
+	Resources *TaskResources `json:"resources,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// Tool describes a tool available to agent tasks, from orchestration.tools.
+type Tool struct {
+	// Name is the tool name.
+	Name string `json:"name" example:"web-search"`
+
+	// Description tells planners what the tool does.
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the JSON Schema of the tool's arguments.
+	InputSchema map[string]any `json:"input_schema,omitempty"`
+
+	// Auth is how the tool authenticates: none, api_key, bearer or oauth2.
+	Auth string `json:"auth" example:"api_key"`
+
+	// Secrets name the secrets the tool's credentials come from.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// RateLimit names the rate limit the tool's calls are taken from.
+	RateLimit string `json:"rate_limit,omitempty"`
+
+	// Cost is the usage one call is expected to record per dimension.
+	Cost map[string]float64 `json:"cost,omitempty"`
+
+	// EstimatedCost is Cost priced with the configured rates, set when cost
+	// accounting is enabled.
+	EstimatedCost *float64 `json:"estimated_cost,omitempty"`
+
+	// Currency labels EstimatedCost.
+	Currency string `json:"currency,omitempty" example:"USD"`
+
+	// Tenant is the tenant Enabled applies to, empty for runs naming none.
+	Tenant string `json:"tenant,omitempty" example:"acme"`
+
+	// Enabled reports whether the tool is available to Tenant.
+	Enabled bool `json:"enabled"`
+
+	// Tenants lists the tenants the tool was enabled or disabled for through
+	// the API.
+	Tenants map[string]bool `json:"tenants,omitempty"`
+}
+
+// ToolListResponse lists the tools of orchestration.tools.
+type ToolListResponse struct {
+	Tools []Tool `json:"tools"`
+	Count int    `json:"count"`
+}
+
+// ToolTenantRequest enables or disables a tool for one tenant.
+type ToolTenantRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
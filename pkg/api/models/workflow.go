@@ -19,6 +19,102 @@ type WorkflowRequest struct {
 
 	// Async controls submission mode. When true, request returns after persistence.
 	Async bool `json:"async,omitempty"`
+
+	// Simulate compiles the workflow and returns a simulation report of its
+	// layer schedule, estimated durations and resource usage instead of
+	// running it. Nothing is persisted.
+	Simulate bool `json:"simulate,omitempty"`
+
+	// Priority orders the workflow's tasks in lanes (higher runs first). Tasks
+	// of a higher-priority workflow may preempt preemptible tasks of lower ones.
+	Priority int `json:"priority,omitempty" validate:"omitempty,min=0,max=10" example:"5"`
+
+	// Deadline is the number of seconds after submission by which the
+	// workflow's tasks should finish. Lanes in EDF dispatch mode run the task
+	// with the earliest deadline first.
+	Deadline int `json:"deadline,omitempty" validate:"omitempty,min=1,max=86400" example:"60"`
+
+	// GangLayers makes every DAG layer all-or-nothing: the layer's tasks in a
+	// lane start only once the lane has a free worker for each of them.
+	GangLayers bool `json:"gang_layers,omitempty"`
+
+	// SLA is the service level the run must meet. Breaches are reported as
+	// workflow.sla_breached events.
+	SLA *SLA `json:"sla,omitempty"`
+
+	// CallbackURL receives the final status of the run in a POST once it
+	// completes, fails or is cancelled.
+	CallbackURL string `json:"callback_url,omitempty" validate:"omitempty,http_url,max=2048" example:"https://example.com/hooks/goclaw"`
+
+	// Budget caps the usage of the run. A run exceeding it, or the budget
+	// of its tenant, fails and its unfinished tasks are cancelled. It
+	// requires cost accounting.
+	Budget *Budget `json:"budget,omitempty"`
+}
+
+// WorkflowTasksRequest appends tasks to a running workflow.
+type WorkflowTasksRequest struct {
+	// Tasks are the tasks to add. They may depend on each other and on tasks
+	// of the workflow that have not started yet or have completed.
+	Tasks []TaskDefinition `json:"tasks" validate:"required,min=1,dive"`
+}
+
+// PlanRequest submits a planner task whose result proposes a workflow. The
+// proposed workflow is stored in the planned status and runs only once
+// approved.
+type PlanRequest struct {
+	// Name is the name of the planner workflow, and of the proposed workflow
+	// unless the planner names it.
+	Name string `json:"name" validate:"required,min=1,max=100" example:"research-plan"`
+
+	// Description is an optional description of the plan.
+	Description string `json:"description,omitempty" validate:"max=500"`
+
+	// Planner is the task producing the proposed workflow as its result: a
+	// workflow request, as an object or a JSON string.
+	Planner TaskDefinition `json:"planner" validate:"required"`
+
+	// Template is the name of a workflow template the proposed workflow is
+	// compared with for review.
+	Template string `json:"template,omitempty" validate:"omitempty,max=100" example:"research"`
+
+	// Metadata holds optional key-value pairs of the planner workflow.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// SLA bounds when a workflow run must finish. Its deadline is the earlier of
+// the two bounds set.
+type SLA struct {
+	// MaxDuration is the number of seconds after submission by which the
+	// run must finish.
+	MaxDuration int `json:"max_duration,omitempty" validate:"omitempty,min=1,max=604800" example:"3600"`
+
+	// FinishBy is when the run must have finished.
+	FinishBy *time.Time `json:"finish_by,omitempty"`
+}
+
+// SLA states of a workflow run.
+const (
+	// SLAPending is an unfinished run before its SLA deadline.
+	SLAPending = "pending"
+	// SLAMet is a run that completed by its SLA deadline.
+	SLAMet = "met"
+	// SLAUnmet is a run that failed or was cancelled by its SLA deadline.
+	SLAUnmet = "unmet"
+	// SLABreached is a run unfinished at its SLA deadline.
+	SLABreached = "breached"
+)
+
+// SLAStatus reports how a workflow run fares against its SLA.
+type SLAStatus struct {
+	// Deadline is when the run must have finished.
+	Deadline time.Time `json:"deadline"`
+
+	// State is pending, met, unmet or breached.
+	State string `json:"state" example:"met"`
+
+	// BreachedAt is when the breach was detected.
+	BreachedAt *time.Time `json:"breached_at,omitempty"`
 }
 
 // TaskDefinition defines a single task in a workflow.
@@ -29,8 +125,8 @@ type TaskDefinition struct {
 	// Name is the task name.
 	Name string `json:"name" validate:"required,min=1,max=100" example:"Fetch data from API"`
 
-	// Type is the task type (e.g., "http", "script", "function").
-	Type string `json:"type" validate:"required,oneof=http script function" example:"http"`
+	// Type is the task type (e.g., "http", "script", "function", "container").
+	Type string `json:"type" validate:"required,oneof=http script function container" example:"http"`
 
 	// DependsOn lists task IDs that must complete before this task.
 	DependsOn []string `json:"depends_on,omitempty" example:"task-0"`
@@ -43,6 +139,145 @@ type TaskDefinition struct {
 
 	// Retries is the number of retry attempts on failure.
 	Retries int `json:"retries,omitempty" validate:"omitempty,min=0,max=5" example:"3"`
+
+	// DedupeKey collapses tasks with the same key into a single execution
+	// whose outcome and result are shared, within the workflow run and with
+	// later runs of the tenant until orchestration.dedupe_ttl elapses.
+	DedupeKey string `json:"dedupe_key,omitempty" validate:"omitempty,max=200" example:"fetch:https://api.example.com/users"`
+
+	// Preemptible allows the task to be cancelled and requeued when a
+	// higher-priority workflow needs its lane. The task must honour ctx.
+	Preemptible bool `json:"preemptible,omitempty"`
+
+	// Deadline is the number of seconds after workflow submission by which
+	// this task should finish. The earlier of this and the workflow deadline
+	// applies.
+	Deadline int `json:"deadline,omitempty" validate:"omitempty,min=1,max=86400" example:"30"`
+
+	// HeartbeatTimeout is the number of seconds a running task may go without
+	// a heartbeat before it counts as stalled. Remote agent tasks heartbeat
+	// automatically while their agent is alive.
+	HeartbeatTimeout int `json:"heartbeat_timeout,omitempty" validate:"omitempty,min=1,max=3600" example:"60"`
+
+	// StallPolicy is what happens to a stalled task: "mark" flags it and lets
+	// it run, "retry" (the default) abandons the attempt and retries it, and
+	// "fail" fails it immediately.
+	StallPolicy string `json:"stall_policy,omitempty" validate:"omitempty,oneof=mark retry fail" example:"retry"`
+
+	// Env names environment bundles from orchestration.environments to
+	// inject into the task; later bundles override earlier ones.
+	Env []string `json:"env,omitempty" validate:"omitempty,max=20,dive,min=1,max=100" example:"reporting"`
+
+	// Secrets maps environment variable names to secrets from
+	// orchestration.secrets to inject into the task. Secrets are resolved
+	// when the task runs and their values are redacted from task errors,
+	// events and logs.
+	Secrets map[string]string `json:"secrets,omitempty" validate:"omitempty,max=50"`
+
+	// Gang labels tasks that must start together or not at all. Tasks of a
+	// gang must be in the same DAG layer and lane.
+	Gang string `json:"gang,omitempty" validate:"omitempty,max=100" example:"training-workers"`
+
+	// AffinityKey makes tasks sharing the key, e.g. a customer ID, run one at a
+	// time in submission order in their lane, across workflows. Gang tasks
+	// cannot have one.
+	AffinityKey string `json:"affinity_key,omitempty" validate:"omitempty,max=200" example:"customer-42"`
+
+	// Locks names locks the task holds while it runs, across workflows and,
+	// with Redis, across nodes. The task waits for all of them before it
+	// starts and releases them when it finishes.
+	Locks []string `json:"locks,omitempty" validate:"omitempty,max=10,dive,min=1,max=200" example:"billing-db"`
+
+	// RateLimits names rate limits from orchestration.rate_limits; each
+	// attempt of the task takes one request from every one of them before it
+	// runs, sharing their budget with all other tasks naming them.
+	RateLimits []string `json:"rate_limits,omitempty" validate:"omitempty,max=10,dive,min=1,max=100" example:"github-api"`
+
+	// OutputSchema is a JSON Schema the task's result, set with
+	// engine.SetTaskResult, must match; a task that sets no result is
+	// checked as null. A task whose result does not match fails without
+	// being retried, so malformed data never reaches its dependents.
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+
+	// When is an expression deciding whether the task runs; a task whose
+	// condition is false is skipped and counts as completed for its
+	// dependents. Config strings may embed expressions as ${{ expr }}.
+	When string `json:"when,omitempty" validate:"omitempty,max=4096" example:"tasks.extract.result.rows > 0"`
+
+	// RetryIf is an expression deciding whether a failed attempt is retried,
+	// given its error; the task fails at once when it is false.
+	RetryIf string `json:"retry_if,omitempty" validate:"omitempty,max=4096" example:"error.contains(\"503\")"`
+
+	// Compensation undoes the task when a later task fails the workflow. A
+	// workflow with compensations runs as a saga: once it fails, the
+	// compensations of its completed tasks run in reverse dependency order.
+	Compensation *TaskCompensation `json:"compensation,omitempty"`
+
+	// Resources is the CPU, memory and GPU the task needs. The task starts
+	// only once its lane has them free; workflows with a task that needs more
+	// than its lane provides are rejected.
+	Resources *TaskResources `json:"resources,omitempty"`
+
+	// Container is the container a "container" task runs as. The task's
+	// resources are its limits.
+	Container *ContainerSpec `json:"container,omitempty" validate:"required_if=Type container"`
+
+	// Sensitive marks the task's config, including its compensation's, and
+	// its result as sensitive. They are encrypted before they are persisted
+	// when storage encryption is configured, and replaced with RedactedValue
+	// in events and in API responses to callers without a sensitive data
+	// role.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// RedactedValue replaces sensitive task configs and results the caller may
+// not read.
+const RedactedValue = "******"
+
+// ContainerSpec describes the container a task runs as.
+type ContainerSpec struct {
+	// Image is the container image reference.
+	Image string `json:"image" validate:"required,max=500" example:"python:3.12-slim"`
+
+	// Command overrides the image entrypoint.
+	Command []string `json:"command,omitempty" validate:"omitempty,max=50" example:"python"`
+
+	// Args are the arguments passed to the entrypoint.
+	Args []string `json:"args,omitempty" validate:"omitempty,max=100" example:"report.py"`
+
+	// Env sets environment variables in the container. The task's env
+	// bundles and secrets are added on top.
+	Env map[string]string `json:"env,omitempty" validate:"omitempty,max=100"`
+}
+
+// TaskCompensation is the work undoing a completed task.
+type TaskCompensation struct {
+	// Type is the agent type the compensation runs as. Empty means the
+	// task's type.
+	Type string `json:"type,omitempty" validate:"omitempty,oneof=http script function container" example:"function"`
+
+	// Config is the compensation's configuration, read with
+	// engine.TaskConfig. Strings may embed ${{ expr }} placeholders.
+	Config map[string]interface{} `json:"config,omitempty"`
+
+	// Container is the container a "container" compensation runs as.
+	Container *ContainerSpec `json:"container,omitempty"`
+
+	// Timeout is the number of seconds an attempt of the compensation may
+	// take.
+	Timeout int `json:"timeout,omitempty" validate:"omitempty,min=1,max=3600" example:"60"`
+}
+
+// TaskResources is the CPU, memory and GPU a task needs while it runs.
+type TaskResources struct {
+	// CPU is the number of CPU cores; fractions are allowed.
+	CPU float64 `json:"cpu,omitempty" validate:"omitempty,min=0" example:"0.5"`
+
+	// MemoryMB is the amount of memory in megabytes.
+	MemoryMB int64 `json:"memory_mb,omitempty" validate:"omitempty,min=0" example:"512"`
+
+	// GPU is the number of GPUs.
+	GPU int `json:"gpu,omitempty" validate:"omitempty,min=0" example:"1"`
 }
 
 // WorkflowResponse represents a workflow submission response.
@@ -63,6 +298,34 @@ type WorkflowResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// WorkflowExecuteResponse is the response of an executed workflow, with the
+// results of its tasks inline.
+type WorkflowExecuteResponse struct {
+	// ID is the workflow identifier.
+	ID string `json:"id"`
+
+	// Name is the workflow name.
+	Name string `json:"name"`
+
+	// Status is the final workflow status.
+	Status string `json:"status"`
+
+	// Outputs maps the ID of every completed task to its result.
+	Outputs map[string]interface{} `json:"outputs"`
+
+	// Errors maps the ID of every failed task to its error.
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// Error holds error information if the workflow failed.
+	Error string `json:"error,omitempty"`
+
+	// DurationMS is how long the execution took, in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
+
+	// RequestID is the ID of the request that executed the run.
+	RequestID string `json:"request_id,omitempty"`
+}
+
 // WorkflowStatusResponse represents a workflow status query response.
 type WorkflowStatusResponse struct {
 	// ID is the workflow identifier.
@@ -83,14 +346,41 @@ type WorkflowStatusResponse struct {
 	// CompletedAt is when the workflow completed.
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
-	// Tasks is the list of task statuses.
-	Tasks []TaskStatus `json:"tasks"`
+	// Tasks is the list of task statuses. It is omitted in the summary view.
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+
+	// TaskSummary aggregates the task statuses in the summary view.
+	TaskSummary *TaskSummary `json:"task_summary,omitempty"`
 
 	// Metadata holds workflow metadata.
 	Metadata map[string]string `json:"metadata,omitempty"`
 
 	// Error holds error information if the workflow failed.
 	Error string `json:"error,omitempty"`
+
+	// Priority is the workflow priority.
+	Priority int `json:"priority,omitempty"`
+
+	// Deadline is when the workflow's tasks should have finished.
+	Deadline *time.Time `json:"deadline,omitempty"`
+
+	// RequestID is the ID of the HTTP or gRPC request that submitted the run.
+	RequestID string `json:"request_id,omitempty"`
+
+	// CallbackURL receives the final status of the run.
+	CallbackURL string `json:"callback_url,omitempty"`
+
+	// SLA is the SLA status of a run with an SLA.
+	SLA *SLAStatus `json:"sla,omitempty"`
+
+	// Cost is the usage and cost of the run when cost accounting is enabled.
+	Cost *RunCost `json:"cost,omitempty"`
+
+	// Budget is the budget the run was submitted with.
+	Budget *Budget `json:"budget,omitempty"`
+
+	// Simulation is the predicted execution of a simulated submission.
+	Simulation *SimulationReport `json:"simulation,omitempty"`
 }
 
 // TaskStatus represents the status of a single task.
@@ -104,6 +394,21 @@ type TaskStatus struct {
 	// Status is the current task status.
 	Status string `json:"status"`
 
+	// Type is the task type (e.g., "http", "script", "function", "container").
+	Type string `json:"type,omitempty"`
+
+	// DependsOn lists task IDs that must complete before this task.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Config holds the task's input configuration.
+	Config map[string]interface{} `json:"config,omitempty"`
+
+	// Timeout is the maximum execution time in seconds.
+	Timeout int `json:"timeout,omitempty"`
+
+	// Retries is the number of retry attempts on failure.
+	Retries int `json:"retries,omitempty"`
+
 	// StartedAt is when the task started.
 	StartedAt *time.Time `json:"started_at,omitempty"`
 
@@ -115,6 +420,73 @@ type TaskStatus struct {
 
 	// Result holds the task result data.
 	Result interface{} `json:"result,omitempty"`
+
+	// DedupeKey is the task's idempotency key, if any.
+	DedupeKey string `json:"dedupe_key,omitempty"`
+
+	// DedupedFrom is the ID of the task whose execution this task shares,
+	// as <workflow_id>/<task_id> for a task of an earlier run.
+	DedupedFrom string `json:"deduped_from,omitempty"`
+
+	// Preemptions is how many times the task was preempted and requeued.
+	Preemptions int `json:"preemptions,omitempty"`
+
+	// DeadlineMissed reports that the task finished after its deadline.
+	DeadlineMissed bool `json:"deadline_missed,omitempty"`
+
+	// Stalls is how many times the task went without a heartbeat for longer
+	// than its heartbeat timeout.
+	Stalls int `json:"stalls,omitempty"`
+
+	// Skipped reports that the task completed without running because its
+	// condition was false.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Inputs are the outputs of other tasks the task read.
+	Inputs []TaskInput `json:"inputs,omitempty"`
+
+	// Sensitive reports that the task's config and result are sensitive, and
+	// redacted unless the caller may read them.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// TaskSummary aggregates the task statuses of a workflow.
+type TaskSummary struct {
+	// Total is the number of tasks.
+	Total int `json:"total"`
+
+	// Counts is the number of tasks per status.
+	Counts map[string]int `json:"counts"`
+
+	// RecentFailures lists the most recently failed tasks, newest first.
+	RecentFailures []TaskStatus `json:"recent_failures,omitempty"`
+}
+
+// TaskFilter defines filtering options for listing the tasks of a workflow.
+type TaskFilter struct {
+	// Status filters by task status.
+	Status string `json:"status,omitempty"`
+
+	// Limit is the maximum number of results to return.
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is the starting position in the result set.
+	Offset int `json:"offset,omitempty"`
+}
+
+// TaskListResponse represents a paginated list of the tasks of a workflow.
+type TaskListResponse struct {
+	// Tasks is the list of task statuses, ordered by task ID.
+	Tasks []TaskStatus `json:"tasks"`
+
+	// Total is the total number of tasks matching the filter.
+	Total int `json:"total"`
+
+	// Limit is the maximum number of results returned.
+	Limit int `json:"limit"`
+
+	// Offset is the starting position in the result set.
+	Offset int `json:"offset"`
 }
 
 // WorkflowListResponse represents a paginated list of workflows.
@@ -184,4 +556,56 @@ type TaskResultResponse struct {
 
 	// CompletedAt is when the task completed.
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// DedupedFrom is the ID of the task whose execution this task shares,
+	// as <workflow_id>/<task_id> for a task of an earlier run.
+	DedupedFrom string `json:"deduped_from,omitempty"`
+}
+
+// TaskLogLine is one line of output of a task.
+type TaskLogLine struct {
+	// Seq numbers the task's lines from 1.
+	Seq int `json:"seq"`
+
+	// Time is when the line was written.
+	Time time.Time `json:"time"`
+
+	// Stream is "stdout" or "stderr".
+	Stream string `json:"stream"`
+
+	// Text is the line without its newline.
+	Text string `json:"text"`
+}
+
+// TaskLogsResponse represents a page of task output.
+type TaskLogsResponse struct {
+	// Lines are the lines after the requested sequence number, oldest first.
+	Lines []TaskLogLine `json:"lines"`
+
+	// Next is the sequence number to pass as after to poll for newer lines.
+	Next int `json:"next"`
+}
+
+// WorkflowValueRequest sets a workflow value.
+type WorkflowValueRequest struct {
+	// Value is the new value of the key.
+	Value string `json:"value" validate:"max=4096" example:"ready"`
+}
+
+// WorkflowValue is one key-value pair of a workflow run.
+type WorkflowValue struct {
+	// Key is the name of the value.
+	Key string `json:"key" example:"schema_migrated"`
+
+	// Value is the value of the key.
+	Value string `json:"value" example:"ready"`
+}
+
+// WorkflowValuesResponse represents the values of a workflow run.
+type WorkflowValuesResponse struct {
+	// WorkflowID is the workflow run the values belong to.
+	WorkflowID string `json:"workflow_id"`
+
+	// Values maps keys to values.
+	Values map[string]string `json:"values"`
 }
@@ -0,0 +1,1050 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/goclaw/goclaw/pkg/api/handlers"
+	"github.com/goclaw/goclaw/pkg/api/middleware"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/api/openapi"
+	"github.com/goclaw/goclaw/pkg/api/response"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/memory"
+	"github.com/goclaw/goclaw/pkg/metrics"
+	"github.com/goclaw/goclaw/pkg/snapshot"
+)
+
+// OpenAPIPath is the path the generated OpenAPI document is served from.
+const OpenAPIPath = "/openapi.json"
+
+// apiInfo describes the HTTP API in the generated document.
+var apiInfo = openapi.Info{
+	Title:       "Goclaw API",
+	Description: "Production-grade, high-performance, distributed-ready multi-agent orchestration engine",
+	Version:     "1.0",
+}
+
+// Shared response descriptions.
+var (
+	errBadRequest          = openapi.Resp{Status: http.StatusBadRequest, Description: "Invalid request", Body: response.ErrorResponse{}}
+	errNotFound            = openapi.Resp{Status: http.StatusNotFound, Description: "Resource not found", Body: response.ErrorResponse{}}
+	errConflict            = openapi.Resp{Status: http.StatusConflict, Description: "Invalid resource state", Body: response.ErrorResponse{}}
+	errInternal            = openapi.Resp{Status: http.StatusInternalServerError, Description: "Internal server error", Body: response.ErrorResponse{}}
+	errUnavailable         = openapi.Resp{Status: http.StatusServiceUnavailable, Description: "Runtime unavailable", Body: response.ErrorResponse{}}
+	errRateLimited         = openapi.Resp{Status: http.StatusTooManyRequests, Description: "Concurrency limit reached", Body: response.ErrorResponse{}}
+	paramLimit             = openapi.Param{Name: "limit", In: openapi.InQuery, Type: "integer", Description: "Maximum number of results"}
+	paramOffset            = openapi.Param{Name: "offset", In: openapi.InQuery, Type: "integer", Description: "Offset for pagination", Default: 0}
+	paramWorkflowID        = openapi.Param{Name: "id", In: openapi.InPath, Description: "Workflow ID"}
+	paramTaskID            = openapi.Param{Name: "tid", In: openapi.InPath, Description: "Task ID"}
+	paramArtifactName      = openapi.Param{Name: "name", In: openapi.InPath, Description: "Artifact name"}
+	paramValueKey          = openapi.Param{Name: "key", In: openapi.InPath, Description: "Workflow value key"}
+	paramSagaID            = openapi.Param{Name: "id", In: openapi.InPath, Description: "Saga ID"}
+	paramSessionID         = openapi.Param{Name: "sessionID", In: openapi.InPath, Description: "Memory session ID"}
+	paramIfNoneMatch       = openapi.Param{Name: "If-None-Match", In: openapi.InHeader, Description: "ETag from a previous response"}
+	paramResourceName      = openapi.Param{Name: "name", In: openapi.InPath, Description: "Resource name"}
+	paramIfMatch           = openapi.Param{Name: "If-Match", In: openapi.InHeader, Description: "Only write if the resource is at this version (ETag), or exists for *"}
+	paramIfNoneMatchCreate = openapi.Param{Name: "If-None-Match", In: openapi.InHeader, Description: "* to only create the resource"}
+	errPreconditionFailed  = openapi.Resp{Status: http.StatusPreconditionFailed, Description: "If-Match or If-None-Match failed", Body: response.ErrorResponse{}}
+	notModified            = openapi.Resp{Status: http.StatusNotModified, Description: "Representation matches If-None-Match"}
+)
+
+// apiRoutes describes every documented route registered by RegisterRoutes.
+// Keep it in sync with the router; TestOpenAPI_MatchesRouter enforces this.
+var apiRoutes = []openapi.Route{
+	// Workflows
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows", OperationID: "submitWorkflow", Tag: "workflows",
+		Summary:     "Submit a new workflow",
+		Description: "Submit a new workflow for execution with tasks and dependencies",
+		Request:     models.WorkflowRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Workflow created successfully", Body: models.WorkflowResponse{}},
+			errBadRequest, errRateLimited, errInternal,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows:execute", OperationID: "executeWorkflow", Tag: "workflows",
+		Summary: "Execute a workflow and return its results",
+		Description: "Run the workflow synchronously and return the results of its completed tasks, and the errors " +
+			"of its failed ones, inline. A run still going when the timeout elapses is cancelled.",
+		Request: models.WorkflowRequest{},
+		Params: []openapi.Param{
+			{Name: "timeout", In: openapi.InQuery, Description: "How long the run may take, as a duration of at most 5m", Default: "30s"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow finished", Body: models.WorkflowExecuteResponse{}},
+			errBadRequest, errRateLimited, errInternal,
+			{Status: http.StatusGatewayTimeout, Description: "Workflow did not finish in time", Body: response.ErrorResponse{}},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows:plan", OperationID: "planWorkflow", Tag: "workflows",
+		Summary: "Plan a workflow for approval",
+		Description: "Submit a planner task whose result is a proposed workflow request. Once the planner completes, " +
+			"the proposed workflow is stored in the planned status, and its ID in the planner's " +
+			"goclaw.io/planned-workflow metadata. It runs only once approved.",
+		Request: models.PlanRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Planner submitted", Body: models.WorkflowResponse{}},
+			errBadRequest, errRateLimited, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows", OperationID: "listWorkflows", Tag: "workflows",
+		Summary: "List workflows",
+		Description: "List all workflows with optional filtering and pagination. With Accept: application/x-ndjson, " +
+			"every matching workflow is streamed as one summary per line (limit defaults to no limit), " +
+			"and a failure mid-stream is reported as a final error line.",
+		Params: []openapi.Param{
+			{Name: "status", In: openapi.InQuery, Description: "Filter by status"},
+			withDefault(paramLimit, 10), paramOffset, paramIfNoneMatch,
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "List of workflows", Body: models.WorkflowListResponse{}},
+			{Status: http.StatusOK, Body: models.WorkflowSummary{}, ContentType: response.ContentTypeNDJSON},
+			notModified, errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}", OperationID: "getWorkflow", Tag: "workflows",
+		Summary: "Get workflow status",
+		Description: "Get the current status and details of a specific workflow. With view=summary, " +
+			"task_summary (counts per status and the most recent failures) replaces the task list.",
+		Params: []openapi.Param{
+			paramWorkflowID,
+			{Name: "view", In: openapi.InQuery, Description: "full (default) or summary", Default: "full"},
+			paramIfNoneMatch,
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow status", Body: models.WorkflowStatusResponse{}},
+			notModified, errBadRequest, errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/wait", OperationID: "waitWorkflow", Tag: "workflows",
+		Summary: "Wait for a workflow to finish",
+		Description: "Block until the workflow completes, fails or is cancelled and return its final status. " +
+			"If the timeout elapses first, the current status is returned, so callers repeat the request " +
+			"while the status is not terminal.",
+		Params: []openapi.Param{
+			paramWorkflowID,
+			{Name: "timeout", In: openapi.InQuery, Description: "How long to wait, as a duration of at most 5m", Default: "30s"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow status", Body: models.WorkflowStatusResponse{}},
+			errBadRequest, errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows/{id}/cancel", OperationID: "cancelWorkflow", Tag: "workflows",
+		Summary:     "Cancel a workflow",
+		Description: "Cancel a running or pending workflow",
+		Params:      []openapi.Param{paramWorkflowID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow cancelled successfully", Body: map[string]string{}},
+			errBadRequest, errConflict,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows/{id}/retry", OperationID: "retryWorkflow", Tag: "workflows",
+		Summary:     "Retry a workflow",
+		Description: "Resubmit the definition of a failed or cancelled workflow as a new workflow",
+		Params:      []openapi.Param{paramWorkflowID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Workflow resubmitted", Body: models.WorkflowResponse{}},
+			errBadRequest, errNotFound, errConflict,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/workflows/{id}/approve", OperationID: "approveWorkflow", Tag: "workflows",
+		Summary:     "Approve a planned workflow",
+		Description: "Start a workflow in the planned status. A planned workflow is rejected by cancelling it",
+		Params:      []openapi.Param{paramWorkflowID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Approved workflow status", Body: models.WorkflowStatusResponse{}},
+			errBadRequest, errNotFound, errConflict, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/tasks", OperationID: "listWorkflowTasks", Tag: "workflows",
+		Summary:     "List workflow tasks",
+		Description: "List the task statuses of a workflow one page at a time, ordered by task ID",
+		Params: []openapi.Param{
+			paramWorkflowID,
+			{Name: "status", In: openapi.InQuery, Description: "Filter by task status"},
+			withDefault(paramLimit, 50), paramOffset, paramIfNoneMatch,
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Page of task statuses", Body: models.TaskListResponse{}},
+			notModified, errBadRequest, errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/v1/workflows/{id}/tasks", OperationID: "appendWorkflowTasks", Tag: "workflows",
+		Summary:     "Append tasks to a running workflow",
+		Description: "Add tasks to a running workflow. They may depend on each other and on tasks of the workflow that have not started yet or have completed; the layers not started yet are recompiled with them",
+		Params:      []openapi.Param{paramWorkflowID},
+		Request:     models.WorkflowTasksRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow status with the appended tasks", Body: models.WorkflowStatusResponse{}},
+			errBadRequest, errNotFound, errConflict, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/tasks/{tid}/result", OperationID: "getTaskResult", Tag: "workflows",
+		Summary:     "Get task result",
+		Description: "Get the result of a specific task within a workflow",
+		Params:      []openapi.Param{paramWorkflowID, paramTaskID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Task result", Body: models.TaskResultResponse{}},
+			errBadRequest, errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/tasks/{tid}/logs", OperationID: "getTaskLogs", Tag: "workflows",
+		Summary:     "Get task logs",
+		Description: "Get the most recent output lines of a task. Poll with after set to the previous response's next to follow the output",
+		Params: []openapi.Param{
+			paramWorkflowID, paramTaskID,
+			{Name: "after", In: openapi.InQuery, Type: "integer", Description: "Only return lines with a sequence number above this", Default: 0},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Task output lines", Body: models.TaskLogsResponse{}},
+			errBadRequest, errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/values", OperationID: "listWorkflowValues", Tag: "workflows",
+		Summary:     "List workflow values",
+		Description: "The key-value pairs tasks of the workflow run set, persisted with the run",
+		Params:      []openapi.Param{paramWorkflowID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow values", Body: models.WorkflowValuesResponse{}},
+			errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/values/{key}", OperationID: "getWorkflowValue", Tag: "workflows",
+		Summary:     "Get workflow value",
+		Description: "Get one value of the workflow run",
+		Params:      []openapi.Param{paramWorkflowID, paramValueKey},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Workflow value", Body: models.WorkflowValue{}},
+			errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/workflows/{id}/values/{key}", OperationID: "putWorkflowValue", Tag: "workflows",
+		Summary:     "Set workflow value",
+		Description: "Set a value of a running workflow, visible to its tasks. Values of finished runs are read-only",
+		Params:      []openapi.Param{paramWorkflowID, paramValueKey},
+		Request:     models.WorkflowValueRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Value set", Body: models.WorkflowValue{}},
+			errBadRequest, errNotFound, errConflict, errInternal,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/workflows/{id}/values/{key}", OperationID: "deleteWorkflowValue", Tag: "workflows",
+		Summary:     "Delete workflow value",
+		Description: "Remove a value of a running workflow",
+		Params:      []openapi.Param{paramWorkflowID, paramValueKey},
+		Responses: []openapi.Resp{
+			{Status: http.StatusNoContent, Description: "Value deleted"},
+			errNotFound, errConflict, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/lineage", OperationID: "getWorkflowLineage", Tag: "workflows",
+		Summary:     "Get workflow lineage",
+		Description: "The tasks of the workflow run and, traced upstream, the runs and tasks whose outputs they read: artifacts they downloaded, inputs they recorded and the runs that triggered them",
+		Params: []openapi.Param{
+			paramWorkflowID,
+			{Name: "depth", In: openapi.InQuery, Type: "integer", Description: "How many workflows upstream to trace, at most 10", Default: 3},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lineage graph", Body: models.LineageGraph{}},
+			errNotFound, errInternal,
+		},
+	},
+
+	// Artifacts
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/artifacts", OperationID: "listArtifacts", Tag: "artifacts",
+		Summary:     "List workflow artifacts",
+		Description: "Files uploaded by the workflow's tasks, with their sizes, checksums and expiry",
+		Params:      []openapi.Param{paramWorkflowID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Artifacts", Body: models.ArtifactListResponse{}},
+			errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/workflows/{id}/tasks/{tid}/artifacts/{name}", OperationID: "uploadArtifact", Tag: "artifacts",
+		Summary:            "Upload a task artifact",
+		Description:        "Store the request body as an artifact of the task, replacing any artifact of the same name",
+		Params:             []openapi.Param{paramWorkflowID, paramTaskID, paramArtifactName},
+		Request:            openapi.Binary{},
+		RequestContentType: "application/octet-stream",
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Artifact stored", Body: models.Artifact{}},
+			errBadRequest, errNotFound, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/workflows/{id}/tasks/{tid}/artifacts/{name}", OperationID: "downloadArtifact", Tag: "artifacts",
+		Summary:     "Download a task artifact",
+		Description: "Artifact contents, verified against their checksum; the X-Artifact-Sha256 header carries the checksum",
+		Params:      []openapi.Param{paramWorkflowID, paramTaskID, paramArtifactName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Artifact contents", Body: openapi.Binary{}, ContentType: "application/octet-stream"},
+			errBadRequest, errNotFound, errInternal,
+		},
+	},
+
+	// Memory
+	{
+		Method: http.MethodPost, Path: "/api/v1/memory/{sessionID}", OperationID: "storeMemory", Tag: "memory",
+		Summary: "Store a memory entry",
+		Params:  []openapi.Param{paramSessionID},
+		Request: models.MemoryStoreRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Memory stored", Body: models.MemoryStoreResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/memory/{sessionID}", OperationID: "queryMemory", Tag: "memory",
+		Summary:     "Query memory",
+		Description: "Retrieve memories ranked by relevance. Parameters named metadata.<key> filter by metadata.",
+		Params: []openapi.Param{
+			paramSessionID,
+			{Name: "query", In: openapi.InQuery, Required: true, Description: "Query text"},
+			{Name: "mode", In: openapi.InQuery, Description: "Retrieval mode: hybrid, vector or bm25"},
+			withDefault(paramLimit, 10),
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Matching memories", Body: []*memory.RetrievalResult{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/memory/{sessionID}", OperationID: "deleteMemory", Tag: "memory",
+		Summary: "Delete memory entries",
+		Params:  []openapi.Param{paramSessionID},
+		Request: models.MemoryDeleteRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Entries deleted", Body: models.MemoryDeleteResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/memory/{sessionID}/list", OperationID: "listMemory", Tag: "memory",
+		Summary: "List memory entries",
+		Params:  []openapi.Param{paramSessionID, withDefault(paramLimit, 20), paramOffset},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Memory entries", Body: models.MemoryListResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/memory/{sessionID}/stats", OperationID: "getMemoryStats", Tag: "memory",
+		Summary: "Get memory statistics",
+		Params:  []openapi.Param{paramSessionID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Memory statistics", Body: memory.MemoryStats{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/memory/{sessionID}/all", OperationID: "deleteMemorySession", Tag: "memory",
+		Summary: "Delete all memories in a session",
+		Params:  []openapi.Param{paramSessionID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Entries deleted", Body: models.MemoryDeleteResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/memory/{sessionID}/weak", OperationID: "deleteWeakMemories", Tag: "memory",
+		Summary: "Delete weak memories",
+		Params: []openapi.Param{
+			paramSessionID,
+			{Name: "threshold", In: openapi.InQuery, Type: "number", Description: "Strength below which entries are deleted"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Entries deleted", Body: models.MemoryDeleteResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+
+	// Sagas
+	{
+		Method: http.MethodPost, Path: "/api/v1/sagas", OperationID: "submitSaga", Tag: "sagas",
+		Summary:     "Submit a saga",
+		Description: "Submit a saga definition for asynchronous execution",
+		Request:     models.SagaSubmitRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusCreated, Description: "Saga accepted", Body: models.SagaSubmitResponse{}},
+			errBadRequest, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/sagas", OperationID: "listSagas", Tag: "sagas",
+		Summary:     "List sagas",
+		Description: "List saga instances with optional state filter and pagination",
+		Params: []openapi.Param{
+			{Name: "state", In: openapi.InQuery, Description: "Filter by saga state"},
+			withDefault(paramLimit, 20), paramOffset,
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Saga list", Body: models.SagaListResponse{}},
+			errInternal, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/sagas/archive", OperationID: "listArchivedSagas", Tag: "sagas",
+		Summary:     "List archived sagas",
+		Description: "List terminal sagas archived before their WAL and checkpoint were pruned",
+		Params: []openapi.Param{
+			{Name: "state", In: openapi.InQuery, Description: "Filter by terminal saga state"},
+			withDefault(paramLimit, 20), paramOffset,
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Archived saga list", Body: models.SagaArchiveListResponse{}},
+			errInternal, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/sagas/{id}/archive", OperationID: "getArchivedSaga", Tag: "sagas",
+		Summary:     "Get archived saga",
+		Description: "Get an archived saga with its WAL entries",
+		Params:      []openapi.Param{paramSagaID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Archived saga", Body: models.SagaArchiveResponse{}},
+			errBadRequest, errNotFound, errInternal, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/sagas/{id}", OperationID: "getSaga", Tag: "sagas",
+		Summary:     "Get saga status",
+		Description: "Get runtime status for a saga instance, read from the archive once it was archived",
+		Params:      []openapi.Param{paramSagaID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Saga status", Body: models.SagaStatusResponse{}},
+			errBadRequest, errNotFound, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/sagas/{id}/compensate", OperationID: "compensateSaga", Tag: "sagas",
+		Summary:         "Trigger saga compensation",
+		Description:     "Manually trigger compensation for a saga in pending-compensation state",
+		Params:          []openapi.Param{paramSagaID},
+		Request:         models.SagaCompensateRequest{},
+		RequestOptional: true,
+		Responses: []openapi.Resp{
+			{Status: http.StatusAccepted, Description: "Compensation started", Body: models.SagaActionResponse{}},
+			errBadRequest, errNotFound, errConflict, errInternal, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/sagas/{id}/compensate:preview", OperationID: "previewSagaCompensation", Tag: "sagas",
+		Summary: "Preview saga compensation",
+		Description: "Return the steps compensation would undo, in order, with their policies and timeouts, " +
+			"and the completed steps it would skip, without running it",
+		Params: []openapi.Param{paramSagaID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Compensation plan", Body: models.SagaCompensationPreview{}},
+			errBadRequest, errNotFound, errInternal, errUnavailable,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/sagas/{id}/recover", OperationID: "recoverSaga", Tag: "sagas",
+		Summary:     "Recover saga from checkpoint",
+		Description: "Resume a non-terminal saga from its latest checkpoint",
+		Params:      []openapi.Param{paramSagaID},
+		Responses: []openapi.Resp{
+			{Status: http.StatusAccepted, Description: "Recovery executed", Body: models.SagaActionResponse{}},
+			errBadRequest, errNotFound, errConflict, errInternal, errUnavailable,
+		},
+	},
+
+	// Signals
+	{
+		Method: http.MethodGet, Path: "/api/v1/signals/{channel}/history", OperationID: "getSignalHistory", Tag: "signals",
+		Summary:     "Get signal history",
+		Description: "List the most recent signals published to a channel (task ID), oldest first",
+		Params: []openapi.Param{
+			{Name: "channel", In: openapi.InPath, Description: "Signal channel (task ID)"},
+			withDefault(paramLimit, 50),
+			{Name: "since", In: openapi.InQuery, Description: "Only return signals sent at or after this RFC 3339 timestamp"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Signal history", Body: models.SignalHistoryResponse{}},
+			errBadRequest, errInternal,
+		},
+	},
+
+	{
+		Method: http.MethodGet, Path: "/api/v1/signals/schemas", OperationID: "listSignalSchemas", Tag: "signals",
+		Summary:     "List signal schemas",
+		Description: "List the JSON Schemas published signal payloads are validated against",
+		Params: []openapi.Param{
+			{Name: "channel", In: openapi.InQuery, Description: "Only list schemas applying to this channel (task ID)"},
+			{Name: "type", In: openapi.InQuery, Description: "Only list schemas applying to this signal type (used with channel)"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Signal schemas", Body: models.SignalSchemaListResponse{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/signals/schemas", OperationID: "registerSignalSchema", Tag: "signals",
+		Summary:     "Register signal schema",
+		Description: "Register or replace the JSON Schema for a channel pattern and signal type. Publishes that do not match are rejected",
+		Request:     models.SignalSchemaRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schema registered", Body: models.SignalSchema{}},
+			errBadRequest, errNotFound,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/signals/schemas", OperationID: "deleteSignalSchema", Tag: "signals",
+		Summary:     "Delete signal schema",
+		Description: "Remove the JSON Schema registered for a channel pattern and signal type",
+		Params: []openapi.Param{
+			{Name: "channel", In: openapi.InQuery, Required: true, Description: "Channel pattern the schema was registered for"},
+			{Name: "type", In: openapi.InQuery, Description: "Signal type the schema was registered for"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schema deleted", Body: models.SignalSchemaDeleteResponse{}},
+			errBadRequest, errNotFound,
+		},
+	},
+
+	// Lanes
+	{
+		Method: http.MethodGet, Path: "/api/v1/lanes", OperationID: "listLanes", Tag: "lanes",
+		Summary:     "List lane statistics",
+		Description: "Queue depth, concurrency, counters and wait time percentiles of every lane",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lane statistics", Body: models.LaneListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/lanes/{name}", OperationID: "getLane", Tag: "lanes",
+		Summary:     "Get lane statistics",
+		Description: "Queue depth, counters, wait time percentiles and rate limit state of one lane",
+		Params: []openapi.Param{
+			{Name: "name", In: openapi.InPath, Description: "Lane name"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lane statistics", Body: models.LaneStats{}},
+			errBadRequest, errNotFound,
+		},
+	},
+
+	// Insights
+	{
+		Method: http.MethodGet, Path: "/api/v1/insights", OperationID: "getInsights", Tag: "insights",
+		Summary:     "Get duration insights",
+		Description: "Learned durations per workflow and task, and the recent runs flagged as unusually slow",
+		Params: []openapi.Param{
+			{Name: "workflow", In: openapi.InQuery, Description: "Only return insights of this workflow name"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Duration insights", Body: models.InsightsResponse{}},
+		},
+	},
+
+	// Costs
+	{
+		Method: http.MethodGet, Path: "/api/v1/costs", OperationID: "getCostReport", Tag: "costs",
+		Summary:     "Get cost report",
+		Description: "Usage and cost of the workflow runs created in a time range, per tenant or per workflow name",
+		Params: []openapi.Param{
+			{Name: "group_by", In: openapi.InQuery, Description: "tenant (default) or workflow", Default: "tenant"},
+			{Name: "from", In: openapi.InQuery, Description: "Only include runs created at or after this RFC 3339 timestamp"},
+			{Name: "to", In: openapi.InQuery, Description: "Only include runs created before this RFC 3339 timestamp"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Cost report", Body: models.CostReport{}},
+			errBadRequest, errNotFound,
+		},
+	},
+
+	// Tools
+	{
+		Method: http.MethodGet, Path: "/api/v1/tools", OperationID: "listTools", Tag: "tools",
+		Summary:     "List tools",
+		Description: "Tools available to agent tasks, with their input schema, auth, rate limit and cost hints, and whether each is enabled for a tenant",
+		Params: []openapi.Param{
+			{Name: "tenant", In: openapi.InQuery, Description: "Report enablement for this tenant"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Tool list", Body: models.ToolListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/tools/{name}", OperationID: "getTool", Tag: "tools",
+		Summary: "Get tool",
+		Params: []openapi.Param{
+			{Name: "name", In: openapi.InPath, Description: "Tool name"},
+			{Name: "tenant", In: openapi.InQuery, Description: "Report enablement for this tenant"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Tool", Body: models.Tool{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/tools/{name}/tenants/{tenant}", OperationID: "setToolTenant", Tag: "tools",
+		Summary:     "Enable or disable tool for tenant",
+		Description: "Make a tool available to a tenant or not, whatever its configuration says; the override survives restarts",
+		Params: []openapi.Param{
+			{Name: "name", In: openapi.InPath, Description: "Tool name"},
+			{Name: "tenant", In: openapi.InPath, Description: "Tenant"},
+		},
+		Request: models.ToolTenantRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Tool as seen by the tenant", Body: models.Tool{}},
+			errBadRequest, errNotFound,
+		},
+	},
+
+	// Monitoring
+	{
+		Method: http.MethodGet, Path: "/api/v1/monitoring/dashboard", OperationID: "getMonitoringDashboard", Tag: "monitoring",
+		Summary:     "Generate Grafana dashboard",
+		Description: "Grafana dashboard, in the import API format, with a panel per metric family of the live registry and a variable listing the configured lanes",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Grafana dashboard", Body: metrics.GrafanaImport{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/monitoring/alerts", OperationID: "getMonitoringAlertRules", Tag: "monitoring",
+		Summary:     "Generate Prometheus alerting rules",
+		Description: "Prometheus alerting rule file tuned to the capacity of the configured lanes and to the saga settings",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Alerting rule file", Body: metrics.RuleFile{}, ContentType: handlers.ContentTypeYAML},
+			errNotFound,
+		},
+	},
+
+	// Declarative management
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/templates", OperationID: "listManagedTemplates", Tag: "manage",
+		Summary:     "List templates",
+		Description: "Workflow templates, submitted by schedules",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Templates", Body: models.TemplateListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/templates/{name}", OperationID: "getManagedTemplate", Tag: "manage",
+		Summary:     "Get template",
+		Description: "Get a template with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Template", Body: models.TemplateResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/templates/{name}", OperationID: "putManagedTemplate", Tag: "manage",
+		Summary:     "Apply template",
+		Description: "Create or replace a workflow template. The workflow is checked like a submission without running it. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.WorkflowRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Template replaced or unchanged", Body: models.TemplateResource{}},
+			{Status: http.StatusCreated, Description: "Template created", Body: models.TemplateResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/templates/{name}", OperationID: "deleteManagedTemplate", Tag: "manage",
+		Summary:     "Delete template",
+		Description: "Delete a template",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Template deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/templates/{name}/diff", OperationID: "diffManagedTemplate", Tag: "manage",
+		Summary:     "Diff a workflow against a template",
+		Description: "Compare the tasks of a workflow, such as a planned one awaiting approval, with those of the template",
+		Params: []openapi.Param{
+			paramResourceName,
+			{Name: "workflow", In: openapi.InQuery, Description: "ID of the workflow to compare", Required: true},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Tasks added, removed and changed by the workflow", Body: models.TemplateDiff{}},
+			errBadRequest, errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/schedules", OperationID: "listManagedSchedules", Tag: "manage",
+		Summary:     "List schedules",
+		Description: "Schedules submitting a template on a cron expression, with their next and last runs",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schedules", Body: models.ScheduleListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/schedules/{name}", OperationID: "getManagedSchedule", Tag: "manage",
+		Summary:     "Get schedule",
+		Description: "Get a schedule with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schedule", Body: models.ScheduleResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/schedules/{name}", OperationID: "putManagedSchedule", Tag: "manage",
+		Summary:     "Apply schedule",
+		Description: "Create or replace a schedule. A changed spec computes the next run from the time of the change. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.ScheduleSpec{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schedule replaced or unchanged", Body: models.ScheduleResource{}},
+			{Status: http.StatusCreated, Description: "Schedule created", Body: models.ScheduleResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/schedules/{name}", OperationID: "deleteManagedSchedule", Tag: "manage",
+		Summary:     "Delete schedule",
+		Description: "Delete a schedule",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Schedule deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/manage/schedules/{name}/backfills", OperationID: "startScheduleBackfill", Tag: "manage",
+		Summary:     "Backfill schedule",
+		Description: "Submit the schedule's template for every time its cron expression matched from start to end, inclusive, with the logical date of each run injected. At most max_parallel runs are unfinished at once",
+		Params:      []openapi.Param{paramResourceName},
+		Request:     models.BackfillRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusAccepted, Description: "Backfill started", Body: models.Backfill{}},
+			errBadRequest, errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/schedules/{name}/backfills", OperationID: "listScheduleBackfills", Tag: "manage",
+		Summary:     "List backfills",
+		Description: "Running and recent backfills of a schedule, oldest first, with the state of their runs",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Backfills", Body: models.BackfillListResponse{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/schedules/{name}/backfills/{id}", OperationID: "cancelScheduleBackfill", Tag: "manage",
+		Summary:     "Cancel backfill",
+		Description: "Stop submitting runs of a backfill. Submitted runs keep running",
+		Params: []openapi.Param{
+			paramResourceName,
+			{Name: "id", In: openapi.InPath, Description: "Backfill ID"},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Backfill cancelled", Body: models.Backfill{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/triggers", OperationID: "listManagedTriggers", Tag: "manage",
+		Summary:     "List triggers",
+		Description: "Triggers submitting a template once upstream workflows complete, with the completions recorded so far",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Triggers", Body: models.TriggerListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/triggers/{name}", OperationID: "getManagedTrigger", Tag: "manage",
+		Summary:     "Get trigger",
+		Description: "Get a trigger with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Trigger", Body: models.TriggerResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/triggers/{name}", OperationID: "putManagedTrigger", Tag: "manage",
+		Summary:     "Apply trigger",
+		Description: "Create or replace a trigger. A changed spec forgets the completions recorded so far. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.TriggerSpec{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Trigger replaced or unchanged", Body: models.TriggerResource{}},
+			{Status: http.StatusCreated, Description: "Trigger created", Body: models.TriggerResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/triggers/{name}", OperationID: "deleteManagedTrigger", Tag: "manage",
+		Summary:     "Delete trigger",
+		Description: "Delete a trigger",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Trigger deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/calendars", OperationID: "listManagedCalendars", Tag: "manage",
+		Summary:     "List calendars",
+		Description: "Calendars of holidays and blackout windows schedules do not run in",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Calendars", Body: models.CalendarListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/calendars/{name}", OperationID: "getManagedCalendar", Tag: "manage",
+		Summary:     "Get calendar",
+		Description: "Get a calendar with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Calendar", Body: models.CalendarResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/calendars/{name}", OperationID: "putManagedCalendar", Tag: "manage",
+		Summary:     "Apply calendar",
+		Description: "Create or replace a calendar. The next run of the schedules using it is computed anew. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.CalendarSpec{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Calendar replaced or unchanged", Body: models.CalendarResource{}},
+			{Status: http.StatusCreated, Description: "Calendar created", Body: models.CalendarResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/calendars/{name}", OperationID: "deleteManagedCalendar", Tag: "manage",
+		Summary:     "Delete calendar",
+		Description: "Delete a calendar. Calendars used by a schedule cannot be deleted",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Calendar deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/webhooks", OperationID: "listManagedWebhooks", Tag: "manage",
+		Summary:     "List webhooks",
+		Description: "Webhooks receiving workflow events. Secrets are never returned",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Webhooks", Body: models.WebhookListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/webhooks/{name}", OperationID: "getManagedWebhook", Tag: "manage",
+		Summary:     "Get webhook",
+		Description: "Get a webhook with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Webhook", Body: models.WebhookResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/webhooks/{name}", OperationID: "putManagedWebhook", Tag: "manage",
+		Summary:     "Apply webhook",
+		Description: "Create or replace a webhook. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.WebhookSpec{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Webhook replaced or unchanged", Body: models.WebhookResource{}},
+			{Status: http.StatusCreated, Description: "Webhook created", Body: models.WebhookResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/webhooks/{name}", OperationID: "deleteManagedWebhook", Tag: "manage",
+		Summary:     "Delete webhook",
+		Description: "Delete a webhook",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Webhook deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/lanes", OperationID: "listManagedLanes", Tag: "manage",
+		Summary:     "List lanes",
+		Description: "Lanes created through the management API",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lanes", Body: models.LaneResourceListResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/manage/lanes/{name}", OperationID: "getManagedLane", Tag: "manage",
+		Summary:     "Get lane",
+		Description: "Get a lane with its version, which is also sent as ETag",
+		Params:      []openapi.Param{paramResourceName},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lane", Body: models.LaneResource{}},
+			errNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/manage/lanes/{name}", OperationID: "putManagedLane", Tag: "manage",
+		Summary:     "Apply lane",
+		Description: "Create a memory lane, or change the concurrency and rate limit of one. Capacity cannot change and the default lane cannot be managed. An unchanged spec keeps the version",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch, paramIfNoneMatchCreate},
+		Request:     models.LaneSpec{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lane replaced or unchanged", Body: models.LaneResource{}},
+			{Status: http.StatusCreated, Description: "Lane created", Body: models.LaneResource{}},
+			errBadRequest, errConflict, errPreconditionFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/manage/lanes/{name}", OperationID: "deleteManagedLane", Tag: "manage",
+		Summary:     "Delete lane",
+		Description: "Delete a lane",
+		Params:      []openapi.Param{paramResourceName, paramIfMatch},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Lane deleted", Body: models.ResourceDeleteResponse{}},
+			errNotFound, errConflict, errPreconditionFailed,
+		},
+	},
+
+	// Health
+	{
+		Method: http.MethodGet, Path: "/health", OperationID: "health", Tag: "health",
+		Summary:     "Health check",
+		Description: "Check if the service is alive and running",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Service is healthy", Body: map[string]string{}},
+			{Status: http.StatusServiceUnavailable, Description: "Service is unhealthy", Body: map[string]string{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/ready", OperationID: "ready", Tag: "health",
+		Summary:     "Readiness check",
+		Description: "Check if the service is ready to accept requests, with the state of each startup phase",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Service is ready", Body: engine.Readiness{}},
+			{Status: http.StatusServiceUnavailable, Description: "Service is starting up or draining", Body: engine.Readiness{}},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/drain", OperationID: "drain", Tag: "health",
+		Summary:     "Drain before stop",
+		Description: "Mark the service not ready and wait for running workflows, for use as a pre-stop hook. Only accepted from loopback addresses",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Drain finished or timed out", Body: models.DrainResponse{}},
+			{Status: http.StatusForbidden, Description: "Caller is not on a loopback address", Body: response.ErrorResponse{}},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/status", OperationID: "status", Tag: "health",
+		Summary:     "Detailed status",
+		Description: "Get detailed status information about the service and engine",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Detailed status information", Body: engine.EngineStatus{}},
+		},
+	},
+
+	// Runtime log levels
+	{
+		Method: http.MethodGet, Path: "/admin/loglevel", OperationID: "getLogLevels", Tag: "admin",
+		Summary:     "Get log levels",
+		Description: "The server log level and the level of each component logger",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Log levels", Body: models.LogLevelsResponse{}},
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/loglevel", OperationID: "setLogLevel", Tag: "admin",
+		Summary:     "Set log level",
+		Description: "Change the level of a component logger, or the server level without a component, until the next restart",
+		Params: []openapi.Param{
+			{Name: "component", In: openapi.InQuery, Description: "Component to set the level of, such as lane or http"},
+		},
+		Request: models.LogLevelRequest{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Log levels after the change", Body: models.LogLevelsResponse{}},
+			errBadRequest,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/loglevel", OperationID: "resetLogLevel", Tag: "admin",
+		Summary:     "Reset component log level",
+		Description: "Make a component logger follow the server level again",
+		Params: []openapi.Param{
+			{Name: "component", In: openapi.InQuery, Description: "Component to reset", Required: true},
+		},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Log levels after the reset", Body: models.LogLevelsResponse{}},
+			errBadRequest,
+		},
+	},
+
+	// Snapshots
+	{
+		Method: http.MethodGet, Path: "/admin/snapshot", OperationID: "exportSnapshot", Tag: "admin",
+		Summary: "Export a snapshot",
+		Description: "A gzip-compressed tar archive of the workflows, tasks, sagas, memory entries and managed resources, " +
+			"for disaster recovery or cloning the instance",
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Snapshot archive", Body: openapi.Binary{}, ContentType: handlers.ContentTypeSnapshot},
+			errInternal,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/snapshot", OperationID: "importSnapshot", Tag: "admin",
+		Summary:            "Import a snapshot",
+		Description:        "Restore an exported snapshot archive. The instance must hold no workflows, sagas, memory entries or managed resources",
+		Request:            openapi.Binary{},
+		RequestContentType: handlers.ContentTypeSnapshot,
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Manifest of the restored snapshot", Body: snapshot.Manifest{}},
+			errBadRequest, errConflict, errInternal,
+		},
+	},
+}
+
+func withDefault(p openapi.Param, def interface{}) openapi.Param {
+	p.Default = def
+	return p
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIJSON []byte
+	openAPIErr  error
+)
+
+// OpenAPIDocument returns the OpenAPI 3 document describing the HTTP API.
+func OpenAPIDocument() *openapi.Document {
+	return openapi.Build(apiInfo, apiRoutes)
+}
+
+// openAPIHandler serves the generated document. It is built once on first use.
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	openAPIOnce.Do(func() {
+		openAPIJSON, openAPIErr = json.Marshal(OpenAPIDocument())
+	})
+	if openAPIErr != nil {
+		response.Error(w, http.StatusInternalServerError, response.ErrCodeInternalServer, "Failed to build OpenAPI document", middleware.GetRequestID(r.Context()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openAPIJSON)
+}
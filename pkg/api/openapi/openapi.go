@@ -0,0 +1,306 @@
+// Package openapi builds OpenAPI 3 documents from route descriptions and Go
+// model types.
+//
+// Request and response schemas are derived by reflection from the models'
+// json and validate tags, so the published document cannot drift from the
+// types the handlers actually encode and decode.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version emitted by Build.
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the API is served from.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations available on a single path.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+// Operations returns the operations of the path item keyed by HTTP method.
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+		http.MethodPatch:  p.Patch,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+func (p *PathItem) set(method string, op *Operation) bool {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodPatch:
+		p.Patch = op
+	default:
+		return false
+	}
+	return true
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's request body.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema for one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Route describes one operation to include in a document.
+type Route struct {
+	// Method is the HTTP method (e.g. http.MethodPost).
+	Method string
+	// Path is the route pattern using chi's "{param}" syntax.
+	Path string
+	// OperationID uniquely identifies the operation.
+	OperationID string
+	// Summary is a short description of the operation.
+	Summary string
+	// Description is a longer description of the operation.
+	Description string
+	// Tag groups the operation.
+	Tag string
+	// Params describes path, query and header parameters. Path parameters
+	// that are not listed are added automatically as required strings.
+	Params []Param
+	// Request is a value of the request body type, or nil for no body.
+	Request interface{}
+	// RequestContentType is the media type of the request body; it
+	// defaults to "application/json".
+	RequestContentType string
+	// RequestOptional marks the request body as optional.
+	RequestOptional bool
+	// Responses lists the documented responses.
+	Responses []Resp
+}
+
+// Param describes a path, query or header parameter of a Route.
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	// Type is the JSON type of the parameter; it defaults to "string".
+	Type string
+	// Default is the default value, if any.
+	Default interface{}
+}
+
+// Resp describes one response of a Route. Responses with the same status
+// and different content types are merged into one.
+type Resp struct {
+	Status      int
+	Description string
+	// Body is a value of the response body type, or nil for no body.
+	Body interface{}
+	// ContentType is the media type of the body; it defaults to
+	// "application/json".
+	ContentType string
+}
+
+// Parameter locations.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+const contentTypeJSON = "application/json"
+
+// Binary stands for a raw binary body, such as a file upload or download.
+// Use a Binary value as a Route's Request or a Resp's Body together with a
+// non-JSON content type.
+type Binary []byte
+
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// Build generates a document describing routes. Schemas for named struct
+// types are emitted once under components and referenced by $ref.
+func Build(info Info, routes []Route) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = &PathItem{}
+		}
+		if !item.set(strings.ToUpper(route.Method), g.operation(route)) {
+			continue
+		}
+		doc.Paths[route.Path] = item
+
+		if route.Tag != "" && !tags[route.Tag] {
+			tags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func (g *generator) operation(route Route) *Operation {
+	op := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Responses:   make(map[string]*Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	declared := make(map[string]bool)
+	for _, p := range route.Params {
+		declared[p.In+":"+p.Name] = true
+		op.Parameters = append(op.Parameters, parameter(p))
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		if declared[InPath+":"+match[1]] {
+			continue
+		}
+		op.Parameters = append(op.Parameters, parameter(Param{Name: match[1], In: InPath, Required: true}))
+	}
+
+	if route.Request != nil {
+		contentType := route.RequestContentType
+		if contentType == "" {
+			contentType = contentTypeJSON
+		}
+		op.RequestBody = &RequestBody{
+			Required: !route.RequestOptional,
+			Content:  map[string]*MediaType{contentType: {Schema: g.schemaFor(route.Request)}},
+		}
+	}
+
+	for _, resp := range route.Responses {
+		status := strconv.Itoa(resp.Status)
+		r, ok := op.Responses[status]
+		if !ok {
+			r = &Response{Description: resp.Description}
+			if r.Description == "" {
+				r.Description = http.StatusText(resp.Status)
+			}
+			op.Responses[status] = r
+		}
+		if resp.Body != nil {
+			contentType := resp.ContentType
+			if contentType == "" {
+				contentType = contentTypeJSON
+			}
+			if r.Content == nil {
+				r.Content = make(map[string]*MediaType)
+			}
+			r.Content[contentType] = &MediaType{Schema: g.schemaFor(resp.Body)}
+		}
+	}
+	return op
+}
+
+func parameter(p Param) *Parameter {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	return &Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		// Path parameters are always required by the specification.
+		Required: p.Required || p.In == InPath,
+		Schema:   &Schema{Type: typ, Default: p.Default},
+	}
+}
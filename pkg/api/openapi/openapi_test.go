@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type sampleItem struct {
+	ID    string    `json:"id" validate:"required,max=10"`
+	Count int       `json:"count,omitempty" validate:"omitempty,min=1,max=5"`
+	Kind  string    `json:"kind,omitempty" validate:"omitempty,oneof=a b"`
+	When  time.Time `json:"when"`
+	Next  *sampleItem
+}
+
+type sampleRequest struct {
+	Items    []sampleItem      `json:"items" validate:"required,min=1,dive"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Optional *int              `json:"optional,omitempty"`
+	Ignored  string            `json:"-"`
+}
+
+func TestBuild_Paths(t *testing.T) {
+	doc := Build(Info{Title: "test", Version: "1"}, []Route{
+		{
+			Method: http.MethodPost, Path: "/items/{id}", OperationID: "createItem", Tag: "items",
+			Params:    []Param{{Name: "dry_run", In: InQuery, Type: "boolean"}},
+			Request:   sampleRequest{},
+			Responses: []Resp{{Status: http.StatusCreated, Body: sampleItem{}}, {Status: http.StatusNoContent}},
+		},
+		{
+			Method: http.MethodGet, Path: "/items/{id}", OperationID: "getItem", Tag: "items",
+			Responses: []Resp{
+				{Status: http.StatusOK, Description: "Item", Body: sampleItem{}},
+				{Status: http.StatusOK, Body: sampleItem{}, ContentType: "application/x-ndjson"},
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/files/{name}", OperationID: "putFile",
+			Request: Binary{}, RequestContentType: "application/octet-stream",
+			Responses: []Resp{{Status: http.StatusOK, Body: Binary{}, ContentType: "application/octet-stream"}},
+		},
+		{Method: "TRACE", Path: "/ignored"},
+	})
+
+	if doc.OpenAPI != Version {
+		t.Fatalf("expected version %s, got %s", Version, doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/ignored"]; ok {
+		t.Fatal("expected unsupported method to be skipped")
+	}
+
+	item := doc.Paths["/items/{id}"]
+	if item == nil || item.Post == nil || item.Get == nil {
+		t.Fatalf("expected GET and POST on /items/{id}, got %+v", item)
+	}
+	if len(item.Operations()) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(item.Operations()))
+	}
+
+	post := item.Post
+	if len(post.Parameters) != 2 {
+		t.Fatalf("expected query and implicit path parameter, got %d", len(post.Parameters))
+	}
+	if p := post.Parameters[1]; p.Name != "id" || p.In != InPath || !p.Required {
+		t.Fatalf("unexpected path parameter: %+v", p)
+	}
+	if post.RequestBody == nil || !post.RequestBody.Required {
+		t.Fatal("expected required request body")
+	}
+	if ref := post.RequestBody.Content["application/json"].Schema.Ref; ref != RefPrefix+"openapi.sampleRequest" {
+		t.Fatalf("unexpected request ref %q", ref)
+	}
+	if got := post.Responses["201"].Description; got != "Created" {
+		t.Fatalf("expected default description, got %q", got)
+	}
+	if post.Responses["204"].Content != nil {
+		t.Fatal("expected no content for body-less response")
+	}
+	if get := item.Get.Responses["200"]; get.Description != "Item" || len(get.Content) != 2 || get.Content["application/x-ndjson"] == nil {
+		t.Fatalf("expected JSON and NDJSON content merged into one response, got %+v", get)
+	}
+	put := doc.Paths["/files/{name}"].Put
+	if schema := put.RequestBody.Content["application/octet-stream"].Schema; schema.Type != "string" || schema.Format != "binary" {
+		t.Fatalf("expected binary request schema, got %+v", schema)
+	}
+	if put.Responses["200"].Content["application/octet-stream"] == nil {
+		t.Fatalf("expected binary response, got %+v", put.Responses["200"])
+	}
+	if len(doc.Tags) != 1 || doc.Tags[0].Name != "items" {
+		t.Fatalf("unexpected tags: %+v", doc.Tags)
+	}
+}
+
+func TestBuild_Schemas(t *testing.T) {
+	doc := Build(Info{Title: "test", Version: "1"}, []Route{
+		{Method: http.MethodPost, Path: "/items", Request: sampleRequest{}},
+	})
+
+	req := doc.Components.Schemas["openapi.sampleRequest"]
+	if req == nil {
+		t.Fatal("expected sampleRequest component")
+	}
+	if !reflect.DeepEqual(req.Required, []string{"items"}) {
+		t.Fatalf("unexpected required: %v", req.Required)
+	}
+	if _, ok := req.Properties["-"]; ok {
+		t.Fatal("expected json:\"-\" field to be skipped")
+	}
+	items := req.Properties["items"]
+	if items.Type != "array" || items.MinItems == nil || *items.MinItems != 1 {
+		t.Fatalf("unexpected items schema: %+v", items)
+	}
+	if items.Items.Ref != RefPrefix+"openapi.sampleItem" {
+		t.Fatalf("unexpected items ref %q", items.Items.Ref)
+	}
+	if labels := req.Properties["labels"]; labels.AdditionalProperties == nil || labels.AdditionalProperties.Type != "string" {
+		t.Fatalf("unexpected labels schema: %+v", labels)
+	}
+	if !req.Properties["optional"].Nullable {
+		t.Fatal("expected pointer field to be nullable")
+	}
+
+	item := doc.Components.Schemas["openapi.sampleItem"]
+	if item == nil {
+		t.Fatal("expected sampleItem component")
+	}
+	if id := item.Properties["id"]; id.MinLength == nil || *id.MinLength != 1 || id.MaxLength == nil || *id.MaxLength != 10 {
+		t.Fatalf("unexpected id schema: %+v", id)
+	}
+	if count := item.Properties["count"]; count.Minimum == nil || *count.Minimum != 1 || *count.Maximum != 5 {
+		t.Fatalf("unexpected count schema: %+v", count)
+	}
+	if kind := item.Properties["kind"]; !reflect.DeepEqual(kind.Enum, []interface{}{"a", "b"}) {
+		t.Fatalf("unexpected kind enum: %v", kind.Enum)
+	}
+	if when := item.Properties["when"]; when.Type != "string" || when.Format != "date-time" {
+		t.Fatalf("unexpected time schema: %+v", when)
+	}
+	if next := item.Properties["Next"]; next.Ref != RefPrefix+"openapi.sampleItem" {
+		t.Fatalf("expected self reference, got %+v", next)
+	}
+	if !reflect.DeepEqual(item.Required, []string{"id", "when"}) {
+		t.Fatalf("unexpected required: %v", item.Required)
+	}
+}
@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RefPrefix is the prefix of component schema references.
+const RefPrefix = "#/components/schemas/"
+
+// Schema is an OpenAPI 3.0 schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	rawJSONType  = reflect.TypeOf(json.RawMessage(nil))
+	binaryType   = reflect.TypeOf(Binary(nil))
+)
+
+// generator collects component schemas while building a document.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// SchemaName returns the component name used for a named type, in the
+// "package.Type" form (e.g. "models.WorkflowRequest").
+func SchemaName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	pkg := typ.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if pkg == "" {
+		return typ.Name()
+	}
+	return pkg + "." + typ.Name()
+}
+
+func (g *generator) schemaFor(v interface{}) *Schema {
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+// typeSchema returns the schema for typ. Named structs are registered as
+// components and returned as references.
+func (g *generator) typeSchema(typ reflect.Type) *Schema {
+	switch typ {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds"}
+	case rawJSONType:
+		return &Schema{}
+	case binaryType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(typ.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return g.objectSchema(typ)
+		}
+		return g.componentRef(typ)
+	default:
+		// Interfaces and other dynamic values accept any JSON value.
+		return &Schema{}
+	}
+}
+
+func (g *generator) componentRef(typ reflect.Type) *Schema {
+	name, ok := g.names[typ]
+	if !ok {
+		name = SchemaName(typ)
+		g.names[typ] = name
+		// Register before recursing so self-referencing types terminate.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.objectSchema(typ)
+	}
+	return &Schema{Ref: RefPrefix + name}
+}
+
+// objectSchema builds the schema for a struct type using its json tags.
+// Embedded structs without a json name are flattened, as encoding/json does.
+func (g *generator) objectSchema(typ reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := g.objectSchema(embedded)
+				for key, prop := range inner.Properties {
+					schema.Properties[key] = prop
+				}
+				schema.Required = append(schema.Required, inner.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.typeSchema(field.Type)
+		if field.Type.Kind() == reflect.Ptr && prop.Ref == "" {
+			prop.Nullable = true
+		}
+		if example := field.Tag.Get("example"); example != "" && prop.Ref == "" {
+			prop.Example = parseExample(prop.Type, example)
+		}
+		if applyValidationRules(prop, field.Type, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		} else if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr &&
+			field.Type.Kind() != reflect.Interface && field.Type.Kind() != reflect.Map &&
+			field.Type.Kind() != reflect.Slice {
+			// Fields without omitempty are always present in encoded output.
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	}
+
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+	return schema
+}
+
+// applyValidationRules maps validator tags onto schema keywords and reports
+// whether the field is required. Rules inside a dive apply to the elements.
+func applyValidationRules(schema *Schema, typ reflect.Type, rules string) bool {
+	if rules == "" || schema.Ref != "" {
+		return rules != "" && hasRule(rules, "required")
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+			if typ.Kind() == reflect.String && schema.MinLength == nil {
+				schema.MinLength = intPtr(1)
+			}
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, parseExample(schema.Type, v))
+			}
+		case "min", "gte":
+			setBound(schema, typ, param, true)
+		case "max", "lte":
+			setBound(schema, typ, param, false)
+		}
+	}
+	return required
+}
+
+func hasRule(rules, want string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			return false
+		}
+		if rule == want {
+			return true
+		}
+	}
+	return false
+}
+
+func setBound(schema *Schema, typ reflect.Type, param string, lower bool) {
+	switch typ.Kind() {
+	case reflect.String:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.MinLength = intPtr(n)
+		} else {
+			schema.MaxLength = intPtr(n)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.MinItems = intPtr(n)
+		} else {
+			schema.MaxItems = intPtr(n)
+		}
+	default:
+		f, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.Minimum = &f
+		} else {
+			schema.Maximum = &f
+		}
+	}
+}
+
+// parseExample converts a tag value into a value of the schema's type.
+func parseExample(typ, value string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "array":
+		return strings.Split(value, ",")
+	case "object":
+		return nil
+	}
+	return value
+}
+
+func intPtr(n int) *int { return &n }
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/handlers"
+	"github.com/goclaw/goclaw/pkg/api/openapi"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/metrics"
+	"github.com/goclaw/goclaw/pkg/snapshot"
+)
+
+type nopMemoryLogger struct{}
+
+func (nopMemoryLogger) Debug(msg string, args ...any) {}
+func (nopMemoryLogger) Info(msg string, args ...any)  {}
+func (nopMemoryLogger) Warn(msg string, args ...any)  {}
+func (nopMemoryLogger) Error(msg string, args ...any) {}
+
+// newDocumentedRouter builds a router with every documented handler group registered.
+func newDocumentedRouter(t *testing.T) chi.Router {
+	t.Helper()
+	log := logger.New(&logger.Config{Level: logger.ErrorLevel, Format: "json", Output: "stdout"})
+	r := chi.NewRouter()
+	RegisterRoutes(r, config.DefaultConfig(), log, &Handlers{
+		Workflow:   handlers.NewWorkflowHandler(nil, log),
+		Health:     handlers.NewHealthHandler(nil),
+		Memory:     handlers.NewMemoryHandler(nil, nopMemoryLogger{}),
+		Saga:       handlers.NewSagaHandler(nil, nil, nil, nil, log),
+		Signal:     handlers.NewSignalHandler(nil, nil, log),
+		Lane:       handlers.NewLaneHandler(nil, log),
+		Insights:   handlers.NewInsightsHandler(nil, log),
+		Cost:       handlers.NewCostHandler(nil, log),
+		Tool:       handlers.NewToolHandler(nil, log),
+		Monitoring: handlers.NewMonitoringHandler(nil, nil, metrics.MonitoringTarget{}, log),
+		LogLevel:   handlers.NewLogLevelHandler(nil, log),
+		Artifact:   handlers.NewArtifactHandler(nil, nil, log),
+		Manage:     handlers.NewManageHandler(nil, nil, log),
+		Snapshot:   handlers.NewSnapshotHandler(snapshot.Source{}, log),
+	})
+	return r
+}
+
+// undocumentedRoutes are served by the router but intentionally not part of the spec.
+func undocumentedRoute(path string) bool {
+	return path == OpenAPIPath || path == "/ws/events" ||
+		strings.HasPrefix(path, "/swagger/") || strings.HasPrefix(path, "/ui")
+}
+
+func TestOpenAPI_MatchesRouter(t *testing.T) {
+	routed := make(map[string]bool)
+	err := chi.Walk(newDocumentedRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		if !undocumentedRoute(route) {
+			routed[method+" "+route] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range OpenAPIDocument().Paths {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for route := range routed {
+		if !documented[route] {
+			t.Errorf("route %s is registered but not documented", route)
+		}
+	}
+	for route := range documented {
+		if !routed[route] {
+			t.Errorf("route %s is documented but not registered", route)
+		}
+	}
+}
+
+func TestOpenAPI_DocumentIsConsistent(t *testing.T) {
+	doc := OpenAPIDocument()
+	if doc.OpenAPI != openapi.Version {
+		t.Fatalf("expected openapi %s, got %s", openapi.Version, doc.OpenAPI)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// Every $ref must resolve to a component schema.
+	var refs []string
+	collectRefs(raw, &refs)
+	if len(refs) == 0 {
+		t.Fatal("expected component references in document")
+	}
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref, openapi.RefPrefix)
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("unresolved reference %s", ref)
+		}
+	}
+
+	operationIDs := make(map[string]string)
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			if op.OperationID == "" {
+				t.Errorf("%s %s has no operationId", method, path)
+			} else if other, ok := operationIDs[op.OperationID]; ok {
+				t.Errorf("operationId %s used by %s and %s %s", op.OperationID, other, method, path)
+			}
+			operationIDs[op.OperationID] = method + " " + path
+			if len(op.Responses) == 0 {
+				t.Errorf("%s %s has no responses", method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_RequestSchemaFromValidateTags(t *testing.T) {
+	schema := OpenAPIDocument().Components.Schemas["models.TaskDefinition"]
+	if schema == nil {
+		t.Fatal("expected models.TaskDefinition schema")
+	}
+
+	required := append([]string(nil), schema.Required...)
+	sort.Strings(required)
+	if strings.Join(required, ",") != "id,name,type" {
+		t.Fatalf("unexpected required fields: %v", required)
+	}
+	if got := schema.Properties["type"].Enum; len(got) != 4 {
+		t.Fatalf("expected type enum from oneof, got %v", got)
+	}
+	if max := schema.Properties["timeout"].Maximum; max == nil || *max != 3600 {
+		t.Fatalf("expected timeout maximum 3600, got %v", max)
+	}
+}
+
+func TestOpenAPI_ServedByRouter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, OpenAPIPath, nil)
+	rec := httptest.NewRecorder()
+	newDocumentedRouter(t).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if doc.Paths["/api/v1/sagas/{id}/recover"] == nil || doc.Paths["/api/v1/memory/{sessionID}/stats"] == nil {
+		t.Fatal("expected saga and memory paths in served document")
+	}
+}
+
+func collectRefs(v interface{}, refs *[]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				*refs = append(*refs, ref)
+				continue
+			}
+			collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range val {
+			collectRefs(child, refs)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package response
 
 import (
-	"errors"
 	"net/http"
+
+	"github.com/goclaw/goclaw/pkg/errs"
 )
 
 // ErrorResponse is the standard error response format.
@@ -14,80 +15,60 @@ type ErrorResponse struct {
 type ErrorDetail struct {
 	Code      string                 `json:"code"`
 	Message   string                 `json:"message"`
+	Retryable bool                   `json:"retryable"`
 	Details   map[string]interface{} `json:"details,omitempty"`
 	RequestID string                 `json:"request_id"`
 }
 
-// Common error codes
+// Common error codes. These are the stable codes defined in package errs.
 const (
-	ErrCodeBadRequest         = "BAD_REQUEST"
-	ErrCodeUnauthorized       = "UNAUTHORIZED"
-	ErrCodeForbidden          = "FORBIDDEN"
-	ErrCodeNotFound           = "NOT_FOUND"
-	ErrCodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
-	ErrCodeConflict           = "CONFLICT"
-	ErrCodeValidationFailed   = "VALIDATION_FAILED"
-	ErrCodeInternalServer     = "INTERNAL_SERVER_ERROR"
-	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
-	ErrCodeGatewayTimeout     = "GATEWAY_TIMEOUT"
+	ErrCodeBadRequest         = string(errs.BadRequest)
+	ErrCodeUnauthorized       = string(errs.Unauthenticated)
+	ErrCodeForbidden          = string(errs.PermissionDenied)
+	ErrCodeNotFound           = string(errs.NotFound)
+	ErrCodeMethodNotAllowed   = string(errs.MethodNotAllowed)
+	ErrCodeConflict           = string(errs.Conflict)
+	ErrCodePreconditionFailed = string(errs.PreconditionFailed)
+	ErrCodeAlreadyExists      = string(errs.AlreadyExists)
+	ErrCodeValidationFailed   = string(errs.ValidationFailed)
+	ErrCodeRateLimited        = string(errs.RateLimited)
+	ErrCodeInternalServer     = string(errs.Internal)
+	ErrCodeServiceUnavailable = string(errs.ServiceUnavailable)
+	ErrCodeGatewayTimeout     = string(errs.Timeout)
 )
 
 // Common errors
 var (
-	ErrNotFound           = errors.New("resource not found")
-	ErrInvalidInput       = errors.New("invalid input")
-	ErrValidationFailed   = errors.New("validation failed")
-	ErrConflict           = errors.New("resource conflict")
-	ErrServiceUnavailable = errors.New("service unavailable")
-	ErrTimeout            = errors.New("request timeout")
-	ErrInternalServer     = errors.New("internal server error")
+	ErrNotFound           = errs.New(errs.NotFound, "resource not found")
+	ErrInvalidInput       = errs.New(errs.BadRequest, "invalid input")
+	ErrValidationFailed   = errs.New(errs.ValidationFailed, "validation failed")
+	ErrConflict           = errs.New(errs.Conflict, "resource conflict")
+	ErrServiceUnavailable = errs.New(errs.ServiceUnavailable, "service unavailable")
+	ErrTimeout            = errs.New(errs.Timeout, "request timeout")
+	ErrInternalServer     = errs.New(errs.Internal, "internal server error")
 )
 
-// HTTPStatusFromError maps common errors to HTTP status codes.
+// HTTPStatusFromError maps errors to HTTP status codes using the errs taxonomy.
 func HTTPStatusFromError(err error) int {
-	switch {
-	case errors.Is(err, ErrNotFound):
-		return http.StatusNotFound
-	case errors.Is(err, ErrInvalidInput), errors.Is(err, ErrValidationFailed):
-		return http.StatusBadRequest
-	case errors.Is(err, ErrConflict):
-		return http.StatusConflict
-	case errors.Is(err, ErrServiceUnavailable):
-		return http.StatusServiceUnavailable
-	case errors.Is(err, ErrTimeout):
-		return http.StatusGatewayTimeout
-	default:
-		return http.StatusInternalServerError
-	}
+	return errs.HTTPStatus(err)
 }
 
 // ErrorCodeFromStatus returns an error code for the given HTTP status.
 func ErrorCodeFromStatus(status int) string {
-	switch status {
-	case http.StatusBadRequest:
-		return ErrCodeBadRequest
-	case http.StatusUnauthorized:
-		return ErrCodeUnauthorized
-	case http.StatusForbidden:
-		return ErrCodeForbidden
-	case http.StatusNotFound:
-		return ErrCodeNotFound
-	case http.StatusMethodNotAllowed:
-		return ErrCodeMethodNotAllowed
-	case http.StatusConflict:
-		return ErrCodeConflict
-	case http.StatusServiceUnavailable:
-		return ErrCodeServiceUnavailable
-	case http.StatusGatewayTimeout:
-		return ErrCodeGatewayTimeout
-	default:
-		return ErrCodeInternalServer
-	}
+	return string(errs.CodeFromHTTPStatus(status))
 }
 
 // HandleError is a convenience function to handle errors and write appropriate responses.
+// Unclassified errors are reported as internal errors without exposing their message.
 func HandleError(w http.ResponseWriter, err error, requestID string) {
-	status := HTTPStatusFromError(err)
-	code := ErrorCodeFromStatus(status)
-	Error(w, status, code, err.Error(), requestID)
+	e := errs.From(err)
+	JSON(w, e.Code.HTTPStatus(), ErrorResponse{
+		Error: ErrorDetail{
+			Code:      string(e.Code),
+			Message:   e.Message,
+			Retryable: e.IsRetryable(),
+			Details:   e.Details,
+			RequestID: requestID,
+		},
+	})
 }
@@ -4,6 +4,8 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/goclaw/goclaw/pkg/errs"
 )
 
 // JSON writes a JSON response with the given status code and data.
@@ -26,6 +28,7 @@ func Error(w http.ResponseWriter, statusCode int, code, message string, requestI
 		Error: ErrorDetail{
 			Code:      code,
 			Message:   message,
+			Retryable: errs.Code(code).Retryable(),
 			RequestID: requestID,
 		},
 	}
@@ -38,6 +41,7 @@ func ErrorWithDetails(w http.ResponseWriter, statusCode int, code, message strin
 		Error: ErrorDetail{
 			Code:      code,
 			Message:   message,
+			Retryable: errs.Code(code).Retryable(),
 			Details:   details,
 			RequestID: requestID,
 		},
@@ -0,0 +1,84 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// ContentTypeNDJSON is the media type of newline-delimited JSON streams.
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// WantsNDJSON reports whether r asks for a newline-delimited JSON stream.
+// Middleware that buffers response bodies passes such requests through, so
+// that lines reach the client as they are written.
+func WantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ContentTypeNDJSON)
+}
+
+// NDJSONWriter writes a stream of JSON values, one per line, flushing the
+// response after every chunk of lines.
+type NDJSONWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	enc     *json.Encoder
+	chunk   int
+	pending int
+}
+
+// NewNDJSONWriter starts a 200 OK NDJSON response on w that is flushed every
+// chunk lines. The server's write timeout is lifted for the response, since a
+// stream can outlast it.
+func NewNDJSONWriter(w http.ResponseWriter, chunk int) *NDJSONWriter {
+	w.Header().Set("Content-Type", ContentTypeNDJSON)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+	return &NDJSONWriter{
+		w:     w,
+		rc:    rc,
+		enc:   json.NewEncoder(w),
+		chunk: max(chunk, 1),
+	}
+}
+
+// Encode writes v as the next line.
+func (n *NDJSONWriter) Encode(v interface{}) error {
+	if err := n.enc.Encode(v); err != nil {
+		return err
+	}
+	n.pending++
+	if n.pending >= n.chunk {
+		return n.Flush()
+	}
+	return nil
+}
+
+// Error writes an error as the last line of the stream. The status code has
+// already been sent, so clients detect a failed stream by this line.
+func (n *NDJSONWriter) Error(code, message, requestID string) error {
+	if err := n.enc.Encode(ErrorResponse{Error: ErrorDetail{
+		Code:      code,
+		Message:   message,
+		Retryable: errs.Code(code).Retryable(),
+		RequestID: requestID,
+	}}); err != nil {
+		return err
+	}
+	return n.Flush()
+}
+
+// Flush sends the lines written so far to the client.
+func (n *NDJSONWriter) Flush() error {
+	n.pending = 0
+	if err := n.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -2,9 +2,14 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/goclaw/goclaw/pkg/errs"
 )
 
 func TestJSON(t *testing.T) {
@@ -240,6 +245,50 @@ func TestHandleError(t *testing.T) {
 	}
 }
 
+func TestHandleError_HidesUnclassifiedErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	HandleError(w, errors.New("dial tcp 10.0.0.7:6379: connection refused"), "req-internal")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error.Code != ErrCodeInternalServer {
+		t.Fatalf("code = %s, want %s", resp.Error.Code, ErrCodeInternalServer)
+	}
+	if strings.Contains(resp.Error.Message, "10.0.0.7") {
+		t.Fatalf("message leaked internal error: %s", resp.Error.Message)
+	}
+}
+
+func TestHandleError_RetryableWithDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := errs.New(errs.RateLimited, "lane is full").WithDetail("lane", "default")
+	HandleError(w, fmt.Errorf("submit: %w", err), "req-retry")
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error.Code != ErrCodeRateLimited {
+		t.Fatalf("code = %s, want %s", resp.Error.Code, ErrCodeRateLimited)
+	}
+	if !resp.Error.Retryable {
+		t.Fatal("expected retryable hint")
+	}
+	if resp.Error.Details["lane"] != "default" {
+		t.Fatalf("details = %v, want lane=default", resp.Error.Details)
+	}
+}
+
 func TestErrorWithDetails(t *testing.T) {
 	w := httptest.NewRecorder()
 	details := map[string]interface{}{"field": "name", "reason": "required"}
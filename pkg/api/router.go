@@ -11,10 +11,10 @@ import (
 	"github.com/goclaw/goclaw/config"
 	"github.com/goclaw/goclaw/pkg/api/handlers"
 	"github.com/goclaw/goclaw/pkg/api/middleware"
+	grpcpkg "github.com/goclaw/goclaw/pkg/grpc"
+	"github.com/goclaw/goclaw/pkg/identity"
 	"github.com/goclaw/goclaw/pkg/logger"
 	httpSwagger "github.com/swaggo/http-swagger"
-
-	_ "github.com/goclaw/goclaw/docs/swagger" // Import generated docs
 )
 
 // Handlers holds all HTTP handlers.
@@ -31,11 +31,44 @@ type Handlers struct {
 	// Saga handles saga-related endpoints
 	Saga *handlers.SagaHandler
 
+	// Signal handles signal inspection endpoints
+	Signal *handlers.SignalHandler
+
+	// Lane handles lane statistics endpoints
+	Lane *handlers.LaneHandler
+
+	// Insights handles the duration insights endpoint
+	Insights *handlers.InsightsHandler
+
+	// Cost handles the cost report endpoint
+	Cost *handlers.CostHandler
+
+	// Tool handles the tool registry endpoints
+	Tool *handlers.ToolHandler
+
+	// Monitoring handles the dashboard and alert rule generation endpoints
+	Monitoring *handlers.MonitoringHandler
+
+	// LogLevel handles the runtime log level endpoints
+	LogLevel *handlers.LogLevelHandler
+
+	// Artifact handles task artifact endpoints
+	Artifact *handlers.ArtifactHandler
+
+	// Manage handles the declarative management endpoints
+	Manage *handlers.ManageHandler
+
+	// Snapshot handles the snapshot export and import endpoints
+	Snapshot *handlers.SnapshotHandler
+
 	// Metrics is the optional metrics recorder
 	Metrics middleware.MetricsRecorder
 
 	// WebSocket handles websocket events endpoint
 	WebSocket http.Handler
+
+	// GRPCWeb serves the gRPC services to gRPC-Web clients
+	GRPCWeb http.Handler
 }
 
 // NewRouter creates a new chi router with middleware and routes.
@@ -44,6 +77,11 @@ func NewRouter(cfg *config.Config, log logger.Logger, handlers *Handlers) chi.Ro
 
 	// Register global middleware
 	r.Use(middleware.RequestID())
+	if cfg != nil && cfg.Server.HTTP.TLS.Enabled && cfg.Server.HTTP.TLS.ClientAuth {
+		// ValidateWithDetails has checked the mappings
+		mapper, _ := identity.NewMapper(cfg.Server.Auth.IdentityMappings())
+		r.Use(middleware.ClientIdentity(mapper))
+	}
 	if cfg != nil && cfg.Tracing.Enabled {
 		r.Use(middleware.Tracing(middleware.DefaultTracingOptions()))
 	}
@@ -56,7 +94,15 @@ func NewRouter(cfg *config.Config, log logger.Logger, handlers *Handlers) chi.Ro
 	}
 
 	r.Use(middleware.CORS(&cfg.Server.CORS))
-	r.Use(middleware.Timeout(cfg.Server.HTTP.ReadTimeout))
+	if handlers.GRPCWeb != nil {
+		// gRPC-Web calls skip compression and the request timeout, which
+		// would buffer server streams.
+		r.Use(grpcWeb(handlers.GRPCWeb))
+	}
+	r.Use(middleware.Compress(&cfg.Server.HTTP.Compression))
+	// Requests waiting for workflows bound themselves by their timeout
+	// parameter.
+	r.Use(middleware.Timeout(cfg.Server.HTTP.ReadTimeout, waitsForWorkflow))
 
 	// Register routes
 	RegisterRoutes(r, cfg, log, handlers)
@@ -64,21 +110,66 @@ func NewRouter(cfg *config.Config, log logger.Logger, handlers *Handlers) chi.Ro
 	return r
 }
 
+// grpcWeb returns a middleware that hands gRPC-Web calls to h.
+func grpcWeb(h http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if grpcpkg.IsWebRequest(r) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// waitsForWorkflow reports whether r waits for a workflow to finish.
+func waitsForWorkflow(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet:
+		return strings.HasPrefix(r.URL.Path, "/api/v1/workflows/") && strings.HasSuffix(r.URL.Path, "/wait")
+	case http.MethodPost:
+		return r.URL.Path == "/api/v1/workflows:execute"
+	}
+	return false
+}
+
 // RegisterRoutes registers all API routes.
 func RegisterRoutes(r chi.Router, cfg *config.Config, log logger.Logger, handlers *Handlers) {
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Workflow routes
 		if handlers.Workflow != nil {
+			r.Post("/workflows:execute", handlers.Workflow.ExecuteWorkflow)
+			r.Post("/workflows:plan", handlers.Workflow.PlanWorkflow)
 			r.Route("/workflows", func(r chi.Router) {
 				r.Post("/", handlers.Workflow.SubmitWorkflow)
-				r.Get("/", handlers.Workflow.ListWorkflows)
-				r.Get("/{id}", handlers.Workflow.GetWorkflow)
+				// Read endpoints support conditional requests via ETag/If-None-Match
+				r.With(middleware.ETag()).Get("/", handlers.Workflow.ListWorkflows)
+				r.With(middleware.ETag()).Get("/{id}", handlers.Workflow.GetWorkflow)
+				r.Get("/{id}/wait", handlers.Workflow.WaitWorkflow)
 				r.Post("/{id}/cancel", handlers.Workflow.CancelWorkflow)
+				r.Post("/{id}/retry", handlers.Workflow.RetryWorkflow)
+				r.Post("/{id}/approve", handlers.Workflow.ApproveWorkflow)
+				r.With(middleware.ETag()).Get("/{id}/tasks", handlers.Workflow.ListTasks)
+				r.Patch("/{id}/tasks", handlers.Workflow.AppendTasks)
 				r.Get("/{id}/tasks/{tid}/result", handlers.Workflow.GetTaskResult)
+				r.Get("/{id}/tasks/{tid}/logs", handlers.Workflow.GetTaskLogs)
+				r.Get("/{id}/values", handlers.Workflow.ListWorkflowValues)
+				r.Get("/{id}/values/{key}", handlers.Workflow.GetWorkflowValue)
+				r.Put("/{id}/values/{key}", handlers.Workflow.PutWorkflowValue)
+				r.Delete("/{id}/values/{key}", handlers.Workflow.DeleteWorkflowValue)
+				r.Get("/{id}/lineage", handlers.Workflow.GetWorkflowLineage)
 			})
 		}
 
+		// Artifact routes
+		if handlers.Artifact != nil {
+			r.Get("/workflows/{id}/artifacts", handlers.Artifact.ListArtifacts)
+			r.Put("/workflows/{id}/tasks/{tid}/artifacts/{name}", handlers.Artifact.UploadArtifact)
+			r.Get("/workflows/{id}/tasks/{tid}/artifacts/{name}", handlers.Artifact.DownloadArtifact)
+		}
+
 		// Memory routes
 		if handlers.Memory != nil {
 			r.Route("/memory/{sessionID}", func(r chi.Router) {
@@ -97,27 +188,116 @@ func RegisterRoutes(r chi.Router, cfg *config.Config, log logger.Logger, handler
 			r.Route("/sagas", func(r chi.Router) {
 				r.Post("/", handlers.Saga.SubmitSaga)
 				r.Get("/", handlers.Saga.ListSagas)
+				r.Get("/archive", handlers.Saga.ListArchivedSagas)
 				r.Get("/{id}", handlers.Saga.GetSaga)
+				r.Get("/{id}/archive", handlers.Saga.GetArchivedSaga)
 				r.Post("/{id}/compensate", handlers.Saga.CompensateSaga)
+				r.Post("/{id}/compensate:preview", handlers.Saga.PreviewCompensation)
 				r.Post("/{id}/recover", handlers.Saga.RecoverSaga)
 			})
 		}
+
+		// Signal routes
+		if handlers.Signal != nil {
+			r.Get("/signals/schemas", handlers.Signal.ListSchemas)
+			r.Put("/signals/schemas", handlers.Signal.RegisterSchema)
+			r.Delete("/signals/schemas", handlers.Signal.DeleteSchema)
+			r.Get("/signals/{channel}/history", handlers.Signal.GetHistory)
+		}
+
+		// Lane routes
+		if handlers.Lane != nil {
+			r.Get("/lanes", handlers.Lane.ListLanes)
+			r.Get("/lanes/{name}", handlers.Lane.GetLane)
+		}
+
+		// Insights routes
+		if handlers.Insights != nil {
+			r.Get("/insights", handlers.Insights.GetInsights)
+		}
+
+		// Cost routes
+		if handlers.Cost != nil {
+			r.Get("/costs", handlers.Cost.GetCostReport)
+		}
+
+		// Tool routes
+		if handlers.Tool != nil {
+			r.Get("/tools", handlers.Tool.ListTools)
+			r.Get("/tools/{name}", handlers.Tool.GetTool)
+			r.Put("/tools/{name}/tenants/{tenant}", handlers.Tool.SetToolTenant)
+		}
+
+		// Monitoring routes
+		if handlers.Monitoring != nil {
+			r.Get("/monitoring/dashboard", handlers.Monitoring.GetDashboard)
+			r.Get("/monitoring/alerts", handlers.Monitoring.GetAlertRules)
+		}
+
+		// Declarative management routes
+		if handlers.Manage != nil {
+			r.Route("/manage", func(r chi.Router) {
+				r.Get("/templates", handlers.Manage.ListTemplates)
+				r.Get("/templates/{name}", handlers.Manage.GetTemplate)
+				r.Put("/templates/{name}", handlers.Manage.PutTemplate)
+				r.Delete("/templates/{name}", handlers.Manage.DeleteTemplate)
+				r.Get("/templates/{name}/diff", handlers.Manage.DiffTemplate)
+				r.Get("/schedules", handlers.Manage.ListSchedules)
+				r.Get("/schedules/{name}", handlers.Manage.GetSchedule)
+				r.Put("/schedules/{name}", handlers.Manage.PutSchedule)
+				r.Delete("/schedules/{name}", handlers.Manage.DeleteSchedule)
+				r.Post("/schedules/{name}/backfills", handlers.Manage.StartBackfill)
+				r.Get("/schedules/{name}/backfills", handlers.Manage.ListBackfills)
+				r.Delete("/schedules/{name}/backfills/{id}", handlers.Manage.CancelBackfill)
+				r.Get("/triggers", handlers.Manage.ListTriggers)
+				r.Get("/triggers/{name}", handlers.Manage.GetTrigger)
+				r.Put("/triggers/{name}", handlers.Manage.PutTrigger)
+				r.Delete("/triggers/{name}", handlers.Manage.DeleteTrigger)
+				r.Get("/calendars", handlers.Manage.ListCalendars)
+				r.Get("/calendars/{name}", handlers.Manage.GetCalendar)
+				r.Put("/calendars/{name}", handlers.Manage.PutCalendar)
+				r.Delete("/calendars/{name}", handlers.Manage.DeleteCalendar)
+				r.Get("/webhooks", handlers.Manage.ListWebhooks)
+				r.Get("/webhooks/{name}", handlers.Manage.GetWebhook)
+				r.Put("/webhooks/{name}", handlers.Manage.PutWebhook)
+				r.Delete("/webhooks/{name}", handlers.Manage.DeleteWebhook)
+				r.Get("/lanes", handlers.Manage.ListLanes)
+				r.Get("/lanes/{name}", handlers.Manage.GetLane)
+				r.Put("/lanes/{name}", handlers.Manage.PutLane)
+				r.Delete("/lanes/{name}", handlers.Manage.DeleteLane)
+			})
+		}
 	})
 
 	// Health check routes (not versioned)
 	if handlers.Health != nil {
 		r.Get("/health", handlers.Health.Health)
 		r.Get("/ready", handlers.Health.Ready)
+		r.Post("/drain", handlers.Health.Drain)
 		r.Get("/status", handlers.Health.Status)
 	}
 
+	// Runtime log levels (not versioned)
+	if handlers.LogLevel != nil {
+		r.Get("/admin/loglevel", handlers.LogLevel.GetLogLevels)
+		r.Put("/admin/loglevel", handlers.LogLevel.SetLogLevel)
+		r.Delete("/admin/loglevel", handlers.LogLevel.ResetLogLevel)
+	}
+
+	// Snapshot export and import (not versioned)
+	if handlers.Snapshot != nil {
+		r.Get("/admin/snapshot", handlers.Snapshot.ExportSnapshot)
+		r.Post("/admin/snapshot", handlers.Snapshot.ImportSnapshot)
+	}
+
 	// WebSocket events
 	if handlers.WebSocket != nil {
 		r.Handle("/ws/events", handlers.WebSocket)
 	}
 
-	// Swagger documentation
-	r.Get("/swagger/*", httpSwagger.WrapHandler)
+	// API documentation: the generated OpenAPI document and a Swagger UI for it
+	r.Get(OpenAPIPath, openAPIHandler)
+	r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL(OpenAPIPath)))
 
 	registerUIRoutes(r, cfg, log)
 }
@@ -232,6 +232,62 @@ func TestRegisterRoutes_WorkflowEndpoints(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("workflow endpoint status = %v, want %v", w.Code, http.StatusOK)
 	}
+
+	// The execute endpoint is routed next to the workflow collection.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/workflows:execute", strings.NewReader("{"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("execute endpoint status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRegisterRoutes_WorkflowConditionalGet(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			HTTP: config.HTTPConfig{
+				ReadTimeout: 30 * time.Second,
+				Compression: config.CompressionConfig{Enabled: true},
+			},
+		},
+	}
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+
+	testHandlers, cleanup := createTestHandlers(t)
+	defer cleanup()
+
+	router := NewRouter(cfg, log, testHandlers)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("workflow list status = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip-encoded workflow list, got %q", w.Header().Get("Content-Encoding"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag on workflow list")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/workflows", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("conditional workflow list status = %v, want %v", w.Code, http.StatusNotModified)
+	}
 }
 
 func TestRegisterRoutes_UIEnabled(t *testing.T) {
@@ -415,6 +471,45 @@ func TestRegisterRoutes_WebSocket(t *testing.T) {
 	}
 }
 
+func TestNewRouter_GRPCWeb(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			HTTP: config.HTTPConfig{
+				ReadTimeout: 30 * time.Second,
+			},
+		},
+	}
+
+	log := logger.New(&logger.Config{
+		Level:  logger.InfoLevel,
+		Format: "json",
+		Output: "stdout",
+	})
+
+	router := NewRouter(cfg, log, &Handlers{
+		GRPCWeb: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/grpc-web+proto")
+			_, _ = w.Write([]byte(r.URL.Path))
+		}),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/goclaw.v1.StreamingService/WatchWorkflow", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/grpc-web-text")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "/goclaw.v1.StreamingService/WatchWorkflow" {
+		t.Fatalf("gRPC-Web call got status %d and body %q", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/goclaw.v1.StreamingService/WatchWorkflow", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("JSON call got status %d, want 404", w.Code)
+	}
+}
+
 func setRouterTracingProvider(t *testing.T) (*tracetest.SpanRecorder, func()) {
 	t.Helper()
 
@@ -58,7 +58,7 @@ func TestSagaEndpointsIntegration(t *testing.T) {
 
 	cfg := config.DefaultConfig()
 	httpHandlers := &Handlers{
-		Saga: handlers.NewSagaHandler(orchestrator, checkpointStore, recoveryManager, log),
+		Saga: handlers.NewSagaHandler(orchestrator, checkpointStore, recoveryManager, nil, log),
 	}
 	router := NewRouter(cfg, log, httpHandlers)
 
@@ -92,6 +92,17 @@ func TestSagaEndpointsIntegration(t *testing.T) {
 		t.Fatalf("get status = %d, want %d", getW.Code, http.StatusOK)
 	}
 
+	previewReq := httptest.NewRequest(http.MethodPost, "/api/v1/sagas/"+submitResp.SagaID+"/compensate:preview", nil)
+	previewW := httptest.NewRecorder()
+	router.ServeHTTP(previewW, previewReq)
+	var preview models.SagaCompensationPreview
+	if err := json.NewDecoder(previewW.Body).Decode(&preview); err != nil || previewW.Code != http.StatusOK {
+		t.Fatalf("preview status = %d, %v, want %d", previewW.Code, err, http.StatusOK)
+	}
+	if preview.CanTrigger || len(preview.Steps) != 0 || len(preview.Skipped) != 1 {
+		t.Fatalf("preview = %+v, want a skipped step without compensation", preview)
+	}
+
 	listReq := httptest.NewRequest(http.MethodGet, "/api/v1/sagas?state=completed", nil)
 	listW := httptest.NewRecorder()
 	router.ServeHTTP(listW, listReq)
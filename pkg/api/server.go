@@ -3,8 +3,11 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/goclaw/goclaw/config"
@@ -56,7 +59,17 @@ func (s *HTTPServer) Start() error {
 		"write_timeout", s.config.Server.HTTP.WriteTimeout,
 	)
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	var err error
+	if tlsCfg := s.config.Server.HTTP.TLS; tlsCfg.Enabled {
+		s.server.TLSConfig, err = buildTLSConfig(tlsCfg)
+		if err != nil {
+			return fmt.Errorf("failed to build TLS config: %w", err)
+		}
+		err = s.server.ListenAndServeTLS(tlsCfg.CertFile, tlsCfg.KeyFile)
+	} else {
+		err = s.server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		s.logger.Error("HTTP server failed", "error", err)
 		return fmt.Errorf("failed to start HTTP server: %w", err)
 	}
@@ -64,6 +77,28 @@ func (s *HTTPServer) Start() error {
 	return nil
 }
 
+// buildTLSConfig returns the server TLS config, requiring and verifying client
+// certificates against the configured CA when client auth is enabled.
+func buildTLSConfig(cfg config.HTTPTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if !cfg.ClientAuth {
+		return tlsConfig, nil
+	}
+
+	caCert, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.ClientCAs = certPool
+	return tlsConfig, nil
+}
+
 // Shutdown gracefully shuts down the HTTP server.
 func (s *HTTPServer) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down HTTP server")
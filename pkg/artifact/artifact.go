@@ -0,0 +1,394 @@
+// Package artifact stores files produced by tasks, keyed by workflow and
+// task, so later tasks and API clients can fetch them.
+//
+// Contents live in a Backend (local disk or S3) next to a small JSON
+// metadata record holding the size and SHA-256 checksum, which are verified
+// on every download.
+package artifact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+var (
+	// ErrNotFound is returned for artifacts that do not exist or expired.
+	ErrNotFound = errs.New(errs.NotFound, "artifact: not found")
+
+	// ErrTooLarge is returned by Put for contents over the store's size limit.
+	ErrTooLarge = errs.New(errs.BadRequest, "artifact: exceeds maximum size")
+
+	// ErrChecksumMismatch is returned when downloaded contents do not match
+	// the size or checksum recorded at upload.
+	ErrChecksumMismatch = errs.New(errs.Internal, "artifact: checksum mismatch")
+)
+
+// Backend stores artifact contents and metadata as opaque objects under
+// slash-separated keys.
+type Backend interface {
+	// Put stores size bytes read from r under key, replacing any object.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+
+	// Get opens the object under key. It returns ErrNotFound if there is
+	// none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object under key. Deleting a missing object is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the keys starting with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Artifact describes a stored file.
+type Artifact struct {
+	// WorkflowID is the workflow the artifact belongs to.
+	WorkflowID string `json:"workflow_id"`
+
+	// TaskID is the task that produced the artifact.
+	TaskID string `json:"task_id"`
+
+	// Name is the file name, unique per task.
+	Name string `json:"name"`
+
+	// Size is the content length in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex SHA-256 checksum of the contents.
+	SHA256 string `json:"sha256"`
+
+	// ContentType is the media type given at upload.
+	ContentType string `json:"content_type,omitempty"`
+
+	// CreatedAt is the upload time.
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the artifact is deleted, or nil if it is kept.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithRetention sets how long artifacts are kept after upload. Zero, the
+// default, keeps them until deleted.
+func WithRetention(d time.Duration) Option {
+	return func(s *Store) {
+		s.retention = d
+	}
+}
+
+// WithMaxSize limits the size of one artifact in bytes. Zero, the default,
+// means no limit.
+func WithMaxSize(n int64) Option {
+	return func(s *Store) {
+		s.maxSize = n
+	}
+}
+
+// WithLogger sets the logger of the background sweeper.
+func WithLogger(l logger.Logger) Option {
+	return func(s *Store) {
+		s.logger = l
+	}
+}
+
+// Store keeps task artifacts in a Backend.
+type Store struct {
+	backend   Backend
+	retention time.Duration
+	maxSize   int64
+	logger    logger.Logger
+	now       func() time.Time
+}
+
+// NewStore returns a store keeping artifacts in backend.
+func NewStore(backend Backend, opts ...Option) *Store {
+	s := &Store{backend: backend, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.logger == nil {
+		s.logger = logger.Global()
+	}
+	return s
+}
+
+// Put stores the contents read from r as artifact name of a task, replacing
+// any previous artifact of that name.
+func (s *Store) Put(ctx context.Context, workflowID, taskID, name, contentType string, r io.Reader) (*Artifact, error) {
+	if err := validateKey(workflowID, taskID, name); err != nil {
+		return nil, err
+	}
+
+	// Spool to a temporary file first: the size and checksum must be known
+	// before anything reaches the backend, which needs the length up front.
+	tmp, err := os.CreateTemp("", "goclaw-artifact-*")
+	if err != nil {
+		return nil, fmt.Errorf("artifact: spool upload: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	sum := sha256.New()
+	src := r
+	if s.maxSize > 0 {
+		src = io.LimitReader(r, s.maxSize+1)
+	}
+	size, err := io.Copy(io.MultiWriter(tmp, sum), src)
+	if err != nil {
+		return nil, fmt.Errorf("artifact: read upload: %w", err)
+	}
+	if s.maxSize > 0 && size > s.maxSize {
+		return nil, ErrTooLarge
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("artifact: spool upload: %w", err)
+	}
+
+	a := &Artifact{
+		WorkflowID:  workflowID,
+		TaskID:      taskID,
+		Name:        name,
+		Size:        size,
+		SHA256:      hex.EncodeToString(sum.Sum(nil)),
+		ContentType: contentType,
+		CreatedAt:   s.now().UTC(),
+	}
+	if s.retention > 0 {
+		expiresAt := a.CreatedAt.Add(s.retention)
+		a.ExpiresAt = &expiresAt
+	}
+
+	if err := s.backend.Put(ctx, dataKey(workflowID, taskID, name), tmp, size); err != nil {
+		return nil, fmt.Errorf("artifact: store contents: %w", err)
+	}
+	meta, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("artifact: encode metadata: %w", err)
+	}
+	if err := s.backend.Put(ctx, metaKey(workflowID, taskID, name), strings.NewReader(string(meta)), int64(len(meta))); err != nil {
+		return nil, fmt.Errorf("artifact: store metadata: %w", err)
+	}
+	return a, nil
+}
+
+// Stat returns the metadata of an artifact.
+func (s *Store) Stat(ctx context.Context, workflowID, taskID, name string) (*Artifact, error) {
+	if err := validateKey(workflowID, taskID, name); err != nil {
+		return nil, err
+	}
+	a, err := s.readMeta(ctx, metaKey(workflowID, taskID, name))
+	if err != nil {
+		return nil, err
+	}
+	if s.expired(a) {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+// Open returns the metadata and contents of an artifact. Reading the
+// contents fails with ErrChecksumMismatch instead of io.EOF if they do not
+// match the size and checksum recorded at upload.
+func (s *Store) Open(ctx context.Context, workflowID, taskID, name string) (*Artifact, io.ReadCloser, error) {
+	a, err := s.Stat(ctx, workflowID, taskID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.backend.Get(ctx, dataKey(workflowID, taskID, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, &verifyingReader{rc: rc, hash: sha256.New(), want: a}, nil
+}
+
+// List returns the unexpired artifacts of a workflow, ordered by task and
+// name.
+func (s *Store) List(ctx context.Context, workflowID string) ([]*Artifact, error) {
+	if err := validateName("workflow ID", workflowID); err != nil {
+		return nil, err
+	}
+	artifacts, err := s.list(ctx, "meta/"+workflowID+"/")
+	if err != nil {
+		return nil, err
+	}
+	live := artifacts[:0]
+	for _, a := range artifacts {
+		if !s.expired(a) {
+			live = append(live, a)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool {
+		if live[i].TaskID != live[j].TaskID {
+			return live[i].TaskID < live[j].TaskID
+		}
+		return live[i].Name < live[j].Name
+	})
+	return live, nil
+}
+
+// Delete removes an artifact.
+func (s *Store) Delete(ctx context.Context, workflowID, taskID, name string) error {
+	if err := validateKey(workflowID, taskID, name); err != nil {
+		return err
+	}
+	return s.delete(ctx, workflowID, taskID, name)
+}
+
+// Sweep deletes expired artifacts and returns how many it deleted.
+func (s *Store) Sweep(ctx context.Context) (int, error) {
+	artifacts, err := s.list(ctx, "meta/")
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, a := range artifacts {
+		if !s.expired(a) {
+			continue
+		}
+		if err := s.delete(ctx, a.WorkflowID, a.TaskID, a.Name); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Run sweeps expired artifacts every interval until ctx is done.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.Sweep(ctx)
+			if err != nil {
+				s.logger.Warn("Artifact sweep failed", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				s.logger.Info("Deleted expired artifacts", "count", deleted)
+			}
+		}
+	}
+}
+
+func (s *Store) expired(a *Artifact) bool {
+	return a.ExpiresAt != nil && !s.now().Before(*a.ExpiresAt)
+}
+
+func (s *Store) delete(ctx context.Context, workflowID, taskID, name string) error {
+	// Metadata first, so a half-deleted artifact is never listed.
+	if err := s.backend.Delete(ctx, metaKey(workflowID, taskID, name)); err != nil {
+		return fmt.Errorf("artifact: delete metadata: %w", err)
+	}
+	if err := s.backend.Delete(ctx, dataKey(workflowID, taskID, name)); err != nil {
+		return fmt.Errorf("artifact: delete contents: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) list(ctx context.Context, prefix string) ([]*Artifact, error) {
+	keys, err := s.backend.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("artifact: list: %w", err)
+	}
+	artifacts := make([]*Artifact, 0, len(keys))
+	for _, key := range keys {
+		a, err := s.readMeta(ctx, key)
+		if errs.Is(err, errs.NotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
+
+func (s *Store) readMeta(ctx context.Context, key string) (*Artifact, error) {
+	rc, err := s.backend.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var a Artifact
+	if err := json.NewDecoder(rc).Decode(&a); err != nil {
+		return nil, fmt.Errorf("artifact: decode metadata %s: %w", key, err)
+	}
+	return &a, nil
+}
+
+func dataKey(workflowID, taskID, name string) string {
+	return "data/" + workflowID + "/" + taskID + "/" + name
+}
+
+func metaKey(workflowID, taskID, name string) string {
+	return "meta/" + workflowID + "/" + taskID + "/" + name
+}
+
+func validateKey(workflowID, taskID, name string) error {
+	if err := validateName("workflow ID", workflowID); err != nil {
+		return err
+	}
+	if err := validateName("task ID", taskID); err != nil {
+		return err
+	}
+	return validateName("name", name)
+}
+
+// validateName rejects values that cannot be used as one segment of a key.
+func validateName(what, value string) error {
+	if value == "" || value == "." || value == ".." || len(value) > 255 {
+		return errs.Newf(errs.BadRequest, "artifact: invalid %s %q", what, value)
+	}
+	for _, r := range value {
+		if r == '/' || r == '\\' || unicode.IsControl(r) {
+			return errs.Newf(errs.BadRequest, "artifact: invalid %s %q", what, value)
+		}
+	}
+	return nil
+}
+
+// verifyingReader checks the contents it reads against their metadata.
+type verifyingReader struct {
+	rc   io.ReadCloser
+	hash hash.Hash
+	want *Artifact
+	read int64
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.hash.Write(p[:n])
+	r.read += int64(n)
+	if err == io.EOF {
+		if r.read != r.want.Size || hex.EncodeToString(r.hash.Sum(nil)) != r.want.SHA256 {
+			return n, ErrChecksumMismatch
+		}
+	}
+	return n, err
+}
+
+func (r *verifyingReader) Close() error { return r.rc.Close() }
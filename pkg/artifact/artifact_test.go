@@ -0,0 +1,145 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+func newLocalStore(t *testing.T, opts ...Option) (*Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	backend, err := NewLocalBackend(dir)
+	if err != nil {
+		t.Fatalf("NewLocalBackend() error = %v", err)
+	}
+	return NewStore(backend, opts...), dir
+}
+
+func TestStore_PutOpenList(t *testing.T) {
+	store, _ := newLocalStore(t)
+	ctx := context.Background()
+
+	a, err := store.Put(ctx, "wf-1", "build", "report.txt", "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// SHA-256 of "hello".
+	if a.Size != 5 || a.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected artifact %+v", a)
+	}
+	if _, err := store.Put(ctx, "wf-1", "archive", "out.bin", "", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	_, rc, err := store.Open(ctx, "wf-1", "build", "report.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+
+	list, err := store.List(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].TaskID != "archive" || list[1].Name != "report.txt" {
+		t.Fatalf("unexpected list %+v", list)
+	}
+	if list, _ := store.List(ctx, "wf-2"); len(list) != 0 {
+		t.Fatalf("expected no artifacts for another workflow, got %+v", list)
+	}
+}
+
+func TestStore_OpenDetectsCorruption(t *testing.T) {
+	store, dir := newLocalStore(t)
+	ctx := context.Background()
+	if _, err := store.Put(ctx, "wf-1", "build", "report.txt", "", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data", "wf-1", "build", "report.txt"), []byte("jello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, rc, err := store.Open(ctx, "wf-1", "build", "report.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
+
+func TestStore_SweepDeletesExpired(t *testing.T) {
+	store, dir := newLocalStore(t, WithRetention(time.Hour))
+	ctx := context.Background()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	if _, err := store.Put(ctx, "wf-1", "build", "old.txt", "", strings.NewReader("old")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	now = now.Add(30 * time.Minute)
+	if _, err := store.Put(ctx, "wf-1", "build", "new.txt", "", strings.NewReader("new")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	now = now.Add(45 * time.Minute)
+
+	if _, err := store.Stat(ctx, "wf-1", "build", "old.txt"); !errs.Is(err, errs.NotFound) {
+		t.Fatalf("expected expired artifact to be not found, got %v", err)
+	}
+	deleted, err := store.Sweep(ctx)
+	if err != nil || deleted != 1 {
+		t.Fatalf("Sweep() = %d, %v; want 1 deleted", deleted, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "wf-1", "build", "old.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected expired contents to be deleted, got %v", err)
+	}
+	list, _ := store.List(ctx, "wf-1")
+	if len(list) != 1 || list[0].Name != "new.txt" {
+		t.Fatalf("unexpected list after sweep %+v", list)
+	}
+}
+
+func TestStore_RejectsOversizedAndInvalidNames(t *testing.T) {
+	store, _ := newLocalStore(t, WithMaxSize(4))
+	ctx := context.Background()
+
+	if _, err := store.Put(ctx, "wf-1", "build", "big.bin", "", strings.NewReader("12345")); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	for _, name := range []string{"", "..", "a/b", "a\\b"} {
+		if _, err := store.Put(ctx, "wf-1", "build", name, "", strings.NewReader("x")); !errs.Is(err, errs.BadRequest) {
+			t.Fatalf("Put(%q) error = %v, want BadRequest", name, err)
+		}
+	}
+}
+
+func TestUploadDownload_UseTaskScope(t *testing.T) {
+	store, _ := newLocalStore(t)
+	ctx := WithTask(context.Background(), store, "wf-1", "build")
+
+	if _, err := Upload(ctx, "bin.tar", "application/x-tar", strings.NewReader("tar")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	_, rc, err := Download(WithTask(context.Background(), store, "wf-1", "deploy"), "build", "bin.tar")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	rc.Close()
+
+	if _, err := Upload(context.Background(), "bin.tar", "", strings.NewReader("tar")); !errors.Is(err, ErrNoStore) {
+		t.Fatalf("expected ErrNoStore, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package artifact
+
+import (
+	"context"
+	"io"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// ErrNoStore is returned by Upload and Download for tasks run without an
+// artifact store.
+var ErrNoStore = errs.New(errs.NotImplemented, "artifact: no artifact store configured")
+
+// taskKey is the context key of the running task's taskScope.
+type taskKey struct{}
+
+// taskScope is the store and identity of a running task.
+type taskScope struct {
+	store      *Store
+	workflowID string
+	taskID     string
+	// onDownload is called with every artifact Download opens, if set.
+	onDownload func(*Artifact)
+}
+
+// WithTask returns ctx carrying the store and identity of a running task,
+// for Upload and Download.
+func WithTask(ctx context.Context, store *Store, workflowID, taskID string) context.Context {
+	return context.WithValue(ctx, taskKey{}, &taskScope{store: store, workflowID: workflowID, taskID: taskID})
+}
+
+// WithDownloadHook returns ctx whose Download calls hook with every artifact
+// it opens, so that the engine can record what tasks read. ctx must carry a
+// task from WithTask; otherwise it is returned unchanged.
+func WithDownloadHook(ctx context.Context, hook func(*Artifact)) context.Context {
+	scope, ok := ctx.Value(taskKey{}).(*taskScope)
+	if !ok {
+		return ctx
+	}
+	hooked := *scope
+	hooked.onDownload = hook
+	return context.WithValue(ctx, taskKey{}, &hooked)
+}
+
+// Upload stores r as artifact name of the task running with ctx.
+func Upload(ctx context.Context, name, contentType string, r io.Reader) (*Artifact, error) {
+	scope, ok := ctx.Value(taskKey{}).(*taskScope)
+	if !ok || scope.store == nil {
+		return nil, ErrNoStore
+	}
+	return scope.store.Put(ctx, scope.workflowID, scope.taskID, name, contentType, r)
+}
+
+// Download opens artifact name of task taskID in the workflow of the task
+// running with ctx, typically one of its dependencies.
+func Download(ctx context.Context, taskID, name string) (*Artifact, io.ReadCloser, error) {
+	scope, ok := ctx.Value(taskKey{}).(*taskScope)
+	if !ok || scope.store == nil {
+		return nil, nil, ErrNoStore
+	}
+	a, rc, err := scope.store.Open(ctx, scope.workflowID, taskID, name)
+	if err == nil && scope.onDownload != nil {
+		scope.onDownload(a)
+	}
+	return a, rc, err
+}
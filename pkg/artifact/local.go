@@ -0,0 +1,113 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// tempPrefix marks files being written by LocalBackend.Put.
+const tempPrefix = ".tmp-"
+
+// LocalBackend stores objects as files under a root directory.
+type LocalBackend struct {
+	root string
+}
+
+// NewLocalBackend returns a backend storing objects under dir, creating it
+// if needed.
+func NewLocalBackend(dir string) (*LocalBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("artifact: create directory: %w", err)
+	}
+	return &LocalBackend{root: dir}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place, so
+// readers never see a partial object.
+func (b *LocalBackend) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	target := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("wrote %d bytes, want %d", written, size)
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// Get opens the object's file.
+func (b *LocalBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(b.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Delete removes the object's file and the directories it leaves empty.
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	err := os.Remove(b.path(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for dir := path.Dir(key); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if os.Remove(b.path(dir)) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// List walks the directory holding prefix.
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	dir := "."
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = prefix[:i]
+	}
+
+	var keys []string
+	err := filepath.WalkDir(b.path(dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(b.root, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return ctx.Err()
+	})
+	return keys, err
+}
+
+func (b *LocalBackend) path(key string) string {
+	return filepath.Join(b.root, filepath.FromSlash(key))
+}
@@ -0,0 +1,188 @@
+package artifact
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/awsauth"
+)
+
+// S3Options configures an S3Backend.
+type S3Options struct {
+	// Bucket is the bucket name.
+	Bucket string
+
+	// Region is the bucket's region.
+	Region string
+
+	// Endpoint overrides https://s3.<region>.amazonaws.com, for S3-compatible
+	// services such as MinIO.
+	Endpoint string
+
+	// Prefix is prepended to every object key.
+	Prefix string
+
+	// Credentials sign the requests.
+	Credentials awsauth.Credentials
+
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// S3Backend stores objects in an S3 bucket, addressed path-style.
+type S3Backend struct {
+	opts S3Options
+}
+
+// NewS3Backend returns a backend storing objects in the bucket of opts.
+func NewS3Backend(opts S3Options) *S3Backend {
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	opts.Endpoint = strings.TrimRight(opts.Endpoint, "/")
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &S3Backend{opts: opts}
+}
+
+// Put uploads the object. The payload is not signed so it can be streamed.
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	req, err := b.newRequest(ctx, http.MethodPut, b.objectURL(key), r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads the object.
+func (b *S3Backend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := b.newRequest(ctx, http.MethodGet, b.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete deletes the object.
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	req, err := b.newRequest(ctx, http.MethodDelete, b.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// listBucketResult is the ListObjectsV2 response.
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2.
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {b.opts.Prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		listURL := b.opts.Endpoint + "/" + awsauth.Escape(b.opts.Bucket, false) + "?" + encodeQuery(query)
+		req, err := b.newRequest(ctx, http.MethodGet, listURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := b.do(req)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode list response: %w", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(object.Key, b.opts.Prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (b *S3Backend) objectURL(key string) string {
+	return b.opts.Endpoint + "/" + awsauth.Escape(b.opts.Bucket, false) + "/" + awsauth.Escape(b.opts.Prefix+key, true)
+}
+
+func (b *S3Backend) newRequest(ctx context.Context, method, rawURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awsauth.UnsignedPayload)
+	awsauth.Sign(req, awsauth.UnsignedPayload, b.opts.Region, "s3", b.opts.Credentials, time.Now())
+	return req, nil
+}
+
+// do sends req and turns error responses into errors, ErrNotFound for 404.
+func (b *S3Backend) do(req *http.Request) (*http.Response, error) {
+	resp, err := b.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// encodeQuery encodes query the way SigV4 canonicalizes it, so the signed
+// and the sent query strings match.
+func encodeQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsauth.Escape(key, false)+"="+awsauth.Escape(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,104 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/awsauth"
+)
+
+// fakeS3 serves the object operations S3Backend uses from memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key, isObject := strings.CutPrefix(r.URL.Path, "/artifacts/")
+	switch {
+	case r.Method == http.MethodGet && !isObject:
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, k := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", k)
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Backend_StoreRoundTrip(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	backend := NewS3Backend(S3Options{
+		Bucket:      "artifacts",
+		Region:      "us-east-1",
+		Endpoint:    server.URL,
+		Prefix:      "goclaw/",
+		Credentials: awsauth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	store := NewStore(backend)
+	ctx := context.Background()
+
+	if _, err := store.Put(ctx, "wf-1", "build", "my report.txt", "text/plain", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := fake.objects["goclaw/data/wf-1/build/my report.txt"]; !ok {
+		t.Fatalf("expected prefixed object key, got %v", fake.objects)
+	}
+
+	list, err := store.List(ctx, "wf-1")
+	if err != nil || len(list) != 1 || list[0].Name != "my report.txt" {
+		t.Fatalf("List() = %+v, %v", list, err)
+	}
+	_, rc, err := store.Open(ctx, "wf-1", "build", "my report.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+
+	if err := store.Delete(ctx, "wf-1", "build", "my report.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Stat(ctx, "wf-1", "build", "my report.txt"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package awsauth
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSign_AWSExample checks the signature of the example request in the AWS
+// Signature Version 4 documentation.
+func TestSign_AWSExample(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Version=2010-05-08&Action=ListUsers", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	Sign(req, PayloadHash(nil), "us-east-1", "iam", creds, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestSign_SessionToken(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://s3.amazonaws.com/bucket/key", nil)
+	Sign(req, UnsignedPayload, "us-east-1", "s3", Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "TOKEN"}, time.Now())
+
+	if req.Header.Get("X-Amz-Security-Token") != "TOKEN" {
+		t.Fatal("expected session token header")
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "x-amz-security-token") {
+		t.Fatalf("expected session token to be signed, got %q", req.Header.Get("Authorization"))
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := Escape("wf 1/task+a~b.c", true); got != "wf%201/task%2Ba~b.c" {
+		t.Fatalf("Escape(keepSlash) = %q", got)
+	}
+	if got := Escape("a/b", false); got != "a%2Fb" {
+		t.Fatalf("Escape() = %q", got)
+	}
+}
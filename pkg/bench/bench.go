@@ -0,0 +1,353 @@
+// Package bench generates synthetic workflow load against Goclaw and measures
+// throughput, latency and lane queue growth, so that performance regressions
+// show up as numbers.
+//
+// Workflows are layered DAGs submitted synchronously at a fixed rate to a
+// Target: a running server reached over the HTTP API, or an in-process engine
+// whose tasks sleep for a configurable time.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/stats"
+)
+
+// Config describes the load to generate.
+type Config struct {
+	// Layers is the number of DAG layers per workflow.
+	Layers int
+
+	// Width is the number of tasks per layer.
+	Width int
+
+	// FanIn is the number of tasks of the previous layer each task depends
+	// on. Zero makes every task independent.
+	FanIn int
+
+	// Rate is the number of workflows submitted per second.
+	Rate float64
+
+	// Duration is how long workflows are submitted for.
+	Duration time.Duration
+
+	// MaxInFlight bounds the outstanding submissions; submissions due while
+	// it is reached are skipped and counted. Zero means unbounded.
+	MaxInFlight int
+
+	// SampleInterval is how often lane statistics are polled.
+	SampleInterval time.Duration
+
+	// Seed makes the generated dependencies reproducible.
+	Seed int64
+}
+
+// DefaultConfig returns a small load.
+func DefaultConfig() Config {
+	return Config{
+		Layers:         3,
+		Width:          4,
+		FanIn:          2,
+		Rate:           10,
+		Duration:       30 * time.Second,
+		MaxInFlight:    1000,
+		SampleInterval: time.Second,
+		Seed:           1,
+	}
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	switch {
+	case c.Layers < 1:
+		return fmt.Errorf("layers must be at least 1, got %d", c.Layers)
+	case c.Width < 1:
+		return fmt.Errorf("width must be at least 1, got %d", c.Width)
+	case c.FanIn < 0 || c.FanIn > c.Width:
+		return fmt.Errorf("fan-in must be between 0 and width (%d), got %d", c.Width, c.FanIn)
+	case c.Rate <= 0:
+		return fmt.Errorf("rate must be positive, got %v", c.Rate)
+	case c.Duration <= 0:
+		return fmt.Errorf("duration must be positive, got %v", c.Duration)
+	case c.MaxInFlight < 0:
+		return fmt.Errorf("max in-flight cannot be negative, got %d", c.MaxInFlight)
+	case c.SampleInterval <= 0:
+		return fmt.Errorf("sample interval must be positive, got %v", c.SampleInterval)
+	}
+	return nil
+}
+
+// Report summarizes a benchmark run.
+type Report struct {
+	// Submitted is the number of workflows sent to the target.
+	Submitted int `json:"submitted"`
+
+	// Completed is the number of workflows that completed.
+	Completed int `json:"completed"`
+
+	// Failed is the number of workflows that ended failed or cancelled.
+	Failed int `json:"failed"`
+
+	// Accepted is the number of workflows the target accepted without
+	// running them to a terminal status. A server only executes workflows
+	// whose tasks it has executors for; the others stay pending.
+	Accepted int `json:"accepted"`
+
+	// Errors is the number of submissions rejected by the target or lost to
+	// transport errors.
+	Errors int `json:"errors"`
+
+	// Skipped is the number of submissions not sent because MaxInFlight was
+	// reached, a sign that the target cannot keep up with the rate.
+	Skipped int `json:"skipped"`
+
+	// ElapsedSeconds is the wall-clock time from the first submission until
+	// the last response.
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+
+	// Throughput is the number of workflows handled per second, i.e. those
+	// completed, failed or accepted.
+	Throughput float64 `json:"throughput"`
+
+	// TaskThroughput is the number of tasks of handled workflows per second.
+	TaskThroughput float64 `json:"task_throughput"`
+
+	// Latency summarizes the time from submission to the target's response
+	// for handled workflows. For workflows that ran, the response arrives
+	// when they reach a terminal status.
+	Latency LatencySummary `json:"latency_ms"`
+
+	// Lanes reports the queue growth of every lane while load was generated.
+	Lanes []LaneGrowth `json:"lanes"`
+}
+
+// LatencySummary holds latency statistics in milliseconds.
+type LatencySummary struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// LaneGrowth reports how the pending queue of a lane evolved.
+type LaneGrowth struct {
+	Name string `json:"name"`
+
+	// StartPending is the queue length before load was generated.
+	StartPending int `json:"start_pending"`
+
+	// PeakPending is the largest queue length sampled.
+	PeakPending int `json:"peak_pending"`
+
+	// EndPending is the queue length when submission stopped.
+	EndPending int `json:"end_pending"`
+
+	// GrowthPerSec is the average change of the queue length per second of
+	// submission. A sustained positive value means the lane falls behind.
+	GrowthPerSec float64 `json:"growth_per_sec"`
+}
+
+// Workflow returns the seq-th synthetic workflow for cfg. Task i of layer l
+// depends on FanIn distinct tasks of layer l-1, picked with rng.
+func Workflow(cfg Config, seq int, rng *rand.Rand) *models.WorkflowRequest {
+	req := &models.WorkflowRequest{
+		Name:     "bench",
+		Metadata: map[string]string{"bench_seq": fmt.Sprintf("%d", seq)},
+		Tasks:    make([]models.TaskDefinition, 0, cfg.Layers*cfg.Width),
+	}
+	for l := 0; l < cfg.Layers; l++ {
+		for i := 0; i < cfg.Width; i++ {
+			task := models.TaskDefinition{
+				ID:   taskID(l, i),
+				Name: taskID(l, i),
+				Type: "function",
+			}
+			if l > 0 && cfg.FanIn > 0 {
+				for _, dep := range rng.Perm(cfg.Width)[:cfg.FanIn] {
+					task.DependsOn = append(task.DependsOn, taskID(l-1, dep))
+				}
+			}
+			req.Tasks = append(req.Tasks, task)
+		}
+	}
+	return req
+}
+
+func taskID(layer, index int) string {
+	return fmt.Sprintf("l%d-t%d", layer, index)
+}
+
+// outcome is the result of one submission.
+type outcome struct {
+	latency time.Duration
+	status  string
+	err     error
+}
+
+// Run generates the load described by cfg against target and reports the
+// measurements.
+func Run(ctx context.Context, cfg Config, target Target) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	start, err := target.LanePending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lane statistics: %w", err)
+	}
+	growth := newGrowthTracker(start)
+
+	var (
+		mu       sync.Mutex
+		outcomes []outcome
+		wg       sync.WaitGroup
+		inFlight = make(chan struct{}, max(cfg.MaxInFlight, 1))
+		report   = &Report{}
+	)
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	submitCtx, stop := context.WithTimeout(ctx, cfg.Duration)
+	defer stop()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+	sampler := time.NewTicker(cfg.SampleInterval)
+	defer sampler.Stop()
+
+	began := time.Now()
+	for seq := 0; submitCtx.Err() == nil; {
+		select {
+		case <-submitCtx.Done():
+			continue
+		case <-sampler.C:
+			if sample, err := target.LanePending(submitCtx); err == nil {
+				growth.observe(sample)
+			}
+			continue
+		case <-ticker.C:
+		}
+
+		req := Workflow(cfg, seq, rng)
+		seq++
+		if cfg.MaxInFlight > 0 {
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				report.Skipped++
+				continue
+			}
+		}
+		report.Submitted++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cfg.MaxInFlight > 0 {
+				defer func() { <-inFlight }()
+			}
+			started := time.Now()
+			status, err := target.Submit(ctx, req)
+			o := outcome{latency: time.Since(started), status: status, err: err}
+			mu.Lock()
+			outcomes = append(outcomes, o)
+			mu.Unlock()
+		}()
+	}
+	submitted := time.Since(began)
+	if end, err := target.LanePending(ctx); err == nil {
+		growth.observe(end)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(began)
+
+	latencies := make([]time.Duration, 0, len(outcomes))
+	for _, o := range outcomes {
+		switch {
+		case o.err != nil:
+			report.Errors++
+			continue
+		case o.status == "completed":
+			report.Completed++
+		case o.status == "failed" || o.status == "cancelled":
+			report.Failed++
+		default:
+			report.Accepted++
+		}
+		latencies = append(latencies, o.latency)
+	}
+	report.ElapsedSeconds = elapsed.Seconds()
+	report.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	report.TaskThroughput = report.Throughput * float64(cfg.Layers*cfg.Width)
+	report.Latency = summarize(latencies)
+	report.Lanes = growth.report(submitted)
+	return report, nil
+}
+
+// growthTracker follows the pending queue of each lane across samples.
+type growthTracker struct {
+	lanes map[string]*LaneGrowth
+}
+
+func newGrowthTracker(start map[string]int) *growthTracker {
+	g := &growthTracker{lanes: make(map[string]*LaneGrowth, len(start))}
+	for name, pending := range start {
+		g.lanes[name] = &LaneGrowth{Name: name, StartPending: pending, PeakPending: pending, EndPending: pending}
+	}
+	return g
+}
+
+// observe records a sample; lanes appearing mid-run start from zero.
+func (g *growthTracker) observe(sample map[string]int) {
+	for name, pending := range sample {
+		lane, ok := g.lanes[name]
+		if !ok {
+			lane = &LaneGrowth{Name: name}
+			g.lanes[name] = lane
+		}
+		lane.PeakPending = max(lane.PeakPending, pending)
+		lane.EndPending = pending
+	}
+}
+
+// report returns the growth of every lane over window, sorted by name.
+func (g *growthTracker) report(window time.Duration) []LaneGrowth {
+	out := make([]LaneGrowth, 0, len(g.lanes))
+	for _, lane := range g.lanes {
+		growth := *lane
+		if window > 0 {
+			growth.GrowthPerSec = float64(growth.EndPending-growth.StartPending) / window.Seconds()
+		}
+		out = append(out, growth)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// summarize computes latency statistics of samples.
+func summarize(samples []time.Duration) LatencySummary {
+	if len(samples) == 0 {
+		return LatencySummary{}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+	return LatencySummary{
+		Mean: ms(total / time.Duration(len(samples))),
+		P50:  ms(stats.Percentile(samples, 0.50)),
+		P90:  ms(stats.Percentile(samples, 0.90)),
+		P99:  ms(stats.Percentile(samples, 0.99)),
+		Max:  ms(samples[len(samples)-1]),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,157 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestWorkflow_Shape(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Layers, cfg.Width, cfg.FanIn = 3, 4, 2
+
+	req := Workflow(cfg, 7, rand.New(rand.NewSource(1)))
+	if len(req.Tasks) != 12 {
+		t.Fatalf("expected 12 tasks, got %d", len(req.Tasks))
+	}
+	for _, task := range req.Tasks {
+		wantDeps := 2
+		if strings.HasPrefix(task.ID, "l0-") {
+			wantDeps = 0
+		}
+		if len(task.DependsOn) != wantDeps {
+			t.Fatalf("task %s has %d deps, want %d", task.ID, len(task.DependsOn), wantDeps)
+		}
+		for _, dep := range task.DependsOn {
+			if dep[1] != task.ID[1]-1 {
+				t.Fatalf("task %s depends on %s outside the previous layer", task.ID, dep)
+			}
+		}
+	}
+
+	again := Workflow(cfg, 7, rand.New(rand.NewSource(1)))
+	for i := range req.Tasks {
+		if strings.Join(req.Tasks[i].DependsOn, ",") != strings.Join(again.Tasks[i].DependsOn, ",") {
+			t.Fatalf("same seed generated different dependencies for %s", req.Tasks[i].ID)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	cfg.FanIn = cfg.Width + 1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected fan-in above width to be rejected")
+	}
+}
+
+func TestRun(t *testing.T) {
+	var submissions, lanePolls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/workflows":
+			var req models.WorkflowRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Tasks) != 4 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			n := submissions.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			status := "completed"
+			switch n % 4 {
+			case 0:
+				status = "failed"
+			case 1:
+				status = "pending"
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(models.WorkflowResponse{ID: req.Metadata["bench_seq"], Status: status})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/lanes":
+			pending := int(lanePolls.Add(1))
+			_ = json.NewEncoder(w).Encode(models.LaneListResponse{
+				Lanes: []models.LaneStats{{Name: "default", Pending: pending}},
+				Count: 1,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.Layers, cfg.Width, cfg.FanIn = 2, 2, 1
+	cfg.Rate = 200
+	cfg.Duration = 200 * time.Millisecond
+	cfg.SampleInterval = 20 * time.Millisecond
+
+	report, err := Run(context.Background(), cfg, NewHTTPTarget(srv.URL+"/", srv.Client()))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Submitted == 0 || report.Errors != 0 {
+		t.Fatalf("unexpected submissions: %+v", report)
+	}
+	if report.Completed+report.Failed+report.Accepted != report.Submitted || report.Failed == 0 || report.Accepted == 0 {
+		t.Fatalf("outcomes do not add up: %+v", report)
+	}
+	if report.Latency.P99 < 5 || report.Latency.P99 < report.Latency.P50 || report.Latency.Max < report.Latency.P99 {
+		t.Fatalf("unexpected latency summary: %+v", report.Latency)
+	}
+	if report.Throughput <= 0 || report.TaskThroughput != report.Throughput*4 {
+		t.Fatalf("unexpected throughput: %v tasks %v", report.Throughput, report.TaskThroughput)
+	}
+	if len(report.Lanes) != 1 || report.Lanes[0].StartPending != 1 || report.Lanes[0].GrowthPerSec <= 0 {
+		t.Fatalf("unexpected lane growth: %+v", report.Lanes)
+	}
+}
+
+func TestRun_ServerUnavailable(t *testing.T) {
+	if _, err := Run(context.Background(), DefaultConfig(), NewHTTPTarget("http://127.0.0.1:0", nil)); err == nil {
+		t.Fatal("expected error when the server is unreachable")
+	}
+}
+
+func TestRun_EngineTarget(t *testing.T) {
+	eng, err := engine.New(config.DefaultConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	cfg := DefaultConfig()
+	cfg.Rate = 100
+	cfg.Duration = 100 * time.Millisecond
+	cfg.SampleInterval = 10 * time.Millisecond
+
+	report, err := Run(context.Background(), cfg, NewEngineTarget(eng, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Submitted == 0 || report.Completed != report.Submitted {
+		t.Fatalf("expected every workflow to complete: %+v", report)
+	}
+	if report.Latency.P50 < 3 {
+		t.Fatalf("latency %vms shorter than three sequential layers of 1ms tasks", report.Latency.P50)
+	}
+	if len(report.Lanes) == 0 {
+		t.Fatal("expected lane statistics from the engine")
+	}
+}
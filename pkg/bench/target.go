@@ -0,0 +1,144 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/engine"
+)
+
+// Target is the system a benchmark puts under load.
+type Target interface {
+	// Submit submits req, waits for the response and returns the workflow
+	// status it reports.
+	Submit(ctx context.Context, req *models.WorkflowRequest) (string, error)
+
+	// LanePending returns the pending queue length of every lane.
+	LanePending(ctx context.Context) (map[string]int, error)
+}
+
+// HTTPTarget submits workflows to a running server through its HTTP API.
+type HTTPTarget struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPTarget creates a target for the server at baseURL, e.g.
+// http://localhost:8080. Pass a nil client to use http.DefaultClient.
+func NewHTTPTarget(baseURL string, client *http.Client) *HTTPTarget {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTarget{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// Submit implements Target.
+func (t *HTTPTarget) Submit(ctx context.Context, req *models.WorkflowRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/api/v1/workflows", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	var wfResp models.WorkflowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&wfResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return wfResp.Status, nil
+}
+
+// LanePending implements Target.
+func (t *HTTPTarget) LanePending(ctx context.Context) (map[string]int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+"/api/v1/lanes", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var list models.LaneListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode lanes: %w", err)
+	}
+	pending := make(map[string]int, len(list.Lanes))
+	for _, l := range list.Lanes {
+		pending[l.Name] = l.Pending
+	}
+	return pending, nil
+}
+
+// EngineTarget runs workflows on an in-process engine. Every task sleeps for
+// the configured duration, so workflows execute end to end.
+type EngineTarget struct {
+	engine   *engine.Engine
+	taskTime time.Duration
+}
+
+// NewEngineTarget creates a target for the started engine eng whose tasks
+// take taskTime each.
+func NewEngineTarget(eng *engine.Engine, taskTime time.Duration) *EngineTarget {
+	return &EngineTarget{engine: eng, taskTime: taskTime}
+}
+
+// Submit implements Target.
+func (t *EngineTarget) Submit(ctx context.Context, req *models.WorkflowRequest) (string, error) {
+	work := func(ctx context.Context) error {
+		if t.taskTime <= 0 {
+			return nil
+		}
+		select {
+		case <-time.After(t.taskTime):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	taskFns := make(map[string]func(context.Context) error, len(req.Tasks))
+	for _, task := range req.Tasks {
+		taskFns[task.ID] = work
+	}
+	resp, err := t.engine.SubmitWorkflowRuntime(ctx, req, engine.SubmitWorkflowOptions{
+		Mode:    engine.SubmissionModeSync,
+		TaskFns: taskFns,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Status, nil
+}
+
+// LanePending implements Target.
+func (t *EngineTarget) LanePending(context.Context) (map[string]int, error) {
+	stats := t.engine.LaneStats()
+	pending := make(map[string]int, len(stats))
+	for _, s := range stats {
+		pending[s.Name] = s.Pending
+	}
+	return pending, nil
+}
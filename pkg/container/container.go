@@ -0,0 +1,167 @@
+// Package container runs "container" tasks as containers, on a Docker
+// daemon or as Kubernetes Jobs.
+//
+// The Executor turns a task's container spec, resources and environment
+// into a Job for a Runtime, streams the container output into the task log
+// and maps the exit code to the task outcome: zero completes the task, any
+// other code fails the attempt with an *ExitError, which is retried like any
+// other task failure.
+package container
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/engine"
+)
+
+// Labels set on every container, identifying its task.
+const (
+	LabelWorkflowID = "goclaw.io/workflow-id"
+	LabelTaskID     = "goclaw.io/task-id"
+)
+
+// Job is one container run.
+type Job struct {
+	// Name is unique per run and valid as a DNS label.
+	Name string
+	// Image is the container image reference.
+	Image string
+	// Command overrides the image entrypoint when set.
+	Command []string
+	// Args are the entrypoint arguments.
+	Args []string
+	// Env is the container environment.
+	Env map[string]string
+	// Labels identify the task the container runs.
+	Labels map[string]string
+	// CPU is the CPU limit in cores; zero means none.
+	CPU float64
+	// MemoryMB is the memory limit in megabytes; zero means none.
+	MemoryMB int64
+	// GPU is the number of GPUs to attach.
+	GPU int
+}
+
+// Runtime runs containers.
+type Runtime interface {
+	// Run runs job to completion, writing its output to stdout and stderr,
+	// and returns its exit code. Cancelling ctx stops the container.
+	Run(ctx context.Context, job Job, stdout, stderr io.Writer) (int, error)
+}
+
+// ExitError is the error of a container that exited with a non-zero code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	if e.Code == 137 {
+		return "container exited with code 137 (killed, possibly out of memory)"
+	}
+	return fmt.Sprintf("container exited with code %d", e.Code)
+}
+
+// Executor runs container tasks with a Runtime. It implements
+// engine.TaskExecutor.
+type Executor struct {
+	runtime Runtime
+}
+
+// NewExecutor returns an executor running containers with runtime.
+func NewExecutor(runtime Runtime) *Executor {
+	return &Executor{runtime: runtime}
+}
+
+// TaskFunc returns the function running task as a container. Each line the
+// container writes counts as a heartbeat of the task.
+func (x *Executor) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if task.Container == nil || task.Container.Image == "" {
+			return fmt.Errorf("container task %s has no image", task.ID)
+		}
+
+		env := maps.Clone(task.Container.Env)
+		if env == nil {
+			env = make(map[string]string)
+		}
+		maps.Copy(env, engine.TaskEnv(ctx))
+
+		job := Job{
+			Name:     jobName(task.ID),
+			Image:    task.Container.Image,
+			Command:  task.Container.Command,
+			Args:     task.Container.Args,
+			Env:      env,
+			Labels:   map[string]string{LabelWorkflowID: labelValue(workflowID), LabelTaskID: labelValue(task.ID)},
+			CPU:      task.Resources.CPU,
+			MemoryMB: task.Resources.MemoryMB,
+			GPU:      task.Resources.GPU,
+		}
+
+		stdout := engine.TaskLog(ctx, engine.TaskLogStdout)
+		stderr := engine.TaskLog(ctx, engine.TaskLogStderr)
+		defer stdout.Close()
+		defer stderr.Close()
+
+		code, err := x.runtime.Run(ctx, job, heartbeatWriter{ctx, stdout}, heartbeatWriter{ctx, stderr})
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return &ExitError{Code: code}
+		}
+		return nil
+	}
+}
+
+// heartbeatWriter heartbeats the task on every write.
+type heartbeatWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (h heartbeatWriter) Write(p []byte) (int, error) {
+	engine.Heartbeat(h.ctx)
+	return h.w.Write(p)
+}
+
+// jobName returns a unique DNS label for a run of taskID.
+func jobName(taskID string) string {
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	name := labelValue(strings.ToLower(taskID))
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	name = strings.Trim(strings.ReplaceAll(strings.ReplaceAll(name, "_", "-"), ".", "-"), "-")
+	if name == "" {
+		name = "task"
+	}
+	return "goclaw-" + name + "-" + hex.EncodeToString(suffix[:])
+}
+
+// labelValue returns s restricted to the characters and length Kubernetes
+// allows in label values.
+func labelValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	v := b.String()
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return strings.Trim(v, "-_.")
+}
@@ -0,0 +1,109 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+)
+
+// fakeRuntime records the job it runs and exits with code.
+type fakeRuntime struct {
+	job    Job
+	output string
+	code   int
+	err    error
+}
+
+func (f *fakeRuntime) Run(ctx context.Context, job Job, stdout, stderr io.Writer) (int, error) {
+	f.job = job
+	io.WriteString(stdout, f.output)
+	return f.code, f.err
+}
+
+func containerTask() *dag.Task {
+	return &dag.Task{
+		ID:        "Build_Image",
+		Agent:     dag.AgentContainer,
+		Resources: dag.Resources{CPU: 0.5, MemoryMB: 256, GPU: 1},
+		Container: &dag.ContainerSpec{
+			Image:   "alpine:3.20",
+			Command: []string{"sh", "-c"},
+			Args:    []string{"echo hi"},
+			Env:     map[string]string{"MODE": "release"},
+		},
+	}
+}
+
+func TestExecutor_RunsJob(t *testing.T) {
+	rt := &fakeRuntime{output: "hi\n"}
+	fn := NewExecutor(rt).TaskFunc("wf-1", containerTask())
+	if err := fn(context.Background()); err != nil {
+		t.Fatalf("task error = %v", err)
+	}
+
+	job := rt.job
+	if job.Image != "alpine:3.20" || len(job.Command) != 2 || job.Args[0] != "echo hi" {
+		t.Fatalf("job = %+v", job)
+	}
+	if job.Env["MODE"] != "release" {
+		t.Fatalf("job env = %v", job.Env)
+	}
+	if job.CPU != 0.5 || job.MemoryMB != 256 || job.GPU != 1 {
+		t.Fatalf("job resources = %v %v %v", job.CPU, job.MemoryMB, job.GPU)
+	}
+	if job.Labels[LabelWorkflowID] != "wf-1" || job.Labels[LabelTaskID] != "Build_Image" {
+		t.Fatalf("job labels = %v", job.Labels)
+	}
+	if !regexp.MustCompile(`^goclaw-build-image-[0-9a-f]{8}$`).MatchString(job.Name) {
+		t.Fatalf("job name = %q", job.Name)
+	}
+}
+
+func TestExecutor_MapsExitCode(t *testing.T) {
+	fn := NewExecutor(&fakeRuntime{code: 137}).TaskFunc("wf-1", containerTask())
+	err := fn(context.Background())
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 137 {
+		t.Fatalf("task error = %v, want exit code 137", err)
+	}
+	if !strings.Contains(err.Error(), "out of memory") {
+		t.Fatalf("error = %q", err)
+	}
+
+	runErr := errors.New("daemon unreachable")
+	fn = NewExecutor(&fakeRuntime{err: runErr}).TaskFunc("wf-1", containerTask())
+	if err := fn(context.Background()); !errors.Is(err, runErr) {
+		t.Fatalf("task error = %v, want %v", err, runErr)
+	}
+}
+
+func TestExecutor_RequiresImage(t *testing.T) {
+	task := containerTask()
+	task.Container = nil
+	rt := &fakeRuntime{}
+	if err := NewExecutor(rt).TaskFunc("wf-1", task)(context.Background()); err == nil {
+		t.Fatal("expected error for a task without image")
+	}
+	if rt.job.Image != "" {
+		t.Fatal("runtime ran a task without image")
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	tests := map[string]string{
+		"build":                 "build",
+		"a/b c":                 "a-b-c",
+		"-edge-":                "edge",
+		strings.Repeat("x", 70): strings.Repeat("x", 63),
+	}
+	for in, want := range tests {
+		if got := labelValue(in); got != want {
+			t.Errorf("labelValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
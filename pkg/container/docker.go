@@ -0,0 +1,266 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dockerAPIVersion is the Docker Engine API version requests use.
+const dockerAPIVersion = "v1.41"
+
+// removeTimeout bounds the removal of a container after its run.
+const removeTimeout = 30 * time.Second
+
+// DockerRuntime runs containers through the Docker Engine API.
+type DockerRuntime struct {
+	client *http.Client
+	base   string
+	pull   bool
+}
+
+// NewDockerRuntime returns a runtime talking to the Docker daemon at host,
+// a unix:///path or tcp://host:port address. With pull, images missing on
+// the daemon are pulled before their containers are created.
+func NewDockerRuntime(host string, pull bool) (*DockerRuntime, error) {
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		socket := strings.TrimPrefix(host, "unix://")
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &DockerRuntime{client: &http.Client{Transport: transport}, base: "http://docker", pull: pull}, nil
+	case strings.HasPrefix(host, "tcp://"):
+		return &DockerRuntime{client: &http.Client{}, base: "http://" + strings.TrimPrefix(host, "tcp://"), pull: pull}, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker host %q", host)
+	}
+}
+
+// dockerCreateRequest is the body of POST /containers/create.
+type dockerCreateRequest struct {
+	Image      string            `json:"Image"`
+	Entrypoint []string          `json:"Entrypoint,omitempty"`
+	Cmd        []string          `json:"Cmd,omitempty"`
+	Env        []string          `json:"Env,omitempty"`
+	Labels     map[string]string `json:"Labels,omitempty"`
+	HostConfig dockerHostConfig  `json:"HostConfig"`
+}
+
+type dockerHostConfig struct {
+	NanoCPUs       int64                 `json:"NanoCpus,omitempty"`
+	Memory         int64                 `json:"Memory,omitempty"`
+	DeviceRequests []dockerDeviceRequest `json:"DeviceRequests,omitempty"`
+}
+
+type dockerDeviceRequest struct {
+	Count        int        `json:"Count"`
+	Capabilities [][]string `json:"Capabilities"`
+}
+
+// Run creates, starts and follows a container, then removes it.
+func (d *DockerRuntime) Run(ctx context.Context, job Job, stdout, stderr io.Writer) (int, error) {
+	if d.pull {
+		if err := d.ensureImage(ctx, job.Image); err != nil {
+			return 0, err
+		}
+	}
+
+	create := dockerCreateRequest{
+		Image:      job.Image,
+		Entrypoint: job.Command,
+		Cmd:        job.Args,
+		Env:        envList(job.Env),
+		Labels:     job.Labels,
+		HostConfig: dockerHostConfig{
+			NanoCPUs: int64(job.CPU * 1e9),
+			Memory:   job.MemoryMB << 20,
+		},
+	}
+	if job.GPU > 0 {
+		create.HostConfig.DeviceRequests = []dockerDeviceRequest{{Count: job.GPU, Capabilities: [][]string{{"gpu"}}}}
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := d.call(ctx, http.MethodPost, "/containers/create?name="+url.QueryEscape(job.Name), create, &created); err != nil {
+		return 0, fmt.Errorf("create container: %w", err)
+	}
+	defer func() {
+		// Force removal also kills a container still running after ctx was
+		// cancelled.
+		removeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), removeTimeout)
+		defer cancel()
+		d.call(removeCtx, http.MethodDelete, "/containers/"+created.ID+"?force=1", nil, nil)
+	}()
+
+	if err := d.call(ctx, http.MethodPost, "/containers/"+created.ID+"/start", nil, nil); err != nil {
+		return 0, fmt.Errorf("start container: %w", err)
+	}
+
+	logs, err := d.do(ctx, http.MethodGet, "/containers/"+created.ID+"/logs?follow=1&stdout=1&stderr=1", nil)
+	if err != nil {
+		return 0, fmt.Errorf("follow container logs: %w", err)
+	}
+	err = demuxDockerStream(logs.Body, stdout, stderr)
+	logs.Body.Close()
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read container logs: %w", err)
+	}
+
+	var waited struct {
+		StatusCode int `json:"StatusCode"`
+		Error      *struct {
+			Message string `json:"Message"`
+		} `json:"Error"`
+	}
+	if err := d.call(ctx, http.MethodPost, "/containers/"+created.ID+"/wait", nil, &waited); err != nil {
+		return 0, fmt.Errorf("wait for container: %w", err)
+	}
+	if waited.Error != nil && waited.Error.Message != "" {
+		return 0, fmt.Errorf("wait for container: %s", waited.Error.Message)
+	}
+	return waited.StatusCode, nil
+}
+
+// ensureImage pulls image unless the daemon has it.
+func (d *DockerRuntime) ensureImage(ctx context.Context, image string) error {
+	resp, err := d.do(ctx, http.MethodGet, "/images/"+image+"/json", nil)
+	if err == nil {
+		resp.Body.Close()
+		return nil
+	}
+	if !isDockerNotFound(err) {
+		return fmt.Errorf("inspect image: %w", err)
+	}
+
+	resp, err = d.do(ctx, http.MethodPost, "/images/create?fromImage="+url.QueryEscape(image), nil)
+	if err != nil {
+		return fmt.Errorf("pull image %s: %w", image, err)
+	}
+	defer resp.Body.Close()
+	// Pull failures are reported in the progress stream, not the status.
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var progress struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &progress) == nil && progress.Error != "" {
+			return fmt.Errorf("pull image %s: %s", image, progress.Error)
+		}
+	}
+	return scanner.Err()
+}
+
+// dockerError is an error response of the Docker API.
+type dockerError struct {
+	status  int
+	message string
+}
+
+func (e *dockerError) Error() string {
+	return fmt.Sprintf("docker API status %d: %s", e.status, e.message)
+}
+
+func isDockerNotFound(err error) bool {
+	de, ok := err.(*dockerError)
+	return ok && de.status == http.StatusNotFound
+}
+
+// call sends a JSON request and decodes the JSON response into out, if set.
+func (d *DockerRuntime) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	resp, err := d.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (d *DockerRuntime) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, d.base+"/"+dockerAPIVersion+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var msg struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &msg) != nil || msg.Message == "" {
+			msg.Message = strings.TrimSpace(string(data))
+		}
+		return nil, &dockerError{status: resp.StatusCode, message: msg.Message}
+	}
+	return resp, nil
+}
+
+// demuxDockerStream splits the multiplexed log stream of a container
+// without a TTY into stdout and stderr. Each frame is an 8-byte header,
+// holding the stream in byte 0 and the big-endian payload size in bytes
+// 4-7, followed by the payload.
+func demuxDockerStream(r io.Reader, stdout, stderr io.Writer) error {
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		w := stdout
+		if header[0] == 2 {
+			w = stderr
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(w, r, size); err != nil {
+			return err
+		}
+	}
+}
+
+// envList returns env as sorted NAME=value pairs.
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for name, value := range env {
+		list = append(list, name+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
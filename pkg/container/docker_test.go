@@ -0,0 +1,113 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDocker serves the Docker API calls DockerRuntime makes for one
+// container.
+type fakeDocker struct {
+	mu       sync.Mutex
+	pulled   bool
+	create   dockerCreateRequest
+	removed  bool
+	exitCode int
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+dockerAPIVersion)
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/images/"):
+		if !f.pulled {
+			http.Error(w, `{"message":"No such image"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && path == "/images/create":
+		f.pulled = true
+		w.Write([]byte(`{"status":"Pulling"}` + "\n" + `{"status":"Done"}` + "\n"))
+	case r.Method == http.MethodPost && path == "/containers/create":
+		json.NewDecoder(r.Body).Decode(&f.create)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"c1"}`))
+	case r.Method == http.MethodPost && path == "/containers/c1/start":
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && path == "/containers/c1/logs":
+		w.Write(dockerFrame(1, "building\n"))
+		w.Write(dockerFrame(2, "warning\n"))
+	case r.Method == http.MethodPost && path == "/containers/c1/wait":
+		json.NewEncoder(w).Encode(map[string]int{"StatusCode": f.exitCode})
+	case r.Method == http.MethodDelete && path == "/containers/c1":
+		f.removed = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"message":"unexpected request"}`, http.StatusBadRequest)
+	}
+}
+
+func dockerFrame(stream byte, payload string) []byte {
+	frame := make([]byte, 8, 8+len(payload))
+	frame[0] = stream
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestDockerRuntime_Run(t *testing.T) {
+	fake := &fakeDocker{exitCode: 3}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	rt, err := NewDockerRuntime("tcp://"+srv.Listener.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("NewDockerRuntime() error = %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	code, err := rt.Run(context.Background(), Job{
+		Name:     "goclaw-build-1",
+		Image:    "alpine",
+		Args:     []string{"make"},
+		Env:      map[string]string{"B": "2", "A": "1"},
+		CPU:      1.5,
+		MemoryMB: 64,
+		GPU:      1,
+	}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if code != 3 {
+		t.Fatalf("Run() code = %d, want 3", code)
+	}
+	if stdout.String() != "building\n" || stderr.String() != "warning\n" {
+		t.Fatalf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.pulled || !fake.removed {
+		t.Fatalf("pulled = %v, removed = %v", fake.pulled, fake.removed)
+	}
+	hc := fake.create.HostConfig
+	if hc.NanoCPUs != 1.5e9 || hc.Memory != 64<<20 || len(hc.DeviceRequests) != 1 {
+		t.Fatalf("host config = %+v", hc)
+	}
+	if strings.Join(fake.create.Env, ",") != "A=1,B=2" {
+		t.Fatalf("env = %v", fake.create.Env)
+	}
+}
+
+func TestNewDockerRuntime_RejectsHost(t *testing.T) {
+	if _, err := NewDockerRuntime("npipe:////./pipe/docker_engine", false); err == nil {
+		t.Fatal("expected error for an unsupported host")
+	}
+}
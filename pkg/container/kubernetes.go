@@ -0,0 +1,281 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/kube"
+)
+
+// jobContainer is the name of the container in a Job's pod.
+const jobContainer = "task"
+
+// defaultPollInterval is how often pod state is polled.
+const defaultPollInterval = time.Second
+
+// KubernetesOptions configures a KubernetesRuntime.
+type KubernetesOptions struct {
+	// APIServer is the API server URL. When empty, the in-cluster address
+	// from KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT is used.
+	APIServer string
+
+	// Namespace is the namespace Jobs are created in.
+	Namespace string
+
+	// TokenFile holds the bearer token. It is re-read on every request, as
+	// projected service account tokens are rotated.
+	TokenFile string
+
+	// CAFile is the CA bundle verifying the API server. A missing file
+	// falls back to the system roots.
+	CAFile string
+
+	// ServiceAccount is the service account Job pods run as.
+	ServiceAccount string
+
+	// TTLAfterFinished is how long finished Jobs are kept. Zero keeps them.
+	TTLAfterFinished time.Duration
+
+	// HTTPClient sends the requests. Defaults to a client trusting CAFile.
+	HTTPClient *http.Client
+
+	// PollInterval is how often pod state is polled. Defaults to 1s.
+	PollInterval time.Duration
+}
+
+// KubernetesRuntime runs containers as Kubernetes Jobs.
+type KubernetesRuntime struct {
+	opts   KubernetesOptions
+	client *kube.Client
+}
+
+// NewKubernetesRuntime returns a runtime creating Jobs as opts describes.
+func NewKubernetesRuntime(opts KubernetesOptions) (*KubernetesRuntime, error) {
+	if opts.Namespace == "" {
+		return nil, errors.New("kubernetes namespace is required")
+	}
+	client, err := kube.NewClient(kube.Options{
+		APIServer:  opts.APIServer,
+		TokenFile:  opts.TokenFile,
+		CAFile:     opts.CAFile,
+		HTTPClient: opts.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	return &KubernetesRuntime{opts: opts, client: client}, nil
+}
+
+// Run creates a Job running job, follows its pod and returns the exit code
+// of its container. The Job is deleted when the run fails or ctx is
+// cancelled; finished Jobs are left to TTLAfterFinished.
+func (k *KubernetesRuntime) Run(ctx context.Context, job Job, stdout, stderr io.Writer) (code int, err error) {
+	if err := k.client.Call(ctx, http.MethodPost, k.jobsPath(), k.manifest(job), nil); err != nil {
+		return 0, fmt.Errorf("create job: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		deleteCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), removeTimeout)
+		defer cancel()
+		k.client.Call(deleteCtx, http.MethodDelete, k.jobsPath()+"/"+job.Name+"?propagationPolicy=Background", nil, nil)
+	}()
+
+	pod, err := k.waitForPod(ctx, job.Name, func(p *k8sPod) bool { return p.started() })
+	if err != nil {
+		return 0, err
+	}
+
+	// Kubernetes merges stdout and stderr into one log.
+	logs, err := k.client.Do(ctx, http.MethodGet, k.podsPath()+"/"+pod.Metadata.Name+"/log?follow=true&container="+jobContainer, nil)
+	if err != nil {
+		return 0, fmt.Errorf("follow pod logs: %w", err)
+	}
+	_, err = io.Copy(stdout, logs.Body)
+	logs.Body.Close()
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read pod logs: %w", err)
+	}
+
+	pod, err = k.waitForPod(ctx, job.Name, func(p *k8sPod) bool { return p.terminated() != nil })
+	if err != nil {
+		return 0, err
+	}
+	return pod.terminated().ExitCode, nil
+}
+
+// waitForPod polls the pod of a Job until done reports true. It fails on
+// errors that keep the container from ever starting.
+func (k *KubernetesRuntime) waitForPod(ctx context.Context, jobName string, done func(*k8sPod) bool) (*k8sPod, error) {
+	ticker := time.NewTicker(k.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		var pods struct {
+			Items []k8sPod `json:"items"`
+		}
+		selector := url.QueryEscape("job-name=" + jobName)
+		if err := k.client.Call(ctx, http.MethodGet, k.podsPath()+"?labelSelector="+selector, nil, &pods); err != nil {
+			return nil, fmt.Errorf("get job pod: %w", err)
+		}
+		if len(pods.Items) > 0 {
+			pod := &pods.Items[0]
+			if done(pod) {
+				return pod, nil
+			}
+			if reason, msg := pod.waitingError(); reason != "" {
+				return nil, fmt.Errorf("container cannot start: %s: %s", reason, msg)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// manifest returns the batch/v1 Job running job.
+func (k *KubernetesRuntime) manifest(job Job) map[string]interface{} {
+	container := map[string]interface{}{
+		"name":  jobContainer,
+		"image": job.Image,
+	}
+	if len(job.Command) > 0 {
+		container["command"] = job.Command
+	}
+	if len(job.Args) > 0 {
+		container["args"] = job.Args
+	}
+	if len(job.Env) > 0 {
+		env := make([]map[string]string, 0, len(job.Env))
+		for _, pair := range envList(job.Env) {
+			name, value, _ := strings.Cut(pair, "=")
+			env = append(env, map[string]string{"name": name, "value": value})
+		}
+		container["env"] = env
+	}
+	limits := map[string]string{}
+	if job.CPU > 0 {
+		limits["cpu"] = strconv.FormatInt(int64(job.CPU*1000), 10) + "m"
+	}
+	if job.MemoryMB > 0 {
+		limits["memory"] = strconv.FormatInt(job.MemoryMB, 10) + "Mi"
+	}
+	if job.GPU > 0 {
+		limits["nvidia.com/gpu"] = strconv.Itoa(job.GPU)
+	}
+	if len(limits) > 0 {
+		container["resources"] = map[string]interface{}{"limits": limits}
+	}
+
+	podSpec := map[string]interface{}{
+		"restartPolicy": "Never",
+		"containers":    []interface{}{container},
+	}
+	if k.opts.ServiceAccount != "" {
+		podSpec["serviceAccountName"] = k.opts.ServiceAccount
+	}
+	spec := map[string]interface{}{
+		// Retries are the engine's, not the Job's.
+		"backoffLimit": 0,
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": job.Labels},
+			"spec":     podSpec,
+		},
+	}
+	if k.opts.TTLAfterFinished > 0 {
+		spec["ttlSecondsAfterFinished"] = int64(k.opts.TTLAfterFinished / time.Second)
+	}
+	return map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":      job.Name,
+			"namespace": k.opts.Namespace,
+			"labels":    job.Labels,
+		},
+		"spec": spec,
+	}
+}
+
+// k8sPod is the part of a Pod the runtime reads.
+type k8sPod struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		Phase             string `json:"phase"`
+		ContainerStatuses []struct {
+			Name  string `json:"name"`
+			State struct {
+				Waiting *struct {
+					Reason  string `json:"reason"`
+					Message string `json:"message"`
+				} `json:"waiting"`
+				Terminated *k8sTerminated `json:"terminated"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+type k8sTerminated struct {
+	ExitCode int    `json:"exitCode"`
+	Reason   string `json:"reason"`
+}
+
+// started reports whether the pod's container has started, so its logs
+// can be followed.
+func (p *k8sPod) started() bool {
+	switch p.Status.Phase {
+	case "Running", "Succeeded", "Failed":
+		return true
+	}
+	return false
+}
+
+// terminated returns the final state of the pod's container, if it ended.
+func (p *k8sPod) terminated() *k8sTerminated {
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Name == jobContainer {
+			return cs.State.Terminated
+		}
+	}
+	return nil
+}
+
+// waitingError returns why the pod's container cannot start, if it never
+// will without intervention.
+func (p *k8sPod) waitingError() (reason, message string) {
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Name != jobContainer || cs.State.Waiting == nil {
+			continue
+		}
+		switch cs.State.Waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+			return cs.State.Waiting.Reason, cs.State.Waiting.Message
+		}
+	}
+	return "", ""
+}
+
+func (k *KubernetesRuntime) jobsPath() string {
+	return "/apis/batch/v1/namespaces/" + k.opts.Namespace + "/jobs"
+}
+
+func (k *KubernetesRuntime) podsPath() string {
+	return "/api/v1/namespaces/" + k.opts.Namespace + "/pods"
+}
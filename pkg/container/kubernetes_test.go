@@ -0,0 +1,151 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKubernetes serves the Job and Pod calls KubernetesRuntime makes. Its
+// pod is pending on the first poll, then runs and terminates with exitCode
+// or waits with waiting.
+type fakeKubernetes struct {
+	mu       sync.Mutex
+	job      map[string]interface{}
+	polls    int
+	exitCode int
+	waiting  string
+	deleted  bool
+	token    string
+}
+
+func (f *fakeKubernetes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.token = r.Header.Get("Authorization")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/apis/batch/v1/namespaces/ci/jobs":
+		json.NewDecoder(r.Body).Decode(&f.job)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ci/pods":
+		f.polls++
+		status := map[string]interface{}{"phase": "Pending"}
+		switch {
+		case f.polls > 1 && f.waiting != "":
+			status["containerStatuses"] = []interface{}{map[string]interface{}{
+				"name":  "task",
+				"state": map[string]interface{}{"waiting": map[string]string{"reason": f.waiting, "message": "pull failed"}},
+			}}
+		case f.polls > 1:
+			status["phase"] = "Failed"
+			status["containerStatuses"] = []interface{}{map[string]interface{}{
+				"name":  "task",
+				"state": map[string]interface{}{"terminated": map[string]interface{}{"exitCode": f.exitCode}},
+			}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{map[string]interface{}{
+			"metadata": map[string]string{"name": "pod-1"},
+			"status":   status,
+		}}})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ci/pods/pod-1/log":
+		w.Write([]byte("step 1\nstep 2\n"))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/apis/batch/v1/namespaces/ci/jobs/"):
+		f.deleted = true
+		w.Write([]byte(`{}`))
+	default:
+		http.Error(w, `{"message":"unexpected request"}`, http.StatusBadRequest)
+	}
+}
+
+func newTestKubernetesRuntime(t *testing.T, fake *fakeKubernetes) *KubernetesRuntime {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rt, err := NewKubernetesRuntime(KubernetesOptions{
+		APIServer:        srv.URL,
+		Namespace:        "ci",
+		TokenFile:        tokenFile,
+		ServiceAccount:   "runner",
+		TTLAfterFinished: 10 * time.Minute,
+		PollInterval:     time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewKubernetesRuntime() error = %v", err)
+	}
+	return rt
+}
+
+func TestKubernetesRuntime_Run(t *testing.T) {
+	fake := &fakeKubernetes{exitCode: 2}
+	rt := newTestKubernetesRuntime(t, fake)
+
+	var stdout bytes.Buffer
+	code, err := rt.Run(context.Background(), Job{
+		Name:     "goclaw-test-1",
+		Image:    "alpine",
+		Command:  []string{"make"},
+		Env:      map[string]string{"A": "1"},
+		Labels:   map[string]string{LabelTaskID: "test"},
+		CPU:      0.25,
+		MemoryMB: 512,
+	}, &stdout, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if code != 2 {
+		t.Fatalf("Run() code = %d, want 2", code)
+	}
+	if stdout.String() != "step 1\nstep 2\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.token != "Bearer secret" {
+		t.Fatalf("Authorization = %q", fake.token)
+	}
+	if fake.deleted {
+		t.Fatal("finished job was deleted")
+	}
+	spec := fake.job["spec"].(map[string]interface{})
+	if spec["backoffLimit"] != float64(0) || spec["ttlSecondsAfterFinished"] != float64(600) {
+		t.Fatalf("job spec = %v", spec)
+	}
+	pod := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	if pod["restartPolicy"] != "Never" || pod["serviceAccountName"] != "runner" {
+		t.Fatalf("pod spec = %v", pod)
+	}
+	limits := pod["containers"].([]interface{})[0].(map[string]interface{})["resources"].(map[string]interface{})["limits"].(map[string]interface{})
+	if limits["cpu"] != "250m" || limits["memory"] != "512Mi" {
+		t.Fatalf("limits = %v", limits)
+	}
+}
+
+func TestKubernetesRuntime_ImagePullFailure(t *testing.T) {
+	fake := &fakeKubernetes{waiting: "ErrImagePull"}
+	rt := newTestKubernetesRuntime(t, fake)
+
+	_, err := rt.Run(context.Background(), Job{Name: "goclaw-test-2", Image: "missing"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "ErrImagePull") {
+		t.Fatalf("Run() error = %v, want ErrImagePull", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.deleted {
+		t.Fatal("failed job was not deleted")
+	}
+}
@@ -0,0 +1,112 @@
+// Package contract types the data tasks pass each other with protobuf
+// messages. A Contract pairs the input and output message of a task;
+// protoc-gen-goclaw generates one per method of a proto service, so task
+// functions and the tasks reading their results agree on the types at
+// compile time. The engine stores results in their protojson form, where
+// output schemas, the API and other tasks read them like any other result.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Contract is the input and output message of a task.
+type Contract[In, Out proto.Message] struct {
+	name   string
+	newIn  func() In
+	newOut func() Out
+}
+
+// New returns the contract name whose input and output messages newIn and
+// newOut allocate. Generated code calls it; use the generated contracts.
+func New[In, Out proto.Message](name string, newIn func() In, newOut func() Out) Contract[In, Out] {
+	return Contract[In, Out]{name: name, newIn: newIn, newOut: newOut}
+}
+
+// Name returns the contract name, the full name of its proto method.
+func (c Contract[In, Out]) Name() string { return c.name }
+
+// TaskFunc returns a task function running fn. Its input is the result of
+// the task's dependency, or an empty message when it has none; tasks with
+// several dependencies read them with Result instead. The output fn returns
+// is stored as the task's result.
+func (c Contract[In, Out]) TaskFunc(fn func(context.Context, In) (Out, error)) func(context.Context) error {
+	return func(ctx context.Context) error {
+		in, err := c.input(ctx)
+		if err != nil {
+			return err
+		}
+		out, err := fn(ctx, in)
+		if err != nil {
+			return err
+		}
+		return c.SetResult(ctx, out)
+	}
+}
+
+// input decodes the result of the single dependency of the task running with
+// ctx.
+func (c Contract[In, Out]) input(ctx context.Context) (In, error) {
+	deps, err := engine.TaskDependencies(ctx)
+	if err != nil {
+		return c.newIn(), err
+	}
+	switch len(deps) {
+	case 0:
+		return c.newIn(), nil
+	case 1:
+		in := c.newIn()
+		if err := read(ctx, deps[0], in); err != nil {
+			return in, fmt.Errorf("%s input: %w", c.name, err)
+		}
+		return in, nil
+	default:
+		return c.newIn(), errs.Newf(errs.BadRequest, "%s input: task has %d dependencies, want at most 1", c.name, len(deps))
+	}
+}
+
+// Result returns the output of the completed task taskID of the running
+// workflow, a task running with this contract, and records it as an input
+// of the task running with ctx.
+func (c Contract[In, Out]) Result(ctx context.Context, taskID string) (Out, error) {
+	out := c.newOut()
+	if err := read(ctx, taskID, out); err != nil {
+		return out, fmt.Errorf("%s result of task %s: %w", c.name, taskID, err)
+	}
+	return out, nil
+}
+
+// SetResult stores out as the result of the task running with ctx, in its
+// protojson form.
+func (c Contract[In, Out]) SetResult(ctx context.Context, out Out) error {
+	data, err := protojson.Marshal(out)
+	if err != nil {
+		return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("encode %s output", c.name))
+	}
+	return engine.SetTaskResult(ctx, json.RawMessage(data))
+}
+
+// read decodes the result of task taskID into m. Fields m does not know
+// are skipped, so producers can add fields before their consumers learn
+// them.
+func read(ctx context.Context, taskID string, m proto.Message) error {
+	result, err := engine.UpstreamResult(ctx, taskID)
+	if err != nil || result == nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, m); err != nil {
+		return errs.Wrap(err, errs.BadRequest, "decode result")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package contract
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var (
+	count = New("test.Pipeline.Count",
+		func() *wrapperspb.StringValue { return new(wrapperspb.StringValue) },
+		func() *wrapperspb.Int64Value { return new(wrapperspb.Int64Value) },
+	)
+	format = New("test.Pipeline.Format",
+		func() *wrapperspb.Int64Value { return new(wrapperspb.Int64Value) },
+		func() *wrapperspb.StringValue { return new(wrapperspb.StringValue) },
+	)
+)
+
+func TestContract_PassesTypedResults(t *testing.T) {
+	cfg := &config.Config{
+		App:           config.AppConfig{Name: "test", Environment: "development"},
+		Orchestration: config.OrchestrationConfig{MaxAgents: 4},
+	}
+	eng, err := engine.New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("engine.New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var joined string
+	req := &models.WorkflowRequest{
+		Name: "contracts",
+		Tasks: []models.TaskDefinition{
+			{ID: "count", Name: "count", Type: "function"},
+			{ID: "format", Name: "format", Type: "function", DependsOn: []string{"count"}},
+			{ID: "join", Name: "join", Type: "function", DependsOn: []string{"count", "format"}},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, req, engine.SubmitWorkflowOptions{Mode: engine.SubmissionModeSync, TaskFns: map[string]func(context.Context) error{
+		"count": count.TaskFunc(func(_ context.Context, in *wrapperspb.StringValue) (*wrapperspb.Int64Value, error) {
+			if in.GetValue() != "" {
+				t.Errorf("count input = %q, want an empty message", in.GetValue())
+			}
+			return wrapperspb.Int64(42), nil
+		}),
+		"format": format.TaskFunc(func(_ context.Context, in *wrapperspb.Int64Value) (*wrapperspb.StringValue, error) {
+			return wrapperspb.String("rows: " + strconv.FormatInt(in.GetValue(), 10)), nil
+		}),
+		"join": func(ctx context.Context) error {
+			if err := count.TaskFunc(nil)(ctx); !errs.Is(err, errs.BadRequest) {
+				t.Errorf("TaskFunc() with two dependencies error = %v, want BadRequest", err)
+			}
+			rows, err := count.Result(ctx, "count")
+			if err != nil {
+				return err
+			}
+			text, err := format.Result(ctx, "format")
+			if err != nil {
+				return err
+			}
+			joined = strconv.FormatInt(rows.GetValue(), 10) + " " + text.GetValue()
+			return nil
+		},
+	}})
+	if err != nil || resp.Status != "completed" {
+		t.Fatalf("SubmitWorkflowRuntime() = %+v, %v", resp, err)
+	}
+	if joined != "42 rows: 42" {
+		t.Fatalf("join read %q, want the typed results", joined)
+	}
+
+	result, err := eng.GetTaskResultResponse(ctx, resp.ID, "count")
+	if err != nil {
+		t.Fatalf("GetTaskResultResponse() error = %v", err)
+	}
+	// Int64Value is a string in its protojson form.
+	if result.Result != "42" {
+		t.Fatalf("stored result = %#v, want the protojson payload", result.Result)
+	}
+	if _, err := count.Result(ctx, "count"); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("Result() outside a task error = %v, want BadRequest", err)
+	}
+}
@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of holiday dates.
+const DateLayout = "2006-01-02"
+
+// Calendar excludes fire times of a schedule: holidays, which are whole
+// days in the time zone of the fire time, and blackout windows.
+type Calendar struct {
+	holidays  map[string]bool
+	blackouts []Blackout
+}
+
+// Blackout is a window in which a schedule does not fire: once from Start to
+// End, or for Duration from every time Schedule matches.
+type Blackout struct {
+	Start, End time.Time
+
+	Schedule *Schedule
+	Duration time.Duration
+}
+
+// NewCalendar returns a calendar of the holidays, in DateLayout, and the
+// blackout windows.
+func NewCalendar(holidays []string, blackouts []Blackout) (*Calendar, error) {
+	c := &Calendar{holidays: make(map[string]bool, len(holidays)), blackouts: blackouts}
+	for _, day := range holidays {
+		if _, err := time.Parse(DateLayout, day); err != nil {
+			return nil, fmt.Errorf("holiday %q is not a YYYY-MM-DD date", day)
+		}
+		c.holidays[day] = true
+	}
+	for _, b := range blackouts {
+		switch {
+		case b.Schedule != nil && b.Duration <= 0:
+			return nil, fmt.Errorf("recurring blackout needs a positive duration")
+		case b.Schedule == nil && !b.End.After(b.Start):
+			return nil, fmt.Errorf("blackout ends at %s, not after its start", b.End.Format(time.RFC3339))
+		}
+	}
+	return c, nil
+}
+
+// Excludes reports whether t falls on a holiday or in a blackout window.
+// Recurring windows are evaluated in t's location.
+func (c *Calendar) Excludes(t time.Time) bool {
+	if c.holidays[t.Format(DateLayout)] {
+		return true
+	}
+	for _, b := range c.blackouts {
+		if b.Schedule == nil {
+			if !t.Before(b.Start) && t.Before(b.End) {
+				return true
+			}
+			continue
+		}
+		// The window contains t if it started after t-Duration and by t.
+		if start := b.Schedule.Next(t.Add(-b.Duration)); !start.IsZero() && !start.After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package cron
+
+import "time"
+
+// Catch-up policies decide which fire times missed while the scheduler was
+// down still run.
+const (
+	// CatchUpSkip runs none of them.
+	CatchUpSkip = "skip"
+	// CatchUpRunOnce runs the latest of them, unless a fire time is on
+	// time. It is the default.
+	CatchUpRunOnce = "run-once"
+	// CatchUpRunAll runs each of them, oldest first, up to MaxCatchUp.
+	CatchUpRunAll = "run-all"
+)
+
+// MaxCatchUp bounds the missed fire times CatchUpRunAll runs; older ones
+// are skipped.
+const MaxCatchUp = 100
+
+// maxExcludedSearch bounds the fire times NextIncluded looks at.
+const maxExcludedSearch = 10000
+
+// Pending are the due fire times of a schedule.
+type Pending struct {
+	// Run are the fire times to run, oldest first.
+	Run []time.Time
+	// Last is the latest due fire time, whether it runs or not.
+	Last time.Time
+	// Missed counts the missed fire times the catch-up policy skipped.
+	Missed int
+	// Excluded counts the fire times the calendar excluded.
+	Excluded int
+}
+
+// Due returns the fire times of s after last and by now. Fire times exclude
+// reports never run. Fire times more than grace before now were missed,
+// and run as policy decides; the others are on time and always run.
+// exclude may be nil.
+func (s *Schedule) Due(last, now time.Time, grace time.Duration, policy string, exclude func(time.Time) bool) Pending {
+	var p Pending
+	var missed []time.Time
+	for t := s.Next(last); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		p.Last = t
+		switch {
+		case exclude != nil && exclude(t):
+			p.Excluded++
+		case now.Sub(t) > grace:
+			missed = append(missed, t)
+		default:
+			p.Run = append(p.Run, t)
+		}
+	}
+
+	switch policy {
+	case CatchUpSkip:
+		p.Missed = len(missed)
+	case CatchUpRunAll:
+		if len(missed) > MaxCatchUp {
+			p.Missed = len(missed) - MaxCatchUp
+			missed = missed[p.Missed:]
+		}
+		p.Run = append(missed, p.Run...)
+	default:
+		p.Missed = len(missed)
+		if len(missed) > 0 && len(p.Run) == 0 {
+			p.Missed--
+			p.Run = missed[len(missed)-1:]
+		}
+	}
+	return p
+}
+
+// NextIncluded returns the first time after t the schedule matches that
+// exclude does not exclude, or the zero time if there is none.
+func (s *Schedule) NextIncluded(t time.Time, exclude func(time.Time) bool) time.Time {
+	for i := 0; i < maxExcludedSearch; i++ {
+		t = s.Next(t)
+		if t.IsZero() || exclude == nil || !exclude(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC) // a Saturday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 3, 14, 13, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 6 * * 1-5", time.Date(2026, 3, 16, 6, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 1", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_NextNever(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("Next() = %v, want zero", got)
+	}
+}
+
+func TestCron_ParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCalendar_Excludes(t *testing.T) {
+	weekly, _ := Parse("0 2 * * 0")
+	cal, err := NewCalendar([]string{"2026-12-25"}, []Blackout{
+		{Start: time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{Schedule: weekly, Duration: 2 * time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(2026, 12, 25, 23, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 10, 8, 30, 0, 0, time.UTC), true},
+		{time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC), true}, // a Sunday
+		{time.Date(2026, 3, 15, 3, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 3, 15, 4, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 16, 2, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := cal.Excludes(tt.t); got != tt.want {
+			t.Errorf("Excludes(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+
+	// Holidays are days in the time zone of the fire time.
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if !cal.Excludes(time.Date(2026, 12, 24, 23, 30, 0, 0, time.UTC).In(berlin)) {
+		t.Error("Excludes() of Christmas in Berlin = false, want true")
+	}
+
+	if _, err := NewCalendar([]string{"25.12.2026"}, nil); err == nil {
+		t.Error("NewCalendar() with a malformed holiday succeeded")
+	}
+	if _, err := NewCalendar(nil, []Blackout{{Schedule: weekly}}); err == nil {
+		t.Error("NewCalendar() with a recurring blackout without duration succeeded")
+	}
+}
+
+func TestSchedule_Due(t *testing.T) {
+	hourly, _ := Parse("@hourly")
+	last := time.Date(2026, 3, 14, 0, 30, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 14, 5, 0, 20, 0, time.UTC)
+	skip4 := func(t time.Time) bool { return t.Hour() == 4 }
+	at := func(hours ...int) []time.Time {
+		var out []time.Time
+		for _, h := range hours {
+			out = append(out, time.Date(2026, 3, 14, h, 0, 0, 0, time.UTC))
+		}
+		return out
+	}
+
+	tests := []struct {
+		policy  string
+		now     time.Time
+		exclude func(time.Time) bool
+		run     []time.Time
+		missed  int
+	}{
+		// 05:00 is on time, 01:00 to 03:00 were missed and 04:00 excluded.
+		{CatchUpSkip, now, skip4, at(5), 3},
+		{CatchUpRunOnce, now, skip4, at(5), 3},
+		{CatchUpRunAll, now, skip4, at(1, 2, 3, 5), 0},
+		// Without an on-time fire time, run-once runs the latest missed.
+		{CatchUpRunOnce, now.Add(-time.Minute), nil, at(4), 3},
+		{CatchUpSkip, now.Add(-time.Minute), nil, nil, 4},
+	}
+	for _, tt := range tests {
+		p := hourly.Due(last, tt.now, time.Minute, tt.policy, tt.exclude)
+		if !reflect.DeepEqual(p.Run, tt.run) || p.Missed != tt.missed {
+			t.Errorf("Due(%s, %v) = run %v, missed %d; want %v, %d", tt.policy, tt.now, p.Run, p.Missed, tt.run, tt.missed)
+		}
+	}
+
+	p := hourly.Due(last, now, time.Minute, CatchUpRunOnce, skip4)
+	if p.Excluded != 1 || !p.Last.Equal(at(5)[0]) {
+		t.Errorf("Due() = excluded %d, last %v; want 1, 05:00", p.Excluded, p.Last)
+	}
+	if got := hourly.NextIncluded(at(3)[0], skip4); !got.Equal(at(5)[0]) {
+		t.Errorf("NextIncluded() = %v, want 05:00", got)
+	}
+}
@@ -344,6 +344,21 @@ func TestTask_Validate(t *testing.T) {
 			task:    &Task{ID: "t1", Name: "Task", Agent: "test", Retries: -1},
 			wantErr: true,
 		},
+		{
+			name:    "negative resources",
+			task:    &Task{ID: "t1", Name: "Task", Agent: "test", Resources: Resources{GPU: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "container without image",
+			task:    &Task{ID: "t1", Name: "Task", Agent: AgentContainer, Container: &ContainerSpec{}},
+			wantErr: true,
+		},
+		{
+			name:    "container task",
+			task:    &Task{ID: "t1", Name: "Task", Agent: AgentContainer, Container: &ContainerSpec{Image: "alpine:3"}},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -358,14 +373,34 @@ func TestTask_Validate(t *testing.T) {
 
 func TestTask_Clone(t *testing.T) {
 	original := &Task{
-		ID:       "t1",
-		Name:     "Task",
-		Agent:    "test",
-		Deps:     []string{"a", "b"},
-		Metadata: map[string]string{"key": "value"},
+		ID:          "t1",
+		Name:        "Task",
+		Agent:       "test",
+		Deps:        []string{"a", "b"},
+		DedupeKey:   "fetch:users",
+		Gang:        "train",
+		Resources:   Resources{CPU: 2, MemoryMB: 4096, GPU: 1},
+		AffinityKey: "customer-42",
+		Deadline:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Metadata:    map[string]string{"key": "value"},
 	}
 
 	cloned := original.Clone()
+	if cloned.DedupeKey != original.DedupeKey {
+		t.Errorf("clone DedupeKey = %q, want %q", cloned.DedupeKey, original.DedupeKey)
+	}
+	if cloned.Gang != original.Gang {
+		t.Errorf("clone Gang = %q, want %q", cloned.Gang, original.Gang)
+	}
+	if cloned.AffinityKey != original.AffinityKey {
+		t.Errorf("clone AffinityKey = %q, want %q", cloned.AffinityKey, original.AffinityKey)
+	}
+	if cloned.Resources != original.Resources {
+		t.Errorf("clone Resources = %+v, want %+v", cloned.Resources, original.Resources)
+	}
+	if !cloned.Deadline.Equal(original.Deadline) {
+		t.Errorf("clone Deadline = %v, want %v", cloned.Deadline, original.Deadline)
+	}
 
 	// Modify original
 	original.Deps[0] = "modified"
@@ -105,3 +105,20 @@ func (e *InvalidEdgeError) Error() string {
 func (e *InvalidEdgeError) TaskID() string {
 	return e.From
 }
+
+// GangError is returned when the tasks of a gang cannot be dispatched
+// together, because they sit in different layers or lanes.
+type GangError struct {
+	Gang   string
+	ID     string
+	Reason string
+}
+
+func (e *GangError) Error() string {
+	return fmt.Sprintf("task %s in gang %s: %s", e.ID, e.Gang, e.Reason)
+}
+
+// TaskID returns the offending task ID.
+func (e *GangError) TaskID() string {
+	return e.ID
+}
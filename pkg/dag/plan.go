@@ -67,6 +67,10 @@ func (g *Graph) Compile() (*ExecutionPlan, error) {
 		return nil, err
 	}
 
+	if err := g.validateGangs(layers); err != nil {
+		return nil, err
+	}
+
 	// Build parallel groups
 	parallelGroups := make([]TaskGroup, len(layers))
 	maxParallel := 0
@@ -100,6 +104,58 @@ func (g *Graph) Compile() (*ExecutionPlan, error) {
 	}, nil
 }
 
+// validateGangs checks that the tasks of every gang share a layer and a lane,
+// and that none of them has an affinity key, which would keep them from
+// starting together.
+func (g *Graph) validateGangs(layers [][]string) error {
+	gangs := newGangTracker()
+	for i, layer := range layers {
+		for _, id := range layer {
+			if err := gangs.check(g.tasks[id], i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gangTracker remembers the layer and lane of every gang seen so far.
+type gangTracker struct {
+	homes map[string]gangHome
+}
+
+type gangHome struct {
+	layer int
+	lane  string
+}
+
+func newGangTracker() *gangTracker {
+	return &gangTracker{homes: make(map[string]gangHome)}
+}
+
+// check validates task, found in the given layer, against the other members
+// of its gang.
+func (t *gangTracker) check(task *Task, layer int) error {
+	if task.Gang == "" {
+		return nil
+	}
+	if task.AffinityKey != "" {
+		return &GangError{Gang: task.Gang, ID: task.ID, Reason: "gang tasks cannot have an affinity key"}
+	}
+	home, ok := t.homes[task.Gang]
+	if !ok {
+		t.homes[task.Gang] = gangHome{layer: layer, lane: task.Lane}
+		return nil
+	}
+	if home.layer != layer {
+		return &GangError{Gang: task.Gang, ID: task.ID, Reason: "gang tasks must be in the same layer"}
+	}
+	if home.lane != task.Lane {
+		return &GangError{Gang: task.Gang, ID: task.ID, Reason: "gang tasks must use the same lane"}
+	}
+	return nil
+}
+
 // calculateCriticalPath finds the longest path in the DAG using dynamic programming.
 // Returns the list of task IDs representing the critical path.
 func (g *Graph) calculateCriticalPath() []string {
@@ -166,6 +222,13 @@ func (p *ExecutionPlan) GetTask(id string) (*Task, bool) {
 	return task.Clone(), true
 }
 
+// Task returns the plan's own task with the given ID, without copying it.
+// The task is shared and must not be modified; use GetTask for a private copy.
+func (p *ExecutionPlan) Task(id string) (*Task, bool) {
+	task, ok := p.taskMap[id]
+	return task, ok
+}
+
 // GetLayer returns the layer index for a given task ID.
 // Returns -1 if the task is not in the plan.
 func (p *ExecutionPlan) GetLayer(taskID string) int {
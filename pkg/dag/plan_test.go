@@ -96,6 +96,37 @@ func TestGraph_Compile_WithMissingDependency(t *testing.T) {
 	}
 }
 
+func TestGraph_Compile_Gangs(t *testing.T) {
+	g := NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test", Gang: "train"})
+	g.AddTask(&Task{ID: "b", Name: "B", Agent: "test", Gang: "train"})
+	g.AddTask(&Task{ID: "c", Name: "C", Agent: "test", Deps: []string{"a"}})
+
+	if _, err := g.Compile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g.AddTask(&Task{ID: "d", Name: "D", Agent: "test", Lane: "gpu", Gang: "train"})
+	_, err := g.Compile()
+	if gangErr, ok := err.(*GangError); !ok || gangErr.ID != "d" {
+		t.Fatalf("expected lane gang error for d, got %v", err)
+	}
+
+	g.RemoveTask("d")
+	g.AddTask(&Task{ID: "e", Name: "E", Agent: "test", Deps: []string{"c"}, Gang: "train"})
+	_, err = g.Compile()
+	if gangErr, ok := err.(*GangError); !ok || gangErr.ID != "e" {
+		t.Fatalf("expected layer gang error for e, got %v", err)
+	}
+
+	g.RemoveTask("e")
+	g.AddTask(&Task{ID: "f", Name: "F", Agent: "test", Gang: "train", AffinityKey: "customer-42"})
+	_, err = g.Compile()
+	if gangErr, ok := err.(*GangError); !ok || gangErr.ID != "f" {
+		t.Fatalf("expected affinity gang error for f, got %v", err)
+	}
+}
+
 func TestGraph_Compile_Empty(t *testing.T) {
 	g := NewGraph()
 
@@ -129,6 +160,29 @@ func TestExecutionPlan_GetTask(t *testing.T) {
 	}
 }
 
+func TestExecutionPlan_Task(t *testing.T) {
+	g := NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test"})
+
+	plan, _ := g.Compile()
+
+	task, ok := plan.Task("a")
+	if !ok || task.ID != "a" {
+		t.Fatalf("expected to find task 'a', got %v %v", task, ok)
+	}
+	again, _ := plan.Task("a")
+	if again != task {
+		t.Error("expected Task to return the shared task, not a copy")
+	}
+	if copied, _ := plan.GetTask("a"); copied == task {
+		t.Error("expected GetTask to return a copy")
+	}
+
+	if _, ok := plan.Task("nonexistent"); ok {
+		t.Error("expected not to find non-existent task")
+	}
+}
+
 func TestExecutionPlan_GetLayer(t *testing.T) {
 	g := NewGraph()
 	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test"})
@@ -0,0 +1,97 @@
+package dag
+
+import "sort"
+
+// StreamSummary describes a graph compiled with CompileStream.
+type StreamSummary struct {
+	// TotalTasks is the total number of tasks streamed.
+	TotalTasks int `json:"total_tasks"`
+
+	// TotalLayers is the number of execution layers, which is also the
+	// length of the critical path.
+	TotalLayers int `json:"total_layers"`
+
+	// MaxParallel is the size of the largest layer.
+	MaxParallel int `json:"max_parallel"`
+}
+
+// CompileStream compiles the DAG layer by layer, calling fn with every layer
+// as soon as all of its dependencies have been emitted. Layers match those of
+// Compile, with task IDs sorted within each layer.
+//
+// Unlike Compile, CompileStream keeps neither the layers nor copies of the
+// tasks, so memory stays proportional to the widest layer rather than the
+// whole plan; this suits large, programmatically generated graphs. The tasks
+// passed to fn are the graph's own and must not be modified; fn may keep the
+// slice.
+//
+// Gang constraints and cycles are detected when the stream reaches them, so fn
+// may already have received earlier layers when an error is returned. An
+// error returned by fn stops the stream and is returned as is.
+func (g *Graph) CompileStream(fn func(layer int, tasks []*Task) error) (*StreamSummary, error) {
+	summary := &StreamSummary{}
+	if len(g.tasks) == 0 {
+		return summary, nil
+	}
+
+	g.rebuildEdges()
+	for id, task := range g.tasks {
+		for _, depID := range task.Deps {
+			if _, exists := g.tasks[depID]; !exists {
+				return nil, &DependencyNotFoundError{SrcTask: id, DepID: depID}
+			}
+		}
+	}
+
+	// remaining counts the unemitted dependencies of every task not yet
+	// emitted; emitted tasks are dropped from it.
+	remaining := make(map[string]int, len(g.tasks))
+	var ready []string
+	for id := range g.tasks {
+		if n := g.inDegree[id]; n > 0 {
+			remaining[id] = n
+		} else {
+			ready = append(ready, id)
+		}
+	}
+
+	gangs := newGangTracker()
+	for layer := 0; len(ready) > 0; layer++ {
+		sort.Strings(ready)
+		tasks := make([]*Task, len(ready))
+		for i, id := range ready {
+			task := g.tasks[id]
+			if err := gangs.check(task, layer); err != nil {
+				return nil, err
+			}
+			tasks[i] = task
+		}
+		if err := fn(layer, tasks); err != nil {
+			return nil, err
+		}
+
+		summary.TotalTasks += len(tasks)
+		summary.TotalLayers++
+		summary.MaxParallel = max(summary.MaxParallel, len(tasks))
+
+		var next []string
+		for _, id := range ready {
+			for _, dependent := range g.edges[id] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					delete(remaining, dependent)
+					next = append(next, dependent)
+				}
+			}
+		}
+		ready = next
+	}
+
+	if len(remaining) > 0 {
+		if cycle, hasCycle := g.DetectCycle(); hasCycle {
+			return nil, cycle
+		}
+		return nil, &CyclicDependencyError{}
+	}
+	return summary, nil
+}
@@ -0,0 +1,131 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGraph_CompileStream_MatchesCompile(t *testing.T) {
+	g := NewGraph()
+	for l := 0; l < 4; l++ {
+		for i := 0; i < 5; i++ {
+			task := &Task{ID: fmt.Sprintf("l%d-t%d", l, i), Name: "T", Agent: "test"}
+			if l > 0 {
+				task.Deps = []string{fmt.Sprintf("l%d-t%d", l-1, (i+1)%5)}
+			}
+			if l > 1 && i == 0 {
+				task.Deps = append(task.Deps, "l0-t0")
+			}
+			g.AddTask(task)
+		}
+	}
+	g.AddTask(&Task{ID: "solo", Name: "Solo", Agent: "test"})
+
+	plan, err := g.Compile()
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	var layers [][]string
+	summary, err := g.CompileStream(func(layer int, tasks []*Task) error {
+		if layer != len(layers) {
+			t.Fatalf("layer %d streamed out of order", layer)
+		}
+		ids := make([]string, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		layers = append(layers, ids)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CompileStream() error = %v", err)
+	}
+	if !reflect.DeepEqual(layers, plan.Layers) {
+		t.Fatalf("streamed layers %v, compiled layers %v", layers, plan.Layers)
+	}
+	want := StreamSummary{TotalTasks: plan.TotalTasks, TotalLayers: plan.TotalLayers, MaxParallel: plan.MaxParallel}
+	if *summary != want {
+		t.Fatalf("summary = %+v, want %+v", *summary, want)
+	}
+}
+
+func TestGraph_CompileStream_Errors(t *testing.T) {
+	g := NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test"})
+	g.AddTask(&Task{ID: "b", Name: "B", Agent: "test", Deps: []string{"missing"}})
+	if _, err := g.CompileStream(func(int, []*Task) error { return nil }); err == nil {
+		t.Fatal("expected missing dependency error")
+	} else if _, ok := err.(*DependencyNotFoundError); !ok {
+		t.Fatalf("expected DependencyNotFoundError, got %T", err)
+	}
+
+	g = NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test"})
+	g.AddTask(&Task{ID: "b", Name: "B", Agent: "test", Deps: []string{"a", "c"}})
+	g.AddTask(&Task{ID: "c", Name: "C", Agent: "test", Deps: []string{"b"}})
+	streamed := 0
+	_, err := g.CompileStream(func(_ int, tasks []*Task) error {
+		streamed += len(tasks)
+		return nil
+	})
+	if _, ok := err.(*CyclicDependencyError); !ok {
+		t.Fatalf("expected CyclicDependencyError, got %v", err)
+	}
+	if streamed != 1 {
+		t.Fatalf("expected only the root before the cycle, streamed %d tasks", streamed)
+	}
+
+	g = NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test", Gang: "train"})
+	g.AddTask(&Task{ID: "b", Name: "B", Agent: "test", Deps: []string{"a"}, Gang: "train"})
+	if _, err := g.CompileStream(func(int, []*Task) error { return nil }); err == nil {
+		t.Fatal("expected gang error")
+	} else if gangErr, ok := err.(*GangError); !ok || gangErr.ID != "b" {
+		t.Fatalf("expected GangError for b, got %v", err)
+	}
+
+	stop := errors.New("stop")
+	g = NewGraph()
+	g.AddTask(&Task{ID: "a", Name: "A", Agent: "test"})
+	g.AddTask(&Task{ID: "b", Name: "B", Agent: "test", Deps: []string{"a"}})
+	calls := 0
+	_, err = g.CompileStream(func(int, []*Task) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected the callback error after one layer, got %v after %d calls", err, calls)
+	}
+}
+
+func BenchmarkGraph_CompileStream(b *testing.B) {
+	g := NewGraph()
+	for l := 0; l < 100; l++ {
+		for i := 0; i < 100; i++ {
+			task := &Task{ID: fmt.Sprintf("l%d-t%d", l, i), Name: "T", Agent: "test"}
+			if l > 0 {
+				task.Deps = []string{fmt.Sprintf("l%d-t%d", l-1, i)}
+			}
+			g.AddTask(task)
+		}
+	}
+	b.Run("Compile", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := g.Compile(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("CompileStream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := g.CompileStream(func(int, []*Task) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -69,16 +69,142 @@ type Task struct {
 	// Zero means no retries.
 	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
 
+	// DedupeKey is an idempotency key. Tasks sharing a key execute once and
+	// share the outcome and result, across runs when the engine remembers
+	// executions.
+	DedupeKey string `json:"dedupe_key,omitempty" yaml:"dedupe_key,omitempty"`
+
+	// Preemptible allows the task to be cancelled and requeued in favour of
+	// higher-priority work.
+	Preemptible bool `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
+
+	// Gang labels a group of tasks that must start together. All tasks of a
+	// gang must be in the same layer and lane.
+	Gang string `json:"gang,omitempty" yaml:"gang,omitempty"`
+
+	// AffinityKey routes tasks sharing the key, e.g. a customer ID, to run
+	// one at a time in submission order within their lane.
+	AffinityKey string `json:"affinity_key,omitempty" yaml:"affinity_key,omitempty"`
+
+	// Locks names the locks the task holds while it runs. It waits for all of
+	// them before it starts and releases them when it finishes.
+	Locks []string `json:"locks,omitempty" yaml:"locks,omitempty"`
+
+	// RateLimits names the rate limits each attempt of the task takes one
+	// request from before it runs.
+	RateLimits []string `json:"rate_limits,omitempty" yaml:"rate_limits,omitempty"`
+
+	// Resources is the CPU, memory and GPU the task needs while it runs. The
+	// task only starts once its lane has them free.
+	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// Deadline is when the task should have finished. Zero means no deadline.
+	Deadline time.Time `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+
+	// HeartbeatTimeout is how long a running task may go without a heartbeat
+	// before it counts as stalled. Zero disables heartbeat checks.
+	HeartbeatTimeout time.Duration `json:"heartbeat_timeout,omitempty" yaml:"heartbeat_timeout,omitempty"`
+
+	// StallPolicy is what happens to a stalled task. Empty means
+	// StallPolicyRetry.
+	StallPolicy StallPolicy `json:"stall_policy,omitempty" yaml:"stall_policy,omitempty"`
+
+	// Env names the environment bundles injected into the task.
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Secrets maps environment variable names to the named secrets injected
+	// into the task.
+	Secrets map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// When is an expression deciding whether the task runs. Empty means
+	// always.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+
+	// RetryIf is an expression deciding whether a failed attempt is retried.
+	// Empty means every failure is.
+	RetryIf string `json:"retry_if,omitempty" yaml:"retry_if,omitempty"`
+
+	// Container is the container the task runs as. Required for tasks of
+	// agent type AgentContainer.
+	Container *ContainerSpec `json:"container,omitempty" yaml:"container,omitempty"`
+
 	// Metadata contains arbitrary key-value pairs for the task.
 	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 
 	// Input defines the expected input schema (optional).
 	Input interface{} `json:"input,omitempty" yaml:"input,omitempty"`
 
-	// Output defines the expected output schema (optional).
+	// Output defines the expected output schema (optional). The engine
+	// validates task results against it when it is a JSON Schema decoded
+	// into a map.
 	Output interface{} `json:"output,omitempty" yaml:"output,omitempty"`
 }
 
+// StallPolicy is what happens to a task that stops heartbeating.
+type StallPolicy string
+
+const (
+	// StallPolicyMark flags the task as stalled but lets it keep running.
+	StallPolicyMark StallPolicy = "mark"
+	// StallPolicyRetry abandons the attempt and retries it if retries remain.
+	StallPolicyRetry StallPolicy = "retry"
+	// StallPolicyFail abandons the attempt and fails the task without retrying.
+	StallPolicyFail StallPolicy = "fail"
+)
+
+// AgentContainer is the agent type of tasks that run as a container.
+const AgentContainer = "container"
+
+// ContainerSpec describes the container a task runs as. The task's
+// Resources are its resource limits.
+type ContainerSpec struct {
+	// Image is the container image reference.
+	Image string `json:"image" yaml:"image"`
+
+	// Command overrides the image entrypoint.
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// Args are the arguments passed to the entrypoint.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+
+	// Env sets environment variables in the container. The task's
+	// environment bundles and secrets are added on top.
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// Clone returns a deep copy of s.
+func (s *ContainerSpec) Clone() *ContainerSpec {
+	if s == nil {
+		return nil
+	}
+	cloned := &ContainerSpec{Image: s.Image}
+	if s.Command != nil {
+		cloned.Command = append([]string(nil), s.Command...)
+	}
+	if s.Args != nil {
+		cloned.Args = append([]string(nil), s.Args...)
+	}
+	if s.Env != nil {
+		cloned.Env = make(map[string]string, len(s.Env))
+		for k, v := range s.Env {
+			cloned.Env[k] = v
+		}
+	}
+	return cloned
+}
+
+// Resources is the amount of compute resources a task requests.
+type Resources struct {
+	// CPU is the number of CPU cores; fractions are allowed.
+	CPU float64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+
+	// MemoryMB is the amount of memory in megabytes.
+	MemoryMB int64 `json:"memory_mb,omitempty" yaml:"memory_mb,omitempty"`
+
+	// GPU is the number of GPUs.
+	GPU int `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+}
+
 // Validate checks if the task definition is valid.
 func (t *Task) Validate() error {
 	if t.ID == "" {
@@ -96,6 +222,20 @@ func (t *Task) Validate() error {
 	if t.Retries < 0 {
 		return fmt.Errorf("task retries cannot be negative")
 	}
+	if t.HeartbeatTimeout < 0 {
+		return fmt.Errorf("task heartbeat timeout cannot be negative")
+	}
+	switch t.StallPolicy {
+	case "", StallPolicyMark, StallPolicyRetry, StallPolicyFail:
+	default:
+		return fmt.Errorf("unknown task stall policy %q", t.StallPolicy)
+	}
+	if t.Resources.CPU < 0 || t.Resources.MemoryMB < 0 || t.Resources.GPU < 0 {
+		return fmt.Errorf("task resources cannot be negative")
+	}
+	if t.Agent == AgentContainer && (t.Container == nil || t.Container.Image == "") {
+		return fmt.Errorf("container task requires an image")
+	}
 	return nil
 }
 
@@ -103,14 +243,25 @@ func (t *Task) Validate() error {
 // Slice and map fields are copied, but Input/Output are shared references.
 func (t *Task) Clone() *Task {
 	cloned := &Task{
-		ID:      t.ID,
-		Name:    t.Name,
-		Agent:   t.Agent,
-		Lane:    t.Lane,
-		Timeout: t.Timeout,
-		Retries: t.Retries,
-		Input:   t.Input,
-		Output:  t.Output,
+		ID:               t.ID,
+		Name:             t.Name,
+		Agent:            t.Agent,
+		Lane:             t.Lane,
+		Timeout:          t.Timeout,
+		Retries:          t.Retries,
+		DedupeKey:        t.DedupeKey,
+		Preemptible:      t.Preemptible,
+		Gang:             t.Gang,
+		AffinityKey:      t.AffinityKey,
+		Resources:        t.Resources,
+		Deadline:         t.Deadline,
+		HeartbeatTimeout: t.HeartbeatTimeout,
+		StallPolicy:      t.StallPolicy,
+		When:             t.When,
+		RetryIf:          t.RetryIf,
+		Container:        t.Container.Clone(),
+		Input:            t.Input,
+		Output:           t.Output,
 	}
 
 	if t.Deps != nil {
@@ -125,6 +276,28 @@ func (t *Task) Clone() *Task {
 		}
 	}
 
+	if t.Env != nil {
+		cloned.Env = make([]string, len(t.Env))
+		copy(cloned.Env, t.Env)
+	}
+
+	if t.Secrets != nil {
+		cloned.Secrets = make(map[string]string, len(t.Secrets))
+		for k, v := range t.Secrets {
+			cloned.Secrets[k] = v
+		}
+	}
+
+	if t.Locks != nil {
+		cloned.Locks = make([]string, len(t.Locks))
+		copy(cloned.Locks, t.Locks)
+	}
+
+	if t.RateLimits != nil {
+		cloned.RateLimits = make([]string, len(t.RateLimits))
+		copy(cloned.RateLimits, t.RateLimits)
+	}
+
 	return cloned
 }
 
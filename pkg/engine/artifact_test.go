@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_TasksShareArtifacts(t *testing.T) {
+	backend, err := artifact.NewLocalBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBackend() error = %v", err)
+	}
+	store := artifact.NewStore(backend)
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithArtifactStore(store))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var downloaded string
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "release",
+		Tasks: []models.TaskDefinition{
+			{ID: "build", Name: "build", Type: "function"},
+			{ID: "publish", Name: "publish", Type: "function", DependsOn: []string{"build"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"build": func(ctx context.Context) error {
+				_, err := artifact.Upload(ctx, "app.tar", "application/x-tar", strings.NewReader("binary"))
+				return err
+			},
+			"publish": func(ctx context.Context) error {
+				_, rc, err := artifact.Download(ctx, "build", "app.tar")
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				data, err := io.ReadAll(rc)
+				downloaded = string(data)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if downloaded != "binary" {
+		t.Fatalf("downloaded %q, want %q", downloaded, "binary")
+	}
+
+	list, err := store.List(ctx, resp.ID)
+	if err != nil || len(list) != 1 || list[0].TaskID != "build" {
+		t.Fatalf("List() = %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// blackboardPrefix is the prefix of the workflow values holding blackboard
+// entries. Values under it are only written through the blackboard.
+const blackboardPrefix = "blackboard/"
+
+// AnyVersion makes PublishBlackboard replace an entry whatever its version.
+const AnyVersion int64 = -1
+
+// BlackboardEntry is the value of a blackboard key. Its version starts at 1
+// and increases with every publish.
+type BlackboardEntry struct {
+	Key     string `json:"-"`
+	Value   string `json:"value"`
+	Version int64  `json:"version"`
+	// Task is the ID of the task that published the value.
+	Task string `json:"task,omitempty"`
+}
+
+// ReadBlackboard returns the entry of key on the blackboard of the workflow
+// run the task running with ctx belongs to. It reports false when the key
+// was never published or the task runs outside the engine.
+func ReadBlackboard(ctx context.Context, key string) (BlackboardEntry, bool) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return BlackboardEntry{}, false
+	}
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	return v.blackboardEntry(key)
+}
+
+// PublishBlackboard sets key to value on the blackboard of the workflow run
+// the task running with ctx belongs to, if the entry is still at version: the
+// version last read, 0 for a key not published yet, or AnyVersion. A key
+// another task published in between fails with a Conflict error; the caller
+// reads the entry again and retries with what it learned. The entry is
+// persisted with the run and sent to the subscribers of key before
+// PublishBlackboard returns.
+func PublishBlackboard(ctx context.Context, key, value string, version int64) (BlackboardEntry, error) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return BlackboardEntry{}, errs.New(errs.BadRequest, "the blackboard is only available to tasks run by the engine")
+	}
+	if err := validateBlackboardKey(key); err != nil {
+		return BlackboardEntry{}, err
+	}
+	entry := BlackboardEntry{Key: key, Value: value}
+	if out, ok := ctx.Value(outputKey{}).(*taskOutput); ok {
+		entry.Task = out.taskID
+	}
+
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	cur, exists := v.blackboardEntry(key)
+	if version != AnyVersion && version != cur.Version {
+		return cur, errs.Newf(errs.Conflict, "blackboard key %s is at version %d, not %d", key, cur.Version, version)
+	}
+	entry.Version = cur.Version + 1
+	if exists && cur.Value == value && cur.Task == entry.Task {
+		return cur, nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return BlackboardEntry{}, errs.Wrap(err, errs.BadRequest, "encode blackboard entry")
+	}
+	if err := v.setLocked(blackboardPrefix+key, string(data)); err != nil {
+		return BlackboardEntry{}, err
+	}
+	for _, ch := range v.exec.blackboardSubs[key] {
+		sendLatest(ch, entry)
+	}
+	return entry, nil
+}
+
+// SubscribeBlackboard returns a channel receiving the entry of key on the
+// blackboard of the workflow run the task running with ctx belongs to, first
+// as it is and then whenever it is published, until ctx is done. A
+// subscriber that falls behind receives only the latest entry.
+func SubscribeBlackboard(ctx context.Context, key string) (<-chan BlackboardEntry, error) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "the blackboard is only available to tasks run by the engine")
+	}
+	if err := validateBlackboardKey(key); err != nil {
+		return nil, err
+	}
+	ch := make(chan BlackboardEntry, 1)
+
+	exec := v.exec
+	exec.mu.Lock()
+	if entry, ok := v.blackboardEntry(key); ok {
+		ch <- entry
+	}
+	if exec.blackboardSubs == nil {
+		exec.blackboardSubs = make(map[string][]chan BlackboardEntry)
+	}
+	exec.blackboardSubs[key] = append(exec.blackboardSubs[key], ch)
+	exec.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		exec.mu.Lock()
+		defer exec.mu.Unlock()
+		subs := exec.blackboardSubs[key]
+		for i, sub := range subs {
+			if sub == ch {
+				exec.blackboardSubs[key] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(exec.blackboardSubs[key]) == 0 {
+			delete(exec.blackboardSubs, key)
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// blackboardEntry decodes the entry of key. The caller holds exec.mu.
+func (v *workflowValues) blackboardEntry(key string) (BlackboardEntry, bool) {
+	data, ok := v.exec.wfState.Values[blackboardPrefix+key]
+	if !ok {
+		return BlackboardEntry{Key: key}, false
+	}
+	var entry BlackboardEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return BlackboardEntry{Key: key}, false
+	}
+	entry.Key = key
+	return entry, true
+}
+
+// sendLatest sends entry on ch, replacing the entry waiting in its buffer if
+// the subscriber has not received it yet. The caller holds exec.mu, so it is
+// the only sender.
+func sendLatest(ch chan BlackboardEntry, entry BlackboardEntry) {
+	select {
+	case ch <- entry:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- entry
+}
+
+// validateBlackboardKey checks key against the workflow value limits, which
+// the prefix counts towards.
+func validateBlackboardKey(key string) error {
+	if key == "" {
+		return errs.New(errs.BadRequest, "blackboard key is required")
+	}
+	return validateWorkflowValue(blackboardPrefix+key, "")
+}
+
+// checkValueKey rejects writes of key as a plain workflow value when the
+// blackboard owns it.
+func checkValueKey(key string) error {
+	if strings.HasPrefix(key, blackboardPrefix) {
+		return errs.Newf(errs.BadRequest, "workflow value keys starting with %s are reserved for the blackboard", blackboardPrefix)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestBlackboard_CoordinatesConcurrentTasks(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var seen []BlackboardEntry
+	status, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "research",
+		Tasks: []models.TaskDefinition{
+			{ID: "propose", Name: "propose", Type: "function"},
+			{ID: "critique", Name: "critique", Type: "function"},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"propose": func(ctx context.Context) error {
+				if _, err := PublishBlackboard(ctx, "hypothesis", "draft", 0); err != nil {
+					return err
+				}
+				// A stale version loses the race instead of overwriting.
+				if _, err := PublishBlackboard(ctx, "hypothesis", "other", 0); !errs.Is(err, errs.Conflict) {
+					return errors.New("publishing at a stale version did not conflict")
+				}
+				cur, _ := ReadBlackboard(ctx, "hypothesis")
+				_, err := PublishBlackboard(ctx, "hypothesis", "refined", cur.Version)
+				return err
+			},
+			"critique": func(ctx context.Context) error {
+				subCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+				updates, err := SubscribeBlackboard(subCtx, "hypothesis")
+				if err != nil {
+					return err
+				}
+				for entry := range updates {
+					seen = append(seen, entry)
+					if entry.Value == "refined" {
+						_, err := PublishBlackboard(ctx, "verdict", "accepted", AnyVersion)
+						return err
+					}
+				}
+				return errors.New("hypothesis was not refined")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if status.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", status.Status, status.Error)
+	}
+	last := seen[len(seen)-1]
+	if last.Version != 2 || last.Task != "propose" {
+		t.Fatalf("critique saw %+v last, want version 2 published by propose", last)
+	}
+
+	stored, err := store.GetWorkflow(ctx, status.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if _, ok := stored.Values[blackboardPrefix+"verdict"]; !ok {
+		t.Fatalf("stored values = %v, want the verdict persisted", stored.Values)
+	}
+}
+
+func TestBlackboard_ReservesItsValueKeys(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.PutWorkflowValue(context.Background(), "wf", blackboardPrefix+"hypothesis", "x"); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PutWorkflowValue() of a blackboard key error = %v, want BadRequest", err)
+	}
+	if _, err := PublishBlackboard(context.Background(), "hypothesis", "x", 0); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PublishBlackboard() outside a task error = %v, want BadRequest", err)
+	}
+}
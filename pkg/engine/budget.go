@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// BudgetExceeded starts the error of runs failed for exceeding a budget.
+const BudgetExceeded = "budget exceeded"
+
+// Defaults of orchestration.budgets.
+const (
+	defaultBudgetPeriod        = 24 * time.Hour
+	defaultBudgetCheckInterval = time.Second
+)
+
+// checkBudget rejects a budget on a submission when no usage is metered to
+// enforce it.
+func (e *Engine) checkBudget(req *models.WorkflowRequest) error {
+	if req.Budget != nil && !e.cfg.Orchestration.Costs.Enabled {
+		return errs.New(errs.BadRequest, "budgets require cost accounting")
+	}
+	return nil
+}
+
+// tenantBudget returns the budget of tenant, or nil when it has none.
+func (e *Engine) tenantBudget(tenant string) *models.Budget {
+	cfg, ok := e.cfg.Orchestration.Budgets.Tenants[tenant]
+	if !ok || tenant == "" {
+		return nil
+	}
+	return &models.Budget{Tokens: cfg.Tokens, Cost: cfg.Cost, TaskSeconds: cfg.TaskSeconds}
+}
+
+// watchBudget fails exec once its usage exceeds its budget or the usage of
+// its tenant's recent runs exceeds the tenant's. It checks whenever the run's
+// tasks record usage and every check interval, until ctx is done.
+func (e *Engine) watchBudget(ctx context.Context, exec *workflowExecution) {
+	if exec.usage == nil {
+		return
+	}
+	exec.mu.Lock()
+	budget := exec.wfState.Budget
+	tenant := e.tenantOf(exec.wfState)
+	exec.mu.Unlock()
+	tenantBudget := e.tenantBudget(tenant)
+	if budget == nil && tenantBudget == nil {
+		return
+	}
+
+	interval := e.cfg.Orchestration.Budgets.CheckInterval
+	if interval <= 0 {
+		interval = defaultBudgetCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now().UTC()
+		reason := ""
+		if budget != nil {
+			reason = e.overBudget(*budget, e.liveUsage(exec, now), "the run's budget")
+		}
+		if reason == "" && tenantBudget != nil {
+			usage, err := e.budgets.tenantUsage(ctx, e, tenant, now)
+			if err != nil {
+				e.logger.Warn("failed to read tenant usage", "workflow_id", exec.workflowID, "tenant", tenant, "error", err)
+			} else {
+				reason = e.overBudget(*tenantBudget, usage, "the budget of tenant "+tenant)
+			}
+		}
+		if reason != "" {
+			e.failOverBudget(exec, BudgetExceeded+": "+reason)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-exec.usage.changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// overBudget returns why usage exceeds budget, or "" when it does not.
+func (e *Engine) overBudget(budget models.Budget, usage map[string]float64, of string) string {
+	if budget.Tokens > 0 && usage[UsageLLMTokens] > budget.Tokens {
+		return fmt.Sprintf("%g llm tokens used, over %s of %g", usage[UsageLLMTokens], of, budget.Tokens)
+	}
+	if budget.Cost > 0 {
+		if cost := e.costOf(usage); cost > budget.Cost {
+			return fmt.Sprintf("cost of %g %s, over %s of %g", cost, e.cfg.Orchestration.Costs.Currency, of, budget.Cost)
+		}
+	}
+	if budget.TaskSeconds > 0 {
+		var seconds float64
+		for dimension, amount := range usage {
+			if dimension == UsageTaskSeconds || strings.HasPrefix(dimension, UsageTaskSeconds+":") {
+				seconds += amount
+			}
+		}
+		if seconds > budget.TaskSeconds {
+			return fmt.Sprintf("%.0f task seconds used, over %s of %g", seconds, of, budget.TaskSeconds)
+		}
+	}
+	return ""
+}
+
+// liveUsage returns the usage of exec at now, counting the time its running
+// tasks have run so far.
+func (e *Engine) liveUsage(exec *workflowExecution, now time.Time) map[string]float64 {
+	usage := exec.usage.snapshot()
+	if usage == nil {
+		usage = make(map[string]float64)
+	}
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	for taskID, task := range exec.wfState.TaskStatus {
+		if task.Status == taskStatusRunning && task.StartedAt != nil {
+			usage[taskSecondsDimension(taskLane(exec.wfState, taskID))] += now.Sub(*task.StartedAt).Seconds()
+		}
+	}
+	return usage
+}
+
+// failOverBudget fails exec for reason, cancelling its unfinished tasks.
+func (e *Engine) failOverBudget(exec *workflowExecution, reason string) {
+	e.logger.Warn("workflow exceeded its budget", "workflow_id", exec.workflowID, "reason", reason)
+
+	exec.mu.Lock()
+	exec.overBudget = reason
+	var unfinished []string
+	for taskID, task := range exec.wfState.TaskStatus {
+		if !isTerminalTaskStatus(task.Status) {
+			unfinished = append(unfinished, taskID)
+		}
+	}
+	exec.mu.Unlock()
+
+	exec.cancel()
+	for _, taskID := range unfinished {
+		if err := e.transitionTask(exec, taskID, TaskStatePending, TaskStateCancelled, TaskResult{Error: errs.New(errs.Canceled, reason)}); err != nil {
+			e.logger.Warn("failed to cancel task over budget", "workflow_id", exec.workflowID, "task_id", taskID, "error", err)
+		}
+	}
+	if err := e.transitionWorkflow(exec, workflowStatusFailed, reason); err != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
+		e.logger.Error("failed to fail workflow over budget", "workflow_id", exec.workflowID, "error", err)
+	}
+}
+
+// budgetLedger keeps the usage of the recent runs of tenants with a budget.
+// The stored runs of a tenant are scanned once, on its first check; the runs
+// settled on this node after that are added as they finish.
+type budgetLedger struct {
+	mu     sync.Mutex
+	loaded map[string]bool
+	runs   map[string]map[string]settledRun // by tenant, then workflow ID
+}
+
+// settledRun is the final usage of a run.
+type settledRun struct {
+	createdAt time.Time
+	usage     map[string]float64
+}
+
+func newBudgetLedger() *budgetLedger {
+	return &budgetLedger{loaded: make(map[string]bool), runs: make(map[string]map[string]settledRun)}
+}
+
+// settle records the final usage of the run id of tenant, created at
+// createdAt.
+func (l *budgetLedger) settle(tenant, id string, createdAt time.Time, usage map[string]float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.add(tenant, id, settledRun{createdAt: createdAt, usage: usage})
+}
+
+// add records run under id for tenant. l.mu must be held.
+func (l *budgetLedger) add(tenant, id string, run settledRun) {
+	runs, ok := l.runs[tenant]
+	if !ok {
+		runs = make(map[string]settledRun)
+		l.runs[tenant] = runs
+	}
+	runs[id] = run
+}
+
+// load scans the stored runs of tenant created since since, unless they were
+// scanned before. Runs settled meanwhile keep their settled usage.
+func (l *budgetLedger) load(ctx context.Context, e *Engine, tenant string, since time.Time) error {
+	l.mu.Lock()
+	loaded := l.loaded[tenant]
+	l.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	scanned := make(map[string]settledRun)
+	err := storage.ScanWorkflows(ctx, e.storage, &storage.WorkflowFilter{}, func(wf *storage.WorkflowState) error {
+		if e.tenantOf(wf) == tenant && !wf.CreatedAt.Before(since) {
+			scanned[wf.ID] = settledRun{createdAt: wf.CreatedAt, usage: wf.Usage}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.loaded[tenant] {
+		return nil
+	}
+	l.loaded[tenant] = true
+	for id, run := range scanned {
+		if _, ok := l.runs[tenant][id]; !ok {
+			l.add(tenant, id, run)
+		}
+	}
+	return nil
+}
+
+// tenantUsage returns the usage of the runs of tenant created within the
+// budget period before now, with the live usage of those executing on this
+// node.
+func (l *budgetLedger) tenantUsage(ctx context.Context, e *Engine, tenant string, now time.Time) (map[string]float64, error) {
+	period := e.cfg.Orchestration.Budgets.Period
+	if period <= 0 {
+		period = defaultBudgetPeriod
+	}
+	since := now.Add(-period)
+	if err := l.load(ctx, e, tenant, since); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	runs := make(map[string]map[string]float64, len(l.runs[tenant]))
+	for id, run := range l.runs[tenant] {
+		if run.createdAt.Before(since) {
+			delete(l.runs[tenant], id)
+			continue
+		}
+		runs[id] = run.usage
+	}
+	l.mu.Unlock()
+
+	e.execMu.RLock()
+	execs := make([]*workflowExecution, 0, len(e.executions))
+	for _, exec := range e.executions {
+		execs = append(execs, exec)
+	}
+	e.execMu.RUnlock()
+	for _, exec := range execs {
+		exec.mu.Lock()
+		mine := e.tenantOf(exec.wfState) == tenant && !exec.wfState.CreatedAt.Before(since)
+		exec.mu.Unlock()
+		if mine && exec.usage != nil {
+			runs[exec.workflowID] = e.liveUsage(exec, now)
+		}
+	}
+
+	total := make(map[string]float64)
+	for _, usage := range runs {
+		for dimension, amount := range usage {
+			total[dimension] += amount
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func budgetConfig() *config.Config {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 4
+	cfg.Orchestration.Costs = config.CostsConfig{Enabled: true, TenantKey: "tenant"}
+	return cfg
+}
+
+func TestEngine_RunBudgetCancelsRemainingTasks(t *testing.T) {
+	eng, err := New(budgetConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:   "agent-loop",
+		Budget: &models.Budget{Tokens: 1000},
+		Tasks: []models.TaskDefinition{
+			{ID: "think", Name: "think", Type: "function"},
+			{ID: "search", Name: "search", Type: "function"},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"think": func(ctx context.Context) error {
+				// An agent loop spending tokens until it is stopped.
+				for {
+					RecordUsage(ctx, UsageLLMTokens, 300)
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(10 * time.Millisecond):
+					}
+				}
+			},
+			"search": func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusFailed || !strings.HasPrefix(resp.Error, BudgetExceeded) {
+		t.Fatalf("workflow = %s (%s), want failed over budget", resp.Status, resp.Error)
+	}
+	if resp.Budget == nil || resp.Budget.Tokens != 1000 {
+		t.Fatalf("budget = %+v", resp.Budget)
+	}
+	for _, task := range resp.Tasks {
+		if task.Status != taskStatusCancelled {
+			t.Fatalf("task %s = %s, want cancelled", task.ID, task.Status)
+		}
+	}
+}
+
+func TestEngine_TenantBudgetSpansRuns(t *testing.T) {
+	cfg := budgetConfig()
+	cfg.Orchestration.Budgets.Tenants = map[string]config.BudgetConfig{"team-a": {Tokens: 1000}}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	run := func(tenant string, tokens float64) *models.WorkflowStatusResponse {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+			Name:     "summarize",
+			Metadata: map[string]string{"tenant": tenant},
+			Tasks:    []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{
+				"a": func(ctx context.Context) error {
+					RecordUsage(ctx, UsageLLMTokens, tokens)
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		return resp
+	}
+
+	// The tasks only finish when the budget stops them.
+	if resp := run("team-a", 1200); resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, "tenant team-a") {
+		t.Fatalf("first run = %s (%s), want failed over the tenant budget", resp.Status, resp.Error)
+	}
+	if resp := run("team-a", 1); resp.Status != workflowStatusFailed {
+		t.Fatalf("run of an exhausted tenant = %s, want failed", resp.Status)
+	}
+}
+
+func TestEngine_TenantBudgetConcurrentRuns(t *testing.T) {
+	cfg := budgetConfig()
+	cfg.Orchestration.Budgets.CheckInterval = time.Minute
+	cfg.Orchestration.Budgets.Tenants = map[string]config.BudgetConfig{"team-a": {Tokens: 1000}}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	run := func(tokens float64, task func(context.Context) error) (*models.WorkflowStatusResponse, error) {
+		return eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+			Name:     "summarize",
+			Metadata: map[string]string{"tenant": "team-a"},
+			Tasks:    []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{
+				"a": func(ctx context.Context) error {
+					RecordUsage(ctx, UsageLLMTokens, tokens)
+					return task(ctx)
+				},
+			},
+		})
+	}
+
+	// Each run settles while the other reads the usage of the tenant.
+	var wg sync.WaitGroup
+	statuses := make([]string, 2)
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := run(200, func(ctx context.Context) error {
+				for range 20 {
+					RecordUsage(ctx, UsageLLMTokens, 10)
+					time.Sleep(time.Millisecond)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Errorf("SubmitWorkflowRuntime() error = %v", err)
+				return
+			}
+			statuses[i] = resp.Status
+		}()
+	}
+	wg.Wait()
+	for i, status := range statuses {
+		if status != workflowStatusCompleted {
+			t.Fatalf("run %d = %s, want completed", i, status)
+		}
+	}
+
+	// The settled 800 tokens count toward the next run.
+	resp, err := run(300, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, "tenant team-a") {
+		t.Fatalf("third run = %s (%s), want failed over the tenant budget", resp.Status, resp.Error)
+	}
+}
+
+func TestBudgetLedger_ConcurrentSettle(t *testing.T) {
+	cfg := budgetConfig()
+	cfg.Orchestration.Budgets.CheckInterval = time.Minute
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	if _, err := eng.budgets.tenantUsage(ctx, eng, "team-a", now); err != nil {
+		t.Fatalf("tenantUsage() error = %v", err)
+	}
+
+	// Two runs of the tenant settle while their budgets are checked.
+	var wg sync.WaitGroup
+	for run := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				eng.budgets.settle("team-a", fmt.Sprintf("wf-%d-%d", run, i), now, map[string]float64{UsageLLMTokens: 1})
+				if _, err := eng.budgets.tenantUsage(ctx, eng, "team-a", now); err != nil {
+					t.Errorf("tenantUsage() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	usage, err := eng.budgets.tenantUsage(ctx, eng, "team-a", now)
+	if err != nil {
+		t.Fatalf("tenantUsage() error = %v", err)
+	}
+	if usage[UsageLLMTokens] != 200 {
+		t.Fatalf("tenant tokens = %g, want 200", usage[UsageLLMTokens])
+	}
+}
+
+func TestEngine_BudgetRequiresCostAccounting(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	err = eng.ValidateWorkflowRequest(context.Background(), &models.WorkflowRequest{
+		Name:   "agent-loop",
+		Budget: &models.Budget{Cost: 5},
+		Tasks:  []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	})
+	if !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("ValidateWorkflowRequest() error = %v, want BadRequest", err)
+	}
+}
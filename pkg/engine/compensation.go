@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/saga"
+)
+
+// errNotSagaStep is the action of the steps of the sagas wrapping workflows.
+// Their tasks run in the engine, so the orchestrator never calls it.
+var errNotSagaStep = errors.New("workflow tasks run in the engine, not the saga orchestrator")
+
+// checkCompensations rejects compensations of req that cannot run: they need
+// the saga runtime, and container compensations need an image and the
+// container executor unless a compensation function runs them.
+func (e *Engine) checkCompensations(req *models.WorkflowRequest, compensationFns map[string]func(context.Context) error) error {
+	for _, task := range req.Tasks {
+		comp := task.Compensation
+		if comp == nil {
+			continue
+		}
+		if e.sagaOrchestrator == nil {
+			return errs.Newf(errs.BadRequest, "task %s: compensations require saga.enabled", task.ID)
+		}
+		if _, err := expandConfig(comp.Config, nil, false); err != nil {
+			return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("task %s compensation config", task.ID))
+		}
+		if compensationType(task) != dag.AgentContainer || compensationFns[task.ID] != nil {
+			continue
+		}
+		if comp.Container == nil || comp.Container.Image == "" {
+			return errs.Newf(errs.BadRequest, "task %s: container compensations need a container image", task.ID)
+		}
+		if _, ok := e.executors[dag.AgentContainer]; !ok {
+			return errs.Newf(errs.BadRequest, "task %s: container tasks are not enabled", task.ID)
+		}
+	}
+	return nil
+}
+
+// compensationType returns the agent type the compensation of task runs as.
+func compensationType(task models.TaskDefinition) string {
+	if task.Compensation.Type != "" {
+		return task.Compensation.Type
+	}
+	return task.Type
+}
+
+// compensate rolls back the failed run of exec as a saga: the run's DAG
+// becomes the saga definition, its completed tasks the completed steps, and
+// the saga orchestrator runs the compensations of those tasks in reverse
+// dependency order, retrying them and recording them in its WAL. The saga
+// has the workflow's ID. It returns nil when the run has nothing to
+// compensate.
+func (e *Engine) compensate(ctx context.Context, exec *workflowExecution, cause error) error {
+	if e.sagaOrchestrator == nil {
+		return nil
+	}
+	exec.mu.Lock()
+	wf := exec.wfState
+	defs := append([]models.TaskDefinition(nil), wf.Tasks...)
+	var completed, failed []string
+	results := make(map[string]any)
+	for id, state := range wf.TaskStatus {
+		switch {
+		case state.Status == taskStatusCompleted && !state.Skipped && state.DedupedFrom == "":
+			completed = append(completed, id)
+			results[id] = state.Result
+		case state.Status == taskStatusFailed:
+			failed = append(failed, id)
+		}
+	}
+	name, workflowID := wf.Name, wf.ID
+	exec.mu.Unlock()
+
+	if !slices.ContainsFunc(defs, func(def models.TaskDefinition) bool {
+		return def.Compensation != nil && slices.Contains(completed, def.ID)
+	}) {
+		return nil
+	}
+
+	builder := saga.New(name).WithCompensationPolicy(saga.ManualCompensate)
+	for _, def := range defs {
+		opts := []saga.StepOption{saga.Action(func(context.Context, *saga.StepContext) (any, error) {
+			return nil, errNotSagaStep
+		})}
+		if len(def.DependsOn) > 0 {
+			opts = append(opts, saga.DependsOn(def.DependsOn...))
+		}
+		if def.Compensation != nil {
+			opts = append(opts, saga.Compensate(e.compensationFn(exec, def)))
+			if def.Compensation.Timeout > 0 {
+				opts = append(opts, saga.StepTimeout(time.Duration(def.Compensation.Timeout)*time.Second))
+			}
+		}
+		builder = builder.Step(def.ID, opts...)
+	}
+	definition, err := builder.Build()
+	if err != nil {
+		return fmt.Errorf("build saga: %w", err)
+	}
+
+	sort.Strings(completed)
+	sort.Strings(failed)
+	instance := saga.NewSagaInstance(workflowID, definition)
+	if err := instance.TransitionTo(saga.SagaStateRunning); err != nil {
+		return err
+	}
+	for _, id := range completed {
+		instance.MarkStepCompleted(id, results[id])
+	}
+	failedStep := ""
+	if len(failed) > 0 {
+		failedStep = failed[0]
+	}
+	instance.SetFailure(failedStep, cause)
+	if err := instance.TransitionTo(saga.SagaStatePendingCompensation); err != nil {
+		return err
+	}
+	if err := e.sagaOrchestrator.RestoreInstance(ctx, instance); err != nil {
+		return fmt.Errorf("record saga: %w", err)
+	}
+	_, err = e.sagaOrchestrator.TriggerCompensation(ctx, workflowID, definition, nil, cause)
+	return err
+}
+
+// compensationFn returns the saga compensation running the compensation of
+// def: its compensation function if the workflow was submitted with one, or
+// the executor of its type. It runs with the task's environment, and its
+// config is evaluated against the failed run.
+func (e *Engine) compensationFn(exec *workflowExecution, def models.TaskDefinition) saga.CompensationFunc {
+	comp := def.Compensation
+	task := &dag.Task{
+		ID:        def.ID,
+		Name:      def.Name,
+		Agent:     compensationType(def),
+		Env:       append([]string(nil), def.Env...),
+		Secrets:   maps.Clone(def.Secrets),
+		Container: containerSpec(comp.Container),
+	}
+	return func(ctx context.Context, _ *saga.CompensationContext) error {
+		fn := exec.compensationFns[def.ID]
+		if fn == nil {
+			fn = noopTaskFn
+			if executor, ok := e.executors[task.Agent]; ok {
+				fn = executor.TaskFunc(exec.workflowID, task)
+			}
+		}
+		runner := &taskRunner{task: task, env: e.env}
+		ctx, redact, err := runner.withTaskEnv(ctx)
+		if err != nil {
+			return fmt.Errorf("resolve task environment: %w", err)
+		}
+		exprs := &workflowExpressions{engine: e, exec: exec}
+		config, err := expandConfig(comp.Config, exprs.vars(task, 0, nil), true)
+		if err != nil {
+			return fmt.Errorf("evaluate compensation config: %w", err)
+		}
+		ctx = context.WithValue(ctx, configKey{}, config)
+		return redact.redactError(fn(ctx))
+	}
+}
+
+// containerSpec converts the container of a task definition.
+func containerSpec(spec *models.ContainerSpec) *dag.ContainerSpec {
+	if spec == nil {
+		return nil
+	}
+	return &dag.ContainerSpec{
+		Image:   spec.Image,
+		Command: append([]string(nil), spec.Command...),
+		Args:    append([]string(nil), spec.Args...),
+		Env:     maps.Clone(spec.Env),
+	}
+}
@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+const (
+	// workflowLimitPolicyWait queues workflows until a slot is free.
+	workflowLimitPolicyWait = "wait"
+	// workflowLimitPolicyReject fails submissions when no slot is free.
+	workflowLimitPolicyReject = "reject"
+)
+
+// workflowLimiter bounds the number of simultaneously executing workflows,
+// both engine-wide and per workflow name. A nil limiter admits everything.
+type workflowLimiter struct {
+	policy string
+	global chan struct{}
+	byName map[string]chan struct{}
+
+	mu      sync.Mutex
+	waiting int
+}
+
+// newWorkflowLimiter builds a limiter from cfg. It returns nil when no limit
+// is configured.
+func newWorkflowLimiter(cfg config.WorkflowLimitsConfig) *workflowLimiter {
+	l := &workflowLimiter{
+		policy: cfg.Policy,
+		byName: make(map[string]chan struct{}, len(cfg.PerName)),
+	}
+	if l.policy == "" {
+		l.policy = workflowLimitPolicyWait
+	}
+	if cfg.MaxConcurrent > 0 {
+		l.global = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	for name, limit := range cfg.PerName {
+		if limit > 0 {
+			l.byName[name] = make(chan struct{}, limit)
+		}
+	}
+	if l.global == nil && len(l.byName) == 0 {
+		return nil
+	}
+	return l
+}
+
+// rejects reports whether the limiter fails fast instead of queueing.
+func (l *workflowLimiter) rejects() bool {
+	return l != nil && l.policy == workflowLimitPolicyReject
+}
+
+// acquire takes a slot for a workflow named name. With the reject policy it
+// returns a WorkflowConcurrencyLimitError when no slot is free; otherwise it
+// blocks until a slot frees up or ctx is done. The returned release func must
+// be called once the workflow finishes.
+func (l *workflowLimiter) acquire(ctx context.Context, name string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Take the per-name slot first so that a workflow waiting on its own
+	// name limit does not hold a global slot.
+	nameSlot := l.byName[name]
+	if nameSlot != nil {
+		if err := l.take(ctx, nameSlot, name, cap(nameSlot)); err != nil {
+			return nil, err
+		}
+	}
+	if l.global != nil {
+		if err := l.take(ctx, l.global, "", cap(l.global)); err != nil {
+			if nameSlot != nil {
+				<-nameSlot
+			}
+			return nil, err
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if l.global != nil {
+				<-l.global
+			}
+			if nameSlot != nil {
+				<-nameSlot
+			}
+		})
+	}, nil
+}
+
+func (l *workflowLimiter) take(ctx context.Context, slots chan struct{}, name string, limit int) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.policy == workflowLimitPolicyReject {
+		return &WorkflowConcurrencyLimitError{WorkflowName: name, Limit: limit}
+	}
+
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+	}()
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// queued returns the number of workflows waiting for a slot.
+func (l *workflowLimiter) queued() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestWorkflowLimiter_Reject(t *testing.T) {
+	if l := newWorkflowLimiter(config.WorkflowLimitsConfig{PerName: map[string]int{"x": 0}}); l != nil {
+		t.Fatalf("expected nil limiter without limits, got %+v", l)
+	}
+
+	l := newWorkflowLimiter(config.WorkflowLimitsConfig{
+		MaxConcurrent: 2,
+		PerName:       map[string]int{"nightly-report": 1},
+		Policy:        workflowLimitPolicyReject,
+	})
+
+	release, err := l.acquire(context.Background(), "nightly-report")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	var limitErr *WorkflowConcurrencyLimitError
+	if _, err := l.acquire(context.Background(), "nightly-report"); !errors.As(err, &limitErr) || limitErr.WorkflowName != "nightly-report" {
+		t.Fatalf("expected per-name limit error, got %v", err)
+	}
+
+	other, err := l.acquire(context.Background(), "other")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if _, err := l.acquire(context.Background(), "third"); !errors.As(err, &limitErr) || limitErr.WorkflowName != "" || limitErr.Limit != 2 {
+		t.Fatalf("expected global limit error, got %v", err)
+	}
+
+	release()
+	release()
+	other()
+	if _, err := l.acquire(context.Background(), "nightly-report"); err != nil {
+		t.Fatalf("expected slot after release, got %v", err)
+	}
+}
+
+func TestWorkflowLimiter_Wait(t *testing.T) {
+	l := newWorkflowLimiter(config.WorkflowLimitsConfig{MaxConcurrent: 1})
+
+	release, err := l.acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	acquired := make(chan func(), 1)
+	go func() {
+		next, err := l.acquire(context.Background(), "b")
+		if err == nil {
+			acquired <- next
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for l.queued() != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if l.queued() != 1 {
+		t.Fatalf("expected one queued workflow, got %d", l.queued())
+	}
+
+	release()
+	select {
+	case next := <-acquired:
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("queued workflow did not get a slot")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hold, _ := l.acquire(context.Background(), "a")
+	defer hold()
+	cancel()
+	if _, err := l.acquire(ctx, "b"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context error while waiting, got %v", err)
+	}
+}
+
+func TestSubmitWorkflowRuntime_PerNameConcurrency(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Workflows.PerName = map[string]int{"nightly-report": 1}
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	release := make(chan struct{})
+	submit := func() string {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+			Name:  "nightly-report",
+			Tasks: []models.TaskDefinition{{ID: "t1", Name: "task-1", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeAsync,
+			TaskFns: map[string]func(context.Context) error{
+				"t1": func(ctx context.Context) error {
+					select {
+					case <-release:
+					case <-ctx.Done():
+					}
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		return resp.ID
+	}
+
+	first := submit()
+	if err := waitWorkflowStatus(eng, first, workflowStatusRunning, 2*time.Second); err != nil {
+		t.Fatalf("first workflow did not start: %v", err)
+	}
+	second := submit()
+
+	time.Sleep(50 * time.Millisecond)
+	status, err := eng.GetWorkflowStatusResponse(context.Background(), second)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if status.Status != workflowStatusPending {
+		t.Fatalf("second workflow status = %s, want %s", status.Status, workflowStatusPending)
+	}
+	if queued := eng.GetStatus().QueuedWorkflows; queued != 1 {
+		t.Fatalf("QueuedWorkflows = %d, want 1", queued)
+	}
+
+	close(release)
+	if err := waitWorkflowStatus(eng, second, workflowStatusCompleted, 2*time.Second); err != nil {
+		t.Fatalf("second workflow did not complete: %v", err)
+	}
+}
+
+func TestSubmitWorkflowRuntime_RejectPolicy(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Workflows = config.WorkflowLimitsConfig{MaxConcurrent: 1, Policy: "reject"}
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	release := make(chan struct{})
+	defer close(release)
+	opts := SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"t1": func(ctx context.Context) error {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil
+			},
+		},
+	}
+	req := &models.WorkflowRequest{
+		Name:  "busy",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "task-1", Type: "function"}},
+	}
+
+	if _, err := eng.SubmitWorkflowRuntime(context.Background(), req, opts); err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if _, err := eng.SubmitWorkflowRuntime(context.Background(), req, opts); !errs.Is(err, errs.RateLimited) {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+}
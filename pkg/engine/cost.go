@@ -0,0 +1,217 @@
+package engine
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Usage dimensions. Tasks report their own dimensions with RecordUsage;
+// task seconds are accounted by the engine per lane, as
+// UsageTaskSeconds + ":" + lane.
+const (
+	UsageTaskSeconds = "task_seconds"
+	UsageLLMTokens   = "llm_tokens"
+	UsageAPICalls    = "api_calls"
+)
+
+// Cost report groupings.
+const (
+	CostGroupTenant   = "tenant"
+	CostGroupWorkflow = "workflow"
+)
+
+// usageKey is the context key of the running workflow's usageMeter.
+type usageKey struct{}
+
+// RecordUsage adds amount units of dimension, such as UsageLLMTokens or
+// UsageAPICalls, to the usage of the workflow run the task running with ctx
+// belongs to. It does nothing outside the engine or when cost accounting is
+// disabled.
+func RecordUsage(ctx context.Context, dimension string, amount float64) {
+	if m, ok := ctx.Value(usageKey{}).(*usageMeter); ok && dimension != "" && amount > 0 {
+		m.add(dimension, amount)
+	}
+}
+
+// usageMeter accumulates the usage of one workflow run.
+type usageMeter struct {
+	mu    sync.Mutex
+	usage map[string]float64
+	// changed is signalled whenever usage is added, for the budget check
+	// of the run.
+	changed chan struct{}
+}
+
+// newUsageMeter returns a meter continuing from usage, e.g. of a resumed run.
+func newUsageMeter(usage map[string]float64) *usageMeter {
+	m := &usageMeter{usage: maps.Clone(usage), changed: make(chan struct{}, 1)}
+	if m.usage == nil {
+		m.usage = make(map[string]float64)
+	}
+	return m
+}
+
+func (m *usageMeter) add(dimension string, amount float64) {
+	m.mu.Lock()
+	m.usage[dimension] += amount
+	m.mu.Unlock()
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// snapshot returns a copy of the usage, or nil when there is none.
+func (m *usageMeter) snapshot() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.usage) == 0 {
+		return nil
+	}
+	return maps.Clone(m.usage)
+}
+
+// withTask returns ctx carrying the meter for RecordUsage.
+func (m *usageMeter) withTask(ctx context.Context) context.Context {
+	return context.WithValue(ctx, usageKey{}, m)
+}
+
+// taskSecondsDimension returns the usage dimension of the time tasks run in
+// laneName.
+func taskSecondsDimension(laneName string) string {
+	return UsageTaskSeconds + ":" + laneName
+}
+
+// taskLane returns the lane task taskID of wf runs in.
+func taskLane(wf *storage.WorkflowState, taskID string) string {
+	for _, def := range wf.Tasks {
+		if def.ID != taskID {
+			continue
+		}
+		if laneName, ok := def.Config["lane"].(string); ok && laneName != "" {
+			return laneName
+		}
+		break
+	}
+	return defaultLaneName
+}
+
+// accountTaskSeconds adds the run time of a finished task to the usage of
+// exec. The caller holds exec.mu.
+func (e *Engine) accountTaskSeconds(exec *workflowExecution, taskID string, taskState *storage.TaskState) {
+	if exec.usage == nil || taskState.StartedAt == nil || taskState.CompletedAt == nil || taskState.DedupedFrom != "" {
+		return
+	}
+	seconds := taskState.CompletedAt.Sub(*taskState.StartedAt).Seconds()
+	if seconds > 0 {
+		exec.usage.add(taskSecondsDimension(taskLane(exec.wfState, taskID)), seconds)
+	}
+	exec.wfState.Usage = exec.usage.snapshot()
+}
+
+// costOf prices usage with the configured rates.
+func (e *Engine) costOf(usage map[string]float64) float64 {
+	rates := e.cfg.Orchestration.Costs.Rates
+	var total float64
+	for dimension, amount := range usage {
+		rate, ok := rates[dimension]
+		if !ok {
+			if base, _, found := strings.Cut(dimension, ":"); found {
+				rate = rates[base]
+			}
+		}
+		total += amount * rate
+	}
+	return total
+}
+
+// tenantOf returns the tenant wf is charged to, or "" when it names none.
+func (e *Engine) tenantOf(wf *storage.WorkflowState) string {
+	return wf.Metadata[e.cfg.Orchestration.Costs.TenantKey]
+}
+
+// runCost returns the usage and cost of wf, or nil when cost accounting is
+// disabled.
+func (e *Engine) runCost(wf *storage.WorkflowState) *models.RunCost {
+	if !e.cfg.Orchestration.Costs.Enabled {
+		return nil
+	}
+	return &models.RunCost{
+		Tenant:   e.tenantOf(wf),
+		Usage:    maps.Clone(wf.Usage),
+		Total:    e.costOf(wf.Usage),
+		Currency: e.cfg.Orchestration.Costs.Currency,
+	}
+}
+
+// CostReport aggregates the usage and cost of the workflow runs created in
+// [from, to) by tenant or by workflow name. Zero bounds are open.
+func (e *Engine) CostReport(ctx context.Context, groupBy string, from, to time.Time) (*models.CostReport, error) {
+	if !e.cfg.Orchestration.Costs.Enabled {
+		return nil, errs.New(errs.NotFound, "cost accounting is disabled")
+	}
+	if groupBy == "" {
+		groupBy = CostGroupTenant
+	}
+	if groupBy != CostGroupTenant && groupBy != CostGroupWorkflow {
+		return nil, errs.Newf(errs.BadRequest, "group_by must be %s or %s", CostGroupTenant, CostGroupWorkflow)
+	}
+
+	report := &models.CostReport{
+		GroupBy:  groupBy,
+		Currency: e.cfg.Orchestration.Costs.Currency,
+		Groups:   []models.CostGroup{},
+	}
+	if !from.IsZero() {
+		report.From = &from
+	}
+	if !to.IsZero() {
+		report.To = &to
+	}
+
+	groups := make(map[string]*models.CostGroup)
+	err := storage.ScanWorkflows(ctx, e.storage, &storage.WorkflowFilter{}, func(wf *storage.WorkflowState) error {
+		if (!from.IsZero() && wf.CreatedAt.Before(from)) || (!to.IsZero() && !wf.CreatedAt.Before(to)) {
+			return nil
+		}
+		key := wf.Name
+		if groupBy == CostGroupTenant {
+			key = e.tenantOf(wf)
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &models.CostGroup{Key: key, Usage: map[string]float64{}}
+			groups[key] = group
+		}
+		group.Runs++
+		for dimension, amount := range wf.Usage {
+			group.Usage[dimension] += amount
+		}
+		cost := e.costOf(wf.Usage)
+		group.Total += cost
+		report.Total += cost
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Total != report.Groups[j].Total {
+			return report.Groups[i].Total > report.Groups[j].Total
+		}
+		return report.Groups[i].Key < report.Groups[j].Key
+	})
+	return report, nil
+}
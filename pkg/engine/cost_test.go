@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_AccountsRunCosts(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Costs = config.CostsConfig{
+		Enabled:   true,
+		TenantKey: "tenant",
+		Currency:  "USD",
+		Rates:     map[string]float64{UsageTaskSeconds: 1, UsageLLMTokens: 0.001},
+	}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	run := func(name, tenant string, tokens float64) *models.WorkflowStatusResponse {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+			Name:     name,
+			Metadata: map[string]string{"tenant": tenant},
+			Tasks:    []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{
+				"a": func(ctx context.Context) error {
+					RecordUsage(ctx, UsageLLMTokens, tokens)
+					RecordUsage(ctx, UsageAPICalls, 1)
+					time.Sleep(10 * time.Millisecond)
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		return resp
+	}
+
+	resp := run("summarize", "team-a", 1000)
+	if resp.Cost == nil || resp.Cost.Tenant != "team-a" || resp.Cost.Currency != "USD" {
+		t.Fatalf("cost = %+v", resp.Cost)
+	}
+	usage := resp.Cost.Usage
+	seconds := usage[taskSecondsDimension(defaultLaneName)]
+	if usage[UsageLLMTokens] != 1000 || usage[UsageAPICalls] != 1 || seconds < 0.01 {
+		t.Fatalf("usage = %v", usage)
+	}
+	// api_calls has no rate; task_seconds:default uses the task_seconds rate.
+	if want := 1 + seconds; math.Abs(resp.Cost.Total-want) > 1e-9 {
+		t.Fatalf("total = %v, want %v", resp.Cost.Total, want)
+	}
+
+	run("summarize", "team-b", 3000)
+	run("translate", "team-b", 2000)
+
+	report, err := eng.CostReport(ctx, CostGroupTenant, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("CostReport() error = %v", err)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Key != "team-b" || report.Groups[0].Runs != 2 || report.Groups[0].Usage[UsageLLMTokens] != 5000 {
+		t.Fatalf("groups = %+v", report.Groups)
+	}
+	byWorkflow, err := eng.CostReport(ctx, CostGroupWorkflow, time.Time{}, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CostReport() error = %v", err)
+	}
+	if len(byWorkflow.Groups) != 0 || byWorkflow.Total != 0 {
+		t.Fatalf("runs outside the range reported: %+v", byWorkflow)
+	}
+	if _, err := eng.CostReport(ctx, "lane", time.Time{}, time.Time{}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("CostReport(lane) error = %v, want bad request", err)
+	}
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Kinds of the nondeterministic decisions task attempts journal.
+const (
+	decisionTime   = "time"
+	decisionRandom = "random"
+	// decisionModelRoute is followed by the route name.
+	decisionModelRoute = "model_route:"
+)
+
+// TaskNow returns the current time for the task running with ctx. The time
+// is recorded in the run's journal, so that a replay of the attempt reads
+// the same time. Outside of tasks run by the engine it is time.Now.
+func TaskNow(ctx context.Context) time.Time {
+	now := time.Now()
+	var decided time.Time
+	if err := decide(ctx, decisionTime, now, &decided); err != nil {
+		return now
+	}
+	return decided
+}
+
+// TaskRandom returns a pseudo-random number in [0.0, 1.0) for the task
+// running with ctx. The number is recorded in the run's journal, so that a
+// replay of the attempt draws the same number.
+func TaskRandom(ctx context.Context) float64 {
+	value := rand.Float64()
+	var decided float64
+	if err := decide(ctx, decisionRandom, value, &decided); err != nil {
+		return value
+	}
+	return decided
+}
+
+// decide stores in decided the decision kind of the attempt running with
+// ctx, which is value or, when the attempt is replayed, the recorded
+// decision, and journals it.
+func decide(ctx context.Context, kind string, value, decided any) error {
+	data, ok := replayedDecision(ctx, kind)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+	recordDecision(ctx, kind, data)
+	return json.Unmarshal(data, decided)
+}
+
+// recordDecision journals the decision kind of the attempt running with ctx,
+// encoded as data.
+func recordDecision(ctx context.Context, kind string, data json.RawMessage) {
+	out, ok := ctx.Value(outputKey{}).(*taskOutput)
+	if !ok {
+		return
+	}
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	out.decisions = append(out.decisions, storage.JournalDecision{Kind: kind, Value: data})
+}
+
+// replayedDecision returns the recorded decision kind of the attempt running
+// with ctx when it is replayed.
+func replayedDecision(ctx context.Context, kind string) (json.RawMessage, bool) {
+	attempt, ok := ctx.Value(replayKey{}).(*replayAttempt)
+	if !ok {
+		return nil, false
+	}
+	return attempt.decision(kind)
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -13,8 +16,13 @@ import (
 
 	dgbadger "github.com/dgraph-io/badger/v4"
 	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/artifact"
 	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/insights"
 	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/llmcache"
+	"github.com/goclaw/goclaw/pkg/policy"
+	"github.com/goclaw/goclaw/pkg/requestid"
 	"github.com/goclaw/goclaw/pkg/saga"
 	"github.com/goclaw/goclaw/pkg/signal"
 	"github.com/goclaw/goclaw/pkg/storage"
@@ -63,6 +71,14 @@ type Workflow struct {
 	// TaskFns maps task IDs to their execution functions.
 	// Tasks without an entry will be executed as no-ops.
 	TaskFns map[string]func(context.Context) error
+	// Priority orders tasks in lanes; higher-priority workflows may preempt
+	// preemptible tasks of lower-priority ones.
+	Priority int
+	// Deadline applies to every task without an earlier deadline of its own.
+	// Zero means no deadline.
+	Deadline time.Time
+	// GangLayers dispatches each layer's tasks in a lane all-or-nothing.
+	GangLayers bool
 }
 
 // WorkflowResult holds the outcome of a completed workflow.
@@ -104,14 +120,17 @@ type EventBroadcaster interface {
 type Engine struct {
 	cfg                 *config.Config
 	logger              appLogger
+	laneLogger          appLogger
 	storage             storage.Storage
+	taskWrites          *taskWriter
 	laneManager         *lane.Manager
 	scheduler           *Scheduler
 	metrics             MetricsRecorder
 	memoryHub           MemoryHub
 	signalBus           signal.Bus
-	redisClient         redis.Cmdable
+	redisClient         redis.UniversalClient
 	redisOwnershipGuard lane.RedisOwnershipGuard
+	enqueueOnly         bool
 	events              EventBroadcaster
 	sagaDB              *dgbadger.DB
 	sagaWAL             *saga.BadgerWAL
@@ -119,10 +138,36 @@ type Engine struct {
 	sagaCheckpointStore saga.CheckpointStore
 	sagaRecoveryManager *saga.RecoveryManager
 	sagaCleanupManager  *saga.CleanupManager
+	sagaArchive         saga.ArchiveStore
+	sagaWatchdog        *saga.Watchdog
 	sagaCleanupCancel   context.CancelFunc
+	slaCancel           context.CancelFunc
+	notifier            Notifier
+	outboxWake          chan struct{}
+	outboxCancel        context.CancelFunc
+	waiters             workflowWaiters
+	approvals           sync.Mutex
+	reloader            *config.Reloader
+	hooks               lifecycleHooks
 	state               atomic.Int32
 	execMu              sync.RWMutex
 	executions          map[string]*workflowExecution
+	workflowLimits      *workflowLimiter
+	preemptor           *preemptor
+	inheritance         *priorityInheritance
+	env                 *envResolver
+	artifacts           *artifact.Store
+	taskLogs            *taskLogStore
+	locks               *lockManager
+	rateLimits          *rateLimits
+	tools               *toolRegistry
+	budgets             *budgetLedger
+	llmCache            *llmcache.Cache
+	executors           map[string]TaskExecutor
+	taskMiddleware      []TaskMiddleware
+	policy              policy.Evaluator
+	readiness           *readiness
+	insights            *insights.Detector
 }
 
 // New creates a new Engine from the given configuration, logger, and storage.
@@ -137,18 +182,44 @@ func New(cfg *config.Config, logger appLogger, store storage.Storage, opts ...Op
 		return nil, fmt.Errorf("storage cannot be nil")
 	}
 	e := &Engine{
-		cfg:        cfg,
-		logger:     logger,
-		storage:    store,
-		metrics:    &nopMetrics{},
-		executions: make(map[string]*workflowExecution),
+		cfg:            cfg,
+		logger:         logger,
+		laneLogger:     logger,
+		storage:        store,
+		metrics:        &nopMetrics{},
+		executions:     make(map[string]*workflowExecution),
+		reloader:       config.NewReloader(cfg),
+		workflowLimits: newWorkflowLimiter(cfg.Orchestration.Workflows),
+		taskWrites:     newTaskWriter(cfg.Storage.TaskWrites, store, logger),
+		env:            newEnvResolver(cfg.Orchestration),
+		taskLogs:       newTaskLogStore(cfg.Orchestration.TaskLogs),
+		readiness:      newReadiness(),
+		outboxWake:     make(chan struct{}, 1),
+	}
+	if cfg.Orchestration.Insights.Enabled {
+		e.insights = insights.New(cfg.Orchestration.Insights)
 	}
 	e.state.Store(int32(stateIdle))
+	e.reloader.Register(e.checkConfigPolicy)
+	e.reloader.Register(e.applyRuntimeConfig)
 
 	// Apply options
 	for _, opt := range opts {
 		opt(e)
 	}
+	if e.policy == nil && cfg.Policy.Enabled {
+		e.policy = policy.NewOPA(cfg.Policy)
+	}
+	e.preemptor = newPreemptor(e.metrics)
+	e.inheritance = newPriorityInheritance(e.metrics)
+	e.locks = newLockManager(cfg.Orchestration.Locks, e.redisClient, cfg.Redis.KeyPrefix, logger)
+	e.rateLimits = newRateLimits(cfg.Orchestration.RateLimits, e.redisClient, cfg.Redis.KeyPrefix)
+	tools, err := newToolRegistry(cfg.Orchestration.Tools)
+	if err != nil {
+		return nil, err
+	}
+	e.tools = tools
+	e.budgets = newBudgetLedger()
 
 	if e.signalBus == nil {
 		e.signalBus = signal.NewLocalBus(cfg.Signal.BufferSize)
@@ -171,6 +242,17 @@ func (e *Engine) Start(ctx context.Context) error {
 	}
 
 	e.logger.Info("starting engine", "app", e.cfg.App.Name)
+	e.readiness.reset()
+
+	if _, _, err := e.storage.ListWorkflows(ctx, &storage.WorkflowFilter{Limit: 1}); err != nil {
+		e.state.Store(int32(stateError))
+		return fmt.Errorf("storage not readable: %w", err)
+	}
+	if err := e.loadToolOverrides(ctx); err != nil {
+		e.state.Store(int32(stateError))
+		return fmt.Errorf("failed to load tool overrides: %w", err)
+	}
+	e.readiness.complete(PhaseStorage, "")
 
 	if e.signalBus == nil {
 		e.signalBus = signal.NewLocalBus(e.cfg.Signal.BufferSize)
@@ -185,16 +267,23 @@ func (e *Engine) Start(ctx context.Context) error {
 	e.laneManager = lane.NewManager()
 	if e.redisClient != nil {
 		e.laneManager.SetRedisClient(e.redisClient)
+		e.laneManager.SetRedisNamespace(e.cfg.Redis.KeyPrefix)
 	}
 	if e.redisOwnershipGuard != nil {
 		e.laneManager.SetRedisOwnershipGuard(e.redisOwnershipGuard)
 	}
+	if e.enqueueOnly {
+		e.laneManager.SetRedisConsume(false)
+	}
 
 	queueSize := e.cfg.Orchestration.Queue.Size
 	if queueSize <= 0 {
 		queueSize = 1000
 	}
-	concurrency := e.cfg.Orchestration.MaxAgents
+	// Hot-reloadable settings come from the reloader so that changes applied
+	// before Start are honoured.
+	runtimeCfg := e.reloader.Current()
+	concurrency := runtimeCfg.Orchestration.MaxAgents
 	if concurrency <= 0 {
 		concurrency = 4
 	}
@@ -204,6 +293,21 @@ func (e *Engine) Start(ctx context.Context) error {
 		Capacity:       queueSize,
 		MaxConcurrency: concurrency,
 		Backpressure:   lane.Block,
+		RateLimit:      runtimeCfg.Orchestration.Queue.RateLimit,
+	}
+	if e.cfg.Orchestration.Queue.Dispatch == "edf" {
+		defaultCfg.Dispatch = lane.DispatchEDF
+	}
+	if resources := e.cfg.Orchestration.Queue.Resources; !resources.IsZero() {
+		defaultCfg.Resources = &lane.Resources{CPU: resources.CPU, MemoryMB: resources.MemoryMB, GPU: resources.GPU}
+	}
+	if adaptive := e.cfg.Orchestration.Queue.Adaptive; adaptive.Enabled {
+		defaultCfg.Adaptive = &lane.AdaptiveConfig{
+			MinConcurrency: adaptive.MinConcurrency,
+			TargetLatency:  adaptive.TargetLatency,
+			MaxErrorRate:   adaptive.MaxErrorRate,
+			Interval:       adaptive.Interval,
+		}
 	}
 	var (
 		defaultLane lane.Lane
@@ -212,7 +316,7 @@ func (e *Engine) Start(ctx context.Context) error {
 
 	if e.cfg.Orchestration.Queue.Type == "redis" {
 		if e.redisClient == nil {
-			e.logger.Warn("redis queue configured but redis client unavailable; falling back to memory lane")
+			e.laneLogger.Warn("redis queue configured but redis client unavailable; falling back to memory lane")
 			defaultLane, err = e.laneManager.Register(defaultCfg)
 		} else {
 			redisCfg := lane.DefaultRedisConfig(defaultLaneName)
@@ -236,9 +340,19 @@ func (e *Engine) Start(ctx context.Context) error {
 	if metricsLane, ok := defaultLane.(interface{ SetMetrics(lane.MetricsRecorder) }); ok {
 		metricsLane.SetMetrics(e.metrics)
 	}
+	for _, name := range slices.Sorted(maps.Keys(runtimeCfg.Orchestration.Lanes)) {
+		named := runtimeCfg.Orchestration.Lanes[name]
+		if err := e.PutLane(name, named.Capacity, named.MaxConcurrency, named.RateLimit); err != nil {
+			e.state.Store(int32(stateError))
+			return fmt.Errorf("failed to register lane %s: %w", name, err)
+		}
+	}
+
+	e.taskWrites.start()
+	e.readiness.complete(PhaseLanes, "")
 
 	// Create scheduler (tracker is per-workflow, created in Submit).
-	e.scheduler = newScheduler(newStateTracker(), e.logger, e.signalBus, e.laneManager)
+	e.scheduler = newScheduler(newStateTracker(), e.laneLogger, e.signalBus, e.laneManager)
 
 	// Start memory hub if configured
 	if e.memoryHub != nil {
@@ -249,28 +363,62 @@ func (e *Engine) Start(ctx context.Context) error {
 		}
 	}
 
+	if err := e.runHooks(ctx, "start", e.hooks.onStart); err != nil {
+		e.state.Store(int32(stateError))
+		return err
+	}
+
 	e.state.Store(int32(stateRunning))
 	e.logger.Info("engine started")
 
 	// Recover workflows from storage
+	var recoveryErrs []error
 	if err := e.RecoverWorkflows(ctx); err != nil {
 		e.logger.Warn("workflow recovery completed with errors", "error", err)
+		recoveryErrs = append(recoveryErrs, err)
 	}
 
 	if e.sagaRecoveryManager != nil {
 		recovered, err := e.sagaRecoveryManager.Recover(ctx, map[string]*saga.SagaDefinition{}, nil)
 		if err != nil {
 			e.logger.Warn("saga recovery completed with errors", "error", err)
+			recoveryErrs = append(recoveryErrs, err)
 		} else if recovered > 0 {
 			e.logger.Info("saga recovery completed", "recovered", recovered)
 		}
 	}
+	warning := ""
+	if err := errors.Join(recoveryErrs...); err != nil {
+		warning = err.Error()
+	}
+	e.readiness.complete(PhaseRecovery, warning)
+	e.runHooksLogged(ctx, "recovery_complete", e.hooks.onRecoveryComplete)
+
+	slaInterval := e.cfg.Orchestration.SLACheckInterval
+	if slaInterval <= 0 {
+		slaInterval = defaultSLACheckInterval
+	}
+	slaCtx, cancelSLA := context.WithCancel(context.Background())
+	e.slaCancel = cancelSLA
+	go e.watchSLAs(slaCtx, slaInterval)
+	if e.outboxEnabled() {
+		pollInterval := e.cfg.Orchestration.Outbox.PollInterval
+		if pollInterval <= 0 {
+			pollInterval = defaultOutboxPollInterval
+		}
+		outboxCtx, cancelOutbox := context.WithCancel(context.Background())
+		e.outboxCancel = cancelOutbox
+		go e.dispatchOutbox(outboxCtx, pollInterval)
+	}
 	if e.sagaCleanupManager != nil {
 		cleanupCtx, cancel := context.WithCancel(context.Background())
 		e.sagaCleanupCancel = cancel
 		if err := e.sagaCleanupManager.Start(cleanupCtx, e.cfg.Saga.WALCleanupInterval, e.cfg.Saga.WALRetention); err != nil {
 			e.logger.Warn("failed to start saga wal cleanup", "error", err)
 		}
+		if err := e.sagaWatchdog.Start(cleanupCtx); err != nil {
+			e.logger.Warn("failed to start saga watchdog", "error", err)
+		}
 	}
 
 	return nil
@@ -283,6 +431,7 @@ func (e *Engine) Stop(ctx context.Context) error {
 	}
 
 	e.logger.Info("stopping engine")
+	e.runHooksLogged(ctx, "stop", e.hooks.onStop)
 
 	// Stop memory hub first
 	if e.memoryHub != nil {
@@ -297,6 +446,9 @@ func (e *Engine) Stop(ctx context.Context) error {
 			return fmt.Errorf("error closing lane manager: %w", err)
 		}
 	}
+	if err := e.taskWrites.close(ctx); err != nil {
+		e.logger.Warn("error flushing task transitions", "error", err)
+	}
 
 	if e.signalBus != nil {
 		if err := e.signalBus.Close(); err != nil {
@@ -307,6 +459,14 @@ func (e *Engine) Stop(ctx context.Context) error {
 		e.sagaCleanupCancel()
 		e.sagaCleanupCancel = nil
 	}
+	if e.slaCancel != nil {
+		e.slaCancel()
+		e.slaCancel = nil
+	}
+	if e.outboxCancel != nil {
+		e.outboxCancel()
+		e.outboxCancel = nil
+	}
 	if e.sagaWAL != nil {
 		if err := e.sagaWAL.Close(); err != nil {
 			e.logger.Warn("error closing saga wal", "error", err)
@@ -332,6 +492,13 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 		return nil, &EngineNotRunningError{}
 	}
 
+	// Workflows submitted directly carry no name, so only the global limit applies.
+	release, err := e.workflowLimits.acquire(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	ctx, workflowSpan := runtimeTracer().Start(ctx, spanWorkflowExecute)
 	workflowSpan.SetAttributes(
 		attribute.String("workflow.id", wf.ID),
@@ -339,8 +506,9 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 	)
 	defer workflowSpan.End()
 
-	e.logger.Info("submitting workflow", "workflow_id", wf.ID, "tasks", len(wf.Tasks))
-	e.emitWorkflowStateChanged(wf.ID, wf.ID, "pending", "running")
+	requestID := requestid.FromContext(ctx)
+	e.logger.Info("submitting workflow", "workflow_id", wf.ID, "request_id", requestID, "tasks", len(wf.Tasks))
+	e.emitWorkflowStateChanged(requestID, wf.ID, wf.ID, "pending", "running")
 
 	// Record workflow submission
 	e.metrics.RecordWorkflowSubmission("pending")
@@ -365,6 +533,9 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 
 	// Compile to execution plan.
 	plan, err := g.Compile()
+	if err == nil {
+		err = checkPlanResources(e.laneManager, plan, wf.GangLayers)
+	}
 	if err != nil {
 		workflowSpan.RecordError(err)
 		workflowSpan.SetStatus(otelcodes.Error, "compile_error")
@@ -379,11 +550,11 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 
 	// Initialise per-workflow state tracker.
 	tracker := newStateTracker()
-	taskNameByID := make(map[string]string, len(wf.Tasks))
+	taskByID := make(map[string]*dag.Task, len(wf.Tasks))
 	taskIDs := make([]string, 0, len(wf.Tasks))
 	for _, t := range wf.Tasks {
 		taskIDs = append(taskIDs, t.ID)
-		taskNameByID[t.ID] = t.Name
+		taskByID[t.ID] = t
 	}
 	tracker.InitTasks(taskIDs)
 	tracker.SetOnStateChange(func(taskID string, oldState, newState TaskState, result TaskResult) {
@@ -391,11 +562,28 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 		if result.Error != nil {
 			errorMessage = result.Error.Error()
 		}
-		e.emitTaskStateChanged(wf.ID, taskID, taskNameByID[taskID], oldState.String(), newState.String(), errorMessage, nil)
+		e.emitTaskStateChanged(requestID, wf.ID, taskID, taskByID[taskID].Name, oldState.String(), newState.String(), errorMessage, nil)
+		if result.DeadlineMissed && isTerminalTaskStatus(mapTaskStateToStatus(newState)) {
+			e.emitTaskDeadlineMissed(wf.ID, taskID, taskByID[taskID].Name, result.Deadline, result.EndedAt)
+		}
+	})
+	tracker.SetOnStall(func(taskID string, result TaskResult) {
+		e.emitTaskStalled(wf.ID, taskByID[taskID], result)
 	})
 
 	// Create a scheduler with this workflow's tracker.
-	sched := newScheduler(tracker, e.logger, e.signalBus, e.laneManager)
+	sched := newScheduler(tracker, e.laneLogger, e.signalBus, e.laneManager)
+	sched.priority = wf.Priority
+	sched.deadline = wf.Deadline
+	sched.gangLayers = wf.GangLayers
+	sched.preemptor = e.preemptor
+	sched.inheritance = e.inheritance
+	sched.env = e.env
+	sched.artifacts = e.artifacts
+	sched.logs = e.taskLogs
+	sched.executors = e.executors
+	sched.middleware = e.taskMiddleware
+	sched.workflowID = wf.ID
 
 	taskFns := wf.TaskFns
 	if taskFns == nil {
@@ -416,11 +604,15 @@ func (e *Engine) Submit(ctx context.Context, wf *Workflow) (*WorkflowResult, err
 			statusStr = "failed"
 		}
 	}
-	e.emitWorkflowStateChanged(wf.ID, wf.ID, "running", statusStr)
+	e.emitWorkflowStateChanged(requestID, wf.ID, wf.ID, "running", statusStr)
 
 	// Record workflow duration
 	duration := time.Since(start)
-	e.metrics.RecordWorkflowDuration(statusStr, duration)
+	if recorder, ok := e.metrics.(runMetricsRecorder); ok {
+		recorder.RecordWorkflowDurationFor(ctx, statusStr, "", "", duration)
+	} else {
+		e.metrics.RecordWorkflowDuration(statusStr, duration)
+	}
 	e.metrics.RecordWorkflowSubmission(statusStr)
 
 	result := &WorkflowResult{
@@ -533,6 +725,46 @@ func (e *Engine) State() string {
 	}
 }
 
+// LaneStats returns the statistics of every registered lane, sorted by
+// lane name. It is empty until the engine has started.
+func (e *Engine) LaneStats() []lane.Stats {
+	if e.laneManager == nil {
+		return []lane.Stats{}
+	}
+
+	byName := e.laneManager.GetStats()
+	stats := make([]lane.Stats, 0, len(byName))
+	for _, s := range byName {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// LaneStatsByName returns the statistics of one lane. It returns a
+// *lane.LaneNotFoundError if no lane with that name is registered.
+func (e *Engine) LaneStatsByName(name string) (lane.Stats, error) {
+	if e.laneManager == nil {
+		return lane.Stats{}, &lane.LaneNotFoundError{LaneName: name}
+	}
+	l, err := e.laneManager.GetLane(name)
+	if err != nil {
+		return lane.Stats{}, err
+	}
+	return l.Stats(), nil
+}
+
+// Storage returns the storage workflows and tasks are persisted in.
+func (e *Engine) Storage() storage.Storage {
+	return e.storage
+}
+
+// FlushTaskWrites commits the task transitions buffered for write-behind
+// batching, so that the storage holds the latest state of every task.
+func (e *Engine) FlushTaskWrites(ctx context.Context) error {
+	return e.taskWrites.flushAll(ctx)
+}
+
 // GetSagaOrchestrator returns the saga orchestrator when enabled.
 func (e *Engine) GetSagaOrchestrator() *saga.SagaOrchestrator {
 	return e.sagaOrchestrator
@@ -543,6 +775,12 @@ func (e *Engine) GetSagaCheckpointStore() saga.CheckpointStore {
 	return e.sagaCheckpointStore
 }
 
+// GetSagaArchiveStore returns the archive of terminal sagas when sagas and
+// archiving are enabled.
+func (e *Engine) GetSagaArchiveStore() saga.ArchiveStore {
+	return e.sagaArchive
+}
+
 // GetSagaRecoveryManager returns saga recovery manager when enabled.
 func (e *Engine) GetSagaRecoveryManager() *saga.RecoveryManager {
 	return e.sagaRecoveryManager
@@ -599,6 +837,9 @@ func (e *Engine) initializeSagaRuntime() error {
 	if sagaMetrics, ok := e.metrics.(saga.MetricsRecorder); ok {
 		sagaOptions = append(sagaOptions, saga.WithMetrics(sagaMetrics))
 	}
+	if e.policy != nil {
+		sagaOptions = append(sagaOptions, saga.WithAdmission(e.admitSaga))
+	}
 
 	orchestrator := saga.NewSagaOrchestrator(sagaOptions...)
 	recoveryManager, err := saga.NewRecoveryManager(orchestrator, checkpointStore, e.logger)
@@ -619,6 +860,16 @@ func (e *Engine) initializeSagaRuntime() error {
 		},
 		e.logger,
 	)
+	if e.cfg.Saga.ArchiveEnabled {
+		archive, err := saga.NewBadgerArchiveStore(db)
+		if err != nil {
+			_ = wal.Close()
+			_ = db.Close()
+			return fmt.Errorf("create saga archive store: %w", err)
+		}
+		cleanupManager.WithArchive(archive, orchestrator)
+		e.sagaArchive = archive
+	}
 
 	e.sagaDB = db
 	e.sagaWAL = wal
@@ -626,6 +877,11 @@ func (e *Engine) initializeSagaRuntime() error {
 	e.sagaCheckpointStore = checkpointStore
 	e.sagaRecoveryManager = recoveryManager
 	e.sagaCleanupManager = cleanupManager
+	e.sagaWatchdog = saga.NewWatchdog(orchestrator, saga.WatchdogConfig{
+		Interval:            e.cfg.Saga.WatchdogInterval,
+		RunningTimeout:      e.cfg.Saga.StuckRunningTimeout,
+		CompensatingTimeout: e.cfg.Saga.StuckCompensatingTimeout,
+	}, e.logger)
 
 	return nil
 }
@@ -653,20 +909,76 @@ func (n *nopMetrics) DecQueueDepth(laneName string)
 func (n *nopMetrics) RecordWaitDuration(laneName string, duration time.Duration)   {}
 func (n *nopMetrics) RecordThroughput(laneName string)                             {}
 
-func (e *Engine) emitWorkflowStateChanged(workflowID, name, oldState, newState string) {
+// requestBroadcaster is implemented by event broadcasters that tag workflow
+// and task state changes with the ID of the request that submitted the run.
+// It is optional so that EventBroadcaster stays unchanged.
+type requestBroadcaster interface {
+	BroadcastWorkflowStateChangedFor(requestID, workflowID, name, oldState, newState string, updatedAt time.Time)
+	BroadcastTaskStateChangedFor(requestID, workflowID, taskID, taskName, oldState, newState, errorMessage string, result any, updatedAt time.Time)
+}
+
+func (e *Engine) emitWorkflowStateChanged(requestID, workflowID, name, oldState, newState string) {
+	if invalidator, ok := e.storage.(storage.Invalidator); ok {
+		invalidator.Invalidate(workflowID, "")
+	}
+	if isTerminalWorkflowStatus(newState) {
+		e.waiters.wake(workflowID)
+	}
 	if e.events == nil {
 		return
 	}
+	if broadcaster, ok := e.events.(requestBroadcaster); ok {
+		broadcaster.BroadcastWorkflowStateChangedFor(requestID, workflowID, name, oldState, newState, time.Now().UTC())
+		return
+	}
 	e.events.BroadcastWorkflowStateChanged(workflowID, name, oldState, newState, time.Now().UTC())
 }
 
+// deadlineMissBroadcaster is implemented by event broadcasters that publish
+// deadline misses. It is optional so that EventBroadcaster stays unchanged.
+type deadlineMissBroadcaster interface {
+	BroadcastTaskDeadlineMissed(workflowID, taskID, taskName string, deadline, finishedAt time.Time)
+}
+
+func (e *Engine) emitTaskDeadlineMissed(workflowID, taskID, taskName string, deadline, finishedAt time.Time) {
+	if broadcaster, ok := e.events.(deadlineMissBroadcaster); ok {
+		broadcaster.BroadcastTaskDeadlineMissed(workflowID, taskID, taskName, deadline, finishedAt)
+	}
+}
+
+// stallBroadcaster is implemented by event broadcasters that publish task
+// stalls. It is optional so that EventBroadcaster stays unchanged.
+type stallBroadcaster interface {
+	BroadcastTaskStalled(workflowID, taskID, taskName string, heartbeatTimeout time.Duration, stalls int)
+}
+
+// emitTaskStalled logs and publishes a task that missed its heartbeat timeout.
+func (e *Engine) emitTaskStalled(workflowID string, task *dag.Task, result TaskResult) {
+	e.logger.Warn("task stalled",
+		"workflow_id", workflowID,
+		"task_id", task.ID,
+		"heartbeat_timeout", task.HeartbeatTimeout,
+		"stall_policy", task.StallPolicy,
+	)
+	if broadcaster, ok := e.events.(stallBroadcaster); ok {
+		broadcaster.BroadcastTaskStalled(workflowID, task.ID, task.Name, task.HeartbeatTimeout, result.Stalls)
+	}
+}
+
 func (e *Engine) emitTaskStateChanged(
-	workflowID, taskID, taskName, oldState, newState, errorMessage string,
+	requestID, workflowID, taskID, taskName, oldState, newState, errorMessage string,
 	result any,
 ) {
+	if invalidator, ok := e.storage.(storage.Invalidator); ok {
+		invalidator.Invalidate(workflowID, taskID)
+	}
 	if e.events == nil {
 		return
 	}
+	if broadcaster, ok := e.events.(requestBroadcaster); ok {
+		broadcaster.BroadcastTaskStateChangedFor(requestID, workflowID, taskID, taskName, oldState, newState, errorMessage, result, time.Now().UTC())
+		return
+	}
 	e.events.BroadcastTaskStateChanged(
 		workflowID,
 		taskID,
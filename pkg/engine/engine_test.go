@@ -3,6 +3,8 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -145,6 +147,62 @@ func TestStateTracker_Concurrent(t *testing.T) {
 	}
 }
 
+func TestStateTracker_Range(t *testing.T) {
+	tr := newStateTracker()
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("task-%d", i)
+	}
+	tr.InitTasks(ids)
+	tr.SetState("task-7", TaskStateRunning)
+
+	seen := make(map[string]TaskState, len(ids))
+	tr.Range(func(r TaskResult) bool {
+		seen[r.TaskID] = r.State
+		// Transitions from within the callback must not deadlock.
+		tr.SetState(r.TaskID, TaskStateScheduled)
+		return true
+	})
+	if len(seen) != len(ids) {
+		t.Fatalf("Range visited %d tasks, want %d", len(seen), len(ids))
+	}
+	if seen["task-7"] != TaskStateRunning || seen["task-8"] != TaskStatePending {
+		t.Fatalf("unexpected states: task-7=%s task-8=%s", seen["task-7"], seen["task-8"])
+	}
+
+	visited := 0
+	tr.Range(func(TaskResult) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Fatalf("Range visited %d tasks after stopping, want 10", visited)
+	}
+	if got := len(tr.Results()); got != len(ids) {
+		t.Fatalf("Results() returned %d tasks, want %d", got, len(ids))
+	}
+}
+
+func BenchmarkStateTracker_Transitions(b *testing.B) {
+	tr := newStateTracker()
+	ids := make([]string, 100000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("task-%d", i)
+	}
+	tr.InitTasks(ids)
+	var next atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id := ids[next.Add(1)%int64(len(ids))]
+			tr.SetState(id, TaskStateRunning)
+			tr.SetState(id, TaskStateScheduled)
+		}
+	})
+}
+
 // --- taskRunner tests ---
 
 func TestTaskRunner_Success(t *testing.T) {
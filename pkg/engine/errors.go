@@ -1,6 +1,10 @@
 package engine
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
 
 // WorkflowCompileError is returned when a workflow DAG fails to compile.
 type WorkflowCompileError struct {
@@ -12,6 +16,9 @@ func (e *WorkflowCompileError) Error() string {
 	return fmt.Sprintf("workflow %q compile error: %v", e.WorkflowID, e.Cause)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *WorkflowCompileError) ErrorCode() errs.Code { return errs.ValidationFailed }
+
 func (e *WorkflowCompileError) Unwrap() error { return e.Cause }
 
 // TaskExecutionError is returned when a task fails after all retries.
@@ -33,3 +40,38 @@ type EngineNotRunningError struct{}
 func (e *EngineNotRunningError) Error() string {
 	return "engine is not running"
 }
+
+// ErrorCode implements errs.Coder.
+func (e *EngineNotRunningError) ErrorCode() errs.Code { return errs.ServiceUnavailable }
+
+// WorkflowConcurrencyLimitError is returned when a workflow is rejected because
+// the engine-wide or per-name concurrency limit is reached.
+type WorkflowConcurrencyLimitError struct {
+	// WorkflowName is the name whose limit was hit, or empty for the global limit.
+	WorkflowName string
+	Limit        int
+}
+
+func (e *WorkflowConcurrencyLimitError) Error() string {
+	if e.WorkflowName == "" {
+		return fmt.Sprintf("workflow concurrency limit reached (%d running)", e.Limit)
+	}
+	return fmt.Sprintf("workflow %q concurrency limit reached (%d running)", e.WorkflowName, e.Limit)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *WorkflowConcurrencyLimitError) ErrorCode() errs.Code { return errs.RateLimited }
+
+// WorkflowNotReplayableError is returned when a workflow run cannot be
+// replayed, e.g. because it has not finished or has no execution journal.
+type WorkflowNotReplayableError struct {
+	WorkflowID string
+	Reason     string
+}
+
+func (e *WorkflowNotReplayableError) Error() string {
+	return fmt.Sprintf("workflow %q cannot be replayed: %s", e.WorkflowID, e.Reason)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *WorkflowNotReplayableError) ErrorCode() errs.Code { return errs.Conflict }
@@ -2,12 +2,18 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/goclaw/goclaw/config"
 	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/requestid"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 )
 
@@ -58,6 +64,232 @@ func (m *mockEventBroadcaster) BroadcastTaskStateChanged(
 	})
 }
 
+type deadlineEventBroadcaster struct {
+	mockEventBroadcaster
+	missed chan string
+}
+
+func (m *deadlineEventBroadcaster) BroadcastTaskDeadlineMissed(_, taskID, _ string, _, _ time.Time) {
+	m.missed <- taskID
+}
+
+func TestEngine_EmitsDeadlineMissedEvent(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Queue.Dispatch = "edf"
+	mockEvents := &deadlineEventBroadcaster{missed: make(chan string, 2)}
+	store := memory.NewMemoryStorage()
+
+	eng, err := New(cfg, nil, store, WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:     "slo",
+		Deadline: 1,
+		Tasks: []models.TaskDefinition{
+			{ID: "fast", Name: "fast", Type: "function"},
+			{ID: "slow", Name: "slow", Type: "function", DependsOn: []string{"fast"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"slow": func(context.Context) error {
+				time.Sleep(1100 * time.Millisecond)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	select {
+	case taskID := <-mockEvents.missed:
+		if taskID != "slow" {
+			t.Fatalf("deadline missed event for %q, want slow", taskID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a deadline missed event")
+	}
+
+	status, err := eng.GetWorkflowStatusResponse(ctx, resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if status.Deadline == nil || !status.Deadline.Equal(status.CreatedAt.Add(time.Second)) {
+		t.Fatalf("Deadline = %v, want created_at + 1s", status.Deadline)
+	}
+	for _, task := range status.Tasks {
+		if task.DeadlineMissed != (task.ID == "slow") {
+			t.Fatalf("task %s DeadlineMissed = %v", task.ID, task.DeadlineMissed)
+		}
+	}
+	// The lane counts the miss once the task function has returned.
+	deadline := time.Now().Add(time.Second)
+	for eng.laneManager.GetStats()[defaultLaneName].DeadlineMissed == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := eng.laneManager.GetStats()[defaultLaneName].DeadlineMissed; got != 1 {
+		t.Fatalf("lane DeadlineMissed = %d, want 1", got)
+	}
+}
+
+type stallEventBroadcaster struct {
+	mockEventBroadcaster
+	stalled chan string
+}
+
+func (m *stallEventBroadcaster) BroadcastTaskStalled(_, taskID, _ string, _ time.Duration, _ int) {
+	m.stalled <- taskID
+}
+
+func TestEngine_FailsStalledTask(t *testing.T) {
+	mockEvents := &stallEventBroadcaster{stalled: make(chan string, 1)}
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	// The task hangs without heartbeating, like one whose worker died.
+	hung := make(chan struct{})
+	defer close(hung)
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "zombie",
+		Tasks: []models.TaskDefinition{
+			{ID: "work", Name: "work", Type: "function", HeartbeatTimeout: 1, StallPolicy: "fail"},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"work": func(context.Context) error {
+				<-hung
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	select {
+	case taskID := <-mockEvents.stalled:
+		if taskID != "work" {
+			t.Fatalf("stall event for %q, want work", taskID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a task stalled event")
+	}
+
+	status, err := eng.GetWorkflowStatusResponse(ctx, resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if status.Status != workflowStatusFailed {
+		t.Fatalf("workflow status = %q, want failed", status.Status)
+	}
+	if task := status.Tasks[0]; task.Status != taskStatusFailed || task.Stalls != 1 {
+		t.Fatalf("task = %+v, want failed with one stall", task)
+	}
+}
+
+type outputEventBroadcaster struct {
+	mockEventBroadcaster
+	chunks []string
+}
+
+func (m *outputEventBroadcaster) BroadcastTaskOutput(_, taskID string, seq int64, chunk string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunks = append(m.chunks, fmt.Sprintf("%s#%d:%s", taskID, seq, chunk))
+}
+
+func TestEngine_StreamsTaskOutput(t *testing.T) {
+	mockEvents := &outputEventBroadcaster{}
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	attempts := 0
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "generate",
+		Tasks: []models.TaskDefinition{{ID: "llm", Name: "llm", Type: "function", Retries: 1}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"llm": func(ctx context.Context) error {
+				attempts++
+				for _, token := range []string{"Hel", "", "lo"} {
+					if err := StreamTaskOutput(ctx, token); err != nil {
+						return err
+					}
+				}
+				if attempts == 1 {
+					return errors.New("connection reset")
+				}
+				return SetTaskResult(ctx, "Hello")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", resp.Status, resp.Error)
+	}
+
+	mockEvents.mu.Lock()
+	want := []string{"llm#1:Hel", "llm#2:lo", "llm#1:Hel", "llm#2:lo"}
+	if !slices.Equal(mockEvents.chunks, want) {
+		t.Fatalf("chunks = %v, want %v", mockEvents.chunks, want)
+	}
+	mockEvents.chunks = nil
+	mockEvents.mu.Unlock()
+
+	resp, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "generate-secret",
+		Tasks: []models.TaskDefinition{{ID: "llm", Name: "llm", Type: "function", Sensitive: true}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"llm": func(ctx context.Context) error {
+				return StreamTaskOutput(ctx, "account 1234")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", resp.Status, resp.Error)
+	}
+	mockEvents.mu.Lock()
+	if len(mockEvents.chunks) != 0 {
+		t.Fatalf("chunks of a sensitive task = %v, want none", mockEvents.chunks)
+	}
+	mockEvents.mu.Unlock()
+
+	if err := StreamTaskOutput(ctx, "x"); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("StreamTaskOutput() outside a task error = %v, want BadRequest", err)
+	}
+}
+
 func TestEngine_EmitsWorkflowAndTaskEvents(t *testing.T) {
 	cfg := minConfig()
 	mockEvents := &mockEventBroadcaster{}
@@ -116,6 +348,81 @@ func TestEngine_EmitsWorkflowAndTaskEvents(t *testing.T) {
 	}
 }
 
+// requestEventBroadcaster records the request IDs of state changes.
+type requestEventBroadcaster struct {
+	mockEventBroadcaster
+	requestIDs []string
+}
+
+func (m *requestEventBroadcaster) BroadcastWorkflowStateChangedFor(requestID, workflowID, name, oldState, newState string, updatedAt time.Time) {
+	m.mu.Lock()
+	m.requestIDs = append(m.requestIDs, requestID)
+	m.mu.Unlock()
+	m.BroadcastWorkflowStateChanged(workflowID, name, oldState, newState, updatedAt)
+}
+
+func (m *requestEventBroadcaster) BroadcastTaskStateChangedFor(requestID, workflowID, taskID, taskName, oldState, newState, errorMessage string, result any, updatedAt time.Time) {
+	m.mu.Lock()
+	m.requestIDs = append(m.requestIDs, requestID)
+	m.mu.Unlock()
+	m.BroadcastTaskStateChanged(workflowID, taskID, taskName, oldState, newState, errorMessage, result, updatedAt)
+}
+
+func TestEngine_CorrelatesRequestID(t *testing.T) {
+	mockEvents := &requestEventBroadcaster{}
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store, WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var taskRequestID string
+	resp, err := eng.SubmitWorkflowRuntime(requestid.NewContext(ctx, "req-42"), &models.WorkflowRequest{
+		Name:  "correlated",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "task-1", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"t1": func(ctx context.Context) error {
+				taskRequestID = requestid.FromContext(ctx)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.RequestID != "req-42" {
+		t.Fatalf("response request ID = %q, want req-42", resp.RequestID)
+	}
+	if taskRequestID != "req-42" {
+		t.Fatalf("task context request ID = %q, want req-42", taskRequestID)
+	}
+	stored, err := store.GetWorkflow(ctx, resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if stored.RequestID != "req-42" {
+		t.Fatalf("stored request ID = %q, want req-42", stored.RequestID)
+	}
+
+	mockEvents.mu.Lock()
+	defer mockEvents.mu.Unlock()
+	if len(mockEvents.requestIDs) == 0 || len(mockEvents.taskEvents) == 0 {
+		t.Fatalf("expected workflow and task events, got %d request IDs, %d task events", len(mockEvents.requestIDs), len(mockEvents.taskEvents))
+	}
+	for i, id := range mockEvents.requestIDs {
+		if id != "req-42" {
+			t.Fatalf("event %d request ID = %q, want req-42", i, id)
+		}
+	}
+}
+
 func TestEngine_EmitsCancelEvent(t *testing.T) {
 	cfg := minConfig()
 	mockEvents := &mockEventBroadcaster{}
@@ -159,3 +466,155 @@ func TestEngine_EmitsCancelEvent(t *testing.T) {
 		t.Fatalf("expected cancelled event for workflow %s", workflowID)
 	}
 }
+
+type slaEventBroadcaster struct {
+	mockEventBroadcaster
+	breached chan string
+}
+
+func (m *slaEventBroadcaster) BroadcastWorkflowSLABreached(workflowID, _, status string, _ map[string]string, _, _ time.Time) {
+	m.breached <- workflowID + ":" + status
+}
+
+func TestEngine_EmitsSLABreachedEvent(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.SLACheckInterval = 20 * time.Millisecond
+	mockEvents := &slaEventBroadcaster{breached: make(chan string, 2)}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage(), WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	release := make(chan struct{})
+	finishBy := time.Now().Add(100 * time.Millisecond)
+	late, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "late",
+		SLA:   &models.SLA{MaxDuration: 3600, FinishBy: &finishBy},
+		Tasks: []models.TaskDefinition{{ID: "wait", Name: "wait", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"wait": func(context.Context) error {
+				<-release
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	// The breach is reported while the run is still unfinished.
+	select {
+	case got := <-mockEvents.breached:
+		if got != late.ID+":running" {
+			t.Fatalf("sla breached event %q, want %s:running", got, late.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an sla breached event")
+	}
+	status, err := eng.GetWorkflowStatusResponse(ctx, late.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if status.SLA == nil || status.SLA.State != models.SLABreached || !status.SLA.Deadline.Equal(finishBy.UTC()) || status.SLA.BreachedAt == nil {
+		t.Fatalf("SLA = %+v, want breached at the finish_by deadline", status.SLA)
+	}
+	close(release)
+
+	onTime, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "on-time",
+		SLA:   &models.SLA{MaxDuration: 3600},
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if onTime.SLA == nil || onTime.SLA.State != models.SLAMet {
+		t.Fatalf("SLA = %+v, want met", onTime.SLA)
+	}
+	select {
+	case got := <-mockEvents.breached:
+		t.Fatalf("unexpected sla breached event %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type anomalyEventBroadcaster struct {
+	mockEventBroadcaster
+	mu        sync.Mutex
+	anomalies []string
+}
+
+func (m *anomalyEventBroadcaster) BroadcastDurationAnomaly(workflowID, _, taskID string, _, _ time.Duration, _, _ float64, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.anomalies = append(m.anomalies, workflowID+":"+taskID)
+}
+
+func (m *anomalyEventBroadcaster) has(event string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range m.anomalies {
+		if a == event {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEngine_EmitsDurationAnomalyEvents(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Insights = config.InsightsConfig{Enabled: true, ZScore: 3, MinSamples: 5}
+	mockEvents := &anomalyEventBroadcaster{}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage(), WithEventBroadcaster(mockEvents))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	run := func(delay time.Duration) string {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+			Name:  "etl",
+			Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{
+				"a": func(context.Context) error {
+					time.Sleep(delay)
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		return resp.ID
+	}
+
+	for i := 0; i < 10; i++ {
+		run(time.Millisecond)
+	}
+	slow := run(300 * time.Millisecond)
+
+	if !mockEvents.has(slow+":") || !mockEvents.has(slow+":a") {
+		t.Fatalf("anomalies = %v, want the workflow and task of %s", mockEvents.anomalies, slow)
+	}
+	stats := eng.Insights().Stats("etl")
+	if len(stats) != 2 || stats[0].Samples != 11 || stats[1].Task != "a" {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
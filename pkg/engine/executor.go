@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// TaskExecutor runs tasks submitted without a task function, such as tasks
+// from the REST API, for one agent type.
+type TaskExecutor interface {
+	// TaskFunc returns the function running task of workflow workflowID.
+	TaskFunc(workflowID string, task *dag.Task) func(context.Context) error
+}
+
+// RegisterTaskExecutor is WithTaskExecutor for OnStart hooks. Executors are
+// read without locking, so it fails once the engine is running.
+func (e *Engine) RegisterTaskExecutor(taskType string, exec TaskExecutor) error {
+	if state := engineState(e.state.Load()); state == stateRunning || state == stateStopping {
+		return errs.New(errs.Conflict, "task executors cannot be registered while the engine is running")
+	}
+	WithTaskExecutor(taskType, exec)(e)
+	return nil
+}
+
+// checkExecutors rejects container tasks of req that no task function or
+// executor can run.
+func (e *Engine) checkExecutors(req *models.WorkflowRequest, taskFns map[string]func(context.Context) error) error {
+	for _, task := range req.Tasks {
+		if task.Type != dag.AgentContainer || taskFns[task.ID] != nil {
+			continue
+		}
+		if _, ok := e.executors[dag.AgentContainer]; !ok {
+			return errs.Newf(errs.BadRequest, "task %s: container tasks are not enabled", task.ID)
+		}
+	}
+	return nil
+}
+
+// hasExecutorTasks reports whether an executor runs some task of req, so
+// the workflow runs without task functions.
+func (e *Engine) hasExecutorTasks(req *models.WorkflowRequest) bool {
+	for _, task := range req.Tasks {
+		if _, ok := e.executors[task.Type]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// executorFn returns the function of the executor for task's agent type, or
+// a no-op if there is none.
+func (s *Scheduler) executorFn(task *dag.Task) func(context.Context) error {
+	if exec, ok := s.executors[task.Agent]; ok {
+		return exec.TaskFunc(s.workflowID, task)
+	}
+	return noopTaskFn
+}
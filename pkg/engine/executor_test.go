@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// echoExecutor runs tasks by writing their ID to the task log.
+type echoExecutor struct{}
+
+func (echoExecutor) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		w := TaskLog(ctx, TaskLogStdout)
+		defer w.Close()
+		fmt.Fprintf(w, "hello from %s\npartial", task.ID)
+		return nil
+	}
+}
+
+func TestEngine_RejectsContainerTasksWithoutExecutor(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	_, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "build",
+		Tasks: []models.TaskDefinition{
+			{ID: "c", Name: "c", Type: "container", Container: &models.ContainerSpec{Image: "alpine"}},
+		},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeSync})
+	if !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v, want BadRequest", err)
+	}
+}
+
+func TestEngine_ExecutorOutputInTaskLogs(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithTaskExecutor(dag.AgentContainer, echoExecutor{}))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "build",
+		Tasks: []models.TaskDefinition{
+			{ID: "c", Name: "c", Type: "container", Container: &models.ContainerSpec{Image: "alpine"}},
+		},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeSync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	lines, err := eng.TaskLogs(ctx, resp.ID, "c", 0)
+	if err != nil {
+		t.Fatalf("TaskLogs() error = %v", err)
+	}
+	if len(lines) != 2 || lines[0].Text != "hello from c" || lines[1].Text != "partial" || lines[0].Stream != TaskLogStdout {
+		t.Fatalf("TaskLogs() = %+v", lines)
+	}
+	if lines, _ := eng.TaskLogs(ctx, resp.ID, "c", lines[0].Seq); len(lines) != 1 || lines[0].Seq != 2 {
+		t.Fatalf("TaskLogs(after 1) = %+v", lines)
+	}
+	if _, err := eng.TaskLogs(ctx, resp.ID, "missing", 0); !errs.Is(err, errs.NotFound) {
+		t.Fatalf("TaskLogs(missing) error = %v, want NotFound", err)
+	}
+}
+
+func TestTaskLog_KeepsLastLines(t *testing.T) {
+	store := newTaskLogStore(config.TaskLogsConfig{MaxLines: 2, MaxTasks: 1})
+	ctx := store.withTask(context.Background(), "wf", "a")
+	w := TaskLog(ctx, TaskLogStderr)
+	fmt.Fprint(w, "one\ntwo\r\nthree\n")
+
+	lines := store.since("wf", "a", 0)
+	if len(lines) != 2 || lines[0].Text != "two" || lines[1].Text != "three" || lines[1].Seq != 3 {
+		t.Fatalf("since() = %+v", lines)
+	}
+
+	store.withTask(context.Background(), "wf", "b")
+	if lines := store.since("wf", "a", 0); lines != nil {
+		t.Fatalf("since() of evicted task = %+v", lines)
+	}
+}
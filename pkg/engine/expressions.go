@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/expr"
+)
+
+// Expression kinds, as reported to metrics.
+const (
+	exprKindWhen    = "when"
+	exprKindRetryIf = "retry_if"
+	exprKindConfig  = "config"
+)
+
+// Variables of task expressions. Every expression sees the workflow, the
+// states of its tasks, the task itself and facts about the node running it;
+// retry conditions also see the error of the failed attempt.
+var (
+	exprVars        = []string{"workflow", "tasks", "task", "env"}
+	retryIfExprVars = []string{"workflow", "tasks", "task", "env", "error"}
+)
+
+// expressionRecorder is implemented by metrics recorders that count task
+// expression evaluations. It is optional so that MetricsRecorder stays
+// unchanged.
+type expressionRecorder interface {
+	RecordExpressionEvaluation(kind, outcome string, duration time.Duration)
+}
+
+// configKey is the context key of the running attempt's evaluated config.
+type configKey struct{}
+
+// TaskConfig returns the config of the task running with ctx, with the
+// ${{ expr }} placeholders of its strings evaluated for the running attempt.
+// The config belongs to the caller.
+func TaskConfig(ctx context.Context) (map[string]any, error) {
+	config, ok := ctx.Value(configKey{}).(map[string]any)
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "task config is only available to tasks run by the engine")
+	}
+	return copyConfig(config).(map[string]any), nil
+}
+
+// checkExpressions returns a BadRequest error when a condition, retry
+// condition or config placeholder of a task of req does not compile.
+func checkExpressions(req *models.WorkflowRequest) error {
+	for _, task := range req.Tasks {
+		if task.When != "" {
+			if _, err := expr.Compile(task.When, exprVars); err != nil {
+				return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("task %s when", task.ID))
+			}
+		}
+		if task.RetryIf != "" {
+			if _, err := expr.Compile(task.RetryIf, retryIfExprVars); err != nil {
+				return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("task %s retry_if", task.ID))
+			}
+		}
+		if _, err := expandConfig(task.Config, nil, false); err != nil {
+			return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("task %s config", task.ID))
+		}
+	}
+	return nil
+}
+
+// expandConfig returns a copy of v with the placeholders of its strings
+// evaluated with vars. With eval false the placeholders are only compiled.
+func expandConfig(v any, vars map[string]any, eval bool) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if !expr.IsTemplate(v) {
+			return v, nil
+		}
+		tmpl, err := expr.CompileTemplate(v, exprVars)
+		if err != nil || !eval {
+			return v, err
+		}
+		return tmpl.Eval(vars)
+	case map[string]interface{}:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			expanded, err := expandConfig(item, vars, eval)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := expandConfig(item, vars, eval)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// workflowExpressions evaluates the expressions of the tasks of one running
+// workflow against its record, which exec.mu guards.
+type workflowExpressions struct {
+	engine *Engine
+	exec   *workflowExecution
+}
+
+// when reports whether task should run.
+func (x *workflowExpressions) when(task *dag.Task) (bool, error) {
+	start := time.Now()
+	run, err := x.evalBool(task.When, exprVars, x.vars(task, 0, nil))
+	x.record(exprKindWhen, start, err)
+	return run, err
+}
+
+// retryIf reports whether task should be retried after its attempt, counted
+// from zero, failed with cause.
+func (x *workflowExpressions) retryIf(task *dag.Task, attempt int, cause error) (bool, error) {
+	start := time.Now()
+	retry, err := x.evalBool(task.RetryIf, retryIfExprVars, x.vars(task, attempt, cause))
+	x.record(exprKindRetryIf, start, err)
+	return retry, err
+}
+
+// withConfig returns ctx carrying the config of task evaluated for attempt,
+// counted from zero, for TaskConfig. It returns ctx as it is on error.
+func (x *workflowExpressions) withConfig(ctx context.Context, task *dag.Task, attempt int) (context.Context, error) {
+	start := time.Now()
+	config, err := x.config(task, attempt)
+	x.record(exprKindConfig, start, err)
+	if err != nil {
+		return ctx, fmt.Errorf("evaluate task config: %w", err)
+	}
+	return context.WithValue(ctx, configKey{}, config), nil
+}
+
+func (x *workflowExpressions) config(task *dag.Task, attempt int) (map[string]any, error) {
+	var raw map[string]interface{}
+	x.exec.mu.Lock()
+	for _, def := range x.exec.wfState.Tasks {
+		if def.ID == task.ID {
+			raw = def.Config
+			break
+		}
+	}
+	x.exec.mu.Unlock()
+	if raw == nil {
+		return map[string]any{}, nil
+	}
+	config, err := expandConfig(raw, x.vars(task, attempt, nil), true)
+	if err != nil {
+		return nil, err
+	}
+	return config.(map[string]any), nil
+}
+
+func (x *workflowExpressions) evalBool(src string, names []string, vars map[string]any) (bool, error) {
+	p, err := expr.Compile(src, names)
+	if err != nil {
+		return false, err
+	}
+	return p.EvalBool(vars)
+}
+
+// vars returns the variables of an expression of task, evaluated for
+// attempt; cause is the error of a failed attempt, nil otherwise.
+func (x *workflowExpressions) vars(task *dag.Task, attempt int, cause error) map[string]any {
+	hostname, _ := os.Hostname()
+	x.exec.mu.Lock()
+	wf := x.exec.wfState
+	tasks := make(map[string]any, len(wf.TaskStatus))
+	for id, state := range wf.TaskStatus {
+		tasks[id] = map[string]any{
+			"status":  state.Status,
+			"result":  state.Result,
+			"error":   state.Error,
+			"skipped": state.Skipped,
+		}
+	}
+	workflow := map[string]any{
+		"id":       wf.ID,
+		"name":     wf.Name,
+		"metadata": stringMap(wf.Metadata),
+		"values":   stringMap(wf.Values),
+		"priority": float64(wf.Priority),
+	}
+	x.exec.mu.Unlock()
+
+	vars := map[string]any{
+		"workflow": workflow,
+		"tasks":    tasks,
+		"task": map[string]any{
+			"id":      task.ID,
+			"name":    task.Name,
+			"lane":    task.Lane,
+			"attempt": float64(attempt + 1),
+			"retries": float64(task.Retries),
+		},
+		"env": map[string]any{
+			"hostname":    hostname,
+			"environment": x.engine.cfg.App.Environment,
+			"node":        x.engine.cfg.Cluster.NodeID,
+		},
+	}
+	if cause != nil {
+		vars["error"] = cause.Error()
+	}
+	return vars
+}
+
+// record reports an evaluation that started at start to the metrics
+// recorder.
+func (x *workflowExpressions) record(kind string, start time.Time, err error) {
+	recorder, ok := x.engine.metrics.(expressionRecorder)
+	if !ok {
+		return
+	}
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	recorder.RecordExpressionEvaluation(kind, outcome, time.Since(start))
+}
+
+// copyConfig returns a deep copy of the config value v.
+func copyConfig(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = copyConfig(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = copyConfig(item)
+		}
+		return out
+	}
+	return v
+}
+
+func stringMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
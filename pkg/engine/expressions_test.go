@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+type expressionMetrics struct {
+	nopMetrics
+	mu    sync.Mutex
+	evals map[string]int
+}
+
+func (m *expressionMetrics) RecordExpressionEvaluation(kind, outcome string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evals[kind+"/"+outcome]++
+}
+
+func TestExpressions_ConditionsRetriesAndConfig(t *testing.T) {
+	metrics := &expressionMetrics{evals: make(map[string]int)}
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var mu sync.Mutex
+	ran := make(map[string]int)
+	var config map[string]any
+	count := func(id string) {
+		mu.Lock()
+		defer mu.Unlock()
+		ran[id]++
+	}
+	req := &models.WorkflowRequest{
+		Name:     "expressions",
+		Metadata: map[string]string{"region": "eu-west-1"},
+		Tasks: []models.TaskDefinition{
+			{ID: "extract", Name: "extract", Type: "function"},
+			{ID: "load", Name: "load", Type: "function", DependsOn: []string{"extract"},
+				When: `tasks.extract.result.rows > 0`,
+				Config: map[string]interface{}{
+					"rows":   "${{ tasks.extract.result.rows }}",
+					"target": "s3://bucket/${{ workflow.metadata.region }}/${{ lower(task.name) }}-${{ task.attempt }}.csv",
+					"tags":   []interface{}{"static", "${{ env.environment }}"},
+				}},
+			{ID: "archive", Name: "archive", Type: "function", DependsOn: []string{"extract"},
+				When: `tasks.extract.result.rows == 0`},
+			{ID: "notify", Name: "notify", Type: "function", DependsOn: []string{"archive"}, Retries: 3,
+				RetryIf: `error.contains("503") && tasks.archive.skipped`},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeSync, TaskFns: map[string]func(context.Context) error{
+		"extract": func(ctx context.Context) error {
+			count("extract")
+			return SetTaskResult(ctx, map[string]any{"rows": 42})
+		},
+		"load": func(ctx context.Context) error {
+			count("load")
+			var err error
+			config, err = TaskConfig(ctx)
+			return err
+		},
+		"archive": func(context.Context) error {
+			count("archive")
+			return nil
+		},
+		"notify": func(context.Context) error {
+			count("notify")
+			mu.Lock()
+			defer mu.Unlock()
+			if ran["notify"] == 1 {
+				return errors.New("HTTP 503")
+			}
+			return errors.New("HTTP 400")
+		},
+	}})
+	if err != nil || resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, "HTTP 400") {
+		t.Fatalf("SubmitWorkflowRuntime() = %+v, %v, want notify to fail with HTTP 400", resp, err)
+	}
+	if ran["load"] != 1 || ran["archive"] != 0 || ran["notify"] != 2 {
+		t.Fatalf("runs = %v, want load once, archive skipped and notify retried once", ran)
+	}
+	want := map[string]any{
+		"rows":   float64(42),
+		"target": "s3://bucket/eu-west-1/load-1.csv",
+		"tags":   []any{"static", minConfig().App.Environment},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Fatalf("TaskConfig() = %v, want %v", config, want)
+	}
+
+	tasks, _, err := eng.ListWorkflowTasksResponse(ctx, resp.ID, models.TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkflowTasksResponse() error = %v", err)
+	}
+	for _, task := range tasks {
+		if skipped := task.ID == "archive"; task.Skipped != skipped {
+			t.Fatalf("task %s skipped = %v, want %v", task.ID, task.Skipped, skipped)
+		}
+		if task.ID == "archive" && task.Status != taskStatusCompleted {
+			t.Fatalf("archive status = %s, want completed", task.Status)
+		}
+	}
+	metrics.mu.Lock()
+	if metrics.evals["when/ok"] != 2 || metrics.evals["retry_if/ok"] != 2 || metrics.evals["config/ok"] != 4 {
+		t.Fatalf("evaluations = %v", metrics.evals)
+	}
+	metrics.mu.Unlock()
+
+	for _, task := range []models.TaskDefinition{
+		{ID: "a", Name: "a", Type: "function", When: `secrets.token != ""`},
+		{ID: "a", Name: "a", Type: "function", RetryIf: `error ==`},
+		{ID: "a", Name: "a", Type: "function", Config: map[string]interface{}{"cmd": []interface{}{"${{ exec('id') }}"}}},
+	} {
+		req := &models.WorkflowRequest{Name: "invalid", Tasks: []models.TaskDefinition{task}}
+		if err := eng.ValidateWorkflowRequest(ctx, req); !errs.Is(err, errs.BadRequest) {
+			t.Fatalf("ValidateWorkflowRequest(%+v) error = %v, want BadRequest", task, err)
+		}
+	}
+	if _, err := TaskConfig(ctx); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("TaskConfig() without a task error = %v, want BadRequest", err)
+	}
+}
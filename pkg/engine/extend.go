@@ -0,0 +1,266 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/policy"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// runPlan is the plan a Scheduler runs. The layers after the running one
+// are recompiled when tasks are appended to the run.
+type runPlan struct {
+	mu      sync.Mutex
+	tracker *StateTracker
+	layers  [][]string
+	// tasks holds every task of the run by ID, with all its dependencies.
+	tasks map[string]*dag.Task
+	// current is the index of the running layer, -1 before the first.
+	current int
+	// done is set once the scheduler has no layer left to run.
+	done bool
+}
+
+func newRunPlan(plan *dag.ExecutionPlan, tracker *StateTracker) *runPlan {
+	p := &runPlan{
+		tracker: tracker,
+		layers:  slices.Clone(plan.Layers),
+		tasks:   make(map[string]*dag.Task, plan.TotalTasks),
+		current: -1,
+	}
+	for _, layer := range plan.Layers {
+		for _, taskID := range layer {
+			if task, ok := plan.Task(taskID); ok {
+				p.tasks[taskID] = task
+			}
+		}
+	}
+	return p
+}
+
+// layer returns layer idx and marks it running. Once there is no such
+// layer the plan is done and takes no more tasks.
+func (p *runPlan) layer(idx int) ([]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done || idx >= len(p.layers) {
+		p.done = true
+		return nil, false
+	}
+	p.current = idx
+	return p.layers[idx], true
+}
+
+// finish marks the plan done when the scheduler stops early.
+func (p *runPlan) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = true
+}
+
+// task returns the task of the run with the given ID.
+func (p *runPlan) task(id string) (*dag.Task, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	task, ok := p.tasks[id]
+	return task, ok
+}
+
+// extend compiles tasks with the layers not started yet and, if commit
+// accepts the compiled remainder of the plan, replaces those layers with
+// it. Dependencies on tasks of the running or earlier layers are left out
+// of the compilation, as they finish before any later layer starts.
+func (p *runPlan) extend(tasks []*dag.Task, commit func(remainder *dag.ExecutionPlan) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return errs.New(errs.Conflict, "workflow has no tasks left to run")
+	}
+
+	pending := make(map[string]*dag.Task)
+	for _, layer := range p.layers[p.current+1:] {
+		for _, taskID := range layer {
+			pending[taskID] = p.tasks[taskID]
+		}
+	}
+	for _, task := range tasks {
+		pending[task.ID] = task
+	}
+	g := dag.NewGraph()
+	for _, task := range pending {
+		remaining := task.Clone()
+		remaining.Deps = slices.DeleteFunc(remaining.Deps, func(dep string) bool {
+			_, ok := pending[dep]
+			return !ok
+		})
+		if err := g.AddTask(remaining); err != nil {
+			return errs.Wrap(err, errs.BadRequest, "invalid task")
+		}
+	}
+	remainder, err := g.Compile()
+	if err != nil {
+		return errs.Wrap(err, errs.BadRequest, "invalid task dependencies")
+	}
+	if err := commit(remainder); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		p.tasks[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+	p.tracker.InitTasks(ids)
+	p.layers = append(p.layers[:p.current+1:p.current+1], remainder.Layers...)
+	return nil
+}
+
+// AppendWorkflowTasks adds tasks to the workflow id while it runs, for
+// agents that grow their plan as they go. The tasks may depend on each
+// other and on tasks of the workflow that have not started yet or have
+// completed. The layers not started yet are recompiled with them, so each
+// runs as soon as its dependencies allow. Only the node running the
+// workflow can extend it.
+func (e *Engine) AppendWorkflowTasks(ctx context.Context, id string, tasks []models.TaskDefinition) (*models.WorkflowStatusResponse, error) {
+	exec, ok := e.getExecution(id)
+	if !ok {
+		wfState, err := e.storage.GetWorkflow(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if isTerminalWorkflowStatus(wfState.Status) {
+			return nil, errs.Newf(errs.Conflict, "workflow is already %s", wfState.Status)
+		}
+		return nil, errs.New(errs.Conflict, "workflow is not running on this node")
+	}
+
+	exec.mu.Lock()
+	name := exec.wfState.Name
+	exec.mu.Unlock()
+	req := &models.WorkflowRequest{Name: name, Tasks: tasks}
+	if err := e.env.check(req); err != nil {
+		return nil, err
+	}
+	if err := e.rateLimits.check(req); err != nil {
+		return nil, err
+	}
+	if err := checkOutputSchemas(req); err != nil {
+		return nil, err
+	}
+	if err := checkExpressions(req); err != nil {
+		return nil, err
+	}
+	if err := e.checkCompensations(req, nil); err != nil {
+		return nil, err
+	}
+	if err := e.checkExecutors(req, nil); err != nil {
+		return nil, err
+	}
+	if err := e.checkPolicy(ctx, policy.KindWorkflow, req.Name, req); err != nil {
+		return nil, err
+	}
+
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	if exec.run == nil {
+		return nil, errs.New(errs.Conflict, "workflow has not started yet")
+	}
+	if isTerminalWorkflowStatus(exec.wfState.Status) {
+		return nil, errs.Newf(errs.Conflict, "workflow is already %s", exec.wfState.Status)
+	}
+	if err := checkAppendedDependencies(exec.wfState, tasks); err != nil {
+		return nil, err
+	}
+
+	wf := e.workflowFromState(&storage.WorkflowState{Tasks: tasks, CreatedAt: exec.wfState.CreatedAt}, nil)
+	for _, task := range wf.Tasks {
+		if task.Lane == "" {
+			task.Lane = defaultLaneName
+		}
+	}
+	err := exec.run.extend(wf.Tasks, func(remainder *dag.ExecutionPlan) error {
+		if err := checkPlanResources(e.laneManager, remainder, exec.wfState.GangLayers); err != nil {
+			return err
+		}
+		return e.saveAppendedTasks(ctx, exec, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	e.logger.Info("tasks appended to workflow", "workflow_id", id, "tasks", len(tasks))
+	return e.workflowStateToResponse(ctx, exec.wfState), nil
+}
+
+// checkAppendedDependencies rejects tasks whose ID is taken, or that depend
+// on a task of wf that is running or did not complete. The caller holds the
+// execution lock.
+func checkAppendedDependencies(wf *storage.WorkflowState, tasks []models.TaskDefinition) error {
+	added := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if _, ok := wf.TaskStatus[task.ID]; ok || added[task.ID] {
+			return errs.Newf(errs.BadRequest, "task %s already exists", task.ID)
+		}
+		added[task.ID] = true
+	}
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if added[dep] {
+				continue
+			}
+			state, ok := wf.TaskStatus[dep]
+			if !ok {
+				return errs.Newf(errs.BadRequest, "task %s depends on unknown task %s", task.ID, dep)
+			}
+			switch state.Status {
+			case taskStatusPending, taskStatusScheduled, taskStatusCompleted:
+			default:
+				return errs.Newf(errs.BadRequest, "task %s depends on task %s, which is %s", task.ID, dep, state.Status)
+			}
+		}
+	}
+	return nil
+}
+
+// saveAppendedTasks adds tasks to the state of exec and saves them, leaving
+// the state unchanged if that fails. The caller holds the execution lock.
+func (e *Engine) saveAppendedTasks(ctx context.Context, exec *workflowExecution, tasks []models.TaskDefinition) error {
+	wfState := exec.wfState
+	before := wfState.Tasks
+	wfState.Tasks = append(slices.Clip(before), tasks...)
+	states := make([]*storage.TaskState, 0, len(tasks))
+	for _, task := range tasks {
+		taskState := newTaskState(task)
+		wfState.TaskStatus[task.ID] = taskState
+		states = append(states, taskState)
+	}
+
+	err := e.taskWrites.flush(ctx, exec.workflowID)
+	if err == nil {
+		err = storage.WriteBatch(ctx, e.storage, func(w storage.Writer) error {
+			if err := w.SaveWorkflow(ctx, wfState); err != nil {
+				return fmt.Errorf("failed to save workflow: %w", err)
+			}
+			for _, taskState := range states {
+				if err := w.SaveTask(ctx, wfState.ID, taskState); err != nil {
+					return fmt.Errorf("failed to save appended task %s: %w", taskState.ID, err)
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		wfState.Tasks = before
+		for _, task := range tasks {
+			delete(wfState.TaskStatus, task.ID)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// orderExecutor records the order tasks run in; the gate task blocks until
+// released.
+type orderExecutor struct {
+	mu      sync.Mutex
+	ran     []string
+	release chan struct{}
+}
+
+func (x *orderExecutor) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if task.ID == "gate" {
+			<-x.release
+		}
+		x.mu.Lock()
+		defer x.mu.Unlock()
+		x.ran = append(x.ran, task.ID)
+		return nil
+	}
+}
+
+func TestEngine_AppendWorkflowTasks(t *testing.T) {
+	x := &orderExecutor{release: make(chan struct{})}
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store, WithTaskExecutor("script", x))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	status, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "agent",
+		Tasks: []models.TaskDefinition{
+			{ID: "gate", Name: "gate", Type: "script"},
+			{ID: "after", Name: "after", Type: "script", DependsOn: []string{"gate"}},
+		},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stored, err := store.GetWorkflow(ctx, status.ID)
+		if err == nil && stored.TaskStatus["gate"].Status == taskStatusRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("gate task did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for name, tasks := range map[string][]models.TaskDefinition{
+		"a duplicate ID":                 {{ID: "after", Name: "after", Type: "script"}},
+		"an unknown dependency":          {{ID: "x", Name: "x", Type: "script", DependsOn: []string{"missing"}}},
+		"a dependency on a running task": {{ID: "x", Name: "x", Type: "script", DependsOn: []string{"gate"}}},
+	} {
+		if _, err := eng.AppendWorkflowTasks(ctx, status.ID, tasks); !errs.Is(err, errs.BadRequest) {
+			t.Errorf("AppendWorkflowTasks() with %s error = %v, want BadRequest", name, err)
+		}
+	}
+
+	resp, err := eng.AppendWorkflowTasks(ctx, status.ID, []models.TaskDefinition{
+		{ID: "extra", Name: "extra", Type: "script", DependsOn: []string{"after", "side"}},
+		{ID: "side", Name: "side", Type: "script"},
+	})
+	if err != nil {
+		t.Fatalf("AppendWorkflowTasks() error = %v", err)
+	}
+	if len(resp.Tasks) != 4 {
+		t.Fatalf("AppendWorkflowTasks() returned %d tasks, want 4", len(resp.Tasks))
+	}
+
+	close(x.release)
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	final, err := eng.WaitWorkflow(waitCtx, status.ID)
+	if err != nil {
+		t.Fatalf("WaitWorkflow() error = %v", err)
+	}
+	if final.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", final.Status, final.Error)
+	}
+	for _, task := range final.Tasks {
+		if task.Status != taskStatusCompleted {
+			t.Errorf("task %s status = %s, want completed", task.ID, task.Status)
+		}
+	}
+
+	x.mu.Lock()
+	ran := slices.Clone(x.ran)
+	x.mu.Unlock()
+	pos := func(id string) int { return slices.Index(ran, id) }
+	if len(ran) != 4 || pos("gate") > pos("after") || pos("after") > pos("extra") || pos("side") > pos("extra") {
+		t.Fatalf("tasks ran in order %v, want extra after gate, after and side", ran)
+	}
+	stored, err := store.GetWorkflow(ctx, status.ID)
+	if err != nil || len(stored.Tasks) != 4 {
+		t.Fatalf("stored workflow = %+v, %v, want the appended tasks persisted", stored, err)
+	}
+
+	if _, err := eng.AppendWorkflowTasks(ctx, status.ID, []models.TaskDefinition{{ID: "late", Name: "late", Type: "script"}}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("AppendWorkflowTasks() to a finished workflow error = %v, want Conflict", err)
+	}
+}
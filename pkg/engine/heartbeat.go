@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+)
+
+// ErrTaskStalled is the error of a task attempt abandoned because it went
+// without a heartbeat for longer than its heartbeat timeout.
+var ErrTaskStalled = errors.New("task stalled: no heartbeat within timeout")
+
+// heartbeatKey is the context key of the running attempt's heartbeatMonitor.
+type heartbeatKey struct{}
+
+// Heartbeat records that the task running with ctx is still making progress.
+// Tasks with a heartbeat timeout must call it more often than the timeout, or
+// they are treated as stalled according to their stall policy. It is a no-op
+// for tasks without a heartbeat timeout, so task functions may call it
+// unconditionally.
+func Heartbeat(ctx context.Context) {
+	if m, ok := ctx.Value(heartbeatKey{}).(*heartbeatMonitor); ok {
+		m.beat()
+	}
+}
+
+// heartbeatMonitor watches the heartbeats of one task attempt.
+type heartbeatMonitor struct {
+	timeout time.Duration
+	beats   chan struct{}
+}
+
+func (m *heartbeatMonitor) beat() {
+	select {
+	case m.beats <- struct{}{}:
+	default:
+	}
+}
+
+// watch calls onStall when timeout passes without a heartbeat and onResume
+// when heartbeats resume after a stall, until ctx is done.
+func (m *heartbeatMonitor) watch(ctx context.Context, onStall, onResume func()) {
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+
+	stalled := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.beats:
+			if stalled {
+				stalled = false
+				onResume()
+			}
+			timer.Reset(m.timeout)
+		case <-timer.C:
+			if !stalled {
+				stalled = true
+				onStall()
+			}
+		}
+	}
+}
+
+// watchHeartbeat returns the context of one attempt of a task with a
+// heartbeat timeout and a func that must be called when the attempt ends.
+// Unless the stall policy is StallPolicyMark, a stall cancels the context
+// with ErrTaskStalled.
+func (r *taskRunner) watchHeartbeat(ctx context.Context) (context.Context, func()) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	m := &heartbeatMonitor{timeout: r.task.HeartbeatTimeout, beats: make(chan struct{}, 1)}
+	runCtx = context.WithValue(runCtx, heartbeatKey{}, m)
+
+	watchCtx, stop := context.WithCancel(runCtx)
+	go m.watch(watchCtx, func() {
+		r.tracker.SetStalled(r.task.ID, true)
+		if r.task.StallPolicy != dag.StallPolicyMark {
+			cancel(ErrTaskStalled)
+		}
+	}, func() {
+		r.tracker.SetStalled(r.task.ID, false)
+	})
+
+	return runCtx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// call runs the task function for one attempt, starting at 1. An attempt that
+// stalls under an abandoning policy returns ErrTaskStalled without waiting
+// for the function, whose worker may have died and never return.
+func (r *taskRunner) call(ctx context.Context, attempt int) error {
+	if r.task.HeartbeatTimeout <= 0 || r.task.StallPolicy == dag.StallPolicyMark {
+		return r.run(ctx, attempt)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- r.run(ctx, attempt) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if isStalled(ctx) {
+			return ErrTaskStalled
+		}
+		return <-done
+	}
+}
+
+// isStalled reports whether ctx was cancelled because its attempt stalled.
+func isStalled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrTaskStalled)
+}
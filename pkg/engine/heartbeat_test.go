@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+)
+
+// heartbeatUntilDone calls Heartbeat every interval until ctx is done.
+func heartbeatUntilDone(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		Heartbeat(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func TestTaskRunner_StalledAttemptIsRetried(t *testing.T) {
+	tr := newStateTracker()
+	tr.InitTasks([]string{"t1"})
+	var stalls atomic.Int32
+	tr.SetOnStall(func(taskID string, result TaskResult) { stalls.Add(1) })
+
+	// The first attempt hangs like a task whose worker died; it is abandoned
+	// without waiting for it to return.
+	hung := make(chan struct{})
+	defer close(hung)
+	var attempts atomic.Int32
+	task := &dag.Task{ID: "t1", Name: "t1", Agent: "test", Retries: 1, HeartbeatTimeout: 50 * time.Millisecond}
+	runner := newTaskRunner(task, tr, func(ctx context.Context) error {
+		if attempts.Add(1) == 1 {
+			<-hung
+			return nil
+		}
+		beatCtx, stop := context.WithTimeout(ctx, 150*time.Millisecond)
+		defer stop()
+		heartbeatUntilDone(beatCtx, 10*time.Millisecond)
+		return nil
+	})
+
+	if err := runner.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts.Load())
+	}
+	r, _ := tr.GetResult("t1")
+	if r.State != TaskStateCompleted || r.Stalls != 1 || r.Stalled {
+		t.Fatalf("expected completed task with one stall, got %+v", r)
+	}
+	if stalls.Load() != 1 {
+		t.Fatalf("expected one stall callback, got %d", stalls.Load())
+	}
+}
+
+func TestTaskRunner_StallPolicyFail(t *testing.T) {
+	tr := newStateTracker()
+	tr.InitTasks([]string{"t1"})
+
+	var attempts atomic.Int32
+	task := &dag.Task{
+		ID:               "t1",
+		Name:             "t1",
+		Agent:            "test",
+		Retries:          2,
+		HeartbeatTimeout: 30 * time.Millisecond,
+		StallPolicy:      dag.StallPolicyFail,
+	}
+	runner := newTaskRunner(task, tr, func(ctx context.Context) error {
+		attempts.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := runner.Execute(context.Background())
+	if !errors.Is(err, ErrTaskStalled) {
+		t.Fatalf("expected ErrTaskStalled, got %v", err)
+	}
+	if attempts.Load() != 1 {
+		t.Fatalf("expected no retries, got %d attempts", attempts.Load())
+	}
+	r, _ := tr.GetResult("t1")
+	if r.State != TaskStateFailed || r.Stalls != 1 {
+		t.Fatalf("expected failed task with one stall, got %+v", r)
+	}
+}
+
+func TestTaskRunner_StallPolicyMark(t *testing.T) {
+	tr := newStateTracker()
+	tr.InitTasks([]string{"t1"})
+
+	task := &dag.Task{
+		ID:               "t1",
+		Name:             "t1",
+		Agent:            "test",
+		HeartbeatTimeout: 30 * time.Millisecond,
+		StallPolicy:      dag.StallPolicyMark,
+	}
+	runner := newTaskRunner(task, tr, func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		if r, _ := tr.GetResult("t1"); !r.Stalled {
+			return errors.New("expected task to be marked stalled")
+		}
+		Heartbeat(ctx)
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if r, _ := tr.GetResult("t1"); !r.Stalled {
+				return ctx.Err()
+			}
+		}
+		return errors.New("expected heartbeat to clear the stall")
+	})
+
+	if err := runner.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, _ := tr.GetResult("t1")
+	if r.State != TaskStateCompleted || r.Stalls != 1 {
+		t.Fatalf("expected completed task with one stall, got %+v", r)
+	}
+}
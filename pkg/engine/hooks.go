@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+)
+
+// Hook is a function the engine calls at a point of its lifecycle, so that
+// embedders can attach warmup and teardown logic such as registering
+// executors or seeding lanes. Hooks of the same point run in the order they
+// were registered.
+type Hook func(ctx context.Context, e *Engine) error
+
+// lifecycleHooks holds the hooks registered with the With*Hook options.
+type lifecycleHooks struct {
+	onStart            []Hook
+	onRecoveryComplete []Hook
+	onDrain            []Hook
+	onStop             []Hook
+}
+
+// WithOnStart registers a hook that runs during Start once storage, lanes and
+// the scheduler are up, before the engine accepts workflows and recovers
+// stored ones. Lanes can be created with PutLane and executors registered
+// with RegisterTaskExecutor. An error fails Start.
+func WithOnStart(hook Hook) Option {
+	return func(e *Engine) {
+		if hook != nil {
+			e.hooks.onStart = append(e.hooks.onStart, hook)
+		}
+	}
+}
+
+// WithOnRecoveryComplete registers a hook that runs at the end of Start, once
+// workflow and saga recovery have finished, even if they reported errors.
+// Errors are logged.
+func WithOnRecoveryComplete(hook Hook) Option {
+	return func(e *Engine) {
+		if hook != nil {
+			e.hooks.onRecoveryComplete = append(e.hooks.onRecoveryComplete, hook)
+		}
+	}
+}
+
+// WithOnDrain registers a hook that runs when Drain first marks the engine
+// not ready, before it waits for running workflows. Errors are logged.
+func WithOnDrain(hook Hook) Option {
+	return func(e *Engine) {
+		if hook != nil {
+			e.hooks.onDrain = append(e.hooks.onDrain, hook)
+		}
+	}
+}
+
+// WithOnStop registers a hook that runs when Stop begins, while lanes and the
+// memory hub are still up. Errors are logged.
+func WithOnStop(hook Hook) Option {
+	return func(e *Engine) {
+		if hook != nil {
+			e.hooks.onStop = append(e.hooks.onStop, hook)
+		}
+	}
+}
+
+// runHooks runs hooks in order and returns the first error, naming the
+// lifecycle point.
+func (e *Engine) runHooks(ctx context.Context, point string, hooks []Hook) error {
+	for i, hook := range hooks {
+		if err := hook(ctx, e); err != nil {
+			return fmt.Errorf("%s hook %d: %w", point, i, err)
+		}
+	}
+	return nil
+}
+
+// runHooksLogged runs every hook of a point whose errors do not stop the
+// engine, logging each error.
+func (e *Engine) runHooksLogged(ctx context.Context, point string, hooks []Hook) {
+	for i, hook := range hooks {
+		if err := hook(ctx, e); err != nil {
+			e.logger.Warn("lifecycle hook failed", "hook", point, "index", i, "error", err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_LifecycleHooks(t *testing.T) {
+	var calls []string
+	record := func(point string) Hook {
+		return func(ctx context.Context, e *Engine) error {
+			calls = append(calls, point)
+			return nil
+		}
+	}
+	warmup := func(ctx context.Context, e *Engine) error {
+		if err := e.PutLane("warm", 10, 2, 0); err != nil {
+			return err
+		}
+		return e.RegisterTaskExecutor(dag.AgentContainer, echoExecutor{})
+	}
+
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(),
+		WithOnStart(record("start")),
+		WithOnStart(warmup),
+		WithOnRecoveryComplete(record("recovery_complete")),
+		WithOnDrain(record("drain")),
+		WithOnStop(record("stop")),
+	)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+
+	if _, err := eng.LaneStatsByName("warm"); err != nil {
+		t.Fatalf("expected lane created by the start hook: %v", err)
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "build",
+		Tasks: []models.TaskDefinition{
+			{ID: "c", Name: "c", Type: "container", Container: &models.ContainerSpec{Image: "alpine"}},
+		},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeSync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s, want %s", resp.Status, workflowStatusCompleted)
+	}
+	if err := eng.RegisterTaskExecutor("shell", echoExecutor{}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("RegisterTaskExecutor() on a running engine error = %v, want Conflict", err)
+	}
+
+	eng.Drain(ctx)
+	eng.Drain(ctx)
+	if err := eng.Stop(ctx); err != nil {
+		t.Fatalf("failed to stop engine: %v", err)
+	}
+
+	want := []string{"start", "recovery_complete", "drain", "stop"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("hook calls = %v, want %v", calls, want)
+	}
+}
+
+func TestEngine_StartHookErrorFailsStart(t *testing.T) {
+	boom := errors.New("boom")
+	recovered := false
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(),
+		WithOnStart(func(context.Context, *Engine) error { return boom }),
+		WithOnRecoveryComplete(func(context.Context, *Engine) error {
+			recovered = true
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("Start() error = %v, want %v", err, boom)
+	}
+	if eng.State() != "error" {
+		t.Fatalf("engine state = %s, want error", eng.State())
+	}
+	if recovered {
+		t.Fatal("recovery hook ran after a failed start")
+	}
+}
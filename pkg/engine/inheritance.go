@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// priorityInversionRecorder is implemented by metrics recorders that count
+// priority inversions. It is optional so that MetricsRecorder stays unchanged.
+type priorityInversionRecorder interface {
+	RecordPriorityInversion(laneName string, inherited int)
+	RecordPriorityInversionWait(laneName string, wait time.Duration)
+}
+
+// laneWaiter is a task, or task group, queued in a lane that has not
+// started yet.
+type laneWaiter struct {
+	lane     string
+	taskID   string
+	priority int
+	queuedAt time.Time
+
+	// The fields below are guarded by priorityInheritance.mu.
+
+	// inherited is the highest priority of the tasks queued behind this one
+	// that it holds back; zero when it inherited none.
+	inherited int
+	// inverted is set when lower-priority tasks were queued ahead of this
+	// one while the lane was saturated.
+	inverted bool
+	started  bool
+}
+
+// effective returns the priority the waiter is scheduled with.
+func (w *laneWaiter) effective() int {
+	return max(w.priority, w.inherited)
+}
+
+// priorityInheritance tracks the tasks queued in each lane in submission
+// order. A task queued behind lower-priority tasks of other workflows lends
+// them its priority, so that the engine reclaims workers for them as it would
+// for the task itself instead of leaving it stuck behind them.
+type priorityInheritance struct {
+	metrics MetricsRecorder
+
+	mu     sync.Mutex
+	queued map[string][]*laneWaiter
+}
+
+func newPriorityInheritance(metrics MetricsRecorder) *priorityInheritance {
+	return &priorityInheritance{
+		metrics: metrics,
+		queued:  make(map[string][]*laneWaiter),
+	}
+}
+
+// enqueue records a task queued in laneName and returns its waiter, which
+// must be passed to start once the task starts or is not queued after all.
+func (p *priorityInheritance) enqueue(laneName, taskID string, priority int) *laneWaiter {
+	if p == nil {
+		return nil
+	}
+	w := &laneWaiter{lane: laneName, taskID: taskID, priority: priority, queuedAt: time.Now()}
+	p.mu.Lock()
+	p.queued[laneName] = append(p.queued[laneName], w)
+	p.mu.Unlock()
+	return w
+}
+
+// start removes w from its lane and returns the priority it inherited while
+// queued, zero when none. For a waiter that was queued behind lower-priority
+// tasks it records how long it waited. Calling start again only returns the
+// inherited priority.
+func (p *priorityInheritance) start(w *laneWaiter) int {
+	if p == nil || w == nil {
+		return 0
+	}
+	p.mu.Lock()
+	inherited := w.inherited
+	if w.started {
+		p.mu.Unlock()
+		return inherited
+	}
+	w.started = true
+	queue := p.queued[w.lane]
+	for i, queued := range queue {
+		if queued == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) == 0 {
+		delete(p.queued, w.lane)
+	} else {
+		p.queued[w.lane] = queue
+	}
+	inverted := w.inverted
+	p.mu.Unlock()
+
+	if inverted {
+		if recorder, ok := p.metrics.(priorityInversionRecorder); ok {
+			recorder.RecordPriorityInversionWait(w.lane, time.Since(w.queuedAt))
+		}
+	}
+	return inherited
+}
+
+// inherit lends the effective priority of w to every task queued ahead of it
+// in its lane with a lower effective priority, and returns how many tasks
+// inherited it.
+func (p *priorityInheritance) inherit(w *laneWaiter) int {
+	if p == nil || w == nil {
+		return 0
+	}
+	p.mu.Lock()
+	if w.started {
+		p.mu.Unlock()
+		return 0
+	}
+	priority := w.effective()
+	inherited := 0
+	for _, queued := range p.queued[w.lane] {
+		if queued == w {
+			break
+		}
+		if queued.effective() < priority {
+			queued.inherited = priority
+			inherited++
+		}
+	}
+	if inherited > 0 {
+		w.inverted = true
+	}
+	p.mu.Unlock()
+
+	if inherited > 0 {
+		if recorder, ok := p.metrics.(priorityInversionRecorder); ok {
+			recorder.RecordPriorityInversion(w.lane, inherited)
+		}
+	}
+	return inherited
+}
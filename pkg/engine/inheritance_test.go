@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+type inversionMetrics struct {
+	preemptionMetrics
+	inversions []int
+	waits      []time.Duration
+}
+
+func (m *inversionMetrics) RecordPriorityInversion(laneName string, inherited int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inversions = append(m.inversions, inherited)
+}
+
+func (m *inversionMetrics) RecordPriorityInversionWait(laneName string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waits = append(m.waits, wait)
+}
+
+func TestPriorityInheritance_LendsToTasksQueuedAhead(t *testing.T) {
+	metrics := &inversionMetrics{}
+	p := newPriorityInheritance(metrics)
+
+	low := p.enqueue("default", "low", 1)
+	mid := p.enqueue("default", "mid", 3)
+	other := p.enqueue("io", "other", 0)
+	high := p.enqueue("default", "high", 5)
+
+	if got := p.inherit(mid); got != 1 {
+		t.Fatalf("inherit(mid) = %d, want 1", got)
+	}
+	if got := p.inherit(high); got != 2 {
+		t.Fatalf("inherit(high) = %d, want 2", got)
+	}
+	if got := p.inherit(high); got != 0 {
+		t.Fatalf("expected nothing left to inherit, got %d", got)
+	}
+
+	if got := p.start(low); got != 5 {
+		t.Fatalf("start(low) = %d, want 5", got)
+	}
+	if got := p.start(other); got != 0 {
+		t.Fatalf("start(other) = %d, want 0", got)
+	}
+	if got := p.start(high); got != 0 {
+		t.Fatalf("start(high) = %d, want 0", got)
+	}
+	if got := p.inherit(high); got != 0 {
+		t.Fatalf("expected a started task not to lend its priority, got %d", got)
+	}
+	p.start(mid)
+	if len(p.queued) != 0 {
+		t.Fatalf("expected all lanes to be empty, got %v", p.queued)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.inversions) != 2 || metrics.inversions[1] != 2 {
+		t.Fatalf("unexpected inversion metrics: %v", metrics.inversions)
+	}
+	if len(metrics.waits) != 2 {
+		t.Fatalf("expected a wait for both inverted tasks, got %v", metrics.waits)
+	}
+}
+
+func TestSubmitWorkflowRuntime_InheritsPriorityAcrossWorkflows(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 2
+	store := memory.NewMemoryStorage()
+	metrics := &inversionMetrics{}
+
+	eng, err := New(cfg, nil, store, WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	// A medium-priority workflow holds both workers until it is preempted.
+	var attempts sync.Map
+	started := make(chan struct{}, 4)
+	hold := func(id string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			started <- struct{}{}
+			n, _ := attempts.LoadOrStore(id, new(atomic.Int32))
+			if n.(*atomic.Int32).Add(1) == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		}
+	}
+	mid, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:     "reindex",
+		Priority: 3,
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function", Preemptible: true},
+			{ID: "b", Name: "b", Type: "function", Preemptible: true},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{"a": hold("a"), "b": hold("b")},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("medium-priority tasks did not start")
+		}
+	}
+
+	// A low-priority task queues ahead of the high-priority one.
+	low, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "cleanup",
+		Tasks: []models.TaskDefinition{{ID: "sweep", Name: "sweep", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{"sweep": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	l, err := eng.laneManager.GetLane(defaultLaneName)
+	if err != nil {
+		t.Fatalf("GetLane() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Stats().Pending == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("low-priority task was not queued")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Without inheritance only one medium task would be preempted, and its
+	// worker would go to the low-priority task queued ahead.
+	high, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:     "incident",
+		Priority: 5,
+		Tasks:    []models.TaskDefinition{{ID: "page", Name: "page", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{"page": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if err := waitWorkflowStatus(eng, high.ID, workflowStatusCompleted, 2*time.Second); err != nil {
+		t.Fatalf("high-priority workflow did not complete: %v", err)
+	}
+
+	for _, id := range []string{low.ID, mid.ID} {
+		if err := waitWorkflowStatus(eng, id, workflowStatusCompleted, 2*time.Second); err != nil {
+			t.Fatalf("workflow %s did not complete: %v", id, err)
+		}
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.lanes) != 2 {
+		t.Fatalf("expected both medium tasks to be preempted, got %v", metrics.lanes)
+	}
+	if len(metrics.inversions) != 1 || metrics.inversions[0] != 1 {
+		t.Fatalf("unexpected inversion metrics: %v", metrics.inversions)
+	}
+	if len(metrics.waits) != 1 {
+		t.Fatalf("expected the inverted task's wait to be recorded, got %v", metrics.waits)
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/insights"
+)
+
+// anomalyBroadcaster is implemented by event broadcasters that publish
+// duration anomalies. It is optional so that EventBroadcaster stays
+// unchanged.
+type anomalyBroadcaster interface {
+	BroadcastDurationAnomaly(workflowID, workflowName, taskID string, duration, mean time.Duration, zScore, percentile float64, reason string)
+}
+
+// Insights returns the duration anomaly detector, or nil when insights are
+// disabled.
+func (e *Engine) Insights() *insights.Detector {
+	return e.insights
+}
+
+// observeDuration learns the duration of a completed workflow run, or of its
+// task when taskID is set, and publishes the anomaly if it was unusually
+// slow.
+func (e *Engine) observeDuration(workflowID, workflowName, taskID string, duration time.Duration, now time.Time) {
+	if e.insights == nil {
+		return
+	}
+	anomaly := e.insights.Observe(workflowID, workflowName, taskID, duration, now)
+	if anomaly == nil {
+		return
+	}
+	e.logger.Warn("unusually slow run",
+		"workflow_id", workflowID,
+		"name", workflowName,
+		"task_id", taskID,
+		"duration", duration,
+		"mean", anomaly.Mean,
+		"reason", anomaly.Reason,
+	)
+	if broadcaster, ok := e.events.(anomalyBroadcaster); ok {
+		broadcaster.BroadcastDurationAnomaly(workflowID, workflowName, taskID, duration, anomaly.Mean, anomaly.ZScore, anomaly.Percentile, anomaly.Reason)
+	}
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/lane"
+)
+
+// PutLane creates the memory lane name, or updates the concurrency and rate
+// limit of an existing one. The capacity of an existing lane cannot change,
+// and the default lane is configured by the orchestration settings only.
+func (e *Engine) PutLane(name string, capacity, maxConcurrency int, rateLimit float64) error {
+	if name == defaultLaneName {
+		return errs.Newf(errs.BadRequest, "lane %s is configured by the orchestration settings", name)
+	}
+	if e.laneManager == nil {
+		return errs.New(errs.ServiceUnavailable, "engine is not started")
+	}
+
+	existing, err := e.laneManager.GetLane(name)
+	if err != nil {
+		l, err := e.laneManager.Register(&lane.Config{
+			Name:           name,
+			Capacity:       capacity,
+			MaxConcurrency: maxConcurrency,
+			Backpressure:   lane.Block,
+			RateLimit:      rateLimit,
+		})
+		if err != nil {
+			return errs.Newf(errs.BadRequest, "lane %s: %v", name, err)
+		}
+		if metricsLane, ok := l.(interface{ SetMetrics(lane.MetricsRecorder) }); ok {
+			metricsLane.SetMetrics(e.metrics)
+		}
+		e.logger.Info("lane created", "lane", name, "capacity", capacity, "max_concurrency", maxConcurrency)
+		return nil
+	}
+
+	if stats := existing.Stats(); stats.Capacity != capacity {
+		return errs.Newf(errs.Conflict, "capacity of lane %s is %d and cannot change; delete and recreate the lane", name, stats.Capacity)
+	}
+	resizable, ok := existing.(interface{ SetMaxConcurrency(int) error })
+	if !ok {
+		return errs.Newf(errs.Conflict, "lane %s does not support runtime concurrency changes", name)
+	}
+	if err := resizable.SetMaxConcurrency(maxConcurrency); err != nil {
+		return errs.Newf(errs.BadRequest, "lane %s: %v", name, err)
+	}
+	limited, ok := existing.(interface{ SetRateLimit(float64) error })
+	if !ok {
+		return errs.Newf(errs.Conflict, "lane %s does not support runtime rate limit changes", name)
+	}
+	if err := limited.SetRateLimit(rateLimit); err != nil {
+		return errs.Newf(errs.BadRequest, "lane %s: %v", name, err)
+	}
+	e.logger.Info("lane updated", "lane", name, "max_concurrency", maxConcurrency, "rate_limit", rateLimit)
+	return nil
+}
+
+// DeleteLane closes and removes the lane name. Tasks of running workflows
+// that still target it fail. The default lane cannot be deleted.
+func (e *Engine) DeleteLane(ctx context.Context, name string) error {
+	if name == defaultLaneName {
+		return errs.Newf(errs.BadRequest, "lane %s cannot be deleted", name)
+	}
+	if e.laneManager == nil {
+		return errs.New(errs.ServiceUnavailable, "engine is not started")
+	}
+	if err := e.laneManager.Unregister(ctx, name); err != nil {
+		return fmt.Errorf("delete lane %s: %w", name, err)
+	}
+	e.logger.Info("lane deleted", "lane", name)
+	return nil
+}
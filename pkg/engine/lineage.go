@@ -0,0 +1,217 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/manage"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+const (
+	// MaxTaskInputs bounds the inputs recorded per task.
+	MaxTaskInputs = 100
+
+	// MaxLineageDepth bounds how many workflows upstream lineage graphs
+	// trace.
+	MaxLineageDepth     = 10
+	defaultLineageDepth = 3
+)
+
+// lineageKey is the context key of the running task's taskLineage.
+type lineageKey struct{}
+
+// RecordInput records that the task running with ctx read the output of task
+// taskID of the workflow run workflowID, or of its own run when workflowID is
+// empty; artifact names the artifact read, if any. Artifacts the task
+// downloads with artifact.Download are recorded without it. Inputs are
+// persisted with the task's next state change and make up lineage graphs.
+func RecordInput(ctx context.Context, workflowID, taskID, artifact string) error {
+	l, ok := ctx.Value(lineageKey{}).(*taskLineage)
+	if !ok {
+		return errs.New(errs.BadRequest, "lineage is only available to tasks run by the engine")
+	}
+	return l.record(storage.TaskInput{WorkflowID: workflowID, TaskID: taskID, Artifact: artifact})
+}
+
+// workflowLineage records the inputs of the tasks of one running workflow in
+// their task states, which exec.mu guards.
+type workflowLineage struct {
+	exec *workflowExecution
+}
+
+// withTask returns ctx carrying the lineage of taskID for RecordInput, with
+// the artifacts the task downloads recorded as its inputs.
+func (l *workflowLineage) withTask(ctx context.Context, taskID string) context.Context {
+	t := &taskLineage{exec: l.exec, taskID: taskID}
+	ctx = context.WithValue(ctx, lineageKey{}, t)
+	return artifact.WithDownloadHook(ctx, t.recordArtifact)
+}
+
+// taskLineage records the inputs of one task.
+type taskLineage struct {
+	exec   *workflowExecution
+	taskID string
+}
+
+func (t *taskLineage) recordArtifact(a *artifact.Artifact) {
+	_ = t.record(storage.TaskInput{WorkflowID: a.WorkflowID, TaskID: a.TaskID, Artifact: a.Name})
+}
+
+func (t *taskLineage) record(input storage.TaskInput) error {
+	if input.TaskID == "" {
+		return errs.New(errs.BadRequest, "input task ID is required")
+	}
+	if input.WorkflowID == "" {
+		input.WorkflowID = t.exec.workflowID
+	}
+	if input.WorkflowID == t.exec.workflowID && input.TaskID == t.taskID {
+		return nil
+	}
+	t.exec.mu.Lock()
+	defer t.exec.mu.Unlock()
+
+	taskState, ok := t.exec.wfState.TaskStatus[t.taskID]
+	if !ok || slices.Contains(taskState.Inputs, input) {
+		return nil
+	}
+	if len(taskState.Inputs) >= MaxTaskInputs {
+		return errs.Newf(errs.BadRequest, "task %s already has %d inputs", t.taskID, MaxTaskInputs)
+	}
+	// Stored copies of the task state share the slice; never write past
+	// their length.
+	taskState.Inputs = append(slices.Clip(taskState.Inputs), input)
+	return nil
+}
+
+// WorkflowLineage returns the lineage graph of the workflow run id: its tasks
+// and, traced upstream through the inputs of tasks and the runs that
+// triggered workflows, the runs and tasks whose outputs they read, up to depth
+// workflows away. Zero depth means 3.
+func (e *Engine) WorkflowLineage(ctx context.Context, id string, depth int) (*models.LineageGraph, error) {
+	if depth <= 0 {
+		depth = defaultLineageDepth
+	}
+	depth = min(depth, MaxLineageDepth)
+	root, err := e.storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &lineageBuilder{nodes: make(map[string]*models.LineageNode), seen: map[string]bool{id: true}}
+	queue := []lineageVisit{{wf: root}}
+	for len(queue) > 0 {
+		visit := queue[0]
+		queue = queue[1:]
+		for _, upstream := range b.addWorkflow(visit.wf, visit.depth, visit.depth < depth) {
+			wf, err := e.storage.GetWorkflow(ctx, upstream)
+			if errs.Is(err, errs.NotFound) {
+				// The run was deleted; it stays a bare node.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			queue = append(queue, lineageVisit{wf: wf, depth: visit.depth + 1})
+		}
+	}
+
+	graph := &models.LineageGraph{WorkflowID: id, Nodes: make([]models.LineageNode, 0, len(b.nodes)), Edges: b.edges}
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		a, b := graph.Nodes[i], graph.Nodes[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		return a.ID < b.ID
+	})
+	if graph.Edges == nil {
+		graph.Edges = []models.LineageEdge{}
+	}
+	return graph, nil
+}
+
+type lineageVisit struct {
+	wf    *storage.WorkflowState
+	depth int
+}
+
+// lineageBuilder collects the nodes and edges of a lineage graph.
+type lineageBuilder struct {
+	nodes map[string]*models.LineageNode
+	edges []models.LineageEdge
+	// seen holds the workflows visited or queued.
+	seen map[string]bool
+}
+
+// addWorkflow adds the workflow wf at depth with its tasks and the edges
+// into them. Edges from other workflows are added when upstream is set; the
+// workflows they lead to that are not yet visited are returned.
+func (b *lineageBuilder) addWorkflow(wf *storage.WorkflowState, depth int, upstream bool) []string {
+	var next []string
+	visit := func(workflowID string) {
+		if !b.seen[workflowID] {
+			b.seen[workflowID] = true
+			next = append(next, workflowID)
+		}
+	}
+
+	wfNode := b.node(wf.ID, "", depth)
+	wfNode.Workflow, wfNode.Status = wf.Name, wf.Status
+	definitions := taskDefinitions(wf)
+	for _, taskID := range sortedTaskIDs(wf) {
+		taskState := wf.TaskStatus[taskID]
+		node := b.node(wf.ID, taskID, depth)
+		node.Workflow, node.Status = wf.Name, taskState.Status
+		for _, dep := range definitions[taskID].DependsOn {
+			b.edge(b.node(wf.ID, dep, depth), node, models.LineageDependency, "")
+		}
+		for _, input := range taskState.Inputs {
+			fromDepth := depth
+			if input.WorkflowID != wf.ID {
+				if !upstream {
+					continue
+				}
+				fromDepth = depth + 1
+				visit(input.WorkflowID)
+			}
+			b.edge(b.node(input.WorkflowID, input.TaskID, fromDepth), node, models.LineageInput, input.Artifact)
+		}
+	}
+	if upstream {
+		for _, from := range strings.Split(wf.Metadata[manage.MetadataTriggeredBy], ",") {
+			if from == "" {
+				continue
+			}
+			visit(from)
+			b.edge(b.node(from, "", depth+1), wfNode, models.LineageTrigger, "")
+		}
+	}
+	return next
+}
+
+// node returns the node of the task taskID of workflowID, or of the workflow
+// when taskID is empty, adding it at depth if it is new.
+func (b *lineageBuilder) node(workflowID, taskID string, depth int) *models.LineageNode {
+	id := workflowID
+	if taskID != "" {
+		id += "/" + taskID
+	}
+	node, ok := b.nodes[id]
+	if !ok {
+		node = &models.LineageNode{ID: id, WorkflowID: workflowID, TaskID: taskID, Depth: depth}
+		b.nodes[id] = node
+	}
+	return node
+}
+
+func (b *lineageBuilder) edge(from, to *models.LineageNode, kind, artifact string) {
+	b.edges = append(b.edges, models.LineageEdge{From: from.ID, To: to.ID, Kind: kind, Artifact: artifact})
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/artifact"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/manage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestWorkflowLineage_TracesInputsAndTriggers(t *testing.T) {
+	backend, err := artifact.NewLocalBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBackend() error = %v", err)
+	}
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithArtifactStore(artifact.NewStore(backend)))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	submit := func(req *models.WorkflowRequest, fns map[string]func(context.Context) error) string {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeSync, TaskFns: fns})
+		if err != nil || resp.Status != workflowStatusCompleted {
+			t.Fatalf("SubmitWorkflowRuntime(%s) = %+v, %v", req.Name, resp, err)
+		}
+		return resp.ID
+	}
+
+	etl := submit(&models.WorkflowRequest{
+		Name: "etl",
+		Tasks: []models.TaskDefinition{
+			{ID: "extract", Name: "extract", Type: "function"},
+			{ID: "load", Name: "load", Type: "function", DependsOn: []string{"extract"}},
+		},
+	}, map[string]func(context.Context) error{
+		"extract": func(ctx context.Context) error {
+			_, err := artifact.Upload(ctx, "rows.csv", "text/csv", strings.NewReader("a,b"))
+			return err
+		},
+		"load": func(ctx context.Context) error {
+			_, rc, err := artifact.Download(ctx, "extract", "rows.csv")
+			if err != nil {
+				return err
+			}
+			return rc.Close()
+		},
+	})
+	report := submit(&models.WorkflowRequest{
+		Name:  "report",
+		Tasks: []models.TaskDefinition{{ID: "render", Name: "render", Type: "function"}},
+	}, map[string]func(context.Context) error{
+		"render": func(ctx context.Context) error {
+			if err := RecordInput(ctx, "", "", ""); !errs.Is(err, errs.BadRequest) {
+				t.Errorf("RecordInput() without a task error = %v, want BadRequest", err)
+			}
+			return RecordInput(ctx, etl, "load", "")
+		},
+	})
+	publish := submit(&models.WorkflowRequest{
+		Name:     "publish",
+		Metadata: map[string]string{manage.MetadataTriggeredBy: report},
+		Tasks:    []models.TaskDefinition{{ID: "upload", Name: "upload", Type: "function"}},
+	}, map[string]func(context.Context) error{"upload": func(context.Context) error { return nil }})
+
+	tasks, _, err := eng.ListWorkflowTasksResponse(ctx, etl, models.TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkflowTasksResponse() error = %v", err)
+	}
+	if inputs := tasks[1].Inputs; len(inputs) != 1 || inputs[0] != (models.TaskInput{WorkflowID: etl, TaskID: "extract", Artifact: "rows.csv"}) {
+		t.Fatalf("load inputs = %+v, want the downloaded artifact", inputs)
+	}
+
+	graph, err := eng.WorkflowLineage(ctx, publish, 0)
+	if err != nil {
+		t.Fatalf("WorkflowLineage() error = %v", err)
+	}
+	edges := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		edges[edge.Kind+" "+edge.From+" -> "+edge.To+" "+edge.Artifact] = true
+	}
+	for _, want := range []string{
+		"trigger " + report + " -> " + publish + " ",
+		"input " + etl + "/load -> " + report + "/render ",
+		"input " + etl + "/extract -> " + etl + "/load rows.csv",
+		"dependency " + etl + "/extract -> " + etl + "/load ",
+	} {
+		if !edges[want] {
+			t.Errorf("missing edge %q in %v", want, graph.Edges)
+		}
+	}
+	if len(graph.Nodes) != 7 || graph.Nodes[0].ID != publish || graph.Nodes[len(graph.Nodes)-1].Depth != 2 {
+		t.Fatalf("nodes = %+v, want the three runs and their four tasks", graph.Nodes)
+	}
+
+	graph, err = eng.WorkflowLineage(ctx, publish, 1)
+	if err != nil {
+		t.Fatalf("WorkflowLineage() with depth 1 error = %v", err)
+	}
+	for _, node := range graph.Nodes {
+		if node.WorkflowID == etl {
+			t.Fatalf("depth 1 traced %s further than the report", node.ID)
+		}
+	}
+}
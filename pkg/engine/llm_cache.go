@@ -0,0 +1,20 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/llmcache"
+)
+
+// CachedLLMCall returns the response of the LLM call req, from the engine's
+// LLM cache when a call with the same content succeeded before, and otherwise
+// by making it with call. Usage the call records with RecordUsage is only
+// paid on a miss. Without a cache, or outside the engine, it makes the call.
+func CachedLLMCall(ctx context.Context, req llmcache.Request, call func(context.Context) ([]byte, error)) ([]byte, error) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok || v.engine.llmCache == nil {
+		return call(ctx)
+	}
+	value, _, err := v.engine.llmCache.Do(ctx, req, call)
+	return value, err
+}
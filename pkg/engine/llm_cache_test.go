@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/llmcache"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestCachedLLMCall_ReusesResponsesAcrossRuns(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Costs = config.CostsConfig{Enabled: true}
+	cache := llmcache.New(llmcache.NewMemoryStore(0), 0)
+	eng, err := New(cfg, nil, memory.NewMemoryStorage(), WithLLMCache(cache))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	calls := 0
+	run := func() *models.WorkflowStatusResponse {
+		t.Helper()
+		resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+			Name:  "summarize",
+			Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+		}, SubmitWorkflowOptions{
+			Mode: SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{
+				"a": func(ctx context.Context) error {
+					_, err := CachedLLMCall(ctx, llmcache.Request{Model: "small", Prompt: "Summarize the report."}, func(ctx context.Context) ([]byte, error) {
+						calls++
+						RecordUsage(ctx, UsageLLMTokens, 500)
+						return []byte("A summary."), nil
+					})
+					return err
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		return resp
+	}
+
+	first, second := run(), run()
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+	if first.Cost.Usage[UsageLLMTokens] != 500 || second.Cost.Usage[UsageLLMTokens] != 0 {
+		t.Fatalf("token usage = %v then %v, want 500 then none", first.Cost.Usage, second.Cost.Usage)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/rediskey"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLockTTL           = 30 * time.Second
+	defaultLockRetryInterval = 100 * time.Millisecond
+
+	// MaxLockNameBytes bounds the length of lock names.
+	MaxLockNameBytes = 200
+)
+
+// locksKey is the context key of the running task's taskLocks.
+type locksKey struct{}
+
+// AcquireLock blocks until the task running with ctx holds the lock name, or
+// ctx is done. The lock is held until ReleaseLock or until the task finishes,
+// and is renewed meanwhile; ttl, zero for orchestration.locks.ttl, only bounds
+// how long it outlives a node that dies while holding it. Acquiring a lock
+// the task already holds does nothing.
+func AcquireLock(ctx context.Context, name string, ttl time.Duration) error {
+	l, ok := ctx.Value(locksKey{}).(*taskLocks)
+	if !ok {
+		return errs.New(errs.BadRequest, "locks are only available to tasks run by the engine")
+	}
+	return l.acquire(ctx, name, ttl)
+}
+
+// TryAcquireLock is AcquireLock without waiting. It reports whether the task
+// running with ctx holds the lock name.
+func TryAcquireLock(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l, ok := ctx.Value(locksKey{}).(*taskLocks)
+	if !ok {
+		return false, errs.New(errs.BadRequest, "locks are only available to tasks run by the engine")
+	}
+	return l.tryAcquire(ctx, name, ttl)
+}
+
+// ReleaseLock releases the lock name held by the task running with ctx
+// before the task finishes. Releasing a lock the task does not hold does
+// nothing.
+func ReleaseLock(ctx context.Context, name string) error {
+	l, ok := ctx.Value(locksKey{}).(*taskLocks)
+	if !ok {
+		return errs.New(errs.BadRequest, "locks are only available to tasks run by the engine")
+	}
+	return l.release(ctx, name)
+}
+
+// lockBackend stores the owner of each lock. Owners are unique per task
+// execution, so a task taking a lock again only extends it.
+type lockBackend interface {
+	// tryLock takes name for owner until ttl from now if it is free or
+	// already owner's, and reports whether owner holds it.
+	tryLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// renew extends owner's hold on name until ttl from now, and reports
+	// whether owner still held it.
+	renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// unlock releases name if owner holds it.
+	unlock(ctx context.Context, name, owner string) error
+}
+
+// lockManager hands out the locks of task executions.
+type lockManager struct {
+	backend lockBackend
+	ttl     time.Duration
+	retry   time.Duration
+	logger  appLogger
+}
+
+// newLockManager returns the lock manager of cfg. Locks are kept in Redis
+// when client is set, under the lock keys of the namespace keyPrefix, so
+// that all nodes sharing it see them, and in memory otherwise.
+func newLockManager(cfg config.LocksConfig, client redis.UniversalClient, keyPrefix string, logger appLogger) *lockManager {
+	m := &lockManager{ttl: cfg.TTL, retry: cfg.RetryInterval, logger: logger}
+	if m.ttl <= 0 {
+		m.ttl = defaultLockTTL
+	}
+	if m.retry <= 0 {
+		m.retry = defaultLockRetryInterval
+	}
+	if client != nil {
+		m.backend = &redisLockBackend{client: client, prefix: rediskey.Prefix(keyPrefix, "lock")}
+	} else {
+		m.backend = newMemoryLockBackend()
+	}
+	return m
+}
+
+// forTask returns the locks of one execution of a task.
+func (m *lockManager) forTask(workflowID, taskID string) *taskLocks {
+	return &taskLocks{
+		manager:    m,
+		workflowID: workflowID,
+		taskID:     taskID,
+		owner:      workflowID + "/" + taskID + "/" + uuid.NewString(),
+		held:       make(map[string]context.CancelFunc),
+	}
+}
+
+// taskLocks are the locks held by one execution of a task. Each held lock
+// is renewed by its own goroutine until it is released.
+type taskLocks struct {
+	manager    *lockManager
+	workflowID string
+	taskID     string
+	owner      string
+
+	mu   sync.Mutex
+	held map[string]context.CancelFunc
+}
+
+// withTask returns ctx carrying the locks for AcquireLock and ReleaseLock.
+func (l *taskLocks) withTask(ctx context.Context) context.Context {
+	return context.WithValue(ctx, locksKey{}, l)
+}
+
+func (l *taskLocks) acquire(ctx context.Context, name string, ttl time.Duration) error {
+	ticker := time.NewTicker(l.manager.retry)
+	defer ticker.Stop()
+	for {
+		ok, err := l.tryAcquire(ctx, name, ttl)
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquireAll acquires names in sorted order, so that tasks sharing some of
+// their locks cannot deadlock.
+func (l *taskLocks) acquireAll(ctx context.Context, names []string) error {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		if err := l.acquire(ctx, name, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *taskLocks) tryAcquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	if name == "" {
+		return false, errs.New(errs.BadRequest, "lock name is required")
+	}
+	if len(name) > MaxLockNameBytes {
+		return false, errs.Newf(errs.BadRequest, "lock name exceeds %d bytes", MaxLockNameBytes)
+	}
+	if ttl <= 0 {
+		ttl = l.manager.ttl
+	}
+	l.mu.Lock()
+	_, held := l.held[name]
+	l.mu.Unlock()
+	if held {
+		return true, nil
+	}
+
+	ok, err := l.manager.backend.tryLock(ctx, name, l.owner, ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, held := l.held[name]; !held {
+		renewCtx, cancel := context.WithCancel(context.Background())
+		l.held[name] = cancel
+		go l.renew(renewCtx, name, ttl)
+	}
+	return true, nil
+}
+
+// renew extends the lock name every third of ttl until ctx is done.
+func (l *taskLocks) renew(ctx context.Context, name string, ttl time.Duration) {
+	ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ok, err := l.manager.backend.renew(ctx, name, l.owner, ttl)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			l.manager.logger.Warn("failed to renew task lock", "workflow_id", l.workflowID, "task_id", l.taskID, "lock", name, "error", err)
+			continue
+		}
+		if !ok {
+			l.manager.logger.Warn("task lock expired while held", "workflow_id", l.workflowID, "task_id", l.taskID, "lock", name)
+			return
+		}
+	}
+}
+
+func (l *taskLocks) release(ctx context.Context, name string) error {
+	l.mu.Lock()
+	cancel, held := l.held[name]
+	delete(l.held, name)
+	l.mu.Unlock()
+	if !held {
+		return nil
+	}
+	cancel()
+	return l.manager.backend.unlock(ctx, name, l.owner)
+}
+
+// releaseAll releases every lock still held once the task finishes.
+func (l *taskLocks) releaseAll() {
+	l.mu.Lock()
+	names := make([]string, 0, len(l.held))
+	for name := range l.held {
+		names = append(names, name)
+	}
+	l.mu.Unlock()
+	for _, name := range names {
+		if err := l.release(context.Background(), name); err != nil {
+			l.manager.logger.Warn("failed to release task lock", "workflow_id", l.workflowID, "task_id", l.taskID, "lock", name, "error", err)
+		}
+	}
+}
+
+// memoryLockBackend keeps locks in process memory.
+type memoryLockBackend struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+}
+
+type memoryLock struct {
+	owner   string
+	expires time.Time
+}
+
+func newMemoryLockBackend() *memoryLockBackend {
+	return &memoryLockBackend{locks: make(map[string]memoryLock)}
+}
+
+func (b *memoryLockBackend) tryLock(_ context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if lock, ok := b.locks[name]; ok && lock.owner != owner && now.Before(lock.expires) {
+		return false, nil
+	}
+	b.locks[name] = memoryLock{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (b *memoryLockBackend) renew(_ context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	lock, ok := b.locks[name]
+	if !ok || lock.owner != owner || !now.Before(lock.expires) {
+		return false, nil
+	}
+	b.locks[name] = memoryLock{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (b *memoryLockBackend) unlock(_ context.Context, name, owner string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lock, ok := b.locks[name]; ok && lock.owner == owner {
+		delete(b.locks, name)
+	}
+	return nil
+}
+
+var (
+	// redisTryLock sets KEYS[1] to owner ARGV[1] for ARGV[2] milliseconds
+	// unless another owner holds it.
+	redisTryLock = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and current ~= ARGV[1] then
+  return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1`)
+
+	// redisRenewLock extends KEYS[1] by ARGV[2] milliseconds if owner ARGV[1]
+	// holds it.
+	redisRenewLock = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0`)
+
+	// redisUnlock deletes KEYS[1] if owner ARGV[1] holds it.
+	redisUnlock = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`)
+)
+
+// redisLockBackend keeps locks in Redis, one key per lock holding its owner
+// and expiring with the lock.
+type redisLockBackend struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func (b *redisLockBackend) tryLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	n, err := redisTryLock.Run(ctx, b.client, []string{b.prefix + name}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, errs.Wrap(err, errs.ServiceUnavailable, "acquire lock "+name)
+	}
+	return n == 1, nil
+}
+
+func (b *redisLockBackend) renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	n, err := redisRenewLock.Run(ctx, b.client, []string{b.prefix + name}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, errs.Wrap(err, errs.ServiceUnavailable, "renew lock "+name)
+	}
+	return n == 1, nil
+}
+
+func (b *redisLockBackend) unlock(ctx context.Context, name, owner string) error {
+	if err := redisUnlock.Run(ctx, b.client, []string{b.prefix + name}, owner).Err(); err != nil {
+		return errs.Wrap(err, errs.ServiceUnavailable, "release lock "+name)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewLockManager_RedisKeysUseNamespace(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	m := newLockManager(config.LocksConfig{}, client, "staging:", &nopLogger{})
+	backend, ok := m.backend.(*redisLockBackend)
+	if !ok {
+		t.Fatalf("backend = %T, want *redisLockBackend", m.backend)
+	}
+	if backend.prefix != "staging:goclaw:lock:" {
+		t.Fatalf("lock key prefix = %q, want staging:goclaw:lock:", backend.prefix)
+	}
+}
+
+func TestTaskLocks_ExpireAndRelease(t *testing.T) {
+	m := newLockManager(config.LocksConfig{RetryInterval: time.Millisecond}, nil, "", &nopLogger{})
+	first := m.forTask("wf-1", "a")
+	second := m.forTask("wf-2", "b")
+	ctx := context.Background()
+
+	if ok, err := first.tryAcquire(ctx, "db", time.Hour); err != nil || !ok {
+		t.Fatalf("first tryAcquire() = %v, %v", ok, err)
+	}
+	if ok, _ := first.tryAcquire(ctx, "db", time.Hour); !ok {
+		t.Fatal("expected the holder to acquire its lock again")
+	}
+	if ok, _ := second.tryAcquire(ctx, "db", time.Hour); ok {
+		t.Fatal("expected a held lock not to be acquired by another task")
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := second.acquire(waitCtx, "db", 0); err != context.DeadlineExceeded {
+		t.Fatalf("acquire() error = %v, want deadline exceeded", err)
+	}
+
+	first.releaseAll()
+	if ok, _ := second.tryAcquire(ctx, "db", time.Hour); !ok {
+		t.Fatal("expected a released lock to be free")
+	}
+	second.releaseAll()
+
+	// A lock that is not renewed expires after its TTL.
+	if ok, _ := m.backend.tryLock(ctx, "cache", "gone", time.Millisecond); !ok {
+		t.Fatal("expected the lock to be free")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ok, _ := first.tryAcquire(ctx, "cache", time.Hour); !ok {
+		t.Fatal("expected an expired lock to be free")
+	}
+	first.releaseAll()
+
+	if _, err := first.tryAcquire(ctx, "", 0); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request for an empty name, got %v", err)
+	}
+}
+
+func TestSubmitWorkflowRuntime_TaskLocksSerializeWorkflows(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 4
+	cfg.Orchestration.Locks.RetryInterval = 5 * time.Millisecond
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var holders, overlaps atomic.Int32
+	critical := func(context.Context) error {
+		if holders.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		time.Sleep(20 * time.Millisecond)
+		holders.Add(-1)
+		return nil
+	}
+	acquiring := func(ctx context.Context) error {
+		if err := AcquireLock(ctx, "billing-db", time.Second); err != nil {
+			return err
+		}
+		if err := critical(ctx); err != nil {
+			return err
+		}
+		return ReleaseLock(ctx, "billing-db")
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		status, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+			Name: "migrate",
+			Tasks: []models.TaskDefinition{
+				{ID: "declared", Name: "declared", Type: "function", Locks: []string{"billing-db"}},
+				{ID: "acquired", Name: "acquired", Type: "function"},
+			},
+		}, SubmitWorkflowOptions{
+			Mode:    SubmissionModeAsync,
+			TaskFns: map[string]func(context.Context) error{"declared": critical, "acquired": acquiring},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		ids = append(ids, status.ID)
+	}
+	for _, id := range ids {
+		if err := waitWorkflowStatus(eng, id, workflowStatusCompleted, 5*time.Second); err != nil {
+			t.Fatalf("workflow %s did not complete: %v", id, err)
+		}
+	}
+	if n := overlaps.Load(); n != 0 {
+		t.Fatalf("tasks held the lock together %d times", n)
+	}
+
+	if err := AcquireLock(context.Background(), "billing-db", 0); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request outside the engine, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+)
+
+// TaskAttempt describes one attempt of a task to task middleware.
+type TaskAttempt struct {
+	WorkflowID string
+	// Task is the task's spec. Middleware must not modify it.
+	Task *dag.Task
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+}
+
+// TaskHandler runs one attempt of a task.
+type TaskHandler func(ctx context.Context, attempt TaskAttempt) error
+
+// TaskMiddleware wraps every attempt of the tasks the engine runs, so that
+// embedders can inject auth tokens, enrich traces or enforce policies
+// without forking the scheduler. It is called with the attempt's context,
+// which carries the task's environment, config, timeout and span
+// (trace.SpanFromContext). A middleware that returns an error without
+// calling next fails the attempt as if the task had returned it, so the
+// task is retried per its retry settings.
+type TaskMiddleware func(next TaskHandler) TaskHandler
+
+// WithTaskMiddleware adds middleware around task attempts. Middleware runs in
+// the order it was added, the first added outermost.
+func WithTaskMiddleware(middleware ...TaskMiddleware) Option {
+	return func(e *Engine) {
+		for _, mw := range middleware {
+			if mw != nil {
+				e.taskMiddleware = append(e.taskMiddleware, mw)
+			}
+		}
+	}
+}
+
+// run runs the task function for the given attempt, starting at 1, through
+// the runner's middleware.
+func (r *taskRunner) run(ctx context.Context, attempt int) error {
+	ctx = context.WithValue(ctx, attemptKey{}, attempt)
+	if len(r.middleware) == 0 {
+		return r.fn(ctx)
+	}
+	handler := TaskHandler(func(ctx context.Context, _ TaskAttempt) error { return r.fn(ctx) })
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler(ctx, TaskAttempt{WorkflowID: r.workflowID, Task: r.task, Attempt: attempt})
+}
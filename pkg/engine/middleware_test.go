@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+type tokenKey struct{}
+
+func TestEngine_TaskMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	errDenied := errors.New("denied by policy")
+
+	trace := func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, attempt TaskAttempt) error {
+			record("trace " + attempt.Task.ID)
+			return next(ctx, attempt)
+		}
+	}
+	auth := func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, attempt TaskAttempt) error {
+			if attempt.WorkflowID == "" {
+				t.Error("middleware called without a workflow id")
+			}
+			if attempt.Task.ID == "denied" && attempt.Attempt == 1 {
+				return errDenied
+			}
+			return next(context.WithValue(ctx, tokenKey{}, "token"), attempt)
+		}
+	}
+
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithTaskMiddleware(trace, nil, auth))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer func() { _ = eng.Stop(ctx) }()
+
+	task := func(id string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if ctx.Value(tokenKey{}) != "token" {
+				return errors.New("missing token")
+			}
+			record("run " + id)
+			return nil
+		}
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "middleware",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function"},
+			{ID: "denied", Name: "denied", Type: "function", DependsOn: []string{"a"}, Retries: 1},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": task("a"), "denied": task("denied")},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s, want %s", resp.Status, workflowStatusCompleted)
+	}
+
+	// The denied first attempt of "denied" is retried, and its second runs.
+	want := []string{"trace a", "run a", "trace denied", "trace denied", "run denied"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// ModelTarget is a provider and a model of it an LLM call is made with.
+type ModelTarget struct {
+	Provider string
+	Model    string
+}
+
+// attemptKey is the context key of the attempt number of the running task,
+// starting at 1.
+type attemptKey struct{}
+
+// modelRouteRecorder is implemented by metrics recorders that count the
+// calls made through model routes.
+type modelRouteRecorder interface {
+	RecordModelRouteCall(route, provider, model, result string, duration time.Duration)
+}
+
+// RouteLLMCall makes an LLM call of the task running with ctx through the
+// route name of orchestration.model_routes, so that tasks do not hardcode
+// providers. call is made with the route's primary target, or its retry
+// target when the task is retried, and then with each fallback in turn while
+// it fails: with any error but cancellation, or only with errs.RateLimited
+// errors when the route falls back on rate_limit. It returns the first
+// response and the target that gave it, or the last error. The target is
+// recorded in the run's journal, and a replay of the attempt calls it
+// directly.
+func RouteLLMCall(ctx context.Context, name string, call func(context.Context, ModelTarget) ([]byte, error)) ([]byte, ModelTarget, error) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return nil, ModelTarget{}, errs.New(errs.BadRequest, "model routes are only available to tasks run by the engine")
+	}
+	route, ok := v.engine.cfg.Orchestration.ModelRoutes[name]
+	if !ok {
+		return nil, ModelTarget{}, errs.Newf(errs.NotFound, "model route %s not found", name)
+	}
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+
+	kind := decisionModelRoute + name
+	targets := routeTargets(route, attempt > 1)
+	if data, ok := replayedDecision(ctx, kind); ok {
+		var recorded ModelTarget
+		if err := json.Unmarshal(data, &recorded); err == nil && recorded != (ModelTarget{}) {
+			targets = []ModelTarget{recorded}
+		}
+	}
+
+	var lastErr error
+	for i, target := range targets {
+		if i > 0 {
+			v.engine.logger.Warn("llm call falling back",
+				"workflow_id", v.exec.wfState.ID,
+				"route", name,
+				"provider", target.Provider,
+				"model", target.Model,
+				"error", lastErr,
+			)
+		}
+		start := time.Now()
+		out, err := call(ctx, target)
+		result := routeResult(err)
+		if recorder, ok := v.engine.metrics.(modelRouteRecorder); ok {
+			recorder.RecordModelRouteCall(name, target.Provider, target.Model, result, time.Since(start))
+		}
+		if err == nil {
+			recordRoute(ctx, kind, target)
+			return out, target, nil
+		}
+		lastErr = err
+		if result == "canceled" || ctx.Err() != nil {
+			break
+		}
+		if route.FallbackOn == "rate_limit" && result != "rate_limited" {
+			break
+		}
+	}
+	recordRoute(ctx, kind, ModelTarget{})
+	return nil, ModelTarget{}, lastErr
+}
+
+// recordRoute journals the target a routed call was answered by, or the
+// zero target when every target failed.
+func recordRoute(ctx context.Context, kind string, target ModelTarget) {
+	data, err := json.Marshal(target)
+	if err == nil {
+		recordDecision(ctx, kind, data)
+	}
+}
+
+// routeTargets returns the targets of route in the order calls try them.
+func routeTargets(route config.ModelRouteConfig, retry bool) []ModelTarget {
+	first := route.Primary
+	if retry && route.Retry != nil {
+		first = *route.Retry
+	}
+	targets := []ModelTarget{{Provider: first.Provider, Model: first.Model}}
+	for _, fallback := range route.Fallbacks {
+		targets = append(targets, ModelTarget{Provider: fallback.Provider, Model: fallback.Model})
+	}
+	return targets
+}
+
+// routeResult classifies the outcome of a routed call for metrics.
+func routeResult(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errs.Is(err, errs.RateLimited):
+		return "rate_limited"
+	case errs.Is(err, errs.Canceled):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestRouteLLMCall_FallsBackAndRetriesOnCheaperModel(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.ModelRoutes = map[string]config.ModelRouteConfig{
+		"chat": {
+			Primary:   config.ModelTargetConfig{Provider: "openai", Model: "gpt-4o"},
+			Fallbacks: []config.ModelTargetConfig{{Provider: "anthropic", Model: "claude-sonnet"}},
+			Retry:     &config.ModelTargetConfig{Provider: "openai", Model: "gpt-4o-mini"},
+		},
+		"strict": {
+			Primary:    config.ModelTargetConfig{Provider: "openai", Model: "gpt-4o"},
+			Fallbacks:  []config.ModelTargetConfig{{Provider: "anthropic", Model: "claude-sonnet"}},
+			FallbackOn: "rate_limit",
+		},
+	}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var tried []string
+	var served ModelTarget
+	status, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "answer",
+		Tasks: []models.TaskDefinition{
+			{ID: "chat", Name: "chat", Type: "function", Retries: 1},
+			{ID: "strict", Name: "strict", Type: "function", DependsOn: []string{"chat"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"chat": func(ctx context.Context) error {
+				_, target, err := RouteLLMCall(ctx, "chat", func(_ context.Context, target ModelTarget) ([]byte, error) {
+					tried = append(tried, target.Model)
+					switch target.Model {
+					case "gpt-4o":
+						return nil, errs.New(errs.RateLimited, "429 from provider")
+					case "claude-sonnet":
+						return nil, errors.New("provider unavailable")
+					}
+					return []byte("answer"), nil
+				})
+				served = target
+				return err
+			},
+			"strict": func(ctx context.Context) error {
+				_, _, err := RouteLLMCall(ctx, "strict", func(_ context.Context, target ModelTarget) ([]byte, error) {
+					tried = append(tried, "strict:"+target.Model)
+					return nil, errors.New("invalid prompt")
+				})
+				if err == nil || err.Error() != "invalid prompt" {
+					return errors.New("an error that is not a rate limit fell back")
+				}
+				if _, _, err := RouteLLMCall(ctx, "missing", nil); !errs.Is(err, errs.NotFound) {
+					return errors.New("an unknown route did not fail with NotFound")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if status.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", status.Status, status.Error)
+	}
+
+	want := []string{"gpt-4o", "claude-sonnet", "gpt-4o-mini", "strict:gpt-4o"}
+	if len(tried) != len(want) {
+		t.Fatalf("tried %v, want %v", tried, want)
+	}
+	for i := range want {
+		if tried[i] != want[i] {
+			t.Fatalf("tried %v, want %v", tried, want)
+		}
+	}
+	if served != (ModelTarget{Provider: "openai", Model: "gpt-4o-mini"}) {
+		t.Fatalf("served by %+v, want the retry target", served)
+	}
+
+	if _, _, err := RouteLLMCall(ctx, "chat", nil); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("RouteLLMCall() outside a task error = %v, want BadRequest", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 // WorkflowEvent represents a workflow state change event
 type WorkflowEvent struct {
 	WorkflowID string
+	RequestID  string
 	EventType  WorkflowEventType
 	Status     string
 	Message    string
@@ -45,12 +46,18 @@ func (t WorkflowEventType) String() string {
 // TaskEvent represents a task state change event
 type TaskEvent struct {
 	WorkflowID string
+	RequestID  string
 	TaskID     string
 	EventType  TaskEventType
 	Status     string
 	Progress   int
 	Message    string
 	Timestamp  int64
+	// Chunk is the next piece of the output the task streams, with
+	// ChunkSeq numbering it in the attempt, on TaskEventProgress events
+	// published by StreamTaskOutput.
+	Chunk    string
+	ChunkSeq int64
 }
 
 // TaskEventType represents the type of task event
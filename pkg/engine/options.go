@@ -1,7 +1,9 @@
 package engine
 
 import (
+	"github.com/goclaw/goclaw/pkg/artifact"
 	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/llmcache"
 	"github.com/goclaw/goclaw/pkg/signal"
 	"github.com/redis/go-redis/v9"
 )
@@ -18,6 +20,16 @@ func WithMetrics(metrics MetricsRecorder) Option {
 	}
 }
 
+// WithLaneLogger sets the logger of task dispatch through lanes, so that its
+// level can be set apart from the engine's. It defaults to the engine logger.
+func WithLaneLogger(log appLogger) Option {
+	return func(e *Engine) {
+		if log != nil {
+			e.laneLogger = log
+		}
+	}
+}
+
 // WithMemoryHub sets the memory hub for the engine.
 func WithMemoryHub(hub MemoryHub) Option {
 	return func(e *Engine) {
@@ -27,6 +39,13 @@ func WithMemoryHub(hub MemoryHub) Option {
 	}
 }
 
+// WithLLMCache caches the LLM calls tasks make through CachedLLMCall in c.
+func WithLLMCache(c *llmcache.Cache) Option {
+	return func(e *Engine) {
+		e.llmCache = c
+	}
+}
+
 // WithSignalBus sets the signal bus for the engine.
 func WithSignalBus(bus signal.Bus) Option {
 	return func(e *Engine) {
@@ -37,7 +56,8 @@ func WithSignalBus(bus signal.Bus) Option {
 }
 
 // WithRedisClient sets the shared Redis client used by Redis-backed lanes.
-func WithRedisClient(client redis.Cmdable) Option {
+// Single-node, Sentinel and Cluster clients are all accepted.
+func WithRedisClient(client redis.UniversalClient) Option {
 	return func(e *Engine) {
 		if client != nil {
 			e.redisClient = client
@@ -54,6 +74,16 @@ func WithRedisOwnershipGuard(guard lane.RedisOwnershipGuard) Option {
 	}
 }
 
+// WithEnqueueOnly makes the engine enqueue tasks onto Redis-backed lanes
+// without consuming them, leaving execution to worker nodes that share the
+// Redis. Memory lanes, including the local fallback of a Redis lane, still
+// run their tasks.
+func WithEnqueueOnly() Option {
+	return func(e *Engine) {
+		e.enqueueOnly = true
+	}
+}
+
 // WithEventBroadcaster sets an event broadcaster for workflow/task state changes.
 func WithEventBroadcaster(broadcaster EventBroadcaster) Option {
 	return func(e *Engine) {
@@ -62,3 +92,27 @@ func WithEventBroadcaster(broadcaster EventBroadcaster) Option {
 		}
 	}
 }
+
+// WithArtifactStore sets the store tasks upload and download artifacts with
+// through artifact.Upload and artifact.Download.
+func WithArtifactStore(store *artifact.Store) Option {
+	return func(e *Engine) {
+		if store != nil {
+			e.artifacts = store
+		}
+	}
+}
+
+// WithTaskExecutor runs tasks of agent type taskType that have no task
+// function with exec, e.g. "container" tasks with a container runtime.
+func WithTaskExecutor(taskType string, exec TaskExecutor) Option {
+	return func(e *Engine) {
+		if exec == nil {
+			return
+		}
+		if e.executors == nil {
+			e.executors = make(map[string]TaskExecutor)
+		}
+		e.executors[taskType] = exec
+	}
+}
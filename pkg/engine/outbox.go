@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Outbox defaults used when orchestration.outbox leaves them unset.
+const (
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxMaxBackoff   = 5 * time.Minute
+
+	// outboxBatchSize is how many entries a dispatch pass reads at once.
+	outboxBatchSize = 100
+)
+
+// Notifier delivers the notifications of the outbox. An error leaves the
+// entry in the outbox to be retried, so Notify may see an entry again after
+// it returned nil but before the entry was deleted. Notify may append the
+// targets that accepted the entry to its Delivered list, which is saved
+// with the entry when Notify fails, so that retries skip them.
+type Notifier interface {
+	Notify(ctx context.Context, entry *storage.OutboxEntry) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, entry *storage.OutboxEntry) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, entry *storage.OutboxEntry) error {
+	return f(ctx, entry)
+}
+
+// WithNotifier records workflow state changes and SLA breaches in the
+// outbox of the storage, in the same write as the change, and delivers them
+// with n until it accepts them. It has no effect unless the storage
+// implements storage.Outbox.
+func WithNotifier(n Notifier) Option {
+	return func(e *Engine) {
+		if n != nil {
+			e.notifier = n
+		}
+	}
+}
+
+// outboxEnabled reports whether notifications go through the outbox.
+func (e *Engine) outboxEnabled() bool {
+	if e.notifier == nil {
+		return false
+	}
+	_, ok := e.storage.(storage.Outbox)
+	return ok
+}
+
+// notifications returns the outbox entries reporting wf, which was
+// oldStatus before: its state change if it changed, and its SLA breach if
+// breached. It returns nil when the outbox is not in use.
+func (e *Engine) notifications(wf *storage.WorkflowState, oldStatus string, breached bool) ([]*storage.OutboxEntry, error) {
+	if !e.outboxEnabled() {
+		return nil, nil
+	}
+	now := time.Now().UTC()
+	var entries []*storage.OutboxEntry
+	if oldStatus != wf.Status {
+		payload := events.WorkflowStateChangedPayload(wf.RequestID, wf.ID, wf.Name, oldStatus, wf.Status, now)
+		entry, err := storage.NewOutboxEntry(events.TypeWorkflowStateChanged, payload, now)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if breached {
+		payload := events.WorkflowSLABreachedPayload(wf.ID, wf.Name, wf.Status, wf.Metadata, *wf.SLADeadline, *wf.SLABreachedAt)
+		entry, err := storage.NewOutboxEntry(events.TypeWorkflowSLABreached, payload, now)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// writeOutbox saves entries with w.
+func writeOutbox(ctx context.Context, w storage.Writer, entries []*storage.OutboxEntry) error {
+	for _, entry := range entries {
+		if err := storage.SaveOutbox(ctx, w, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveWorkflowNotifying saves wf together with the outbox entries reporting
+// its change, and wakes the dispatcher once they are committed.
+func (e *Engine) saveWorkflowNotifying(ctx context.Context, wf *storage.WorkflowState, entries []*storage.OutboxEntry) error {
+	if len(entries) == 0 {
+		return e.storage.SaveWorkflow(ctx, wf)
+	}
+	err := storage.WriteBatch(ctx, e.storage, func(w storage.Writer) error {
+		if err := w.SaveWorkflow(ctx, wf); err != nil {
+			return err
+		}
+		return writeOutbox(ctx, w, entries)
+	})
+	if err != nil {
+		return err
+	}
+	e.wakeOutbox()
+	return nil
+}
+
+// wakeOutbox makes the dispatcher look for due entries now.
+func (e *Engine) wakeOutbox() {
+	select {
+	case e.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchOutbox delivers the outbox every interval, and whenever woken,
+// until ctx is done.
+func (e *Engine) dispatchOutbox(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.deliverOutbox(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.outboxWake:
+		}
+	}
+}
+
+// deliverOutbox delivers the due entries of the outbox in the order they
+// were created, deleting each once the notifier accepts it. A failed
+// delivery is scheduled again with exponential backoff from interval, and
+// the pass goes on with the next entries, so that one failing receiver does
+// not hold back the others. Entries older than the retention are dropped.
+func (e *Engine) deliverOutbox(ctx context.Context, interval time.Duration) {
+	cfg := e.cfg.Orchestration.Outbox
+	maxBackoff := cfg.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultOutboxMaxBackoff
+	}
+	for {
+		entries, err := storage.ListOutbox(ctx, e.storage, outboxBatchSize)
+		if err != nil {
+			e.logger.Warn("failed to list outbox", "error", err)
+			return
+		}
+		// A pass lists the outbox again while it deletes entries: the
+		// entries it skips are listed first.
+		deleted := 0
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return
+			}
+			now := time.Now().UTC()
+			if cfg.Retention > 0 && now.Sub(entry.CreatedAt) > cfg.Retention {
+				e.logger.Warn("dropping undelivered notification",
+					"id", entry.ID,
+					"type", entry.Type,
+					"attempts", entry.Attempts,
+					"last_error", entry.LastError,
+				)
+				if err := storage.DeleteOutbox(ctx, e.storage, entry.ID); err != nil {
+					e.logger.Warn("failed to delete outbox entry", "id", entry.ID, "error", err)
+					return
+				}
+				deleted++
+				continue
+			}
+			if entry.NextAttemptAt.After(now) {
+				continue
+			}
+			if err := e.notifier.Notify(ctx, entry); err != nil {
+				entry.Attempts++
+				entry.LastError = err.Error()
+				entry.NextAttemptAt = now.Add(outboxBackoff(interval, maxBackoff, entry.Attempts))
+				e.logger.Warn("failed to deliver notification",
+					"id", entry.ID,
+					"type", entry.Type,
+					"attempts", entry.Attempts,
+					"next_attempt_at", entry.NextAttemptAt,
+					"error", err,
+				)
+				if err := storage.SaveOutbox(ctx, e.storage, entry); err != nil {
+					e.logger.Warn("failed to reschedule outbox entry", "id", entry.ID, "error", err)
+				}
+				continue
+			}
+			if err := storage.DeleteOutbox(ctx, e.storage, entry.ID); err != nil {
+				e.logger.Warn("failed to delete outbox entry", "id", entry.ID, "error", err)
+				return
+			}
+			deleted++
+		}
+		if len(entries) < outboxBatchSize || deleted == 0 {
+			return
+		}
+	}
+}
+
+// outboxBackoff returns the delay before the next attempt after attempts
+// failed ones: base doubled per failure, capped at max.
+func outboxBackoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
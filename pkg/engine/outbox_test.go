@@ -0,0 +1,187 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_DeliversNotificationsThroughOutbox(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Outbox.PollInterval = 10 * time.Millisecond
+	cfg.Orchestration.Outbox.MaxBackoff = 20 * time.Millisecond
+
+	var mu sync.Mutex
+	var delivered []string
+	failures := 2
+	notifier := NotifierFunc(func(_ context.Context, entry *storage.OutboxEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			return errors.New("receiver unavailable")
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			return err
+		}
+		delivered = append(delivered, entry.Type+":"+payload["new_state"].(string))
+		return nil
+	})
+
+	store := memory.NewMemoryStorage()
+	eng, err := New(cfg, nil, store, WithNotifier(notifier))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	_, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "notified",
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	// The failed deliveries are retried, without holding back the state
+	// changes after them.
+	want := []string{
+		"workflow.state_changed:completed",
+		"workflow.state_changed:pending",
+		"workflow.state_changed:running",
+		"workflow.state_changed:scheduled",
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		got := append([]string(nil), delivered...)
+		mu.Unlock()
+		if len(got) == len(want) {
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("delivered %v, want %v", got, want)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered %v, want %v", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	entries, err := store.ListOutbox(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListOutbox() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("outbox holds %d delivered entries, want none", len(entries))
+	}
+}
+
+func TestEngine_OutboxDropsExpiredEntries(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Outbox.Retention = time.Hour
+
+	store := memory.NewMemoryStorage()
+	notified := 0
+	eng, err := New(cfg, nil, store, WithNotifier(NotifierFunc(func(context.Context, *storage.OutboxEntry) error {
+		notified++
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	expired, _ := storage.NewOutboxEntry("workflow.state_changed", nil, time.Now().Add(-2*time.Hour))
+	if err := store.SaveOutbox(ctx, expired); err != nil {
+		t.Fatalf("SaveOutbox() error = %v", err)
+	}
+	fresh, _ := storage.NewOutboxEntry("workflow.state_changed", nil, time.Now())
+	if err := store.SaveOutbox(ctx, fresh); err != nil {
+		t.Fatalf("SaveOutbox() error = %v", err)
+	}
+
+	eng.deliverOutbox(ctx, time.Second)
+	if notified != 1 {
+		t.Fatalf("notified %d entries, want only the fresh one", notified)
+	}
+	if entries, _ := store.ListOutbox(ctx, 0); len(entries) != 0 {
+		t.Fatalf("outbox holds %d entries, want none", len(entries))
+	}
+}
+
+func TestEngine_OutboxRetriesFailedEntriesAlone(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	var notified []string
+	eng, err := New(minConfig(), nil, store, WithNotifier(NotifierFunc(func(_ context.Context, entry *storage.OutboxEntry) error {
+		notified = append(notified, entry.ID)
+		if entry.Type == "failing" {
+			// One of two targets accepted it.
+			if len(entry.Delivered) == 0 {
+				entry.Delivered = append(entry.Delivered, "ops")
+			}
+			return errors.New("receiver unavailable")
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	failing, _ := storage.NewOutboxEntry("failing", nil, now)
+	later, _ := storage.NewOutboxEntry("workflow.state_changed", nil, now.Add(time.Millisecond))
+	for _, entry := range []*storage.OutboxEntry{failing, later} {
+		if err := store.SaveOutbox(ctx, entry); err != nil {
+			t.Fatalf("SaveOutbox() error = %v", err)
+		}
+	}
+
+	eng.deliverOutbox(ctx, time.Minute)
+	if want := []string{failing.ID, later.ID}; !reflect.DeepEqual(notified, want) {
+		t.Fatalf("notified %v, want %v", notified, want)
+	}
+	entries, _ := store.ListOutbox(ctx, 0)
+	if len(entries) != 1 || entries[0].ID != failing.ID {
+		t.Fatalf("outbox holds %+v, want only the failed entry", entries)
+	}
+	if entry := entries[0]; entry.Attempts != 1 || !entry.NextAttemptAt.After(now) || !reflect.DeepEqual(entry.Delivered, []string{"ops"}) {
+		t.Fatalf("failed entry = %+v, want it rescheduled with its accepted target", entry)
+	}
+
+	// The backoff of the failed entry is not over yet.
+	eng.deliverOutbox(ctx, time.Minute)
+	if len(notified) != 2 {
+		t.Fatalf("notified %v before the backoff was over", notified)
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		4: 8 * time.Second,
+		9: 10 * time.Second,
+	} {
+		if got := outboxBackoff(time.Second, 10*time.Second, attempts); got != want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
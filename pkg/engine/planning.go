@@ -0,0 +1,197 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/requestid"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Metadata of planner workflows and of the workflows they propose.
+const (
+	// MetadataPlan marks a planner workflow.
+	MetadataPlan = "goclaw.io/plan"
+	// MetadataPlanTemplate names the template a plan is reviewed against.
+	MetadataPlanTemplate = "goclaw.io/plan-template"
+	// MetadataPlannedBy is the ID of the planner workflow that proposed a
+	// planned workflow.
+	MetadataPlannedBy = "goclaw.io/planned-by"
+	// MetadataPlannedWorkflow is the ID of the workflow a planner proposed.
+	MetadataPlannedWorkflow = "goclaw.io/planned-workflow"
+)
+
+// PlanWorkflow submits the planner task of req as an asynchronous workflow.
+// Once the planner completes, the workflow request it set as its result is
+// stored in the planned status, for a reviewer to approve with
+// ApproveWorkflow or reject by cancelling it. The planner workflow's
+// metadata then holds the ID of the planned one under
+// MetadataPlannedWorkflow.
+func (e *Engine) PlanWorkflow(ctx context.Context, req *models.PlanRequest) (*models.WorkflowStatusResponse, error) {
+	if _, ok := e.executors[req.Planner.Type]; !ok {
+		return nil, errs.Newf(errs.BadRequest, "planner task %s: no executor runs %s tasks", req.Planner.ID, req.Planner.Type)
+	}
+	metadata := maps.Clone(req.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string, 2)
+	}
+	metadata[MetadataPlan] = "planner"
+	delete(metadata, MetadataPlanTemplate)
+	if req.Template != "" {
+		metadata[MetadataPlanTemplate] = req.Template
+	}
+	return e.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Tasks:       []models.TaskDefinition{req.Planner},
+		Metadata:    metadata,
+		Async:       true,
+	}, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+}
+
+// proposePlan stores the workflow proposed by the planner workflow of exec
+// in the planned status, once the planner completed. It does nothing for
+// other workflows.
+func (e *Engine) proposePlan(ctx context.Context, exec *workflowExecution) error {
+	exec.mu.Lock()
+	planner := exec.wfState
+	if planner.Metadata[MetadataPlan] == "" || len(planner.Tasks) != 1 {
+		exec.mu.Unlock()
+		return nil
+	}
+	var result any
+	if taskState := planner.TaskStatus[planner.Tasks[0].ID]; taskState != nil {
+		result = taskState.Result
+	}
+	plannerID, name, description, template := planner.ID, planner.Name, planner.Description, planner.Metadata[MetadataPlanTemplate]
+	exec.mu.Unlock()
+
+	req, err := decodePlan(result)
+	if err != nil {
+		return err
+	}
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Description == "" {
+		req.Description = description
+	}
+	req.Metadata = maps.Clone(req.Metadata)
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string, 2)
+	}
+	delete(req.Metadata, MetadataPlan)
+	delete(req.Metadata, MetadataPlanTemplate)
+	req.Metadata[MetadataPlannedBy] = plannerID
+	if template != "" {
+		req.Metadata[MetadataPlanTemplate] = template
+	}
+	if err := e.ValidateWorkflowRequest(ctx, req); err != nil {
+		return fmt.Errorf("invalid plan: %w", err)
+	}
+
+	wfState := newWorkflowState(req)
+	wfState.Status = workflowStatusPlanned
+	wfState.RequestID = requestid.FromContext(ctx)
+	err = storage.WriteBatch(ctx, e.storage, func(w storage.Writer) error {
+		if err := w.SaveWorkflow(ctx, wfState); err != nil {
+			return fmt.Errorf("failed to save planned workflow: %w", err)
+		}
+		for _, taskState := range wfState.TaskStatus {
+			if err := w.SaveTask(ctx, wfState.ID, taskState); err != nil {
+				return fmt.Errorf("failed to save planned task %s: %w", taskState.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.emitWorkflowStateChanged(wfState.RequestID, wfState.ID, wfState.Name, "", wfState.Status)
+	e.logger.Info("workflow planned", "id", wfState.ID, "planner_id", plannerID, "tasks", len(wfState.Tasks))
+
+	// The planner's metadata is saved with its completion.
+	exec.mu.Lock()
+	planner.Metadata = maps.Clone(planner.Metadata)
+	planner.Metadata[MetadataPlannedWorkflow] = wfState.ID
+	exec.mu.Unlock()
+	return nil
+}
+
+// decodePlan decodes the workflow request a planner set as its result,
+// either as an object or as a JSON string.
+func decodePlan(result any) (*models.WorkflowRequest, error) {
+	var data []byte
+	switch v := result.(type) {
+	case nil:
+		return nil, errors.New("planner set no result")
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("failed to encode planner result: %w", err)
+		}
+	}
+	var req models.WorkflowRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("planner result is not a workflow: %w", err)
+	}
+	if len(req.Tasks) == 0 {
+		return nil, errors.New("planner proposed no tasks")
+	}
+	return &req, nil
+}
+
+// ApproveWorkflow moves the planned workflow id to pending and starts it, as
+// if it had just been submitted asynchronously.
+func (e *Engine) ApproveWorkflow(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	// Approvals are serialized so that a workflow approved twice starts once.
+	e.approvals.Lock()
+	defer e.approvals.Unlock()
+
+	wfState, err := e.storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wfState.Status != workflowStatusPlanned {
+		return nil, errs.Newf(errs.Conflict, "workflow is not awaiting approval: %s", wfState.Status)
+	}
+	// The run's latency budget starts with the approval, not the plan.
+	now := time.Now().UTC()
+	waited := now.Sub(wfState.CreatedAt)
+	for _, deadline := range []*time.Time{wfState.Deadline, wfState.SLADeadline} {
+		if deadline != nil {
+			*deadline = deadline.Add(waited)
+		}
+	}
+	wfState.Status = workflowStatusPending
+	wfState.CreatedAt = now
+	notifications, err := e.notifications(wfState, workflowStatusPlanned, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.saveWorkflowNotifying(ctx, wfState, notifications); err != nil {
+		return nil, err
+	}
+	e.metrics.RecordWorkflowSubmission(workflowStatusPending)
+	e.emitWorkflowStateChanged(wfState.RequestID, wfState.ID, wfState.Name, workflowStatusPlanned, wfState.Status)
+	e.logger.Info("workflow approved", "id", id)
+
+	if !e.hasExecutorTasks(&models.WorkflowRequest{Tasks: wfState.Tasks}) {
+		return e.workflowStateToResponse(ctx, wfState), nil
+	}
+	if _, err := e.startWorkflowExecution(ctx, id, nil, nil); err != nil {
+		if transitionErr := e.markWorkflowFailedFromPending(ctx, id, err); transitionErr != nil {
+			e.logger.Error("failed to mark workflow failed after start error", "workflow_id", id, "error", transitionErr)
+		}
+		return nil, err
+	}
+	return e.workflowStateToResponse(ctx, wfState), nil
+}
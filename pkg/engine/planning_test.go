@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// planExecutor runs "function" tasks: the planner task "plan" sets plan as
+// its result, and the other tasks are recorded as run.
+type planExecutor struct {
+	mu   sync.Mutex
+	plan any
+	ran  []string
+}
+
+func (x *planExecutor) TaskFunc(workflowID string, task *dag.Task) func(context.Context) error {
+	return func(ctx context.Context) error {
+		x.mu.Lock()
+		defer x.mu.Unlock()
+		if task.ID == "plan" {
+			return SetTaskResult(ctx, x.plan)
+		}
+		x.ran = append(x.ran, task.ID)
+		return nil
+	}
+}
+
+func TestEngine_PlanAndApproveWorkflow(t *testing.T) {
+	x := &planExecutor{}
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store, WithTaskExecutor("function", x))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	if _, err := eng.PlanWorkflow(ctx, &models.PlanRequest{
+		Name:    "research",
+		Planner: models.TaskDefinition{ID: "plan", Name: "plan", Type: "http"},
+	}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PlanWorkflow() without a planner executor error = %v, want BadRequest", err)
+	}
+
+	plan := func(result any) *models.WorkflowStatusResponse {
+		t.Helper()
+		x.mu.Lock()
+		x.plan = result
+		x.mu.Unlock()
+		resp, err := eng.PlanWorkflow(ctx, &models.PlanRequest{
+			Name:     "research",
+			Planner:  models.TaskDefinition{ID: "plan", Name: "plan", Type: "function"},
+			Template: "research",
+		})
+		if err != nil {
+			t.Fatalf("PlanWorkflow() error = %v", err)
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		final, err := eng.WaitWorkflow(waitCtx, resp.ID)
+		if err != nil {
+			t.Fatalf("WaitWorkflow() error = %v", err)
+		}
+		return final
+	}
+
+	if planner := plan(`{"tasks": []}`); planner.Status != workflowStatusFailed {
+		t.Fatalf("planner proposing no tasks status = %s, want failed", planner.Status)
+	}
+
+	planner := plan(map[string]any{
+		"tasks": []any{
+			map[string]any{"id": "fetch", "name": "fetch", "type": "function"},
+			map[string]any{"id": "summarize", "name": "summarize", "type": "function", "depends_on": []string{"fetch"}},
+		},
+	})
+	if planner.Status != workflowStatusCompleted {
+		t.Fatalf("planner status = %s (%s), want completed", planner.Status, planner.Error)
+	}
+	plannedID := planner.Metadata[MetadataPlannedWorkflow]
+	planned, err := eng.GetWorkflowStatusResponse(ctx, plannedID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse(%q) error = %v", plannedID, err)
+	}
+	if planned.Status != workflowStatusPlanned || planned.Name != "research" || len(planned.Tasks) != 2 {
+		t.Fatalf("planned workflow = %+v, want research with 2 tasks awaiting approval", planned)
+	}
+	if planned.Metadata[MetadataPlannedBy] != planner.ID || planned.Metadata[MetadataPlanTemplate] != "research" {
+		t.Fatalf("planned workflow metadata = %v, want the planner and template", planned.Metadata)
+	}
+	x.mu.Lock()
+	ran := len(x.ran)
+	x.mu.Unlock()
+	if ran != 0 {
+		t.Fatalf("%d planned tasks ran before approval", ran)
+	}
+
+	if _, err := eng.ApproveWorkflow(ctx, planner.ID); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("ApproveWorkflow() of the planner error = %v, want Conflict", err)
+	}
+	if _, err := eng.ApproveWorkflow(ctx, plannedID); err != nil {
+		t.Fatalf("ApproveWorkflow() error = %v", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	final, err := eng.WaitWorkflow(waitCtx, plannedID)
+	if err != nil {
+		t.Fatalf("WaitWorkflow() error = %v", err)
+	}
+	if final.Status != workflowStatusCompleted {
+		t.Fatalf("approved workflow status = %s (%s), want completed", final.Status, final.Error)
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if len(x.ran) != 2 || x.ran[0] != "fetch" || x.ran[1] != "summarize" {
+		t.Fatalf("approved workflow ran %v, want fetch then summarize", x.ran)
+	}
+}
+
+func TestEngine_RejectPlannedWorkflow(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store, WithTaskExecutor("function", &planExecutor{}))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+
+	wfState := newWorkflowState(&models.WorkflowRequest{
+		Name:  "planned",
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	})
+	wfState.Status = workflowStatusPlanned
+	if err := store.SaveWorkflow(ctx, wfState); err != nil {
+		t.Fatalf("SaveWorkflow() error = %v", err)
+	}
+	if err := eng.CancelWorkflowRequest(ctx, wfState.ID); err != nil {
+		t.Fatalf("CancelWorkflowRequest() error = %v", err)
+	}
+	if _, err := eng.ApproveWorkflow(ctx, wfState.ID); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("ApproveWorkflow() of a rejected plan error = %v, want Conflict", err)
+	}
+}
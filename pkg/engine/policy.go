@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/policy"
+	"github.com/goclaw/goclaw/pkg/requestid"
+	"github.com/goclaw/goclaw/pkg/saga"
+)
+
+// WithPolicy checks workflow and saga submissions and config updates with
+// the given evaluator instead of the OPA server configured under policy.
+func WithPolicy(evaluator policy.Evaluator) Option {
+	return func(e *Engine) {
+		if evaluator != nil {
+			e.policy = evaluator
+		}
+	}
+}
+
+// checkPolicy evaluates a request against the policy and logs the decision
+// as an audit record. Denied requests fail with errs.PermissionDenied. When
+// the policy cannot be evaluated the request fails with
+// errs.ServiceUnavailable, or is admitted if the policy fails open.
+func (e *Engine) checkPolicy(ctx context.Context, kind policy.Kind, name string, request any) error {
+	if e.policy == nil {
+		return nil
+	}
+	requestID := requestid.FromContext(ctx)
+	decision, err := e.policy.Evaluate(ctx, policy.Input{Kind: kind, RequestID: requestID, Request: request})
+	if err != nil {
+		failOpen := e.cfg.Policy.FailOpen
+		e.logger.Error("policy decision", "audit", true, "kind", kind, "name", name, "request_id", requestID, "allowed", failOpen, "error", err)
+		if failOpen {
+			return nil
+		}
+		return errs.Wrap(err, errs.ServiceUnavailable, "policy evaluation failed")
+	}
+	e.logger.Info("policy decision", "audit", true, "kind", kind, "name", name, "request_id", requestID, "allowed", decision.Allowed, "violations", decision.Violations)
+	if !decision.Allowed {
+		return errs.New(errs.PermissionDenied, "rejected by policy: "+strings.Join(decision.Violations, "; ")).
+			WithDetail("violations", decision.Violations)
+	}
+	return nil
+}
+
+// admitSaga is the saga orchestrator's admission check.
+func (e *Engine) admitSaga(ctx context.Context, submission saga.Submission) error {
+	definition := submission.Definition
+	steps := make([]map[string]any, 0, len(definition.StepOrder))
+	for _, id := range definition.StepOrder {
+		step := definition.Steps[id]
+		steps = append(steps, map[string]any{
+			"id":         step.ID,
+			"depends_on": step.Dependencies,
+			"timeout_ms": step.Timeout.Milliseconds(),
+		})
+	}
+	return e.checkPolicy(ctx, policy.KindSaga, definition.Name, map[string]any{
+		"name":       definition.Name,
+		"policy":     definition.Policy.String(),
+		"timeout_ms": definition.Timeout.Milliseconds(),
+		"metadata":   submission.Metadata,
+		"input":      submission.Input,
+		"steps":      steps,
+	})
+}
+
+// checkConfigPolicy is a reload applier rejecting config changes the policy
+// denies. It is registered ahead of the appliers that make changes.
+func (e *Engine) checkConfigPolicy(_ *config.Config, changes []config.FieldChange) error {
+	if e.policy == nil {
+		return nil
+	}
+	request := make([]map[string]any, 0, len(changes))
+	for _, change := range changes {
+		request = append(request, map[string]any{"key": change.Key, "old": change.Old, "new": change.New})
+	}
+	return e.checkPolicy(context.Background(), policy.KindConfig, "", map[string]any{"changes": request})
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/policy"
+	"github.com/goclaw/goclaw/pkg/saga"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// maxTasksPolicy denies workflows with more tasks than max and config
+// updates of max_agents.
+type maxTasksPolicy struct {
+	max    int
+	err    error
+	inputs []policy.Input
+}
+
+func (p *maxTasksPolicy) Evaluate(_ context.Context, input policy.Input) (policy.Decision, error) {
+	p.inputs = append(p.inputs, input)
+	if p.err != nil {
+		return policy.Decision{}, p.err
+	}
+	switch req := input.Request.(type) {
+	case *models.WorkflowRequest:
+		if len(req.Tasks) > p.max {
+			return policy.Decision{Violations: []string{"workflow has too many tasks"}}, nil
+		}
+	case map[string]any:
+		if changes, ok := req["changes"].([]map[string]any); ok && changes[0]["key"] == "orchestration.max_agents" {
+			return policy.Decision{Violations: []string{"max_agents is managed centrally"}}, nil
+		}
+	}
+	return policy.Decision{Allowed: true}, nil
+}
+
+func TestEngine_PolicyRejectsSubmissions(t *testing.T) {
+	evaluator := &maxTasksPolicy{max: 1}
+	eng, err := New(config.DefaultConfig(), nil, memory.NewMemoryStorage(), WithPolicy(evaluator))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	req := &models.WorkflowRequest{
+		Name: "oversized",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function"},
+			{ID: "b", Name: "b", Type: "function", DependsOn: []string{"a"}},
+		},
+	}
+	_, err = eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+	if !errs.Is(err, errs.PermissionDenied) {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v, want %s", err, errs.PermissionDenied)
+	}
+	if len(evaluator.inputs) != 1 || evaluator.inputs[0].Kind != policy.KindWorkflow {
+		t.Fatalf("policy inputs = %+v", evaluator.inputs)
+	}
+
+	req.Tasks = req.Tasks[:1]
+	if _, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeAsync}); err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() of a compliant workflow error = %v", err)
+	}
+
+	definition, err := saga.New("payment").Step("charge", saga.Action(func(context.Context, *saga.StepContext) (any, error) { return nil, nil })).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if err := eng.admitSaga(ctx, saga.Submission{Definition: definition, Metadata: map[string]string{"team": "payments"}}); err != nil {
+		t.Fatalf("admitSaga() error = %v", err)
+	}
+	last := evaluator.inputs[len(evaluator.inputs)-1]
+	if last.Kind != policy.KindSaga || last.Request.(map[string]any)["name"] != "payment" {
+		t.Fatalf("saga policy input = %+v", last)
+	}
+
+	evaluator.err = errors.New("connection refused")
+	_, err = eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+	if !errs.Is(err, errs.ServiceUnavailable) {
+		t.Fatalf("SubmitWorkflowRuntime() with the policy unavailable error = %v, want %s", err, errs.ServiceUnavailable)
+	}
+	eng.cfg.Policy.FailOpen = true
+	if _, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeAsync}); err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() failing open error = %v", err)
+	}
+}
+
+func TestEngine_PolicyRejectsConfigUpdates(t *testing.T) {
+	eng, err := New(config.DefaultConfig(), nil, memory.NewMemoryStorage(), WithPolicy(&maxTasksPolicy{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := eng.ConfigReloader().ApplyUpdates(map[string]string{"orchestration.max_agents": "64"})
+	if err != nil {
+		t.Fatalf("ApplyUpdates failed: %v", err)
+	}
+	if len(result.Applied) != 0 || len(result.Rejected) != 1 {
+		t.Fatalf("expected the change to be rejected, got applied=%+v rejected=%+v", result.Applied, result.Rejected)
+	}
+	if got := eng.ConfigReloader().Current().Orchestration.MaxAgents; got == 64 {
+		t.Fatal("rejected max_agents change was applied")
+	}
+
+	result, err = eng.ConfigReloader().ApplyUpdates(map[string]string{"log.level": "debug"})
+	if err != nil {
+		t.Fatalf("ApplyUpdates failed: %v", err)
+	}
+	if len(result.Applied) != 1 {
+		t.Fatalf("expected the change to be applied, got applied=%+v rejected=%+v", result.Applied, result.Rejected)
+	}
+}
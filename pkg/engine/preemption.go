@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errTaskPreempted is the cancellation cause of a preempted task attempt.
+var errTaskPreempted = errors.New("task preempted by higher-priority work")
+
+// preemptionRecorder is implemented by metrics recorders that count task
+// preemptions. It is optional so that MetricsRecorder stays unchanged.
+type preemptionRecorder interface {
+	RecordTaskPreemption(laneName string)
+}
+
+// preemptibleRun is a running attempt of a preemptible task.
+type preemptibleRun struct {
+	taskID   string
+	lane     string
+	priority int
+	cancel   context.CancelCauseFunc
+}
+
+// preemptor tracks running preemptible tasks so that higher-priority work
+// arriving at a saturated lane can reclaim a worker from them.
+type preemptor struct {
+	metrics MetricsRecorder
+
+	mu      sync.Mutex
+	running map[*preemptibleRun]struct{}
+}
+
+func newPreemptor(metrics MetricsRecorder) *preemptor {
+	return &preemptor{
+		metrics: metrics,
+		running: make(map[*preemptibleRun]struct{}),
+	}
+}
+
+// track registers a running attempt and returns its context, which is
+// cancelled with errTaskPreempted on preemption, and a func that must be
+// called when the attempt ends.
+func (p *preemptor) track(ctx context.Context, taskID, laneName string, priority int) (context.Context, func()) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	run := &preemptibleRun{taskID: taskID, lane: laneName, priority: priority, cancel: cancel}
+
+	p.mu.Lock()
+	p.running[run] = struct{}{}
+	p.mu.Unlock()
+
+	return runCtx, func() {
+		p.mu.Lock()
+		delete(p.running, run)
+		p.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// preempt cancels the lowest-priority preemptible task running in laneName
+// whose priority is below priority. It returns the preempted task ID, or
+// false when no task qualifies.
+func (p *preemptor) preempt(laneName string, priority int) (string, bool) {
+	p.mu.Lock()
+	var victim *preemptibleRun
+	for run := range p.running {
+		if run.lane != laneName || run.priority >= priority {
+			continue
+		}
+		if victim == nil || run.priority < victim.priority {
+			victim = run
+		}
+	}
+	if victim != nil {
+		delete(p.running, victim)
+	}
+	p.mu.Unlock()
+
+	if victim == nil {
+		return "", false
+	}
+	victim.cancel(errTaskPreempted)
+	if recorder, ok := p.metrics.(preemptionRecorder); ok {
+		recorder.RecordTaskPreemption(laneName)
+	}
+	return victim.taskID, true
+}
+
+// isPreempted reports whether ctx was cancelled by a preemption.
+func isPreempted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errTaskPreempted)
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+type preemptionMetrics struct {
+	nopMetrics
+	mu    sync.Mutex
+	lanes []string
+}
+
+func (m *preemptionMetrics) RecordTaskPreemption(laneName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lanes = append(m.lanes, laneName)
+}
+
+func TestPreemptor_PicksLowestPriority(t *testing.T) {
+	metrics := &preemptionMetrics{}
+	p := newPreemptor(metrics)
+
+	lowCtx, releaseLow := p.track(context.Background(), "low", "default", 1)
+	defer releaseLow()
+	midCtx, releaseMid := p.track(context.Background(), "mid", "default", 3)
+	defer releaseMid()
+	otherCtx, releaseOther := p.track(context.Background(), "other", "io", 0)
+	defer releaseOther()
+
+	if _, ok := p.preempt("default", 1); ok {
+		t.Fatal("expected no victim with equal priority")
+	}
+
+	victim, ok := p.preempt("default", 5)
+	if !ok || victim != "low" {
+		t.Fatalf("preempt() = %q, %v, want low", victim, ok)
+	}
+	if !isPreempted(lowCtx) {
+		t.Fatal("expected low task context to be preempted")
+	}
+	if midCtx.Err() != nil || otherCtx.Err() != nil {
+		t.Fatal("expected other tasks to keep running")
+	}
+
+	if victim, _ := p.preempt("default", 5); victim != "mid" {
+		t.Fatalf("expected mid to be preempted next, got %q", victim)
+	}
+	if _, ok := p.preempt("default", 5); ok {
+		t.Fatal("expected no more victims in default lane")
+	}
+	if len(metrics.lanes) != 2 || metrics.lanes[0] != "default" {
+		t.Fatalf("unexpected preemption metrics: %v", metrics.lanes)
+	}
+}
+
+func TestSubmitWorkflowRuntime_PreemptsLowPriorityTask(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 1
+	store := memory.NewMemoryStorage()
+	metrics := &preemptionMetrics{}
+
+	eng, err := New(cfg, nil, store, WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var lowAttempts atomic.Int32
+	lowStarted := make(chan struct{}, 2)
+	low, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "backfill",
+		Tasks: []models.TaskDefinition{{ID: "scan", Name: "scan", Type: "function", Preemptible: true}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"scan": func(ctx context.Context) error {
+				lowStarted <- struct{}{}
+				if lowAttempts.Add(1) == 1 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	select {
+	case <-lowStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("low-priority task did not start")
+	}
+
+	high, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:     "incident",
+		Priority: 5,
+		Tasks:    []models.TaskDefinition{{ID: "page", Name: "page", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"page": func(context.Context) error { return nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if high.Status != workflowStatusCompleted {
+		t.Fatalf("high-priority workflow status = %s, want %s", high.Status, workflowStatusCompleted)
+	}
+
+	if err := waitWorkflowStatus(eng, low.ID, workflowStatusCompleted, 2*time.Second); err != nil {
+		t.Fatalf("preempted workflow did not complete after requeue: %v", err)
+	}
+	status, err := eng.GetWorkflowStatusResponse(context.Background(), low.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if got := status.Tasks[0].Preemptions; got != 1 {
+		t.Fatalf("Preemptions = %d, want 1", got)
+	}
+	if got := lowAttempts.Load(); got != 2 {
+		t.Fatalf("expected the preempted task to run twice, got %d", got)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.lanes) != 1 || metrics.lanes[0] != defaultLaneName {
+		t.Fatalf("unexpected preemption metrics: %v", metrics.lanes)
+	}
+}
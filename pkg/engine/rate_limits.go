@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/rediskey"
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitsKey is the context key of the engine's rateLimits in tasks.
+type rateLimitsKey struct{}
+
+// WaitRateLimit blocks until n requests of the rate limit name from
+// orchestration.rate_limits are available to the task running with ctx, or
+// ctx is done. Tasks call it before each request to the endpoint the limit
+// guards; the budget is shared with every other task using it.
+func WaitRateLimit(ctx context.Context, name string, n int) error {
+	l, ok := ctx.Value(rateLimitsKey{}).(*rateLimits)
+	if !ok {
+		return errs.New(errs.BadRequest, "rate limits are only available to tasks run by the engine")
+	}
+	return l.wait(ctx, name, n)
+}
+
+// rateLimit is a token bucket holding up to burst requests and refilled with
+// rate requests per second.
+type rateLimit struct {
+	rate  float64
+	burst float64
+}
+
+// rateLimitBackend keeps the buckets of the rate limits.
+type rateLimitBackend interface {
+	// take takes n requests from the bucket name if it holds them and
+	// returns zero, or returns how long until it will without taking any.
+	take(ctx context.Context, name string, limit rateLimit, n int) (time.Duration, error)
+}
+
+// rateLimits are the named rate limits of external endpoints.
+type rateLimits struct {
+	limits  map[string]rateLimit
+	backend rateLimitBackend
+}
+
+// newRateLimits returns the rate limits of cfg. Their buckets are kept in
+// Redis when client is set, under the rate limit keys of the namespace
+// keyPrefix, so that all nodes sharing it share them, and in memory
+// otherwise.
+func newRateLimits(cfg map[string]config.RateLimitConfig, client redis.UniversalClient, keyPrefix string) *rateLimits {
+	l := &rateLimits{limits: make(map[string]rateLimit, len(cfg))}
+	for name, limit := range cfg {
+		per := limit.Per
+		if per <= 0 {
+			per = time.Second
+		}
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = limit.Limit
+		}
+		l.limits[name] = rateLimit{rate: float64(limit.Limit) / per.Seconds(), burst: float64(burst)}
+	}
+	if client != nil {
+		l.backend = &redisRateLimitBackend{client: client, prefix: rediskey.Prefix(keyPrefix, "ratelimit")}
+	} else {
+		l.backend = newMemoryRateLimitBackend()
+	}
+	return l
+}
+
+// check rejects tasks of req naming unknown rate limits.
+func (l *rateLimits) check(req *models.WorkflowRequest) error {
+	for _, task := range req.Tasks {
+		for _, name := range task.RateLimits {
+			if _, ok := l.limits[name]; !ok {
+				return errs.Newf(errs.BadRequest, "task %s: unknown rate limit %q", task.ID, name)
+			}
+		}
+	}
+	return nil
+}
+
+// withTask returns ctx carrying the rate limits for WaitRateLimit.
+func (l *rateLimits) withTask(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitsKey{}, l)
+}
+
+func (l *rateLimits) wait(ctx context.Context, name string, n int) error {
+	limit, ok := l.limits[name]
+	if !ok {
+		return errs.Newf(errs.BadRequest, "unknown rate limit %q", name)
+	}
+	if n <= 0 {
+		n = 1
+	}
+	if float64(n) > limit.burst {
+		return errs.Newf(errs.BadRequest, "rate limit %s allows at most %d requests at once", name, int(limit.burst))
+	}
+	for {
+		delay, err := l.backend.take(ctx, name, limit, n)
+		if err != nil || delay <= 0 {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// waitRateLimits takes one request from each rate limit the task running with
+// ctx names, before an attempt.
+func (r *taskRunner) waitRateLimits(ctx context.Context) error {
+	if len(r.task.RateLimits) == 0 {
+		return nil
+	}
+	l, ok := ctx.Value(rateLimitsKey{}).(*rateLimits)
+	if !ok {
+		return errs.New(errs.BadRequest, "rate limits are only available to tasks run by the engine")
+	}
+	for _, name := range r.task.RateLimits {
+		if err := l.wait(ctx, name, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memoryRateLimitBackend keeps the buckets in process memory.
+type memoryRateLimitBackend struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newMemoryRateLimitBackend() *memoryRateLimitBackend {
+	return &memoryRateLimitBackend{buckets: make(map[string]*memoryBucket)}
+}
+
+func (b *memoryRateLimitBackend) take(_ context.Context, name string, limit rateLimit, n int) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	bucket, ok := b.buckets[name]
+	if !ok {
+		bucket = &memoryBucket{tokens: limit.burst, updated: now}
+		b.buckets[name] = bucket
+	}
+	bucket.tokens = math.Min(limit.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*limit.rate)
+	bucket.updated = now
+	if missing := float64(n) - bucket.tokens; missing > 0 {
+		return time.Duration(math.Ceil(missing / limit.rate * float64(time.Second))), nil
+	}
+	bucket.tokens -= float64(n)
+	return 0, nil
+}
+
+// redisTakeRateLimit refills the bucket KEYS[1] at ARGV[1] tokens per
+// millisecond up to ARGV[2] tokens, then takes ARGV[3] tokens and returns 0,
+// or returns the milliseconds until it holds them. Redis's clock is used so
+// that nodes with skewed clocks agree.
+var redisTakeRateLimit = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+local wait = 0
+if tokens < n then
+  wait = math.ceil((n - tokens) / rate)
+else
+  tokens = tokens - n
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+return wait`)
+
+// redisRateLimitBackend keeps each bucket in a Redis hash.
+type redisRateLimitBackend struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func (b *redisRateLimitBackend) take(ctx context.Context, name string, limit rateLimit, n int) (time.Duration, error) {
+	perMilli := limit.rate / 1000
+	wait, err := redisTakeRateLimit.Run(ctx, b.client, []string{b.prefix + name}, perMilli, limit.burst, n).Int64()
+	if err != nil {
+		return 0, errs.Wrap(err, errs.ServiceUnavailable, "take rate limit "+name)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
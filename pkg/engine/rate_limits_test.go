@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewRateLimits_RedisKeysUseNamespace(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	l := newRateLimits(nil, client, "staging:")
+	backend, ok := l.backend.(*redisRateLimitBackend)
+	if !ok {
+		t.Fatalf("backend = %T, want *redisRateLimitBackend", l.backend)
+	}
+	if backend.prefix != "staging:goclaw:ratelimit:" {
+		t.Fatalf("rate limit key prefix = %q, want staging:goclaw:ratelimit:", backend.prefix)
+	}
+}
+
+func TestRateLimits_TokenBucket(t *testing.T) {
+	l := newRateLimits(map[string]config.RateLimitConfig{
+		"github-api": {Limit: 2, Per: time.Second},
+	}, nil, "")
+	limit := l.limits["github-api"]
+	if limit.rate != 2 || limit.burst != 2 {
+		t.Fatalf("unexpected limit %+v", limit)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if wait, err := l.backend.take(ctx, "github-api", limit, 1); err != nil || wait != 0 {
+			t.Fatalf("take %d = %v, %v, want no wait", i, wait, err)
+		}
+	}
+	wait, err := l.backend.take(ctx, "github-api", limit, 1)
+	if err != nil || wait <= 0 || wait > 500*time.Millisecond {
+		t.Fatalf("take on an empty bucket = %v, %v, want a wait of up to 500ms", wait, err)
+	}
+
+	if err := l.wait(ctx, "github-api", 3); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request above the burst, got %v", err)
+	}
+	if err := l.wait(ctx, "gitlab-api", 1); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request for an unknown limit, got %v", err)
+	}
+	if err := WaitRateLimit(ctx, "github-api", 1); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request outside the engine, got %v", err)
+	}
+}
+
+func TestSubmitWorkflowRuntime_TasksShareRateLimit(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 4
+	cfg.Orchestration.RateLimits = map[string]config.RateLimitConfig{
+		"github-api": {Limit: 20, Per: time.Second, Burst: 1},
+	}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	_, err = eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "sync",
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function", RateLimits: []string{"gitlab-api"}}},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+	if !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request for an unknown rate limit, got %v", err)
+	}
+
+	noop := func(context.Context) error { return nil }
+	calling := func(ctx context.Context) error { return WaitRateLimit(ctx, "github-api", 1) }
+	start := time.Now()
+	var ids []string
+	for i := 0; i < 2; i++ {
+		status, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+			Name: "sync",
+			Tasks: []models.TaskDefinition{
+				{ID: "a", Name: "a", Type: "function", RateLimits: []string{"github-api"}},
+				{ID: "b", Name: "b", Type: "function"},
+			},
+		}, SubmitWorkflowOptions{
+			Mode:    SubmissionModeAsync,
+			TaskFns: map[string]func(context.Context) error{"a": noop, "b": calling},
+		})
+		if err != nil {
+			t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+		}
+		ids = append(ids, status.ID)
+	}
+	for _, id := range ids {
+		if err := waitWorkflowStatus(eng, id, workflowStatusCompleted, 5*time.Second); err != nil {
+			t.Fatalf("workflow %s did not complete: %v", id, err)
+		}
+	}
+	// Four requests with a burst of one at 20/s take at least 150ms.
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Fatalf("four rate-limited requests took %v, want at least 150ms", elapsed)
+	}
+}
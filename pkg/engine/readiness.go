@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Startup phases of the engine, in the order Start completes them. The
+// engine is ready once all of them are done.
+const (
+	// PhaseStorage is done once storage, opened and migrated before the
+	// engine is created, answers reads.
+	PhaseStorage = "storage"
+	// PhaseLanes is done once the default lane accepts tasks.
+	PhaseLanes = "lanes"
+	// PhaseRecovery is done once the workflows and sagas interrupted by the
+	// last shutdown have been resubmitted.
+	PhaseRecovery = "recovery"
+)
+
+var startupPhases = []string{PhaseStorage, PhaseLanes, PhaseRecovery}
+
+// drainPollInterval is how often Drain checks for running workflows.
+const drainPollInterval = 100 * time.Millisecond
+
+// ReadinessPhase is the state of one startup phase.
+type ReadinessPhase struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+	// CompletedAt is when the phase was done.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Warning notes errors the phase completed despite, such as workflows
+	// that could not be recovered.
+	Warning string `json:"warning,omitempty"`
+}
+
+// Readiness reports whether the engine should receive traffic and why not.
+type Readiness struct {
+	Ready bool `json:"ready"`
+	// Draining is set once Drain was called; a draining engine is not ready.
+	Draining bool             `json:"draining,omitempty"`
+	Phases   []ReadinessPhase `json:"phases"`
+	// RunningWorkflows is the number of workflows executing on this node.
+	RunningWorkflows int `json:"running_workflows"`
+}
+
+// readiness tracks the startup phases and drain state of an engine.
+type readiness struct {
+	mu       sync.Mutex
+	done     map[string]time.Time
+	warnings map[string]string
+	draining atomic.Bool
+}
+
+func newReadiness() *readiness {
+	return &readiness{done: make(map[string]time.Time), warnings: make(map[string]string)}
+}
+
+// complete marks phase done, with warning if it completed despite errors.
+func (r *readiness) complete(phase, warning string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done[phase] = time.Now().UTC()
+	if warning != "" {
+		r.warnings[phase] = warning
+	}
+}
+
+// reset forgets the completed phases, for a restart of the engine.
+func (r *readiness) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.done)
+	clear(r.warnings)
+	r.draining.Store(false)
+}
+
+func (r *readiness) phases() ([]ReadinessPhase, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	phases := make([]ReadinessPhase, 0, len(startupPhases))
+	all := true
+	for _, name := range startupPhases {
+		phase := ReadinessPhase{Name: name, Warning: r.warnings[name]}
+		if at, ok := r.done[name]; ok {
+			phase.Done = true
+			phase.CompletedAt = &at
+		} else {
+			all = false
+		}
+		phases = append(phases, phase)
+	}
+	return phases, all
+}
+
+// Readiness returns the startup phases and drain state of the engine.
+func (e *Engine) Readiness() Readiness {
+	phases, all := e.readiness.phases()
+	draining := e.readiness.draining.Load()
+	return Readiness{
+		Ready:            all && !draining && engineState(e.state.Load()) == stateRunning && e.laneManager != nil,
+		Draining:         draining,
+		Phases:           phases,
+		RunningWorkflows: e.runningWorkflows(),
+	}
+}
+
+// Drain marks the engine not ready, so load balancers stop routing to it,
+// and waits until the workflows executing on this node finish or ctx is
+// done. It returns the number of workflows still running. The engine keeps
+// accepting requests that reach it while draining.
+func (e *Engine) Drain(ctx context.Context) int {
+	if !e.readiness.draining.Swap(true) {
+		e.logger.Info("engine draining", "running_workflows", e.runningWorkflows())
+		e.runHooksLogged(ctx, "drain", e.hooks.onDrain)
+	}
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		running := e.runningWorkflows()
+		if running == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return running
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) runningWorkflows() int {
+	e.execMu.RLock()
+	defer e.execMu.RUnlock()
+	return len(e.executions)
+}
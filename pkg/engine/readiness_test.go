@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_ReadinessPhases(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if r := eng.Readiness(); r.Ready || len(r.Phases) != 3 || r.Phases[0].Done {
+		t.Fatalf("Readiness() before Start = %+v, want no phase done", r)
+	}
+
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	r := eng.Readiness()
+	if !r.Ready {
+		t.Fatalf("Readiness() after Start = %+v, want ready", r)
+	}
+	for i, name := range []string{PhaseStorage, PhaseLanes, PhaseRecovery} {
+		if r.Phases[i].Name != name || !r.Phases[i].Done || r.Phases[i].CompletedAt == nil {
+			t.Errorf("phase %d = %+v, want %s done", i, r.Phases[i], name)
+		}
+	}
+}
+
+func TestEngine_DrainWaitsForRunningWorkflows(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "slow",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "t1", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"t1": func(ctx context.Context) error {
+				close(started)
+				<-release
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	<-started
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if running := eng.Drain(timeoutCtx); running != 1 {
+		t.Fatalf("Drain() with a running workflow = %d, want 1", running)
+	}
+	if r := eng.Readiness(); r.Ready || !r.Draining {
+		t.Fatalf("Readiness() while draining = %+v, want draining and not ready", r)
+	}
+
+	close(release)
+	waitCtx, cancelWait := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelWait()
+	if running := eng.Drain(waitCtx); running != 0 {
+		t.Fatalf("Drain() after the workflow finished = %d, want 0", running)
+	}
+}
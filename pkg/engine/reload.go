@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+// ConfigReloader returns the reloader that applies runtime configuration changes.
+// Callers may register additional appliers for components owned outside the engine.
+func (e *Engine) ConfigReloader() *config.Reloader {
+	return e.reloader
+}
+
+// applyRuntimeConfig applies hot-reloadable orchestration settings to the
+// default lane and the named lanes.
+func (e *Engine) applyRuntimeConfig(cfg *config.Config, changes []config.FieldChange) error {
+	var concurrencyChanged, rateLimitChanged bool
+	var lanesChange *config.FieldChange
+	for i, change := range changes {
+		switch change.Key {
+		case "orchestration.max_agents":
+			concurrencyChanged = true
+		case "orchestration.queue.rate_limit":
+			rateLimitChanged = true
+		case "orchestration.lanes":
+			lanesChange = &changes[i]
+		}
+	}
+	if !concurrencyChanged && !rateLimitChanged && lanesChange == nil {
+		return nil
+	}
+
+	// Before Start the new values are picked up when the lanes are created.
+	if e.laneManager == nil {
+		return nil
+	}
+	if lanesChange != nil {
+		old, _ := lanesChange.Old.(map[string]config.NamedLaneConfig)
+		if err := e.applyNamedLanes(old, cfg.Orchestration.Lanes); err != nil {
+			return err
+		}
+	}
+	if !concurrencyChanged && !rateLimitChanged {
+		return nil
+	}
+
+	defaultLane, err := e.laneManager.GetLane(defaultLaneName)
+	if err != nil {
+		return err
+	}
+
+	if concurrencyChanged {
+		resizable, ok := defaultLane.(interface{ SetMaxConcurrency(int) error })
+		if !ok {
+			return fmt.Errorf("lane %s does not support runtime concurrency changes", defaultLaneName)
+		}
+		if err := resizable.SetMaxConcurrency(cfg.Orchestration.MaxAgents); err != nil {
+			return err
+		}
+	}
+	if rateLimitChanged {
+		limited, ok := defaultLane.(interface{ SetRateLimit(float64) error })
+		if !ok {
+			return fmt.Errorf("lane %s does not support runtime rate limit changes", defaultLaneName)
+		}
+		if err := limited.SetRateLimit(cfg.Orchestration.Queue.RateLimit); err != nil {
+			return err
+		}
+	}
+
+	e.logger.Info("runtime config applied",
+		"max_agents", cfg.Orchestration.MaxAgents,
+		"rate_limit", cfg.Orchestration.Queue.RateLimit,
+	)
+	return nil
+}
+
+// applyNamedLanes creates the named lanes added to next and updates the
+// concurrency and rate limit of the others through the lane manager.
+// Removing a lane or changing its capacity requires a restart, so such a
+// change is rejected before any lane is touched.
+func (e *Engine) applyNamedLanes(old, next map[string]config.NamedLaneConfig) error {
+	for name, named := range old {
+		updated, ok := next[name]
+		if !ok {
+			return fmt.Errorf("removing lane %s requires restart", name)
+		}
+		if updated.Capacity != named.Capacity {
+			return fmt.Errorf("changing the capacity of lane %s requires restart", name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(next)) {
+		named := next[name]
+		if err := e.PutLane(name, named.Capacity, named.MaxConcurrency, named.RateLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
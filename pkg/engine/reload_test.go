@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_ConfigReloaderResizesDefaultLane(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Orchestration.MaxAgents = 4
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	result, err := eng.ConfigReloader().ApplyUpdates(map[string]string{
+		"orchestration.max_agents":       "8",
+		"orchestration.queue.rate_limit": "100",
+	})
+	if err != nil {
+		t.Fatalf("ApplyUpdates failed: %v", err)
+	}
+	if len(result.Applied) != 2 {
+		t.Fatalf("expected 2 applied changes, got applied=%+v rejected=%+v", result.Applied, result.Rejected)
+	}
+
+	defaultLane, err := eng.laneManager.GetLane(defaultLaneName)
+	if err != nil {
+		t.Fatalf("GetLane failed: %v", err)
+	}
+	if got := defaultLane.Stats().MaxConcurrency; got != 8 {
+		t.Errorf("expected default lane concurrency 8, got %d", got)
+	}
+}
+
+func TestEngine_ConfigReloaderAppliesNamedLanes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Orchestration.Lanes = map[string]config.NamedLaneConfig{"gpu": {Capacity: 10, MaxConcurrency: 2}}
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	maxConcurrency := func(name string) int {
+		t.Helper()
+		l, err := eng.laneManager.GetLane(name)
+		if err != nil {
+			t.Fatalf("GetLane(%s) failed: %v", name, err)
+		}
+		return l.Stats().MaxConcurrency
+	}
+	if got := maxConcurrency("gpu"); got != 2 {
+		t.Fatalf("expected gpu lane concurrency 2, got %d", got)
+	}
+
+	next := eng.ConfigReloader().Current()
+	next.Orchestration.Lanes = map[string]config.NamedLaneConfig{
+		"gpu":   {Capacity: 10, MaxConcurrency: 4, RateLimit: 5},
+		"batch": {Capacity: 50, MaxConcurrency: 1},
+	}
+	result, err := eng.ConfigReloader().Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != 1 || len(result.Rejected) != 0 {
+		t.Fatalf("expected the lanes to apply, got applied=%+v rejected=%+v", result.Applied, result.Rejected)
+	}
+	if got := maxConcurrency("gpu"); got != 4 {
+		t.Errorf("expected gpu lane concurrency 4, got %d", got)
+	}
+	if got := maxConcurrency("batch"); got != 1 {
+		t.Errorf("expected batch lane concurrency 1, got %d", got)
+	}
+
+	next = eng.ConfigReloader().Current()
+	next.Orchestration.Lanes = map[string]config.NamedLaneConfig{"gpu": {Capacity: 20, MaxConcurrency: 8}}
+	result, err = eng.ConfigReloader().Apply(next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Rejected) != 1 || !strings.Contains(result.Rejected[0].Reason, "requires restart") {
+		t.Fatalf("expected the lanes to be rejected, got applied=%+v rejected=%+v", result.Applied, result.Rejected)
+	}
+	if got := maxConcurrency("gpu"); got != 4 {
+		t.Errorf("expected gpu lane concurrency to stay 4, got %d", got)
+	}
+}
@@ -0,0 +1,517 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// DefaultReplayStepTimeout is how long a replayed transition waits for its
+// turn in the recorded order, while no other transition happens, before it is
+// let through out of order.
+const DefaultReplayStepTimeout = 2 * time.Second
+
+// errReplayedAttempt is returned by a stubbed attempt whose recorded failure
+// has no error message.
+var errReplayedAttempt = errors.New("replayed attempt failed")
+
+// ReplayReport describes the replay of a recorded workflow run.
+type ReplayReport struct {
+	WorkflowID string `json:"workflow_id"`
+	Name       string `json:"name"`
+
+	// RecordedStatus is the final status of the recorded run.
+	RecordedStatus string `json:"recorded_status"`
+
+	// ReplayedStatus is the final status of the replay.
+	ReplayedStatus string `json:"replayed_status"`
+
+	// Steps are the task transitions of the replay, in the order they
+	// happened.
+	Steps []ReplayStep `json:"steps"`
+
+	// Divergences lists where the replay departed from the recording.
+	Divergences []ReplayDivergence `json:"divergences,omitempty"`
+}
+
+// Reproduced reports whether the replay followed the recording exactly.
+func (r *ReplayReport) Reproduced() bool {
+	return len(r.Divergences) == 0 && r.RecordedStatus == r.ReplayedStatus
+}
+
+// ReplayStep is one task transition observed during a replay.
+type ReplayStep struct {
+	// Seq is the journal sequence number the transition matched, or 0 when
+	// it matched none.
+	Seq    int    `json:"seq"`
+	TaskID string `json:"task_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReplayDivergence is a point where a replay departed from the recording.
+type ReplayDivergence struct {
+	// Seq is the journal entry the recording expected next.
+	Seq int `json:"seq,omitempty"`
+
+	// Expected describes the transition the recording expected, or is empty
+	// when the journal had no more entries.
+	Expected string `json:"expected,omitempty"`
+
+	// Observed describes the transition that happened instead, or is empty
+	// when the expected transition never happened.
+	Observed string `json:"observed,omitempty"`
+}
+
+// ReplayOptions configures ReplayWorkflowWith.
+type ReplayOptions struct {
+	// StepTimeout is how long a transition waits for its turn in the
+	// recorded order. Zero uses DefaultReplayStepTimeout.
+	StepTimeout time.Duration
+
+	// TaskFns re-execute the tasks they name instead of stubbing them. Each
+	// attempt is fed back what its recording decided through TaskNow,
+	// TaskRandom and RouteLLMCall, and departures of its decisions, of the
+	// upstream results it reads and of its result from the recording are
+	// reported as divergences.
+	TaskFns map[string]func(context.Context) error
+}
+
+// replayKey is the context key of the replayAttempt of a re-executed task.
+type replayKey struct{}
+
+// ReplayWorkflow re-executes the finished run workflowID from its execution
+// journal, with every task stubbed; see ReplayWorkflowWith.
+func (e *Engine) ReplayWorkflow(ctx context.Context, workflowID string, stepTimeout time.Duration) (*ReplayReport, error) {
+	return e.ReplayWorkflowWith(ctx, workflowID, ReplayOptions{StepTimeout: stepTimeout})
+}
+
+// ReplayWorkflowWith re-executes the finished run workflowID from its
+// execution journal. Executors are replaced by stubs returning the recorded
+// outcome and result of each attempt, unless opts re-executes the task,
+// tasks run in private lanes, and every task transition waits until it is
+// next in the journal, so the recorded scheduling order is reproduced. A
+// transition that does not come up within the step timeout proceeds out of
+// order and is reported as a divergence. Results are fed back as recorded,
+// so that the tasks downstream read what they read in the recording; results
+// of sensitive tasks were not recorded and read as nil. The engine does not
+// need to be started.
+func (e *Engine) ReplayWorkflowWith(ctx context.Context, workflowID string, opts ReplayOptions) (*ReplayReport, error) {
+	wfState, err := e.storage.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTerminalWorkflowStatus(wfState.Status) {
+		return nil, &WorkflowNotReplayableError{WorkflowID: workflowID, Reason: fmt.Sprintf("workflow is %s", wfState.Status)}
+	}
+	if len(wfState.Journal) == 0 {
+		return nil, &WorkflowNotReplayableError{WorkflowID: workflowID, Reason: "no execution journal was recorded"}
+	}
+	stepTimeout := opts.StepTimeout
+	if stepTimeout <= 0 {
+		stepTimeout = DefaultReplayStepTimeout
+	}
+
+	attempts := recordedAttempts(wfState.Journal)
+	stubs := newReplayStubs(attempts, opts.TaskFns)
+	wf := e.workflowFromState(wfState, stubs.taskFns(wfState))
+
+	laneManager := lane.NewManager()
+	defer laneManager.Close(context.Background())
+
+	laneSizes := make(map[string]int)
+	g := dag.NewGraph()
+	for _, t := range wf.Tasks {
+		if t.Lane == "" {
+			t.Lane = defaultLaneName
+		}
+		// Stubs return at once, and timing is dictated by the journal.
+		t.Timeout = 0
+		t.Deadline = time.Time{}
+		t.Resources = dag.Resources{}
+		t.Retries = max(t.Retries, len(attempts[t.ID])-1)
+		laneSizes[t.Lane]++
+		if err := g.AddTask(t); err != nil {
+			return nil, &WorkflowCompileError{WorkflowID: wf.ID, Cause: err}
+		}
+	}
+	plan, err := g.Compile()
+	if err != nil {
+		return nil, &WorkflowCompileError{WorkflowID: wf.ID, Cause: err}
+	}
+	for name, size := range laneSizes {
+		if _, err := laneManager.Register(&lane.Config{Name: name, Capacity: size, MaxConcurrency: size}); err != nil {
+			return nil, fmt.Errorf("failed to register replay lane %s: %w", name, err)
+		}
+	}
+
+	// Results are fed back into a private copy of the run's state, which
+	// cannot be written to storage.
+	exec := &workflowExecution{workflowID: wfState.ID, wfState: replayState(wfState)}
+	seq := newReplaySequencer(wfState.Journal, stepTimeout)
+	tracker := newStateTracker()
+	taskIDs := make([]string, 0, len(wf.Tasks))
+	for _, t := range wf.Tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	tracker.InitTasks(taskIDs)
+	tracker.SetOnStateChange(func(taskID string, oldState, newState TaskState, result TaskResult) {
+		from, to := mapTaskStateToStatus(oldState), mapTaskStateToStatus(newState)
+		if to == "" || from == to {
+			return
+		}
+		step := ReplayStep{TaskID: taskID, From: from, To: to}
+		if result.Error != nil {
+			step.Error = result.Error.Error()
+		}
+		seq.await(ctx, step)
+		exec.mu.Lock()
+		if taskState, ok := exec.wfState.TaskStatus[taskID]; ok {
+			taskState.Status = to
+		}
+		exec.mu.Unlock()
+	})
+
+	sched := newScheduler(tracker, e.logger, nil, laneManager)
+	sched.priority = wf.Priority
+	sched.gangLayers = wf.GangLayers
+	sched.values = &workflowValues{engine: e, exec: exec}
+	sched.outputs = &workflowOutputs{exec: exec}
+	sched.workflowID = wfState.ID
+	err = sched.Schedule(ctx, plan, wf.TaskFns)
+
+	report := &ReplayReport{
+		WorkflowID:     wfState.ID,
+		Name:           wfState.Name,
+		RecordedStatus: wfState.Status,
+		ReplayedStatus: workflowStatusCompleted,
+	}
+	switch {
+	case ctx.Err() != nil:
+		report.ReplayedStatus = workflowStatusCancelled
+	case errors.Is(err, context.Canceled):
+		report.ReplayedStatus = workflowStatusCancelled
+	case err != nil:
+		report.ReplayedStatus = workflowStatusFailed
+	}
+	report.Steps, report.Divergences = seq.finish()
+	report.Divergences = append(report.Divergences, stubs.finish()...)
+	return report, nil
+}
+
+// replayState returns a copy of the recorded run wfState with its tasks
+// pending and their results cleared, to feed recorded results back into.
+func replayState(wfState *storage.WorkflowState) *storage.WorkflowState {
+	state := &storage.WorkflowState{
+		ID:         wfState.ID,
+		Name:       wfState.Name,
+		Status:     wfState.Status,
+		Metadata:   maps.Clone(wfState.Metadata),
+		Values:     maps.Clone(wfState.Values),
+		TaskStatus: make(map[string]*storage.TaskState, len(wfState.TaskStatus)),
+	}
+	for id, taskState := range wfState.TaskStatus {
+		state.TaskStatus[id] = &storage.TaskState{ID: id, Name: taskState.Name, Status: taskStatusPending, Sensitive: taskState.Sensitive}
+	}
+	return state
+}
+
+// recordedAttempt is a task attempt of the journal.
+type recordedAttempt struct {
+	// entry is the journal entry ending the attempt.
+	entry storage.JournalEntry
+	// outcome is nil for success, otherwise the error the attempt ended with.
+	outcome error
+}
+
+// replayed reports whether the recorded output of the attempt can be fed
+// back.
+func (a recordedAttempt) replayed() bool {
+	return a.outcome == nil && a.entry.Output != nil && a.entry.Output != models.RedactedValue
+}
+
+// recordedAttempts returns, per task, the recorded attempts in order.
+func recordedAttempts(journal []storage.JournalEntry) map[string][]recordedAttempt {
+	attempts := make(map[string][]recordedAttempt)
+	for _, entry := range journal {
+		if entry.From != taskStatusRunning {
+			continue
+		}
+		var outcome error
+		switch entry.To {
+		case taskStatusCompleted:
+		case taskStatusCancelled:
+			outcome = context.Canceled
+		default:
+			outcome = errReplayedAttempt
+			if entry.Error != "" {
+				outcome = errors.New(entry.Error)
+			}
+		}
+		attempts[entry.TaskID] = append(attempts[entry.TaskID], recordedAttempt{entry: entry, outcome: outcome})
+	}
+	return attempts
+}
+
+// replayStubs stands in for task executors during a replay, and feeds the
+// recording back to the tasks re-executed.
+type replayStubs struct {
+	mu       sync.Mutex
+	attempts map[string][]recordedAttempt
+	// fns are the functions of the tasks re-executed, by task ID.
+	fns map[string]func(context.Context) error
+	// started counts the attempts of each task.
+	started     map[string]int
+	divergences []ReplayDivergence
+}
+
+func newReplayStubs(attempts map[string][]recordedAttempt, fns map[string]func(context.Context) error) *replayStubs {
+	return &replayStubs{attempts: attempts, fns: fns}
+}
+
+// taskFns returns a function per task that replays its recorded attempts:
+// a stub returning the recorded outcome and result, or the task's function
+// with the recording fed back. Attempts beyond the recording of stubbed
+// tasks succeed.
+func (s *replayStubs) taskFns(wfState *storage.WorkflowState) map[string]func(context.Context) error {
+	fns := make(map[string]func(context.Context) error, len(wfState.Tasks))
+	for _, t := range wfState.Tasks {
+		taskID := t.ID
+		fn := s.fns[taskID]
+		fns[taskID] = func(ctx context.Context) error {
+			recorded, number := s.next(taskID)
+			out, _ := ctx.Value(outputKey{}).(*taskOutput)
+			if fn == nil {
+				if recorded.replayed() && out != nil {
+					out.set(recorded.entry.Output)
+				}
+				return recorded.outcome
+			}
+			attempt := &replayAttempt{stubs: s, taskID: taskID, number: number, recorded: recorded, decisions: recorded.entry.Decisions}
+			err := fn(context.WithValue(ctx, replayKey{}, attempt))
+			attempt.finish(out, err)
+			return err
+		}
+	}
+	return fns
+}
+
+// next returns the next recorded attempt of taskID, the zero attempt beyond
+// the recording, and its number, starting at 1.
+func (s *replayStubs) next(taskID string) (recordedAttempt, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started == nil {
+		s.started = make(map[string]int)
+	}
+	s.started[taskID]++
+	attempts := s.attempts[taskID]
+	if len(attempts) == 0 {
+		return recordedAttempt{}, s.started[taskID]
+	}
+	s.attempts[taskID] = attempts[1:]
+	return attempts[0], s.started[taskID]
+}
+
+func (s *replayStubs) diverge(divergence ReplayDivergence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.divergences = append(s.divergences, divergence)
+}
+
+// finish returns where the re-executed attempts departed from the
+// recording.
+func (s *replayStubs) finish() []ReplayDivergence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.divergences
+}
+
+// replayAttempt feeds the recording of an attempt back to its
+// re-execution.
+type replayAttempt struct {
+	stubs    *replayStubs
+	taskID   string
+	number   int
+	recorded recordedAttempt
+
+	mu sync.Mutex
+	// decisions are the recorded decisions not yet made again.
+	decisions []storage.JournalDecision
+	// diverged stops feeding back decisions once the attempt departed from
+	// the recorded ones.
+	diverged bool
+}
+
+// decision returns the next recorded decision when it is of kind, and
+// reports a divergence otherwise.
+func (a *replayAttempt) decision(kind string) (json.RawMessage, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.diverged {
+		return nil, false
+	}
+	if len(a.decisions) == 0 || a.decisions[0].Kind != kind {
+		a.diverged = true
+		expected := "no more decisions"
+		if len(a.decisions) > 0 {
+			expected = a.decisions[0].Kind + " decision"
+		}
+		a.diverge(expected, kind+" decision")
+		return nil, false
+	}
+	decision := a.decisions[0]
+	a.decisions = a.decisions[1:]
+	return decision.Value, true
+}
+
+// finish compares the ended attempt, with output out and error err, to the
+// recording, and feeds the recorded result back. Attempts beyond the
+// recording are reported by the sequencer.
+func (a *replayAttempt) finish(out *taskOutput, err error) {
+	a.mu.Lock()
+	if !a.diverged && len(a.decisions) > 0 {
+		a.diverge(a.decisions[0].Kind+" decision", "no more decisions")
+	}
+	a.mu.Unlock()
+	if out == nil || a.recorded.entry.Seq == 0 {
+		return
+	}
+
+	out.mu.Lock()
+	inputs, value := out.inputs, out.value
+	out.mu.Unlock()
+	if expected, observed := replayJSON(a.recorded.entry.Inputs), replayJSON(inputs); expected != observed {
+		a.diverge("inputs "+expected, "inputs "+observed)
+	}
+	if err != nil || a.recorded.outcome != nil || a.recorded.entry.Output == models.RedactedValue {
+		return
+	}
+	if expected, observed := replayJSON(a.recorded.entry.Output), replayJSON(value); expected != observed {
+		a.diverge("output "+expected, "output "+observed)
+	}
+	out.set(a.recorded.entry.Output)
+}
+
+func (a *replayAttempt) diverge(expected, observed string) {
+	prefix := fmt.Sprintf("%s attempt %d ", a.taskID, a.number)
+	a.stubs.diverge(ReplayDivergence{Seq: a.recorded.entry.Seq, Expected: prefix + expected, Observed: prefix + observed})
+}
+
+// replayJSON returns the JSON encoding values are compared in.
+func replayJSON(value any) string {
+	if m, ok := value.(map[string]any); ok && len(m) == 0 {
+		value = nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// replaySequencer releases task transitions in journal order.
+type replaySequencer struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	// pending are the journal entries not yet replayed.
+	pending []storage.JournalEntry
+	// changed is closed and replaced whenever pending shrinks.
+	changed     chan struct{}
+	steps       []ReplayStep
+	divergences []ReplayDivergence
+}
+
+func newReplaySequencer(journal []storage.JournalEntry, timeout time.Duration) *replaySequencer {
+	return &replaySequencer{
+		timeout: timeout,
+		pending: append([]storage.JournalEntry(nil), journal...),
+		changed: make(chan struct{}),
+	}
+}
+
+// await blocks until step is the next transition in the journal. When the
+// replay makes no progress for the timeout, or ctx is done, step proceeds out
+// of order and a divergence is recorded.
+func (s *replaySequencer) await(ctx context.Context, step ReplayStep) {
+	for stalled := false; !stalled; {
+		s.mu.Lock()
+		if len(s.pending) > 0 && journalMatches(s.pending[0], step) {
+			step.Seq = s.pending[0].Seq
+			s.consume(0, step)
+			s.mu.Unlock()
+			return
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		timer := time.NewTimer(s.timeout)
+		select {
+		case <-changed:
+		case <-timer.C:
+			stalled = true
+		case <-ctx.Done():
+			stalled = true
+		}
+		timer.Stop()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	divergence := ReplayDivergence{Observed: describeStep(step)}
+	if len(s.pending) > 0 {
+		divergence.Seq = s.pending[0].Seq
+		divergence.Expected = describeEntry(s.pending[0])
+	}
+	s.divergences = append(s.divergences, divergence)
+	for i, entry := range s.pending {
+		if journalMatches(entry, step) {
+			step.Seq = entry.Seq
+			s.consume(i, step)
+			return
+		}
+	}
+	s.steps = append(s.steps, step)
+}
+
+// consume removes pending entry i, replayed as step. Callers hold s.mu.
+func (s *replaySequencer) consume(i int, step ReplayStep) {
+	s.pending = append(s.pending[:i], s.pending[i+1:]...)
+	s.steps = append(s.steps, step)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// finish returns the replayed steps and divergences, reporting journal
+// entries that were never replayed.
+func (s *replaySequencer) finish() ([]ReplayStep, []ReplayDivergence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.pending {
+		s.divergences = append(s.divergences, ReplayDivergence{Seq: entry.Seq, Expected: describeEntry(entry)})
+	}
+	s.pending = nil
+	return s.steps, s.divergences
+}
+
+func journalMatches(entry storage.JournalEntry, step ReplayStep) bool {
+	return entry.TaskID == step.TaskID && entry.From == step.From && entry.To == step.To
+}
+
+func describeEntry(entry storage.JournalEntry) string {
+	return fmt.Sprintf("%s %s -> %s", entry.TaskID, entry.From, entry.To)
+}
+
+func describeStep(step ReplayStep) string {
+	return fmt.Sprintf("%s %s -> %s", step.TaskID, step.From, step.To)
+}
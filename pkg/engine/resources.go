@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/lane"
+)
+
+// resourceLane is implemented by lanes that place tasks by resource requests.
+type resourceLane interface {
+	ResourceCapacity() (lane.Resources, bool)
+}
+
+// laneResources converts a task's resource request into its lane form.
+func laneResources(r dag.Resources) lane.Resources {
+	return lane.Resources{CPU: r.CPU, MemoryMB: r.MemoryMB, GPU: r.GPU}
+}
+
+// checkPlanResources rejects plans with a task, or a gang, that requests more
+// resources than its lane provides, since it could never be placed. Lanes
+// that do not track resources accept any request.
+func checkPlanResources(laneManager *lane.Manager, plan *dag.ExecutionPlan, gangLayers bool) error {
+	if laneManager == nil {
+		return nil
+	}
+	for layerIdx, layer := range plan.Layers {
+		gangs := make(map[string]lane.Resources)
+		var gangOrder []string
+		gangLanes := make(map[string]string)
+		for _, taskID := range layer {
+			task, ok := plan.GetTask(taskID)
+			if !ok {
+				continue
+			}
+			request := laneResources(task.Resources)
+			if err := checkLaneResources(laneManager, task.Lane, taskID, request); err != nil {
+				return err
+			}
+			if gang := gangKey(task, layerIdx, task.Lane, gangLayers); gang != "" {
+				if _, ok := gangs[gang]; !ok {
+					gangOrder = append(gangOrder, gang)
+					gangLanes[gang] = task.Lane
+				}
+				gangs[gang] = gangs[gang].Add(request)
+			}
+		}
+		for _, gang := range gangOrder {
+			if err := checkLaneResources(laneManager, gangLanes[gang], gang, gangs[gang]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkLaneResources checks that request fits in the capacity of laneName.
+func checkLaneResources(laneManager *lane.Manager, laneName, taskID string, request lane.Resources) error {
+	if request.IsZero() {
+		return nil
+	}
+	l, err := laneManager.GetLane(laneName)
+	if err != nil {
+		return nil
+	}
+	rl, ok := l.(resourceLane)
+	if !ok {
+		return nil
+	}
+	capacity, ok := rl.ResourceCapacity()
+	if !ok || request.Fits(capacity) {
+		return nil
+	}
+	return &lane.ResourceError{LaneName: laneName, TaskID: taskID, Requested: request, Capacity: capacity}
+}
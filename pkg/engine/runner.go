@@ -3,9 +3,11 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -17,22 +19,63 @@ type taskRunner struct {
 	task    *dag.Task
 	tracker *StateTracker
 	fn      func(ctx context.Context) error
+
+	// priority is the owning workflow's priority.
+	priority int
+	// inherited is the priority the task inherited from tasks queued behind
+	// it; attempts are only preemptible by work above the higher of the two.
+	inherited int
+	// preemptor tracks preemptible attempts; nil disables preemption.
+	preemptor *preemptor
+	// env resolves the task's environment bundles and secrets; nil skips
+	// them.
+	env *envResolver
+	// outputs stores the result each successful attempt sets; nil drops
+	// results.
+	outputs *workflowOutputs
+	// exprs evaluates the task's condition, retry condition and config; nil
+	// runs the task unconditionally with its config unevaluated.
+	exprs *workflowExpressions
+	// middleware wraps each attempt, the first outermost.
+	middleware []TaskMiddleware
+	// workflowID is the ID of the task's workflow, for middleware.
+	workflowID string
+	// traced records a span per execution.
+	traced bool
 }
 
 // newTaskRunner creates a taskRunner for the given dag.Task.
 // fn is the actual work function; pass nil to use a no-op (useful in tests).
 func newTaskRunner(task *dag.Task, tracker *StateTracker, fn func(ctx context.Context) error) *taskRunner {
 	if fn == nil {
-		fn = func(ctx context.Context) error { return nil }
+		fn = noopTaskFn
+	}
+	return &taskRunner{task: task, tracker: tracker, fn: fn, traced: true}
+}
+
+func noopTaskFn(context.Context) error { return nil }
+
+// acquireLocks waits for the locks the task declares. They are released with
+// the task's other locks once it finishes.
+func (r *taskRunner) acquireLocks(ctx context.Context) error {
+	if len(r.task.Locks) == 0 {
+		return nil
+	}
+	locks, ok := ctx.Value(locksKey{}).(*taskLocks)
+	if !ok {
+		return errs.New(errs.BadRequest, "locks are only available to tasks run by the engine")
 	}
-	return &taskRunner{task: task, tracker: tracker, fn: fn}
+	if err := locks.acquireAll(ctx, r.task.Locks); err != nil {
+		return fmt.Errorf("acquire task locks: %w", err)
+	}
+	return nil
 }
 
 // ID implements lane.Task.
 func (r *taskRunner) ID() string { return r.task.ID }
 
 // Priority implements lane.Task.
-func (r *taskRunner) Priority() int { return 1 }
+func (r *taskRunner) Priority() int { return r.priority }
 
 // Lane implements lane.Task.
 func (r *taskRunner) Lane() string {
@@ -43,41 +86,141 @@ func (r *taskRunner) Lane() string {
 }
 
 // Execute runs the task function with retry logic and updates the StateTracker.
+// A preempted attempt moves the task back to scheduled and returns
+// errTaskPreempted without consuming a retry; the caller requeues it. An
+// attempt abandoned for missing heartbeats fails with ErrTaskStalled and is
+// retried unless the task's stall policy is StallPolicyFail. The task's
+// environment is resolved once per Execute, and secret values in its errors
+// are redacted. The result of a successful attempt is checked against the
+// task's output schema first; a violation fails the task with
+// ErrOutputSchemaViolation without retrying it. A task whose condition is
+// false completes as skipped without running, and a failed attempt is only
+// retried while the task's retry condition holds. Each attempt runs through
+// the engine's task middleware.
 func (r *taskRunner) Execute(ctx context.Context) error {
-	ctx, span := runtimeTracer().Start(ctx, spanTaskRun)
-	span.SetAttributes(
-		attribute.String("task.id", r.task.ID),
-		attribute.String("lane.name", r.Lane()),
-		attribute.Int("task.max_retries", r.task.Retries),
-	)
-	defer span.End()
+	span := noopSpan
+	if r.traced {
+		ctx, span = runtimeTracer().Start(ctx, spanTaskRun)
+		span.SetAttributes(
+			attribute.String("task.id", r.task.ID),
+			attribute.String("lane.name", r.Lane()),
+			attribute.Int("task.max_retries", r.task.Retries),
+		)
+		defer span.End()
+	}
+
+	if r.exprs != nil && r.task.When != "" {
+		run, err := r.exprs.when(r.task)
+		r.tracker.SetState(r.task.ID, TaskStateRunning)
+		if err != nil {
+			err = fmt.Errorf("evaluate task condition: %w", err)
+			r.tracker.SetFailed(r.task.ID, err, 0)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, "failed")
+			return &TaskExecutionError{TaskID: r.task.ID, Cause: err}
+		}
+		if !run {
+			r.tracker.SetSkipped(r.task.ID)
+			span.AddEvent("task.skipped")
+			span.SetStatus(otelcodes.Ok, "skipped")
+			return nil
+		}
+	}
+
+	ctx, redact, err := r.withTaskEnv(ctx)
+	if err != nil {
+		err = fmt.Errorf("resolve task environment: %w", err)
+		r.tracker.SetState(r.task.ID, TaskStateRunning)
+		r.tracker.SetFailed(r.task.ID, err, 0)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "failed")
+		return &TaskExecutionError{TaskID: r.task.ID, Cause: err}
+	}
+	if err := r.acquireLocks(ctx); err != nil {
+		r.tracker.SetState(r.task.ID, TaskStateRunning)
+		span.RecordError(err)
+		if ctx.Err() != nil {
+			r.tracker.SetState(r.task.ID, TaskStateCancelled)
+			span.SetStatus(otelcodes.Error, "cancelled")
+		} else {
+			r.tracker.SetFailed(r.task.ID, err, 0)
+			span.SetStatus(otelcodes.Error, "failed")
+		}
+		return &TaskExecutionError{TaskID: r.task.ID, Cause: err}
+	}
 
 	maxAttempts := r.task.Retries + 1
 	var lastErr error
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		span.AddEvent(
-			"task.attempt",
-			trace.WithAttributes(attribute.Int("task.attempt", attempt+1)),
-		)
+		if r.traced {
+			span.AddEvent(
+				"task.attempt",
+				trace.WithAttributes(attribute.Int("task.attempt", attempt+1)),
+			)
+		}
 		if attempt > 0 {
-			r.tracker.SetState(r.task.ID, TaskStateRetrying)
+			r.tracker.SetRetrying(r.task.ID, lastErr)
 		}
 		r.tracker.SetState(r.task.ID, TaskStateRunning)
+		// Rate limits are waited for before the attempt's timeout and
+		// heartbeat checks start.
+		startErr := r.waitRateLimits(ctx)
 
-		// Apply per-task timeout if configured.
 		runCtx := ctx
+		var release func()
+		if r.task.Preemptible && r.preemptor != nil {
+			runCtx, release = r.preemptor.track(ctx, r.task.ID, r.Lane(), max(r.priority, r.inherited))
+		}
+		var unwatch func()
+		if r.task.HeartbeatTimeout > 0 {
+			runCtx, unwatch = r.watchHeartbeat(runCtx)
+		}
+
+		// Apply per-task timeout if configured.
 		var cancel context.CancelFunc
 		if r.task.Timeout > 0 {
-			runCtx, cancel = context.WithTimeout(ctx, r.task.Timeout)
+			runCtx, cancel = context.WithTimeout(runCtx, r.task.Timeout)
+		}
+		var out *taskOutput
+		if r.outputs != nil {
+			runCtx, out = r.outputs.withTask(runCtx, r.task)
+		}
+		if startErr == nil && r.exprs != nil {
+			runCtx, startErr = r.exprs.withConfig(runCtx, r.task, attempt)
 		}
 
-		lastErr = r.fn(runCtx)
+		if startErr != nil {
+			lastErr = startErr
+		} else {
+			lastErr = redact.redactError(r.call(runCtx, attempt+1))
+		}
 		runCtxErr := runCtx.Err()
+		preempted := release != nil && isPreempted(runCtx)
+		stalled := unwatch != nil && isStalled(runCtx)
 
 		if cancel != nil {
 			cancel()
 		}
+		if unwatch != nil {
+			unwatch()
+		}
+		if release != nil {
+			release()
+		}
+
+		if preempted && ctx.Err() == nil {
+			r.tracker.SetPreempted(r.task.ID)
+			span.AddEvent("task.preempted")
+			return errTaskPreempted
+		}
+		if stalled && ctx.Err() == nil {
+			lastErr = ErrTaskStalled
+			span.AddEvent("task.stalled")
+			if r.task.StallPolicy == dag.StallPolicyFail {
+				break
+			}
+		}
 
 		if lastErr == nil {
 			if runCtxErr != nil {
@@ -88,6 +231,14 @@ func (r *taskRunner) Execute(ctx context.Context) error {
 				lastErr = ctx.Err()
 				break
 			}
+			if err := r.checkOutput(out); err != nil {
+				lastErr = err
+				span.AddEvent("task.output_invalid")
+				break
+			}
+			if out != nil {
+				out.store()
+			}
 			r.tracker.SetState(r.task.ID, TaskStateCompleted)
 			span.SetStatus(otelcodes.Ok, "completed")
 			return nil
@@ -98,6 +249,18 @@ func (r *taskRunner) Execute(ctx context.Context) error {
 			break
 		}
 
+		if attempt < maxAttempts-1 && r.exprs != nil && r.task.RetryIf != "" {
+			retry, err := r.exprs.retryIf(r.task, attempt, lastErr)
+			if err != nil {
+				lastErr = fmt.Errorf("%w (evaluate retry condition: %v)", lastErr, err)
+				break
+			}
+			if !retry {
+				span.AddEvent("task.retry_declined")
+				break
+			}
+		}
+
 		// Back off briefly between retries (simple fixed delay).
 		if attempt < maxAttempts-1 {
 			select {
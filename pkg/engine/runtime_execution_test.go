@@ -2,11 +2,20 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/goclaw/goclaw/config"
 	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/metrics"
 	"github.com/goclaw/goclaw/pkg/storage"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 )
@@ -191,6 +200,50 @@ func TestSubmitWorkflowRuntime_PersistsAndEmitsTransitions(t *testing.T) {
 	}
 }
 
+func TestSubmitWorkflowRuntime_LabelsRunMetrics(t *testing.T) {
+	metricsCfg := metrics.DefaultConfig()
+	metricsCfg.WorkflowLabel = true
+	metricsCfg.TenantLabel = true
+	recorder := metrics.NewManager(metricsCfg)
+
+	cfg := minConfig()
+	cfg.Orchestration.Costs.TenantKey = "tenant"
+	eng, err := New(cfg, nil, memory.NewMemoryStorage(), WithMetrics(recorder))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	_, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:     "etl",
+		Metadata: map[string]string{"tenant": "team-a"},
+		Tasks:    []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`workflow_submissions_total{status="completed",tenant="team-a",workflow="etl"} 1`,
+		`task_executions_total{status="completed",tenant="team-a",workflow="etl"} 1`,
+		`task_duration_seconds_count{tenant="team-a",workflow="etl"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in metrics output", want)
+		}
+	}
+}
+
 func TestSubmitWorkflowRuntime_CancelPrecedence(t *testing.T) {
 	cfg := minConfig()
 	store := memory.NewMemoryStorage()
@@ -243,6 +296,503 @@ func TestSubmitWorkflowRuntime_CancelPrecedence(t *testing.T) {
 	}
 }
 
+// racingStorage runs race once after the next GetWorkflow, as if another node
+// updated the workflow between the caller's read and write.
+type racingStorage struct {
+	*memory.MemoryStorage
+	race func()
+}
+
+func (s *racingStorage) GetWorkflow(ctx context.Context, id string) (*storage.WorkflowState, error) {
+	wf, err := s.MemoryStorage.GetWorkflow(ctx, id)
+	if race := s.race; race != nil {
+		s.race = nil
+		race()
+	}
+	return wf, err
+}
+
+func TestCancelWorkflowRequest_DetectsConcurrentUpdate(t *testing.T) {
+	store := &racingStorage{MemoryStorage: memory.NewMemoryStorage()}
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	// Without task functions the workflow stays pending, so the cancel is a
+	// read-modify-write of the stored record.
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "cancel-race",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "task-1", Type: "function"}},
+	}, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	store.race = func() {
+		wf, err := store.MemoryStorage.GetWorkflow(context.Background(), resp.ID)
+		if err != nil {
+			t.Errorf("GetWorkflow() error = %v", err)
+			return
+		}
+		wf.Status = workflowStatusRunning
+		if err := store.MemoryStorage.SaveWorkflow(context.Background(), wf); err != nil {
+			t.Errorf("SaveWorkflow() error = %v", err)
+		}
+	}
+	if err := eng.CancelWorkflowRequest(context.Background(), resp.ID); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("expected conflict cancelling a concurrently updated workflow, got %v", err)
+	}
+
+	wf, err := store.GetWorkflow(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if wf.Status != workflowStatusRunning {
+		t.Fatalf("workflow status = %s, want the concurrent update %s", wf.Status, workflowStatusRunning)
+	}
+	for _, task := range wf.TaskStatus {
+		if task.Status == taskStatusCancelled {
+			t.Fatalf("task %s cancelled by a conflicting cancel", task.ID)
+		}
+	}
+}
+
+func TestRetryWorkflowRequest(t *testing.T) {
+	cfg := minConfig()
+	store := memory.NewMemoryStorage()
+
+	eng, err := New(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	release := make(chan struct{})
+	req := &models.WorkflowRequest{
+		Name:     "retry-me",
+		Metadata: map[string]string{"team": "data"},
+		Tasks: []models.TaskDefinition{
+			{ID: "t1", Name: "task-1", Type: "function", Retries: 2, Config: map[string]interface{}{"url": "http://example"}},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), req, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"t1": func(ctx context.Context) error {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if err := waitWorkflowStatus(eng, resp.ID, workflowStatusRunning, 2*time.Second); err != nil {
+		t.Fatalf("workflow did not reach running state: %v", err)
+	}
+
+	if _, err := eng.RetryWorkflowRequest(context.Background(), resp.ID); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("expected conflict retrying a running workflow, got %v", err)
+	}
+
+	if err := eng.CancelWorkflowRequest(context.Background(), resp.ID); err != nil {
+		t.Fatalf("CancelWorkflowRequest() error = %v", err)
+	}
+	close(release)
+	if err := waitWorkflowStatus(eng, resp.ID, workflowStatusCancelled, 2*time.Second); err != nil {
+		t.Fatalf("workflow did not reach cancelled state: %v", err)
+	}
+
+	retried, err := eng.RetryWorkflowRequest(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("RetryWorkflowRequest() error = %v", err)
+	}
+	if retried.ID == resp.ID {
+		t.Fatal("expected retry to create a new workflow")
+	}
+
+	status, err := eng.GetWorkflowStatusResponse(context.Background(), retried.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	if status.Name != "retry-me" || status.Metadata["retry_of"] != resp.ID || status.Metadata["team"] != "data" {
+		t.Fatalf("unexpected retried workflow: %+v", status)
+	}
+	if len(status.Tasks) != 1 || status.Tasks[0].Type != "function" || status.Tasks[0].Retries != 2 || status.Tasks[0].Config["url"] != "http://example" {
+		t.Fatalf("expected task definition in status, got %+v", status.Tasks)
+	}
+}
+
+func TestSubmitWorkflowRuntime_DedupeKey(t *testing.T) {
+	cfg := minConfig()
+	store := memory.NewMemoryStorage()
+
+	eng, err := New(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var mu sync.Mutex
+	executed := make([]string, 0, 3)
+	record := func(id string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			executed = append(executed, id)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			return SetTaskResult(ctx, "users of "+id)
+		}
+	}
+
+	req := &models.WorkflowRequest{
+		Name: "dedupe",
+		Tasks: []models.TaskDefinition{
+			{ID: "fetch-a", Name: "fetch", Type: "function", DedupeKey: "fetch:users"},
+			{ID: "fetch-b", Name: "fetch", Type: "function", DedupeKey: "fetch:users"},
+			{ID: "refetch", Name: "fetch", Type: "function", DependsOn: []string{"fetch-a"}, DedupeKey: "fetch:users"},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), req, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"fetch-a": record("fetch-a"),
+			"fetch-b": record("fetch-b"),
+			"refetch": record("refetch"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s, want %s", resp.Status, workflowStatusCompleted)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(executed) != 1 {
+		t.Fatalf("expected one execution for the dedupe key, got %v", executed)
+	}
+	original := executed[0]
+
+	for _, task := range resp.Tasks {
+		if task.Status != taskStatusCompleted || task.DedupeKey != "fetch:users" {
+			t.Fatalf("unexpected task status: %+v", task)
+		}
+		want := original
+		if task.ID == original {
+			want = ""
+		}
+		if task.DedupedFrom != want {
+			t.Fatalf("task %s deduped_from = %q, want %q", task.ID, task.DedupedFrom, want)
+		}
+	}
+
+	stored, err := store.GetTask(context.Background(), resp.ID, "refetch")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stored.DedupedFrom != original || stored.DedupeKey != "fetch:users" {
+		t.Fatalf("expected dedupe tracked in storage, got %+v", stored)
+	}
+	for _, id := range []string{"fetch-a", "fetch-b", "refetch"} {
+		task, err := store.GetTask(context.Background(), resp.ID, id)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		if task.Result != "users of "+original {
+			t.Fatalf("task %s result = %v, want the result of %s", id, task.Result, original)
+		}
+	}
+
+	// A later run with the key shares the recorded execution.
+	mu.Unlock()
+	again, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "dedupe",
+		Tasks: []models.TaskDefinition{{ID: "fetch", Name: "fetch", Type: "function", DedupeKey: "fetch:users"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"fetch": record("fetch")},
+	})
+	mu.Lock()
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if len(executed) != 1 {
+		t.Fatalf("expected the later run to share the execution, got %v", executed)
+	}
+	if again.Status != workflowStatusCompleted || len(again.Tasks) != 1 {
+		t.Fatalf("unexpected later run: %+v", again)
+	}
+	if task := again.Tasks[0]; task.Status != taskStatusCompleted || task.DedupedFrom != resp.ID+"/"+original || task.Result != "users of "+original {
+		t.Fatalf("unexpected deduped task of the later run: %+v", task)
+	}
+}
+
+func TestSubmitWorkflowRuntime_GangStartsTogether(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.MaxAgents = 2
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	release := make(chan struct{})
+	blocker, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "blocker",
+		Tasks: []models.TaskDefinition{{ID: "hold", Name: "hold", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"hold": func(context.Context) error {
+				<-release
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if err := waitWorkflowStatus(eng, blocker.ID, workflowStatusRunning, 2*time.Second); err != nil {
+		t.Fatalf("blocker did not start: %v", err)
+	}
+
+	// Each member waits for the other, so a partial start would time out.
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	member := func(ctx context.Context) error {
+		barrier.Done()
+		waited := make(chan struct{})
+		go func() {
+			barrier.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("gang member started alone")
+		}
+	}
+	gang, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "gang",
+		Tasks: []models.TaskDefinition{
+			{ID: "w1", Name: "worker-1", Type: "function", Gang: "pair"},
+			{ID: "w2", Name: "worker-2", Type: "function", Gang: "pair"},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{"w1": member, "w2": member},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	status, err := eng.GetWorkflowStatusResponse(context.Background(), gang.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	for _, task := range status.Tasks {
+		if task.Status != taskStatusScheduled {
+			t.Fatalf("task %s status = %s while one worker is busy, want %s", task.ID, task.Status, taskStatusScheduled)
+		}
+	}
+
+	close(release)
+	if err := waitWorkflowStatus(eng, gang.ID, workflowStatusCompleted, 3*time.Second); err != nil {
+		t.Fatalf("gang workflow did not complete: %v", err)
+	}
+}
+
+func TestSubmitWorkflowRuntime_GangAcrossLayersFails(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "split-gang",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function", Gang: "pair"},
+			{ID: "b", Name: "b", Type: "function", Gang: "pair", DependsOn: []string{"a"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"a": func(context.Context) error { return nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, "same layer") {
+		t.Fatalf("expected failed workflow with gang error, got %s %q", resp.Status, resp.Error)
+	}
+}
+
+func TestSubmitWorkflowRuntime_ResourcesLimitConcurrency(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Queue.Resources = config.LaneResourcesConfig{GPU: 1}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var running, maxRunning atomic.Int32
+	gpuTask := func(context.Context) error {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	gpu := &models.TaskResources{GPU: 1}
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "gpu-jobs",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function", Resources: gpu},
+			{ID: "b", Name: "b", Type: "function", Resources: gpu},
+			{ID: "c", Name: "c", Type: "function", Resources: gpu},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": gpuTask, "b": gpuTask, "c": gpuTask},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("expected completed workflow, got %s %q", resp.Status, resp.Error)
+	}
+	if got := maxRunning.Load(); got != 1 {
+		t.Fatalf("max concurrent gpu tasks = %d, want 1", got)
+	}
+}
+
+func TestSubmitWorkflowRuntime_AffinityRunsSerially(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var running, maxRunning atomic.Int32
+	customerTask := func(context.Context) error {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "customer-sync",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function", AffinityKey: "customer-42"},
+			{ID: "b", Name: "b", Type: "function", AffinityKey: "customer-42"},
+			{ID: "c", Name: "c", Type: "function", AffinityKey: "customer-42"},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": customerTask, "b": customerTask, "c": customerTask},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("expected completed workflow, got %s %q", resp.Status, resp.Error)
+	}
+	if got := maxRunning.Load(); got != 1 {
+		t.Fatalf("max concurrent tasks with one affinity key = %d, want 1", got)
+	}
+}
+
+func TestSubmitWorkflowRuntime_ResourcesExceedLaneFails(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Queue.Resources = config.LaneResourcesConfig{CPU: 2, MemoryMB: 1024}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	tests := []struct {
+		name  string
+		tasks []models.TaskDefinition
+	}{
+		{
+			name:  "task",
+			tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function", Resources: &models.TaskResources{GPU: 1}}},
+		},
+		{
+			name: "gang",
+			tasks: []models.TaskDefinition{
+				{ID: "a", Name: "a", Type: "function", Gang: "pair", Resources: &models.TaskResources{CPU: 1.5}},
+				{ID: "b", Name: "b", Type: "function", Gang: "pair", Resources: &models.TaskResources{CPU: 1.5}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		ran := false
+		noop := func(context.Context) error { ran = true; return nil }
+		resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+			Name:  "too-big-" + tt.name,
+			Tasks: tt.tasks,
+		}, SubmitWorkflowOptions{
+			Mode:    SubmissionModeSync,
+			TaskFns: map[string]func(context.Context) error{"a": noop, "b": noop},
+		})
+		if err != nil {
+			t.Fatalf("%s: SubmitWorkflowRuntime() error = %v", tt.name, err)
+		}
+		if resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, "exceeding the capacity") {
+			t.Fatalf("%s: expected failed workflow with resource error, got %s %q", tt.name, resp.Status, resp.Error)
+		}
+		if ran {
+			t.Fatalf("%s: expected no task to run", tt.name)
+		}
+	}
+}
+
 func waitWorkflowStatus(eng *Engine, workflowID, want string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
@@ -254,3 +804,397 @@ func waitWorkflowStatus(eng *Engine, workflowID, want string, timeout time.Durat
 	}
 	return context.DeadlineExceeded
 }
+
+func TestSubmitWorkflowRuntime_Simulate(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	req := func() *models.WorkflowRequest {
+		return &models.WorkflowRequest{
+			Name: "nightly-report",
+			Tasks: []models.TaskDefinition{
+				{ID: "fetch", Name: "fetch", Type: "function", Resources: &models.TaskResources{CPU: 1}},
+				{ID: "render", Name: "render", Type: "function", Resources: &models.TaskResources{CPU: 2}},
+				{ID: "publish", Name: "publish", Type: "function", DependsOn: []string{"fetch", "render"}},
+			},
+		}
+	}
+	sleep := func(d time.Duration) func(context.Context) error {
+		return func(context.Context) error {
+			time.Sleep(d)
+			return nil
+		}
+	}
+	// One real run gives fetch and render a history.
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), req(), SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"fetch":   sleep(20 * time.Millisecond),
+			"render":  sleep(40 * time.Millisecond),
+			"publish": sleep(0),
+		},
+	})
+	if err != nil || resp.Status != workflowStatusCompleted {
+		t.Fatalf("real run: status=%v err=%v", resp, err)
+	}
+	_, before, err := store.ListWorkflows(context.Background(), &storage.WorkflowFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListWorkflows() error = %v", err)
+	}
+
+	sim, err := eng.SubmitWorkflowRuntime(context.Background(), req(), SubmitWorkflowOptions{Mode: SubmissionModeSimulate})
+	if err != nil {
+		t.Fatalf("simulate error = %v", err)
+	}
+	if sim.Status != workflowStatusSimulated || sim.Simulation == nil {
+		t.Fatalf("expected simulation report, got %+v", sim)
+	}
+	report := sim.Simulation
+	if len(report.Layers) != 2 || len(report.Layers[0].Tasks) != 2 {
+		t.Fatalf("unexpected layers: %+v", report.Layers)
+	}
+	if got := report.Layers[0].Resources; got == nil || got.CPU != 3 {
+		t.Fatalf("layer 0 resources = %+v, want cpu=3", got)
+	}
+	if report.PeakResources == nil || report.PeakResources.CPU != 3 {
+		t.Fatalf("peak resources = %+v, want cpu=3", report.PeakResources)
+	}
+	tasks := make(map[string]models.SimulatedTask)
+	for _, task := range report.Tasks {
+		tasks[task.ID] = task
+	}
+	render := tasks["render"]
+	if render.Estimate != "history" || render.Samples != 1 || render.DurationMS < 40 {
+		t.Fatalf("unexpected render estimate: %+v", render)
+	}
+	if publish := tasks["publish"]; publish.Layer != 1 || publish.StartMS < render.DurationMS {
+		t.Fatalf("expected publish to start after render, got %+v", publish)
+	}
+	if report.EstimatedDurationMS < render.DurationMS {
+		t.Fatalf("estimated duration %v shorter than render %v", report.EstimatedDurationMS, render.DurationMS)
+	}
+
+	_, after, err := store.ListWorkflows(context.Background(), &storage.WorkflowFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListWorkflows() error = %v", err)
+	}
+	if after != before {
+		t.Fatalf("simulation persisted a workflow: %d workflows, want %d", after, before)
+	}
+
+	bad := req()
+	bad.Tasks[2].DependsOn = []string{"missing"}
+	var compileErr *WorkflowCompileError
+	if _, err := eng.SubmitWorkflowRuntime(context.Background(), bad, SubmitWorkflowOptions{Mode: SubmissionModeSimulate}); !errors.As(err, &compileErr) {
+		t.Fatalf("expected compile error, got %v", err)
+	}
+}
+
+func TestReplayWorkflow_ReproducesRecordedOrder(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	var fetchAttempts atomic.Int32
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "ingest",
+		Tasks: []models.TaskDefinition{
+			{ID: "fetch", Name: "fetch", Type: "function", Retries: 2},
+			{ID: "parse", Name: "parse", Type: "function"},
+			{ID: "store", Name: "store", Type: "function", DependsOn: []string{"fetch", "parse"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"fetch": func(context.Context) error {
+				if fetchAttempts.Add(1) == 1 {
+					return errors.New("connection reset")
+				}
+				return nil
+			},
+			"parse": func(context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			},
+			"store": func(context.Context) error { return errors.New("disk full") },
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusFailed {
+		t.Fatalf("expected failed workflow, got %s", resp.Status)
+	}
+
+	recorded, err := store.GetWorkflow(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	var retryErr string
+	for _, entry := range recorded.Journal {
+		if entry.TaskID == "fetch" && entry.From == taskStatusRunning && entry.To == taskStatusScheduled {
+			retryErr = entry.Error
+		}
+	}
+	if retryErr != "connection reset" {
+		t.Fatalf("expected the failed attempt in the journal, got %+v", recorded.Journal)
+	}
+
+	report, err := eng.ReplayWorkflow(context.Background(), resp.ID, 0)
+	if err != nil {
+		t.Fatalf("ReplayWorkflow() error = %v", err)
+	}
+	if !report.Reproduced() {
+		t.Fatalf("replay diverged: status %s, divergences %+v", report.ReplayedStatus, report.Divergences)
+	}
+	if len(report.Steps) != len(recorded.Journal) {
+		t.Fatalf("replayed %d steps, want %d", len(report.Steps), len(recorded.Journal))
+	}
+	for i, step := range report.Steps {
+		entry := recorded.Journal[i]
+		if step.Seq != entry.Seq || step.TaskID != entry.TaskID || step.To != entry.To || step.Error != entry.Error {
+			t.Fatalf("step %d = %+v, want %+v", i, step, entry)
+		}
+	}
+}
+
+func TestReplayWorkflowWith_FeedsBackRecording(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	sample := func(ctx context.Context) error {
+		return SetTaskResult(ctx, map[string]any{"at": TaskNow(ctx), "n": TaskRandom(ctx)})
+	}
+	report := func(ctx context.Context) error {
+		sampled, err := UpstreamResult(ctx, "sample")
+		if err != nil {
+			return err
+		}
+		return SetTaskResult(ctx, map[string]any{"sampled": sampled})
+	}
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "sampling",
+		Tasks: []models.TaskDefinition{
+			{ID: "sample", Name: "sample", Type: "function"},
+			{ID: "report", Name: "report", Type: "function", DependsOn: []string{"sample"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"sample": sample, "report": report},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("expected completed workflow, got %s", resp.Status)
+	}
+
+	recorded, err := store.GetWorkflow(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	var sampled, reported *storage.JournalEntry
+	for i, entry := range recorded.Journal {
+		if entry.From == taskStatusRunning && entry.TaskID == "sample" {
+			sampled = &recorded.Journal[i]
+		}
+		if entry.From == taskStatusRunning && entry.TaskID == "report" {
+			reported = &recorded.Journal[i]
+		}
+	}
+	if sampled == nil || len(sampled.Decisions) != 2 || sampled.Decisions[0].Kind != "time" || sampled.Decisions[1].Kind != "random" || sampled.Output == nil {
+		t.Fatalf("expected the sample attempt with its decisions and output, got %+v", sampled)
+	}
+	if reported == nil || !reflect.DeepEqual(reported.Inputs, map[string]any{"sample": sampled.Output}) {
+		t.Fatalf("expected the report attempt with its input, got %+v", reported)
+	}
+
+	// Re-executed tasks draw the recorded time and number again, so they
+	// reproduce their results.
+	replayed, err := eng.ReplayWorkflowWith(context.Background(), resp.ID, ReplayOptions{
+		TaskFns: map[string]func(context.Context) error{"sample": sample, "report": report},
+	})
+	if err != nil {
+		t.Fatalf("ReplayWorkflowWith() error = %v", err)
+	}
+	if !replayed.Reproduced() {
+		t.Fatalf("replay diverged: %+v", replayed.Divergences)
+	}
+
+	// A task that departs from its recording is reported; the task
+	// downstream still reads the recorded result.
+	replayed, err = eng.ReplayWorkflowWith(context.Background(), resp.ID, ReplayOptions{
+		TaskFns: map[string]func(context.Context) error{
+			"sample": func(ctx context.Context) error { return SetTaskResult(ctx, map[string]any{"n": TaskRandom(ctx)}) },
+			"report": report,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ReplayWorkflowWith() error = %v", err)
+	}
+	if len(replayed.Divergences) != 2 {
+		t.Fatalf("expected the decision and output of sample to diverge, got %+v", replayed.Divergences)
+	}
+	for _, divergence := range replayed.Divergences {
+		if divergence.Seq != sampled.Seq || !strings.HasPrefix(divergence.Observed, "sample attempt 1 ") {
+			t.Fatalf("unexpected divergence %+v", divergence)
+		}
+	}
+}
+
+func TestReplayWorkflow_NotReplayable(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	for _, wf := range []*storage.WorkflowState{
+		{ID: "running", Name: "wf", Status: workflowStatusRunning},
+		{ID: "no-journal", Name: "wf", Status: workflowStatusCompleted},
+	} {
+		if err := store.SaveWorkflow(context.Background(), wf); err != nil {
+			t.Fatalf("SaveWorkflow() error = %v", err)
+		}
+		var notReplayable *WorkflowNotReplayableError
+		if _, err := eng.ReplayWorkflow(context.Background(), wf.ID, 0); !errors.As(err, &notReplayable) {
+			t.Fatalf("%s: expected WorkflowNotReplayableError, got %v", wf.ID, err)
+		}
+	}
+}
+
+// invalidationRecorder records the cache invalidations the engine requests.
+type invalidationRecorder struct {
+	*memory.MemoryStorage
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *invalidationRecorder) Invalidate(workflowID, taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, workflowID+"/"+taskID)
+}
+
+func TestSubmitWorkflowRuntime_InvalidatesCachedStorage(t *testing.T) {
+	store := &invalidationRecorder{MemoryStorage: memory.NewMemoryStorage()}
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name:  "invalidate",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "task-1", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"t1": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	var workflowCalls, taskCalls int
+	for _, call := range store.calls {
+		switch call {
+		case resp.ID + "/":
+			workflowCalls++
+		case resp.ID + "/t1":
+			taskCalls++
+		}
+	}
+	// pending, scheduled, running, completed for the workflow; scheduled,
+	// running, completed for the task.
+	if workflowCalls != 4 || taskCalls != 3 {
+		t.Fatalf("invalidations = %v, want 4 for the workflow and 3 for the task", store.calls)
+	}
+}
+
+func TestWorkflowSummaryAndTaskPages(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	wf := &storage.WorkflowState{
+		ID:         "wf-large",
+		Name:       "large",
+		Status:     workflowStatusFailed,
+		TaskStatus: make(map[string]*storage.TaskState),
+		CreatedAt:  base,
+	}
+	for i := 0; i < 25; i++ {
+		id := fmt.Sprintf("t%02d", i)
+		completed := base.Add(time.Duration(i) * time.Second)
+		task := &storage.TaskState{ID: id, Name: id, Status: taskStatusCompleted, CompletedAt: &completed}
+		if i%2 == 0 {
+			task.Status = taskStatusFailed
+			task.Error = "boom"
+		}
+		wf.Tasks = append(wf.Tasks, models.TaskDefinition{ID: id, Name: id, Type: "function"})
+		wf.TaskStatus[id] = task
+	}
+	if err := store.SaveWorkflow(context.Background(), wf); err != nil {
+		t.Fatalf("SaveWorkflow() error = %v", err)
+	}
+
+	summary, err := eng.GetWorkflowSummaryResponse(context.Background(), wf.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowSummaryResponse() error = %v", err)
+	}
+	if summary.Tasks != nil || summary.TaskSummary == nil {
+		t.Fatalf("expected a task summary instead of tasks, got %+v", summary)
+	}
+	s := summary.TaskSummary
+	if s.Total != 25 || s.Counts[taskStatusFailed] != 13 || s.Counts[taskStatusCompleted] != 12 {
+		t.Fatalf("unexpected summary counts: %+v", s)
+	}
+	if len(s.RecentFailures) != recentFailuresLimit || s.RecentFailures[0].ID != "t24" || s.RecentFailures[1].ID != "t22" {
+		t.Fatalf("expected the newest failures first, got %d starting with %+v", len(s.RecentFailures), s.RecentFailures[0])
+	}
+	if s.RecentFailures[0].Type != "function" || s.RecentFailures[0].Error != "boom" {
+		t.Fatalf("expected failures with definitions and errors, got %+v", s.RecentFailures[0])
+	}
+
+	tasks, total, err := eng.ListWorkflowTasksResponse(context.Background(), wf.ID, models.TaskFilter{Status: taskStatusFailed, Limit: 5, Offset: 10})
+	if err != nil {
+		t.Fatalf("ListWorkflowTasksResponse() error = %v", err)
+	}
+	if total != 13 || len(tasks) != 3 || tasks[0].ID != "t20" {
+		t.Fatalf("expected 3 of 13 failed tasks from t20, got %d of %d", len(tasks), total)
+	}
+	if tasks, total, _ := eng.ListWorkflowTasksResponse(context.Background(), wf.ID, models.TaskFilter{Offset: 100}); total != 25 || len(tasks) != 0 {
+		t.Fatalf("expected an empty page past the end, got %d of %d", len(tasks), total)
+	}
+	if _, _, err := eng.ListWorkflowTasksResponse(context.Background(), "missing", models.TaskFilter{}); !errs.Is(err, errs.NotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
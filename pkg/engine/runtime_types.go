@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/goclaw/goclaw/pkg/storage"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -15,6 +16,10 @@ const (
 	workflowStatusCompleted = "completed"
 	workflowStatusFailed    = "failed"
 	workflowStatusCancelled = "cancelled"
+	workflowStatusSimulated = "simulated"
+	// workflowStatusPlanned is the status of a workflow proposed by a planner
+	// that waits for approval before it runs.
+	workflowStatusPlanned = "planned"
 
 	taskStatusPending   = "pending"
 	taskStatusScheduled = "scheduled"
@@ -30,6 +35,9 @@ type SubmissionMode string
 const (
 	SubmissionModeSync  SubmissionMode = "sync"
 	SubmissionModeAsync SubmissionMode = "async"
+	// SubmissionModeSimulate compiles the workflow and predicts its execution
+	// without persisting or running it.
+	SubmissionModeSimulate SubmissionMode = "simulate"
 )
 
 type workflowExecution struct {
@@ -38,9 +46,38 @@ type workflowExecution struct {
 	done       chan struct{}
 	mu         sync.Mutex
 	wfState    *storage.WorkflowState
+	// usage is the run's usage meter; nil without cost accounting.
+	usage *usageMeter
+	// spanCtx is the span context of the run once it starts executing. It
+	// links the duration metrics of the run to its trace.
+	spanCtx trace.SpanContext
+	// compensationFns run the compensations of tasks that have one, by task
+	// ID.
+	compensationFns map[string]func(context.Context) error
+	// run is the plan being scheduled once the run has compiled it; tasks
+	// appended to the run are added to it.
+	run *runPlan
+	// blackboardSubs holds the subscribers of each blackboard key.
+	blackboardSubs map[string][]chan BlackboardEntry
+	// overBudget is why the run exceeded a budget once it did; the run then
+	// fails instead of being cancelled.
+	overBudget string
+	// attempts are the outputs of the running attempts, by task ID, until
+	// the transitions ending them are journaled.
+	attempts map[string]*taskOutput
+}
+
+// traceContext returns a context carrying the span context of the run. The
+// caller holds exec.mu.
+func (exec *workflowExecution) traceContext() context.Context {
+	return trace.ContextWithSpanContext(context.Background(), exec.spanCtx)
 }
 
 var allowedWorkflowTransitions = map[string]map[string]struct{}{
+	workflowStatusPlanned: {
+		workflowStatusPending:   {},
+		workflowStatusCancelled: {},
+	},
 	workflowStatusPending: {
 		workflowStatusScheduled: {},
 		workflowStatusFailed:    {},
@@ -86,7 +123,7 @@ func isTerminalTaskStatus(status string) bool {
 }
 
 func validateWorkflowTransition(oldStatus, newStatus string) error {
-	if oldStatus == "" && newStatus == workflowStatusPending {
+	if oldStatus == "" && (newStatus == workflowStatusPending || newStatus == workflowStatusPlanned) {
 		return nil
 	}
 	if oldStatus == newStatus {
@@ -13,6 +13,9 @@ func TestValidateWorkflowTransition(t *testing.T) {
 		{name: "pending to scheduled", oldStatus: workflowStatusPending, newStatus: workflowStatusScheduled, wantErr: false},
 		{name: "running to completed", oldStatus: workflowStatusRunning, newStatus: workflowStatusCompleted, wantErr: false},
 		{name: "pending to running invalid", oldStatus: workflowStatusPending, newStatus: workflowStatusRunning, wantErr: true},
+		{name: "create planned", oldStatus: "", newStatus: workflowStatusPlanned, wantErr: false},
+		{name: "planned to pending", oldStatus: workflowStatusPlanned, newStatus: workflowStatusPending, wantErr: false},
+		{name: "planned to running invalid", oldStatus: workflowStatusPlanned, newStatus: workflowStatusRunning, wantErr: true},
 		{name: "terminal immutable", oldStatus: workflowStatusCompleted, newStatus: workflowStatusFailed, wantErr: true},
 	}
 
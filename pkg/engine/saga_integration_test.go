@@ -2,10 +2,15 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
 	"github.com/goclaw/goclaw/pkg/saga"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 )
@@ -71,3 +76,90 @@ func TestEngineSagaOrchestratorLifecycle(t *testing.T) {
 		t.Fatal("expected cleanup cancel to be cleared on stop")
 	}
 }
+
+func TestEngineCompensatesFailedWorkflowAsSaga(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.Type = "memory"
+	cfg.Storage.Badger.Path = t.TempDir()
+	cfg.Saga.Enabled = true
+
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var mu sync.Mutex
+	var compensated []string
+	var refund map[string]any
+	compensation := func(id string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			compensated = append(compensated, id)
+			if id == "charge" {
+				var err error
+				refund, err = TaskConfig(ctx)
+				return err
+			}
+			return nil
+		}
+	}
+	req := &models.WorkflowRequest{
+		Name: "order",
+		Tasks: []models.TaskDefinition{
+			{ID: "reserve", Name: "reserve", Type: "function", Compensation: &models.TaskCompensation{}},
+			{ID: "charge", Name: "charge", Type: "function", DependsOn: []string{"reserve"},
+				Compensation: &models.TaskCompensation{Config: map[string]interface{}{"payment": "${{ tasks.charge.result.id }}"}}},
+			{ID: "notify", Name: "notify", Type: "function", DependsOn: []string{"reserve"}},
+			{ID: "ship", Name: "ship", Type: "function", DependsOn: []string{"charge", "notify"},
+				Compensation: &models.TaskCompensation{}},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"charge": func(ctx context.Context) error { return SetTaskResult(ctx, map[string]any{"id": "pay-1"}) },
+			"ship":   func(context.Context) error { return errors.New("carrier unavailable") },
+		},
+		CompensationFns: map[string]func(context.Context) error{
+			"reserve": compensation("reserve"),
+			"charge":  compensation("charge"),
+			"ship":    compensation("ship"),
+		},
+	})
+	if err != nil || resp.Status != workflowStatusFailed {
+		t.Fatalf("SubmitWorkflowRuntime() = %+v, %v, want a failed workflow", resp, err)
+	}
+	if !reflect.DeepEqual(compensated, []string{"charge", "reserve"}) {
+		t.Fatalf("compensated %v, want charge then reserve", compensated)
+	}
+	if refund["payment"] != "pay-1" {
+		t.Fatalf("charge compensation config = %v, want the payment of the run", refund)
+	}
+
+	instance, err := eng.GetSagaOrchestrator().GetInstance(resp.ID)
+	if err != nil {
+		t.Fatalf("GetInstance() error = %v", err)
+	}
+	if instance.State != saga.SagaStateCompensated || instance.FailedStep != "ship" || len(instance.Compensated) != 2 {
+		t.Fatalf("saga = %+v, want ship failed and two steps compensated", instance)
+	}
+}
+
+func TestEngineRejectsCompensationsWithoutSaga(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	req := &models.WorkflowRequest{Name: "order", Tasks: []models.TaskDefinition{
+		{ID: "charge", Name: "charge", Type: "function", Compensation: &models.TaskCompensation{}},
+	}}
+	if err := eng.ValidateWorkflowRequest(context.Background(), req); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("ValidateWorkflowRequest() error = %v, want BadRequest", err)
+	}
+}
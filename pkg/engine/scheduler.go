@@ -2,12 +2,15 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/artifact"
 	"github.com/goclaw/goclaw/pkg/dag"
 	"github.com/goclaw/goclaw/pkg/lane"
 	"github.com/goclaw/goclaw/pkg/signal"
+	"github.com/goclaw/goclaw/pkg/storage"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -19,6 +22,52 @@ type Scheduler struct {
 	logger      appLogger
 	signalBus   signal.Bus
 	laneManager *lane.Manager
+
+	// priority is the priority of the workflow being scheduled.
+	priority int
+	// preemptor enables preemption of lower-priority tasks when set.
+	preemptor *preemptor
+	// inheritance lends the priority of queued tasks to lower-priority tasks
+	// queued ahead of them when set.
+	inheritance *priorityInheritance
+	// env resolves task environments and secrets when set.
+	env *envResolver
+	// artifacts is handed to tasks for uploads and downloads when set.
+	artifacts *artifact.Store
+	// logs keeps the output tasks write with TaskLog when set.
+	logs *taskLogStore
+	// usage accumulates what tasks report with RecordUsage when set.
+	usage *usageMeter
+	// values holds the run's workflow values for tasks when set.
+	values *workflowValues
+	// lineage records the outputs tasks read when set.
+	lineage *workflowLineage
+	// outputs stores the results tasks set when set.
+	outputs *workflowOutputs
+	// exprs evaluates task conditions, retry conditions and config when set.
+	exprs *workflowExpressions
+	// dedupes remembers the executions of dedupe keys across runs for
+	// dedupeTTL when set, under the keys of dedupeScope, the run's tenant.
+	dedupes     storage.DedupeStore
+	dedupeTTL   time.Duration
+	dedupeScope string
+	// locks hands out the locks tasks hold while they run when set.
+	locks *lockManager
+	// rateLimits are the named rate limits tasks take requests from when set.
+	rateLimits *rateLimits
+	// executors run tasks without a task function, by agent type.
+	executors map[string]TaskExecutor
+	// middleware wraps every task attempt.
+	middleware []TaskMiddleware
+	// workflowID is the ID of the workflow being scheduled.
+	workflowID string
+	// deadline is the workflow deadline; zero means none.
+	deadline time.Time
+	// gangLayers dispatches every layer all-or-nothing per lane.
+	gangLayers bool
+	// run is the plan being run, to which tasks can be appended. Schedule
+	// creates it from its plan when unset.
+	run *runPlan
 }
 
 // newScheduler creates a new Scheduler.
@@ -49,15 +98,323 @@ type scheduledTaskResult struct {
 	err    error
 }
 
+// dedupeEntry tracks the task that executes on behalf of a dedupe key.
+type dedupeEntry struct {
+	taskID string
+	done   chan struct{}
+	err    error
+}
+
+// awaitDuplicate waits for the task executing entry and mirrors its outcome,
+// including its result, onto the duplicate taskID.
+func (s *Scheduler) awaitDuplicate(taskID string, entry *dedupeEntry, resultCh chan<- scheduledTaskResult) {
+	<-entry.done
+
+	s.tracker.SetDedupedFrom(taskID, entry.taskID)
+	original, _ := s.tracker.GetResult(entry.taskID)
+	switch {
+	case original != nil && original.State == TaskStateCompleted:
+		if s.outputs != nil {
+			value, _ := s.outputs.result(entry.taskID)
+			s.outputs.setResult(taskID, value)
+		}
+		s.tracker.SetState(taskID, TaskStateRunning)
+		s.tracker.SetState(taskID, TaskStateCompleted)
+	case original != nil && original.State == TaskStateFailed:
+		s.tracker.SetState(taskID, TaskStateRunning)
+		s.tracker.SetFailed(taskID, original.Error, original.Retries)
+	default:
+		s.tracker.SetState(taskID, TaskStateCancelled)
+	}
+	resultCh <- scheduledTaskResult{taskID: taskID, err: entry.err}
+}
+
+// defaultDedupeTTL is how long the execution of a dedupe key is remembered
+// when orchestration.dedupe_ttl leaves it unset.
+const defaultDedupeTTL = 24 * time.Hour
+
+// dedupeTTL returns how long the execution of a dedupe key is remembered.
+func (e *Engine) dedupeTTL() time.Duration {
+	if ttl := e.cfg.Orchestration.DedupeTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultDedupeTTL
+}
+
+// dedupeRecordKey returns the key the execution of a dedupe key is
+// remembered under, so that tenants do not share executions.
+func (s *Scheduler) dedupeRecordKey(key string) string {
+	if s.dedupeScope == "" {
+		return key
+	}
+	return s.dedupeScope + "/" + key
+}
+
+// recordedDedupe returns the unexpired execution of key by an earlier run,
+// or nil.
+func (s *Scheduler) recordedDedupe(ctx context.Context, key string) *storage.DedupeRecord {
+	if s.dedupes == nil {
+		return nil
+	}
+	record, err := s.dedupes.GetDedupe(ctx, s.dedupeRecordKey(key))
+	if err != nil {
+		var notFound *storage.NotFoundError
+		if !errors.As(err, &notFound) {
+			s.logger.Warn("failed to look up dedupe key", "dedupe_key", key, "error", err)
+		}
+		return nil
+	}
+	return record
+}
+
+// reuseDedupe completes taskID with the result of the execution of its
+// dedupe key by an earlier run.
+func (s *Scheduler) reuseDedupe(taskID string, record *storage.DedupeRecord) {
+	s.tracker.SetDedupedFrom(taskID, record.WorkflowID+"/"+record.TaskID)
+	if s.outputs != nil {
+		s.outputs.setResult(taskID, record.Result)
+	}
+	s.tracker.SetState(taskID, TaskStateRunning)
+	s.tracker.SetState(taskID, TaskStateCompleted)
+}
+
+// rememberDedupe records the execution of the dedupe key of task, once it
+// completed, for later runs. Results of sensitive tasks are not recorded.
+func (s *Scheduler) rememberDedupe(task *dag.Task) {
+	if s.dedupes == nil {
+		return
+	}
+	if res, ok := s.tracker.GetResult(task.ID); !ok || res.State != TaskStateCompleted {
+		return
+	}
+	var value any
+	if s.outputs != nil {
+		var sensitive bool
+		if value, sensitive = s.outputs.result(task.ID); sensitive {
+			return
+		}
+	}
+	now := time.Now()
+	record := &storage.DedupeRecord{
+		Key:         s.dedupeRecordKey(task.DedupeKey),
+		WorkflowID:  s.workflowID,
+		TaskID:      task.ID,
+		Result:      value,
+		CompletedAt: now,
+		ExpiresAt:   now.Add(s.dedupeTTL),
+	}
+	if err := s.dedupes.SaveDedupe(context.Background(), record); err != nil {
+		s.logger.Warn("failed to record dedupe key", "task_id", task.ID, "dedupe_key", task.DedupeKey, "error", err)
+	}
+}
+
+// taskDeadline returns the earlier of the task's own deadline and the
+// workflow deadline.
+func (s *Scheduler) taskDeadline(task *dag.Task) time.Time {
+	if task.Deadline.IsZero() || (!s.deadline.IsZero() && s.deadline.Before(task.Deadline)) {
+		return s.deadline
+	}
+	return task.Deadline
+}
+
+// gangMember is a scheduled task waiting for the rest of its gang.
+type gangMember struct {
+	task    *scheduledTask
+	retries int
+}
+
+// gangKey returns the gang a task belongs to in layerIdx, or "" when it is
+// dispatched on its own. With gangLayers every layer forms a gang per lane.
+func gangKey(task *dag.Task, layerIdx int, laneName string, gangLayers bool) string {
+	if task.Gang != "" {
+		return task.Gang
+	}
+	if gangLayers {
+		return fmt.Sprintf("layer-%d/%s", layerIdx, laneName)
+	}
+	return ""
+}
+
+// preemptFor preempts one lower-priority task in laneName when the lane is
+// saturated, so that the task just submitted can take its worker. Tasks of
+// lower priority queued ahead of it inherit its priority, and one more task
+// is preempted for each of them, so that they cannot hold it back.
+func (s *Scheduler) preemptFor(laneName string, waiter *laneWaiter) {
+	if s.priority <= 0 || (s.preemptor == nil && s.inheritance == nil) {
+		return
+	}
+	l, err := s.laneManager.GetLane(laneName)
+	if err != nil {
+		return
+	}
+	stats := l.Stats()
+	if stats.Pending == 0 || stats.Running < stats.MaxConcurrency {
+		return
+	}
+	victims := 1
+	if inherited := s.inheritance.inherit(waiter); inherited > 0 {
+		s.logger.Info("priority inherited by queued tasks", "task_id", waiter.taskID, "lane", laneName, "priority", s.priority, "tasks", inherited)
+		victims += inherited
+	}
+	if s.preemptor == nil {
+		return
+	}
+	for i := 0; i < victims; i++ {
+		victim, ok := s.preemptor.preempt(laneName, s.priority)
+		if !ok {
+			return
+		}
+		s.logger.Info("preempted task", "task_id", victim, "lane", laneName, "priority", s.priority)
+	}
+}
+
+// scheduledTask is the lane task of one plan task during Schedule. It carries
+// everything dispatch needs, so a task costs no closures or wrappers: the
+// tasks of a layer are allocated together in one slice.
+type scheduledTask struct {
+	scheduler *Scheduler
+	runner    taskRunner
+	// ctx is the context tasks run under; schedCtx is the context of the
+	// Schedule call, used to requeue preempted tasks.
+	ctx      context.Context
+	schedCtx context.Context
+	deadline time.Time
+	// entry is set when the task executes on behalf of a dedupe key.
+	entry *dedupeEntry
+	// waiter tracks the task, or its gang, while it is queued in the lane.
+	waiter   *laneWaiter
+	resultCh chan<- scheduledTaskResult
+	// traced records a lane wait span, measured from submittedAt.
+	traced      bool
+	submittedAt time.Time
+}
+
+// ID implements lane.Task.
+func (t *scheduledTask) ID() string { return t.runner.task.ID }
+
+// Priority implements lane.Task.
+func (t *scheduledTask) Priority() int { return t.runner.priority }
+
+// Lane implements lane.Task.
+func (t *scheduledTask) Lane() string { return t.runner.Lane() }
+
+// Deadline implements lane.DeadlineTask.
+func (t *scheduledTask) Deadline() (time.Time, bool) { return t.deadline, !t.deadline.IsZero() }
+
+// Resources implements lane.ResourceTask.
+func (t *scheduledTask) Resources() lane.Resources { return laneResources(t.runner.task.Resources) }
+
+// AffinityKey implements lane.AffinityTask.
+func (t *scheduledTask) AffinityKey() string { return t.runner.task.AffinityKey }
+
+// Execute implements lane.Task. A task runs with the priority it inherited
+// while queued, if higher than its own. A preempted task is requeued behind
+// the work that preempted it; otherwise its outcome is reported to Schedule.
+func (t *scheduledTask) Execute(context.Context) error {
+	taskID := t.runner.task.ID
+	t.runner.inherited = t.scheduler.inheritance.start(t.waiter)
+	taskCtx, cleanup := t.scheduler.attachSignalChannel(t.ctx, taskID)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if t.scheduler.artifacts != nil {
+		taskCtx = artifact.WithTask(taskCtx, t.scheduler.artifacts, t.scheduler.workflowID, taskID)
+	}
+	if t.scheduler.logs != nil {
+		taskCtx = t.scheduler.logs.withTask(taskCtx, t.scheduler.workflowID, taskID)
+	}
+	if t.scheduler.usage != nil {
+		taskCtx = t.scheduler.usage.withTask(taskCtx)
+	}
+	if t.scheduler.values != nil {
+		taskCtx = t.scheduler.values.withTask(taskCtx)
+	}
+	if t.scheduler.lineage != nil {
+		taskCtx = t.scheduler.lineage.withTask(taskCtx, taskID)
+	}
+	if t.scheduler.locks != nil {
+		locks := t.scheduler.locks.forTask(t.scheduler.workflowID, taskID)
+		defer locks.releaseAll()
+		taskCtx = locks.withTask(taskCtx)
+	}
+	if t.scheduler.rateLimits != nil {
+		taskCtx = t.scheduler.rateLimits.withTask(taskCtx)
+	}
+
+	if t.traced {
+		var waitSpan trace.Span
+		taskCtx, waitSpan = runtimeTracer().Start(
+			taskCtx,
+			spanLaneWait,
+			trace.WithTimestamp(t.submittedAt),
+		)
+		waitSpan.SetAttributes(
+			attribute.String("task.id", taskID),
+			attribute.String("lane.name", t.Lane()),
+		)
+		waitSpan.SetStatus(otelcodes.Ok, "ok")
+		waitSpan.End()
+	}
+
+	err := t.runner.Execute(taskCtx)
+	if errors.Is(err, errTaskPreempted) {
+		// Submit from a separate goroutine so a full lane cannot block this
+		// worker.
+		go func() {
+			t.waiter = t.scheduler.inheritance.enqueue(t.Lane(), taskID, t.runner.priority)
+			if err := t.scheduler.laneManager.Submit(t.schedCtx, t); err != nil {
+				t.scheduler.inheritance.start(t.waiter)
+				t.scheduler.tracker.SetFailed(taskID, err, t.runner.task.Retries)
+				t.finish(fmt.Errorf("lane requeue failed for task %s: %w", taskID, err))
+			}
+		}()
+		return nil
+	}
+	t.finish(err)
+	return err
+}
+
+// finish reports the outcome of the task to Schedule and to its duplicates.
+func (t *scheduledTask) finish(err error) {
+	if t.entry != nil {
+		if err == nil {
+			t.scheduler.rememberDedupe(t.runner.task)
+		}
+		t.entry.err = err
+		close(t.entry.done)
+	}
+	t.resultCh <- scheduledTaskResult{taskID: t.runner.task.ID, err: err}
+}
+
 // Schedule executes the plan layer by layer.
 // All tasks within a layer run concurrently; the next layer starts only after
-// every task in the current layer has completed.
+// every task in the current layer has completed. Tasks sharing a DedupeKey
+// execute once; the others wait for that execution and share its outcome
+// and result. With a DedupeStore, a key executed by an earlier run is not
+// executed again until its record expires.
+// Tasks appended to the run while it goes on join the layers after the
+// running one.
+// Tasks of a gang are submitted as one lane.TaskGroup after the rest of the
+// layer, so that they start together or not at all. Per-task spans are only
+// recorded when ctx carries a recording span.
 func (s *Scheduler) Schedule(ctx context.Context, plan *dag.ExecutionPlan, taskFns map[string]func(context.Context) error) error {
 	if s.laneManager == nil {
 		return fmt.Errorf("lane manager is not configured")
 	}
 
-	for layerIdx, layer := range plan.Layers {
+	traced := tracingActive(ctx)
+	var dedupe map[string]*dedupeEntry
+
+	if s.run == nil {
+		s.run = newRunPlan(plan, s.tracker)
+	}
+	defer s.run.finish()
+
+	for layerIdx := 0; ; layerIdx++ {
+		layer, ok := s.run.layer(layerIdx)
+		if !ok {
+			break
+		}
 		layerCtx, layerSpan := runtimeTracer().Start(ctx, spanWorkflowLayer)
 		layerSpan.SetAttributes(
 			attribute.Int("workflow.layer_index", layerIdx),
@@ -77,8 +434,11 @@ func (s *Scheduler) Schedule(ctx context.Context, plan *dag.ExecutionPlan, taskF
 		s.logger.Debug("scheduling layer", "layer", layerIdx, "tasks", layer)
 
 		resultCh := make(chan scheduledTaskResult, len(layer))
+		tasks := make([]scheduledTask, len(layer))
 		submitted := 0
 		firstErr := error(nil)
+		var gangs map[string][]gangMember
+		var gangOrder []string
 
 		for idx, taskID := range layer {
 			if ctx.Err() != nil {
@@ -89,7 +449,7 @@ func (s *Scheduler) Schedule(ctx context.Context, plan *dag.ExecutionPlan, taskF
 				break
 			}
 
-			dagTask, ok := plan.GetTask(taskID)
+			dagTask, ok := s.run.task(taskID)
 			if !ok {
 				for _, remainingTaskID := range layer[idx:] {
 					s.tracker.SetState(remainingTaskID, TaskStateFailed)
@@ -98,46 +458,85 @@ func (s *Scheduler) Schedule(ctx context.Context, plan *dag.ExecutionPlan, taskF
 				break
 			}
 
-			fn := taskFns[taskID]
-			runner := newTaskRunner(dagTask, s.tracker, fn)
+			task := &tasks[idx]
+			*task = scheduledTask{
+				scheduler: s,
+				runner:    taskRunner{task: dagTask, tracker: s.tracker, fn: taskFns[taskID], priority: s.priority, preemptor: s.preemptor, env: s.env, outputs: s.outputs, exprs: s.exprs, middleware: s.middleware, workflowID: s.workflowID, traced: traced},
+				ctx:       layerCtx,
+				schedCtx:  ctx,
+				deadline:  s.taskDeadline(dagTask),
+				resultCh:  resultCh,
+				traced:    traced,
+			}
+			if task.runner.fn == nil {
+				task.runner.fn = s.executorFn(dagTask)
+			}
+			if !task.deadline.IsZero() {
+				s.tracker.SetDeadline(taskID, task.deadline)
+			}
 			s.tracker.SetState(taskID, TaskStateScheduled)
 
-			submitCtx, submitSpan := runtimeTracer().Start(layerCtx, spanTaskSchedule)
-			submitSpan.SetAttributes(
-				attribute.String("task.id", taskID),
-				attribute.String("lane.name", runner.Lane()),
-				attribute.Int("workflow.layer_index", layerIdx),
-			)
-			submittedAt := time.Now()
-
-			laneTask := lane.NewTaskFunc(taskID, runner.Lane(), runner.Priority(), func(_ context.Context) error {
-				taskCtx, cleanup := s.attachSignalChannel(submitCtx, taskID)
-				if cleanup != nil {
-					defer cleanup()
+			if dagTask.DedupeKey != "" {
+				if existing, ok := dedupe[dagTask.DedupeKey]; ok {
+					s.logger.Debug("task deduplicated", "task_id", taskID, "dedupe_key", dagTask.DedupeKey, "deduped_from", existing.taskID)
+					go s.awaitDuplicate(taskID, existing, resultCh)
+					submitted++
+					continue
+				}
+				if dedupe == nil {
+					dedupe = make(map[string]*dedupeEntry)
+				}
+				task.entry = &dedupeEntry{taskID: taskID, done: make(chan struct{})}
+				dedupe[dagTask.DedupeKey] = task.entry
+				if record := s.recordedDedupe(ctx, dagTask.DedupeKey); record != nil {
+					s.logger.Debug("task deduplicated", "task_id", taskID, "dedupe_key", dagTask.DedupeKey, "deduped_from", record.WorkflowID+"/"+record.TaskID)
+					s.reuseDedupe(taskID, record)
+					close(task.entry.done)
+					resultCh <- scheduledTaskResult{taskID: taskID}
+					submitted++
+					continue
 				}
+			}
 
-				waitCtx, waitSpan := runtimeTracer().Start(
-					taskCtx,
-					spanLaneWait,
-					trace.WithTimestamp(submittedAt),
-				)
-				waitSpan.SetAttributes(
+			submitSpan := noopSpan
+			if traced {
+				task.ctx, submitSpan = runtimeTracer().Start(layerCtx, spanTaskSchedule)
+				submitSpan.SetAttributes(
 					attribute.String("task.id", taskID),
-					attribute.String("lane.name", runner.Lane()),
+					attribute.String("lane.name", task.Lane()),
+					attribute.Int("workflow.layer_index", layerIdx),
 				)
-				waitSpan.SetStatus(otelcodes.Ok, "ok")
-				waitSpan.End()
+				task.submittedAt = time.Now()
+			}
 
-				err := runner.Execute(waitCtx)
-				resultCh <- scheduledTaskResult{taskID: taskID, err: err}
-				return err
-			})
+			if gang := gangKey(dagTask, layerIdx, task.Lane(), s.gangLayers); gang != "" {
+				if gangs == nil {
+					gangs = make(map[string][]gangMember)
+				}
+				if _, ok := gangs[gang]; !ok {
+					gangOrder = append(gangOrder, gang)
+				}
+				gangs[gang] = append(gangs[gang], gangMember{task: task, retries: dagTask.Retries})
+				submitSpan.SetStatus(otelcodes.Ok, "gang_queued")
+				submitSpan.End()
+				submitted++
+				continue
+			}
 
-			if err := s.laneManager.Submit(ctx, laneTask); err != nil {
+			// The task owns its waiter once submitted; a preempted task
+			// replaces it when requeued.
+			waiter := s.inheritance.enqueue(task.Lane(), taskID, s.priority)
+			task.waiter = waiter
+			if err := s.laneManager.Submit(ctx, task); err != nil {
+				s.inheritance.start(waiter)
 				submitSpan.RecordError(err)
 				submitSpan.SetStatus(otelcodes.Error, "submit_failed")
 				submitSpan.End()
 				s.tracker.SetFailed(taskID, err, dagTask.Retries)
+				if task.entry != nil {
+					task.entry.err = err
+					close(task.entry.done)
+				}
 				for _, remainingTaskID := range layer[idx+1:] {
 					s.tracker.SetState(remainingTaskID, TaskStateCancelled)
 				}
@@ -147,6 +546,37 @@ func (s *Scheduler) Schedule(ctx context.Context, plan *dag.ExecutionPlan, taskF
 			submitSpan.SetStatus(otelcodes.Ok, "submitted")
 			submitSpan.End()
 			submitted++
+			s.preemptFor(task.Lane(), waiter)
+		}
+
+		for _, gang := range gangOrder {
+			members := gangs[gang]
+			if firstErr != nil {
+				for _, m := range members {
+					s.tracker.SetState(m.task.ID(), TaskStateCancelled)
+					m.task.finish(firstErr)
+				}
+				continue
+			}
+			tasks := make([]lane.Task, 0, len(members))
+			for _, m := range members {
+				tasks = append(tasks, m.task)
+			}
+			group := lane.NewTaskGroup(gang, members[0].task.Lane(), s.priority, tasks...)
+			waiter := s.inheritance.enqueue(group.Lane(), gang, s.priority)
+			for _, m := range members {
+				m.task.waiter = waiter
+			}
+			if err := s.laneManager.Submit(ctx, group); err != nil {
+				s.inheritance.start(waiter)
+				firstErr = fmt.Errorf("lane submit failed for gang %s: %w", gang, err)
+				for _, m := range members {
+					s.tracker.SetFailed(m.task.ID(), err, m.retries)
+					m.task.finish(firstErr)
+				}
+				continue
+			}
+			s.preemptFor(group.Lane(), waiter)
 		}
 
 		for i := 0; i < submitted; i++ {
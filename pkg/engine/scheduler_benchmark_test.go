@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/lane"
+)
+
+// benchmarkPlan compiles a DAG of layers x width no-op tasks where every task
+// depends on one task of the previous layer.
+func benchmarkPlan(b *testing.B, layers, width int) (*dag.ExecutionPlan, map[string]func(context.Context) error) {
+	b.Helper()
+	g := dag.NewGraph()
+	fns := make(map[string]func(context.Context) error, layers*width)
+	noop := func(context.Context) error { return nil }
+	for l := 0; l < layers; l++ {
+		for i := 0; i < width; i++ {
+			task := &dag.Task{ID: fmt.Sprintf("l%d-t%d", l, i), Name: "task", Agent: "function", Lane: defaultLaneName}
+			if l > 0 {
+				task.Deps = []string{fmt.Sprintf("l%d-t%d", l-1, i)}
+			}
+			if err := g.AddTask(task); err != nil {
+				b.Fatalf("AddTask() error = %v", err)
+			}
+			fns[task.ID] = noop
+		}
+	}
+	plan, err := g.Compile()
+	if err != nil {
+		b.Fatalf("Compile() error = %v", err)
+	}
+	return plan, fns
+}
+
+func benchmarkSchedule(b *testing.B, layers, width int) {
+	plan, fns := benchmarkPlan(b, layers, width)
+	laneManager := lane.NewManager()
+	defer laneManager.Close(context.Background())
+	if _, err := laneManager.Register(&lane.Config{Name: defaultLaneName, Capacity: width, MaxConcurrency: 64}); err != nil {
+		b.Fatalf("Register() error = %v", err)
+	}
+	taskIDs := make([]string, 0, plan.TotalTasks)
+	for _, layer := range plan.Layers {
+		taskIDs = append(taskIDs, layer...)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tracker := newStateTracker()
+		tracker.InitTasks(taskIDs)
+		sched := newScheduler(tracker, &nopLogger{}, nil, laneManager)
+		if err := sched.Schedule(context.Background(), plan, fns); err != nil {
+			b.Fatalf("Schedule() error = %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*plan.TotalTasks), "ns/task")
+}
+
+func BenchmarkSchedule_1kTasks(b *testing.B)  { benchmarkSchedule(b, 10, 100) }
+func BenchmarkSchedule_50kTasks(b *testing.B) { benchmarkSchedule(b, 50, 1000) }
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/identity"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// readsSensitive reports whether the caller of ctx may read the configs and
+// results of sensitive tasks: its client certificate was granted one of the
+// configured sensitive data roles.
+func (e *Engine) readsSensitive(ctx context.Context) bool {
+	id, ok := identity.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, role := range e.cfg.Server.Auth.SensitiveDataRoles {
+		if id.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactConfig returns config with every value replaced, so that callers
+// still see which keys a sensitive task was given.
+func redactConfig(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(config))
+	for key := range config {
+		redacted[key] = models.RedactedValue
+	}
+	return redacted
+}
+
+// redactResult returns the redacted form of a sensitive task's result.
+func redactResult(result any) any {
+	if result == nil {
+		return nil
+	}
+	return models.RedactedValue
+}
+
+// eventResult returns the result of a task as published in state change
+// events, which have no caller to authorize.
+func eventResult(taskState *storage.TaskState) any {
+	if taskState.Sensitive {
+		return redactResult(taskState.Result)
+	}
+	return taskState.Result
+}
+
+// sensitive reports whether the task whose attempt out belongs to is
+// sensitive.
+func (o *taskOutput) sensitive() bool {
+	o.exec.mu.Lock()
+	defer o.exec.mu.Unlock()
+	taskState := o.exec.wfState.TaskStatus[o.taskID]
+	return taskState != nil && taskState.Sensitive
+}
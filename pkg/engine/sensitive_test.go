@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/identity"
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_RedactsSensitiveTasks(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(config.DefaultConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	now := time.Now()
+	wf := &storage.WorkflowState{
+		ID:        "wf-1",
+		Name:      "payout",
+		Status:    "completed",
+		CreatedAt: now,
+		Tasks: []models.TaskDefinition{
+			{ID: "charge", Name: "charge", Type: "http", Sensitive: true, Config: map[string]interface{}{"api_key": "sk-live"}},
+			{ID: "notify", Name: "notify", Type: "http", DependsOn: []string{"charge"}, Config: map[string]interface{}{"channel": "#ops"}},
+		},
+		TaskStatus: map[string]*storage.TaskState{
+			"charge": {ID: "charge", Name: "charge", Status: "completed", Sensitive: true, Result: "4242", CompletedAt: &now},
+			"notify": {ID: "notify", Name: "notify", Status: "completed", Result: "sent", CompletedAt: &now},
+		},
+	}
+	if err := store.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	for _, task := range wf.TaskStatus {
+		if err := store.SaveTask(ctx, wf.ID, task); err != nil {
+			t.Fatalf("SaveTask failed: %v", err)
+		}
+	}
+
+	resp, err := eng.GetWorkflowStatusResponse(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse failed: %v", err)
+	}
+	for _, task := range resp.Tasks {
+		switch task.ID {
+		case "charge":
+			if !task.Sensitive || task.Config["api_key"] != models.RedactedValue || task.Result != models.RedactedValue {
+				t.Fatalf("sensitive task not redacted: %+v", task)
+			}
+		case "notify":
+			if task.Config["channel"] != "#ops" || task.Result != "sent" {
+				t.Fatalf("non-sensitive task redacted: %+v", task)
+			}
+		}
+	}
+	result, err := eng.GetTaskResultResponse(ctx, "wf-1", "charge")
+	if err != nil {
+		t.Fatalf("GetTaskResultResponse failed: %v", err)
+	}
+	if result.Result != models.RedactedValue {
+		t.Fatalf("task result = %v, want it redacted", result.Result)
+	}
+
+	operator := identity.NewContext(ctx, &identity.Identity{Subject: "ops", Roles: []string{"operator"}})
+	if result, _ := eng.GetTaskResultResponse(operator, "wf-1", "charge"); result.Result != models.RedactedValue {
+		t.Fatalf("task result for an operator = %v, want it redacted", result.Result)
+	}
+
+	admin := identity.NewContext(ctx, &identity.Identity{Subject: "admin", Roles: []string{"admin"}})
+	resp, err = eng.GetWorkflowStatusResponse(admin, "wf-1")
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse failed: %v", err)
+	}
+	for _, task := range resp.Tasks {
+		if task.ID == "charge" && (task.Config["api_key"] != "sk-live" || task.Result != "4242") {
+			t.Fatalf("sensitive task redacted for an admin: %+v", task)
+		}
+	}
+	if result, _ := eng.GetTaskResultResponse(admin, "wf-1", "charge"); result.Result != "4242" {
+		t.Fatalf("task result for an admin = %v", result.Result)
+	}
+}
@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/lane"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// simulationHistoryLimit bounds the completed workflows scanned for past task
+// durations.
+const simulationHistoryLimit = 200
+
+// Duration estimate sources reported in a simulation.
+const (
+	estimateHistory = "history"
+	estimateLane    = "lane"
+	estimateNone    = "none"
+)
+
+// durationSamples accumulates past durations of one task.
+type durationSamples struct {
+	total time.Duration
+	count int
+}
+
+// simulateWorkflow compiles req and predicts its execution: the layer
+// schedule, task durations estimated from past runs, and resource usage.
+// Nothing is persisted or executed.
+func (e *Engine) simulateWorkflow(ctx context.Context, req *models.WorkflowRequest) (*models.WorkflowStatusResponse, error) {
+	wfState := newWorkflowState(req)
+	wf := e.workflowFromState(wfState, nil)
+
+	g := dag.NewGraph()
+	for _, t := range wf.Tasks {
+		if t.Lane == "" {
+			t.Lane = defaultLaneName
+		}
+		if err := g.AddTask(t); err != nil {
+			return nil, &WorkflowCompileError{WorkflowID: wf.ID, Cause: err}
+		}
+	}
+	plan, err := g.Compile()
+	if err == nil {
+		err = checkPlanResources(e.laneManager, plan, wf.GangLayers)
+	}
+	if err != nil {
+		return nil, &WorkflowCompileError{WorkflowID: wf.ID, Cause: err}
+	}
+
+	history, err := e.taskDurationHistory(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task history: %w", err)
+	}
+
+	resp := e.workflowStateToResponse(ctx, wfState)
+	resp.Status = workflowStatusSimulated
+	resp.Simulation = e.simulatePlan(plan, history)
+	return resp, nil
+}
+
+// taskDurationHistory collects the durations of completed tasks in recent
+// completed workflows named name, keyed by task ID.
+func (e *Engine) taskDurationHistory(ctx context.Context, name string) (map[string]*durationSamples, error) {
+	workflows, _, err := e.storage.ListWorkflows(ctx, &storage.WorkflowFilter{
+		Status: []string{workflowStatusCompleted},
+		Limit:  simulationHistoryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[string]*durationSamples)
+	for _, wf := range workflows {
+		if wf.Name != name {
+			continue
+		}
+		for taskID, task := range wf.TaskStatus {
+			if task.Status != taskStatusCompleted || task.StartedAt == nil || task.CompletedAt == nil || task.DedupedFrom != "" {
+				continue
+			}
+			samples, ok := history[taskID]
+			if !ok {
+				samples = &durationSamples{}
+				history[taskID] = samples
+			}
+			samples.total += task.CompletedAt.Sub(*task.StartedAt)
+			samples.count++
+		}
+	}
+	return history, nil
+}
+
+// estimateTaskDuration estimates how long task runs, preferring its own past
+// runs over the lane's average processing time.
+func (e *Engine) estimateTaskDuration(task *dag.Task, history map[string]*durationSamples) (time.Duration, string, int) {
+	if samples, ok := history[task.ID]; ok && samples.count > 0 {
+		return samples.total / time.Duration(samples.count), estimateHistory, samples.count
+	}
+	if e.laneManager != nil {
+		if l, err := e.laneManager.GetLane(task.Lane); err == nil {
+			if processTime := l.Stats().ProcessTime; processTime > 0 {
+				return processTime, estimateLane, 0
+			}
+		}
+	}
+	return 0, estimateNone, 0
+}
+
+// laneConcurrency returns the number of tasks laneName runs at once, or 0
+// when unknown.
+func (e *Engine) laneConcurrency(laneName string) int {
+	if e.laneManager == nil {
+		return 0
+	}
+	l, err := e.laneManager.GetLane(laneName)
+	if err != nil {
+		return 0
+	}
+	return l.Stats().MaxConcurrency
+}
+
+// simulatePlan predicts the schedule of plan. Layers run one after another;
+// within a layer each task takes the lane worker that frees up first. Waits
+// for resources, rate limits and other workflows' tasks are not modelled.
+func (e *Engine) simulatePlan(plan *dag.ExecutionPlan, history map[string]*durationSamples) *models.SimulationReport {
+	report := &models.SimulationReport{
+		Layers:       make([]models.SimulatedLayer, 0, len(plan.Layers)),
+		Tasks:        make([]models.SimulatedTask, 0, plan.TotalTasks),
+		CriticalPath: plan.CriticalPath,
+	}
+
+	var elapsed time.Duration
+	var peak lane.Resources
+	for layerIdx, layer := range plan.Layers {
+		layerEnd := elapsed
+		var layerResources lane.Resources
+		// workers holds, per lane, when each worker is free again.
+		workers := make(map[string][]time.Duration)
+
+		for _, taskID := range layer {
+			task, ok := plan.GetTask(taskID)
+			if !ok {
+				continue
+			}
+			duration, source, samples := e.estimateTaskDuration(task, history)
+
+			slots, ok := workers[task.Lane]
+			if !ok {
+				slots = make([]time.Duration, max(e.laneConcurrency(task.Lane), 1))
+				for i := range slots {
+					slots[i] = elapsed
+				}
+				workers[task.Lane] = slots
+			}
+			next := 0
+			for i := range slots {
+				if slots[i] < slots[next] {
+					next = i
+				}
+			}
+			start := slots[next]
+			slots[next] = start + duration
+			layerEnd = max(layerEnd, slots[next])
+
+			request := laneResources(task.Resources)
+			layerResources = layerResources.Add(request)
+			report.Tasks = append(report.Tasks, models.SimulatedTask{
+				ID:         task.ID,
+				Name:       task.Name,
+				Lane:       task.Lane,
+				Layer:      layerIdx,
+				StartMS:    durationMS(start),
+				DurationMS: durationMS(duration),
+				Estimate:   source,
+				Samples:    samples,
+				Resources:  resourcesModel(request),
+			})
+		}
+
+		report.Layers = append(report.Layers, models.SimulatedLayer{
+			Index:      layerIdx,
+			Tasks:      layer,
+			StartMS:    durationMS(elapsed),
+			DurationMS: durationMS(layerEnd - elapsed),
+			Resources:  resourcesModel(layerResources),
+		})
+		peak = lane.Resources{
+			CPU:      max(peak.CPU, layerResources.CPU),
+			MemoryMB: max(peak.MemoryMB, layerResources.MemoryMB),
+			GPU:      max(peak.GPU, layerResources.GPU),
+		}
+		elapsed = layerEnd
+	}
+
+	report.EstimatedDurationMS = durationMS(elapsed)
+	report.PeakResources = resourcesModel(peak)
+	return report
+}
+
+// resourcesModel converts r to its API form, or nil when r is zero.
+func resourcesModel(r lane.Resources) *models.TaskResources {
+	if r.IsZero() {
+		return nil
+	}
+	return &models.TaskResources{CPU: r.CPU, MemoryMB: r.MemoryMB, GPU: r.GPU}
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
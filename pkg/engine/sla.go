@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// defaultSLACheckInterval is how often running workflows are checked against
+// their SLA deadline unless configured otherwise.
+const defaultSLACheckInterval = 10 * time.Second
+
+// slaBroadcaster is implemented by event broadcasters that publish SLA
+// breaches. It is optional so that EventBroadcaster stays unchanged.
+type slaBroadcaster interface {
+	BroadcastWorkflowSLABreached(workflowID, name, status string, metadata map[string]string, deadline, breachedAt time.Time)
+}
+
+// slaRecorder is implemented by metrics recorders that count SLA breaches.
+// It is optional so that MetricsRecorder stays unchanged.
+type slaRecorder interface {
+	RecordSLABreach(workflowName string)
+}
+
+// slaDeadline returns the earlier of the bounds of sla for a run submitted
+// at submitted, or nil without an SLA.
+func slaDeadline(sla *models.SLA, submitted time.Time) *time.Time {
+	if sla == nil {
+		return nil
+	}
+	var deadline *time.Time
+	if sla.MaxDuration > 0 {
+		t := submitted.Add(time.Duration(sla.MaxDuration) * time.Second)
+		deadline = &t
+	}
+	if sla.FinishBy != nil && (deadline == nil || sla.FinishBy.Before(*deadline)) {
+		t := sla.FinishBy.UTC()
+		deadline = &t
+	}
+	return deadline
+}
+
+// watchSLAs checks the executing workflows against their SLA deadline every
+// interval until ctx is done.
+func (e *Engine) watchSLAs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.checkSLAs(now.UTC())
+		}
+	}
+}
+
+// checkSLAs records a breach for every executing workflow still unfinished
+// after its SLA deadline.
+func (e *Engine) checkSLAs(now time.Time) {
+	e.execMu.RLock()
+	execs := make([]*workflowExecution, 0, len(e.executions))
+	for _, exec := range e.executions {
+		execs = append(execs, exec)
+	}
+	e.execMu.RUnlock()
+
+	for _, exec := range execs {
+		exec.mu.Lock()
+		if !markSLABreached(exec.wfState, now) {
+			exec.mu.Unlock()
+			continue
+		}
+		err := e.taskWrites.flush(context.Background(), exec.workflowID)
+		var notifications []*storage.OutboxEntry
+		if err == nil {
+			notifications, err = e.notifications(exec.wfState, exec.wfState.Status, true)
+		}
+		if err == nil {
+			err = e.saveWorkflowNotifying(context.Background(), exec.wfState, notifications)
+		}
+		wf := *exec.wfState
+		exec.mu.Unlock()
+
+		if err != nil {
+			e.logger.Warn("failed to persist sla breach", "workflow_id", wf.ID, "error", err)
+		}
+		e.emitSLABreached(&wf)
+	}
+}
+
+// markSLABreached records the breach of a run unfinished after its SLA
+// deadline, unless it was recorded before. It reports whether it did.
+func markSLABreached(wf *storage.WorkflowState, now time.Time) bool {
+	if wf.SLADeadline == nil || wf.SLABreachedAt != nil || !now.After(*wf.SLADeadline) {
+		return false
+	}
+	wf.SLABreachedAt = &now
+	return true
+}
+
+// emitSLABreached logs, counts and publishes the SLA breach of wf.
+func (e *Engine) emitSLABreached(wf *storage.WorkflowState) {
+	e.logger.Warn("workflow breached sla",
+		"workflow_id", wf.ID,
+		"name", wf.Name,
+		"status", wf.Status,
+		"sla_deadline", *wf.SLADeadline,
+	)
+	if recorder, ok := e.metrics.(slaRecorder); ok {
+		recorder.RecordSLABreach(wf.Name)
+	}
+	if broadcaster, ok := e.events.(slaBroadcaster); ok {
+		broadcaster.BroadcastWorkflowSLABreached(wf.ID, wf.Name, wf.Status, wf.Metadata, *wf.SLADeadline, *wf.SLABreachedAt)
+	}
+}
+
+// slaStatus returns how wf fares against its SLA at now, or nil without an
+// SLA.
+func slaStatus(wf *storage.WorkflowState, now time.Time) *models.SLAStatus {
+	if wf.SLADeadline == nil {
+		return nil
+	}
+	status := &models.SLAStatus{Deadline: *wf.SLADeadline, BreachedAt: wf.SLABreachedAt}
+	switch {
+	case wf.SLABreachedAt != nil:
+		status.State = models.SLABreached
+	case wf.CompletedAt != nil && wf.CompletedAt.After(*wf.SLADeadline):
+		// Finished late before a check noticed.
+		status.State = models.SLABreached
+	case wf.CompletedAt != nil && wf.Status == workflowStatusCompleted:
+		status.State = models.SLAMet
+	case wf.CompletedAt != nil:
+		status.State = models.SLAUnmet
+	case now.After(*wf.SLADeadline):
+		status.State = models.SLABreached
+	default:
+		status.State = models.SLAPending
+	}
+	return status
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -48,105 +49,220 @@ type TaskResult struct {
 	StartedAt time.Time
 	EndedAt   time.Time
 	Retries   int
+	// DedupedFrom is the ID of the task whose execution this task shares.
+	DedupedFrom string
+	// Preemptions counts how often the task was preempted and requeued.
+	Preemptions int
+	// Deadline is when the task should have finished; zero means none.
+	Deadline time.Time
+	// DeadlineMissed reports that the task ended after its deadline.
+	DeadlineMissed bool
+	// Stalled reports that the running attempt has missed its heartbeat
+	// timeout and not heartbeated since.
+	Stalled bool
+	// Stalls counts how often the task stalled.
+	Stalls int
+	// Skipped reports that the task completed without running because its
+	// condition was false.
+	Skipped bool
 }
 
-// StateTracker tracks the state of all tasks in a workflow execution.
+// end records the end time of a task that reached a terminal state.
+func (r *TaskResult) end() {
+	r.EndedAt = time.Now()
+	r.DeadlineMissed = !r.Deadline.IsZero() && r.EndedAt.After(r.Deadline)
+}
+
+// trackerShards is the number of shards a StateTracker spreads tasks over.
+const trackerShards = 64
+
+// stateChangeFunc is invoked on task state transitions.
+type stateChangeFunc func(taskID string, oldState, newState TaskState, result TaskResult)
+
+// stallFunc is invoked when a running task stalls.
+type stallFunc func(taskID string, result TaskResult)
+
+// trackerShard holds the results of the tasks hashing to it.
+type trackerShard struct {
+	mu      sync.RWMutex
+	results map[string]*TaskResult
+}
+
+// StateTracker tracks the state of all tasks in a workflow execution. Tasks
+// are sharded by ID so that transitions of different tasks in very large
+// workflows do not contend on one lock.
 type StateTracker struct {
-	mu            sync.RWMutex
-	results       map[string]*TaskResult
-	onStateChange func(taskID string, oldState, newState TaskState, result TaskResult)
+	shards        [trackerShards]trackerShard
+	onStateChange atomic.Pointer[stateChangeFunc]
+	onStall       atomic.Pointer[stallFunc]
 }
 
 // newStateTracker creates a new StateTracker.
 func newStateTracker() *StateTracker {
-	return &StateTracker{
-		results: make(map[string]*TaskResult),
+	return &StateTracker{}
+}
+
+// shard returns the shard of taskID, hashing it with FNV-1a.
+func (t *StateTracker) shard(taskID string) *trackerShard {
+	h := uint32(2166136261)
+	for i := 0; i < len(taskID); i++ {
+		h ^= uint32(taskID[i])
+		h *= 16777619
 	}
+	return &t.shards[h%trackerShards]
 }
 
-// InitTasks initialises all given task IDs to TaskStatePending.
+// InitTasks initialises all given task IDs to TaskStatePending. The results
+// are allocated in one block.
 func (t *StateTracker) InitTasks(taskIDs []string) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	for _, id := range taskIDs {
-		t.results[id] = &TaskResult{
-			TaskID: id,
-			State:  TaskStatePending,
+	results := make([]TaskResult, len(taskIDs))
+	for i, id := range taskIDs {
+		results[i] = TaskResult{TaskID: id, State: TaskStatePending}
+		shard := t.shard(id)
+		shard.mu.Lock()
+		if shard.results == nil {
+			shard.results = make(map[string]*TaskResult, len(taskIDs)/trackerShards+1)
 		}
+		shard.results[id] = &results[i]
+		shard.mu.Unlock()
 	}
 }
 
-// SetState updates the state of a task.
-func (t *StateTracker) SetState(taskID string, state TaskState) {
-	var (
-		oldState TaskState
-		snapshot TaskResult
-		hook     func(taskID string, oldState, newState TaskState, result TaskResult)
-	)
-
-	t.mu.Lock()
-	r, ok := t.results[taskID]
+// update applies change to the result of taskID under its shard lock, then
+// invokes the state change callback if the state changed.
+func (t *StateTracker) update(taskID string, change func(r *TaskResult)) {
+	shard := t.shard(taskID)
+	shard.mu.Lock()
+	if shard.results == nil {
+		shard.results = make(map[string]*TaskResult)
+	}
+	r, ok := shard.results[taskID]
 	if !ok {
 		r = &TaskResult{TaskID: taskID}
-		t.results[taskID] = r
-	}
-	oldState = r.State
-	r.State = state
-	switch state {
-	case TaskStateRunning:
-		r.StartedAt = time.Now()
-	case TaskStateCompleted, TaskStateFailed, TaskStateCancelled:
-		r.EndedAt = time.Now()
+		shard.results[taskID] = r
 	}
-	snapshot = *r
-	hook = t.onStateChange
-	t.mu.Unlock()
+	oldState := r.State
+	change(r)
+	snapshot := *r
+	shard.mu.Unlock()
 
-	if hook != nil && oldState != state {
-		hook(taskID, oldState, state, snapshot)
+	if hook := t.onStateChange.Load(); hook != nil && oldState != snapshot.State {
+		(*hook)(taskID, oldState, snapshot.State, snapshot)
 	}
 }
 
+// SetState updates the state of a task.
+func (t *StateTracker) SetState(taskID string, state TaskState) {
+	t.update(taskID, func(r *TaskResult) {
+		r.State = state
+		switch state {
+		case TaskStateRunning:
+			r.StartedAt = time.Now()
+			r.Error = nil
+			r.Stalled = false
+		case TaskStateCompleted, TaskStateFailed, TaskStateCancelled:
+			r.end()
+		}
+	})
+}
+
 // SetFailed marks a task as failed with the given error and retry count.
 func (t *StateTracker) SetFailed(taskID string, err error, retries int) {
-	var (
-		oldState TaskState
-		snapshot TaskResult
-		hook     func(taskID string, oldState, newState TaskState, result TaskResult)
-	)
-
-	t.mu.Lock()
-	r, ok := t.results[taskID]
-	if !ok {
-		r = &TaskResult{TaskID: taskID}
-		t.results[taskID] = r
-	}
-	oldState = r.State
-	r.State = TaskStateFailed
-	r.Error = err
-	r.Retries = retries
-	r.EndedAt = time.Now()
-	snapshot = *r
-	hook = t.onStateChange
-	t.mu.Unlock()
+	t.update(taskID, func(r *TaskResult) {
+		r.State = TaskStateFailed
+		r.Error = err
+		r.Retries = retries
+		r.end()
+	})
+}
+
+// SetSkipped marks a task as completed without having run.
+func (t *StateTracker) SetSkipped(taskID string) {
+	t.update(taskID, func(r *TaskResult) {
+		r.State = TaskStateCompleted
+		r.Skipped = true
+		r.end()
+	})
+}
+
+// SetRetrying moves a task back to retrying after an attempt failed with err.
+func (t *StateTracker) SetRetrying(taskID string, err error) {
+	t.update(taskID, func(r *TaskResult) {
+		r.State = TaskStateRetrying
+		r.Error = err
+	})
+}
+
+// SetPreempted moves a running task back to scheduled after it was preempted
+// and counts the preemption.
+func (t *StateTracker) SetPreempted(taskID string) {
+	t.update(taskID, func(r *TaskResult) {
+		r.State = TaskStateScheduled
+		r.Preemptions++
+	})
+}
 
-	if hook != nil && oldState != TaskStateFailed {
-		hook(taskID, oldState, TaskStateFailed, snapshot)
+// SetStalled records whether the running attempt of taskID is stalled. It
+// does not change the task state; each new stall is counted and reported to
+// the stall callback.
+func (t *StateTracker) SetStalled(taskID string, stalled bool) {
+	var stalledNow bool
+	var snapshot TaskResult
+	t.update(taskID, func(r *TaskResult) {
+		if stalled && !r.Stalled {
+			r.Stalls++
+			stalledNow = true
+		}
+		r.Stalled = stalled
+		snapshot = *r
+	})
+	if hook := t.onStall.Load(); hook != nil && stalledNow {
+		(*hook)(taskID, snapshot)
 	}
 }
 
+// SetDedupedFrom records that taskID shares the execution of originalID.
+// It does not change the task state.
+func (t *StateTracker) SetDedupedFrom(taskID, originalID string) {
+	t.update(taskID, func(r *TaskResult) {
+		r.DedupedFrom = originalID
+	})
+}
+
+// SetDeadline records when taskID should finish. It does not change the task
+// state.
+func (t *StateTracker) SetDeadline(taskID string, deadline time.Time) {
+	t.update(taskID, func(r *TaskResult) {
+		r.Deadline = deadline
+	})
+}
+
 // SetOnStateChange sets a callback invoked on task state transitions.
 func (t *StateTracker) SetOnStateChange(fn func(taskID string, oldState, newState TaskState, result TaskResult)) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	t.onStateChange = fn
+	if fn == nil {
+		t.onStateChange.Store(nil)
+		return
+	}
+	hook := stateChangeFunc(fn)
+	t.onStateChange.Store(&hook)
+}
+
+// SetOnStall sets a callback invoked when a running task stalls.
+func (t *StateTracker) SetOnStall(fn func(taskID string, result TaskResult)) {
+	if fn == nil {
+		t.onStall.Store(nil)
+		return
+	}
+	hook := stallFunc(fn)
+	t.onStall.Store(&hook)
 }
 
 // GetResult returns a copy of the TaskResult for the given task ID.
 func (t *StateTracker) GetResult(taskID string) (*TaskResult, bool) {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	r, ok := t.results[taskID]
+	shard := t.shard(taskID)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	r, ok := shard.results[taskID]
 	if !ok {
 		return nil, false
 	}
@@ -154,14 +270,35 @@ func (t *StateTracker) GetResult(taskID string) (*TaskResult, bool) {
 	return &copy, true
 }
 
+// Range calls fn with a copy of every task result, one shard at a time, until
+// fn returns false. Only one shard is locked at a time, and never while fn
+// runs, so iterating a large workflow does not stall its transitions; results
+// reflect each shard at the moment it was visited.
+func (t *StateTracker) Range(fn func(result TaskResult) bool) {
+	var batch []TaskResult
+	for i := range t.shards {
+		shard := &t.shards[i]
+		shard.mu.RLock()
+		batch = batch[:0]
+		for _, r := range shard.results {
+			batch = append(batch, *r)
+		}
+		shard.mu.RUnlock()
+
+		for _, r := range batch {
+			if !fn(r) {
+				return
+			}
+		}
+	}
+}
+
 // Results returns a snapshot of all task results.
 func (t *StateTracker) Results() map[string]*TaskResult {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	out := make(map[string]*TaskResult, len(t.results))
-	for k, v := range t.results {
-		copy := *v
-		out[k] = &copy
-	}
+	out := make(map[string]*TaskResult)
+	t.Range(func(r TaskResult) bool {
+		out[r.TaskID] = &r
+		return true
+	})
 	return out
 }
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// taskEnvKey is the context key of the running task's environment.
+type taskEnvKey struct{}
+
+// TaskEnv returns the environment resolved for the task running with ctx:
+// the variables of its environment bundles and its secrets. It returns nil
+// for tasks without either. The map must not be modified.
+func TaskEnv(ctx context.Context) map[string]string {
+	env, _ := ctx.Value(taskEnvKey{}).(map[string]string)
+	return env
+}
+
+// envResolver resolves the environment bundles and named secrets tasks
+// request from the orchestration config.
+type envResolver struct {
+	secrets      map[string]string
+	environments map[string]map[string]string
+}
+
+func newEnvResolver(cfg config.OrchestrationConfig) *envResolver {
+	return &envResolver{secrets: cfg.Secrets, environments: cfg.Environments}
+}
+
+// check reports an unknown environment bundle or secret requested by a task
+// of req.
+func (r *envResolver) check(req *models.WorkflowRequest) error {
+	for _, task := range req.Tasks {
+		for _, bundle := range task.Env {
+			if _, ok := r.environments[bundle]; !ok {
+				return errs.Newf(errs.BadRequest, "task %s: unknown environment %q", task.ID, bundle)
+			}
+		}
+		for name, secret := range task.Secrets {
+			if _, ok := r.secrets[secret]; !ok {
+				return errs.Newf(errs.BadRequest, "task %s: unknown secret %q for %s", task.ID, secret, name)
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the environment of task and the secret values in it.
+// Bundle values that are secret references count as secrets too.
+func (r *envResolver) resolve(ctx context.Context, task *dag.Task) (map[string]string, []string, error) {
+	if len(task.Env) == 0 && len(task.Secrets) == 0 {
+		return nil, nil, nil
+	}
+
+	env := make(map[string]string)
+	var secrets []string
+	for _, bundle := range task.Env {
+		vars, ok := r.environments[bundle]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown environment %q", bundle)
+		}
+		for name, value := range vars {
+			if !config.IsSecretRef(value) {
+				env[name] = value
+				continue
+			}
+			resolved, err := config.ResolveSecret(ctx, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("environment %s: %s: %w", bundle, name, err)
+			}
+			env[name] = resolved
+			secrets = append(secrets, resolved)
+		}
+	}
+
+	names := make([]string, 0, len(task.Secrets))
+	for name := range task.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref, ok := r.secrets[task.Secrets[name]]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown secret %q", task.Secrets[name])
+		}
+		resolved, err := config.ResolveSecret(ctx, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("secret %s: %w", task.Secrets[name], err)
+		}
+		env[name] = resolved
+		secrets = append(secrets, resolved)
+	}
+	return env, secrets, nil
+}
+
+// withTaskEnv resolves the environment of the runner's task and returns ctx
+// carrying it, with the redactor of its secret values.
+func (r *taskRunner) withTaskEnv(ctx context.Context) (context.Context, *redactor, error) {
+	if r.env == nil {
+		return ctx, nil, nil
+	}
+	env, secrets, err := r.env.resolve(ctx, r.task)
+	if err != nil || env == nil {
+		return ctx, nil, err
+	}
+	return context.WithValue(ctx, taskEnvKey{}, maps.Clone(env)), newRedactor(secrets), nil
+}
+
+// redactor masks secret values in text.
+type redactor struct {
+	replacer *strings.Replacer
+}
+
+// newRedactor returns a redactor of secrets, or nil if there is nothing to
+// redact.
+func newRedactor(secrets []string) *redactor {
+	// Longer values first, so a secret containing another is masked whole.
+	secrets = append([]string(nil), secrets...)
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	var pairs []string
+	for _, secret := range secrets {
+		if secret != "" {
+			pairs = append(pairs, secret, config.MaskSecret(secret))
+		}
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+	return &redactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// redactError returns err with secret values masked in its message. The
+// original error stays reachable through errors.Is and errors.As.
+func (r *redactor) redactError(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+	msg := err.Error()
+	redacted := r.replacer.Replace(msg)
+	if redacted == msg {
+		return err
+	}
+	return &redactedError{msg: redacted, err: err}
+}
+
+// redactedError is an error whose message had secret values masked.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
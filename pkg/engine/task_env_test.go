@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_InjectsTaskEnvAndRedactsSecrets(t *testing.T) {
+	t.Setenv("GOCLAW_TEST_TASK_PASSWORD", "s3cr3t-pass")
+	t.Setenv("GOCLAW_TEST_TASK_USER", "reporter")
+
+	cfg := minConfig()
+	cfg.Orchestration.Secrets = map[string]string{"db_password": "env://GOCLAW_TEST_TASK_PASSWORD"}
+	cfg.Orchestration.Environments = map[string]map[string]string{
+		"reporting": {"DB_HOST": "db.internal", "DB_USER": "env://GOCLAW_TEST_TASK_USER"},
+	}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var env map[string]string
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name: "report",
+		Tasks: []models.TaskDefinition{{
+			ID:      "export",
+			Name:    "export",
+			Type:    "function",
+			Env:     []string{"reporting"},
+			Secrets: map[string]string{"DB_PASSWORD": "db_password"},
+		}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"export": func(ctx context.Context) error {
+				env = TaskEnv(ctx)
+				return errors.New("login failed for " + env["DB_USER"] + " with " + env["DB_PASSWORD"])
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	want := map[string]string{"DB_HOST": "db.internal", "DB_USER": "reporter", "DB_PASSWORD": "s3cr3t-pass"}
+	for name, value := range want {
+		if env[name] != value {
+			t.Fatalf("TaskEnv()[%s] = %q, want %q", name, env[name], value)
+		}
+	}
+
+	status, err := eng.GetWorkflowStatusResponse(ctx, resp.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatusResponse() error = %v", err)
+	}
+	taskErr := status.Tasks[0].Error
+	if strings.Contains(taskErr, "s3cr3t-pass") || strings.Contains(taskErr, "reporter") {
+		t.Fatalf("task error leaked a secret: %q", taskErr)
+	}
+	if !strings.Contains(taskErr, "login failed for ****** with ******") {
+		t.Fatalf("task error = %q, want redacted message", taskErr)
+	}
+}
+
+func TestEngine_RejectsUnknownTaskSecret(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	_, err = eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "report",
+		Tasks: []models.TaskDefinition{{
+			ID:      "export",
+			Name:    "export",
+			Type:    "function",
+			Secrets: map[string]string{"DB_PASSWORD": "missing"},
+		}},
+	}, SubmitWorkflowOptions{})
+	if !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected BadRequest, got %v", err)
+	}
+}
+
+func TestRedactor_MasksLongestSecretFirst(t *testing.T) {
+	r := newRedactor([]string{"abc", "abcdef", ""})
+	cause := errors.New("token abcdef, prefix abc")
+
+	err := r.redactError(cause)
+	if err.Error() != "token ******, prefix ******" {
+		t.Fatalf("redacted error = %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected redacted error to wrap its cause")
+	}
+	if newRedactor(nil).redactError(cause) != cause {
+		t.Fatal("expected nil redactor to return the error unchanged")
+	}
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+const (
+	defaultTaskLogLines = 1000
+	defaultTaskLogTasks = 1000
+
+	// maxTaskLogLineBytes splits longer lines so one runaway line cannot
+	// use up a task's log.
+	maxTaskLogLineBytes = 16 << 10
+)
+
+// Task log streams.
+const (
+	TaskLogStdout = "stdout"
+	TaskLogStderr = "stderr"
+)
+
+// TaskLogLine is one line of output of a task.
+type TaskLogLine struct {
+	// Seq numbers the task's lines from 1, across attempts.
+	Seq int
+	// Time is when the line was written.
+	Time time.Time
+	// Stream is TaskLogStdout or TaskLogStderr.
+	Stream string
+	// Text is the line without its newline.
+	Text string
+}
+
+// taskLogKey is the context key of the running task's taskLog.
+type taskLogKey struct{}
+
+// TaskLog returns a writer appending to the output log of the task running
+// with ctx, one line per newline-terminated write. Close flushes a final
+// unterminated line. It discards the output of tasks run outside the
+// engine.
+func TaskLog(ctx context.Context, stream string) io.WriteCloser {
+	log, _ := ctx.Value(taskLogKey{}).(*taskLog)
+	return &taskLogWriter{log: log, stream: stream}
+}
+
+// taskLog is the bounded output log of one task.
+type taskLog struct {
+	mu       sync.Mutex
+	maxLines int
+	lines    []TaskLogLine
+	seq      int
+}
+
+func (l *taskLog) append(stream, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	if len(l.lines) == l.maxLines {
+		copy(l.lines, l.lines[1:])
+		l.lines = l.lines[:len(l.lines)-1]
+	}
+	l.lines = append(l.lines, TaskLogLine{Seq: l.seq, Time: time.Now().UTC(), Stream: stream, Text: text})
+}
+
+// since returns the lines after sequence number after.
+func (l *taskLog) since(after int) []TaskLogLine {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, line := range l.lines {
+		if line.Seq > after {
+			return append([]TaskLogLine(nil), l.lines[i:]...)
+		}
+	}
+	return nil
+}
+
+// taskLogWriter splits writes into lines of a taskLog.
+type taskLogWriter struct {
+	log     *taskLog
+	stream  string
+	partial []byte
+}
+
+func (w *taskLogWriter) Write(p []byte) (int, error) {
+	if w.log == nil {
+		return len(p), nil
+	}
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.partial = append(w.partial, p...)
+			for len(w.partial) >= maxTaskLogLineBytes {
+				w.log.append(w.stream, string(w.partial[:maxTaskLogLineBytes]))
+				w.partial = w.partial[maxTaskLogLineBytes:]
+			}
+			break
+		}
+		line := append(w.partial, p[:i]...)
+		w.partial = w.partial[:0]
+		for len(line) > maxTaskLogLineBytes {
+			w.log.append(w.stream, string(line[:maxTaskLogLineBytes]))
+			line = line[maxTaskLogLineBytes:]
+		}
+		w.log.append(w.stream, string(bytes.TrimSuffix(line, []byte("\r"))))
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *taskLogWriter) Close() error {
+	if w.log != nil && len(w.partial) > 0 {
+		w.log.append(w.stream, string(w.partial))
+		w.partial = nil
+	}
+	return nil
+}
+
+// taskLogStore keeps the logs of the most recent tasks.
+type taskLogStore struct {
+	mu       sync.Mutex
+	maxLines int
+	maxTasks int
+	logs     map[string]*taskLog
+	order    []string
+}
+
+func newTaskLogStore(cfg config.TaskLogsConfig) *taskLogStore {
+	s := &taskLogStore{maxLines: cfg.MaxLines, maxTasks: cfg.MaxTasks, logs: make(map[string]*taskLog)}
+	if s.maxLines <= 0 {
+		s.maxLines = defaultTaskLogLines
+	}
+	if s.maxTasks <= 0 {
+		s.maxTasks = defaultTaskLogTasks
+	}
+	return s
+}
+
+func taskLogID(workflowID, taskID string) string {
+	return workflowID + "/" + taskID
+}
+
+// open returns the log of a task, creating it and dropping the oldest log
+// if the store is full.
+func (s *taskLogStore) open(workflowID, taskID string) *taskLog {
+	id := taskLogID(workflowID, taskID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if log, ok := s.logs[id]; ok {
+		return log
+	}
+	if len(s.order) == s.maxTasks {
+		delete(s.logs, s.order[0])
+		s.order = s.order[1:]
+	}
+	log := &taskLog{maxLines: s.maxLines}
+	s.logs[id] = log
+	s.order = append(s.order, id)
+	return log
+}
+
+// withTask returns ctx carrying the log of a task for TaskLog.
+func (s *taskLogStore) withTask(ctx context.Context, workflowID, taskID string) context.Context {
+	return context.WithValue(ctx, taskLogKey{}, s.open(workflowID, taskID))
+}
+
+// since returns the lines of a task after sequence number after.
+func (s *taskLogStore) since(workflowID, taskID string, after int) []TaskLogLine {
+	s.mu.Lock()
+	log, ok := s.logs[taskLogID(workflowID, taskID)]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return log.since(after)
+}
+
+// TaskLogs returns the output lines of a task with sequence numbers after
+// after, oldest first. Only the most recent lines of recent tasks are kept.
+func (e *Engine) TaskLogs(ctx context.Context, workflowID, taskID string, after int) ([]TaskLogLine, error) {
+	if _, err := e.storage.GetTask(ctx, workflowID, taskID); err != nil {
+		return nil, err
+	}
+	return e.taskLogs.since(workflowID, taskID, after), nil
+}
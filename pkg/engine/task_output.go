@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/jsonschema"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// MaxTaskResultBytes bounds the JSON encoding of a task result.
+const MaxTaskResultBytes = 256 << 10
+
+// ErrOutputSchemaViolation is the error of a task whose result does not match
+// its output schema. The task fails without being retried, since rerunning it
+// would not change the shape of what it returns.
+var ErrOutputSchemaViolation = errors.New("task output violates its schema")
+
+// outputKey is the context key of the running attempt's taskOutput.
+type outputKey struct{}
+
+// SetTaskResult sets the result of the task running with ctx, replacing any
+// result set earlier in the same attempt. The result must encode to JSON; it
+// is checked against the task's output schema and stored with the task once
+// the attempt succeeds.
+func SetTaskResult(ctx context.Context, result any) error {
+	out, ok := ctx.Value(outputKey{}).(*taskOutput)
+	if !ok {
+		return errs.New(errs.BadRequest, "task results are only available to tasks run by the engine")
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errs.Wrap(err, errs.BadRequest, "encode task result")
+	}
+	if len(data) > MaxTaskResultBytes {
+		return errs.Newf(errs.BadRequest, "task result exceeds %d bytes", MaxTaskResultBytes)
+	}
+	// Results are stored and validated in the form they are read back in.
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errs.Wrap(err, errs.BadRequest, "decode task result")
+	}
+	out.set(value)
+	return nil
+}
+
+// UpstreamResult returns the result of the completed task taskID of the
+// workflow run the task running with ctx belongs to, nil if it set none, and
+// records it as an input of the running task. The result is shared and must
+// not be modified.
+func UpstreamResult(ctx context.Context, taskID string) (any, error) {
+	out, ok := ctx.Value(outputKey{}).(*taskOutput)
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "task results are only available to tasks run by the engine")
+	}
+	result, err := out.upstream(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := ctx.Value(lineageKey{}).(*taskLineage); ok {
+		_ = l.record(storage.TaskInput{TaskID: taskID})
+	}
+	return result, nil
+}
+
+// TaskDependencies returns the IDs of the tasks the task running with ctx
+// depends on.
+func TaskDependencies(ctx context.Context) ([]string, error) {
+	out, ok := ctx.Value(outputKey{}).(*taskOutput)
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "task results are only available to tasks run by the engine")
+	}
+	return slices.Clone(out.deps), nil
+}
+
+// workflowOutputs stores the results of the tasks of one running workflow in
+// their task states, which exec.mu guards.
+type workflowOutputs struct {
+	exec *workflowExecution
+}
+
+// result returns the result of taskID and whether the task is sensitive.
+func (o *workflowOutputs) result(taskID string) (any, bool) {
+	o.exec.mu.Lock()
+	defer o.exec.mu.Unlock()
+	taskState, ok := o.exec.wfState.TaskStatus[taskID]
+	if !ok {
+		return nil, false
+	}
+	return taskState.Result, taskState.Sensitive
+}
+
+// setResult sets the result of taskID, which did not execute but shares the
+// result of another execution.
+func (o *workflowOutputs) setResult(taskID string, value any) {
+	o.exec.mu.Lock()
+	defer o.exec.mu.Unlock()
+	if taskState, ok := o.exec.wfState.TaskStatus[taskID]; ok {
+		taskState.Result = value
+	}
+}
+
+// withTask returns ctx carrying a fresh output of task for SetTaskResult.
+// Each attempt gets its own, so results of failed attempts are dropped. The
+// output is journaled with the transition ending the attempt.
+func (o *workflowOutputs) withTask(ctx context.Context, task *dag.Task) (context.Context, *taskOutput) {
+	out := &taskOutput{exec: o.exec, taskID: task.ID, deps: task.Deps}
+	o.exec.mu.Lock()
+	if o.exec.attempts == nil {
+		o.exec.attempts = make(map[string]*taskOutput)
+	}
+	o.exec.attempts[task.ID] = out
+	o.exec.mu.Unlock()
+	return context.WithValue(ctx, outputKey{}, out), out
+}
+
+// taskOutput is the result of one attempt of a task.
+type taskOutput struct {
+	exec   *workflowExecution
+	taskID string
+	deps   []string
+
+	mu    sync.Mutex
+	value any
+	// chunks is the number of output chunks the attempt streamed.
+	chunks int64
+	// inputs are the upstream results the attempt read, by task ID, and
+	// decisions the nondeterministic decisions it made, for the journal.
+	inputs    map[string]any
+	decisions []storage.JournalDecision
+}
+
+func (o *taskOutput) set(value any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.value = value
+}
+
+func (o *taskOutput) get() any {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.value
+}
+
+func (o *taskOutput) upstream(taskID string) (any, error) {
+	o.exec.mu.Lock()
+	defer o.exec.mu.Unlock()
+	taskState, ok := o.exec.wfState.TaskStatus[taskID]
+	if !ok {
+		return nil, errs.Newf(errs.NotFound, "task %s not found", taskID)
+	}
+	if taskState.Status != taskStatusCompleted {
+		return nil, errs.Newf(errs.Conflict, "task %s is not completed: %s", taskID, taskState.Status)
+	}
+	o.recordInput(taskID, taskState)
+	return taskState.Result, nil
+}
+
+// recordInput records the result of taskState as read by the attempt. The
+// caller holds o.exec.mu.
+func (o *taskOutput) recordInput(taskID string, taskState *storage.TaskState) {
+	value := taskState.Result
+	if taskState.Sensitive {
+		value = models.RedactedValue
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.inputs == nil {
+		o.inputs = make(map[string]any)
+	}
+	o.inputs[taskID] = value
+}
+
+// journal records the attempt on entry, the journal entry of the
+// transition ending it, with its result when it succeeded. The caller holds
+// o.exec.mu.
+func (o *taskOutput) journal(entry *storage.JournalEntry, taskState *storage.TaskState, succeeded bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entry.Inputs = o.inputs
+	entry.Decisions = o.decisions
+	if succeeded && taskState.Result != nil {
+		entry.Output = taskState.Result
+		if taskState.Sensitive {
+			entry.Output = models.RedactedValue
+		}
+	}
+}
+
+// store saves the result in the task state, to be persisted with the
+// task's completion.
+func (o *taskOutput) store() {
+	value := o.get()
+	o.exec.mu.Lock()
+	defer o.exec.mu.Unlock()
+	if taskState, ok := o.exec.wfState.TaskStatus[o.taskID]; ok {
+		taskState.Result = value
+	}
+}
+
+// checkOutputSchemas returns a BadRequest error when the output schema of a
+// task of req does not compile.
+func checkOutputSchemas(req *models.WorkflowRequest) error {
+	for _, task := range req.Tasks {
+		if task.OutputSchema == nil {
+			continue
+		}
+		if _, err := compileOutputSchema(task.ID, task.OutputSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileOutputSchema compiles the output schema of a task, reporting errors
+// as BadRequest.
+func compileOutputSchema(taskID string, schema map[string]interface{}) (*jsonschema.Schema, error) {
+	compiled, err := jsonschema.Compile(schema)
+	if err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, fmt.Sprintf("task %s output schema", taskID))
+	}
+	return compiled, nil
+}
+
+// checkOutput validates the result of an attempt, nil when the task set
+// none, against the task's output schema.
+func (r *taskRunner) checkOutput(out *taskOutput) error {
+	schema, ok := r.task.Output.(map[string]interface{})
+	if !ok || schema == nil {
+		return nil
+	}
+	compiled, err := compileOutputSchema(r.task.ID, schema)
+	if err != nil {
+		return err
+	}
+	var value any
+	if out != nil {
+		value = out.get()
+	}
+	if err := compiled.Validate(value); err != nil {
+		return fmt.Errorf("%w: %v", ErrOutputSchemaViolation, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestOutputSchema_ValidatesTaskResults(t *testing.T) {
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"required":   []interface{}{"rows"},
+		"properties": map[string]interface{}{"rows": map[string]interface{}{"type": "integer", "minimum": 0}},
+	}
+	attempts := 0
+	req := &models.WorkflowRequest{
+		Name: "outputs",
+		Tasks: []models.TaskDefinition{
+			{ID: "count", Name: "count", Type: "function", OutputSchema: schema},
+			{ID: "bad", Name: "bad", Type: "function", Retries: 3, OutputSchema: schema},
+			{ID: "report", Name: "report", Type: "function", DependsOn: []string{"bad"}},
+		},
+	}
+	resp, err := eng.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeSync, TaskFns: map[string]func(context.Context) error{
+		"count": func(ctx context.Context) error {
+			return SetTaskResult(ctx, struct {
+				Rows int `json:"rows"`
+			}{Rows: 3})
+		},
+		"bad": func(ctx context.Context) error {
+			attempts++
+			return SetTaskResult(ctx, map[string]any{"rows": -1})
+		},
+		"report": func(context.Context) error { return nil },
+	}})
+	if err != nil || resp.Status != workflowStatusFailed || !strings.Contains(resp.Error, ErrOutputSchemaViolation.Error()+": /rows: -1 is less than 0") {
+		t.Fatalf("SubmitWorkflowRuntime() = %+v, %v, want a schema violation", resp, err)
+	}
+	if attempts != 1 {
+		t.Fatalf("bad ran %d times, want 1", attempts)
+	}
+
+	tasks, _, err := eng.ListWorkflowTasksResponse(ctx, resp.ID, models.TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListWorkflowTasksResponse() error = %v", err)
+	}
+	byID := make(map[string]models.TaskStatus)
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	if got := byID["count"]; got.Status != taskStatusCompleted || got.Result.(map[string]interface{})["rows"] != float64(3) {
+		t.Fatalf("count = %+v, want completed with its result", got)
+	}
+	if got := byID["bad"]; got.Status != taskStatusFailed || got.Result != nil {
+		t.Fatalf("bad = %+v, want failed without a result", got)
+	}
+	if got := byID["report"]; got.Status == taskStatusCompleted {
+		t.Fatalf("report ran after its dependency produced an invalid result")
+	}
+
+	req.Tasks[0].OutputSchema = map[string]interface{}{"$ref": "#/definitions/count"}
+	if err := eng.ValidateWorkflowRequest(ctx, req); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("ValidateWorkflowRequest() with an unsupported schema error = %v, want BadRequest", err)
+	}
+	if err := SetTaskResult(ctx, 1); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("SetTaskResult() without a task error = %v, want BadRequest", err)
+	}
+}
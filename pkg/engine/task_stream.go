@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// outputStreamBroadcaster is implemented by event broadcasters that publish
+// the output tasks stream. It is optional so that EventBroadcaster stays
+// unchanged.
+type outputStreamBroadcaster interface {
+	BroadcastTaskOutput(workflowID, taskID string, seq int64, chunk string)
+}
+
+// StreamTaskOutput publishes chunk, the next piece of the output the task
+// running with ctx is generating such as LLM tokens, to the WebSocket and
+// gRPC watchers of its workflow run, so that they can show it before the task
+// finishes. Chunks are numbered from 1 in each attempt, so a watcher seeing 1
+// again drops what the failed attempt streamed. They are not stored: the task
+// still sets its result with SetTaskResult. Streaming counts as a heartbeat.
+// Like their results in state change events, which have no caller to
+// authorize, the output of sensitive tasks is withheld from watchers.
+func StreamTaskOutput(ctx context.Context, chunk string) error {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return errs.New(errs.BadRequest, "output streaming is only available to tasks run by the engine")
+	}
+	out, ok := ctx.Value(outputKey{}).(*taskOutput)
+	if !ok {
+		return errs.New(errs.BadRequest, "output streaming is only available to tasks run by the engine")
+	}
+	Heartbeat(ctx)
+	if chunk == "" {
+		return nil
+	}
+	broadcaster, ok := v.engine.events.(outputStreamBroadcaster)
+	if !ok || out.sensitive() {
+		return nil
+	}
+
+	// Chunks are published under the lock so that watchers get them in
+	// order when the task streams from several goroutines.
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	out.chunks++
+	broadcaster.BroadcastTaskOutput(v.exec.wfState.ID, out.taskID, out.chunks, chunk)
+	return nil
+}
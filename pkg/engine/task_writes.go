@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// taskWriteModeBatch buffers task transitions and commits them per workflow.
+const taskWriteModeBatch = "batch"
+
+// taskWriter persists task state transitions write-behind: transitions are
+// buffered per workflow, coalesced so that only the latest state of each task
+// is written, and committed together every flush interval or once a workflow
+// has MaxBatch tasks buffered. The engine has no writer in sync mode; the
+// flush and lifecycle methods are no-ops on a nil writer.
+//
+// Callers flush a workflow before persisting its workflow record, so the
+// record never gets ahead of its tasks; a crash only loses the transitions of
+// the last interval, and recovery re-runs them.
+type taskWriter struct {
+	store    storage.Storage
+	logger   appLogger
+	interval time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending map[string]map[string]*storage.TaskState // workflow ID -> task ID -> state
+
+	// flushMu serializes flushes so that an older state of a task is never
+	// written after a newer one.
+	flushMu sync.Mutex
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newTaskWriter builds a writer from cfg. It returns nil unless batch mode is
+// configured.
+func newTaskWriter(cfg config.TaskWritesConfig, store storage.Storage, logger appLogger) *taskWriter {
+	if cfg.Mode != taskWriteModeBatch {
+		return nil
+	}
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = 50 * time.Millisecond
+	}
+	return &taskWriter{
+		store:    store,
+		logger:   logger,
+		interval: interval,
+		maxBatch: max(cfg.MaxBatch, 1),
+		pending:  make(map[string]map[string]*storage.TaskState),
+		kick:     make(chan struct{}, 1),
+	}
+}
+
+// save buffers a copy of task.
+func (w *taskWriter) save(workflowID string, task *storage.TaskState) {
+	copied := *task
+
+	w.mu.Lock()
+	tasks := w.pending[workflowID]
+	if tasks == nil {
+		tasks = make(map[string]*storage.TaskState)
+		w.pending[workflowID] = tasks
+	}
+	tasks[task.ID] = &copied
+	full := len(tasks) >= w.maxBatch
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// flush commits the buffered tasks of workflowID. On failure the tasks are
+// buffered again unless a newer state was buffered meanwhile.
+func (w *taskWriter) flush(ctx context.Context, workflowID string) error {
+	if w == nil {
+		return nil
+	}
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	tasks := w.pending[workflowID]
+	delete(w.pending, workflowID)
+	w.mu.Unlock()
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	batch := make([]*storage.TaskState, 0, len(tasks))
+	for _, task := range tasks {
+		batch = append(batch, task)
+	}
+	err := w.write(ctx, workflowID, batch)
+	if err == nil {
+		return nil
+	}
+	var notFound *storage.NotFoundError
+	if errors.As(err, &notFound) {
+		// The workflow was deleted; its tasks have nowhere to go.
+		return nil
+	}
+
+	w.mu.Lock()
+	requeue := w.pending[workflowID]
+	if requeue == nil {
+		requeue = make(map[string]*storage.TaskState, len(tasks))
+		w.pending[workflowID] = requeue
+	}
+	for id, task := range tasks {
+		if _, newer := requeue[id]; !newer {
+			requeue[id] = task
+		}
+	}
+	w.mu.Unlock()
+	return err
+}
+
+// write commits batch in one call when the storage supports it.
+func (w *taskWriter) write(ctx context.Context, workflowID string, batch []*storage.TaskState) error {
+	if saver, ok := w.store.(storage.TaskBatchSaver); ok {
+		return saver.SaveTasks(ctx, workflowID, batch)
+	}
+	for _, task := range batch {
+		if err := w.store.SaveTask(ctx, workflowID, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushAll commits the buffered tasks of every workflow and returns the first
+// error.
+func (w *taskWriter) flushAll(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	ids := make([]string, 0, len(w.pending))
+	for id := range w.pending {
+		ids = append(ids, id)
+	}
+	w.mu.Unlock()
+
+	var first error
+	for _, id := range ids {
+		if err := w.flush(ctx, id); err != nil {
+			w.logger.Warn("failed to flush task transitions", "workflow_id", id, "error", err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// start launches the background flusher.
+func (w *taskWriter) start() {
+	if w == nil || w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+}
+
+func (w *taskWriter) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-w.kick:
+		}
+		_ = w.flushAll(context.Background())
+	}
+}
+
+// close stops the background flusher and commits everything still buffered.
+func (w *taskWriter) close(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+		w.stop, w.done = nil, nil
+	}
+	return w.flushAll(ctx)
+}
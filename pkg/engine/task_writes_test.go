@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// countingStorage counts task writes and can be told to fail batch writes
+// or the task writes of transactions.
+type countingStorage struct {
+	*memory.MemoryStorage
+
+	mu        sync.Mutex
+	saveTask  int
+	saveTasks int
+	batched   int
+	fail      error
+	failTx    error
+}
+
+func (s *countingStorage) BeginTx(ctx context.Context) (storage.Tx, error) {
+	tx, err := s.MemoryStorage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &countingTx{Tx: tx, s: s}, nil
+}
+
+// countingTx counts the task writes of a transaction as SaveTask calls.
+type countingTx struct {
+	storage.Tx
+	s *countingStorage
+}
+
+func (tx *countingTx) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	tx.s.mu.Lock()
+	tx.s.saveTask++
+	fail := tx.s.failTx
+	tx.s.mu.Unlock()
+	if fail != nil {
+		return fail
+	}
+	return tx.Tx.SaveTask(ctx, workflowID, task)
+}
+
+func (s *countingStorage) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	s.mu.Lock()
+	s.saveTask++
+	s.mu.Unlock()
+	return s.MemoryStorage.SaveTask(ctx, workflowID, task)
+}
+
+func (s *countingStorage) SaveTasks(ctx context.Context, workflowID string, tasks []*storage.TaskState) error {
+	s.mu.Lock()
+	fail := s.fail
+	if fail == nil {
+		s.saveTasks++
+		s.batched += len(tasks)
+	}
+	s.mu.Unlock()
+	if fail != nil {
+		return fail
+	}
+	return s.MemoryStorage.SaveTasks(ctx, workflowID, tasks)
+}
+
+func TestSubmitWorkflowRuntime_BatchedTaskWrites(t *testing.T) {
+	cfg := minConfig()
+	cfg.Storage.TaskWrites = config.TaskWritesConfig{Mode: "batch", FlushInterval: time.Hour, MaxBatch: 1000}
+	store := &countingStorage{MemoryStorage: memory.NewMemoryStorage()}
+
+	eng, err := New(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	const n = 20
+	req := &models.WorkflowRequest{Name: "batched"}
+	taskFns := make(map[string]func(context.Context) error, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("t%d", i)
+		req.Tasks = append(req.Tasks, models.TaskDefinition{ID: id, Name: id, Type: "function"})
+		taskFns[id] = func(context.Context) error { return nil }
+	}
+
+	resp, err := eng.SubmitWorkflowRuntime(context.Background(), req, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: taskFns,
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s, want %s", resp.Status, workflowStatusCompleted)
+	}
+
+	tasks, err := store.ListTasks(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	if len(tasks) != n {
+		t.Fatalf("expected %d persisted tasks, got %d", n, len(tasks))
+	}
+	for _, task := range tasks {
+		if task.Status != taskStatusCompleted {
+			t.Fatalf("task %s persisted as %s before the workflow record", task.ID, task.Status)
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	// Submission saves every task once; transitions only go through batches.
+	if store.saveTask != n {
+		t.Fatalf("expected %d SaveTask calls from submission, got %d", n, store.saveTask)
+	}
+	if store.batched != n {
+		t.Fatalf("expected transitions coalesced to %d task writes, got %d in %d batches", n, store.batched, store.saveTasks)
+	}
+}
+
+func TestSubmitWorkflowRuntime_InitialWritesAreAtomic(t *testing.T) {
+	store := &countingStorage{MemoryStorage: memory.NewMemoryStorage(), failTx: errors.New("disk full")}
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	req := &models.WorkflowRequest{
+		Name: "atomic",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "a", Type: "function"},
+			{ID: "b", Name: "b", Type: "function"},
+		},
+	}
+	if _, err := eng.SubmitWorkflowRuntime(context.Background(), req, SubmitWorkflowOptions{Mode: SubmissionModeAsync}); err == nil {
+		t.Fatal("expected submission to fail")
+	}
+
+	workflows, total, err := store.ListWorkflows(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListWorkflows() error = %v", err)
+	}
+	if total != 0 {
+		t.Fatalf("expected no workflow left by a failed submission, got %d: %+v", total, workflows)
+	}
+}
+
+func TestTaskWriter_FailedFlushKeepsNewerState(t *testing.T) {
+	store := &countingStorage{MemoryStorage: memory.NewMemoryStorage()}
+	ctx := context.Background()
+	if err := store.SaveWorkflow(ctx, &storage.WorkflowState{ID: "wf", Status: workflowStatusRunning}); err != nil {
+		t.Fatalf("SaveWorkflow() error = %v", err)
+	}
+	w := newTaskWriter(config.TaskWritesConfig{Mode: "batch", FlushInterval: time.Hour, MaxBatch: 10}, store, &nopLogger{})
+
+	w.save("wf", &storage.TaskState{ID: "a", Status: taskStatusRunning})
+	w.save("wf", &storage.TaskState{ID: "b", Status: taskStatusRunning})
+	store.fail = errors.New("disk full")
+	if err := w.flush(ctx, "wf"); err == nil {
+		t.Fatal("expected flush error")
+	}
+
+	w.save("wf", &storage.TaskState{ID: "a", Status: taskStatusCompleted})
+	store.fail = nil
+	if err := w.close(ctx); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	for id, want := range map[string]string{"a": taskStatusCompleted, "b": taskStatusRunning} {
+		task, err := store.GetTask(ctx, "wf", id)
+		if err != nil {
+			t.Fatalf("GetTask(%s) error = %v", id, err)
+		}
+		if task.Status != want {
+			t.Fatalf("task %s = %s, want %s", id, task.Status, want)
+		}
+	}
+	if err := w.flush(ctx, "unknown"); err != nil {
+		t.Fatalf("flush of an idle workflow error = %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/jsonschema"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// toolTenantsKind is the kind of the storage.ResourceRecord holding the
+// tenants a tool was enabled or disabled for through the API.
+const toolTenantsKind = "tool_tenants"
+
+// AvailableTools returns the tools of orchestration.tools available to the
+// tenant of the workflow run the task running with ctx belongs to, for
+// planners to choose from.
+func AvailableTools(ctx context.Context) ([]models.Tool, error) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "tools are only available to tasks run by the engine")
+	}
+	tenant := v.tenant()
+	var tools []models.Tool
+	for _, tool := range v.engine.ListTools(tenant) {
+		if tool.Enabled {
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// UseTool accounts one call of the tool name with input by the task running
+// with ctx before the task makes it. It fails with a PermissionDenied error
+// when the tool is not available to the tenant of the run and with a
+// BadRequest error when input does not match the tool's input schema, waits
+// for the tool's rate limit and records its cost hint as usage of the run.
+func UseTool(ctx context.Context, name string, input any) error {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return errs.New(errs.BadRequest, "tools are only available to tasks run by the engine")
+	}
+	tenant := v.tenant()
+	tool, err := v.engine.GetTool(name, tenant)
+	if err != nil {
+		return err
+	}
+	if !tool.Enabled {
+		return errs.Newf(errs.PermissionDenied, "tool %s is not available to tenant %q", name, tenant)
+	}
+	if err := v.engine.tools.checkInput(name, input); err != nil {
+		return err
+	}
+	if tool.RateLimit != "" {
+		if err := WaitRateLimit(ctx, tool.RateLimit, 1); err != nil {
+			return err
+		}
+	}
+	for dimension, amount := range tool.Cost {
+		RecordUsage(ctx, dimension, amount)
+	}
+	return nil
+}
+
+// tenant returns the tenant the run is charged to.
+func (v *workflowValues) tenant() string {
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	return v.engine.tenantOf(v.exec.wfState)
+}
+
+// toolRegistry holds the tools of orchestration.tools, their compiled input
+// schemas and the tenants they were enabled or disabled for through the API.
+// Overrides are saved with the other managed resources when the storage
+// keeps them, and loaded back when the engine starts.
+type toolRegistry struct {
+	tools   map[string]config.ToolConfig
+	schemas map[string]*jsonschema.Schema
+
+	mu        sync.RWMutex
+	overrides map[string]map[string]bool
+}
+
+func newToolRegistry(cfg map[string]config.ToolConfig) (*toolRegistry, error) {
+	r := &toolRegistry{
+		tools:     maps.Clone(cfg),
+		schemas:   make(map[string]*jsonschema.Schema),
+		overrides: make(map[string]map[string]bool),
+	}
+	for name, tool := range cfg {
+		if tool.InputSchema == nil {
+			continue
+		}
+		schema, err := jsonschema.Compile(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s input schema: %w", name, err)
+		}
+		r.schemas[name] = schema
+	}
+	return r, nil
+}
+
+// checkInput validates the input of a call of the tool name against its
+// input schema, reporting a mismatch as BadRequest.
+func (r *toolRegistry) checkInput(name string, input any) error {
+	schema, ok := r.schemas[name]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("tool %s input", name))
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errs.Wrap(err, errs.BadRequest, fmt.Sprintf("tool %s input", name))
+	}
+	if err := schema.Validate(value); err != nil {
+		return errs.Newf(errs.BadRequest, "tool %s input does not match its schema: %v", name, err)
+	}
+	return nil
+}
+
+// loadToolOverrides loads the tenants tools were enabled or disabled for
+// through the API from the storage. Overrides of tools no longer configured
+// are ignored.
+func (e *Engine) loadToolOverrides(ctx context.Context) error {
+	store, ok := e.storage.(storage.ResourceStore)
+	if !ok {
+		return nil
+	}
+	records, err := store.ListResources(ctx, toolTenantsKind)
+	if err != nil {
+		return err
+	}
+	e.tools.mu.Lock()
+	defer e.tools.mu.Unlock()
+	for _, record := range records {
+		if _, ok := e.tools.tools[record.Name]; !ok {
+			continue
+		}
+		var tenants map[string]bool
+		if err := json.Unmarshal(record.Data, &tenants); err != nil {
+			return fmt.Errorf("tool %s tenants: %w", record.Name, err)
+		}
+		e.tools.overrides[record.Name] = tenants
+	}
+	return nil
+}
+
+// enabled reports whether the tool name is available to tenant: as set
+// through the API, else by its allow-list, else unless disabled. The caller
+// holds r.mu.
+func (r *toolRegistry) enabled(name, tenant string) bool {
+	if enabled, ok := r.overrides[name][tenant]; ok {
+		return enabled
+	}
+	tool := r.tools[name]
+	if tool.Disabled {
+		return false
+	}
+	return len(tool.Tenants) == 0 || slices.Contains(tool.Tenants, tenant)
+}
+
+// ListTools returns the tools of orchestration.tools sorted by name, with
+// Enabled telling whether each is available to tenant.
+func (e *Engine) ListTools(tenant string) []models.Tool {
+	names := make([]string, 0, len(e.tools.tools))
+	for name := range e.tools.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	e.tools.mu.RLock()
+	defer e.tools.mu.RUnlock()
+	tools := make([]models.Tool, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, e.toolLocked(name, tenant))
+	}
+	return tools
+}
+
+// GetTool returns the tool name, with Enabled telling whether it is available
+// to tenant.
+func (e *Engine) GetTool(name, tenant string) (*models.Tool, error) {
+	if _, ok := e.tools.tools[name]; !ok {
+		return nil, errs.Newf(errs.NotFound, "tool %s not found", name)
+	}
+	e.tools.mu.RLock()
+	defer e.tools.mu.RUnlock()
+	tool := e.toolLocked(name, tenant)
+	return &tool, nil
+}
+
+// SetToolEnabled makes the tool name available to tenant or not, whatever
+// its configuration says. The override is saved in the storage when it keeps
+// managed resources, so that it survives restarts.
+func (e *Engine) SetToolEnabled(ctx context.Context, name, tenant string, enabled bool) (*models.Tool, error) {
+	if _, ok := e.tools.tools[name]; !ok {
+		return nil, errs.Newf(errs.NotFound, "tool %s not found", name)
+	}
+	if tenant == "" {
+		return nil, errs.New(errs.BadRequest, "tenant is required")
+	}
+	e.tools.mu.Lock()
+	defer e.tools.mu.Unlock()
+	tenants := maps.Clone(e.tools.overrides[name])
+	if tenants == nil {
+		tenants = make(map[string]bool)
+	}
+	tenants[tenant] = enabled
+	if store, ok := e.storage.(storage.ResourceStore); ok {
+		data, err := json.Marshal(tenants)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.SaveResource(ctx, &storage.ResourceRecord{Kind: toolTenantsKind, Name: name, Data: data}); err != nil {
+			return nil, fmt.Errorf("save tool %s tenants: %w", name, err)
+		}
+	}
+	e.tools.overrides[name] = tenants
+	tool := e.toolLocked(name, tenant)
+	return &tool, nil
+}
+
+// toolLocked builds the model of the tool name for tenant. The caller holds
+// e.tools.mu.
+func (e *Engine) toolLocked(name, tenant string) models.Tool {
+	cfg := e.tools.tools[name]
+	tool := models.Tool{
+		Name:        name,
+		Description: cfg.Description,
+		InputSchema: cfg.InputSchema,
+		Auth:        cfg.Auth,
+		Secrets:     slices.Clone(cfg.Secrets),
+		RateLimit:   cfg.RateLimit,
+		Cost:        maps.Clone(cfg.Cost),
+		Tenant:      tenant,
+		Enabled:     e.tools.enabled(name, tenant),
+		Tenants:     maps.Clone(e.tools.overrides[name]),
+	}
+	if tool.Auth == "" {
+		tool.Auth = "none"
+	}
+	if e.cfg.Orchestration.Costs.Enabled && len(cfg.Cost) > 0 {
+		estimate := e.costOf(cfg.Cost)
+		tool.EstimatedCost = &estimate
+		tool.Currency = e.cfg.Orchestration.Costs.Currency
+	}
+	return tool
+}
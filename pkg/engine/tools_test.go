@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestEngine_ToolsPerTenant(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Costs = config.CostsConfig{
+		Enabled:   true,
+		TenantKey: "tenant",
+		Currency:  "USD",
+		Rates:     map[string]float64{UsageAPICalls: 0.01},
+	}
+	cfg.Orchestration.Tools = map[string]config.ToolConfig{
+		"web-search": {Description: "Search the web", Auth: "api_key", Cost: map[string]float64{UsageAPICalls: 2}},
+		"sql":        {Tenants: []string{"team-a"}},
+		"shell":      {Disabled: true},
+	}
+	store := memory.NewMemoryStorage()
+	eng, err := New(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	enabled := func(tenant string) map[string]bool {
+		got := make(map[string]bool)
+		for _, tool := range eng.ListTools(tenant) {
+			got[tool.Name] = tool.Enabled
+		}
+		return got
+	}
+	if got := enabled("team-a"); !got["web-search"] || !got["sql"] || got["shell"] {
+		t.Fatalf("team-a tools = %v", got)
+	}
+	if got := enabled("team-b"); !got["web-search"] || got["sql"] || got["shell"] {
+		t.Fatalf("team-b tools = %v", got)
+	}
+
+	search, err := eng.GetTool("web-search", "team-a")
+	if err != nil {
+		t.Fatalf("GetTool() error = %v", err)
+	}
+	if search.EstimatedCost == nil || *search.EstimatedCost != 0.02 || search.Currency != "USD" || search.Auth != "api_key" {
+		t.Fatalf("web-search = %+v", search)
+	}
+	if _, err := eng.GetTool("browser", ""); !errs.Is(err, errs.NotFound) {
+		t.Fatalf("GetTool() of an unknown tool error = %v, want NotFound", err)
+	}
+
+	if _, err := eng.SetToolEnabled(context.Background(), "shell", "team-b", true); err != nil {
+		t.Fatalf("SetToolEnabled() error = %v", err)
+	}
+	if _, err := eng.SetToolEnabled(context.Background(), "web-search", "team-b", false); err != nil {
+		t.Fatalf("SetToolEnabled() error = %v", err)
+	}
+	if got := enabled("team-b"); got["web-search"] || !got["shell"] {
+		t.Fatalf("team-b tools after overrides = %v", got)
+	}
+	if got := enabled("team-a"); !got["web-search"] || got["shell"] {
+		t.Fatalf("team-a tools after team-b overrides = %v", got)
+	}
+
+	// Overrides survive a restart on the same storage.
+	eng, err = New(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.loadToolOverrides(context.Background()); err != nil {
+		t.Fatalf("loadToolOverrides() error = %v", err)
+	}
+	if got := enabled("team-b"); got["web-search"] || !got["shell"] {
+		t.Fatalf("team-b tools after restart = %v", got)
+	}
+}
+
+func TestNew_RejectsInvalidToolInputSchema(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Tools = map[string]config.ToolConfig{
+		"web-search": {InputSchema: map[string]any{"type": "query"}},
+	}
+	if _, err := New(cfg, nil, memory.NewMemoryStorage()); err == nil {
+		t.Fatal("New() with an invalid tool input schema succeeded")
+	}
+}
+
+func TestUseTool_ChecksTenantAndRecordsCost(t *testing.T) {
+	cfg := minConfig()
+	cfg.Orchestration.Costs = config.CostsConfig{Enabled: true, TenantKey: "tenant"}
+	cfg.Orchestration.RateLimits = map[string]config.RateLimitConfig{"search-api": {Limit: 10}}
+	cfg.Orchestration.Tools = map[string]config.ToolConfig{
+		"web-search": {
+			RateLimit: "search-api",
+			Cost:      map[string]float64{UsageAPICalls: 1},
+			InputSchema: map[string]any{
+				"type":       "object",
+				"required":   []any{"query"},
+				"properties": map[string]any{"query": map[string]any{"type": "string"}},
+			},
+		},
+		"sql": {Tenants: []string{"team-a"}},
+	}
+	eng, err := New(cfg, nil, memory.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	var available []models.Tool
+	resp, err := eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:     "research",
+		Metadata: map[string]string{"tenant": "team-b"},
+		Tasks:    []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{
+			"a": func(ctx context.Context) error {
+				var err error
+				if available, err = AvailableTools(ctx); err != nil {
+					return err
+				}
+				if err := UseTool(ctx, "sql", nil); !errs.Is(err, errs.PermissionDenied) {
+					t.Errorf("UseTool() of a tool of another tenant error = %v, want PermissionDenied", err)
+				}
+				if err := UseTool(ctx, "web-search", map[string]any{"query": 42}); !errs.Is(err, errs.BadRequest) {
+					t.Errorf("UseTool() with input not matching the schema error = %v, want BadRequest", err)
+				}
+				for i := 0; i < 2; i++ {
+					if err := UseTool(ctx, "web-search", struct {
+						Query string `json:"query"`
+					}{"goclaw"}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+	if resp.Status != workflowStatusCompleted {
+		t.Fatalf("workflow status = %s (%s), want completed", resp.Status, resp.Error)
+	}
+	if len(available) != 1 || available[0].Name != "web-search" {
+		t.Fatalf("available tools = %+v, want web-search only", available)
+	}
+	if resp.Cost == nil || resp.Cost.Usage[UsageAPICalls] != 2 {
+		t.Fatalf("cost = %+v, want 2 api calls", resp.Cost)
+	}
+	if err := UseTool(ctx, "web-search", nil); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("UseTool() outside a task error = %v, want BadRequest", err)
+	}
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"context"
+
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -18,3 +20,13 @@ const (
 func runtimeTracer() trace.Tracer {
 	return otel.Tracer(runtimeTracerName)
 }
+
+// tracingActive reports whether ctx carries a recording span. Per-task spans
+// are only created under a recording workflow span, so that dispatching large
+// workflows with tracing disabled allocates nothing for spans.
+func tracingActive(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// noopSpan stands in for per-task spans while tracing is inactive.
+var noopSpan = trace.SpanFromContext(context.Background())
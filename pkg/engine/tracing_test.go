@@ -2,10 +2,14 @@ package engine
 
 import (
 	"context"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/metrics"
 	"github.com/goclaw/goclaw/pkg/storage/memory"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -63,6 +67,49 @@ func TestRuntimeTracing_WorkflowAndLaneSpans(t *testing.T) {
 	}
 }
 
+func TestRuntimeTracing_DurationExemplars(t *testing.T) {
+	_, shutdown := setEngineTracingProvider(t)
+	defer shutdown()
+
+	recorder := metrics.NewManager(metrics.DefaultConfig())
+	eng, err := New(minConfig(), nil, memory.NewMemoryStorage(), WithMetrics(recorder))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := eng.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer eng.Stop(ctx)
+
+	_, err = eng.SubmitWorkflowRuntime(ctx, &models.WorkflowRequest{
+		Name:  "traced",
+		Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+	}, SubmitWorkflowOptions{
+		Mode:    SubmissionModeSync,
+		TaskFns: map[string]func(context.Context) error{"a": func(context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	w := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(w, req)
+	for _, family := range []string{"workflow_duration_seconds_bucket", "task_duration_seconds_bucket"} {
+		found := false
+		for _, line := range strings.Split(w.Body.String(), "\n") {
+			if strings.HasPrefix(line, family) && strings.Contains(line, " # {") && strings.Contains(line, `trace_id="`) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a trace exemplar on %s", family)
+		}
+	}
+}
+
 func setEngineTracingProvider(t *testing.T) (*tracetest.SpanRecorder, func()) {
 	t.Helper()
 
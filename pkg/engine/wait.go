@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// workflowWaitPollInterval is how often WaitWorkflow re-reads the workflow,
+// so that it also notices workflows finished by other nodes.
+const workflowWaitPollInterval = time.Second
+
+// workflowWaiters wakes the callers waiting for workflows to finish. The zero
+// value is ready to use.
+type workflowWaiters struct {
+	mu      sync.Mutex
+	waiters map[string][]chan struct{}
+}
+
+// add returns a channel closed when workflowID finishes.
+func (w *workflowWaiters) add(workflowID string) chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.waiters == nil {
+		w.waiters = make(map[string][]chan struct{})
+	}
+	ch := make(chan struct{})
+	w.waiters[workflowID] = append(w.waiters[workflowID], ch)
+	return ch
+}
+
+// remove forgets ch if it has not been woken yet.
+func (w *workflowWaiters) remove(workflowID string, ch chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	waiters := w.waiters[workflowID]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(w.waiters, workflowID)
+	} else {
+		w.waiters[workflowID] = waiters
+	}
+}
+
+// wake wakes the callers waiting for workflowID.
+func (w *workflowWaiters) wake(workflowID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.waiters[workflowID] {
+		close(ch)
+	}
+	delete(w.waiters, workflowID)
+}
+
+// WaitWorkflow blocks until the workflow completes, fails or is cancelled,
+// and returns its final status. If ctx is done first, it returns the last
+// status read together with the context error.
+func (e *Engine) WaitWorkflow(ctx context.Context, workflowID string) (*models.WorkflowStatusResponse, error) {
+	ticker := time.NewTicker(workflowWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		// Register before reading, so a finish between the read and the
+		// wait is not missed.
+		done := e.waiters.add(workflowID)
+		status, err := e.GetWorkflowStatusResponse(ctx, workflowID)
+		if err != nil || isTerminalWorkflowStatus(status.Status) {
+			e.waiters.remove(workflowID, done)
+			return status, err
+		}
+		select {
+		case <-done:
+		case <-ticker.C:
+			e.waiters.remove(workflowID, done)
+		case <-ctx.Done():
+			e.waiters.remove(workflowID, done)
+			return status, ctx.Err()
+		}
+	}
+}
@@ -4,12 +4,16 @@ package engine
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/goclaw/goclaw/pkg/api/models"
 	"github.com/goclaw/goclaw/pkg/dag"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/policy"
+	"github.com/goclaw/goclaw/pkg/requestid"
 	"github.com/goclaw/goclaw/pkg/storage"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
@@ -20,6 +24,10 @@ import (
 type SubmitWorkflowOptions struct {
 	Mode    SubmissionMode
 	TaskFns map[string]func(context.Context) error
+	// CompensationFns maps task IDs to the functions running their
+	// compensations. Compensations without one run with the executor of
+	// their type, or as no-ops.
+	CompensationFns map[string]func(context.Context) error
 }
 
 // SubmitWorkflowRequest submits a workflow and returns its ID.
@@ -31,35 +39,110 @@ func (e *Engine) SubmitWorkflowRequest(ctx context.Context, req *models.Workflow
 	return resp.ID, nil
 }
 
+// ValidateWorkflowRequest checks that req would be accepted for submission,
+// compiling its DAG without persisting or running it.
+func (e *Engine) ValidateWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) error {
+	if err := e.env.check(req); err != nil {
+		return err
+	}
+	if err := e.rateLimits.check(req); err != nil {
+		return err
+	}
+	if err := e.checkBudget(req); err != nil {
+		return err
+	}
+	if err := checkOutputSchemas(req); err != nil {
+		return err
+	}
+	if err := checkExpressions(req); err != nil {
+		return err
+	}
+	if err := e.checkCompensations(req, nil); err != nil {
+		return err
+	}
+	if err := e.checkExecutors(req, nil); err != nil {
+		return err
+	}
+	if err := e.checkPolicy(ctx, policy.KindWorkflow, req.Name, req); err != nil {
+		return err
+	}
+	_, err := e.simulateWorkflow(ctx, req)
+	return err
+}
+
 // SubmitWorkflowRuntime submits a workflow using explicit runtime semantics.
 func (e *Engine) SubmitWorkflowRuntime(ctx context.Context, req *models.WorkflowRequest, opts SubmitWorkflowOptions) (*models.WorkflowStatusResponse, error) {
 	if req == nil {
 		return nil, fmt.Errorf("workflow request cannot be nil")
 	}
+	if opts.Mode == SubmissionModeSimulate {
+		return e.simulateWorkflow(ctx, req)
+	}
+	if err := e.env.check(req); err != nil {
+		return nil, err
+	}
+	if err := e.rateLimits.check(req); err != nil {
+		return nil, err
+	}
+	if err := e.checkBudget(req); err != nil {
+		return nil, err
+	}
+	if err := checkOutputSchemas(req); err != nil {
+		return nil, err
+	}
+	if err := checkExpressions(req); err != nil {
+		return nil, err
+	}
+	if err := e.checkCompensations(req, opts.CompensationFns); err != nil {
+		return nil, err
+	}
+	if err := e.checkExecutors(req, opts.TaskFns); err != nil {
+		return nil, err
+	}
+	if err := e.checkPolicy(ctx, policy.KindWorkflow, req.Name, req); err != nil {
+		return nil, err
+	}
 
 	wfState := newWorkflowState(req)
-	if err := e.storage.SaveWorkflow(ctx, wfState); err != nil {
-		return nil, fmt.Errorf("failed to save workflow: %w", err)
+	wfState.RequestID = requestid.FromContext(ctx)
+	notifications, err := e.notifications(wfState, "", false)
+	if err != nil {
+		return nil, err
 	}
-	for _, taskState := range wfState.TaskStatus {
-		if err := e.storage.SaveTask(ctx, wfState.ID, taskState); err != nil {
-			return nil, fmt.Errorf("failed to save initial task %s: %w", taskState.ID, err)
+	// The workflow and its initial tasks are written together so that a
+	// crash never leaves a workflow without some of its tasks.
+	err = storage.WriteBatch(ctx, e.storage, func(w storage.Writer) error {
+		if err := w.SaveWorkflow(ctx, wfState); err != nil {
+			return fmt.Errorf("failed to save workflow: %w", err)
 		}
+		for _, taskState := range wfState.TaskStatus {
+			if err := w.SaveTask(ctx, wfState.ID, taskState); err != nil {
+				return fmt.Errorf("failed to save initial task %s: %w", taskState.ID, err)
+			}
+		}
+		return writeOutbox(ctx, w, notifications)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(notifications) > 0 {
+		e.wakeOutbox()
 	}
 	e.metrics.RecordWorkflowSubmission(workflowStatusPending)
-	e.emitWorkflowStateChanged(wfState.ID, wfState.Name, "", wfState.Status)
+	e.emitWorkflowStateChanged(wfState.RequestID, wfState.ID, wfState.Name, "", wfState.Status)
 
-	e.logger.Info("workflow submitted", "id", wfState.ID, "name", wfState.Name, "tasks", len(wfState.Tasks))
+	e.logger.Info("workflow submitted", "id", wfState.ID, "name", wfState.Name, "request_id", wfState.RequestID, "tasks", len(wfState.Tasks))
 
 	mode := normalizeSubmissionMode(opts.Mode)
-	hasTaskFns := len(opts.TaskFns) > 0
+	hasTaskFns := len(opts.TaskFns) > 0 || e.hasExecutorTasks(req)
 
-	// Without executable task functions, workflow remains persisted pending.
+	// Without executable task functions or executors, workflow remains
+	// persisted pending.
 	if !hasTaskFns {
-		return e.workflowStateToResponse(wfState), nil
+		return e.workflowStateToResponse(ctx, wfState), nil
 	}
 
-	exec, err := e.startWorkflowExecution(ctx, wfState.ID, opts.TaskFns)
+	exec, err := e.startWorkflowExecution(ctx, wfState.ID, opts.TaskFns, opts.CompensationFns)
 	if err != nil {
 		if transitionErr := e.markWorkflowFailedFromPending(ctx, wfState.ID, err); transitionErr != nil {
 			e.logger.Error("failed to mark workflow failed after start error", "workflow_id", wfState.ID, "error", transitionErr)
@@ -68,7 +151,7 @@ func (e *Engine) SubmitWorkflowRuntime(ctx context.Context, req *models.Workflow
 	}
 
 	if mode == SubmissionModeAsync {
-		return e.workflowStateToResponse(wfState), nil
+		return e.workflowStateToResponse(ctx, wfState), nil
 	}
 
 	// Sync mode: wait for terminal state or caller cancellation.
@@ -100,14 +183,10 @@ func newWorkflowState(req *models.WorkflowRequest) *storage.WorkflowState {
 	now := time.Now().UTC()
 	taskStatus := make(map[string]*storage.TaskState, len(req.Tasks))
 	for _, task := range req.Tasks {
-		taskStatus[task.ID] = &storage.TaskState{
-			ID:     task.ID,
-			Name:   task.Name,
-			Status: taskStatusPending,
-		}
+		taskStatus[task.ID] = newTaskState(task)
 	}
 
-	return &storage.WorkflowState{
+	state := &storage.WorkflowState{
 		ID:          id,
 		Name:        req.Name,
 		Description: req.Description,
@@ -116,6 +195,27 @@ func newWorkflowState(req *models.WorkflowRequest) *storage.WorkflowState {
 		TaskStatus:  taskStatus,
 		Metadata:    req.Metadata,
 		CreatedAt:   now,
+		Priority:    req.Priority,
+		GangLayers:  req.GangLayers,
+		CallbackURL: req.CallbackURL,
+		Budget:      req.Budget,
+	}
+	if req.Deadline > 0 {
+		deadline := now.Add(time.Duration(req.Deadline) * time.Second)
+		state.Deadline = &deadline
+	}
+	state.SLADeadline = slaDeadline(req.SLA, now)
+	return state
+}
+
+// newTaskState returns the initial state of task.
+func newTaskState(task models.TaskDefinition) *storage.TaskState {
+	return &storage.TaskState{
+		ID:        task.ID,
+		Name:      task.Name,
+		Status:    taskStatusPending,
+		DedupeKey: task.DedupeKey,
+		Sensitive: task.Sensitive,
 	}
 }
 
@@ -123,6 +223,7 @@ func (e *Engine) startWorkflowExecution(
 	parentCtx context.Context,
 	workflowID string,
 	taskFns map[string]func(context.Context) error,
+	compensationFns map[string]func(context.Context) error,
 ) (*workflowExecution, error) {
 	if _, exists := e.getExecution(workflowID); exists {
 		return nil, fmt.Errorf("workflow %s is already executing", workflowID)
@@ -139,18 +240,46 @@ func (e *Engine) startWorkflowExecution(
 	if parentCtx == nil {
 		parentCtx = context.Background()
 	}
-	execCtx, cancel := context.WithCancel(context.WithoutCancel(parentCtx))
+
+	// With the reject policy a full limit fails the submission up front;
+	// otherwise the workflow stays pending until a slot frees up.
+	var release func()
+	if e.workflowLimits.rejects() {
+		release, err = e.workflowLimits.acquire(parentCtx, wfState.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Runs resumed after a restart get the request ID of their submission back.
+	execCtx, cancel := context.WithCancel(requestid.NewContext(context.WithoutCancel(parentCtx), wfState.RequestID))
 	exec := &workflowExecution{
 		workflowID: workflowID,
 		cancel:     cancel,
 		done:       make(chan struct{}),
 		wfState:    wfState,
+
+		compensationFns: compensationFns,
+	}
+	if e.cfg.Orchestration.Costs.Enabled {
+		exec.usage = newUsageMeter(wfState.Usage)
 	}
 	e.registerExecution(exec)
 
 	go func() {
 		defer close(exec.done)
 		defer e.unregisterExecution(workflowID)
+		if release == nil {
+			var err error
+			release, err = e.workflowLimits.acquire(execCtx, wfState.Name)
+			if err != nil {
+				if transitionErr := e.transitionWorkflow(exec, workflowStatusCancelled, err.Error()); transitionErr != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
+					e.logger.Error("failed to cancel queued workflow", "workflow_id", workflowID, "error", transitionErr)
+				}
+				return
+			}
+		}
+		defer release()
 		e.runWorkflowExecution(execCtx, exec, taskFns)
 	}()
 
@@ -165,6 +294,9 @@ func (e *Engine) runWorkflowExecution(ctx context.Context, exec *workflowExecuti
 		attribute.String("workflow.mode", "runtime"),
 	)
 	defer workflowSpan.End()
+	exec.mu.Lock()
+	exec.spanCtx = workflowSpan.SpanContext()
+	exec.mu.Unlock()
 
 	wf := e.workflowFromState(exec.wfState, taskFns)
 
@@ -196,6 +328,9 @@ func (e *Engine) runWorkflowExecution(ctx context.Context, exec *workflowExecuti
 		}
 	}
 	plan, err := g.Compile()
+	if err == nil {
+		err = checkPlanResources(e.laneManager, plan, wf.GangLayers)
+	}
 	if err != nil {
 		workflowSpan.RecordError(err)
 		workflowSpan.SetStatus(otelcodes.Error, "compile_error")
@@ -205,22 +340,65 @@ func (e *Engine) runWorkflowExecution(ctx context.Context, exec *workflowExecuti
 
 	tracker := newStateTracker()
 	taskIDs := make([]string, 0, len(wf.Tasks))
-	taskNameByID := make(map[string]string, len(wf.Tasks))
 	for _, t := range wf.Tasks {
 		taskIDs = append(taskIDs, t.ID)
-		taskNameByID[t.ID] = t.Name
 	}
 	tracker.InitTasks(taskIDs)
+	run := newRunPlan(plan, tracker)
 	tracker.SetOnStateChange(func(taskID string, oldState, newState TaskState, result TaskResult) {
 		if err := e.transitionTask(exec, taskID, oldState, newState, result); err != nil {
 			e.logger.Error("failed to persist task transition", "workflow_id", exec.workflowID, "task_id", taskID, "error", err)
 		}
-		_ = taskNameByID
+	})
+	tracker.SetOnStall(func(taskID string, result TaskResult) {
+		task, _ := run.task(taskID)
+		e.emitTaskStalled(exec.workflowID, task, result)
 	})
 
-	sched := newScheduler(tracker, e.logger, e.signalBus, e.laneManager)
+	sched := newScheduler(tracker, e.laneLogger, e.signalBus, e.laneManager)
+	sched.priority = wf.Priority
+	sched.deadline = wf.Deadline
+	sched.gangLayers = wf.GangLayers
+	sched.preemptor = e.preemptor
+	sched.inheritance = e.inheritance
+	sched.env = e.env
+	sched.artifacts = e.artifacts
+	sched.logs = e.taskLogs
+	sched.usage = exec.usage
+	sched.values = &workflowValues{engine: e, exec: exec}
+	sched.lineage = &workflowLineage{exec: exec}
+	sched.outputs = &workflowOutputs{exec: exec}
+	sched.exprs = &workflowExpressions{engine: e, exec: exec}
+	if dedupes, ok := e.storage.(storage.DedupeStore); ok {
+		sched.dedupes = dedupes
+		sched.dedupeTTL = e.dedupeTTL()
+		sched.dedupeScope = e.tenantOf(exec.wfState)
+	}
+	sched.locks = e.locks
+	sched.rateLimits = e.rateLimits
+	sched.executors = e.executors
+	sched.middleware = e.taskMiddleware
+	sched.workflowID = exec.workflowID
+	sched.run = run
+	exec.mu.Lock()
+	exec.run = run
+	exec.mu.Unlock()
+	budgetCtx, stopBudget := context.WithCancel(ctx)
+	go e.watchBudget(budgetCtx, exec)
 	err = sched.Schedule(ctx, plan, wf.TaskFns)
+	stopBudget()
 	if err != nil {
+		exec.mu.Lock()
+		overBudget := exec.overBudget
+		exec.mu.Unlock()
+		if overBudget != "" {
+			workflowSpan.RecordError(err)
+			workflowSpan.SetStatus(otelcodes.Error, "budget_exceeded")
+			if transitionErr := e.transitionWorkflow(exec, workflowStatusFailed, overBudget); transitionErr != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
+				e.logger.Error("failed to transition workflow over budget", "workflow_id", exec.workflowID, "error", transitionErr)
+			}
+			return
+		}
 		if ctx.Err() != nil {
 			workflowSpan.RecordError(ctx.Err())
 			workflowSpan.SetStatus(otelcodes.Error, workflowStatusCancelled)
@@ -231,12 +409,24 @@ func (e *Engine) runWorkflowExecution(ctx context.Context, exec *workflowExecuti
 		}
 		workflowSpan.RecordError(err)
 		workflowSpan.SetStatus(otelcodes.Error, workflowStatusFailed)
+		if compErr := e.compensate(ctx, exec, err); compErr != nil {
+			e.logger.Error("failed to compensate workflow", "workflow_id", exec.workflowID, "error", compErr)
+			err = fmt.Errorf("%w; compensation failed: %v", err, compErr)
+		}
 		if transitionErr := e.transitionWorkflow(exec, workflowStatusFailed, err.Error()); transitionErr != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
 			e.logger.Error("failed to transition failed workflow", "workflow_id", exec.workflowID, "error", transitionErr)
 		}
 		return
 	}
 
+	if err := e.proposePlan(ctx, exec); err != nil {
+		workflowSpan.RecordError(err)
+		workflowSpan.SetStatus(otelcodes.Error, "plan_error")
+		if transitionErr := e.transitionWorkflow(exec, workflowStatusFailed, err.Error()); transitionErr != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
+			e.logger.Error("failed to transition failed workflow", "workflow_id", exec.workflowID, "error", transitionErr)
+		}
+		return
+	}
 	if transitionErr := e.transitionWorkflow(exec, workflowStatusCompleted, ""); transitionErr != nil && !isTerminalWorkflowStatus(exec.wfState.Status) {
 		workflowSpan.RecordError(transitionErr)
 		workflowSpan.SetStatus(otelcodes.Error, workflowStatusFailed)
@@ -250,29 +440,59 @@ func (e *Engine) workflowFromState(state *storage.WorkflowState, taskFns map[str
 	tasks := make([]*dag.Task, 0, len(state.Tasks))
 	for _, t := range state.Tasks {
 		task := &dag.Task{
-			ID:      t.ID,
-			Name:    t.Name,
-			Agent:   t.Type,
-			Deps:    append([]string(nil), t.DependsOn...),
-			Retries: t.Retries,
+			ID:          t.ID,
+			Name:        t.Name,
+			Agent:       t.Type,
+			Deps:        append([]string(nil), t.DependsOn...),
+			Retries:     t.Retries,
+			DedupeKey:   t.DedupeKey,
+			Preemptible: t.Preemptible,
+			Gang:        t.Gang,
+			AffinityKey: t.AffinityKey,
+			Locks:       append([]string(nil), t.Locks...),
+			RateLimits:  append([]string(nil), t.RateLimits...),
+			StallPolicy: dag.StallPolicy(t.StallPolicy),
+			Env:         append([]string(nil), t.Env...),
+			Secrets:     maps.Clone(t.Secrets),
+			When:        t.When,
+			RetryIf:     t.RetryIf,
+		}
+		if t.OutputSchema != nil {
+			task.Output = t.OutputSchema
 		}
+		if t.Resources != nil {
+			task.Resources = dag.Resources{CPU: t.Resources.CPU, MemoryMB: t.Resources.MemoryMB, GPU: t.Resources.GPU}
+		}
+		task.Container = containerSpec(t.Container)
 		if task.Agent == "" {
 			task.Agent = "function"
 		}
 		if t.Timeout > 0 {
 			task.Timeout = time.Duration(t.Timeout) * time.Second
 		}
+		if t.HeartbeatTimeout > 0 {
+			task.HeartbeatTimeout = time.Duration(t.HeartbeatTimeout) * time.Second
+		}
+		if t.Deadline > 0 {
+			task.Deadline = state.CreatedAt.Add(time.Duration(t.Deadline) * time.Second)
+		}
 		if laneName, ok := t.Config["lane"].(string); ok {
 			task.Lane = laneName
 		}
 		tasks = append(tasks, task)
 	}
 
-	return &Workflow{
-		ID:      state.ID,
-		Tasks:   tasks,
-		TaskFns: taskFns,
+	wf := &Workflow{
+		ID:         state.ID,
+		Tasks:      tasks,
+		TaskFns:    taskFns,
+		Priority:   state.Priority,
+		GangLayers: state.GangLayers,
 	}
+	if state.Deadline != nil {
+		wf.Deadline = *state.Deadline
+	}
+	return wf
 }
 
 func (e *Engine) transitionWorkflow(exec *workflowExecution, newStatus, errMsg string) error {
@@ -305,11 +525,28 @@ func (e *Engine) transitionWorkflow(exec *workflowExecution, newStatus, errMsg s
 		exec.wfState.CompletedAt = &t
 		exec.wfState.Error = errMsg
 	}
+	breached := isTerminalWorkflowStatus(newStatus) && markSLABreached(exec.wfState, now)
+	if isTerminalWorkflowStatus(newStatus) && exec.usage != nil {
+		exec.wfState.Usage = exec.usage.snapshot()
+		if tenant := e.tenantOf(exec.wfState); e.tenantBudget(tenant) != nil {
+			e.budgets.settle(tenant, exec.workflowID, exec.wfState.CreatedAt, exec.wfState.Usage)
+		}
+	}
 
-	if err := e.storage.SaveWorkflow(context.Background(), exec.wfState); err != nil {
+	if err := e.taskWrites.flush(context.Background(), exec.workflowID); err != nil {
 		return err
 	}
-	e.emitWorkflowStateChanged(exec.wfState.ID, exec.wfState.Name, oldStatus, newStatus)
+	notifications, err := e.notifications(exec.wfState, oldStatus, breached)
+	if err != nil {
+		return err
+	}
+	if err := e.saveWorkflowNotifying(context.Background(), exec.wfState, notifications); err != nil {
+		return err
+	}
+	e.emitWorkflowStateChanged(exec.wfState.RequestID, exec.wfState.ID, exec.wfState.Name, oldStatus, newStatus)
+	if breached {
+		e.emitSLABreached(exec.wfState)
+	}
 
 	if newStatus == workflowStatusRunning {
 		e.metrics.IncActiveWorkflows(workflowStatusRunning)
@@ -320,13 +557,56 @@ func (e *Engine) transitionWorkflow(exec *workflowExecution, newStatus, errMsg s
 		if exec.wfState.StartedAt != nil {
 			started = *exec.wfState.StartedAt
 		}
-		e.metrics.RecordWorkflowDuration(workflowMetricLabel(newStatus, errMsg), now.Sub(started))
-		e.metrics.RecordWorkflowSubmission(workflowMetricLabel(newStatus, errMsg))
+		e.recordWorkflowRun(exec.traceContext(), exec.wfState, workflowMetricLabel(newStatus, errMsg), now.Sub(started))
+		if newStatus == workflowStatusCompleted {
+			e.observeDuration(exec.wfState.ID, exec.wfState.Name, "", now.Sub(started), now)
+		}
 	}
 
 	return nil
 }
 
+// runMetricsRecorder is implemented by metrics recorders that can label
+// workflow and task metrics with the workflow name and tenant. It is optional
+// so that MetricsRecorder stays unchanged.
+type runMetricsRecorder interface {
+	RecordWorkflowSubmissionFor(status, workflowName, tenant string)
+	RecordWorkflowDurationFor(ctx context.Context, status, workflowName, tenant string, duration time.Duration)
+	RecordTaskExecutionFor(status, workflowName, tenant string)
+	RecordTaskDurationFor(ctx context.Context, workflowName, tenant string, duration time.Duration)
+}
+
+// recordWorkflowRun records the outcome and duration of the finished run wf.
+// The duration links to the trace of ctx, that of the run.
+func (e *Engine) recordWorkflowRun(ctx context.Context, wf *storage.WorkflowState, status string, duration time.Duration) {
+	if recorder, ok := e.metrics.(runMetricsRecorder); ok {
+		tenant := e.tenantOf(wf)
+		recorder.RecordWorkflowDurationFor(ctx, status, wf.Name, tenant, duration)
+		recorder.RecordWorkflowSubmissionFor(status, wf.Name, tenant)
+		return
+	}
+	e.metrics.RecordWorkflowDuration(status, duration)
+	e.metrics.RecordWorkflowSubmission(status)
+}
+
+// recordTaskRun records the outcome, and the duration if it started, of the
+// finished task taskState of wf. The duration links to the trace of ctx, that
+// of the run the task spans belong to.
+func (e *Engine) recordTaskRun(ctx context.Context, wf *storage.WorkflowState, taskState *storage.TaskState, status string) {
+	if recorder, ok := e.metrics.(runMetricsRecorder); ok {
+		tenant := e.tenantOf(wf)
+		if taskState.StartedAt != nil {
+			recorder.RecordTaskDurationFor(ctx, wf.Name, tenant, taskState.CompletedAt.Sub(*taskState.StartedAt))
+		}
+		recorder.RecordTaskExecutionFor(status, wf.Name, tenant)
+		return
+	}
+	if taskState.StartedAt != nil {
+		e.metrics.RecordTaskDuration(taskState.CompletedAt.Sub(*taskState.StartedAt))
+	}
+	e.metrics.RecordTaskExecution(status)
+}
+
 func workflowMetricLabel(status, errMsg string) string {
 	if status == workflowStatusFailed && strings.Contains(strings.ToLower(errMsg), "deadline") {
 		return "failed_timeout"
@@ -366,6 +646,9 @@ func (e *Engine) transitionTask(exec *workflowExecution, taskID string, oldState
 
 	now := time.Now().UTC()
 	taskState.Status = newStatus
+	if result.DedupedFrom != "" {
+		taskState.DedupedFrom = result.DedupedFrom
+	}
 	if newStatus == taskStatusRunning {
 		started := now
 		if !result.StartedAt.IsZero() {
@@ -374,8 +657,15 @@ func (e *Engine) transitionTask(exec *workflowExecution, taskID string, oldState
 		taskState.StartedAt = &started
 		taskState.Error = ""
 	}
+	if result.Stalls > taskState.Stalls {
+		taskState.Stalls = result.Stalls
+	}
 	if newStatus == taskStatusScheduled && oldStatus == taskStatusRunning {
-		e.metrics.RecordTaskRetry()
+		if result.Preemptions > taskState.Preemptions {
+			taskState.Preemptions = result.Preemptions
+		} else {
+			e.metrics.RecordTaskRetry()
+		}
 	}
 	if isTerminalTaskStatus(newStatus) {
 		completed := now
@@ -383,6 +673,8 @@ func (e *Engine) transitionTask(exec *workflowExecution, taskID string, oldState
 			completed = result.EndedAt.UTC()
 		}
 		taskState.CompletedAt = &completed
+		taskState.DeadlineMissed = result.DeadlineMissed
+		taskState.Skipped = result.Skipped && newStatus == taskStatusCompleted
 		if result.Error != nil {
 			taskState.Error = result.Error.Error()
 		} else if newStatus != taskStatusCompleted {
@@ -390,16 +682,39 @@ func (e *Engine) transitionTask(exec *workflowExecution, taskID string, oldState
 		} else {
 			taskState.Error = ""
 		}
-		if taskState.StartedAt != nil {
-			e.metrics.RecordTaskDuration(completed.Sub(*taskState.StartedAt))
+		e.recordTaskRun(exec.traceContext(), exec.wfState, taskState, taskMetricLabel(newStatus, taskState.Error))
+		if taskState.StartedAt != nil && newStatus == taskStatusCompleted && taskState.DedupedFrom == "" {
+			e.observeDuration(exec.wfState.ID, exec.wfState.Name, taskID, completed.Sub(*taskState.StartedAt), completed)
 		}
-		e.metrics.RecordTaskExecution(taskMetricLabel(newStatus, taskState.Error))
+		e.accountTaskSeconds(exec, taskID, taskState)
+	}
+
+	entry := storage.JournalEntry{
+		Seq:    len(exec.wfState.Journal) + 1,
+		TaskID: taskID,
+		From:   oldStatus,
+		To:     newStatus,
+		At:     now,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	if out, ok := exec.attempts[taskID]; ok && oldStatus == taskStatusRunning {
+		delete(exec.attempts, taskID)
+		out.journal(&entry, taskState, newStatus == taskStatusCompleted)
 	}
+	exec.wfState.Journal = append(exec.wfState.Journal, entry)
 
-	if err := e.storage.SaveTask(context.Background(), exec.workflowID, taskState); err != nil {
+	if e.taskWrites != nil {
+		e.taskWrites.save(exec.workflowID, taskState)
+	} else if err := e.storage.SaveTask(context.Background(), exec.workflowID, taskState); err != nil {
 		return err
 	}
-	e.emitTaskStateChanged(exec.workflowID, taskID, taskState.Name, oldStatus, newStatus, taskState.Error, taskState.Result)
+	e.emitTaskStateChanged(exec.wfState.RequestID, exec.workflowID, taskID, taskState.Name, oldStatus, newStatus, taskState.Error, eventResult(taskState))
+	if isTerminalTaskStatus(newStatus) && result.DeadlineMissed {
+		e.logger.Warn("task missed deadline", "workflow_id", exec.workflowID, "task_id", taskID, "deadline", result.Deadline)
+		e.emitTaskDeadlineMissed(exec.workflowID, taskID, taskState.Name, result.Deadline, *taskState.CompletedAt)
+	}
 
 	_ = oldState
 	return nil
@@ -446,10 +761,14 @@ func (e *Engine) markWorkflowFailedFromPending(ctx context.Context, workflowID s
 	wfState.Status = workflowStatusFailed
 	wfState.CompletedAt = &now
 	wfState.Error = cause.Error()
-	if err := e.storage.SaveWorkflow(ctx, wfState); err != nil {
+	notifications, err := e.notifications(wfState, workflowStatusPending, false)
+	if err != nil {
+		return err
+	}
+	if err := e.saveWorkflowNotifying(ctx, wfState, notifications); err != nil {
 		return err
 	}
-	e.emitWorkflowStateChanged(wfState.ID, wfState.Name, workflowStatusPending, workflowStatusFailed)
+	e.emitWorkflowStateChanged(wfState.RequestID, wfState.ID, wfState.Name, workflowStatusPending, workflowStatusFailed)
 	e.metrics.RecordWorkflowSubmission(workflowMetricLabel(workflowStatusFailed, cause.Error()))
 	return nil
 }
@@ -460,11 +779,112 @@ func (e *Engine) GetWorkflowStatusResponse(ctx context.Context, id string) (*mod
 	if err != nil {
 		return nil, err
 	}
-	return e.workflowStateToResponse(wfState), nil
+	return e.workflowStateToResponse(ctx, wfState), nil
+}
+
+// workflowStateToResponse converts wfState for the caller of ctx, redacting
+// sensitive task data it may not read.
+func (e *Engine) workflowStateToResponse(ctx context.Context, wfState *storage.WorkflowState) *models.WorkflowStatusResponse {
+	resp := e.workflowStateHeader(wfState)
+	resp.Tasks = make([]models.TaskStatus, 0, len(wfState.TaskStatus))
+
+	definitions := taskDefinitions(wfState)
+	reveal := e.readsSensitive(ctx)
+	for _, taskID := range sortedTaskIDs(wfState) {
+		resp.Tasks = append(resp.Tasks, taskStateToStatus(wfState.TaskStatus[taskID], definitions[taskID], reveal))
+	}
+
+	return resp
+}
+
+// recentFailuresLimit is the number of failed tasks listed in a workflow's
+// task summary.
+const recentFailuresLimit = 10
+
+// GetWorkflowSummaryResponse returns the workflow status with a task summary
+// (counts per status and the most recent failures) in place of the task
+// list, which keeps status calls small for workflows with many tasks.
+func (e *Engine) GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	wfState, err := e.storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := e.workflowStateHeader(wfState)
+	summary := &models.TaskSummary{
+		Total:  len(wfState.TaskStatus),
+		Counts: make(map[string]int),
+	}
+	var failed []*storage.TaskState
+	for _, taskState := range wfState.TaskStatus {
+		summary.Counts[taskState.Status]++
+		if taskState.Status == taskStatusFailed {
+			failed = append(failed, taskState)
+		}
+	}
+
+	sort.Slice(failed, func(i, j int) bool {
+		a, b := taskCompletedAt(failed[i]), taskCompletedAt(failed[j])
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return failed[i].ID < failed[j].ID
+	})
+	if len(failed) > recentFailuresLimit {
+		failed = failed[:recentFailuresLimit]
+	}
+	definitions := taskDefinitions(wfState)
+	reveal := e.readsSensitive(ctx)
+	for _, taskState := range failed {
+		summary.RecentFailures = append(summary.RecentFailures, taskStateToStatus(taskState, definitions[taskState.ID], reveal))
+	}
+
+	resp.TaskSummary = summary
+	return resp, nil
+}
+
+// ListWorkflowTasksResponse returns one page of the tasks of a workflow,
+// ordered by task ID, and the number of tasks matching filter.
+func (e *Engine) ListWorkflowTasksResponse(ctx context.Context, id string, filter models.TaskFilter) ([]models.TaskStatus, int, error) {
+	wfState, err := e.storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matched []string
+	for _, taskID := range sortedTaskIDs(wfState) {
+		if filter.Status == "" || wfState.TaskStatus[taskID].Status == filter.Status {
+			matched = append(matched, taskID)
+		}
+	}
+	total := len(matched)
+
+	start := min(max(filter.Offset, 0), total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+
+	definitions := taskDefinitions(wfState)
+	reveal := e.readsSensitive(ctx)
+	tasks := make([]models.TaskStatus, 0, end-start)
+	for _, taskID := range matched[start:end] {
+		tasks = append(tasks, taskStateToStatus(wfState.TaskStatus[taskID], definitions[taskID], reveal))
+	}
+	return tasks, total, nil
+}
+
+// taskCompletedAt returns when the task completed, or the zero time.
+func taskCompletedAt(taskState *storage.TaskState) time.Time {
+	if taskState.CompletedAt == nil {
+		return time.Time{}
+	}
+	return *taskState.CompletedAt
 }
 
-func (e *Engine) workflowStateToResponse(wfState *storage.WorkflowState) *models.WorkflowStatusResponse {
-	resp := &models.WorkflowStatusResponse{
+// workflowStateHeader converts the workflow-level fields of wfState.
+func (e *Engine) workflowStateHeader(wfState *storage.WorkflowState) *models.WorkflowStatusResponse {
+	return &models.WorkflowStatusResponse{
 		ID:          wfState.ID,
 		Name:        wfState.Name,
 		Status:      wfState.Status,
@@ -473,28 +893,74 @@ func (e *Engine) workflowStateToResponse(wfState *storage.WorkflowState) *models
 		CompletedAt: wfState.CompletedAt,
 		Metadata:    wfState.Metadata,
 		Error:       wfState.Error,
-		Tasks:       make([]models.TaskStatus, 0, len(wfState.TaskStatus)),
+		Priority:    wfState.Priority,
+		Deadline:    wfState.Deadline,
+		RequestID:   wfState.RequestID,
+		CallbackURL: wfState.CallbackURL,
+		Budget:      wfState.Budget,
+		SLA:         slaStatus(wfState, time.Now().UTC()),
+		Cost:        e.runCost(wfState),
 	}
+}
 
+func taskDefinitions(wfState *storage.WorkflowState) map[string]models.TaskDefinition {
+	definitions := make(map[string]models.TaskDefinition, len(wfState.Tasks))
+	for _, def := range wfState.Tasks {
+		definitions[def.ID] = def
+	}
+	return definitions
+}
+
+func sortedTaskIDs(wfState *storage.WorkflowState) []string {
 	taskIDs := make([]string, 0, len(wfState.TaskStatus))
 	for taskID := range wfState.TaskStatus {
 		taskIDs = append(taskIDs, taskID)
 	}
 	sort.Strings(taskIDs)
-	for _, taskID := range taskIDs {
-		taskState := wfState.TaskStatus[taskID]
-		resp.Tasks = append(resp.Tasks, models.TaskStatus{
-			ID:          taskState.ID,
-			Name:        taskState.Name,
-			Status:      taskState.Status,
-			StartedAt:   taskState.StartedAt,
-			CompletedAt: taskState.CompletedAt,
-			Error:       taskState.Error,
-			Result:      taskState.Result,
-		})
+	return taskIDs
+}
+
+// taskStateToStatus converts a task's state and definition. Sensitive config
+// and results are redacted unless reveal is set.
+func taskStateToStatus(taskState *storage.TaskState, def models.TaskDefinition, reveal bool) models.TaskStatus {
+	status := models.TaskStatus{
+		ID:             taskState.ID,
+		Name:           taskState.Name,
+		Status:         taskState.Status,
+		Type:           def.Type,
+		DependsOn:      def.DependsOn,
+		Config:         def.Config,
+		Timeout:        def.Timeout,
+		Retries:        def.Retries,
+		StartedAt:      taskState.StartedAt,
+		CompletedAt:    taskState.CompletedAt,
+		Error:          taskState.Error,
+		Result:         taskState.Result,
+		DedupeKey:      taskState.DedupeKey,
+		DedupedFrom:    taskState.DedupedFrom,
+		Preemptions:    taskState.Preemptions,
+		DeadlineMissed: taskState.DeadlineMissed,
+		Stalls:         taskState.Stalls,
+		Skipped:        taskState.Skipped,
+		Inputs:         taskInputs(taskState.Inputs),
+		Sensitive:      def.Sensitive || taskState.Sensitive,
+	}
+	if status.Sensitive && !reveal {
+		status.Config = redactConfig(status.Config)
+		status.Result = redactResult(status.Result)
 	}
+	return status
+}
 
-	return resp
+func taskInputs(inputs []storage.TaskInput) []models.TaskInput {
+	if len(inputs) == 0 {
+		return nil
+	}
+	out := make([]models.TaskInput, len(inputs))
+	for i, input := range inputs {
+		out[i] = models.TaskInput{WorkflowID: input.WorkflowID, TaskID: input.TaskID, Artifact: input.Artifact}
+	}
+	return out
 }
 
 // ListWorkflowsResponse lists workflows with filtering.
@@ -515,12 +981,29 @@ func (e *Engine) ListWorkflowsResponse(ctx context.Context, filter models.Workfl
 
 	result := make([]*models.WorkflowStatusResponse, 0, len(workflows))
 	for _, wf := range workflows {
-		result = append(result, e.workflowStateToResponse(wf))
+		result = append(result, e.workflowStateToResponse(ctx, wf))
 	}
 
 	return result, total, nil
 }
 
+// StreamWorkflows calls fn for each workflow matching filter, reading them
+// from storage one at a time instead of materializing a page and its total.
+// A zero Limit streams every match; an error from fn stops the stream.
+func (e *Engine) StreamWorkflows(ctx context.Context, filter models.WorkflowFilter, fn func(*models.WorkflowStatusResponse) error) error {
+	storageFilter := &storage.WorkflowFilter{
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	if filter.Status != "" {
+		storageFilter.Status = []string{filter.Status}
+	}
+
+	return storage.ScanWorkflows(ctx, e.storage, storageFilter, func(wf *storage.WorkflowState) error {
+		return fn(e.workflowStateToResponse(ctx, wf))
+	})
+}
+
 // CancelWorkflowRequest cancels a running or pending workflow.
 func (e *Engine) CancelWorkflowRequest(ctx context.Context, id string) error {
 	wfState, err := e.storage.GetWorkflow(ctx, id)
@@ -556,6 +1039,7 @@ func (e *Engine) CancelWorkflowRequest(ctx context.Context, id string) error {
 	wfState.Status = workflowStatusCancelled
 	wfState.CompletedAt = &now
 	wfState.Error = "cancelled by request"
+	var cancelled []*storage.TaskState
 	for _, task := range wfState.TaskStatus {
 		if isTerminalTaskStatus(task.Status) {
 			continue
@@ -563,22 +1047,75 @@ func (e *Engine) CancelWorkflowRequest(ctx context.Context, id string) error {
 		task.Status = taskStatusCancelled
 		task.CompletedAt = &now
 		task.Error = "cancelled by request"
-		if err := e.storage.SaveTask(ctx, wfState.ID, task); err != nil {
-			return err
-		}
-		e.emitTaskStateChanged(wfState.ID, task.ID, task.Name, oldStatus, task.Status, task.Error, task.Result)
+		cancelled = append(cancelled, task)
 	}
 
-	if err := e.storage.SaveWorkflow(ctx, wfState); err != nil {
+	notifications, err := e.notifications(wfState, oldStatus, false)
+	if err != nil {
 		return err
 	}
-	e.emitWorkflowStateChanged(wfState.ID, wfState.Name, oldStatus, wfState.Status)
+	err = storage.WriteBatch(ctx, e.storage, func(w storage.Writer) error {
+		for _, task := range cancelled {
+			if err := w.SaveTask(ctx, wfState.ID, task); err != nil {
+				return err
+			}
+		}
+		if err := w.SaveWorkflow(ctx, wfState); err != nil {
+			return err
+		}
+		return writeOutbox(ctx, w, notifications)
+	})
+	if err != nil {
+		return err
+	}
+	if len(notifications) > 0 {
+		e.wakeOutbox()
+	}
+	for _, task := range cancelled {
+		e.emitTaskStateChanged(wfState.RequestID, wfState.ID, task.ID, task.Name, oldStatus, task.Status, task.Error, eventResult(task))
+	}
+	e.emitWorkflowStateChanged(wfState.RequestID, wfState.ID, wfState.Name, oldStatus, wfState.Status)
 	e.metrics.RecordWorkflowSubmission(workflowStatusCancelled)
 
 	e.logger.Info("workflow cancelled", "id", id)
 	return nil
 }
 
+// RetryWorkflowRequest resubmits the definition of a failed or cancelled
+// workflow as a new asynchronous workflow. The new workflow's metadata
+// records the original under "retry_of".
+func (e *Engine) RetryWorkflowRequest(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	wfState, err := e.storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wfState.Status != workflowStatusFailed && wfState.Status != workflowStatusCancelled {
+		return nil, errs.Newf(errs.Conflict, "workflow cannot be retried: %s", wfState.Status)
+	}
+
+	metadata := make(map[string]string, len(wfState.Metadata)+1)
+	for k, v := range wfState.Metadata {
+		metadata[k] = v
+	}
+	metadata["retry_of"] = wfState.ID
+
+	req := &models.WorkflowRequest{
+		Name:        wfState.Name,
+		Description: wfState.Description,
+		Tasks:       wfState.Tasks,
+		Metadata:    metadata,
+		Async:       true,
+		Priority:    wfState.Priority,
+		GangLayers:  wfState.GangLayers,
+		Budget:      wfState.Budget,
+	}
+	if wfState.Deadline != nil {
+		// The retry gets the same latency budget as the original run.
+		req.Deadline = int(wfState.Deadline.Sub(wfState.CreatedAt) / time.Second)
+	}
+	return e.SubmitWorkflowRuntime(ctx, req, SubmitWorkflowOptions{Mode: SubmissionModeAsync})
+}
+
 // GetTaskResultResponse retrieves a task's result.
 func (e *Engine) GetTaskResultResponse(ctx context.Context, workflowID, taskID string) (*models.TaskResultResponse, error) {
 	taskState, err := e.storage.GetTask(ctx, workflowID, taskID)
@@ -592,9 +1129,13 @@ func (e *Engine) GetTaskResultResponse(ctx context.Context, workflowID, taskID s
 		Status:      taskState.Status,
 		Error:       taskState.Error,
 		CompletedAt: taskState.CompletedAt,
+		DedupedFrom: taskState.DedupedFrom,
 	}
 	if isTerminalTaskStatus(taskState.Status) {
 		resp.Result = taskState.Result
+		if taskState.Sensitive && !e.readsSensitive(ctx) {
+			resp.Result = redactResult(resp.Result)
+		}
 	}
 	if !isTerminalTaskStatus(taskState.Status) {
 		resp.Result = nil
@@ -609,9 +1150,10 @@ func (e *Engine) IsHealthy() bool {
 	return engineState(e.state.Load()) == stateRunning
 }
 
-// IsReady returns true if the engine is ready to accept requests.
+// IsReady returns true if the engine is ready to accept requests: it is
+// running, has completed every startup phase and is not draining.
 func (e *Engine) IsReady() bool {
-	return engineState(e.state.Load()) == stateRunning && e.laneManager != nil
+	return e.Readiness().Ready
 }
 
 // EngineStatus represents the engine's current status.
@@ -619,6 +1161,8 @@ type EngineStatus struct {
 	State   string `json:"state"`
 	Uptime  string `json:"uptime,omitempty"`
 	Version string `json:"version,omitempty"`
+	// QueuedWorkflows is the number of workflows waiting for a concurrency slot.
+	QueuedWorkflows int `json:"queued_workflows,omitempty"`
 }
 
 // GetStatus returns detailed engine status.
@@ -639,8 +1183,9 @@ func (e *Engine) GetStatus() *EngineStatus {
 	}
 
 	return &EngineStatus{
-		State:   stateStr,
-		Version: e.cfg.App.Version,
+		State:           stateStr,
+		Version:         e.cfg.App.Version,
+		QueuedWorkflows: e.workflowLimits.queued(),
 	}
 }
 
@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"context"
+	"maps"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Limits of the values of one workflow run. Values are meant for small flags
+// tasks coordinate on, not for data; artifacts and the memory hub hold that.
+const (
+	MaxWorkflowValues         = 128
+	MaxWorkflowValueKeyBytes  = 128
+	MaxWorkflowValueSizeBytes = 4 << 10
+)
+
+// valuesKey is the context key of the running workflow's workflowValues.
+type valuesKey struct{}
+
+// WorkflowValue returns the value of key in the workflow run the task running
+// with ctx belongs to. It reports false when the key is not set or the task
+// runs outside the engine.
+func WorkflowValue(ctx context.Context, key string) (string, bool) {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return "", false
+	}
+	return v.get(key)
+}
+
+// SetWorkflowValue sets key to value in the workflow run the task running with
+// ctx belongs to. The value is persisted with the run before SetWorkflowValue
+// returns, so tasks running after it, including after a restart, see it.
+func SetWorkflowValue(ctx context.Context, key, value string) error {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return errs.New(errs.BadRequest, "workflow values are only available to tasks run by the engine")
+	}
+	if err := checkValueKey(key); err != nil {
+		return err
+	}
+	return v.set(key, value)
+}
+
+// UnsetWorkflowValue removes key from the workflow run the task running with
+// ctx belongs to. Removing a key that is not set is not an error.
+func UnsetWorkflowValue(ctx context.Context, key string) error {
+	v, ok := ctx.Value(valuesKey{}).(*workflowValues)
+	if !ok {
+		return errs.New(errs.BadRequest, "workflow values are only available to tasks run by the engine")
+	}
+	if err := checkValueKey(key); err != nil {
+		return err
+	}
+	return v.unset(key)
+}
+
+// workflowValues is the key-value store of one running workflow. The values
+// live in the run's workflow record and are guarded by exec.mu like the rest
+// of it; every change saves the record.
+type workflowValues struct {
+	engine *Engine
+	exec   *workflowExecution
+}
+
+// withTask returns ctx carrying the values for WorkflowValue and
+// SetWorkflowValue.
+func (v *workflowValues) withTask(ctx context.Context) context.Context {
+	return context.WithValue(ctx, valuesKey{}, v)
+}
+
+func (v *workflowValues) get(key string) (string, bool) {
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	value, ok := v.exec.wfState.Values[key]
+	return value, ok
+}
+
+// snapshot returns a copy of the values.
+func (v *workflowValues) snapshot() map[string]string {
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	return maps.Clone(v.exec.wfState.Values)
+}
+
+func (v *workflowValues) set(key, value string) error {
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+	return v.setLocked(key, value)
+}
+
+// setLocked sets key to value. The caller holds exec.mu.
+func (v *workflowValues) setLocked(key, value string) error {
+	if err := validateWorkflowValue(key, value); err != nil {
+		return err
+	}
+	wf := v.exec.wfState
+	if err := checkValuesWritable(wf); err != nil {
+		return err
+	}
+	old, existed := wf.Values[key]
+	if existed && old == value {
+		return nil
+	}
+	if !existed && len(wf.Values) >= MaxWorkflowValues {
+		return errs.Newf(errs.BadRequest, "workflow %s already has %d values", wf.ID, MaxWorkflowValues)
+	}
+	if wf.Values == nil {
+		wf.Values = make(map[string]string)
+	}
+	wf.Values[key] = value
+	if err := v.save(); err != nil {
+		if existed {
+			wf.Values[key] = old
+		} else {
+			delete(wf.Values, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (v *workflowValues) unset(key string) error {
+	v.exec.mu.Lock()
+	defer v.exec.mu.Unlock()
+
+	wf := v.exec.wfState
+	if err := checkValuesWritable(wf); err != nil {
+		return err
+	}
+	old, existed := wf.Values[key]
+	if !existed {
+		return nil
+	}
+	delete(wf.Values, key)
+	if err := v.save(); err != nil {
+		wf.Values[key] = old
+		return err
+	}
+	return nil
+}
+
+// save persists the workflow record. The caller holds exec.mu.
+func (v *workflowValues) save() error {
+	ctx := context.Background()
+	if err := v.engine.taskWrites.flush(ctx, v.exec.workflowID); err != nil {
+		return err
+	}
+	return v.engine.storage.SaveWorkflow(ctx, v.exec.wfState)
+}
+
+// checkValuesWritable returns a Conflict error once wf has finished; the
+// values of a finished run are read-only.
+func checkValuesWritable(wf *storage.WorkflowState) error {
+	if isTerminalWorkflowStatus(wf.Status) {
+		return errs.Newf(errs.Conflict, "workflow %s is not running: %s", wf.ID, wf.Status)
+	}
+	return nil
+}
+
+// validateWorkflowValue checks key and value against the value limits.
+func validateWorkflowValue(key, value string) error {
+	if key == "" {
+		return errs.New(errs.BadRequest, "workflow value key is required")
+	}
+	if len(key) > MaxWorkflowValueKeyBytes {
+		return errs.Newf(errs.BadRequest, "workflow value key exceeds %d bytes", MaxWorkflowValueKeyBytes)
+	}
+	if len(value) > MaxWorkflowValueSizeBytes {
+		return errs.Newf(errs.BadRequest, "workflow value %s exceeds %d bytes", key, MaxWorkflowValueSizeBytes)
+	}
+	return nil
+}
+
+// WorkflowValues returns the values of a workflow run. Values of a running
+// workflow are read from the run itself.
+func (e *Engine) WorkflowValues(ctx context.Context, workflowID string) (map[string]string, error) {
+	if exec, ok := e.getExecution(workflowID); ok {
+		return (&workflowValues{engine: e, exec: exec}).snapshot(), nil
+	}
+	wfState, err := e.storage.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return wfState.Values, nil
+}
+
+// PutWorkflowValue sets key to value in a running workflow, e.g. to signal
+// its tasks from outside. Values of finished runs are read-only.
+func (e *Engine) PutWorkflowValue(ctx context.Context, workflowID, key, value string) error {
+	if err := checkValueKey(key); err != nil {
+		return err
+	}
+	values, err := e.runningValues(ctx, workflowID)
+	if err != nil {
+		return err
+	}
+	return values.set(key, value)
+}
+
+// DeleteWorkflowValue removes key from a running workflow.
+func (e *Engine) DeleteWorkflowValue(ctx context.Context, workflowID, key string) error {
+	if err := checkValueKey(key); err != nil {
+		return err
+	}
+	values, err := e.runningValues(ctx, workflowID)
+	if err != nil {
+		return err
+	}
+	return values.unset(key)
+}
+
+// runningValues returns the values of a running workflow, or a NotFound or
+// Conflict error when the workflow does not exist or is not running.
+func (e *Engine) runningValues(ctx context.Context, workflowID string) (*workflowValues, error) {
+	if exec, ok := e.getExecution(workflowID); ok {
+		return &workflowValues{engine: e, exec: exec}, nil
+	}
+	wfState, err := e.storage.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkValuesWritable(wfState); err != nil {
+		return nil, err
+	}
+	return nil, errs.Newf(errs.Conflict, "workflow %s is not running: %s", workflowID, wfState.Status)
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func TestWorkflowValues_SharedBetweenTasksAndAPI(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	eng, err := New(minConfig(), nil, store)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := eng.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer eng.Stop(context.Background())
+
+	release := make(chan struct{})
+	var seen string
+	status, err := eng.SubmitWorkflowRuntime(context.Background(), &models.WorkflowRequest{
+		Name: "migrate",
+		Tasks: []models.TaskDefinition{
+			{ID: "migrate", Name: "migrate", Type: "function"},
+			{ID: "verify", Name: "verify", Type: "function", DependsOn: []string{"migrate"}},
+		},
+	}, SubmitWorkflowOptions{
+		Mode: SubmissionModeAsync,
+		TaskFns: map[string]func(context.Context) error{
+			"migrate": func(ctx context.Context) error {
+				if err := SetWorkflowValue(ctx, "schema", "v2"); err != nil {
+					return err
+				}
+				<-release
+				return nil
+			},
+			"verify": func(ctx context.Context) error {
+				seen, _ = WorkflowValue(ctx, "operator")
+				return UnsetWorkflowValue(ctx, "schema")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflowRuntime() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		values, err := eng.WorkflowValues(context.Background(), status.ID)
+		if err != nil {
+			t.Fatalf("WorkflowValues() error = %v", err)
+		}
+		if values["schema"] == "v2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("value set by task not visible, got %v", values)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stored, err := store.GetWorkflow(context.Background(), status.ID)
+	if err != nil || stored.Values["schema"] != "v2" {
+		t.Fatalf("expected the value to be persisted with the run, got %v, %v", stored, err)
+	}
+
+	if err := eng.PutWorkflowValue(context.Background(), status.ID, "operator", "alice"); err != nil {
+		t.Fatalf("PutWorkflowValue() error = %v", err)
+	}
+	if err := eng.PutWorkflowValue(context.Background(), status.ID, "", "x"); !errs.Is(err, errs.BadRequest) {
+		t.Errorf("expected a bad request for an empty key, got %v", err)
+	}
+	if err := eng.PutWorkflowValue(context.Background(), status.ID, "big", strings.Repeat("x", MaxWorkflowValueSizeBytes+1)); !errs.Is(err, errs.BadRequest) {
+		t.Errorf("expected a bad request for an oversized value, got %v", err)
+	}
+	close(release)
+
+	if err := waitWorkflowStatus(eng, status.ID, workflowStatusCompleted, 2*time.Second); err != nil {
+		t.Fatalf("workflow did not complete: %v", err)
+	}
+	if seen != "alice" {
+		t.Errorf("downstream task saw operator = %q, want alice", seen)
+	}
+	values, err := eng.WorkflowValues(context.Background(), status.ID)
+	if err != nil {
+		t.Fatalf("WorkflowValues() error = %v", err)
+	}
+	if len(values) != 1 || values["operator"] != "alice" {
+		t.Errorf("values of the finished run = %v", values)
+	}
+
+	if err := eng.PutWorkflowValue(context.Background(), status.ID, "operator", "bob"); !errs.Is(err, errs.Conflict) {
+		t.Errorf("expected a conflict setting a value of a finished run, got %v", err)
+	}
+	if _, err := eng.WorkflowValues(context.Background(), "missing"); !errs.Is(err, errs.NotFound) {
+		t.Errorf("expected not found for an unknown workflow, got %v", err)
+	}
+}
+
+func TestWorkflowValues_OutsideEngine(t *testing.T) {
+	if _, ok := WorkflowValue(context.Background(), "key"); ok {
+		t.Fatal("expected no value outside the engine")
+	}
+	if err := SetWorkflowValue(context.Background(), "key", "value"); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request outside the engine, got %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+// Package errs defines the error taxonomy shared by the HTTP and gRPC APIs.
+//
+// Every API-facing failure carries a stable Code that clients can branch on,
+// a retriability hint and optional structured details. Codes map to HTTP
+// status codes and gRPC codes through a single table so both transports
+// report the same failure the same way.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code is a stable, machine-readable error code.
+type Code string
+
+// Error codes. The string values are part of the public API and must not change.
+const (
+	BadRequest         Code = "BAD_REQUEST"
+	ValidationFailed   Code = "VALIDATION_FAILED"
+	Unauthenticated    Code = "UNAUTHORIZED"
+	PermissionDenied   Code = "FORBIDDEN"
+	NotFound           Code = "NOT_FOUND"
+	MethodNotAllowed   Code = "METHOD_NOT_ALLOWED"
+	AlreadyExists      Code = "ALREADY_EXISTS"
+	Conflict           Code = "CONFLICT"
+	PreconditionFailed Code = "PRECONDITION_FAILED"
+	RateLimited        Code = "RATE_LIMITED"
+	Canceled           Code = "CANCELED"
+	Internal           Code = "INTERNAL_SERVER_ERROR"
+	NotImplemented     Code = "NOT_IMPLEMENTED"
+	ServiceUnavailable Code = "SERVICE_UNAVAILABLE"
+	Timeout            Code = "GATEWAY_TIMEOUT"
+)
+
+// StatusClientClosedRequest is the non-standard HTTP status used when the
+// client cancelled the request.
+const StatusClientClosedRequest = 499
+
+type codeInfo struct {
+	httpStatus int
+	grpcCode   codes.Code
+	retryable  bool
+}
+
+// codeTable is the single source of truth for transport mappings.
+var codeTable = map[Code]codeInfo{
+	BadRequest:         {http.StatusBadRequest, codes.InvalidArgument, false},
+	ValidationFailed:   {http.StatusBadRequest, codes.InvalidArgument, false},
+	Unauthenticated:    {http.StatusUnauthorized, codes.Unauthenticated, false},
+	PermissionDenied:   {http.StatusForbidden, codes.PermissionDenied, false},
+	NotFound:           {http.StatusNotFound, codes.NotFound, false},
+	MethodNotAllowed:   {http.StatusMethodNotAllowed, codes.Unimplemented, false},
+	AlreadyExists:      {http.StatusConflict, codes.AlreadyExists, false},
+	Conflict:           {http.StatusConflict, codes.FailedPrecondition, false},
+	PreconditionFailed: {http.StatusPreconditionFailed, codes.FailedPrecondition, false},
+	RateLimited:        {http.StatusTooManyRequests, codes.ResourceExhausted, true},
+	Canceled:           {StatusClientClosedRequest, codes.Canceled, false},
+	Internal:           {http.StatusInternalServerError, codes.Internal, false},
+	NotImplemented:     {http.StatusNotImplemented, codes.Unimplemented, false},
+	ServiceUnavailable: {http.StatusServiceUnavailable, codes.Unavailable, true},
+	Timeout:            {http.StatusGatewayTimeout, codes.DeadlineExceeded, true},
+}
+
+func (c Code) info() codeInfo {
+	if info, ok := codeTable[c]; ok {
+		return info
+	}
+	return codeTable[Internal]
+}
+
+// HTTPStatus returns the HTTP status code for c.
+func (c Code) HTTPStatus() int { return c.info().httpStatus }
+
+// GRPCCode returns the gRPC status code for c.
+func (c Code) GRPCCode() codes.Code { return c.info().grpcCode }
+
+// Retryable reports whether failures with code c are worth retrying.
+func (c Code) Retryable() bool { return c.info().retryable }
+
+// String returns the code value.
+func (c Code) String() string { return string(c) }
+
+// CodeFromHTTPStatus returns the code for an HTTP status.
+func CodeFromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnprocessableEntity:
+		return ValidationFailed
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusMethodNotAllowed:
+		return MethodNotAllowed
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusTooManyRequests:
+		return RateLimited
+	case StatusClientClosedRequest:
+		return Canceled
+	case http.StatusNotImplemented:
+		return NotImplemented
+	case http.StatusServiceUnavailable:
+		return ServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return Timeout
+	default:
+		return Internal
+	}
+}
+
+// CodeFromGRPC returns the code for a gRPC status code.
+func CodeFromGRPC(code codes.Code) Code {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return BadRequest
+	case codes.Unauthenticated:
+		return Unauthenticated
+	case codes.PermissionDenied:
+		return PermissionDenied
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.FailedPrecondition, codes.Aborted:
+		return Conflict
+	case codes.ResourceExhausted:
+		return RateLimited
+	case codes.Canceled:
+		return Canceled
+	case codes.Unimplemented:
+		return NotImplemented
+	case codes.Unavailable:
+		return ServiceUnavailable
+	case codes.DeadlineExceeded:
+		return Timeout
+	default:
+		return Internal
+	}
+}
+
+// Error is an API error with a stable code.
+type Error struct {
+	// Code is the stable error code.
+	Code Code
+	// Message is a human-readable description safe to return to clients.
+	Message string
+	// Retryable overrides the code's default retriability when set.
+	Retryable *bool
+	// Details carries structured, client-safe context (field names, IDs, limits).
+	Details map[string]interface{}
+	// Cause is the underlying error. It is never exposed to clients.
+	Cause error
+}
+
+// New creates an error with the given code and message.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf creates an error with the given code and formatted message.
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap creates an error with the given code and client-safe message that wraps cause.
+func Wrap(cause error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil && e.Cause.Error() != e.Message {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error { return e.Cause }
+
+// IsRetryable reports whether the operation may succeed if retried.
+func (e *Error) IsRetryable() bool {
+	if e.Retryable != nil {
+		return *e.Retryable
+	}
+	return e.Code.Retryable()
+}
+
+// WithDetail returns e with an additional detail entry.
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+// WithRetryable returns e with an explicit retriability hint.
+func (e *Error) WithRetryable(retryable bool) *Error {
+	e.Retryable = &retryable
+	return e
+}
+
+// Coder is implemented by domain errors that know their API error code.
+type Coder interface {
+	ErrorCode() Code
+}
+
+// From converts any error into an *Error. Errors that are not already
+// classified become Internal errors with a generic message so internal
+// details do not leak to clients.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var coder Coder
+	if errors.As(err, &coder) {
+		return Wrap(err, coder.ErrorCode(), coder.(error).Error())
+	}
+
+	if grpcErr := fromGRPCStatus(err); grpcErr != nil {
+		return grpcErr
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, Timeout, "request timed out")
+	case errors.Is(err, context.Canceled):
+		return Wrap(err, Canceled, "request canceled")
+	}
+
+	return Wrap(err, Internal, "internal server error")
+}
+
+// CodeOf returns the code of err, or Internal for unclassified errors.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	return From(err).Code
+}
+
+// Is reports whether err carries the given code.
+func Is(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// IsRetryable reports whether err is worth retrying.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return From(err).IsRetryable()
+}
+
+// HTTPStatus returns the HTTP status code for err.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return From(err).Code.HTTPStatus()
+}
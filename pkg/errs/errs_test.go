@@ -0,0 +1,149 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testNotFoundError struct{ id string }
+
+func (e *testNotFoundError) Error() string   { return "thing not found: " + e.id }
+func (e *testNotFoundError) ErrorCode() Code { return NotFound }
+
+func TestCodeMappings(t *testing.T) {
+	tests := []struct {
+		code      Code
+		http      int
+		grpc      codes.Code
+		retryable bool
+	}{
+		{BadRequest, http.StatusBadRequest, codes.InvalidArgument, false},
+		{ValidationFailed, http.StatusBadRequest, codes.InvalidArgument, false},
+		{NotFound, http.StatusNotFound, codes.NotFound, false},
+		{AlreadyExists, http.StatusConflict, codes.AlreadyExists, false},
+		{Conflict, http.StatusConflict, codes.FailedPrecondition, false},
+		{PreconditionFailed, http.StatusPreconditionFailed, codes.FailedPrecondition, false},
+		{RateLimited, http.StatusTooManyRequests, codes.ResourceExhausted, true},
+		{ServiceUnavailable, http.StatusServiceUnavailable, codes.Unavailable, true},
+		{Timeout, http.StatusGatewayTimeout, codes.DeadlineExceeded, true},
+		{Internal, http.StatusInternalServerError, codes.Internal, false},
+		{Code("UNKNOWN_CODE"), http.StatusInternalServerError, codes.Internal, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got := tt.code.HTTPStatus(); got != tt.http {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.http)
+			}
+			if got := tt.code.GRPCCode(); got != tt.grpc {
+				t.Errorf("GRPCCode() = %s, want %s", got, tt.grpc)
+			}
+			if got := tt.code.Retryable(); got != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+	}{
+		{"api error", New(Conflict, "already running"), Conflict, "already running"},
+		{"wrapped api error", fmt.Errorf("ctx: %w", New(NotFound, "gone")), NotFound, "gone"},
+		{"coder", &testNotFoundError{id: "wf-1"}, NotFound, "thing not found: wf-1"},
+		{"deadline", context.DeadlineExceeded, Timeout, "request timed out"},
+		{"canceled", context.Canceled, Canceled, "request canceled"},
+		{"grpc status", status.Error(codes.Unavailable, "down"), ServiceUnavailable, "down"},
+		{"unclassified", errors.New("secret dsn leaked"), Internal, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+			if got.Code != tt.code {
+				t.Errorf("Code = %s, want %s", got.Code, tt.code)
+			}
+			if got.Message != tt.message {
+				t.Errorf("Message = %q, want %q", got.Message, tt.message)
+			}
+		})
+	}
+
+	if From(nil) != nil {
+		t.Error("From(nil) should be nil")
+	}
+}
+
+func TestRetryableOverride(t *testing.T) {
+	err := New(Internal, "transient").WithRetryable(true)
+	if !IsRetryable(err) {
+		t.Error("expected explicit retryable hint to win")
+	}
+	if IsRetryable(New(RateLimited, "slow down").WithRetryable(false)) {
+		t.Error("expected explicit non-retryable hint to win")
+	}
+	if IsRetryable(nil) {
+		t.Error("nil error should not be retryable")
+	}
+}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	original := New(RateLimited, "lane default is full").WithDetail("lane", "default")
+
+	grpcErr := ToGRPC(fmt.Errorf("submit: %w", original))
+	st, ok := status.FromError(grpcErr)
+	if !ok {
+		t.Fatalf("expected gRPC status error, got %T", grpcErr)
+	}
+	if st.Code() != codes.ResourceExhausted {
+		t.Errorf("code = %s, want %s", st.Code(), codes.ResourceExhausted)
+	}
+	if st.Message() != "lane default is full" {
+		t.Errorf("message = %q", st.Message())
+	}
+
+	decoded := From(grpcErr)
+	if decoded.Code != RateLimited {
+		t.Errorf("decoded code = %s, want %s", decoded.Code, RateLimited)
+	}
+	if !decoded.IsRetryable() {
+		t.Error("expected decoded error to be retryable")
+	}
+	if decoded.Details["lane"] != "default" {
+		t.Errorf("decoded details = %v", decoded.Details)
+	}
+}
+
+func TestToGRPC_PreservesHandlerStatus(t *testing.T) {
+	grpcErr := ToGRPC(status.Error(codes.InvalidArgument, "workflow_id is required"))
+	st, _ := status.FromError(grpcErr)
+	if st.Code() != codes.InvalidArgument || st.Message() != "workflow_id is required" {
+		t.Errorf("unexpected status: %s %q", st.Code(), st.Message())
+	}
+	if CodeOf(grpcErr) != BadRequest {
+		t.Errorf("CodeOf = %s, want %s", CodeOf(grpcErr), BadRequest)
+	}
+	if ToGRPC(nil) != nil {
+		t.Error("ToGRPC(nil) should be nil")
+	}
+}
+
+func TestToGRPC_HidesInternalMessages(t *testing.T) {
+	st, _ := status.FromError(ToGRPC(errors.New("pq: password authentication failed")))
+	if st.Code() != codes.Internal {
+		t.Errorf("code = %s, want %s", st.Code(), codes.Internal)
+	}
+	if st.Message() != "internal server error" {
+		t.Errorf("message = %q, want generic message", st.Message())
+	}
+}
@@ -0,0 +1,93 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain identifies goclaw errors in gRPC ErrorInfo details.
+const Domain = "goclaw"
+
+// metadataRetryable is the ErrorInfo metadata key carrying the retriability hint.
+const metadataRetryable = "retryable"
+
+// GRPCStatus converts err into a gRPC status. The stable code is carried as
+// the ErrorInfo reason, with details and the retriability hint as metadata.
+func GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	e := From(err)
+	st := status.New(e.Code.GRPCCode(), e.Message)
+
+	metadata := map[string]string{
+		metadataRetryable: strconv.FormatBool(e.IsRetryable()),
+	}
+	for key, value := range e.Details {
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(e.Code),
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+	if detailErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// ToGRPC converts err into a gRPC status error. Status errors produced by
+// handlers keep their code and message and gain ErrorInfo details.
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	return GRPCStatus(err).Err()
+}
+
+// fromGRPCStatus converts a gRPC status error back into an *Error.
+func fromGRPCStatus(err error) *Error {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return nil
+	}
+	st := grpcErr.GRPCStatus()
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	e := &Error{Code: CodeFromGRPC(st.Code()), Message: st.Message(), Cause: err}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+		e.Code = Code(info.GetReason())
+
+		keys := make([]string, 0, len(info.GetMetadata()))
+		for key := range info.GetMetadata() {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := info.GetMetadata()[key]
+			if key == metadataRetryable {
+				if retryable, err := strconv.ParseBool(value); err == nil {
+					e.WithRetryable(retryable)
+				}
+				continue
+			}
+			e.WithDetail(key, value)
+		}
+	}
+	return e
+}
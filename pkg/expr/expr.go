@@ -0,0 +1,438 @@
+// Package expr evaluates the expressions workflows use for task conditions,
+// retry decisions and configuration: a small, side-effect free language over
+// JSON values. It has the usual arithmetic, comparison and logical operators,
+// in, the conditional operator, member and index access, list and map
+// literals and a fixed library of functions, callable as f(x, y) or
+// x.f(y). Expressions cannot reach anything but the variables they are given,
+// and every evaluation is bounded in steps and in the size of the values it
+// builds.
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Evaluation limits.
+const (
+	// MaxSteps bounds the operations one evaluation performs.
+	MaxSteps = 10000
+	// MaxValueBytes bounds the strings and lists an evaluation builds.
+	MaxValueBytes = 64 << 10
+)
+
+// ErrBudgetExceeded is the error of an evaluation that exceeded MaxSteps.
+var ErrBudgetExceeded = errors.New("expression exceeded its evaluation budget")
+
+// Program is a compiled expression.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile compiles the expression src, which may refer to the variables
+// vars only.
+func Compile(src string, vars []string) (*Program, error) {
+	if len(src) > MaxExpressionBytes {
+		return nil, fmt.Errorf("expression exceeds %d bytes", MaxExpressionBytes)
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, vars: make(map[string]bool, len(vars))}
+	for _, v := range vars {
+		p.vars[v] = true
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected("want end of expression")
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source of the program.
+func (p *Program) String() string { return p.src }
+
+// Eval evaluates the program with vars, which hold JSON values: nil, bool,
+// float64, string, []any and map[string]any.
+func (p *Program) Eval(vars map[string]any) (any, error) {
+	e := &evaluator{vars: vars}
+	return e.eval(p.root)
+}
+
+// EvalBool evaluates the program, which must yield a bool.
+func (p *Program) EvalBool(vars map[string]any) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression yields %s, want bool", typeName(v))
+	}
+	return b, nil
+}
+
+type node interface{}
+
+type (
+	literalNode struct{ value any }
+	identNode   struct{ name string }
+	memberNode  struct {
+		x    node
+		name string
+	}
+	indexNode struct{ x, index node }
+	callNode  struct {
+		name string
+		fn   function
+		args []node
+	}
+	unaryNode struct {
+		op string
+		x  node
+	}
+	binaryNode struct {
+		op   string
+		x, y node
+	}
+	condNode struct{ cond, then, els node }
+	listNode struct{ items []node }
+	mapNode  struct{ keys, values []node }
+)
+
+// evaluator evaluates one program.
+type evaluator struct {
+	vars  map[string]any
+	steps int
+}
+
+func (e *evaluator) eval(n node) (any, error) {
+	if e.steps++; e.steps > MaxSteps {
+		return nil, ErrBudgetExceeded
+	}
+	switch n := n.(type) {
+	case *literalNode:
+		return n.value, nil
+	case *identNode:
+		return e.vars[n.name], nil
+	case *memberNode:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return nil, err
+		}
+		return member(x, n.name)
+	case *indexNode:
+		return e.evalIndex(n)
+	case *callNode:
+		args := make([]any, len(n.args))
+		for i, arg := range n.args {
+			v, err := e.eval(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		v, err := n.fn.call(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.name, err)
+		}
+		return v, checkSize(v)
+	case *unaryNode:
+		return e.evalUnary(n)
+	case *binaryNode:
+		return e.evalBinary(n)
+	case *condNode:
+		cond, err := e.evalBool(n.cond, "?")
+		if err != nil {
+			return nil, err
+		}
+		if cond {
+			return e.eval(n.then)
+		}
+		return e.eval(n.els)
+	case *listNode:
+		list := make([]any, len(n.items))
+		for i, item := range n.items {
+			v, err := e.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case *mapNode:
+		m := make(map[string]any, len(n.keys))
+		for i, k := range n.keys {
+			key, err := e.eval(k)
+			if err != nil {
+				return nil, err
+			}
+			s, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key is %s, want string", typeName(key))
+			}
+			if m[s], err = e.eval(n.values[i]); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unknown node %T", n)
+}
+
+func (e *evaluator) evalBool(n node, op string) (bool, error) {
+	v, err := e.eval(n)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s: got %s, want bool", op, typeName(v))
+	}
+	return b, nil
+}
+
+// member returns the field name of x. Fields of null and missing fields are
+// null, so optional data can be tested with == null.
+func member(x any, name string) (any, error) {
+	switch x := x.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return x[name], nil
+	}
+	return nil, fmt.Errorf("cannot get field %s of %s", name, typeName(x))
+}
+
+func (e *evaluator) evalIndex(n *indexNode) (any, error) {
+	x, err := e.eval(n.x)
+	if err != nil {
+		return nil, err
+	}
+	index, err := e.eval(n.index)
+	if err != nil {
+		return nil, err
+	}
+	switch x := x.(type) {
+	case []any:
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("list index is %s, want an integer", typeName(index))
+		}
+		if i < 0 || int(i) >= len(x) {
+			return nil, fmt.Errorf("list index %v out of range [0, %d)", i, len(x))
+		}
+		return x[int(i)], nil
+	case nil, map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key is %s, want string", typeName(index))
+		}
+		return member(x, key)
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(x))
+}
+
+func (e *evaluator) evalUnary(n *unaryNode) (any, error) {
+	if n.op == "!" {
+		b, err := e.evalBool(n.x, "!")
+		return !b, err
+	}
+	x, err := e.eval(n.x)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := x.(float64)
+	if !ok {
+		return nil, fmt.Errorf("-: got %s, want number", typeName(x))
+	}
+	return -f, nil
+}
+
+func (e *evaluator) evalBinary(n *binaryNode) (any, error) {
+	switch n.op {
+	case "&&", "||":
+		x, err := e.evalBool(n.x, n.op)
+		if err != nil || x == (n.op == "||") {
+			return x, err
+		}
+		return e.evalBool(n.y, n.op)
+	}
+	x, err := e.eval(n.x)
+	if err != nil {
+		return nil, err
+	}
+	y, err := e.eval(n.y)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(x, y), nil
+	case "!=":
+		return !equal(x, y), nil
+	case "in":
+		return in(x, y)
+	case "<", "<=", ">", ">=":
+		return compare(n.op, x, y)
+	case "+":
+		v, err := add(x, y)
+		if err != nil {
+			return nil, err
+		}
+		return v, checkSize(v)
+	}
+	a, aok := x.(float64)
+	b, bok := y.(float64)
+	if !aok || !bok {
+		return nil, fmt.Errorf("%s: got %s and %s, want numbers", n.op, typeName(x), typeName(y))
+	}
+	switch n.op {
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		if b == 0 {
+			return nil, fmt.Errorf("%s: division by zero", n.op)
+		}
+		if n.op == "/" {
+			return a / b, nil
+		}
+		return math.Mod(a, b), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func equal(x, y any) bool {
+	return reflect.DeepEqual(x, y)
+}
+
+func in(x, y any) (any, error) {
+	switch y := y.(type) {
+	case []any:
+		return slices.ContainsFunc(y, func(v any) bool { return equal(x, v) }), nil
+	case map[string]any:
+		key, ok := x.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := y[key]
+		return found, nil
+	case nil:
+		return false, nil
+	}
+	return nil, fmt.Errorf("in: got %s, want list or map", typeName(y))
+}
+
+func compare(op string, x, y any) (any, error) {
+	var c int
+	switch x := x.(type) {
+	case float64:
+		b, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot compare number and %s", op, typeName(y))
+		}
+		c = cmpOrdered(x, b)
+	case string:
+		b, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot compare string and %s", op, typeName(y))
+		}
+		c = strings.Compare(x, b)
+	default:
+		return nil, fmt.Errorf("%s: cannot compare %s", op, typeName(x))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func cmpOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func add(x, y any) (any, error) {
+	switch a := x.(type) {
+	case float64:
+		if b, ok := y.(float64); ok {
+			return a + b, nil
+		}
+	case string:
+		if b, ok := y.(string); ok {
+			return a + b, nil
+		}
+	case []any:
+		if b, ok := y.([]any); ok {
+			return append(slices.Clip(a), b...), nil
+		}
+	}
+	return nil, fmt.Errorf("+: cannot add %s and %s", typeName(x), typeName(y))
+}
+
+// checkSize returns an error when v is a string or list over MaxValueBytes.
+func checkSize(v any) error {
+	switch v := v.(type) {
+	case string:
+		if len(v) > MaxValueBytes {
+			return fmt.Errorf("string exceeds %d bytes", MaxValueBytes)
+		}
+	case []any:
+		if len(v) > MaxValueBytes {
+			return fmt.Errorf("list exceeds %d items", MaxValueBytes)
+		}
+	}
+	return nil
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// format returns v as text: strings as they are, other values as JSON.
+func format(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
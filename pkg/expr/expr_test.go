@@ -0,0 +1,180 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testVars = map[string]any{
+	"workflow": map[string]any{
+		"name":     "etl",
+		"metadata": map[string]any{"team": "data", "region": "eu-west-1"},
+	},
+	"tasks": map[string]any{
+		"extract": map[string]any{"status": "completed", "result": map[string]any{"rows": float64(120), "files": []any{"a.csv", "b.csv"}}},
+	},
+	"error": "HTTP 503: service unavailable",
+}
+
+func TestProgram_Eval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`1 + 2 * 3 - 4 / 2`, float64(5)},
+		{`(1 + 2) * 3 % 4`, float64(1)},
+		{`-tasks.extract.result.rows`, float64(-120)},
+		{`tasks.extract.result.rows > 100 && workflow.metadata.team == "data"`, true},
+		{`tasks.extract.status != 'completed' || false`, false},
+		{`!(1 < 2)`, false},
+		{`"a" + 'b' <= "ab"`, true},
+		{`tasks.extract.result.files[1]`, "b.csv"},
+		{`workflow["metadata"].region`, "eu-west-1"},
+		{`tasks.missing.result.rows`, nil},
+		{`"b.csv" in tasks.extract.result.files && "team" in workflow.metadata`, true},
+		{`size(tasks.extract.result.files) == 2 ? "two" : "other"`, "two"},
+		{`error.startsWith("HTTP 5") && matches(error, "^HTTP 5[0-9]{2}")`, true},
+		{`contains(lower(error), "unavailable")`, true},
+		{`split("a,b,c", ",").join("-")`, "a-b-c"},
+		{`int("42.9") + double(true) + abs(-1) + min(3, 1, 2) + max(1, 5)`, float64(50)},
+		{`string(tasks.extract.result.files)`, `["a.csv","b.csv"]`},
+		{`default(tasks.missing, "fallback")`, "fallback"},
+		{`keys(workflow.metadata)`, []any{"region", "team"}},
+		{`[1, "x", null] + [true]`, []any{float64(1), "x", nil, true}},
+		{`{"rows": tasks.extract.result.rows, "ok": true}.rows`, float64(120)},
+		{`upper(trim("  eu  ")) + "-" + string(size("héllo"))`, "EU-5"},
+		{`2e3 + 0.5`, float64(2000.5)},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.src, []string{"workflow", "tasks", "error"})
+		if err != nil {
+			t.Errorf("Compile(%s) error = %v", tt.src, err)
+			continue
+		}
+		got, err := p.Eval(testVars)
+		if err != nil {
+			t.Errorf("Eval(%s) error = %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Eval(%s) = %#v, want %#v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestProgram_ShortCircuits(t *testing.T) {
+	p, err := Compile(`false && 1 / 0 == 1 || true`, nil)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if ok, err := p.EvalBool(nil); err != nil || !ok {
+		t.Fatalf("EvalBool() = %v, %v, want true", ok, err)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for src, want := range map[string]string{
+		`secrets.token`:                  "unknown variable secrets",
+		`exec("rm -rf /")`:               "unknown function exec",
+		`size(1, 2)`:                     "size at 0: got 2 arguments, want 1",
+		`min()`:                          "want at least 1",
+		`1 +`:                            "unexpected end of expression",
+		`(1`:                             "want )",
+		`"open`:                          "unterminated string",
+		`1 # 2`:                          `unexpected '#'`,
+		`workflow.`:                      "want a field name",
+		`1 2`:                            "want end of expression",
+		strings.Repeat("1+", 3000) + "1": "exceeds 4096 bytes",
+	} {
+		if _, err := Compile(src, []string{"workflow"}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile(%.40s) error = %v, want %q", src, err, want)
+		}
+	}
+}
+
+func TestProgram_EvalErrors(t *testing.T) {
+	for src, want := range map[string]string{
+		`1 / 0`:                         "division by zero",
+		`"a" < 1`:                       "cannot compare string and number",
+		`1 + "a"`:                       "cannot add number and string",
+		`!1`:                            "!: got number, want bool",
+		`tasks.extract.result.files[2]`: "out of range",
+		`tasks.extract.status.code`:     "cannot get field code of string",
+		`matches("a", "(")`:             "matches:",
+		`int("many")`:                   `invalid number "many"`,
+		`1 ? 2 : 3`:                     "?: got number, want bool",
+		grow(grow(grow("error"))):       "string exceeds",
+	} {
+		p, err := Compile(src, []string{"tasks", "error"})
+		if err != nil {
+			t.Errorf("Compile(%s) error = %v", src, err)
+			continue
+		}
+		if _, err := p.Eval(testVars); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Eval(%s) error = %v, want %q", src, err, want)
+		}
+	}
+
+	p, err := Compile(`1`, nil)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if _, err := p.EvalBool(nil); err == nil || !strings.Contains(err.Error(), "want bool") {
+		t.Errorf("EvalBool(1) error = %v, want a type error", err)
+	}
+}
+
+// grow returns an expression putting error between every rune of x.
+func grow(x string) string {
+	return `join(split(` + x + `, ""), error)`
+}
+
+func TestProgram_Budget(t *testing.T) {
+	// Source size bounds compiled programs well under MaxSteps, so build
+	// an oversized one directly.
+	items := make([]node, MaxSteps)
+	for i := range items {
+		items[i] = &literalNode{value: float64(i)}
+	}
+	p := &Program{root: &listNode{items: items}}
+	if _, err := p.Eval(nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Eval() error = %v, want ErrBudgetExceeded", err)
+	}
+	p.root = &listNode{items: items[:MaxSteps-1]}
+	if _, err := p.Eval(nil); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+}
+
+func TestTemplate_Eval(t *testing.T) {
+	vars := []string{"workflow", "tasks"}
+	for s, want := range map[string]any{
+		`${{ tasks.extract.result.rows }}`: float64(120),
+		`plain`:                            "plain",
+		`s3://bucket/${{ workflow.metadata.region }}/${{ tasks.extract.result.rows * 2 }}.csv`: "s3://bucket/eu-west-1/240.csv",
+		`files: ${{tasks.extract.result.files}}`:                                               `files: ["a.csv","b.csv"]`,
+	} {
+		tmpl, err := CompileTemplate(s, vars)
+		if err != nil {
+			t.Errorf("CompileTemplate(%s) error = %v", s, err)
+			continue
+		}
+		got, err := tmpl.Eval(testVars)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Eval(%s) = %#v, %v, want %#v", s, got, err, want)
+		}
+	}
+	if !IsTemplate("a ${{ b }}") || IsTemplate("a ${ b }") {
+		t.Errorf("IsTemplate() misdetects placeholders")
+	}
+	for s, want := range map[string]string{
+		`${{ tasks.x `:     "unterminated ${{",
+		`${{ secrets.x }}`: "unknown variable secrets",
+	} {
+		if _, err := CompileTemplate(s, vars); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CompileTemplate(%s) error = %v, want %q", s, err, want)
+		}
+	}
+}
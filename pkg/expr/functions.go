@@ -0,0 +1,247 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPatternBytes bounds the regular expressions matches compiles.
+const maxPatternBytes = 1 << 10
+
+// function is a function of the library.
+type function struct {
+	// minArgs and maxArgs bound the number of arguments; maxArgs -1 is
+	// unbounded.
+	minArgs, maxArgs int
+	call             func(args []any) (any, error)
+}
+
+func (f function) arity() string {
+	switch {
+	case f.minArgs == f.maxArgs:
+		return strconv.Itoa(f.minArgs)
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d", f.minArgs, f.maxArgs)
+}
+
+// functions is the function library. It is fixed: expressions cannot define
+// functions or reach anything outside their arguments.
+var functions = map[string]function{
+	"size":       {1, 1, size},
+	"contains":   {2, 2, stringFunc2(func(s, sub string) any { return strings.Contains(s, sub) })},
+	"startsWith": {2, 2, stringFunc2(func(s, prefix string) any { return strings.HasPrefix(s, prefix) })},
+	"endsWith":   {2, 2, stringFunc2(func(s, suffix string) any { return strings.HasSuffix(s, suffix) })},
+	"matches":    {2, 2, matches},
+	"lower":      {1, 1, stringFunc(strings.ToLower)},
+	"upper":      {1, 1, stringFunc(strings.ToUpper)},
+	"trim":       {1, 1, stringFunc(strings.TrimSpace)},
+	"split":      {2, 2, split},
+	"join":       {2, 2, join},
+	"int":        {1, 1, toInt},
+	"double":     {1, 1, toDouble},
+	"string":     {1, 1, func(args []any) (any, error) { return format(args[0]), nil }},
+	"keys":       {1, 1, keys},
+	"default":    {2, 2, defaultValue},
+	"min":        {1, -1, fold(math.Min)},
+	"max":        {1, -1, fold(math.Max)},
+	"abs":        {1, 1, abs},
+}
+
+func size(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	}
+	return nil, fmt.Errorf("got %s, want string, list or map", typeName(args[0]))
+}
+
+func stringArg(v any, i int) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %d is %s, want string", i+1, typeName(v))
+	}
+	return s, nil
+}
+
+func stringFunc(fn func(string) string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		s, err := stringArg(args[0], 0)
+		if err != nil {
+			return nil, err
+		}
+		return fn(s), nil
+	}
+}
+
+func stringFunc2(fn func(a, b string) any) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		a, err := stringArg(args[0], 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := stringArg(args[1], 1)
+		if err != nil {
+			return nil, err
+		}
+		return fn(a, b), nil
+	}
+}
+
+// matches reports whether a string matches a regular expression. Go
+// regular expressions run in linear time, so patterns cannot stall it.
+func matches(args []any) (any, error) {
+	s, err := stringArg(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := stringArg(args[1], 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(pattern) > maxPatternBytes {
+		return nil, fmt.Errorf("pattern exceeds %d bytes", maxPatternBytes)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(s), nil
+}
+
+func split(args []any) (any, error) {
+	s, err := stringArg(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	sep, err := stringArg(args[1], 1)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(s, sep, MaxValueBytes+1)
+	list := make([]any, len(parts))
+	for i, part := range parts {
+		list[i] = part
+	}
+	return list, nil
+}
+
+func join(args []any) (any, error) {
+	list, ok := args[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("argument 1 is %s, want list", typeName(args[0]))
+	}
+	sep, err := stringArg(args[1], 1)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	for i, v := range list {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(format(v))
+		if b.Len() > MaxValueBytes {
+			return nil, fmt.Errorf("string exceeds %d bytes", MaxValueBytes)
+		}
+	}
+	return b.String(), nil
+}
+
+func toInt(args []any) (any, error) {
+	v, err := toDouble(args)
+	if err != nil {
+		return nil, err
+	}
+	return math.Trunc(v.(float64)), nil
+}
+
+func toDouble(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+		return float64(0), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to a number", typeName(args[0]))
+}
+
+func keys(args []any) (any, error) {
+	m, ok := args[0].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("got %s, want map", typeName(args[0]))
+	}
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	list := make([]any, len(names))
+	for i, name := range names {
+		list[i] = name
+	}
+	return list, nil
+}
+
+// defaultValue returns the first argument unless it is null.
+func defaultValue(args []any) (any, error) {
+	if args[0] == nil {
+		return args[1], nil
+	}
+	return args[0], nil
+}
+
+// numberArgs returns args, which must all be numbers.
+func numberArgs(args []any) ([]float64, error) {
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		f, ok := arg.(float64)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is %s, want number", i+1, typeName(arg))
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+// fold returns a function folding its number arguments with fn.
+func fold(fn func(a, b float64) float64) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		values, err := numberArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		result := values[0]
+		for _, v := range values[1:] {
+			result = fn(result, v)
+		}
+		return result, nil
+	}
+}
+
+func abs(args []any) (any, error) {
+	values, err := numberArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return math.Abs(values[0]), nil
+}
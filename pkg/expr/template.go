@@ -0,0 +1,79 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template delimiters.
+const (
+	templateOpen  = "${{"
+	templateClose = "}}"
+)
+
+// Template is a string with embedded ${{ expression }} placeholders.
+type Template struct {
+	// text is the literal text around the programs, one element more than
+	// there are programs.
+	text     []string
+	programs []*Program
+}
+
+// IsTemplate reports whether s embeds an expression.
+func IsTemplate(s string) bool {
+	return strings.Contains(s, templateOpen)
+}
+
+// CompileTemplate compiles the expressions embedded in s, which may refer to
+// the variables vars only. Expressions cannot contain }}.
+func CompileTemplate(s string, vars []string) (*Template, error) {
+	t := &Template{}
+	for {
+		start := strings.Index(s, templateOpen)
+		if start < 0 {
+			t.text = append(t.text, s)
+			return t, nil
+		}
+		end := strings.Index(s[start:], templateClose)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated %s", templateOpen)
+		}
+		src := strings.TrimSpace(s[start+len(templateOpen) : start+end])
+		p, err := Compile(src, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s %s: %w", templateOpen, src, templateClose, err)
+		}
+		t.text = append(t.text, s[:start])
+		t.programs = append(t.programs, p)
+		s = s[start+end+len(templateClose):]
+	}
+}
+
+// Eval evaluates the template with vars. A template that is a single
+// placeholder yields its value as it is; otherwise the values are formatted
+// into the text, strings as they are and other values as JSON.
+func (t *Template) Eval(vars map[string]any) (any, error) {
+	if len(t.programs) == 1 && t.text[0] == "" && t.text[1] == "" {
+		v, err := t.programs[0].Eval(vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s %s: %w", templateOpen, t.programs[0], templateClose, err)
+		}
+		return v, nil
+	}
+	var b strings.Builder
+	for i, text := range t.text {
+		b.WriteString(text)
+		if i == len(t.programs) {
+			break
+		}
+		v, err := t.programs[i].Eval(vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s %s: %w", templateOpen, t.programs[i], templateClose, err)
+		}
+		b.WriteString(format(v))
+		if b.Len() > MaxValueBytes {
+			return nil, fmt.Errorf("string exceeds %d bytes", MaxValueBytes)
+		}
+	}
+	return b.String(), nil
+}
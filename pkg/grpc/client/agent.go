@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+)
+
+// AgentHandler runs a task assigned to an agent and returns its result. ctx is
+// cancelled when the server cancels the assignment; progress reports how far
+// the task has got.
+type AgentHandler func(ctx context.Context, task *pb.TaskAssignment, progress func(percent float64, message string)) ([]byte, error)
+
+// AgentOperations provides remote agent operations.
+type AgentOperations struct {
+	client *Client
+}
+
+// Agents returns agent operations.
+func (c *Client) Agents() *AgentOperations {
+	return &AgentOperations{client: c}
+}
+
+// List lists connected agents, optionally only those accepting agentType.
+func (a *AgentOperations) List(ctx context.Context, agentType string) (*pb.ListAgentsResponse, error) {
+	return withRetry(a.client, ctx, func(ctx context.Context) (*pb.ListAgentsResponse, error) {
+		return a.client.agentClient.ListAgents(ctx, &pb.ListAgentsRequest{AgentType: agentType})
+	})
+}
+
+// Serve registers as an agent and runs assigned tasks with handler, up to the
+// registered capacity at once, until ctx is done or the server ends the
+// channel. Heartbeats are sent at the interval the server asks for. Serve
+// returns nil when ctx is cancelled.
+func (a *AgentOperations) Serve(ctx context.Context, reg *pb.AgentRegistration, handler AgentHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := a.client.agentClient.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect agent: %w", err)
+	}
+	if err := stream.Send(&pb.AgentMessage{Payload: &pb.AgentMessage_Register{Register: reg}}); err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	registered := first.GetRegistered()
+	if registered == nil {
+		return fmt.Errorf("failed to register agent: unexpected command %T", first.Command)
+	}
+
+	w := &agentWorker{stream: stream, running: make(map[string]context.CancelFunc)}
+	defer w.wait()
+
+	interval := time.Duration(registered.HeartbeatIntervalSeconds) * time.Second
+	if interval > 0 {
+		go w.heartbeat(ctx, interval)
+	}
+
+	for {
+		cmd, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		switch c := cmd.Command.(type) {
+		case *pb.AgentCommand_Assign:
+			w.run(ctx, c.Assign, handler)
+		case *pb.AgentCommand_Cancel:
+			w.cancel(c.Cancel.AssignmentId)
+		case *pb.AgentCommand_Error:
+			return fmt.Errorf("agent channel closed by server: %s: %s", c.Error.Code, c.Error.Message)
+		}
+	}
+}
+
+// agentWorker runs the assignments of one agent channel
+type agentWorker struct {
+	stream pb.AgentService_ConnectClient
+
+	// sendMu serializes sends, which gRPC does not allow concurrently.
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func (w *agentWorker) send(msg *pb.AgentMessage) {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	_ = w.stream.Send(msg)
+}
+
+func (w *agentWorker) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			active := len(w.running)
+			w.mu.Unlock()
+			w.send(&pb.AgentMessage{Payload: &pb.AgentMessage_Heartbeat{Heartbeat: &pb.AgentHeartbeat{ActiveTasks: int32(active)}}})
+		}
+	}
+}
+
+func (w *agentWorker) run(ctx context.Context, task *pb.TaskAssignment, handler AgentHandler) {
+	var taskCtx context.Context
+	var cancel context.CancelFunc
+	if task.Deadline != nil {
+		taskCtx, cancel = context.WithDeadline(ctx, task.Deadline.AsTime())
+	} else {
+		taskCtx, cancel = context.WithCancel(ctx)
+	}
+	w.mu.Lock()
+	w.running[task.AssignmentId] = cancel
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.cancel(task.AssignmentId)
+
+		progress := func(percent float64, message string) {
+			w.send(&pb.AgentMessage{Payload: &pb.AgentMessage_Progress{Progress: &pb.TaskProgress{
+				AssignmentId: task.AssignmentId,
+				Percent:      percent,
+				Message:      message,
+			}}})
+		}
+		data, err := handler(taskCtx, task, progress)
+
+		completion := &pb.TaskCompletion{AssignmentId: task.AssignmentId, Success: err == nil, ResultData: data}
+		if err != nil {
+			completion.ErrorMessage = err.Error()
+		}
+		w.send(&pb.AgentMessage{Payload: &pb.AgentMessage_Completion{Completion: completion}})
+	}()
+}
+
+func (w *agentWorker) cancel(assignmentID string) {
+	w.mu.Lock()
+	cancel, ok := w.running[assignmentID]
+	delete(w.running, assignmentID)
+	w.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (w *agentWorker) wait() {
+	w.mu.Lock()
+	for id, cancel := range w.running {
+		cancel()
+		delete(w.running, id)
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+}
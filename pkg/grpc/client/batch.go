@@ -23,10 +23,12 @@ func (b *BatchOperations) SubmitWorkflows(ctx context.Context, req *pb.SubmitWor
 	})
 }
 
-// GetWorkflowStatuses retrieves statuses for multiple workflows
-func (b *BatchOperations) GetWorkflowStatuses(ctx context.Context, workflowIDs []string) (*pb.GetWorkflowStatusesResponse, error) {
+// GetWorkflowStatuses retrieves statuses for multiple workflows. When fields
+// are given, only those fields of each status are populated.
+func (b *BatchOperations) GetWorkflowStatuses(ctx context.Context, workflowIDs []string, fields ...string) (*pb.GetWorkflowStatusesResponse, error) {
 	req := &pb.GetWorkflowStatusesRequest{
 		WorkflowIds: workflowIDs,
+		FieldMask:   fieldMask(fields),
 	}
 
 	return withRetry(b.client, ctx, func(ctx context.Context) (*pb.GetWorkflowStatusesResponse, error) {
@@ -79,8 +81,9 @@ func (b *BatchOperations) SubmitWorkflowsOrdered(ctx context.Context, workflows
 	return b.SubmitWorkflows(ctx, req)
 }
 
-// GetAllWorkflowStatuses retrieves all workflow statuses across pages
-func (b *BatchOperations) GetAllWorkflowStatuses(ctx context.Context, workflowIDs []string) ([]*pb.WorkflowStatusResult, error) {
+// GetAllWorkflowStatuses retrieves all workflow statuses across pages,
+// limited to fields when any are given
+func (b *BatchOperations) GetAllWorkflowStatuses(ctx context.Context, workflowIDs []string, fields ...string) ([]*pb.WorkflowStatusResult, error) {
 	var allResults []*pb.WorkflowStatusResult
 	pageToken := ""
 
@@ -91,6 +94,7 @@ func (b *BatchOperations) GetAllWorkflowStatuses(ctx context.Context, workflowID
 				PageSize:  100,
 				PageToken: pageToken,
 			},
+			FieldMask: fieldMask(fields),
 		}
 
 		resp, err := b.client.batchClient.GetWorkflowStatuses(ctx, req)
@@ -23,8 +23,10 @@ type Client struct {
 	workflowClient  pb.WorkflowServiceClient
 	streamingClient pb.StreamingServiceClient
 	batchClient     pb.BatchServiceClient
+	sagaBatchClient pb.SagaBatchServiceClient
 	adminClient     pb.AdminServiceClient
 	signalClient    pb.SignalServiceClient
+	agentClient     pb.AgentServiceClient
 	healthClient    grpc_health_v1.HealthClient
 	opts            *Options
 	retryPolicy     *RetryPolicy
@@ -42,6 +44,10 @@ type Options struct {
 	CAFile     string
 	ServerName string
 
+	// Token is sent as a bearer token with every call, for servers with
+	// authentication enabled
+	Token string
+
 	// Connection options
 	MaxRecvMsgSize int
 	MaxSendMsgSize int
@@ -141,6 +147,11 @@ func NewClient(opts *Options) (*Client, error) {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	// Add bearer token
+	if opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials(opts.Token)))
+	}
+
 	// Add custom dial options
 	dialOpts = append(dialOpts, opts.DialOptions...)
 
@@ -169,8 +180,10 @@ func NewClient(opts *Options) (*Client, error) {
 		workflowClient:  pb.NewWorkflowServiceClient(conn),
 		streamingClient: pb.NewStreamingServiceClient(conn),
 		batchClient:     pb.NewBatchServiceClient(conn),
+		sagaBatchClient: pb.NewSagaBatchServiceClient(conn),
 		adminClient:     pb.NewAdminServiceClient(conn),
 		signalClient:    pb.NewSignalServiceClient(conn),
+		agentClient:     pb.NewAgentServiceClient(conn),
 		healthClient:    grpc_health_v1.NewHealthClient(conn),
 		opts:            opts,
 		retryPolicy:     opts.RetryPolicy,
@@ -179,6 +192,20 @@ func NewClient(opts *Options) (*Client, error) {
 	return client, nil
 }
 
+// tokenCredentials attaches a bearer token to every call
+type tokenCredentials string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Tokens
+// are allowed over plaintext so that local deployments without TLS work.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
 // loadTLSCredentials loads TLS credentials from files
 func loadTLSCredentials(opts *Options) (credentials.TransportCredentials, error) {
 	// Load CA certificate
@@ -274,6 +301,11 @@ func (c *Client) BatchClient() pb.BatchServiceClient {
 	return c.batchClient
 }
 
+// SagaBatchClient returns the saga batch service client
+func (c *Client) SagaBatchClient() pb.SagaBatchServiceClient {
+	return c.sagaBatchClient
+}
+
 // AdminClient returns the admin service client
 func (c *Client) AdminClient() pb.AdminServiceClient {
 	return c.adminClient
@@ -283,3 +315,8 @@ func (c *Client) AdminClient() pb.AdminServiceClient {
 func (c *Client) SignalClient() pb.SignalServiceClient {
 	return c.signalClient
 }
+
+// AgentClient returns the agent service client.
+func (c *Client) AgentClient() pb.AgentServiceClient {
+	return c.agentClient
+}
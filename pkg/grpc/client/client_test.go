@@ -370,6 +370,15 @@ func TestKeepAliveOptions(t *testing.T) {
 	assert.True(t, opts.PermitWithoutStream)
 }
 
+func TestTokenCredentials(t *testing.T) {
+	creds := tokenCredentials("secret")
+
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", md["authorization"])
+	assert.False(t, creds.RequireTransportSecurity())
+}
+
 func TestRetryPolicyBackoff(t *testing.T) {
 	policy := &RetryPolicy{
 		InitialBackoff:    100 * time.Millisecond,
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/errs"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -74,6 +75,10 @@ func (c *Client) isRetryableError(err error) bool {
 		}
 	}
 
+	// Fall back to the retriability hint attached by goclaw servers
+	if hint := errs.From(err).Retryable; hint != nil {
+		return *hint
+	}
 	return false
 }
 
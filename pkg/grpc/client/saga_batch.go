@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+)
+
+// SagaBatchOperations provides high-level batch saga operations
+type SagaBatchOperations struct {
+	client *Client
+}
+
+// SagaBatch returns batch saga operations
+func (c *Client) SagaBatch() *SagaBatchOperations {
+	return &SagaBatchOperations{client: c}
+}
+
+// SubmitSagas submits multiple sagas. Set an idempotency key so that retried
+// calls do not submit the sagas twice.
+func (b *SagaBatchOperations) SubmitSagas(ctx context.Context, req *pb.SubmitSagasRequest) (*pb.SubmitSagasResponse, error) {
+	return withRetry(b.client, ctx, func(ctx context.Context) (*pb.SubmitSagasResponse, error) {
+		return b.client.sagaBatchClient.SubmitSagas(ctx, req)
+	})
+}
+
+// GetSagaStatuses retrieves statuses for the sagas filter selects
+func (b *SagaBatchOperations) GetSagaStatuses(ctx context.Context, filter *pb.SagaFilter, pagination *pb.PaginationRequest) (*pb.GetSagaStatusesResponse, error) {
+	req := &pb.GetSagaStatusesRequest{
+		Filter:     filter,
+		Pagination: pagination,
+	}
+
+	return withRetry(b.client, ctx, func(ctx context.Context) (*pb.GetSagaStatusesResponse, error) {
+		return b.client.sagaBatchClient.GetSagaStatuses(ctx, req)
+	})
+}
+
+// CompensateSagas triggers compensation of the sagas filter selects
+func (b *SagaBatchOperations) CompensateSagas(ctx context.Context, req *pb.CompensateSagasRequest) (*pb.CompensateSagasResponse, error) {
+	return withRetry(b.client, ctx, func(ctx context.Context) (*pb.CompensateSagasResponse, error) {
+		return b.client.sagaBatchClient.CompensateSagas(ctx, req)
+	})
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 )
@@ -22,3 +24,45 @@ func (s *SignalOperations) SignalTask(ctx context.Context, req *pb.SignalTaskReq
 		return s.client.signalClient.SignalTask(ctx, req)
 	})
 }
+
+// AckSignal acknowledges a signal delivered with at-least-once delivery.
+func (s *SignalOperations) AckSignal(ctx context.Context, taskID, deliveryID string) (*pb.AckSignalResponse, error) {
+	return withRetry(s.client, ctx, func(ctx context.Context) (*pb.AckSignalResponse, error) {
+		return s.client.signalClient.AckSignal(ctx, &pb.AckSignalRequest{TaskId: taskID, DeliveryId: deliveryID})
+	})
+}
+
+// ListPendingSignals lists the unacknowledged signals of a task.
+func (s *SignalOperations) ListPendingSignals(ctx context.Context, taskID string) (*pb.ListPendingSignalsResponse, error) {
+	return withRetry(s.client, ctx, func(ctx context.Context) (*pb.ListPendingSignalsResponse, error) {
+		return s.client.signalClient.ListPendingSignals(ctx, &pb.ListPendingSignalsRequest{TaskId: taskID})
+	})
+}
+
+// GetDeliveryStats returns the signal bus delivery counters.
+func (s *SignalOperations) GetDeliveryStats(ctx context.Context) (*pb.GetDeliveryStatsResponse, error) {
+	return withRetry(s.client, ctx, func(ctx context.Context) (*pb.GetDeliveryStatsResponse, error) {
+		return s.client.signalClient.GetDeliveryStats(ctx, &pb.GetDeliveryStatsRequest{})
+	})
+}
+
+// ReplaySignals returns the recorded history of a channel, oldest first.
+func (s *SignalOperations) ReplaySignals(ctx context.Context, req *pb.ReplaySignalsRequest) ([]*pb.SignalRecord, error) {
+	return withRetry(s.client, ctx, func(ctx context.Context) ([]*pb.SignalRecord, error) {
+		stream, err := s.client.signalClient.ReplaySignals(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var records []*pb.SignalRecord
+		for {
+			record, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	})
+}
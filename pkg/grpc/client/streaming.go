@@ -91,6 +91,11 @@ func (s *StreamingOperations) WatchWorkflowWithHandler(ctx context.Context, work
 			return fmt.Errorf("stream error: %w", err)
 		}
 
+		// The server ends streams with an error update, e.g. on shutdown
+		if update.Error != nil {
+			return fmt.Errorf("stream error: %s", update.Error.Message)
+		}
+
 		if err := handler(update); err != nil {
 			return fmt.Errorf("handler error: %w", err)
 		}
@@ -115,6 +120,11 @@ func (s *StreamingOperations) WatchTasksWithHandler(ctx context.Context, workflo
 			return fmt.Errorf("stream error: %w", err)
 		}
 
+		// The server ends streams with an error update, e.g. on shutdown
+		if update.Error != nil {
+			return fmt.Errorf("stream error: %s", update.Error.Message)
+		}
+
 		if err := handler(update); err != nil {
 			return fmt.Errorf("handler error: %w", err)
 		}
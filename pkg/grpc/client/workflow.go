@@ -3,8 +3,10 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // WorkflowOperations provides high-level workflow operations
@@ -24,10 +26,12 @@ func (w *WorkflowOperations) Submit(ctx context.Context, req *pb.SubmitWorkflowR
 	})
 }
 
-// Get retrieves workflow status
-func (w *WorkflowOperations) Get(ctx context.Context, workflowID string) (*pb.GetWorkflowStatusResponse, error) {
+// Get retrieves workflow status. When fields are given (e.g. "status"), only
+// those fields of the response are populated.
+func (w *WorkflowOperations) Get(ctx context.Context, workflowID string, fields ...string) (*pb.GetWorkflowStatusResponse, error) {
 	req := &pb.GetWorkflowStatusRequest{
 		WorkflowId: workflowID,
+		FieldMask:  fieldMask(fields),
 	}
 
 	return withRetry(w.client, ctx, func(ctx context.Context) (*pb.GetWorkflowStatusResponse, error) {
@@ -97,6 +101,33 @@ func (w *WorkflowOperations) ListAll(ctx context.Context, statusFilter pb.Workfl
 	return allWorkflows, nil
 }
 
+// Each calls fn for every workflow matching statusFilter, read from a
+// single server stream instead of pages. An error from fn stops the stream.
+func (w *WorkflowOperations) Each(ctx context.Context, statusFilter pb.WorkflowStatus, fn func(*pb.WorkflowSummary) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := w.client.workflowClient.ListWorkflowsStream(ctx, &pb.ListWorkflowsStreamRequest{
+		StatusFilter: statusFilter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream workflows: %w", err)
+	}
+
+	for {
+		summary, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("stream error: %w", err)
+		}
+		if err := fn(summary); err != nil {
+			return err
+		}
+	}
+}
+
 // WaitForCompletion waits for a workflow to complete
 func (w *WorkflowOperations) WaitForCompletion(ctx context.Context, workflowID string) (*pb.GetWorkflowStatusResponse, error) {
 	stream, err := w.client.streamingClient.WatchWorkflow(ctx, &pb.WatchWorkflowRequest{
@@ -119,6 +150,14 @@ func (w *WorkflowOperations) WaitForCompletion(ctx context.Context, workflowID s
 	}
 }
 
+// fieldMask returns a mask selecting fields, or nil to select all of them
+func fieldMask(fields []string) *fieldmaskpb.FieldMask {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fieldmaskpb.FieldMask{Paths: fields}
+}
+
 // isTerminalWorkflowStatus checks if status is terminal
 func isTerminalWorkflowStatus(status pb.WorkflowStatus) bool {
 	return status == pb.WorkflowStatus_WORKFLOW_STATUS_COMPLETED ||
@@ -2,7 +2,12 @@ package grpc
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/identity"
+	"github.com/goclaw/goclaw/pkg/logger"
 )
 
 // Config holds gRPC server configuration
@@ -33,6 +38,61 @@ type Config struct {
 
 	// EnableHealthCheck enables gRPC health check service
 	EnableHealthCheck bool
+
+	// EnableLogging enables request logging interceptors
+	EnableLogging bool
+
+	// Logger receives request logs and recovered panics (defaults to the
+	// global logger)
+	Logger logger.Logger
+
+	// Auth configures token authentication (nil disables it)
+	Auth *AuthConfig
+
+	// RateLimit configures per-client rate limiting (nil disables it)
+	RateLimit *RateLimitConfig
+
+	// RoleMappings grant roles to mTLS clients by certificate SAN. When set
+	// and TLS requires client certificates, admin methods require a client
+	// mapped to the admin role.
+	RoleMappings []identity.RoleMapping
+}
+
+// AuthConfig holds token authentication configuration
+type AuthConfig struct {
+	// Enabled indicates whether calls must carry a valid token
+	Enabled bool
+
+	// Tokens are the accepted tokens, sent in the "authorization" metadata
+	// key with or without a "Bearer " prefix
+	Tokens []string
+}
+
+// RateLimitConfig holds rate limiting configuration
+type RateLimitConfig struct {
+	// Enabled indicates whether rate limiting is enabled
+	Enabled bool
+
+	// RequestsPerSecond is the default per-client call rate
+	RequestsPerSecond float64
+
+	// Burst is the default per-client burst size
+	Burst int
+
+	// Methods overrides the default limit for individual methods
+	Methods []MethodRateLimit
+}
+
+// MethodRateLimit is the rate limit of a single method
+type MethodRateLimit struct {
+	// Method is the full method name, e.g. "/goclaw.v1.WorkflowService/SubmitWorkflow"
+	Method string
+
+	// RequestsPerSecond is the per-client call rate of the method
+	RequestsPerSecond float64
+
+	// Burst is the per-client burst size of the method
+	Burst int
 }
 
 // TLSConfig holds TLS/mTLS configuration
@@ -87,6 +147,7 @@ func DefaultConfig() *Config {
 		MaxSendMsgSize:    4 * 1024 * 1024, // 4MB
 		EnableReflection:  false,
 		EnableHealthCheck: true,
+		EnableLogging:     true,
 		Keepalive: &KeepaliveConfig{
 			MaxIdleSeconds:      300,  // 5 minutes
 			MaxAgeSeconds:       3600, // 1 hour
@@ -99,6 +160,67 @@ func DefaultConfig() *Config {
 	}
 }
 
+// FromConfig converts the gRPC settings of the application configuration to
+// a server Config.
+func FromConfig(g *config.GRPCConfig) *Config {
+	cfg := &Config{
+		Address:           fmt.Sprintf(":%d", g.Port),
+		EnableTracing:     g.EnableTracing,
+		MaxConnections:    g.MaxConnections,
+		MaxRecvMsgSize:    g.MaxRecvMsgSize,
+		MaxSendMsgSize:    g.MaxSendMsgSize,
+		EnableReflection:  g.EnableReflection,
+		EnableHealthCheck: g.EnableHealthCheck,
+		EnableLogging:     g.Interceptors.Logging.Enabled,
+	}
+
+	// Convert TLS config
+	if g.TLS.Enabled {
+		cfg.TLS = &TLSConfig{
+			Enabled:    g.TLS.Enabled,
+			CertFile:   g.TLS.CertFile,
+			KeyFile:    g.TLS.KeyFile,
+			CAFile:     g.TLS.CAFile,
+			ClientAuth: g.TLS.ClientAuth,
+		}
+	}
+
+	// Convert Keepalive config
+	cfg.Keepalive = &KeepaliveConfig{
+		MaxIdleSeconds:      g.Keepalive.MaxIdleSeconds,
+		MaxAgeSeconds:       g.Keepalive.MaxAgeSeconds,
+		MaxAgeGraceSeconds:  g.Keepalive.MaxAgeGraceSeconds,
+		TimeSeconds:         g.Keepalive.TimeSeconds,
+		TimeoutSeconds:      g.Keepalive.TimeoutSeconds,
+		MinTimeSeconds:      g.Keepalive.MinTimeSeconds,
+		PermitWithoutStream: g.Keepalive.PermitWithoutStream,
+	}
+
+	// Convert interceptor config
+	if auth := g.Interceptors.Auth; auth.Enabled {
+		cfg.Auth = &AuthConfig{
+			Enabled: true,
+			Tokens:  append([]string(nil), auth.Tokens...),
+		}
+	}
+	if rl := g.Interceptors.RateLimit; rl.Enabled {
+		cfg.RateLimit = &RateLimitConfig{
+			Enabled:           true,
+			RequestsPerSecond: rl.RequestsPerSecond,
+			Burst:             rl.Burst,
+		}
+		for _, m := range rl.Methods {
+			cfg.RateLimit.Methods = append(cfg.RateLimit.Methods, MethodRateLimit{
+				Method:            m.Method,
+				RequestsPerSecond: m.RequestsPerSecond,
+				Burst:             m.Burst,
+			})
+		}
+	}
+
+	return cfg
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Address == "" {
@@ -129,6 +251,77 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Auth != nil && c.Auth.Enabled {
+		if err := c.Auth.Validate(); err != nil {
+			return fmt.Errorf("invalid auth config: %w", err)
+		}
+	}
+
+	if c.RateLimit != nil && c.RateLimit.Enabled {
+		if err := c.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("invalid rate limit config: %w", err)
+		}
+	}
+
+	if len(c.RoleMappings) > 0 {
+		if _, err := identity.NewMapper(c.RoleMappings); err != nil {
+			return fmt.Errorf("invalid role mappings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate validates authentication configuration
+func (a *AuthConfig) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if len(a.Tokens) == 0 {
+		return fmt.Errorf("at least one token is required when auth is enabled")
+	}
+
+	for i, token := range a.Tokens {
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("token %d is empty", i)
+		}
+	}
+
+	return nil
+}
+
+// Validate validates rate limiting configuration
+func (r *RateLimitConfig) Validate() error {
+	if !r.Enabled {
+		return nil
+	}
+
+	if r.RequestsPerSecond <= 0 {
+		return fmt.Errorf("requests per second must be positive")
+	}
+
+	if r.Burst <= 0 {
+		return fmt.Errorf("burst must be positive")
+	}
+
+	seen := make(map[string]bool, len(r.Methods))
+	for _, m := range r.Methods {
+		if !strings.HasPrefix(m.Method, "/") || strings.Count(m.Method, "/") != 2 {
+			return fmt.Errorf("method %q must be a full method name like /package.Service/Method", m.Method)
+		}
+		if seen[m.Method] {
+			return fmt.Errorf("duplicate limit for method %s", m.Method)
+		}
+		seen[m.Method] = true
+		if m.RequestsPerSecond <= 0 {
+			return fmt.Errorf("requests per second of %s must be positive", m.Method)
+		}
+		if m.Burst <= 0 {
+			return fmt.Errorf("burst of %s must be positive", m.Method)
+		}
+	}
+
 	return nil
 }
 
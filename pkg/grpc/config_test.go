@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	grpcCfg := FromConfig(&cfg.Server.GRPC)
+
+	if grpcCfg == nil {
+		t.Fatal("expected non-nil grpc config")
+	}
+
+	// Check address format
+	if grpcCfg.Address != ":9090" {
+		t.Errorf("expected ':9090', got '%s'", grpcCfg.Address)
+	}
+
+	// Check default values
+	if !grpcCfg.EnableTracing {
+		t.Error("expected gRPC tracing to be enabled by default")
+	}
+	if grpcCfg.MaxConnections != 1000 {
+		t.Errorf("expected 1000, got %d", grpcCfg.MaxConnections)
+	}
+	if grpcCfg.MaxRecvMsgSize != 4*1024*1024 {
+		t.Errorf("expected %d, got %d", 4*1024*1024, grpcCfg.MaxRecvMsgSize)
+	}
+	if grpcCfg.Keepalive == nil || grpcCfg.Keepalive.MaxAgeSeconds != 3600 || grpcCfg.Keepalive.MaxAgeGraceSeconds != 60 {
+		t.Errorf("expected keepalive max age 3600s with 60s grace, got %+v", grpcCfg.Keepalive)
+	}
+}
+
+func TestFromConfig_WithTLS(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Server.GRPC.TLS = config.GRPCTLSConfig{
+		Enabled:    true,
+		CertFile:   "/path/to/cert.pem",
+		KeyFile:    "/path/to/key.pem",
+		CAFile:     "/path/to/ca.pem",
+		ClientAuth: true,
+	}
+
+	grpcCfg := FromConfig(&cfg.Server.GRPC)
+
+	if grpcCfg.TLS == nil {
+		t.Fatal("expected non-nil TLS config")
+	}
+	if !grpcCfg.TLS.Enabled {
+		t.Error("expected TLS to be enabled")
+	}
+	if grpcCfg.TLS.CertFile != "/path/to/cert.pem" {
+		t.Errorf("expected '/path/to/cert.pem', got '%s'", grpcCfg.TLS.CertFile)
+	}
+}
+
+func TestFromConfig_TracingToggle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Server.GRPC.EnableTracing = false
+
+	grpcCfg := FromConfig(&cfg.Server.GRPC)
+	if grpcCfg.EnableTracing {
+		t.Fatal("expected gRPC tracing to be disabled")
+	}
+}
+
+func TestFromConfig_Interceptors(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if grpcCfg := FromConfig(&cfg.Server.GRPC); grpcCfg.Auth != nil || grpcCfg.RateLimit != nil || !grpcCfg.EnableLogging {
+		t.Fatalf("expected only logging enabled by default, got auth=%v rate_limit=%v logging=%v",
+			grpcCfg.Auth, grpcCfg.RateLimit, grpcCfg.EnableLogging)
+	}
+
+	cfg.Server.GRPC.Interceptors.Auth = config.GRPCAuthConfig{Enabled: true, Tokens: []string{"secret"}}
+	cfg.Server.GRPC.Interceptors.RateLimit.Enabled = true
+	cfg.Server.GRPC.Interceptors.RateLimit.Methods = []config.GRPCMethodRateLimitConfig{
+		{Method: "/goclaw.v1.WorkflowService/SubmitWorkflow", RequestsPerSecond: 5, Burst: 10},
+	}
+
+	grpcCfg := FromConfig(&cfg.Server.GRPC)
+	if grpcCfg.Auth == nil || len(grpcCfg.Auth.Tokens) != 1 {
+		t.Fatalf("expected auth with one token, got %+v", grpcCfg.Auth)
+	}
+	if grpcCfg.RateLimit == nil || grpcCfg.RateLimit.RequestsPerSecond != 100 || len(grpcCfg.RateLimit.Methods) != 1 {
+		t.Fatalf("expected rate limit with one method override, got %+v", grpcCfg.RateLimit)
+	}
+	if err := grpcCfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
@@ -117,11 +117,9 @@ func (s *AdminServiceServer) UpdateConfig(ctx context.Context, req *pb.UpdateCon
 	applied, err := s.engine.UpdateConfig(ctx, req.ConfigUpdates, req.Persist)
 	if err != nil {
 		return &pb.UpdateConfigResponse{
-			Success: false,
-			Error: &pb.Error{
-				Code:    "CONFIG_UPDATE_FAILED",
-				Message: err.Error(),
-			},
+			Success:        false,
+			AppliedChanges: applied,
+			Error:          newProtoError("CONFIG_UPDATE_FAILED", err),
 		}, nil
 	}
 
@@ -146,10 +144,7 @@ func (s *AdminServiceServer) ManageCluster(ctx context.Context, req *pb.ManageCl
 		if err != nil {
 			return &pb.ManageClusterResponse{
 				Success: false,
-				Error: &pb.Error{
-					Code:    "LIST_NODES_FAILED",
-					Message: err.Error(),
-				},
+				Error:   newProtoError("LIST_NODES_FAILED", err),
 			}, nil
 		}
 
@@ -161,10 +156,7 @@ func (s *AdminServiceServer) ManageCluster(ctx context.Context, req *pb.ManageCl
 		if err != nil {
 			return &pb.ManageClusterResponse{
 				Success: false,
-				Error: &pb.Error{
-					Code:    "ADD_NODE_FAILED",
-					Message: err.Error(),
-				},
+				Error:   newProtoError("ADD_NODE_FAILED", err),
 			}, nil
 		}
 		nodes, _ = s.engine.ListClusterNodes(ctx)
@@ -180,10 +172,7 @@ func (s *AdminServiceServer) ManageCluster(ctx context.Context, req *pb.ManageCl
 		if err != nil {
 			return &pb.ManageClusterResponse{
 				Success: false,
-				Error: &pb.Error{
-					Code:    "REMOVE_NODE_FAILED",
-					Message: err.Error(),
-				},
+				Error:   newProtoError("REMOVE_NODE_FAILED", err),
 			}, nil
 		}
 		nodes, _ = s.engine.ListClusterNodes(ctx)
@@ -220,10 +209,7 @@ func (s *AdminServiceServer) PauseWorkflows(ctx context.Context, req *pb.PauseWo
 	if err != nil {
 		return &pb.PauseWorkflowsResponse{
 			Success: false,
-			Error: &pb.Error{
-				Code:    "PAUSE_FAILED",
-				Message: err.Error(),
-			},
+			Error:   newProtoError("PAUSE_FAILED", err),
 		}, nil
 	}
 
@@ -239,10 +225,7 @@ func (s *AdminServiceServer) ResumeWorkflows(ctx context.Context, req *pb.Resume
 	if err != nil {
 		return &pb.ResumeWorkflowsResponse{
 			Success: false,
-			Error: &pb.Error{
-				Code:    "RESUME_FAILED",
-				Message: err.Error(),
-			},
+			Error:   newProtoError("RESUME_FAILED", err),
 		}, nil
 	}
 
@@ -270,10 +253,7 @@ func (s *AdminServiceServer) PurgeWorkflows(ctx context.Context, req *pb.PurgeWo
 	if err != nil {
 		return &pb.PurgeWorkflowsResponse{
 			Success: false,
-			Error: &pb.Error{
-				Code:    "PURGE_FAILED",
-				Message: err.Error(),
-			},
+			Error:   newProtoError("PURGE_FAILED", err),
 		}, nil
 	}
 
@@ -293,10 +273,7 @@ func (s *AdminServiceServer) GetLaneStats(ctx context.Context, req *pb.GetLaneSt
 	stats, err := s.engine.GetLaneStats(ctx, laneName)
 	if err != nil {
 		return &pb.GetLaneStatsResponse{
-			Error: &pb.Error{
-				Code:    "GET_STATS_FAILED",
-				Message: err.Error(),
-			},
+			Error: newProtoError("GET_STATS_FAILED", err),
 		}, nil
 	}
 
@@ -336,10 +313,7 @@ func (s *AdminServiceServer) ExportMetrics(ctx context.Context, req *pb.ExportMe
 	data, err := s.engine.ExportMetrics(ctx, format, req.PrefixFilter)
 	if err != nil {
 		return &pb.ExportMetricsResponse{
-			Error: &pb.Error{
-				Code:    "EXPORT_FAILED",
-				Message: err.Error(),
-			},
+			Error: newProtoError("EXPORT_FAILED", err),
 		}, nil
 	}
 
@@ -374,10 +348,7 @@ func (s *AdminServiceServer) GetDebugInfo(ctx context.Context, req *pb.GetDebugI
 
 	if err != nil {
 		return &pb.GetDebugInfoResponse{
-			Error: &pb.Error{
-				Code:    "DEBUG_INFO_FAILED",
-				Message: err.Error(),
-			},
+			Error: newProtoError("DEBUG_INFO_FAILED", err),
 		}, nil
 	}
 
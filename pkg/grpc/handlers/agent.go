@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/goclaw/goclaw/pkg/agent"
+	"github.com/goclaw/goclaw/pkg/errs"
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AgentServiceServer implements the gRPC AgentService
+type AgentServiceServer struct {
+	pb.UnimplementedAgentServiceServer
+	registry *agent.Registry
+}
+
+// NewAgentServiceServer creates a new agent service server
+func NewAgentServiceServer(registry *agent.Registry) *AgentServiceServer {
+	return &AgentServiceServer{registry: registry}
+}
+
+// Drain implements grpc.Drainer. Connected agents receive a final "server
+// shutting down" error and their channels end; tasks still assigned to them
+// fail with agent.ErrClosed.
+func (s *AgentServiceServer) Drain() {
+	s.registry.Close()
+}
+
+// Connect handles an agent's control channel
+func (s *AgentServiceServer) Connect(stream pb.AgentService_ConnectServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	reg := first.GetRegister()
+	if reg == nil {
+		return status.Error(codes.InvalidArgument, "first message must be a registration")
+	}
+
+	session, err := s.registry.Register(agent.Registration{
+		ID:       reg.AgentId,
+		Types:    reg.AgentTypes,
+		Capacity: int(reg.Capacity),
+		Labels:   reg.Labels,
+	})
+	if err != nil {
+		return errs.ToGRPC(err)
+	}
+	defer s.registry.Unregister(session, nil)
+
+	if err := stream.Send(&pb.AgentCommand{Command: &pb.AgentCommand_Registered{Registered: &pb.AgentRegistered{
+		AgentId:                  session.ID(),
+		HeartbeatIntervalSeconds: int32(s.registry.HeartbeatInterval().Seconds()),
+	}}}); err != nil {
+		return err
+	}
+
+	// Only this goroutine receives; only the loop below sends.
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err == nil {
+				err = handleAgentMessage(session, msg)
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case cmd := <-session.Commands():
+			if err := stream.Send(toProtoAgentCommand(cmd)); err != nil {
+				return err
+			}
+
+		case <-session.Done():
+			cause := session.Err()
+			if errors.Is(cause, agent.ErrClosed) {
+				_ = stream.Send(&pb.AgentCommand{Command: &pb.AgentCommand_Error{Error: shutdownError()}})
+				return nil
+			}
+			_ = stream.Send(&pb.AgentCommand{Command: &pb.AgentCommand_Error{Error: newProtoError("AGENT_EVICTED", cause)}})
+			return errs.ToGRPC(cause)
+
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// handleAgentMessage applies a message received after registration.
+// Progress and completions for assignments the agent no longer holds, e.g.
+// after a cancellation crossed a completion, are ignored.
+func handleAgentMessage(session *agent.Session, msg *pb.AgentMessage) error {
+	var err error
+	switch payload := msg.Payload.(type) {
+	case *pb.AgentMessage_Heartbeat:
+		session.Heartbeat()
+	case *pb.AgentMessage_Progress:
+		err = session.Progress(agent.Progress{
+			AssignmentID: payload.Progress.AssignmentId,
+			Percent:      payload.Progress.Percent,
+			Message:      payload.Progress.Message,
+		})
+	case *pb.AgentMessage_Completion:
+		var taskErr error
+		if !payload.Completion.Success {
+			taskErr = fmt.Errorf("agent %s: %s", session.ID(), payload.Completion.ErrorMessage)
+		}
+		err = session.Complete(payload.Completion.AssignmentId, payload.Completion.ResultData, taskErr)
+	case *pb.AgentMessage_Register:
+		return status.Error(codes.InvalidArgument, "agent is already registered")
+	default:
+		return status.Error(codes.InvalidArgument, "message payload is required")
+	}
+	if errors.Is(err, agent.ErrUnknownAssignment) {
+		return nil
+	}
+	return err
+}
+
+// ListAgents lists connected agents, optionally only those accepting a type
+func (s *AgentServiceServer) ListAgents(ctx context.Context, req *pb.ListAgentsRequest) (*pb.ListAgentsResponse, error) {
+	resp := &pb.ListAgentsResponse{}
+	for _, info := range s.registry.Agents() {
+		if req.GetAgentType() != "" && len(info.Types) > 0 && !slices.Contains(info.Types, req.GetAgentType()) {
+			continue
+		}
+		resp.Agents = append(resp.Agents, &pb.AgentInfo{
+			AgentId:       info.ID,
+			AgentTypes:    info.Types,
+			Capacity:      int32(info.Capacity),
+			ActiveTasks:   int32(info.ActiveTasks),
+			Labels:        info.Labels,
+			ConnectedAt:   timestamppb.New(info.ConnectedAt),
+			LastHeartbeat: timestamppb.New(info.LastHeartbeat),
+		})
+	}
+	return resp, nil
+}
+
+// toProtoAgentCommand converts a registry command to its proto message
+func toProtoAgentCommand(cmd agent.Command) *pb.AgentCommand {
+	if cmd.Cancel != nil {
+		return &pb.AgentCommand{Command: &pb.AgentCommand_Cancel{Cancel: &pb.TaskCancellation{
+			AssignmentId: cmd.Cancel.AssignmentID,
+			Reason:       cmd.Cancel.Reason,
+		}}}
+	}
+
+	a := cmd.Assign
+	assignment := &pb.TaskAssignment{
+		AssignmentId: a.ID,
+		WorkflowId:   a.WorkflowID,
+		TaskId:       a.TaskID,
+		Name:         a.Name,
+		AgentType:    a.AgentType,
+		Payload:      a.Payload,
+		Env:          a.Env,
+	}
+	if !a.Deadline.IsZero() {
+		assignment.Deadline = timestamppb.New(a.Deadline)
+	}
+	return &pb.AgentCommand{Command: &pb.AgentCommand_Assign{Assign: assignment}}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/agent"
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockAgentConnectStream implements pb.AgentService_ConnectServer over channels
+type mockAgentConnectStream struct {
+	ctx context.Context
+	in  chan *pb.AgentMessage
+	out chan *pb.AgentCommand
+}
+
+func newMockAgentConnectStream(ctx context.Context) *mockAgentConnectStream {
+	return &mockAgentConnectStream{
+		ctx: ctx,
+		in:  make(chan *pb.AgentMessage, 8),
+		out: make(chan *pb.AgentCommand, 8),
+	}
+}
+
+func (m *mockAgentConnectStream) Recv() (*pb.AgentMessage, error) {
+	select {
+	case msg, ok := <-m.in:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-m.ctx.Done():
+		return nil, m.ctx.Err()
+	}
+}
+
+func (m *mockAgentConnectStream) Send(cmd *pb.AgentCommand) error {
+	m.out <- cmd
+	return nil
+}
+
+func (m *mockAgentConnectStream) receive(t *testing.T) *pb.AgentCommand {
+	t.Helper()
+	select {
+	case cmd := <-m.out:
+		return cmd
+	case <-m.ctx.Done():
+		t.Fatal("timed out waiting for agent command")
+		return nil
+	}
+}
+
+func (m *mockAgentConnectStream) Context() context.Context        { return m.ctx }
+func (m *mockAgentConnectStream) SetHeader(md metadata.MD) error  { return nil }
+func (m *mockAgentConnectStream) SendHeader(md metadata.MD) error { return nil }
+func (m *mockAgentConnectStream) SetTrailer(md metadata.MD)       {}
+func (m *mockAgentConnectStream) SendMsg(msg interface{}) error   { return nil }
+func (m *mockAgentConnectStream) RecvMsg(msg interface{}) error   { return nil }
+
+func TestAgentConnect_AssignsTasksAndDrains(t *testing.T) {
+	registry := agent.NewRegistry(agent.WithHeartbeatInterval(5 * time.Second))
+	server := NewAgentServiceServer(registry)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := newMockAgentConnectStream(ctx)
+
+	errChan := make(chan error, 1)
+	go func() { errChan <- server.Connect(stream) }()
+
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Register{Register: &pb.AgentRegistration{
+		AgentId:    "worker-1",
+		AgentTypes: []string{"shell"},
+		Capacity:   2,
+	}}}
+	registered := stream.receive(t).GetRegistered()
+	require.NotNil(t, registered)
+	assert.Equal(t, "worker-1", registered.AgentId)
+	assert.Equal(t, int32(5), registered.HeartbeatIntervalSeconds)
+
+	type dispatchResult struct {
+		data []byte
+		err  error
+	}
+	results := make(chan dispatchResult, 1)
+	go func() {
+		data, err := registry.Dispatch(ctx, agent.Assignment{
+			WorkflowID: "wf-1",
+			TaskID:     "build",
+			AgentType:  "shell",
+			Env:        map[string]string{"TOKEN": "t0ken"},
+		})
+		results <- dispatchResult{data, err}
+	}()
+
+	assign := stream.receive(t).GetAssign()
+	require.NotNil(t, assign)
+	assert.Equal(t, "wf-1", assign.WorkflowId)
+	assert.Equal(t, "build", assign.TaskId)
+	assert.Equal(t, map[string]string{"TOKEN": "t0ken"}, assign.Env)
+	require.NotNil(t, assign.Deadline)
+
+	resp, err := server.ListAgents(ctx, &pb.ListAgentsRequest{AgentType: "shell"})
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 1)
+	assert.Equal(t, int32(1), resp.Agents[0].ActiveTasks)
+	assert.Equal(t, int32(2), resp.Agents[0].Capacity)
+
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Heartbeat{Heartbeat: &pb.AgentHeartbeat{ActiveTasks: 1}}}
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Progress{Progress: &pb.TaskProgress{AssignmentId: assign.AssignmentId, Percent: 50}}}
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Completion{Completion: &pb.TaskCompletion{
+		AssignmentId: assign.AssignmentId,
+		Success:      true,
+		ResultData:   []byte("done"),
+	}}}
+	result := <-results
+	require.NoError(t, result.err)
+	assert.Equal(t, "done", string(result.data))
+
+	resp, err = server.ListAgents(ctx, &pb.ListAgentsRequest{AgentType: "gpu"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Agents)
+
+	server.Drain()
+	final := stream.receive(t).GetError()
+	require.NotNil(t, final)
+	assert.Equal(t, "SERVICE_UNAVAILABLE", final.Code)
+	require.NoError(t, <-errChan)
+}
+
+func TestAgentConnect_RequiresRegistration(t *testing.T) {
+	server := NewAgentServiceServer(agent.NewRegistry())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := newMockAgentConnectStream(ctx)
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Heartbeat{Heartbeat: &pb.AgentHeartbeat{}}}
+
+	err := server.Connect(stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAgentConnect_DisconnectFailsAssignedTasks(t *testing.T) {
+	registry := agent.NewRegistry()
+	server := NewAgentServiceServer(registry)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := newMockAgentConnectStream(ctx)
+
+	errChan := make(chan error, 1)
+	go func() { errChan <- server.Connect(stream) }()
+	stream.in <- &pb.AgentMessage{Payload: &pb.AgentMessage_Register{Register: &pb.AgentRegistration{AgentId: "worker-1", Capacity: 1}}}
+	stream.receive(t)
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := registry.Dispatch(ctx, agent.Assignment{TaskID: "build"})
+		results <- err
+	}()
+	require.NotNil(t, stream.receive(t).GetAssign())
+
+	close(stream.in)
+	require.NoError(t, <-errChan)
+	assert.ErrorIs(t, <-results, agent.ErrDisconnected)
+	assert.Empty(t, registry.Agents())
+}
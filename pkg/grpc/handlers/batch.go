@@ -250,10 +250,7 @@ func (s *BatchServiceServer) submitSingleWorkflow(ctx context.Context, wf *pb.Su
 		return &pb.WorkflowSubmissionResult{
 			Index:   int32(index),
 			Success: false,
-			Error: &pb.Error{
-				Code:    "SUBMISSION_FAILED",
-				Message: err.Error(),
-			},
+			Error:   newProtoError("SUBMISSION_FAILED", err),
 		}
 	}
 
@@ -275,6 +272,11 @@ func (s *BatchServiceServer) GetWorkflowStatuses(ctx context.Context, req *pb.Ge
 		return nil, status.Errorf(codes.InvalidArgument, "batch size exceeds maximum of %d", MaxBatchSize)
 	}
 
+	mask, err := newStatusMask(req.FieldMask)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apply pagination
 	startIdx := 0
 	endIdx := len(req.WorkflowIds)
@@ -314,7 +316,7 @@ func (s *BatchServiceServer) GetWorkflowStatuses(ctx context.Context, req *pb.Ge
 		go func() {
 			defer wg.Done()
 			for i := range workChan {
-				result := s.getSingleWorkflowStatus(ctx, workflowIDs[i])
+				result := s.getSingleWorkflowStatus(ctx, workflowIDs[i], mask)
 				resultChan <- struct {
 					index  int
 					result *pb.WorkflowStatusResult
@@ -356,43 +358,20 @@ func (s *BatchServiceServer) GetWorkflowStatuses(ctx context.Context, req *pb.Ge
 }
 
 // getSingleWorkflowStatus retrieves status for a single workflow
-func (s *BatchServiceServer) getSingleWorkflowStatus(ctx context.Context, workflowID string) *pb.WorkflowStatusResult {
+func (s *BatchServiceServer) getSingleWorkflowStatus(ctx context.Context, workflowID string, mask *statusMask) *pb.WorkflowStatusResult {
 	ws, err := s.engine.GetWorkflowStatus(ctx, workflowID)
 	if err != nil {
 		return &pb.WorkflowStatusResult{
 			WorkflowId: workflowID,
 			Found:      false,
-			Error: &pb.Error{
-				Code:    "NOT_FOUND",
-				Message: err.Error(),
-			},
-		}
-	}
-
-	// Convert tasks
-	pbTasks := make([]*pb.TaskStatusDetail, len(ws.Tasks))
-	for i, t := range ws.Tasks {
-		pbTasks[i] = &pb.TaskStatusDetail{
-			TaskId:       t.TaskID,
-			Name:         t.Name,
-			Status:       convertToProtoTaskStatus(t.Status),
-			StartedAt:    timestampFromUnix(t.StartedAt),
-			CompletedAt:  timestampFromUnix(t.CompletedAt),
-			ErrorMessage: t.ErrorMsg,
+			Error:      newProtoError("NOT_FOUND", err),
 		}
 	}
 
 	return &pb.WorkflowStatusResult{
 		WorkflowId: workflowID,
 		Found:      true,
-		Status: &pb.GetWorkflowStatusResponse{
-			WorkflowId: ws.WorkflowID,
-			Name:       ws.Name,
-			Status:     convertToProtoStatus(ws.Status),
-			Tasks:      pbTasks,
-			CreatedAt:  timestampFromUnix(ws.CreatedAt),
-			UpdatedAt:  timestampFromUnix(ws.UpdatedAt),
-		},
+		Status:     toProtoWorkflowStatus(ws, mask),
 	}
 }
 
@@ -479,10 +458,7 @@ func (s *BatchServiceServer) cancelSingleWorkflow(ctx context.Context, workflowI
 		return &pb.WorkflowCancellationResult{
 			WorkflowId: workflowID,
 			Success:    false,
-			Error: &pb.Error{
-				Code:    "CANCEL_FAILED",
-				Message: err.Error(),
-			},
+			Error:      newProtoError("CANCEL_FAILED", err),
 		}
 	}
 
@@ -593,10 +569,7 @@ func (s *BatchServiceServer) getSingleTaskResult(ctx context.Context, workflowID
 		return &pb.TaskResultDetail{
 			TaskId: taskID,
 			Found:  false,
-			Error: &pb.Error{
-				Code:    "NOT_FOUND",
-				Message: err.Error(),
-			},
+			Error:  newProtoError("NOT_FOUND", err),
 		}
 	}
 
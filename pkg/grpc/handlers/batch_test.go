@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // mockBatchEngine implements WorkflowEngine for batch testing
@@ -312,6 +313,38 @@ func TestGetWorkflowStatuses_Success(t *testing.T) {
 	assert.Equal(t, "wf-1", resp.Results[0].WorkflowId)
 }
 
+func TestGetWorkflowStatuses_FieldMask(t *testing.T) {
+	engine := &mockBatchEngine{
+		getStatusFunc: func(ctx context.Context, workflowID string) (*WorkflowStatus, error) {
+			return &WorkflowStatus{
+				WorkflowID: workflowID,
+				Status:     "COMPLETED",
+				Tasks:      []*TaskStatus{{TaskID: "task-1", Status: "COMPLETED"}},
+			}, nil
+		},
+	}
+	server := NewBatchServiceServer(engine)
+
+	resp, err := server.GetWorkflowStatuses(context.Background(), &pb.GetWorkflowStatusesRequest{
+		WorkflowIds: []string{"wf-1", "wf-2"},
+		FieldMask:   &fieldmaskpb.FieldMask{Paths: []string{"workflow_id", "status"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	for _, r := range resp.Results {
+		require.True(t, r.Found)
+		assert.Equal(t, r.WorkflowId, r.Status.WorkflowId)
+		assert.Equal(t, pb.WorkflowStatus_WORKFLOW_STATUS_COMPLETED, r.Status.Status)
+		assert.Empty(t, r.Status.Tasks)
+	}
+
+	_, err = server.GetWorkflowStatuses(context.Background(), &pb.GetWorkflowStatusesRequest{
+		WorkflowIds: []string{"wf-1"},
+		FieldMask:   &fieldmaskpb.FieldMask{Paths: []string{"unknown"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestGetWorkflowStatuses_EmptyRequest(t *testing.T) {
 	engine := &mockBatchEngine{}
 	server := NewBatchServiceServer(engine)
@@ -50,6 +50,7 @@ func (a *EngineAdapter) SubmitWorkflow(ctx context.Context, name string, tasks [
 			Type:      "function",
 			DependsOn: append([]string(nil), t.Dependencies...),
 			Config:    map[string]interface{}{},
+			Sensitive: t.Metadata["sensitive"] == "true",
 		}
 		if laneName, ok := t.Metadata["lane"]; ok && laneName != "" {
 			taskDef.Config["lane"] = laneName
@@ -146,6 +147,28 @@ func (a *EngineAdapter) ListWorkflows(ctx context.Context, filter WorkflowFilter
 	return summaries, nextToken, nil
 }
 
+// StreamWorkflows implements WorkflowStreamer by streaming workflows from
+// the engine's storage.
+func (a *EngineAdapter) StreamWorkflows(ctx context.Context, filter WorkflowStreamFilter, fn func(*WorkflowSummary) error) error {
+	err := a.engine.StreamWorkflows(ctx, models.WorkflowFilter{
+		Status: normalizeWorkflowFilterStatus(filter.StatusFilter),
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, func(wf *models.WorkflowStatusResponse) error {
+		return fn(&WorkflowSummary{
+			WorkflowID: wf.ID,
+			Name:       wf.Name,
+			Status:     wf.Status,
+			CreatedAt:  wf.CreatedAt.Unix(),
+			UpdatedAt:  chooseWorkflowUpdatedAt(wf),
+		})
+	})
+	if err != nil {
+		a.lastErrMsg = err.Error()
+	}
+	return err
+}
+
 // CancelWorkflow cancels a pending/running workflow.
 func (a *EngineAdapter) CancelWorkflow(ctx context.Context, workflowID string, force bool) error {
 	_ = force
@@ -212,12 +235,27 @@ func (a *EngineAdapter) IsHealthy() bool {
 	return a.engine.IsHealthy()
 }
 
-// UpdateConfig is not supported yet in local runtime mode.
+// UpdateConfig applies hot-reloadable config changes to the running engine.
+// Safe changes are applied even when others are rejected; the returned error
+// lists the rejected fields. Updates are not persisted to the config file.
 func (a *EngineAdapter) UpdateConfig(ctx context.Context, updates map[string]string, persist bool) (map[string]string, error) {
 	_ = ctx
-	_ = updates
 	_ = persist
-	return nil, errors.New("runtime config updates are not supported yet")
+	reloader := a.engine.ConfigReloader()
+	if reloader == nil {
+		return nil, errors.New("runtime config updates are not supported")
+	}
+
+	result, err := reloader.ApplyUpdates(updates)
+	if err != nil {
+		a.lastErrMsg = err.Error()
+		return nil, err
+	}
+	if rejectedErr := result.RejectedError(); rejectedErr != nil {
+		a.lastErrMsg = rejectedErr.Error()
+		return result.AppliedKeys(), rejectedErr
+	}
+	return result.AppliedKeys(), nil
 }
 
 // ListClusterNodes returns an empty local cluster view.
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"strings"
+
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// statusMask selects the fields of GetWorkflowStatusResponse returned to a
+// client. A nil statusMask selects every field.
+type statusMask struct {
+	paths []string
+}
+
+// newStatusMask validates mask against GetWorkflowStatusResponse. An empty
+// mask yields nil, so that full responses skip pruning altogether.
+func newStatusMask(mask *fieldmaskpb.FieldMask) (*statusMask, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+	if !mask.IsValid(&pb.GetWorkflowStatusResponse{}) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid field_mask: %v", mask.GetPaths())
+	}
+	normalized := proto.Clone(mask).(*fieldmaskpb.FieldMask)
+	normalized.Normalize()
+	return &statusMask{paths: normalized.GetPaths()}, nil
+}
+
+// includes reports whether any part of the top-level field is selected, so
+// that handlers can skip building fields nobody asked for
+func (m *statusMask) includes(field string) bool {
+	if m == nil {
+		return true
+	}
+	for _, p := range m.paths {
+		if p == field || strings.HasPrefix(p, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// apply clears every field of resp that the mask does not select
+func (m *statusMask) apply(resp *pb.GetWorkflowStatusResponse) {
+	if m == nil || resp == nil {
+		return
+	}
+	pruneMessage(resp.ProtoReflect(), m.paths)
+}
+
+// pruneMessage clears the fields of msg not named by paths. Paths must be
+// normalized, so a field is never selected both whole and in part.
+func pruneMessage(msg protoreflect.Message, paths []string) {
+	selected := make(map[protoreflect.Name][]string, len(paths))
+	for _, p := range paths {
+		head, rest, nested := strings.Cut(p, ".")
+		name := protoreflect.Name(head)
+		if !nested {
+			selected[name] = nil
+			continue
+		}
+		selected[name] = append(selected[name], rest)
+	}
+
+	var unselected []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := selected[fd.Name()]
+		switch {
+		case !ok:
+			unselected = append(unselected, fd)
+		case sub != nil && fd.Message() != nil && fd.Cardinality() != protoreflect.Repeated:
+			pruneMessage(v.Message(), sub)
+		}
+		return true
+	})
+	for _, fd := range unselected {
+		msg.Clear(fd)
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/errs"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -50,3 +53,22 @@ func timestampFromUnix(unix int64) *timestamppb.Timestamp {
 	}
 	return timestamppb.New(time.Unix(unix, 0))
 }
+
+// newProtoError builds an in-band response error for the given operation code.
+// The stable errs code and retry hint are carried in details so clients can
+// branch on them regardless of the operation.
+func newProtoError(code string, err error) *pb.Error {
+	e := errs.From(err)
+	details := map[string]string{
+		"error_code": string(e.Code),
+		"retryable":  strconv.FormatBool(e.IsRetryable()),
+	}
+	for key, value := range e.Details {
+		details[key] = fmt.Sprint(value)
+	}
+	return &pb.Error{
+		Code:    code,
+		Message: err.Error(),
+		Details: details,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/errs"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"github.com/goclaw/goclaw/pkg/saga"
 	"github.com/google/uuid"
@@ -16,6 +17,10 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// errSagaDefinitionNotFound is returned when compensating a saga this server
+// did not submit.
+var errSagaDefinitionNotFound = errs.New(errs.NotFound, "saga definition not found")
+
 // SagaServiceServer implements gRPC SagaService.
 type SagaServiceServer struct {
 	pb.UnimplementedSagaServiceServer
@@ -38,7 +43,6 @@ func NewSagaServiceServer(orchestrator *saga.SagaOrchestrator, checkpointStore s
 
 // SubmitSaga submits a Saga for asynchronous execution.
 func (s *SagaServiceServer) SubmitSaga(ctx context.Context, req *pb.SubmitSagaRequest) (*pb.SubmitSagaResponse, error) {
-	_ = ctx
 	if s.orchestrator == nil {
 		return nil, status.Error(codes.Unavailable, "saga orchestrator unavailable")
 	}
@@ -47,7 +51,24 @@ func (s *SagaServiceServer) SubmitSaga(ctx context.Context, req *pb.SubmitSagaRe
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	if err := s.admit(ctx, req, definition, input); err != nil {
+		return nil, errs.ToGRPC(err)
+	}
+
+	return s.submit(definition, input), nil
+}
+
+// admit runs a built saga through the orchestrator's admission check.
+func (s *SagaServiceServer) admit(ctx context.Context, req *pb.SubmitSagaRequest, definition *saga.SagaDefinition, input any) error {
+	return s.orchestrator.Admit(ctx, saga.Submission{
+		Definition: definition,
+		Metadata:   req.GetMetadata(),
+		Input:      input,
+	})
+}
 
+// submit starts a validated saga in the background.
+func (s *SagaServiceServer) submit(definition *saga.SagaDefinition, input any) *pb.SubmitSagaResponse {
 	sagaID := uuid.NewString()
 	s.defMu.Lock()
 	s.definitions[sagaID] = definition
@@ -62,7 +83,7 @@ func (s *SagaServiceServer) SubmitSaga(ctx context.Context, req *pb.SubmitSagaRe
 		Name:      definition.Name,
 		State:     pb.SagaState_SAGA_STATE_RUNNING,
 		CreatedAt: timestamppb.Now(),
-	}, nil
+	}
 }
 
 // GetSagaStatus gets one saga runtime status.
@@ -76,15 +97,12 @@ func (s *SagaServiceServer) GetSagaStatus(ctx context.Context, req *pb.GetSagaSt
 
 	instance, err := s.orchestrator.GetInstance(req.SagaId)
 	if err != nil {
-		if errors.Is(err, saga.ErrSagaNotFound) {
-			return nil, status.Error(codes.NotFound, "saga not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 
 	resp, err := sagaInstanceToStatus(instance)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 	return resp, nil
 }
@@ -125,7 +143,7 @@ func (s *SagaServiceServer) ListSagas(ctx context.Context, req *pb.ListSagasRequ
 		Offset: offset,
 	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 
 	items := make([]*pb.SagaSummary, 0, len(instances))
@@ -156,25 +174,18 @@ func (s *SagaServiceServer) CompensateSaga(ctx context.Context, req *pb.Compensa
 		return nil, status.Error(codes.InvalidArgument, "saga_id is required")
 	}
 
-	definition := s.getDefinition(req.SagaId)
-	if definition == nil {
-		return nil, status.Error(codes.NotFound, "saga definition not found")
-	}
-
-	reason := errors.New("manual compensation requested")
-	if strings.TrimSpace(req.Reason) != "" {
-		reason = errors.New(req.Reason)
-	}
-
-	instance, err := s.orchestrator.TriggerCompensation(ctx, req.SagaId, definition, nil, reason)
+	instance, err := s.compensate(ctx, req.SagaId, req.Reason)
 	if err != nil {
+		if errors.Is(err, errSagaDefinitionNotFound) {
+			return nil, status.Error(codes.NotFound, "saga definition not found")
+		}
 		if errors.Is(err, saga.ErrSagaNotFound) {
 			return nil, status.Error(codes.NotFound, "saga not found")
 		}
 		if strings.Contains(err.Error(), "pending-compensation") {
 			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 
 	return &pb.CompensateSagaResponse{
@@ -183,6 +194,20 @@ func (s *SagaServiceServer) CompensateSaga(ctx context.Context, req *pb.Compensa
 	}, nil
 }
 
+// compensate triggers the compensation of a saga this server submitted.
+func (s *SagaServiceServer) compensate(ctx context.Context, sagaID, reason string) (*saga.SagaInstance, error) {
+	definition := s.getDefinition(sagaID)
+	if definition == nil {
+		return nil, errSagaDefinitionNotFound
+	}
+
+	cause := errors.New("manual compensation requested")
+	if strings.TrimSpace(reason) != "" {
+		cause = errors.New(reason)
+	}
+	return s.orchestrator.TriggerCompensation(ctx, sagaID, definition, nil, cause)
+}
+
 // WatchSaga streams saga state changes until terminal state.
 func (s *SagaServiceServer) WatchSaga(req *pb.WatchSagaRequest, stream pb.SagaService_WatchSagaServer) error {
 	if s.orchestrator == nil {
@@ -220,7 +245,7 @@ func (s *SagaServiceServer) WatchSaga(req *pb.WatchSagaRequest, stream pb.SagaSe
 				}
 				return status.Error(codes.NotFound, "saga not found")
 			}
-			return status.Error(codes.Internal, err.Error())
+			return errs.ToGRPC(err)
 		}
 		notFoundRetries = 0
 
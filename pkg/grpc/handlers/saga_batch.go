@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"github.com/goclaw/goclaw/pkg/saga"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SagaBatchServiceServer implements the gRPC SagaBatchService on top of a
+// SagaServiceServer, so that sagas submitted in batches can be compensated
+// one by one and the other way around.
+type SagaBatchServiceServer struct {
+	pb.UnimplementedSagaBatchServiceServer
+	sagas            *SagaServiceServer
+	workerPoolSize   int
+	idempotencyCache *IdempotencyCache
+}
+
+// NewSagaBatchServiceServer creates a new saga batch service server
+func NewSagaBatchServiceServer(sagas *SagaServiceServer) *SagaBatchServiceServer {
+	return &SagaBatchServiceServer{
+		sagas:            sagas,
+		workerPoolSize:   DefaultWorkerPoolSize,
+		idempotencyCache: NewIdempotencyCache(time.Hour), // 1 hour TTL
+	}
+}
+
+// SetWorkerPoolSize sets the worker pool size for parallel processing
+func (s *SagaBatchServiceServer) SetWorkerPoolSize(size int) {
+	if size > 0 {
+		s.workerPoolSize = size
+	}
+}
+
+// SubmitSagas handles batch saga submission
+func (s *SagaBatchServiceServer) SubmitSagas(ctx context.Context, req *pb.SubmitSagasRequest) (*pb.SubmitSagasResponse, error) {
+	if s.sagas == nil || s.sagas.orchestrator == nil {
+		return nil, status.Error(codes.Unavailable, "saga orchestrator unavailable")
+	}
+	if req == nil || len(req.Sagas) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one saga is required")
+	}
+	if len(req.Sagas) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch size exceeds maximum of %d", MaxBatchSize)
+	}
+
+	if req.IdempotencyKey != "" {
+		if cachedResp := s.idempotencyCache.Get(req.IdempotencyKey); cachedResp != nil {
+			return cachedResp.(*pb.SubmitSagasResponse), nil
+		}
+	}
+
+	results := make([]*pb.SagaSubmissionResult, len(req.Sagas))
+	if req.Atomic {
+		// Submitted sagas cannot be withdrawn, so atomic batches are
+		// validated entirely before the first saga starts.
+		definitions := make([]*saga.SagaDefinition, len(req.Sagas))
+		inputs := make([]any, len(req.Sagas))
+		for i, sagaReq := range req.Sagas {
+			definition, input, err := buildSagaDefinitionFromProto(sagaReq)
+			if err != nil {
+				return &pb.SubmitSagasResponse{
+					Error: &pb.Error{
+						Code:    "VALIDATION_FAILED",
+						Message: fmt.Sprintf("saga %d: %v (submitted none)", i, err),
+					},
+				}, nil
+			}
+			if err := s.sagas.admit(ctx, sagaReq, definition, input); err != nil {
+				return &pb.SubmitSagasResponse{
+					Error: &pb.Error{
+						Code:    string(errs.CodeOf(err)),
+						Message: fmt.Sprintf("saga %d: %v (submitted none)", i, err),
+					},
+				}, nil
+			}
+			definitions[i], inputs[i] = definition, input
+		}
+		for i := range req.Sagas {
+			results[i] = &pb.SagaSubmissionResult{
+				Index:   int32(i),
+				Success: true,
+				SagaId:  s.sagas.submit(definitions[i], inputs[i]).SagaId,
+			}
+		}
+	} else if req.Ordered {
+		for i, sagaReq := range req.Sagas {
+			results[i] = s.submitSingleSaga(ctx, sagaReq, i)
+		}
+	} else {
+		s.forEach(len(req.Sagas), func(i int) {
+			results[i] = s.submitSingleSaga(ctx, req.Sagas[i], i)
+		})
+	}
+
+	resp := &pb.SubmitSagasResponse{
+		Results: results,
+		Pagination: &pb.PaginationResponse{
+			TotalCount: int32(len(results)),
+		},
+	}
+
+	// Cache response for idempotency
+	if req.IdempotencyKey != "" {
+		s.idempotencyCache.Set(req.IdempotencyKey, resp)
+	}
+
+	return resp, nil
+}
+
+// submitSingleSaga submits a single saga and returns the result
+func (s *SagaBatchServiceServer) submitSingleSaga(ctx context.Context, req *pb.SubmitSagaRequest, index int) *pb.SagaSubmissionResult {
+	definition, input, err := buildSagaDefinitionFromProto(req)
+	if err != nil {
+		return &pb.SagaSubmissionResult{
+			Index:   int32(index),
+			Success: false,
+			Error: &pb.Error{
+				Code:    "VALIDATION_FAILED",
+				Message: err.Error(),
+			},
+		}
+	}
+	if err := s.sagas.admit(ctx, req, definition, input); err != nil {
+		return &pb.SagaSubmissionResult{
+			Index:   int32(index),
+			Success: false,
+			Error: &pb.Error{
+				Code:    string(errs.CodeOf(err)),
+				Message: err.Error(),
+			},
+		}
+	}
+
+	return &pb.SagaSubmissionResult{
+		Index:   int32(index),
+		Success: true,
+		SagaId:  s.sagas.submit(definition, input).SagaId,
+	}
+}
+
+// GetSagaStatuses handles batch saga status retrieval
+func (s *SagaBatchServiceServer) GetSagaStatuses(ctx context.Context, req *pb.GetSagaStatusesRequest) (*pb.GetSagaStatusesResponse, error) {
+	if s.sagas == nil || s.sagas.orchestrator == nil {
+		return nil, status.Error(codes.Unavailable, "saga orchestrator unavailable")
+	}
+	if req == nil {
+		req = &pb.GetSagaStatusesRequest{}
+	}
+
+	sagaIDs, err := s.selectSagas(ctx, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	pageSize := MaxBatchSize
+	startIdx := 0
+	if req.Pagination != nil {
+		if req.Pagination.PageSize > 0 && int(req.Pagination.PageSize) < pageSize {
+			pageSize = int(req.Pagination.PageSize)
+		}
+		if req.Pagination.PageToken != "" {
+			offset, err := parsePageTokenOffset(req.Pagination.PageToken)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+			}
+			startIdx = min(offset, len(sagaIDs))
+		}
+	}
+	endIdx := min(startIdx+pageSize, len(sagaIDs))
+
+	page := sagaIDs[startIdx:endIdx]
+	results := make([]*pb.SagaStatusResult, len(page))
+	s.forEach(len(page), func(i int) {
+		results[i] = s.getSingleSagaStatus(page[i])
+	})
+
+	// Generate next page token
+	nextPageToken := ""
+	if endIdx < len(sagaIDs) {
+		nextPageToken = fmt.Sprintf("%d", endIdx)
+	}
+
+	return &pb.GetSagaStatusesResponse{
+		Results: results,
+		Pagination: &pb.PaginationResponse{
+			NextPageToken: nextPageToken,
+			TotalCount:    int32(len(sagaIDs)),
+		},
+	}, nil
+}
+
+// getSingleSagaStatus retrieves status for a single saga
+func (s *SagaBatchServiceServer) getSingleSagaStatus(sagaID string) *pb.SagaStatusResult {
+	instance, err := s.sagas.orchestrator.GetInstance(sagaID)
+	if err != nil {
+		return &pb.SagaStatusResult{
+			SagaId: sagaID,
+			Found:  false,
+			Error:  newProtoError("NOT_FOUND", err),
+		}
+	}
+
+	sagaStatus, err := sagaInstanceToStatus(instance)
+	if err != nil {
+		return &pb.SagaStatusResult{
+			SagaId: sagaID,
+			Found:  true,
+			Error:  newProtoError("STATUS_FAILED", err),
+		}
+	}
+
+	return &pb.SagaStatusResult{
+		SagaId: sagaID,
+		Found:  true,
+		Status: sagaStatus,
+	}
+}
+
+// CompensateSagas handles batch saga compensation. At most MaxBatchSize
+// sagas are compensated per call; the count of the others is returned so
+// that clients can call again.
+func (s *SagaBatchServiceServer) CompensateSagas(ctx context.Context, req *pb.CompensateSagasRequest) (*pb.CompensateSagasResponse, error) {
+	if s.sagas == nil || s.sagas.orchestrator == nil {
+		return nil, status.Error(codes.Unavailable, "saga orchestrator unavailable")
+	}
+	filter := req.GetFilter()
+	if len(filter.GetSagaIds()) == 0 && filter.GetState() == pb.SagaState_SAGA_STATE_UNSPECIFIED && filter.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "filter must select sagas by id, state or name")
+	}
+
+	if req.IdempotencyKey != "" {
+		if cachedResp := s.idempotencyCache.Get(req.IdempotencyKey); cachedResp != nil {
+			return cachedResp.(*pb.CompensateSagasResponse), nil
+		}
+	}
+
+	sagaIDs, err := s.selectSagas(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	remaining := 0
+	if len(sagaIDs) > MaxBatchSize {
+		remaining = len(sagaIDs) - MaxBatchSize
+		sagaIDs = sagaIDs[:MaxBatchSize]
+	}
+
+	// Apply timeout if specified
+	compensateCtx := ctx
+	if req.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		compensateCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	results := make([]*pb.SagaCompensationResult, len(sagaIDs))
+	s.forEach(len(sagaIDs), func(i int) {
+		results[i] = s.compensateSingleSaga(compensateCtx, sagaIDs[i], req.Reason)
+	})
+
+	resp := &pb.CompensateSagasResponse{
+		Results:   results,
+		Remaining: int32(remaining),
+	}
+
+	// Cache response for idempotency
+	if req.IdempotencyKey != "" {
+		s.idempotencyCache.Set(req.IdempotencyKey, resp)
+	}
+
+	return resp, nil
+}
+
+// compensateSingleSaga compensates a single saga
+func (s *SagaBatchServiceServer) compensateSingleSaga(ctx context.Context, sagaID, reason string) *pb.SagaCompensationResult {
+	instance, err := s.sagas.compensate(ctx, sagaID, reason)
+	if err != nil {
+		return &pb.SagaCompensationResult{
+			SagaId:  sagaID,
+			Success: false,
+			Error:   newProtoError("COMPENSATE_FAILED", err),
+		}
+	}
+
+	return &pb.SagaCompensationResult{
+		SagaId:  sagaID,
+		Success: true,
+		State:   sagaStateToProto(instance.State),
+	}
+}
+
+// selectSagas returns the IDs of the sagas filter selects.
+func (s *SagaBatchServiceServer) selectSagas(ctx context.Context, filter *pb.SagaFilter) ([]string, error) {
+	if ids := filter.GetSagaIds(); len(ids) > 0 {
+		if len(ids) > MaxBatchSize {
+			return nil, status.Errorf(codes.InvalidArgument, "batch size exceeds maximum of %d", MaxBatchSize)
+		}
+		return ids, nil
+	}
+
+	state, err := protoStateFilterToString(filter.GetState())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	instances, _, err := s.sagas.orchestrator.ListInstancesFiltered(ctx, saga.SagaListFilter{State: state})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sagas: %v", err)
+	}
+
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if filter.GetName() != "" && instance.DefinitionName != filter.GetName() {
+			continue
+		}
+		ids = append(ids, instance.ID)
+	}
+	return ids, nil
+}
+
+// forEach calls fn for the indexes below n on the worker pool.
+func (s *SagaBatchServiceServer) forEach(n int, fn func(i int)) {
+	workChan := make(chan int, n)
+	for i := 0; i < n; i++ {
+		workChan <- i
+	}
+	close(workChan)
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.workerPoolSize && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range workChan {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func manualSagaRequest(name string) *pb.SubmitSagaRequest {
+	return &pb.SubmitSagaRequest{
+		Name:   name,
+		Policy: pb.SagaCompensationPolicy_SAGA_COMPENSATION_POLICY_MANUAL,
+		Steps: []*pb.SagaStepDefinition{
+			{Id: "a", EnableCompensation: true},
+			{Id: "b", DependsOn: []string{"a"}, ShouldFail: true},
+		},
+	}
+}
+
+func TestSagaBatchSubmitSagas(t *testing.T) {
+	sagas, cleanup := newSagaServiceForTest(t)
+	defer cleanup()
+	server := NewSagaBatchServiceServer(sagas)
+
+	invalid := &pb.SubmitSagaRequest{
+		Name: "cycle",
+		Steps: []*pb.SagaStepDefinition{
+			{Id: "a", DependsOn: []string{"b"}},
+			{Id: "b", DependsOn: []string{"a"}},
+		},
+	}
+	req := &pb.SubmitSagasRequest{
+		Sagas:          []*pb.SubmitSagaRequest{manualSagaRequest("batch"), invalid, manualSagaRequest("batch")},
+		IdempotencyKey: "submit-1",
+	}
+	resp, err := server.SubmitSagas(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+	assert.True(t, resp.Results[0].Success)
+	assert.False(t, resp.Results[1].Success)
+	assert.Equal(t, "VALIDATION_FAILED", resp.Results[1].Error.Code)
+	assert.True(t, resp.Results[2].Success)
+	assert.Equal(t, int32(2), resp.Results[2].Index)
+
+	again, err := server.SubmitSagas(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, resp.Results[0].SagaId, again.Results[0].SagaId, "idempotent retry must not resubmit")
+
+	atomic, err := server.SubmitSagas(context.Background(), &pb.SubmitSagasRequest{
+		Sagas:  []*pb.SubmitSagaRequest{manualSagaRequest("atomic"), invalid},
+		Atomic: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, atomic.Error)
+	assert.Equal(t, "VALIDATION_FAILED", atomic.Error.Code)
+	assert.Empty(t, atomic.Results)
+
+	time.Sleep(100 * time.Millisecond)
+	statuses, err := server.GetSagaStatuses(context.Background(), &pb.GetSagaStatusesRequest{
+		Filter: &pb.SagaFilter{Name: "atomic"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, statuses.Results, "atomic batch with an invalid saga must submit none")
+
+	_, err = server.SubmitSagas(context.Background(), &pb.SubmitSagasRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSagaBatchStatusesAndCompensation(t *testing.T) {
+	sagas, cleanup := newSagaServiceForTest(t)
+	defer cleanup()
+	server := NewSagaBatchServiceServer(sagas)
+	server.SetWorkerPoolSize(2)
+
+	requests := make([]*pb.SubmitSagaRequest, 0, 5)
+	for i := 0; i < 5; i++ {
+		requests = append(requests, manualSagaRequest("bulk"))
+	}
+	submitted, err := server.SubmitSagas(context.Background(), &pb.SubmitSagasRequest{Sagas: requests})
+	require.NoError(t, err)
+	time.Sleep(150 * time.Millisecond)
+
+	page, err := server.GetSagaStatuses(context.Background(), &pb.GetSagaStatusesRequest{
+		Filter:     &pb.SagaFilter{Name: "bulk", State: pb.SagaState_SAGA_STATE_PENDING_COMPENSATION},
+		Pagination: &pb.PaginationRequest{PageSize: 3},
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Results, 3)
+	assert.Equal(t, int32(5), page.Pagination.TotalCount)
+	assert.Equal(t, "3", page.Pagination.NextPageToken)
+
+	byID, err := server.GetSagaStatuses(context.Background(), &pb.GetSagaStatusesRequest{
+		Filter: &pb.SagaFilter{SagaIds: []string{submitted.Results[0].SagaId, "missing"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, byID.Results, 2)
+	assert.True(t, byID.Results[0].Found)
+	assert.Equal(t, pb.SagaState_SAGA_STATE_PENDING_COMPENSATION, byID.Results[0].Status.State)
+	assert.False(t, byID.Results[1].Found)
+
+	_, err = server.CompensateSagas(context.Background(), &pb.CompensateSagasRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "an empty filter must not compensate every saga")
+
+	compensated, err := server.CompensateSagas(context.Background(), &pb.CompensateSagasRequest{
+		Filter: &pb.SagaFilter{State: pb.SagaState_SAGA_STATE_PENDING_COMPENSATION},
+		Reason: "bulk rollback",
+	})
+	require.NoError(t, err)
+	require.Len(t, compensated.Results, 5)
+	for _, result := range compensated.Results {
+		assert.True(t, result.Success, "saga %s: %v", result.SagaId, result.Error)
+		assert.Equal(t, pb.SagaState_SAGA_STATE_COMPENSATED, result.State)
+	}
+	assert.Zero(t, compensated.Remaining)
+
+	again, err := server.CompensateSagas(context.Background(), &pb.CompensateSagasRequest{
+		Filter: &pb.SagaFilter{SagaIds: []string{submitted.Results[0].SagaId}},
+	})
+	require.NoError(t, err)
+	require.Len(t, again.Results, 1)
+	assert.False(t, again.Results[0].Success, "a compensated saga cannot be compensated again")
+}
+
+func TestSagaBatchUnavailable(t *testing.T) {
+	server := NewSagaBatchServiceServer(NewSagaServiceServer(nil, nil))
+	_, err := server.SubmitSagas(context.Background(), &pb.SubmitSagasRequest{Sagas: []*pb.SubmitSagaRequest{manualSagaRequest("x")}})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = server.GetSagaStatuses(context.Background(), &pb.GetSagaStatusesRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
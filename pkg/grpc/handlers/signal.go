@@ -6,8 +6,10 @@ import (
 
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"github.com/goclaw/goclaw/pkg/signal"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // SignalServiceServer implements the gRPC SignalService.
@@ -55,10 +57,7 @@ func (s *SignalServiceServer) SignalTask(ctx context.Context, req *pb.SignalTask
 		if err := signal.SendSteer(ctx, s.bus, req.TaskId, params); err != nil {
 			return &pb.SignalTaskResponse{
 				Success: false,
-				Error: &pb.Error{
-					Code:    "SIGNAL_STEER_FAILED",
-					Message: err.Error(),
-				},
+				Error:   newProtoError("SIGNAL_STEER_FAILED", err),
 			}, nil
 		}
 		return &pb.SignalTaskResponse{Success: true}, nil
@@ -71,10 +70,7 @@ func (s *SignalServiceServer) SignalTask(ctx context.Context, req *pb.SignalTask
 		if err := signal.SendInterrupt(ctx, s.bus, req.TaskId, req.Graceful, req.Reason, timeout); err != nil {
 			return &pb.SignalTaskResponse{
 				Success: false,
-				Error: &pb.Error{
-					Code:    "SIGNAL_INTERRUPT_FAILED",
-					Message: err.Error(),
-				},
+				Error:   newProtoError("SIGNAL_INTERRUPT_FAILED", err),
 			}, nil
 		}
 		return &pb.SignalTaskResponse{Success: true}, nil
@@ -108,10 +104,7 @@ func (s *SignalServiceServer) SignalTask(ctx context.Context, req *pb.SignalTask
 			})
 		}
 		if err != nil {
-			resp.Error = &pb.Error{
-				Code:    "COLLECT_INCOMPLETE",
-				Message: err.Error(),
-			}
+			resp.Error = newProtoError("COLLECT_INCOMPLETE", err)
 		}
 		return resp, nil
 
@@ -119,3 +112,136 @@ func (s *SignalServiceServer) SignalTask(ctx context.Context, req *pb.SignalTask
 		return nil, status.Error(codes.InvalidArgument, "unknown signal type")
 	}
 }
+
+// AckSignal acknowledges a signal delivered by an at-least-once bus.
+func (s *SignalServiceServer) AckSignal(ctx context.Context, req *pb.AckSignalRequest) (*pb.AckSignalResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	if req.TaskId == "" || req.DeliveryId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id and delivery_id are required")
+	}
+
+	ackBus, ok := signal.AsAckBus(s.bus)
+	if !ok {
+		return &pb.AckSignalResponse{
+			Success: false,
+			Error:   newProtoError("SIGNAL_ACK_FAILED", signal.ErrAckDisabled),
+		}, nil
+	}
+	if err := ackBus.Ack(ctx, req.TaskId, req.DeliveryId); err != nil {
+		return &pb.AckSignalResponse{
+			Success: false,
+			Error:   newProtoError("SIGNAL_ACK_FAILED", err),
+		}, nil
+	}
+	return &pb.AckSignalResponse{Success: true}, nil
+}
+
+// ListPendingSignals lists the unacknowledged signals of a task. Buses that
+// deliver at most once never have pending signals.
+func (s *SignalServiceServer) ListPendingSignals(ctx context.Context, req *pb.ListPendingSignalsRequest) (*pb.ListPendingSignalsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+
+	ackBus, ok := signal.AsAckBus(s.bus)
+	if !ok {
+		return &pb.ListPendingSignalsResponse{}, nil
+	}
+	pending, err := ackBus.Pending(ctx, req.TaskId)
+	if err != nil {
+		return &pb.ListPendingSignalsResponse{
+			Error: newProtoError("SIGNAL_PENDING_FAILED", err),
+		}, nil
+	}
+
+	resp := &pb.ListPendingSignalsResponse{Signals: make([]*pb.PendingSignal, 0, len(pending))}
+	for _, p := range pending {
+		resp.Signals = append(resp.Signals, &pb.PendingSignal{
+			DeliveryId: p.DeliveryID,
+			TaskId:     p.TaskID,
+			Consumer:   p.Consumer,
+			IdleMs:     p.Idle.Milliseconds(),
+			Deliveries: p.Deliveries,
+		})
+	}
+	return resp, nil
+}
+
+// GetDeliveryStats returns the signal bus delivery counters.
+func (s *SignalServiceServer) GetDeliveryStats(_ context.Context, req *pb.GetDeliveryStatsRequest) (*pb.GetDeliveryStatsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ackBus, ok := signal.AsAckBus(s.bus)
+	if !ok {
+		return &pb.GetDeliveryStatsResponse{}, nil
+	}
+	stats := ackBus.DeliveryStats()
+	return &pb.GetDeliveryStatsResponse{
+		AckEnabled:   stats.AckEnabled,
+		Published:    stats.Published,
+		Delivered:    stats.Delivered,
+		Acked:        stats.Acked,
+		Redelivered:  stats.Redelivered,
+		DeadLettered: stats.DeadLettered,
+	}, nil
+}
+
+// ReplaySignals streams the recorded history of a channel, oldest first.
+func (s *SignalServiceServer) ReplaySignals(req *pb.ReplaySignalsRequest, stream grpc.ServerStreamingServer[pb.SignalRecord]) error {
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	if req.Channel == "" {
+		return status.Error(codes.InvalidArgument, "channel is required")
+	}
+	if req.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+
+	history := signal.HistoryOf(s.bus)
+	if history == nil {
+		return status.Error(codes.FailedPrecondition, "signal history is not enabled")
+	}
+
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+	signals, err := history.List(stream.Context(), req.Channel, since, int(req.Limit))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read signal history: %v", err)
+	}
+
+	for _, sig := range signals {
+		if err := stream.Send(&pb.SignalRecord{
+			Type:    convertToProtoSignalType(sig.Type),
+			TaskId:  sig.TaskID,
+			Payload: sig.Payload,
+			SentAt:  timestamppb.New(sig.SentAt),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertToProtoSignalType converts a signal type to its proto enum.
+func convertToProtoSignalType(t signal.SignalType) pb.SignalType {
+	switch t {
+	case signal.SignalSteer:
+		return pb.SignalType_SIGNAL_TYPE_STEER
+	case signal.SignalInterrupt:
+		return pb.SignalType_SIGNAL_TYPE_INTERRUPT
+	case signal.SignalCollect:
+		return pb.SignalType_SIGNAL_TYPE_COLLECT
+	default:
+		return pb.SignalType_SIGNAL_TYPE_UNSPECIFIED
+	}
+}
@@ -8,6 +8,10 @@ import (
 
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"github.com/goclaw/goclaw/pkg/signal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestSignalServiceServer_SignalTask_Steer(t *testing.T) {
@@ -79,3 +83,156 @@ func TestSignalServiceServer_SignalTask_Collect(t *testing.T) {
 		t.Fatalf("expected %d results, got %d", len(taskIDs), len(resp.Results))
 	}
 }
+
+type fakeAckBus struct {
+	*signal.LocalBus
+	acked []string
+}
+
+func (b *fakeAckBus) Ack(_ context.Context, taskID, deliveryID string) error {
+	if deliveryID == "missing" {
+		return signal.ErrNotPending
+	}
+	b.acked = append(b.acked, taskID+"/"+deliveryID)
+	return nil
+}
+
+func (b *fakeAckBus) Pending(_ context.Context, taskID string) ([]signal.PendingSignal, error) {
+	return []signal.PendingSignal{{DeliveryID: "1-0", TaskID: taskID, Consumer: "node-a", Idle: 1500 * time.Millisecond, Deliveries: 2}}, nil
+}
+
+func (b *fakeAckBus) DeliveryStats() signal.DeliveryStats {
+	return signal.DeliveryStats{AckEnabled: true, Published: 3, Delivered: 4, Acked: 2, Redelivered: 1}
+}
+
+func TestSignalServiceServer_AckAndPending(t *testing.T) {
+	bus := &fakeAckBus{LocalBus: signal.NewLocalBus(16)}
+	defer bus.Close()
+	server := NewSignalServiceServer(bus)
+
+	if _, err := server.AckSignal(context.Background(), &pb.AckSignalRequest{TaskId: "task-1"}); err == nil {
+		t.Fatal("expected invalid argument without delivery_id")
+	}
+
+	resp, err := server.AckSignal(context.Background(), &pb.AckSignalRequest{TaskId: "task-1", DeliveryId: "1-0"})
+	if err != nil || !resp.Success {
+		t.Fatalf("AckSignal: resp=%v err=%v", resp, err)
+	}
+	if len(bus.acked) != 1 || bus.acked[0] != "task-1/1-0" {
+		t.Fatalf("unexpected acks: %v", bus.acked)
+	}
+
+	resp, err = server.AckSignal(context.Background(), &pb.AckSignalRequest{TaskId: "task-1", DeliveryId: "missing"})
+	if err != nil {
+		t.Fatalf("AckSignal: %v", err)
+	}
+	if resp.Success || resp.Error == nil || resp.Error.Details["error_code"] != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND error, got %+v", resp)
+	}
+
+	pending, err := server.ListPendingSignals(context.Background(), &pb.ListPendingSignalsRequest{TaskId: "task-1"})
+	if err != nil {
+		t.Fatalf("ListPendingSignals: %v", err)
+	}
+	if len(pending.Signals) != 1 || pending.Signals[0].IdleMs != 1500 || pending.Signals[0].Deliveries != 2 {
+		t.Fatalf("unexpected pending signals: %+v", pending.Signals)
+	}
+
+	stats, err := server.GetDeliveryStats(context.Background(), &pb.GetDeliveryStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetDeliveryStats: %v", err)
+	}
+	if !stats.AckEnabled || stats.Published != 3 || stats.Redelivered != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSignalServiceServer_AckUnsupportedBus(t *testing.T) {
+	bus := signal.NewLocalBus(16)
+	defer bus.Close()
+	server := NewSignalServiceServer(bus)
+
+	resp, err := server.AckSignal(context.Background(), &pb.AckSignalRequest{TaskId: "task-1", DeliveryId: "1-0"})
+	if err != nil {
+		t.Fatalf("AckSignal: %v", err)
+	}
+	if resp.Success || resp.Error == nil || resp.Error.Details["error_code"] != "NOT_IMPLEMENTED" {
+		t.Fatalf("expected NOT_IMPLEMENTED error, got %+v", resp)
+	}
+
+	pending, err := server.ListPendingSignals(context.Background(), &pb.ListPendingSignalsRequest{TaskId: "task-1"})
+	if err != nil || len(pending.Signals) != 0 {
+		t.Fatalf("expected no pending signals, got %+v, %v", pending, err)
+	}
+
+	stats, err := server.GetDeliveryStats(context.Background(), &pb.GetDeliveryStatsRequest{})
+	if err != nil || stats.AckEnabled {
+		t.Fatalf("expected ack disabled stats, got %+v, %v", stats, err)
+	}
+}
+
+type mockReplayStream struct {
+	ctx     context.Context
+	records []*pb.SignalRecord
+}
+
+func (m *mockReplayStream) Send(record *pb.SignalRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *mockReplayStream) Context() context.Context        { return m.ctx }
+func (m *mockReplayStream) SetHeader(md metadata.MD) error  { return nil }
+func (m *mockReplayStream) SendHeader(md metadata.MD) error { return nil }
+func (m *mockReplayStream) SetTrailer(md metadata.MD)       {}
+func (m *mockReplayStream) SendMsg(msg interface{}) error   { return nil }
+func (m *mockReplayStream) RecvMsg(msg interface{}) error   { return nil }
+
+func TestSignalServiceServer_ReplaySignals(t *testing.T) {
+	history := signal.NewMemoryHistory(10, 10)
+	bus := signal.NewHistoryBus(signal.NewLocalBus(16), history)
+	defer bus.Close()
+	server := NewSignalServiceServer(bus)
+
+	start := time.Now()
+	if err := signal.SendSteer(context.Background(), bus, "task-1", map[string]interface{}{"rate": 1}); err != nil {
+		t.Fatalf("SendSteer: %v", err)
+	}
+	if err := signal.SendInterrupt(context.Background(), bus, "task-1", true, "done", time.Second); err != nil {
+		t.Fatalf("SendInterrupt: %v", err)
+	}
+
+	stream := &mockReplayStream{ctx: context.Background()}
+	if err := server.ReplaySignals(&pb.ReplaySignalsRequest{Channel: "task-1", Since: timestamppb.New(start)}, stream); err != nil {
+		t.Fatalf("ReplaySignals: %v", err)
+	}
+	if len(stream.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(stream.records))
+	}
+	if stream.records[0].Type != pb.SignalType_SIGNAL_TYPE_STEER || stream.records[1].Type != pb.SignalType_SIGNAL_TYPE_INTERRUPT {
+		t.Fatalf("unexpected record order: %v, %v", stream.records[0].Type, stream.records[1].Type)
+	}
+
+	stream = &mockReplayStream{ctx: context.Background()}
+	if err := server.ReplaySignals(&pb.ReplaySignalsRequest{Channel: "task-1", Limit: 1}, stream); err != nil {
+		t.Fatalf("ReplaySignals: %v", err)
+	}
+	if len(stream.records) != 1 || stream.records[0].Type != pb.SignalType_SIGNAL_TYPE_INTERRUPT {
+		t.Fatalf("expected newest record only, got %+v", stream.records)
+	}
+}
+
+func TestSignalServiceServer_ReplaySignals_HistoryDisabled(t *testing.T) {
+	bus := signal.NewLocalBus(16)
+	defer bus.Close()
+	server := NewSignalServiceServer(bus)
+
+	err := server.ReplaySignals(&pb.ReplaySignalsRequest{Channel: "task-1"}, &mockReplayStream{ctx: context.Background()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+	err = server.ReplaySignals(&pb.ReplaySignalsRequest{}, &mockReplayStream{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
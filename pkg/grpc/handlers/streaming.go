@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/goclaw/goclaw/pkg/engine"
+	"github.com/goclaw/goclaw/pkg/errs"
 	"github.com/goclaw/goclaw/pkg/eventbus"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"github.com/goclaw/goclaw/pkg/grpc/streaming"
@@ -13,6 +14,10 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// shutdownMessage is the message of the final update sent on streams that are
+// drained by server shutdown
+const shutdownMessage = "server shutting down"
+
 // StreamingServiceServer implements the gRPC StreamingService
 type StreamingServiceServer struct {
 	pb.UnimplementedStreamingServiceServer
@@ -42,6 +47,12 @@ func (s *StreamingServiceServer) AttachEventBusBridge(bus *eventbus.MemoryBus, r
 	return nil
 }
 
+// Drain implements grpc.Drainer. Open streams flush their buffered events,
+// send a final "server shutting down" update and end.
+func (s *StreamingServiceServer) Drain() {
+	s.registry.Shutdown()
+}
+
 // Close releases bridge resources.
 func (s *StreamingServiceServer) Close() error {
 	if s.bridge == nil {
@@ -75,6 +86,32 @@ func (s *StreamingServiceServer) WatchWorkflow(req *pb.WatchWorkflowRequest, str
 		return status.Errorf(codes.Internal, "failed to send initial update: %v", err)
 	}
 
+	// send converts a buffered event and sends it to the client
+	send := func(event interface{}) error {
+		seqEvent, ok := event.(*streaming.SequencedEvent)
+		if !ok {
+			return nil
+		}
+
+		// Skip events before resume point
+		if req.ResumeFromSequence > 0 && seqEvent.Sequence <= req.ResumeFromSequence {
+			return nil
+		}
+
+		update, err := s.convertWorkflowEvent(seqEvent)
+		if err != nil {
+			return nil // Skip invalid events
+		}
+
+		if err := stream.Send(update); err != nil {
+			return status.Errorf(codes.Internal, "failed to send update: %v", err)
+		}
+
+		// Update last sequence
+		sub.LastSequence = seqEvent.Sequence
+		return nil
+	}
+
 	// Stream events
 	for {
 		select {
@@ -84,33 +121,28 @@ func (s *StreamingServiceServer) WatchWorkflow(req *pb.WatchWorkflowRequest, str
 		case err := <-sub.ErrorChan:
 			return status.Errorf(codes.Internal, "stream error: %v", err)
 
-		case event, ok := <-sub.EventChan:
-			if !ok {
-				return status.Error(codes.Aborted, "event channel closed")
+		case <-s.registry.ShuttingDown():
+			if err := drainEvents(sub, send); err != nil {
+				return err
 			}
-
-			// Convert event to proto message
-			seqEvent, ok := event.(*streaming.SequencedEvent)
-			if !ok {
-				continue
-			}
-
-			// Skip events before resume point
-			if req.ResumeFromSequence > 0 && seqEvent.Sequence <= req.ResumeFromSequence {
-				continue
+			if err := stream.Send(&pb.WorkflowStatusUpdate{
+				SequenceNumber: sub.LastSequence,
+				Timestamp:      timestamppb.Now(),
+				WorkflowId:     req.WorkflowId,
+				Message:        shutdownMessage,
+				Error:          shutdownError(),
+			}); err != nil {
+				return status.Errorf(codes.Internal, "failed to send final update: %v", err)
 			}
+			return nil
 
-			update, err := s.convertWorkflowEvent(seqEvent)
-			if err != nil {
-				continue // Skip invalid events
+		case event, ok := <-sub.EventChan:
+			if !ok {
+				return status.Error(codes.Aborted, "event channel closed")
 			}
-
-			if err := stream.Send(update); err != nil {
-				return status.Errorf(codes.Internal, "failed to send update: %v", err)
+			if err := send(event); err != nil {
+				return err
 			}
-
-			// Update last sequence
-			sub.LastSequence = seqEvent.Sequence
 		}
 	}
 }
@@ -137,6 +169,45 @@ func (s *StreamingServiceServer) WatchTasks(req *pb.WatchTasksRequest, stream pb
 		}
 	}
 
+	// send converts a buffered event and sends it to the client if it
+	// passes the filters
+	send := func(event interface{}) error {
+		seqEvent, ok := event.(*streaming.SequencedEvent)
+		if !ok {
+			return nil
+		}
+
+		// Skip events before resume point
+		if req.ResumeFromSequence > 0 && seqEvent.Sequence <= req.ResumeFromSequence {
+			return nil
+		}
+
+		// Only process task events
+		taskEvent, ok := seqEvent.Event.(engine.TaskEvent)
+		if !ok {
+			return nil
+		}
+
+		// Apply task filter
+		if len(taskFilter) > 0 && !taskFilter[taskEvent.TaskID] {
+			return nil
+		}
+
+		// Apply terminal-only filter
+		if req.TerminalOnly && !isTerminalTaskEvent(taskEvent.EventType) {
+			return nil
+		}
+
+		update := s.convertTaskEvent(seqEvent.Sequence, taskEvent)
+		if err := stream.Send(update); err != nil {
+			return status.Errorf(codes.Internal, "failed to send update: %v", err)
+		}
+
+		// Update last sequence
+		sub.LastSequence = seqEvent.Sequence
+		return nil
+	}
+
 	// Stream events
 	for {
 		select {
@@ -146,45 +217,28 @@ func (s *StreamingServiceServer) WatchTasks(req *pb.WatchTasksRequest, stream pb
 		case err := <-sub.ErrorChan:
 			return status.Errorf(codes.Internal, "stream error: %v", err)
 
-		case event, ok := <-sub.EventChan:
-			if !ok {
-				return status.Error(codes.Aborted, "event channel closed")
+		case <-s.registry.ShuttingDown():
+			if err := drainEvents(sub, send); err != nil {
+				return err
 			}
-
-			// Convert event to proto message
-			seqEvent, ok := event.(*streaming.SequencedEvent)
-			if !ok {
-				continue
-			}
-
-			// Skip events before resume point
-			if req.ResumeFromSequence > 0 && seqEvent.Sequence <= req.ResumeFromSequence {
-				continue
+			if err := stream.Send(&pb.TaskProgressUpdate{
+				SequenceNumber: sub.LastSequence,
+				Timestamp:      timestamppb.Now(),
+				WorkflowId:     req.WorkflowId,
+				Message:        shutdownMessage,
+				Error:          shutdownError(),
+			}); err != nil {
+				return status.Errorf(codes.Internal, "failed to send final update: %v", err)
 			}
+			return nil
 
-			// Only process task events
-			taskEvent, ok := seqEvent.Event.(engine.TaskEvent)
+		case event, ok := <-sub.EventChan:
 			if !ok {
-				continue
-			}
-
-			// Apply task filter
-			if len(taskFilter) > 0 && !taskFilter[taskEvent.TaskID] {
-				continue
-			}
-
-			// Apply terminal-only filter
-			if req.TerminalOnly && !isTerminalTaskEvent(taskEvent.EventType) {
-				continue
+				return status.Error(codes.Aborted, "event channel closed")
 			}
-
-			update := s.convertTaskEvent(seqEvent.Sequence, taskEvent)
-			if err := stream.Send(update); err != nil {
-				return status.Errorf(codes.Internal, "failed to send update: %v", err)
+			if err := send(event); err != nil {
+				return err
 			}
-
-			// Update last sequence
-			sub.LastSequence = seqEvent.Sequence
 		}
 	}
 }
@@ -243,6 +297,24 @@ func (s *StreamingServiceServer) StreamLogs(stream pb.StreamingService_StreamLog
 		}
 	}()
 
+	// send converts a buffered event to log entries and sends them
+	send := func(event interface{}) error {
+		seqEvent, ok := event.(*streaming.SequencedEvent)
+		if !ok {
+			return nil
+		}
+
+		logEntries := s.convertToLogEntries(seqEvent, taskFilter, minLevel)
+		if len(logEntries) == 0 {
+			return nil
+		}
+
+		if err := stream.Send(&pb.LogStreamResponse{Entries: logEntries}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send logs: %v", err)
+		}
+		return nil
+	}
+
 	// Stream log entries
 	for {
 		select {
@@ -255,33 +327,54 @@ func (s *StreamingServiceServer) StreamLogs(stream pb.StreamingService_StreamLog
 		case err := <-sub.ErrorChan:
 			return status.Errorf(codes.Internal, "stream error: %v", err)
 
-		case event, ok := <-sub.EventChan:
-			if !ok {
-				return status.Error(codes.Aborted, "event channel closed")
+		case <-s.registry.ShuttingDown():
+			if err := drainEvents(sub, send); err != nil {
+				return err
 			}
+			if err := stream.Send(&pb.LogStreamResponse{Error: shutdownError()}); err != nil {
+				return status.Errorf(codes.Internal, "failed to send final update: %v", err)
+			}
+			return nil
 
-			// Convert event to log entries
-			seqEvent, ok := event.(*streaming.SequencedEvent)
+		case event, ok := <-sub.EventChan:
 			if !ok {
-				continue
+				return status.Error(codes.Aborted, "event channel closed")
 			}
-
-			logEntries := s.convertToLogEntries(seqEvent, taskFilter, minLevel)
-			if len(logEntries) == 0 {
-				continue
+			if err := send(event); err != nil {
+				return err
 			}
+		}
+	}
+}
 
-			response := &pb.LogStreamResponse{
-				Entries: logEntries,
+// drainEvents passes the events already buffered for sub to send, without
+// waiting for more
+func drainEvents(sub *streaming.Subscriber, send func(interface{}) error) error {
+	for {
+		select {
+		case event, ok := <-sub.EventChan:
+			if !ok {
+				return nil
 			}
-
-			if err := stream.Send(response); err != nil {
-				return status.Errorf(codes.Internal, "failed to send logs: %v", err)
+			if err := send(event); err != nil {
+				return err
 			}
+		default:
+			return nil
 		}
 	}
 }
 
+// shutdownError is attached to the final update of a stream ended by server
+// shutdown. Clients should reconnect, resuming from the update's sequence
+// number.
+func shutdownError() *pb.Error {
+	return &pb.Error{
+		Code:    string(errs.ServiceUnavailable),
+		Message: shutdownMessage,
+	}
+}
+
 // convertWorkflowEvent converts engine workflow event to proto message
 func (s *StreamingServiceServer) convertWorkflowEvent(seqEvent *streaming.SequencedEvent) (*pb.WorkflowStatusUpdate, error) {
 	workflowEvent, ok := seqEvent.Event.(engine.WorkflowEvent)
@@ -295,6 +388,7 @@ func (s *StreamingServiceServer) convertWorkflowEvent(seqEvent *streaming.Sequen
 		WorkflowId:     workflowEvent.WorkflowID,
 		Status:         convertWorkflowEventTypeToStatus(workflowEvent.EventType),
 		Message:        workflowEvent.Message,
+		RequestId:      workflowEvent.RequestID,
 	}
 
 	return update, nil
@@ -310,6 +404,9 @@ func (s *StreamingServiceServer) convertTaskEvent(sequence int64, taskEvent engi
 		Status:          convertTaskEventTypeToStatus(taskEvent.EventType),
 		ProgressPercent: int32(taskEvent.Progress),
 		Message:         taskEvent.Message,
+		RequestId:       taskEvent.RequestID,
+		OutputChunk:     taskEvent.Chunk,
+		OutputSeq:       taskEvent.ChunkSeq,
 	}
 }
 
@@ -339,6 +436,7 @@ func (s *StreamingServiceServer) convertToLogEntries(seqEvent *streaming.Sequenc
 			Level:      level,
 			WorkflowId: event.WorkflowID,
 			Message:    fmt.Sprintf("[Workflow] %s: %s", event.Status, event.Message),
+			Fields:     requestIDFields(event.RequestID),
 		})
 
 	case engine.TaskEvent:
@@ -346,6 +444,10 @@ func (s *StreamingServiceServer) convertToLogEntries(seqEvent *streaming.Sequenc
 		if len(taskFilter) > 0 && !taskFilter[event.TaskID] {
 			return nil
 		}
+		// Streamed output is not logged; WatchTasks carries it.
+		if event.Chunk != "" {
+			return nil
+		}
 
 		level := pb.LogLevel_LOG_LEVEL_INFO
 		if event.EventType == engine.TaskEventFailed {
@@ -363,12 +465,22 @@ func (s *StreamingServiceServer) convertToLogEntries(seqEvent *streaming.Sequenc
 			WorkflowId: event.WorkflowID,
 			TaskId:     event.TaskID,
 			Message:    fmt.Sprintf("[Task %s] %s: %s", event.TaskID, event.Status, event.Message),
+			Fields:     requestIDFields(event.RequestID),
 		})
 	}
 
 	return entries
 }
 
+// requestIDFields returns the log entry fields carrying requestID, or nil
+// for events without one.
+func requestIDFields(requestID string) map[string]string {
+	if requestID == "" {
+		return nil
+	}
+	return map[string]string{"request_id": requestID}
+}
+
 // convertWorkflowEventTypeToStatus converts workflow event type to proto status
 func convertWorkflowEventTypeToStatus(eventType engine.WorkflowEventType) pb.WorkflowStatus {
 	switch eventType {
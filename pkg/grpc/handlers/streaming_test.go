@@ -221,6 +221,54 @@ func TestWatchWorkflow(t *testing.T) {
 	}
 }
 
+func TestWatchWorkflow_DrainsOnShutdown(t *testing.T) {
+	registry := streaming.NewSubscriberRegistry()
+	server := NewStreamingServiceServer(registry)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream := &mockWatchWorkflowStream{ctx: ctx}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.WatchWorkflow(&pb.WatchWorkflowRequest{WorkflowId: "wf-123"}, stream)
+	}()
+	require.Eventually(t, func() bool { return registry.GetSubscriberCount() == 1 }, time.Second, time.Millisecond)
+
+	server.observer.OnWorkflowEvent(engine.WorkflowEvent{
+		WorkflowID: "wf-123",
+		EventType:  engine.WorkflowEventStarted,
+		Message:    "Workflow started",
+		Timestamp:  time.Now().Unix(),
+	})
+	server.Drain()
+
+	require.NoError(t, <-errChan)
+	require.Len(t, stream.updates, 3) // initial + buffered event + final
+	assert.Equal(t, "Workflow started", stream.updates[1].Message)
+	final := stream.updates[2]
+	assert.Equal(t, "server shutting down", final.Message)
+	require.NotNil(t, final.Error)
+	assert.Equal(t, "SERVICE_UNAVAILABLE", final.Error.Code)
+	assert.Equal(t, stream.updates[1].SequenceNumber, final.SequenceNumber)
+}
+
+func TestWatchTasks_DrainsOnShutdown(t *testing.T) {
+	registry := streaming.NewSubscriberRegistry()
+	server := NewStreamingServiceServer(registry)
+	server.Drain()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream := &mockWatchTasksStream{ctx: ctx}
+
+	// Streams opened after shutdown began end right away.
+	require.NoError(t, server.WatchTasks(&pb.WatchTasksRequest{WorkflowId: "wf-123"}, stream))
+	require.Len(t, stream.updates, 1)
+	assert.Equal(t, "server shutting down", stream.updates[0].Message)
+	require.NotNil(t, stream.updates[0].Error)
+}
+
 func TestWatchTasks(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -600,3 +648,26 @@ func TestIsTerminalTaskEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamedTaskOutput(t *testing.T) {
+	server := NewStreamingServiceServer(streaming.NewSubscriberRegistry())
+	event := engine.TaskEvent{
+		WorkflowID: "wf-123",
+		TaskID:     "task-1",
+		EventType:  engine.TaskEventProgress,
+		Status:     "RUNNING",
+		Message:    "task output",
+		Timestamp:  time.Now().Unix(),
+		Chunk:      "Hello",
+		ChunkSeq:   2,
+	}
+
+	update := server.convertTaskEvent(7, event)
+	assert.Equal(t, "Hello", update.OutputChunk)
+	assert.Equal(t, int64(2), update.OutputSeq)
+	assert.Equal(t, pb.TaskStatus_TASK_STATUS_RUNNING, update.Status)
+
+	// Streamed output goes to WatchTasks, not the log stream.
+	entries := server.convertToLogEntries(&streaming.SequencedEvent{Sequence: 7, Event: event}, nil, pb.LogLevel_LOG_LEVEL_UNSPECIFIED)
+	assert.Empty(t, entries)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 
+	"github.com/goclaw/goclaw/pkg/errs"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -17,6 +19,21 @@ type WorkflowEngine interface {
 	GetTaskResult(ctx context.Context, workflowID, taskID string) (*TaskResult, error)
 }
 
+// WorkflowStreamer is implemented by engines that can stream workflow
+// summaries without paging. ListWorkflowsStream walks ListWorkflows pages for
+// engines without it.
+type WorkflowStreamer interface {
+	StreamWorkflows(ctx context.Context, filter WorkflowStreamFilter, fn func(*WorkflowSummary) error) error
+}
+
+// WorkflowStreamFilter represents a workflow stream filter. A zero Limit
+// streams every matching workflow.
+type WorkflowStreamFilter struct {
+	StatusFilter string
+	Limit        int
+	Offset       int
+}
+
 // WorkflowTask represents a task definition
 type WorkflowTask struct {
 	ID           string
@@ -114,10 +131,7 @@ func (s *WorkflowServiceServer) SubmitWorkflow(ctx context.Context, req *pb.Subm
 	workflowID, err := s.engine.SubmitWorkflow(ctx, req.Name, tasks)
 	if err != nil {
 		return &pb.SubmitWorkflowResponse{
-			Error: &pb.Error{
-				Code:    "SUBMISSION_FAILED",
-				Message: err.Error(),
-			},
+			Error: newProtoError("SUBMISSION_FAILED", err),
 		}, nil
 	}
 
@@ -162,23 +176,14 @@ func (s *WorkflowServiceServer) ListWorkflows(ctx context.Context, req *pb.ListW
 	workflows, nextToken, err := s.engine.ListWorkflows(ctx, filter)
 	if err != nil {
 		return &pb.ListWorkflowsResponse{
-			Error: &pb.Error{
-				Code:    "LIST_FAILED",
-				Message: err.Error(),
-			},
+			Error: newProtoError("LIST_FAILED", err),
 		}, nil
 	}
 
 	// Convert to proto format
 	pbWorkflows := make([]*pb.WorkflowSummary, len(workflows))
 	for i, w := range workflows {
-		pbWorkflows[i] = &pb.WorkflowSummary{
-			WorkflowId: w.WorkflowID,
-			Name:       w.Name,
-			Status:     convertToProtoStatus(w.Status),
-			CreatedAt:  timestampFromUnix(w.CreatedAt),
-			UpdatedAt:  timestampFromUnix(w.UpdatedAt),
-		}
+		pbWorkflows[i] = toProtoWorkflowSummary(w)
 	}
 
 	return &pb.ListWorkflowsResponse{
@@ -190,42 +195,137 @@ func (s *WorkflowServiceServer) ListWorkflows(ctx context.Context, req *pb.ListW
 	}, nil
 }
 
+// ListWorkflowsStream streams the summaries of all workflows matching the
+// request, so that clients can iterate large result sets without the server
+// building pages and totals.
+func (s *WorkflowServiceServer) ListWorkflowsStream(req *pb.ListWorkflowsStreamRequest, stream grpc.ServerStreamingServer[pb.WorkflowSummary]) error {
+	if req == nil {
+		req = &pb.ListWorkflowsStreamRequest{}
+	}
+	if req.Limit < 0 || req.Offset < 0 {
+		return status.Error(codes.InvalidArgument, "limit and offset cannot be negative")
+	}
+
+	filter := WorkflowStreamFilter{
+		Limit:  int(req.Limit),
+		Offset: int(req.Offset),
+	}
+	if req.StatusFilter != pb.WorkflowStatus_WORKFLOW_STATUS_UNSPECIFIED {
+		filter.StatusFilter = normalizeWorkflowFilterStatus(req.StatusFilter.String())
+	}
+
+	var sendErr error
+	send := func(w *WorkflowSummary) error {
+		sendErr = stream.Send(toProtoWorkflowSummary(w))
+		return sendErr
+	}
+
+	var err error
+	if streamer, ok := s.engine.(WorkflowStreamer); ok {
+		err = streamer.StreamWorkflows(stream.Context(), filter, send)
+	} else {
+		err = s.pageWorkflows(stream.Context(), filter, send)
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return errs.ToGRPC(err)
+	}
+	return nil
+}
+
+// pageWorkflows streams workflows by walking ListWorkflows pages.
+func (s *WorkflowServiceServer) pageWorkflows(ctx context.Context, filter WorkflowStreamFilter, fn func(*WorkflowSummary) error) error {
+	skip, remaining := filter.Offset, filter.Limit
+	pageToken := ""
+	for {
+		workflows, nextToken, err := s.engine.ListWorkflows(ctx, WorkflowFilter{
+			StatusFilter: filter.StatusFilter,
+			PageSize:     1000,
+			PageToken:    pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, w := range workflows {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if err := fn(w); err != nil {
+				return err
+			}
+			if remaining--; remaining == 0 {
+				return nil
+			}
+		}
+		if nextToken == "" {
+			return nil
+		}
+		pageToken = nextToken
+	}
+}
+
+func toProtoWorkflowSummary(w *WorkflowSummary) *pb.WorkflowSummary {
+	return &pb.WorkflowSummary{
+		WorkflowId: w.WorkflowID,
+		Name:       w.Name,
+		Status:     convertToProtoStatus(w.Status),
+		CreatedAt:  timestampFromUnix(w.CreatedAt),
+		UpdatedAt:  timestampFromUnix(w.UpdatedAt),
+	}
+}
+
 // GetWorkflowStatus handles workflow status retrieval
 func (s *WorkflowServiceServer) GetWorkflowStatus(ctx context.Context, req *pb.GetWorkflowStatusRequest) (*pb.GetWorkflowStatusResponse, error) {
 	if req == nil || req.WorkflowId == "" {
 		return nil, status.Error(codes.InvalidArgument, "workflow_id is required")
 	}
 
+	mask, err := newStatusMask(req.FieldMask)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get status from engine
 	ws, err := s.engine.GetWorkflowStatus(ctx, req.WorkflowId)
 	if err != nil {
-		if IsNotFoundError(err) {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 
-	// Convert tasks
-	pbTasks := make([]*pb.TaskStatusDetail, len(ws.Tasks))
-	for i, t := range ws.Tasks {
-		pbTasks[i] = &pb.TaskStatusDetail{
-			TaskId:       t.TaskID,
-			Name:         t.Name,
-			Status:       convertToProtoTaskStatus(t.Status),
-			StartedAt:    timestampFromUnix(t.StartedAt),
-			CompletedAt:  timestampFromUnix(t.CompletedAt),
-			ErrorMessage: t.ErrorMsg,
+	return toProtoWorkflowStatus(ws, mask), nil
+}
+
+// toProtoWorkflowStatus converts a workflow status to its response, keeping
+// only the fields selected by mask. Task details are not converted at all
+// when the mask leaves them out.
+func toProtoWorkflowStatus(ws *WorkflowStatus, mask *statusMask) *pb.GetWorkflowStatusResponse {
+	var pbTasks []*pb.TaskStatusDetail
+	if mask.includes("tasks") {
+		pbTasks = make([]*pb.TaskStatusDetail, len(ws.Tasks))
+		for i, t := range ws.Tasks {
+			pbTasks[i] = &pb.TaskStatusDetail{
+				TaskId:       t.TaskID,
+				Name:         t.Name,
+				Status:       convertToProtoTaskStatus(t.Status),
+				StartedAt:    timestampFromUnix(t.StartedAt),
+				CompletedAt:  timestampFromUnix(t.CompletedAt),
+				ErrorMessage: t.ErrorMsg,
+			}
 		}
 	}
 
-	return &pb.GetWorkflowStatusResponse{
+	resp := &pb.GetWorkflowStatusResponse{
 		WorkflowId: ws.WorkflowID,
 		Name:       ws.Name,
 		Status:     convertToProtoStatus(ws.Status),
 		Tasks:      pbTasks,
 		CreatedAt:  timestampFromUnix(ws.CreatedAt),
 		UpdatedAt:  timestampFromUnix(ws.UpdatedAt),
-	}, nil
+	}
+	mask.apply(resp)
+	return resp
 }
 
 // CancelWorkflow handles workflow cancellation
@@ -257,10 +357,7 @@ func (s *WorkflowServiceServer) GetTaskResult(ctx context.Context, req *pb.GetTa
 	// Get task result from engine
 	result, err := s.engine.GetTaskResult(ctx, req.WorkflowId, req.TaskId)
 	if err != nil {
-		if IsNotFoundError(err) {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errs.ToGRPC(err)
 	}
 
 	return &pb.GetTaskResultResponse{
@@ -7,7 +7,9 @@ import (
 
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // MockWorkflowEngine is a mock implementation of WorkflowEngine for testing
@@ -262,6 +264,52 @@ func TestGetWorkflowStatus_MissingID(t *testing.T) {
 	}
 }
 
+func TestGetWorkflowStatus_FieldMask(t *testing.T) {
+	engine := &MockWorkflowEngine{
+		GetWorkflowStatusFunc: func(ctx context.Context, workflowID string) (*WorkflowStatus, error) {
+			return &WorkflowStatus{
+				WorkflowID: workflowID,
+				Name:       "test-workflow",
+				Status:     "RUNNING",
+				Tasks:      []*TaskStatus{{TaskID: "task-1", Status: "RUNNING"}},
+				CreatedAt:  1234567890,
+				UpdatedAt:  1234567890,
+			}, nil
+		},
+	}
+	server := NewWorkflowServiceServer(engine)
+
+	resp, err := server.GetWorkflowStatus(context.Background(), &pb.GetWorkflowStatusRequest{
+		WorkflowId: "workflow-123",
+		FieldMask:  &fieldmaskpb.FieldMask{Paths: []string{"status", "updated_at.seconds"}},
+	})
+	if err != nil {
+		t.Fatalf("GetWorkflowStatus failed: %v", err)
+	}
+
+	if resp.Status != pb.WorkflowStatus_WORKFLOW_STATUS_RUNNING {
+		t.Errorf("Expected RUNNING status, got %v", resp.Status)
+	}
+	if resp.UpdatedAt.GetSeconds() != 1234567890 {
+		t.Errorf("Expected updated_at seconds to be kept, got %v", resp.UpdatedAt)
+	}
+	if resp.WorkflowId != "" || resp.Name != "" || len(resp.Tasks) != 0 || resp.CreatedAt != nil {
+		t.Errorf("Expected unselected fields to be cleared, got %v", resp)
+	}
+}
+
+func TestGetWorkflowStatus_InvalidFieldMask(t *testing.T) {
+	server := NewWorkflowServiceServer(&MockWorkflowEngine{})
+
+	_, err := server.GetWorkflowStatus(context.Background(), &pb.GetWorkflowStatusRequest{
+		WorkflowId: "workflow-123",
+		FieldMask:  &fieldmaskpb.FieldMask{Paths: []string{"tasks.status"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
+
 func TestCancelWorkflow_Success(t *testing.T) {
 	engine := &MockWorkflowEngine{}
 	server := NewWorkflowServiceServer(engine)
@@ -355,3 +403,109 @@ func TestGetTaskResult_MissingIDs(t *testing.T) {
 		t.Errorf("Expected InvalidArgument, got %v", st.Code())
 	}
 }
+
+type mockWorkflowSummaryStream struct {
+	ctx       context.Context
+	workflows []*pb.WorkflowSummary
+}
+
+func (m *mockWorkflowSummaryStream) Send(w *pb.WorkflowSummary) error {
+	m.workflows = append(m.workflows, w)
+	return nil
+}
+
+func (m *mockWorkflowSummaryStream) Context() context.Context        { return m.ctx }
+func (m *mockWorkflowSummaryStream) SetHeader(md metadata.MD) error  { return nil }
+func (m *mockWorkflowSummaryStream) SendHeader(md metadata.MD) error { return nil }
+func (m *mockWorkflowSummaryStream) SetTrailer(md metadata.MD)       {}
+func (m *mockWorkflowSummaryStream) SendMsg(msg interface{}) error   { return nil }
+func (m *mockWorkflowSummaryStream) RecvMsg(msg interface{}) error   { return nil }
+
+// streamingWorkflowEngine adds WorkflowStreamer to MockWorkflowEngine.
+type streamingWorkflowEngine struct {
+	MockWorkflowEngine
+	filter WorkflowStreamFilter
+	err    error
+}
+
+func (m *streamingWorkflowEngine) StreamWorkflows(ctx context.Context, filter WorkflowStreamFilter, fn func(*WorkflowSummary) error) error {
+	m.filter = filter
+	for _, id := range []string{"workflow-1", "workflow-2"} {
+		if err := fn(&WorkflowSummary{WorkflowID: id, Status: "running"}); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func TestListWorkflowsStream_PagesWithoutStreamer(t *testing.T) {
+	ids := []string{"w-0", "w-1", "w-2", "w-3", "w-4"}
+	var calls int
+	engine := &MockWorkflowEngine{
+		ListWorkflowsFunc: func(ctx context.Context, filter WorkflowFilter) ([]*WorkflowSummary, string, error) {
+			calls++
+			offset := 0
+			if filter.PageToken != "" {
+				offset = int(filter.PageToken[0] - '0')
+			}
+			end := min(offset+2, len(ids))
+			var page []*WorkflowSummary
+			for _, id := range ids[offset:end] {
+				page = append(page, &WorkflowSummary{WorkflowID: id, Status: "COMPLETED"})
+			}
+			next := ""
+			if end < len(ids) {
+				next = string(rune('0' + end))
+			}
+			return page, next, nil
+		},
+	}
+	server := NewWorkflowServiceServer(engine)
+
+	stream := &mockWorkflowSummaryStream{ctx: context.Background()}
+	if err := server.ListWorkflowsStream(&pb.ListWorkflowsStreamRequest{}, stream); err != nil {
+		t.Fatalf("ListWorkflowsStream failed: %v", err)
+	}
+	if len(stream.workflows) != len(ids) || calls != 3 {
+		t.Fatalf("expected %d workflows from 3 pages, got %d from %d", len(ids), len(stream.workflows), calls)
+	}
+
+	stream = &mockWorkflowSummaryStream{ctx: context.Background()}
+	if err := server.ListWorkflowsStream(&pb.ListWorkflowsStreamRequest{Offset: 1, Limit: 3}, stream); err != nil {
+		t.Fatalf("ListWorkflowsStream failed: %v", err)
+	}
+	if len(stream.workflows) != 3 || stream.workflows[0].WorkflowId != "w-1" || stream.workflows[2].WorkflowId != "w-3" {
+		t.Fatalf("expected w-1..w-3, got %v", stream.workflows)
+	}
+	if stream.workflows[0].Status != pb.WorkflowStatus_WORKFLOW_STATUS_COMPLETED {
+		t.Errorf("Expected completed status, got %v", stream.workflows[0].Status)
+	}
+}
+
+func TestListWorkflowsStream_UsesStreamer(t *testing.T) {
+	engine := &streamingWorkflowEngine{}
+	server := NewWorkflowServiceServer(engine)
+
+	stream := &mockWorkflowSummaryStream{ctx: context.Background()}
+	req := &pb.ListWorkflowsStreamRequest{StatusFilter: pb.WorkflowStatus_WORKFLOW_STATUS_RUNNING, Limit: 10}
+	if err := server.ListWorkflowsStream(req, stream); err != nil {
+		t.Fatalf("ListWorkflowsStream failed: %v", err)
+	}
+	if len(stream.workflows) != 2 {
+		t.Fatalf("expected 2 workflows, got %d", len(stream.workflows))
+	}
+	if engine.filter.StatusFilter != "running" || engine.filter.Limit != 10 {
+		t.Errorf("unexpected filter: %+v", engine.filter)
+	}
+
+	engine.err = errors.New("storage failure")
+	err := server.ListWorkflowsStream(req, &mockWorkflowSummaryStream{ctx: context.Background()})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.Internal {
+		t.Errorf("Expected Internal, got %v", err)
+	}
+
+	err = server.ListWorkflowsStream(&pb.ListWorkflowsStreamRequest{Limit: -1}, stream)
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
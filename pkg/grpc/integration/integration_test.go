@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/agent"
 	"github.com/goclaw/goclaw/pkg/grpc/client"
 	"github.com/goclaw/goclaw/pkg/grpc/handlers"
 	pb "github.com/goclaw/goclaw/pkg/grpc/pb/v1"
@@ -28,6 +29,7 @@ type testServer struct {
 	workflowSvc  *handlers.WorkflowServiceServer
 	streamingSvc *handlers.StreamingServiceServer
 	batchSvc     *handlers.BatchServiceServer
+	agents       *agent.Registry
 }
 
 // mockEngine implements a simple engine for testing
@@ -166,6 +168,9 @@ func setupTestServer(t *testing.T) *testServer {
 	batchSvc := handlers.NewBatchServiceServer(mockEng)
 	pb.RegisterBatchServiceServer(grpcServer, batchSvc)
 
+	agents := agent.NewRegistry()
+	pb.RegisterAgentServiceServer(grpcServer, handlers.NewAgentServiceServer(agents))
+
 	// Start server in background
 	go func() {
 		if err := grpcServer.Serve(listener); err != nil {
@@ -184,6 +189,7 @@ func setupTestServer(t *testing.T) *testServer {
 		workflowSvc:  workflowSvc,
 		streamingSvc: streamingSvc,
 		batchSvc:     batchSvc,
+		agents:       agents,
 	}
 }
 
@@ -271,6 +277,15 @@ func TestIntegration_WorkflowOperations(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, listResp)
 		assert.GreaterOrEqual(t, len(listResp.Workflows), 1) // At least the ones we just submitted
+
+		// Stream the same listing
+		streamed := 0
+		err = c.Workflows().Each(ctx, pb.WorkflowStatus_WORKFLOW_STATUS_UNSPECIFIED, func(*pb.WorkflowSummary) error {
+			streamed++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, len(listResp.Workflows), streamed)
 	})
 
 	t.Run("Cancel workflow", func(t *testing.T) {
@@ -416,6 +431,41 @@ func TestIntegration_BatchOperations(t *testing.T) {
 	})
 }
 
+// TestIntegration_AgentOperations tests a remote agent serving assignments
+func TestIntegration_AgentOperations(t *testing.T) {
+	ts := setupTestServer(t)
+	defer ts.teardown()
+
+	c := createTestClient(t, ts.address)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serveCtx, stopServing := context.WithCancel(ctx)
+	serveErr := make(chan error, 1)
+	go func() {
+		reg := &pb.AgentRegistration{AgentId: "worker-1", AgentTypes: []string{"shell"}, Capacity: 2}
+		serveErr <- c.Agents().Serve(serveCtx, reg, func(ctx context.Context, task *pb.TaskAssignment, progress func(float64, string)) ([]byte, error) {
+			progress(50, "halfway")
+			return []byte("ran " + task.TaskId), nil
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, err := c.Agents().List(ctx, "shell")
+		return err == nil && len(resp.Agents) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	data, err := ts.agents.Dispatch(ctx, agent.Assignment{WorkflowID: "wf-1", TaskID: "build", AgentType: "shell"})
+	require.NoError(t, err)
+	assert.Equal(t, "ran build", string(data))
+
+	stopServing()
+	require.NoError(t, <-serveErr)
+	require.Eventually(t, func() bool { return len(ts.agents.Agents()) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
 // Benchmark tests
 func BenchmarkIntegration_SubmitWorkflow(b *testing.B) {
 	ts := setupTestServer(&testing.T{})
@@ -2,7 +2,12 @@ package interceptors
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
 
+	"github.com/goclaw/goclaw/pkg/identity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -14,32 +19,40 @@ const (
 	AuthorizationKey = "authorization"
 )
 
-// AuthenticationUnaryInterceptor validates authentication tokens
-func AuthenticationUnaryInterceptor() grpc.UnaryServerInterceptor {
+// TokenValidator validates an authentication token and returns the ID of the
+// caller it identifies
+type TokenValidator func(token string) (string, error)
+
+// StaticTokenValidator accepts only the given tokens. Callers are identified by
+// a short hash of their token, so that rate limits apply per token without the
+// token itself appearing in logs.
+func StaticTokenValidator(tokens []string) TokenValidator {
+	return func(token string) (string, error) {
+		for _, candidate := range tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				sum := sha256.Sum256([]byte(token))
+				return "token-" + hex.EncodeToString(sum[:4]), nil
+			}
+		}
+		return "", status.Error(codes.Unauthenticated, "unknown token")
+	}
+}
+
+// AuthenticationUnaryInterceptor validates authentication tokens. A nil
+// validator accepts any non-empty token.
+func AuthenticationUnaryInterceptor(validate TokenValidator) grpc.UnaryServerInterceptor {
+	if validate == nil {
+		validate = validateToken
+	}
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Skip authentication for health check
-		if info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if isHealthMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		// Extract token from metadata
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
-		}
-
-		tokens := md.Get(AuthorizationKey)
-		if len(tokens) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
-		}
-
-		token := tokens[0]
-
-		// Validate token (simplified - in production use proper JWT validation)
-		userID, err := validateToken(token)
+		userID, err := authenticate(ctx, validate)
 		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid token")
+			return nil, err
 		}
 
 		// Add user ID to context
@@ -50,42 +63,62 @@ func AuthenticationUnaryInterceptor() grpc.UnaryServerInterceptor {
 }
 
 // AuthenticationStreamInterceptor validates authentication tokens for streams
-func AuthenticationStreamInterceptor() grpc.StreamServerInterceptor {
+func AuthenticationStreamInterceptor(validate TokenValidator) grpc.StreamServerInterceptor {
+	if validate == nil {
+		validate = validateToken
+	}
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Skip authentication for health check
-		if info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if isHealthMethod(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
-		ctx := ss.Context()
-
-		// Extract token from metadata
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return status.Error(codes.Unauthenticated, "missing metadata")
+		userID, err := authenticate(ss.Context(), validate)
+		if err != nil {
+			return err
 		}
 
-		tokens := md.Get(AuthorizationKey)
-		if len(tokens) == 0 {
-			return status.Error(codes.Unauthenticated, "missing authorization token")
-		}
+		// Wrap stream with new context
+		wrapped := &wrappedStream{ServerStream: ss, ctx: withUserID(ss.Context(), userID)}
+		return handler(srv, wrapped)
+	}
+}
 
-		token := tokens[0]
+// authenticate extracts the token from incoming metadata and validates it.
+// Tokens may be sent bare or with a "Bearer " prefix. Callers identified by a
+// verified client certificate need no token.
+func authenticate(ctx context.Context, validate TokenValidator) (string, error) {
+	if id, ok := identity.FromContext(ctx); ok {
+		return id.Subject, nil
+	}
 
-		// Validate token
-		userID, err := validateToken(token)
-		if err != nil {
-			return status.Error(codes.Unauthenticated, "invalid token")
-		}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
 
-		// Add user ID to context
-		ctx = withUserID(ctx, userID)
+	tokens := md.Get(AuthorizationKey)
+	if len(tokens) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization token")
+	}
 
-		// Wrap stream with new context
-		wrapped := &wrappedStream{ServerStream: ss, ctx: ctx}
-		return handler(srv, wrapped)
+	token := strings.TrimSpace(tokens[0])
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
 	}
+
+	userID, err := validate(token)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return userID, nil
+}
+
+// isHealthMethod reports whether method belongs to the gRPC health service,
+// which load balancers call without credentials
+func isHealthMethod(method string) bool {
+	return method == "/grpc.health.v1.Health/Check" ||
+		method == "/grpc.health.v1.Health/Watch"
 }
 
 // validateToken validates the authentication token
@@ -3,6 +3,7 @@ package interceptors
 import (
 	"context"
 
+	"github.com/goclaw/goclaw/pkg/identity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,7 +34,7 @@ func AuthorizationUnaryInterceptor() grpc.UnaryServerInterceptor {
 
 		// Check if method requires admin role
 		if requiresAdminRole(info.FullMethod) {
-			role := getUserRole(userID)
+			role := getUserRole(ctx, userID)
 			if role != RoleAdmin {
 				return nil, status.Error(codes.PermissionDenied, "admin role required")
 			}
@@ -62,7 +63,7 @@ func AuthorizationStreamInterceptor() grpc.StreamServerInterceptor {
 
 		// Check if method requires admin role
 		if requiresAdminRole(info.FullMethod) {
-			role := getUserRole(userID)
+			role := getUserRole(ctx, userID)
 			if role != RoleAdmin {
 				return status.Error(codes.PermissionDenied, "admin role required")
 			}
@@ -90,9 +91,16 @@ func requiresAdminRole(method string) bool {
 	return adminMethods[method]
 }
 
-// getUserRole retrieves the role for a user
-// In production, this should query a database or cache
-func getUserRole(userID string) Role {
+// getUserRole retrieves the role for a user. Callers identified by a client
+// certificate get the roles mapped from its SANs.
+func getUserRole(ctx context.Context, userID string) Role {
+	if id, ok := identity.FromContext(ctx); ok {
+		if id.HasRole(string(RoleAdmin)) {
+			return RoleAdmin
+		}
+		return RoleUser
+	}
+
 	// Simplified role lookup - in production query database
 	// For now, return user role for all users
 	return RoleUser
@@ -1,6 +1,8 @@
 package interceptors
 
 import (
+	"github.com/goclaw/goclaw/pkg/identity"
+	"github.com/goclaw/goclaw/pkg/logger"
 	"google.golang.org/grpc"
 )
 
@@ -18,10 +20,18 @@ func NewChainBuilder() *ChainBuilder {
 	}
 }
 
+// WithErrorMapping adds the interceptor that attaches stable error codes to
+// returned statuses (should be outermost so it sees every error)
+func (b *ChainBuilder) WithErrorMapping() *ChainBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, ErrorMappingUnaryInterceptor())
+	b.streamInterceptors = append(b.streamInterceptors, ErrorMappingStreamInterceptor())
+	return b
+}
+
 // WithRecovery adds recovery interceptor (should be first)
-func (b *ChainBuilder) WithRecovery() *ChainBuilder {
-	b.unaryInterceptors = append(b.unaryInterceptors, RecoveryUnaryInterceptor())
-	b.streamInterceptors = append(b.streamInterceptors, RecoveryStreamInterceptor())
+func (b *ChainBuilder) WithRecovery(log logger.Logger) *ChainBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, RecoveryUnaryInterceptor(log))
+	b.streamInterceptors = append(b.streamInterceptors, RecoveryStreamInterceptor(log))
 	return b
 }
 
@@ -32,10 +42,18 @@ func (b *ChainBuilder) WithRequestID() *ChainBuilder {
 	return b
 }
 
+// WithIdentity adds the interceptor that attributes calls to the caller's
+// verified mTLS client certificate (should precede logging and auth)
+func (b *ChainBuilder) WithIdentity(mapper *identity.Mapper) *ChainBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, IdentityUnaryInterceptor(mapper))
+	b.streamInterceptors = append(b.streamInterceptors, IdentityStreamInterceptor(mapper))
+	return b
+}
+
 // WithAuthentication adds authentication interceptor
-func (b *ChainBuilder) WithAuthentication() *ChainBuilder {
-	b.unaryInterceptors = append(b.unaryInterceptors, AuthenticationUnaryInterceptor())
-	b.streamInterceptors = append(b.streamInterceptors, AuthenticationStreamInterceptor())
+func (b *ChainBuilder) WithAuthentication(validate TokenValidator) *ChainBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, AuthenticationUnaryInterceptor(validate))
+	b.streamInterceptors = append(b.streamInterceptors, AuthenticationStreamInterceptor(validate))
 	return b
 }
 
@@ -48,7 +66,12 @@ func (b *ChainBuilder) WithAuthorization() *ChainBuilder {
 
 // WithRateLimit adds rate limiting interceptor
 func (b *ChainBuilder) WithRateLimit(requestsPerSecond float64, burst int) *ChainBuilder {
-	rl := NewRateLimiter(requestsPerSecond, burst)
+	return b.WithRateLimiter(NewRateLimiter(requestsPerSecond, burst))
+}
+
+// WithRateLimiter adds rate limiting interceptor using a configured limiter,
+// e.g. one with per-method limits
+func (b *ChainBuilder) WithRateLimiter(rl *RateLimiter) *ChainBuilder {
 	b.unaryInterceptors = append(b.unaryInterceptors, RateLimitUnaryInterceptor(rl))
 	b.streamInterceptors = append(b.streamInterceptors, RateLimitStreamInterceptor(rl))
 	return b
@@ -62,9 +85,9 @@ func (b *ChainBuilder) WithValidation() *ChainBuilder {
 }
 
 // WithLogging adds logging interceptor
-func (b *ChainBuilder) WithLogging() *ChainBuilder {
-	b.unaryInterceptors = append(b.unaryInterceptors, LoggingUnaryInterceptor())
-	b.streamInterceptors = append(b.streamInterceptors, LoggingStreamInterceptor())
+func (b *ChainBuilder) WithLogging(log logger.Logger) *ChainBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, LoggingUnaryInterceptor(log))
+	b.streamInterceptors = append(b.streamInterceptors, LoggingStreamInterceptor(log))
 	return b
 }
 
@@ -102,7 +125,7 @@ func (b *ChainBuilder) Build() []grpc.ServerOption {
 }
 
 // DefaultChain returns a chain with recommended interceptors in correct order:
-// recovery -> request_id -> auth -> authorization -> rate_limit -> validation -> logging -> metrics -> tracing
+// error_mapping -> recovery -> request_id -> auth -> authorization -> rate_limit -> validation -> logging -> metrics -> tracing
 func DefaultChain() *ChainBuilder {
 	return DefaultChainWithTracing(true)
 }
@@ -110,13 +133,14 @@ func DefaultChain() *ChainBuilder {
 // DefaultChainWithTracing returns the default interceptor chain with tracing toggle.
 func DefaultChainWithTracing(enableTracing bool) *ChainBuilder {
 	builder := NewChainBuilder().
-		WithRecovery().
+		WithErrorMapping().
+		WithRecovery(nil).
 		WithRequestID().
-		WithAuthentication().
+		WithAuthentication(nil).
 		WithAuthorization().
 		WithRateLimit(100, 200). // 100 req/s, burst of 200
 		WithValidation().
-		WithLogging().
+		WithLogging(nil).
 		WithMetrics(nil) // nil will create default metrics
 	if enableTracing {
 		builder.WithTracing()
@@ -1,13 +1,14 @@
 package interceptors
 
-import "context"
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/requestid"
+)
 
 type contextKey string
 
-const (
-	userIDContextKey    contextKey = "user_id"
-	requestIDContextKey contextKey = "request_id"
-)
+const userIDContextKey contextKey = "user_id"
 
 func withUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, userIDContextKey, userID)
@@ -19,10 +20,10 @@ func userIDFromContext(ctx context.Context) (string, bool) {
 }
 
 func withRequestID(ctx context.Context, requestID string) context.Context {
-	return context.WithValue(ctx, requestIDContextKey, requestID)
+	return requestid.NewContext(ctx, requestID)
 }
 
 func requestIDFromContext(ctx context.Context) (string, bool) {
-	requestID, ok := ctx.Value(requestIDContextKey).(string)
-	return requestID, ok
+	requestID := requestid.FromContext(ctx)
+	return requestID, requestID != ""
 }
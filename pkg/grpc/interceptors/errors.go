@@ -0,0 +1,28 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"google.golang.org/grpc"
+)
+
+// ErrorMappingUnaryInterceptor converts handler errors into gRPC statuses carrying
+// the stable errs code, so every RPC reports failures the same way.
+func ErrorMappingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, errs.ToGRPC(err)
+		}
+		return resp, nil
+	}
+}
+
+// ErrorMappingStreamInterceptor converts streaming handler errors into gRPC statuses
+// carrying the stable errs code.
+func ErrorMappingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return errs.ToGRPC(handler(srv, ss))
+	}
+}
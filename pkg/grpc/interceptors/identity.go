@@ -0,0 +1,49 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/goclaw/goclaw/pkg/identity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// IdentityUnaryInterceptor stores the identity of the caller's verified mTLS
+// client certificate in the context. The certificate subject becomes the user
+// ID, so authentication, authorization, rate limiting and logging attribute
+// the call to it.
+func IdentityUnaryInterceptor(mapper *identity.Mapper) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withPeerIdentity(ctx, mapper), req)
+	}
+}
+
+// IdentityStreamInterceptor stores the client certificate identity for streams
+func IdentityStreamInterceptor(mapper *identity.Mapper) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withPeerIdentity(ss.Context(), mapper)
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withPeerIdentity returns ctx with the identity of the peer's verified client
+// certificate, or ctx unchanged if there is none
+func withPeerIdentity(ctx context.Context, mapper *identity.Mapper) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ctx
+	}
+	id := mapper.FromTLS(&tlsInfo.State)
+	if id == nil {
+		return ctx
+	}
+	return withUserID(identity.NewContext(ctx, id), id.Subject)
+}
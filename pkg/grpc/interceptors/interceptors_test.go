@@ -2,11 +2,17 @@ package interceptors
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"io"
+	"net"
 	"reflect"
 	"testing"
 
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/identity"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.opentelemetry.io/otel"
@@ -19,7 +25,9 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -71,7 +79,7 @@ func (b businessRuleReq) Validate() error {
 }
 
 func TestRecoveryUnaryInterceptor_Panic(t *testing.T) {
-	interceptor := RecoveryUnaryInterceptor()
+	interceptor := RecoveryUnaryInterceptor(nil)
 	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req interface{}) (interface{}, error) {
 		panic("boom")
 	})
@@ -80,6 +88,26 @@ func TestRecoveryUnaryInterceptor_Panic(t *testing.T) {
 	}
 }
 
+func TestErrorMappingUnaryInterceptor(t *testing.T) {
+	interceptor := ErrorMappingUnaryInterceptor()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errs.New(errs.NotFound, "workflow not found")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+	if errs.CodeOf(err) != errs.NotFound {
+		t.Fatalf("expected stable code %s, got %s", errs.NotFound, errs.CodeOf(err))
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("badger: value log truncated")
+	})
+	if status.Code(err) != codes.Internal || status.Convert(err).Message() != "internal server error" {
+		t.Fatalf("expected generic Internal error, got %v", err)
+	}
+}
+
 func TestRequestIDUnaryInterceptor_Generates(t *testing.T) {
 	interceptor := RequestIDUnaryInterceptor()
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
@@ -99,7 +127,7 @@ func TestRequestIDUnaryInterceptor_Generates(t *testing.T) {
 }
 
 func TestAuthenticationUnaryInterceptor_MissingToken(t *testing.T) {
-	interceptor := AuthenticationUnaryInterceptor()
+	interceptor := AuthenticationUnaryInterceptor(nil)
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
 	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req interface{}) (interface{}, error) {
 		return nil, nil
@@ -110,7 +138,7 @@ func TestAuthenticationUnaryInterceptor_MissingToken(t *testing.T) {
 }
 
 func TestAuthenticationUnaryInterceptor_HealthCheckBypass(t *testing.T) {
-	interceptor := AuthenticationUnaryInterceptor()
+	interceptor := AuthenticationUnaryInterceptor(nil)
 	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, func(ctx context.Context, req interface{}) (interface{}, error) {
 		return "ok", nil
 	})
@@ -148,8 +176,114 @@ func TestRateLimitUnaryInterceptor_Exceeded(t *testing.T) {
 	}
 }
 
+func TestRateLimitUnaryInterceptor_PerMethod(t *testing.T) {
+	rl := NewRateLimiter(100, 100)
+	rl.SetMethodLimit("/svc/Submit", 1, 1)
+	interceptor := RateLimitUnaryInterceptor(rl)
+	ctx := withUserID(context.Background(), "token-a")
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	if err := call(ctx, "/svc/Submit"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := call(ctx, "/svc/Submit"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for limited method, got %v", status.Code(err))
+	}
+	if err := call(ctx, "/svc/Get"); err != nil {
+		t.Fatalf("expected other methods to use the default limit, got %v", err)
+	}
+	if err := call(withUserID(context.Background(), "token-b"), "/svc/Submit"); err != nil {
+		t.Fatalf("expected method limit to apply per client, got %v", err)
+	}
+}
+
+func TestAuthenticationUnaryInterceptor_StaticTokens(t *testing.T) {
+	interceptor := AuthenticationUnaryInterceptor(StaticTokenValidator([]string{"secret"}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/m"}
+
+	var userID string
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer secret"))
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		userID, _ = userIDFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID == "" || userID == "secret" {
+		t.Fatalf("expected hashed caller id, got %q", userID)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer wrong"))
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("handler called with invalid token")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", status.Code(err))
+	}
+}
+
+func TestIdentityUnaryInterceptor_ClientCertificate(t *testing.T) {
+	mapper, err := identity.NewMapper([]identity.RoleMapping{
+		{SAN: "ops.goclaw.internal", Roles: []string{"admin"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMapper() error = %v", err)
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "ops"}, DNSNames: []string{"ops.goclaw.internal"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+
+	// Identity -> authentication (no token) -> authorization of an admin method
+	chain := []grpc.UnaryServerInterceptor{
+		IdentityUnaryInterceptor(mapper),
+		AuthenticationUnaryInterceptor(StaticTokenValidator([]string{"secret"})),
+		AuthorizationUnaryInterceptor(),
+	}
+	var userID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		userID, _ = userIDFromContext(ctx)
+		return nil, nil
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/goclaw.v1.AdminService/GetEngineStatus"}, next)
+		}
+	}
+
+	if _, err := handler(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != "ops" {
+		t.Fatalf("expected calls attributed to certificate subject, got %q", userID)
+	}
+}
+
+func TestRecoveryStreamInterceptor_Panic(t *testing.T) {
+	interceptor := RecoveryStreamInterceptor(nil)
+	stream := &testServerStream{ctx: context.Background()}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/svc/s"}, func(srv interface{}, ss grpc.ServerStream) error {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", status.Code(err))
+	}
+}
+
 func TestLoggingUnaryInterceptor(t *testing.T) {
-	interceptor := LoggingUnaryInterceptor()
+	interceptor := LoggingUnaryInterceptor(nil)
 	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req interface{}) (interface{}, error) {
 		return "ok", nil
 	})
@@ -2,72 +2,79 @@ package interceptors
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/identity"
+	"github.com/goclaw/goclaw/pkg/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-// LoggingUnaryInterceptor logs request and response for unary RPCs
-func LoggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+// LoggingUnaryInterceptor logs each unary RPC once it completes, like the HTTP
+// Logger middleware. A nil log uses the global logger.
+func LoggingUnaryInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	if log == nil {
+		log = logger.Global()
+	}
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 
-		// Extract request ID from context
-		requestID, ok := requestIDFromContext(ctx)
-		if !ok {
-			requestID = "unknown"
-		}
-
-		// Log request
-		fmt.Printf("[%s] --> %s\n", requestID, info.FullMethod)
-
 		// Call handler
 		resp, err := handler(ctx, req)
 
-		// Log response
-		duration := time.Since(start)
-		statusCode := codes.OK
-		if err != nil {
-			statusCode = status.Code(err)
-		}
-
-		fmt.Printf("[%s] <-- %s [%s] %v\n", requestID, info.FullMethod, statusCode, duration)
+		log.Info("gRPC request", callAttrs(ctx, info.FullMethod, err, start)...)
 
 		return resp, err
 	}
 }
 
-// LoggingStreamInterceptor logs stream lifecycle for streaming RPCs
-func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+// LoggingStreamInterceptor logs each streaming RPC once it ends
+func LoggingStreamInterceptor(log logger.Logger) grpc.StreamServerInterceptor {
+	if log == nil {
+		log = logger.Global()
+	}
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		start := time.Now()
 
-		// Extract request ID from context
-		ctx := ss.Context()
-		requestID, ok := requestIDFromContext(ctx)
-		if !ok {
-			requestID = "unknown"
-		}
-
-		// Log stream start
-		fmt.Printf("[%s] --> STREAM %s (client=%v, server=%v)\n",
-			requestID, info.FullMethod, info.IsClientStream, info.IsServerStream)
-
 		// Call handler
 		err := handler(srv, ss)
 
-		// Log stream end
-		duration := time.Since(start)
-		statusCode := codes.OK
-		if err != nil {
-			statusCode = status.Code(err)
-		}
-
-		fmt.Printf("[%s] <-- STREAM %s [%s] %v\n", requestID, info.FullMethod, statusCode, duration)
+		attrs := callAttrs(ss.Context(), info.FullMethod, err, start)
+		attrs = append(attrs, "client_stream", info.IsClientStream, "server_stream", info.IsServerStream)
+		log.Info("gRPC stream", attrs...)
 
 		return err
 	}
 }
+
+// callAttrs returns the log attributes of a completed call
+func callAttrs(ctx context.Context, method string, err error, start time.Time) []any {
+	statusCode := codes.OK
+	if err != nil {
+		statusCode = status.Code(err)
+	}
+
+	requestID, ok := requestIDFromContext(ctx)
+	if !ok {
+		requestID = "unknown"
+	}
+
+	peerAddr := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+
+	attrs := []any{
+		"method", method,
+		"code", statusCode.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", requestID,
+		"peer", peerAddr,
+	}
+	if id, ok := identity.FromContext(ctx); ok {
+		attrs = append(attrs, "client", id.Subject)
+	}
+	return attrs
+}
@@ -2,41 +2,68 @@ package interceptors
 
 import (
 	"context"
+	"net"
 	"sync"
 
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-// RateLimiter manages rate limiting per client
+// RateLimiter manages rate limiting per client, with optional per-method
+// overrides
 type RateLimiter struct {
 	limiters map[string]*rate.Limiter
+	methods  map[string]methodLimit
 	mu       sync.RWMutex
 	rate     rate.Limit
 	burst    int
 }
 
+// methodLimit is the rate and burst of a method with its own limit
+type methodLimit struct {
+	rate  rate.Limit
+	burst int
+}
+
 // NewRateLimiter creates a new rate limiter
 func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
 	return &RateLimiter{
 		limiters: make(map[string]*rate.Limiter),
+		methods:  make(map[string]methodLimit),
 		rate:     rate.Limit(requestsPerSecond),
 		burst:    burst,
 	}
 }
 
-// getLimiter gets or creates a limiter for a client
-func (rl *RateLimiter) getLimiter(clientID string) *rate.Limiter {
+// SetMethodLimit gives a full method name (e.g.
+// "/goclaw.v1.WorkflowService/SubmitWorkflow") its own limit. Calls to the
+// method are counted against it instead of the client's default limit.
+func (rl *RateLimiter) SetMethodLimit(method string, requestsPerSecond float64, burst int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.methods[method] = methodLimit{rate: rate.Limit(requestsPerSecond), burst: burst}
+}
+
+// getLimiter gets or creates a limiter for a client calling method
+func (rl *RateLimiter) getLimiter(method, clientID string) *rate.Limiter {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	limiter, exists := rl.limiters[clientID]
+	key := clientID
+	limit, burst := rl.rate, rl.burst
+	if ml, ok := rl.methods[method]; ok {
+		key = method + " " + clientID
+		limit, burst = ml.rate, ml.burst
+	}
+
+	limiter, exists := rl.limiters[key]
 	if !exists {
-		limiter = rate.NewLimiter(rl.rate, rl.burst)
-		rl.limiters[clientID] = limiter
+		limiter = rate.NewLimiter(limit, burst)
+		rl.limiters[key] = limiter
 	}
 
 	return limiter
@@ -46,28 +73,16 @@ func (rl *RateLimiter) getLimiter(clientID string) *rate.Limiter {
 func RateLimitUnaryInterceptor(rl *RateLimiter) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Skip rate limiting for health check
-		if info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if isHealthMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		// Get client ID (use user_id from auth, or IP address)
-		clientID := getClientID(ctx)
-
 		// Get limiter for this client
-		limiter := rl.getLimiter(clientID)
+		limiter := rl.getLimiter(info.FullMethod, getClientID(ctx))
 
 		// Check if request is allowed
 		if !limiter.Allow() {
-			// Calculate retry-after duration
-			reservation := limiter.Reserve()
-			retryAfter := reservation.Delay()
-			reservation.Cancel()
-
-			// Add retry-after to metadata
-			md := metadata.Pairs("retry-after", retryAfter.String())
-			_ = grpc.SetHeader(ctx, md)
-
+			_ = grpc.SetHeader(ctx, retryAfter(limiter))
 			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
 		}
 
@@ -79,21 +94,16 @@ func RateLimitUnaryInterceptor(rl *RateLimiter) grpc.UnaryServerInterceptor {
 func RateLimitStreamInterceptor(rl *RateLimiter) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Skip rate limiting for health check
-		if info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if isHealthMethod(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
-		ctx := ss.Context()
-
-		// Get client ID
-		clientID := getClientID(ctx)
-
 		// Get limiter for this client
-		limiter := rl.getLimiter(clientID)
+		limiter := rl.getLimiter(info.FullMethod, getClientID(ss.Context()))
 
 		// Check if request is allowed
 		if !limiter.Allow() {
+			_ = ss.SetHeader(retryAfter(limiter))
 			return status.Error(codes.ResourceExhausted, "rate limit exceeded")
 		}
 
@@ -101,6 +111,15 @@ func RateLimitStreamInterceptor(rl *RateLimiter) grpc.StreamServerInterceptor {
 	}
 }
 
+// retryAfter returns response metadata telling the client when limiter will
+// next allow a call
+func retryAfter(limiter *rate.Limiter) metadata.MD {
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	reservation.Cancel()
+	return metadata.Pairs("retry-after", delay.String())
+}
+
 // getClientID extracts client identifier from context
 func getClientID(ctx context.Context) string {
 	// Try to get user ID from context (set by auth interceptor)
@@ -108,9 +127,12 @@ func getClientID(ctx context.Context) string {
 		return userID
 	}
 
-	// Fall back to request ID
-	if requestID, ok := requestIDFromContext(ctx); ok {
-		return requestID
+	// Fall back to the peer's IP address
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
 	}
 
 	// Default to "anonymous"
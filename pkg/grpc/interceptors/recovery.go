@@ -2,25 +2,25 @@ package interceptors
 
 import (
 	"context"
-	"fmt"
 	"runtime/debug"
 
+	"github.com/goclaw/goclaw/pkg/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// RecoveryInterceptor recovers from panics and returns Internal error
-func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+// RecoveryUnaryInterceptor recovers from panics and returns Internal error.
+// A nil log uses the global logger.
+func RecoveryUnaryInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	if log == nil {
+		log = logger.Global()
+	}
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				// Log the panic and stack trace
-				stack := debug.Stack()
-				fmt.Printf("PANIC recovered in %s: %v\n%s\n", info.FullMethod, r, stack)
-
-				// Return Internal error
-				err = status.Errorf(codes.Internal, "internal server error")
+				logPanic(ctx, log, info.FullMethod, r)
+				err = status.Error(codes.Internal, "internal server error")
 			}
 		}()
 
@@ -29,19 +29,32 @@ func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
 }
 
 // RecoveryStreamInterceptor recovers from panics in streaming RPCs
-func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+func RecoveryStreamInterceptor(log logger.Logger) grpc.StreamServerInterceptor {
+	if log == nil {
+		log = logger.Global()
+	}
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				// Log the panic and stack trace
-				stack := debug.Stack()
-				fmt.Printf("PANIC recovered in %s: %v\n%s\n", info.FullMethod, r, stack)
-
-				// Return Internal error
-				err = status.Errorf(codes.Internal, "internal server error")
+				logPanic(ss.Context(), log, info.FullMethod, r)
+				err = status.Error(codes.Internal, "internal server error")
 			}
 		}()
 
 		return handler(srv, ss)
 	}
 }
+
+// logPanic logs a recovered panic with its stack trace
+func logPanic(ctx context.Context, log logger.Logger, method string, r interface{}) {
+	requestID, ok := requestIDFromContext(ctx)
+	if !ok {
+		requestID = "unknown"
+	}
+	log.Error("Panic recovered",
+		"error", r,
+		"method", method,
+		"request_id", requestID,
+		"stack", string(debug.Stack()),
+	)
+}
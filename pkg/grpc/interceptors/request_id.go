@@ -3,14 +3,14 @@ package interceptors
 import (
 	"context"
 
-	"github.com/google/uuid"
+	"github.com/goclaw/goclaw/pkg/requestid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
 const (
 	// RequestIDKey is the metadata key for request ID
-	RequestIDKey = "x-request-id"
+	RequestIDKey = requestid.MetadataKey
 )
 
 // RequestIDUnaryInterceptor generates or propagates request ID
@@ -19,8 +19,9 @@ func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
 		requestID := extractOrGenerateRequestID(ctx)
 		ctx = withRequestID(ctx, requestID)
 
-		// Add to outgoing metadata
+		// Add to outgoing metadata and echo it in the response header
 		ctx = metadata.AppendToOutgoingContext(ctx, RequestIDKey, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID))
 
 		return handler(ctx, req)
 	}
@@ -32,6 +33,7 @@ func RequestIDStreamInterceptor() grpc.StreamServerInterceptor {
 		ctx := ss.Context()
 		requestID := extractOrGenerateRequestID(ctx)
 		ctx = withRequestID(ctx, requestID)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDKey, requestID))
 
 		// Wrap the stream with new context
 		wrapped := &wrappedStream{ServerStream: ss, ctx: ctx}
@@ -47,7 +49,7 @@ func extractOrGenerateRequestID(ctx context.Context) string {
 			return ids[0]
 		}
 	}
-	return uuid.New().String()
+	return requestid.New()
 }
 
 // wrappedStream wraps grpc.ServerStream with custom context
@@ -0,0 +1,1071 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.27.3
+// source: goclaw/v1/agent.proto
+
+package pbv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AgentRegistration announces an agent and what it can run.
+type AgentRegistration struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Task agent types the agent executes. Empty accepts any type.
+	AgentTypes []string `protobuf:"bytes,2,rep,name=agent_types,json=agentTypes,proto3" json:"agent_types,omitempty"`
+	// Maximum number of tasks the agent runs at once.
+	Capacity      int32             `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentRegistration) Reset() {
+	*x = AgentRegistration{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentRegistration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentRegistration) ProtoMessage() {}
+
+func (x *AgentRegistration) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentRegistration.ProtoReflect.Descriptor instead.
+func (*AgentRegistration) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AgentRegistration) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentRegistration) GetAgentTypes() []string {
+	if x != nil {
+		return x.AgentTypes
+	}
+	return nil
+}
+
+func (x *AgentRegistration) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *AgentRegistration) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// AgentHeartbeat keeps an agent's registration alive.
+type AgentHeartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveTasks   int32                  `protobuf:"varint,1,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentHeartbeat) Reset() {
+	*x = AgentHeartbeat{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentHeartbeat) ProtoMessage() {}
+
+func (x *AgentHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentHeartbeat.ProtoReflect.Descriptor instead.
+func (*AgentHeartbeat) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AgentHeartbeat) GetActiveTasks() int32 {
+	if x != nil {
+		return x.ActiveTasks
+	}
+	return 0
+}
+
+// TaskProgress reports how far an assigned task has got.
+type TaskProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssignmentId  string                 `protobuf:"bytes,1,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	Percent       float64                `protobuf:"fixed64,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskProgress) Reset() {
+	*x = TaskProgress{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskProgress) ProtoMessage() {}
+
+func (x *TaskProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskProgress.ProtoReflect.Descriptor instead.
+func (*TaskProgress) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TaskProgress) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *TaskProgress) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// TaskCompletion reports the outcome of an assigned task.
+type TaskCompletion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssignmentId  string                 `protobuf:"bytes,1,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	ResultData    []byte                 `protobuf:"bytes,3,opt,name=result_data,json=resultData,proto3" json:"result_data,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCompletion) Reset() {
+	*x = TaskCompletion{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCompletion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCompletion) ProtoMessage() {}
+
+func (x *TaskCompletion) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCompletion.ProtoReflect.Descriptor instead.
+func (*TaskCompletion) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TaskCompletion) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *TaskCompletion) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TaskCompletion) GetResultData() []byte {
+	if x != nil {
+		return x.ResultData
+	}
+	return nil
+}
+
+func (x *TaskCompletion) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// AgentMessage is sent by an agent on its control channel.
+type AgentMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*AgentMessage_Register
+	//	*AgentMessage_Heartbeat
+	//	*AgentMessage_Progress
+	//	*AgentMessage_Completion
+	Payload       isAgentMessage_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentMessage) Reset() {
+	*x = AgentMessage{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentMessage) ProtoMessage() {}
+
+func (x *AgentMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentMessage.ProtoReflect.Descriptor instead.
+func (*AgentMessage) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AgentMessage) GetPayload() isAgentMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetRegister() *AgentRegistration {
+	if x != nil {
+		if x, ok := x.Payload.(*AgentMessage_Register); ok {
+			return x.Register
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetHeartbeat() *AgentHeartbeat {
+	if x != nil {
+		if x, ok := x.Payload.(*AgentMessage_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetProgress() *TaskProgress {
+	if x != nil {
+		if x, ok := x.Payload.(*AgentMessage_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetCompletion() *TaskCompletion {
+	if x != nil {
+		if x, ok := x.Payload.(*AgentMessage_Completion); ok {
+			return x.Completion
+		}
+	}
+	return nil
+}
+
+type isAgentMessage_Payload interface {
+	isAgentMessage_Payload()
+}
+
+type AgentMessage_Register struct {
+	Register *AgentRegistration `protobuf:"bytes,1,opt,name=register,proto3,oneof"`
+}
+
+type AgentMessage_Heartbeat struct {
+	Heartbeat *AgentHeartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type AgentMessage_Progress struct {
+	Progress *TaskProgress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
+}
+
+type AgentMessage_Completion struct {
+	Completion *TaskCompletion `protobuf:"bytes,4,opt,name=completion,proto3,oneof"`
+}
+
+func (*AgentMessage_Register) isAgentMessage_Payload() {}
+
+func (*AgentMessage_Heartbeat) isAgentMessage_Payload() {}
+
+func (*AgentMessage_Progress) isAgentMessage_Payload() {}
+
+func (*AgentMessage_Completion) isAgentMessage_Payload() {}
+
+// AgentRegistered acknowledges a registration.
+type AgentRegistered struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Interval at which the agent must send heartbeats.
+	HeartbeatIntervalSeconds int32 `protobuf:"varint,2,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AgentRegistered) Reset() {
+	*x = AgentRegistered{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentRegistered) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentRegistered) ProtoMessage() {}
+
+func (x *AgentRegistered) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentRegistered.ProtoReflect.Descriptor instead.
+func (*AgentRegistered) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AgentRegistered) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentRegistered) GetHeartbeatIntervalSeconds() int32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+// TaskAssignment hands a task to an agent.
+type TaskAssignment struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AssignmentId string                 `protobuf:"bytes,1,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	WorkflowId   string                 `protobuf:"bytes,2,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	TaskId       string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Name         string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	AgentType    string                 `protobuf:"bytes,5,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	Payload      []byte                 `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	Deadline     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Environment variables for the task, including resolved secrets.
+	Env           map[string]string `protobuf:"bytes,8,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskAssignment) Reset() {
+	*x = TaskAssignment{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskAssignment) ProtoMessage() {}
+
+func (x *TaskAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskAssignment.ProtoReflect.Descriptor instead.
+func (*TaskAssignment) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TaskAssignment) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *TaskAssignment) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *TaskAssignment) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskAssignment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskAssignment) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
+func (x *TaskAssignment) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TaskAssignment) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *TaskAssignment) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+// TaskCancellation asks an agent to stop an assigned task.
+type TaskCancellation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssignmentId  string                 `protobuf:"bytes,1,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCancellation) Reset() {
+	*x = TaskCancellation{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCancellation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCancellation) ProtoMessage() {}
+
+func (x *TaskCancellation) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCancellation.ProtoReflect.Descriptor instead.
+func (*TaskCancellation) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TaskCancellation) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *TaskCancellation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// AgentCommand is sent by the server on an agent's control channel.
+type AgentCommand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Command:
+	//
+	//	*AgentCommand_Registered
+	//	*AgentCommand_Assign
+	//	*AgentCommand_Cancel
+	//	*AgentCommand_Error
+	Command       isAgentCommand_Command `protobuf_oneof:"command"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentCommand) Reset() {
+	*x = AgentCommand{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentCommand) ProtoMessage() {}
+
+func (x *AgentCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentCommand.ProtoReflect.Descriptor instead.
+func (*AgentCommand) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AgentCommand) GetCommand() isAgentCommand_Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *AgentCommand) GetRegistered() *AgentRegistered {
+	if x != nil {
+		if x, ok := x.Command.(*AgentCommand_Registered); ok {
+			return x.Registered
+		}
+	}
+	return nil
+}
+
+func (x *AgentCommand) GetAssign() *TaskAssignment {
+	if x != nil {
+		if x, ok := x.Command.(*AgentCommand_Assign); ok {
+			return x.Assign
+		}
+	}
+	return nil
+}
+
+func (x *AgentCommand) GetCancel() *TaskCancellation {
+	if x != nil {
+		if x, ok := x.Command.(*AgentCommand_Cancel); ok {
+			return x.Cancel
+		}
+	}
+	return nil
+}
+
+func (x *AgentCommand) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Command.(*AgentCommand_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isAgentCommand_Command interface {
+	isAgentCommand_Command()
+}
+
+type AgentCommand_Registered struct {
+	Registered *AgentRegistered `protobuf:"bytes,1,opt,name=registered,proto3,oneof"`
+}
+
+type AgentCommand_Assign struct {
+	Assign *TaskAssignment `protobuf:"bytes,2,opt,name=assign,proto3,oneof"`
+}
+
+type AgentCommand_Cancel struct {
+	Cancel *TaskCancellation `protobuf:"bytes,3,opt,name=cancel,proto3,oneof"`
+}
+
+type AgentCommand_Error struct {
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*AgentCommand_Registered) isAgentCommand_Command() {}
+
+func (*AgentCommand_Assign) isAgentCommand_Command() {}
+
+func (*AgentCommand_Cancel) isAgentCommand_Command() {}
+
+func (*AgentCommand_Error) isAgentCommand_Command() {}
+
+// ListAgentsRequest lists connected agents.
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentType     string                 `protobuf:"bytes,1,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListAgentsRequest) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
+// AgentInfo describes a connected agent.
+type AgentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentTypes    []string               `protobuf:"bytes,2,rep,name=agent_types,json=agentTypes,proto3" json:"agent_types,omitempty"`
+	Capacity      int32                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	ActiveTasks   int32                  `protobuf:"varint,4,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ConnectedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	LastHeartbeat *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentInfo) Reset() {
+	*x = AgentInfo{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentInfo) ProtoMessage() {}
+
+func (x *AgentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentInfo.ProtoReflect.Descriptor instead.
+func (*AgentInfo) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AgentInfo) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentInfo) GetAgentTypes() []string {
+	if x != nil {
+		return x.AgentTypes
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *AgentInfo) GetActiveTasks() int32 {
+	if x != nil {
+		return x.ActiveTasks
+	}
+	return 0
+}
+
+func (x *AgentInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetLastHeartbeat() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return nil
+}
+
+// ListAgentsResponse lists connected agents.
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*AgentInfo           `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_goclaw_v1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*AgentInfo {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+var File_goclaw_v1_agent_proto protoreflect.FileDescriptor
+
+const file_goclaw_v1_agent_proto_rawDesc = "" +
+	"\n" +
+	"\x15goclaw/v1/agent.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\x01\n" +
+	"\x11AgentRegistration\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vagent_types\x18\x02 \x03(\tR\n" +
+	"agentTypes\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x05R\bcapacity\x12@\n" +
+	"\x06labels\x18\x04 \x03(\v2(.goclaw.v1.AgentRegistration.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"3\n" +
+	"\x0eAgentHeartbeat\x12!\n" +
+	"\factive_tasks\x18\x01 \x01(\x05R\vactiveTasks\"g\n" +
+	"\fTaskProgress\x12#\n" +
+	"\rassignment_id\x18\x01 \x01(\tR\fassignmentId\x12\x18\n" +
+	"\apercent\x18\x02 \x01(\x01R\apercent\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x95\x01\n" +
+	"\x0eTaskCompletion\x12#\n" +
+	"\rassignment_id\x18\x01 \x01(\tR\fassignmentId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x1f\n" +
+	"\vresult_data\x18\x03 \x01(\fR\n" +
+	"resultData\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\x84\x02\n" +
+	"\fAgentMessage\x12:\n" +
+	"\bregister\x18\x01 \x01(\v2\x1c.goclaw.v1.AgentRegistrationH\x00R\bregister\x129\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x19.goclaw.v1.AgentHeartbeatH\x00R\theartbeat\x125\n" +
+	"\bprogress\x18\x03 \x01(\v2\x17.goclaw.v1.TaskProgressH\x00R\bprogress\x12;\n" +
+	"\n" +
+	"completion\x18\x04 \x01(\v2\x19.goclaw.v1.TaskCompletionH\x00R\n" +
+	"completionB\t\n" +
+	"\apayload\"j\n" +
+	"\x0fAgentRegistered\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12<\n" +
+	"\x1aheartbeat_interval_seconds\x18\x02 \x01(\x05R\x18heartbeatIntervalSeconds\"\xe2\x02\n" +
+	"\x0eTaskAssignment\x12#\n" +
+	"\rassignment_id\x18\x01 \x01(\tR\fassignmentId\x12\x1f\n" +
+	"\vworkflow_id\x18\x02 \x01(\tR\n" +
+	"workflowId\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\x05 \x01(\tR\tagentType\x12\x18\n" +
+	"\apayload\x18\x06 \x01(\fR\apayload\x126\n" +
+	"\bdeadline\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x124\n" +
+	"\x03env\x18\b \x03(\v2\".goclaw.v1.TaskAssignment.EnvEntryR\x03env\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"O\n" +
+	"\x10TaskCancellation\x12#\n" +
+	"\rassignment_id\x18\x01 \x01(\tR\fassignmentId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xed\x01\n" +
+	"\fAgentCommand\x12<\n" +
+	"\n" +
+	"registered\x18\x01 \x01(\v2\x1a.goclaw.v1.AgentRegisteredH\x00R\n" +
+	"registered\x123\n" +
+	"\x06assign\x18\x02 \x01(\v2\x19.goclaw.v1.TaskAssignmentH\x00R\x06assign\x125\n" +
+	"\x06cancel\x18\x03 \x01(\v2\x1b.goclaw.v1.TaskCancellationH\x00R\x06cancel\x12(\n" +
+	"\x05error\x18\x04 \x01(\v2\x10.goclaw.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\acommand\"2\n" +
+	"\x11ListAgentsRequest\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\x01 \x01(\tR\tagentType\"\xfd\x02\n" +
+	"\tAgentInfo\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vagent_types\x18\x02 \x03(\tR\n" +
+	"agentTypes\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x05R\bcapacity\x12!\n" +
+	"\factive_tasks\x18\x04 \x01(\x05R\vactiveTasks\x128\n" +
+	"\x06labels\x18\x05 \x03(\v2 .goclaw.v1.AgentInfo.LabelsEntryR\x06labels\x12=\n" +
+	"\fconnected_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vconnectedAt\x12A\n" +
+	"\x0elast_heartbeat\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rlastHeartbeat\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\x12ListAgentsResponse\x12,\n" +
+	"\x06agents\x18\x01 \x03(\v2\x14.goclaw.v1.AgentInfoR\x06agents2\x9a\x01\n" +
+	"\fAgentService\x12?\n" +
+	"\aConnect\x12\x17.goclaw.v1.AgentMessage\x1a\x17.goclaw.v1.AgentCommand(\x010\x01\x12I\n" +
+	"\n" +
+	"ListAgents\x12\x1c.goclaw.v1.ListAgentsRequest\x1a\x1d.goclaw.v1.ListAgentsResponseB.Z,github.com/goclaw/goclaw/pkg/grpc/pb/v1;pbv1b\x06proto3"
+
+var (
+	file_goclaw_v1_agent_proto_rawDescOnce sync.Once
+	file_goclaw_v1_agent_proto_rawDescData []byte
+)
+
+func file_goclaw_v1_agent_proto_rawDescGZIP() []byte {
+	file_goclaw_v1_agent_proto_rawDescOnce.Do(func() {
+		file_goclaw_v1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goclaw_v1_agent_proto_rawDesc), len(file_goclaw_v1_agent_proto_rawDesc)))
+	})
+	return file_goclaw_v1_agent_proto_rawDescData
+}
+
+var file_goclaw_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_goclaw_v1_agent_proto_goTypes = []any{
+	(*AgentRegistration)(nil),     // 0: goclaw.v1.AgentRegistration
+	(*AgentHeartbeat)(nil),        // 1: goclaw.v1.AgentHeartbeat
+	(*TaskProgress)(nil),          // 2: goclaw.v1.TaskProgress
+	(*TaskCompletion)(nil),        // 3: goclaw.v1.TaskCompletion
+	(*AgentMessage)(nil),          // 4: goclaw.v1.AgentMessage
+	(*AgentRegistered)(nil),       // 5: goclaw.v1.AgentRegistered
+	(*TaskAssignment)(nil),        // 6: goclaw.v1.TaskAssignment
+	(*TaskCancellation)(nil),      // 7: goclaw.v1.TaskCancellation
+	(*AgentCommand)(nil),          // 8: goclaw.v1.AgentCommand
+	(*ListAgentsRequest)(nil),     // 9: goclaw.v1.ListAgentsRequest
+	(*AgentInfo)(nil),             // 10: goclaw.v1.AgentInfo
+	(*ListAgentsResponse)(nil),    // 11: goclaw.v1.ListAgentsResponse
+	nil,                           // 12: goclaw.v1.AgentRegistration.LabelsEntry
+	nil,                           // 13: goclaw.v1.TaskAssignment.EnvEntry
+	nil,                           // 14: goclaw.v1.AgentInfo.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(*Error)(nil),                 // 16: goclaw.v1.Error
+}
+var file_goclaw_v1_agent_proto_depIdxs = []int32{
+	12, // 0: goclaw.v1.AgentRegistration.labels:type_name -> goclaw.v1.AgentRegistration.LabelsEntry
+	0,  // 1: goclaw.v1.AgentMessage.register:type_name -> goclaw.v1.AgentRegistration
+	1,  // 2: goclaw.v1.AgentMessage.heartbeat:type_name -> goclaw.v1.AgentHeartbeat
+	2,  // 3: goclaw.v1.AgentMessage.progress:type_name -> goclaw.v1.TaskProgress
+	3,  // 4: goclaw.v1.AgentMessage.completion:type_name -> goclaw.v1.TaskCompletion
+	15, // 5: goclaw.v1.TaskAssignment.deadline:type_name -> google.protobuf.Timestamp
+	13, // 6: goclaw.v1.TaskAssignment.env:type_name -> goclaw.v1.TaskAssignment.EnvEntry
+	5,  // 7: goclaw.v1.AgentCommand.registered:type_name -> goclaw.v1.AgentRegistered
+	6,  // 8: goclaw.v1.AgentCommand.assign:type_name -> goclaw.v1.TaskAssignment
+	7,  // 9: goclaw.v1.AgentCommand.cancel:type_name -> goclaw.v1.TaskCancellation
+	16, // 10: goclaw.v1.AgentCommand.error:type_name -> goclaw.v1.Error
+	14, // 11: goclaw.v1.AgentInfo.labels:type_name -> goclaw.v1.AgentInfo.LabelsEntry
+	15, // 12: goclaw.v1.AgentInfo.connected_at:type_name -> google.protobuf.Timestamp
+	15, // 13: goclaw.v1.AgentInfo.last_heartbeat:type_name -> google.protobuf.Timestamp
+	10, // 14: goclaw.v1.ListAgentsResponse.agents:type_name -> goclaw.v1.AgentInfo
+	4,  // 15: goclaw.v1.AgentService.Connect:input_type -> goclaw.v1.AgentMessage
+	9,  // 16: goclaw.v1.AgentService.ListAgents:input_type -> goclaw.v1.ListAgentsRequest
+	8,  // 17: goclaw.v1.AgentService.Connect:output_type -> goclaw.v1.AgentCommand
+	11, // 18: goclaw.v1.AgentService.ListAgents:output_type -> goclaw.v1.ListAgentsResponse
+	17, // [17:19] is the sub-list for method output_type
+	15, // [15:17] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_goclaw_v1_agent_proto_init() }
+func file_goclaw_v1_agent_proto_init() {
+	if File_goclaw_v1_agent_proto != nil {
+		return
+	}
+	file_goclaw_v1_common_proto_init()
+	file_goclaw_v1_agent_proto_msgTypes[4].OneofWrappers = []any{
+		(*AgentMessage_Register)(nil),
+		(*AgentMessage_Heartbeat)(nil),
+		(*AgentMessage_Progress)(nil),
+		(*AgentMessage_Completion)(nil),
+	}
+	file_goclaw_v1_agent_proto_msgTypes[8].OneofWrappers = []any{
+		(*AgentCommand_Registered)(nil),
+		(*AgentCommand_Assign)(nil),
+		(*AgentCommand_Cancel)(nil),
+		(*AgentCommand_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goclaw_v1_agent_proto_rawDesc), len(file_goclaw_v1_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_goclaw_v1_agent_proto_goTypes,
+		DependencyIndexes: file_goclaw_v1_agent_proto_depIdxs,
+		MessageInfos:      file_goclaw_v1_agent_proto_msgTypes,
+	}.Build()
+	File_goclaw_v1_agent_proto = out.File
+	file_goclaw_v1_agent_proto_goTypes = nil
+	file_goclaw_v1_agent_proto_depIdxs = nil
+}
@@ -9,6 +9,7 @@ package pbv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -222,9 +223,12 @@ func (x *SubmitWorkflowsResponse) GetError() *Error {
 
 // Get workflow statuses request
 type GetWorkflowStatusesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowIds   []string               `protobuf:"bytes,1,rep,name=workflow_ids,json=workflowIds,proto3" json:"workflow_ids,omitempty"`
-	Pagination    *PaginationRequest     `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowIds []string               `protobuf:"bytes,1,rep,name=workflow_ids,json=workflowIds,proto3" json:"workflow_ids,omitempty"`
+	Pagination  *PaginationRequest     `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	// Fields of each GetWorkflowStatusResponse to return. All fields are
+	// returned when empty.
+	FieldMask     *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -273,6 +277,13 @@ func (x *GetWorkflowStatusesRequest) GetPagination() *PaginationRequest {
 	return nil
 }
 
+func (x *GetWorkflowStatusesRequest) GetFieldMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
 // Workflow status result
 type WorkflowStatusResult struct {
 	state         protoimpl.MessageState     `protogen:"open.v1"`
@@ -781,7 +792,7 @@ var File_goclaw_v1_batch_proto protoreflect.FileDescriptor
 
 const file_goclaw_v1_batch_proto_rawDesc = "" +
 	"\n" +
-	"\x15goclaw/v1/batch.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x18goclaw/v1/workflow.proto\"\xb3\x01\n" +
+	"\x15goclaw/v1/batch.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x18goclaw/v1/workflow.proto\x1a google/protobuf/field_mask.proto\"\xb3\x01\n" +
 	"\x16SubmitWorkflowsRequest\x12>\n" +
 	"\tworkflows\x18\x01 \x03(\v2 .goclaw.v1.SubmitWorkflowRequestR\tworkflows\x12\x16\n" +
 	"\x06atomic\x18\x02 \x01(\bR\x06atomic\x12'\n" +
@@ -798,12 +809,14 @@ const file_goclaw_v1_batch_proto_rawDesc = "" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1d.goclaw.v1.PaginationResponseR\n" +
 	"pagination\x12&\n" +
-	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"}\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\xb8\x01\n" +
 	"\x1aGetWorkflowStatusesRequest\x12!\n" +
 	"\fworkflow_ids\x18\x01 \x03(\tR\vworkflowIds\x12<\n" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1c.goclaw.v1.PaginationRequestR\n" +
-	"pagination\"\xb3\x01\n" +
+	"pagination\x129\n" +
+	"\n" +
+	"field_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\tfieldMask\"\xb3\x01\n" +
 	"\x14WorkflowStatusResult\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x12\x14\n" +
@@ -883,8 +896,9 @@ var file_goclaw_v1_batch_proto_goTypes = []any{
 	(*Error)(nil),                       // 13: goclaw.v1.Error
 	(*PaginationResponse)(nil),          // 14: goclaw.v1.PaginationResponse
 	(*PaginationRequest)(nil),           // 15: goclaw.v1.PaginationRequest
-	(*GetWorkflowStatusResponse)(nil),   // 16: goclaw.v1.GetWorkflowStatusResponse
-	(*GetTaskResultResponse)(nil),       // 17: goclaw.v1.GetTaskResultResponse
+	(*fieldmaskpb.FieldMask)(nil),       // 16: google.protobuf.FieldMask
+	(*GetWorkflowStatusResponse)(nil),   // 17: goclaw.v1.GetWorkflowStatusResponse
+	(*GetTaskResultResponse)(nil),       // 18: goclaw.v1.GetTaskResultResponse
 }
 var file_goclaw_v1_batch_proto_depIdxs = []int32{
 	12, // 0: goclaw.v1.SubmitWorkflowsRequest.workflows:type_name -> goclaw.v1.SubmitWorkflowRequest
@@ -893,33 +907,34 @@ var file_goclaw_v1_batch_proto_depIdxs = []int32{
 	14, // 3: goclaw.v1.SubmitWorkflowsResponse.pagination:type_name -> goclaw.v1.PaginationResponse
 	13, // 4: goclaw.v1.SubmitWorkflowsResponse.error:type_name -> goclaw.v1.Error
 	15, // 5: goclaw.v1.GetWorkflowStatusesRequest.pagination:type_name -> goclaw.v1.PaginationRequest
-	16, // 6: goclaw.v1.WorkflowStatusResult.status:type_name -> goclaw.v1.GetWorkflowStatusResponse
-	13, // 7: goclaw.v1.WorkflowStatusResult.error:type_name -> goclaw.v1.Error
-	4,  // 8: goclaw.v1.GetWorkflowStatusesResponse.results:type_name -> goclaw.v1.WorkflowStatusResult
-	14, // 9: goclaw.v1.GetWorkflowStatusesResponse.pagination:type_name -> goclaw.v1.PaginationResponse
-	13, // 10: goclaw.v1.GetWorkflowStatusesResponse.error:type_name -> goclaw.v1.Error
-	13, // 11: goclaw.v1.WorkflowCancellationResult.error:type_name -> goclaw.v1.Error
-	7,  // 12: goclaw.v1.CancelWorkflowsResponse.results:type_name -> goclaw.v1.WorkflowCancellationResult
-	13, // 13: goclaw.v1.CancelWorkflowsResponse.error:type_name -> goclaw.v1.Error
-	15, // 14: goclaw.v1.GetTaskResultsRequest.pagination:type_name -> goclaw.v1.PaginationRequest
-	17, // 15: goclaw.v1.TaskResultDetail.result:type_name -> goclaw.v1.GetTaskResultResponse
-	13, // 16: goclaw.v1.TaskResultDetail.error:type_name -> goclaw.v1.Error
-	10, // 17: goclaw.v1.GetTaskResultsResponse.results:type_name -> goclaw.v1.TaskResultDetail
-	14, // 18: goclaw.v1.GetTaskResultsResponse.pagination:type_name -> goclaw.v1.PaginationResponse
-	13, // 19: goclaw.v1.GetTaskResultsResponse.error:type_name -> goclaw.v1.Error
-	0,  // 20: goclaw.v1.BatchService.SubmitWorkflows:input_type -> goclaw.v1.SubmitWorkflowsRequest
-	3,  // 21: goclaw.v1.BatchService.GetWorkflowStatuses:input_type -> goclaw.v1.GetWorkflowStatusesRequest
-	6,  // 22: goclaw.v1.BatchService.CancelWorkflows:input_type -> goclaw.v1.CancelWorkflowsRequest
-	9,  // 23: goclaw.v1.BatchService.GetTaskResults:input_type -> goclaw.v1.GetTaskResultsRequest
-	2,  // 24: goclaw.v1.BatchService.SubmitWorkflows:output_type -> goclaw.v1.SubmitWorkflowsResponse
-	5,  // 25: goclaw.v1.BatchService.GetWorkflowStatuses:output_type -> goclaw.v1.GetWorkflowStatusesResponse
-	8,  // 26: goclaw.v1.BatchService.CancelWorkflows:output_type -> goclaw.v1.CancelWorkflowsResponse
-	11, // 27: goclaw.v1.BatchService.GetTaskResults:output_type -> goclaw.v1.GetTaskResultsResponse
-	24, // [24:28] is the sub-list for method output_type
-	20, // [20:24] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	16, // 6: goclaw.v1.GetWorkflowStatusesRequest.field_mask:type_name -> google.protobuf.FieldMask
+	17, // 7: goclaw.v1.WorkflowStatusResult.status:type_name -> goclaw.v1.GetWorkflowStatusResponse
+	13, // 8: goclaw.v1.WorkflowStatusResult.error:type_name -> goclaw.v1.Error
+	4,  // 9: goclaw.v1.GetWorkflowStatusesResponse.results:type_name -> goclaw.v1.WorkflowStatusResult
+	14, // 10: goclaw.v1.GetWorkflowStatusesResponse.pagination:type_name -> goclaw.v1.PaginationResponse
+	13, // 11: goclaw.v1.GetWorkflowStatusesResponse.error:type_name -> goclaw.v1.Error
+	13, // 12: goclaw.v1.WorkflowCancellationResult.error:type_name -> goclaw.v1.Error
+	7,  // 13: goclaw.v1.CancelWorkflowsResponse.results:type_name -> goclaw.v1.WorkflowCancellationResult
+	13, // 14: goclaw.v1.CancelWorkflowsResponse.error:type_name -> goclaw.v1.Error
+	15, // 15: goclaw.v1.GetTaskResultsRequest.pagination:type_name -> goclaw.v1.PaginationRequest
+	18, // 16: goclaw.v1.TaskResultDetail.result:type_name -> goclaw.v1.GetTaskResultResponse
+	13, // 17: goclaw.v1.TaskResultDetail.error:type_name -> goclaw.v1.Error
+	10, // 18: goclaw.v1.GetTaskResultsResponse.results:type_name -> goclaw.v1.TaskResultDetail
+	14, // 19: goclaw.v1.GetTaskResultsResponse.pagination:type_name -> goclaw.v1.PaginationResponse
+	13, // 20: goclaw.v1.GetTaskResultsResponse.error:type_name -> goclaw.v1.Error
+	0,  // 21: goclaw.v1.BatchService.SubmitWorkflows:input_type -> goclaw.v1.SubmitWorkflowsRequest
+	3,  // 22: goclaw.v1.BatchService.GetWorkflowStatuses:input_type -> goclaw.v1.GetWorkflowStatusesRequest
+	6,  // 23: goclaw.v1.BatchService.CancelWorkflows:input_type -> goclaw.v1.CancelWorkflowsRequest
+	9,  // 24: goclaw.v1.BatchService.GetTaskResults:input_type -> goclaw.v1.GetTaskResultsRequest
+	2,  // 25: goclaw.v1.BatchService.SubmitWorkflows:output_type -> goclaw.v1.SubmitWorkflowsResponse
+	5,  // 26: goclaw.v1.BatchService.GetWorkflowStatuses:output_type -> goclaw.v1.GetWorkflowStatusesResponse
+	8,  // 27: goclaw.v1.BatchService.CancelWorkflows:output_type -> goclaw.v1.CancelWorkflowsResponse
+	11, // 28: goclaw.v1.BatchService.GetTaskResults:output_type -> goclaw.v1.GetTaskResultsResponse
+	25, // [25:29] is the sub-list for method output_type
+	21, // [21:25] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_goclaw_v1_batch_proto_init() }
@@ -0,0 +1,819 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.27.3
+// source: goclaw/v1/saga_batch.proto
+
+package pbv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Selects sagas. Sagas listed in saga_ids are selected as is; otherwise all
+// sagas in state with name are, an unspecified state or an empty name
+// matching any.
+type SagaFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaIds       []string               `protobuf:"bytes,1,rep,name=saga_ids,json=sagaIds,proto3" json:"saga_ids,omitempty"`
+	State         SagaState              `protobuf:"varint,2,opt,name=state,proto3,enum=goclaw.v1.SagaState" json:"state,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaFilter) Reset() {
+	*x = SagaFilter{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaFilter) ProtoMessage() {}
+
+func (x *SagaFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaFilter.ProtoReflect.Descriptor instead.
+func (*SagaFilter) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SagaFilter) GetSagaIds() []string {
+	if x != nil {
+		return x.SagaIds
+	}
+	return nil
+}
+
+func (x *SagaFilter) GetState() SagaState {
+	if x != nil {
+		return x.State
+	}
+	return SagaState_SAGA_STATE_UNSPECIFIED
+}
+
+func (x *SagaFilter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Submit sagas request
+type SubmitSagasRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sagas []*SubmitSagaRequest   `protobuf:"bytes,1,rep,name=sagas,proto3" json:"sagas,omitempty"`
+	// Validate every saga before submitting any, and submit none if one is
+	// invalid.
+	Atomic         bool   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Ordered        bool   `protobuf:"varint,4,opt,name=ordered,proto3" json:"ordered,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SubmitSagasRequest) Reset() {
+	*x = SubmitSagasRequest{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitSagasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSagasRequest) ProtoMessage() {}
+
+func (x *SubmitSagasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSagasRequest.ProtoReflect.Descriptor instead.
+func (*SubmitSagasRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitSagasRequest) GetSagas() []*SubmitSagaRequest {
+	if x != nil {
+		return x.Sagas
+	}
+	return nil
+}
+
+func (x *SubmitSagasRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+func (x *SubmitSagasRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *SubmitSagasRequest) GetOrdered() bool {
+	if x != nil {
+		return x.Ordered
+	}
+	return false
+}
+
+// Saga submission result
+type SagaSubmissionResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	SagaId        string                 `protobuf:"bytes,3,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	Error         *Error                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaSubmissionResult) Reset() {
+	*x = SagaSubmissionResult{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaSubmissionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaSubmissionResult) ProtoMessage() {}
+
+func (x *SagaSubmissionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaSubmissionResult.ProtoReflect.Descriptor instead.
+func (*SagaSubmissionResult) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SagaSubmissionResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SagaSubmissionResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SagaSubmissionResult) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+func (x *SagaSubmissionResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Submit sagas response
+type SubmitSagasResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Results       []*SagaSubmissionResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Pagination    *PaginationResponse     `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Error         *Error                  `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitSagasResponse) Reset() {
+	*x = SubmitSagasResponse{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitSagasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSagasResponse) ProtoMessage() {}
+
+func (x *SubmitSagasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSagasResponse.ProtoReflect.Descriptor instead.
+func (*SubmitSagasResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitSagasResponse) GetResults() []*SagaSubmissionResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SubmitSagasResponse) GetPagination() *PaginationResponse {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *SubmitSagasResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Get saga statuses request
+type GetSagaStatusesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *SagaFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Pagination    *PaginationRequest     `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSagaStatusesRequest) Reset() {
+	*x = GetSagaStatusesRequest{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSagaStatusesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSagaStatusesRequest) ProtoMessage() {}
+
+func (x *GetSagaStatusesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSagaStatusesRequest.ProtoReflect.Descriptor instead.
+func (*GetSagaStatusesRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSagaStatusesRequest) GetFilter() *SagaFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetSagaStatusesRequest) GetPagination() *PaginationRequest {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+// Saga status result
+type SagaStatusResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaId        string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Status        *GetSagaStatusResponse `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error         *Error                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaStatusResult) Reset() {
+	*x = SagaStatusResult{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaStatusResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaStatusResult) ProtoMessage() {}
+
+func (x *SagaStatusResult) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaStatusResult.ProtoReflect.Descriptor instead.
+func (*SagaStatusResult) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SagaStatusResult) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+func (x *SagaStatusResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *SagaStatusResult) GetStatus() *GetSagaStatusResponse {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *SagaStatusResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Get saga statuses response
+type GetSagaStatusesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SagaStatusResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Pagination    *PaginationResponse    `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSagaStatusesResponse) Reset() {
+	*x = GetSagaStatusesResponse{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSagaStatusesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSagaStatusesResponse) ProtoMessage() {}
+
+func (x *GetSagaStatusesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSagaStatusesResponse.ProtoReflect.Descriptor instead.
+func (*GetSagaStatusesResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSagaStatusesResponse) GetResults() []*SagaStatusResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *GetSagaStatusesResponse) GetPagination() *PaginationResponse {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *GetSagaStatusesResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Compensate sagas request
+type CompensateSagasRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Filter         *SagaFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Reason         string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	TimeoutSeconds int32                  `protobuf:"varint,4,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CompensateSagasRequest) Reset() {
+	*x = CompensateSagasRequest{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateSagasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateSagasRequest) ProtoMessage() {}
+
+func (x *CompensateSagasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateSagasRequest.ProtoReflect.Descriptor instead.
+func (*CompensateSagasRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CompensateSagasRequest) GetFilter() *SagaFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CompensateSagasRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CompensateSagasRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CompensateSagasRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// Saga compensation result
+type SagaCompensationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaId        string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	State         SagaState              `protobuf:"varint,3,opt,name=state,proto3,enum=goclaw.v1.SagaState" json:"state,omitempty"`
+	Error         *Error                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaCompensationResult) Reset() {
+	*x = SagaCompensationResult{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaCompensationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaCompensationResult) ProtoMessage() {}
+
+func (x *SagaCompensationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaCompensationResult.ProtoReflect.Descriptor instead.
+func (*SagaCompensationResult) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SagaCompensationResult) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+func (x *SagaCompensationResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SagaCompensationResult) GetState() SagaState {
+	if x != nil {
+		return x.State
+	}
+	return SagaState_SAGA_STATE_UNSPECIFIED
+}
+
+func (x *SagaCompensationResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Compensate sagas response
+type CompensateSagasResponse struct {
+	state   protoimpl.MessageState    `protogen:"open.v1"`
+	Results []*SagaCompensationResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// Sagas matching the filter beyond the batch size, left for another call.
+	Remaining     int32  `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Error         *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompensateSagasResponse) Reset() {
+	*x = CompensateSagasResponse{}
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateSagasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateSagasResponse) ProtoMessage() {}
+
+func (x *CompensateSagasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_saga_batch_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateSagasResponse.ProtoReflect.Descriptor instead.
+func (*CompensateSagasResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_saga_batch_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CompensateSagasResponse) GetResults() []*SagaCompensationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *CompensateSagasResponse) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *CompensateSagasResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+var File_goclaw_v1_saga_batch_proto protoreflect.FileDescriptor
+
+const file_goclaw_v1_saga_batch_proto_rawDesc = "" +
+	"\n" +
+	"\x1agoclaw/v1/saga_batch.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x14goclaw/v1/saga.proto\"g\n" +
+	"\n" +
+	"SagaFilter\x12\x19\n" +
+	"\bsaga_ids\x18\x01 \x03(\tR\asagaIds\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.goclaw.v1.SagaStateR\x05state\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\xa3\x01\n" +
+	"\x12SubmitSagasRequest\x122\n" +
+	"\x05sagas\x18\x01 \x03(\v2\x1c.goclaw.v1.SubmitSagaRequestR\x05sagas\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x18\n" +
+	"\aordered\x18\x04 \x01(\bR\aordered\"\x87\x01\n" +
+	"\x14SagaSubmissionResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x17\n" +
+	"\asaga_id\x18\x03 \x01(\tR\x06sagaId\x12&\n" +
+	"\x05error\x18\x04 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\xb7\x01\n" +
+	"\x13SubmitSagasResponse\x129\n" +
+	"\aresults\x18\x01 \x03(\v2\x1f.goclaw.v1.SagaSubmissionResultR\aresults\x12=\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1d.goclaw.v1.PaginationResponseR\n" +
+	"pagination\x12&\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\x85\x01\n" +
+	"\x16GetSagaStatusesRequest\x12-\n" +
+	"\x06filter\x18\x01 \x01(\v2\x15.goclaw.v1.SagaFilterR\x06filter\x12<\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1c.goclaw.v1.PaginationRequestR\n" +
+	"pagination\"\xa3\x01\n" +
+	"\x10SagaStatusResult\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x128\n" +
+	"\x06status\x18\x03 \x01(\v2 .goclaw.v1.GetSagaStatusResponseR\x06status\x12&\n" +
+	"\x05error\x18\x04 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\xb7\x01\n" +
+	"\x17GetSagaStatusesResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.goclaw.v1.SagaStatusResultR\aresults\x12=\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1d.goclaw.v1.PaginationResponseR\n" +
+	"pagination\x12&\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\xb1\x01\n" +
+	"\x16CompensateSagasRequest\x12-\n" +
+	"\x06filter\x18\x01 \x01(\v2\x15.goclaw.v1.SagaFilterR\x06filter\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12'\n" +
+	"\x0ftimeout_seconds\x18\x04 \x01(\x05R\x0etimeoutSeconds\"\x9f\x01\n" +
+	"\x16SagaCompensationResult\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12*\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x14.goclaw.v1.SagaStateR\x05state\x12&\n" +
+	"\x05error\x18\x04 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\x9c\x01\n" +
+	"\x17CompensateSagasResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.goclaw.v1.SagaCompensationResultR\aresults\x12\x1c\n" +
+	"\tremaining\x18\x02 \x01(\x05R\tremaining\x12&\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error2\x94\x02\n" +
+	"\x10SagaBatchService\x12L\n" +
+	"\vSubmitSagas\x12\x1d.goclaw.v1.SubmitSagasRequest\x1a\x1e.goclaw.v1.SubmitSagasResponse\x12X\n" +
+	"\x0fGetSagaStatuses\x12!.goclaw.v1.GetSagaStatusesRequest\x1a\".goclaw.v1.GetSagaStatusesResponse\x12X\n" +
+	"\x0fCompensateSagas\x12!.goclaw.v1.CompensateSagasRequest\x1a\".goclaw.v1.CompensateSagasResponseB.Z,github.com/goclaw/goclaw/pkg/grpc/pb/v1;pbv1b\x06proto3"
+
+var (
+	file_goclaw_v1_saga_batch_proto_rawDescOnce sync.Once
+	file_goclaw_v1_saga_batch_proto_rawDescData []byte
+)
+
+func file_goclaw_v1_saga_batch_proto_rawDescGZIP() []byte {
+	file_goclaw_v1_saga_batch_proto_rawDescOnce.Do(func() {
+		file_goclaw_v1_saga_batch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goclaw_v1_saga_batch_proto_rawDesc), len(file_goclaw_v1_saga_batch_proto_rawDesc)))
+	})
+	return file_goclaw_v1_saga_batch_proto_rawDescData
+}
+
+var file_goclaw_v1_saga_batch_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_goclaw_v1_saga_batch_proto_goTypes = []any{
+	(*SagaFilter)(nil),              // 0: goclaw.v1.SagaFilter
+	(*SubmitSagasRequest)(nil),      // 1: goclaw.v1.SubmitSagasRequest
+	(*SagaSubmissionResult)(nil),    // 2: goclaw.v1.SagaSubmissionResult
+	(*SubmitSagasResponse)(nil),     // 3: goclaw.v1.SubmitSagasResponse
+	(*GetSagaStatusesRequest)(nil),  // 4: goclaw.v1.GetSagaStatusesRequest
+	(*SagaStatusResult)(nil),        // 5: goclaw.v1.SagaStatusResult
+	(*GetSagaStatusesResponse)(nil), // 6: goclaw.v1.GetSagaStatusesResponse
+	(*CompensateSagasRequest)(nil),  // 7: goclaw.v1.CompensateSagasRequest
+	(*SagaCompensationResult)(nil),  // 8: goclaw.v1.SagaCompensationResult
+	(*CompensateSagasResponse)(nil), // 9: goclaw.v1.CompensateSagasResponse
+	(SagaState)(0),                  // 10: goclaw.v1.SagaState
+	(*SubmitSagaRequest)(nil),       // 11: goclaw.v1.SubmitSagaRequest
+	(*Error)(nil),                   // 12: goclaw.v1.Error
+	(*PaginationResponse)(nil),      // 13: goclaw.v1.PaginationResponse
+	(*PaginationRequest)(nil),       // 14: goclaw.v1.PaginationRequest
+	(*GetSagaStatusResponse)(nil),   // 15: goclaw.v1.GetSagaStatusResponse
+}
+var file_goclaw_v1_saga_batch_proto_depIdxs = []int32{
+	10, // 0: goclaw.v1.SagaFilter.state:type_name -> goclaw.v1.SagaState
+	11, // 1: goclaw.v1.SubmitSagasRequest.sagas:type_name -> goclaw.v1.SubmitSagaRequest
+	12, // 2: goclaw.v1.SagaSubmissionResult.error:type_name -> goclaw.v1.Error
+	2,  // 3: goclaw.v1.SubmitSagasResponse.results:type_name -> goclaw.v1.SagaSubmissionResult
+	13, // 4: goclaw.v1.SubmitSagasResponse.pagination:type_name -> goclaw.v1.PaginationResponse
+	12, // 5: goclaw.v1.SubmitSagasResponse.error:type_name -> goclaw.v1.Error
+	0,  // 6: goclaw.v1.GetSagaStatusesRequest.filter:type_name -> goclaw.v1.SagaFilter
+	14, // 7: goclaw.v1.GetSagaStatusesRequest.pagination:type_name -> goclaw.v1.PaginationRequest
+	15, // 8: goclaw.v1.SagaStatusResult.status:type_name -> goclaw.v1.GetSagaStatusResponse
+	12, // 9: goclaw.v1.SagaStatusResult.error:type_name -> goclaw.v1.Error
+	5,  // 10: goclaw.v1.GetSagaStatusesResponse.results:type_name -> goclaw.v1.SagaStatusResult
+	13, // 11: goclaw.v1.GetSagaStatusesResponse.pagination:type_name -> goclaw.v1.PaginationResponse
+	12, // 12: goclaw.v1.GetSagaStatusesResponse.error:type_name -> goclaw.v1.Error
+	0,  // 13: goclaw.v1.CompensateSagasRequest.filter:type_name -> goclaw.v1.SagaFilter
+	10, // 14: goclaw.v1.SagaCompensationResult.state:type_name -> goclaw.v1.SagaState
+	12, // 15: goclaw.v1.SagaCompensationResult.error:type_name -> goclaw.v1.Error
+	8,  // 16: goclaw.v1.CompensateSagasResponse.results:type_name -> goclaw.v1.SagaCompensationResult
+	12, // 17: goclaw.v1.CompensateSagasResponse.error:type_name -> goclaw.v1.Error
+	1,  // 18: goclaw.v1.SagaBatchService.SubmitSagas:input_type -> goclaw.v1.SubmitSagasRequest
+	4,  // 19: goclaw.v1.SagaBatchService.GetSagaStatuses:input_type -> goclaw.v1.GetSagaStatusesRequest
+	7,  // 20: goclaw.v1.SagaBatchService.CompensateSagas:input_type -> goclaw.v1.CompensateSagasRequest
+	3,  // 21: goclaw.v1.SagaBatchService.SubmitSagas:output_type -> goclaw.v1.SubmitSagasResponse
+	6,  // 22: goclaw.v1.SagaBatchService.GetSagaStatuses:output_type -> goclaw.v1.GetSagaStatusesResponse
+	9,  // 23: goclaw.v1.SagaBatchService.CompensateSagas:output_type -> goclaw.v1.CompensateSagasResponse
+	21, // [21:24] is the sub-list for method output_type
+	18, // [18:21] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_goclaw_v1_saga_batch_proto_init() }
+func file_goclaw_v1_saga_batch_proto_init() {
+	if File_goclaw_v1_saga_batch_proto != nil {
+		return
+	}
+	file_goclaw_v1_common_proto_init()
+	file_goclaw_v1_saga_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goclaw_v1_saga_batch_proto_rawDesc), len(file_goclaw_v1_saga_batch_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_goclaw_v1_saga_batch_proto_goTypes,
+		DependencyIndexes: file_goclaw_v1_saga_batch_proto_depIdxs,
+		MessageInfos:      file_goclaw_v1_saga_batch_proto_msgTypes,
+	}.Build()
+	File_goclaw_v1_saga_batch_proto = out.File
+	file_goclaw_v1_saga_batch_proto_goTypes = nil
+	file_goclaw_v1_saga_batch_proto_depIdxs = nil
+}
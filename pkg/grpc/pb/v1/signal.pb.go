@@ -9,6 +9,7 @@ package pbv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -289,11 +290,545 @@ func (x *SignalTaskResponse) GetError() *Error {
 	return nil
 }
 
+// AckSignalRequest acknowledges a signal delivered at least once.
+type AckSignalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	DeliveryId    string                 `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckSignalRequest) Reset() {
+	*x = AckSignalRequest{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckSignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckSignalRequest) ProtoMessage() {}
+
+func (x *AckSignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckSignalRequest.ProtoReflect.Descriptor instead.
+func (*AckSignalRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AckSignalRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AckSignalRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+// AckSignalResponse reports the result of an acknowledgement.
+type AckSignalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         *Error                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckSignalResponse) Reset() {
+	*x = AckSignalResponse{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckSignalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckSignalResponse) ProtoMessage() {}
+
+func (x *AckSignalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckSignalResponse.ProtoReflect.Descriptor instead.
+func (*AckSignalResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AckSignalResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AckSignalResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// ListPendingSignalsRequest lists the unacknowledged signals of a task.
+type ListPendingSignalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingSignalsRequest) Reset() {
+	*x = ListPendingSignalsRequest{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingSignalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingSignalsRequest) ProtoMessage() {}
+
+func (x *ListPendingSignalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingSignalsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingSignalsRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPendingSignalsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// PendingSignal is a delivered signal awaiting acknowledgement.
+type PendingSignal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Consumer      string                 `protobuf:"bytes,3,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	IdleMs        int64                  `protobuf:"varint,4,opt,name=idle_ms,json=idleMs,proto3" json:"idle_ms,omitempty"`
+	Deliveries    int64                  `protobuf:"varint,5,opt,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingSignal) Reset() {
+	*x = PendingSignal{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingSignal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingSignal) ProtoMessage() {}
+
+func (x *PendingSignal) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingSignal.ProtoReflect.Descriptor instead.
+func (*PendingSignal) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PendingSignal) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *PendingSignal) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *PendingSignal) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *PendingSignal) GetIdleMs() int64 {
+	if x != nil {
+		return x.IdleMs
+	}
+	return 0
+}
+
+func (x *PendingSignal) GetDeliveries() int64 {
+	if x != nil {
+		return x.Deliveries
+	}
+	return 0
+}
+
+// ListPendingSignalsResponse holds the pending signals of a task.
+type ListPendingSignalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signals       []*PendingSignal       `protobuf:"bytes,1,rep,name=signals,proto3" json:"signals,omitempty"`
+	Error         *Error                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingSignalsResponse) Reset() {
+	*x = ListPendingSignalsResponse{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingSignalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingSignalsResponse) ProtoMessage() {}
+
+func (x *ListPendingSignalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingSignalsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingSignalsResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPendingSignalsResponse) GetSignals() []*PendingSignal {
+	if x != nil {
+		return x.Signals
+	}
+	return nil
+}
+
+func (x *ListPendingSignalsResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// GetDeliveryStatsRequest requests the signal bus delivery counters.
+type GetDeliveryStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatsRequest) Reset() {
+	*x = GetDeliveryStatsRequest{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsRequest) ProtoMessage() {}
+
+func (x *GetDeliveryStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{8}
+}
+
+// GetDeliveryStatsResponse holds cumulative signal bus delivery counters.
+type GetDeliveryStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AckEnabled    bool                   `protobuf:"varint,1,opt,name=ack_enabled,json=ackEnabled,proto3" json:"ack_enabled,omitempty"`
+	Published     uint64                 `protobuf:"varint,2,opt,name=published,proto3" json:"published,omitempty"`
+	Delivered     uint64                 `protobuf:"varint,3,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Acked         uint64                 `protobuf:"varint,4,opt,name=acked,proto3" json:"acked,omitempty"`
+	Redelivered   uint64                 `protobuf:"varint,5,opt,name=redelivered,proto3" json:"redelivered,omitempty"`
+	DeadLettered  uint64                 `protobuf:"varint,6,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatsResponse) Reset() {
+	*x = GetDeliveryStatsResponse{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsResponse) ProtoMessage() {}
+
+func (x *GetDeliveryStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsResponse) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetDeliveryStatsResponse) GetAckEnabled() bool {
+	if x != nil {
+		return x.AckEnabled
+	}
+	return false
+}
+
+func (x *GetDeliveryStatsResponse) GetPublished() uint64 {
+	if x != nil {
+		return x.Published
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatsResponse) GetDelivered() uint64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatsResponse) GetAcked() uint64 {
+	if x != nil {
+		return x.Acked
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatsResponse) GetRedelivered() uint64 {
+	if x != nil {
+		return x.Redelivered
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatsResponse) GetDeadLettered() uint64 {
+	if x != nil {
+		return x.DeadLettered
+	}
+	return 0
+}
+
+// ReplaySignalsRequest replays the recorded history of a signal channel.
+type ReplaySignalsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// channel is the task ID the signals were sent to.
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaySignalsRequest) Reset() {
+	*x = ReplaySignalsRequest{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaySignalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaySignalsRequest) ProtoMessage() {}
+
+func (x *ReplaySignalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaySignalsRequest.ProtoReflect.Descriptor instead.
+func (*ReplaySignalsRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReplaySignalsRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ReplaySignalsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ReplaySignalsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SignalRecord is a signal recorded in a channel's history.
+type SignalRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          SignalType             `protobuf:"varint,1,opt,name=type,proto3,enum=goclaw.v1.SignalType" json:"type,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalRecord) Reset() {
+	*x = SignalRecord{}
+	mi := &file_goclaw_v1_signal_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalRecord) ProtoMessage() {}
+
+func (x *SignalRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_signal_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalRecord.ProtoReflect.Descriptor instead.
+func (*SignalRecord) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_signal_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SignalRecord) GetType() SignalType {
+	if x != nil {
+		return x.Type
+	}
+	return SignalType_SIGNAL_TYPE_UNSPECIFIED
+}
+
+func (x *SignalRecord) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *SignalRecord) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SignalRecord) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
 var File_goclaw_v1_signal_proto protoreflect.FileDescriptor
 
 const file_goclaw_v1_signal_proto_rawDesc = "" +
 	"\n" +
-	"\x16goclaw/v1/signal.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\"\xd2\x02\n" +
+	"\x16goclaw/v1/signal.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd2\x02\n" +
 	"\x11SignalTaskRequest\x12)\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x15.goclaw.v1.SignalTypeR\x04type\x12\x17\n" +
 	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x19\n" +
@@ -316,16 +851,59 @@ const file_goclaw_v1_signal_proto_rawDesc = "" +
 	"\x12SignalTaskResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x122\n" +
 	"\aresults\x18\x02 \x03(\v2\x18.goclaw.v1.CollectResultR\aresults\x12&\n" +
-	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error*t\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"L\n" +
+	"\x10AckSignalRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1f\n" +
+	"\vdelivery_id\x18\x02 \x01(\tR\n" +
+	"deliveryId\"U\n" +
+	"\x11AckSignalResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12&\n" +
+	"\x05error\x18\x02 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"4\n" +
+	"\x19ListPendingSignalsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x9e\x01\n" +
+	"\rPendingSignal\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bconsumer\x18\x03 \x01(\tR\bconsumer\x12\x17\n" +
+	"\aidle_ms\x18\x04 \x01(\x03R\x06idleMs\x12\x1e\n" +
+	"\n" +
+	"deliveries\x18\x05 \x01(\x03R\n" +
+	"deliveries\"x\n" +
+	"\x1aListPendingSignalsResponse\x122\n" +
+	"\asignals\x18\x01 \x03(\v2\x18.goclaw.v1.PendingSignalR\asignals\x12&\n" +
+	"\x05error\x18\x02 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\x19\n" +
+	"\x17GetDeliveryStatsRequest\"\xd4\x01\n" +
+	"\x18GetDeliveryStatsResponse\x12\x1f\n" +
+	"\vack_enabled\x18\x01 \x01(\bR\n" +
+	"ackEnabled\x12\x1c\n" +
+	"\tpublished\x18\x02 \x01(\x04R\tpublished\x12\x1c\n" +
+	"\tdelivered\x18\x03 \x01(\x04R\tdelivered\x12\x14\n" +
+	"\x05acked\x18\x04 \x01(\x04R\x05acked\x12 \n" +
+	"\vredelivered\x18\x05 \x01(\x04R\vredelivered\x12#\n" +
+	"\rdead_lettered\x18\x06 \x01(\x04R\fdeadLettered\"x\n" +
+	"\x14ReplaySignalsRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xa1\x01\n" +
+	"\fSignalRecord\x12)\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x15.goclaw.v1.SignalTypeR\x04type\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x123\n" +
+	"\asent_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt*t\n" +
 	"\n" +
 	"SignalType\x12\x1b\n" +
 	"\x17SIGNAL_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11SIGNAL_TYPE_STEER\x10\x01\x12\x19\n" +
 	"\x15SIGNAL_TYPE_INTERRUPT\x10\x02\x12\x17\n" +
-	"\x13SIGNAL_TYPE_COLLECT\x10\x032Z\n" +
+	"\x13SIGNAL_TYPE_COLLECT\x10\x032\xaf\x03\n" +
 	"\rSignalService\x12I\n" +
 	"\n" +
-	"SignalTask\x12\x1c.goclaw.v1.SignalTaskRequest\x1a\x1d.goclaw.v1.SignalTaskResponseB.Z,github.com/goclaw/goclaw/pkg/grpc/pb/v1;pbv1b\x06proto3"
+	"SignalTask\x12\x1c.goclaw.v1.SignalTaskRequest\x1a\x1d.goclaw.v1.SignalTaskResponse\x12F\n" +
+	"\tAckSignal\x12\x1b.goclaw.v1.AckSignalRequest\x1a\x1c.goclaw.v1.AckSignalResponse\x12a\n" +
+	"\x12ListPendingSignals\x12$.goclaw.v1.ListPendingSignalsRequest\x1a%.goclaw.v1.ListPendingSignalsResponse\x12[\n" +
+	"\x10GetDeliveryStats\x12\".goclaw.v1.GetDeliveryStatsRequest\x1a#.goclaw.v1.GetDeliveryStatsResponse\x12K\n" +
+	"\rReplaySignals\x12\x1f.goclaw.v1.ReplaySignalsRequest\x1a\x17.goclaw.v1.SignalRecord0\x01B.Z,github.com/goclaw/goclaw/pkg/grpc/pb/v1;pbv1b\x06proto3"
 
 var (
 	file_goclaw_v1_signal_proto_rawDescOnce sync.Once
@@ -340,27 +918,51 @@ func file_goclaw_v1_signal_proto_rawDescGZIP() []byte {
 }
 
 var file_goclaw_v1_signal_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_goclaw_v1_signal_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_goclaw_v1_signal_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_goclaw_v1_signal_proto_goTypes = []any{
-	(SignalType)(0),            // 0: goclaw.v1.SignalType
-	(*SignalTaskRequest)(nil),  // 1: goclaw.v1.SignalTaskRequest
-	(*CollectResult)(nil),      // 2: goclaw.v1.CollectResult
-	(*SignalTaskResponse)(nil), // 3: goclaw.v1.SignalTaskResponse
-	nil,                        // 4: goclaw.v1.SignalTaskRequest.ParametersEntry
-	(*Error)(nil),              // 5: goclaw.v1.Error
+	(SignalType)(0),                    // 0: goclaw.v1.SignalType
+	(*SignalTaskRequest)(nil),          // 1: goclaw.v1.SignalTaskRequest
+	(*CollectResult)(nil),              // 2: goclaw.v1.CollectResult
+	(*SignalTaskResponse)(nil),         // 3: goclaw.v1.SignalTaskResponse
+	(*AckSignalRequest)(nil),           // 4: goclaw.v1.AckSignalRequest
+	(*AckSignalResponse)(nil),          // 5: goclaw.v1.AckSignalResponse
+	(*ListPendingSignalsRequest)(nil),  // 6: goclaw.v1.ListPendingSignalsRequest
+	(*PendingSignal)(nil),              // 7: goclaw.v1.PendingSignal
+	(*ListPendingSignalsResponse)(nil), // 8: goclaw.v1.ListPendingSignalsResponse
+	(*GetDeliveryStatsRequest)(nil),    // 9: goclaw.v1.GetDeliveryStatsRequest
+	(*GetDeliveryStatsResponse)(nil),   // 10: goclaw.v1.GetDeliveryStatsResponse
+	(*ReplaySignalsRequest)(nil),       // 11: goclaw.v1.ReplaySignalsRequest
+	(*SignalRecord)(nil),               // 12: goclaw.v1.SignalRecord
+	nil,                                // 13: goclaw.v1.SignalTaskRequest.ParametersEntry
+	(*Error)(nil),                      // 14: goclaw.v1.Error
+	(*timestamppb.Timestamp)(nil),      // 15: google.protobuf.Timestamp
 }
 var file_goclaw_v1_signal_proto_depIdxs = []int32{
-	0, // 0: goclaw.v1.SignalTaskRequest.type:type_name -> goclaw.v1.SignalType
-	4, // 1: goclaw.v1.SignalTaskRequest.parameters:type_name -> goclaw.v1.SignalTaskRequest.ParametersEntry
-	2, // 2: goclaw.v1.SignalTaskResponse.results:type_name -> goclaw.v1.CollectResult
-	5, // 3: goclaw.v1.SignalTaskResponse.error:type_name -> goclaw.v1.Error
-	1, // 4: goclaw.v1.SignalService.SignalTask:input_type -> goclaw.v1.SignalTaskRequest
-	3, // 5: goclaw.v1.SignalService.SignalTask:output_type -> goclaw.v1.SignalTaskResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: goclaw.v1.SignalTaskRequest.type:type_name -> goclaw.v1.SignalType
+	13, // 1: goclaw.v1.SignalTaskRequest.parameters:type_name -> goclaw.v1.SignalTaskRequest.ParametersEntry
+	2,  // 2: goclaw.v1.SignalTaskResponse.results:type_name -> goclaw.v1.CollectResult
+	14, // 3: goclaw.v1.SignalTaskResponse.error:type_name -> goclaw.v1.Error
+	14, // 4: goclaw.v1.AckSignalResponse.error:type_name -> goclaw.v1.Error
+	7,  // 5: goclaw.v1.ListPendingSignalsResponse.signals:type_name -> goclaw.v1.PendingSignal
+	14, // 6: goclaw.v1.ListPendingSignalsResponse.error:type_name -> goclaw.v1.Error
+	15, // 7: goclaw.v1.ReplaySignalsRequest.since:type_name -> google.protobuf.Timestamp
+	0,  // 8: goclaw.v1.SignalRecord.type:type_name -> goclaw.v1.SignalType
+	15, // 9: goclaw.v1.SignalRecord.sent_at:type_name -> google.protobuf.Timestamp
+	1,  // 10: goclaw.v1.SignalService.SignalTask:input_type -> goclaw.v1.SignalTaskRequest
+	4,  // 11: goclaw.v1.SignalService.AckSignal:input_type -> goclaw.v1.AckSignalRequest
+	6,  // 12: goclaw.v1.SignalService.ListPendingSignals:input_type -> goclaw.v1.ListPendingSignalsRequest
+	9,  // 13: goclaw.v1.SignalService.GetDeliveryStats:input_type -> goclaw.v1.GetDeliveryStatsRequest
+	11, // 14: goclaw.v1.SignalService.ReplaySignals:input_type -> goclaw.v1.ReplaySignalsRequest
+	3,  // 15: goclaw.v1.SignalService.SignalTask:output_type -> goclaw.v1.SignalTaskResponse
+	5,  // 16: goclaw.v1.SignalService.AckSignal:output_type -> goclaw.v1.AckSignalResponse
+	8,  // 17: goclaw.v1.SignalService.ListPendingSignals:output_type -> goclaw.v1.ListPendingSignalsResponse
+	10, // 18: goclaw.v1.SignalService.GetDeliveryStats:output_type -> goclaw.v1.GetDeliveryStatsResponse
+	12, // 19: goclaw.v1.SignalService.ReplaySignals:output_type -> goclaw.v1.SignalRecord
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_goclaw_v1_signal_proto_init() }
@@ -375,7 +977,7 @@ func file_goclaw_v1_signal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goclaw_v1_signal_proto_rawDesc), len(file_goclaw_v1_signal_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SignalService_SignalTask_FullMethodName = "/goclaw.v1.SignalService/SignalTask"
+	SignalService_SignalTask_FullMethodName         = "/goclaw.v1.SignalService/SignalTask"
+	SignalService_AckSignal_FullMethodName          = "/goclaw.v1.SignalService/AckSignal"
+	SignalService_ListPendingSignals_FullMethodName = "/goclaw.v1.SignalService/ListPendingSignals"
+	SignalService_GetDeliveryStats_FullMethodName   = "/goclaw.v1.SignalService/GetDeliveryStats"
+	SignalService_ReplaySignals_FullMethodName      = "/goclaw.v1.SignalService/ReplaySignals"
 )
 
 // SignalServiceClient is the client API for SignalService service.
@@ -29,6 +33,10 @@ const (
 // SignalService provides task signaling operations.
 type SignalServiceClient interface {
 	SignalTask(ctx context.Context, in *SignalTaskRequest, opts ...grpc.CallOption) (*SignalTaskResponse, error)
+	AckSignal(ctx context.Context, in *AckSignalRequest, opts ...grpc.CallOption) (*AckSignalResponse, error)
+	ListPendingSignals(ctx context.Context, in *ListPendingSignalsRequest, opts ...grpc.CallOption) (*ListPendingSignalsResponse, error)
+	GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsRequest, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error)
+	ReplaySignals(ctx context.Context, in *ReplaySignalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SignalRecord], error)
 }
 
 type signalServiceClient struct {
@@ -49,6 +57,55 @@ func (c *signalServiceClient) SignalTask(ctx context.Context, in *SignalTaskRequ
 	return out, nil
 }
 
+func (c *signalServiceClient) AckSignal(ctx context.Context, in *AckSignalRequest, opts ...grpc.CallOption) (*AckSignalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AckSignalResponse)
+	err := c.cc.Invoke(ctx, SignalService_AckSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalServiceClient) ListPendingSignals(ctx context.Context, in *ListPendingSignalsRequest, opts ...grpc.CallOption) (*ListPendingSignalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingSignalsResponse)
+	err := c.cc.Invoke(ctx, SignalService_ListPendingSignals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalServiceClient) GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsRequest, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeliveryStatsResponse)
+	err := c.cc.Invoke(ctx, SignalService_GetDeliveryStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalServiceClient) ReplaySignals(ctx context.Context, in *ReplaySignalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SignalRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SignalService_ServiceDesc.Streams[0], SignalService_ReplaySignals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReplaySignalsRequest, SignalRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SignalService_ReplaySignalsClient = grpc.ServerStreamingClient[SignalRecord]
+
 // SignalServiceServer is the server API for SignalService service.
 // All implementations must embed UnimplementedSignalServiceServer
 // for forward compatibility.
@@ -56,6 +113,10 @@ func (c *signalServiceClient) SignalTask(ctx context.Context, in *SignalTaskRequ
 // SignalService provides task signaling operations.
 type SignalServiceServer interface {
 	SignalTask(context.Context, *SignalTaskRequest) (*SignalTaskResponse, error)
+	AckSignal(context.Context, *AckSignalRequest) (*AckSignalResponse, error)
+	ListPendingSignals(context.Context, *ListPendingSignalsRequest) (*ListPendingSignalsResponse, error)
+	GetDeliveryStats(context.Context, *GetDeliveryStatsRequest) (*GetDeliveryStatsResponse, error)
+	ReplaySignals(*ReplaySignalsRequest, grpc.ServerStreamingServer[SignalRecord]) error
 	mustEmbedUnimplementedSignalServiceServer()
 }
 
@@ -69,6 +130,18 @@ type UnimplementedSignalServiceServer struct{}
 func (UnimplementedSignalServiceServer) SignalTask(context.Context, *SignalTaskRequest) (*SignalTaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SignalTask not implemented")
 }
+func (UnimplementedSignalServiceServer) AckSignal(context.Context, *AckSignalRequest) (*AckSignalResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AckSignal not implemented")
+}
+func (UnimplementedSignalServiceServer) ListPendingSignals(context.Context, *ListPendingSignalsRequest) (*ListPendingSignalsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPendingSignals not implemented")
+}
+func (UnimplementedSignalServiceServer) GetDeliveryStats(context.Context, *GetDeliveryStatsRequest) (*GetDeliveryStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDeliveryStats not implemented")
+}
+func (UnimplementedSignalServiceServer) ReplaySignals(*ReplaySignalsRequest, grpc.ServerStreamingServer[SignalRecord]) error {
+	return status.Error(codes.Unimplemented, "method ReplaySignals not implemented")
+}
 func (UnimplementedSignalServiceServer) mustEmbedUnimplementedSignalServiceServer() {}
 func (UnimplementedSignalServiceServer) testEmbeddedByValue()                       {}
 
@@ -108,6 +181,71 @@ func _SignalService_SignalTask_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SignalService_AckSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckSignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalServiceServer).AckSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalService_AckSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalServiceServer).AckSignal(ctx, req.(*AckSignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignalService_ListPendingSignals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingSignalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalServiceServer).ListPendingSignals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalService_ListPendingSignals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalServiceServer).ListPendingSignals(ctx, req.(*ListPendingSignalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignalService_GetDeliveryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalServiceServer).GetDeliveryStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalService_GetDeliveryStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalServiceServer).GetDeliveryStats(ctx, req.(*GetDeliveryStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignalService_ReplaySignals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReplaySignalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SignalServiceServer).ReplaySignals(m, &grpc.GenericServerStream[ReplaySignalsRequest, SignalRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SignalService_ReplaySignalsServer = grpc.ServerStreamingServer[SignalRecord]
+
 // SignalService_ServiceDesc is the grpc.ServiceDesc for SignalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -119,7 +257,25 @@ var SignalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignalTask",
 			Handler:    _SignalService_SignalTask_Handler,
 		},
+		{
+			MethodName: "AckSignal",
+			Handler:    _SignalService_AckSignal_Handler,
+		},
+		{
+			MethodName: "ListPendingSignals",
+			Handler:    _SignalService_ListPendingSignals_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStats",
+			Handler:    _SignalService_GetDeliveryStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReplaySignals",
+			Handler:       _SignalService_ReplaySignals_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "goclaw/v1/signal.proto",
 }
@@ -140,8 +140,10 @@ type WorkflowStatusUpdate struct {
 	Status         WorkflowStatus         `protobuf:"varint,4,opt,name=status,proto3,enum=goclaw.v1.WorkflowStatus" json:"status,omitempty"`
 	Message        string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
 	Error          *Error                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// ID of the request that submitted the workflow.
+	RequestId     string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WorkflowStatusUpdate) Reset() {
@@ -216,6 +218,13 @@ func (x *WorkflowStatusUpdate) GetError() *Error {
 	return nil
 }
 
+func (x *WorkflowStatusUpdate) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 // Watch tasks request
 type WatchTasksRequest struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
@@ -296,8 +305,14 @@ type TaskProgressUpdate struct {
 	ProgressPercent int32                  `protobuf:"varint,6,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
 	Message         string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
 	Error           *Error                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// ID of the request that submitted the workflow.
+	RequestId string `protobuf:"bytes,9,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Next chunk of the output a running task streams, such as LLM tokens.
+	OutputChunk string `protobuf:"bytes,10,opt,name=output_chunk,json=outputChunk,proto3" json:"output_chunk,omitempty"`
+	// Number of the chunk in the task attempt, from 1.
+	OutputSeq     int64 `protobuf:"varint,11,opt,name=output_seq,json=outputSeq,proto3" json:"output_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TaskProgressUpdate) Reset() {
@@ -386,6 +401,27 @@ func (x *TaskProgressUpdate) GetError() *Error {
 	return nil
 }
 
+func (x *TaskProgressUpdate) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *TaskProgressUpdate) GetOutputChunk() string {
+	if x != nil {
+		return x.OutputChunk
+	}
+	return ""
+}
+
+func (x *TaskProgressUpdate) GetOutputSeq() int64 {
+	if x != nil {
+		return x.OutputSeq
+	}
+	return 0
+}
+
 // Log stream request (client to server)
 type LogStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -593,7 +629,7 @@ const file_goclaw_v1_streaming_proto_rawDesc = "" +
 	"\x14WatchWorkflowRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x120\n" +
-	"\x14resume_from_sequence\x18\x02 \x01(\x03R\x12resumeFromSequence\"\x8f\x02\n" +
+	"\x14resume_from_sequence\x18\x02 \x01(\x03R\x12resumeFromSequence\"\xae\x02\n" +
 	"\x14WorkflowStatusUpdate\x12'\n" +
 	"\x0fsequence_number\x18\x01 \x01(\x03R\x0esequenceNumber\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1f\n" +
@@ -601,13 +637,15 @@ const file_goclaw_v1_streaming_proto_rawDesc = "" +
 	"workflowId\x121\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x19.goclaw.v1.WorkflowStatusR\x06status\x12\x18\n" +
 	"\amessage\x18\x05 \x01(\tR\amessage\x12&\n" +
-	"\x05error\x18\x06 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\xa6\x01\n" +
+	"\x05error\x18\x06 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\"\xa6\x01\n" +
 	"\x11WatchTasksRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x12\x19\n" +
 	"\btask_ids\x18\x02 \x03(\tR\ataskIds\x12#\n" +
 	"\rterminal_only\x18\x03 \x01(\bR\fterminalOnly\x120\n" +
-	"\x14resume_from_sequence\x18\x04 \x01(\x03R\x12resumeFromSequence\"\xcd\x02\n" +
+	"\x14resume_from_sequence\x18\x04 \x01(\x03R\x12resumeFromSequence\"\xae\x03\n" +
 	"\x12TaskProgressUpdate\x12'\n" +
 	"\x0fsequence_number\x18\x01 \x01(\x03R\x0esequenceNumber\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1f\n" +
@@ -617,7 +655,13 @@ const file_goclaw_v1_streaming_proto_rawDesc = "" +
 	"\x06status\x18\x05 \x01(\x0e2\x15.goclaw.v1.TaskStatusR\x06status\x12)\n" +
 	"\x10progress_percent\x18\x06 \x01(\x05R\x0fprogressPercent\x12\x18\n" +
 	"\amessage\x18\a \x01(\tR\amessage\x12&\n" +
-	"\x05error\x18\b \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\x80\x01\n" +
+	"\x05error\x18\b \x01(\v2\x10.goclaw.v1.ErrorR\x05error\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\t \x01(\tR\trequestId\x12!\n" +
+	"\foutput_chunk\x18\n" +
+	" \x01(\tR\voutputChunk\x12\x1d\n" +
+	"\n" +
+	"output_seq\x18\v \x01(\x03R\toutputSeq\"\x80\x01\n" +
 	"\x10LogStreamRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x120\n" +
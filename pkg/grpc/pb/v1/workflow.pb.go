@@ -9,6 +9,7 @@ package pbv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -514,17 +515,82 @@ func (x *ListWorkflowsResponse) GetError() *Error {
 	return nil
 }
 
+// List workflows stream request. Every matching workflow is streamed unless
+// limit is set; no total is computed.
+type ListWorkflowsStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatusFilter  WorkflowStatus         `protobuf:"varint,1,opt,name=status_filter,json=statusFilter,proto3,enum=goclaw.v1.WorkflowStatus" json:"status_filter,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkflowsStreamRequest) Reset() {
+	*x = ListWorkflowsStreamRequest{}
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkflowsStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkflowsStreamRequest) ProtoMessage() {}
+
+func (x *ListWorkflowsStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkflowsStreamRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkflowsStreamRequest) Descriptor() ([]byte, []int) {
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListWorkflowsStreamRequest) GetStatusFilter() WorkflowStatus {
+	if x != nil {
+		return x.StatusFilter
+	}
+	return WorkflowStatus_WORKFLOW_STATUS_UNSPECIFIED
+}
+
+func (x *ListWorkflowsStreamRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListWorkflowsStreamRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
 // Get workflow status request
 type GetWorkflowStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	// Fields of GetWorkflowStatusResponse to return, e.g. "status" or
+	// "tasks". All fields are returned when empty.
+	FieldMask     *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetWorkflowStatusRequest) Reset() {
 	*x = GetWorkflowStatusRequest{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[6]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -536,7 +602,7 @@ func (x *GetWorkflowStatusRequest) String() string {
 func (*GetWorkflowStatusRequest) ProtoMessage() {}
 
 func (x *GetWorkflowStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[6]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -549,7 +615,7 @@ func (x *GetWorkflowStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkflowStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetWorkflowStatusRequest) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{6}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetWorkflowStatusRequest) GetWorkflowId() string {
@@ -559,6 +625,13 @@ func (x *GetWorkflowStatusRequest) GetWorkflowId() string {
 	return ""
 }
 
+func (x *GetWorkflowStatusRequest) GetFieldMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
 // Task status detail
 type TaskStatusDetail struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -574,7 +647,7 @@ type TaskStatusDetail struct {
 
 func (x *TaskStatusDetail) Reset() {
 	*x = TaskStatusDetail{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[7]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -586,7 +659,7 @@ func (x *TaskStatusDetail) String() string {
 func (*TaskStatusDetail) ProtoMessage() {}
 
 func (x *TaskStatusDetail) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[7]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +672,7 @@ func (x *TaskStatusDetail) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TaskStatusDetail.ProtoReflect.Descriptor instead.
 func (*TaskStatusDetail) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{7}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *TaskStatusDetail) GetTaskId() string {
@@ -660,7 +733,7 @@ type GetWorkflowStatusResponse struct {
 
 func (x *GetWorkflowStatusResponse) Reset() {
 	*x = GetWorkflowStatusResponse{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[8]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -672,7 +745,7 @@ func (x *GetWorkflowStatusResponse) String() string {
 func (*GetWorkflowStatusResponse) ProtoMessage() {}
 
 func (x *GetWorkflowStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[8]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,7 +758,7 @@ func (x *GetWorkflowStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkflowStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetWorkflowStatusResponse) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{8}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetWorkflowStatusResponse) GetWorkflowId() string {
@@ -748,7 +821,7 @@ type CancelWorkflowRequest struct {
 
 func (x *CancelWorkflowRequest) Reset() {
 	*x = CancelWorkflowRequest{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[9]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -760,7 +833,7 @@ func (x *CancelWorkflowRequest) String() string {
 func (*CancelWorkflowRequest) ProtoMessage() {}
 
 func (x *CancelWorkflowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[9]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -773,7 +846,7 @@ func (x *CancelWorkflowRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelWorkflowRequest.ProtoReflect.Descriptor instead.
 func (*CancelWorkflowRequest) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{9}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CancelWorkflowRequest) GetWorkflowId() string {
@@ -801,7 +874,7 @@ type CancelWorkflowResponse struct {
 
 func (x *CancelWorkflowResponse) Reset() {
 	*x = CancelWorkflowResponse{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[10]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -813,7 +886,7 @@ func (x *CancelWorkflowResponse) String() string {
 func (*CancelWorkflowResponse) ProtoMessage() {}
 
 func (x *CancelWorkflowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[10]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -826,7 +899,7 @@ func (x *CancelWorkflowResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelWorkflowResponse.ProtoReflect.Descriptor instead.
 func (*CancelWorkflowResponse) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{10}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CancelWorkflowResponse) GetSuccess() bool {
@@ -854,7 +927,7 @@ type GetTaskResultRequest struct {
 
 func (x *GetTaskResultRequest) Reset() {
 	*x = GetTaskResultRequest{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[11]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -866,7 +939,7 @@ func (x *GetTaskResultRequest) String() string {
 func (*GetTaskResultRequest) ProtoMessage() {}
 
 func (x *GetTaskResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[11]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -879,7 +952,7 @@ func (x *GetTaskResultRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskResultRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskResultRequest) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{11}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetTaskResultRequest) GetWorkflowId() string {
@@ -910,7 +983,7 @@ type GetTaskResultResponse struct {
 
 func (x *GetTaskResultResponse) Reset() {
 	*x = GetTaskResultResponse{}
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[12]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -922,7 +995,7 @@ func (x *GetTaskResultResponse) String() string {
 func (*GetTaskResultResponse) ProtoMessage() {}
 
 func (x *GetTaskResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_goclaw_v1_workflow_proto_msgTypes[12]
+	mi := &file_goclaw_v1_workflow_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -935,7 +1008,7 @@ func (x *GetTaskResultResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskResultResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskResultResponse) Descriptor() ([]byte, []int) {
-	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{12}
+	return file_goclaw_v1_workflow_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetTaskResultResponse) GetTaskId() string {
@@ -977,7 +1050,7 @@ var File_goclaw_v1_workflow_proto protoreflect.FileDescriptor
 
 const file_goclaw_v1_workflow_proto_rawDesc = "" +
 	"\n" +
-	"\x18goclaw/v1/workflow.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xda\x01\n" +
+	"\x18goclaw/v1/workflow.proto\x12\tgoclaw.v1\x1a\x16goclaw/v1/common.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xda\x01\n" +
 	"\x0eTaskDefinition\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\"\n" +
@@ -1016,10 +1089,16 @@ const file_goclaw_v1_workflow_proto_rawDesc = "" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1d.goclaw.v1.PaginationResponseR\n" +
 	"pagination\x12&\n" +
-	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\";\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.goclaw.v1.ErrorR\x05error\"\x8a\x01\n" +
+	"\x1aListWorkflowsStreamRequest\x12>\n" +
+	"\rstatus_filter\x18\x01 \x01(\x0e2\x19.goclaw.v1.WorkflowStatusR\fstatusFilter\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"v\n" +
 	"\x18GetWorkflowStatusRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
-	"workflowId\"\x8d\x02\n" +
+	"workflowId\x129\n" +
+	"\n" +
+	"field_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\tfieldMask\"\x8d\x02\n" +
 	"\x10TaskStatusDetail\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
@@ -1071,10 +1150,11 @@ const file_goclaw_v1_workflow_proto_rawDesc = "" +
 	"\x13TASK_STATUS_RUNNING\x10\x02\x12\x19\n" +
 	"\x15TASK_STATUS_COMPLETED\x10\x03\x12\x16\n" +
 	"\x12TASK_STATUS_FAILED\x10\x04\x12\x19\n" +
-	"\x15TASK_STATUS_CANCELLED\x10\x052\xc7\x03\n" +
+	"\x15TASK_STATUS_CANCELLED\x10\x052\xa3\x04\n" +
 	"\x0fWorkflowService\x12U\n" +
 	"\x0eSubmitWorkflow\x12 .goclaw.v1.SubmitWorkflowRequest\x1a!.goclaw.v1.SubmitWorkflowResponse\x12R\n" +
-	"\rListWorkflows\x12\x1f.goclaw.v1.ListWorkflowsRequest\x1a .goclaw.v1.ListWorkflowsResponse\x12^\n" +
+	"\rListWorkflows\x12\x1f.goclaw.v1.ListWorkflowsRequest\x1a .goclaw.v1.ListWorkflowsResponse\x12Z\n" +
+	"\x13ListWorkflowsStream\x12%.goclaw.v1.ListWorkflowsStreamRequest\x1a\x1a.goclaw.v1.WorkflowSummary0\x01\x12^\n" +
 	"\x11GetWorkflowStatus\x12#.goclaw.v1.GetWorkflowStatusRequest\x1a$.goclaw.v1.GetWorkflowStatusResponse\x12U\n" +
 	"\x0eCancelWorkflow\x12 .goclaw.v1.CancelWorkflowRequest\x1a!.goclaw.v1.CancelWorkflowResponse\x12R\n" +
 	"\rGetTaskResult\x12\x1f.goclaw.v1.GetTaskResultRequest\x1a .goclaw.v1.GetTaskResultResponseB.Z,github.com/goclaw/goclaw/pkg/grpc/pb/v1;pbv1b\x06proto3"
@@ -1092,69 +1172,75 @@ func file_goclaw_v1_workflow_proto_rawDescGZIP() []byte {
 }
 
 var file_goclaw_v1_workflow_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_goclaw_v1_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_goclaw_v1_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_goclaw_v1_workflow_proto_goTypes = []any{
-	(WorkflowStatus)(0),               // 0: goclaw.v1.WorkflowStatus
-	(TaskStatus)(0),                   // 1: goclaw.v1.TaskStatus
-	(*TaskDefinition)(nil),            // 2: goclaw.v1.TaskDefinition
-	(*SubmitWorkflowRequest)(nil),     // 3: goclaw.v1.SubmitWorkflowRequest
-	(*SubmitWorkflowResponse)(nil),    // 4: goclaw.v1.SubmitWorkflowResponse
-	(*ListWorkflowsRequest)(nil),      // 5: goclaw.v1.ListWorkflowsRequest
-	(*WorkflowSummary)(nil),           // 6: goclaw.v1.WorkflowSummary
-	(*ListWorkflowsResponse)(nil),     // 7: goclaw.v1.ListWorkflowsResponse
-	(*GetWorkflowStatusRequest)(nil),  // 8: goclaw.v1.GetWorkflowStatusRequest
-	(*TaskStatusDetail)(nil),          // 9: goclaw.v1.TaskStatusDetail
-	(*GetWorkflowStatusResponse)(nil), // 10: goclaw.v1.GetWorkflowStatusResponse
-	(*CancelWorkflowRequest)(nil),     // 11: goclaw.v1.CancelWorkflowRequest
-	(*CancelWorkflowResponse)(nil),    // 12: goclaw.v1.CancelWorkflowResponse
-	(*GetTaskResultRequest)(nil),      // 13: goclaw.v1.GetTaskResultRequest
-	(*GetTaskResultResponse)(nil),     // 14: goclaw.v1.GetTaskResultResponse
-	nil,                               // 15: goclaw.v1.TaskDefinition.MetadataEntry
-	nil,                               // 16: goclaw.v1.SubmitWorkflowRequest.MetadataEntry
-	(*Error)(nil),                     // 17: goclaw.v1.Error
-	(*PaginationRequest)(nil),         // 18: goclaw.v1.PaginationRequest
-	(*timestamppb.Timestamp)(nil),     // 19: google.protobuf.Timestamp
-	(*PaginationResponse)(nil),        // 20: goclaw.v1.PaginationResponse
+	(WorkflowStatus)(0),                // 0: goclaw.v1.WorkflowStatus
+	(TaskStatus)(0),                    // 1: goclaw.v1.TaskStatus
+	(*TaskDefinition)(nil),             // 2: goclaw.v1.TaskDefinition
+	(*SubmitWorkflowRequest)(nil),      // 3: goclaw.v1.SubmitWorkflowRequest
+	(*SubmitWorkflowResponse)(nil),     // 4: goclaw.v1.SubmitWorkflowResponse
+	(*ListWorkflowsRequest)(nil),       // 5: goclaw.v1.ListWorkflowsRequest
+	(*WorkflowSummary)(nil),            // 6: goclaw.v1.WorkflowSummary
+	(*ListWorkflowsResponse)(nil),      // 7: goclaw.v1.ListWorkflowsResponse
+	(*ListWorkflowsStreamRequest)(nil), // 8: goclaw.v1.ListWorkflowsStreamRequest
+	(*GetWorkflowStatusRequest)(nil),   // 9: goclaw.v1.GetWorkflowStatusRequest
+	(*TaskStatusDetail)(nil),           // 10: goclaw.v1.TaskStatusDetail
+	(*GetWorkflowStatusResponse)(nil),  // 11: goclaw.v1.GetWorkflowStatusResponse
+	(*CancelWorkflowRequest)(nil),      // 12: goclaw.v1.CancelWorkflowRequest
+	(*CancelWorkflowResponse)(nil),     // 13: goclaw.v1.CancelWorkflowResponse
+	(*GetTaskResultRequest)(nil),       // 14: goclaw.v1.GetTaskResultRequest
+	(*GetTaskResultResponse)(nil),      // 15: goclaw.v1.GetTaskResultResponse
+	nil,                                // 16: goclaw.v1.TaskDefinition.MetadataEntry
+	nil,                                // 17: goclaw.v1.SubmitWorkflowRequest.MetadataEntry
+	(*Error)(nil),                      // 18: goclaw.v1.Error
+	(*PaginationRequest)(nil),          // 19: goclaw.v1.PaginationRequest
+	(*timestamppb.Timestamp)(nil),      // 20: google.protobuf.Timestamp
+	(*PaginationResponse)(nil),         // 21: goclaw.v1.PaginationResponse
+	(*fieldmaskpb.FieldMask)(nil),      // 22: google.protobuf.FieldMask
 }
 var file_goclaw_v1_workflow_proto_depIdxs = []int32{
-	15, // 0: goclaw.v1.TaskDefinition.metadata:type_name -> goclaw.v1.TaskDefinition.MetadataEntry
+	16, // 0: goclaw.v1.TaskDefinition.metadata:type_name -> goclaw.v1.TaskDefinition.MetadataEntry
 	2,  // 1: goclaw.v1.SubmitWorkflowRequest.tasks:type_name -> goclaw.v1.TaskDefinition
-	16, // 2: goclaw.v1.SubmitWorkflowRequest.metadata:type_name -> goclaw.v1.SubmitWorkflowRequest.MetadataEntry
-	17, // 3: goclaw.v1.SubmitWorkflowResponse.error:type_name -> goclaw.v1.Error
-	18, // 4: goclaw.v1.ListWorkflowsRequest.pagination:type_name -> goclaw.v1.PaginationRequest
+	17, // 2: goclaw.v1.SubmitWorkflowRequest.metadata:type_name -> goclaw.v1.SubmitWorkflowRequest.MetadataEntry
+	18, // 3: goclaw.v1.SubmitWorkflowResponse.error:type_name -> goclaw.v1.Error
+	19, // 4: goclaw.v1.ListWorkflowsRequest.pagination:type_name -> goclaw.v1.PaginationRequest
 	0,  // 5: goclaw.v1.ListWorkflowsRequest.status_filter:type_name -> goclaw.v1.WorkflowStatus
 	0,  // 6: goclaw.v1.WorkflowSummary.status:type_name -> goclaw.v1.WorkflowStatus
-	19, // 7: goclaw.v1.WorkflowSummary.created_at:type_name -> google.protobuf.Timestamp
-	19, // 8: goclaw.v1.WorkflowSummary.updated_at:type_name -> google.protobuf.Timestamp
+	20, // 7: goclaw.v1.WorkflowSummary.created_at:type_name -> google.protobuf.Timestamp
+	20, // 8: goclaw.v1.WorkflowSummary.updated_at:type_name -> google.protobuf.Timestamp
 	6,  // 9: goclaw.v1.ListWorkflowsResponse.workflows:type_name -> goclaw.v1.WorkflowSummary
-	20, // 10: goclaw.v1.ListWorkflowsResponse.pagination:type_name -> goclaw.v1.PaginationResponse
-	17, // 11: goclaw.v1.ListWorkflowsResponse.error:type_name -> goclaw.v1.Error
-	1,  // 12: goclaw.v1.TaskStatusDetail.status:type_name -> goclaw.v1.TaskStatus
-	19, // 13: goclaw.v1.TaskStatusDetail.started_at:type_name -> google.protobuf.Timestamp
-	19, // 14: goclaw.v1.TaskStatusDetail.completed_at:type_name -> google.protobuf.Timestamp
-	0,  // 15: goclaw.v1.GetWorkflowStatusResponse.status:type_name -> goclaw.v1.WorkflowStatus
-	9,  // 16: goclaw.v1.GetWorkflowStatusResponse.tasks:type_name -> goclaw.v1.TaskStatusDetail
-	19, // 17: goclaw.v1.GetWorkflowStatusResponse.created_at:type_name -> google.protobuf.Timestamp
-	19, // 18: goclaw.v1.GetWorkflowStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
-	17, // 19: goclaw.v1.GetWorkflowStatusResponse.error:type_name -> goclaw.v1.Error
-	17, // 20: goclaw.v1.CancelWorkflowResponse.error:type_name -> goclaw.v1.Error
-	1,  // 21: goclaw.v1.GetTaskResultResponse.status:type_name -> goclaw.v1.TaskStatus
-	17, // 22: goclaw.v1.GetTaskResultResponse.error:type_name -> goclaw.v1.Error
-	3,  // 23: goclaw.v1.WorkflowService.SubmitWorkflow:input_type -> goclaw.v1.SubmitWorkflowRequest
-	5,  // 24: goclaw.v1.WorkflowService.ListWorkflows:input_type -> goclaw.v1.ListWorkflowsRequest
-	8,  // 25: goclaw.v1.WorkflowService.GetWorkflowStatus:input_type -> goclaw.v1.GetWorkflowStatusRequest
-	11, // 26: goclaw.v1.WorkflowService.CancelWorkflow:input_type -> goclaw.v1.CancelWorkflowRequest
-	13, // 27: goclaw.v1.WorkflowService.GetTaskResult:input_type -> goclaw.v1.GetTaskResultRequest
-	4,  // 28: goclaw.v1.WorkflowService.SubmitWorkflow:output_type -> goclaw.v1.SubmitWorkflowResponse
-	7,  // 29: goclaw.v1.WorkflowService.ListWorkflows:output_type -> goclaw.v1.ListWorkflowsResponse
-	10, // 30: goclaw.v1.WorkflowService.GetWorkflowStatus:output_type -> goclaw.v1.GetWorkflowStatusResponse
-	12, // 31: goclaw.v1.WorkflowService.CancelWorkflow:output_type -> goclaw.v1.CancelWorkflowResponse
-	14, // 32: goclaw.v1.WorkflowService.GetTaskResult:output_type -> goclaw.v1.GetTaskResultResponse
-	28, // [28:33] is the sub-list for method output_type
-	23, // [23:28] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	21, // 10: goclaw.v1.ListWorkflowsResponse.pagination:type_name -> goclaw.v1.PaginationResponse
+	18, // 11: goclaw.v1.ListWorkflowsResponse.error:type_name -> goclaw.v1.Error
+	0,  // 12: goclaw.v1.ListWorkflowsStreamRequest.status_filter:type_name -> goclaw.v1.WorkflowStatus
+	22, // 13: goclaw.v1.GetWorkflowStatusRequest.field_mask:type_name -> google.protobuf.FieldMask
+	1,  // 14: goclaw.v1.TaskStatusDetail.status:type_name -> goclaw.v1.TaskStatus
+	20, // 15: goclaw.v1.TaskStatusDetail.started_at:type_name -> google.protobuf.Timestamp
+	20, // 16: goclaw.v1.TaskStatusDetail.completed_at:type_name -> google.protobuf.Timestamp
+	0,  // 17: goclaw.v1.GetWorkflowStatusResponse.status:type_name -> goclaw.v1.WorkflowStatus
+	10, // 18: goclaw.v1.GetWorkflowStatusResponse.tasks:type_name -> goclaw.v1.TaskStatusDetail
+	20, // 19: goclaw.v1.GetWorkflowStatusResponse.created_at:type_name -> google.protobuf.Timestamp
+	20, // 20: goclaw.v1.GetWorkflowStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 21: goclaw.v1.GetWorkflowStatusResponse.error:type_name -> goclaw.v1.Error
+	18, // 22: goclaw.v1.CancelWorkflowResponse.error:type_name -> goclaw.v1.Error
+	1,  // 23: goclaw.v1.GetTaskResultResponse.status:type_name -> goclaw.v1.TaskStatus
+	18, // 24: goclaw.v1.GetTaskResultResponse.error:type_name -> goclaw.v1.Error
+	3,  // 25: goclaw.v1.WorkflowService.SubmitWorkflow:input_type -> goclaw.v1.SubmitWorkflowRequest
+	5,  // 26: goclaw.v1.WorkflowService.ListWorkflows:input_type -> goclaw.v1.ListWorkflowsRequest
+	8,  // 27: goclaw.v1.WorkflowService.ListWorkflowsStream:input_type -> goclaw.v1.ListWorkflowsStreamRequest
+	9,  // 28: goclaw.v1.WorkflowService.GetWorkflowStatus:input_type -> goclaw.v1.GetWorkflowStatusRequest
+	12, // 29: goclaw.v1.WorkflowService.CancelWorkflow:input_type -> goclaw.v1.CancelWorkflowRequest
+	14, // 30: goclaw.v1.WorkflowService.GetTaskResult:input_type -> goclaw.v1.GetTaskResultRequest
+	4,  // 31: goclaw.v1.WorkflowService.SubmitWorkflow:output_type -> goclaw.v1.SubmitWorkflowResponse
+	7,  // 32: goclaw.v1.WorkflowService.ListWorkflows:output_type -> goclaw.v1.ListWorkflowsResponse
+	6,  // 33: goclaw.v1.WorkflowService.ListWorkflowsStream:output_type -> goclaw.v1.WorkflowSummary
+	11, // 34: goclaw.v1.WorkflowService.GetWorkflowStatus:output_type -> goclaw.v1.GetWorkflowStatusResponse
+	13, // 35: goclaw.v1.WorkflowService.CancelWorkflow:output_type -> goclaw.v1.CancelWorkflowResponse
+	15, // 36: goclaw.v1.WorkflowService.GetTaskResult:output_type -> goclaw.v1.GetTaskResultResponse
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_goclaw_v1_workflow_proto_init() }
@@ -1169,7 +1255,7 @@ func file_goclaw_v1_workflow_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goclaw_v1_workflow_proto_rawDesc), len(file_goclaw_v1_workflow_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   15,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
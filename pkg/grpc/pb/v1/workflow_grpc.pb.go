@@ -19,11 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	WorkflowService_SubmitWorkflow_FullMethodName    = "/goclaw.v1.WorkflowService/SubmitWorkflow"
-	WorkflowService_ListWorkflows_FullMethodName     = "/goclaw.v1.WorkflowService/ListWorkflows"
-	WorkflowService_GetWorkflowStatus_FullMethodName = "/goclaw.v1.WorkflowService/GetWorkflowStatus"
-	WorkflowService_CancelWorkflow_FullMethodName    = "/goclaw.v1.WorkflowService/CancelWorkflow"
-	WorkflowService_GetTaskResult_FullMethodName     = "/goclaw.v1.WorkflowService/GetTaskResult"
+	WorkflowService_SubmitWorkflow_FullMethodName      = "/goclaw.v1.WorkflowService/SubmitWorkflow"
+	WorkflowService_ListWorkflows_FullMethodName       = "/goclaw.v1.WorkflowService/ListWorkflows"
+	WorkflowService_ListWorkflowsStream_FullMethodName = "/goclaw.v1.WorkflowService/ListWorkflowsStream"
+	WorkflowService_GetWorkflowStatus_FullMethodName   = "/goclaw.v1.WorkflowService/GetWorkflowStatus"
+	WorkflowService_CancelWorkflow_FullMethodName      = "/goclaw.v1.WorkflowService/CancelWorkflow"
+	WorkflowService_GetTaskResult_FullMethodName       = "/goclaw.v1.WorkflowService/GetTaskResult"
 )
 
 // WorkflowServiceClient is the client API for WorkflowService service.
@@ -34,6 +35,7 @@ const (
 type WorkflowServiceClient interface {
 	SubmitWorkflow(ctx context.Context, in *SubmitWorkflowRequest, opts ...grpc.CallOption) (*SubmitWorkflowResponse, error)
 	ListWorkflows(ctx context.Context, in *ListWorkflowsRequest, opts ...grpc.CallOption) (*ListWorkflowsResponse, error)
+	ListWorkflowsStream(ctx context.Context, in *ListWorkflowsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowSummary], error)
 	GetWorkflowStatus(ctx context.Context, in *GetWorkflowStatusRequest, opts ...grpc.CallOption) (*GetWorkflowStatusResponse, error)
 	CancelWorkflow(ctx context.Context, in *CancelWorkflowRequest, opts ...grpc.CallOption) (*CancelWorkflowResponse, error)
 	GetTaskResult(ctx context.Context, in *GetTaskResultRequest, opts ...grpc.CallOption) (*GetTaskResultResponse, error)
@@ -67,6 +69,25 @@ func (c *workflowServiceClient) ListWorkflows(ctx context.Context, in *ListWorkf
 	return out, nil
 }
 
+func (c *workflowServiceClient) ListWorkflowsStream(ctx context.Context, in *ListWorkflowsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowSummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[0], WorkflowService_ListWorkflowsStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListWorkflowsStreamRequest, WorkflowSummary]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_ListWorkflowsStreamClient = grpc.ServerStreamingClient[WorkflowSummary]
+
 func (c *workflowServiceClient) GetWorkflowStatus(ctx context.Context, in *GetWorkflowStatusRequest, opts ...grpc.CallOption) (*GetWorkflowStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetWorkflowStatusResponse)
@@ -105,6 +126,7 @@ func (c *workflowServiceClient) GetTaskResult(ctx context.Context, in *GetTaskRe
 type WorkflowServiceServer interface {
 	SubmitWorkflow(context.Context, *SubmitWorkflowRequest) (*SubmitWorkflowResponse, error)
 	ListWorkflows(context.Context, *ListWorkflowsRequest) (*ListWorkflowsResponse, error)
+	ListWorkflowsStream(*ListWorkflowsStreamRequest, grpc.ServerStreamingServer[WorkflowSummary]) error
 	GetWorkflowStatus(context.Context, *GetWorkflowStatusRequest) (*GetWorkflowStatusResponse, error)
 	CancelWorkflow(context.Context, *CancelWorkflowRequest) (*CancelWorkflowResponse, error)
 	GetTaskResult(context.Context, *GetTaskResultRequest) (*GetTaskResultResponse, error)
@@ -124,6 +146,9 @@ func (UnimplementedWorkflowServiceServer) SubmitWorkflow(context.Context, *Submi
 func (UnimplementedWorkflowServiceServer) ListWorkflows(context.Context, *ListWorkflowsRequest) (*ListWorkflowsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListWorkflows not implemented")
 }
+func (UnimplementedWorkflowServiceServer) ListWorkflowsStream(*ListWorkflowsStreamRequest, grpc.ServerStreamingServer[WorkflowSummary]) error {
+	return status.Error(codes.Unimplemented, "method ListWorkflowsStream not implemented")
+}
 func (UnimplementedWorkflowServiceServer) GetWorkflowStatus(context.Context, *GetWorkflowStatusRequest) (*GetWorkflowStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetWorkflowStatus not implemented")
 }
@@ -190,6 +215,17 @@ func _WorkflowService_ListWorkflows_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_ListWorkflowsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListWorkflowsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServiceServer).ListWorkflowsStream(m, &grpc.GenericServerStream[ListWorkflowsStreamRequest, WorkflowSummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_ListWorkflowsStreamServer = grpc.ServerStreamingServer[WorkflowSummary]
+
 func _WorkflowService_GetWorkflowStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetWorkflowStatusRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +308,12 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _WorkflowService_GetTaskResult_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListWorkflowsStream",
+			Handler:       _WorkflowService_ListWorkflowsStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "goclaw/v1/workflow.proto",
 }
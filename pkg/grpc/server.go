@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/goclaw/goclaw/pkg/grpc/interceptors"
+	"github.com/goclaw/goclaw/pkg/identity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -25,10 +26,19 @@ type Server struct {
 	listener     net.Listener
 	healthServer *HealthServer
 	pending      []serviceRegistration
+	drainers     []Drainer
 	mu           sync.RWMutex
 	running      bool
 }
 
+// Drainer is implemented by services with long-lived streams. Stop calls Drain
+// on every registered service that implements it before waiting for in-flight
+// calls, so that streams end with a final update instead of being cut off
+// when the shutdown deadline expires.
+type Drainer interface {
+	Drain()
+}
+
 type serviceRegistration struct {
 	desc *grpc.ServiceDesc
 	impl interface{}
@@ -114,6 +124,15 @@ func (s *Server) Stop(ctx context.Context) error {
 		return nil
 	}
 
+	// Report NOT_SERVING so that load balancers stop routing new calls here,
+	// then let open streams wind down
+	if s.healthServer != nil {
+		s.healthServer.Shutdown()
+	}
+	for _, d := range s.drainers {
+		d.Drain()
+	}
+
 	// Create a channel to signal when graceful stop completes
 	stopped := make(chan struct{})
 
@@ -141,6 +160,9 @@ func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if d, ok := impl.(Drainer); ok {
+		s.drainers = append(s.drainers, d)
+	}
 	if s.grpcSrv != nil {
 		s.grpcSrv.RegisterService(desc, impl)
 		return
@@ -216,11 +238,51 @@ func (s *Server) buildServerOptions() ([]grpc.ServerOption, error) {
 	if s.config.MaxSendMsgSize > 0 {
 		opts = append(opts, grpc.MaxSendMsgSize(s.config.MaxSendMsgSize))
 	}
+	opts = append(opts, s.buildInterceptors().Build()...)
+
+	return opts, nil
+}
+
+// buildInterceptors assembles the interceptor chain in the same order as the
+// HTTP middleware stack: error_mapping -> request_id -> identity -> tracing ->
+// logging -> recovery -> auth -> authorization -> rate_limit. Logging sits
+// outside recovery so that calls which panicked are logged with their
+// Internal status.
+func (s *Server) buildInterceptors() *interceptors.ChainBuilder {
+	mtls := s.config.TLS != nil && s.config.TLS.Enabled && s.config.TLS.ClientAuth
+
+	chain := interceptors.NewChainBuilder().
+		WithErrorMapping().
+		WithRequestID()
+	if mtls {
+		// Validate has checked the mappings
+		mapper, _ := identity.NewMapper(s.config.RoleMappings)
+		chain.WithIdentity(mapper)
+	}
 	if s.config.EnableTracing {
-		opts = append(opts, interceptors.NewChainBuilder().WithTracing().Build()...)
+		chain.WithTracing()
+	}
+	if s.config.EnableLogging {
+		chain.WithLogging(s.config.Logger)
 	}
+	chain.WithRecovery(s.config.Logger)
 
-	return opts, nil
+	if auth := s.config.Auth; auth != nil && auth.Enabled {
+		chain.WithAuthentication(interceptors.StaticTokenValidator(auth.Tokens))
+	}
+	if mtls && len(s.config.RoleMappings) > 0 {
+		chain.WithAuthorization()
+	}
+
+	if rl := s.config.RateLimit; rl != nil && rl.Enabled {
+		limiter := interceptors.NewRateLimiter(rl.RequestsPerSecond, rl.Burst)
+		for _, m := range rl.Methods {
+			limiter.SetMethodLimit(m.Method, m.RequestsPerSecond, m.Burst)
+		}
+		chain.WithRateLimiter(limiter)
+	}
+
+	return chain
 }
 
 // buildTLSCredentials creates TLS credentials from config
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/grpc/interceptors"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestServerStart_InterceptorChainEchoesRequestID(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Address = "127.0.0.1:0"
+	cfg.EnableTracing = false
+	cfg.Auth = &AuthConfig{Enabled: true, Tokens: []string{"secret"}}
+	cfg.RateLimit = &RateLimitConfig{Enabled: true, RequestsPerSecond: 10, Burst: 10}
+
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Stop(stopCtx)
+	}()
+
+	conn, err := ggrpc.NewClient(srv.Address(), ggrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, interceptors.RequestIDKey, "req-1")
+
+	// Health checks bypass authentication.
+	var header metadata.MD
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+	if _, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, ggrpc.Header(&header)); err != nil {
+		t.Fatalf("health check error = %v", err)
+	}
+	if got := header.Get(interceptors.RequestIDKey); len(got) != 1 || got[0] != "req-1" {
+		t.Fatalf("expected request id echoed in header, got %v", got)
+	}
+}
+
+type drainingService struct {
+	drained chan struct{}
+}
+
+func (d *drainingService) Drain() { close(d.drained) }
+
+func TestServerStop_DrainsServices(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Address = "127.0.0.1:0"
+	cfg.EnableTracing = false
+
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	svc := &drainingService{drained: make(chan struct{})}
+	srv.RegisterService(&ggrpc.ServiceDesc{
+		ServiceName: "test.Draining",
+		HandlerType: (*interface{})(nil),
+	}, svc)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := srv.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	select {
+	case <-svc.drained:
+	default:
+		t.Fatal("expected Stop to drain the service")
+	}
+}
+
+func TestConfigValidate_Interceptors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Auth = &AuthConfig{Enabled: true}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for auth without tokens")
+	}
+
+	cfg = DefaultConfig()
+	cfg.RateLimit = &RateLimitConfig{
+		Enabled:           true,
+		RequestsPerSecond: 10,
+		Burst:             10,
+		Methods:           []MethodRateLimit{{Method: "SubmitWorkflow", RequestsPerSecond: 1, Burst: 1}},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for method without full name")
+	}
+}
@@ -22,10 +22,12 @@ type Subscriber struct {
 
 // SubscriberRegistry manages streaming subscribers
 type SubscriberRegistry struct {
-	mu          sync.RWMutex
-	subscribers map[string]*Subscriber // subscriberID -> Subscriber
-	byWorkflow  map[string][]string    // workflowID -> []subscriberID
-	sequence    int64
+	mu           sync.RWMutex
+	subscribers  map[string]*Subscriber // subscriberID -> Subscriber
+	byWorkflow   map[string][]string    // workflowID -> []subscriberID
+	sequence     int64
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
 // NewSubscriberRegistry creates a new subscriber registry
@@ -34,9 +36,24 @@ func NewSubscriberRegistry() *SubscriberRegistry {
 		subscribers: make(map[string]*Subscriber),
 		byWorkflow:  make(map[string][]string),
 		sequence:    0,
+		shutdown:    make(chan struct{}),
 	}
 }
 
+// Shutdown tells every stream reading from the registry that the server is
+// shutting down. Streams send what they have buffered and a final update,
+// then end. It is safe to call more than once.
+func (r *SubscriberRegistry) Shutdown() {
+	r.shutdownOnce.Do(func() {
+		close(r.shutdown)
+	})
+}
+
+// ShuttingDown returns a channel that is closed by Shutdown
+func (r *SubscriberRegistry) ShuttingDown() <-chan struct{} {
+	return r.shutdown
+}
+
 // Subscribe creates a new subscriber for a workflow
 func (r *SubscriberRegistry) Subscribe(workflowID string, bufferSize int) *Subscriber {
 	r.mu.Lock()
@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// gRPC-Web content types. Both may carry a codec suffix such as "+proto".
+const (
+	webContentType     = "application/grpc-web"
+	webTextContentType = "application/grpc-web-text"
+)
+
+// webTrailerFlag marks the frame carrying the trailers at the end of a
+// gRPC-Web response body.
+const webTrailerFlag byte = 0x80
+
+// IsWebRequest reports whether r is a gRPC-Web call.
+func IsWebRequest(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), webContentType)
+}
+
+// WebHandler returns a handler serving the services of s to gRPC-Web
+// clients, such as browsers, over HTTP/1.1 or HTTP/2 without a proxy. Calls
+// are translated for the gRPC server in process, so they go through the same
+// interceptors as native calls. Both the binary and the base64
+// application/grpc-web-text encodings are supported, including server
+// streams. Calls fail with 503 until the server is started.
+func (s *Server) WebHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv := s.GetServer()
+		if srv == nil {
+			http.Error(w, "gRPC server not started", http.StatusServiceUnavailable)
+			return
+		}
+		serveWeb(srv, w, r)
+	})
+}
+
+// serveWeb serves the gRPC-Web call r with the gRPC handler h.
+func serveWeb(h http.Handler, w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, webTextContentType)
+	base := webContentType
+	if text {
+		base = webTextContentType
+	}
+	subtype := strings.TrimPrefix(contentType, base)
+
+	req := r.WithContext(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", "application/grpc"+subtype)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{&webTextReader{r: r.Body}, r.Body}
+	}
+
+	rc := http.NewResponseController(w)
+	// Server streams run until the call ends, like websocket connections.
+	_ = rc.SetWriteDeadline(time.Time{})
+	ww := &webResponseWriter{
+		w:           w,
+		rc:          rc,
+		header:      make(http.Header),
+		contentType: base + subtype,
+		text:        text,
+	}
+	h.ServeHTTP(ww, req)
+	ww.finish()
+}
+
+// webTextReader decodes an application/grpc-web-text request body. Clients
+// may encode each message on its own, so the body can be several padded
+// base64 strings one after another; each of them is decoded separately.
+type webTextReader struct {
+	r   io.Reader
+	buf [4096]byte
+	in  []byte // undecoded input, less than a quantum
+	out []byte // decoded input not read yet
+	err error
+}
+
+func (t *webTextReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if t.err != nil {
+			if t.err == io.EOF && len(t.in) > 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, t.err
+		}
+		n, err := t.r.Read(t.buf[:])
+		for _, c := range t.buf[:n] {
+			if c != '\r' && c != '\n' {
+				t.in = append(t.in, c)
+			}
+		}
+		t.err = err
+		whole := len(t.in) - len(t.in)%4
+		out, decodeErr := decodeWebText(t.in[:whole])
+		if decodeErr != nil {
+			t.err = decodeErr
+			return 0, decodeErr
+		}
+		t.out = out
+		t.in = append(t.in[:0], t.in[whole:]...)
+	}
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
+
+// decodeWebText decodes src, a whole number of base64 quanta, ending a
+// string after each padded quantum.
+func decodeWebText(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, base64.StdEncoding.DecodedLen(len(src)))
+	for len(src) > 0 {
+		end := len(src)
+		if i := bytes.IndexByte(src, '='); i >= 0 {
+			end = i - i%4 + 4
+		}
+		n, err := base64.StdEncoding.Decode(dst[len(dst):cap(dst)], src[:end])
+		if err != nil {
+			return nil, err
+		}
+		dst = dst[:len(dst)+n]
+		src = src[end:]
+	}
+	return dst, nil
+}
+
+// webResponseWriter turns the response of a gRPC handler into a gRPC-Web
+// response: the trailers the handler sets after the body are sent as a
+// final frame of the body, and text responses are base64 encoded.
+type webResponseWriter struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	header      http.Header
+	contentType string
+	text        bool
+
+	status      int
+	wroteHeader bool
+	// pending holds the bytes of a text response written since the last
+	// flush, which are encoded together.
+	pending []byte
+}
+
+func (ww *webResponseWriter) Header() http.Header {
+	return ww.header
+}
+
+func (ww *webResponseWriter) WriteHeader(status int) {
+	if ww.wroteHeader {
+		return
+	}
+	ww.wroteHeader = true
+	ww.status = status
+	h := ww.w.Header()
+	for key, values := range ww.header {
+		if key == "Trailer" || strings.HasPrefix(key, http.TrailerPrefix) {
+			continue
+		}
+		h[key] = append([]string(nil), values...)
+	}
+	if status == http.StatusOK {
+		h.Set("Content-Type", ww.contentType)
+	}
+	ww.w.WriteHeader(status)
+}
+
+func (ww *webResponseWriter) Write(p []byte) (int, error) {
+	ww.WriteHeader(http.StatusOK)
+	if ww.text && ww.status == http.StatusOK {
+		ww.pending = append(ww.pending, p...)
+		return len(p), nil
+	}
+	return ww.w.Write(p)
+}
+
+func (ww *webResponseWriter) Flush() {
+	ww.WriteHeader(http.StatusOK)
+	if len(ww.pending) > 0 {
+		_, _ = io.WriteString(ww.w, base64.StdEncoding.EncodeToString(ww.pending))
+		ww.pending = ww.pending[:0]
+	}
+	_ = ww.rc.Flush()
+}
+
+// finish writes the trailers frame once the handler has returned.
+func (ww *webResponseWriter) finish() {
+	ww.WriteHeader(http.StatusOK)
+	if ww.status != http.StatusOK {
+		return
+	}
+
+	var trailers strings.Builder
+	writeTrailer := func(key string, values []string) {
+		for _, value := range values {
+			trailers.WriteString(strings.ToLower(key))
+			trailers.WriteString(": ")
+			trailers.WriteString(value)
+			trailers.WriteString("\r\n")
+		}
+	}
+	for _, key := range ww.header.Values("Trailer") {
+		writeTrailer(key, ww.header.Values(key))
+	}
+	for key, values := range ww.header {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			writeTrailer(name, values)
+		}
+	}
+
+	frame := make([]byte, 5, 5+trailers.Len())
+	frame[0] = webTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailers.Len()))
+	frame = append(frame, trailers.String()...)
+	_, _ = ww.Write(frame)
+	ww.Flush()
+}
@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// webFrames splits a gRPC-Web response body into its messages and trailers.
+func webFrames(t *testing.T, body []byte) (messages [][]byte, trailers string) {
+	t.Helper()
+	for len(body) > 0 {
+		if len(body) < 5 {
+			t.Fatalf("truncated frame header %x", body)
+		}
+		size := binary.BigEndian.Uint32(body[1:5])
+		if uint32(len(body)-5) < size {
+			t.Fatalf("truncated frame of %d bytes", size)
+		}
+		data := body[5 : 5+size]
+		if body[0]&webTrailerFlag != 0 {
+			trailers = string(data)
+		} else {
+			messages = append(messages, data)
+		}
+		body = body[5+size:]
+	}
+	return messages, trailers
+}
+
+// decodeWebTextBody decodes a text response, whose flushes are padded
+// separately, one base64 quantum at a time as browser clients do.
+func decodeWebTextBody(t *testing.T, text []byte) []byte {
+	t.Helper()
+	var out []byte
+	for len(text) >= 4 {
+		data, err := base64.StdEncoding.DecodeString(string(text[:4]))
+		if err != nil {
+			t.Fatalf("decode text body: %v", err)
+		}
+		out = append(out, data...)
+		text = text[4:]
+	}
+	if len(text) > 0 {
+		t.Fatalf("text body has %d trailing bytes", len(text))
+	}
+	return out
+}
+
+func webRequest(t *testing.T, method string, msg proto.Message, text bool) *http.Request {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	body := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(data)))
+	body = append(body, data...)
+	contentType := "application/grpc-web+proto"
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+		contentType = "application/grpc-web-text"
+	}
+	req := httptest.NewRequest(http.MethodPost, method, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func TestWebHandler(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Address = "127.0.0.1:0"
+	cfg.EnableTracing = false
+	cfg.EnableHealthCheck = true
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	handler := srv.WebHandler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, webRequest(t, "/grpc.health.v1.Health/Check", &grpc_health_v1.HealthCheckRequest{}, false))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status before Start = %d, want 503", rec.Code)
+	}
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Stop(stopCtx)
+	}()
+
+	for _, text := range []bool{false, true} {
+		req := webRequest(t, "/grpc.health.v1.Health/Check", &grpc_health_v1.HealthCheckRequest{}, text)
+		if !IsWebRequest(req) {
+			t.Fatalf("IsWebRequest(%s) = false", req.Header.Get("Content-Type"))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+		}
+		body := rec.Body.Bytes()
+		wantType := "application/grpc-web+proto"
+		if text {
+			wantType = "application/grpc-web-text"
+			body = decodeWebTextBody(t, body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != wantType {
+			t.Fatalf("Content-Type = %q, want %q", got, wantType)
+		}
+		messages, trailers := webFrames(t, body)
+		if len(messages) != 1 || !strings.Contains(trailers, "grpc-status: 0\r\n") {
+			t.Fatalf("got %d messages and trailers %q, want one message and status 0", len(messages), trailers)
+		}
+		var resp grpc_health_v1.HealthCheckResponse
+		if err := proto.Unmarshal(messages[0], &resp); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Fatalf("health status = %v, want SERVING", resp.Status)
+		}
+	}
+
+	// Errors are reported in the trailers.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, webRequest(t, "/grpc.health.v1.Health/Missing", &grpc_health_v1.HealthCheckRequest{}, false))
+	if messages, trailers := webFrames(t, rec.Body.Bytes()); len(messages) != 0 || !strings.Contains(trailers, "grpc-status: 12\r\n") {
+		t.Fatalf("got %d messages and trailers %q, want status 12 (Unimplemented)", len(messages), trailers)
+	}
+
+	plain := httptest.NewRequest(http.MethodPost, "/api/v1/workflows", nil)
+	plain.Header.Set("Content-Type", "application/json")
+	if IsWebRequest(plain) {
+		t.Fatal("IsWebRequest() of a JSON request = true")
+	}
+}
+
+func TestWebTextReader(t *testing.T) {
+	// Two frames encoded one at a time, each ending with padding.
+	first := []byte{0, 0, 0, 0, 2, 'h', 'i'}
+	second := []byte{0, 0, 0, 0, 3, 'b', 'y', 'e', '!'}
+	body := base64.StdEncoding.EncodeToString(first) + base64.StdEncoding.EncodeToString(second)
+	if !strings.Contains(body[:len(body)-1], "=") {
+		t.Fatalf("body %q has no padding between the frames", body)
+	}
+
+	got, err := io.ReadAll(&webTextReader{r: iotest.OneByteReader(strings.NewReader(body))})
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if want := append(first, second...); !bytes.Equal(got, want) {
+		t.Fatalf("decoded %x, want %x", got, want)
+	}
+
+	if _, err := io.ReadAll(&webTextReader{r: strings.NewReader(body[:len(body)-2])}); err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadAll() of a truncated body error = %v, want ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package identity derives caller identities from verified mTLS client
+// certificates and maps them to roles, so that the HTTP and gRPC servers
+// attribute requests the same way.
+package identity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"path"
+	"slices"
+)
+
+// Identity is the caller named by a verified client certificate.
+type Identity struct {
+	// Subject is the certificate's common name, or its first SAN when the
+	// common name is empty.
+	Subject string
+
+	// SANs are the certificate's URI, DNS, email and IP subject alternative
+	// names, in that order.
+	SANs []string
+
+	// Roles are the roles granted by the configured SAN mappings.
+	Roles []string
+}
+
+// HasRole reports whether the identity was granted role.
+func (id *Identity) HasRole(role string) bool {
+	return id != nil && slices.Contains(id.Roles, role)
+}
+
+// RoleMapping grants Roles to certificates with a SAN matching the SAN
+// pattern. Patterns use path.Match syntax, so "*" does not cross "/":
+// "spiffe://goclaw/admin/*" matches "spiffe://goclaw/admin/ops" only.
+type RoleMapping struct {
+	SAN   string
+	Roles []string
+}
+
+// Mapper builds identities from certificates.
+type Mapper struct {
+	mappings []RoleMapping
+}
+
+// NewMapper returns a Mapper applying mappings in order. It fails if a
+// pattern is malformed.
+func NewMapper(mappings []RoleMapping) (*Mapper, error) {
+	for _, m := range mappings {
+		if _, err := path.Match(m.SAN, ""); err != nil {
+			return nil, fmt.Errorf("invalid SAN pattern %q: %w", m.SAN, err)
+		}
+	}
+	return &Mapper{mappings: slices.Clone(mappings)}, nil
+}
+
+// FromCertificate returns the identity named by cert. Roles of every mapping
+// that matches one of its SANs are granted, without duplicates.
+func (m *Mapper) FromCertificate(cert *x509.Certificate) *Identity {
+	id := &Identity{SANs: subjectAltNames(cert)}
+
+	id.Subject = cert.Subject.CommonName
+	if id.Subject == "" && len(id.SANs) > 0 {
+		id.Subject = id.SANs[0]
+	}
+
+	if m == nil {
+		return id
+	}
+	for _, mapping := range m.mappings {
+		if !matchesAny(mapping.SAN, id.SANs) {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !slices.Contains(id.Roles, role) {
+				id.Roles = append(id.Roles, role)
+			}
+		}
+	}
+	return id
+}
+
+// FromTLS returns the identity of the verified client certificate of a TLS
+// connection, or nil if the client did not present one that was verified.
+func (m *Mapper) FromTLS(state *tls.ConnectionState) *Identity {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return m.FromCertificate(state.VerifiedChains[0][0])
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity stored in ctx, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(*Identity)
+	return id, ok && id != nil
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses))
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+func matchesAny(pattern string, sans []string) bool {
+	for _, san := range sans {
+		if ok, _ := path.Match(pattern, san); ok {
+			return true
+		}
+	}
+	return false
+}
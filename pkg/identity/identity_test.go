@@ -0,0 +1,86 @@
+package identity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func testCert(cn string, uris ...string) *x509.Certificate {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		if err != nil {
+			panic(err)
+		}
+		cert.URIs = append(cert.URIs, u)
+	}
+	return cert
+}
+
+func TestMapper_FromCertificate(t *testing.T) {
+	mapper, err := NewMapper([]RoleMapping{
+		{SAN: "spiffe://goclaw/admin/*", Roles: []string{"admin", "user"}},
+		{SAN: "spiffe://goclaw/*/*", Roles: []string{"user"}},
+		{SAN: "*.workers.goclaw.internal", Roles: []string{"worker"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMapper() error = %v", err)
+	}
+
+	id := mapper.FromCertificate(testCert("ops", "spiffe://goclaw/admin/ops"))
+	if id.Subject != "ops" {
+		t.Fatalf("expected subject ops, got %q", id.Subject)
+	}
+	if !reflect.DeepEqual(id.Roles, []string{"admin", "user"}) {
+		t.Fatalf("expected admin and user roles once each, got %v", id.Roles)
+	}
+
+	cert := testCert("")
+	cert.DNSNames = []string{"a.workers.goclaw.internal"}
+	id = mapper.FromCertificate(cert)
+	if id.Subject != "a.workers.goclaw.internal" {
+		t.Fatalf("expected subject from first SAN, got %q", id.Subject)
+	}
+	if !id.HasRole("worker") || id.HasRole("admin") {
+		t.Fatalf("expected only worker role, got %v", id.Roles)
+	}
+
+	// "*" does not cross path separators.
+	id = mapper.FromCertificate(testCert("deep", "spiffe://goclaw/admin/team/ops"))
+	if id.HasRole("admin") {
+		t.Fatalf("expected no admin role for nested path, got %v", id.Roles)
+	}
+}
+
+func TestNewMapper_InvalidPattern(t *testing.T) {
+	if _, err := NewMapper([]RoleMapping{{SAN: "spiffe://[", Roles: []string{"admin"}}}); err == nil {
+		t.Fatal("expected error for malformed pattern")
+	}
+}
+
+func TestMapper_FromTLS(t *testing.T) {
+	mapper, _ := NewMapper(nil)
+
+	if id := mapper.FromTLS(&tls.ConnectionState{}); id != nil {
+		t.Fatalf("expected no identity without verified chains, got %+v", id)
+	}
+
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{testCert("client")}}}
+	id := mapper.FromTLS(state)
+	if id == nil || id.Subject != "client" {
+		t.Fatalf("expected client identity, got %+v", id)
+	}
+
+	ctx := NewContext(context.Background(), id)
+	if got, ok := FromContext(ctx); !ok || got != id {
+		t.Fatalf("expected identity from context, got %+v", got)
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("expected no identity in empty context")
+	}
+}
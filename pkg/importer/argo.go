@@ -0,0 +1,440 @@
+package importer
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/dag"
+)
+
+// argoManifest is the part of an Argo Workflow, WorkflowTemplate or
+// CronWorkflow manifest the import reads.
+type argoManifest struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name         string            `yaml:"name"`
+		GenerateName string            `yaml:"generateName"`
+		Labels       map[string]string `yaml:"labels"`
+		Annotations  map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Spec argoSpec `yaml:"spec"`
+}
+
+type argoSpec struct {
+	Entrypoint            string         `yaml:"entrypoint"`
+	Arguments             argoArguments  `yaml:"arguments"`
+	Templates             []argoTemplate `yaml:"templates"`
+	ActiveDeadlineSeconds intOrString    `yaml:"activeDeadlineSeconds"`
+
+	// Schedule and WorkflowSpec are set in CronWorkflows.
+	Schedule     string    `yaml:"schedule"`
+	WorkflowSpec *argoSpec `yaml:"workflowSpec"`
+}
+
+type argoArguments struct {
+	Parameters []argoParameter `yaml:"parameters"`
+}
+
+type argoParameter struct {
+	Name    string  `yaml:"name"`
+	Value   *string `yaml:"value"`
+	Default *string `yaml:"default"`
+}
+
+type argoTemplate struct {
+	Name   string `yaml:"name"`
+	Inputs struct {
+		Parameters []argoParameter `yaml:"parameters"`
+	} `yaml:"inputs"`
+	Container *argoContainer `yaml:"container"`
+	Script    *argoScript    `yaml:"script"`
+	DAG       *struct {
+		Tasks []argoTask `yaml:"tasks"`
+	} `yaml:"dag"`
+	Steps         [][]argoTask `yaml:"steps"`
+	RetryStrategy *struct {
+		Limit intOrString `yaml:"limit"`
+	} `yaml:"retryStrategy"`
+	ActiveDeadlineSeconds intOrString `yaml:"activeDeadlineSeconds"`
+}
+
+type argoContainer struct {
+	Image     string    `yaml:"image"`
+	Command   []string  `yaml:"command"`
+	Args      []string  `yaml:"args"`
+	Env       []argoEnv `yaml:"env"`
+	Resources struct {
+		Limits   map[string]string `yaml:"limits"`
+		Requests map[string]string `yaml:"requests"`
+	} `yaml:"resources"`
+}
+
+type argoScript struct {
+	argoContainer `yaml:",inline"`
+	Source        string `yaml:"source"`
+}
+
+type argoEnv struct {
+	Name      string `yaml:"name"`
+	Value     string `yaml:"value"`
+	ValueFrom *struct {
+		SecretKeyRef *struct {
+			Name string `yaml:"name"`
+			Key  string `yaml:"key"`
+		} `yaml:"secretKeyRef"`
+	} `yaml:"valueFrom"`
+}
+
+type argoTask struct {
+	Name         string        `yaml:"name"`
+	Template     string        `yaml:"template"`
+	TemplateRef  *yaml.Node    `yaml:"templateRef"`
+	Dependencies []string      `yaml:"dependencies"`
+	Depends      string        `yaml:"depends"`
+	When         string        `yaml:"when"`
+	Arguments    argoArguments `yaml:"arguments"`
+	WithItems    *yaml.Node    `yaml:"withItems"`
+	WithParam    string        `yaml:"withParam"`
+}
+
+// intOrString is an integer that may be written as a string, as Kubernetes
+// allows.
+type intOrString int
+
+func (v *intOrString) UnmarshalYAML(n *yaml.Node) error {
+	i, err := strconv.Atoi(n.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %q is not an integer", n.Line, n.Value)
+	}
+	*v = intOrString(i)
+	return nil
+}
+
+// FromArgo converts an Argo Workflow, WorkflowTemplate or CronWorkflow
+// manifest into a workflow template. Its entrypoint must be a DAG or steps
+// template whose tasks run container or script templates; a steps group
+// depends on every step of the group before it. Script sources are passed
+// to the command with -c, which shells and python accept.
+func FromArgo(data []byte) (*Result, error) {
+	var m argoManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse argo manifest: %w", err)
+	}
+	switch m.Kind {
+	case "Workflow", "WorkflowTemplate", "ClusterWorkflowTemplate", "CronWorkflow":
+	default:
+		return nil, fmt.Errorf("argo manifest kind %q is not a workflow", m.Kind)
+	}
+
+	res := &Result{}
+	spec := &m.Spec
+	if m.Kind == "CronWorkflow" {
+		if spec.WorkflowSpec == nil {
+			return nil, fmt.Errorf("cron workflow has no workflowSpec")
+		}
+		res.warnf("cron schedule %q dropped: create a schedule submitting the template", spec.Schedule)
+		spec = spec.WorkflowSpec
+	}
+
+	name := m.Metadata.Name
+	if name == "" {
+		name = strings.TrimSuffix(m.Metadata.GenerateName, "-")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("argo manifest has no name")
+	}
+	res.Workflow = models.WorkflowRequest{
+		Name:        name,
+		Description: m.Metadata.Annotations["workflows.argoproj.io/description"],
+		Metadata:    m.Metadata.Labels,
+	}
+	if d := int(spec.ActiveDeadlineSeconds); d > 0 {
+		res.Workflow.Deadline = res.clamp("workflow", "deadline", d, 86400)
+	}
+
+	imp := &argoImport{
+		res:       res,
+		templates: make(map[string]*argoTemplate, len(spec.Templates)),
+		params:    make(map[string]string),
+	}
+	for i := range spec.Templates {
+		imp.templates[spec.Templates[i].Name] = &spec.Templates[i]
+	}
+	for _, p := range spec.Arguments.Parameters {
+		imp.params["workflow.parameters."+p.Name] = p.value()
+	}
+
+	entry, ok := imp.templates[spec.Entrypoint]
+	if !ok {
+		return nil, fmt.Errorf("entrypoint template %q not found", spec.Entrypoint)
+	}
+	var err error
+	switch {
+	case entry.DAG != nil:
+		err = imp.dag(entry.DAG.Tasks)
+	case entry.Steps != nil:
+		err = imp.steps(entry.Steps)
+	case entry.Container != nil || entry.Script != nil:
+		err = imp.task(argoTask{Name: entry.Name, Template: entry.Name}, nil)
+	default:
+		err = fmt.Errorf("entrypoint template %q is neither a DAG, steps nor a container", entry.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// argoImport converts the templates of one manifest.
+type argoImport struct {
+	res       *Result
+	templates map[string]*argoTemplate
+	// params are the workflow parameters, keyed by their reference without
+	// braces.
+	params map[string]string
+}
+
+func (imp *argoImport) dag(tasks []argoTask) error {
+	for _, t := range tasks {
+		deps := slices.Clone(t.Dependencies)
+		if t.Depends != "" {
+			deps = append(deps, imp.parseDepends(t.Name, t.Depends)...)
+		}
+		slices.Sort(deps)
+		if err := imp.task(t, slices.Compact(deps)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *argoImport) steps(groups [][]argoTask) error {
+	var prev []string
+	for _, group := range groups {
+		names := make([]string, 0, len(group))
+		for _, t := range group {
+			if err := imp.task(t, prev); err != nil {
+				return err
+			}
+			names = append(names, t.Name)
+		}
+		prev = names
+	}
+	return nil
+}
+
+// dependsToken matches a task, with an optional result, in a depends
+// expression.
+var dependsToken = regexp.MustCompile(`[A-Za-z0-9_-]+(\.[A-Za-z]+)?`)
+
+// parseDepends returns the tasks of a depends expression. Anything but a
+// conjunction of successes is simplified to one, with a warning.
+func (imp *argoImport) parseDepends(task, expr string) []string {
+	var deps []string
+	simplified := strings.ContainsAny(expr, "|!")
+	for _, tok := range dependsToken.FindAllString(expr, -1) {
+		name, result, _ := strings.Cut(tok, ".")
+		if result != "" && result != "Succeeded" {
+			simplified = true
+		}
+		deps = append(deps, name)
+	}
+	if simplified {
+		imp.res.warnf("task %s: depends %q simplified to the success of %s", task, expr, strings.Join(deps, ", "))
+	}
+	return deps
+}
+
+// task adds the task running the container or script template of t.
+func (imp *argoImport) task(t argoTask, deps []string) error {
+	switch {
+	case t.TemplateRef != nil:
+		return fmt.Errorf("task %s: templateRef is not supported", t.Name)
+	case t.WithItems != nil || t.WithParam != "":
+		return fmt.Errorf("task %s: loops are not supported", t.Name)
+	}
+	tmpl, ok := imp.templates[t.Template]
+	if !ok {
+		return fmt.Errorf("task %s: template %q not found", t.Name, t.Template)
+	}
+	c := tmpl.Container
+	var source string
+	if tmpl.Script != nil {
+		c, source = &tmpl.Script.argoContainer, tmpl.Script.Source
+	}
+	if c == nil {
+		return fmt.Errorf("task %s: template %s must be a container or script; nested templates are not supported", t.Name, tmpl.Name)
+	}
+	if t.When != "" {
+		imp.res.warnf("task %s: condition %q dropped, the task always runs", t.Name, t.When)
+	}
+
+	params := maps.Clone(imp.params)
+	for _, p := range tmpl.Inputs.Parameters {
+		params["inputs.parameters."+p.Name] = p.value()
+	}
+	for _, p := range t.Arguments.Parameters {
+		params["inputs.parameters."+p.Name] = imp.substitute(t.Name, p.value(), imp.params)
+	}
+	sub := func(s string) string { return imp.substitute(t.Name, s, params) }
+
+	spec := &models.ContainerSpec{Image: sub(c.Image)}
+	for _, s := range c.Command {
+		spec.Command = append(spec.Command, sub(s))
+	}
+	for _, s := range c.Args {
+		spec.Args = append(spec.Args, sub(s))
+	}
+	if source != "" {
+		spec.Args = append(spec.Args, "-c", sub(source))
+	}
+	for _, env := range c.Env {
+		if env.ValueFrom != nil {
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				imp.res.warnf("task %s: env %s from secret %s/%s dropped: add it to orchestration.secrets and the task's secrets", t.Name, env.Name, ref.Name, ref.Key)
+			} else {
+				imp.res.warnf("task %s: env %s dropped: only values and secrets are supported", t.Name, env.Name)
+			}
+			continue
+		}
+		if spec.Env == nil {
+			spec.Env = make(map[string]string)
+		}
+		spec.Env[env.Name] = sub(env.Value)
+	}
+
+	task := models.TaskDefinition{
+		ID:        t.Name,
+		Name:      t.Name,
+		Type:      dag.AgentContainer,
+		DependsOn: deps,
+		Container: spec,
+	}
+	resources, err := argoResources(c)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", t.Name, err)
+	}
+	task.Resources = resources
+	if tmpl.RetryStrategy != nil {
+		task.Retries = imp.res.clamp(t.Name, "retries", int(tmpl.RetryStrategy.Limit), maxTaskRetries)
+	}
+	if d := int(tmpl.ActiveDeadlineSeconds); d > 0 {
+		task.Timeout = imp.res.clamp(t.Name, "timeout", d, maxTaskTimeout)
+	}
+	imp.res.Workflow.Tasks = append(imp.res.Workflow.Tasks, task)
+	return nil
+}
+
+// substitute replaces the parameter references of s. References it cannot
+// resolve, such as task outputs, are kept with a warning.
+func (imp *argoImport) substitute(task, s string, params map[string]string) string {
+	var unresolved []string
+	out := argoReference.ReplaceAllStringFunc(s, func(ref string) string {
+		key := strings.TrimSpace(ref[2 : len(ref)-2])
+		if v, ok := params[key]; ok {
+			return v
+		}
+		unresolved = append(unresolved, key)
+		return ref
+	})
+	for _, key := range unresolved {
+		imp.res.warnf("task %s: reference {{%s}} left unresolved", task, key)
+	}
+	return out
+}
+
+// argoReference matches a {{...}} reference.
+var argoReference = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+func (p argoParameter) value() string {
+	switch {
+	case p.Value != nil:
+		return *p.Value
+	case p.Default != nil:
+		return *p.Default
+	}
+	return ""
+}
+
+// argoResources returns the limits of c, or its requests where it has no
+// limits.
+func argoResources(c *argoContainer) (*models.TaskResources, error) {
+	get := func(name string) string {
+		if v, ok := c.Resources.Limits[name]; ok {
+			return v
+		}
+		return c.Resources.Requests[name]
+	}
+	var r models.TaskResources
+	if v := get("cpu"); v != "" {
+		cpu, err := parseCPU(v)
+		if err != nil {
+			return nil, err
+		}
+		r.CPU = cpu
+	}
+	if v := get("memory"); v != "" {
+		bytes, err := parseBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		r.MemoryMB = int64(math.Ceil(bytes / (1 << 20)))
+	}
+	if v := get("nvidia.com/gpu"); v != "" {
+		gpu, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gpu quantity %q", v)
+		}
+		r.GPU = gpu
+	}
+	if r == (models.TaskResources{}) {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// parseCPU parses a Kubernetes CPU quantity such as 500m or 2.
+func parseCPU(s string) (float64, error) {
+	num, scale := s, 1.0
+	if strings.HasSuffix(s, "m") {
+		num, scale = strings.TrimSuffix(s, "m"), 0.001
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid cpu quantity %q", s)
+	}
+	return v * scale, nil
+}
+
+// quantitySuffixes are the multipliers of Kubernetes quantity suffixes.
+var quantitySuffixes = []struct {
+	suffix string
+	mult   float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// parseBytes parses a Kubernetes memory quantity such as 512Mi or 1G.
+func parseBytes(s string) (float64, error) {
+	num, mult := s, 1.0
+	for _, q := range quantitySuffixes {
+		if strings.HasSuffix(s, q.suffix) {
+			num, mult = strings.TrimSuffix(s, q.suffix), q.mult
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid memory quantity %q", s)
+	}
+	return v * mult, nil
+}
@@ -0,0 +1,84 @@
+// Package importer converts workflows of other engines into Goclaw workflow
+// templates, easing migration from them.
+//
+// Argo Workflows manifests map closely: a DAG or steps template becomes the
+// workflow and each container or script template it runs becomes a
+// container task. Temporal has no declarative workflow definition, so a
+// Temporal workflow is imported from the JSON export of one of its
+// executions: each scheduled activity becomes a function task, depending on
+// the activities that had completed when it was scheduled. The bodies of
+// the activities have to be ported separately.
+//
+// What a template cannot express, such as conditions or output parameters,
+// is dropped with a warning rather than failing the import.
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// Source formats.
+const (
+	FormatArgo     = "argo"
+	FormatTemporal = "temporal"
+)
+
+// Bounds of the task fields imported values are clamped to.
+const (
+	maxTaskTimeout = 3600
+	maxTaskRetries = 5
+)
+
+// Result is an imported workflow template.
+type Result struct {
+	// Workflow is the template, in the body format of POST /api/v1/workflows.
+	Workflow models.WorkflowRequest
+
+	// Warnings describe what the import dropped or changed.
+	Warnings []string
+}
+
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Import converts data in format into a workflow template.
+func Import(format string, data []byte) (*Result, error) {
+	switch format {
+	case FormatArgo:
+		return FromArgo(data)
+	case FormatTemporal:
+		return FromTemporal(data)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+}
+
+// DetectFormat guesses the format of the file path from its extension:
+// YAML is an Argo manifest and JSON a Temporal history export.
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatArgo, nil
+	case ".json":
+		return FormatTemporal, nil
+	default:
+		return "", fmt.Errorf("cannot detect the format of %s", path)
+	}
+}
+
+// clamp bounds v to [0, max], warning when it had to change.
+func (r *Result) clamp(task, field string, v, max int) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > max:
+		r.warnf("task %s: %s %d lowered to %d", task, field, v, max)
+		return max
+	}
+	return v
+}
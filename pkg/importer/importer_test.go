@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+const argoDAG = `
+apiVersion: argoproj.io/v1alpha1
+kind: Workflow
+metadata:
+  generateName: etl-
+  labels:
+    team: data
+spec:
+  entrypoint: main
+  activeDeadlineSeconds: 600
+  arguments:
+    parameters:
+      - name: date
+        value: "2026-01-01"
+  templates:
+    - name: main
+      dag:
+        tasks:
+          - name: extract
+            template: run
+            arguments:
+              parameters:
+                - name: step
+                  value: "extract {{workflow.parameters.date}}"
+          - name: transform
+            template: run
+            dependencies: [extract]
+            arguments:
+              parameters:
+                - name: step
+                  value: transform
+          - name: load
+            template: script
+            depends: "transform && (extract.Succeeded || extract.Skipped)"
+            when: "{{workflow.parameters.date}} != ''"
+    - name: run
+      inputs:
+        parameters:
+          - name: step
+      retryStrategy:
+        limit: "9"
+      container:
+        image: alpine:3
+        command: [sh, -c]
+        args: ["echo {{inputs.parameters.step}}"]
+        env:
+          - name: MODE
+            value: batch
+          - name: TOKEN
+            valueFrom:
+              secretKeyRef: {name: api, key: token}
+        resources:
+          requests:
+            cpu: 250m
+          limits:
+            cpu: 2
+            memory: 512Mi
+    - name: script
+      activeDeadlineSeconds: 120
+      script:
+        image: python:3.12
+        command: [python]
+        source: print("{{tasks.transform.outputs.result}}")
+`
+
+func TestFromArgo_DAG(t *testing.T) {
+	res, err := FromArgo([]byte(argoDAG))
+	if err != nil {
+		t.Fatalf("FromArgo failed: %v", err)
+	}
+	wf := res.Workflow
+	if wf.Name != "etl" || wf.Deadline != 600 || wf.Metadata["team"] != "data" {
+		t.Fatalf("unexpected workflow fields: %+v", wf)
+	}
+	if len(wf.Tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(wf.Tasks))
+	}
+
+	extract, transform, load := wf.Tasks[0], wf.Tasks[1], wf.Tasks[2]
+	if extract.Type != "container" || extract.Container.Image != "alpine:3" {
+		t.Fatalf("unexpected extract task: %+v", extract)
+	}
+	if got := extract.Container.Args; !reflect.DeepEqual(got, []string{"echo extract 2026-01-01"}) {
+		t.Fatalf("parameters not substituted: %q", got)
+	}
+	if got := extract.Container.Env; !reflect.DeepEqual(got, map[string]string{"MODE": "batch"}) {
+		t.Fatalf("unexpected env: %v", got)
+	}
+	if extract.Retries != 5 {
+		t.Fatalf("expected retries clamped to 5, got %d", extract.Retries)
+	}
+	if want := (&models.TaskResources{CPU: 2, MemoryMB: 512}); !reflect.DeepEqual(extract.Resources, want) {
+		t.Fatalf("resources = %+v, want %+v", extract.Resources, want)
+	}
+	if !reflect.DeepEqual(transform.DependsOn, []string{"extract"}) {
+		t.Fatalf("transform depends on %v", transform.DependsOn)
+	}
+	if !reflect.DeepEqual(load.DependsOn, []string{"extract", "transform"}) {
+		t.Fatalf("load depends on %v", load.DependsOn)
+	}
+	if got := load.Container.Args; !reflect.DeepEqual(got, []string{"-c", `print("{{tasks.transform.outputs.result}}")`}) {
+		t.Fatalf("unexpected script args: %q", got)
+	}
+	if load.Timeout != 120 {
+		t.Fatalf("expected timeout 120, got %d", load.Timeout)
+	}
+
+	for _, want := range []string{"retries 9", "secret api/token", "depends", "condition", "tasks.transform.outputs.result"} {
+		if !containsWarning(res.Warnings, want) {
+			t.Errorf("no warning mentions %q: %q", want, res.Warnings)
+		}
+	}
+}
+
+func TestFromArgo_Steps(t *testing.T) {
+	manifest := `
+kind: WorkflowTemplate
+metadata:
+  name: steps
+spec:
+  entrypoint: main
+  templates:
+    - name: main
+      steps:
+        - - name: a
+            template: echo
+        - - name: b
+            template: echo
+          - name: c
+            template: echo
+        - - name: d
+            template: echo
+    - name: echo
+      container:
+        image: alpine:3
+`
+	res, err := FromArgo([]byte(manifest))
+	if err != nil {
+		t.Fatalf("FromArgo failed: %v", err)
+	}
+	deps := make(map[string][]string)
+	for _, task := range res.Workflow.Tasks {
+		deps[task.ID] = task.DependsOn
+	}
+	want := map[string][]string{"a": nil, "b": {"a"}, "c": {"a"}, "d": {"b", "c"}}
+	if !reflect.DeepEqual(deps, want) {
+		t.Fatalf("dependencies = %v, want %v", deps, want)
+	}
+}
+
+func TestFromArgo_Unsupported(t *testing.T) {
+	tests := map[string]string{
+		"kind": "kind: Pod\nmetadata: {name: x}",
+		"entrypoint": `
+kind: Workflow
+metadata: {name: x}
+spec: {entrypoint: missing}`,
+		"nested": `
+kind: Workflow
+metadata: {name: x}
+spec:
+  entrypoint: main
+  templates:
+    - name: main
+      dag:
+        tasks: [{name: inner, template: main}]`,
+		"loop": `
+kind: Workflow
+metadata: {name: x}
+spec:
+  entrypoint: main
+  templates:
+    - name: main
+      dag:
+        tasks: [{name: each, template: echo, withItems: [1, 2]}]
+    - name: echo
+      container: {image: alpine}`,
+	}
+	for name, manifest := range tests {
+		if _, err := FromArgo([]byte(manifest)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+const temporalHistoryJSON = `{"events": [
+  {"eventId": "1", "eventType": "EVENT_TYPE_WORKFLOW_EXECUTION_STARTED",
+   "workflowExecutionStartedEventAttributes": {"workflowType": {"name": "OrderWorkflow"}, "taskQueue": {"name": "orders"}}},
+  {"eventId": "4", "eventType": "EVENT_TYPE_WORKFLOW_TASK_COMPLETED"},
+  {"eventId": "5", "eventType": "EVENT_TYPE_ACTIVITY_TASK_SCHEDULED",
+   "activityTaskScheduledEventAttributes": {"activityId": "5", "activityType": {"name": "Reserve"}, "taskQueue": {"name": "orders"},
+    "input": {"payloads": [{"metadata": {"encoding": "anNvbi9wbGFpbg=="}, "data": "eyJpZCI6NDJ9"}]},
+    "startToCloseTimeout": "10s", "heartbeatTimeout": "1.5s", "workflowTaskCompletedEventId": "4",
+    "retryPolicy": {"maximumAttempts": 3}}},
+  {"eventId": "6", "eventType": "EVENT_TYPE_ACTIVITY_TASK_SCHEDULED",
+   "activityTaskScheduledEventAttributes": {"activityId": "6", "activityType": {"name": "Charge"}, "scheduleToCloseTimeout": "2h",
+    "workflowTaskCompletedEventId": "4", "retryPolicy": {}}},
+  {"eventId": "8", "eventType": "EVENT_TYPE_ACTIVITY_TASK_COMPLETED", "activityTaskCompletedEventAttributes": {"scheduledEventId": "5"}},
+  {"eventId": "11", "eventType": "EVENT_TYPE_WORKFLOW_TASK_COMPLETED"},
+  {"eventId": "12", "eventType": "EVENT_TYPE_ACTIVITY_TASK_SCHEDULED",
+   "activityTaskScheduledEventAttributes": {"activityId": "12", "activityType": {"name": "Pack"}, "workflowTaskCompletedEventId": "11"}},
+  {"eventId": "13", "eventType": "EVENT_TYPE_ACTIVITY_TASK_COMPLETED", "activityTaskCompletedEventAttributes": {"scheduledEventId": "6"}},
+  {"eventId": "14", "eventType": "EVENT_TYPE_ACTIVITY_TASK_COMPLETED", "activityTaskCompletedEventAttributes": {"scheduledEventId": "12"}},
+  {"eventId": 17, "eventType": "WorkflowTaskCompleted"},
+  {"eventId": 18, "eventType": "ActivityTaskScheduled",
+   "activityTaskScheduledEventAttributes": {"activityId": "ship", "activityType": {"name": "Ship"}, "workflowTaskCompletedEventId": 17}}
+]}`
+
+func TestFromTemporal(t *testing.T) {
+	res, err := FromTemporal([]byte(temporalHistoryJSON))
+	if err != nil {
+		t.Fatalf("FromTemporal failed: %v", err)
+	}
+	wf := res.Workflow
+	if wf.Name != "OrderWorkflow" || len(wf.Tasks) != 4 {
+		t.Fatalf("unexpected workflow: %+v", wf)
+	}
+
+	deps := make(map[string][]string)
+	for _, task := range wf.Tasks {
+		deps[task.ID] = task.DependsOn
+	}
+	// Pack was scheduled once Reserve completed; Ship once all had, but
+	// depends on Reserve only through Pack.
+	want := map[string][]string{"5": nil, "6": nil, "12": {"5"}, "ship": {"12", "6"}}
+	if !reflect.DeepEqual(deps, want) {
+		t.Fatalf("dependencies = %v, want %v", deps, want)
+	}
+
+	reserve, charge := wf.Tasks[0], wf.Tasks[1]
+	if reserve.Type != "function" || reserve.Name != "Reserve" {
+		t.Fatalf("unexpected reserve task: %+v", reserve)
+	}
+	wantConfig := map[string]interface{}{
+		"activity":   "Reserve",
+		"task_queue": "orders",
+		"input":      []interface{}{map[string]interface{}{"id": float64(42)}},
+	}
+	if !reflect.DeepEqual(reserve.Config, wantConfig) {
+		t.Fatalf("config = %v, want %v", reserve.Config, wantConfig)
+	}
+	if reserve.Timeout != 10 || reserve.HeartbeatTimeout != 2 || reserve.Retries != 2 {
+		t.Fatalf("unexpected reserve limits: %+v", reserve)
+	}
+	if charge.Timeout != 3600 || charge.Retries != 5 {
+		t.Fatalf("unexpected charge limits: %+v", charge)
+	}
+	if !containsWarning(res.Warnings, "unlimited retries") || !containsWarning(res.Warnings, "timeout 7200") {
+		t.Fatalf("unexpected warnings: %q", res.Warnings)
+	}
+}
+
+func TestFromTemporal_NoActivities(t *testing.T) {
+	history := `{"events": [{"eventId": "1", "eventType": "WorkflowExecutionStarted",
+		"workflowExecutionStartedEventAttributes": {"workflowType": {"name": "Empty"}}}]}`
+	if _, err := FromTemporal([]byte(history)); err == nil {
+		t.Fatal("expected an error for a history without activities")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	for path, want := range map[string]string{"wf.yaml": FormatArgo, "WF.YML": FormatArgo, "history.json": FormatTemporal} {
+		if got, err := DetectFormat(path); err != nil || got != want {
+			t.Errorf("DetectFormat(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := DetectFormat("workflow.txt"); err == nil {
+		t.Error("expected an error for an unknown extension")
+	}
+}
+
+func containsWarning(warnings []string, s string) bool {
+	for _, w := range warnings {
+		if strings.Contains(w, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// temporalHistory is the part of a workflow history exported with
+// "temporal workflow show --output json" or tctl the import reads.
+type temporalHistory struct {
+	Events []temporalEvent `json:"events"`
+}
+
+type temporalEvent struct {
+	EventID   eventID `json:"eventId"`
+	EventType string  `json:"eventType"`
+
+	Started *struct {
+		WorkflowType temporalName `json:"workflowType"`
+		TaskQueue    temporalName `json:"taskQueue"`
+	} `json:"workflowExecutionStartedEventAttributes"`
+
+	Scheduled *struct {
+		ActivityID   string       `json:"activityId"`
+		ActivityType temporalName `json:"activityType"`
+		TaskQueue    temporalName `json:"taskQueue"`
+		Input        *struct {
+			Payloads []temporalPayload `json:"payloads"`
+		} `json:"input"`
+		StartToCloseTimeout          string  `json:"startToCloseTimeout"`
+		ScheduleToCloseTimeout       string  `json:"scheduleToCloseTimeout"`
+		HeartbeatTimeout             string  `json:"heartbeatTimeout"`
+		WorkflowTaskCompletedEventID eventID `json:"workflowTaskCompletedEventId"`
+		RetryPolicy                  *struct {
+			MaximumAttempts int `json:"maximumAttempts"`
+		} `json:"retryPolicy"`
+	} `json:"activityTaskScheduledEventAttributes"`
+
+	Completed *struct {
+		ScheduledEventID eventID `json:"scheduledEventId"`
+	} `json:"activityTaskCompletedEventAttributes"`
+}
+
+type temporalName struct {
+	Name string `json:"name"`
+}
+
+type temporalPayload struct {
+	Metadata map[string]string `json:"metadata"`
+	Data     string            `json:"data"`
+}
+
+// eventID is an event ID, which the proto JSON encoding writes as a string
+// and tctl as a number.
+type eventID int64
+
+func (id *eventID) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseInt(string(bytes.Trim(b, `"`)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid event id %s", b)
+	}
+	*id = eventID(v)
+	return nil
+}
+
+// temporalActivity is a scheduled activity and when it completed.
+type temporalActivity struct {
+	event     *temporalEvent
+	id        string
+	completed eventID
+	deps      map[string]bool
+}
+
+// FromTemporal converts the JSON history of a Temporal workflow execution
+// into a workflow template. Every scheduled activity becomes a function
+// task whose config names the activity, its task queue and its input. A
+// task depends on the activities that had completed when the workflow
+// scheduled it, leaving out those it already depends on through others.
+func FromTemporal(data []byte) (*Result, error) {
+	var h temporalHistory
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parse temporal history: %w", err)
+	}
+
+	res := &Result{}
+	var activities []*temporalActivity
+	byEvent := make(map[eventID]*temporalActivity)
+	for i := range h.Events {
+		ev := &h.Events[i]
+		switch temporalEventType(ev.EventType) {
+		case "WorkflowExecutionStarted":
+			if ev.Started != nil {
+				res.Workflow.Name = ev.Started.WorkflowType.Name
+			}
+		case "ActivityTaskScheduled":
+			if ev.Scheduled == nil {
+				return nil, fmt.Errorf("event %d: missing activity attributes", ev.EventID)
+			}
+			a := &temporalActivity{event: ev, id: ev.Scheduled.ActivityID, deps: make(map[string]bool)}
+			if a.id == "" {
+				a.id = strconv.FormatInt(int64(ev.EventID), 10)
+			}
+			activities = append(activities, a)
+			byEvent[ev.EventID] = a
+		case "ActivityTaskCompleted":
+			if ev.Completed == nil {
+				continue
+			}
+			if a, ok := byEvent[ev.Completed.ScheduledEventID]; ok {
+				a.completed = ev.EventID
+			}
+		}
+	}
+	if res.Workflow.Name == "" {
+		return nil, fmt.Errorf("temporal history has no workflow execution started event")
+	}
+	if len(activities) == 0 {
+		return nil, fmt.Errorf("temporal history of %s schedules no activities", res.Workflow.Name)
+	}
+
+	// closure holds the transitive dependencies of each activity, which
+	// only depends on activities scheduled before it.
+	closure := make(map[string]map[string]bool, len(activities))
+	for i, a := range activities {
+		scheduledBy := a.event.Scheduled.WorkflowTaskCompletedEventID
+		all := make(map[string]bool)
+		for _, b := range activities[:i] {
+			if b.completed != 0 && b.completed < scheduledBy {
+				all[b.id] = true
+				for id := range closure[b.id] {
+					all[id] = true
+				}
+			}
+		}
+		closure[a.id] = all
+		for _, b := range activities[:i] {
+			if !all[b.id] {
+				continue
+			}
+			a.deps[b.id] = true
+			for id := range closure[b.id] {
+				delete(a.deps, id)
+			}
+		}
+	}
+	for _, a := range activities {
+		task, err := res.temporalTask(a)
+		if err != nil {
+			return nil, err
+		}
+		res.Workflow.Tasks = append(res.Workflow.Tasks, task)
+	}
+	return res, nil
+}
+
+// temporalEventType strips the EVENT_TYPE_ prefix of the proto JSON
+// encoding, so that EVENT_TYPE_ACTIVITY_TASK_SCHEDULED becomes
+// ActivityTaskScheduled as tctl writes it.
+func temporalEventType(t string) string {
+	rest, ok := strings.CutPrefix(t, "EVENT_TYPE_")
+	if !ok {
+		return t
+	}
+	var b strings.Builder
+	for _, word := range strings.Split(strings.ToLower(rest), "_") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+func (r *Result) temporalTask(a *temporalActivity) (models.TaskDefinition, error) {
+	attrs := a.event.Scheduled
+	config := map[string]interface{}{"activity": attrs.ActivityType.Name}
+	if attrs.TaskQueue.Name != "" {
+		config["task_queue"] = attrs.TaskQueue.Name
+	}
+	if attrs.Input != nil && len(attrs.Input.Payloads) > 0 {
+		input := make([]interface{}, 0, len(attrs.Input.Payloads))
+		for _, p := range attrs.Input.Payloads {
+			input = append(input, r.decodePayload(a.id, p))
+		}
+		config["input"] = input
+	}
+
+	task := models.TaskDefinition{
+		ID:     a.id,
+		Name:   attrs.ActivityType.Name,
+		Type:   "function",
+		Config: config,
+	}
+	for id := range a.deps {
+		task.DependsOn = append(task.DependsOn, id)
+	}
+	slices.Sort(task.DependsOn)
+
+	timeout := attrs.StartToCloseTimeout
+	if timeout == "" {
+		timeout = attrs.ScheduleToCloseTimeout
+	}
+	secs, err := temporalSeconds(timeout)
+	if err != nil {
+		return task, fmt.Errorf("activity %s: timeout: %w", a.id, err)
+	}
+	task.Timeout = r.clamp(a.id, "timeout", secs, maxTaskTimeout)
+	if secs, err = temporalSeconds(attrs.HeartbeatTimeout); err != nil {
+		return task, fmt.Errorf("activity %s: heartbeat timeout: %w", a.id, err)
+	}
+	task.HeartbeatTimeout = r.clamp(a.id, "heartbeat timeout", secs, maxTaskTimeout)
+	if p := attrs.RetryPolicy; p != nil {
+		if p.MaximumAttempts == 0 {
+			// Temporal retries without limit.
+			task.Retries = maxTaskRetries
+			r.warnf("task %s: unlimited retries lowered to %d", a.id, maxTaskRetries)
+		} else {
+			task.Retries = r.clamp(a.id, "retries", p.MaximumAttempts-1, maxTaskRetries)
+		}
+	}
+	return task, nil
+}
+
+// decodePayload returns the value of a json/plain payload, or its encoded
+// data otherwise.
+func (r *Result) decodePayload(task string, p temporalPayload) interface{} {
+	encoding, _ := base64.StdEncoding.DecodeString(p.Metadata["encoding"])
+	data, err := base64.StdEncoding.DecodeString(p.Data)
+	if err == nil && string(encoding) == "json/plain" {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err == nil {
+			return v
+		}
+	}
+	r.warnf("task %s: input with encoding %q kept encoded", task, encoding)
+	return p.Data
+}
+
+// temporalSeconds returns a proto JSON duration such as "10s" in whole
+// seconds, rounding up. The empty duration is 0.
+func temporalSeconds(d string) (int, error) {
+	if d == "" {
+		return 0, nil
+	}
+	v, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Ceil(v.Seconds())), nil
+}
@@ -0,0 +1,272 @@
+// Package insights learns the typical durations of workflows and tasks and
+// flags runs that take unusually long.
+package insights
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/stats"
+)
+
+const (
+	defaultWindow       = 500
+	defaultMaxAnomalies = 100
+)
+
+// Reasons a run is flagged.
+const (
+	ReasonZScore     = "z_score"
+	ReasonPercentile = "percentile"
+)
+
+// Stats summarises the recent durations of a workflow, or of one of its tasks
+// when Task is set.
+type Stats struct {
+	Workflow string
+	Task     string
+	// Samples is the number of recent runs the figures cover.
+	Samples int
+	Mean    time.Duration
+	StdDev  time.Duration
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	// Anomalies is the number of runs flagged since the detector started.
+	Anomalies int
+}
+
+// Anomaly is a completed run that took unusually long compared with the
+// recent runs of its workflow or task.
+type Anomaly struct {
+	WorkflowID string
+	Workflow   string
+	// Task is empty for the duration of a whole workflow run.
+	Task     string
+	Duration time.Duration
+	Mean     time.Duration
+	StdDev   time.Duration
+	// ZScore is the number of standard deviations above the mean.
+	ZScore float64
+	// Percentile is the share of recent runs that were faster, from 0 to 1.
+	Percentile float64
+	// Reason is ReasonZScore or ReasonPercentile.
+	Reason     string
+	DetectedAt time.Time
+}
+
+// Detector learns durations per workflow and per workflow task. It is safe
+// for concurrent use.
+type Detector struct {
+	zScore     float64
+	percentile float64
+	minSamples int
+	window     int
+
+	mu           sync.Mutex
+	series       map[seriesKey]*series
+	anomalies    []Anomaly
+	maxAnomalies int
+}
+
+type seriesKey struct {
+	workflow string
+	task     string
+}
+
+// series is the ring of recent durations of one workflow or task.
+type series struct {
+	samples   []time.Duration
+	next      int
+	full      bool
+	anomalies int
+}
+
+// New creates a detector with the thresholds of cfg.
+func New(cfg config.InsightsConfig) *Detector {
+	d := &Detector{
+		zScore:       cfg.ZScore,
+		percentile:   cfg.Percentile,
+		minSamples:   cfg.MinSamples,
+		window:       cfg.Window,
+		maxAnomalies: cfg.MaxAnomalies,
+		series:       make(map[seriesKey]*series),
+	}
+	if d.window <= 0 {
+		d.window = defaultWindow
+	}
+	if d.maxAnomalies <= 0 {
+		d.maxAnomalies = defaultMaxAnomalies
+	}
+	// A percentile needs at least a few runs to mean anything.
+	if d.minSamples < 2 {
+		d.minSamples = 2
+	}
+	return d
+}
+
+// Observe learns the duration of a completed run of workflow, or of its task
+// when task is set, and returns the anomaly when the run was unusually slow.
+// The run is judged against the runs observed before it.
+func (d *Detector) Observe(workflowID, workflow, task string, dur time.Duration, at time.Time) *Anomaly {
+	if dur < 0 {
+		dur = 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := seriesKey{workflow: workflow, task: task}
+	s := d.series[key]
+	if s == nil {
+		s = &series{samples: make([]time.Duration, d.window)}
+		d.series[key] = s
+	}
+
+	anomaly := d.judge(s.recent(), dur)
+	s.add(dur)
+	if anomaly == nil {
+		return nil
+	}
+	anomaly.WorkflowID = workflowID
+	anomaly.Workflow = workflow
+	anomaly.Task = task
+	anomaly.DetectedAt = at
+	s.anomalies++
+	d.anomalies = append(d.anomalies, *anomaly)
+	if len(d.anomalies) > d.maxAnomalies {
+		d.anomalies = d.anomalies[len(d.anomalies)-d.maxAnomalies:]
+	}
+	return anomaly
+}
+
+// judge returns the anomaly of dur against recent, or nil when it is within
+// the thresholds or too few runs were learned.
+func (d *Detector) judge(recent []time.Duration, dur time.Duration) *Anomaly {
+	if len(recent) < d.minSamples {
+		return nil
+	}
+	mean, stddev := meanStdDev(recent)
+	a := &Anomaly{Duration: dur, Mean: mean, StdDev: stddev, Percentile: rank(recent, dur)}
+	if stddev > 0 {
+		a.ZScore = float64(dur-mean) / float64(stddev)
+	}
+
+	switch {
+	case d.zScore > 0 && a.ZScore > d.zScore:
+		a.Reason = ReasonZScore
+	case d.percentile > 0 && dur > stats.Percentile(sorted(recent), d.percentile):
+		a.Reason = ReasonPercentile
+	default:
+		return nil
+	}
+	return a
+}
+
+// Stats returns the durations learned per workflow and task, sorted by
+// workflow then task. A non-empty workflow limits them to that workflow.
+func (d *Detector) Stats(workflow string) []Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]Stats, 0, len(d.series))
+	for key, s := range d.series {
+		if workflow != "" && key.workflow != workflow {
+			continue
+		}
+		recent := sorted(s.recent())
+		mean, stddev := meanStdDev(recent)
+		out = append(out, Stats{
+			Workflow:  key.workflow,
+			Task:      key.task,
+			Samples:   len(recent),
+			Mean:      mean,
+			StdDev:    stddev,
+			P50:       stats.Percentile(recent, 0.50),
+			P95:       stats.Percentile(recent, 0.95),
+			P99:       stats.Percentile(recent, 0.99),
+			Anomalies: s.anomalies,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Workflow != out[j].Workflow {
+			return out[i].Workflow < out[j].Workflow
+		}
+		return out[i].Task < out[j].Task
+	})
+	return out
+}
+
+// Anomalies returns the most recent anomalies, newest first. A non-empty
+// workflow limits them to that workflow.
+func (d *Detector) Anomalies(workflow string) []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]Anomaly, 0, len(d.anomalies))
+	for i := len(d.anomalies) - 1; i >= 0; i-- {
+		if workflow != "" && d.anomalies[i].Workflow != workflow {
+			continue
+		}
+		out = append(out, d.anomalies[i])
+	}
+	return out
+}
+
+func (s *series) add(d time.Duration) {
+	s.samples[s.next] = d
+	s.next = (s.next + 1) % len(s.samples)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// recent returns a copy of the samples in the ring.
+func (s *series) recent() []time.Duration {
+	n := s.next
+	if s.full {
+		n = len(s.samples)
+	}
+	out := make([]time.Duration, n)
+	copy(out, s.samples[:n])
+	return out
+}
+
+func meanStdDev(samples []time.Duration) (time.Duration, time.Duration) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, d := range samples {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(samples))
+	var sq float64
+	for _, d := range samples {
+		diff := float64(d) - mean
+		sq += diff * diff
+	}
+	return time.Duration(mean), time.Duration(math.Sqrt(sq / float64(len(samples))))
+}
+
+// rank returns the share of samples shorter than d.
+func rank(samples []time.Duration, d time.Duration) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	below := 0
+	for _, s := range samples {
+		if s < d {
+			below++
+		}
+	}
+	return float64(below) / float64(len(samples))
+}
+
+func sorted(samples []time.Duration) []time.Duration {
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples
+}
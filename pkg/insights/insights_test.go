@@ -0,0 +1,77 @@
+package insights
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+func TestDetector_FlagsZScore(t *testing.T) {
+	d := New(config.InsightsConfig{ZScore: 3, MinSamples: 10})
+	now := time.Now()
+
+	for i := 0; i < 20; i++ {
+		dur := 100*time.Millisecond + time.Duration(i%5)*time.Millisecond
+		if a := d.Observe("wf-"+string(rune('a'+i)), "etl", "", dur, now); a != nil {
+			t.Fatalf("run %d flagged as %+v", i, a)
+		}
+	}
+
+	a := d.Observe("wf-slow", "etl", "", time.Second, now)
+	if a == nil {
+		t.Fatal("expected the slow run to be flagged")
+	}
+	if a.Reason != ReasonZScore || a.WorkflowID != "wf-slow" || a.Workflow != "etl" || a.Task != "" {
+		t.Fatalf("unexpected anomaly %+v", a)
+	}
+	if a.ZScore <= 3 || a.Percentile != 1 {
+		t.Fatalf("z-score %v, percentile %v", a.ZScore, a.Percentile)
+	}
+
+	if got := d.Anomalies(""); len(got) != 1 || got[0].WorkflowID != "wf-slow" {
+		t.Fatalf("anomalies = %+v", got)
+	}
+	stats := d.Stats("etl")
+	if len(stats) != 1 || stats[0].Samples != 21 || stats[0].Anomalies != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestDetector_WarmUpAndPercentile(t *testing.T) {
+	d := New(config.InsightsConfig{Percentile: 0.9, MinSamples: 5})
+	now := time.Now()
+
+	// Too few runs to judge.
+	for i := 1; i <= 4; i++ {
+		if a := d.Observe("wf", "build", "compile", time.Duration(i)*time.Hour, now); a != nil {
+			t.Fatalf("run %d flagged during warm-up", i)
+		}
+	}
+	for i := 5; i <= 10; i++ {
+		d.Observe("wf", "build", "compile", time.Duration(i)*time.Second, now)
+	}
+
+	a := d.Observe("wf", "build", "compile", 5*time.Hour, now)
+	if a == nil || a.Reason != ReasonPercentile || a.Task != "compile" {
+		t.Fatalf("unexpected anomaly %+v", a)
+	}
+	if got := d.Anomalies("other"); len(got) != 0 {
+		t.Fatalf("anomalies of another workflow = %+v", got)
+	}
+}
+
+func TestDetector_BoundsWindowAndAnomalies(t *testing.T) {
+	d := New(config.InsightsConfig{ZScore: 1, MinSamples: 2, Window: 4, MaxAnomalies: 2})
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		d.Observe("wf", "grow", "", time.Duration(1<<i)*time.Millisecond, now)
+	}
+	if stats := d.Stats(""); stats[0].Samples != 4 {
+		t.Fatalf("samples = %d, want 4", stats[0].Samples)
+	}
+	if got := d.Anomalies(""); len(got) != 2 {
+		t.Fatalf("kept %d anomalies, want 2", len(got))
+	}
+}
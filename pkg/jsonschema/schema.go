@@ -0,0 +1,501 @@
+// Package jsonschema validates JSON values against the subset of JSON Schema
+// task output schemas, signal payload schemas and tool input schemas use:
+// types, objects, arrays, enumerations, numeric and length bounds, patterns
+// and the allOf, anyOf, oneOf and not combinators.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// annotations are keywords that do not constrain values.
+var annotations = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+	"format":      true,
+	"deprecated":  true,
+	"readOnly":    true,
+	"writeOnly":   true,
+}
+
+// types are the values of the type keyword.
+var types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	types []string
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	minProperties        *int
+	maxProperties        *int
+
+	items    *Schema
+	minItems *int
+	maxItems *int
+
+	enum   []any
+	consts []any
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+
+	// never is the false schema, which no value is valid against.
+	never bool
+}
+
+// Compile compiles the schema doc, a JSON object decoded into a map.
+// Keywords outside the supported subset, such as $ref, are rejected rather
+// than ignored.
+func Compile(doc map[string]any) (*Schema, error) {
+	return compile(doc, "")
+}
+
+func compile(doc any, path string) (*Schema, error) {
+	switch doc := doc.(type) {
+	case bool:
+		return &Schema{never: !doc}, nil
+	case map[string]any:
+		s := &Schema{}
+		keys := make([]string, 0, len(doc))
+		for key := range doc {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := s.compileKeyword(key, doc[key], path); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("schema%s: must be an object or a boolean", at(path))
+	}
+}
+
+func (s *Schema) compileKeyword(key string, value any, path string) error {
+	var err error
+	switch key {
+	case "type":
+		s.types, err = compileTypes(value)
+	case "properties":
+		props, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("schema%s: properties must be an object", at(path))
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, prop := range props {
+			if s.properties[name], err = compile(prop, path+"/properties/"+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "required":
+		s.required, err = stringList(value)
+	case "additionalProperties":
+		if allowed, ok := value.(bool); ok {
+			s.noAdditional = !allowed
+			return nil
+		}
+		s.additionalProperties, err = compile(value, path+"/additionalProperties")
+		return err
+	case "minProperties":
+		s.minProperties, err = count(value)
+	case "maxProperties":
+		s.maxProperties, err = count(value)
+	case "items":
+		s.items, err = compile(value, path+"/items")
+		return err
+	case "minItems":
+		s.minItems, err = count(value)
+	case "maxItems":
+		s.maxItems, err = count(value)
+	case "enum":
+		values, ok := value.([]any)
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("schema%s: enum must be a non-empty array", at(path))
+		}
+		s.enum = values
+	case "const":
+		s.consts = []any{value}
+	case "minimum":
+		s.minimum, err = number(value)
+	case "maximum":
+		s.maximum, err = number(value)
+	case "exclusiveMinimum":
+		s.exclusiveMinimum, err = number(value)
+	case "exclusiveMaximum":
+		s.exclusiveMaximum, err = number(value)
+	case "multipleOf":
+		if s.multipleOf, err = number(value); err == nil && *s.multipleOf <= 0 {
+			err = fmt.Errorf("must be greater than 0")
+		}
+	case "minLength":
+		s.minLength, err = count(value)
+	case "maxLength":
+		s.maxLength, err = count(value)
+	case "pattern":
+		pattern, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("schema%s: pattern must be a string", at(path))
+		}
+		s.pattern, err = regexp.Compile(pattern)
+	case "allOf":
+		s.allOf, err = compileList(value, path, key)
+		return err
+	case "anyOf":
+		s.anyOf, err = compileList(value, path, key)
+		return err
+	case "oneOf":
+		s.oneOf, err = compileList(value, path, key)
+		return err
+	case "not":
+		s.not, err = compile(value, path+"/not")
+		return err
+	default:
+		if annotations[key] {
+			return nil
+		}
+		return fmt.Errorf("schema%s: unsupported keyword %s", at(path), key)
+	}
+	if err != nil {
+		return fmt.Errorf("schema%s: %s: %w", at(path), key, err)
+	}
+	return nil
+}
+
+func compileTypes(value any) ([]string, error) {
+	names, err := stringList(value)
+	if name, ok := value.(string); ok {
+		names, err = []string{name}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !slices.Contains(types, name) {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+func compileList(value any, path, key string) ([]*Schema, error) {
+	docs, ok := value.([]any)
+	if !ok || len(docs) == 0 {
+		return nil, fmt.Errorf("schema%s: %s must be a non-empty array", at(path), key)
+	}
+	list := make([]*Schema, len(docs))
+	for i, doc := range docs {
+		var err error
+		if list[i], err = compile(doc, fmt.Sprintf("%s/%s/%d", path, key, i)); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func stringList(value any) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	list := make([]string, len(values))
+	for i, v := range values {
+		if list[i], ok = v.(string); !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+	}
+	return list, nil
+}
+
+func number(value any) (*float64, error) {
+	n, ok := toFloat(value)
+	if !ok {
+		return nil, fmt.Errorf("must be a number")
+	}
+	return &n, nil
+}
+
+func count(value any) (*int, error) {
+	n, ok := toFloat(value)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, fmt.Errorf("must be a non-negative integer")
+	}
+	c := int(n)
+	return &c, nil
+}
+
+// toFloat returns the number value holds: a float64 or json.Number as
+// decoded by encoding/json, or a Go integer.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " at " + path
+}
+
+// Violation is one way a value does not match a schema.
+type Violation struct {
+	// Path locates the offending value from the validated one: property
+	// names as strings and array indices as ints. Missing required and
+	// disallowed properties are located at the property.
+	Path []any
+	// Keyword is the schema keyword the value violates.
+	Keyword string
+	// Message describes the violation.
+	Message string
+}
+
+// Pointer returns Path as a JSON Pointer, "/" for the validated value.
+func (v Violation) Pointer() string {
+	if len(v.Path) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, elem := range v.Path {
+		fmt.Fprintf(&b, "/%v", elem)
+	}
+	return b.String()
+}
+
+func (v Violation) Error() string {
+	return v.Pointer() + ": " + v.Message
+}
+
+// Validate validates v, a value as decoded by encoding/json into an any, and
+// returns an error describing the first violation.
+func (s *Schema) Validate(v any) error {
+	if violations := s.Violations(v); len(violations) > 0 {
+		return violations[0]
+	}
+	return nil
+}
+
+// Violations validates v like Validate and returns every violation: those of
+// the value itself before those of its properties, in name order, and of its
+// items, in order. A value of the wrong type is not checked further.
+func (s *Schema) Violations(v any) []Violation {
+	var out []Violation
+	s.validate(v, nil, &out)
+	return out
+}
+
+// matches reports whether v is valid against s.
+func (s *Schema) matches(v any) bool {
+	return len(s.Violations(v)) == 0
+}
+
+func (s *Schema) validate(v any, path []any, out *[]Violation) {
+	add := func(keyword, format string, args ...any) {
+		*out = append(*out, Violation{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.never {
+		add("false", "no value is allowed")
+		return
+	}
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(v, t) }) {
+		add("type", "got %s, want %s", typeOf(v), strings.Join(s.types, " or "))
+		return
+	}
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(e any) bool { return equal(e, v) }) {
+		add("enum", "value is not one of the enumerated values")
+	}
+	if s.consts != nil && !equal(s.consts[0], v) {
+		add("const", "value does not equal the constant")
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		s.validateObject(v, path, add, out)
+	case []any:
+		s.validateArray(v, path, add, out)
+	case string:
+		s.validateString(v, add)
+	default:
+		if n, ok := toFloat(v); ok {
+			s.validateNumber(n, add)
+		}
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, path, out)
+	}
+	if s.anyOf != nil && !slices.ContainsFunc(s.anyOf, func(sub *Schema) bool { return sub.matches(v) }) {
+		add("anyOf", "value matches none of anyOf")
+	}
+	if s.oneOf != nil {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if sub.matches(v) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			add("oneOf", "value matches %d of oneOf, want 1", matched)
+		}
+	}
+	if s.not != nil && s.not.matches(v) {
+		add("not", "value matches not")
+	}
+}
+
+// violationFunc records a violation of keyword by the value being validated.
+type violationFunc func(keyword, format string, args ...any)
+
+func (s *Schema) validateObject(v map[string]any, path []any, add violationFunc, out *[]Violation) {
+	for _, name := range s.required {
+		if _, ok := v[name]; !ok {
+			*out = append(*out, Violation{Path: append(slices.Clip(path), name), Keyword: "required", Message: "missing required property " + name})
+		}
+	}
+	if s.minProperties != nil && len(v) < *s.minProperties {
+		add("minProperties", "has %d properties, want at least %d", len(v), *s.minProperties)
+	}
+	if s.maxProperties != nil && len(v) > *s.maxProperties {
+		add("maxProperties", "has %d properties, want at most %d", len(v), *s.maxProperties)
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub, ok := s.properties[name]
+		switch {
+		case ok:
+		case s.noAdditional:
+			*out = append(*out, Violation{Path: append(slices.Clip(path), name), Keyword: "additionalProperties", Message: "property " + name + " is not allowed"})
+			continue
+		case s.additionalProperties != nil:
+			sub = s.additionalProperties
+		default:
+			continue
+		}
+		sub.validate(v[name], append(slices.Clip(path), name), out)
+	}
+}
+
+func (s *Schema) validateArray(v []any, path []any, add violationFunc, out *[]Violation) {
+	if s.minItems != nil && len(v) < *s.minItems {
+		add("minItems", "has %d items, want at least %d", len(v), *s.minItems)
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		add("maxItems", "has %d items, want at most %d", len(v), *s.maxItems)
+	}
+	if s.items != nil {
+		for i, item := range v {
+			s.items.validate(item, append(slices.Clip(path), i), out)
+		}
+	}
+}
+
+func (s *Schema) validateString(v string, add violationFunc) {
+	length := len([]rune(v))
+	if s.minLength != nil && length < *s.minLength {
+		add("minLength", "has length %d, want at least %d", length, *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		add("maxLength", "has length %d, want at most %d", length, *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		add("pattern", "does not match pattern %s", s.pattern)
+	}
+}
+
+func (s *Schema) validateNumber(v float64, add violationFunc) {
+	if s.minimum != nil && v < *s.minimum {
+		add("minimum", "%v is less than %v", v, *s.minimum)
+	}
+	if s.maximum != nil && v > *s.maximum {
+		add("maximum", "%v is greater than %v", v, *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+		add("exclusiveMinimum", "%v is not greater than %v", v, *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+		add("exclusiveMaximum", "%v is not less than %v", v, *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil && math.Abs(math.Remainder(v, *s.multipleOf)) > 1e-9 {
+		add("multipleOf", "%v is not a multiple of %v", v, *s.multipleOf)
+	}
+}
+
+func hasType(v any, t string) bool {
+	if t == "integer" {
+		n, ok := toFloat(v)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	}
+	return typeOf(v) == t
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	}
+	if _, ok := toFloat(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// equal reports whether a and b are the same JSON value; numbers compare by
+// value.
+func equal(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
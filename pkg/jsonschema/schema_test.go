@@ -0,0 +1,150 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func mustDecode(t *testing.T, doc string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(doc), &m); err != nil {
+		t.Fatalf("decode %s: %v", doc, err)
+	}
+	return m
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Compile(mustDecode(t, `{
+		"title": "report",
+		"type": "object",
+		"required": ["rows", "status"],
+		"additionalProperties": false,
+		"properties": {
+			"rows": {"type": "integer", "minimum": 0},
+			"status": {"enum": ["ok", "partial"]},
+			"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "maxItems": 2},
+			"ratio": {"type": ["number", "null"], "exclusiveMaximum": 1},
+			"owner": {"anyOf": [{"type": "string", "minLength": 1}, {"type": "object", "required": ["id"]}]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "valid", value: `{"rows": 3, "status": "ok", "tags": ["a"], "ratio": null, "owner": {"id": 1}}`},
+		{name: "not an object", value: `[]`, want: "/: got array, want object"},
+		{name: "missing property", value: `{"rows": 3}`, want: "missing required property status"},
+		{name: "extra property", value: `{"rows": 3, "status": "ok", "extra": 1}`, want: "property extra is not allowed"},
+		{name: "fraction", value: `{"rows": 1.5, "status": "ok"}`, want: "/rows: got number, want integer"},
+		{name: "below minimum", value: `{"rows": -1, "status": "ok"}`, want: "/rows: -1 is less than 0"},
+		{name: "not enumerated", value: `{"rows": 1, "status": "failed"}`, want: "/status: value is not one of"},
+		{name: "item pattern", value: `{"rows": 1, "status": "ok", "tags": ["A"]}`, want: "/tags/0: does not match pattern"},
+		{name: "too many items", value: `{"rows": 1, "status": "ok", "tags": ["a", "b", "c"]}`, want: "/tags: has 3 items"},
+		{name: "exclusive maximum", value: `{"rows": 1, "status": "ok", "ratio": 1}`, want: "/ratio: 1 is not less than 1"},
+		{name: "any of", value: `{"rows": 1, "status": "ok", "owner": ""}`, want: "/owner: value matches none of anyOf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(tt.value), &v); err != nil {
+				t.Fatalf("decode value: %v", err)
+			}
+			err := schema.Validate(v)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_RejectsInvalidSchemas(t *testing.T) {
+	for doc, want := range map[string]string{
+		`{"$ref": "#/definitions/row"}`:                    "unsupported keyword $ref",
+		`{"type": "record"}`:                               `type: unknown type "record"`,
+		`{"properties": {"id": {"minLength": -1}}}`:        "at /properties/id: minLength",
+		`{"pattern": "("}`:                                 "pattern",
+		`{"oneOf": []}`:                                    "oneOf must be a non-empty array",
+		`{"items": {"anyOf": [{"type": "string"}, 3]}}`:    "at /items/anyOf/1: must be an object or a boolean",
+		`{"multipleOf": 0}`:                                "multipleOf: must be greater than 0",
+		`{"required": "id"}`:                               "required: must be an array of strings",
+		`{"not": {"const": 1}, "enum": "a"}`:               "enum must be a non-empty array",
+		`{"additionalProperties": {"type": ["x", "int"]}}`: "at /additionalProperties: type",
+	} {
+		if _, err := Compile(mustDecode(t, doc)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile(%s) error = %v, want %q", doc, err, want)
+		}
+	}
+}
+
+func TestSchema_OneOfNotAndBooleanSchemas(t *testing.T) {
+	schema, err := Compile(mustDecode(t, `{
+		"oneOf": [{"type": "integer"}, {"type": "number", "multipleOf": 0.5}],
+		"not": {"const": 0},
+		"properties": {"never": false}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	for value, want := range map[any]string{
+		float64(3):   "matches 2 of oneOf",
+		float64(1.5): "",
+		float64(0.3): "matches 0 of oneOf",
+		"text":       "matches 0 of oneOf",
+	} {
+		err := schema.Validate(value)
+		if (want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), want)) {
+			t.Errorf("Validate(%v) error = %v, want %q", value, err, want)
+		}
+	}
+	if err := schema.Validate(map[string]any{"never": nil}); err == nil || !strings.Contains(err.Error(), "/never: no value is allowed") {
+		t.Errorf("Validate() with a false property error = %v", err)
+	}
+}
+
+func TestSchema_ViolationsReportsEveryViolation(t *testing.T) {
+	schema, err := Compile(mustDecode(t, `{
+		"type": "object",
+		"required": ["id", "name"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "string"},
+			"tags": {"type": "array", "maxItems": 1, "items": {"type": "string"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	violations := schema.Violations(map[string]any{"extra": true, "tags": []any{"a", float64(2)}})
+	want := []string{
+		"/id: missing required property id",
+		"/name: missing required property name",
+		"/extra: property extra is not allowed",
+		"/tags: has 2 items, want at most 1",
+		"/tags/1: got number, want string",
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("Violations() = %v, want %d violations", violations, len(want))
+	}
+	for i, v := range violations {
+		if v.Error() != want[i] {
+			t.Errorf("violation %d = %q, want %q", i, v.Error(), want[i])
+		}
+	}
+	if got := violations[4]; got.Keyword != "type" || len(got.Path) != 2 || got.Path[0] != "tags" || got.Path[1] != 1 {
+		t.Errorf("unexpected item violation %+v", got)
+	}
+}
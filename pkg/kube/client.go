@@ -0,0 +1,152 @@
+// Package kube is a minimal client of the Kubernetes API, sending JSON
+// requests with a service account token.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Options configures a Client.
+type Options struct {
+	// APIServer is the API server URL. When empty, the in-cluster address
+	// from KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT is used.
+	APIServer string
+
+	// TokenFile holds the bearer token. It is re-read on every request, as
+	// projected service account tokens are rotated.
+	TokenFile string
+
+	// CAFile is the CA bundle verifying the API server. A missing file
+	// falls back to the system roots.
+	CAFile string
+
+	// HTTPClient sends the requests. Defaults to a client trusting CAFile.
+	HTTPClient *http.Client
+}
+
+// Client sends requests to the Kubernetes API.
+type Client struct {
+	opts Options
+}
+
+// NewClient returns a client of the API server of opts.
+func NewClient(opts Options) (*Client, error) {
+	if opts.APIServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, errors.New("kubernetes API server not configured and not running in a cluster")
+		}
+		opts.APIServer = "https://" + net.JoinHostPort(host, port)
+	}
+	opts.APIServer = strings.TrimRight(opts.APIServer, "/")
+	if opts.HTTPClient == nil {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile)
+			switch {
+			case err == nil:
+				pool := x509.NewCertPool()
+				if !pool.AppendCertsFromPEM(pem) {
+					return nil, fmt.Errorf("no certificates in %s", opts.CAFile)
+				}
+				tlsConfig.RootCAs = pool
+			case !os.IsNotExist(err):
+				return nil, fmt.Errorf("read kubernetes CA: %w", err)
+			}
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		opts.HTTPClient = &http.Client{Transport: transport}
+	}
+	return &Client{opts: opts}, nil
+}
+
+// StatusError is an error response of the API server.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("kubernetes API status %d: %s", e.Code, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 response, such as an update
+// with a stale resourceVersion.
+func IsConflict(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusConflict
+}
+
+// Call sends in as JSON, if set, and decodes the JSON response into out,
+// if set.
+func (c *Client) Call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	resp, err := c.Do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Do sends a request with a JSON body, if set, and returns the response of
+// a successful status. Other statuses are returned as *StatusError.
+func (c *Client) Do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.opts.APIServer+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.opts.TokenFile != "" {
+		if token, err := os.ReadFile(c.opts.TokenFile); err == nil {
+			req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+		}
+	}
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var status struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &status) != nil || status.Message == "" {
+			status.Message = strings.TrimSpace(string(data))
+		}
+		return nil, &StatusError{Code: resp.StatusCode, Message: status.Message}
+	}
+	return resp, nil
+}
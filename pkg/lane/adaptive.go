@@ -0,0 +1,162 @@
+package lane
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAdaptiveInterval is how often adaptive concurrency is adjusted.
+	DefaultAdaptiveInterval = time.Second
+
+	// DefaultAdaptiveDecreaseFactor is the multiplicative decrease applied
+	// when the latency target or error budget is exceeded.
+	DefaultAdaptiveDecreaseFactor = 0.5
+)
+
+// AdaptiveConfig enables AIMD (additive increase, multiplicative decrease)
+// tuning of a lane's concurrency. Every Interval the lane looks at the tasks
+// that finished since the last adjustment: if their average latency exceeds
+// TargetLatency or their error rate exceeds MaxErrorRate the concurrency
+// limit is multiplied by DecreaseFactor, otherwise it grows by IncreaseStep
+// while tasks are queued. The limit stays within [MinConcurrency,
+// Config.MaxConcurrency].
+type AdaptiveConfig struct {
+	// MinConcurrency is the lowest concurrency the lane backs off to.
+	MinConcurrency int
+
+	// TargetLatency is the average task latency above which concurrency is
+	// decreased. Zero disables the latency signal.
+	TargetLatency time.Duration
+
+	// MaxErrorRate is the failed task ratio (0-1) above which concurrency is
+	// decreased. Zero disables the error signal.
+	MaxErrorRate float64
+
+	// Interval is how often concurrency is adjusted. Default is 1s.
+	Interval time.Duration
+
+	// IncreaseStep is the number of workers added per healthy interval.
+	// Default is 1.
+	IncreaseStep int
+
+	// DecreaseFactor multiplies the limit when a signal is exceeded.
+	// Must be in (0, 1). Default is 0.5.
+	DecreaseFactor float64
+}
+
+// Validate validates the adaptive configuration against the lane's maximum
+// concurrency.
+func (c *AdaptiveConfig) Validate(maxConcurrency int) error {
+	if c.MinConcurrency <= 0 {
+		return fmt.Errorf("adaptive min concurrency must be positive, got %d", c.MinConcurrency)
+	}
+	if c.MinConcurrency > maxConcurrency {
+		return fmt.Errorf("adaptive min concurrency (%d) cannot exceed max concurrency (%d)", c.MinConcurrency, maxConcurrency)
+	}
+	if c.TargetLatency < 0 {
+		return fmt.Errorf("adaptive target latency cannot be negative")
+	}
+	if c.MaxErrorRate < 0 || c.MaxErrorRate > 1 {
+		return fmt.Errorf("adaptive max error rate must be between 0 and 1, got %v", c.MaxErrorRate)
+	}
+	if c.TargetLatency == 0 && c.MaxErrorRate == 0 {
+		return fmt.Errorf("adaptive concurrency needs a target latency or a max error rate")
+	}
+	if c.Interval < 0 || c.IncreaseStep < 0 {
+		return fmt.Errorf("adaptive interval and increase step cannot be negative")
+	}
+	if c.DecreaseFactor < 0 || c.DecreaseFactor >= 1 {
+		return fmt.Errorf("adaptive decrease factor must be in (0, 1), got %v", c.DecreaseFactor)
+	}
+	return nil
+}
+
+// adaptiveLimiter implements the AIMD decision. It is driven by the owning
+// lane, which feeds it task outcomes and applies the returned limits.
+type adaptiveLimiter struct {
+	cfg AdaptiveConfig
+
+	mu      sync.Mutex
+	limit   int
+	ceiling int
+	count   int64
+	errors  int64
+	latency time.Duration
+}
+
+func newAdaptiveLimiter(cfg AdaptiveConfig, ceiling int) *adaptiveLimiter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultAdaptiveInterval
+	}
+	if cfg.IncreaseStep <= 0 {
+		cfg.IncreaseStep = 1
+	}
+	if cfg.DecreaseFactor <= 0 {
+		cfg.DecreaseFactor = DefaultAdaptiveDecreaseFactor
+	}
+	return &adaptiveLimiter{cfg: cfg, limit: ceiling, ceiling: ceiling}
+}
+
+// observe records the outcome of one finished task.
+func (a *adaptiveLimiter) observe(latency time.Duration, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.count++
+	a.latency += latency
+	if failed {
+		a.errors++
+	}
+}
+
+// adjust ends the current window and returns the new concurrency limit.
+// queued reports whether tasks are waiting for a worker.
+func (a *adaptiveLimiter) adjust(queued bool) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	count, errors, latency := a.count, a.errors, a.latency
+	a.count, a.errors, a.latency = 0, 0, 0
+
+	if count == 0 {
+		return a.limit
+	}
+
+	overloaded := (a.cfg.TargetLatency > 0 && latency/time.Duration(count) > a.cfg.TargetLatency) ||
+		(a.cfg.MaxErrorRate > 0 && float64(errors)/float64(count) > a.cfg.MaxErrorRate)
+	switch {
+	case overloaded:
+		a.limit = int(math.Floor(float64(a.limit) * a.cfg.DecreaseFactor))
+	case queued:
+		a.limit += a.cfg.IncreaseStep
+	}
+	a.limit = a.clamp(a.limit)
+	return a.limit
+}
+
+// setCeiling changes the upper bound and returns the clamped limit.
+func (a *adaptiveLimiter) setCeiling(ceiling int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.ceiling = ceiling
+	a.limit = a.clamp(a.limit)
+	return a.limit
+}
+
+func (a *adaptiveLimiter) clamp(limit int) int {
+	lower := a.cfg.MinConcurrency
+	if lower > a.ceiling {
+		lower = a.ceiling
+	}
+	if limit < lower {
+		return lower
+	}
+	if limit > a.ceiling {
+		return a.ceiling
+	}
+	return limit
+}
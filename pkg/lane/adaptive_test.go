@@ -0,0 +1,176 @@
+package lane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	limiter := newAdaptiveLimiter(AdaptiveConfig{
+		MinConcurrency: 2,
+		TargetLatency:  100 * time.Millisecond,
+		MaxErrorRate:   0.2,
+	}, 8)
+
+	if got := limiter.adjust(true); got != 8 {
+		t.Fatalf("expected limit to stay at 8 without samples, got %d", got)
+	}
+
+	limiter.observe(300*time.Millisecond, false)
+	if got := limiter.adjust(true); got != 4 {
+		t.Fatalf("expected slow tasks to halve the limit, got %d", got)
+	}
+
+	limiter.observe(10*time.Millisecond, false)
+	limiter.observe(10*time.Millisecond, true)
+	if got := limiter.adjust(true); got != 2 {
+		t.Fatalf("expected failures to halve the limit, got %d", got)
+	}
+
+	limiter.observe(10*time.Millisecond, true)
+	if got := limiter.adjust(true); got != 2 {
+		t.Fatalf("expected limit to stop at the minimum, got %d", got)
+	}
+
+	limiter.observe(10*time.Millisecond, false)
+	if got := limiter.adjust(false); got != 2 {
+		t.Fatalf("expected no increase without queued tasks, got %d", got)
+	}
+
+	limiter.observe(10*time.Millisecond, false)
+	if got := limiter.adjust(true); got != 3 {
+		t.Fatalf("expected healthy window to add one worker, got %d", got)
+	}
+
+	if got := limiter.setCeiling(1); got != 1 {
+		t.Fatalf("expected ceiling below minimum to win, got %d", got)
+	}
+}
+
+func TestConfigValidate_Adaptive(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(c *Config) {}},
+		{
+			name:    "dynamic workers",
+			mutate:  func(c *Config) { c.EnableDynamicWorkers, c.MinConcurrency = true, 1 },
+			wantErr: "dynamic workers",
+		},
+		{
+			name:    "min above max",
+			mutate:  func(c *Config) { c.Adaptive.MinConcurrency = 10 },
+			wantErr: "cannot exceed",
+		},
+		{
+			name:    "no signal",
+			mutate:  func(c *Config) { c.Adaptive.TargetLatency = 0 },
+			wantErr: "target latency or a max error rate",
+		},
+		{
+			name:    "error rate out of range",
+			mutate:  func(c *Config) { c.Adaptive.MaxErrorRate = 1.5 },
+			wantErr: "between 0 and 1",
+		},
+		{
+			name:    "decrease factor",
+			mutate:  func(c *Config) { c.Adaptive.DecreaseFactor = 1 },
+			wantErr: "decrease factor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Name:           "adaptive",
+				Capacity:       10,
+				MaxConcurrency: 4,
+				Adaptive:       &AdaptiveConfig{MinConcurrency: 1, TargetLatency: time.Second},
+			}
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChannelLane_AdaptiveConcurrency(t *testing.T) {
+	lane, err := New(&Config{
+		Name:           "adaptive",
+		Capacity:       100,
+		MaxConcurrency: 4,
+		Backpressure:   Block,
+		Adaptive: &AdaptiveConfig{
+			MinConcurrency: 1,
+			MaxErrorRate:   0.5,
+			Interval:       20 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create lane: %v", err)
+	}
+	defer lane.Close(context.Background())
+	lane.Run()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			task := NewTaskFunc(fmt.Sprintf("task-%d", i), "adaptive", 1, func(ctx context.Context) error {
+				time.Sleep(time.Millisecond)
+				if failing.Load() {
+					return errors.New("upstream unavailable")
+				}
+				return nil
+			})
+			if err := lane.Submit(context.Background(), task); err != nil {
+				return
+			}
+		}
+	}()
+	waitForConcurrency(t, lane, func(n int) bool { return n == 1 })
+
+	failing.Store(false)
+	waitForConcurrency(t, lane, func(n int) bool { return n >= 3 })
+
+	if err := lane.SetMaxConcurrency(2); err != nil {
+		t.Fatalf("SetMaxConcurrency() error = %v", err)
+	}
+	if got := lane.Stats().MaxConcurrency; got > 2 {
+		t.Fatalf("expected limit clamped to new ceiling, got %d", got)
+	}
+}
+
+func waitForConcurrency(t *testing.T, lane *ChannelLane, ok func(int) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if ok(lane.Stats().MaxConcurrency) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("max concurrency did not converge, got %d", lane.Stats().MaxConcurrency)
+}
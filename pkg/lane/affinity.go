@@ -0,0 +1,91 @@
+package lane
+
+import "sync"
+
+// AffinityTask is implemented by tasks that must not run concurrently with
+// other tasks sharing their affinity key, e.g. all work of one customer.
+// A ChannelLane runs tasks with the same key one at a time, in submission
+// order; a RedisLane does so across all of its consumers, in the order they
+// are dequeued. Affinity keys of task group members are ignored.
+type AffinityTask interface {
+	Task
+
+	// AffinityKey returns the key, or "" when the task has no affinity.
+	AffinityKey() string
+}
+
+// taskAffinityKey returns the affinity key of task, if it has one.
+func taskAffinityKey(task Task) string {
+	if at, ok := task.(AffinityTask); ok {
+		return at.AffinityKey()
+	}
+	return ""
+}
+
+// affinityTracker serialises tasks sharing an affinity key. A key is active
+// from the moment its first task is admitted until no task with the key is
+// running or waiting.
+type affinityTracker struct {
+	mu sync.Mutex
+	// waiting holds, per active key, the tasks parked behind the running one.
+	waiting map[string][]Task
+	// ready holds parked tasks whose predecessor finished.
+	ready []Task
+	// signal is notified whenever ready gains a task.
+	signal chan struct{}
+}
+
+func newAffinityTracker() *affinityTracker {
+	return &affinityTracker{
+		waiting: make(map[string][]Task),
+		signal:  make(chan struct{}, 1),
+	}
+}
+
+// admit returns true if task can be dispatched now. Otherwise it is parked
+// until the tasks before it with the same key have finished.
+func (a *affinityTracker) admit(task Task) bool {
+	key := taskAffinityKey(task)
+	if key == "" {
+		return true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if waiting, ok := a.waiting[key]; ok {
+		a.waiting[key] = append(waiting, task)
+		return false
+	}
+	a.waiting[key] = nil
+	return true
+}
+
+// done marks the running task with key as finished and readies the next
+// parked task with the same key.
+func (a *affinityTracker) done(key string) {
+	if key == "" {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	waiting := a.waiting[key]
+	if len(waiting) == 0 {
+		delete(a.waiting, key)
+		return
+	}
+	a.ready = append(a.ready, waiting[0])
+	waiting[0] = nil
+	a.waiting[key] = waiting[1:]
+	select {
+	case a.signal <- struct{}{}:
+	default:
+	}
+}
+
+// takeReady removes and returns the tasks readied by done.
+func (a *affinityTracker) takeReady() []Task {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ready := a.ready
+	a.ready = nil
+	return ready
+}
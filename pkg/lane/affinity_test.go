@@ -0,0 +1,126 @@
+package lane
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChannelLane_AffinitySerializesKey(t *testing.T) {
+	for _, dispatch := range []DispatchOrder{DispatchFIFO, DispatchEDF} {
+		t.Run(dispatch.String(), func(t *testing.T) {
+			l, err := New(&Config{Name: "sticky", Capacity: 20, MaxConcurrency: 4, Dispatch: dispatch})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer l.Close(context.Background())
+			l.Run()
+
+			var (
+				mu      sync.Mutex
+				order   = make(map[string][]int)
+				running = make(map[string]int)
+				overlap atomic.Bool
+				wg      sync.WaitGroup
+			)
+			for i := 0; i < 6; i++ {
+				key := fmt.Sprintf("customer-%d", i%2)
+				seq := i
+				wg.Add(1)
+				task := NewTaskFunc(fmt.Sprintf("t%d", i), "sticky", 0, func(context.Context) error {
+					defer wg.Done()
+					mu.Lock()
+					running[key]++
+					if running[key] > 1 {
+						overlap.Store(true)
+					}
+					order[key] = append(order[key], seq)
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					running[key]--
+					mu.Unlock()
+					return nil
+				}).WithAffinity(key)
+				if err := l.Submit(context.Background(), task); err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+			}
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for tasks")
+			}
+
+			if overlap.Load() {
+				t.Fatal("expected tasks sharing an affinity key to run one at a time")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for key, seqs := range order {
+				for i := 1; i < len(seqs); i++ {
+					if seqs[i] < seqs[i-1] {
+						t.Fatalf("%s ran out of submission order: %v", key, seqs)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChannelLane_AffinityDoesNotBlockOtherKeys(t *testing.T) {
+	l, err := New(&Config{Name: "sticky", Capacity: 10, MaxConcurrency: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+	l.Run()
+
+	release := make(chan struct{})
+	started := make(chan string, 3)
+	task := func(id, key string) *TaskFunc {
+		return NewTaskFunc(id, "sticky", 0, func(context.Context) error {
+			started <- id
+			<-release
+			return nil
+		}).WithAffinity(key)
+	}
+	for _, tk := range []*TaskFunc{task("a1", "a"), task("a2", "a"), task("b1", "b")} {
+		if err := l.Submit(context.Background(), tk); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	got := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-started:
+			got[id] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for tasks to start")
+		}
+	}
+	if !got["a1"] || !got["b1"] {
+		t.Fatalf("expected a1 and b1 to run while a2 waits, got %v", got)
+	}
+	close(release)
+	select {
+	case id := <-started:
+		if id != "a2" {
+			t.Fatalf("expected a2 to start last, got %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a2")
+	}
+}
@@ -20,15 +20,29 @@ type submissionOutcomeRecorder interface {
 	RecordSubmissionOutcome(laneName string, outcome string)
 }
 
+type deadlineMissRecorder interface {
+	RecordDeadlineMissed(laneName string)
+}
+
+type resourceUsageRecorder interface {
+	RecordResourceUtilization(laneName string, resource string, utilization float64)
+}
+
 type taskExecutor interface {
 	Start()
 	Stop()
 	Submit(task Task)
 }
 
+type resizableExecutor interface {
+	Resize(workers int)
+}
+
 type queuedTask struct {
 	task       Task
 	enqueuedAt time.Time
+	// grouped marks members of a task group, which ignore affinity.
+	grouped bool
 }
 
 func newQueuedTask(task Task) queuedTask {
@@ -50,6 +64,21 @@ func (q queuedTask) Lane() string {
 	return q.task.Lane()
 }
 
+func (q queuedTask) Deadline() (time.Time, bool) {
+	return taskDeadline(q.task)
+}
+
+func (q queuedTask) Resources() Resources {
+	return taskResources(q.task)
+}
+
+func (q queuedTask) AffinityKey() string {
+	if q.grouped {
+		return ""
+	}
+	return taskAffinityKey(q.task)
+}
+
 func (q queuedTask) EnqueuedAt() time.Time {
 	return q.enqueuedAt
 }
@@ -63,28 +92,49 @@ type ChannelLane struct {
 	config      *Config
 	taskCh      chan Task
 	workerPool  taskExecutor
-	rateLimiter *TokenBucket
+	rateLimiter atomic.Pointer[TokenBucket]
 	metrics     MetricsRecorder
 
+	// maxConcurrency tracks the live worker limit, which may change at runtime.
+	maxConcurrency atomic.Int32
+
+	// busy counts tasks handed to workers that have not finished yet; freed
+	// is signalled whenever one finishes so that task groups and tasks waiting
+	// for resources can start.
+	busy  atomic.Int32
+	freed chan struct{}
+
+	// resources tracks the lane's resource capacity; nil when not configured.
+	resources *resourcePool
+
+	// affinity parks tasks until no task with their affinity key is running.
+	affinity *affinityTracker
+
+	// adaptive tunes maxConcurrency from task outcomes when configured.
+	adaptive *adaptiveLimiter
+
 	// State
 	closed    atomic.Bool
 	closeCh   chan struct{}
 	closeOnce sync.Once
 
 	// Statistics
-	pending   atomic.Int32
-	running   atomic.Int32
-	completed atomic.Int64
-	failed    atomic.Int64
-	dropped   atomic.Int64
+	pending    atomic.Int32
+	running    atomic.Int32
+	completed  atomic.Int64
+	failed     atomic.Int64
+	dropped    atomic.Int64
 	accepted   atomic.Int64
 	rejected   atomic.Int64
 	redirected atomic.Int64
+	throttled  atomic.Int64
+	missed     atomic.Int64
 
 	// For redirect strategy
 	manager *Manager
 
 	// Wait time tracking
+	waits            *waitTracker
 	totalProcessTime atomic.Int64 // nanoseconds
 	taskCount        atomic.Int64
 }
@@ -96,15 +146,25 @@ func New(config *Config) (*ChannelLane, error) {
 	}
 
 	l := &ChannelLane{
-		config:  config,
-		taskCh:  make(chan Task, config.Capacity),
-		closeCh: make(chan struct{}),
-		metrics: &nopMetrics{},
+		config:   config,
+		taskCh:   make(chan Task, config.Capacity),
+		closeCh:  make(chan struct{}),
+		freed:    make(chan struct{}, 1),
+		affinity: newAffinityTracker(),
+		metrics:  &nopMetrics{},
+		waits:    newWaitTracker(),
 	}
 
 	// Initialize rate limiter if configured
 	if config.RateLimit > 0 {
-		l.rateLimiter = NewTokenBucket(config.RateLimit, config.RateLimit*2)
+		l.rateLimiter.Store(NewTokenBucket(config.RateLimit, config.RateLimit*2))
+	}
+	l.maxConcurrency.Store(int32(config.MaxConcurrency))
+	if config.Resources != nil {
+		l.resources = &resourcePool{capacity: *config.Resources}
+	}
+	if config.Adaptive != nil {
+		l.adaptive = newAdaptiveLimiter(*config.Adaptive, config.MaxConcurrency)
 	}
 
 	// Fixed-size workers are the default. Dynamic scaling is optional.
@@ -138,10 +198,19 @@ func (l *ChannelLane) Submit(ctx context.Context, task Task) error {
 		l.recordRejected()
 		return fmt.Errorf("task cannot be nil")
 	}
+	if err := l.checkGroup(task); err != nil {
+		l.recordRejected()
+		return err
+	}
+	if err := l.checkResources(task); err != nil {
+		l.recordRejected()
+		return err
+	}
 
 	// Token bucket is the normative Week3 admission baseline for ChannelLane.
-	if l.rateLimiter != nil {
-		if err := l.rateLimiter.Wait(ctx); err != nil {
+	if limiter := l.rateLimiter.Load(); limiter != nil && !limiter.Allow() {
+		l.throttled.Add(1)
+		if err := limiter.Wait(ctx); err != nil {
 			l.recordRejected()
 			return err
 		}
@@ -164,9 +233,7 @@ func (l *ChannelLane) submitBlock(ctx context.Context, task Task) error {
 	qt := newQueuedTask(task)
 	select {
 	case l.taskCh <- qt:
-		l.pending.Add(1)
-		l.recordAccepted()
-		l.metrics.IncQueueDepth(l.config.Name)
+		l.recordQueued(task)
 		return nil
 	case <-ctx.Done():
 		l.recordRejected()
@@ -182,9 +249,7 @@ func (l *ChannelLane) submitDrop(task Task) error {
 	qt := newQueuedTask(task)
 	select {
 	case l.taskCh <- qt:
-		l.pending.Add(1)
-		l.recordAccepted()
-		l.metrics.IncQueueDepth(l.config.Name)
+		l.recordQueued(task)
 		return nil
 	default:
 		l.dropped.Add(1)
@@ -197,9 +262,7 @@ func (l *ChannelLane) submitDrop(task Task) error {
 func (l *ChannelLane) submitRedirect(ctx context.Context, task Task) error {
 	select {
 	case l.taskCh <- task:
-		l.pending.Add(1)
-		l.recordAccepted()
-		l.metrics.IncQueueDepth(l.config.Name)
+		l.recordQueued(task)
 		return nil
 	default:
 		// Try to redirect
@@ -235,9 +298,14 @@ func (l *ChannelLane) TrySubmit(task Task) bool {
 		l.recordRejected()
 		return false
 	}
+	if l.checkGroup(task) != nil || l.checkResources(task) != nil {
+		l.recordRejected()
+		return false
+	}
 
 	// Token bucket is the normative Week3 admission baseline for ChannelLane.
-	if l.rateLimiter != nil && !l.rateLimiter.Allow() {
+	if limiter := l.rateLimiter.Load(); limiter != nil && !limiter.Allow() {
+		l.throttled.Add(1)
 		l.recordRejected()
 		return false
 	}
@@ -245,9 +313,7 @@ func (l *ChannelLane) TrySubmit(task Task) bool {
 	qt := newQueuedTask(task)
 	select {
 	case l.taskCh <- qt:
-		l.pending.Add(1)
-		l.recordAccepted()
-		l.metrics.IncQueueDepth(l.config.Name)
+		l.recordQueued(task)
 		return true
 	default:
 		l.recordRejected()
@@ -263,15 +329,19 @@ func (l *ChannelLane) executeTask(task Task) {
 	// Record wait duration
 	if tw, ok := task.(interface{ EnqueuedAt() time.Time }); ok {
 		waitDuration := time.Since(tw.EnqueuedAt())
+		l.waits.observe(waitDuration)
 		l.metrics.RecordWaitDuration(l.config.Name, waitDuration)
 	}
 
+	affinityKey := taskAffinityKey(task)
 	if wrapped, ok := task.(interface{ UnwrapTask() Task }); ok {
 		task = wrapped.UnwrapTask()
 	}
 
+	defer l.affinity.done(affinityKey)
 	l.running.Add(1)
 	defer l.running.Add(-1)
+	defer l.release(task)
 
 	startTime := time.Now()
 
@@ -290,11 +360,31 @@ func (l *ChannelLane) executeTask(task Task) {
 	} else {
 		l.completed.Add(1)
 	}
+	if l.adaptive != nil {
+		l.adaptive.observe(processTime, err != nil)
+	}
+	if deadline, ok := taskDeadline(task); ok && time.Now().After(deadline) {
+		l.missed.Add(1)
+		if recorder, ok := l.metrics.(deadlineMissRecorder); ok {
+			recorder.RecordDeadlineMissed(l.config.Name)
+		}
+	}
 
 	// Record throughput
 	l.metrics.RecordThroughput(l.config.Name)
 }
 
+// release marks a dispatched task as finished, returns its resources and wakes
+// a task waiting for workers or resources.
+func (l *ChannelLane) release(task Task) {
+	l.releaseResources(task)
+	l.busy.Add(-1)
+	select {
+	case l.freed <- struct{}{}:
+	default:
+	}
+}
+
 // Stats returns current lane statistics.
 func (l *ChannelLane) Stats() Stats {
 	stats := Stats{
@@ -308,7 +398,17 @@ func (l *ChannelLane) Stats() Stats {
 		Rejected:       l.rejected.Load(),
 		Redirected:     l.redirected.Load(),
 		Capacity:       l.config.Capacity,
-		MaxConcurrency: l.config.MaxConcurrency,
+		MaxConcurrency: int(l.maxConcurrency.Load()),
+		Throttled:      l.throttled.Load(),
+		DeadlineMissed: l.missed.Load(),
+	}
+	if limiter := l.rateLimiter.Load(); limiter != nil {
+		stats.RateLimit = limiter.Rate()
+		stats.RateLimitTokens = limiter.Tokens()
+	}
+	if l.resources != nil {
+		stats.Resources = l.resources.capacity
+		stats.ResourcesInUse = l.resources.inUse()
 	}
 
 	// Calculate average times
@@ -316,6 +416,7 @@ func (l *ChannelLane) Stats() Stats {
 	if count > 0 {
 		stats.ProcessTime = time.Duration(l.totalProcessTime.Load() / count)
 	}
+	l.waits.snapshot().apply(&stats)
 
 	return stats
 }
@@ -352,6 +453,69 @@ func (l *ChannelLane) IsClosed() bool {
 	return l.closed.Load()
 }
 
+// SetMaxConcurrency changes the number of workers while the lane is running.
+// Lanes using dynamic worker scaling do not support runtime resizing. For
+// adaptive lanes n becomes the upper bound of the tuned limit.
+func (l *ChannelLane) SetMaxConcurrency(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("max concurrency must be positive, got %d", n)
+	}
+	pool, ok := l.workerPool.(resizableExecutor)
+	if !ok || l.config.EnableDynamicWorkers {
+		return fmt.Errorf("lane %s does not support runtime concurrency changes", l.config.Name)
+	}
+	if l.adaptive != nil {
+		n = l.adaptive.setCeiling(n)
+	}
+	pool.Resize(n)
+	l.maxConcurrency.Store(int32(n))
+	return nil
+}
+
+// runAdaptive periodically applies the adaptive concurrency limit until the
+// lane is closed.
+func (l *ChannelLane) runAdaptive() {
+	pool, ok := l.workerPool.(resizableExecutor)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(l.adaptive.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.closeCh:
+			return
+		case <-ticker.C:
+			limit := l.adaptive.adjust(l.pending.Load() > 0)
+			if int32(limit) != l.maxConcurrency.Load() {
+				pool.Resize(limit)
+				l.maxConcurrency.Store(int32(limit))
+			}
+		}
+	}
+}
+
+// SetRateLimit changes the admission rate (tasks per second, 0 = unlimited)
+// while the lane is running.
+func (l *ChannelLane) SetRateLimit(rate float64) error {
+	if rate < 0 {
+		return fmt.Errorf("rate limit cannot be negative")
+	}
+	if rate == 0 {
+		l.rateLimiter.Store(nil)
+		return nil
+	}
+	if limiter := l.rateLimiter.Load(); limiter != nil {
+		limiter.SetRate(rate)
+		limiter.SetCapacity(rate * 2)
+		return nil
+	}
+	l.rateLimiter.Store(NewTokenBucket(rate, rate*2))
+	return nil
+}
+
 // SetManager sets the manager for redirect strategy.
 func (l *ChannelLane) SetManager(m *Manager) {
 	l.manager = m
@@ -364,6 +528,17 @@ func (l *ChannelLane) SetMetrics(m MetricsRecorder) {
 	}
 }
 
+// recordQueued accounts for a task admitted to the queue. A task group counts
+// once per task.
+func (l *ChannelLane) recordQueued(task Task) {
+	n := taskCount(task)
+	l.pending.Add(int32(n))
+	l.recordAccepted()
+	for i := 0; i < n; i++ {
+		l.metrics.IncQueueDepth(l.config.Name)
+	}
+}
+
 func (l *ChannelLane) recordAccepted() {
 	l.accepted.Add(1)
 	l.recordOutcome("accepted")
@@ -391,13 +566,31 @@ func (l *ChannelLane) recordOutcome(outcome string) {
 
 // Run starts the main loop that distributes tasks to workers.
 func (l *ChannelLane) Run() {
+	if l.adaptive != nil {
+		go l.runAdaptive()
+	}
+	if pool, ok := l.workerPool.(dispatcher); ok && l.config.Dispatch == DispatchEDF {
+		go l.runEDF(pool)
+		return
+	}
 	go func() {
 		for {
 			select {
 			case <-l.closeCh:
 				return
 			case task := <-l.taskCh:
-				l.workerPool.Submit(task)
+				if !l.affinity.admit(task) {
+					continue
+				}
+				if !l.dispatchTask(task) {
+					return
+				}
+			case <-l.affinity.signal:
+				for _, task := range l.affinity.takeReady() {
+					if !l.dispatchTask(task) {
+						return
+					}
+				}
 			}
 		}
 	}()
@@ -0,0 +1,132 @@
+package lane
+
+import (
+	"container/heap"
+	"time"
+)
+
+// taskDeadline returns the deadline of task, if it has one.
+func taskDeadline(task Task) (time.Time, bool) {
+	if dt, ok := task.(DeadlineTask); ok {
+		return dt.Deadline()
+	}
+	return time.Time{}, false
+}
+
+// deadlineItem is an item in the deadline heap.
+type deadlineItem struct {
+	task        Task
+	deadline    time.Time
+	hasDeadline bool
+	seq         int64
+}
+
+// deadlineHeap implements heap.Interface ordered by earliest deadline.
+// Tasks without a deadline sort after all tasks that have one.
+type deadlineHeap []*deadlineItem
+
+func (h deadlineHeap) Len() int { return len(h) }
+
+func (h deadlineHeap) Less(i, j int) bool {
+	if h[i].hasDeadline != h[j].hasDeadline {
+		return h[i].hasDeadline
+	}
+	if h[i].hasDeadline && !h[i].deadline.Equal(h[j].deadline) {
+		return h[i].deadline.Before(h[j].deadline)
+	}
+	if pi, pj := h[i].task.Priority(), h[j].task.Priority(); pi != pj {
+		return pi > pj
+	}
+	// Deterministic tie-breaker: earlier enqueued task first.
+	return h[i].seq < h[j].seq
+}
+
+func (h deadlineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *deadlineHeap) Push(x any) {
+	*h = append(*h, x.(*deadlineItem))
+}
+
+func (h *deadlineHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	*h = old[0 : n-1]
+	return item
+}
+
+// deadlineQueue orders tasks earliest deadline first. It is owned by the
+// lane's dispatch goroutine and is not safe for concurrent use.
+type deadlineQueue struct {
+	heap deadlineHeap
+	seq  int64
+}
+
+func (q *deadlineQueue) Len() int { return q.heap.Len() }
+
+func (q *deadlineQueue) Push(task Task) {
+	deadline, ok := taskDeadline(task)
+	q.seq++
+	heap.Push(&q.heap, &deadlineItem{task: task, deadline: deadline, hasDeadline: ok, seq: q.seq})
+}
+
+// Peek returns the task with the earliest deadline without removing it.
+func (q *deadlineQueue) Peek() Task {
+	if q.heap.Len() == 0 {
+		return nil
+	}
+	return q.heap[0].task
+}
+
+// Pop removes and returns the task with the earliest deadline.
+func (q *deadlineQueue) Pop() Task {
+	if q.heap.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&q.heap).(*deadlineItem).task
+}
+
+// dispatcher is implemented by worker pools that expose their hand-off
+// channel, so that a dispatch loop can select on it.
+type dispatcher interface {
+	dispatch() chan<- Task
+}
+
+// runEDF moves tasks from the lane queue into a deadline heap and hands the
+// earliest-deadline task to the next free worker until the lane is closed.
+func (l *ChannelLane) runEDF(pool dispatcher) {
+	var queue deadlineQueue
+	for {
+		var (
+			next Task
+			out  chan<- Task
+		)
+		if queue.Len() > 0 {
+			next = queue.Peek()
+			if _, ok := unwrapGroup(next); ok || l.waitsForResources(next) {
+				if !l.dispatchTask(queue.Pop()) {
+					return
+				}
+				continue
+			}
+			out = pool.dispatch()
+		}
+
+		select {
+		case <-l.closeCh:
+			return
+		case task := <-l.taskCh:
+			if l.affinity.admit(task) {
+				queue.Push(task)
+			}
+		case <-l.affinity.signal:
+			for _, task := range l.affinity.takeReady() {
+				queue.Push(task)
+			}
+		case out <- next:
+			queue.Pop()
+			l.busy.Add(1)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package lane
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type deadlineRecorder struct {
+	nopMetrics
+	mu    sync.Mutex
+	lanes []string
+}
+
+func (r *deadlineRecorder) RecordDeadlineMissed(laneName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lanes = append(r.lanes, laneName)
+}
+
+func TestDeadlineQueue_Order(t *testing.T) {
+	now := time.Now()
+	var q deadlineQueue
+	q.Push(NewTaskFunc("none", "edf", 0, nil))
+	q.Push(NewTaskFunc("late", "edf", 0, nil).WithDeadline(now.Add(time.Minute)))
+	q.Push(NewTaskFunc("early-low", "edf", 1, nil).WithDeadline(now.Add(time.Second)))
+	q.Push(NewTaskFunc("early-high", "edf", 5, nil).WithDeadline(now.Add(time.Second)))
+	q.Push(newQueuedTask(NewTaskFunc("wrapped", "edf", 0, nil).WithDeadline(now.Add(30 * time.Second))))
+
+	want := []string{"early-high", "early-low", "wrapped", "late", "none"}
+	for _, id := range want {
+		if got := q.Pop().ID(); got != id {
+			t.Fatalf("Pop() = %s, want %s", got, id)
+		}
+	}
+	if q.Pop() != nil {
+		t.Fatal("expected empty queue")
+	}
+}
+
+func TestChannelLane_EDFDispatch(t *testing.T) {
+	l, err := New(&Config{Name: "edf", Capacity: 10, MaxConcurrency: 1, Dispatch: DispatchEDF})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+	l.Run()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocker := NewTaskFunc("blocker", "edf", 0, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	if err := l.Submit(context.Background(), blocker); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	done := make(chan struct{}, 3)
+	now := time.Now()
+	for _, task := range []*TaskFunc{
+		NewTaskFunc("none", "edf", 0, nil),
+		NewTaskFunc("late", "edf", 0, nil).WithDeadline(now.Add(time.Hour)),
+		NewTaskFunc("early", "edf", 0, nil).WithDeadline(now.Add(time.Minute)),
+	} {
+		id := task.ID()
+		task.fn = func(context.Context) error {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			done <- struct{}{}
+			return nil
+		}
+		if err := l.Submit(context.Background(), task); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	// Wait for the dispatch loop to move every task into its deadline heap.
+	for i := 0; len(l.taskCh) > 0 && i < 100; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for tasks")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"early", "late", "none"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("dispatch order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChannelLane_DeadlineMissed(t *testing.T) {
+	l, err := New(&Config{Name: "fifo", Capacity: 10, MaxConcurrency: 1})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+	recorder := &deadlineRecorder{}
+	l.SetMetrics(recorder)
+	l.Run()
+
+	done := make(chan struct{}, 2)
+	finish := func(context.Context) error {
+		done <- struct{}{}
+		return nil
+	}
+	tasks := []*TaskFunc{
+		NewTaskFunc("missed", "fifo", 0, finish).WithDeadline(time.Now().Add(-time.Second)),
+		NewTaskFunc("on-time", "fifo", 0, finish).WithDeadline(time.Now().Add(time.Hour)),
+	}
+	for _, task := range tasks {
+		if err := l.Submit(context.Background(), task); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	for range tasks {
+		<-done
+	}
+
+	recorded := func() []string {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		return append([]string(nil), recorder.lanes...)
+	}
+	deadline := time.Now().Add(time.Second)
+	for (l.Stats().Completed < 2 || len(recorded()) == 0) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := l.Stats().DeadlineMissed; got != 1 {
+		t.Fatalf("DeadlineMissed = %d, want 1", got)
+	}
+	if lanes := recorded(); len(lanes) != 1 || lanes[0] != "fifo" {
+		t.Fatalf("unexpected deadline metrics: %v", lanes)
+	}
+}
+
+func TestConfigValidate_Dispatch(t *testing.T) {
+	cfg := &Config{Name: "edf", Capacity: 1, MaxConcurrency: 1, Dispatch: DispatchOrder(7)}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected unknown dispatch order error")
+	}
+	if DispatchEDF.String() != "edf" || DispatchFIFO.String() != "fifo" {
+		t.Fatalf("unexpected dispatch order names: %s, %s", DispatchFIFO, DispatchEDF)
+	}
+}
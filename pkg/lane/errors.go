@@ -2,6 +2,8 @@ package lane
 
 import (
 	"fmt"
+
+	"github.com/goclaw/goclaw/pkg/errs"
 )
 
 // LaneFullError is returned when a lane is at capacity and cannot accept new tasks.
@@ -14,6 +16,9 @@ func (e *LaneFullError) Error() string {
 	return fmt.Sprintf("lane %s is full (capacity: %d)", e.LaneName, e.Capacity)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *LaneFullError) ErrorCode() errs.Code { return errs.RateLimited }
+
 // LaneClosedError is returned when attempting to submit to a closed lane.
 type LaneClosedError struct {
 	LaneName string
@@ -23,6 +28,9 @@ func (e *LaneClosedError) Error() string {
 	return fmt.Sprintf("lane %s is closed", e.LaneName)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *LaneClosedError) ErrorCode() errs.Code { return errs.ServiceUnavailable }
+
 // TaskDroppedError is returned when a task is dropped due to backpressure.
 type TaskDroppedError struct {
 	LaneName string
@@ -33,6 +41,9 @@ func (e *TaskDroppedError) Error() string {
 	return fmt.Sprintf("task %s dropped in lane %s due to backpressure", e.TaskID, e.LaneName)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *TaskDroppedError) ErrorCode() errs.Code { return errs.RateLimited }
+
 // TaskDuplicateError is returned when a duplicate task is submitted.
 type TaskDuplicateError struct {
 	LaneName string
@@ -43,6 +54,9 @@ func (e *TaskDuplicateError) Error() string {
 	return fmt.Sprintf("task %s is duplicate in lane %s", e.TaskID, e.LaneName)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *TaskDuplicateError) ErrorCode() errs.Code { return errs.AlreadyExists }
+
 // LaneNotFoundError is returned when a lane is not found.
 type LaneNotFoundError struct {
 	LaneName string
@@ -52,6 +66,9 @@ func (e *LaneNotFoundError) Error() string {
 	return fmt.Sprintf("lane %s not found", e.LaneName)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *LaneNotFoundError) ErrorCode() errs.Code { return errs.NotFound }
+
 // DuplicateLaneError is returned when attempting to register a lane that already exists.
 type DuplicateLaneError struct {
 	LaneName string
@@ -61,6 +78,9 @@ func (e *DuplicateLaneError) Error() string {
 	return fmt.Sprintf("lane %s already exists", e.LaneName)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *DuplicateLaneError) ErrorCode() errs.Code { return errs.AlreadyExists }
+
 // RateLimitError is returned when rate limit is exceeded.
 type RateLimitError struct {
 	LaneName string
@@ -71,6 +91,56 @@ func (e *RateLimitError) Error() string {
 	return fmt.Sprintf("rate limit exceeded in lane %s, retry after %.2f seconds", e.LaneName, e.WaitTime)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *RateLimitError) ErrorCode() errs.Code { return errs.RateLimited }
+
+// GangSizeError is returned when a task group is empty or needs more workers
+// than the lane has.
+type GangSizeError struct {
+	LaneName       string
+	GroupID        string
+	Size           int
+	MaxConcurrency int
+}
+
+func (e *GangSizeError) Error() string {
+	return fmt.Sprintf("task group %s of %d tasks cannot be dispatched in lane %s (max concurrency: %d)",
+		e.GroupID, e.Size, e.LaneName, e.MaxConcurrency)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *GangSizeError) ErrorCode() errs.Code { return errs.ValidationFailed }
+
+// GangUnsupportedError is returned when a task group is submitted to a lane
+// that cannot dispatch task groups.
+type GangUnsupportedError struct {
+	LaneName string
+}
+
+func (e *GangUnsupportedError) Error() string {
+	return fmt.Sprintf("lane %s does not support task groups", e.LaneName)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *GangUnsupportedError) ErrorCode() errs.Code { return errs.NotImplemented }
+
+// ResourceError is returned when a task requests more resources than the
+// lane provides.
+type ResourceError struct {
+	LaneName  string
+	TaskID    string
+	Requested Resources
+	Capacity  Resources
+}
+
+func (e *ResourceError) Error() string {
+	return fmt.Sprintf("task %s requests %s, exceeding the capacity of lane %s (%s)",
+		e.TaskID, e.Requested, e.LaneName, e.Capacity)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *ResourceError) ErrorCode() errs.Code { return errs.ValidationFailed }
+
 // IsLaneFullError returns true if the error is a LaneFullError.
 func IsLaneFullError(err error) bool {
 	_, ok := err.(*LaneFullError)
@@ -185,6 +185,13 @@ func (fl *FallbackLane) Run() {
 	go fl.healthCheckLoop()
 }
 
+// runFallback starts the fallback lane and the health checker but no Redis
+// consumers, for nodes that only enqueue.
+func (fl *FallbackLane) runFallback() {
+	fl.fallback.Run()
+	go fl.healthCheckLoop()
+}
+
 // IsDegraded returns true if currently using the fallback lane.
 func (fl *FallbackLane) IsDegraded() bool {
 	return fl.degraded.Load()
@@ -0,0 +1,137 @@
+package lane
+
+import "time"
+
+// TaskGroup is a gang of tasks dispatched all-or-nothing: none of its tasks
+// start until the lane has a free worker for every one of them, and then all
+// of them start together. A group takes a single queue slot, and while it
+// waits for workers no later task in the lane is dispatched.
+type TaskGroup struct {
+	id       string
+	lane     string
+	priority int
+	tasks    []Task
+}
+
+// NewTaskGroup creates a TaskGroup of tasks for the given lane.
+func NewTaskGroup(id, lane string, priority int, tasks ...Task) *TaskGroup {
+	return &TaskGroup{
+		id:       id,
+		lane:     lane,
+		priority: priority,
+		tasks:    tasks,
+	}
+}
+
+// ID implements Task.ID.
+func (g *TaskGroup) ID() string {
+	return g.id
+}
+
+// Priority implements Task.Priority.
+func (g *TaskGroup) Priority() int {
+	return g.priority
+}
+
+// Lane implements Task.Lane.
+func (g *TaskGroup) Lane() string {
+	return g.lane
+}
+
+// Tasks returns the tasks of the group.
+func (g *TaskGroup) Tasks() []Task {
+	return g.tasks
+}
+
+// Size returns the number of tasks in the group.
+func (g *TaskGroup) Size() int {
+	return len(g.tasks)
+}
+
+// Deadline implements DeadlineTask.Deadline. A group is due when its
+// earliest task is.
+func (g *TaskGroup) Deadline() (time.Time, bool) {
+	var earliest time.Time
+	for _, task := range g.tasks {
+		if deadline, ok := taskDeadline(task); ok && (earliest.IsZero() || deadline.Before(earliest)) {
+			earliest = deadline
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+// Resources implements ResourceTask.Resources. A group needs the resources of
+// all of its tasks at once.
+func (g *TaskGroup) Resources() Resources {
+	var total Resources
+	for _, task := range g.tasks {
+		total = total.Add(taskResources(task))
+	}
+	return total
+}
+
+// taskCount returns the number of tasks task stands for.
+func taskCount(task Task) int {
+	if group, ok := unwrapGroup(task); ok {
+		return group.Size()
+	}
+	return 1
+}
+
+// unwrapGroup returns the TaskGroup behind task, if it is one.
+func unwrapGroup(task Task) (*TaskGroup, bool) {
+	if wrapped, ok := task.(interface{ UnwrapTask() Task }); ok {
+		task = wrapped.UnwrapTask()
+	}
+	group, ok := task.(*TaskGroup)
+	return group, ok
+}
+
+// checkGroup rejects task groups that are empty or larger than the lane's
+// concurrency, since they could never start.
+func (l *ChannelLane) checkGroup(task Task) error {
+	group, ok := task.(*TaskGroup)
+	if !ok {
+		return nil
+	}
+	if limit := int(l.maxConcurrency.Load()); group.Size() == 0 || group.Size() > limit {
+		return &GangSizeError{LaneName: l.config.Name, GroupID: group.ID(), Size: group.Size(), MaxConcurrency: limit}
+	}
+	return nil
+}
+
+// dispatchTask hands task to the worker pool. A task group first waits until
+// a worker is free for each of its tasks, and a task requesting resources
+// until they are free in the lane. It returns false if the lane is closed
+// while waiting.
+func (l *ChannelLane) dispatchTask(task Task) bool {
+	group, isGroup := unwrapGroup(task)
+	size := 0
+	if isGroup {
+		size = group.Size()
+	}
+
+	for int(l.maxConcurrency.Load()-l.busy.Load()) < size || !l.reserveResources(task) {
+		select {
+		case <-l.freed:
+		case <-l.closeCh:
+			return false
+		}
+	}
+
+	if !isGroup {
+		l.busy.Add(1)
+		l.workerPool.Submit(task)
+		return true
+	}
+
+	enqueuedAt := time.Now()
+	if tw, ok := task.(interface{ EnqueuedAt() time.Time }); ok {
+		enqueuedAt = tw.EnqueuedAt()
+	}
+	l.busy.Add(int32(group.Size()))
+	for _, member := range group.tasks {
+		l.workerPool.Submit(queuedTask{task: member, enqueuedAt: enqueuedAt, grouped: true})
+	}
+	return true
+}
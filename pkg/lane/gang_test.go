@@ -0,0 +1,141 @@
+package lane
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubLane is a Lane that is not a ChannelLane.
+type stubLane struct{ name string }
+
+func (s *stubLane) Name() string                       { return s.name }
+func (s *stubLane) Submit(context.Context, Task) error { return nil }
+func (s *stubLane) TrySubmit(Task) bool                { return true }
+func (s *stubLane) Stats() Stats                       { return Stats{Name: s.name} }
+func (s *stubLane) Close(context.Context) error        { return nil }
+func (s *stubLane) IsClosed() bool                     { return false }
+
+func TestChannelLane_TaskGroupStartsTogether(t *testing.T) {
+	l, err := New(&Config{Name: "gang", Capacity: 10, MaxConcurrency: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+	l.Run()
+
+	release := make(chan struct{})
+	blockerStarted := make(chan struct{})
+	blocker := NewTaskFunc("blocker", "gang", 0, func(context.Context) error {
+		close(blockerStarted)
+		<-release
+		return nil
+	})
+	if err := l.Submit(context.Background(), blocker); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-blockerStarted
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(id string) {
+		mu.Lock()
+		order = append(order, id)
+		mu.Unlock()
+	}
+
+	// Both members must be running at the same time to pass the barrier.
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	done := make(chan struct{}, 3)
+	member := func(id string) *TaskFunc {
+		return NewTaskFunc(id, "gang", 0, func(context.Context) error {
+			record(id)
+			barrier.Done()
+			barrier.Wait()
+			done <- struct{}{}
+			return nil
+		})
+	}
+	group := NewTaskGroup("group", "gang", 0, member("m1"), member("m2"))
+	if err := l.Submit(context.Background(), group); err != nil {
+		t.Fatalf("Submit(group) error = %v", err)
+	}
+	if got := l.Stats().Pending; got != 2 {
+		t.Fatalf("Pending = %d, want 2", got)
+	}
+	after := NewTaskFunc("after", "gang", 0, func(context.Context) error {
+		record("after")
+		done <- struct{}{}
+		return nil
+	})
+	if err := l.Submit(context.Background(), after); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	started := len(order)
+	mu.Unlock()
+	if started != 0 {
+		t.Fatalf("expected nothing to start while one worker is busy, got %v", order)
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for tasks")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if order[2] != "after" {
+		t.Fatalf("expected the group to start before later tasks, got %v", order)
+	}
+}
+
+func TestChannelLane_TaskGroupSize(t *testing.T) {
+	l, err := New(&Config{Name: "gang", Capacity: 10, MaxConcurrency: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+
+	noop := func(context.Context) error { return nil }
+	big := NewTaskGroup("big", "gang", 0,
+		NewTaskFunc("a", "gang", 0, noop),
+		NewTaskFunc("b", "gang", 0, noop),
+		NewTaskFunc("c", "gang", 0, noop),
+	)
+	var sizeErr *GangSizeError
+	if err := l.Submit(context.Background(), big); !errors.As(err, &sizeErr) || sizeErr.Size != 3 {
+		t.Fatalf("expected gang size error, got %v", err)
+	}
+	if l.TrySubmit(NewTaskGroup("empty", "gang", 0)) {
+		t.Fatal("expected empty group to be rejected")
+	}
+	if got := l.Stats().Rejected; got != 2 {
+		t.Fatalf("Rejected = %d, want 2", got)
+	}
+}
+
+func TestManager_TaskGroupUnsupportedLane(t *testing.T) {
+	m := NewManager()
+	defer m.Close(context.Background())
+	if err := m.RegisterLane(&stubLane{name: "remote"}); err != nil {
+		t.Fatalf("RegisterLane() error = %v", err)
+	}
+
+	group := NewTaskGroup("group", "remote", 0, NewTaskFunc("a", "remote", 0, nil))
+	var unsupported *GangUnsupportedError
+	if err := m.Submit(context.Background(), group); !errors.As(err, &unsupported) {
+		t.Fatalf("expected unsupported lane error, got %v", err)
+	}
+}
@@ -46,12 +46,25 @@ type Task interface {
 	Lane() string
 }
 
+// DeadlineTask is implemented by tasks that should complete by a deadline.
+// Lanes using DispatchEDF dispatch them earliest deadline first, and every
+// lane counts tasks that finish after their deadline.
+type DeadlineTask interface {
+	Task
+
+	// Deadline returns the deadline and whether one is set.
+	Deadline() (time.Time, bool)
+}
+
 // TaskFunc is a function type that implements the Task interface.
 type TaskFunc struct {
-	id       string
-	priority int
-	lane     string
-	fn       func(ctx context.Context) error
+	id        string
+	priority  int
+	lane      string
+	deadline  time.Time
+	resources Resources
+	affinity  string
+	fn        func(ctx context.Context) error
 }
 
 // NewTaskFunc creates a new TaskFunc.
@@ -79,6 +92,41 @@ func (t *TaskFunc) Lane() string {
 	return t.lane
 }
 
+// WithDeadline sets the time by which the task should complete and returns t.
+// A zero deadline clears it.
+func (t *TaskFunc) WithDeadline(deadline time.Time) *TaskFunc {
+	t.deadline = deadline
+	return t
+}
+
+// Deadline implements DeadlineTask.Deadline.
+func (t *TaskFunc) Deadline() (time.Time, bool) {
+	return t.deadline, !t.deadline.IsZero()
+}
+
+// WithResources sets the resources the task needs while it runs and returns t.
+func (t *TaskFunc) WithResources(resources Resources) *TaskFunc {
+	t.resources = resources
+	return t
+}
+
+// Resources implements ResourceTask.Resources.
+func (t *TaskFunc) Resources() Resources {
+	return t.resources
+}
+
+// WithAffinity sets the affinity key of the task and returns t. An empty key
+// clears it.
+func (t *TaskFunc) WithAffinity(key string) *TaskFunc {
+	t.affinity = key
+	return t
+}
+
+// AffinityKey implements AffinityTask.AffinityKey.
+func (t *TaskFunc) AffinityKey() string {
+	return t.affinity
+}
+
 // Execute executes the task function.
 func (t *TaskFunc) Execute(ctx context.Context) error {
 	if t.fn == nil {
@@ -113,6 +161,29 @@ func (s BackpressureStrategy) String() string {
 	}
 }
 
+// DispatchOrder defines the order in which queued tasks reach workers.
+type DispatchOrder int
+
+const (
+	// DispatchFIFO dispatches tasks in submission order.
+	DispatchFIFO DispatchOrder = iota
+	// DispatchEDF dispatches the task with the earliest deadline first. Tasks
+	// without a deadline run after all tasks that have one.
+	DispatchEDF
+)
+
+// String returns the string representation of DispatchOrder.
+func (o DispatchOrder) String() string {
+	switch o {
+	case DispatchFIFO:
+		return "fifo"
+	case DispatchEDF:
+		return "edf"
+	default:
+		return "unknown"
+	}
+}
+
 // Config holds the configuration for a Lane.
 type Config struct {
 	// Name is the unique name of the lane.
@@ -143,6 +214,20 @@ type Config struct {
 
 	// RateLimit enables rate limiting (tasks per second, 0 = unlimited).
 	RateLimit float64
+
+	// Adaptive enables AIMD concurrency tuning between
+	// Adaptive.MinConcurrency and MaxConcurrency. Nil keeps a fixed limit.
+	// Cannot be combined with EnableDynamicWorkers.
+	Adaptive *AdaptiveConfig
+
+	// Dispatch is the order in which queued tasks are handed to workers.
+	// Default is DispatchFIFO.
+	Dispatch DispatchOrder
+
+	// Resources is the total CPU, memory and GPU the lane provides to its
+	// running tasks. A task starts only once its request fits in what is
+	// free. Nil disables resource tracking.
+	Resources *Resources
 }
 
 // Validate validates the lane configuration.
@@ -170,6 +255,22 @@ func (c *Config) Validate() error {
 	if c.RateLimit < 0 {
 		return fmt.Errorf("rate limit cannot be negative")
 	}
+	if c.Dispatch != DispatchFIFO && c.Dispatch != DispatchEDF {
+		return fmt.Errorf("unknown dispatch order %d", c.Dispatch)
+	}
+	if c.Resources != nil {
+		if err := c.Resources.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Adaptive != nil {
+		if c.EnableDynamicWorkers {
+			return fmt.Errorf("adaptive concurrency cannot be combined with dynamic workers")
+		}
+		if err := c.Adaptive.Validate(c.MaxConcurrency); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -234,8 +335,36 @@ type Stats struct {
 	// WaitTime is the average wait time in the queue.
 	WaitTime time.Duration
 
+	// WaitP50, WaitP95 and WaitP99 are queue wait time percentiles over the
+	// most recently dequeued tasks.
+	WaitP50 time.Duration
+	WaitP95 time.Duration
+	WaitP99 time.Duration
+
 	// ProcessTime is the average processing time.
 	ProcessTime time.Duration
+
+	// RateLimit is the admission rate in tasks per second, 0 when unlimited.
+	RateLimit float64
+
+	// RateLimitTokens is the number of submissions the rate limiter admits
+	// right now without waiting.
+	RateLimitTokens float64
+
+	// Throttled is the total number of submissions that had to wait for, or
+	// were rejected by, the rate limiter.
+	Throttled int64
+
+	// DeadlineMissed is the total number of tasks that finished after their
+	// deadline.
+	DeadlineMissed int64
+
+	// Resources is the resource capacity of the lane, zero when the lane does
+	// not track resources.
+	Resources Resources
+
+	// ResourcesInUse is the share of Resources held by running tasks.
+	ResourcesInUse Resources
 }
 
 // Utilization returns the current utilization ratio (0.0 - 1.0).
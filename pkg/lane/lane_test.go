@@ -589,3 +589,112 @@ func IsDuplicateLane(err error) bool {
 	_, ok := err.(*DuplicateLaneError)
 	return ok
 }
+
+func TestChannelLane_SetMaxConcurrency(t *testing.T) {
+	l, err := New(&Config{Name: "resize", Capacity: 100, MaxConcurrency: 1, Backpressure: Block})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close(context.Background())
+	l.Run()
+
+	if err := l.SetMaxConcurrency(0); err == nil {
+		t.Fatal("expected error for zero concurrency")
+	}
+	if err := l.SetMaxConcurrency(4); err != nil {
+		t.Fatalf("SetMaxConcurrency failed: %v", err)
+	}
+	if got := l.Stats().MaxConcurrency; got != 4 {
+		t.Fatalf("expected max concurrency 4, got %d", got)
+	}
+
+	release := make(chan struct{})
+	var running atomic.Int32
+	for i := 0; i < 4; i++ {
+		task := NewTaskFunc(fmt.Sprintf("task-%d", i), "resize", 0, func(ctx context.Context) error {
+			running.Add(1)
+			<-release
+			return nil
+		})
+		if err := l.Submit(context.Background(), task); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for running.Load() < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	if got := running.Load(); got != 4 {
+		t.Fatalf("expected 4 concurrent tasks after resize, got %d", got)
+	}
+
+	if err := l.SetMaxConcurrency(2); err != nil {
+		t.Fatalf("SetMaxConcurrency shrink failed: %v", err)
+	}
+	if got := l.Stats().MaxConcurrency; got != 2 {
+		t.Errorf("expected max concurrency 2, got %d", got)
+	}
+}
+
+func TestChannelLane_SetRateLimit(t *testing.T) {
+	l, err := New(&Config{Name: "rate", Capacity: 10, MaxConcurrency: 1, Backpressure: Drop})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close(context.Background())
+
+	if err := l.SetRateLimit(-1); err == nil {
+		t.Fatal("expected error for negative rate")
+	}
+	if err := l.SetRateLimit(1); err != nil {
+		t.Fatalf("SetRateLimit failed: %v", err)
+	}
+	if limiter := l.rateLimiter.Load(); limiter == nil || limiter.Rate() != 1 {
+		t.Fatalf("expected rate limiter with rate 1")
+	}
+	if err := l.SetRateLimit(5); err != nil {
+		t.Fatalf("SetRateLimit update failed: %v", err)
+	}
+	if got := l.rateLimiter.Load().Rate(); got != 5 {
+		t.Errorf("expected rate 5, got %v", got)
+	}
+	if err := l.SetRateLimit(0); err != nil {
+		t.Fatalf("SetRateLimit disable failed: %v", err)
+	}
+	if l.rateLimiter.Load() != nil {
+		t.Error("expected rate limiter to be removed")
+	}
+}
+
+func TestChannelLane_StatsRateLimit(t *testing.T) {
+	l, err := New(&Config{Name: "rate-stats", Capacity: 10, MaxConcurrency: 1, Backpressure: Drop, RateLimit: 1})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close(context.Background())
+
+	// The bucket starts with two tokens; the third submission is throttled.
+	for i := 0; i < 3; i++ {
+		l.TrySubmit(NewTaskFunc(fmt.Sprintf("task-%d", i), "rate-stats", 1, nil))
+	}
+
+	stats := l.Stats()
+	if stats.RateLimit != 1 {
+		t.Errorf("expected rate limit 1, got %v", stats.RateLimit)
+	}
+	if stats.RateLimitTokens >= 1 {
+		t.Errorf("expected drained bucket, got %v tokens", stats.RateLimitTokens)
+	}
+	if stats.Throttled != 1 || stats.Rejected != 1 {
+		t.Errorf("expected 1 throttled and rejected submission, got throttled=%d rejected=%d", stats.Throttled, stats.Rejected)
+	}
+
+	if err := l.SetRateLimit(0); err != nil {
+		t.Fatalf("SetRateLimit disable failed: %v", err)
+	}
+	if stats := l.Stats(); stats.RateLimit != 0 || stats.RateLimitTokens != 0 {
+		t.Errorf("expected no rate limit state, got rate=%v tokens=%v", stats.RateLimit, stats.RateLimitTokens)
+	}
+}
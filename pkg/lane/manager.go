@@ -13,8 +13,10 @@ import (
 type Manager struct {
 	lanes       map[string]Lane
 	configs     map[string]*LaneSpec
-	redisClient redis.Cmdable
+	redisClient redis.UniversalClient
+	redisNS     string
 	ownership   RedisOwnershipGuard
+	noConsume   bool
 	mu          sync.RWMutex
 	closed      atomic.Bool
 	closeOnce   sync.Once
@@ -29,12 +31,21 @@ func NewManager() *Manager {
 }
 
 // SetRedisClient sets the shared Redis client for Redis-backed lanes.
-func (m *Manager) SetRedisClient(client redis.Cmdable) {
+func (m *Manager) SetRedisClient(client redis.UniversalClient) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.redisClient = client
 }
 
+// SetRedisNamespace sets a prefix prepended to the key prefix of every
+// Redis-backed lane registered afterwards, isolating environments that
+// share one Redis.
+func (m *Manager) SetRedisNamespace(namespace string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.redisNS = namespace
+}
+
 // SetRedisOwnershipGuard sets distributed ownership guard for Redis-backed lanes.
 func (m *Manager) SetRedisOwnershipGuard(guard RedisOwnershipGuard) {
 	m.mu.Lock()
@@ -48,6 +59,15 @@ func (m *Manager) SetRedisOwnershipGuard(guard RedisOwnershipGuard) {
 	}
 }
 
+// SetRedisConsume sets whether Redis-backed lanes registered afterwards start
+// workers consuming their queue. Nodes that only enqueue work for other nodes
+// disable it; a lane with a local fallback still runs the fallback.
+func (m *Manager) SetRedisConsume(consume bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.noConsume = !consume
+}
+
 // Register registers a new lane with the given configuration.
 // Returns an error if a lane with the same name already exists.
 func (m *Manager) Register(config *Config) (Lane, error) {
@@ -94,7 +114,9 @@ func (m *Manager) RegisterSpec(spec *LaneSpec) (Lane, error) {
 		if m.redisClient == nil {
 			return nil, fmt.Errorf("redis client is not configured")
 		}
-		redisLane, err := NewRedisLane(m.redisClient, spec.Redis)
+		redisCfg := *spec.Redis
+		redisCfg.KeyPrefix = m.redisNS + redisCfg.KeyPrefix
+		redisLane, err := NewRedisLane(m.redisClient, &redisCfg)
 		if err != nil {
 			return nil, err
 		}
@@ -125,7 +147,11 @@ func (m *Manager) RegisterSpec(spec *LaneSpec) (Lane, error) {
 	m.lanes[name] = lane
 	m.configs[name] = spec
 
-	if runner, ok := lane.(interface{ Run() }); ok {
+	if spec.Type == LaneTypeRedis && m.noConsume {
+		if fallback, ok := lane.(*FallbackLane); ok {
+			fallback.runFallback()
+		}
+	} else if runner, ok := lane.(interface{ Run() }); ok {
 		runner.Run()
 	}
 
@@ -207,6 +233,11 @@ func (m *Manager) Submit(ctx context.Context, task Task) error {
 	if err != nil {
 		return err
 	}
+	if _, ok := task.(*TaskGroup); ok {
+		if _, ok := lane.(*ChannelLane); !ok {
+			return &GangUnsupportedError{LaneName: laneName}
+		}
+	}
 
 	return lane.Submit(ctx, task)
 }
@@ -262,6 +293,7 @@ func (m *Manager) AggregateStats() Stats {
 		agg.Accepted += stats.Accepted
 		agg.Rejected += stats.Rejected
 		agg.Redirected += stats.Redirected
+		agg.Throttled += stats.Throttled
 		agg.Capacity += stats.Capacity
 		agg.MaxConcurrency += stats.MaxConcurrency
 	}
@@ -72,3 +72,128 @@ func TestManager_RegisterSpec_MixedTypes(t *testing.T) {
 		t.Fatalf("manager close failed: %v", err)
 	}
 }
+
+func TestManager_RegisterSpec_RedisNamespace(t *testing.T) {
+	manager := NewManager()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         "127.0.0.1:0",
+		DialTimeout:  5 * time.Millisecond,
+		ReadTimeout:  5 * time.Millisecond,
+		WriteTimeout: 5 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	manager.SetRedisClient(client)
+	manager.SetRedisNamespace("staging:")
+
+	spec := &LaneSpec{
+		Type: LaneTypeRedis,
+		Redis: &RedisConfig{
+			Name:           "io",
+			Capacity:       5,
+			MaxConcurrency: 1,
+			Backpressure:   Block,
+			KeyPrefix:      DefaultKeyPrefix,
+			BlockTimeout:   time.Second,
+		},
+	}
+
+	l, err := manager.RegisterSpec(spec)
+	if err != nil {
+		t.Fatalf("failed to register redis lane: %v", err)
+	}
+	var rl *RedisLane
+	switch v := l.(type) {
+	case *RedisLane:
+		rl = v
+	case *FallbackLane:
+		rl = v.primary
+	default:
+		t.Fatalf("expected redis-backed lane, got %T", l)
+	}
+	if rl.queueKey != "staging:goclaw:lane:io:queue" {
+		t.Errorf("unexpected queue key %q", rl.queueKey)
+	}
+	if spec.Redis.KeyPrefix != DefaultKeyPrefix {
+		t.Errorf("expected spec to be left unchanged, got prefix %q", spec.Redis.KeyPrefix)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := manager.Close(ctx); err != nil {
+		t.Fatalf("manager close failed: %v", err)
+	}
+}
+
+// universalMock adapts mockRedisClient to the UniversalClient the manager
+// takes, forwarding the commands of a redis lane without dedup.
+type universalMock struct {
+	redis.UniversalClient
+	mock *mockRedisClient
+}
+
+func (u universalMock) Ping(ctx context.Context) *redis.StatusCmd { return u.mock.Ping(ctx) }
+
+func (u universalMock) LLen(ctx context.Context, key string) *redis.IntCmd {
+	return u.mock.LLen(ctx, key)
+}
+
+func (u universalMock) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	return u.mock.LPush(ctx, key, values...)
+}
+
+func (u universalMock) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd {
+	return u.mock.BRPop(ctx, timeout, keys...)
+}
+
+func TestManager_SetRedisConsume(t *testing.T) {
+	client := universalMock{mock: newMockRedisClient(t)}
+	prefix := uniqueKeyPrefix("enqueue-only")
+	queueKey := prefix + "jobs:queue"
+	spec := &LaneSpec{
+		Type: LaneTypeRedis,
+		Redis: &RedisConfig{
+			Name:           "jobs",
+			Capacity:       5,
+			MaxConcurrency: 1,
+			Backpressure:   Block,
+			KeyPrefix:      prefix,
+			BlockTimeout:   10 * time.Millisecond,
+		},
+	}
+
+	producer := NewManager()
+	producer.SetRedisClient(client)
+	producer.SetRedisConsume(false)
+	if _, err := producer.RegisterSpec(spec); err != nil {
+		t.Fatalf("failed to register redis lane: %v", err)
+	}
+	if err := producer.Submit(context.Background(), NewTaskFunc("job-1", "jobs", 0, nil)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := client.LLen(context.Background(), queueKey).Val(); n != 1 {
+		t.Fatalf("queue length on an enqueue-only manager = %d, want 1", n)
+	}
+
+	consumer := NewManager()
+	consumer.SetRedisClient(client)
+	if _, err := consumer.RegisterSpec(spec); err != nil {
+		t.Fatalf("failed to register redis lane: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for client.LLen(context.Background(), queueKey).Val() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("consuming manager did not drain the queue")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, m := range []*Manager{producer, consumer} {
+		if err := m.Close(ctx); err != nil {
+			t.Fatalf("manager close failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package lane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisAffinityTTL is how long the key of a running affinity task is held
+// without being refreshed, so that a consumer that crashed does not hold it
+// forever. The worker running the task refreshes it.
+const redisAffinityTTL = 30 * time.Second
+
+// Tasks sharing an affinity key are serialised across the consumers of a
+// RedisLane with two keys per affinity key: a marker held, with a TTL, while
+// one of its tasks runs or is readied to run next, and a list of the tasks
+// dequeued meanwhile, parked in dequeue order. Both sides of a handoff check
+// again after their change, so that a task parked as the key is released is
+// not left behind.
+
+// affinityKeys returns the Redis keys of the marker and the parked tasks of
+// key.
+func (l *RedisLane) affinityKeys(key string) (held, waiting string) {
+	held = l.config.KeyPrefix + l.config.Name + ":affinity:" + key
+	return held, held + ":waiting"
+}
+
+// admitAffinity returns the task to run for the dequeued payload: payload
+// itself, or an earlier task with its key that was left parked, or nil when
+// payload was parked behind the running task with its key. On error,
+// payload was not parked.
+func (l *RedisLane) admitAffinity(ctx context.Context, payload *RedisTaskPayload) (*RedisTaskPayload, error) {
+	if payload.AffinityKey == "" || payload.Admitted {
+		return payload, nil
+	}
+	held, waiting := l.affinityKeys(payload.AffinityKey)
+	acquired, err := l.client.SetNX(ctx, held, l.config.Name, redisAffinityTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if acquired {
+		// Tasks parked behind a holder that crashed run first.
+		parked, err := l.client.LLen(ctx, waiting).Result()
+		if err != nil {
+			_ = l.client.Del(ctx, held).Err()
+			return nil, err
+		}
+		if parked == 0 {
+			return payload, nil
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task: %w", err)
+	}
+	if err := l.client.RPush(ctx, waiting, data).Err(); err != nil {
+		return nil, err
+	}
+	// Once payload is parked, errors leave it to the next task with its key
+	// rather than fail it.
+	if !acquired {
+		// The holder may have released the key before payload was parked.
+		if acquired, err = l.client.SetNX(ctx, held, l.config.Name, redisAffinityTTL).Result(); err != nil || !acquired {
+			return nil, nil
+		}
+	}
+	next, _ := l.nextWaiting(ctx, payload.AffinityKey)
+	return next, nil
+}
+
+// nextWaiting pops the oldest parked task of key, which the caller holds,
+// or releases the key and returns nil when none is parked.
+func (l *RedisLane) nextWaiting(ctx context.Context, key string) (*RedisTaskPayload, error) {
+	held, waiting := l.affinityKeys(key)
+	for {
+		data, err := l.client.LPop(ctx, waiting).Result()
+		if err == nil {
+			var payload RedisTaskPayload
+			if err := json.Unmarshal([]byte(data), &payload); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+			}
+			return &payload, nil
+		}
+		if err != redis.Nil {
+			return nil, err
+		}
+
+		if err := l.client.Del(ctx, held).Err(); err != nil {
+			return nil, err
+		}
+		// A task parked before the release is seen here, one parked after
+		// it acquires the key itself.
+		parked, err := l.client.LLen(ctx, waiting).Result()
+		if err != nil || parked == 0 {
+			return nil, err
+		}
+		acquired, err := l.client.SetNX(ctx, held, l.config.Name, redisAffinityTTL).Result()
+		if err != nil || !acquired {
+			return nil, err
+		}
+	}
+}
+
+// holdAffinity refreshes the TTL of key while its task runs, until the
+// returned function is called.
+func (l *RedisLane) holdAffinity(key string) func() {
+	held, _ := l.affinityKeys(key)
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(redisAffinityTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = l.client.Expire(context.Background(), held, redisAffinityTTL).Err()
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
+
+// finishAffinity stops holding the affinity key of the finished payload and
+// hands it over.
+func (l *RedisLane) finishAffinity(ctx context.Context, payload *RedisTaskPayload, stopHold func()) {
+	if stopHold == nil {
+		return
+	}
+	stopHold()
+	l.releaseAffinity(ctx, payload.AffinityKey)
+}
+
+// releaseAffinity hands key over to its next parked task once the running
+// one finished, requeuing it to be dequeued next, or releases the key.
+func (l *RedisLane) releaseAffinity(ctx context.Context, key string) {
+	next, err := l.nextWaiting(ctx, key)
+	if err != nil || next == nil {
+		return
+	}
+	next.Admitted = true
+	held, _ := l.affinityKeys(key)
+	_ = l.client.Expire(ctx, held, redisAffinityTTL).Err()
+	if err := l.requeue(ctx, next); err == nil {
+		l.pending.Add(1)
+	}
+}
+
+// requeue puts a dequeued task back at the head of the queue.
+func (l *RedisLane) requeue(ctx context.Context, payload *RedisTaskPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	if l.config.EnablePriority {
+		return l.client.ZAdd(ctx, l.queueKey, redis.Z{Score: float64(payload.Priority), Member: string(data)}).Err()
+	}
+	// BRPOP takes from the right.
+	return l.client.RPush(ctx, l.queueKey, data).Err()
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/rediskey"
 	"github.com/redis/go-redis/v9"
 )
 
+// DefaultKeyPrefix is the default Redis key prefix for lane keys.
+const DefaultKeyPrefix = rediskey.Root + "lane:"
+
 // RedisConfig holds configuration for a Redis-backed Lane.
 type RedisConfig struct {
 	// Name is the lane name.
@@ -50,7 +54,7 @@ func DefaultRedisConfig(name string) *RedisConfig {
 		EnablePriority: false,
 		EnableDedup:    false,
 		DedupTTL:       1 * time.Hour,
-		KeyPrefix:      "goclaw:lane:",
+		KeyPrefix:      DefaultKeyPrefix,
 		BlockTimeout:   2 * time.Second,
 	}
 }
@@ -77,6 +81,12 @@ func NewRedisSentinelClient(opts *redis.FailoverOptions) *redis.Client {
 	return redis.NewFailoverClient(opts)
 }
 
+// NewRedisClusterClient creates a Redis Cluster client. Lane keys are
+// single-key operations, so each lane's keys may live on any slot.
+func NewRedisClusterClient(opts *redis.ClusterOptions) *redis.ClusterClient {
+	return redis.NewClusterClient(opts)
+}
+
 // PingRedis checks if the Redis connection is healthy.
 func PingRedis(ctx context.Context, client redis.Cmdable) error {
 	return client.Ping(ctx).Err()
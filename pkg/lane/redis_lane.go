@@ -43,6 +43,14 @@ type RedisTaskPayload struct {
 	Payload    json.RawMessage   `json:"payload,omitempty"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
 	EnqueuedAt time.Time         `json:"enqueued_at"`
+
+	// AffinityKey serialises the task with the others sharing the key,
+	// across all consumers of the lane.
+	AffinityKey string `json:"affinity_key,omitempty"`
+
+	// Admitted marks a task handed the affinity key by the task before it,
+	// which is held for it until it runs.
+	Admitted bool `json:"admitted,omitempty"`
 }
 
 // RedisLane implements the Lane interface using Redis as the backing store.
@@ -69,6 +77,7 @@ type RedisLane struct {
 	accepted  atomic.Int64
 	rejected  atomic.Int64
 	redirected atomic.Int64
+	waits      *waitTracker
 
 	// Worker management
 	taskHandler func(ctx context.Context, payload *RedisTaskPayload) error
@@ -102,6 +111,7 @@ func NewRedisLane(client redis.Cmdable, config *RedisConfig) (*RedisLane, error)
 		statsKey: prefix + ":stats",
 		closeCh:  make(chan struct{}),
 		metrics:  &nopMetrics{},
+		waits:    newWaitTracker(),
 	}
 
 	return l, nil
@@ -218,6 +228,11 @@ func (l *RedisLane) Submit(ctx context.Context, task Task) (err error) {
 		payload.ShardKey = distributedTask.ShardKey()
 		payload.Fencing = distributedTask.FencingToken()
 	}
+	payload.AffinityKey = taskAffinityKey(task)
+	if payload.ShardKey == "" {
+		// Tasks sharing an affinity key share a shard, and so its owner.
+		payload.ShardKey = payload.AffinityKey
+	}
 
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -275,7 +290,7 @@ func (l *RedisLane) TrySubmit(task Task) bool {
 
 // Stats returns current lane statistics.
 func (l *RedisLane) Stats() Stats {
-	return Stats{
+	stats := Stats{
 		Name:           l.config.Name,
 		Pending:        int(l.pending.Load()),
 		Running:        int(l.running.Load()),
@@ -288,6 +303,8 @@ func (l *RedisLane) Stats() Stats {
 		Capacity:       l.config.Capacity,
 		MaxConcurrency: l.config.MaxConcurrency,
 	}
+	l.waits.snapshot().apply(&stats)
+	return stats
 }
 
 // Close gracefully shuts down the Redis lane.
@@ -411,12 +428,30 @@ func (l *RedisLane) worker() {
 		}
 
 		l.pending.Add(-1)
-		l.running.Add(1)
 		l.metrics.DecQueueDepth(l.config.Name)
 		if recorder, ok := l.metrics.(redisMetricsRecorder); ok {
 			recorder.SetRedisQueueDepth(l.config.Name, float64(l.pending.Load()))
 		}
 
+		next, err := l.admitAffinity(ctx, payload)
+		if err != nil {
+			if l.requeue(ctx, payload) == nil {
+				l.pending.Add(1)
+			}
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		if next == nil {
+			// Parked until the running task with its affinity key finishes.
+			continue
+		}
+		payload = next
+		l.running.Add(1)
+		var releaseHold func()
+		if payload.AffinityKey != "" {
+			releaseHold = l.holdAffinity(payload.AffinityKey)
+		}
+
 		start := time.Now()
 		if l.ownershipGuard != nil && payload.Fencing > 0 {
 			shardKey := payload.ShardKey
@@ -426,6 +461,7 @@ func (l *RedisLane) worker() {
 			if ferr := l.ownershipGuard.ValidateFencing(ctx, shardKey, payload.Fencing); ferr != nil {
 				l.failed.Add(1)
 				l.running.Add(-1)
+				l.finishAffinity(ctx, payload, releaseHold)
 				continue
 			}
 		}
@@ -439,9 +475,12 @@ func (l *RedisLane) worker() {
 			l.completed.Add(1)
 		}
 		_ = l.removeDedup(context.Background(), payload.ID)
+		l.finishAffinity(ctx, payload, releaseHold)
 
 		l.running.Add(-1)
-		l.metrics.RecordWaitDuration(l.config.Name, time.Since(payload.EnqueuedAt))
+		waitDuration := time.Since(payload.EnqueuedAt)
+		l.waits.observe(waitDuration)
+		l.metrics.RecordWaitDuration(l.config.Name, waitDuration)
 		l.metrics.RecordThroughput(l.config.Name)
 		if recorder, ok := l.metrics.(redisMetricsRecorder); ok {
 			recorder.RecordRedisThroughput(l.config.Name)
@@ -2,6 +2,8 @@ package lane
 
 import (
 	"context"
+	"reflect"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -345,3 +347,114 @@ func TestRedisLane_Unit_BackpressureBlock(t *testing.T) {
 		t.Fatal("blocked submit did not resume")
 	}
 }
+
+func TestRedisLane_Unit_AffinityKeyBecomesShardKey(t *testing.T) {
+	client := newMockRedisClient(t)
+
+	cfg := DefaultRedisConfig("affinity")
+	cfg.KeyPrefix = uniqueKeyPrefix("affinity")
+	cfg.Capacity = 10
+	cfg.MaxConcurrency = 1
+	cfg.BlockTimeout = 20 * time.Millisecond
+
+	l, err := NewRedisLane(client, cfg)
+	if err != nil {
+		t.Fatalf("NewRedisLane failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = l.Close(context.Background())
+	})
+
+	shardKeys := make(chan string, 1)
+	l.SetTaskHandler(func(ctx context.Context, payload *RedisTaskPayload) error {
+		shardKeys <- payload.ShardKey
+		return nil
+	})
+	l.Run()
+
+	if err := l.Submit(context.Background(), NewTaskFunc("t1", "affinity", 0, nil).WithAffinity("customer-42")); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	select {
+	case got := <-shardKeys:
+		if got != "customer-42" {
+			t.Fatalf("shard key = %q, want customer-42", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for task")
+	}
+}
+
+func TestRedisLane_Unit_AffinitySerialisesAcrossConsumers(t *testing.T) {
+	client := newMockRedisClient(t)
+	prefix := uniqueKeyPrefix("affinity-serial")
+
+	var mu sync.Mutex
+	running := map[string]int{}
+	var order []string
+	overlap := make(chan string, 8)
+	done := make(chan struct{}, 8)
+	// Two lanes on the same keys are two consumers of the queue.
+	for i := 0; i < 2; i++ {
+		cfg := DefaultRedisConfig("affinity")
+		cfg.KeyPrefix = prefix
+		cfg.Capacity = 10
+		cfg.MaxConcurrency = 2
+		cfg.BlockTimeout = 10 * time.Millisecond
+		l, err := NewRedisLane(client, cfg)
+		if err != nil {
+			t.Fatalf("NewRedisLane failed: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = l.Close(context.Background())
+		})
+		l.SetTaskHandler(func(ctx context.Context, payload *RedisTaskPayload) error {
+			mu.Lock()
+			running[payload.AffinityKey]++
+			if running[payload.AffinityKey] > 1 {
+				overlap <- payload.ID
+			}
+			if payload.AffinityKey == "customer-42" {
+				order = append(order, payload.ID)
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running[payload.AffinityKey]--
+			mu.Unlock()
+			done <- struct{}{}
+			return nil
+		})
+		if i == 0 {
+			for _, id := range []string{"t1", "t2", "t3"} {
+				if err := l.Submit(context.Background(), NewTaskFunc(id, "affinity", 0, nil).WithAffinity("customer-42")); err != nil {
+					t.Fatalf("submit failed: %v", err)
+				}
+			}
+			if err := l.Submit(context.Background(), NewTaskFunc("other", "affinity", 0, nil).WithAffinity("customer-7")); err != nil {
+				t.Fatalf("submit failed: %v", err)
+			}
+		}
+		l.Run()
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-done:
+		case id := <-overlap:
+			t.Fatalf("task %s ran alongside another task with its affinity key", id)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out after %d tasks", i)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"t1", "t2", "t3"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("ran %v, want %v", order, want)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.values) != 0 {
+		t.Fatalf("affinity keys still held: %v", client.values)
+	}
+}
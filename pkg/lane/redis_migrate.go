@@ -0,0 +1,175 @@
+package lane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goclaw/goclaw/pkg/rediskey"
+	"github.com/redis/go-redis/v9"
+)
+
+// NamespaceMigration describes moving goclaw keys between Redis namespaces.
+type NamespaceMigration struct {
+	// From is the namespace the keys currently live under ("" for none).
+	From string
+
+	// To is the namespace to move the keys to.
+	To string
+
+	// KeyPrefixes are the prefixes of the keys to move inside the
+	// namespace. Defaults to rediskey.Root, which starts every key goclaw
+	// writes: lanes and their affinity keys, signal streams and history,
+	// locks, rate limits and the LLM cache. A signal.channel_prefix outside
+	// of it has to be added.
+	KeyPrefixes []string
+
+	// DryRun lists the keys that would be moved without modifying Redis.
+	DryRun bool
+
+	// ScanCount is the SCAN batch size hint. Defaults to 100.
+	ScanCount int64
+}
+
+// MigrateNamespace moves goclaw keys from one namespace to another so an
+// existing deployment can adopt redis.key_prefix. Keys are copied with
+// DUMP/RESTORE, keeping their TTL, and then deleted, which also works across
+// Redis Cluster slots. The migration refuses to run if any destination key
+// already exists. Run it while no goclaw instance is using the keys.
+//
+// It returns the source keys that were (or, for a dry run, would be) moved.
+func MigrateNamespace(ctx context.Context, client redis.UniversalClient, m NamespaceMigration) ([]string, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client cannot be nil")
+	}
+	if m.From == m.To {
+		return nil, fmt.Errorf("source and destination namespace are both %q", m.From)
+	}
+	if len(m.KeyPrefixes) == 0 {
+		m.KeyPrefixes = []string{rediskey.Root}
+	}
+	if m.ScanCount <= 0 {
+		m.ScanCount = 100
+	}
+
+	patterns := make([]string, 0, len(m.KeyPrefixes))
+	for _, prefix := range m.KeyPrefixes {
+		patterns = append(patterns, escapeGlob(m.From+prefix)+"*")
+	}
+	keys, err := scanKeys(ctx, client, patterns, m.ScanCount)
+	if err != nil {
+		return nil, fmt.Errorf("scan keys: %w", err)
+	}
+
+	destination := func(key string) string {
+		return m.To + strings.TrimPrefix(key, m.From)
+	}
+
+	for _, key := range keys {
+		n, err := client.Exists(ctx, destination(key)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", destination(key), err)
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("destination key %s already exists", destination(key))
+		}
+	}
+	if m.DryRun {
+		return keys, nil
+	}
+
+	moved := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ok, err := moveKey(ctx, client, key, destination(key))
+		if err != nil {
+			return moved, fmt.Errorf("move %s: %w", key, err)
+		}
+		if ok {
+			moved = append(moved, key)
+		}
+	}
+	return moved, nil
+}
+
+// scanKeys returns the sorted keys matching any of patterns. Cluster
+// clients are scanned on every master.
+func scanKeys(ctx context.Context, client redis.UniversalClient, patterns []string, count int64) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]struct{})
+	)
+	scan := func(ctx context.Context, c redis.Cmdable) error {
+		for _, pattern := range patterns {
+			iter := c.Scan(ctx, 0, pattern, count).Iterator()
+			for iter.Next(ctx) {
+				mu.Lock()
+				seen[iter.Val()] = struct{}{}
+				mu.Unlock()
+			}
+			if err := iter.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scan(ctx, node)
+		})
+	} else {
+		err = scan(ctx, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// moveKey copies src to dst with its TTL and deletes src. It reports false
+// if src disappeared before it could be copied.
+func moveKey(ctx context.Context, client redis.UniversalClient, src, dst string) (bool, error) {
+	ttl, err := client.PTTL(ctx, src).Result()
+	if err != nil {
+		return false, err
+	}
+	data, err := client.Dump(ctx, src).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// PTTL reports -1 for keys without expiry; RESTORE takes 0 for none.
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := client.Restore(ctx, dst, ttl, data).Err(); err != nil {
+		return false, err
+	}
+	return true, client.Del(ctx, src).Err()
+}
+
+// escapeGlob escapes the SCAN MATCH metacharacters in s.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
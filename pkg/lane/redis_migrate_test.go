@@ -0,0 +1,118 @@
+package lane
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscapeGlob(t *testing.T) {
+	if got := escapeGlob(`a*b?[c]\`); got != `a\*b\?\[c\]\\` {
+		t.Fatalf("escapeGlob() = %q", got)
+	}
+	if got := escapeGlob("staging:"); got != "staging:" {
+		t.Fatalf("escapeGlob() = %q", got)
+	}
+}
+
+func TestMigrateNamespace_Validation(t *testing.T) {
+	if _, err := MigrateNamespace(context.Background(), nil, NamespaceMigration{To: "x:"}); err == nil {
+		t.Fatal("expected error for nil client")
+	}
+
+	client := requireRedisClient(t)
+	if _, err := MigrateNamespace(context.Background(), client, NamespaceMigration{From: "x:", To: "x:"}); err == nil {
+		t.Fatal("expected error for identical namespaces")
+	}
+}
+
+func TestMigrateNamespace(t *testing.T) {
+	client := requireRedisClient(t)
+	ctx := context.Background()
+
+	from := fmt.Sprintf("migrate-from-%d:", time.Now().UnixNano())
+	to := fmt.Sprintf("migrate-to-%d:", time.Now().UnixNano())
+	queueKey := from + DefaultKeyPrefix + "default:queue"
+	dedupKey := from + DefaultKeyPrefix + "default:dedup"
+	lockKey := from + "goclaw:lock:billing-db"
+	otherKey := from + "unrelated"
+	t.Cleanup(func() {
+		_ = client.Del(ctx, queueKey, dedupKey, lockKey, otherKey,
+			to+DefaultKeyPrefix+"default:queue", to+DefaultKeyPrefix+"default:dedup", to+"goclaw:lock:billing-db").Err()
+	})
+
+	if err := client.RPush(ctx, queueKey, "a", "b").Err(); err != nil {
+		t.Fatalf("RPush() error = %v", err)
+	}
+	if err := client.SAdd(ctx, dedupKey, "task-1").Err(); err != nil {
+		t.Fatalf("SAdd() error = %v", err)
+	}
+	if err := client.Expire(ctx, dedupKey, time.Hour).Err(); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+	if err := client.Set(ctx, lockKey, "owner", time.Minute).Err(); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := client.Set(ctx, otherKey, "keep", 0).Err(); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	planned, err := MigrateNamespace(ctx, client, NamespaceMigration{From: from, To: to, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+	if len(planned) != 3 {
+		t.Fatalf("expected 3 planned keys, got %v", planned)
+	}
+	if n := client.Exists(ctx, queueKey).Val(); n != 1 {
+		t.Fatal("dry run must not modify keys")
+	}
+
+	moved, err := MigrateNamespace(ctx, client, NamespaceMigration{From: from, To: to})
+	if err != nil {
+		t.Fatalf("MigrateNamespace() error = %v", err)
+	}
+	if len(moved) != 3 {
+		t.Fatalf("expected 3 moved keys, got %v", moved)
+	}
+
+	if n := client.Exists(ctx, queueKey, dedupKey, lockKey).Val(); n != 0 {
+		t.Fatalf("expected source keys to be removed, %d remain", n)
+	}
+	items := client.LRange(ctx, to+DefaultKeyPrefix+"default:queue", 0, -1).Val()
+	if strings.Join(items, ",") != "a,b" {
+		t.Fatalf("unexpected migrated queue: %v", items)
+	}
+	if ttl := client.TTL(ctx, to+DefaultKeyPrefix+"default:dedup").Val(); ttl <= 0 {
+		t.Fatalf("expected TTL to be preserved, got %v", ttl)
+	}
+	if client.Get(ctx, to+"goclaw:lock:billing-db").Val() != "owner" {
+		t.Fatal("expected the lock key to be migrated")
+	}
+	if client.Get(ctx, otherKey).Val() != "keep" {
+		t.Fatal("expected keys outside the goclaw prefix to be untouched")
+	}
+}
+
+func TestMigrateNamespace_DestinationExists(t *testing.T) {
+	client := requireRedisClient(t)
+	ctx := context.Background()
+
+	from := fmt.Sprintf("migrate-conflict-from-%d:", time.Now().UnixNano())
+	to := fmt.Sprintf("migrate-conflict-to-%d:", time.Now().UnixNano())
+	src := from + DefaultKeyPrefix + "default:queue"
+	dst := to + DefaultKeyPrefix + "default:queue"
+	t.Cleanup(func() { _ = client.Del(ctx, src, dst).Err() })
+
+	_ = client.RPush(ctx, src, "a").Err()
+	_ = client.RPush(ctx, dst, "b").Err()
+
+	if _, err := MigrateNamespace(ctx, client, NamespaceMigration{From: from, To: to}); err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if n := client.Exists(ctx, src).Val(); n != 1 {
+		t.Fatal("expected source key to be untouched after conflict")
+	}
+}
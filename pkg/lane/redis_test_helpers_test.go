@@ -28,16 +28,19 @@ type mockRedisClient struct {
 	lists map[string][]string
 	zsets map[string][]mockZMember
 	sets  map[string]map[string]struct{}
-	down  atomic.Bool
+	// values holds string keys.
+	values map[string]string
+	down   atomic.Bool
 }
 
 func newMockRedisClient(t *testing.T) *mockRedisClient {
 	t.Helper()
 
 	return &mockRedisClient{
-		lists: make(map[string][]string),
-		zsets: make(map[string][]mockZMember),
-		sets:  make(map[string]map[string]struct{}),
+		lists:  make(map[string][]string),
+		zsets:  make(map[string][]mockZMember),
+		sets:   make(map[string]map[string]struct{}),
+		values: make(map[string]string),
 	}
 }
 
@@ -77,6 +80,65 @@ func (m *mockRedisClient) LPush(_ context.Context, key string, values ...interfa
 	return redis.NewIntResult(int64(len(list)), nil)
 }
 
+func (m *mockRedisClient) RPush(_ context.Context, key string, values ...interface{}) *redis.IntCmd {
+	if m.down.Load() {
+		return redis.NewIntResult(0, errMockRedisUnavailable)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, val := range values {
+		m.lists[key] = append(m.lists[key], normalizeRedisValue(val))
+	}
+	return redis.NewIntResult(int64(len(m.lists[key])), nil)
+}
+
+func (m *mockRedisClient) LPop(_ context.Context, key string) *redis.StringCmd {
+	if m.down.Load() {
+		return redis.NewStringResult("", errMockRedisUnavailable)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := m.lists[key]
+	if len(list) == 0 {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	m.lists[key] = list[1:]
+	return redis.NewStringResult(list[0], nil)
+}
+
+func (m *mockRedisClient) SetNX(_ context.Context, key string, value interface{}, _ time.Duration) *redis.BoolCmd {
+	if m.down.Load() {
+		return redis.NewBoolResult(false, errMockRedisUnavailable)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	m.values[key] = normalizeRedisValue(value)
+	return redis.NewBoolResult(true, nil)
+}
+
+func (m *mockRedisClient) Del(_ context.Context, keys ...string) *redis.IntCmd {
+	if m.down.Load() {
+		return redis.NewIntResult(0, errMockRedisUnavailable)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := m.values[key]; ok {
+			delete(m.values, key)
+			deleted++
+		}
+		if _, ok := m.lists[key]; ok {
+			delete(m.lists, key)
+			deleted++
+		}
+	}
+	return redis.NewIntResult(deleted, nil)
+}
+
 func (m *mockRedisClient) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd {
 	if m.down.Load() {
 		return redis.NewStringSliceResult(nil, errMockRedisUnavailable)
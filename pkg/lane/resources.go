@@ -0,0 +1,176 @@
+package lane
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Resources is an amount of compute resources, either requested by a task or
+// provided by a lane.
+type Resources struct {
+	// CPU is the number of CPU cores; fractions are allowed.
+	CPU float64
+	// MemoryMB is the amount of memory in megabytes.
+	MemoryMB int64
+	// GPU is the number of GPUs.
+	GPU int
+}
+
+// IsZero returns true if no resources are set.
+func (r Resources) IsZero() bool {
+	return r == Resources{}
+}
+
+// Add returns the sum of r and o.
+func (r Resources) Add(o Resources) Resources {
+	return Resources{CPU: r.CPU + o.CPU, MemoryMB: r.MemoryMB + o.MemoryMB, GPU: r.GPU + o.GPU}
+}
+
+// Sub returns r minus o.
+func (r Resources) Sub(o Resources) Resources {
+	return Resources{CPU: r.CPU - o.CPU, MemoryMB: r.MemoryMB - o.MemoryMB, GPU: r.GPU - o.GPU}
+}
+
+// Fits returns true if r does not exceed capacity in any resource.
+func (r Resources) Fits(capacity Resources) bool {
+	return r.CPU <= capacity.CPU && r.MemoryMB <= capacity.MemoryMB && r.GPU <= capacity.GPU
+}
+
+// Validate checks that no resource is negative.
+func (r Resources) Validate() error {
+	if r.CPU < 0 || r.MemoryMB < 0 || r.GPU < 0 {
+		return fmt.Errorf("resources cannot be negative, got %s", r)
+	}
+	return nil
+}
+
+// String returns a human-readable string representation of Resources.
+func (r Resources) String() string {
+	return fmt.Sprintf("cpu=%g memory_mb=%d gpu=%d", r.CPU, r.MemoryMB, r.GPU)
+}
+
+// ResourceTask is implemented by tasks that request resources. Lanes
+// configured with resources start such a task only once its request fits in
+// what is left of the lane's capacity.
+type ResourceTask interface {
+	Task
+
+	// Resources returns the resources the task needs while it runs.
+	Resources() Resources
+}
+
+// taskResources returns the resources requested by task.
+func taskResources(task Task) Resources {
+	if rt, ok := task.(ResourceTask); ok {
+		return rt.Resources()
+	}
+	return Resources{}
+}
+
+// resourcePool tracks the resources of a lane that running tasks hold.
+type resourcePool struct {
+	mu       sync.Mutex
+	capacity Resources
+	used     Resources
+}
+
+// reserve takes request from the pool if it fits in what is free.
+func (p *resourcePool) reserve(request Resources) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.used.Add(request).Fits(p.capacity) {
+		return false
+	}
+	p.used = p.used.Add(request)
+	return true
+}
+
+// release returns request to the pool.
+func (p *resourcePool) release(request Resources) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.used = p.used.Sub(request)
+}
+
+// inUse returns the resources currently held by running tasks.
+func (p *resourcePool) inUse() Resources {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.used
+}
+
+// ResourceCapacity returns the resources the lane provides, and false if the
+// lane does not track resources.
+func (l *ChannelLane) ResourceCapacity() (Resources, bool) {
+	if l.resources == nil {
+		return Resources{}, false
+	}
+	return l.resources.capacity, true
+}
+
+// checkResources rejects tasks whose request exceeds the lane's capacity,
+// since they could never start.
+func (l *ChannelLane) checkResources(task Task) error {
+	if l.resources == nil {
+		return nil
+	}
+	if request := taskResources(task); !request.Fits(l.resources.capacity) {
+		return &ResourceError{LaneName: l.config.Name, TaskID: task.ID(), Requested: request, Capacity: l.resources.capacity}
+	}
+	return nil
+}
+
+// reserveResources takes the resources task requests. It returns false if
+// they are not free yet.
+func (l *ChannelLane) reserveResources(task Task) bool {
+	if l.resources == nil {
+		return true
+	}
+	request := taskResources(task)
+	if request.IsZero() {
+		return true
+	}
+	if !l.resources.reserve(request) {
+		return false
+	}
+	l.recordResourceUsage()
+	return true
+}
+
+// releaseResources returns the resources task held while it ran.
+func (l *ChannelLane) releaseResources(task Task) {
+	if l.resources == nil {
+		return
+	}
+	request := taskResources(task)
+	if request.IsZero() {
+		return
+	}
+	l.resources.release(request)
+	l.recordResourceUsage()
+}
+
+// waitsForResources returns true if task has to wait for free resources
+// before it can be dispatched.
+func (l *ChannelLane) waitsForResources(task Task) bool {
+	return l.resources != nil && !taskResources(task).IsZero()
+}
+
+// recordResourceUsage reports the utilization of every resource the lane
+// provides.
+func (l *ChannelLane) recordResourceUsage() {
+	recorder, ok := l.metrics.(resourceUsageRecorder)
+	if !ok {
+		return
+	}
+	capacity, used := l.resources.capacity, l.resources.inUse()
+	if capacity.CPU > 0 {
+		recorder.RecordResourceUtilization(l.config.Name, "cpu", used.CPU/capacity.CPU)
+	}
+	if capacity.MemoryMB > 0 {
+		recorder.RecordResourceUtilization(l.config.Name, "memory", float64(used.MemoryMB)/float64(capacity.MemoryMB))
+	}
+	if capacity.GPU > 0 {
+		recorder.RecordResourceUtilization(l.config.Name, "gpu", float64(used.GPU)/float64(capacity.GPU))
+	}
+}
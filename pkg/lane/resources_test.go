@@ -0,0 +1,143 @@
+package lane
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingResourceMetrics struct {
+	nopMetrics
+	mu          sync.Mutex
+	utilization map[string]float64
+}
+
+func (r *recordingResourceMetrics) RecordResourceUtilization(laneName, resource string, utilization float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.utilization[resource] = utilization
+}
+
+func (r *recordingResourceMetrics) get(resource string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.utilization[resource]
+}
+
+func TestResources_Fits(t *testing.T) {
+	capacity := Resources{CPU: 4, MemoryMB: 8192}
+	tests := []struct {
+		name    string
+		request Resources
+		want    bool
+	}{
+		{"zero", Resources{}, true},
+		{"exact", Resources{CPU: 4, MemoryMB: 8192}, true},
+		{"cpu", Resources{CPU: 4.5}, false},
+		{"memory", Resources{MemoryMB: 9000}, false},
+		{"gpu without gpus", Resources{GPU: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.request.Fits(capacity); got != tt.want {
+			t.Errorf("%s: Fits() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_ValidateResources(t *testing.T) {
+	cfg := &Config{Name: "res", Capacity: 1, MaxConcurrency: 1, Resources: &Resources{CPU: -1}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected negative resources to be rejected")
+	}
+}
+
+func TestChannelLane_ResourcesLimitConcurrency(t *testing.T) {
+	metrics := &recordingResourceMetrics{utilization: make(map[string]float64)}
+	l, err := New(&Config{Name: "res", Capacity: 10, MaxConcurrency: 4, Resources: &Resources{CPU: 2, GPU: 1}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+	l.SetMetrics(metrics)
+	l.Run()
+
+	release := make(chan struct{})
+	started := make(chan string, 3)
+	task := func(id string, request Resources) *TaskFunc {
+		return NewTaskFunc(id, "res", 0, func(context.Context) error {
+			started <- id
+			<-release
+			return nil
+		}).WithResources(request)
+	}
+
+	if err := l.Submit(context.Background(), task("gpu-1", Resources{CPU: 1, GPU: 1})); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+	if err := l.Submit(context.Background(), task("gpu-2", Resources{CPU: 1, GPU: 1})); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	select {
+	case id := <-started:
+		t.Fatalf("expected %s to wait for the GPU", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+	stats := l.Stats()
+	if stats.ResourcesInUse != (Resources{CPU: 1, GPU: 1}) {
+		t.Fatalf("ResourcesInUse = %+v, want cpu=1 gpu=1", stats.ResourcesInUse)
+	}
+	if got := metrics.get("gpu"); got != 1 {
+		t.Fatalf("gpu utilization = %v, want 1", got)
+	}
+	if got := metrics.get("cpu"); got != 0.5 {
+		t.Fatalf("cpu utilization = %v, want 0.5", got)
+	}
+
+	close(release)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the second task")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Stats().Completed < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for tasks to complete")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := l.Stats().ResourcesInUse; !got.IsZero() {
+		t.Fatalf("ResourcesInUse = %+v after completion, want zero", got)
+	}
+}
+
+func TestChannelLane_ResourcesExceedCapacity(t *testing.T) {
+	l, err := New(&Config{Name: "res", Capacity: 10, MaxConcurrency: 2, Resources: &Resources{CPU: 2, MemoryMB: 1024}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.Close(context.Background())
+
+	noop := func(context.Context) error { return nil }
+	var resErr *ResourceError
+	err = l.Submit(context.Background(), NewTaskFunc("gpu", "res", 0, noop).WithResources(Resources{GPU: 1}))
+	if !errors.As(err, &resErr) || resErr.TaskID != "gpu" {
+		t.Fatalf("expected resource error, got %v", err)
+	}
+
+	group := NewTaskGroup("group", "res", 0,
+		NewTaskFunc("a", "res", 0, noop).WithResources(Resources{MemoryMB: 600}),
+		NewTaskFunc("b", "res", 0, noop).WithResources(Resources{MemoryMB: 600}),
+	)
+	if l.TrySubmit(group) {
+		t.Fatal("expected group exceeding the memory capacity to be rejected")
+	}
+	if got := l.Stats().Rejected; got != 2 {
+		t.Fatalf("Rejected = %d, want 2", got)
+	}
+}
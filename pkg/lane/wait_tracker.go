@@ -0,0 +1,95 @@
+package lane
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/stats"
+)
+
+// waitSampleSize is the number of recent queue wait times kept per lane for
+// percentile estimation.
+const waitSampleSize = 512
+
+// waitTracker records how long tasks waited in a lane's queue. The average
+// covers every task; percentiles are computed over the most recent samples.
+type waitTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+	total   time.Duration
+	count   int64
+}
+
+// waitSnapshot is a point-in-time summary of a waitTracker.
+type waitSnapshot struct {
+	avg time.Duration
+	p50 time.Duration
+	p95 time.Duration
+	p99 time.Duration
+}
+
+func newWaitTracker() *waitTracker {
+	return &waitTracker{samples: make([]time.Duration, waitSampleSize)}
+}
+
+// observe records one queue wait. A nil tracker ignores observations.
+func (t *waitTracker) observe(d time.Duration) {
+	if t == nil {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % len(t.samples)
+	if t.next == 0 {
+		t.full = true
+	}
+	t.total += d
+	t.count++
+}
+
+// snapshot returns the average and recent percentiles. It is zero when no
+// task has been dequeued yet.
+func (t *waitTracker) snapshot() waitSnapshot {
+	if t == nil {
+		return waitSnapshot{}
+	}
+
+	t.mu.Lock()
+	n := t.next
+	if t.full {
+		n = len(t.samples)
+	}
+	recent := make([]time.Duration, n)
+	copy(recent, t.samples[:n])
+	total, count := t.total, t.count
+	t.mu.Unlock()
+
+	if count == 0 {
+		return waitSnapshot{}
+	}
+
+	sort.Slice(recent, func(i, j int) bool { return recent[i] < recent[j] })
+	return waitSnapshot{
+		avg: total / time.Duration(count),
+		p50: stats.Percentile(recent, 0.50),
+		p95: stats.Percentile(recent, 0.95),
+		p99: stats.Percentile(recent, 0.99),
+	}
+}
+
+// apply fills the wait fields of stats.
+func (s waitSnapshot) apply(stats *Stats) {
+	stats.WaitTime = s.avg
+	stats.WaitP50 = s.p50
+	stats.WaitP95 = s.p95
+	stats.WaitP99 = s.p99
+}
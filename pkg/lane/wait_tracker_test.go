@@ -0,0 +1,98 @@
+package lane
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWaitTracker_Percentiles(t *testing.T) {
+	tracker := newWaitTracker()
+	if snap := tracker.snapshot(); snap != (waitSnapshot{}) {
+		t.Fatalf("expected empty snapshot, got %+v", snap)
+	}
+
+	for i := 1; i <= 100; i++ {
+		tracker.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	snap := tracker.snapshot()
+	if snap.p50 != 50*time.Millisecond {
+		t.Errorf("p50 = %v, want 50ms", snap.p50)
+	}
+	if snap.p95 != 95*time.Millisecond {
+		t.Errorf("p95 = %v, want 95ms", snap.p95)
+	}
+	if snap.p99 != 99*time.Millisecond {
+		t.Errorf("p99 = %v, want 99ms", snap.p99)
+	}
+	if want := 50500 * time.Microsecond; snap.avg != want {
+		t.Errorf("avg = %v, want %v", snap.avg, want)
+	}
+}
+
+func TestWaitTracker_KeepsRecentSamples(t *testing.T) {
+	tracker := newWaitTracker()
+	for i := 0; i < waitSampleSize; i++ {
+		tracker.observe(time.Second)
+	}
+	for i := 0; i < waitSampleSize; i++ {
+		tracker.observe(time.Millisecond)
+	}
+
+	snap := tracker.snapshot()
+	if snap.p99 != time.Millisecond {
+		t.Errorf("expected old samples to be overwritten, p99 = %v", snap.p99)
+	}
+	if snap.avg <= time.Millisecond {
+		t.Errorf("expected average over all samples, got %v", snap.avg)
+	}
+
+	var nilTracker *waitTracker
+	nilTracker.observe(time.Second)
+	if snap := nilTracker.snapshot(); snap != (waitSnapshot{}) {
+		t.Fatalf("expected empty snapshot from nil tracker, got %+v", snap)
+	}
+}
+
+func TestChannelLane_StatsWaitTimes(t *testing.T) {
+	lane, err := New(&Config{
+		Name:           "waits",
+		Capacity:       10,
+		MaxConcurrency: 1,
+		Backpressure:   Block,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create lane: %v", err)
+	}
+	defer lane.Close(context.Background())
+	lane.Run()
+
+	done := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		task := NewTaskFunc(fmt.Sprintf("task-%d", i), "waits", 1, func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			done <- struct{}{}
+			return nil
+		})
+		if err := lane.Submit(context.Background(), task); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for tasks")
+		}
+	}
+
+	stats := lane.Stats()
+	if stats.WaitP99 < 20*time.Millisecond {
+		t.Errorf("expected the last task to wait behind the others, p99 = %v", stats.WaitP99)
+	}
+	if stats.WaitP50 > stats.WaitP99 || stats.WaitTime <= 0 {
+		t.Errorf("unexpected wait stats: avg=%v p50=%v p99=%v", stats.WaitTime, stats.WaitP50, stats.WaitP99)
+	}
+}
@@ -11,6 +11,12 @@ type WorkerPool struct {
 	taskCh     chan Task
 	workerFn   func(Task)
 
+	// Resizing
+	resizeMu sync.Mutex
+	workers  int
+	nextID   int
+	retireCh chan struct{}
+
 	// State
 	running  atomic.Bool
 	stopCh   chan struct{}
@@ -28,6 +34,7 @@ func NewWorkerPool(maxWorkers int, workerFn func(Task)) *WorkerPool {
 		taskCh:     make(chan Task),
 		workerFn:   workerFn,
 		stopCh:     make(chan struct{}),
+		retireCh:   make(chan struct{}),
 	}
 }
 
@@ -40,10 +47,56 @@ func (p *WorkerPool) Start() {
 	p.running.Store(true)
 
 	// Start workers
+	p.resizeMu.Lock()
 	for i := 0; i < p.maxWorkers; i++ {
-		p.wg.Add(1)
-		go p.worker(i)
+		p.spawnLocked()
 	}
+	p.resizeMu.Unlock()
+}
+
+// Resize changes the number of workers while the pool is running.
+// Surplus workers retire after finishing their current task.
+func (p *WorkerPool) Resize(workers int) {
+	if workers <= 0 || !p.running.Load() {
+		return
+	}
+
+	p.resizeMu.Lock()
+	defer p.resizeMu.Unlock()
+
+	for p.workers < workers {
+		p.spawnLocked()
+	}
+	retire := p.workers - workers
+	p.workers = workers
+	p.maxWorkers = workers
+
+	if retire > 0 {
+		go func() {
+			for i := 0; i < retire; i++ {
+				select {
+				case p.retireCh <- struct{}{}:
+				case <-p.stopCh:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Workers returns the target number of workers.
+func (p *WorkerPool) Workers() int {
+	p.resizeMu.Lock()
+	defer p.resizeMu.Unlock()
+	return p.workers
+}
+
+// spawnLocked starts one worker. The caller must hold resizeMu.
+func (p *WorkerPool) spawnLocked() {
+	p.wg.Add(1)
+	go p.worker(p.nextID)
+	p.nextID++
+	p.workers++
 }
 
 // Stop gracefully stops the worker pool.
@@ -85,6 +138,11 @@ func (p *WorkerPool) TrySubmit(task Task) bool {
 	}
 }
 
+// dispatch returns the channel workers receive tasks from.
+func (p *WorkerPool) dispatch() chan<- Task {
+	return p.taskCh
+}
+
 // worker is the main loop for each worker goroutine.
 func (p *WorkerPool) worker(id int) {
 	defer p.wg.Done()
@@ -96,6 +154,8 @@ func (p *WorkerPool) worker(id int) {
 				return
 			}
 			p.processTask(task)
+		case <-p.retireCh:
+			return
 		case <-p.stopCh:
 			// Process remaining tasks in the channel
 			for {
@@ -0,0 +1,200 @@
+// Package llmcache caches the responses of LLM calls by their content, so
+// that agent steps repeating a prompt across runs reuse the earlier response
+// instead of paying its latency and cost again.
+package llmcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// DefaultTTL is how long responses are reused unless configured otherwise.
+const DefaultTTL = 24 * time.Hour
+
+// errCallAborted is the error waiters get when the call they waited for
+// panicked.
+var errCallAborted = errors.New("llm call aborted")
+
+// Request is an LLM call as far as its response depends on it.
+type Request struct {
+	// Model names the provider's model, e.g. gpt-4o-mini.
+	Model string
+
+	// Prompt is the prompt, or the messages of a chat call encoded by the
+	// caller.
+	Prompt string
+
+	// Params are the sampling parameters, e.g. temperature and max_tokens.
+	Params map[string]any
+}
+
+// Key returns the content address of req: the SHA-256 of its model and its
+// prompt with their ends trimmed, and its params as canonical JSON.
+// Whitespace inside the prompt is kept, as it can change the response, e.g.
+// of code or tables.
+func Key(req Request) (string, error) {
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		return "", errs.Wrap(err, errs.BadRequest, "encode llm params")
+	}
+	h := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(strings.TrimSpace(req.Model)),
+		[]byte(strings.TrimSpace(req.Prompt)),
+		params,
+	} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Store keeps cached responses by key.
+type Store interface {
+	// Get returns the response stored under key, reporting false when
+	// there is none or it expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Recorder counts cache lookups, e.g. as Prometheus metrics.
+type Recorder interface {
+	RecordLLMCacheLookup(model string, hit bool)
+}
+
+// Stats are the lookups of a cache since it was created.
+type Stats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Cache caches LLM responses in a Store.
+type Cache struct {
+	store    Store
+	ttl      time.Duration
+	recorder Recorder
+
+	hits   atomic.Int64
+	misses atomic.Int64
+
+	// calls holds the calls in flight by key, so that concurrent misses of
+	// the same request make it once.
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithRecorder counts the lookups of the cache with r.
+func WithRecorder(r Recorder) Option {
+	return func(c *Cache) {
+		c.recorder = r
+	}
+}
+
+// New returns a cache keeping responses in store for ttl. Zero uses
+// DefaultTTL.
+func New(store Store, ttl time.Duration, opts ...Option) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	c := &Cache{store: store, ttl: ttl, calls: make(map[string]*call)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Do returns the cached response of req, or makes the call with fn and
+// caches its response when it succeeds. It reports whether the response came
+// from the cache. A store that fails is bypassed rather than failing the
+// call.
+func (c *Cache) Do(ctx context.Context, req Request, fn func(context.Context) ([]byte, error)) ([]byte, bool, error) {
+	key, err := Key(req)
+	if err != nil {
+		return nil, false, err
+	}
+	if value, ok, err := c.store.Get(ctx, key); err == nil && ok {
+		c.record(req.Model, true)
+		return value, true, nil
+	}
+
+	c.mu.Lock()
+	for {
+		inflight, ok := c.calls[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+		select {
+		case <-inflight.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if inflight.err == nil {
+			c.record(req.Model, true)
+			return inflight.value, true, nil
+		}
+		// The call failed for its caller; make ours unless another waiter
+		// already is.
+		c.mu.Lock()
+	}
+	inflight := &call{done: make(chan struct{}), err: errCallAborted}
+	c.calls[key] = inflight
+	c.mu.Unlock()
+	// Waiters are released even when fn panics.
+	defer func() {
+		c.mu.Lock()
+		if c.calls[key] == inflight {
+			delete(c.calls, key)
+		}
+		c.mu.Unlock()
+		close(inflight.done)
+	}()
+
+	c.record(req.Model, false)
+	inflight.value, inflight.err = fn(ctx)
+	if inflight.err == nil {
+		_ = c.store.Set(ctx, key, inflight.value, c.ttl)
+	}
+	return inflight.value, false, inflight.err
+}
+
+// Stats returns the lookups of c so far.
+func (c *Cache) Stats() Stats {
+	stats := Stats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (c *Cache) record(model string, hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	if c.recorder != nil {
+		c.recorder.RecordLLMCacheLookup(model, hit)
+	}
+}
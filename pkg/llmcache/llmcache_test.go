@@ -0,0 +1,168 @@
+package llmcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+func TestKey_NormalizesPrompt(t *testing.T) {
+	base := Request{Model: "gpt-4o-mini", Prompt: "Summarize\n  the report.", Params: map[string]any{"temperature": 0, "max_tokens": 256}}
+	same := Request{Model: " gpt-4o-mini", Prompt: "\n Summarize\n  the report. ", Params: map[string]any{"max_tokens": 256, "temperature": 0}}
+	a, err := Key(base)
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if b, _ := Key(same); a != b {
+		t.Fatalf("keys of equivalent requests differ: %s != %s", a, b)
+	}
+	for _, other := range []Request{
+		{Model: "gpt-4o", Prompt: base.Prompt, Params: base.Params},
+		{Model: base.Model, Prompt: "Summarize the reports.", Params: base.Params},
+		{Model: base.Model, Prompt: "Summarize the report.", Params: base.Params},
+		{Model: base.Model, Prompt: base.Prompt, Params: map[string]any{"temperature": 0.7, "max_tokens": 256}},
+	} {
+		if b, _ := Key(other); a == b {
+			t.Fatalf("key of %+v equals the key of %+v", other, base)
+		}
+	}
+}
+
+type countingRecorder struct {
+	mu   sync.Mutex
+	hits map[bool]int
+}
+
+func (r *countingRecorder) RecordLLMCacheLookup(model string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits[hit]++
+}
+
+func TestCache_Do(t *testing.T) {
+	recorder := &countingRecorder{hits: map[bool]int{}}
+	c := New(NewMemoryStore(0), time.Minute, WithRecorder(recorder))
+	ctx := context.Background()
+	req := Request{Model: "gpt-4o-mini", Prompt: "hello"}
+
+	var calls atomic.Int32
+	fn := func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return []byte("hi"), nil
+	}
+
+	// Concurrent misses of one request make a single call.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, _, err := c.Do(ctx, req, fn); err != nil || string(value) != "hi" {
+				t.Errorf("Do() = %q, %v", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+	value, hit, err := c.Do(ctx, req, fn)
+	if err != nil || !hit || string(value) != "hi" {
+		t.Fatalf("Do() = %q, %v, %v, want a cached hi", value, hit, err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+	if stats := c.Stats(); stats.Misses != 1 || stats.Hits != 4 || stats.HitRate != 0.8 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if recorder.hits[true] != 4 || recorder.hits[false] != 1 {
+		t.Fatalf("recorded lookups = %v", recorder.hits)
+	}
+
+	// Failed calls are not cached.
+	failing := Request{Model: "gpt-4o-mini", Prompt: "fail"}
+	boom := errors.New("rate limited")
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Do(ctx, failing, func(context.Context) ([]byte, error) { return nil, boom }); !errors.Is(err, boom) {
+			t.Fatalf("Do() error = %v, want %v", err, boom)
+		}
+	}
+	if stats := c.Stats(); stats.Misses != 3 {
+		t.Fatalf("misses = %d, want 3", stats.Misses)
+	}
+
+	// A call that panics releases its waiters, which then make their own.
+	panicking := Request{Model: "gpt-4o-mini", Prompt: "panic"}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer func() { _ = recover() }()
+		_, _, _ = c.Do(ctx, panicking, func(context.Context) ([]byte, error) {
+			close(started)
+			<-release
+			panic("provider client bug")
+		})
+	}()
+	<-started
+	waited := make(chan error, 1)
+	go func() {
+		value, _, err := c.Do(ctx, panicking, func(context.Context) ([]byte, error) { return []byte("ok"), nil })
+		if err == nil && string(value) != "ok" {
+			err = errors.New("unexpected value " + string(value))
+		}
+		waited <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatalf("Do() after a panicking call error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter of a panicking call hangs")
+	}
+}
+
+func TestMemoryStore_ExpiresAndEvicts(t *testing.T) {
+	s := NewMemoryStore(2)
+	ctx := context.Background()
+	_ = s.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = s.Set(ctx, "b", []byte("2"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok, _ := s.Get(ctx, "b"); ok {
+		t.Fatal("expected b to have expired")
+	}
+	_ = s.Set(ctx, "c", []byte("3"), time.Minute)
+	_ = s.Set(ctx, "d", []byte("4"), time.Minute)
+	if _, ok, _ := s.Get(ctx, "a"); ok {
+		t.Fatal("expected a to have been evicted")
+	}
+	if value, ok, _ := s.Get(ctx, "d"); !ok || string(value) != "4" {
+		t.Fatalf("Get(d) = %q, %v", value, ok)
+	}
+}
+
+func TestBadgerStore(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("open badger: %v", err)
+	}
+	defer db.Close()
+	s := NewBadgerStore(db, "llmcache:")
+	ctx := context.Background()
+
+	if _, ok, err := s.Get(ctx, "k"); ok || err != nil {
+		t.Fatalf("Get() of a missing key = %v, %v", ok, err)
+	}
+	if err := s.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, ok, err := s.Get(ctx, "k"); !ok || err != nil || string(value) != "v" {
+		t.Fatalf("Get() = %q, %v, %v", value, ok, err)
+	}
+}
@@ -0,0 +1,152 @@
+package llmcache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultMaxEntries bounds the memory store unless configured otherwise.
+const DefaultMaxEntries = 10000
+
+// MemoryStore keeps responses in process, evicting the least recently used
+// beyond its capacity.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	items      map[string]*list.Element
+	eviction   *list.List
+}
+
+type memoryItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns a memory store of at most maxEntries responses.
+// Zero uses DefaultMaxEntries.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		eviction:   list.New(),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := elem.Value.(*memoryItem)
+	if time.Now().After(item.expires) {
+		s.eviction.Remove(elem)
+		delete(s.items, key)
+		return nil, false, nil
+	}
+	s.eviction.MoveToFront(elem)
+	return item.value, true, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := &memoryItem{key: key, value: value, expires: time.Now().Add(ttl)}
+	if elem, ok := s.items[key]; ok {
+		elem.Value = item
+		s.eviction.MoveToFront(elem)
+		return nil
+	}
+	s.items[key] = s.eviction.PushFront(item)
+	for s.eviction.Len() > s.maxEntries {
+		oldest := s.eviction.Back()
+		s.eviction.Remove(oldest)
+		delete(s.items, oldest.Value.(*memoryItem).key)
+	}
+	return nil
+}
+
+// BadgerStore keeps responses in a Badger database, which expires them.
+type BadgerStore struct {
+	db     *badger.DB
+	prefix []byte
+}
+
+// NewBadgerStore returns a store keeping responses in db under keys starting
+// with prefix.
+func NewBadgerStore(db *badger.DB, prefix string) *BadgerStore {
+	return &BadgerStore{db: db, prefix: []byte(prefix)}
+}
+
+// Get implements Store.
+func (s *BadgerStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(s.key(key))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements Store.
+func (s *BadgerStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry(s.key(key), value).WithTTL(ttl))
+	})
+}
+
+func (s *BadgerStore) key(key string) []byte {
+	return append(append([]byte(nil), s.prefix...), key...)
+}
+
+// RedisStore keeps responses in Redis, shared by all nodes using it.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a store keeping responses in client under keys
+// starting with prefix.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements Store.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, ttl).Err()
+}
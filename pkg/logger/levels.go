@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Levels holds the level of a logger and of its components. Components
+// follow the logger's level until their own is set.
+type Levels struct {
+	root *slog.LevelVar
+
+	mu         sync.Mutex
+	components map[string]*componentLevel
+}
+
+// ComponentLevel is the level of a component logger.
+type ComponentLevel struct {
+	Name  string
+	Level Level
+	// Overridden reports whether the level was set for the component rather
+	// than inherited from the logger.
+	Overridden bool
+}
+
+// componentLevel is the slog.Leveler of a component.
+type componentLevel struct {
+	root       *slog.LevelVar
+	level      slog.LevelVar
+	overridden atomic.Bool
+}
+
+func (c *componentLevel) Level() slog.Level {
+	if c.overridden.Load() {
+		return c.level.Level()
+	}
+	return c.root.Level()
+}
+
+func newLevels(level Level) *Levels {
+	v := &Levels{root: &slog.LevelVar{}, components: make(map[string]*componentLevel)}
+	v.root.Set(slogLevel(level))
+	return v
+}
+
+// Level returns the level of the logger.
+func (v *Levels) Level() Level {
+	return levelFromSlog(v.root.Level())
+}
+
+// SetLevel changes the level of the logger and of the components without
+// their own level.
+func (v *Levels) SetLevel(level Level) {
+	v.root.Set(slogLevel(level))
+}
+
+// Component returns the effective level of the named component.
+func (v *Levels) Component(name string) Level {
+	return levelFromSlog(v.component(name).Level())
+}
+
+// SetComponent sets the level of the named component. Components can be set
+// before their logger is created.
+func (v *Levels) SetComponent(name string, level Level) {
+	c := v.component(name)
+	c.level.Set(slogLevel(level))
+	c.overridden.Store(true)
+}
+
+// ResetComponent makes the named component follow the logger's level again.
+func (v *Levels) ResetComponent(name string) {
+	v.component(name).overridden.Store(false)
+}
+
+// Components returns the levels of the known components, sorted by name.
+func (v *Levels) Components() []ComponentLevel {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := make([]ComponentLevel, 0, len(v.components))
+	for name, c := range v.components {
+		out = append(out, ComponentLevel{
+			Name:       name,
+			Level:      levelFromSlog(c.Level()),
+			Overridden: c.overridden.Load(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (v *Levels) component(name string) *componentLevel {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	c, ok := v.components[name]
+	if !ok {
+		c = &componentLevel{root: v.root}
+		v.components[name] = c
+	}
+	return c
+}
+
+// levelHandler drops the records below the level of its leveler before they
+// reach the shared inner handler.
+type levelHandler struct {
+	leveler slog.Leveler
+	inner   slog.Handler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.leveler.Level()
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{leveler: h.leveler, inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{leveler: h.leveler, inner: h.inner.WithGroup(name)}
+}
@@ -3,11 +3,13 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"sync"
 
+	"github.com/goclaw/goclaw/pkg/scrub"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -53,11 +55,37 @@ func ParseLevel(s string) Level {
 	}
 }
 
+// LookupLevel parses a level string, reporting whether it names a level.
+func LookupLevel(s string) (Level, bool) {
+	switch s {
+	case "debug", "info", "warn", "warning", "error":
+		return ParseLevel(s), true
+	default:
+		return InfoLevel, false
+	}
+}
+
 // Config holds logger configuration.
 type Config struct {
 	Level  Level
 	Format string // "json" or "text"
-	Output string // "stdout", "stderr", or file path
+	Output string // "stdout", "stderr", "syslog", "journald", or file path
+
+	// Rotation configures the rotation of file output.
+	Rotation Rotation
+
+	// Syslog configures the syslog and journald outputs.
+	Syslog Syslog
+
+	// Components sets the initial levels of component loggers, overriding
+	// Level for them.
+	Components map[string]Level
+
+	// Sampling thins out repeated debug messages. The zero value logs all.
+	Sampling Sampling
+
+	// Scrubber removes customer data from records before they are written.
+	Scrubber *scrub.Scrubber
 }
 
 // Logger is the interface for structured logging.
@@ -75,6 +103,10 @@ type Logger interface {
 	With(args ...any) Logger
 	WithContext(ctx context.Context) context.Context
 
+	// Component returns a child logger for the named component. Its records
+	// carry a "component" attribute and its level can be changed on its own.
+	Component(name string) Logger
+
 	SetLevel(level Level)
 	GetLevel() Level
 
@@ -85,8 +117,10 @@ type Logger interface {
 // SlogLogger is a Logger implementation using log/slog.
 type SlogLogger struct {
 	logger *slog.Logger
-	level  *slog.LevelVar
-	closer io.Closer // holds the closer for file output, if any
+	levels *Levels
+	// component is the name of the component logged for, if any.
+	component string
+	closer    io.Closer // holds the closer for file output, if any
 }
 
 var (
@@ -115,44 +149,74 @@ func New(cfg *Config) Logger {
 		}
 	}
 
-	levelVar := &slog.LevelVar{}
-	levelVar.Set(slogLevel(cfg.Level))
+	writer, closer := getWriter(cfg)
+	return newSlogLogger(cfg, writer, closer)
+}
 
+func newSlogLogger(cfg *Config, writer io.Writer, closer io.Closer) *SlogLogger {
+	levels := newLevels(cfg.Level)
+	for name, level := range cfg.Components {
+		levels.SetComponent(name, level)
+	}
+
+	// The levels are checked by levelHandler, so that component loggers
+	// sharing the output can have their own.
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{
-		Level:       levelVar,
+		Level:       slog.LevelDebug,
 		AddSource:   true,
 		ReplaceAttr: replaceAttr,
 	}
-
-	writer, closer := getWriter(cfg.Output)
+	if s := cfg.Scrubber; s != nil {
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			return replaceAttr(groups, scrubAttr(s, groups, a))
+		}
+	}
 
 	if cfg.Format == "text" {
 		handler = slog.NewTextHandler(writer, opts)
 	} else {
 		handler = slog.NewJSONHandler(writer, opts)
 	}
+	if out, ok := writer.(*severityWriter); ok {
+		handler = &severityHandler{out: out, inner: handler}
+	}
+	if s := newSampler(cfg.Sampling); s != nil {
+		handler = &samplingHandler{sampler: s, inner: handler}
+	}
 
 	return &SlogLogger{
-		logger: slog.New(handler),
-		level:  levelVar,
+		logger: slog.New(&levelHandler{leveler: levels.root, inner: handler}),
+		levels: levels,
 		closer: closer,
 	}
 }
 
-// getWriter returns an io.Writer and io.Closer for the given output specification.
+// getWriter returns an io.Writer and io.Closer for the output of cfg.
 // The closer may be nil if the output doesn't need explicit closing (e.g., stdout/stderr).
-func getWriter(output string) (io.Writer, io.Closer) {
-	switch output {
+func getWriter(cfg *Config) (io.Writer, io.Closer) {
+	switch cfg.Output {
 	case "stdout":
 		return os.Stdout, nil
 	case "stderr":
 		return os.Stderr, nil
 	case "":
 		return os.Stdout, nil
+	case OutputSyslog, OutputJournald:
+		open := openSyslog
+		if cfg.Output == OutputJournald {
+			open = func(s Syslog) (*severityWriter, error) { return openJournald(journalSocket, s) }
+		}
+		w, err := open(cfg.Syslog)
+		if err != nil {
+			// Fall back to stderr, which service managers capture
+			fmt.Fprintf(os.Stderr, "logger: %v; logging to stderr\n", err)
+			return os.Stderr, nil
+		}
+		return w, w
 	default:
 		// Try to open as file
-		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		f, err := openRotatingFile(cfg.Output, cfg.Rotation)
 		if err != nil {
 			// Fall back to stdout on error
 			return os.Stdout, nil
@@ -177,6 +241,20 @@ func slogLevel(l Level) slog.Level {
 	}
 }
 
+// levelFromSlog converts a slog.Level to our Level.
+func levelFromSlog(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
 // replaceAttr customizes log attribute handling.
 func replaceAttr(groups []string, a slog.Attr) slog.Attr {
 	// Rename "msg" to "message" for consistency with some systems
@@ -233,27 +311,50 @@ func (l *SlogLogger) ErrorContext(ctx context.Context, msg string, args ...any)
 // With returns a new Logger with the given attributes.
 func (l *SlogLogger) With(args ...any) Logger {
 	return &SlogLogger{
-		logger: l.logger.With(args...),
-		level:  l.level,
-		closer: nil, // derived loggers don't own the closer
+		logger:    l.logger.With(args...),
+		levels:    l.levels,
+		component: l.component,
+		closer:    nil, // derived loggers don't own the closer
 	}
 }
 
+// Component returns a child logger for the named component, keeping the
+// attributes of l. Its level follows l's until set with Levels.SetComponent.
+func (l *SlogLogger) Component(name string) Logger {
+	h := l.logger.Handler().(*levelHandler)
+	return &SlogLogger{
+		logger:    slog.New(&levelHandler{leveler: l.levels.component(name), inner: h.inner}).With("component", name),
+		levels:    l.levels,
+		component: name,
+	}
+}
+
+// Levels returns the levels of l, its children and its components.
+func (l *SlogLogger) Levels() *Levels {
+	return l.levels
+}
+
 // WithContext returns a context with the logger attached.
 func (l *SlogLogger) WithContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, loggerKey{}, l)
 }
 
-// SetLevel dynamically changes the logging level.
+// SetLevel dynamically changes the logging level. On a component logger it
+// changes the level of the component.
 func (l *SlogLogger) SetLevel(level Level) {
-	l.level.Set(slogLevel(level))
+	if l.component != "" {
+		l.levels.SetComponent(l.component, level)
+		return
+	}
+	l.levels.SetLevel(level)
 }
 
 // GetLevel returns the current logging level.
 func (l *SlogLogger) GetLevel() Level {
-	// This is a simplification - slog doesn't expose the current level
-	// In production, you'd track this separately
-	return InfoLevel
+	if l.component != "" {
+		return l.levels.Component(l.component)
+	}
+	return l.levels.Level()
 }
 
 // Close closes any resources held by the logger.
@@ -287,6 +388,12 @@ func SetGlobal(l Logger) {
 	})
 }
 
+// Component returns a child logger of the global logger for the named
+// component.
+func Component(name string) Logger {
+	return global.Component(name)
+}
+
 // SetLevel sets the level of the global logger.
 func SetLevel(level Level) {
 	if l, ok := global.(*SlogLogger); ok {
@@ -1,11 +1,17 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/goclaw/goclaw/pkg/scrub"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -84,8 +90,104 @@ func TestSlogLogger_Level(t *testing.T) {
 
 	// Test SetLevel
 	log.SetLevel(DebugLevel)
-	if got := log.GetLevel(); got != InfoLevel { // GetLevel is simplified in implementation
-		t.Logf("GetLevel returned %v; current implementation keeps configured level", got)
+	if got := log.GetLevel(); got != DebugLevel {
+		t.Errorf("GetLevel() = %v, want debug", got)
+	}
+}
+
+func TestSlogLogger_ComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := newSlogLogger(&Config{
+		Level:      InfoLevel,
+		Format:     "json",
+		Components: map[string]Level{"lane": DebugLevel},
+	}, &buf, nil)
+
+	lane := log.With("node", "a").Component("lane")
+	http := log.Component("http")
+	lane.Debug("lane debug")
+	http.Debug("http debug")
+	if out := buf.String(); !strings.Contains(out, `"message":"lane debug"`) || !strings.Contains(out, `"component":"lane"`) || !strings.Contains(out, `"node":"a"`) {
+		t.Fatalf("expected the lane debug record with its attributes, got %s", out)
+	}
+	if strings.Contains(buf.String(), "http debug") {
+		t.Fatal("http debug record logged at info level")
+	}
+
+	// Components without their own level follow the logger's.
+	log.SetLevel(DebugLevel)
+	http.Debug("http debug")
+	if !strings.Contains(buf.String(), "http debug") {
+		t.Fatal("http debug record dropped at debug level")
+	}
+
+	levels := log.Levels()
+	levels.SetComponent("http", ErrorLevel)
+	if got := http.GetLevel(); got != ErrorLevel {
+		t.Errorf("http level = %v, want error", got)
+	}
+	levels.ResetComponent("lane")
+	levels.SetLevel(WarnLevel)
+	got := levels.Components()
+	if len(got) != 2 || got[0] != (ComponentLevel{Name: "http", Level: ErrorLevel, Overridden: true}) ||
+		got[1] != (ComponentLevel{Name: "lane", Level: WarnLevel}) {
+		t.Fatalf("Components() = %+v", got)
+	}
+}
+
+func TestSlogLogger_Scrubbing(t *testing.T) {
+	scrubber, err := scrub.New([]scrub.Rule{
+		{Pattern: `[a-z]+@example\.com`},
+		{Fields: []string{"customer", "request.ssn"}},
+	})
+	if err != nil {
+		t.Fatalf("scrub.New failed: %v", err)
+	}
+	var buf bytes.Buffer
+	log := newSlogLogger(&Config{Level: InfoLevel, Format: "json", Scrubber: scrubber}, &buf, nil)
+
+	log.With("customer", "Jane Doe").Info("notified jane@example.com",
+		"error", errors.New("bounced: jane@example.com"),
+		slog.Group("request", "ssn", 123456789, "id", "req-1"),
+	)
+	out := buf.String()
+	for _, leaked := range []string{"jane@example.com", "Jane Doe", "123456789"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("record leaked %q: %s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, `"message":"notified [REDACTED]"`) || !strings.Contains(out, `"id":"req-1"`) || !strings.Contains(out, `"level":"INFO"`) {
+		t.Fatalf("unexpected record: %s", out)
+	}
+}
+
+func TestSlogLogger_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	log := newSlogLogger(&Config{
+		Level:    DebugLevel,
+		Format:   "json",
+		Sampling: Sampling{Initial: 2, Thereafter: 3, Tick: time.Hour},
+	}, &buf, nil)
+
+	for i := 0; i < 10; i++ {
+		log.Debug("hot path")
+		log.Info("not sampled")
+	}
+	// Records 1, 2, 5 and 8 of the message are logged.
+	if got := strings.Count(buf.String(), "hot path"); got != 4 {
+		t.Errorf("logged %d sampled records, want 4", got)
+	}
+	if got := strings.Count(buf.String(), "not sampled"); got != 10 {
+		t.Errorf("logged %d info records, want 10", got)
+	}
+}
+
+func TestLookupLevel(t *testing.T) {
+	if level, ok := LookupLevel("warning"); !ok || level != WarnLevel {
+		t.Errorf("LookupLevel(warning) = %v, %v", level, ok)
+	}
+	if _, ok := LookupLevel("verbose"); ok {
+		t.Error("LookupLevel(verbose) reported a level")
 	}
 }
 
@@ -265,7 +367,7 @@ func TestGetWriter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, closer := getWriter(tt.output)
+			_, closer := getWriter(&Config{Output: tt.output})
 			if tt.wantCloser && closer == nil {
 				t.Error("expected non-nil closer")
 			}
@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in the names of rotated files. It sorts
+// in time order.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation configures the rotation of file output. The zero value never
+// rotates.
+type Rotation struct {
+	// MaxSize is the size in bytes past which the file is rotated; 0 never
+	// rotates.
+	MaxSize int64
+
+	// MaxAge removes rotated files older than this; 0 keeps them.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files kept; 0 keeps all.
+	MaxBackups int
+
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+// rotatingFile is a log file that is renamed to <name>-<time><ext> and
+// reopened once it grows past the configured size.
+type rotatingFile struct {
+	path     string
+	rotation Rotation
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	// cleanup serializes the compression and removal of rotated files;
+	// cleanups tracks the ones in flight.
+	cleanup  sync.Mutex
+	cleanups sync.WaitGroup
+	now      func() time.Time
+}
+
+func openRotatingFile(path string, rotation Rotation) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, rotation: rotation, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes p to the file, rotating it first if p would take it past
+// the maximum size. A record is never split across files.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.rotation.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.rotation.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file, opens a new one and cleans up the rotated
+// files in the background. The caller holds f.mu.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.cleanups.Add(1)
+	go func() {
+		defer f.cleanups.Done()
+		f.cleanupBackups(backup)
+	}()
+	return nil
+}
+
+func (f *rotatingFile) backupName(t time.Time) string {
+	dir, name := filepath.Split(f.path)
+	ext := filepath.Ext(name)
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", strings.TrimSuffix(name, ext), t.UTC().Format(backupTimeFormat), ext))
+}
+
+// cleanupBackups compresses the newest rotated file if configured, then
+// removes the rotated files past MaxBackups or older than MaxAge.
+func (f *rotatingFile) cleanupBackups(newest string) {
+	f.cleanup.Lock()
+	defer f.cleanup.Unlock()
+
+	if f.rotation.Compress {
+		if err := compressFile(newest); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: compress %s: %v\n", newest, err)
+		}
+	}
+
+	backups := f.backups()
+	cutoff := time.Time{}
+	if f.rotation.MaxAge > 0 {
+		cutoff = f.now().Add(-f.rotation.MaxAge)
+	}
+	for i, b := range backups {
+		expired := !cutoff.IsZero() && b.at.Before(cutoff)
+		excess := f.rotation.MaxBackups > 0 && i >= f.rotation.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "logger: remove %s: %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	at   time.Time
+}
+
+// backups returns the rotated files of f, newest first.
+func (f *rotatingFile) backups() []backupFile {
+	dir, name := filepath.Split(f.path)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "-"
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []backupFile
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		stamp, ok = strings.CutSuffix(stamp, ext)
+		if !ok {
+			continue
+		}
+		at, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		out = append(out, backupFile{path: filepath.Join(dir, entry.Name()), at: at})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].at.After(out[j].at) })
+	return out
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		zw.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Close closes the file. Rotated files still being cleaned up are finished
+// first.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cleanups.Wait()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
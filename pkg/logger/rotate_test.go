@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_RotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goclaw.log")
+	f, err := openRotatingFile(path, Rotation{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, line := range []string{"record-1\n", "record-2\n", "record-3\n", "record-4\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(current) != "record-4\n" {
+		t.Fatalf("current file = %q, want the last record", current)
+	}
+	backups := f.backups()
+	if len(backups) != 2 {
+		t.Fatalf("backups = %+v, want the 2 newest", backups)
+	}
+	newest, err := os.ReadFile(backups[0].path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(newest) != "record-3\n" {
+		t.Fatalf("newest backup = %q, want record-3", newest)
+	}
+	if !strings.HasPrefix(filepath.Base(backups[0].path), "goclaw-2026-01-02T03-04-") {
+		t.Fatalf("backup name = %s, want goclaw-<time>.log", backups[0].path)
+	}
+}
+
+func TestRotatingFile_CompressesAndExpires(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goclaw.log")
+	// A rotated file from a previous run, long past MaxAge.
+	old := filepath.Join(dir, "goclaw-2020-01-01T00-00-00.000.log.gz")
+	if err := os.WriteFile(old, nil, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	f, err := openRotatingFile(path, Rotation{MaxSize: 10, MaxAge: 24 * time.Hour, Compress: true})
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	for _, line := range []string{"record-1\n", "record-2\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expired backup still exists: %v", err)
+	}
+	backups := f.backups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0].path, ".log.gz") {
+		t.Fatalf("backups = %+v, want one compressed backup", backups)
+	}
+	gz, err := os.Open(backups[0].path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(content) != "record-1\n" {
+		t.Fatalf("compressed backup = %q, want record-1", content)
+	}
+}
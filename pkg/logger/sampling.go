@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultSamplingTick is the sampling period unless configured otherwise.
+const defaultSamplingTick = time.Second
+
+// Sampling thins out repeated debug messages: per Tick, the first Initial
+// records with the same message are logged, then every Thereafter-th.
+// Sampling is off while Initial is 0. Info and higher are never sampled.
+type Sampling struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// sampler counts the debug records per message in the current tick.
+type sampler struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+func newSampler(cfg Sampling) *sampler {
+	if cfg.Initial <= 0 {
+		return nil
+	}
+	s := &sampler{
+		initial:    cfg.Initial,
+		thereafter: cfg.Thereafter,
+		tick:       cfg.Tick,
+		counts:     make(map[string]int),
+	}
+	if s.tick <= 0 {
+		s.tick = defaultSamplingTick
+	}
+	return s
+}
+
+// allow reports whether the n-th record of msg in the current tick is logged.
+func (s *sampler) allow(msg string, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if at.Sub(s.start) >= s.tick {
+		s.start = at
+		clear(s.counts)
+	}
+	s.counts[msg]++
+	n := s.counts[msg]
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// samplingHandler drops the debug records its sampler does not allow.
+type samplingHandler struct {
+	sampler *sampler
+	inner   slog.Handler
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo && !h.sampler.allow(r.Message, r.Time) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{sampler: h.sampler, inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{sampler: h.sampler, inner: h.inner.WithGroup(name)}
+}
@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"log/slog"
+
+	"github.com/goclaw/goclaw/pkg/scrub"
+)
+
+// scrubAttr scrubs the value of a at the field path of its groups and key.
+// Messages are scrubbed by the rules applying to all fields; the time,
+// level and source of records are left alone.
+func scrubAttr(s *scrub.Scrubber, groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.MessageKey:
+			return slog.String(a.Key, s.String(a.Value.String()))
+		case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+			return a
+		}
+	}
+	path := append(groups[:len(groups):len(groups)], a.Key)
+	a.Value = slog.AnyValue(s.Value(path, a.Value.Any()))
+	return a
+}
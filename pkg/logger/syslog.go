@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// OutputSyslog is the Config.Output that writes to syslog.
+	OutputSyslog = "syslog"
+	// OutputJournald is the Config.Output that writes to the systemd journal.
+	OutputJournald = "journald"
+)
+
+// journalSocket is the socket of the native journald protocol.
+const journalSocket = "/run/systemd/journal/socket"
+
+// Syslog configures the syslog and journald outputs.
+type Syslog struct {
+	// Network and Address name a remote syslog server, such as "udp" and
+	// "logs.example.com:514". Empty values use the local syslog daemon.
+	Network string
+	Address string
+
+	// Tag identifies the program in syslog records and is the journal's
+	// SYSLOG_IDENTIFIER. It defaults to the program name.
+	Tag string
+
+	// Facility is the syslog facility, such as daemon or local0. It
+	// defaults to daemon.
+	Facility string
+}
+
+func (s Syslog) tag() string {
+	if s.Tag != "" {
+		return s.Tag
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// severityWriter is an output that needs the level of each record, such as
+// syslog. severityHandler sets the level before the formatting handler
+// writes the record.
+type severityWriter struct {
+	mu    sync.Mutex
+	level slog.Level
+	write func(level slog.Level, msg []byte) error
+	close func() error
+}
+
+// Write writes one formatted record at the current level. The caller holds
+// w.mu.
+func (w *severityWriter) Write(p []byte) (int, error) {
+	if err := w.write(w.level, bytes.TrimSuffix(p, []byte("\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *severityWriter) Close() error {
+	return w.close()
+}
+
+// severityHandler passes the level of each record to its severityWriter.
+type severityHandler struct {
+	out   *severityWriter
+	inner slog.Handler
+}
+
+func (h *severityHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *severityHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	h.out.level = r.Level
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *severityHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &severityHandler{out: h.out, inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *severityHandler) WithGroup(name string) slog.Handler {
+	return &severityHandler{out: h.out, inner: h.inner.WithGroup(name)}
+}
+
+// syslogSeverity returns the syslog severity of a level.
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// openJournald connects to the journal at socket using the native protocol,
+// which keeps the level of each record as its priority.
+func openJournald(socket string, cfg Syslog) (*severityWriter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("connect to journald: %w", err)
+	}
+	identifier := cfg.tag()
+	var buf bytes.Buffer
+	return &severityWriter{
+		write: func(level slog.Level, msg []byte) error {
+			buf.Reset()
+			fmt.Fprintf(&buf, "PRIORITY=%d\nSYSLOG_IDENTIFIER=%s\n", syslogSeverity(level), identifier)
+			// MESSAGE uses the binary form, which allows newlines in the value.
+			buf.WriteString("MESSAGE\n")
+			_ = binary.Write(&buf, binary.LittleEndian, uint64(len(msg)))
+			buf.Write(msg)
+			buf.WriteByte('\n')
+			_, err := conn.Write(buf.Bytes())
+			return err
+		},
+		close: conn.Close,
+	}, nil
+}
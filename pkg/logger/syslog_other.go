@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package logger
+
+import "errors"
+
+// openSyslog reports that syslog is not available on this platform.
+func openSyslog(cfg Syslog) (*severityWriter, error) {
+	return nil, errors.New("syslog output is not supported on this platform")
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournald_WritesPriorityAndMessage(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	out, err := openJournald(socket, Syslog{Tag: "goclaw-test"})
+	if err != nil {
+		t.Fatalf("openJournald() error = %v", err)
+	}
+	log := newSlogLogger(&Config{Level: InfoLevel, Format: "text"}, out, out)
+	defer log.Close()
+	log.Warn("lane backlog", "lane", "default")
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	datagram := buf[:n]
+	header, body, ok := bytes.Cut(datagram, []byte("MESSAGE\n"))
+	if !ok {
+		t.Fatalf("datagram = %q, want a MESSAGE field", datagram)
+	}
+	if string(header) != "PRIORITY=4\nSYSLOG_IDENTIFIER=goclaw-test\n" {
+		t.Fatalf("fields = %q, want warning priority and identifier", header)
+	}
+	size := binary.LittleEndian.Uint64(body[:8])
+	msg := string(body[8 : 8+size])
+	if !strings.Contains(msg, "message=\"lane backlog\"") || strings.HasSuffix(msg, "\n") {
+		t.Fatalf("message = %q, want the formatted record without newline", msg)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"log/syslog"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// openSyslog connects to the syslog daemon named by cfg.
+func openSyslog(cfg Syslog) (*severityWriter, error) {
+	facility := syslog.LOG_DAEMON
+	if cfg.Facility != "" {
+		f, ok := syslogFacilities[cfg.Facility]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", cfg.Facility)
+		}
+		facility = f
+	}
+	w, err := syslog.Dial(cfg.Network, cfg.Address, facility|syslog.LOG_INFO, cfg.tag())
+	if err != nil {
+		return nil, fmt.Errorf("connect to syslog: %w", err)
+	}
+	return &severityWriter{
+		write: func(level slog.Level, msg []byte) error {
+			switch syslogSeverity(level) {
+			case 3:
+				return w.Err(string(msg))
+			case 4:
+				return w.Warning(string(msg))
+			case 6:
+				return w.Info(string(msg))
+			default:
+				return w.Debug(string(msg))
+			}
+		},
+		close: w.Close,
+	}, nil
+}
@@ -0,0 +1,39 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslog_WritesFacilityAndSeverity(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	log := New(&Config{
+		Level:  DebugLevel,
+		Format: "text",
+		Output: OutputSyslog,
+		Syslog: Syslog{Network: "udp", Address: conn.LocalAddr().String(), Tag: "goclaw", Facility: "local0"},
+	})
+	defer log.Close()
+	log.Error("task failed", "task_id", "t1")
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	msg := string(buf[:n])
+	// local0 (16) * 8 + err (3)
+	if !strings.HasPrefix(msg, "<131>") || !strings.Contains(msg, "goclaw[") || !strings.Contains(msg, "task failed") {
+		t.Fatalf("syslog message = %q, want local0.err from goclaw", msg)
+	}
+}
@@ -0,0 +1,249 @@
+package manage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// Backfill limits.
+const (
+	// defaultMaxParallelBackfill is the number of runs of a backfill
+	// unfinished at once unless configured otherwise.
+	defaultMaxParallelBackfill = 4
+	// maxBackfillRuns bounds the runs of one backfill.
+	maxBackfillRuns = 1000
+	// keptBackfills is the number of finished backfills kept per schedule.
+	keptBackfills = 20
+)
+
+// Backfill states.
+const (
+	BackfillRunning   = "running"
+	BackfillCompleted = "completed"
+	BackfillCancelled = "cancelled"
+)
+
+// backfill is a running or finished backfill. Its status is guarded by the
+// Service lock.
+type backfill struct {
+	status models.Backfill
+	cancel context.CancelFunc
+}
+
+// StartBackfill submits the template of the schedule name for every time
+// its cron expression matches from req.Start to req.End, in order, with at
+// most the backfill's max parallel runs unfinished at once. Each run carries
+// its logical date like a scheduled run does, but not the schedule's SLA,
+// which past dates would breach. Dates the schedule's calendars exclude are
+// skipped. Suspended schedules can be backfilled.
+func (s *Service) StartBackfill(name string, req models.BackfillRequest) (models.Backfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, err := s.schedules.get("schedule", name)
+	if err != nil {
+		return models.Backfill{}, err
+	}
+	if req.End.Before(req.Start) {
+		return models.Backfill{}, badRequestf("backfill end is before its start")
+	}
+	if req.MaxParallel > s.maxParallelBackfill {
+		return models.Backfill{}, badRequestf("max_parallel exceeds the limit of %d", s.maxParallelBackfill)
+	}
+	maxParallel := req.MaxParallel
+	if maxParallel == 0 {
+		maxParallel = s.maxParallelBackfill
+	}
+
+	state := s.scheduleStates[name]
+	exclude := s.excluder(res.Spec)
+	var runs []models.BackfillRun
+	for t := state.cron.NextIncluded(req.Start.Add(-time.Second).In(state.loc), exclude); !t.IsZero() && !t.After(req.End); t = state.cron.NextIncluded(t, exclude) {
+		if len(runs) == maxBackfillRuns {
+			return models.Backfill{}, badRequestf("backfill has more than %d runs", maxBackfillRuns)
+		}
+		runs = append(runs, models.BackfillRun{LogicalDate: t.UTC(), Status: "pending"})
+	}
+	if len(runs) == 0 {
+		return models.Backfill{}, badRequestf("schedule %s has no runs from %s to %s", name, req.Start.Format(time.RFC3339), req.End.Format(time.RFC3339))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bf := &backfill{
+		status: models.Backfill{
+			ID:          uuid.NewString(),
+			Schedule:    name,
+			Start:       req.Start.UTC(),
+			End:         req.End.UTC(),
+			MaxParallel: maxParallel,
+			State:       BackfillRunning,
+			Total:       len(runs),
+			Runs:        runs,
+			CreatedAt:   s.now().UTC(),
+		},
+		cancel: cancel,
+	}
+	s.pruneBackfills(name)
+	s.backfills[bf.status.ID] = bf
+	go s.runBackfill(ctx, bf, s.templates.items[res.Spec.Template].Spec)
+	return cloneBackfill(bf.status), nil
+}
+
+// ListBackfills returns the backfills of the schedule name, oldest first.
+func (s *Service) ListBackfills(name string) ([]models.Backfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.schedules.get("schedule", name); err != nil {
+		return nil, err
+	}
+	var list []models.Backfill
+	for _, bf := range s.backfills {
+		if bf.status.Schedule == name {
+			list = append(list, cloneBackfill(bf.status))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+// CancelBackfill stops the backfill id of the schedule name from submitting
+// more runs. Submitted workflows keep running.
+func (s *Service) CancelBackfill(name, id string) (models.Backfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bf, ok := s.backfills[id]
+	if !ok || bf.status.Schedule != name {
+		return models.Backfill{}, errs.Newf(errs.NotFound, "backfill %s of schedule %s not found", id, name)
+	}
+	bf.cancel()
+	return cloneBackfill(bf.status), nil
+}
+
+// cancelBackfills cancels the backfills of the schedule name, or all
+// backfills if name is empty.
+func (s *Service) cancelBackfills(name string) {
+	for _, bf := range s.backfills {
+		if name == "" || bf.status.Schedule == name {
+			bf.cancel()
+		}
+	}
+}
+
+// pruneBackfills drops the oldest finished backfills of the schedule name
+// beyond keptBackfills.
+func (s *Service) pruneBackfills(name string) {
+	var finished []*backfill
+	for _, bf := range s.backfills {
+		if bf.status.Schedule == name && bf.status.State != BackfillRunning {
+			finished = append(finished, bf)
+		}
+	}
+	if len(finished) < keptBackfills {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].status.CreatedAt.Before(finished[j].status.CreatedAt) })
+	for _, bf := range finished[:len(finished)-keptBackfills+1] {
+		delete(s.backfills, bf.status.ID)
+	}
+}
+
+// runBackfill submits the runs of bf in order until ctx is done.
+func (s *Service) runBackfill(ctx context.Context, bf *backfill, template models.WorkflowRequest) {
+	slots := make(chan struct{}, bf.status.MaxParallel)
+	var wg sync.WaitGroup
+	for i := range bf.status.Runs {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.runBackfillRun(ctx, bf, i, template)
+		}()
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bf.status.State = BackfillCompleted
+	if ctx.Err() != nil {
+		bf.status.State = BackfillCancelled
+	}
+	now := s.now().UTC()
+	bf.status.CompletedAt = &now
+	bf.cancel()
+}
+
+// runBackfillRun submits run i of bf and waits until its workflow finished
+// or ctx is done.
+func (s *Service) runBackfillRun(ctx context.Context, bf *backfill, i int, template models.WorkflowRequest) {
+	s.mu.Lock()
+	run := &bf.status.Runs[i]
+	req := scheduledRequest(template, bf.status.Schedule, run.LogicalDate, nil)
+	s.mu.Unlock()
+	req.Metadata["goclaw.io/backfill"] = bf.status.ID
+
+	id, err := s.engine.SubmitWorkflowRequest(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled while submitting: the run stays pending.
+		return
+	}
+	s.mu.Lock()
+	if err != nil {
+		run.Status, run.Error = "failed", err.Error()
+		bf.status.Failed++
+	} else {
+		run.WorkflowID, run.Status = id, "pending"
+	}
+	s.mu.Unlock()
+	if err != nil {
+		s.logger.Warn("Failed to submit backfill run", "schedule", bf.status.Schedule, "backfill", bf.status.ID, "error", err)
+		return
+	}
+
+	ticker := time.NewTicker(s.backfillPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		resp, err := s.engine.GetWorkflowSummaryResponse(ctx, id)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("Failed to check backfill run", "backfill", bf.status.ID, "workflow_id", id, "error", err)
+			}
+			continue
+		}
+		s.mu.Lock()
+		run.Status = resp.Status
+		switch resp.Status {
+		case "completed":
+			bf.status.Succeeded++
+		case "failed", "cancelled":
+			bf.status.Failed++
+		}
+		s.mu.Unlock()
+		if isTerminal(resp.Status) {
+			return
+		}
+	}
+}
+
+func cloneBackfill(b models.Backfill) models.Backfill {
+	b.Runs = slices.Clone(b.Runs)
+	return b
+}
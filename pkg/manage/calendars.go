@@ -0,0 +1,114 @@
+package manage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/cron"
+)
+
+// GetCalendar returns the calendar name.
+func (s *Service) GetCalendar(name string) (Resource[models.CalendarSpec], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calendars.get("calendar", name)
+}
+
+// ListCalendars returns the calendars, sorted by name.
+func (s *Service) ListCalendars() []Resource[models.CalendarSpec] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calendars.list()
+}
+
+// PutCalendar creates or replaces the calendar name and computes the next
+// run of the schedules using it anew. It reports whether the calendar was
+// created.
+func (s *Service) PutCalendar(name string, spec models.CalendarSpec, cond Precondition) (Resource[models.CalendarSpec], bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cal *cron.Calendar
+	res, created, err := put(s, &s.calendars, "calendar", name, spec, cond, func() error {
+		var err error
+		cal, err = parseCalendar(spec)
+		return err
+	})
+	if err != nil || cal == nil {
+		return res, created, err
+	}
+	s.parsedCalendars[name] = cal
+	for _, schedule := range s.schedules.list() {
+		if slices.Contains(schedule.Spec.Calendars, name) {
+			state := s.scheduleStates[schedule.Name]
+			state.status.NextRunAt = state.next(schedule.Spec, state.after, s.excluder(schedule.Spec))
+		}
+	}
+	return res, created, nil
+}
+
+// DeleteCalendar deletes the calendar name. Calendars used by a schedule
+// cannot be deleted.
+func (s *Service) DeleteCalendar(name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := remove(s, &s.calendars, "calendar", name, cond, func() error {
+		for _, schedule := range s.schedules.list() {
+			if slices.Contains(schedule.Spec.Calendars, name) {
+				return conflictf("calendar %s is used by schedule %s", name, schedule.Name)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		delete(s.parsedCalendars, name)
+	}
+	return err
+}
+
+// excluder returns whether a calendar of spec excludes a time. The calendars
+// must exist.
+func (s *Service) excluder(spec models.ScheduleSpec) func(time.Time) bool {
+	if len(spec.Calendars) == 0 {
+		return nil
+	}
+	cals := make([]*cron.Calendar, 0, len(spec.Calendars))
+	for _, name := range spec.Calendars {
+		cals = append(cals, s.parsedCalendars[name])
+	}
+	return func(t time.Time) bool {
+		for _, cal := range cals {
+			if cal.Excludes(t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func parseCalendar(spec models.CalendarSpec) (*cron.Calendar, error) {
+	blackouts := make([]cron.Blackout, 0, len(spec.Blackouts))
+	for _, w := range spec.Blackouts {
+		var b cron.Blackout
+		switch {
+		case w.Cron != "":
+			var err error
+			if b.Schedule, err = cron.Parse(w.Cron); err != nil {
+				return nil, badRequestf("blackout: %v", err)
+			}
+			if b.Duration, err = time.ParseDuration(w.Duration); err != nil {
+				return nil, badRequestf("blackout duration %q is not a duration such as 2h", w.Duration)
+			}
+		case w.Start != nil && w.End != nil:
+			b.Start, b.End = *w.Start, *w.End
+		default:
+			return nil, badRequestf("blackout needs a start and end, or a cron expression and duration")
+		}
+		blackouts = append(blackouts, b)
+	}
+	cal, err := cron.NewCalendar(spec.Holidays, blackouts)
+	if err != nil {
+		return nil, badRequestf("%v", err)
+	}
+	return cal, nil
+}
@@ -0,0 +1,165 @@
+package manage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/scrub"
+)
+
+// EventWorkflowCallback is the X-Goclaw-Event header of the POST of a
+// finished run's status to the callback URL it was submitted with.
+const EventWorkflowCallback = "workflow.callback"
+
+// errPrivateCallback is returned for callbacks to a loopback, link-local,
+// private or unspecified address whose host is not allowed.
+var errPrivateCallback = errors.New("callback address is not public")
+
+// WithCallbackSecret signs the callbacks of finished runs with secret, as
+// webhooks are signed with theirs.
+func WithCallbackSecret(secret string) Option {
+	return func(s *Service) {
+		s.callbackSecret = secret
+	}
+}
+
+// WithCallbackAllowedHosts lets callbacks reach hosts, matched against the
+// host name of the callback URL, even when they resolve to a loopback,
+// link-local or private address. Callbacks to other hosts are only posted
+// to public addresses, so that submitters cannot make the server call
+// internal services.
+func WithCallbackAllowedHosts(hosts []string) Option {
+	return func(s *Service) {
+		s.callbackAllowedHosts = make([]string, len(hosts))
+		for i, host := range hosts {
+			s.callbackAllowedHosts[i] = strings.ToLower(host)
+		}
+	}
+}
+
+// WithScrubber scrubs the statuses posted to callback URLs with scrubber, as
+// the event broadcaster scrubs the events webhooks are delivered.
+func WithScrubber(scrubber *scrub.Scrubber) Option {
+	return func(s *Service) {
+		s.scrubber = scrubber
+	}
+}
+
+// isTerminalState reports whether a run in state has finished.
+func isTerminalState(state string) bool {
+	return state == workflowCompleted || state == workflowFailed || state == workflowCancelled
+}
+
+// deliverCallback posts the final status of the run that event reports
+// finished to its callback URL, if it was submitted with one. The
+// HeaderDelivery header carries the workflow ID, so that receivers can
+// deduplicate retries. Sensitive task data is redacted, as the receiver is
+// not a caller that could be granted the sensitive data roles, and the
+// status is scrubbed.
+func (s *Service) deliverCallback(ctx context.Context, event events.Event) {
+	payload, _ := event.Payload.(map[string]any)
+	id, _ := payload["workflow_id"].(string)
+	if id == "" {
+		return
+	}
+	// The event does not carry the callback URL.
+	resp, err := s.engine.GetWorkflowStatusResponse(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to get finished workflow for its callback", "workflow_id", id, "error", err)
+		return
+	}
+	if resp.CallbackURL == "" {
+		return
+	}
+	redactCallback(resp)
+	body, err := json.Marshal(resp)
+	if err != nil {
+		s.logger.Warn("Failed to encode workflow callback", "workflow_id", id, "error", err)
+		return
+	}
+	body = s.scrubber.JSON(body)
+	target := models.WebhookSpec{URL: resp.CallbackURL, Secret: s.callbackSecret}
+	if err := s.deliverCallbackID(target, id, body); err != nil {
+		s.logger.Warn("Failed to deliver workflow callback", "workflow_id", id, "url", resp.CallbackURL, "error", err)
+	}
+}
+
+// deliverCallbackID is deliverID for a callback, through the client that
+// only dials public addresses unless the callback host is allowed.
+func (s *Service) deliverCallbackID(target models.WebhookSpec, id string, body []byte) error {
+	u, err := url.Parse(target.URL)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(s.callbackAllowedHosts, strings.ToLower(u.Hostname())) {
+		return s.deliverID(target, EventWorkflowCallback, id, body)
+	}
+	return s.deliverIDWith(s.callbackClient, target, EventWorkflowCallback, id, body)
+}
+
+// redactCallback replaces the configs and results of sensitive tasks in
+// resp.
+func redactCallback(resp *models.WorkflowStatusResponse) {
+	for i := range resp.Tasks {
+		task := &resp.Tasks[i]
+		if !task.Sensitive {
+			continue
+		}
+		if task.Config != nil {
+			config := make(map[string]interface{}, len(task.Config))
+			for key := range task.Config {
+				config[key] = models.RedactedValue
+			}
+			task.Config = config
+		}
+		if task.Result != nil {
+			task.Result = models.RedactedValue
+		}
+	}
+}
+
+// newCallbackClient returns a client that refuses to connect to loopback,
+// link-local, private and unspecified addresses. The address is checked
+// once resolved, so that host names and redirects leading to internal
+// addresses are refused too, and proxies from the environment are not used.
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", errPrivateCallback, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// publicIP reports whether ip is a public unicast address.
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
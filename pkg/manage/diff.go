@@ -0,0 +1,94 @@
+package manage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// DiffTemplate compares the tasks of the workflow id with those of the
+// template name, for reviewing a plan against the workflow it was meant to
+// resemble.
+func (s *Service) DiffTemplate(ctx context.Context, name, id string) (*models.TemplateDiff, error) {
+	template, err := s.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := s.engine.GetWorkflowStatusResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.TemplateDiff{
+		Template:   name,
+		WorkflowID: id,
+		Added:      []string{},
+		Removed:    []string{},
+		Changed:    []models.TaskDiff{},
+	}
+	planned := make(map[string]models.TaskStatus, len(wf.Tasks))
+	for _, task := range wf.Tasks {
+		planned[task.ID] = task
+	}
+	for _, want := range template.Spec.Tasks {
+		got, ok := planned[want.ID]
+		if !ok {
+			diff.Removed = append(diff.Removed, want.ID)
+			continue
+		}
+		delete(planned, want.ID)
+		if fields := taskDiffFields(want, got); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, models.TaskDiff{ID: want.ID, Fields: fields})
+		}
+	}
+	for id := range planned {
+		diff.Added = append(diff.Added, id)
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ID < diff.Changed[j].ID })
+	return diff, nil
+}
+
+// taskDiffFields returns the JSON names of the fields of the definition
+// that task differs in. Values are compared in their JSON encoding, so that
+// numbers in configs compare equal whatever their Go type.
+func taskDiffFields(def models.TaskDefinition, task models.TaskStatus) []string {
+	pairs := []struct {
+		field     string
+		want, got any
+	}{
+		{"name", def.Name, task.Name},
+		{"type", def.Type, task.Type},
+		{"depends_on", def.DependsOn, task.DependsOn},
+		{"config", def.Config, task.Config},
+		{"timeout", def.Timeout, task.Timeout},
+		{"retries", def.Retries, task.Retries},
+	}
+	var fields []string
+	for _, p := range pairs {
+		if !sameJSON(p.want, p.got) {
+			fields = append(fields, p.field)
+		}
+	}
+	return fields
+}
+
+// sameJSON reports whether a and b encode to the same JSON, treating empty
+// lists and maps as absent.
+func sameJSON(a, b any) bool {
+	ea, _ := json.Marshal(a)
+	eb, _ := json.Marshal(b)
+	return bytes.Equal(emptyAsNull(ea), emptyAsNull(eb))
+}
+
+func emptyAsNull(data []byte) []byte {
+	switch string(data) {
+	case "[]", "{}":
+		return []byte("null")
+	}
+	return data
+}
@@ -0,0 +1,71 @@
+package manage
+
+import "github.com/goclaw/goclaw/pkg/api/models"
+
+// Resources holds every managed resource, sorted by name within each kind.
+type Resources struct {
+	Templates []Resource[models.WorkflowRequest] `json:"templates,omitempty"`
+	Schedules []Resource[models.ScheduleSpec]    `json:"schedules,omitempty"`
+	Triggers  []Resource[models.TriggerSpec]     `json:"triggers,omitempty"`
+	Calendars []Resource[models.CalendarSpec]    `json:"calendars,omitempty"`
+	Webhooks  []Resource[models.WebhookSpec]     `json:"webhooks,omitempty"`
+	Lanes     []Resource[models.LaneSpec]        `json:"lanes,omitempty"`
+}
+
+// Len returns the number of resources in r.
+func (r Resources) Len() int {
+	return len(r.Templates) + len(r.Schedules) + len(r.Triggers) + len(r.Calendars) + len(r.Webhooks) + len(r.Lanes)
+}
+
+// Export returns all resources as of one point in time.
+func (s *Service) Export() Resources {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Resources{
+		Templates: s.templates.list(),
+		Schedules: s.schedules.list(),
+		Triggers:  s.triggers.list(),
+		Calendars: s.calendars.list(),
+		Webhooks:  s.webhooks.list(),
+		Lanes:     s.lanes.list(),
+	}
+}
+
+// Restore creates the resources of r, such as an Export of another service.
+// Resources are created before the schedules and triggers that refer to
+// them, and get new versions; a resource that already exists fails the
+// restore with a PreconditionFailed error.
+func (s *Service) Restore(r Resources) error {
+	create := Precondition{Absent: true}
+	for _, res := range r.Calendars {
+		if _, _, err := s.PutCalendar(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	for _, res := range r.Templates {
+		if _, _, err := s.PutTemplate(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	for _, res := range r.Lanes {
+		if _, _, err := s.PutLane(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	for _, res := range r.Webhooks {
+		if _, _, err := s.PutWebhook(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	for _, res := range r.Schedules {
+		if _, _, _, err := s.PutSchedule(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	for _, res := range r.Triggers {
+		if _, _, _, err := s.PutTrigger(res.Name, res.Spec, create); err != nil {
+			return err
+		}
+	}
+	return nil
+}
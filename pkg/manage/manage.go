@@ -0,0 +1,368 @@
+// Package manage holds the resources infrastructure-as-code tools manage
+// declaratively: workflow templates, schedules submitting them, triggers
+// submitting them after upstream workflows complete, calendars of times
+// schedules do not run at, webhooks receiving workflow events and lanes.
+//
+// Writes replace a resource as a whole. Every change gives the resource a
+// higher version from a counter shared by all kinds, so a version is never
+// reused, even by a resource that is deleted and recreated. Writes may be
+// conditional on the version last read; writing an unchanged spec keeps the
+// version, so reconciling tools can re-apply their state idempotently.
+// With a store, resources are saved as they change and reloaded by Load
+// after a restart; otherwise they are held in memory only.
+package manage
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/cron"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/logger"
+	"github.com/goclaw/goclaw/pkg/scrub"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// Engine is the part of the engine managed resources act on.
+type Engine interface {
+	SubmitWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) (string, error)
+	GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error)
+	GetWorkflowStatusResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error)
+	PutLane(name string, capacity, maxConcurrency int, rateLimit float64) error
+	DeleteLane(ctx context.Context, name string) error
+}
+
+// Resource is a stored resource with spec type T.
+type Resource[T any] struct {
+	Name      string    `json:"name"`
+	Version   int64     `json:"version"`
+	Spec      T         `json:"spec"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Precondition makes a write conditional on the stored resource.
+type Precondition struct {
+	// Version, when non-zero, must be the version of the stored resource.
+	Version int64
+	// Exists requires the resource to exist.
+	Exists bool
+	// Absent requires the resource not to exist.
+	Absent bool
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithLogger sets the logger of schedule, trigger and webhook failures.
+func WithLogger(l logger.Logger) Option {
+	return func(s *Service) {
+		s.logger = l
+	}
+}
+
+// WithHTTPClient sets the client webhooks are delivered with.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Service) {
+		s.client = c
+	}
+}
+
+// WithMaxParallelBackfill sets how many runs of a backfill may be
+// unfinished at once, and the limit requests may lower it from.
+func WithMaxParallelBackfill(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxParallelBackfill = n
+		}
+	}
+}
+
+// WithStore saves the resources in store as they change, for Load to
+// restore them after a restart.
+func WithStore(store storage.ResourceStore) Option {
+	return func(s *Service) {
+		s.store = store
+	}
+}
+
+// WithOutboxDelivery leaves the webhooks of workflow state changes and SLA
+// breaches to Notify, called by the engine's outbox dispatcher. DeliverEvents
+// still records them on schedules and triggers.
+func WithOutboxDelivery() Option {
+	return func(s *Service) {
+		s.outboxDelivery = true
+	}
+}
+
+// Service stores the managed resources and runs schedules, triggers and
+// webhooks.
+type Service struct {
+	engine Engine
+	logger logger.Logger
+	client *http.Client
+	store  storage.ResourceStore
+	now    func() time.Time
+
+	// mu guards all resources, as schedules refer to templates.
+	mu        sync.Mutex
+	version   int64
+	templates registry[models.WorkflowRequest]
+	schedules registry[models.ScheduleSpec]
+	triggers  registry[models.TriggerSpec]
+	calendars registry[models.CalendarSpec]
+	webhooks  registry[models.WebhookSpec]
+	lanes     registry[models.LaneSpec]
+	// configWebhooks names the webhooks declared in the configuration.
+	configWebhooks map[string]bool
+	// scheduleStates holds the observed state of each schedule.
+	scheduleStates map[string]*scheduleState
+	// triggerStates holds the observed state of each trigger.
+	triggerStates map[string]*models.TriggerStatus
+	// parsedCalendars holds the parsed spec of each calendar.
+	parsedCalendars map[string]*cron.Calendar
+	// backfills holds the running and recent backfills by ID.
+	backfills map[string]*backfill
+
+	maxParallelBackfill int
+	backfillPoll        time.Duration
+
+	deliveries     chan struct{}
+	outboxDelivery bool
+	callbackSecret string
+	// callbackClient posts callbacks to hosts not in callbackAllowedHosts.
+	callbackClient       *http.Client
+	callbackAllowedHosts []string
+	scrubber             *scrub.Scrubber
+}
+
+// New returns a service managing resources of eng.
+func New(eng Engine, opts ...Option) *Service {
+	s := &Service{
+		engine:          eng,
+		logger:          logger.Global(),
+		client:          &http.Client{Timeout: webhookTimeout},
+		callbackClient:  newCallbackClient(),
+		now:             time.Now,
+		templates:       newRegistry[models.WorkflowRequest](),
+		schedules:       newRegistry[models.ScheduleSpec](),
+		triggers:        newRegistry[models.TriggerSpec](),
+		calendars:       newRegistry[models.CalendarSpec](),
+		webhooks:        newRegistry[models.WebhookSpec](),
+		lanes:           newRegistry[models.LaneSpec](),
+		scheduleStates:  make(map[string]*scheduleState),
+		triggerStates:   make(map[string]*models.TriggerStatus),
+		backfills:       make(map[string]*backfill),
+		parsedCalendars: make(map[string]*cron.Calendar),
+		deliveries:      make(chan struct{}, maxConcurrentDeliveries),
+
+		maxParallelBackfill: defaultMaxParallelBackfill,
+		backfillPoll:        time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run runs the schedules until ctx is done, then cancels the backfills.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.cancelBackfills("")
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+			s.runDueSchedules(ctx, s.now())
+		}
+	}
+}
+
+// GetTemplate returns the workflow template name.
+func (s *Service) GetTemplate(name string) (Resource[models.WorkflowRequest], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.templates.get("template", name)
+}
+
+// ListTemplates returns the workflow templates, sorted by name.
+func (s *Service) ListTemplates() []Resource[models.WorkflowRequest] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.templates.list()
+}
+
+// PutTemplate creates or replaces the workflow template name. It reports
+// whether the template was created.
+func (s *Service) PutTemplate(name string, spec models.WorkflowRequest, cond Precondition) (Resource[models.WorkflowRequest], bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Submission fields are chosen by the schedule, not the template.
+	spec.Async, spec.Simulate = false, false
+	return put(s, &s.templates, "template", name, spec, cond, nil)
+}
+
+// DeleteTemplate deletes the workflow template name. Templates used by a
+// schedule or trigger cannot be deleted.
+func (s *Service) DeleteTemplate(name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return remove(s, &s.templates, "template", name, cond, func() error {
+		for _, schedule := range s.schedules.list() {
+			if schedule.Spec.Template == name {
+				return conflictf("template %s is used by schedule %s", name, schedule.Name)
+			}
+		}
+		for _, trigger := range s.triggers.list() {
+			if trigger.Spec.Template == name {
+				return conflictf("template %s is used by trigger %s", name, trigger.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// GetLane returns the managed lane name.
+func (s *Service) GetLane(name string) (Resource[models.LaneSpec], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lanes.get("lane", name)
+}
+
+// ListLanes returns the managed lanes, sorted by name.
+func (s *Service) ListLanes() []Resource[models.LaneSpec] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lanes.list()
+}
+
+// PutLane creates the lane name or updates its concurrency and rate limit.
+// It reports whether the lane was created.
+func (s *Service) PutLane(name string, spec models.LaneSpec, cond Precondition) (Resource[models.LaneSpec], bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return put(s, &s.lanes, "lane", name, spec, cond, func() error {
+		return s.engine.PutLane(name, spec.Capacity, spec.MaxConcurrency, spec.RateLimit)
+	})
+}
+
+// DeleteLane deletes the managed lane name.
+func (s *Service) DeleteLane(ctx context.Context, name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return remove(s, &s.lanes, "lane", name, cond, func() error {
+		return s.engine.DeleteLane(ctx, name)
+	})
+}
+
+// put stores spec as the resource name of r unless cond fails, apply fails
+// or the stored spec is equal.
+func put[T any](s *Service, r *registry[T], kind, name string, spec T, cond Precondition, apply func() error) (Resource[T], bool, error) {
+	cur, ok := r.items[name]
+	if err := cond.check(kind, name, cur, ok); err != nil {
+		return Resource[T]{}, false, err
+	}
+	if ok && reflect.DeepEqual(cur.Spec, spec) {
+		return cur, false, nil
+	}
+	if apply != nil {
+		if err := apply(); err != nil {
+			return Resource[T]{}, false, err
+		}
+	}
+	now := s.now().UTC()
+	res := Resource[T]{Name: name, Version: s.version + 1, Spec: spec, CreatedAt: now, UpdatedAt: now}
+	if ok {
+		res.CreatedAt = cur.CreatedAt
+	}
+	if err := save(s, kind, res); err != nil {
+		return Resource[T]{}, false, err
+	}
+	s.version = res.Version
+	r.items[name] = res
+	return res, !ok, nil
+}
+
+// remove deletes the resource name of r unless cond or apply fails.
+func remove[T any](s *Service, r *registry[T], kind, name string, cond Precondition, apply func() error) error {
+	cur, ok := r.items[name]
+	if !ok {
+		return notFound(kind, name)
+	}
+	if err := cond.check(kind, name, cur, ok); err != nil {
+		return err
+	}
+	if apply != nil {
+		if err := apply(); err != nil {
+			return err
+		}
+	}
+	if err := unsave(s, kind, name); err != nil {
+		return err
+	}
+	delete(r.items, name)
+	return nil
+}
+
+// registry holds the resources of one kind. The Service lock guards it.
+type registry[T any] struct {
+	items map[string]Resource[T]
+}
+
+func newRegistry[T any]() registry[T] {
+	return registry[T]{items: make(map[string]Resource[T])}
+}
+
+func (r *registry[T]) get(kind, name string) (Resource[T], error) {
+	res, ok := r.items[name]
+	if !ok {
+		return Resource[T]{}, notFound(kind, name)
+	}
+	return res, nil
+}
+
+func (r *registry[T]) list() []Resource[T] {
+	out := make([]Resource[T], 0, len(r.items))
+	for _, res := range r.items {
+		out = append(out, res)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// check returns a PreconditionFailed error unless p holds for the stored
+// resource cur, which exists when ok.
+func (p Precondition) check(kind, name string, cur interface{ version() int64 }, ok bool) error {
+	switch {
+	case p.Absent && ok:
+		return errs.Newf(errs.PreconditionFailed, "%s %s already exists", kind, name)
+	case (p.Exists || p.Version != 0) && !ok:
+		return errs.Newf(errs.PreconditionFailed, "%s %s does not exist", kind, name)
+	case p.Version != 0 && cur.version() != p.Version:
+		return errs.Newf(errs.PreconditionFailed, "%s %s is at version %d, not %d", kind, name, cur.version(), p.Version)
+	}
+	return nil
+}
+
+func (r Resource[T]) version() int64 { return r.Version }
+
+func notFound(kind, name string) error {
+	return errs.Newf(errs.NotFound, "%s %s not found", kind, name)
+}
+
+func conflictf(format string, args ...interface{}) error {
+	return errs.Newf(errs.Conflict, format, args...)
+}
+
+func badRequestf(format string, args ...interface{}) error {
+	return errs.Newf(errs.BadRequest, format, args...)
+}
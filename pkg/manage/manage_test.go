@@ -0,0 +1,889 @@
+package manage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/scrub"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// fakeEngine records submissions and lane changes.
+type fakeEngine struct {
+	mu        sync.Mutex
+	submitted []*models.WorkflowRequest
+	statuses  map[string]string
+	runs      map[string]*models.WorkflowRequest
+	lanes     map[string]models.LaneSpec
+	// results are the results of tasks, by task ID.
+	results map[string]any
+}
+
+func newFakeEngine() *fakeEngine {
+	return &fakeEngine{
+		statuses: make(map[string]string),
+		runs:     make(map[string]*models.WorkflowRequest),
+		lanes:    make(map[string]models.LaneSpec),
+	}
+}
+
+func (f *fakeEngine) SubmitWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submitted = append(f.submitted, req)
+	id := fmt.Sprintf("%s-%d", req.Name, len(f.submitted))
+	f.statuses[id] = "running"
+	f.runs[id] = req
+	return id, nil
+}
+
+func (f *fakeEngine) GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status, ok := f.statuses[id]
+	if !ok {
+		return nil, errs.New(errs.NotFound, "workflow not found")
+	}
+	resp := &models.WorkflowStatusResponse{ID: id, Status: status}
+	if req, ok := f.runs[id]; ok {
+		resp.Name, resp.Metadata, resp.CallbackURL = req.Name, req.Metadata, req.CallbackURL
+	}
+	return resp, nil
+}
+
+func (f *fakeEngine) GetWorkflowStatusResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	resp, err := f.GetWorkflowSummaryResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, task := range f.runs[id].Tasks {
+		resp.Tasks = append(resp.Tasks, models.TaskStatus{
+			ID: task.ID, Name: task.Name, Type: task.Type, DependsOn: task.DependsOn,
+			Config: task.Config, Timeout: task.Timeout, Retries: task.Retries,
+			Result: f.results[task.ID], Sensitive: task.Sensitive,
+		})
+	}
+	return resp, nil
+}
+
+func (f *fakeEngine) PutLane(name string, capacity, maxConcurrency int, rateLimit float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cur, ok := f.lanes[name]; ok && cur.Capacity != capacity {
+		return errs.New(errs.Conflict, "capacity cannot change")
+	}
+	f.lanes[name] = models.LaneSpec{Capacity: capacity, MaxConcurrency: maxConcurrency, RateLimit: rateLimit}
+	return nil
+}
+
+func (f *fakeEngine) DeleteLane(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.lanes, name)
+	return nil
+}
+
+var testTemplate = models.WorkflowRequest{
+	Name:  "report",
+	Tasks: []models.TaskDefinition{{ID: "t1", Name: "t1", Type: "function"}},
+}
+
+func TestService_PutVersionsAndPreconditions(t *testing.T) {
+	s := New(newFakeEngine())
+
+	res, created, err := s.PutTemplate("report", testTemplate, Precondition{Absent: true})
+	if err != nil || !created || res.Version != 1 {
+		t.Fatalf("PutTemplate() = %+v, %v, %v, want created at version 1", res, created, err)
+	}
+
+	// Re-applying the same spec keeps the version.
+	res, created, err = s.PutTemplate("report", testTemplate, Precondition{Version: 1})
+	if err != nil || created || res.Version != 1 {
+		t.Fatalf("PutTemplate() unchanged = %+v, %v, %v, want version 1", res, created, err)
+	}
+
+	changed := testTemplate
+	changed.Description = "nightly"
+	res, _, err = s.PutTemplate("report", changed, Precondition{Version: 1})
+	if err != nil || res.Version != 2 {
+		t.Fatalf("PutTemplate() changed = %+v, %v, want version 2", res, err)
+	}
+
+	for _, cond := range []Precondition{{Version: 1}, {Absent: true}} {
+		if _, _, err := s.PutTemplate("report", testTemplate, cond); !errs.Is(err, errs.PreconditionFailed) {
+			t.Errorf("PutTemplate(%+v) error = %v, want PreconditionFailed", cond, err)
+		}
+	}
+	if _, _, err := s.PutTemplate("other", testTemplate, Precondition{Exists: true}); !errs.Is(err, errs.PreconditionFailed) {
+		t.Errorf("PutTemplate() of a missing template with If-Match: * error = %v, want PreconditionFailed", err)
+	}
+	if err := s.DeleteTemplate("report", Precondition{Version: 1}); !errs.Is(err, errs.PreconditionFailed) {
+		t.Errorf("DeleteTemplate() with a stale version error = %v, want PreconditionFailed", err)
+	}
+	if err := s.DeleteTemplate("report", Precondition{Version: 2}); err != nil {
+		t.Fatalf("DeleteTemplate() error = %v", err)
+	}
+
+	// A recreated resource does not reuse versions.
+	res, _, _ = s.PutTemplate("report", testTemplate, Precondition{})
+	if res.Version != 3 {
+		t.Fatalf("recreated template version = %d, want 3", res.Version)
+	}
+}
+
+func TestService_TemplateInUseCannotBeDeleted(t *testing.T) {
+	s := New(newFakeEngine())
+	s.PutTemplate("report", testTemplate, Precondition{})
+	if _, _, _, err := s.PutSchedule("nightly", models.ScheduleSpec{Cron: "@daily", Template: "missing"}, Precondition{}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PutSchedule() with a missing template error = %v, want BadRequest", err)
+	}
+	if _, _, _, err := s.PutSchedule("nightly", models.ScheduleSpec{Cron: "@daily", Template: "report"}, Precondition{}); err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+	if err := s.DeleteTemplate("report", Precondition{}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("DeleteTemplate() error = %v, want Conflict", err)
+	}
+}
+
+func TestService_SchedulesSubmitWhenDue(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng)
+	start := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC)
+	s.now = func() time.Time { return start }
+
+	s.PutTemplate("report", testTemplate, Precondition{})
+	_, status, _, err := s.PutSchedule("quarterly", models.ScheduleSpec{
+		Cron:              "*/15 * * * *",
+		Template:          "report",
+		ConcurrencyPolicy: ConcurrencyForbid,
+	}, Precondition{})
+	if err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+	if want := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC); status.NextRunAt == nil || !status.NextRunAt.Equal(want) {
+		t.Fatalf("NextRunAt = %v, want %v", status.NextRunAt, want)
+	}
+
+	ctx := context.Background()
+	s.runDueSchedules(ctx, start.Add(time.Minute))
+	if len(eng.submitted) != 0 {
+		t.Fatalf("submitted %d workflows before the schedule was due", len(eng.submitted))
+	}
+
+	// Missed runs collapse into one.
+	s.runDueSchedules(ctx, start.Add(time.Hour))
+	_, status, _ = s.GetSchedule("quarterly")
+	if len(eng.submitted) != 1 || status.LastWorkflowID == "" {
+		t.Fatalf("submitted %d workflows, status %+v, want one run", len(eng.submitted), status)
+	}
+	if eng.submitted[0].Metadata["goclaw.io/schedule"] != "quarterly" {
+		t.Errorf("submission metadata = %v, want the schedule name", eng.submitted[0].Metadata)
+	}
+	// The run stands for the latest missed time.
+	if got := eng.submitted[0].Tasks[0].Config["logical_date"]; got != "2026-03-14T11:15:00Z" {
+		t.Errorf("logical_date = %v, want 2026-03-14T11:15:00Z", got)
+	}
+	if status.Message != "skipped 3 missed runs" {
+		t.Errorf("Message = %q, want the missed runs", status.Message)
+	}
+	if testTemplate.Tasks[0].Config != nil {
+		t.Errorf("submission modified the template: %v", testTemplate.Tasks[0].Config)
+	}
+
+	// With forbid, the next run is skipped while the last one runs.
+	s.runDueSchedules(ctx, start.Add(2*time.Hour))
+	_, status, _ = s.GetSchedule("quarterly")
+	if len(eng.submitted) != 1 || status.Message == "" {
+		t.Fatalf("submitted %d workflows, status %+v, want a skipped run", len(eng.submitted), status)
+	}
+
+	eng.statuses[status.LastWorkflowID] = "completed"
+	s.runDueSchedules(ctx, start.Add(3*time.Hour))
+	if len(eng.submitted) != 2 {
+		t.Fatalf("submitted %d workflows, want 2", len(eng.submitted))
+	}
+}
+
+func TestService_BackfillThrottlesRuns(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng, WithMaxParallelBackfill(3))
+	s.backfillPoll = time.Millisecond
+	s.PutTemplate("report", testTemplate, Precondition{})
+	if _, _, _, err := s.PutSchedule("daily", models.ScheduleSpec{Cron: "0 2 * * *", Template: "report", Suspend: true}, Precondition{}); err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+
+	req := models.BackfillRequest{
+		Start:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2026, 1, 5, 2, 0, 0, 0, time.UTC),
+		MaxParallel: 4,
+	}
+	if _, err := s.StartBackfill("daily", req); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("StartBackfill() above the limit error = %v, want BadRequest", err)
+	}
+	req.MaxParallel = 2
+	bf, err := s.StartBackfill("daily", req)
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	if bf.Total != 5 || !bf.Runs[4].LogicalDate.Equal(req.End) {
+		t.Fatalf("backfill = %+v, want 5 daily runs ending at %v", bf, req.End)
+	}
+
+	// Complete the running workflows one round at a time; never more than
+	// two may be unfinished.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		eng.mu.Lock()
+		running := 0
+		for id, status := range eng.statuses {
+			if status == "running" {
+				running++
+				eng.statuses[id] = "completed"
+			}
+		}
+		submitted := len(eng.submitted)
+		eng.mu.Unlock()
+		if running > 2 {
+			t.Fatalf("%d backfill runs unfinished at once, want at most 2", running)
+		}
+
+		list, _ := s.ListBackfills("daily")
+		if list[0].State == BackfillCompleted {
+			if submitted != 5 || list[0].Succeeded != 5 {
+				t.Fatalf("backfill finished with %d submissions: %+v", submitted, list[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backfill did not finish: %+v", list[0])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	eng.mu.Lock()
+	defer eng.mu.Unlock()
+	dates := make(map[string]bool)
+	for _, req := range eng.submitted {
+		dates[req.Metadata["goclaw.io/logical-date"]] = true
+	}
+	for day := 1; day <= 5; day++ {
+		if date := fmt.Sprintf("2026-01-%02dT02:00:00Z", day); !dates[date] {
+			t.Errorf("no run submitted for %s: %v", date, dates)
+		}
+	}
+}
+
+func TestService_CalendarsAndCatchUp(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng)
+	start := time.Date(2026, 12, 24, 21, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+	s.PutTemplate("report", testTemplate, Precondition{})
+
+	spec := models.ScheduleSpec{Cron: "0 */2 * * *", Template: "report", Calendars: []string{"holidays"}, CatchUpPolicy: "run-all"}
+	if _, _, _, err := s.PutSchedule("bihourly", spec, Precondition{}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PutSchedule() with an unknown calendar error = %v, want BadRequest", err)
+	}
+	if _, _, err := s.PutCalendar("holidays", models.CalendarSpec{Holidays: []string{"2026-12-25"}}, Precondition{}); err != nil {
+		t.Fatalf("PutCalendar() error = %v", err)
+	}
+	_, status, _, err := s.PutSchedule("bihourly", spec, Precondition{})
+	if err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+	if want := time.Date(2026, 12, 24, 22, 0, 0, 0, time.UTC); !status.NextRunAt.Equal(want) {
+		t.Fatalf("NextRunAt = %v, want %v", status.NextRunAt, want)
+	}
+	if err := s.DeleteCalendar("holidays", Precondition{}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("DeleteCalendar() of a used calendar error = %v, want Conflict", err)
+	}
+
+	// After an outage until the 26th, the missed runs outside the holiday
+	// run one per call, oldest first.
+	ctx := context.Background()
+	now := time.Date(2026, 12, 26, 3, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		s.runDueSchedules(ctx, now)
+	}
+	var dates []string
+	for _, req := range eng.submitted {
+		dates = append(dates, req.Metadata["goclaw.io/logical-date"])
+	}
+	want := []string{"2026-12-24T22:00:00Z", "2026-12-26T00:00:00Z", "2026-12-26T02:00:00Z"}
+	if !reflect.DeepEqual(dates, want) {
+		t.Fatalf("submitted runs for %v, want %v", dates, want)
+	}
+
+	// Changing the calendar moves the next run.
+	if _, _, err := s.PutCalendar("holidays", models.CalendarSpec{Holidays: []string{"2026-12-26"}}, Precondition{}); err != nil {
+		t.Fatalf("PutCalendar() error = %v", err)
+	}
+	_, status, _ = s.GetSchedule("bihourly")
+	if want := time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC); !status.NextRunAt.Equal(want) {
+		t.Fatalf("NextRunAt after the calendar change = %v, want %v", status.NextRunAt, want)
+	}
+}
+
+func TestService_LanesApplyToEngine(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng)
+	spec := models.LaneSpec{Capacity: 10, MaxConcurrency: 2}
+	if _, _, err := s.PutLane("gpu", spec, Precondition{}); err != nil {
+		t.Fatalf("PutLane() error = %v", err)
+	}
+	spec.Capacity = 20
+	if _, _, err := s.PutLane("gpu", spec, Precondition{}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("PutLane() with a new capacity error = %v, want Conflict", err)
+	}
+	if res, _ := s.GetLane("gpu"); res.Spec.Capacity != 10 {
+		t.Fatalf("stored lane = %+v, want the applied spec", res.Spec)
+	}
+	if err := s.DeleteLane(context.Background(), "gpu", Precondition{}); err != nil {
+		t.Fatalf("DeleteLane() error = %v", err)
+	}
+	if _, ok := eng.lanes["gpu"]; ok {
+		t.Fatal("lane still registered with the engine")
+	}
+}
+
+func TestService_DeliversSignedWebhooks(t *testing.T) {
+	type delivery struct {
+		event  string
+		header http.Header
+		body   []byte
+	}
+	received := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{r.Header.Get(HeaderEvent), r.Header.Clone(), body}
+	}))
+	defer server.Close()
+
+	s := New(newFakeEngine())
+	if _, _, err := s.PutWebhook("ops", models.WebhookSpec{URL: "ftp://example.com"}, Precondition{}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PutWebhook() with an ftp URL error = %v, want BadRequest", err)
+	}
+	if _, _, err := s.PutWebhook("ops", models.WebhookSpec{URL: server.URL, Events: []string{"workflow.state_changed"}, Secret: "s3cret"}, Precondition{}); err != nil {
+		t.Fatalf("PutWebhook() error = %v", err)
+	}
+
+	ch := make(chan events.Event, 2)
+	ch <- events.Event{Type: "task.state_changed"}
+	ch <- events.Event{Type: "workflow.state_changed", Payload: map[string]any{"workflow_id": "wf-1"}}
+	close(ch)
+	s.DeliverEvents(ch)
+
+	select {
+	case d := <-received:
+		if d.event != "workflow.state_changed" {
+			t.Fatalf("delivered event %q, want workflow.state_changed", d.event)
+		}
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(d.header.Get(HeaderTimestamp) + "." + d.header.Get(HeaderNonce) + "."))
+		mac.Write(d.body)
+		if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); d.header.Get(HeaderSignature) != want {
+			t.Errorf("signature = %q, want %q", d.header.Get(HeaderSignature), want)
+		}
+		if err := VerifyWebhook("s3cret", d.header, d.body, 0, time.Now()); err != nil {
+			t.Errorf("VerifyWebhook() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	select {
+	case d := <-received:
+		t.Fatalf("unexpected delivery of %q", d.event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestService_LoadRestoresSavedResources(t *testing.T) {
+	store := memory.NewMemoryStorage()
+	start := time.Date(2026, 12, 24, 21, 30, 0, 0, time.UTC)
+	s := New(newFakeEngine(), WithStore(store))
+	s.now = func() time.Time { return start }
+	s.PutTemplate("report", testTemplate, Precondition{})
+	s.PutTemplate("old", testTemplate, Precondition{})
+	s.PutCalendar("holidays", models.CalendarSpec{Holidays: []string{"2026-12-25"}}, Precondition{})
+	schedule := models.ScheduleSpec{Cron: "0 */2 * * *", Template: "report", Calendars: []string{"holidays"}}
+	if _, _, _, err := s.PutSchedule("bihourly", schedule, Precondition{}); err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+	if _, _, _, err := s.PutTrigger("after-etl", models.TriggerSpec{
+		After:    []models.TriggerDependency{{Workflow: "etl"}},
+		Template: "report",
+	}, Precondition{}); err != nil {
+		t.Fatalf("PutTrigger() error = %v", err)
+	}
+	s.PutLane("gpu", models.LaneSpec{Capacity: 10, MaxConcurrency: 2}, Precondition{})
+	s.PutWebhook("api", models.WebhookSpec{URL: "https://api.example.com"}, Precondition{})
+	if err := s.SyncWebhooks(map[string]models.WebhookSpec{"ops": {URL: "https://ops.example.com"}}); err != nil {
+		t.Fatalf("SyncWebhooks() error = %v", err)
+	}
+	last, _ := s.GetWebhook("ops")
+	if err := s.DeleteTemplate("old", Precondition{}); err != nil {
+		t.Fatalf("DeleteTemplate() error = %v", err)
+	}
+
+	eng := newFakeEngine()
+	restarted := New(eng, WithStore(store))
+	restarted.now = func() time.Time { return start }
+	if err := restarted.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := restarted.ListTemplates(); len(got) != 1 || got[0].Name != "report" {
+		t.Fatalf("templates = %+v, want report", got)
+	}
+	// Webhooks declared in the configuration are not saved.
+	if got := restarted.ListWebhooks(); len(got) != 1 || got[0].Name != "api" {
+		t.Fatalf("webhooks = %+v, want api", got)
+	}
+	if _, ok := eng.lanes["gpu"]; !ok {
+		t.Fatal("lane not applied to the engine")
+	}
+	if _, status, err := restarted.GetTrigger("after-etl"); err != nil || len(status.Satisfied) != 1 {
+		t.Fatalf("GetTrigger() = %+v, %v", status, err)
+	}
+	_, status, err := restarted.GetSchedule("bihourly")
+	if want := time.Date(2026, 12, 24, 22, 0, 0, 0, time.UTC); err != nil || status.NextRunAt == nil || !status.NextRunAt.Equal(want) {
+		t.Fatalf("GetSchedule() = %+v, %v, want the next run at %v", status, err, want)
+	}
+	restarted.runDueSchedules(context.Background(), start.Add(time.Hour))
+	if len(eng.submitted) != 1 {
+		t.Fatalf("submitted %d workflows after the restart, want 1", len(eng.submitted))
+	}
+
+	// Versions are not reused, even those of resources deleted before the
+	// restart.
+	res, _, _ := restarted.PutTemplate("old", testTemplate, Precondition{})
+	if res.Version <= last.Version {
+		t.Fatalf("recreated template version = %d, want above %d", res.Version, last.Version)
+	}
+	if err := restarted.DeleteTemplate("old", Precondition{}); err != nil {
+		t.Fatalf("DeleteTemplate() error = %v", err)
+	}
+	records, _ := store.ListResources(context.Background(), storeKind("template"))
+	if len(records) != 1 {
+		t.Fatalf("saved templates = %d, want 1", len(records))
+	}
+}
+
+func TestService_SyncWebhooks(t *testing.T) {
+	s := New(newFakeEngine())
+	if _, _, err := s.PutWebhook("api", models.WebhookSpec{URL: "https://api.example.com"}, Precondition{}); err != nil {
+		t.Fatalf("PutWebhook() error = %v", err)
+	}
+	if err := s.SyncWebhooks(map[string]models.WebhookSpec{
+		"ops":   {URL: "https://ops.example.com"},
+		"slack": {URL: "https://hooks.example.com", Format: WebhookFormatSlack},
+	}); err != nil {
+		t.Fatalf("SyncWebhooks() error = %v", err)
+	}
+	names := func() []string {
+		var names []string
+		for _, res := range s.ListWebhooks() {
+			names = append(names, res.Name)
+		}
+		return names
+	}
+	if got := names(); !reflect.DeepEqual(got, []string{"api", "ops", "slack"}) {
+		t.Fatalf("webhooks = %v", got)
+	}
+
+	if err := s.SyncWebhooks(map[string]models.WebhookSpec{"ops": {URL: "ftp://ops.example.com"}}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("SyncWebhooks() with an ftp URL error = %v, want BadRequest", err)
+	}
+	if got := names(); !reflect.DeepEqual(got, []string{"api", "ops", "slack"}) {
+		t.Fatalf("webhooks after a failed sync = %v", got)
+	}
+
+	if err := s.SyncWebhooks(map[string]models.WebhookSpec{"ops": {URL: "https://ops2.example.com"}}); err != nil {
+		t.Fatalf("SyncWebhooks() error = %v", err)
+	}
+	if got := names(); !reflect.DeepEqual(got, []string{"api", "ops"}) {
+		t.Fatalf("webhooks after removing slack = %v", got)
+	}
+	if ops, _ := s.GetWebhook("ops"); ops.Spec.URL != "https://ops2.example.com" {
+		t.Fatalf("ops = %+v", ops)
+	}
+}
+
+func TestService_DeliversCallbacks(t *testing.T) {
+	received := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	eng := newFakeEngine()
+	eng.results = map[string]any{"t1": "hello ada@example.com", "secret": "token"}
+	scrubber, err := scrub.New([]scrub.Rule{{Name: "email", Pattern: `[a-z]+@[a-z.]+`}})
+	if err != nil {
+		t.Fatalf("scrub.New() error = %v", err)
+	}
+	s := New(eng, WithCallbackSecret("s3cret"), WithCallbackAllowedHosts([]string{"127.0.0.1"}), WithScrubber(scrubber))
+	withCallback := testTemplate
+	withCallback.Tasks = append(slices.Clone(testTemplate.Tasks), models.TaskDefinition{
+		ID: "secret", Name: "secret", Type: "function", Config: map[string]interface{}{"key": "k"}, Sensitive: true,
+	})
+	withCallback.CallbackURL = server.URL + "/done"
+	id, _ := eng.SubmitWorkflowRequest(context.Background(), &withCallback)
+	other, _ := eng.SubmitWorkflowRequest(context.Background(), &testTemplate)
+	eng.statuses[id], eng.statuses[other] = "failed", "completed"
+
+	ch := make(chan events.Event, 3)
+	ch <- events.Event{Type: "workflow.state_changed", Payload: map[string]any{"workflow_id": id, "new_state": "running"}}
+	ch <- events.Event{Type: "workflow.state_changed", Payload: map[string]any{"workflow_id": other, "new_state": "completed"}}
+	ch <- events.Event{Type: "workflow.state_changed", Payload: map[string]any{"workflow_id": id, "new_state": "failed"}}
+	close(ch)
+	s.DeliverEvents(ch)
+
+	select {
+	case r := <-received:
+		body := <-bodies
+		if r.URL.Path != "/done" || r.Header.Get(HeaderEvent) != EventWorkflowCallback || r.Header.Get(HeaderDelivery) != id {
+			t.Fatalf("callback %s with headers %v, want %s to /done for %s", r.Method, r.Header, EventWorkflowCallback, id)
+		}
+		if err := VerifyWebhook("s3cret", r.Header, body, 0, time.Now()); err != nil {
+			t.Errorf("VerifyWebhook() error = %v", err)
+		}
+		var status models.WorkflowStatusResponse
+		if err := json.Unmarshal(body, &status); err != nil || status.ID != id || status.Status != "failed" {
+			t.Errorf("callback body = %s, %v, want the failed status of %s", body, err, id)
+		}
+		if len(status.Tasks) != 2 || status.Tasks[0].Result != "hello [REDACTED]" ||
+			status.Tasks[1].Result != models.RedactedValue || status.Tasks[1].Config["key"] != models.RedactedValue {
+			t.Errorf("callback tasks = %+v, want the email scrubbed and the sensitive task redacted", status.Tasks)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+	select {
+	case r := <-received:
+		t.Fatalf("unexpected callback with headers %v", r.Header)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestService_RejectsPrivateCallbacks(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	s := New(newFakeEngine())
+	target := models.WebhookSpec{URL: server.URL + "/done"}
+	if err := s.deliverCallbackID(target, "run-1", []byte("{}")); !errors.Is(err, errPrivateCallback) {
+		t.Fatalf("deliverCallbackID() to loopback error = %v, want errPrivateCallback", err)
+	}
+	if received.Load() != 0 {
+		t.Fatal("callback to a loopback address was delivered")
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "::1", "fe80::1", "0.0.0.0"} {
+		if publicIP(net.ParseIP(ip)) {
+			t.Errorf("publicIP(%s) = true, want false", ip)
+		}
+	}
+	if !publicIP(net.ParseIP("93.184.216.34")) {
+		t.Error("publicIP(93.184.216.34) = false, want true")
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	sent := time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(sent.Unix(), 10)
+	body := []byte(`{"type":"workflow.state_changed"}`)
+	header := http.Header{}
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderNonce, "abc123")
+	header.Set(HeaderSignature, webhookSignature("s3cret", timestamp, "abc123", body))
+
+	if err := VerifyWebhook("s3cret", header, body, time.Minute, sent.Add(30*time.Second)); err != nil {
+		t.Fatalf("VerifyWebhook() error = %v", err)
+	}
+	if err := VerifyWebhook("s3cret", header, body, time.Minute, sent.Add(2*time.Minute)); !errors.Is(err, ErrStaleTimestamp) {
+		t.Fatalf("VerifyWebhook() of a replayed delivery error = %v, want ErrStaleTimestamp", err)
+	}
+	if err := VerifyWebhook("other", header, body, time.Minute, sent); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("VerifyWebhook() with another secret error = %v, want ErrInvalidSignature", err)
+	}
+	if err := VerifyWebhook("s3cret", header, []byte(`{"type":"forged"}`), time.Minute, sent); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("VerifyWebhook() of a tampered body error = %v, want ErrInvalidSignature", err)
+	}
+	header.Set(HeaderTimestamp, strconv.FormatInt(sent.Add(time.Hour).Unix(), 10))
+	if err := VerifyWebhook("s3cret", header, body, time.Minute, sent.Add(time.Hour)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("VerifyWebhook() with a moved timestamp error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestService_NotifyDeliversOutboxEvents(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	received := make(chan http.Header, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+	audit := make(chan http.Header, 4)
+	auditServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		audit <- r.Header.Clone()
+	}))
+	defer auditServer.Close()
+
+	s := New(newFakeEngine(), WithOutboxDelivery())
+	event := events.Event{ID: "00000000000000000001-abcd", Type: events.TypeWorkflowStateChanged, Payload: map[string]any{"workflow_id": "wf-1"}}
+	if accepted, err := s.Notify(context.Background(), event, nil); err != nil || len(accepted) != 0 {
+		t.Fatalf("Notify() without webhooks = %v, %v, want it delivered", accepted, err)
+	}
+	if _, _, err := s.PutWebhook("ops", models.WebhookSpec{URL: server.URL}, Precondition{}); err != nil {
+		t.Fatalf("PutWebhook() error = %v", err)
+	}
+	if _, _, err := s.PutWebhook("audit", models.WebhookSpec{URL: auditServer.URL}, Precondition{}); err != nil {
+		t.Fatalf("PutWebhook() error = %v", err)
+	}
+	accepted, err := s.Notify(context.Background(), event, nil)
+	if err == nil || !reflect.DeepEqual(accepted, []string{"audit"}) {
+		t.Fatalf("Notify() with a failing webhook = %v, %v, want an error and audit accepted", accepted, err)
+	}
+	<-audit
+
+	// The retry skips the webhook that accepted the event.
+	failing.Store(false)
+	if accepted, err := s.Notify(context.Background(), event, accepted); err != nil || !reflect.DeepEqual(accepted, []string{"ops"}) {
+		t.Fatalf("Notify() retry = %v, %v, want ops accepted", accepted, err)
+	}
+	select {
+	case header := <-received:
+		if header.Get(HeaderDelivery) != event.ID || header.Get(HeaderEvent) != events.TypeWorkflowStateChanged {
+			t.Fatalf("delivery headers = %v", header)
+		}
+	default:
+		t.Fatal("Notify() returned before the webhook was delivered")
+	}
+	select {
+	case <-audit:
+		t.Fatal("Notify() delivered the retry to a webhook that accepted the event")
+	default:
+	}
+
+	// The broadcast copy of the event is left to the outbox.
+	ch := make(chan events.Event, 1)
+	ch <- event
+	close(ch)
+	s.DeliverEvents(ch)
+	select {
+	case <-received:
+		t.Fatal("DeliverEvents delivered an outbox event")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestService_ScheduleSLAAlerts(t *testing.T) {
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer server.Close()
+
+	eng := newFakeEngine()
+	s := New(eng)
+	start := time.Date(2026, 3, 14, 1, 59, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+	s.PutTemplate("report", testTemplate, Precondition{})
+	if _, _, _, err := s.PutSchedule("nightly", models.ScheduleSpec{
+		Cron:     "0 2 * * *",
+		Template: "report",
+		SLA:      &models.ScheduleSLA{MaxDuration: 3600, FinishWithin: 4 * 3600},
+	}, Precondition{}); err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+	if _, _, err := s.PutWebhook("slack", models.WebhookSpec{URL: server.URL, Events: []string{EventSLABreached}, Format: WebhookFormatSlack}, Precondition{}); err != nil {
+		t.Fatalf("PutWebhook() error = %v", err)
+	}
+
+	s.runDueSchedules(context.Background(), start.Add(time.Minute))
+	if len(eng.submitted) != 1 {
+		t.Fatalf("submitted %d workflows, want 1", len(eng.submitted))
+	}
+	sla := eng.submitted[0].SLA
+	if want := time.Date(2026, 3, 14, 6, 0, 0, 0, time.UTC); sla == nil || sla.MaxDuration != 3600 || sla.FinishBy == nil || !sla.FinishBy.Equal(want) {
+		t.Fatalf("run SLA = %+v, want 3600s and finish by %v", sla, want)
+	}
+
+	ch := make(chan events.Event, 1)
+	ch <- events.Event{Type: EventSLABreached, Timestamp: start.Add(4 * time.Hour), Payload: map[string]any{
+		"workflow_id": "wf-1",
+		"name":        "report",
+		"status":      "running",
+		"deadline":    "2026-03-14T06:00:00Z",
+		"breached_at": "2026-03-14T06:00:05Z",
+		"metadata":    eng.submitted[0].Metadata,
+	}}
+	close(ch)
+	s.DeliverEvents(ch)
+
+	select {
+	case body := <-received:
+		var msg map[string]string
+		if err := json.Unmarshal(body, &msg); err != nil || !strings.Contains(msg["text"], "*report* (`wf-1`) breached its SLA") {
+			t.Fatalf("slack message = %s, want the breach", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slack webhook was not delivered")
+	}
+	_, status, _ := s.GetSchedule("nightly")
+	if status.SLABreaches != 1 || status.LastSLABreachAt == nil || !status.LastSLABreachAt.Equal(start.Add(4*time.Hour)) {
+		t.Fatalf("status = %+v, want one SLA breach", status)
+	}
+}
+
+func TestService_TriggersSubmitAfterUpstreamRuns(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng)
+	s.PutTemplate("report", testTemplate, Precondition{})
+	publish := models.WorkflowRequest{Name: "publish", Tasks: testTemplate.Tasks}
+	s.PutTemplate("publish", publish, Precondition{})
+
+	if _, _, _, err := s.PutTrigger("loop", models.TriggerSpec{
+		After:    []models.TriggerDependency{{Workflow: "publish"}},
+		Template: "publish",
+	}, Precondition{}); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("PutTrigger() on its own template error = %v, want BadRequest", err)
+	}
+	_, status, _, err := s.PutTrigger("publish-after-report", models.TriggerSpec{
+		After: []models.TriggerDependency{
+			{Workflow: "report"},
+			{Labels: map[string]string{"team": "data"}},
+		},
+		Template: "publish",
+	}, Precondition{})
+	if err != nil {
+		t.Fatalf("PutTrigger() error = %v", err)
+	}
+	if !reflect.DeepEqual(status.Satisfied, []string{"", ""}) {
+		t.Fatalf("satisfied = %q, want both dependencies awaited", status.Satisfied)
+	}
+	if err := s.DeleteTemplate("publish", Precondition{}); !errs.Is(err, errs.Conflict) {
+		t.Fatalf("DeleteTemplate() of a triggered template error = %v, want Conflict", err)
+	}
+
+	ctx := context.Background()
+	report, _ := eng.SubmitWorkflowRequest(ctx, &testTemplate)
+	load, _ := eng.SubmitWorkflowRequest(ctx, &models.WorkflowRequest{Name: "load", Metadata: map[string]string{"team": "data"}})
+	other, _ := eng.SubmitWorkflowRequest(ctx, &models.WorkflowRequest{Name: "load", Metadata: map[string]string{"team": "web"}})
+	changed := func(id, state string) events.Event {
+		return events.Event{Type: "workflow.state_changed", Payload: map[string]any{"workflow_id": id, "new_state": state}}
+	}
+
+	ch := make(chan events.Event, 4)
+	ch <- changed(report, "failed")
+	ch <- changed(other, "completed")
+	ch <- changed(report, "completed")
+	close(ch)
+	s.DeliverEvents(ch)
+	_, status, _ = s.GetTrigger("publish-after-report")
+	if !reflect.DeepEqual(status.Satisfied, []string{report, ""}) || len(eng.submitted) != 3 {
+		t.Fatalf("satisfied = %q after %d submissions, want only the report", status.Satisfied, len(eng.submitted))
+	}
+
+	ch = make(chan events.Event, 1)
+	ch <- changed(load, "completed")
+	close(ch)
+	s.DeliverEvents(ch)
+	if len(eng.submitted) != 4 {
+		t.Fatalf("submitted %d workflows, want the triggered run", len(eng.submitted))
+	}
+	run := eng.submitted[3]
+	if run.Name != "publish" || run.Metadata[MetadataTrigger] != "publish-after-report" || run.Metadata[MetadataTriggeredBy] != report+","+load {
+		t.Fatalf("triggered run %s has metadata %v, want its lineage", run.Name, run.Metadata)
+	}
+	_, status, _ = s.GetTrigger("publish-after-report")
+	if !reflect.DeepEqual(status.Satisfied, []string{"", ""}) || status.LastWorkflowID != "publish-4" || status.LastRunAt == nil {
+		t.Fatalf("status = %+v, want the last run and both dependencies awaited again", status)
+	}
+
+	// The triggered run completing satisfies nothing.
+	ch = make(chan events.Event, 1)
+	ch <- changed("publish-4", "completed")
+	close(ch)
+	s.DeliverEvents(ch)
+	if len(eng.submitted) != 4 {
+		t.Fatalf("submitted %d workflows, want no further run", len(eng.submitted))
+	}
+}
+
+func TestService_DiffTemplate(t *testing.T) {
+	eng := newFakeEngine()
+	s := New(eng)
+	template := models.WorkflowRequest{
+		Name: "research",
+		Tasks: []models.TaskDefinition{
+			{ID: "fetch", Name: "fetch", Type: "http", Config: map[string]any{"url": "https://example.com"}},
+			{ID: "summarize", Name: "summarize", Type: "function", DependsOn: []string{"fetch"}},
+			{ID: "publish", Name: "publish", Type: "function", DependsOn: []string{"summarize"}},
+		},
+	}
+	if _, _, err := s.PutTemplate("research", template, Precondition{}); err != nil {
+		t.Fatalf("PutTemplate() error = %v", err)
+	}
+	id, _ := eng.SubmitWorkflowRequest(context.Background(), &models.WorkflowRequest{
+		Name: "research",
+		Tasks: []models.TaskDefinition{
+			{ID: "fetch", Name: "fetch", Type: "http", Config: map[string]any{"url": "https://example.com"}},
+			{ID: "translate", Name: "translate", Type: "function", DependsOn: []string{"fetch"}},
+			{ID: "summarize", Name: "summarize", Type: "function", DependsOn: []string{"translate"}, Retries: 2},
+		},
+	})
+
+	diff, err := s.DiffTemplate(context.Background(), "research", id)
+	if err != nil {
+		t.Fatalf("DiffTemplate() error = %v", err)
+	}
+	want := &models.TemplateDiff{
+		Template:   "research",
+		WorkflowID: id,
+		Added:      []string{"translate"},
+		Removed:    []string{"publish"},
+		Changed:    []models.TaskDiff{{ID: "summarize", Fields: []string{"depends_on", "retries"}}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("DiffTemplate() = %+v, want %+v", diff, want)
+	}
+
+	if _, err := s.DiffTemplate(context.Background(), "missing", id); !errs.Is(err, errs.NotFound) {
+		t.Fatalf("DiffTemplate() of a missing template error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,331 @@
+package manage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/cron"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+// scheduleTick is how often schedules are checked for due runs.
+const scheduleTick = time.Second
+
+// missedAfter is how late a run may start before it counts as missed and
+// the catch-up policy decides whether it runs.
+const missedAfter = time.Minute
+
+// Schedule concurrency policies.
+const (
+	ConcurrencyAllow  = "allow"
+	ConcurrencyForbid = "forbid"
+)
+
+// scheduleState is the parsed spec and observed state of a schedule.
+type scheduleState struct {
+	cron   *cron.Schedule
+	loc    *time.Location
+	status models.ScheduleStatus
+	// after is the time up to which runs were handled.
+	after time.Time
+	// running is set while a run is being submitted.
+	running bool
+}
+
+// GetSchedule returns the schedule name and its status.
+func (s *Service) GetSchedule(name string) (Resource[models.ScheduleSpec], models.ScheduleStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, err := s.schedules.get("schedule", name)
+	if err != nil {
+		return res, models.ScheduleStatus{}, err
+	}
+	return res, s.scheduleStates[name].status, nil
+}
+
+// ListSchedules returns the schedules, sorted by name, and their statuses.
+func (s *Service) ListSchedules() ([]Resource[models.ScheduleSpec], []models.ScheduleStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.schedules.list()
+	statuses := make([]models.ScheduleStatus, len(list))
+	for i, res := range list {
+		statuses[i] = s.scheduleStates[res.Name].status
+	}
+	return list, statuses
+}
+
+// PutSchedule creates or replaces the schedule name. A changed spec
+// computes the next run anew, from the time of the change, so runs missed
+// before it are not caught up on. It reports whether the schedule was
+// created.
+func (s *Service) PutSchedule(name string, spec models.ScheduleSpec, cond Precondition) (Resource[models.ScheduleSpec], models.ScheduleStatus, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if spec.ConcurrencyPolicy == "" {
+		spec.ConcurrencyPolicy = ConcurrencyAllow
+	}
+	if spec.CatchUpPolicy == "" {
+		spec.CatchUpPolicy = cron.CatchUpRunOnce
+	}
+	var state *scheduleState
+	res, created, err := put(s, &s.schedules, "schedule", name, spec, cond, func() error {
+		var err error
+		if state, err = parseSchedule(spec); err != nil {
+			return err
+		}
+		if _, ok := s.templates.items[spec.Template]; !ok {
+			return badRequestf("template %s not found", spec.Template)
+		}
+		for _, name := range spec.Calendars {
+			if _, ok := s.calendars.items[name]; !ok {
+				return badRequestf("calendar %s not found", name)
+			}
+		}
+		switch spec.CatchUpPolicy {
+		case cron.CatchUpSkip, cron.CatchUpRunOnce, cron.CatchUpRunAll:
+		default:
+			return badRequestf("invalid catch-up policy %q", spec.CatchUpPolicy)
+		}
+		return nil
+	})
+	if err != nil {
+		return res, models.ScheduleStatus{}, false, err
+	}
+	prev, ok := s.scheduleStates[name]
+	if state == nil {
+		// The spec is unchanged, so was not parsed again.
+		return res, prev.status, created, nil
+	}
+	if ok {
+		state.status.LastRunAt = prev.status.LastRunAt
+		state.status.LastWorkflowID = prev.status.LastWorkflowID
+		state.running = prev.running
+	}
+	state.after = s.now()
+	state.status.NextRunAt = state.next(spec, state.after, s.excluder(spec))
+	s.scheduleStates[name] = state
+	return res, state.status, created, nil
+}
+
+// DeleteSchedule deletes the schedule name and cancels its backfills.
+func (s *Service) DeleteSchedule(name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := remove(s, &s.schedules, "schedule", name, cond, nil)
+	if err == nil {
+		delete(s.scheduleStates, name)
+		s.cancelBackfills(name)
+		for id, bf := range s.backfills {
+			if bf.status.Schedule == name {
+				delete(s.backfills, id)
+			}
+		}
+	}
+	return err
+}
+
+// parseSchedule returns the state of a schedule with spec, which has not
+// run yet.
+func parseSchedule(spec models.ScheduleSpec) (*scheduleState, error) {
+	state := &scheduleState{loc: time.UTC}
+	var err error
+	if state.cron, err = cron.Parse(spec.Cron); err != nil {
+		return nil, badRequestf("%v", err)
+	}
+	if spec.TimeZone != "" {
+		if state.loc, err = time.LoadLocation(spec.TimeZone); err != nil {
+			return nil, badRequestf("invalid time zone %q", spec.TimeZone)
+		}
+	}
+	return state, nil
+}
+
+// next returns the first run of spec after t that exclude does not
+// exclude, or nil if spec is suspended or never runs.
+func (st *scheduleState) next(spec models.ScheduleSpec, t time.Time, exclude func(time.Time) bool) *time.Time {
+	if spec.Suspend {
+		return nil
+	}
+	next := st.cron.NextIncluded(t.In(st.loc), exclude)
+	if next.IsZero() {
+		return nil
+	}
+	next = next.UTC()
+	return &next
+}
+
+// dueRun is a schedule run to submit.
+type dueRun struct {
+	name     string
+	template models.WorkflowRequest
+	forbid   bool
+	last     string
+	logical  time.Time
+	sla      *models.ScheduleSLA
+}
+
+// runDueSchedules submits the template of every schedule due at now. Runs
+// missed by more than missedAfter are caught up on as the schedule's
+// catch-up policy decides, one run per call; runs its calendars exclude are
+// skipped.
+func (s *Service) runDueSchedules(ctx context.Context, now time.Time) {
+	var due []dueRun
+	skipped := make(map[string]string)
+	s.mu.Lock()
+	for _, res := range s.schedules.list() {
+		state := s.scheduleStates[res.Name]
+		if state.running || state.status.NextRunAt == nil || now.Before(*state.status.NextRunAt) {
+			continue
+		}
+		exclude := s.excluder(res.Spec)
+		pending := state.cron.Due(state.after.In(state.loc), now.In(state.loc), missedAfter, res.Spec.CatchUpPolicy, exclude)
+		var message string
+		if pending.Missed > 0 {
+			message = fmt.Sprintf("skipped %d missed runs", pending.Missed)
+		}
+		if len(pending.Run) == 0 {
+			state.after = pending.Last
+			state.status.NextRunAt = state.next(res.Spec, state.after, exclude)
+			state.status.Message = message
+			continue
+		}
+		// Further runs to catch up on are due again on the next call.
+		state.after = pending.Last
+		if len(pending.Run) > 1 {
+			state.after = pending.Run[0]
+		}
+		state.running = true
+		skipped[res.Name] = message
+		due = append(due, dueRun{
+			name:     res.Name,
+			template: s.templates.items[res.Spec.Template].Spec,
+			forbid:   res.Spec.ConcurrencyPolicy == ConcurrencyForbid,
+			last:     state.status.LastWorkflowID,
+			logical:  pending.Run[0],
+			sla:      res.Spec.SLA,
+		})
+	}
+	s.mu.Unlock()
+
+	for _, run := range due {
+		id, message := s.submit(ctx, run)
+		if message == "" {
+			message = skipped[run.name]
+		}
+
+		s.mu.Lock()
+		state, ok := s.scheduleStates[run.name]
+		if ok {
+			state.running = false
+			runAt := now.UTC()
+			state.status.LastRunAt = &runAt
+			state.status.Message = message
+			if id != "" {
+				state.status.LastWorkflowID = id
+			}
+			if res, ok := s.schedules.items[run.name]; ok {
+				state.status.NextRunAt = state.next(res.Spec, state.after, s.excluder(res.Spec))
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// submit submits the template of run, unless its concurrency policy skips
+// it. It returns the submitted workflow or why there is none.
+func (s *Service) submit(ctx context.Context, run dueRun) (string, string) {
+	if run.forbid && run.last != "" {
+		resp, err := s.engine.GetWorkflowSummaryResponse(ctx, run.last)
+		if err != nil && !errs.Is(err, errs.NotFound) {
+			s.logger.Warn("Failed to check last scheduled workflow", "schedule", run.name, "workflow_id", run.last, "error", err)
+			return "", fmt.Sprintf("skipped run: checking workflow %s: %v", run.last, err)
+		}
+		if err == nil && !isTerminal(resp.Status) {
+			return "", fmt.Sprintf("skipped run: workflow %s is unfinished", run.last)
+		}
+	}
+
+	id, err := s.engine.SubmitWorkflowRequest(ctx, scheduledRequest(run.template, run.name, run.logical, run.sla))
+	if err != nil {
+		s.logger.Warn("Failed to submit scheduled workflow", "schedule", run.name, "error", err)
+		return "", fmt.Sprintf("submission failed: %v", err)
+	}
+	return id, ""
+}
+
+// scheduledRequest returns the template of the schedule name to submit for
+// the run at the logical date: the metadata goclaw.io/logical-date, the
+// config key logical_date of every task and the GOCLAW_LOGICAL_DATE variable
+// of container tasks carry the date, in RFC 3339, so that runs process the
+// period they stand for rather than the time they ran. A schedule SLA, if
+// any, replaces the SLA of the template.
+func scheduledRequest(template models.WorkflowRequest, name string, logical time.Time, sla *models.ScheduleSLA) *models.WorkflowRequest {
+	date := logical.UTC().Format(time.RFC3339)
+	req := template
+	req.Metadata = maps.Clone(req.Metadata)
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string)
+	}
+	req.Metadata["goclaw.io/schedule"] = name
+	req.Metadata["goclaw.io/logical-date"] = date
+	req.Tasks = slices.Clone(req.Tasks)
+	for i := range req.Tasks {
+		task := &req.Tasks[i]
+		task.Config = maps.Clone(task.Config)
+		if task.Config == nil {
+			task.Config = make(map[string]interface{})
+		}
+		task.Config["logical_date"] = date
+		if task.Container != nil {
+			container := *task.Container
+			container.Env = maps.Clone(container.Env)
+			if container.Env == nil {
+				container.Env = make(map[string]string)
+			}
+			container.Env["GOCLAW_LOGICAL_DATE"] = date
+			task.Container = &container
+		}
+	}
+	if sla != nil {
+		req.SLA = &models.SLA{MaxDuration: sla.MaxDuration}
+		if sla.FinishWithin > 0 {
+			finishBy := logical.Add(time.Duration(sla.FinishWithin) * time.Second).UTC()
+			req.SLA.FinishBy = &finishBy
+		}
+	}
+	return &req
+}
+
+// recordSLABreach counts the SLA breach event on the schedule that
+// submitted the run. Backfilled runs are not counted.
+func (s *Service) recordSLABreach(event events.Event) {
+	payload, _ := event.Payload.(map[string]any)
+	metadata, _ := payload["metadata"].(map[string]string)
+	name := metadata["goclaw.io/schedule"]
+	if name == "" || metadata["goclaw.io/backfill"] != "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.scheduleStates[name]
+	if !ok {
+		return
+	}
+	at := event.Timestamp.UTC()
+	state.status.SLABreaches++
+	state.status.LastSLABreachAt = &at
+}
+
+func isTerminal(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
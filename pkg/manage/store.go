@@ -0,0 +1,144 @@
+package manage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// versionKind is the store kind of the version counter, saved under its
+// own name.
+const versionKind = "manage_version"
+
+// storeKind returns the store kind of the resources of kind.
+func storeKind(kind string) string {
+	return "manage_" + kind
+}
+
+// Load restores the resources saved in the store and applies the managed
+// lanes to the engine, which must be started. Schedules compute their next
+// run from now, so runs missed while the node was down are not caught up
+// on. Load is called before SyncWebhooks, so that webhooks declared in the
+// configuration replace saved ones of the same name.
+func (s *Service) Load(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.store.ListResources(ctx, versionKind)
+	if err != nil {
+		return fmt.Errorf("load version: %w", err)
+	}
+	for _, record := range records {
+		if err := json.Unmarshal(record.Data, &s.version); err != nil {
+			return fmt.Errorf("load version: %w", err)
+		}
+	}
+
+	// Schedules refer to calendars and templates, so those are loaded first.
+	if err := load(ctx, s, &s.calendars, "calendar", func(res Resource[models.CalendarSpec]) error {
+		cal, err := parseCalendar(res.Spec)
+		if err != nil {
+			return err
+		}
+		s.parsedCalendars[res.Name] = cal
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := load(ctx, s, &s.templates, "template", nil); err != nil {
+		return err
+	}
+	if err := load(ctx, s, &s.lanes, "lane", func(res Resource[models.LaneSpec]) error {
+		return s.engine.PutLane(res.Name, res.Spec.Capacity, res.Spec.MaxConcurrency, res.Spec.RateLimit)
+	}); err != nil {
+		return err
+	}
+	if err := load(ctx, s, &s.webhooks, "webhook", nil); err != nil {
+		return err
+	}
+	if err := load(ctx, s, &s.schedules, "schedule", func(res Resource[models.ScheduleSpec]) error {
+		state, err := parseSchedule(res.Spec)
+		if err != nil {
+			return err
+		}
+		state.after = s.now()
+		state.status.NextRunAt = state.next(res.Spec, state.after, s.excluder(res.Spec))
+		s.scheduleStates[res.Name] = state
+		return nil
+	}); err != nil {
+		return err
+	}
+	return load(ctx, s, &s.triggers, "trigger", func(res Resource[models.TriggerSpec]) error {
+		s.triggerStates[res.Name] = &models.TriggerStatus{Satisfied: make([]string, len(res.Spec.After))}
+		return nil
+	})
+}
+
+// load restores the saved resources of kind into r, calling restore, when
+// set, with each of them first.
+func load[T any](ctx context.Context, s *Service, r *registry[T], kind string, restore func(Resource[T]) error) error {
+	records, err := s.store.ListResources(ctx, storeKind(kind))
+	if err != nil {
+		return fmt.Errorf("load %ss: %w", kind, err)
+	}
+	for _, record := range records {
+		var res Resource[T]
+		if err := json.Unmarshal(record.Data, &res); err != nil {
+			return fmt.Errorf("load %s %s: %w", kind, record.Name, err)
+		}
+		if restore != nil {
+			if err := restore(res); err != nil {
+				return fmt.Errorf("load %s %s: %w", kind, record.Name, err)
+			}
+		}
+		r.items[res.Name] = res
+		s.version = max(s.version, res.Version)
+	}
+	return nil
+}
+
+// save saves res of kind in the store, if any. The version counter is saved
+// first, so that no version is reused after a restart, even by a resource
+// deleted before it. Webhooks declared in the configuration are declared
+// again on start, so only the counter is saved for them.
+func save[T any](s *Service, kind string, res Resource[T]) error {
+	if s.store == nil {
+		return nil
+	}
+	ctx := context.Background()
+	version, err := json.Marshal(res.Version)
+	if err != nil {
+		return err
+	}
+	if err := s.store.SaveResource(ctx, &storage.ResourceRecord{Kind: versionKind, Name: versionKind, Data: version}); err != nil {
+		return fmt.Errorf("save %s %s: %w", kind, res.Name, err)
+	}
+	if kind == "webhook" && s.configWebhooks[res.Name] {
+		return nil
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	if err := s.store.SaveResource(ctx, &storage.ResourceRecord{Kind: storeKind(kind), Name: res.Name, Data: data}); err != nil {
+		return fmt.Errorf("save %s %s: %w", kind, res.Name, err)
+	}
+	return nil
+}
+
+// unsave deletes the resource name of kind from the store, if any.
+func unsave(s *Service, kind, name string) error {
+	if s.store == nil {
+		return nil
+	}
+	if err := s.store.DeleteResource(context.Background(), storeKind(kind), name); err != nil {
+		return fmt.Errorf("delete %s %s: %w", kind, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package manage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// Triggers watch workflow state change events for completed runs, and
+// callbacks for finished ones.
+const (
+	eventWorkflowStateChanged = "workflow.state_changed"
+	workflowCompleted         = "completed"
+	workflowFailed            = "failed"
+	workflowCancelled         = "cancelled"
+)
+
+// Lineage metadata of triggered runs.
+const (
+	// MetadataTrigger is the name of the trigger that submitted a run.
+	MetadataTrigger = "goclaw.io/trigger"
+	// MetadataTriggeredBy lists the upstream runs, comma-separated in the
+	// order of the trigger's dependencies, whose completion submitted a run.
+	MetadataTriggeredBy = "goclaw.io/triggered-by"
+)
+
+// GetTrigger returns the trigger name and its status.
+func (s *Service) GetTrigger(name string) (Resource[models.TriggerSpec], models.TriggerStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, err := s.triggers.get("trigger", name)
+	if err != nil {
+		return res, models.TriggerStatus{}, err
+	}
+	return res, s.triggerStatus(name), nil
+}
+
+// ListTriggers returns the triggers, sorted by name, and their statuses.
+func (s *Service) ListTriggers() ([]Resource[models.TriggerSpec], []models.TriggerStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.triggers.list()
+	statuses := make([]models.TriggerStatus, len(list))
+	for i, res := range list {
+		statuses[i] = s.triggerStatus(res.Name)
+	}
+	return list, statuses
+}
+
+// PutTrigger creates or replaces the trigger name. A changed spec forgets
+// the completions recorded so far. It reports whether the trigger was
+// created.
+func (s *Service) PutTrigger(name string, spec models.TriggerSpec, cond Precondition) (Resource[models.TriggerSpec], models.TriggerStatus, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := false
+	res, created, err := put(s, &s.triggers, "trigger", name, spec, cond, func() error {
+		template, ok := s.templates.items[spec.Template]
+		if !ok {
+			return badRequestf("template %s not found", spec.Template)
+		}
+		// A run of the template that satisfies a dependency would submit
+		// the template again, forever.
+		metadata := triggeredMetadata(template.Spec.Metadata, name, nil)
+		for _, dep := range spec.After {
+			if dep.Workflow == "" && len(dep.Labels) == 0 {
+				return badRequestf("trigger dependencies need a workflow name or labels")
+			}
+			if matchesDependency(dep, template.Spec.Name, metadata) {
+				return badRequestf("template %s satisfies a dependency of the trigger", spec.Template)
+			}
+		}
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return res, models.TriggerStatus{}, false, err
+	}
+	if changed {
+		status := models.TriggerStatus{Satisfied: make([]string, len(spec.After))}
+		if prev, ok := s.triggerStates[name]; ok {
+			status.LastRunAt = prev.LastRunAt
+			status.LastWorkflowID = prev.LastWorkflowID
+		}
+		s.triggerStates[name] = &status
+	}
+	return res, s.triggerStatus(name), created, nil
+}
+
+// DeleteTrigger deletes the trigger name.
+func (s *Service) DeleteTrigger(name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := remove(s, &s.triggers, "trigger", name, cond, nil)
+	if err == nil {
+		delete(s.triggerStates, name)
+	}
+	return err
+}
+
+// triggerStatus returns a copy of the status of the trigger name.
+func (s *Service) triggerStatus(name string) models.TriggerStatus {
+	status := *s.triggerStates[name]
+	status.Satisfied = append([]string(nil), status.Satisfied...)
+	return status
+}
+
+// triggeredRun is a trigger submission.
+type triggeredRun struct {
+	name     string
+	template models.WorkflowRequest
+	upstream []string
+}
+
+// recordCompletion records the completed run of the state change event on
+// the triggers depending on it, and submits the template of every trigger
+// whose dependencies are now all satisfied.
+func (s *Service) recordCompletion(ctx context.Context, event events.Event) {
+	payload, _ := event.Payload.(map[string]any)
+	id, _ := payload["workflow_id"].(string)
+	if state, _ := payload["new_state"].(string); state != workflowCompleted || id == "" {
+		return
+	}
+	s.mu.Lock()
+	waiting := false
+	for _, res := range s.triggers.items {
+		waiting = waiting || !res.Spec.Suspend
+	}
+	s.mu.Unlock()
+	if !waiting {
+		return
+	}
+	// The event does not carry the metadata that labels match.
+	resp, err := s.engine.GetWorkflowSummaryResponse(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to get completed workflow for triggers", "workflow_id", id, "error", err)
+		return
+	}
+
+	var due []triggeredRun
+	s.mu.Lock()
+	for _, res := range s.triggers.list() {
+		if res.Spec.Suspend {
+			continue
+		}
+		status := s.triggerStates[res.Name]
+		matched := false
+		for i, dep := range res.Spec.After {
+			if matchesDependency(dep, resp.Name, resp.Metadata) {
+				status.Satisfied[i] = id
+				matched = true
+			}
+		}
+		if !matched || slices.Contains(status.Satisfied, "") {
+			continue
+		}
+		due = append(due, triggeredRun{
+			name:     res.Name,
+			template: s.templates.items[res.Spec.Template].Spec,
+			upstream: status.Satisfied,
+		})
+		status.Satisfied = make([]string, len(res.Spec.After))
+	}
+	s.mu.Unlock()
+
+	for _, run := range due {
+		req := run.template
+		req.Metadata = triggeredMetadata(req.Metadata, run.name, run.upstream)
+		id, err := s.engine.SubmitWorkflowRequest(ctx, &req)
+		message := ""
+		if err != nil {
+			s.logger.Warn("Failed to submit triggered workflow", "trigger", run.name, "error", err)
+			message = fmt.Sprintf("submission failed: %v", err)
+		}
+
+		s.mu.Lock()
+		if status, ok := s.triggerStates[run.name]; ok {
+			runAt := s.now().UTC()
+			status.LastRunAt = &runAt
+			status.Message = message
+			if id != "" {
+				status.LastWorkflowID = id
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// triggeredMetadata returns metadata with the lineage of a run of the
+// trigger name submitted after the upstream runs.
+func triggeredMetadata(metadata map[string]string, name string, upstream []string) map[string]string {
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata[MetadataTrigger] = name
+	metadata[MetadataTriggeredBy] = strings.Join(upstream, ",")
+	return metadata
+}
+
+// matchesDependency reports whether a run of the workflow name with
+// metadata matches dep.
+func matchesDependency(dep models.TriggerDependency, name string, metadata map[string]string) bool {
+	if dep.Workflow != "" && dep.Workflow != name {
+		return false
+	}
+	for key, value := range dep.Labels {
+		if got, ok := metadata[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,380 @@
+package manage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/events"
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// Webhook delivery settings.
+const (
+	webhookTimeout          = 10 * time.Second
+	webhookAttempts         = 3
+	webhookRetryDelay       = time.Second
+	maxConcurrentDeliveries = 16
+)
+
+// Webhook formats.
+const (
+	// WebhookFormatJSON posts the event as JSON. It is the default.
+	WebhookFormatJSON = "json"
+	// WebhookFormatSlack posts a message for a Slack incoming webhook.
+	WebhookFormatSlack = "slack"
+)
+
+// EventSLABreached is the type of the event of a workflow run unfinished at
+// its SLA deadline.
+const EventSLABreached = "workflow.sla_breached"
+
+// Headers of webhook deliveries. Every delivery attempt carries the time it
+// was sent, in Unix seconds, and a random nonce; with a secret, the
+// signature is sha256= and the hex HMAC-SHA256 of the timestamp, the nonce
+// and the body, joined with dots.
+const (
+	HeaderEvent     = "X-Goclaw-Event"
+	HeaderSignature = "X-Goclaw-Signature"
+	HeaderTimestamp = "X-Goclaw-Timestamp"
+	HeaderNonce     = "X-Goclaw-Nonce"
+	// HeaderDelivery carries the ID of a notification delivered from the
+	// outbox. Redeliveries of a notification share it.
+	HeaderDelivery = "X-Goclaw-Delivery"
+)
+
+// DefaultSignatureTolerance is how far the timestamp of a delivery may be
+// from the receiver's clock for VerifyWebhook to accept it.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Errors of VerifyWebhook.
+var (
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	ErrStaleTimestamp   = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// GetWebhook returns the webhook name.
+func (s *Service) GetWebhook(name string) (Resource[models.WebhookSpec], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.webhooks.get("webhook", name)
+}
+
+// ListWebhooks returns the webhooks, sorted by name.
+func (s *Service) ListWebhooks() []Resource[models.WebhookSpec] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.webhooks.list()
+}
+
+// PutWebhook creates or replaces the webhook name. It reports whether the
+// webhook was created.
+func (s *Service) PutWebhook(name string, spec models.WebhookSpec, cond Precondition) (Resource[models.WebhookSpec], bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return put(s, &s.webhooks, "webhook", name, spec, cond, func() error {
+		return checkWebhook(spec)
+	})
+}
+
+// checkWebhook returns a BadRequest error unless spec can be delivered to.
+func checkWebhook(spec models.WebhookSpec) error {
+	u, err := url.Parse(spec.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return badRequestf("webhook url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// SyncWebhooks makes specs the webhooks declared in the configuration: they
+// are created or replaced, and the webhooks of an earlier call missing from
+// specs are deleted. Webhooks put through the API under other names are left
+// alone. No webhook changes unless every spec is valid.
+func (s *Service) SyncWebhooks(specs map[string]models.WebhookSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, spec := range specs {
+		if err := checkWebhook(spec); err != nil {
+			return fmt.Errorf("webhook %s: %w", name, err)
+		}
+	}
+	for name := range s.configWebhooks {
+		if _, ok := specs[name]; !ok {
+			_ = remove(s, &s.webhooks, "webhook", name, Precondition{}, nil)
+		}
+	}
+	s.configWebhooks = make(map[string]bool, len(specs))
+	for name, spec := range specs {
+		// Marked first, so that the webhook is not saved.
+		s.configWebhooks[name] = true
+		if _, _, err := put(s, &s.webhooks, "webhook", name, spec, Precondition{}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook name.
+func (s *Service) DeleteWebhook(name string, cond Precondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return remove(s, &s.webhooks, "webhook", name, cond, nil)
+}
+
+// DeliverEvents delivers every event of ch to the webhooks subscribed to
+// its type, until ch is closed, counts SLA breaches of scheduled runs on
+// their schedule, records completed runs on the triggers depending on
+// them and posts the status of finished runs to their callback URL.
+// Deliveries run concurrently; when too many are outstanding,
+// DeliverEvents waits for one to finish.
+func (s *Service) DeliverEvents(ch <-chan events.Event) {
+	for event := range ch {
+		switch event.Type {
+		case EventSLABreached:
+			s.recordSLABreach(event)
+		case eventWorkflowStateChanged:
+			s.recordCompletion(context.Background(), event)
+			if state, _ := event.Payload.(map[string]any)["new_state"].(string); isTerminalState(state) {
+				s.deliveries <- struct{}{}
+				go func() {
+					defer func() { <-s.deliveries }()
+					s.deliverCallback(context.Background(), event)
+				}()
+			}
+		}
+		if s.outboxDelivery && isOutboxEvent(event.Type) {
+			continue
+		}
+		targets := s.webhookTargets(event.Type)
+		if len(targets) == 0 {
+			continue
+		}
+
+		bodies := make(map[string][]byte, 1)
+		for _, res := range targets {
+			target := res.Spec
+			body, ok := bodies[target.Format]
+			if !ok {
+				var err error
+				if body, err = webhookBody(target.Format, event); err != nil {
+					s.logger.Warn("Failed to encode webhook event", "type", event.Type, "error", err)
+					continue
+				}
+				bodies[target.Format] = body
+			}
+			s.deliveries <- struct{}{}
+			go func() {
+				defer func() { <-s.deliveries }()
+				if err := s.deliver(target, event.Type, body); err != nil {
+					s.logger.Warn("Failed to deliver webhook", "url", target.URL, "type", event.Type, "error", err)
+				}
+			}()
+		}
+	}
+}
+
+// Notify delivers event to the webhooks subscribed to its type but not
+// named in delivered, and waits for the deliveries. It returns the names of
+// the webhooks that accepted the event and the errors of the others, so that
+// the outbox retries only those. An event no webhook is subscribed to is
+// delivered. Receivers should still deduplicate on the HeaderDelivery
+// header, as a delivery may be repeated when it is not recorded in time.
+func (s *Service) Notify(ctx context.Context, event events.Event, delivered []string) ([]string, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted []string
+		failures []error
+	)
+	for _, res := range s.webhookTargets(event.Type) {
+		if slices.Contains(delivered, res.Name) {
+			continue
+		}
+		target := res.Spec
+		body, err := webhookBody(target.Format, event)
+		if err != nil {
+			return nil, fmt.Errorf("encode webhook event: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.deliverID(target, event.Type, event.ID, body)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, fmt.Errorf("%s: %w", target.URL, err))
+				return
+			}
+			accepted = append(accepted, res.Name)
+		}()
+	}
+	wg.Wait()
+	sort.Strings(accepted)
+	if err := errors.Join(failures...); err != nil {
+		return accepted, err
+	}
+	return accepted, ctx.Err()
+}
+
+// isOutboxEvent reports whether events of the type are delivered from the
+// engine's outbox when it is enabled.
+func isOutboxEvent(eventType string) bool {
+	return eventType == events.TypeWorkflowStateChanged || eventType == events.TypeWorkflowSLABreached
+}
+
+// webhookTargets returns the webhooks subscribed to events of the type.
+func (s *Service) webhookTargets(eventType string) []Resource[models.WebhookSpec] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var targets []Resource[models.WebhookSpec]
+	for _, res := range s.webhooks.items {
+		if len(res.Spec.Events) == 0 || slices.Contains(res.Spec.Events, eventType) {
+			targets = append(targets, res)
+		}
+	}
+	return targets
+}
+
+// webhookBody encodes event in the webhook format.
+func webhookBody(format string, event events.Event) ([]byte, error) {
+	if format != WebhookFormatSlack {
+		return json.Marshal(event)
+	}
+	return json.Marshal(map[string]string{"text": slackText(event)})
+}
+
+// slackText summarises event in a line of Slack markup.
+func slackText(event events.Event) string {
+	payload, _ := event.Payload.(map[string]any)
+	if event.Type == EventSLABreached {
+		return fmt.Sprintf(":warning: Workflow *%v* (`%v`) breached its SLA: due by %v, %v at %v",
+			payload["name"], payload["workflow_id"], payload["deadline"], payload["status"], payload["breached_at"])
+	}
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, payload[key]))
+	}
+	return fmt.Sprintf("goclaw `%s` %s", event.Type, strings.Join(fields, " "))
+}
+
+// deliver posts body to the webhook, retrying network errors and server
+// errors.
+func (s *Service) deliver(target models.WebhookSpec, eventType string, body []byte) error {
+	return s.deliverID(target, eventType, "", body)
+}
+
+// deliverID is deliver with the ID of an outbox notification.
+func (s *Service) deliverID(target models.WebhookSpec, eventType, id string, body []byte) error {
+	return s.deliverIDWith(s.client, target, eventType, id, body)
+}
+
+// deliverIDWith is deliverID through client.
+func (s *Service) deliverIDWith(client *http.Client, target models.WebhookSpec, eventType, id string, body []byte) error {
+	var err error
+	for attempt := 0; attempt < webhookAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(webhookRetryDelay << (attempt - 1))
+		}
+		var retry bool
+		if retry, err = s.post(client, target, eventType, id, body); !retry {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Service) post(client *http.Client, target models.WebhookSpec, eventType, id string, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	nonce, err := newNonce()
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	if id != "" {
+		req.Header.Set(HeaderDelivery, id)
+	}
+	if target.Secret != "" {
+		req.Header.Set(HeaderSignature, webhookSignature(target.Secret, timestamp, nonce, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// A refused address stays refused.
+		return !errors.Is(err, errPrivateCallback), err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode >= 500, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// newNonce returns a random nonce, unique to a delivery attempt.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// webhookSignature signs a delivery attempt with secret.
+func webhookSignature(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook lets receivers check that a delivery was signed with secret
+// and sent within tolerance of now; zero uses DefaultSignatureTolerance.
+// To reject replays within the tolerance, receivers also remember the
+// HeaderNonce values they accepted for at least that long.
+func VerifyWebhook(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	timestamp, nonce := header.Get(HeaderTimestamp), header.Get(HeaderNonce)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || nonce == "" {
+		return ErrInvalidSignature
+	}
+	want := webhookSignature(secret, timestamp, nonce, body)
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(want)) {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(sent, 0)); skew > tolerance || skew < -tolerance {
+		return ErrStaleTimestamp
+	}
+	return nil
+}
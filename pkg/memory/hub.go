@@ -295,6 +295,34 @@ func (h *MemoryHub) DeleteSession(ctx context.Context, sessionID string) (int, e
 	return h.storage.DeleteBySession(ctx, sessionID)
 }
 
+// Entries returns the entries of every session, with their metadata and
+// decay state.
+func (h *MemoryHub) Entries(ctx context.Context) ([]*MemoryEntry, error) {
+	return h.storage.All(ctx)
+}
+
+// Restore stores entries as they are, keeping their IDs and decay state, and
+// indexes them for retrieval.
+func (h *MemoryHub) Restore(ctx context.Context, entries []*MemoryEntry) error {
+	for _, entry := range entries {
+		if entry.SessionID == "" {
+			return ErrInvalidSessionID
+		}
+		if err := h.storage.Store(ctx, entry); err != nil {
+			return fmt.Errorf("memory: restore entry %s failed: %w", entry.ID, err)
+		}
+		if len(entry.Vector) > 0 {
+			if err := h.vector.AddVector(entry.ID, entry.SessionID, entry.Vector); err != nil {
+				h.logger.Warn("failed to index vector", "entry_id", entry.ID, "error", err)
+			}
+		}
+		if entry.Content != "" {
+			h.bm25.IndexDocument(entry.ID, entry.SessionID, entry.Content)
+		}
+	}
+	return nil
+}
+
 // processDecay is the callback for the decay loop.
 func (h *MemoryHub) processDecay(ctx context.Context) error {
 	h.logger.Debug("running memory decay cycle")
@@ -287,6 +287,33 @@ func (s *L2Badger) AllBySession(ctx context.Context, sessionID string) ([]*Memor
 	return entries, err
 }
 
+// All returns the entries of every session, ordered by session and entry
+// ID, as of a single read transaction.
+func (s *L2Badger) All(ctx context.Context) ([]*MemoryEntry, error) {
+	var entries []*MemoryEntry
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(memoryKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var entry MemoryEntry
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry)
+			}); err != nil {
+				return err
+			}
+			entries = append(entries, &entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
 // Close is a no-op since the Badger DB lifecycle is managed externally.
 func (s *L2Badger) Close() error {
 	return nil
@@ -364,6 +391,11 @@ func (t *TieredStorage) AllBySession(ctx context.Context, sessionID string) ([]*
 	return t.l2.AllBySession(ctx, sessionID)
 }
 
+// All delegates to L2.
+func (t *TieredStorage) All(ctx context.Context) ([]*MemoryEntry, error) {
+	return t.l2.All(ctx)
+}
+
 // Close delegates to L2.
 func (t *TieredStorage) Close() error {
 	return t.l2.Close()
@@ -68,16 +68,19 @@ func (m *Manager) recordHTTPRequest(ctx context.Context, method, path, status st
 		requestCounter.Inc()
 	}
 
-	requestDuration := m.httpDuration.WithLabelValues(method, path)
-	if hasExemplar {
-		if exemplarObserver, ok := requestDuration.(prometheus.ExemplarObserver); ok {
-			exemplarObserver.ObserveWithExemplar(duration.Seconds(), exemplar)
-		} else {
-			requestDuration.Observe(duration.Seconds())
+	observeWithTrace(ctx, m.httpDuration.WithLabelValues(method, path), duration.Seconds())
+}
+
+// observeWithTrace observes value, with the trace labels of ctx as exemplar
+// when ctx carries a valid span context.
+func observeWithTrace(ctx context.Context, observer prometheus.Observer, value float64) {
+	if exemplar, ok := traceExemplarLabels(ctx); ok {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(value, exemplar)
+			return
 		}
-	} else {
-		requestDuration.Observe(duration.Seconds())
 	}
+	observer.Observe(value)
 }
 
 // IncActiveConnections increments the active HTTP connections count.
@@ -0,0 +1,67 @@
+package metrics
+
+import "sync"
+
+// OtherLabelValue replaces the label values past the cardinality limit.
+const OtherLabelValue = "other"
+
+// defaultMaxLabelValues is the number of distinct workflow names and tenants
+// labelled unless configured otherwise.
+const defaultMaxLabelValues = 100
+
+// labelLimiter bounds the distinct values of a label. The first max values
+// seen keep their own series; the rest share OtherLabelValue, so a burst of
+// new tenants cannot grow the series count without bound.
+type labelLimiter struct {
+	mu   sync.Mutex
+	max  int
+	seen map[string]struct{}
+}
+
+func newLabelLimiter(max int) *labelLimiter {
+	if max <= 0 {
+		max = defaultMaxLabelValues
+	}
+	return &labelLimiter{max: max, seen: make(map[string]struct{})}
+}
+
+// value returns v if it has its own series or there is room for it, and
+// OtherLabelValue otherwise.
+func (l *labelLimiter) value(v string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+	if len(l.seen) >= l.max {
+		return OtherLabelValue
+	}
+	l.seen[v] = struct{}{}
+	return v
+}
+
+// runLabelNames returns base followed by the workflow and tenant labels that
+// are enabled.
+func (m *Manager) runLabelNames(base ...string) []string {
+	names := append([]string(nil), base...)
+	if m.workflowLabel {
+		names = append(names, "workflow")
+	}
+	if m.tenantLabel {
+		names = append(names, "tenant")
+	}
+	return names
+}
+
+// runLabelValues returns base followed by the bounded values of the workflow
+// and tenant labels that are enabled.
+func (m *Manager) runLabelValues(workflowName, tenant string, base ...string) []string {
+	values := append([]string(nil), base...)
+	if m.workflowLabel {
+		values = append(values, m.workflowValues.value(workflowName))
+	}
+	if m.tenantLabel {
+		values = append(values, m.tenantValues.value(tenant))
+	}
+	return values
+}
@@ -41,6 +41,22 @@ func (m *Manager) initLaneMetrics(cfg Config) {
 		[]string{"lane_name", "outcome"},
 	)
 
+	m.laneDeadlineMiss = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lane_deadline_missed_total",
+			Help: "Total number of tasks that finished after their deadline",
+		},
+		[]string{"lane_name"},
+	)
+
+	m.laneResourceUtil = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lane_resource_utilization",
+			Help: "Share of a lane's resource capacity held by running tasks",
+		},
+		[]string{"lane_name", "resource"},
+	)
+
 	m.redisQueueDepth = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "redis_lane_queue_depth",
@@ -70,6 +86,8 @@ func (m *Manager) initLaneMetrics(cfg Config) {
 	m.registry.MustRegister(m.laneWaitDuration)
 	m.registry.MustRegister(m.laneThroughput)
 	m.registry.MustRegister(m.laneSubmission)
+	m.registry.MustRegister(m.laneDeadlineMiss)
+	m.registry.MustRegister(m.laneResourceUtil)
 	m.registry.MustRegister(m.redisQueueDepth)
 	m.registry.MustRegister(m.redisSubmitDur)
 	m.registry.MustRegister(m.redisThroughput)
@@ -129,6 +147,23 @@ func (m *Manager) RecordSubmissionOutcome(laneName string, outcome string) {
 	}
 }
 
+// RecordDeadlineMissed records a task that finished after its deadline.
+func (m *Manager) RecordDeadlineMissed(laneName string) {
+	if !m.enabled {
+		return
+	}
+	m.laneDeadlineMiss.WithLabelValues(laneName).Inc()
+}
+
+// RecordResourceUtilization sets the share (0.0 - 1.0) of a lane's capacity
+// of resource that running tasks hold.
+func (m *Manager) RecordResourceUtilization(laneName, resource string, utilization float64) {
+	if !m.enabled {
+		return
+	}
+	m.laneResourceUtil.WithLabelValues(laneName, resource).Set(utilization)
+}
+
 // SetRedisQueueDepth sets the current queue depth for a Redis-backed lane.
 func (m *Manager) SetRedisQueueDepth(laneName string, depth float64) {
 	if !m.enabled {
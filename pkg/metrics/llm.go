@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (m *Manager) initLLMCacheMetrics() {
+	m.llmCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "llm_cache_lookups_total",
+			Help: "Total number of LLM cache lookups by model and result (hit or miss)",
+		},
+		[]string{"model", "result"},
+	)
+
+	m.registry.MustRegister(m.llmCacheLookups)
+}
+
+// RecordLLMCacheLookup records a lookup of an LLM call of model in the
+// cache.
+func (m *Manager) RecordLLMCacheLookup(model string, hit bool) {
+	if !m.enabled {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.llmCacheLookups.WithLabelValues(model, result).Inc()
+}
+
+func (m *Manager) initModelRouteMetrics() {
+	m.modelRouteCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "llm_route_calls_total",
+			Help: "Total number of LLM calls made through model routes by route, provider, model and result",
+		},
+		[]string{"route", "provider", "model", "result"},
+	)
+
+	m.modelRouteDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "llm_route_call_duration_seconds",
+			Help:    "Duration of LLM calls made through model routes by route, provider and model",
+			Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 120},
+		},
+		[]string{"route", "provider", "model"},
+	)
+
+	m.registry.MustRegister(m.modelRouteCalls, m.modelRouteDuration)
+}
+
+// RecordModelRouteCall records a call of an LLM through the route name with
+// the given provider and model, and its result: success, rate_limited,
+// canceled or error.
+func (m *Manager) RecordModelRouteCall(route, provider, model, result string, duration time.Duration) {
+	if !m.enabled {
+		return
+	}
+	m.modelRouteCalls.WithLabelValues(route, provider, model, result).Inc()
+	m.modelRouteDuration.WithLabelValues(route, provider, model).Observe(duration.Seconds())
+}
@@ -17,37 +17,63 @@ type Manager struct {
 	registry *prometheus.Registry
 	enabled  bool
 
+	// Optional workflow name and tenant labels and their cardinality limits
+	workflowLabel  bool
+	tenantLabel    bool
+	workflowValues *labelLimiter
+	tenantValues   *labelLimiter
+
 	// Workflow metrics
 	workflowSubmissions *prometheus.CounterVec
 	workflowDuration    *prometheus.HistogramVec
 	workflowActive      *prometheus.GaugeVec
+	workflowSLABreaches *prometheus.CounterVec
 
 	// Task metrics
 	taskExecutions *prometheus.CounterVec
 	taskDuration   *prometheus.HistogramVec
 	taskRetries    *prometheus.CounterVec
+	taskPreempts   *prometheus.CounterVec
+
+	// Expression metrics
+	exprEvaluations *prometheus.CounterVec
+	exprDuration    *prometheus.HistogramVec
+
+	// Priority inversion metrics
+	priorityInversions    *prometheus.CounterVec
+	priorityInherited     *prometheus.CounterVec
+	priorityInversionWait *prometheus.HistogramVec
 
 	// Lane metrics
 	laneQueueDepth   *prometheus.GaugeVec
 	laneWaitDuration *prometheus.HistogramVec
 	laneThroughput   *prometheus.CounterVec
 	laneSubmission   *prometheus.CounterVec
+	laneDeadlineMiss *prometheus.CounterVec
+	laneResourceUtil *prometheus.GaugeVec
 	redisQueueDepth  *prometheus.GaugeVec
 	redisSubmitDur   *prometheus.HistogramVec
 	redisThroughput  *prometheus.CounterVec
 
 	// Signal/message metrics
-	signalSent       *prometheus.CounterVec
-	signalReceived   *prometheus.CounterVec
-	signalFailures   *prometheus.CounterVec
-	signalPatternOps *prometheus.CounterVec
-	signalPatternDur *prometheus.HistogramVec
+	signalSent        *prometheus.CounterVec
+	signalReceived    *prometheus.CounterVec
+	signalFailures    *prometheus.CounterVec
+	signalAcked       *prometheus.CounterVec
+	signalRedelivered *prometheus.CounterVec
+	signalPatternOps  *prometheus.CounterVec
+	signalPatternDur  *prometheus.HistogramVec
 
 	// HTTP metrics
 	httpRequests    *prometheus.CounterVec
 	httpDuration    *prometheus.HistogramVec
 	httpConnections prometheus.Gauge
 
+	// LLM metrics
+	llmCacheLookups    *prometheus.CounterVec
+	modelRouteCalls    *prometheus.CounterVec
+	modelRouteDuration *prometheus.HistogramVec
+
 	// Saga metrics
 	sagaExecutions           *prometheus.CounterVec
 	sagaDuration             *prometheus.HistogramVec
@@ -56,6 +82,7 @@ type Manager struct {
 	sagaCompensationDuration *prometheus.HistogramVec
 	sagaCompensationRetries  *prometheus.CounterVec
 	sagaRecovery             *prometheus.CounterVec
+	sagaStuck                *prometheus.CounterVec
 
 	// Distributed event-bus and ownership metrics
 	ownershipChanges       *prometheus.CounterVec
@@ -78,6 +105,14 @@ type Config struct {
 	TaskDurationBuckets     []float64
 	LaneWaitBuckets         []float64
 	HTTPDurationBuckets     []float64
+
+	// WorkflowLabel and TenantLabel label the workflow and task submission
+	// and duration metrics with the workflow name and tenant. At most
+	// MaxLabelValues distinct values of each are kept; later ones are
+	// reported as OtherLabelValue.
+	WorkflowLabel  bool
+	TenantLabel    bool
+	MaxLabelValues int
 }
 
 // DefaultConfig returns default metrics configuration.
@@ -90,6 +125,7 @@ func DefaultConfig() Config {
 		TaskDurationBuckets:     []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
 		LaneWaitBuckets:         []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30},
 		HTTPDurationBuckets:     []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		MaxLabelValues:          defaultMaxLabelValues,
 	}
 }
 
@@ -106,8 +142,12 @@ func NewManager(cfg Config) *Manager {
 	registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 
 	m := &Manager{
-		registry: registry,
-		enabled:  true,
+		registry:       registry,
+		enabled:        true,
+		workflowLabel:  cfg.WorkflowLabel,
+		tenantLabel:    cfg.TenantLabel,
+		workflowValues: newLabelLimiter(cfg.MaxLabelValues),
+		tenantValues:   newLabelLimiter(cfg.MaxLabelValues),
 	}
 
 	m.initWorkflowMetrics(cfg)
@@ -117,6 +157,8 @@ func NewManager(cfg Config) *Manager {
 	m.initHTTPMetrics(cfg)
 	m.initSagaMetrics(cfg)
 	m.initDistributedMetrics()
+	m.initLLMCacheMetrics()
+	m.initModelRouteMetrics()
 
 	return m
 }
@@ -133,7 +175,9 @@ func (m *Manager) Handler() http.Handler {
 			w.WriteHeader(http.StatusNotFound)
 		})
 	}
-	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	// Exemplars are only exposed in the OpenMetrics format, which scrapers
+	// negotiate with the Accept header.
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
 }
 
 // StartServer starts the metrics HTTP server on the configured port.
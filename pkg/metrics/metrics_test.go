@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -157,6 +158,58 @@ func TestNoOpManager(t *testing.T) {
 	m.RecordCompensationDuration(time.Second)
 	m.RecordCompensationRetry()
 	m.RecordSagaRecovery("success")
+	m.RecordSagaStuck("running", "escalated")
+	m.RecordTaskPreemption("default")
+	m.RecordExpressionEvaluation("when", "ok", time.Millisecond)
+	m.RecordPriorityInversion("default", 2)
+	m.RecordPriorityInversionWait("default", time.Second)
+	m.RecordSLABreach("nightly-report")
+	m.RecordLLMCacheLookup("gpt-4o-mini", true)
+	m.RecordModelRouteCall("chat", "openai", "gpt-4o", "success", time.Second)
+	m.RecordWorkflowSubmissionFor("completed", "etl", "team-a")
+	m.RecordWorkflowDurationFor(context.Background(), "completed", "etl", "team-a", time.Second)
+	m.RecordTaskExecutionFor("completed", "etl", "team-a")
+	m.RecordTaskDurationFor(context.Background(), "etl", "team-a", time.Second)
+	m.RecordDeadlineMissed("default")
+	m.RecordResourceUtilization("default", "cpu", 0.5)
+}
+
+func TestRunLabels_CardinalityLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WorkflowLabel = true
+	cfg.TenantLabel = true
+	cfg.MaxLabelValues = 2
+
+	m := NewManager(cfg)
+	for _, tenant := range []string{"team-a", "team-b", "team-c", "team-d"} {
+		m.RecordWorkflowSubmissionFor("completed", "etl", tenant)
+		m.RecordTaskDurationFor(context.Background(), "etl", tenant, time.Second)
+	}
+	m.RecordWorkflowSubmission("completed")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, req)
+	body := w.Body.String()
+
+	for _, want := range []string{
+		`workflow_submissions_total{status="completed",tenant="team-a",workflow="etl"} 1`,
+		`workflow_submissions_total{status="completed",tenant="team-b",workflow="etl"} 1`,
+		`workflow_submissions_total{status="completed",tenant="other",workflow="etl"} 2`,
+		`task_duration_seconds_count{tenant="other",workflow="etl"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in metrics output", want)
+		}
+	}
+	if strings.Contains(body, "team-c") {
+		t.Error("tenant past the cardinality limit has its own series")
+	}
+	// Each label has its own limit: "" is the second workflow name but the
+	// third tenant.
+	if !strings.Contains(body, `workflow_submissions_total{status="completed",tenant="other",workflow=""} 1`) {
+		t.Errorf("expected the unlabelled submission under other tenant, got:\n%s", body)
+	}
 }
 
 func contains(s, substr string) bool {
@@ -267,6 +320,8 @@ func TestSignalAndRedisMetricsRegistered(t *testing.T) {
 	m.RecordSignalSent("local", "steer")
 	m.RecordSignalReceived("local", "steer")
 	m.RecordSignalFailed("local", "steer", "no_subscriber")
+	m.RecordSignalAcked("redis", "steer")
+	m.RecordSignalRedelivered("redis", "steer")
 	m.RecordSignalPattern("steer", "success", 2*time.Millisecond)
 
 	req := httptest.NewRequest("GET", "/metrics", nil)
@@ -285,6 +340,8 @@ func TestSignalAndRedisMetricsRegistered(t *testing.T) {
 		"signal_sent_total",
 		"signal_received_total",
 		"signal_failures_total",
+		"signal_acked_total",
+		"signal_redelivered_total",
 		"signal_pattern_total",
 		"signal_pattern_duration_seconds",
 	}
@@ -341,6 +398,7 @@ func TestSagaMetricsRegistered(t *testing.T) {
 	m.RecordCompensationDuration(8 * time.Millisecond)
 	m.RecordCompensationRetry()
 	m.RecordSagaRecovery("success")
+	m.RecordSagaStuck("running", "escalated")
 
 	req := httptest.NewRequest("GET", "/metrics", nil)
 	w := httptest.NewRecorder()
@@ -359,6 +417,7 @@ func TestSagaMetricsRegistered(t *testing.T) {
 		"saga_compensation_duration_seconds",
 		"saga_compensation_retries_total",
 		"saga_recovery_total",
+		"saga_stuck_total",
 	}
 	for _, metric := range expected {
 		if !contains(body, metric) {
@@ -366,3 +425,92 @@ func TestSagaMetricsRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestDashboard_PanelsFromRegistry(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	m := NewManager(cfg)
+	m.RecordWorkflowSubmission("completed")
+	m.RecordWorkflowDuration("completed", time.Second)
+	m.SetQueueDepth("cpu", 3)
+
+	imp, err := m.Dashboard(MonitoringTarget{Lanes: []LaneTarget{{Name: "cpu", Capacity: 10}, {Name: "io", Capacity: 5}}})
+	if err != nil {
+		t.Fatalf("Dashboard() error = %v", err)
+	}
+
+	exprs := make(map[string]string)
+	for _, p := range imp.Dashboard.Panels {
+		if strings.HasPrefix(p.Title, "go_") || strings.HasPrefix(p.Title, "process_") {
+			t.Fatalf("runtime family %s has a panel", p.Title)
+		}
+		if len(p.Targets) > 0 {
+			exprs[p.Title] = p.Targets[0].Expr
+		}
+	}
+	if got, want := exprs["workflow_submissions_total (rate)"], "sum by (status) (rate(workflow_submissions_total[5m]))"; got != want {
+		t.Errorf("submissions expr = %q, want %q", got, want)
+	}
+	if got, want := exprs["workflow_duration_seconds (p95)"], "histogram_quantile(0.95, sum by (le, status) (rate(workflow_duration_seconds_bucket[5m])))"; got != want {
+		t.Errorf("duration expr = %q, want %q", got, want)
+	}
+	if got, want := exprs["lane_queue_depth"], `sum by (lane_name) (lane_queue_depth{lane_name=~"$lane"})`; got != want {
+		t.Errorf("queue depth expr = %q, want %q", got, want)
+	}
+
+	lanes := imp.Dashboard.Templating.List[1]
+	if lanes.Name != "lane" || lanes.Query != "cpu,io" || len(lanes.Options) != 2 {
+		t.Errorf("unexpected lane variable %+v", lanes)
+	}
+}
+
+func TestAlertRules_TunedToTarget(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	m := NewManager(cfg)
+
+	target := MonitoringTarget{Lanes: []LaneTarget{{Name: "cpu", Capacity: 100}}}
+	rules, err := m.AlertRules(target)
+	if err != nil {
+		t.Fatalf("AlertRules() error = %v", err)
+	}
+	var exprs []string
+	for _, g := range rules.Groups {
+		if g.Name == "goclaw_sagas" {
+			t.Fatal("saga rules generated with sagas disabled")
+		}
+		for _, r := range g.Rules {
+			exprs = append(exprs, r.Expr)
+		}
+	}
+	if !strings.Contains(strings.Join(exprs, "\n"), `lane_queue_depth{lane_name="cpu"} > 80`) {
+		t.Errorf("no backlog rule at 80%% of the cpu lane capacity in %v", exprs)
+	}
+
+	target.SagaEnabled = true
+	target.SagaTimeout = 10 * time.Minute
+	target.CompensationMaxRetries = 3
+	rules, err = m.AlertRules(target)
+	if err != nil {
+		t.Fatalf("AlertRules() error = %v", err)
+	}
+	var saga *RuleGroup
+	for i := range rules.Groups {
+		if rules.Groups[i].Name == "goclaw_sagas" {
+			saga = &rules.Groups[i]
+		}
+	}
+	if saga == nil || len(saga.Rules) != 4 {
+		t.Fatalf("saga rules = %+v", saga)
+	}
+	if saga.Rules[1].Alert != "GoclawSagaStuck" {
+		t.Errorf("saga rule 1 = %q, want the stuck saga alert", saga.Rules[1].Alert)
+	}
+	if !strings.HasSuffix(saga.Rules[2].Expr, "> 480") {
+		t.Errorf("near-timeout expr = %q, want a threshold of 480s", saga.Rules[2].Expr)
+	}
+
+	if _, err := NewManager(Config{}).AlertRules(target); err == nil {
+		t.Error("expected an error with metrics disabled")
+	}
+}
@@ -0,0 +1,450 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// LaneTarget is a configured lane the generated dashboards and alerts are
+// tuned to.
+type LaneTarget struct {
+	Name     string
+	Capacity int
+}
+
+// MonitoringTarget describes the deployment the generated dashboards and
+// alerts are tuned to.
+type MonitoringTarget struct {
+	Lanes []LaneTarget
+
+	SagaEnabled            bool
+	SagaTimeout            time.Duration
+	CompensationMaxRetries int
+}
+
+// GrafanaImport is a dashboard in the format of the Grafana import API.
+type GrafanaImport struct {
+	Dashboard GrafanaDashboard `json:"dashboard"`
+	Overwrite bool             `json:"overwrite"`
+}
+
+// GrafanaDashboard is a Grafana dashboard model.
+type GrafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Timezone      string            `json:"timezone"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Refresh       string            `json:"refresh"`
+	Time          GrafanaTimeRange  `json:"time"`
+	Templating    GrafanaTemplating `json:"templating"`
+	Panels        []GrafanaPanel    `json:"panels"`
+}
+
+// GrafanaTimeRange is the default time range of a dashboard.
+type GrafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GrafanaTemplating holds the variables of a dashboard.
+type GrafanaTemplating struct {
+	List []GrafanaVariable `json:"list"`
+}
+
+// GrafanaVariable is a dashboard variable.
+type GrafanaVariable struct {
+	Name       string          `json:"name"`
+	Label      string          `json:"label"`
+	Type       string          `json:"type"`
+	Query      string          `json:"query"`
+	IncludeAll bool            `json:"includeAll,omitempty"`
+	Multi      bool            `json:"multi,omitempty"`
+	Options    []GrafanaOption `json:"options,omitempty"`
+}
+
+// GrafanaOption is a value of a custom dashboard variable.
+type GrafanaOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// GrafanaPanel is a dashboard panel; rows group the panels below them.
+type GrafanaPanel struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Datasource  *GrafanaDatasource `json:"datasource,omitempty"`
+	GridPos     GrafanaGridPos     `json:"gridPos"`
+	Targets     []GrafanaTarget    `json:"targets,omitempty"`
+	FieldConfig *GrafanaFieldCfg   `json:"fieldConfig,omitempty"`
+}
+
+// GrafanaDatasource references the data source of a panel.
+type GrafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// GrafanaGridPos is the position of a panel.
+type GrafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// GrafanaTarget is a PromQL query of a panel.
+type GrafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	// Exemplar shows the trace exemplars of histogram queries.
+	Exemplar bool `json:"exemplar,omitempty"`
+}
+
+// GrafanaFieldCfg sets the unit of a panel.
+type GrafanaFieldCfg struct {
+	Defaults GrafanaFieldDefaults `json:"defaults"`
+}
+
+// GrafanaFieldDefaults holds the field defaults of a panel.
+type GrafanaFieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+// RuleFile is a Prometheus alerting rule file.
+type RuleFile struct {
+	Groups []RuleGroup `json:"groups" yaml:"groups"`
+}
+
+// RuleGroup is a group of alerting rules evaluated together.
+type RuleGroup struct {
+	Name     string      `json:"name" yaml:"name"`
+	Interval string      `json:"interval" yaml:"interval"`
+	Rules    []AlertRule `json:"rules" yaml:"rules"`
+}
+
+// AlertRule is a Prometheus alerting rule.
+type AlertRule struct {
+	Alert       string            `json:"alert" yaml:"alert"`
+	Expr        string            `json:"expr" yaml:"expr"`
+	For         string            `json:"for" yaml:"for"`
+	Labels      map[string]string `json:"labels" yaml:"labels"`
+	Annotations map[string]string `json:"annotations" yaml:"annotations"`
+}
+
+// dashboardSections orders the rows of the generated dashboard by metric
+// name prefix. Families matching none go to the last row.
+var dashboardSections = []struct {
+	title    string
+	prefixes []string
+}{
+	{"Workflows", []string{"workflow_"}},
+	{"Tasks", []string{"task_"}},
+	{"Lanes", []string{"lane_", "redis_lane_"}},
+	{"Sagas", []string{"saga_"}},
+	{"Signals", []string{"signal_"}},
+	{"HTTP", []string{"http_"}},
+	{"LLM", []string{"llm_"}},
+	{"Cluster", nil},
+}
+
+const (
+	laneLabel     = "lane_name"
+	datasourceVar = "${datasource}"
+	panelWidth    = 12
+	panelHeight   = 8
+)
+
+// Dashboard generates a Grafana dashboard with a panel per metric family in
+// the registry: rates for counters, the 95th percentile for histograms and
+// the values of gauges. Lane panels are filtered by a lane variable listing
+// the lanes of target. A family gets its panel once it has a series.
+func (m *Manager) Dashboard(target MonitoringTarget) (*GrafanaImport, error) {
+	families, err := m.gather()
+	if err != nil {
+		return nil, err
+	}
+
+	bySection := make([][]*dto.MetricFamily, len(dashboardSections))
+	for _, f := range families {
+		bySection[sectionOf(f.GetName())] = append(bySection[sectionOf(f.GetName())], f)
+	}
+
+	dashboard := GrafanaDashboard{
+		UID:           "goclaw-generated",
+		Title:         "Goclaw",
+		Tags:          []string{"goclaw", "orchestration", "generated"},
+		Timezone:      "browser",
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          GrafanaTimeRange{From: "now-6h", To: "now"},
+		Templating:    GrafanaTemplating{List: dashboardVariables(target)},
+		Panels:        []GrafanaPanel{},
+	}
+	datasource := &GrafanaDatasource{Type: "prometheus", UID: datasourceVar}
+
+	id, y := 1, 0
+	for i, section := range bySection {
+		if len(section) == 0 {
+			continue
+		}
+		dashboard.Panels = append(dashboard.Panels, GrafanaPanel{
+			ID:      id,
+			Title:   dashboardSections[i].title,
+			Type:    "row",
+			GridPos: GrafanaGridPos{H: 1, W: 24, X: 0, Y: y},
+		})
+		id++
+		y++
+		for j, f := range section {
+			panel := familyPanel(f)
+			panel.ID = id
+			panel.Datasource = datasource
+			panel.GridPos = GrafanaGridPos{H: panelHeight, W: panelWidth, X: (j % 2) * panelWidth, Y: y + (j/2)*panelHeight}
+			dashboard.Panels = append(dashboard.Panels, panel)
+			id++
+		}
+		y += (len(section) + 1) / 2 * panelHeight
+	}
+
+	return &GrafanaImport{Dashboard: dashboard, Overwrite: true}, nil
+}
+
+// AlertRules generates Prometheus alerting rules for the workflow, lane,
+// saga and HTTP metrics. Lane backlog rules follow the capacity of each lane
+// of target and saga rules are only generated when sagas are enabled.
+func (m *Manager) AlertRules(target MonitoringTarget) (*RuleFile, error) {
+	if !m.enabled {
+		return nil, errs.New(errs.NotFound, "metrics are disabled")
+	}
+
+	workflow := RuleGroup{Name: "goclaw_workflows", Interval: "30s", Rules: []AlertRule{
+		alertRule("GoclawHighWorkflowFailureRate",
+			`sum(rate(workflow_submissions_total{status="failed"}[5m])) / sum(rate(workflow_submissions_total[5m])) > 0.1`,
+			"5m", "warning", "workflow",
+			"High workflow failure rate",
+			"Workflow failure rate is {{ $value | humanizePercentage }} (threshold: 10%)"),
+		alertRule("GoclawWorkflowSLABreaches",
+			`sum by (workflow) (increase(workflow_sla_breaches_total[15m])) > 0`,
+			"0m", "warning", "workflow",
+			"Workflow SLA breached",
+			"Workflow {{ $labels.workflow }} breached its SLA {{ $value }} times in 15 minutes"),
+		alertRule("GoclawHighTaskFailureRate",
+			`sum(rate(task_executions_total{status="failed"}[5m])) / sum(rate(task_executions_total[5m])) > 0.2`,
+			"10m", "warning", "task",
+			"High task failure rate",
+			"Task failure rate is {{ $value | humanizePercentage }} (threshold: 20%)"),
+	}}
+
+	lanes := RuleGroup{Name: "goclaw_lanes", Interval: "30s", Rules: []AlertRule{}}
+	for _, l := range target.Lanes {
+		if l.Capacity <= 0 {
+			continue
+		}
+		threshold := l.Capacity * 8 / 10
+		if threshold < 1 {
+			threshold = 1
+		}
+		lanes.Rules = append(lanes.Rules, alertRule("GoclawLaneQueueBacklog",
+			fmt.Sprintf(`lane_queue_depth{%s=%q} > %d`, laneLabel, l.Name, threshold),
+			"10m", "warning", "lane",
+			fmt.Sprintf("Lane %s is nearly full", l.Name),
+			fmt.Sprintf("Lane %s has {{ $value }} queued tasks (capacity %d)", l.Name, l.Capacity)))
+	}
+	lanes.Rules = append(lanes.Rules,
+		alertRule("GoclawLaneDeadlinesMissed",
+			fmt.Sprintf(`sum by (%s) (increase(lane_deadline_missed_total[10m])) > 0`, laneLabel),
+			"0m", "warning", "lane",
+			"Lane tasks missed their deadline",
+			"Lane {{ $labels.lane_name }} dropped {{ $value }} tasks past their deadline in 10 minutes"),
+		alertRule("GoclawLaneSlowWait",
+			fmt.Sprintf(`histogram_quantile(0.95, sum by (le, %s) (rate(lane_wait_duration_seconds_bucket[5m]))) > 30`, laneLabel),
+			"10m", "warning", "lane",
+			"Tasks wait long in a lane",
+			"P95 wait time of lane {{ $labels.lane_name }} is {{ $value }}s"))
+
+	http := RuleGroup{Name: "goclaw_http", Interval: "30s", Rules: []AlertRule{
+		alertRule("GoclawHighHTTPErrorRate",
+			`sum(rate(http_requests_total{status=~"5.."}[5m])) / sum(rate(http_requests_total[5m])) > 0.05`,
+			"5m", "critical", "api",
+			"High HTTP error rate",
+			"HTTP 5xx rate is {{ $value | humanizePercentage }} (threshold: 5%)"),
+	}}
+
+	file := &RuleFile{Groups: []RuleGroup{workflow, lanes}}
+	if target.SagaEnabled {
+		file.Groups = append(file.Groups, sagaRules(target))
+	}
+	file.Groups = append(file.Groups, http)
+	return file, nil
+}
+
+// sagaRules returns the saga alerting rules tuned to the saga settings of
+// target.
+func sagaRules(target MonitoringTarget) RuleGroup {
+	group := RuleGroup{Name: "goclaw_sagas", Interval: "30s", Rules: []AlertRule{
+		alertRule("GoclawSagaCompensationFailures",
+			`sum(increase(saga_compensations_total{status="failed"}[10m])) > 0`,
+			"0m", "critical", "saga",
+			"Saga compensation failed",
+			"{{ $value }} saga compensations failed in 10 minutes"),
+		alertRule("GoclawSagaStuck",
+			`sum by (state, action) (increase(saga_stuck_total[10m])) > 0`,
+			"0m", "critical", "saga",
+			"Sagas are stuck",
+			"{{ $value }} {{ $labels.state }} sagas made no progress within their stuck timeout ({{ $labels.action }})"),
+	}}
+	if target.SagaTimeout > 0 {
+		threshold := target.SagaTimeout.Seconds() * 0.8
+		group.Rules = append(group.Rules, alertRule("GoclawSagaNearTimeout",
+			fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(saga_duration_seconds_bucket[10m]))) > %g`, threshold),
+			"10m", "warning", "saga",
+			"Sagas are close to their timeout",
+			fmt.Sprintf("P95 saga duration is {{ $value }}s (timeout %s)", target.SagaTimeout)))
+	}
+	if target.CompensationMaxRetries > 0 {
+		group.Rules = append(group.Rules, alertRule("GoclawSagaCompensationRetries",
+			fmt.Sprintf(`sum(increase(saga_compensation_retries_total[10m])) > %d`, target.CompensationMaxRetries),
+			"0m", "warning", "saga",
+			"Saga compensations are retrying",
+			fmt.Sprintf("{{ $value }} compensation retries in 10 minutes (max %d per step)", target.CompensationMaxRetries)))
+	}
+	return group
+}
+
+func alertRule(name, expr, forDuration, severity, component, summary, description string) AlertRule {
+	return AlertRule{
+		Alert:       name,
+		Expr:        expr,
+		For:         forDuration,
+		Labels:      map[string]string{"severity": severity, "component": component},
+		Annotations: map[string]string{"summary": summary, "description": description},
+	}
+}
+
+// gather returns the Goclaw metric families of the registry, sorted by name,
+// without the Go runtime and process families.
+func (m *Manager) gather() ([]*dto.MetricFamily, error) {
+	if !m.enabled {
+		return nil, errs.New(errs.NotFound, "metrics are disabled")
+	}
+	gathered, err := m.registry.Gather()
+	if err != nil {
+		return nil, errs.Wrap(err, errs.Internal, "gather metrics")
+	}
+	families := make([]*dto.MetricFamily, 0, len(gathered))
+	for _, f := range gathered {
+		if strings.HasPrefix(f.GetName(), "go_") || strings.HasPrefix(f.GetName(), "process_") {
+			continue
+		}
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].GetName() < families[j].GetName() })
+	return families, nil
+}
+
+func sectionOf(name string) int {
+	for i, section := range dashboardSections {
+		for _, prefix := range section.prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return i
+			}
+		}
+	}
+	return len(dashboardSections) - 1
+}
+
+func dashboardVariables(target MonitoringTarget) []GrafanaVariable {
+	lanes := GrafanaVariable{
+		Name:       "lane",
+		Label:      "Lane",
+		Type:       "custom",
+		IncludeAll: true,
+		Multi:      true,
+		Options:    []GrafanaOption{},
+	}
+	names := make([]string, 0, len(target.Lanes))
+	for _, l := range target.Lanes {
+		names = append(names, l.Name)
+		lanes.Options = append(lanes.Options, GrafanaOption{Text: l.Name, Value: l.Name})
+	}
+	lanes.Query = strings.Join(names, ",")
+
+	return []GrafanaVariable{
+		{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		lanes,
+	}
+}
+
+// familyPanel returns the panel of f, without its ID, data source and
+// position.
+func familyPanel(f *dto.MetricFamily) GrafanaPanel {
+	name := f.GetName()
+	labels := familyLabels(f)
+	selector := ""
+	for _, label := range labels {
+		// Task metrics name the lane "lane", lane metrics "lane_name".
+		if label == laneLabel || label == "lane" {
+			selector = fmt.Sprintf(`{%s=~"$lane"}`, label)
+		}
+	}
+	by := ""
+	if len(labels) > 0 {
+		by = " by (" + strings.Join(labels, ", ") + ")"
+	}
+	legend := name
+	if len(labels) > 0 {
+		legends := make([]string, len(labels))
+		for i, label := range labels {
+			legends[i] = "{{" + label + "}}"
+		}
+		legend = strings.Join(legends, " ")
+	}
+
+	panel := GrafanaPanel{Title: name, Type: "timeseries", Description: f.GetHelp()}
+	var expr, unit string
+	switch f.GetType() {
+	case dto.MetricType_COUNTER:
+		panel.Title = name + " (rate)"
+		expr = fmt.Sprintf("sum%s (rate(%s%s[5m]))", by, name, selector)
+		unit = "ops"
+	case dto.MetricType_HISTOGRAM:
+		panel.Title = name + " (p95)"
+		expr = fmt.Sprintf("histogram_quantile(0.95, sum by (%s) (rate(%s_bucket%s[5m])))",
+			strings.Join(append([]string{"le"}, labels...), ", "), name, selector)
+		if strings.HasSuffix(name, "_seconds") {
+			unit = "s"
+		}
+	default:
+		expr = fmt.Sprintf("sum%s (%s%s)", by, name, selector)
+	}
+	panel.Targets = []GrafanaTarget{{RefID: "A", Expr: expr, LegendFormat: legend, Exemplar: f.GetType() == dto.MetricType_HISTOGRAM}}
+	if unit != "" {
+		panel.FieldConfig = &GrafanaFieldCfg{Defaults: GrafanaFieldDefaults{Unit: unit}}
+	}
+	return panel
+}
+
+// familyLabels returns the sorted label names of the series of f.
+func familyLabels(f *dto.MetricFamily) []string {
+	seen := make(map[string]struct{})
+	for _, metric := range f.GetMetric() {
+		for _, pair := range metric.GetLabel() {
+			seen[pair.GetName()] = struct{}{}
+		}
+	}
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
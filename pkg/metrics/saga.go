@@ -70,7 +70,16 @@ func (m *Manager) initSagaMetrics(cfg Config) {
 	m.registry.MustRegister(m.sagaCompensations)
 	m.registry.MustRegister(m.sagaCompensationDuration)
 	m.registry.MustRegister(m.sagaCompensationRetries)
+	m.sagaStuck = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "saga_stuck_total",
+			Help: "Total number of sagas found without progress by state and watchdog action",
+		},
+		[]string{"state", "action"},
+	)
+
 	m.registry.MustRegister(m.sagaRecovery)
+	m.registry.MustRegister(m.sagaStuck)
 }
 
 // RecordSagaExecution records one saga execution outcome.
@@ -136,3 +145,11 @@ func (m *Manager) RecordSagaRecovery(status string) {
 	}
 	m.sagaRecovery.WithLabelValues(status).Inc()
 }
+
+// RecordSagaStuck records one saga found without progress by the watchdog.
+func (m *Manager) RecordSagaStuck(state, action string) {
+	if !m.enabled {
+		return
+	}
+	m.sagaStuck.WithLabelValues(state, action).Inc()
+}
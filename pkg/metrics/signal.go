@@ -31,6 +31,22 @@ func (m *Manager) initSignalMetrics() {
 		[]string{"mode", "type", "reason"},
 	)
 
+	m.signalAcked = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signal_acked_total",
+			Help: "Total number of delivered signals acknowledged by consumers",
+		},
+		[]string{"mode", "type"},
+	)
+
+	m.signalRedelivered = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signal_redelivered_total",
+			Help: "Total number of signals redelivered after the ack timeout",
+		},
+		[]string{"mode", "type"},
+	)
+
 	m.signalPatternOps = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "signal_pattern_total",
@@ -51,6 +67,8 @@ func (m *Manager) initSignalMetrics() {
 	m.registry.MustRegister(m.signalSent)
 	m.registry.MustRegister(m.signalReceived)
 	m.registry.MustRegister(m.signalFailures)
+	m.registry.MustRegister(m.signalAcked)
+	m.registry.MustRegister(m.signalRedelivered)
 	m.registry.MustRegister(m.signalPatternOps)
 	m.registry.MustRegister(m.signalPatternDur)
 }
@@ -79,6 +97,22 @@ func (m *Manager) RecordSignalFailed(mode string, signalType string, reason stri
 	m.signalFailures.WithLabelValues(mode, signalType, reason).Inc()
 }
 
+// RecordSignalAcked records a signal acknowledged by its consumer.
+func (m *Manager) RecordSignalAcked(mode string, signalType string) {
+	if !m.enabled {
+		return
+	}
+	m.signalAcked.WithLabelValues(mode, signalType).Inc()
+}
+
+// RecordSignalRedelivered records a signal redelivered after its ack timeout.
+func (m *Manager) RecordSignalRedelivered(mode string, signalType string) {
+	if !m.enabled {
+		return
+	}
+	m.signalRedelivered.WithLabelValues(mode, signalType).Inc()
+}
+
 // RecordSignalPattern records message-pattern counters and latency.
 func (m *Manager) RecordSignalPattern(pattern string, status string, duration time.Duration) {
 	if !m.enabled {
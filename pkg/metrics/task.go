@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,7 +14,7 @@ func (m *Manager) initTaskMetrics(cfg Config) {
 			Name: "task_executions_total",
 			Help: "Total number of task executions by status",
 		},
-		[]string{"status"},
+		m.runLabelNames("status"),
 	)
 
 	m.taskDuration = prometheus.NewHistogramVec(
@@ -22,7 +23,7 @@ func (m *Manager) initTaskMetrics(cfg Config) {
 			Help:    "Task execution duration in seconds",
 			Buckets: cfg.TaskDurationBuckets,
 		},
-		[]string{},
+		m.runLabelNames(),
 	)
 
 	m.taskRetries = prometheus.NewCounterVec(
@@ -33,25 +34,93 @@ func (m *Manager) initTaskMetrics(cfg Config) {
 		[]string{},
 	)
 
+	m.taskPreempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "task_preemptions_total",
+			Help: "Total number of running tasks preempted by higher-priority work",
+		},
+		[]string{"lane"},
+	)
+
+	m.exprEvaluations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "expression_evaluations_total",
+			Help: "Total number of task expression evaluations by kind and outcome",
+		},
+		[]string{"kind", "outcome"},
+	)
+
+	m.exprDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "expression_evaluation_duration_seconds",
+			Help:    "Task expression evaluation duration in seconds",
+			Buckets: []float64{.00001, .00005, .0001, .0005, .001, .005, .01},
+		},
+		[]string{"kind"},
+	)
+
+	m.priorityInversions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "priority_inversions_total",
+			Help: "Total number of tasks queued behind lower-priority tasks in a saturated lane",
+		},
+		[]string{"lane"},
+	)
+
+	m.priorityInherited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "priority_inherited_tasks_total",
+			Help: "Total number of queued tasks that inherited the priority of a task they blocked",
+		},
+		[]string{"lane"},
+	)
+
+	m.priorityInversionWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "priority_inversion_wait_seconds",
+			Help:    "Time tasks that hit a priority inversion waited in the lane",
+			Buckets: cfg.LaneWaitBuckets,
+		},
+		[]string{"lane"},
+	)
+
 	m.registry.MustRegister(m.taskExecutions)
 	m.registry.MustRegister(m.taskDuration)
 	m.registry.MustRegister(m.taskRetries)
+	m.registry.MustRegister(m.taskPreempts)
+	m.registry.MustRegister(m.exprEvaluations)
+	m.registry.MustRegister(m.exprDuration)
+	m.registry.MustRegister(m.priorityInversions)
+	m.registry.MustRegister(m.priorityInherited)
+	m.registry.MustRegister(m.priorityInversionWait)
 }
 
 // RecordTaskExecution records a task execution event.
 func (m *Manager) RecordTaskExecution(status string) {
+	m.RecordTaskExecutionFor(status, "", "")
+}
+
+// RecordTaskExecutionFor records a task execution event of a run of the
+// named workflow and tenant.
+func (m *Manager) RecordTaskExecutionFor(status, workflowName, tenant string) {
 	if !m.enabled {
 		return
 	}
-	m.taskExecutions.WithLabelValues(status).Inc()
+	m.taskExecutions.WithLabelValues(m.runLabelValues(workflowName, tenant, status)...).Inc()
 }
 
 // RecordTaskDuration records task execution duration.
 func (m *Manager) RecordTaskDuration(duration time.Duration) {
+	m.RecordTaskDurationFor(context.Background(), "", "", duration)
+}
+
+// RecordTaskDurationFor records the execution duration of a task of a run of
+// the named workflow and tenant, with the trace of ctx as exemplar.
+func (m *Manager) RecordTaskDurationFor(ctx context.Context, workflowName, tenant string, duration time.Duration) {
 	if !m.enabled {
 		return
 	}
-	m.taskDuration.WithLabelValues().Observe(duration.Seconds())
+	observeWithTrace(ctx, m.taskDuration.WithLabelValues(m.runLabelValues(workflowName, tenant)...), duration.Seconds())
 }
 
 // RecordTaskRetry records a task retry event.
@@ -61,3 +130,40 @@ func (m *Manager) RecordTaskRetry() {
 	}
 	m.taskRetries.WithLabelValues().Inc()
 }
+
+// RecordTaskPreemption records a task preempted in the given lane.
+func (m *Manager) RecordTaskPreemption(laneName string) {
+	if !m.enabled {
+		return
+	}
+	m.taskPreempts.WithLabelValues(laneName).Inc()
+}
+
+// RecordPriorityInversion records a task queued in the given lane behind
+// lower-priority tasks; inherited is how many of them took over its priority.
+func (m *Manager) RecordPriorityInversion(laneName string, inherited int) {
+	if !m.enabled {
+		return
+	}
+	m.priorityInversions.WithLabelValues(laneName).Inc()
+	m.priorityInherited.WithLabelValues(laneName).Add(float64(inherited))
+}
+
+// RecordPriorityInversionWait records how long a task that hit a priority
+// inversion waited in the given lane.
+func (m *Manager) RecordPriorityInversionWait(laneName string, wait time.Duration) {
+	if !m.enabled {
+		return
+	}
+	m.priorityInversionWait.WithLabelValues(laneName).Observe(wait.Seconds())
+}
+
+// RecordExpressionEvaluation records the evaluation of a task expression of
+// the given kind (when, retry_if or config) and its outcome (ok or error).
+func (m *Manager) RecordExpressionEvaluation(kind, outcome string, duration time.Duration) {
+	if !m.enabled {
+		return
+	}
+	m.exprEvaluations.WithLabelValues(kind, outcome).Inc()
+	m.exprDuration.WithLabelValues(kind).Observe(duration.Seconds())
+}
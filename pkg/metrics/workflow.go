@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,7 +14,7 @@ func (m *Manager) initWorkflowMetrics(cfg Config) {
 			Name: "workflow_submissions_total",
 			Help: "Total number of workflow submissions by status",
 		},
-		[]string{"status"},
+		m.runLabelNames("status"),
 	)
 
 	m.workflowDuration = prometheus.NewHistogramVec(
@@ -22,7 +23,7 @@ func (m *Manager) initWorkflowMetrics(cfg Config) {
 			Help:    "Workflow execution duration in seconds",
 			Buckets: cfg.WorkflowDurationBuckets,
 		},
-		[]string{"status"},
+		m.runLabelNames("status"),
 	)
 
 	m.workflowActive = prometheus.NewGaugeVec(
@@ -33,25 +34,54 @@ func (m *Manager) initWorkflowMetrics(cfg Config) {
 		[]string{"status"},
 	)
 
+	m.workflowSLABreaches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "workflow_sla_breaches_total",
+			Help: "Total number of workflow runs unfinished at their SLA deadline by workflow name",
+		},
+		[]string{"workflow"},
+	)
+
 	m.registry.MustRegister(m.workflowSubmissions)
 	m.registry.MustRegister(m.workflowDuration)
 	m.registry.MustRegister(m.workflowActive)
+	m.registry.MustRegister(m.workflowSLABreaches)
 }
 
 // RecordWorkflowSubmission records a workflow submission event.
 func (m *Manager) RecordWorkflowSubmission(status string) {
+	m.RecordWorkflowSubmissionFor(status, "", "")
+}
+
+// RecordWorkflowSubmissionFor records a workflow submission event of the
+// named workflow and tenant.
+func (m *Manager) RecordWorkflowSubmissionFor(status, workflowName, tenant string) {
 	if !m.enabled {
 		return
 	}
-	m.workflowSubmissions.WithLabelValues(status).Inc()
+	m.workflowSubmissions.WithLabelValues(m.runLabelValues(workflowName, tenant, status)...).Inc()
 }
 
 // RecordWorkflowDuration records workflow execution duration.
 func (m *Manager) RecordWorkflowDuration(status string, duration time.Duration) {
+	m.RecordWorkflowDurationFor(context.Background(), status, "", "", duration)
+}
+
+// RecordWorkflowDurationFor records the execution duration of a run of the
+// named workflow and tenant, with the trace of ctx as exemplar.
+func (m *Manager) RecordWorkflowDurationFor(ctx context.Context, status, workflowName, tenant string, duration time.Duration) {
+	if !m.enabled {
+		return
+	}
+	observeWithTrace(ctx, m.workflowDuration.WithLabelValues(m.runLabelValues(workflowName, tenant, status)...), duration.Seconds())
+}
+
+// RecordSLABreach records a run of the named workflow that breached its SLA.
+func (m *Manager) RecordSLABreach(workflowName string) {
 	if !m.enabled {
 		return
 	}
-	m.workflowDuration.WithLabelValues(status).Observe(duration.Seconds())
+	m.workflowSLABreaches.WithLabelValues(m.workflowValues.value(workflowName)).Inc()
 }
 
 // SetActiveWorkflows sets the current number of active workflows.
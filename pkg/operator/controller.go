@@ -0,0 +1,441 @@
+// Package operator reconciles goclaw custom resources in Kubernetes into
+// workflow submissions, so workflows can be managed with kubectl and GitOps
+// tools while goclaw runs them.
+//
+// A Workflow resource is submitted once per spec generation and its status
+// follows the goclaw workflow. A Schedule resource submits its workflow
+// template on a cron schedule. The controller polls the API server every
+// resync interval. Status updates carry the resourceVersion that was read,
+// so when several goclaw nodes run the controller, only one submission per
+// change wins and the others are cancelled.
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/cron"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/kube"
+	"github.com/goclaw/goclaw/pkg/logger"
+)
+
+const defaultResyncInterval = 10 * time.Second
+
+// Engine is the part of the engine the controller submits workflows to.
+type Engine interface {
+	SubmitWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) (string, error)
+	GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error)
+	CancelWorkflowRequest(ctx context.Context, id string) error
+}
+
+// Option configures a Controller.
+type Option func(*Controller)
+
+// WithResyncInterval sets how often resources are reconciled.
+func WithResyncInterval(d time.Duration) Option {
+	return func(c *Controller) {
+		if d > 0 {
+			c.resync = d
+		}
+	}
+}
+
+// WithLogger sets the logger of reconcile failures.
+func WithLogger(l logger.Logger) Option {
+	return func(c *Controller) {
+		c.logger = l
+	}
+}
+
+// Controller reconciles Workflow and Schedule resources.
+type Controller struct {
+	client    *kube.Client
+	engine    Engine
+	namespace string
+	resync    time.Duration
+	logger    logger.Logger
+	validate  *validator.Validate
+	now       func() time.Time
+
+	mu sync.Mutex
+	// owned maps the Workflow resources seen, by namespace/name, to their
+	// goclaw workflow, so the workflow of a deleted resource is cancelled.
+	owned map[string]string
+}
+
+// NewController returns a controller of the resources in namespace, or in
+// all namespaces if it is empty.
+func NewController(client *kube.Client, eng Engine, namespace string, opts ...Option) *Controller {
+	c := &Controller{
+		client:    client,
+		engine:    eng,
+		namespace: namespace,
+		resync:    defaultResyncInterval,
+		validate:  validator.New(),
+		now:       time.Now,
+		owned:     make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = logger.Global()
+	}
+	return c
+}
+
+// Run reconciles every resync interval until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.resync)
+	defer ticker.Stop()
+	for {
+		if err := c.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Reconcile failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile reconciles every Workflow and Schedule resource once. Failures
+// of single resources are logged and retried on the next call.
+func (c *Controller) Reconcile(ctx context.Context) error {
+	return errors.Join(c.reconcileWorkflows(ctx), c.reconcileSchedules(ctx))
+}
+
+func (c *Controller) reconcileWorkflows(ctx context.Context) error {
+	var list struct {
+		Items []Workflow `json:"items"`
+	}
+	if err := c.client.Call(ctx, http.MethodGet, c.listPath("workflows"), nil, &list); err != nil {
+		return fmt.Errorf("list workflows: %w", err)
+	}
+
+	seen := make(map[string]bool, len(list.Items))
+	for i := range list.Items {
+		wf := &list.Items[i]
+		key := resourceKey(wf.Metadata)
+		seen[key] = true
+		if err := c.reconcileWorkflow(ctx, wf); err != nil {
+			c.logger.Warn("Failed to reconcile workflow resource", "resource", key, "error", err)
+		}
+	}
+
+	c.mu.Lock()
+	var orphans []string
+	for key, id := range c.owned {
+		if !seen[key] {
+			orphans = append(orphans, id)
+			delete(c.owned, key)
+		}
+	}
+	c.mu.Unlock()
+	for _, id := range orphans {
+		c.cancel(ctx, id, "workflow resource deleted")
+	}
+	return nil
+}
+
+func (c *Controller) reconcileWorkflow(ctx context.Context, wf *Workflow) error {
+	key := resourceKey(wf.Metadata)
+	status := wf.Status
+	generation := wf.Metadata.Generation
+
+	if status.ObservedGeneration == generation {
+		if status.Phase == PhaseInvalid {
+			return nil
+		}
+		if status.WorkflowID != "" {
+			resp, err := c.engine.GetWorkflowSummaryResponse(ctx, status.WorkflowID)
+			switch {
+			case err == nil:
+				c.own(key, status.WorkflowID)
+				return c.updateWorkflowStatus(ctx, wf, workflowStatus(generation, resp))
+			case !errs.Is(err, errs.NotFound):
+				return err
+			}
+			// The workflow is gone, e.g. with in-memory storage after a
+			// restart: submit it again.
+		}
+	} else if status.WorkflowID != "" {
+		c.cancel(ctx, status.WorkflowID, "workflow resource changed")
+	}
+
+	id, err := c.submit(ctx, key, wf.Spec)
+	if err != nil {
+		if !errs.Is(err, errs.BadRequest) {
+			return err
+		}
+		return c.updateWorkflowStatus(ctx, wf, WorkflowStatus{ObservedGeneration: generation, Phase: PhaseInvalid, Message: err.Error()})
+	}
+	if err := c.updateWorkflowStatus(ctx, wf, WorkflowStatus{ObservedGeneration: generation, WorkflowID: id, Phase: "pending"}); err != nil {
+		// Another node submitted this generation first, or the resource
+		// changed since it was read.
+		c.cancel(ctx, id, "workflow resource status update failed")
+		return err
+	}
+	c.own(key, id)
+	c.logger.Info("Submitted workflow resource", "resource", key, "workflow_id", id, "generation", generation)
+	return nil
+}
+
+func (c *Controller) reconcileSchedules(ctx context.Context) error {
+	var list struct {
+		Items []Schedule `json:"items"`
+	}
+	if err := c.client.Call(ctx, http.MethodGet, c.listPath("schedules"), nil, &list); err != nil {
+		return fmt.Errorf("list schedules: %w", err)
+	}
+	now := c.now()
+	for i := range list.Items {
+		s := &list.Items[i]
+		if err := c.reconcileSchedule(ctx, s, now); err != nil {
+			c.logger.Warn("Failed to reconcile schedule resource", "resource", resourceKey(s.Metadata), "error", err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) reconcileSchedule(ctx context.Context, s *Schedule, now time.Time) error {
+	status := s.Status
+	status.Message = ""
+
+	schedule, err := cron.Parse(s.Spec.Schedule)
+	if err != nil {
+		status.NextScheduleTime = nil
+		status.Message = err.Error()
+		return c.updateScheduleStatus(ctx, s, status)
+	}
+	loc := time.UTC
+	if s.Spec.TimeZone != "" {
+		if loc, err = time.LoadLocation(s.Spec.TimeZone); err != nil {
+			status.NextScheduleTime = nil
+			status.Message = fmt.Sprintf("invalid time zone %q", s.Spec.TimeZone)
+			return c.updateScheduleStatus(ctx, s, status)
+		}
+	}
+	cal, err := scheduleCalendar(s.Spec)
+	if err != nil {
+		status.NextScheduleTime = nil
+		status.Message = err.Error()
+		return c.updateScheduleStatus(ctx, s, status)
+	}
+	if s.Spec.Suspend {
+		status.NextScheduleTime = nil
+		status.Message = "suspended"
+		return c.updateScheduleStatus(ctx, s, status)
+	}
+
+	last := s.Metadata.CreationTimestamp
+	if status.LastScheduleTime != nil {
+		last = *status.LastScheduleTime
+	}
+	// Runs due more than a resync ago were missed while the controller was
+	// down and run as the catch-up policy decides.
+	pending := schedule.Due(last.In(loc), now.In(loc), c.resync+time.Minute, catchUpPolicy(s.Spec.CatchUpPolicy), cal.Excludes)
+	if pending.Missed > 0 {
+		status.Message = fmt.Sprintf("skipped %d missed runs", pending.Missed)
+	}
+	if !pending.Last.IsZero() {
+		last = pending.Last
+		status.LastScheduleTime = utcTime(last)
+	}
+	if len(pending.Run) == 0 {
+		next := schedule.NextIncluded(last.In(loc), cal.Excludes)
+		if next.IsZero() {
+			status.NextScheduleTime = nil
+			status.Message = "schedule never matches"
+			return c.updateScheduleStatus(ctx, s, status)
+		}
+		status.NextScheduleTime = utcTime(next)
+		return c.updateScheduleStatus(ctx, s, status)
+	}
+	due := pending.Run[0]
+	if len(pending.Run) > 1 {
+		// Further runs to catch up on are submitted on the next resyncs.
+		status.LastScheduleTime = utcTime(due)
+		status.NextScheduleTime = utcTime(pending.Run[1])
+	} else {
+		status.NextScheduleTime = utcTime(schedule.NextIncluded(last.In(loc), cal.Excludes))
+	}
+
+	key := resourceKey(s.Metadata)
+	if status.LastWorkflowID != "" && s.Spec.ConcurrencyPolicy != "" && s.Spec.ConcurrencyPolicy != ConcurrencyAllow {
+		resp, err := c.engine.GetWorkflowSummaryResponse(ctx, status.LastWorkflowID)
+		if err != nil && !errs.Is(err, errs.NotFound) {
+			return err
+		}
+		if err == nil && !isTerminal(resp.Status) {
+			if s.Spec.ConcurrencyPolicy == ConcurrencyForbid {
+				status.Message = fmt.Sprintf("skipped run due at %s: workflow %s is unfinished", due.UTC().Format(time.RFC3339), status.LastWorkflowID)
+				return c.updateScheduleStatus(ctx, s, status)
+			}
+			c.cancel(ctx, status.LastWorkflowID, "replaced by the next scheduled run")
+		}
+	}
+
+	id, err := c.submit(ctx, key, s.Spec.Workflow)
+	if err != nil {
+		if !errs.Is(err, errs.BadRequest) {
+			return err
+		}
+		status.Message = err.Error()
+		return c.updateScheduleStatus(ctx, s, status)
+	}
+	status.LastWorkflowID = id
+	if err := c.updateScheduleStatus(ctx, s, status); err != nil {
+		c.cancel(ctx, id, "schedule resource status update failed")
+		return err
+	}
+	c.logger.Info("Submitted scheduled workflow", "resource", key, "workflow_id", id, "due", due)
+	return nil
+}
+
+// scheduleCalendar returns the calendar of the holidays and blackout
+// windows of spec.
+func scheduleCalendar(spec ScheduleSpec) (*cron.Calendar, error) {
+	blackouts := make([]cron.Blackout, 0, len(spec.Blackouts))
+	for _, w := range spec.Blackouts {
+		var b cron.Blackout
+		switch {
+		case w.Schedule != "":
+			var err error
+			if b.Schedule, err = cron.Parse(w.Schedule); err != nil {
+				return nil, fmt.Errorf("blackout: %w", err)
+			}
+			if b.Duration, err = time.ParseDuration(w.Duration); err != nil {
+				return nil, fmt.Errorf("blackout duration %q is not a duration such as 2h", w.Duration)
+			}
+		case w.Start != nil && w.End != nil:
+			b.Start, b.End = *w.Start, *w.End
+		default:
+			return nil, fmt.Errorf("blackout needs a start and end, or a schedule and duration")
+		}
+		blackouts = append(blackouts, b)
+	}
+	return cron.NewCalendar(spec.Holidays, blackouts)
+}
+
+// catchUpPolicy maps a catch-up policy of a Schedule to the cron one.
+func catchUpPolicy(policy string) string {
+	switch policy {
+	case CatchUpSkip:
+		return cron.CatchUpSkip
+	case CatchUpRunAll:
+		return cron.CatchUpRunAll
+	default:
+		return cron.CatchUpRunOnce
+	}
+}
+
+// submit validates and submits req for the resource key. Invalid requests
+// fail with errs.BadRequest.
+func (c *Controller) submit(ctx context.Context, key string, req models.WorkflowRequest) (string, error) {
+	if err := c.validate.Struct(&req); err != nil {
+		return "", errs.Newf(errs.BadRequest, "invalid workflow spec: %v", err)
+	}
+	req.Metadata = maps.Clone(req.Metadata)
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string, 1)
+	}
+	req.Metadata[LabelResource] = key
+	return c.engine.SubmitWorkflowRequest(ctx, &req)
+}
+
+func (c *Controller) cancel(ctx context.Context, id, reason string) {
+	if err := c.engine.CancelWorkflowRequest(ctx, id); err != nil {
+		c.logger.Debug("Workflow not cancelled", "workflow_id", id, "reason", reason, "error", err)
+		return
+	}
+	c.logger.Info("Cancelled workflow", "workflow_id", id, "reason", reason)
+}
+
+func (c *Controller) own(key, id string) {
+	c.mu.Lock()
+	c.owned[key] = id
+	c.mu.Unlock()
+}
+
+// updateWorkflowStatus writes status to wf unless it is unchanged.
+func (c *Controller) updateWorkflowStatus(ctx context.Context, wf *Workflow, status WorkflowStatus) error {
+	if sameJSON(wf.Status, status) {
+		return nil
+	}
+	updated := *wf
+	updated.Status = status
+	return c.client.Call(ctx, http.MethodPut, c.statusPath("workflows", wf.Metadata), &updated, nil)
+}
+
+// updateScheduleStatus writes status to s unless it is unchanged.
+func (c *Controller) updateScheduleStatus(ctx context.Context, s *Schedule, status ScheduleStatus) error {
+	if sameJSON(s.Status, status) {
+		return nil
+	}
+	updated := *s
+	updated.Status = status
+	return c.client.Call(ctx, http.MethodPut, c.statusPath("schedules", s.Metadata), &updated, nil)
+}
+
+func (c *Controller) listPath(plural string) string {
+	if c.namespace == "" {
+		return "/apis/" + Group + "/" + Version + "/" + plural
+	}
+	return "/apis/" + Group + "/" + Version + "/namespaces/" + c.namespace + "/" + plural
+}
+
+func (c *Controller) statusPath(plural string, meta ObjectMeta) string {
+	return "/apis/" + Group + "/" + Version + "/namespaces/" + meta.Namespace + "/" + plural + "/" + meta.Name + "/status"
+}
+
+// workflowStatus returns the status of a Workflow running resp.
+func workflowStatus(generation int64, resp *models.WorkflowStatusResponse) WorkflowStatus {
+	status := WorkflowStatus{
+		ObservedGeneration: generation,
+		WorkflowID:         resp.ID,
+		Phase:              resp.Status,
+		Message:            resp.Error,
+		StartedAt:          resp.StartedAt,
+		CompletedAt:        resp.CompletedAt,
+	}
+	if resp.TaskSummary != nil {
+		status.Tasks = resp.TaskSummary.Counts
+	}
+	return status
+}
+
+func isTerminal(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+func resourceKey(meta ObjectMeta) string {
+	return meta.Namespace + "/" + meta.Name
+}
+
+func utcTime(t time.Time) *time.Time {
+	t = t.UTC()
+	return &t
+}
+
+// sameJSON reports whether a and b encode alike, which ignores differences
+// such as time locations that do not survive the API server.
+func sameJSON(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
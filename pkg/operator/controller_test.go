@@ -0,0 +1,343 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/kube"
+)
+
+// fakeAPIServer stores Workflow and Schedule resources of namespace "ci"
+// and enforces resourceVersion on status updates.
+type fakeAPIServer struct {
+	mu        sync.Mutex
+	resources map[string]map[string]map[string]interface{} // plural -> name -> object
+	version   int
+}
+
+func newFakeAPIServer() *fakeAPIServer {
+	return &fakeAPIServer{resources: map[string]map[string]map[string]interface{}{"workflows": {}, "schedules": {}}}
+}
+
+// put creates or updates a resource, keeping its status like the API
+// server does on spec updates.
+func (f *fakeAPIServer) put(plural, name string, generation int64, spec interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version++
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": name, "namespace": "ci", "generation": generation,
+			"resourceVersion": strconv.Itoa(f.version), "creationTimestamp": "2026-03-14T10:00:00Z",
+		},
+		"spec": spec,
+	}
+	if old, ok := f.resources[plural][name]; ok {
+		obj["status"] = old["status"]
+	}
+	f.resources[plural][name] = obj
+}
+
+func (f *fakeAPIServer) object(plural, name string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, _ := json.Marshal(f.resources[plural][name])
+	var obj map[string]interface{}
+	json.Unmarshal(data, &obj)
+	return obj
+}
+
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path, ok := strings.CutPrefix(r.URL.Path, "/apis/goclaw.io/v1alpha1/namespaces/ci/")
+	if !ok {
+		http.Error(w, `{"message":"unexpected path"}`, http.StatusNotFound)
+		return
+	}
+	parts := strings.Split(path, "/")
+	objects := f.resources[parts[0]]
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 1:
+		items := make([]interface{}, 0, len(objects))
+		for _, obj := range objects {
+			items = append(items, obj)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	case r.Method == http.MethodPut && len(parts) == 3 && parts[2] == "status":
+		obj, ok := objects[parts[1]]
+		if !ok {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		var update struct {
+			Metadata ObjectMeta             `json:"metadata"`
+			Status   map[string]interface{} `json:"status"`
+		}
+		json.NewDecoder(r.Body).Decode(&update)
+		meta := obj["metadata"].(map[string]interface{})
+		if update.Metadata.ResourceVersion != meta["resourceVersion"] {
+			http.Error(w, `{"message":"the object has been modified"}`, http.StatusConflict)
+			return
+		}
+		f.version++
+		meta["resourceVersion"] = strconv.Itoa(f.version)
+		obj["status"] = update.Status
+		json.NewEncoder(w).Encode(obj)
+	default:
+		http.Error(w, `{"message":"unexpected request"}`, http.StatusBadRequest)
+	}
+}
+
+// fakeEngine records submissions; workflows stay in the status set.
+type fakeEngine struct {
+	mu        sync.Mutex
+	submitted []*models.WorkflowRequest
+	status    map[string]string
+}
+
+func (e *fakeEngine) SubmitWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.submitted = append(e.submitted, req)
+	id := "wf-" + strconv.Itoa(len(e.submitted))
+	e.status[id] = "pending"
+	return id, nil
+}
+
+func (e *fakeEngine) GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status, ok := e.status[id]
+	if !ok {
+		return nil, errs.New(errs.NotFound, "workflow not found")
+	}
+	return &models.WorkflowStatusResponse{ID: id, Status: status, TaskSummary: &models.TaskSummary{Total: 1, Counts: map[string]int{status: 1}}}, nil
+}
+
+func (e *fakeEngine) CancelWorkflowRequest(ctx context.Context, id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status[id] = "cancelled"
+	return nil
+}
+
+func (e *fakeEngine) set(id, status string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status[id] = status
+}
+
+func (e *fakeEngine) get(id string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status[id]
+}
+
+func (e *fakeEngine) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.submitted)
+}
+
+func newTestController(t *testing.T) (*Controller, *fakeAPIServer, *fakeEngine) {
+	t.Helper()
+	api := newFakeAPIServer()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	client, err := kube.NewClient(kube.Options{APIServer: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	eng := &fakeEngine{status: make(map[string]string)}
+	return NewController(client, eng, "ci"), api, eng
+}
+
+var testWorkflowSpec = models.WorkflowRequest{
+	Name:  "nightly",
+	Tasks: []models.TaskDefinition{{ID: "a", Name: "a", Type: "function"}},
+}
+
+func workflowStatusOf(t *testing.T, api *fakeAPIServer, name string) WorkflowStatus {
+	t.Helper()
+	data, _ := json.Marshal(api.object("workflows", name)["status"])
+	var status WorkflowStatus
+	json.Unmarshal(data, &status)
+	return status
+}
+
+func TestController_SubmitsWorkflowPerGeneration(t *testing.T) {
+	c, api, eng := newTestController(t)
+	ctx := context.Background()
+	api.put("workflows", "nightly", 1, testWorkflowSpec)
+
+	if err := c.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	status := workflowStatusOf(t, api, "nightly")
+	if status.WorkflowID != "wf-1" || status.Phase != "pending" || status.ObservedGeneration != 1 {
+		t.Fatalf("status = %+v", status)
+	}
+	if got := eng.submitted[0].Metadata[LabelResource]; got != "ci/nightly" {
+		t.Fatalf("workflow metadata %s = %q", LabelResource, got)
+	}
+
+	eng.set("wf-1", "running")
+	c.Reconcile(ctx)
+	if status := workflowStatusOf(t, api, "nightly"); status.Phase != "running" || status.Tasks["running"] != 1 {
+		t.Fatalf("status = %+v", status)
+	}
+	if eng.count() != 1 {
+		t.Fatalf("submissions = %d, want 1", eng.count())
+	}
+
+	// A spec change resubmits and cancels the previous run.
+	api.put("workflows", "nightly", 2, testWorkflowSpec)
+	c.Reconcile(ctx)
+	if status := workflowStatusOf(t, api, "nightly"); status.WorkflowID != "wf-2" || status.ObservedGeneration != 2 {
+		t.Fatalf("status = %+v", status)
+	}
+	if eng.get("wf-1") != "cancelled" {
+		t.Fatalf("previous workflow status = %q, want cancelled", eng.get("wf-1"))
+	}
+
+	// Deleting the resource cancels its workflow.
+	api.mu.Lock()
+	delete(api.resources["workflows"], "nightly")
+	api.mu.Unlock()
+	c.Reconcile(ctx)
+	if eng.get("wf-2") != "cancelled" {
+		t.Fatalf("workflow of deleted resource status = %q, want cancelled", eng.get("wf-2"))
+	}
+}
+
+func TestController_InvalidWorkflowSpec(t *testing.T) {
+	c, api, eng := newTestController(t)
+	api.put("workflows", "broken", 1, models.WorkflowRequest{Name: "broken"})
+
+	c.Reconcile(context.Background())
+	c.Reconcile(context.Background())
+	status := workflowStatusOf(t, api, "broken")
+	if status.Phase != PhaseInvalid || status.Message == "" {
+		t.Fatalf("status = %+v", status)
+	}
+	if eng.count() != 0 {
+		t.Fatalf("submissions = %d, want 0", eng.count())
+	}
+}
+
+func TestController_StaleStatusUpdateCancelsSubmission(t *testing.T) {
+	c, api, eng := newTestController(t)
+	api.put("workflows", "nightly", 1, testWorkflowSpec)
+
+	var wf Workflow
+	data, _ := json.Marshal(api.object("workflows", "nightly"))
+	json.Unmarshal(data, &wf)
+	wf.Metadata.ResourceVersion = "stale"
+
+	if err := c.reconcileWorkflow(context.Background(), &wf); !kube.IsConflict(err) {
+		t.Fatalf("reconcileWorkflow() error = %v, want conflict", err)
+	}
+	if eng.get("wf-1") != "cancelled" {
+		t.Fatalf("losing submission status = %q, want cancelled", eng.get("wf-1"))
+	}
+}
+
+func TestController_Schedule(t *testing.T) {
+	c, api, eng := newTestController(t)
+	ctx := context.Background()
+	api.put("schedules", "hourly", 1, ScheduleSpec{
+		Schedule:          "@hourly",
+		ConcurrencyPolicy: ConcurrencyForbid,
+		Workflow:          testWorkflowSpec,
+	})
+	scheduleStatus := func() ScheduleStatus {
+		data, _ := json.Marshal(api.object("schedules", "hourly")["status"])
+		var status ScheduleStatus
+		json.Unmarshal(data, &status)
+		return status
+	}
+
+	c.now = func() time.Time { return time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC) }
+	c.Reconcile(ctx)
+	if status := scheduleStatus(); eng.count() != 0 || status.NextScheduleTime == nil || status.NextScheduleTime.Hour() != 11 {
+		t.Fatalf("before due: submissions = %d, status = %+v", eng.count(), status)
+	}
+
+	// Runs missed until 13:05 collapse into the 13:00 run.
+	c.now = func() time.Time { return time.Date(2026, 3, 14, 13, 5, 0, 0, time.UTC) }
+	c.Reconcile(ctx)
+	status := scheduleStatus()
+	if eng.count() != 1 || status.LastWorkflowID != "wf-1" || status.LastScheduleTime.Hour() != 13 || status.NextScheduleTime.Hour() != 14 {
+		t.Fatalf("when due: submissions = %d, status = %+v", eng.count(), status)
+	}
+
+	// Forbid skips the 14:00 run while wf-1 is unfinished.
+	c.now = func() time.Time { return time.Date(2026, 3, 14, 14, 0, 0, 0, time.UTC) }
+	c.Reconcile(ctx)
+	if status := scheduleStatus(); eng.count() != 1 || status.LastScheduleTime.Hour() != 14 || !strings.Contains(status.Message, "skipped") {
+		t.Fatalf("forbidden run: submissions = %d, status = %+v", eng.count(), status)
+	}
+
+	eng.set("wf-1", "completed")
+	c.now = func() time.Time { return time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC) }
+	c.Reconcile(ctx)
+	if status := scheduleStatus(); eng.count() != 2 || status.LastWorkflowID != "wf-2" || status.Message != "" {
+		t.Fatalf("after completion: submissions = %d, status = %+v", eng.count(), status)
+	}
+}
+
+func TestController_ScheduleCatchUpAndCalendar(t *testing.T) {
+	c, api, eng := newTestController(t)
+	ctx := context.Background()
+	api.put("schedules", "six-hourly", 1, ScheduleSpec{
+		Schedule:      "0 */6 * * *",
+		CatchUpPolicy: CatchUpRunAll,
+		Holidays:      []string{"2026-03-15"},
+		Blackouts:     []BlackoutWindow{{Schedule: "0 18 * * *", Duration: "1h"}},
+		Workflow:      testWorkflowSpec,
+	})
+	scheduleStatus := func() ScheduleStatus {
+		data, _ := json.Marshal(api.object("schedules", "six-hourly")["status"])
+		var status ScheduleStatus
+		json.Unmarshal(data, &status)
+		return status
+	}
+
+	// Runs missed until the 16th run one per reconcile, leaving out the
+	// holiday and the evening blackout.
+	c.now = func() time.Time { return time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC) }
+	var due []string
+	for i := 0; i < 4; i++ {
+		c.Reconcile(ctx)
+		if status := scheduleStatus(); eng.count() == i+1 {
+			due = append(due, status.LastScheduleTime.Format(time.RFC3339))
+		}
+	}
+	want := []string{"2026-03-14T12:00:00Z", "2026-03-16T00:00:00Z", "2026-03-16T06:00:00Z"}
+	if eng.count() != 3 || strings.Join(due, ",") != strings.Join(want, ",") {
+		t.Fatalf("caught up on %v with %d submissions, want %v", due, eng.count(), want)
+	}
+	if status := scheduleStatus(); status.NextScheduleTime == nil || !status.NextScheduleTime.Equal(time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("after catching up: status = %+v", status)
+	}
+
+	api.put("schedules", "six-hourly", 2, ScheduleSpec{
+		Schedule:  "0 */6 * * *",
+		Blackouts: []BlackoutWindow{{Schedule: "0 18 * * *"}},
+		Workflow:  testWorkflowSpec,
+	})
+	c.Reconcile(ctx)
+	if status := scheduleStatus(); status.NextScheduleTime != nil || !strings.Contains(status.Message, "duration") {
+		t.Fatalf("invalid blackout: status = %+v", status)
+	}
+}
@@ -0,0 +1,159 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+)
+
+// API group and version of the goclaw custom resources.
+const (
+	Group   = "goclaw.io"
+	Version = "v1alpha1"
+)
+
+// LabelResource is the workflow metadata key naming the custom resource,
+// as namespace/name, a workflow was submitted for.
+const LabelResource = "goclaw.io/resource"
+
+// Phases of a Workflow resource besides the goclaw workflow statuses
+// (pending, scheduled, running, completed, failed, cancelled).
+const (
+	// PhaseInvalid marks a spec goclaw rejected; it is retried once the
+	// spec changes.
+	PhaseInvalid = "invalid"
+)
+
+// Schedule concurrency policies.
+const (
+	// ConcurrencyAllow starts runs even while earlier ones are unfinished.
+	ConcurrencyAllow = "Allow"
+	// ConcurrencyForbid skips a run while the previous one is unfinished.
+	ConcurrencyForbid = "Forbid"
+	// ConcurrencyReplace cancels the unfinished previous run.
+	ConcurrencyReplace = "Replace"
+)
+
+// Schedule catch-up policies for runs missed while the controller was down.
+const (
+	// CatchUpSkip skips missed runs.
+	CatchUpSkip = "Skip"
+	// CatchUpRunOnce runs the latest missed run. It is the default.
+	CatchUpRunOnce = "RunOnce"
+	// CatchUpRunAll runs every missed run, oldest first, one per resync.
+	CatchUpRunAll = "RunAll"
+)
+
+// ObjectMeta is the part of Kubernetes object metadata the controller reads.
+type ObjectMeta struct {
+	Name              string    `json:"name"`
+	Namespace         string    `json:"namespace"`
+	UID               string    `json:"uid,omitempty"`
+	ResourceVersion   string    `json:"resourceVersion,omitempty"`
+	Generation        int64     `json:"generation,omitempty"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// Workflow is a goclaw workflow managed as a Kubernetes resource. The
+// controller submits its spec, and submits it again each time the spec
+// changes, cancelling the run of the previous spec.
+type Workflow struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   ObjectMeta `json:"metadata"`
+
+	// Spec is the workflow, in the body format of POST /api/v1/workflows.
+	Spec models.WorkflowRequest `json:"spec"`
+
+	Status WorkflowStatus `json:"status"`
+}
+
+// WorkflowStatus is the state of the goclaw workflow of a Workflow.
+type WorkflowStatus struct {
+	// ObservedGeneration is the resource generation WorkflowID runs.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// WorkflowID is the ID of the goclaw workflow.
+	WorkflowID string `json:"workflowID,omitempty"`
+
+	// Phase is the goclaw workflow status, or PhaseInvalid.
+	Phase string `json:"phase,omitempty"`
+
+	// Message is the workflow error or why the spec was rejected.
+	Message string `json:"message,omitempty"`
+
+	// Tasks counts the workflow's tasks per status.
+	Tasks map[string]int `json:"tasks,omitempty"`
+
+	// StartedAt is when the workflow started executing.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is when the workflow completed.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Schedule submits a workflow on a cron schedule.
+type Schedule struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Metadata   ObjectMeta   `json:"metadata"`
+	Spec       ScheduleSpec `json:"spec"`
+
+	Status ScheduleStatus `json:"status"`
+}
+
+// ScheduleSpec describes when and what a Schedule submits.
+type ScheduleSpec struct {
+	// Schedule is a five-field cron expression or an @ shorthand such as
+	// @hourly.
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the IANA time zone of Schedule. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Suspend stops new runs while set.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ConcurrencyPolicy is Allow (default), Forbid or Replace.
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+
+	// CatchUpPolicy is Skip, RunOnce (default) or RunAll.
+	CatchUpPolicy string `json:"catchUpPolicy,omitempty"`
+
+	// Holidays are days, as YYYY-MM-DD in TimeZone, without runs.
+	Holidays []string `json:"holidays,omitempty"`
+
+	// Blackouts are windows no runs start in.
+	Blackouts []BlackoutWindow `json:"blackouts,omitempty"`
+
+	// Workflow is the workflow each run submits, in the body format of
+	// POST /api/v1/workflows.
+	Workflow models.WorkflowRequest `json:"workflow"`
+}
+
+// BlackoutWindow is a window no runs of a Schedule start in: once from
+// Start to End, or for Duration from every time Schedule matches.
+type BlackoutWindow struct {
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+
+	// Schedule is a cron expression in the TimeZone of the Schedule.
+	Schedule string `json:"schedule,omitempty"`
+	// Duration is a Go duration such as 2h.
+	Duration string `json:"duration,omitempty"`
+}
+
+// ScheduleStatus records the runs of a Schedule.
+type ScheduleStatus struct {
+	// LastScheduleTime is when the last run was due.
+	LastScheduleTime *time.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is when the next run is due.
+	NextScheduleTime *time.Time `json:"nextScheduleTime,omitempty"`
+
+	// LastWorkflowID is the goclaw workflow of the last run.
+	LastWorkflowID string `json:"lastWorkflowID,omitempty"`
+
+	// Message is why the schedule is not running, if it is not.
+	Message string `json:"message,omitempty"`
+}
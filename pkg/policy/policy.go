@@ -0,0 +1,129 @@
+// Package policy evaluates submissions and config updates against
+// organisation policies held by an Open Policy Agent (OPA) server.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Kind is the kind of request a policy is evaluated for.
+type Kind string
+
+// Request kinds.
+const (
+	KindWorkflow Kind = "workflow"
+	KindSaga     Kind = "saga"
+	KindConfig   Kind = "config"
+)
+
+// Input is the document a policy is evaluated against, sent to OPA as its
+// input.
+type Input struct {
+	Kind      Kind   `json:"kind"`
+	RequestID string `json:"request_id,omitempty"`
+	// Request is the submitted workflow or saga, or the config changes.
+	Request any `json:"request"`
+}
+
+// Decision is the outcome of a policy evaluation.
+type Decision struct {
+	Allowed bool `json:"allowed"`
+	// Violations explains why a request was denied.
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Evaluator decides whether a request complies with the policies.
+type Evaluator interface {
+	Evaluate(ctx context.Context, input Input) (Decision, error)
+}
+
+// OPA evaluates policies with the data API of an OPA server. The decision
+// document at the configured URL is either a boolean, or an object with an
+// optional boolean allow and a set of deny messages; a request is allowed
+// when allow is not false and nothing is denied.
+type OPA struct {
+	url    string
+	client *http.Client
+}
+
+// NewOPA creates an evaluator for the decision at cfg.URL, such as
+// http://localhost:8181/v1/data/goclaw/admission.
+func NewOPA(cfg config.PolicyConfig) *OPA {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &OPA{url: cfg.URL, client: &http.Client{Timeout: timeout}}
+}
+
+// Evaluate implements Evaluator.
+func (o *OPA) Evaluate(ctx context.Context, input Input) (Decision, error) {
+	body, err := json.Marshal(struct {
+		Input Input `json:"input"`
+	}{input})
+	if err != nil {
+		return Decision{}, fmt.Errorf("encode policy input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("query policy server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return Decision{}, fmt.Errorf("policy server responded with status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Decision{}, fmt.Errorf("decode policy decision: %w", err)
+	}
+	return parseResult(out.Result)
+}
+
+// parseResult reads an OPA decision document.
+func parseResult(raw json.RawMessage) (Decision, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return Decision{}, fmt.Errorf("policy decision is undefined")
+	}
+	var allowed bool
+	if err := json.Unmarshal(raw, &allowed); err == nil {
+		return Decision{Allowed: allowed}, nil
+	}
+	var result struct {
+		Allow *bool    `json:"allow"`
+		Deny  []string `json:"deny"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return Decision{}, fmt.Errorf("decode policy decision: %w", err)
+	}
+	if result.Allow == nil && result.Deny == nil {
+		return Decision{}, fmt.Errorf("policy decision has neither allow nor deny")
+	}
+	decision := Decision{
+		Allowed:    (result.Allow == nil || *result.Allow) && len(result.Deny) == 0,
+		Violations: result.Deny,
+	}
+	if !decision.Allowed && len(decision.Violations) == 0 {
+		decision.Violations = []string{"denied by policy"}
+	}
+	return decision, nil
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/goclaw/goclaw/config"
+)
+
+func TestOPA_Evaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  string
+		want    Decision
+		wantErr bool
+	}{
+		{name: "boolean allow", result: `{"result": true}`, want: Decision{Allowed: true}},
+		{name: "boolean deny", result: `{"result": false}`, want: Decision{Allowed: false}},
+		{name: "empty deny set", result: `{"result": {"deny": []}}`, want: Decision{Allowed: true, Violations: []string{}}},
+		{
+			name:   "deny messages",
+			result: `{"result": {"allow": true, "deny": ["image docker.io/evil is forbidden", "label team is required"]}}`,
+			want: Decision{Violations: []string{
+				"image docker.io/evil is forbidden",
+				"label team is required",
+			}},
+		},
+		{name: "allow false", result: `{"result": {"allow": false}}`, want: Decision{Violations: []string{"denied by policy"}}},
+		{name: "undefined", result: `{}`, wantErr: true},
+		{name: "unknown document", result: `{"result": {"ok": true}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Input
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Input Input `json:"input"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode input: %v", err)
+				}
+				got = body.Input
+				_, _ = w.Write([]byte(tt.result))
+			}))
+			defer server.Close()
+
+			decision, err := NewOPA(config.PolicyConfig{URL: server.URL}).Evaluate(context.Background(), Input{
+				Kind:      KindWorkflow,
+				RequestID: "req-1",
+				Request:   map[string]any{"name": "etl"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Kind != KindWorkflow || got.RequestID != "req-1" {
+				t.Fatalf("policy input = %+v", got)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(decision, tt.want) {
+				t.Fatalf("Evaluate() = %+v, want %+v", decision, tt.want)
+			}
+		})
+	}
+}
+
+func TestOPA_EvaluateServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	evaluator := NewOPA(config.PolicyConfig{URL: server.URL})
+	if _, err := evaluator.Evaluate(context.Background(), Input{Kind: KindConfig}); err == nil {
+		t.Fatal("Evaluate() succeeded on a server error")
+	}
+
+	server.Close()
+	if _, err := evaluator.Evaluate(context.Background(), Input{Kind: KindConfig}); err == nil {
+		t.Fatal("Evaluate() against an unreachable server succeeded")
+	}
+}
@@ -0,0 +1,16 @@
+// Package rediskey names the Redis keys and channels goclaw writes.
+//
+// Every key starts with the namespace configured as redis.key_prefix,
+// followed by Root and the name of the component owning the key, so that
+// environments sharing one Redis stay apart and moving a namespace moves
+// every key of it.
+package rediskey
+
+// Root follows the namespace in every key and channel.
+const Root = "goclaw:"
+
+// Prefix returns the prefix of the keys of component in namespace, e.g.
+// "staging:goclaw:lock:" for Prefix("staging:", "lock").
+func Prefix(namespace, component string) string {
+	return namespace + Root + component + ":"
+}
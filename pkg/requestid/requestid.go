@@ -0,0 +1,43 @@
+// Package requestid carries the ID of the request that caused some work, so
+// that HTTP and gRPC requests, the workflow runs they submit, the events of
+// those runs and their log lines can be tied together.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header is the HTTP header that carries the request ID.
+	Header = "X-Request-ID"
+
+	// MetadataKey is the gRPC metadata key that carries the request ID.
+	MetadataKey = "x-request-id"
+)
+
+// New returns a new request ID.
+func New() string {
+	return uuid.New().String()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id. An empty id leaves ctx
+// unchanged.
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
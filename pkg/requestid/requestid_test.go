@@ -0,0 +1,22 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if got := FromContext(ctx); got != "" {
+		t.Fatalf("FromContext(empty) = %q, want empty", got)
+	}
+	if NewContext(ctx, "") != ctx {
+		t.Fatal("NewContext with an empty ID should return ctx unchanged")
+	}
+	if got := FromContext(NewContext(ctx, "req-1")); got != "req-1" {
+		t.Fatalf("FromContext() = %q, want req-1", got)
+	}
+	if New() == New() {
+		t.Fatal("New() returned the same ID twice")
+	}
+}
@@ -0,0 +1,31 @@
+package saga
+
+import "context"
+
+// Submission describes a saga submitted for execution.
+type Submission struct {
+	Definition *SagaDefinition
+	Metadata   map[string]string
+	Input      any
+}
+
+// AdmissionFunc decides whether a submitted saga may run. A non-nil error
+// rejects it.
+type AdmissionFunc func(ctx context.Context, submission Submission) error
+
+// WithAdmission sets the check Admit runs submissions through.
+func WithAdmission(admit AdmissionFunc) OrchestratorOption {
+	return func(orchestrator *SagaOrchestrator) {
+		orchestrator.admit = admit
+	}
+}
+
+// Admit checks a submission before it is executed. Callers that execute
+// sagas in the background call it first so that a rejection reaches the
+// submitter.
+func (o *SagaOrchestrator) Admit(ctx context.Context, submission Submission) error {
+	if o.admit == nil {
+		return nil
+	}
+	return o.admit(ctx, submission)
+}
@@ -0,0 +1,139 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+const sagaArchiveKeyPrefix = "saga-archive:"
+
+// ErrArchiveNotFound is returned when a saga was not archived.
+var ErrArchiveNotFound = errs.New(errs.NotFound, "archived saga not found")
+
+// ArchivedSaga is a terminal saga exported before its WAL and checkpoint
+// were pruned.
+type ArchivedSaga struct {
+	Instance *SagaInstance `json:"instance"`
+	// WAL holds the WAL entries of the saga in sequence order.
+	WAL        []WALEntry `json:"wal"`
+	ArchivedAt time.Time  `json:"archived_at"`
+}
+
+// ArchiveStore keeps archived sagas.
+type ArchiveStore interface {
+	Put(ctx context.Context, archived *ArchivedSaga) error
+	Get(ctx context.Context, sagaID string) (*ArchivedSaga, error)
+	List(ctx context.Context, filter SagaListFilter) ([]*ArchivedSaga, int, error)
+}
+
+// BadgerArchiveStore stores archived sagas in Badger.
+type BadgerArchiveStore struct {
+	db *badger.DB
+}
+
+// NewBadgerArchiveStore creates a Badger-backed archive store.
+func NewBadgerArchiveStore(db *badger.DB) (*BadgerArchiveStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("badger db cannot be nil")
+	}
+	return &BadgerArchiveStore{db: db}, nil
+}
+
+// Put persists one archived saga at key "saga-archive:{sagaID}".
+func (s *BadgerArchiveStore) Put(ctx context.Context, archived *ArchivedSaga) error {
+	if archived == nil || archived.Instance == nil {
+		return fmt.Errorf("archived saga instance cannot be nil")
+	}
+	data, err := json.Marshal(archived)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		return txn.Set([]byte(sagaArchiveKey(archived.Instance.ID)), data)
+	})
+}
+
+// Get loads one archived saga by id.
+func (s *BadgerArchiveStore) Get(ctx context.Context, sagaID string) (*ArchivedSaga, error) {
+	var archived ArchivedSaga
+	err := s.db.View(func(txn *badger.Txn) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		item, err := txn.Get([]byte(sagaArchiveKey(sagaID)))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return ErrArchiveNotFound
+			}
+			return err
+		}
+		return item.Value(func(v []byte) error { return json.Unmarshal(v, &archived) })
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &archived, nil
+}
+
+// List lists archived sagas in saga ID order, with optional terminal state
+// filter and pagination.
+func (s *BadgerArchiveStore) List(ctx context.Context, filter SagaListFilter) ([]*ArchivedSaga, int, error) {
+	archived := make([]*ArchivedSaga, 0)
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(sagaArchiveKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			var entry ArchivedSaga
+			if err := it.Item().Value(func(v []byte) error { return json.Unmarshal(v, &entry) }); err != nil {
+				continue
+			}
+			if filter.State != "" && (entry.Instance == nil || entry.Instance.State.String() != filter.State) {
+				continue
+			}
+			archived = append(archived, &entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(archived)
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if filter.Limit > 0 && offset+filter.Limit < end {
+		end = offset + filter.Limit
+	}
+	return archived[offset:end], total, nil
+}
+
+func sagaArchiveKey(sagaID string) string {
+	return sagaArchiveKeyPrefix + sagaID
+}
@@ -97,35 +97,17 @@ func (e *CompensationExecutor) Execute(
 		return fmt.Errorf("saga instance cannot be nil")
 	}
 
-	layers, err := definition.TopologicalLayers()
+	plan, err := e.Plan(definition, instance)
 	if err != nil {
 		return err
 	}
 
-	completed := make(map[string]struct{}, len(instance.CompletedSteps))
-	for _, stepID := range instance.CompletedSteps {
-		completed[stepID] = struct{}{}
-	}
-
-	for i := len(layers) - 1; i >= 0; i-- {
-		layer := layers[i]
-
+	for _, stage := range plan.Stages() {
 		var wg sync.WaitGroup
 		var mu sync.Mutex
 		var firstErr error
 
-		for _, stepID := range layer {
-			if _, ok := completed[stepID]; !ok {
-				continue
-			}
-			step := definition.Steps[stepID]
-			if step == nil || step.Compensation == nil {
-				continue
-			}
-			if step.CompensationPolicy == SkipCompensate {
-				continue
-			}
-
+		for _, planned := range stage {
 			wg.Add(1)
 			go func(step *Step) {
 				defer wg.Done()
@@ -136,7 +118,7 @@ func (e *CompensationExecutor) Execute(
 					}
 					mu.Unlock()
 				}
-			}(step)
+			}(definition.Steps[planned.StepID])
 		}
 
 		wg.Wait()
@@ -148,6 +130,117 @@ func (e *CompensationExecutor) Execute(
 	return nil
 }
 
+// Reasons a completed step is left out of a compensation plan.
+const (
+	SkipReasonNoCompensation     = "no compensation"
+	SkipReasonPolicy             = "compensation policy skip"
+	SkipReasonAlreadyCompensated = "already compensated"
+)
+
+// PlannedCompensation is one completed step of a compensation plan.
+type PlannedCompensation struct {
+	StepID string
+	// Stage orders the compensations: stages run one after another, from
+	// zero, and the steps of one stage run concurrently.
+	Stage   int
+	Policy  CompensationPolicy
+	Timeout time.Duration
+	// SkipReason says why the step would not be compensated; it is empty for
+	// steps that would be.
+	SkipReason string
+}
+
+// CompensationPlan lists the compensations Execute would run for a saga
+// instance, in order, and the completed steps it would leave alone.
+type CompensationPlan struct {
+	SagaID     string
+	State      SagaState
+	FailedStep string
+	Policy     CompensationPolicy
+	Retry      CompensationRetryConfig
+	Steps      []PlannedCompensation
+	Skipped    []PlannedCompensation
+}
+
+// Stages groups the steps of the plan by stage, in the order they run.
+func (p *CompensationPlan) Stages() [][]PlannedCompensation {
+	var stages [][]PlannedCompensation
+	for _, step := range p.Steps {
+		if len(stages) == 0 || stages[len(stages)-1][0].Stage != step.Stage {
+			stages = append(stages, nil)
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], step)
+	}
+	return stages
+}
+
+// Plan returns the compensation plan of instance without running it: its
+// completed steps in reverse topological layers, less those without a
+// compensation, with the skip policy, or compensated already.
+func (e *CompensationExecutor) Plan(definition *SagaDefinition, instance *SagaInstance) (*CompensationPlan, error) {
+	if definition == nil {
+		return nil, fmt.Errorf("saga definition cannot be nil")
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("saga instance cannot be nil")
+	}
+
+	layers, err := definition.TopologicalLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[string]struct{}, len(instance.CompletedSteps))
+	for _, stepID := range instance.CompletedSteps {
+		completed[stepID] = struct{}{}
+	}
+
+	plan := &CompensationPlan{
+		SagaID:     instance.ID,
+		State:      instance.State,
+		FailedStep: instance.FailedStep,
+		Policy:     definition.Policy,
+		Retry:      definition.Retry,
+	}
+	stage := 0
+	for i := len(layers) - 1; i >= 0; i-- {
+		planned := false
+		for _, stepID := range layers[i] {
+			if _, ok := completed[stepID]; !ok {
+				continue
+			}
+			step := definition.Steps[stepID]
+			if step == nil {
+				continue
+			}
+			timeout := step.Timeout
+			if timeout <= 0 {
+				timeout = definition.DefaultStepTimeout
+			}
+			entry := PlannedCompensation{StepID: stepID, Policy: step.CompensationPolicy, Timeout: timeout}
+			switch {
+			case step.Compensation == nil:
+				entry.SkipReason = SkipReasonNoCompensation
+			case step.CompensationPolicy == SkipCompensate:
+				entry.SkipReason = SkipReasonPolicy
+			case e.idempotencyStore.Seen(CompensationIdempotencyKey(instance.ID, stepID)):
+				entry.SkipReason = SkipReasonAlreadyCompensated
+			}
+			if entry.SkipReason != "" {
+				plan.Skipped = append(plan.Skipped, entry)
+				continue
+			}
+			entry.Stage = stage
+			plan.Steps = append(plan.Steps, entry)
+			planned = true
+		}
+		if planned {
+			stage++
+		}
+	}
+	return plan, nil
+}
+
 func (e *CompensationExecutor) executeStepCompensation(
 	ctx context.Context,
 	definition *SagaDefinition,
@@ -3,6 +3,7 @@ package saga
 import (
 	"context"
 	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -217,6 +218,56 @@ func TestCompensationSkipPolicyOnStep(t *testing.T) {
 	}
 }
 
+func TestCompensationPlan(t *testing.T) {
+	act := Action(func(context.Context, *StepContext) (any, error) { return nil, nil })
+	noop := Compensate(func(context.Context, *CompensationContext) error { return nil })
+	def, err := New("comp-plan").
+		WithDefaultStepTimeout(time.Second).
+		Step("a", act, noop).
+		Step("b", act, noop, DependsOn("a"), StepTimeout(2*time.Second)).
+		Step("c", act, noop, DependsOn("a")).
+		Step("d", act, DependsOn("b")).
+		Step("e", act, noop, DependsOn("c"), WithStepCompensationPolicy(SkipCompensate)).
+		Step("f", act, noop, DependsOn("d", "e")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	instance := NewSagaInstance("saga-plan", def)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		instance.MarkStepCompleted(id, nil)
+	}
+	instance.SetFailure("f", errors.New("fail-f"))
+
+	store := NewInMemoryIdempotencyStore()
+	store.Mark(CompensationIdempotencyKey("saga-plan", "c"))
+	plan, err := NewCompensationExecutor(nil, store).Plan(def, instance)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := []PlannedCompensation{
+		{StepID: "b", Stage: 0, Timeout: 2 * time.Second},
+		{StepID: "a", Stage: 1, Timeout: time.Second},
+	}
+	if !reflect.DeepEqual(plan.Steps, want) {
+		t.Fatalf("Plan() steps = %+v, want %+v", plan.Steps, want)
+	}
+	skipped := map[string]string{}
+	for _, step := range plan.Skipped {
+		skipped[step.StepID] = step.SkipReason
+	}
+	if !reflect.DeepEqual(skipped, map[string]string{
+		"c": SkipReasonAlreadyCompensated,
+		"d": SkipReasonNoCompensation,
+		"e": SkipReasonPolicy,
+	}) {
+		t.Fatalf("Plan() skipped = %v", skipped)
+	}
+	if plan.FailedStep != "f" || len(plan.Stages()) != 2 {
+		t.Fatalf("Plan() = %+v, want f failed and two stages", plan)
+	}
+}
+
 func TestIdempotencyUtilities(t *testing.T) {
 	store := NewInMemoryIdempotencyStore()
 	key := CompensationIdempotencyKey("saga-1", "step-a")
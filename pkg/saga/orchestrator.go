@@ -7,13 +7,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/goclaw/goclaw/pkg/errs"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
 )
 
 // ErrSagaNotFound is returned when saga instance cannot be located.
-var ErrSagaNotFound = errors.New("saga instance not found")
+var ErrSagaNotFound = errs.New(errs.NotFound, "saga instance not found")
+
+// ErrSagaStuck is the failure of sagas escalated because their forward
+// execution made no progress for too long.
+var ErrSagaStuck = errs.New(errs.Timeout, "saga made no progress within its stuck timeout")
 
 // OrchestratorOption customizes SagaOrchestrator initialization.
 type OrchestratorOption func(orchestrator *SagaOrchestrator)
@@ -79,12 +84,17 @@ type SagaOrchestrator struct {
 	metrics              MetricsRecorder
 	maxConcurrent        int
 	sema                 chan struct{}
+	admit                AdmissionFunc
+	// running holds the cancel functions of the forward executions in
+	// progress, by saga ID, for Escalate.
+	running map[string]context.CancelCauseFunc
 }
 
 // NewSagaOrchestrator creates a Saga orchestrator.
 func NewSagaOrchestrator(options ...OrchestratorOption) *SagaOrchestrator {
 	orchestrator := &SagaOrchestrator{
 		instances:            make(map[string]*SagaInstance),
+		running:              make(map[string]context.CancelCauseFunc),
 		compensationExecutor: NewCompensationExecutor(nil, NewInMemoryIdempotencyStore()),
 		metrics:              &nopMetricsRecorder{},
 		maxConcurrent:        100,
@@ -140,10 +150,11 @@ func (o *SagaOrchestrator) ExecuteWithID(
 	}
 	defer func() { <-o.sema }()
 
-	sagaCtx := ctx
+	sagaCtx, escalate := context.WithCancelCause(ctx)
+	defer escalate(nil)
 	cancel := func() {}
 	if definition.Timeout > 0 {
-		sagaCtx, cancel = context.WithTimeout(ctx, definition.Timeout)
+		sagaCtx, cancel = context.WithTimeout(sagaCtx, definition.Timeout)
 	}
 	defer cancel()
 
@@ -152,6 +163,10 @@ func (o *SagaOrchestrator) ExecuteWithID(
 		return nil, err
 	}
 	o.saveInstance(instance)
+	o.mu.Lock()
+	o.running[sagaID] = escalate
+	o.mu.Unlock()
+	defer o.untrack(sagaID)
 
 	layers, err := definition.TopologicalLayers()
 	if err != nil {
@@ -188,7 +203,9 @@ func (o *SagaOrchestrator) ExecuteWithID(
 			}(step)
 		}
 
-		wg.Wait()
+		if !waitLayer(sagaCtx, &wg) {
+			break
+		}
 		close(layerErrCh)
 		if failure, ok := <-layerErrCh; ok {
 			failedStep = failure.stepID
@@ -196,6 +213,21 @@ func (o *SagaOrchestrator) ExecuteWithID(
 			break
 		}
 	}
+	o.untrack(sagaID)
+
+	if errors.Is(context.Cause(sagaCtx), ErrSagaStuck) {
+		// Steps may still be in flight, so leave compensating them to an
+		// operator, whatever the policy.
+		sagaSpan.RecordError(ErrSagaStuck)
+		instanceMu.Lock()
+		defer instanceMu.Unlock()
+		instance.SetFailure("saga-stuck", ErrSagaStuck)
+		_ = instance.TransitionTo(SagaStatePendingCompensation)
+		o.saveInstance(instance)
+		o.recordExecutionMetrics(instance, startedAt)
+		sagaSpan.SetStatus(otelcodes.Error, SagaStatePendingCompensation.String())
+		return instance, ErrSagaStuck
+	}
 
 	if execErr == nil && sagaCtx.Err() != nil {
 		failedStep = "saga-timeout"
@@ -290,6 +322,16 @@ func (o *SagaOrchestrator) TriggerCompensation(
 	return instance, nil
 }
 
+// PreviewCompensation returns the compensation plan TriggerCompensation
+// would run for the saga, without running it or changing its state.
+func (o *SagaOrchestrator) PreviewCompensation(sagaID string, definition *SagaDefinition) (*CompensationPlan, error) {
+	instance, err := o.GetInstance(sagaID)
+	if err != nil {
+		return nil, err
+	}
+	return o.compensationExecutor.Plan(definition, instance)
+}
+
 // ResumeFromCheckpoint resumes a saga from persisted checkpoint state.
 func (o *SagaOrchestrator) ResumeFromCheckpoint(
 	ctx context.Context,
@@ -447,6 +489,41 @@ func (o *SagaOrchestrator) ListInstancesFiltered(ctx context.Context, filter Sag
 	return all[filter.Offset:end], total, nil
 }
 
+// DeleteInstance removes a saga instance from memory and the saga store.
+func (o *SagaOrchestrator) DeleteInstance(ctx context.Context, sagaID string) error {
+	o.mu.Lock()
+	delete(o.instances, sagaID)
+	o.mu.Unlock()
+	if o.store == nil {
+		return nil
+	}
+	if err := o.store.Delete(ctx, sagaID); err != nil && !errors.Is(err, ErrSagaNotFound) {
+		return err
+	}
+	return nil
+}
+
+// RestoreInstance stores instance as it is, without running or compensating
+// it, such as when restoring the sagas of a snapshot. It fails if a saga with
+// the same ID exists.
+func (o *SagaOrchestrator) RestoreInstance(ctx context.Context, instance *SagaInstance) error {
+	if instance == nil || instance.ID == "" {
+		return errs.New(errs.BadRequest, "saga instance must have an id")
+	}
+	if _, err := o.GetInstance(instance.ID); err == nil {
+		return errs.Newf(errs.AlreadyExists, "saga %s already exists", instance.ID)
+	}
+	if o.store != nil {
+		if err := o.store.Save(ctx, instance); err != nil {
+			return err
+		}
+	}
+	o.mu.Lock()
+	o.instances[instance.ID] = cloneInstance(instance)
+	o.mu.Unlock()
+	return nil
+}
+
 func (o *SagaOrchestrator) executeStep(
 	ctx context.Context,
 	definition *SagaDefinition,
@@ -646,6 +723,46 @@ func (o *SagaOrchestrator) resumeRunning(
 	return instance, nil
 }
 
+// Escalate abandons the forward execution of the saga as stuck: the
+// execution stops waiting for its steps in flight and moves the saga to
+// pending-compensation, whatever its compensation policy. It reports whether
+// the orchestrator was running the saga forward.
+func (o *SagaOrchestrator) Escalate(sagaID string) bool {
+	o.mu.RLock()
+	escalate, ok := o.running[sagaID]
+	o.mu.RUnlock()
+	if ok {
+		escalate(ErrSagaStuck)
+	}
+	return ok
+}
+
+func (o *SagaOrchestrator) untrack(sagaID string) {
+	o.mu.Lock()
+	delete(o.running, sagaID)
+	o.mu.Unlock()
+}
+
+// waitLayer waits for the steps of a layer to finish. It returns false,
+// without waiting further, when the saga is escalated as stuck.
+func waitLayer(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		if errors.Is(context.Cause(ctx), ErrSagaStuck) {
+			return false
+		}
+		<-done
+		return true
+	}
+}
+
 type stepFailure struct {
 	stepID string
 	err    error
@@ -111,12 +111,21 @@ func (m *RecoveryManager) Recover(
 	return recovered, firstErr
 }
 
+// InstanceSource lists the saga instances cleanup archives and deletes them
+// once archived. SagaOrchestrator implements it.
+type InstanceSource interface {
+	ListInstancesFiltered(ctx context.Context, filter SagaListFilter) ([]*SagaInstance, int, error)
+	DeleteInstance(ctx context.Context, sagaID string) error
+}
+
 // CleanupManager handles WAL retention and checkpoint cleanup.
 type CleanupManager struct {
 	wal         *BadgerWAL
 	checkpoints CheckpointStore
 	isTerminal  func(sagaID string) bool
 	logger      RecoveryLogger
+	archive     ArchiveStore
+	instances   InstanceSource
 
 	mu      sync.Mutex
 	running bool
@@ -140,6 +149,16 @@ func NewCleanupManager(
 	}
 }
 
+// WithArchive makes cleanup archive terminal sagas instead of pruning their
+// WAL entries one by one: once a saga has been terminal for the retention,
+// its instance and WAL are exported to archive, then its WAL, checkpoint and
+// instance are deleted. It returns m.
+func (m *CleanupManager) WithArchive(archive ArchiveStore, instances InstanceSource) *CleanupManager {
+	m.archive = archive
+	m.instances = instances
+	return m
+}
+
 // Start runs periodic cleanup until the context is cancelled.
 func (m *CleanupManager) Start(ctx context.Context, interval, retention time.Duration) error {
 	if m.wal == nil {
@@ -197,6 +216,9 @@ func (m *CleanupManager) RunOnce(ctx context.Context, retention time.Duration) (
 	}
 
 	cutoff := time.Now().UTC().Add(-retention)
+	if m.archive != nil && m.instances != nil {
+		return m.archiveExpired(ctx, cutoff)
+	}
 	expiredBySaga := make(map[string][][]byte)
 
 	err := m.wal.db.View(func(txn *badger.Txn) error {
@@ -271,6 +293,49 @@ func (m *CleanupManager) RunOnce(ctx context.Context, retention time.Duration) (
 	return totalDeleted, nil
 }
 
+// archiveExpired archives and deletes the sagas that have been terminal since
+// before cutoff. It returns the number of WAL entries deleted.
+func (m *CleanupManager) archiveExpired(ctx context.Context, cutoff time.Time) (int, error) {
+	totalDeleted, archived := 0, 0
+	for _, state := range []SagaState{SagaStateCompleted, SagaStateCompensated, SagaStateCompensationFailed} {
+		instances, _, err := m.instances.ListInstancesFiltered(ctx, SagaListFilter{State: state.String()})
+		if err != nil {
+			return totalDeleted, err
+		}
+		for _, instance := range instances {
+			if instance.CompletedAt == nil || instance.CompletedAt.After(cutoff) {
+				continue
+			}
+			entries, err := m.wal.List(ctx, instance.ID)
+			if err != nil {
+				return totalDeleted, err
+			}
+			if err := m.archive.Put(ctx, &ArchivedSaga{
+				Instance:   instance,
+				WAL:        entries,
+				ArchivedAt: time.Now().UTC(),
+			}); err != nil {
+				return totalDeleted, fmt.Errorf("archive saga %s: %w", instance.ID, err)
+			}
+			if err := m.wal.DeleteBySagaID(ctx, instance.ID); err != nil {
+				return totalDeleted, err
+			}
+			totalDeleted += len(entries)
+			if m.checkpoints != nil {
+				_ = m.checkpoints.Delete(ctx, instance.ID)
+			}
+			if err := m.instances.DeleteInstance(ctx, instance.ID); err != nil {
+				return totalDeleted, err
+			}
+			archived++
+		}
+	}
+	if archived > 0 {
+		m.logger.Info("sagas archived", "sagas", archived)
+	}
+	return totalDeleted, nil
+}
+
 func (m *CleanupManager) isSagaTerminal(sagaID string) bool {
 	if m.isTerminal == nil {
 		return true
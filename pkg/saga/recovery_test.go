@@ -272,6 +272,88 @@ func TestCleanupManagerRunOnce(t *testing.T) {
 	}
 }
 
+func TestCleanupManagerArchivesTerminalSagas(t *testing.T) {
+	db := openTestBadger(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	wal, err := NewBadgerWAL(db, WALOptions{WriteMode: WALWriteModeSync})
+	if err != nil {
+		t.Fatalf("NewBadgerWAL() error = %v", err)
+	}
+	checkpointStore, err := NewBadgerCheckpointStore(db)
+	if err != nil {
+		t.Fatalf("NewBadgerCheckpointStore() error = %v", err)
+	}
+	sagaStore, err := NewBadgerSagaStore(db)
+	if err != nil {
+		t.Fatalf("NewBadgerSagaStore() error = %v", err)
+	}
+	archive, err := NewBadgerArchiveStore(db)
+	if err != nil {
+		t.Fatalf("NewBadgerArchiveStore() error = %v", err)
+	}
+	orchestrator := NewSagaOrchestrator(WithSagaStore(sagaStore))
+
+	ctx := context.Background()
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	for id, state := range map[string]SagaState{"old": SagaStateCompensated, "recent": SagaStateCompleted, "running": SagaStateRunning} {
+		instance := NewSagaInstance(id, &SagaDefinition{Name: "archive"})
+		_ = instance.TransitionTo(SagaStateRunning)
+		if state == SagaStateCompensated {
+			_ = instance.TransitionTo(SagaStateCompensating)
+		}
+		_ = instance.TransitionTo(state)
+		if id == "old" {
+			instance.CompletedAt = &old
+		}
+		if err := orchestrator.RestoreInstance(ctx, instance); err != nil {
+			t.Fatalf("RestoreInstance() error = %v", err)
+		}
+		_, _ = wal.Append(ctx, WALEntry{SagaID: id, StepID: "a", Type: WALEntryTypeStepCompleted, Timestamp: old})
+		_, _ = wal.Append(ctx, WALEntry{SagaID: id, StepID: "a", Type: WALEntryTypeCompensationCompleted, Timestamp: old})
+		_ = checkpointStore.Save(ctx, &Checkpoint{SagaID: id, State: state, LastUpdated: old})
+	}
+
+	cleaner := NewCleanupManager(wal, checkpointStore, nil, &testRecoveryLogger{}).WithArchive(archive, orchestrator)
+	deleted, err := cleaner.RunOnce(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected the two wal entries of the archived saga deleted, got %d", deleted)
+	}
+
+	archived, err := archive.Get(ctx, "old")
+	if err != nil {
+		t.Fatalf("archive.Get() error = %v", err)
+	}
+	if archived.Instance.State != SagaStateCompensated || len(archived.WAL) != 2 || archived.WAL[1].Type != WALEntryTypeCompensationCompleted {
+		t.Fatalf("archived saga = %+v, want the instance and its wal", archived)
+	}
+	if entries, _ := wal.List(ctx, "old"); len(entries) != 0 {
+		t.Fatalf("expected archived saga wal pruned, got %d entries", len(entries))
+	}
+	if _, err := checkpointStore.Load(ctx, "old"); err == nil {
+		t.Fatal("expected archived saga checkpoint to be cleaned")
+	}
+	if _, err := orchestrator.GetInstance("old"); !errors.Is(err, ErrSagaNotFound) {
+		t.Fatalf("GetInstance(old) error = %v, want ErrSagaNotFound", err)
+	}
+
+	// Sagas terminal within the retention keep even their old wal entries.
+	for _, id := range []string{"recent", "running"} {
+		if entries, _ := wal.List(ctx, id); len(entries) != 2 {
+			t.Fatalf("expected saga %s wal untouched, got %d entries", id, len(entries))
+		}
+		if _, err := archive.Get(ctx, id); !errors.Is(err, ErrArchiveNotFound) {
+			t.Fatalf("archive.Get(%s) error = %v, want ErrArchiveNotFound", id, err)
+		}
+	}
+	if list, total, err := archive.List(ctx, SagaListFilter{State: "compensated"}); err != nil || total != 1 || list[0].Instance.ID != "old" {
+		t.Fatalf("archive.List() = %v, %d, %v", list, total, err)
+	}
+}
+
 func TestCleanupManagerStartBackground(t *testing.T) {
 	db := openTestBadger(t)
 	t.Cleanup(func() { _ = db.Close() })
@@ -18,6 +18,20 @@ const (
 	SkipCompensate
 )
 
+// String returns the name of the policy as saga submissions spell it.
+func (p CompensationPolicy) String() string {
+	switch p {
+	case AutoCompensate:
+		return "auto"
+	case ManualCompensate:
+		return "manual"
+	case SkipCompensate:
+		return "skip"
+	default:
+		return "unknown"
+	}
+}
+
 // CompensationRetryConfig controls retry behavior for compensation execution.
 type CompensationRetryConfig struct {
 	MaxRetries     int
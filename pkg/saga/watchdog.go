@@ -0,0 +1,178 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Actions the watchdog takes on stuck sagas.
+const (
+	// StuckActionEscalated reports a running saga moved to
+	// pending-compensation.
+	StuckActionEscalated = "escalated"
+	// StuckActionAlerted reports a stuck saga the watchdog can only report:
+	// a compensating saga, or a running saga another process executes.
+	StuckActionAlerted = "alerted"
+)
+
+// stuckRecorder is implemented by metrics recorders that count stuck sagas.
+// It is optional so that MetricsRecorder stays unchanged.
+type stuckRecorder interface {
+	RecordSagaStuck(state, action string)
+}
+
+// WatchdogConfig sets how long sagas may go without progress. A zero timeout
+// leaves sagas in that state alone.
+type WatchdogConfig struct {
+	// Interval is how often the watchdog looks for stuck sagas.
+	Interval time.Duration
+	// RunningTimeout is how long a running saga may go without completing a
+	// step before it is escalated to pending-compensation.
+	RunningTimeout time.Duration
+	// CompensatingTimeout is how long a compensating saga may go without
+	// compensating a step before it is reported.
+	CompensatingTimeout time.Duration
+}
+
+// StuckSaga is a saga the watchdog found without progress.
+type StuckSaga struct {
+	SagaID string
+	State  SagaState
+	// Since is when the saga last made progress.
+	Since  time.Time
+	Action string
+}
+
+// Watchdog finds sagas stuck in running or compensating, so that a step
+// whose external system never responds does not hang them silently. Running
+// sagas this process executes are escalated to pending-compensation; other
+// stuck sagas are reported to the logger and metrics for operators.
+type Watchdog struct {
+	orchestrator *SagaOrchestrator
+	cfg          WatchdogConfig
+	logger       RecoveryLogger
+
+	mu      sync.Mutex
+	running bool
+	// reported holds the progress time of the stuck sagas already reported,
+	// so that each is reported once until it progresses again.
+	reported map[string]time.Time
+}
+
+// NewWatchdog creates a watchdog over the sagas of orchestrator.
+func NewWatchdog(orchestrator *SagaOrchestrator, cfg WatchdogConfig, logger RecoveryLogger) *Watchdog {
+	if logger == nil {
+		logger = &nopRecoveryLogger{}
+	}
+	return &Watchdog{
+		orchestrator: orchestrator,
+		cfg:          cfg,
+		logger:       logger,
+		reported:     make(map[string]time.Time),
+	}
+}
+
+// Start runs periodic checks until the context is cancelled. It does nothing
+// when both timeouts are zero.
+func (w *Watchdog) Start(ctx context.Context) error {
+	if w.cfg.RunningTimeout <= 0 && w.cfg.CompensatingTimeout <= 0 {
+		return nil
+	}
+	if w.cfg.Interval <= 0 {
+		return fmt.Errorf("watchdog interval must be > 0")
+	}
+
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return fmt.Errorf("saga watchdog already running")
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				w.mu.Lock()
+				w.running = false
+				w.mu.Unlock()
+				return
+			case <-ticker.C:
+				if _, err := w.RunOnce(ctx); err != nil {
+					w.logger.Warn("saga watchdog check failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// RunOnce performs one check and returns the stuck sagas it acted on.
+func (w *Watchdog) RunOnce(ctx context.Context) ([]StuckSaga, error) {
+	now := time.Now()
+	var stuck []StuckSaga
+	seen := make(map[string]struct{})
+	for state, timeout := range map[SagaState]time.Duration{
+		SagaStateRunning:      w.cfg.RunningTimeout,
+		SagaStateCompensating: w.cfg.CompensatingTimeout,
+	} {
+		if timeout <= 0 {
+			continue
+		}
+		instances, _, err := w.orchestrator.ListInstancesFiltered(ctx, SagaListFilter{State: state.String()})
+		if err != nil {
+			return stuck, err
+		}
+		for _, instance := range instances {
+			seen[instance.ID] = struct{}{}
+			if now.Sub(instance.UpdatedAt) < timeout {
+				continue
+			}
+			if found, ok := w.check(instance); ok {
+				stuck = append(stuck, found)
+			}
+		}
+	}
+
+	w.mu.Lock()
+	for id := range w.reported {
+		if _, ok := seen[id]; !ok {
+			delete(w.reported, id)
+		}
+	}
+	w.mu.Unlock()
+	return stuck, nil
+}
+
+// check acts on instance, which made no progress within its timeout, unless
+// it was reported already. It reports whether it acted.
+func (w *Watchdog) check(instance *SagaInstance) (StuckSaga, bool) {
+	w.mu.Lock()
+	if since, ok := w.reported[instance.ID]; ok && since.Equal(instance.UpdatedAt) {
+		w.mu.Unlock()
+		return StuckSaga{}, false
+	}
+	w.reported[instance.ID] = instance.UpdatedAt
+	w.mu.Unlock()
+
+	stuck := StuckSaga{SagaID: instance.ID, State: instance.State, Since: instance.UpdatedAt, Action: StuckActionAlerted}
+	if instance.State == SagaStateRunning && w.orchestrator.Escalate(instance.ID) {
+		stuck.Action = StuckActionEscalated
+	}
+	w.logger.Warn("saga stuck",
+		"saga_id", stuck.SagaID,
+		"state", stuck.State.String(),
+		"since", stuck.Since,
+		"action", stuck.Action,
+	)
+	if recorder, ok := w.orchestrator.metrics.(stuckRecorder); ok {
+		recorder.RecordSagaStuck(stuck.State.String(), stuck.Action)
+	}
+	return stuck, true
+}
@@ -0,0 +1,148 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stuckSagaMetrics struct {
+	*captureSagaMetrics
+	mu    sync.Mutex
+	stuck map[string]int
+}
+
+func (m *stuckSagaMetrics) RecordSagaStuck(state, action string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stuck[state+"/"+action]++
+}
+
+func TestWatchdogEscalatesStuckRunningSaga(t *testing.T) {
+	var mu sync.Mutex
+	var compensated []string
+	hang := make(chan struct{})
+	defer close(hang)
+
+	def, err := New("stuck").
+		Step("a",
+			Action(func(context.Context, *StepContext) (any, error) { return "a", nil }),
+			Compensate(func(context.Context, *CompensationContext) error {
+				mu.Lock()
+				defer mu.Unlock()
+				compensated = append(compensated, "a")
+				return nil
+			}),
+		).
+		Step("b",
+			// The external system never answers, and the action ignores
+			// its context.
+			Action(func(context.Context, *StepContext) (any, error) { <-hang; return nil, nil }),
+			DependsOn("a"),
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	metrics := &stuckSagaMetrics{captureSagaMetrics: newCaptureSagaMetrics(), stuck: make(map[string]int)}
+	orchestrator := NewSagaOrchestrator(WithMetrics(metrics))
+	watchdog := NewWatchdog(orchestrator, WatchdogConfig{Interval: time.Hour, RunningTimeout: 20 * time.Millisecond}, nil)
+
+	type outcome struct {
+		instance *SagaInstance
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		instance, err := orchestrator.ExecuteWithID(context.Background(), "saga-stuck", def, nil)
+		done <- outcome{instance, err}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	var stuck []StuckSaga
+	for len(stuck) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if stuck, err = watchdog.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+	}
+	if len(stuck) != 1 || stuck[0].SagaID != "saga-stuck" || stuck[0].Action != StuckActionEscalated {
+		t.Fatalf("RunOnce() = %+v, want saga-stuck escalated", stuck)
+	}
+
+	var got outcome
+	select {
+	case got = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("escalated saga did not return")
+	}
+	if !errors.Is(got.err, ErrSagaStuck) || got.instance.State != SagaStatePendingCompensation {
+		t.Fatalf("ExecuteWithID() = %s, %v, want pending-compensation with ErrSagaStuck", got.instance.State, got.err)
+	}
+	if len(compensated) != 0 {
+		t.Fatalf("compensated %v before an operator triggered it", compensated)
+	}
+	if metrics.stuck["running/escalated"] != 1 {
+		t.Fatalf("stuck metrics = %v", metrics.stuck)
+	}
+
+	if _, err := orchestrator.TriggerCompensation(context.Background(), "saga-stuck", def, nil, ErrSagaStuck); err != nil {
+		t.Fatalf("TriggerCompensation() error = %v", err)
+	}
+	if len(compensated) != 1 {
+		t.Fatalf("compensated %v, want a", compensated)
+	}
+	if stuck, _ := watchdog.RunOnce(context.Background()); len(stuck) != 0 {
+		t.Fatalf("RunOnce() after compensation = %+v, want none", stuck)
+	}
+}
+
+func TestWatchdogAlertsOnce(t *testing.T) {
+	def, err := New("stuck").
+		Step("a", Action(func(context.Context, *StepContext) (any, error) { return nil, nil })).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	orchestrator := NewSagaOrchestrator()
+	for id, state := range map[string]SagaState{"compensating": SagaStateCompensating, "elsewhere": SagaStateRunning} {
+		instance := NewSagaInstance(id, def)
+		_ = instance.TransitionTo(SagaStateRunning)
+		_ = instance.TransitionTo(state)
+		instance.UpdatedAt = time.Now().Add(-time.Hour)
+		if err := orchestrator.RestoreInstance(context.Background(), instance); err != nil {
+			t.Fatalf("RestoreInstance() error = %v", err)
+		}
+	}
+
+	watchdog := NewWatchdog(orchestrator, WatchdogConfig{
+		Interval:            time.Hour,
+		RunningTimeout:      time.Minute,
+		CompensatingTimeout: time.Minute,
+	}, nil)
+	stuck, err := watchdog.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(stuck) != 2 {
+		t.Fatalf("RunOnce() = %+v, want both sagas", stuck)
+	}
+	for _, s := range stuck {
+		if s.Action != StuckActionAlerted {
+			t.Fatalf("saga %s action = %s, want alerted: it is not run by this orchestrator or compensating", s.SagaID, s.Action)
+		}
+	}
+	if stuck, _ := watchdog.RunOnce(context.Background()); len(stuck) != 0 {
+		t.Fatalf("second RunOnce() = %+v, want the sagas reported once", stuck)
+	}
+
+	if err := NewWatchdog(orchestrator, WatchdogConfig{}, nil).Start(context.Background()); err != nil {
+		t.Fatalf("Start() without timeouts error = %v", err)
+	}
+	if err := NewWatchdog(orchestrator, WatchdogConfig{RunningTimeout: time.Minute}, nil).Start(context.Background()); err == nil {
+		t.Fatal("expected an error without an interval")
+	}
+}
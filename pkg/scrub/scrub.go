@@ -0,0 +1,216 @@
+// Package scrub removes customer data from event payloads, webhook bodies
+// and log records before they leave the process, following configured
+// rules.
+package scrub
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultReplacement replaces scrubbed data unless a rule sets its own.
+const DefaultReplacement = "[REDACTED]"
+
+// Rule is a scrubbing rule.
+type Rule struct {
+	// Name identifies the rule in errors.
+	Name string
+
+	// Pattern is a regular expression whose matches in strings are
+	// replaced.
+	Pattern string
+
+	// Fields limits the rule to the values at these field paths: dot
+	// separated keys, where "*" matches any key. Without a Pattern the
+	// values are replaced whole.
+	Fields []string
+
+	// Replacement replaces the matches, and may refer to submatches as
+	// $1. Empty uses DefaultReplacement.
+	Replacement string
+}
+
+// rule is a compiled Rule.
+type rule struct {
+	re          *regexp.Regexp
+	fields      [][]string
+	replacement string
+}
+
+// Scrubber applies scrubbing rules. A nil Scrubber leaves values unchanged.
+type Scrubber struct {
+	rules []rule
+}
+
+// New compiles rules into a Scrubber. It returns nil if there are none.
+func New(rules []Rule) (*Scrubber, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	s := &Scrubber{rules: make([]rule, 0, len(rules))}
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if r.Pattern == "" && len(r.Fields) == 0 {
+			return nil, fmt.Errorf("scrub rule %s: pattern or fields is required", name)
+		}
+		compiled := rule{replacement: r.Replacement}
+		if compiled.replacement == "" {
+			compiled.replacement = DefaultReplacement
+		}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("scrub rule %s: %w", name, err)
+			}
+			compiled.re = re
+		}
+		for _, field := range r.Fields {
+			if field == "" {
+				return nil, fmt.Errorf("scrub rule %s: empty field path", name)
+			}
+			compiled.fields = append(compiled.fields, strings.Split(field, "."))
+		}
+		s.rules = append(s.rules, compiled)
+	}
+	return s, nil
+}
+
+// String scrubs text that is not at a field, such as a log message, with
+// the rules that apply to all fields.
+func (s *Scrubber) String(text string) string {
+	if s == nil {
+		return text
+	}
+	return s.scrubString(nil, text)
+}
+
+// Value returns v scrubbed, v being the value at the field path. Maps and
+// slices are copied rather than changed; structs and other composite values
+// are scrubbed in their JSON form.
+func (s *Scrubber) Value(path []string, v any) any {
+	if s == nil {
+		return v
+	}
+	return s.value(path, v)
+}
+
+// JSON scrubs a JSON document, returning it unchanged if it is invalid.
+func (s *Scrubber) JSON(data []byte) []byte {
+	if s == nil {
+		return data
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return data
+	}
+	out, err := json.Marshal(s.value(nil, doc))
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func (s *Scrubber) value(path []string, v any) any {
+	if replacement, ok := s.replaced(path); ok {
+		return replacement
+	}
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, json.Number, time.Time, time.Duration:
+		return v
+	case string:
+		return s.scrubString(path, v)
+	case error:
+		return s.scrubString(path, v.Error())
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = s.value(append(path[:len(path):len(path)], key), item)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for key, item := range v {
+			scrubbed := s.value(append(path[:len(path):len(path)], key), item)
+			out[key], _ = scrubbed.(string)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = s.value(path, item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = s.scrubString(path, item)
+		}
+		return out
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return v
+		}
+		return s.value(path, doc)
+	}
+	return v
+}
+
+// replaced returns the replacement of the value at path if a rule without a
+// pattern names it.
+func (s *Scrubber) replaced(path []string) (string, bool) {
+	if len(path) == 0 {
+		return "", false
+	}
+	for _, r := range s.rules {
+		if r.re == nil && r.matches(path) {
+			return r.replacement, true
+		}
+	}
+	return "", false
+}
+
+// scrubString replaces the matches of the pattern rules applying at path.
+func (s *Scrubber) scrubString(path []string, text string) string {
+	for _, r := range s.rules {
+		if r.re == nil || (len(r.fields) > 0 && !r.matches(path)) {
+			continue
+		}
+		text = r.re.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
+
+// matches reports whether path is one of the rule's fields.
+func (r rule) matches(path []string) bool {
+	for _, field := range r.fields {
+		if len(field) != len(path) {
+			continue
+		}
+		matched := true
+		for i, key := range field {
+			if key != "*" && key != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
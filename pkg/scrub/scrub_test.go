@@ -0,0 +1,89 @@
+package scrub
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestScrubber(t *testing.T) *Scrubber {
+	t.Helper()
+	s, err := New([]Rule{
+		{Name: "email", Pattern: `[a-z0-9.]+@[a-z0-9.]+\.[a-z]{2,}`},
+		{Name: "card", Pattern: `\b(\d{4})\d{8}(\d{4})\b`, Replacement: "$1********$2", Fields: []string{"result.*.card"}},
+		{Name: "customer", Fields: []string{"metadata.customer", "result.customer"}},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return s
+}
+
+func TestScrubber_Value(t *testing.T) {
+	s := newTestScrubber(t)
+	payload := map[string]any{
+		"workflow_id": "wf-1",
+		"error":       errors.New("mail to jane.doe@example.com bounced"),
+		"metadata":    map[string]string{"customer": "Jane Doe", "team": "billing"},
+		"result": map[string]any{
+			"customer": map[string]any{"id": 7},
+			"payment":  map[string]any{"card": "4242424242424242", "note": "4242424242424242"},
+			"emails":   []any{"ops@example.com"},
+		},
+	}
+
+	got := s.Value(nil, payload).(map[string]any)
+	want := map[string]any{
+		"workflow_id": "wf-1",
+		"error":       "mail to [REDACTED] bounced",
+		"metadata":    map[string]string{"customer": "[REDACTED]", "team": "billing"},
+		"result": map[string]any{
+			"customer": "[REDACTED]",
+			"payment":  map[string]any{"card": "4242********4242", "note": "4242424242424242"},
+			"emails":   []any{"[REDACTED]"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Value() = %#v, want %#v", got, want)
+	}
+	if payload["metadata"].(map[string]string)["customer"] != "Jane Doe" {
+		t.Fatal("Value() changed its argument")
+	}
+}
+
+func TestScrubber_StructsAndJSON(t *testing.T) {
+	s := newTestScrubber(t)
+	type receipt struct {
+		Customer string `json:"customer"`
+		Contact  string `json:"contact"`
+	}
+	got := s.Value([]string{"result"}, receipt{Customer: "Jane", Contact: "jane@example.com"})
+	if want := map[string]any{"customer": "[REDACTED]", "contact": "[REDACTED]"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Value() of a struct = %#v, want %#v", got, want)
+	}
+
+	body := s.JSON([]byte(`{"type":"task.state_changed","payload":{"error":"bob@example.com"}}`))
+	if strings.Contains(string(body), "bob@example.com") {
+		t.Fatalf("JSON() = %s", body)
+	}
+	if invalid := []byte("not json bob@example.com"); string(s.JSON(invalid)) != string(invalid) {
+		t.Fatal("JSON() changed an invalid document")
+	}
+}
+
+func TestNew_RejectsInvalidRules(t *testing.T) {
+	if _, err := New([]Rule{{Name: "empty"}}); err == nil {
+		t.Fatal("New() accepted a rule without pattern or fields")
+	}
+	if _, err := New([]Rule{{Name: "bad", Pattern: "("}}); err == nil {
+		t.Fatal("New() accepted an invalid pattern")
+	}
+	s, err := New(nil)
+	if err != nil || s != nil {
+		t.Fatalf("New(nil) = %v, %v", s, err)
+	}
+	if got := s.String("jane@example.com"); got != "jane@example.com" {
+		t.Fatalf("nil Scrubber changed %q", got)
+	}
+}
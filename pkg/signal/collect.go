@@ -78,14 +78,8 @@ func (c *Collector) Collect(ctx context.Context) (map[string]*CollectPayload, er
 					if !ok {
 						continue
 					}
-					if sig.Type == SignalCollect {
-						payload, err := ParseCollectPayload(sig)
-						if err == nil {
-							c.mu.Lock()
-							c.results[taskID] = payload
-							c.mu.Unlock()
-							remaining--
-						}
+					if _, ok := c.accept(ctx, taskID, sig); ok {
+						remaining--
 					}
 				case <-ctx.Done():
 					status := "failed"
@@ -157,15 +151,9 @@ func (c *Collector) StreamCollect(ctx context.Context) (<-chan CollectResult, er
 					if !ok {
 						continue
 					}
-					if sig.Type == SignalCollect {
-						payload, err := ParseCollectPayload(sig)
-						if err == nil {
-							c.mu.Lock()
-							c.results[taskID] = payload
-							c.mu.Unlock()
-							remaining--
-							out <- CollectResult{TaskID: taskID, Payload: payload}
-						}
+					if payload, ok := c.accept(ctx, taskID, sig); ok {
+						remaining--
+						out <- CollectResult{TaskID: taskID, Payload: payload}
 					}
 				case <-ctx.Done():
 					return
@@ -177,6 +165,28 @@ func (c *Collector) StreamCollect(ctx context.Context) (<-chan CollectResult, er
 	return out, nil
 }
 
+// accept records the result sig carries for taskID and acknowledges sig, so
+// that a bus delivering at least once does not redeliver it. It reports
+// false for signals that carry no result.
+func (c *Collector) accept(ctx context.Context, taskID string, sig *Signal) (*CollectPayload, bool) {
+	if sig.Type != SignalCollect {
+		return nil, false
+	}
+	payload, err := ParseCollectPayload(sig)
+	if err != nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	c.results[taskID] = payload
+	c.mu.Unlock()
+	// A failed ack leaves the signal to be redelivered, which the
+	// collection no longer reads.
+	if err := sig.Ack(ctx); err != nil {
+		metricsRecorder().RecordSignalFailed("collect", string(sig.Type), "ack_failed")
+	}
+	return payload, true
+}
+
 // CollectResult represents a single task's collected result.
 type CollectResult struct {
 	TaskID  string
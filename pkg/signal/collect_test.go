@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -141,6 +142,73 @@ func TestCollectorCollect_SubscribeFailure(t *testing.T) {
 	}
 }
 
+func TestCollectorCollect_AcksAtLeastOnceSignals(t *testing.T) {
+	client := requireRedisBusClient(t)
+	prefix := fmt.Sprintf("goclaw:test:signal:collect:%d:", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_ = client.Del(context.Background(), prefix+"collect:task-1", prefix+"collect:task-2").Err()
+	})
+
+	bus := NewRedisBus(client, prefix, 16, WithAckDelivery(time.Minute, 3))
+	defer bus.Close()
+
+	collector := NewCollector(bus, []string{"task-1", "task-2"}, 2*time.Second)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = SendCollectResult(context.Background(), bus, "task-1", json.RawMessage(`{"v":1}`), "")
+		_ = SendCollectResult(context.Background(), bus, "task-2", json.RawMessage(`{"v":2}`), "")
+	}()
+	results, err := collector.Collect(context.Background())
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Collect() = %+v, %v, want both results", results, err)
+	}
+
+	for _, taskID := range []string{"collect:task-1", "collect:task-2"} {
+		if pending, err := bus.Pending(context.Background(), taskID); err != nil || len(pending) != 0 {
+			t.Fatalf("Pending(%s) = %+v, %v, want the collected signal acknowledged", taskID, pending, err)
+		}
+	}
+	if stats := bus.DeliveryStats(); stats.Acked != 2 {
+		t.Fatalf("DeliveryStats() = %+v, want 2 acked", stats)
+	}
+}
+
+func TestCollectorStreamCollect_AcksAcceptedSignals(t *testing.T) {
+	bus := newStubCollectBus()
+	collector := NewCollector(bus, []string{"task-1"}, time.Second)
+	stream, err := collector.StreamCollect(context.Background())
+	if err != nil {
+		t.Fatalf("StreamCollect failed: %v", err)
+	}
+
+	acked := make(chan string, 2)
+	ackFor := func(id string) func(context.Context) error {
+		return func(context.Context) error {
+			acked <- id
+			return nil
+		}
+	}
+	payload, _ := json.Marshal(CollectPayload{Result: json.RawMessage(`{"v":1}`)})
+	ch := bus.mustGet("collect:task-1")
+	// Signals that carry no result are not acknowledged.
+	ch <- &Signal{Type: SignalSteer, TaskID: "collect:task-1", ack: ackFor("steer")}
+	ch <- &Signal{Type: SignalCollect, TaskID: "collect:task-1", Payload: payload, ack: ackFor("collect")}
+
+	for range stream {
+	}
+	select {
+	case id := <-acked:
+		if id != "collect" {
+			t.Fatalf("acknowledged %s signal, want the collect signal", id)
+		}
+	default:
+		t.Fatal("collected signal was not acknowledged")
+	}
+	if len(acked) != 0 {
+		t.Fatalf("acknowledged %d more signals", len(acked))
+	}
+}
+
 type stubCollectBus struct {
 	subs map[string]chan *Signal
 }
@@ -0,0 +1,111 @@
+package signal
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+var (
+	// ErrAckDisabled is returned by Ack when the bus delivers at most once.
+	ErrAckDisabled = errs.New(errs.NotImplemented, "signal: acknowledged delivery is not enabled")
+
+	// ErrNotPending is returned by Ack when the delivery is unknown or already acknowledged.
+	ErrNotPending = errs.New(errs.NotFound, "signal: delivery is not pending")
+)
+
+// AckBus is a Bus that supports acknowledged, at-least-once delivery.
+//
+// Signals delivered by an AckBus carry a DeliveryID and stay pending until
+// they are acknowledged. Pending signals that are not acknowledged within the
+// bus's ack timeout are redelivered.
+type AckBus interface {
+	Bus
+
+	// Ack acknowledges a delivered signal so it is not redelivered.
+	Ack(ctx context.Context, taskID, deliveryID string) error
+
+	// Pending lists the delivered but unacknowledged signals of a task.
+	Pending(ctx context.Context, taskID string) ([]PendingSignal, error)
+
+	// DeliveryStats returns the bus's delivery counters.
+	DeliveryStats() DeliveryStats
+}
+
+// AsAckBus returns the first bus in the chain of wrapped buses starting at bus
+// that supports acknowledged delivery. Wrappers expose the bus they wrap with
+// an Unwrap() Bus method.
+func AsAckBus(bus Bus) (AckBus, bool) {
+	for bus != nil {
+		if ackBus, ok := bus.(AckBus); ok {
+			return ackBus, true
+		}
+		wrapper, ok := bus.(interface{ Unwrap() Bus })
+		if !ok {
+			break
+		}
+		bus = wrapper.Unwrap()
+	}
+	return nil, false
+}
+
+// PendingSignal describes a delivered signal awaiting acknowledgement.
+type PendingSignal struct {
+	// DeliveryID identifies the delivery.
+	DeliveryID string `json:"delivery_id"`
+
+	// TaskID is the target task identifier.
+	TaskID string `json:"task_id"`
+
+	// Consumer is the bus instance the signal was last delivered to.
+	Consumer string `json:"consumer"`
+
+	// Idle is the time since the last delivery.
+	Idle time.Duration `json:"idle"`
+
+	// Deliveries is the number of times the signal has been delivered.
+	Deliveries int64 `json:"deliveries"`
+}
+
+// DeliveryStats holds cumulative delivery counters for a bus.
+type DeliveryStats struct {
+	// AckEnabled reports whether the bus delivers at least once.
+	AckEnabled bool `json:"ack_enabled"`
+
+	Published    uint64 `json:"published"`
+	Delivered    uint64 `json:"delivered"`
+	Acked        uint64 `json:"acked"`
+	Redelivered  uint64 `json:"redelivered"`
+	DeadLettered uint64 `json:"dead_lettered"`
+}
+
+// deliveryCounters is the lock-free backing store for DeliveryStats.
+type deliveryCounters struct {
+	published    atomic.Uint64
+	delivered    atomic.Uint64
+	acked        atomic.Uint64
+	redelivered  atomic.Uint64
+	deadLettered atomic.Uint64
+}
+
+func (c *deliveryCounters) snapshot(ackEnabled bool) DeliveryStats {
+	return DeliveryStats{
+		AckEnabled:   ackEnabled,
+		Published:    c.published.Load(),
+		Delivered:    c.delivered.Load(),
+		Acked:        c.acked.Load(),
+		Redelivered:  c.redelivered.Load(),
+		DeadLettered: c.deadLettered.Load(),
+	}
+}
+
+// Ack acknowledges the signal with the bus that delivered it. It is a no-op
+// for signals delivered at most once.
+func (s *Signal) Ack(ctx context.Context) error {
+	if s == nil || s.ack == nil {
+		return nil
+	}
+	return s.ack(ctx)
+}
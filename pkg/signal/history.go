@@ -0,0 +1,284 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultHistorySize is the number of signals kept per channel.
+	DefaultHistorySize = 100
+
+	// DefaultHistoryChannels is the number of channels MemoryHistory tracks.
+	DefaultHistoryChannels = 1000
+)
+
+// History keeps the most recent signals published to each channel (task ID)
+// so they can be inspected and replayed.
+type History interface {
+	// Append records a published signal.
+	Append(ctx context.Context, sig *Signal) error
+
+	// List returns up to limit of the newest signals sent to channel at or
+	// after since, oldest first. A zero since or limit disables that bound.
+	List(ctx context.Context, channel string, since time.Time, limit int) ([]*Signal, error)
+}
+
+// HistoryBus is a Bus that records every successfully published signal.
+type HistoryBus struct {
+	Bus
+	history History
+}
+
+// NewHistoryBus wraps bus so published signals are recorded in history.
+func NewHistoryBus(bus Bus, history History) *HistoryBus {
+	return &HistoryBus{Bus: bus, history: history}
+}
+
+// History returns the history signals are recorded in.
+func (b *HistoryBus) History() History {
+	return b.history
+}
+
+// Unwrap returns the wrapped bus.
+func (b *HistoryBus) Unwrap() Bus {
+	return b.Bus
+}
+
+// Publish publishes the signal and records it. Failing to record does not
+// fail the publish.
+func (b *HistoryBus) Publish(ctx context.Context, sig *Signal) error {
+	if err := b.Bus.Publish(ctx, sig); err != nil {
+		return err
+	}
+	if err := b.history.Append(ctx, sig); err != nil {
+		metricsRecorder().RecordSignalFailed("history", string(sig.Type), "history_append_failed")
+	}
+	return nil
+}
+
+// Ack delegates to the wrapped bus if it supports acknowledged delivery.
+func (b *HistoryBus) Ack(ctx context.Context, taskID, deliveryID string) error {
+	if ackBus, ok := AsAckBus(b.Bus); ok {
+		return ackBus.Ack(ctx, taskID, deliveryID)
+	}
+	return ErrAckDisabled
+}
+
+// Pending delegates to the wrapped bus if it supports acknowledged delivery.
+func (b *HistoryBus) Pending(ctx context.Context, taskID string) ([]PendingSignal, error) {
+	if ackBus, ok := AsAckBus(b.Bus); ok {
+		return ackBus.Pending(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// DeliveryStats delegates to the wrapped bus if it supports acknowledged delivery.
+func (b *HistoryBus) DeliveryStats() DeliveryStats {
+	if ackBus, ok := AsAckBus(b.Bus); ok {
+		return ackBus.DeliveryStats()
+	}
+	return DeliveryStats{}
+}
+
+// HistoryOf returns the history of the first HistoryBus in the chain of
+// wrapped buses starting at bus, or nil if signals are not recorded.
+func HistoryOf(bus Bus) History {
+	for bus != nil {
+		if hb, ok := bus.(*HistoryBus); ok {
+			return hb.History()
+		}
+		wrapper, ok := bus.(interface{ Unwrap() Bus })
+		if !ok {
+			break
+		}
+		bus = wrapper.Unwrap()
+	}
+	return nil
+}
+
+// MemoryHistory is an in-process History with a ring buffer per channel.
+// When more than maxChannels channels are tracked the least recently
+// written one is dropped.
+type MemoryHistory struct {
+	size        int
+	maxChannels int
+
+	mu       sync.Mutex
+	channels map[string]*historyRing
+}
+
+type historyRing struct {
+	entries []*Signal
+	next    int
+	full    bool
+	updated time.Time
+}
+
+// NewMemoryHistory creates an in-process history keeping size signals for up
+// to maxChannels channels.
+func NewMemoryHistory(size, maxChannels int) *MemoryHistory {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	if maxChannels <= 0 {
+		maxChannels = DefaultHistoryChannels
+	}
+	return &MemoryHistory{
+		size:        size,
+		maxChannels: maxChannels,
+		channels:    make(map[string]*historyRing),
+	}
+}
+
+// Append records a signal in its channel's ring buffer.
+func (h *MemoryHistory) Append(_ context.Context, sig *Signal) error {
+	if sig == nil || sig.TaskID == "" {
+		return fmt.Errorf("signal task_id cannot be empty")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ring, ok := h.channels[sig.TaskID]
+	if !ok {
+		if len(h.channels) >= h.maxChannels {
+			h.evictOldest()
+		}
+		ring = &historyRing{entries: make([]*Signal, h.size)}
+		h.channels[sig.TaskID] = ring
+	}
+
+	ring.entries[ring.next] = historyCopy(sig)
+	ring.next = (ring.next + 1) % h.size
+	if ring.next == 0 {
+		ring.full = true
+	}
+	ring.updated = time.Now()
+	return nil
+}
+
+// List returns the recorded signals of a channel, oldest first.
+func (h *MemoryHistory) List(_ context.Context, channel string, since time.Time, limit int) ([]*Signal, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ring, ok := h.channels[channel]
+	if !ok {
+		return []*Signal{}, nil
+	}
+
+	ordered := ring.entries[:ring.next]
+	if ring.full {
+		ordered = append(append([]*Signal{}, ring.entries[ring.next:]...), ring.entries[:ring.next]...)
+	}
+	return filterHistory(ordered, since, limit), nil
+}
+
+func (h *MemoryHistory) evictOldest() {
+	var (
+		oldest  string
+		updated time.Time
+	)
+	for channel, ring := range h.channels {
+		if oldest == "" || ring.updated.Before(updated) {
+			oldest, updated = channel, ring.updated
+		}
+	}
+	delete(h.channels, oldest)
+}
+
+// RedisHistory is a History stored in one capped Redis list per channel, so
+// it is shared by every instance using the same Redis.
+type RedisHistory struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	size      int
+	ttl       time.Duration
+}
+
+// NewRedisHistory creates a Redis-backed history keeping size signals per
+// channel under keyPrefix+channel. Channels not written for ttl expire.
+func NewRedisHistory(client redis.UniversalClient, keyPrefix string, size int, ttl time.Duration) *RedisHistory {
+	if keyPrefix == "" {
+		keyPrefix = DefaultChannelPrefix + "history:"
+	}
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &RedisHistory{
+		client:    client,
+		keyPrefix: keyPrefix,
+		size:      size,
+		ttl:       ttl,
+	}
+}
+
+// Append pushes the signal onto the channel's list and trims it.
+func (h *RedisHistory) Append(ctx context.Context, sig *Signal) error {
+	if sig == nil || sig.TaskID == "" {
+		return fmt.Errorf("signal task_id cannot be empty")
+	}
+	data, err := json.Marshal(historyCopy(sig))
+	if err != nil {
+		return fmt.Errorf("failed to marshal signal: %w", err)
+	}
+
+	key := h.keyPrefix + sig.TaskID
+	_, err = h.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, data)
+		pipe.LTrim(ctx, key, int64(-h.size), -1)
+		if h.ttl > 0 {
+			pipe.Expire(ctx, key, h.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// List reads the channel's list, oldest first.
+func (h *RedisHistory) List(ctx context.Context, channel string, since time.Time, limit int) ([]*Signal, error) {
+	raw, err := h.client.LRange(ctx, h.keyPrefix+channel, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	signals := make([]*Signal, 0, len(raw))
+	for _, item := range raw {
+		var sig Signal
+		if err := json.Unmarshal([]byte(item), &sig); err != nil {
+			continue
+		}
+		signals = append(signals, &sig)
+	}
+	return filterHistory(signals, since, limit), nil
+}
+
+// historyCopy returns the signal without per-delivery state.
+func historyCopy(sig *Signal) *Signal {
+	return &Signal{
+		Type:    sig.Type,
+		TaskID:  sig.TaskID,
+		Payload: sig.Payload,
+		SentAt:  sig.SentAt,
+	}
+}
+
+func filterHistory(signals []*Signal, since time.Time, limit int) []*Signal {
+	out := make([]*Signal, 0, len(signals))
+	for _, sig := range signals {
+		if sig == nil || (!since.IsZero() && sig.SentAt.Before(since)) {
+			continue
+		}
+		out = append(out, sig)
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
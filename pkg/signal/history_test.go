@@ -0,0 +1,125 @@
+package signal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func historySignal(taskID string, n int, sentAt time.Time) *Signal {
+	return &Signal{
+		Type:    SignalSteer,
+		TaskID:  taskID,
+		Payload: []byte(fmt.Sprintf(`{"n":%d}`, n)),
+		SentAt:  sentAt,
+	}
+}
+
+func TestMemoryHistory_RingBuffer(t *testing.T) {
+	h := NewMemoryHistory(3, 10)
+	ctx := context.Background()
+	base := time.Now()
+
+	for i := 0; i < 5; i++ {
+		if err := h.Append(ctx, historySignal("task-1", i, base.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	got, err := h.List(ctx, "task-1", time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 signals, got %d", len(got))
+	}
+	for i, sig := range got {
+		if want := fmt.Sprintf(`{"n":%d}`, i+2); string(sig.Payload) != want {
+			t.Fatalf("signal %d: expected payload %s, got %s", i, want, sig.Payload)
+		}
+	}
+
+	got, _ = h.List(ctx, "task-1", base.Add(3*time.Second), 0)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 signals since t+3s, got %d", len(got))
+	}
+	got, _ = h.List(ctx, "task-1", time.Time{}, 1)
+	if len(got) != 1 || string(got[0].Payload) != `{"n":4}` {
+		t.Fatalf("expected newest signal with limit 1, got %+v", got)
+	}
+
+	got, err = h.List(ctx, "unknown", time.Time{}, 0)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("expected empty list for unknown channel, got %v, %v", got, err)
+	}
+}
+
+func TestMemoryHistory_EvictsLeastRecentChannel(t *testing.T) {
+	h := NewMemoryHistory(2, 2)
+	ctx := context.Background()
+
+	_ = h.Append(ctx, historySignal("a", 1, time.Now()))
+	time.Sleep(time.Millisecond)
+	_ = h.Append(ctx, historySignal("b", 1, time.Now()))
+	time.Sleep(time.Millisecond)
+	_ = h.Append(ctx, historySignal("c", 1, time.Now()))
+
+	if got, _ := h.List(ctx, "a", time.Time{}, 0); len(got) != 0 {
+		t.Fatalf("expected channel a to be evicted, got %d signals", len(got))
+	}
+	for _, channel := range []string{"b", "c"} {
+		if got, _ := h.List(ctx, channel, time.Time{}, 0); len(got) != 1 {
+			t.Fatalf("expected channel %s to be kept, got %d signals", channel, len(got))
+		}
+	}
+}
+
+func TestHistoryBus_RecordsPublishedSignals(t *testing.T) {
+	history := NewMemoryHistory(10, 10)
+	bus := NewHistoryBus(NewLocalBus(4), history)
+	defer bus.Close()
+
+	if err := SendSteer(context.Background(), bus, "task-1", map[string]interface{}{"rate": 1}); err != nil {
+		t.Fatalf("SendSteer() error = %v", err)
+	}
+	if err := bus.Publish(context.Background(), &Signal{Type: SignalSteer}); err == nil {
+		t.Fatal("expected publish error for empty task id")
+	}
+
+	got, _ := bus.History().List(context.Background(), "task-1", time.Time{}, 0)
+	if len(got) != 1 || got[0].Type != SignalSteer {
+		t.Fatalf("expected one recorded steer signal, got %+v", got)
+	}
+
+	if err := bus.Ack(context.Background(), "task-1", "1-0"); !errors.Is(err, ErrAckDisabled) {
+		t.Fatalf("expected ErrAckDisabled from wrapped local bus, got %v", err)
+	}
+}
+
+func TestRedisHistory(t *testing.T) {
+	client := requireRedisBusClient(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("goclaw:test:signal:history:%d:", time.Now().UnixNano())
+	t.Cleanup(func() { _ = client.Del(ctx, prefix+"task-1").Err() })
+
+	h := NewRedisHistory(client, prefix, 2, time.Minute)
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := h.Append(ctx, historySignal("task-1", i, base.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	got, err := h.List(ctx, "task-1", time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 || string(got[0].Payload) != `{"n":1}` || string(got[1].Payload) != `{"n":2}` {
+		t.Fatalf("unexpected history: %+v", got)
+	}
+	if ttl := client.TTL(ctx, prefix+"task-1").Val(); ttl <= 0 {
+		t.Fatalf("expected history key to expire, got TTL %v", ttl)
+	}
+}
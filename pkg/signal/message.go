@@ -37,6 +37,15 @@ type Signal struct {
 
 	// SentAt is the timestamp when the signal was sent.
 	SentAt time.Time `json:"sent_at"`
+
+	// DeliveryID identifies the delivery on buses with acknowledged delivery.
+	DeliveryID string `json:"delivery_id,omitempty"`
+
+	// Attempt is the 1-based delivery attempt on buses with acknowledged delivery.
+	Attempt int `json:"attempt,omitempty"`
+
+	// ack acknowledges the delivery; nil for at-most-once delivery.
+	ack func(context.Context) error
 }
 
 // SteerPayload is the payload for a Steer signal.
@@ -10,6 +10,8 @@ type MetricsRecorder interface {
 	RecordSignalSent(mode string, signalType string)
 	RecordSignalReceived(mode string, signalType string)
 	RecordSignalFailed(mode string, signalType string, reason string)
+	RecordSignalAcked(mode string, signalType string)
+	RecordSignalRedelivered(mode string, signalType string)
 	RecordSignalPattern(pattern string, status string, duration time.Duration)
 }
 
@@ -18,6 +20,8 @@ type nopMetrics struct{}
 func (n *nopMetrics) RecordSignalSent(mode string, signalType string)                           {}
 func (n *nopMetrics) RecordSignalReceived(mode string, signalType string)                       {}
 func (n *nopMetrics) RecordSignalFailed(mode string, signalType string, reason string)          {}
+func (n *nopMetrics) RecordSignalAcked(mode string, signalType string)                          {}
+func (n *nopMetrics) RecordSignalRedelivered(mode string, signalType string)                    {}
 func (n *nopMetrics) RecordSignalPattern(pattern string, status string, duration time.Duration) {}
 
 var (
@@ -11,10 +11,12 @@ import (
 type testSignalMetrics struct {
 	mu sync.Mutex
 
-	sent     int
-	received int
-	failed   int
-	patterns map[string]int
+	sent        int
+	received    int
+	failed      int
+	acked       int
+	redelivered int
+	patterns    map[string]int
 }
 
 func newTestSignalMetrics() *testSignalMetrics {
@@ -39,6 +41,18 @@ func (m *testSignalMetrics) RecordSignalFailed(mode string, signalType string, r
 	m.failed++
 }
 
+func (m *testSignalMetrics) RecordSignalAcked(mode string, signalType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acked++
+}
+
+func (m *testSignalMetrics) RecordSignalRedelivered(mode string, signalType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.redelivered++
+}
+
 func (m *testSignalMetrics) RecordSignalPattern(pattern string, status string, duration time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
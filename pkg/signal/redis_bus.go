@@ -5,16 +5,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/goclaw/goclaw/pkg/rediskey"
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisBus is a Redis Pub/Sub-backed Signal Bus implementation.
+// RedisBus is a Redis-backed Signal Bus implementation.
+//
+// By default signals are sent over Pub/Sub and delivered at most once. With
+// WithAckDelivery each task gets a Redis stream read through a consumer group,
+// so signals stay pending until acknowledged and are redelivered on timeout.
 type RedisBus struct {
 	client        redis.UniversalClient
 	channelPrefix string
 	bufferSize    int
 
+	ackTimeout    time.Duration
+	maxDeliveries int
+	consumer      string
+	stats         deliveryCounters
+
 	mu          sync.RWMutex
 	subscribers map[string]*redisSubscription
 	closed      bool
@@ -24,22 +35,58 @@ type redisSubscription struct {
 	pubsub *redis.PubSub
 	ch     chan *Signal
 	cancel context.CancelFunc
+	// streaming subscriptions close ch from their consumer goroutine.
+	streaming bool
+}
+
+func (s *redisSubscription) stop() {
+	s.cancel()
+	if !s.streaming {
+		close(s.ch)
+	}
 }
 
+// RedisBusOption configures a RedisBus.
+type RedisBusOption func(*RedisBus)
+
+// WithAckDelivery enables at-least-once delivery. Signals not acknowledged
+// within ackTimeout are redelivered, up to maxDeliveries attempts in total
+// (0 for unlimited) after which they are dropped.
+func WithAckDelivery(ackTimeout time.Duration, maxDeliveries int) RedisBusOption {
+	return func(b *RedisBus) {
+		b.ackTimeout = ackTimeout
+		b.maxDeliveries = maxDeliveries
+	}
+}
+
+// DefaultChannelPrefix is the default Redis channel prefix for signals. It
+// also prefixes the streams of at-least-once delivery and the history.
+const DefaultChannelPrefix = rediskey.Root + "signal:"
+
 // NewRedisBus creates a new Redis-backed Signal Bus.
-func NewRedisBus(client redis.UniversalClient, channelPrefix string, bufferSize int) *RedisBus {
+func NewRedisBus(client redis.UniversalClient, channelPrefix string, bufferSize int, opts ...RedisBusOption) *RedisBus {
 	if channelPrefix == "" {
-		channelPrefix = "goclaw:signal:"
+		channelPrefix = DefaultChannelPrefix
 	}
 	if bufferSize <= 0 {
 		bufferSize = 16
 	}
-	return &RedisBus{
+	b := &RedisBus{
 		client:        client,
 		channelPrefix: channelPrefix,
 		bufferSize:    bufferSize,
+		consumer:      newConsumerName(),
 		subscribers:   make(map[string]*redisSubscription),
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// AckEnabled reports whether the bus delivers signals at least once.
+func (b *RedisBus) AckEnabled() bool {
+	return b.ackTimeout > 0
 }
 
 // Publish sends a signal via Redis Pub/Sub.
@@ -68,10 +115,16 @@ func (b *RedisBus) Publish(ctx context.Context, sig *Signal) error {
 	}
 
 	channel := b.channelPrefix + sig.TaskID
-	if err := b.client.Publish(ctx, channel, data).Err(); err != nil {
+	if b.AckEnabled() {
+		err = b.appendStream(ctx, channel, data)
+	} else {
+		err = b.client.Publish(ctx, channel, data).Err()
+	}
+	if err != nil {
 		metricsRecorder().RecordSignalFailed("redis", string(sig.Type), "publish_failed")
 		return err
 	}
+	b.stats.published.Add(1)
 	metricsRecorder().RecordSignalSent("redis", string(sig.Type))
 	return nil
 }
@@ -94,9 +147,19 @@ func (b *RedisBus) Subscribe(ctx context.Context, taskID string) (<-chan *Signal
 	}
 
 	channel := b.channelPrefix + taskID
-	pubsub := b.client.Subscribe(ctx, channel)
-
 	ch := make(chan *Signal, b.bufferSize)
+
+	if b.AckEnabled() {
+		if err := b.ensureGroup(ctx, channel); err != nil {
+			return nil, err
+		}
+		subCtx, cancel := context.WithCancel(ctx)
+		b.subscribers[taskID] = &redisSubscription{ch: ch, cancel: cancel, streaming: true}
+		go b.consumeStream(subCtx, taskID, channel, ch)
+		return ch, nil
+	}
+
+	pubsub := b.client.Subscribe(ctx, channel)
 	subCtx, cancel := context.WithCancel(ctx)
 
 	sub := &redisSubscription{
@@ -133,6 +196,7 @@ func (b *RedisBus) forwardMessages(ctx context.Context, pubsub *redis.PubSub, ch
 			}
 			select {
 			case ch <- &sig:
+				b.stats.delivered.Add(1)
 				metricsRecorder().RecordSignalReceived("redis", string(sig.Type))
 			default:
 				metricsRecorder().RecordSignalFailed("redis", string(sig.Type), "buffer_full_drop")
@@ -142,6 +206,7 @@ func (b *RedisBus) forwardMessages(ctx context.Context, pubsub *redis.PubSub, ch
 				}
 				select {
 				case ch <- &sig:
+					b.stats.delivered.Add(1)
 					metricsRecorder().RecordSignalReceived("redis", string(sig.Type))
 				default:
 					metricsRecorder().RecordSignalFailed("redis", string(sig.Type), "buffer_still_full")
@@ -161,8 +226,7 @@ func (b *RedisBus) Unsubscribe(taskID string) error {
 		return nil
 	}
 
-	sub.cancel()
-	close(sub.ch)
+	sub.stop()
 	delete(b.subscribers, taskID)
 	return nil
 }
@@ -178,8 +242,7 @@ func (b *RedisBus) Close() error {
 
 	b.closed = true
 	for taskID, sub := range b.subscribers {
-		sub.cancel()
-		close(sub.ch)
+		sub.stop()
 		delete(b.subscribers, taskID)
 	}
 	return nil
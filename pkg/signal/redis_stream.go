@@ -0,0 +1,294 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ackGroup is the consumer group every bus instance reads task streams with.
+	ackGroup = "goclaw"
+
+	// streamField is the stream entry field holding the encoded signal.
+	streamField = "signal"
+
+	// streamMaxLen caps each task stream; older entries are trimmed.
+	streamMaxLen = 1000
+
+	// streamTTL expires task streams that are no longer published to.
+	streamTTL = 24 * time.Hour
+
+	// maxPendingList bounds the entries returned by Pending.
+	maxPendingList = 1000
+)
+
+var _ AckBus = (*RedisBus)(nil)
+
+// Ack acknowledges a signal delivered with at-least-once delivery.
+func (b *RedisBus) Ack(ctx context.Context, taskID, deliveryID string) error {
+	return b.ack(ctx, taskID, deliveryID, "unknown")
+}
+
+func (b *RedisBus) ack(ctx context.Context, taskID, deliveryID, signalType string) error {
+	if !b.AckEnabled() {
+		return ErrAckDisabled
+	}
+	if taskID == "" || deliveryID == "" {
+		return fmt.Errorf("task_id and delivery_id cannot be empty")
+	}
+
+	n, err := b.client.XAck(ctx, b.channelPrefix+taskID, ackGroup, deliveryID).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotPending
+	}
+	b.stats.acked.Add(1)
+	metricsRecorder().RecordSignalAcked("redis", signalType)
+	return nil
+}
+
+// Pending lists the unacknowledged signals of a task, oldest first. It
+// returns nil when acknowledged delivery is disabled.
+func (b *RedisBus) Pending(ctx context.Context, taskID string) ([]PendingSignal, error) {
+	if !b.AckEnabled() {
+		return nil, nil
+	}
+	if taskID == "" {
+		return nil, fmt.Errorf("task_id cannot be empty")
+	}
+
+	entries, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: b.channelPrefix + taskID,
+		Group:  ackGroup,
+		Start:  "-",
+		End:    "+",
+		Count:  maxPendingList,
+	}).Result()
+	if err != nil {
+		if isNoGroupErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	pending := make([]PendingSignal, 0, len(entries))
+	for _, e := range entries {
+		pending = append(pending, PendingSignal{
+			DeliveryID: e.ID,
+			TaskID:     taskID,
+			Consumer:   e.Consumer,
+			Idle:       e.Idle,
+			Deliveries: e.RetryCount,
+		})
+	}
+	return pending, nil
+}
+
+// DeliveryStats returns the bus's cumulative delivery counters.
+func (b *RedisBus) DeliveryStats() DeliveryStats {
+	return b.stats.snapshot(b.AckEnabled())
+}
+
+func (b *RedisBus) appendStream(ctx context.Context, stream string, data []byte) error {
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: streamMaxLen,
+			Approx: true,
+			Values: map[string]interface{}{streamField: data},
+		})
+		pipe.Expire(ctx, stream, streamTTL)
+		return nil
+	})
+	return err
+}
+
+// ensureGroup creates the task stream and its consumer group. Reading starts
+// from the beginning of the stream so signals published before the task
+// subscribed are still delivered.
+func (b *RedisBus) ensureGroup(ctx context.Context, stream string) error {
+	err := b.client.XGroupCreateMkStream(ctx, stream, ackGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return b.client.Expire(ctx, stream, streamTTL).Err()
+}
+
+// consumeStream reads new and timed-out signals of a task and forwards them
+// to ch, which it closes on return.
+func (b *RedisBus) consumeStream(ctx context.Context, taskID, stream string, ch chan *Signal) {
+	defer close(ch)
+
+	// XREADGROUP treats a zero block as "forever", so keep it positive and
+	// short enough to reclaim timed-out signals promptly.
+	block := b.ackTimeout / 2
+	if block > time.Second {
+		block = time.Second
+	}
+	if block < 10*time.Millisecond {
+		block = 10 * time.Millisecond
+	}
+
+	var lastReclaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastReclaim) >= b.ackTimeout/2 {
+			lastReclaim = time.Now()
+			if !b.reclaim(ctx, taskID, stream, ch) {
+				return
+			}
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    ackGroup,
+			Consumer: b.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    int64(b.bufferSize),
+			Block:    block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			metricsRecorder().RecordSignalFailed("redis", "unknown", "read_failed")
+			if isNoGroupErr(err) {
+				// The stream expired or was deleted while subscribed.
+				_ = b.ensureGroup(ctx, stream)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(block):
+			}
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				if !b.deliver(ctx, taskID, stream, msg, 1, ch) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// reclaim claims signals that stayed unacknowledged past the ack timeout and
+// redelivers them, dropping those that reached maxDeliveries. It reports
+// false once ctx is done.
+func (b *RedisBus) reclaim(ctx context.Context, taskID, stream string, ch chan *Signal) bool {
+	entries, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  ackGroup,
+		Idle:   b.ackTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(b.bufferSize),
+	}).Result()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		if !isNoGroupErr(err) {
+			metricsRecorder().RecordSignalFailed("redis", "unknown", "pending_failed")
+		}
+		return true
+	}
+
+	attempts := make(map[string]int, len(entries))
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if b.maxDeliveries > 0 && e.RetryCount >= int64(b.maxDeliveries) {
+			if n, err := b.client.XAck(ctx, stream, ackGroup, e.ID).Result(); err == nil && n > 0 {
+				b.stats.deadLettered.Add(1)
+				metricsRecorder().RecordSignalFailed("redis", "unknown", "max_deliveries_exceeded")
+			}
+			continue
+		}
+		attempts[e.ID] = int(e.RetryCount) + 1
+		ids = append(ids, e.ID)
+	}
+	if len(ids) == 0 {
+		return true
+	}
+
+	msgs, err := b.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    ackGroup,
+		Consumer: b.consumer,
+		MinIdle:  b.ackTimeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		metricsRecorder().RecordSignalFailed("redis", "unknown", "claim_failed")
+		return true
+	}
+
+	for _, msg := range msgs {
+		if !b.deliver(ctx, taskID, stream, msg, attempts[msg.ID], ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver decodes a stream entry and blocks until it is handed to ch. It
+// reports false once ctx is done.
+func (b *RedisBus) deliver(ctx context.Context, taskID, stream string, msg redis.XMessage, attempt int, ch chan *Signal) bool {
+	raw, _ := msg.Values[streamField].(string)
+	var sig Signal
+	if err := json.Unmarshal([]byte(raw), &sig); err != nil {
+		metricsRecorder().RecordSignalFailed("redis", "unknown", "decode_failed")
+		// Undecodable entries would be redelivered forever.
+		_ = b.client.XAck(ctx, stream, ackGroup, msg.ID).Err()
+		return true
+	}
+
+	sig.DeliveryID = msg.ID
+	sig.Attempt = attempt
+	signalType := string(sig.Type)
+	sig.ack = func(ctx context.Context) error {
+		return b.ack(ctx, taskID, msg.ID, signalType)
+	}
+
+	select {
+	case ch <- &sig:
+	case <-ctx.Done():
+		return false
+	}
+	b.stats.delivered.Add(1)
+	metricsRecorder().RecordSignalReceived("redis", signalType)
+	if attempt > 1 {
+		b.stats.redelivered.Add(1)
+		metricsRecorder().RecordSignalRedelivered("redis", signalType)
+	}
+	return true
+}
+
+func isNoGroupErr(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// newConsumerName returns a consumer name unique to this bus instance.
+func newConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "goclaw"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
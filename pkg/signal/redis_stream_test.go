@@ -0,0 +1,137 @@
+package signal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSignal_AckWithoutAcknowledgedDelivery(t *testing.T) {
+	var nilSig *Signal
+	if err := nilSig.Ack(context.Background()); err != nil {
+		t.Fatalf("expected nil signal ack to be a no-op, got %v", err)
+	}
+	if err := (&Signal{Type: SignalSteer}).Ack(context.Background()); err != nil {
+		t.Fatalf("expected at-most-once ack to be a no-op, got %v", err)
+	}
+}
+
+func TestRedisBus_AckDisabledByDefault(t *testing.T) {
+	bus := NewRedisBus(nil, "", 16)
+	if bus.AckEnabled() {
+		t.Fatal("expected ack delivery to be disabled by default")
+	}
+	if err := bus.Ack(context.Background(), "task", "1-0"); !errors.Is(err, ErrAckDisabled) {
+		t.Fatalf("expected ErrAckDisabled, got %v", err)
+	}
+	pending, err := bus.Pending(context.Background(), "task")
+	if err != nil || pending != nil {
+		t.Fatalf("expected no pending signals, got %v, %v", pending, err)
+	}
+	if stats := bus.DeliveryStats(); stats.AckEnabled {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRedisBus_AckDelivery(t *testing.T) {
+	client := requireRedisBusClient(t)
+	prefix := fmt.Sprintf("goclaw:test:signal:ack:%d:", time.Now().UnixNano())
+	t.Cleanup(func() { _ = client.Del(context.Background(), prefix+"task-1").Err() })
+
+	bus := NewRedisBus(client, prefix, 16, WithAckDelivery(time.Minute, 3))
+	defer bus.Close()
+
+	// Signals published before the task subscribes are still delivered.
+	if err := SendSteer(context.Background(), bus, "task-1", map[string]interface{}{"rate": 1}); err != nil {
+		t.Fatalf("SendSteer() error = %v", err)
+	}
+
+	ch, err := bus.Subscribe(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	var sig *Signal
+	select {
+	case sig = <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for signal")
+	}
+	if sig.DeliveryID == "" || sig.Attempt != 1 {
+		t.Fatalf("unexpected delivery metadata: id=%q attempt=%d", sig.DeliveryID, sig.Attempt)
+	}
+
+	pending, err := bus.Pending(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].DeliveryID != sig.DeliveryID || pending[0].Deliveries != 1 {
+		t.Fatalf("unexpected pending signals: %+v", pending)
+	}
+
+	if err := sig.Ack(context.Background()); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if err := bus.Ack(context.Background(), "task-1", sig.DeliveryID); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("expected ErrNotPending on second ack, got %v", err)
+	}
+	if pending, _ := bus.Pending(context.Background(), "task-1"); len(pending) != 0 {
+		t.Fatalf("expected no pending signals after ack, got %+v", pending)
+	}
+
+	stats := bus.DeliveryStats()
+	if !stats.AckEnabled || stats.Published != 1 || stats.Delivered != 1 || stats.Acked != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRedisBus_RedeliversUnackedSignals(t *testing.T) {
+	client := requireRedisBusClient(t)
+	prefix := fmt.Sprintf("goclaw:test:signal:redeliver:%d:", time.Now().UnixNano())
+	t.Cleanup(func() { _ = client.Del(context.Background(), prefix+"task-1").Err() })
+
+	bus := NewRedisBus(client, prefix, 16, WithAckDelivery(100*time.Millisecond, 2))
+	defer bus.Close()
+
+	ch, err := bus.Subscribe(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := SendInterrupt(context.Background(), bus, "task-1", true, "test", time.Second); err != nil {
+		t.Fatalf("SendInterrupt() error = %v", err)
+	}
+
+	receive := func() *Signal {
+		t.Helper()
+		select {
+		case sig := <-ch:
+			return sig
+		case <-time.After(3 * time.Second):
+			t.Fatal("timeout waiting for signal")
+			return nil
+		}
+	}
+
+	first := receive()
+	second := receive()
+	if second.DeliveryID != first.DeliveryID || second.Attempt != 2 {
+		t.Fatalf("expected redelivery of %s as attempt 2, got %s attempt %d", first.DeliveryID, second.DeliveryID, second.Attempt)
+	}
+
+	// The third attempt exceeds max deliveries and the signal is dropped.
+	deadline := time.Now().Add(3 * time.Second)
+	for bus.DeliveryStats().DeadLettered == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected signal to be dead-lettered, stats: %+v", bus.DeliveryStats())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if pending, _ := bus.Pending(context.Background(), "task-1"); len(pending) != 0 {
+		t.Fatalf("expected no pending signals, got %+v", pending)
+	}
+	if stats := bus.DeliveryStats(); stats.Redelivered != 1 {
+		t.Fatalf("expected 1 redelivery, got %+v", stats)
+	}
+}
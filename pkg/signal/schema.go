@@ -0,0 +1,328 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/jsonschema"
+)
+
+// Schema is a compiled JSON Schema for signal payloads, in the subset
+// package jsonschema supports. Any other keyword is rejected so schemas
+// never silently validate less than they appear to.
+type Schema struct {
+	raw      json.RawMessage
+	compiled *jsonschema.Schema
+}
+
+// SchemaViolation describes one way a payload does not match a schema.
+type SchemaViolation struct {
+	// Path locates the offending value (e.g. "payload.parameters.rate").
+	Path string `json:"path"`
+	// Keyword is the schema keyword that failed.
+	Keyword string `json:"keyword"`
+	// Message is a human-readable description of the violation.
+	Message string `json:"message"`
+}
+
+// PayloadError is the cause of errors returned for signals whose payload does
+// not match a registered schema.
+type PayloadError struct {
+	// Channel is the channel pattern of the schema that failed.
+	Channel string
+	// Type is the signal type of the schema that failed; empty for any type.
+	Type SignalType
+	// Violations lists every mismatch found.
+	Violations []SchemaViolation
+}
+
+func (e *PayloadError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Path+": "+v.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CompileSchema parses a JSON Schema document.
+func CompileSchema(raw []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	var compiled *jsonschema.Schema
+	var err error
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		compiled, err = jsonschema.Compile(v)
+	case bool:
+		// jsonschema compiles boolean schemas nested in an object.
+		compiled, err = jsonschema.Compile(map[string]interface{}{"allOf": []interface{}{v}})
+	default:
+		err = fmt.Errorf("schema must be an object or boolean")
+	}
+	if err != nil {
+		return nil, err
+	}
+	compact, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{raw: compact, compiled: compiled}, nil
+}
+
+// Raw returns the schema document.
+func (s *Schema) Raw() json.RawMessage {
+	return s.raw
+}
+
+// Validate checks payload against the schema and returns every violation.
+// An empty payload is validated as null.
+func (s *Schema) Validate(payload []byte) []SchemaViolation {
+	var value interface{}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return []SchemaViolation{{Path: "payload", Keyword: "type", Message: "is not valid JSON"}}
+		}
+	}
+	var out []SchemaViolation
+	for _, v := range s.compiled.Violations(value) {
+		out = append(out, SchemaViolation{Path: payloadPath(v.Path), Keyword: v.Keyword, Message: v.Message})
+	}
+	return out
+}
+
+// payloadPath renders the path of a violation as in "payload.tags[1]".
+func payloadPath(path []any) string {
+	var b strings.Builder
+	b.WriteString("payload")
+	for _, elem := range path {
+		if i, ok := elem.(int); ok {
+			fmt.Fprintf(&b, "[%d]", i)
+		} else {
+			fmt.Fprintf(&b, ".%v", elem)
+		}
+	}
+	return b.String()
+}
+
+// SchemaRegistration binds a payload schema to the channels matching a
+// pattern and, optionally, a single signal type.
+type SchemaRegistration struct {
+	// Channel is a path.Match pattern for the channels (task IDs) the schema
+	// applies to, e.g. "*" or "ingest-*".
+	Channel string `json:"channel"`
+
+	// Type restricts the schema to one signal type; empty matches every type.
+	Type SignalType `json:"type,omitempty"`
+
+	// Schema is the JSON Schema document.
+	Schema json.RawMessage `json:"schema"`
+
+	// UpdatedAt is when the schema was registered.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type schemaKey struct {
+	channel string
+	typ     SignalType
+}
+
+type schemaEntry struct {
+	reg    SchemaRegistration
+	schema *Schema
+}
+
+// SchemaRegistry holds the payload schemas signals are validated against.
+// A signal must match every registered schema whose channel pattern matches
+// its task ID and whose type is empty or equal to its type.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	entries map[schemaKey]*schemaEntry
+}
+
+// NewSchemaRegistry creates an empty schema registry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{entries: make(map[schemaKey]*schemaEntry)}
+}
+
+// Register compiles schema and binds it to channel and typ, replacing any
+// schema registered for the same pair.
+func (r *SchemaRegistry) Register(channel string, typ SignalType, schema []byte) (SchemaRegistration, error) {
+	if channel == "" {
+		return SchemaRegistration{}, errs.New(errs.ValidationFailed, "schema channel cannot be empty")
+	}
+	if _, err := path.Match(channel, ""); err != nil {
+		return SchemaRegistration{}, errs.Newf(errs.ValidationFailed, "invalid schema channel pattern %q", channel)
+	}
+	switch typ {
+	case "", SignalSteer, SignalInterrupt, SignalCollect:
+	default:
+		return SchemaRegistration{}, errs.Newf(errs.ValidationFailed, "unknown signal type %q", typ)
+	}
+	compiled, err := CompileSchema(schema)
+	if err != nil {
+		return SchemaRegistration{}, errs.Wrap(err, errs.ValidationFailed, "invalid signal schema").WithDetail("reason", err.Error())
+	}
+
+	entry := &schemaEntry{
+		reg: SchemaRegistration{
+			Channel:   channel,
+			Type:      typ,
+			Schema:    compiled.Raw(),
+			UpdatedAt: time.Now().UTC(),
+		},
+		schema: compiled,
+	}
+
+	r.mu.Lock()
+	r.entries[schemaKey{channel: channel, typ: typ}] = entry
+	r.mu.Unlock()
+	return entry.reg, nil
+}
+
+// Unregister removes the schema bound to channel and typ and reports whether
+// one was registered.
+func (r *SchemaRegistry) Unregister(channel string, typ SignalType) bool {
+	key := schemaKey{channel: channel, typ: typ}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[key]; !ok {
+		return false
+	}
+	delete(r.entries, key)
+	return true
+}
+
+// List returns every registration ordered by channel pattern and type.
+func (r *SchemaRegistry) List() []SchemaRegistration {
+	return r.collect(func(*schemaEntry) bool { return true })
+}
+
+// Match returns the registrations that apply to signals of typ sent to
+// channel. An empty typ matches registrations of every type.
+func (r *SchemaRegistry) Match(channel string, typ SignalType) []SchemaRegistration {
+	return r.collect(func(e *schemaEntry) bool {
+		return e.applies(channel, typ)
+	})
+}
+
+// Validate checks the signal's payload against every applicable schema. The
+// returned error has code errs.ValidationFailed, lists the violations in its
+// details and wraps a *PayloadError.
+func (r *SchemaRegistry) Validate(sig *Signal) error {
+	if r == nil || sig == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]schemaKey, 0, len(r.entries))
+	for key, entry := range r.entries {
+		if entry.applies(sig.TaskID, sig.Type) {
+			keys = append(keys, key)
+		}
+	}
+	sortSchemaKeys(keys)
+
+	for _, key := range keys {
+		entry := r.entries[key]
+		violations := entry.schema.Validate(sig.Payload)
+		if len(violations) == 0 {
+			continue
+		}
+		payloadErr := &PayloadError{Channel: entry.reg.Channel, Type: entry.reg.Type, Violations: violations}
+		err := errs.Wrap(payloadErr, errs.ValidationFailed,
+			fmt.Sprintf("signal payload does not match schema for channel %q", entry.reg.Channel)).
+			WithDetail("channel", sig.TaskID).
+			WithDetail("signal_type", string(sig.Type))
+		messages := make(map[string][]string)
+		for _, v := range violations {
+			messages[v.Path] = append(messages[v.Path], v.Message)
+		}
+		for at, msgs := range messages {
+			err.WithDetail(at, strings.Join(msgs, "; "))
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *SchemaRegistry) collect(keep func(*schemaEntry) bool) []SchemaRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]schemaKey, 0, len(r.entries))
+	for key, entry := range r.entries {
+		if keep(entry) {
+			keys = append(keys, key)
+		}
+	}
+	sortSchemaKeys(keys)
+
+	regs := make([]SchemaRegistration, 0, len(keys))
+	for _, key := range keys {
+		regs = append(regs, r.entries[key].reg)
+	}
+	return regs
+}
+
+func (e *schemaEntry) applies(channel string, typ SignalType) bool {
+	if e.reg.Type != "" && typ != "" && e.reg.Type != typ {
+		return false
+	}
+	ok, _ := path.Match(e.reg.Channel, channel)
+	return ok
+}
+
+func sortSchemaKeys(keys []schemaKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channel != keys[j].channel {
+			return keys[i].channel < keys[j].channel
+		}
+		return keys[i].typ < keys[j].typ
+	})
+}
+
+// ValidatingBus is a Bus that rejects signals whose payload does not match
+// the schemas registered for their channel.
+type ValidatingBus struct {
+	Bus
+	schemas *SchemaRegistry
+}
+
+// NewValidatingBus wraps bus so published signals are validated against schemas.
+func NewValidatingBus(bus Bus, schemas *SchemaRegistry) *ValidatingBus {
+	if schemas == nil {
+		schemas = NewSchemaRegistry()
+	}
+	return &ValidatingBus{Bus: bus, schemas: schemas}
+}
+
+// Schemas returns the registry signals are validated against.
+func (b *ValidatingBus) Schemas() *SchemaRegistry {
+	return b.schemas
+}
+
+// Unwrap returns the wrapped bus.
+func (b *ValidatingBus) Unwrap() Bus {
+	return b.Bus
+}
+
+// Publish validates the signal and publishes it if it matches its schemas.
+func (b *ValidatingBus) Publish(ctx context.Context, sig *Signal) error {
+	if err := b.schemas.Validate(sig); err != nil {
+		metricsRecorder().RecordSignalFailed("schema", string(sig.Type), "schema_violation")
+		return err
+	}
+	return b.Bus.Publish(ctx, sig)
+}
@@ -0,0 +1,210 @@
+package signal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+)
+
+func TestCompileSchema_RejectsUnsupportedKeywords(t *testing.T) {
+	for _, raw := range []string{
+		`{"type":"object","if":{"required":["a"]}}`,
+		`{"type":"decimal"}`,
+		`{"properties":{"a":{"minLength":-1}}}`,
+		`{"pattern":"("}`,
+		`"object"`,
+		`{`,
+	} {
+		if _, err := CompileSchema([]byte(raw)); err == nil {
+			t.Errorf("expected compile error for %s", raw)
+		}
+	}
+
+	if _, err := CompileSchema([]byte(`{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"steer","type":"object"}`)); err != nil {
+		t.Fatalf("expected annotations to be accepted, got %v", err)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{
+		"type": "object",
+		"required": ["parameters"],
+		"properties": {
+			"parameters": {
+				"type": "object",
+				"required": ["rate"],
+				"additionalProperties": false,
+				"properties": {
+					"rate": {"type": "integer", "minimum": 1, "maximum": 100},
+					"mode": {"enum": ["fast", "slow"]},
+					"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z]+$"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("CompileSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{name: "valid", payload: `{"parameters":{"rate":10,"mode":"fast","tags":["a"]}}`},
+		{name: "missing required", payload: `{}`, want: []string{"payload.parameters: missing required property parameters"}},
+		{name: "wrong type", payload: `{"parameters":{"rate":1.5}}`, want: []string{"payload.parameters.rate: got number, want integer"}},
+		{
+			name:    "several violations",
+			payload: `{"parameters":{"rate":0,"mode":"medium","extra":true,"tags":["a","B","c"]}}`,
+			want: []string{
+				"payload.parameters.extra: property extra is not allowed",
+				"payload.parameters.mode: value is not one of",
+				"payload.parameters.rate: 0 is less than 1",
+				"payload.parameters.tags: has 3 items, want at most 2",
+				"payload.parameters.tags[1]: does not match pattern",
+			},
+		},
+		{name: "null payload", payload: ``, want: []string{"payload: got null, want object"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := schema.Validate([]byte(tt.payload))
+			if len(violations) != len(tt.want) {
+				t.Fatalf("expected %d violations, got %+v", len(tt.want), violations)
+			}
+			for i, want := range tt.want {
+				if got := violations[i].Path + ": " + violations[i].Message; !strings.HasPrefix(got, want) {
+					t.Errorf("violation %d: expected %q, got %q", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSchema_Combinators(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{
+		"oneOf": [{"type": "string"}, {"type": "integer"}],
+		"not": {"const": 0}
+	}`))
+	if err != nil {
+		t.Fatalf("CompileSchema() error = %v", err)
+	}
+
+	for payload, valid := range map[string]bool{
+		`"a"`:  true,
+		`7`:    true,
+		`0`:    false,
+		`true`: false,
+	} {
+		if got := len(schema.Validate([]byte(payload))) == 0; got != valid {
+			t.Errorf("%s: expected valid=%v, got %v", payload, valid, got)
+		}
+	}
+}
+
+func TestSchemaRegistry_Match(t *testing.T) {
+	registry := NewSchemaRegistry()
+	for _, reg := range []struct {
+		channel string
+		typ     SignalType
+	}{
+		{"*", ""},
+		{"ingest-*", SignalSteer},
+		{"ingest-*", SignalInterrupt},
+	} {
+		if _, err := registry.Register(reg.channel, reg.typ, []byte(`{"type":"object"}`)); err != nil {
+			t.Fatalf("Register(%q, %q) error = %v", reg.channel, reg.typ, err)
+		}
+	}
+
+	if got := registry.Match("ingest-1", SignalSteer); len(got) != 2 || got[0].Channel != "*" || got[1].Type != SignalSteer {
+		t.Fatalf("unexpected matches: %+v", got)
+	}
+	if got := registry.Match("other", SignalSteer); len(got) != 1 {
+		t.Fatalf("expected only the wildcard schema, got %+v", got)
+	}
+	if got := registry.Match("ingest-1", ""); len(got) != 3 {
+		t.Fatalf("expected every type to match an empty type, got %+v", got)
+	}
+
+	if !registry.Unregister("ingest-*", SignalInterrupt) || registry.Unregister("ingest-*", SignalInterrupt) {
+		t.Fatal("expected Unregister to remove the schema once")
+	}
+	if got := registry.List(); len(got) != 2 {
+		t.Fatalf("expected 2 schemas, got %+v", got)
+	}
+
+	for _, reg := range []struct {
+		channel string
+		typ     SignalType
+		schema  string
+	}{
+		{"", "", `{}`},
+		{"[", "", `{}`},
+		{"*", "resume", `{}`},
+		{"*", "", `{"type":1}`},
+	} {
+		if _, err := registry.Register(reg.channel, reg.typ, []byte(reg.schema)); !errs.Is(err, errs.ValidationFailed) {
+			t.Errorf("Register(%q, %q, %s): expected validation error, got %v", reg.channel, reg.typ, reg.schema, err)
+		}
+	}
+}
+
+func TestValidatingBus_RejectsInvalidPayloads(t *testing.T) {
+	registry := NewSchemaRegistry()
+	if _, err := registry.Register("task-*", SignalSteer, []byte(`{
+		"type": "object",
+		"properties": {"parameters": {"type": "object", "required": ["rate"]}}
+	}`)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	history := NewMemoryHistory(10, 10)
+	bus := NewValidatingBus(NewHistoryBus(NewLocalBus(4), history), registry)
+	defer bus.Close()
+
+	ch, err := bus.Subscribe(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	err = SendSteer(context.Background(), bus, "task-1", map[string]interface{}{"mode": "fast"})
+	if !errs.Is(err, errs.ValidationFailed) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	var payloadErr *PayloadError
+	if !errors.As(err, &payloadErr) || payloadErr.Channel != "task-*" || len(payloadErr.Violations) != 1 {
+		t.Fatalf("expected payload error for task-*, got %#v", err)
+	}
+	if got := errs.From(err).Details["payload.parameters.rate"]; got != "missing required property rate" {
+		t.Fatalf("expected violation in error details, got %v", errs.From(err).Details)
+	}
+
+	// Other signal types and channels are not constrained by the schema.
+	if err := SendInterrupt(context.Background(), bus, "task-1", true, "stop", 0); err != nil {
+		t.Fatalf("SendInterrupt() error = %v", err)
+	}
+	if err := SendSteer(context.Background(), bus, "task-1", map[string]interface{}{"rate": 2}); err != nil {
+		t.Fatalf("SendSteer() error = %v", err)
+	}
+
+	for _, want := range []SignalType{SignalInterrupt, SignalSteer} {
+		if sig := <-ch; sig.Type != want {
+			t.Fatalf("expected %s signal, got %s", want, sig.Type)
+		}
+	}
+	if got := HistoryOf(bus); got != history {
+		t.Fatal("expected HistoryOf to find the wrapped history")
+	}
+	if recorded, _ := history.List(context.Background(), "task-1", time.Time{}, 0); len(recorded) != 2 {
+		t.Fatalf("expected rejected signal not to be recorded, got %d signals", len(recorded))
+	}
+	if _, ok := AsAckBus(bus); !ok {
+		t.Fatal("expected AsAckBus to find the history bus")
+	}
+}
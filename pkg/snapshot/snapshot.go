@@ -0,0 +1,381 @@
+// Package snapshot exports the state of a Goclaw instance to a
+// gzip-compressed tar archive and restores it into an empty instance, for
+// disaster recovery or to clone an environment.
+//
+// An archive holds manifest.json, describing it, then workflows.jsonl with
+// one workflow and its tasks per line, sagas.jsonl, memory.jsonl with the
+// memory entries and their metadata and decay state, and resources.json with
+// the managed templates, schedules, calendars, webhooks and lanes. Each part
+// is read as of one point in time of its store: workflows and tasks from one
+// storage snapshot, sagas from one listing, memory entries from one read
+// transaction and resources under one lock. The parts are read one after
+// another, so to take all of them at the same point in time, drain the
+// instance first.
+//
+// An import reads and checks the whole archive before it writes anything,
+// and only restores into an instance without workflows, sagas, memory
+// entries or resources. Workflows keep their IDs and states but start again
+// at version 1, and resources get new versions. Unfinished workflows are
+// resumed by the recovery of the instance's next start.
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/manage"
+	"github.com/goclaw/goclaw/pkg/memory"
+	"github.com/goclaw/goclaw/pkg/saga"
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/version"
+)
+
+// FormatVersion is the version of the archive layout written by Export.
+const FormatVersion = 1
+
+// Names of the archive entries.
+const (
+	manifestFile  = "manifest.json"
+	workflowsFile = "workflows.jsonl"
+	sagasFile     = "sagas.jsonl"
+	memoryFile    = "memory.jsonl"
+	resourcesFile = "resources.json"
+)
+
+// Source is the state of an instance, as exported and restored. Only Storage
+// is required; the parts of disabled features are left nil.
+type Source struct {
+	Storage   storage.Storage
+	Sagas     *saga.SagaOrchestrator
+	Memory    *memory.MemoryHub
+	Resources *manage.Service
+
+	// Flush, when set, is called before an export reads Storage, to persist
+	// buffered writes.
+	Flush func(ctx context.Context) error
+}
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	GoclawVersion string    `json:"goclaw_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Workflows     int       `json:"workflows"`
+	Tasks         int       `json:"tasks"`
+	Sagas         int       `json:"sagas"`
+	MemoryEntries int       `json:"memory_entries"`
+	Resources     int       `json:"resources"`
+}
+
+// WorkflowRecord is one line of workflows.jsonl.
+type WorkflowRecord struct {
+	Workflow *storage.WorkflowState `json:"workflow"`
+	Tasks    []*storage.TaskState   `json:"tasks,omitempty"`
+}
+
+// Export writes an archive of src to w and returns its manifest. Nothing is
+// written to w unless every part was read.
+func Export(ctx context.Context, w io.Writer, src Source) (*Manifest, error) {
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		GoclawVersion: version.Version,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if src.Flush != nil {
+		if err := src.Flush(ctx); err != nil {
+			return nil, fmt.Errorf("flush task writes: %w", err)
+		}
+	}
+	var workflows bytes.Buffer
+	enc := json.NewEncoder(&workflows)
+	err := storage.Snapshot(ctx, src.Storage, func(wf *storage.WorkflowState, tasks []*storage.TaskState) error {
+		manifest.Workflows++
+		manifest.Tasks += len(tasks)
+		return enc.Encode(WorkflowRecord{Workflow: wf, Tasks: tasks})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export workflows: %w", err)
+	}
+
+	var sagas bytes.Buffer
+	if src.Sagas != nil {
+		instances, _, err := src.Sagas.ListInstancesFiltered(ctx, saga.SagaListFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("export sagas: %w", err)
+		}
+		if err := encodeLines(&sagas, instances); err != nil {
+			return nil, fmt.Errorf("export sagas: %w", err)
+		}
+		manifest.Sagas = len(instances)
+	}
+
+	var entries bytes.Buffer
+	if src.Memory != nil {
+		all, err := src.Memory.Entries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("export memory: %w", err)
+		}
+		if err := encodeLines(&entries, all); err != nil {
+			return nil, fmt.Errorf("export memory: %w", err)
+		}
+		manifest.MemoryEntries = len(all)
+	}
+
+	var resources manage.Resources
+	if src.Resources != nil {
+		resources = src.Resources.Export()
+		manifest.Resources = resources.Len()
+	}
+	resourcesData, err := json.Marshal(resources)
+	if err != nil {
+		return nil, fmt.Errorf("export resources: %w", err)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{manifestFile, manifestData},
+		{workflowsFile, workflows.Bytes()},
+		{sagasFile, sagas.Bytes()},
+		{memoryFile, entries.Bytes()},
+		{resourcesFile, resourcesData},
+	}
+	for _, part := range parts {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    part.name,
+			Mode:    0o644,
+			Size:    int64(len(part.data)),
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(part.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func encodeLines[T any](w io.Writer, items []T) error {
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archive is a decoded archive.
+type archive struct {
+	manifest  Manifest
+	workflows []WorkflowRecord
+	sagas     []*saga.SagaInstance
+	memory    []*memory.MemoryEntry
+	resources manage.Resources
+}
+
+// Import restores the archive read from r into dst and returns its manifest.
+// It fails with a BadRequest error if the archive is malformed or holds
+// parts dst has no store for, and with a Conflict error if dst is not empty.
+func Import(ctx context.Context, r io.Reader, dst Source) (*Manifest, error) {
+	a, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	if a.manifest.Sagas > 0 && dst.Sagas == nil {
+		return nil, errs.Newf(errs.BadRequest, "snapshot has %d sagas but sagas are disabled", a.manifest.Sagas)
+	}
+	if a.manifest.MemoryEntries > 0 && dst.Memory == nil {
+		return nil, errs.Newf(errs.BadRequest, "snapshot has %d memory entries but memory is disabled", a.manifest.MemoryEntries)
+	}
+	if a.manifest.Resources > 0 && dst.Resources == nil {
+		return nil, errs.Newf(errs.BadRequest, "snapshot has %d managed resources but the management API is disabled", a.manifest.Resources)
+	}
+	if err := checkEmpty(ctx, dst); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range a.workflows {
+		rec.Workflow.Version = 0
+		err := storage.WriteBatch(ctx, dst.Storage, func(w storage.Writer) error {
+			if err := w.SaveWorkflow(ctx, rec.Workflow); err != nil {
+				return err
+			}
+			for _, task := range rec.Tasks {
+				if err := w.SaveTask(ctx, rec.Workflow.ID, task); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("restore workflow %s: %w", rec.Workflow.ID, err)
+		}
+	}
+	for _, instance := range a.sagas {
+		if err := dst.Sagas.RestoreInstance(ctx, instance); err != nil {
+			return nil, fmt.Errorf("restore saga %s: %w", instance.ID, err)
+		}
+	}
+	if len(a.memory) > 0 {
+		if err := dst.Memory.Restore(ctx, a.memory); err != nil {
+			return nil, err
+		}
+	}
+	if a.resources.Len() > 0 {
+		if err := dst.Resources.Restore(a.resources); err != nil {
+			return nil, fmt.Errorf("restore resources: %w", err)
+		}
+	}
+	return &a.manifest, nil
+}
+
+// readArchive reads and decodes the archive of r, checking its parts
+// against the manifest.
+func readArchive(r io.Reader) (*archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, "snapshot is not a gzip archive")
+	}
+	defer gz.Close()
+
+	parts := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errs.Wrap(err, errs.BadRequest, "snapshot archive is corrupt")
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errs.Wrap(err, errs.BadRequest, "snapshot archive is corrupt")
+		}
+		parts[hdr.Name] = data
+	}
+
+	a := &archive{}
+	data, ok := parts[manifestFile]
+	if !ok {
+		return nil, errs.New(errs.BadRequest, "snapshot has no "+manifestFile)
+	}
+	if err := json.Unmarshal(data, &a.manifest); err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, "invalid "+manifestFile)
+	}
+	if a.manifest.FormatVersion != FormatVersion {
+		return nil, errs.Newf(errs.BadRequest, "unsupported snapshot format version %d", a.manifest.FormatVersion)
+	}
+	if err := decodeLines(parts[workflowsFile], &a.workflows); err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, "invalid "+workflowsFile)
+	}
+	if err := decodeLines(parts[sagasFile], &a.sagas); err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, "invalid "+sagasFile)
+	}
+	if err := decodeLines(parts[memoryFile], &a.memory); err != nil {
+		return nil, errs.Wrap(err, errs.BadRequest, "invalid "+memoryFile)
+	}
+	if data := parts[resourcesFile]; len(data) > 0 {
+		if err := json.Unmarshal(data, &a.resources); err != nil {
+			return nil, errs.Wrap(err, errs.BadRequest, "invalid "+resourcesFile)
+		}
+	}
+
+	tasks := 0
+	for _, rec := range a.workflows {
+		if rec.Workflow == nil || rec.Workflow.ID == "" {
+			return nil, errs.New(errs.BadRequest, "snapshot has a workflow without an id")
+		}
+		tasks += len(rec.Tasks)
+	}
+	counts := []struct {
+		part      string
+		want, got int
+	}{
+		{"workflows", a.manifest.Workflows, len(a.workflows)},
+		{"tasks", a.manifest.Tasks, tasks},
+		{"sagas", a.manifest.Sagas, len(a.sagas)},
+		{"memory entries", a.manifest.MemoryEntries, len(a.memory)},
+		{"resources", a.manifest.Resources, a.resources.Len()},
+	}
+	for _, c := range counts {
+		if c.want != c.got {
+			return nil, errs.Newf(errs.BadRequest, "snapshot manifest lists %d %s but the archive holds %d", c.want, c.part, c.got)
+		}
+	}
+	return a, nil
+}
+
+func decodeLines[T any](data []byte, out *[]T) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var item T
+		err := dec.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		*out = append(*out, item)
+	}
+}
+
+// checkEmpty returns a Conflict error unless dst holds no state.
+func checkEmpty(ctx context.Context, dst Source) error {
+	_, workflows, err := dst.Storage.ListWorkflows(ctx, &storage.WorkflowFilter{Limit: 1})
+	if err != nil {
+		return err
+	}
+	if workflows > 0 {
+		return errs.Newf(errs.Conflict, "snapshots restore into an empty instance, but this one has %d workflows", workflows)
+	}
+	if dst.Sagas != nil {
+		_, sagas, err := dst.Sagas.ListInstancesFiltered(ctx, saga.SagaListFilter{Limit: 1})
+		if err != nil {
+			return err
+		}
+		if sagas > 0 {
+			return errs.Newf(errs.Conflict, "snapshots restore into an empty instance, but this one has %d sagas", sagas)
+		}
+	}
+	if dst.Memory != nil {
+		entries, err := dst.Memory.Entries(ctx)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return errs.Newf(errs.Conflict, "snapshots restore into an empty instance, but this one has %d memory entries", len(entries))
+		}
+	}
+	if dst.Resources != nil {
+		if n := dst.Resources.Export().Len(); n > 0 {
+			return errs.Newf(errs.Conflict, "snapshots restore into an empty instance, but this one has %d managed resources", n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	dgbadger "github.com/dgraph-io/badger/v4"
+	"github.com/goclaw/goclaw/config"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
+	"github.com/goclaw/goclaw/pkg/manage"
+	"github.com/goclaw/goclaw/pkg/memory"
+	"github.com/goclaw/goclaw/pkg/saga"
+	"github.com/goclaw/goclaw/pkg/storage"
+	memorystorage "github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// laneEngine is the part of the engine managed lanes need.
+type laneEngine struct{}
+
+func (laneEngine) SubmitWorkflowRequest(ctx context.Context, req *models.WorkflowRequest) (string, error) {
+	return "", errs.New(errs.NotImplemented, "not submitted in tests")
+}
+
+func (laneEngine) GetWorkflowSummaryResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	return nil, errs.New(errs.NotFound, "workflow not found")
+}
+
+func (laneEngine) GetWorkflowStatusResponse(ctx context.Context, id string) (*models.WorkflowStatusResponse, error) {
+	return nil, errs.New(errs.NotFound, "workflow not found")
+}
+
+func (laneEngine) PutLane(name string, capacity, maxConcurrency int, rateLimit float64) error {
+	return nil
+}
+
+func (laneEngine) DeleteLane(ctx context.Context, name string) error { return nil }
+
+func newSource(t *testing.T) Source {
+	t.Helper()
+	db, err := dgbadger.Open(dgbadger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("open memory db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	cfg := &config.MemoryConfig{
+		VectorDimension:  3,
+		VectorWeight:     0.7,
+		BM25Weight:       0.3,
+		L1CacheSize:      10,
+		ForgetThreshold:  0.1,
+		DecayInterval:    time.Hour,
+		DefaultStability: 24,
+		BM25:             config.BM25Config{K1: 1.5, B: 0.75},
+	}
+	hub := memory.NewMemoryHub(cfg, memory.NewTieredStorage(memory.NewL1Cache(10), memory.NewL2Badger(db)), nil)
+
+	return Source{
+		Storage:   memorystorage.NewMemoryStorage(),
+		Sagas:     saga.NewSagaOrchestrator(),
+		Memory:    hub,
+		Resources: manage.New(laneEngine{}),
+	}
+}
+
+func populate(t *testing.T, src Source) {
+	t.Helper()
+	ctx := context.Background()
+	for _, id := range []string{"wf-1", "wf-2"} {
+		wf := &storage.WorkflowState{ID: id, Name: "report", Status: "completed", CreatedAt: time.Now().UTC()}
+		if err := src.Storage.SaveWorkflow(ctx, wf); err != nil {
+			t.Fatalf("SaveWorkflow() error = %v", err)
+		}
+		if err := src.Storage.SaveTask(ctx, id, &storage.TaskState{ID: "t1", Name: "t1", Status: "completed", Result: "ok"}); err != nil {
+			t.Fatalf("SaveTask() error = %v", err)
+		}
+	}
+	instance := saga.NewSagaInstance("saga-1", nil)
+	if err := src.Sagas.RestoreInstance(ctx, instance); err != nil {
+		t.Fatalf("RestoreInstance() error = %v", err)
+	}
+	if _, err := src.Memory.Memorize(ctx, "session", "the sky is blue", []float32{1, 0, 0}, map[string]string{"source": "test"}); err != nil {
+		t.Fatalf("Memorize() error = %v", err)
+	}
+	if _, _, err := src.Resources.PutTemplate("report", models.WorkflowRequest{
+		Name:  "report",
+		Tasks: []models.TaskDefinition{{ID: "t1", Name: "t1", Type: "function"}},
+	}, manage.Precondition{}); err != nil {
+		t.Fatalf("PutTemplate() error = %v", err)
+	}
+	if _, _, _, err := src.Resources.PutSchedule("nightly", models.ScheduleSpec{Cron: "0 2 * * *", Template: "report"}, manage.Precondition{}); err != nil {
+		t.Fatalf("PutSchedule() error = %v", err)
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	populate(t, src)
+	flushed := false
+	src.Flush = func(context.Context) error {
+		flushed = true
+		return nil
+	}
+
+	var buf bytes.Buffer
+	manifest, err := Export(ctx, &buf, src)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !flushed {
+		t.Error("expected Export to flush buffered writes")
+	}
+	want := Manifest{Workflows: 2, Tasks: 2, Sagas: 1, MemoryEntries: 1, Resources: 2}
+	got := Manifest{Workflows: manifest.Workflows, Tasks: manifest.Tasks, Sagas: manifest.Sagas, MemoryEntries: manifest.MemoryEntries, Resources: manifest.Resources}
+	if got != want {
+		t.Fatalf("manifest counts = %+v, want %+v", got, want)
+	}
+
+	dst := newSource(t)
+	imported, err := Import(ctx, bytes.NewReader(buf.Bytes()), dst)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if imported.Workflows != 2 || !imported.CreatedAt.Equal(manifest.CreatedAt) {
+		t.Errorf("imported manifest = %+v", imported)
+	}
+
+	wf, err := dst.Storage.GetWorkflow(ctx, "wf-2")
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if wf.Status != "completed" || wf.Version != 1 {
+		t.Errorf("restored workflow status = %s, version = %d", wf.Status, wf.Version)
+	}
+	task, err := dst.Storage.GetTask(ctx, "wf-2", "t1")
+	if err != nil || task.Result != "ok" {
+		t.Errorf("restored task = %+v, %v", task, err)
+	}
+	if _, err := dst.Sagas.GetInstance("saga-1"); err != nil {
+		t.Errorf("GetInstance() error = %v", err)
+	}
+	results, err := dst.Memory.Retrieve(ctx, "session", memory.Query{Text: "sky", Mode: "bm25", TopK: 1})
+	if err != nil || len(results) != 1 || results[0].Entry.Metadata["source"] != "test" {
+		t.Errorf("restored memory was not retrievable: %v, %v", results, err)
+	}
+	if _, status, err := dst.Resources.GetSchedule("nightly"); err != nil || status.NextRunAt == nil {
+		t.Errorf("restored schedule = %+v, %v", status, err)
+	}
+}
+
+func TestImport_RequiresEmptyInstance(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	populate(t, src)
+	var buf bytes.Buffer
+	if _, err := Export(ctx, &buf, src); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	_, err := Import(ctx, bytes.NewReader(buf.Bytes()), src)
+	if !errs.Is(err, errs.Conflict) {
+		t.Fatalf("expected a conflict importing into a populated instance, got %v", err)
+	}
+}
+
+func TestImport_RejectsMissingParts(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	populate(t, src)
+	var buf bytes.Buffer
+	if _, err := Export(ctx, &buf, src); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	dst := Source{Storage: memorystorage.NewMemoryStorage()}
+	if _, err := Import(ctx, bytes.NewReader(buf.Bytes()), dst); !errs.Is(err, errs.BadRequest) {
+		t.Fatalf("expected a bad request importing sagas without a saga store, got %v", err)
+	}
+	if _, total, _ := dst.Storage.ListWorkflows(ctx, nil); total != 0 {
+		t.Errorf("expected nothing restored, got %d workflows", total)
+	}
+
+	if _, err := Import(ctx, bytes.NewReader([]byte("not an archive")), dst); !errs.Is(err, errs.BadRequest) {
+		t.Errorf("expected a bad request for a non-archive, got %v", err)
+	}
+	truncated := buf.Bytes()[:buf.Len()/2]
+	if _, err := Import(ctx, bytes.NewReader(truncated), dst); !errs.Is(err, errs.BadRequest) {
+		t.Errorf("expected a bad request for a truncated archive, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+// Package stats summarises samples of durations, such as queue waits, task
+// runs and benchmark latencies.
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// Percentile returns the nearest-rank percentile p, in [0, 1], of samples
+// sorted in ascending order, or 0 when there are none.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 10)
+	for i := range samples {
+		samples[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{0.5, 5 * time.Millisecond},
+		{0.51, 6 * time.Millisecond},
+		{0.95, 10 * time.Millisecond},
+		{1, 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(samples, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 0.5); got != 0 {
+		t.Errorf("Percentile() of no samples = %v, want 0", got)
+	}
+}
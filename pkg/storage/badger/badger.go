@@ -4,6 +4,7 @@ package badger
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -18,12 +19,17 @@ type Config struct {
 	SyncWrites        bool
 	ValueLogFileSize  int64
 	NumVersionsToKeep int
+
+	// Encoding is the encoding of new records, EncodingJSON (the default) or
+	// EncodingProtobuf. Records in the other encoding are migrated on open.
+	Encoding string
 }
 
 // BadgerStorage implements the Storage interface using Badger.
 type BadgerStorage struct {
-	db     *badger.DB
-	config *Config
+	db       *badger.DB
+	config   *Config
+	encoding string
 }
 
 // NewBadgerStorage creates a new Badger storage instance.
@@ -33,15 +39,30 @@ func NewBadgerStorage(config *Config) (*BadgerStorage, error) {
 	opts.ValueLogFileSize = config.ValueLogFileSize
 	opts.NumVersionsToKeep = config.NumVersionsToKeep
 
+	encoding := config.Encoding
+	switch encoding {
+	case "":
+		encoding = EncodingJSON
+	case EncodingJSON, EncodingProtobuf:
+	default:
+		return nil, fmt.Errorf("unknown storage encoding %q", encoding)
+	}
+
 	db, err := badger.Open(opts)
 	if err != nil {
 		return nil, &storage.StorageUnavailableError{Cause: err}
 	}
 
-	return &BadgerStorage{
-		db:     db,
-		config: config,
-	}, nil
+	b := &BadgerStorage{
+		db:       db,
+		config:   config,
+		encoding: encoding,
+	}
+	if _, err := b.migrateEncoding(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate records to %s encoding: %w", encoding, err)
+	}
+	return b, nil
 }
 
 // Key generation functions
@@ -61,6 +82,22 @@ func workflowIndexCreatedKey(timestamp time.Time, id string) []byte {
 	return []byte(fmt.Sprintf("workflow:index:created:%d:%s", timestamp.Unix(), id))
 }
 
+// dedupeKey keys the records of dedupe keys outside the "workflow:" keys.
+func dedupeKey(key string) []byte {
+	return []byte("dedupe:" + key)
+}
+
+// resourceKey keys managed resources outside the "workflow:" keys.
+func resourceKey(kind, name string) []byte {
+	return []byte("resource:" + kind + ":" + name)
+}
+
+// outboxKey keys outbox entries outside the "workflow:" keys, so scans and
+// snapshots of workflows skip them.
+func outboxKey(id string) []byte {
+	return []byte(fmt.Sprintf("outbox:%s", id))
+}
+
 // Serialization helpers
 func serialize(v interface{}) ([]byte, error) {
 	data, err := json.Marshal(v)
@@ -85,29 +122,79 @@ func deserialize(data []byte, v interface{}) error {
 
 // SaveWorkflow saves a workflow to Badger.
 func (b *BadgerStorage) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
-	data, err := serialize(wf)
+	var version int64
+	save := func(txn *badger.Txn) error {
+		var err error
+		version, err = b.saveWorkflowInTxn(txn, wf)
+		return err
+	}
+	err := b.db.Update(save)
+	// A concurrent save of the same workflow committed first. Unconditional
+	// saves are retried; versioned ones lost the race.
+	for wf.Version == 0 && errors.Is(err, badger.ErrConflict) {
+		err = b.db.Update(save)
+	}
+	if errors.Is(err, badger.ErrConflict) {
+		conflict := &storage.VersionConflictError{ID: wf.ID, Expected: wf.Version}
+		if stored, err := b.GetWorkflow(ctx, wf.ID); err == nil {
+			conflict.Actual = stored.Version
+		}
+		return conflict
+	}
 	if err != nil {
 		return err
 	}
+	wf.Version = version
+	return nil
+}
 
-	return b.db.Update(func(txn *badger.Txn) error {
-		// Save workflow data
-		if err := txn.Set(workflowKey(wf.ID), data); err != nil {
-			return err
-		}
+// saveWorkflowInTxn checks the version of wf against the stored record and
+// writes wf at the next version, which it returns. wf itself is unchanged.
+func (b *BadgerStorage) saveWorkflowInTxn(txn *badger.Txn, wf *storage.WorkflowState) (int64, error) {
+	var current int64
+	stored, err := b.getWorkflowInTxn(txn, wf.ID)
+	var notFound *storage.NotFoundError
+	switch {
+	case err == nil:
+		current = stored.Version
+	case !errors.As(err, &notFound):
+		return 0, err
+	}
+	version, err := storage.NextVersion(wf, current)
+	if err != nil {
+		return 0, err
+	}
 
-		// Update status index
-		if err := txn.Set(workflowIndexStatusKey(wf.Status, wf.ID), []byte{}); err != nil {
-			return err
-		}
+	saved := *wf
+	saved.Version = version
+	data, err := b.serialize(&saved)
+	if err != nil {
+		return 0, err
+	}
+	if err := setWorkflowInTxn(txn, &saved, data); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
 
-		// Update created time index
-		if err := txn.Set(workflowIndexCreatedKey(wf.CreatedAt, wf.ID), []byte{}); err != nil {
-			return err
-		}
+// setWorkflowInTxn writes a serialized workflow and its index entries.
+func setWorkflowInTxn(txn *badger.Txn, wf *storage.WorkflowState, data []byte) error {
+	// Save workflow data
+	if err := txn.Set(workflowKey(wf.ID), data); err != nil {
+		return err
+	}
 
-		return nil
-	})
+	// Update status index
+	if err := txn.Set(workflowIndexStatusKey(wf.Status, wf.ID), []byte{}); err != nil {
+		return err
+	}
+
+	// Update created time index
+	if err := txn.Set(workflowIndexCreatedKey(wf.CreatedAt, wf.ID), []byte{}); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // GetWorkflow retrieves a workflow by ID.
@@ -127,7 +214,7 @@ func (b *BadgerStorage) GetWorkflow(ctx context.Context, id string) (*storage.Wo
 		}
 
 		return item.Value(func(val []byte) error {
-			return deserialize(val, &wf)
+			return decodeRecord(val, &wf)
 		})
 	})
 
@@ -189,7 +276,7 @@ func (b *BadgerStorage) ListWorkflows(ctx context.Context, filter *storage.Workf
 
 				var wf storage.WorkflowState
 				err := item.Value(func(val []byte) error {
-					return deserialize(val, &wf)
+					return decodeRecord(val, &wf)
 				})
 				if err != nil {
 					continue
@@ -226,6 +313,173 @@ func (b *BadgerStorage) ListWorkflows(ctx context.Context, filter *storage.Workf
 	return workflows, total, nil
 }
 
+// scanChunkSize is the number of keys ScanWorkflows reads per read
+// transaction, so that a slow consumer does not hold one transaction open for
+// the whole scan.
+const scanChunkSize = 256
+
+// ScanWorkflows implements storage.WorkflowScanner. Keys are read in chunks,
+// each in its own read transaction, and workflows are visited in key order.
+func (b *BadgerStorage) ScanWorkflows(ctx context.Context, filter *storage.WorkflowFilter, fn func(*storage.WorkflowState) error) error {
+	prefixes := [][]byte{[]byte("workflow:")}
+	skip, remaining := 0, -1
+	if filter != nil {
+		if len(filter.Status) > 0 {
+			prefixes = prefixes[:0]
+			for _, status := range filter.Status {
+				prefixes = append(prefixes, []byte(fmt.Sprintf("workflow:index:status:%s:", status)))
+			}
+		}
+		skip = filter.Offset
+		if filter.Limit > 0 {
+			remaining = filter.Limit
+		}
+	}
+
+	for _, prefix := range prefixes {
+		indexed := filter != nil && len(filter.Status) > 0
+		seek := prefix
+		for seek != nil && remaining != 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var chunk []*storage.WorkflowState
+			next, err := b.scanChunk(prefix, seek, indexed, &chunk)
+			if err != nil {
+				return err
+			}
+			seek = next
+
+			for _, wf := range chunk {
+				if skip > 0 {
+					skip--
+					continue
+				}
+				if remaining == 0 {
+					break
+				}
+				if err := fn(wf); err != nil {
+					return err
+				}
+				if remaining > 0 {
+					remaining--
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// scanChunk reads the workflows of up to scanChunkSize keys under prefix,
+// starting at seek. It returns the key to continue from, or nil at the end of
+// the prefix. Status index keys are resolved to their workflows, and index
+// entries whose workflow is gone are skipped.
+func (b *BadgerStorage) scanChunk(prefix, seek []byte, indexed bool, chunk *[]*storage.WorkflowState) ([]byte, error) {
+	var next []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		opts.PrefetchValues = false
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		read := 0
+		for it.Seek(seek); it.Valid(); it.Next() {
+			item := it.Item()
+			if read == scanChunkSize {
+				next = item.KeyCopy(nil)
+				return nil
+			}
+			read++
+
+			key := string(item.Key())
+			if indexed {
+				wf, err := b.getWorkflowInTxn(txn, strings.TrimPrefix(key, string(prefix)))
+				if err != nil {
+					continue
+				}
+				*chunk = append(*chunk, wf)
+				continue
+			}
+
+			if strings.Contains(key, ":index:") || strings.Contains(key, ":task:") {
+				continue
+			}
+			var wf storage.WorkflowState
+			if err := item.Value(func(val []byte) error {
+				return decodeRecord(val, &wf)
+			}); err != nil {
+				continue
+			}
+			*chunk = append(*chunk, &wf)
+		}
+		return nil
+	})
+	return next, err
+}
+
+// Snapshot implements storage.Snapshotter. All workflows and tasks are read
+// in one read transaction, in key order, which holds that version of the
+// data until the visit ends.
+func (b *BadgerStorage) Snapshot(ctx context.Context, fn func(wf *storage.WorkflowState, tasks []*storage.TaskState) error) error {
+	return b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte("workflow:")
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			item := it.Item()
+			key := string(item.Key())
+			if strings.Contains(key, ":index:") || strings.Contains(key, ":task:") {
+				continue
+			}
+
+			var wf storage.WorkflowState
+			if err := item.Value(func(val []byte) error {
+				return decodeRecord(val, &wf)
+			}); err != nil {
+				return &storage.SerializationError{Operation: "snapshot workflow " + key, Cause: err}
+			}
+			tasks, err := listTasksInTxn(txn, wf.ID)
+			if err != nil {
+				return err
+			}
+			if err := fn(&wf, tasks); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// listTasksInTxn reads the tasks of a workflow within a transaction.
+func listTasksInTxn(txn *badger.Txn, workflowID string) ([]*storage.TaskState, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = []byte(fmt.Sprintf("workflow:%s:task:", workflowID))
+
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	var tasks []*storage.TaskState
+	for it.Rewind(); it.Valid(); it.Next() {
+		var task storage.TaskState
+		if err := it.Item().Value(func(val []byte) error {
+			return decodeRecord(val, &task)
+		}); err != nil {
+			return nil, &storage.SerializationError{Operation: "snapshot task " + string(it.Item().Key()), Cause: err}
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
 // getWorkflowInTxn retrieves a workflow within a transaction.
 func (b *BadgerStorage) getWorkflowInTxn(txn *badger.Txn, id string) (*storage.WorkflowState, error) {
 	var wf storage.WorkflowState
@@ -242,7 +496,7 @@ func (b *BadgerStorage) getWorkflowInTxn(txn *badger.Txn, id string) (*storage.W
 	}
 
 	err = item.Value(func(val []byte) error {
-		return deserialize(val, &wf)
+		return decodeRecord(val, &wf)
 	})
 
 	if err != nil {
@@ -297,7 +551,7 @@ func (b *BadgerStorage) SaveTask(ctx context.Context, workflowID string, task *s
 		return err
 	}
 
-	data, err := serialize(task)
+	data, err := b.serialize(task)
 	if err != nil {
 		return err
 	}
@@ -307,6 +561,100 @@ func (b *BadgerStorage) SaveTask(ctx context.Context, workflowID string, task *s
 	})
 }
 
+// SaveTasks saves several task states of a workflow in one transaction.
+func (b *BadgerStorage) SaveTasks(ctx context.Context, workflowID string, tasks []*storage.TaskState) error {
+	// Verify workflow exists
+	_, err := b.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*badger.Entry, len(tasks))
+	for i, task := range tasks {
+		data, err := b.serialize(task)
+		if err != nil {
+			return err
+		}
+		entries[i] = badger.NewEntry(taskKey(workflowID, task.ID), data)
+	}
+
+	return b.db.Update(func(txn *badger.Txn) error {
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BeginTx implements storage.Transactional with a Badger read-write
+// transaction.
+func (b *BadgerStorage) BeginTx(ctx context.Context) (storage.Tx, error) {
+	return &badgerTx{b: b, txn: b.db.NewTransaction(true)}, nil
+}
+
+// badgerTx is a storage.Tx over a Badger transaction. Reads inside it see
+// its own writes, so a task can be saved for a workflow saved earlier in the
+// same transaction.
+type badgerTx struct {
+	b   *BadgerStorage
+	txn *badger.Txn
+
+	// saved maps the workflows saved in the transaction to the versions they
+	// get on Commit.
+	saved map[*storage.WorkflowState]int64
+}
+
+func (t *badgerTx) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	version, err := t.b.saveWorkflowInTxn(t.txn, wf)
+	if err != nil {
+		return err
+	}
+	if t.saved == nil {
+		t.saved = make(map[*storage.WorkflowState]int64)
+	}
+	t.saved[wf] = version
+	return nil
+}
+
+func (t *badgerTx) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	if _, err := t.b.getWorkflowInTxn(t.txn, workflowID); err != nil {
+		return err
+	}
+	data, err := t.b.serialize(task)
+	if err != nil {
+		return err
+	}
+	return t.txn.Set(taskKey(workflowID, task.ID), data)
+}
+
+// SaveOutbox implements storage.OutboxWriter.
+func (t *badgerTx) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	data, err := serialize(entry)
+	if err != nil {
+		return err
+	}
+	return t.txn.Set(outboxKey(entry.ID), data)
+}
+
+func (t *badgerTx) Commit() error {
+	if err := t.txn.Commit(); err != nil {
+		return err
+	}
+	for wf, version := range t.saved {
+		wf.Version = version
+	}
+	return nil
+}
+
+// Rollback discards the transaction. Badger's Discard is a no-op after
+// Commit.
+func (t *badgerTx) Rollback() error {
+	t.txn.Discard()
+	return nil
+}
+
 // GetTask retrieves a task by workflow ID and task ID.
 func (b *BadgerStorage) GetTask(ctx context.Context, workflowID, taskID string) (*storage.TaskState, error) {
 	var task storage.TaskState
@@ -324,7 +672,7 @@ func (b *BadgerStorage) GetTask(ctx context.Context, workflowID, taskID string)
 		}
 
 		return item.Value(func(val []byte) error {
-			return deserialize(val, &task)
+			return decodeRecord(val, &task)
 		})
 	})
 
@@ -358,7 +706,7 @@ func (b *BadgerStorage) ListTasks(ctx context.Context, workflowID string) ([]*st
 
 			var task storage.TaskState
 			err := item.Value(func(val []byte) error {
-				return deserialize(val, &task)
+				return decodeRecord(val, &task)
 			})
 			if err != nil {
 				continue
@@ -377,6 +725,137 @@ func (b *BadgerStorage) ListTasks(ctx context.Context, workflowID string) ([]*st
 	return tasks, nil
 }
 
+// SaveOutbox implements storage.Outbox. Entries are always stored as JSON.
+func (b *BadgerStorage) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	data, err := serialize(entry)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(outboxKey(entry.ID), data)
+	})
+}
+
+// ListOutbox implements storage.Outbox.
+func (b *BadgerStorage) ListOutbox(ctx context.Context, limit int) ([]*storage.OutboxEntry, error) {
+	var entries []*storage.OutboxEntry
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte("outbox:")
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if limit > 0 && len(entries) >= limit {
+				break
+			}
+			var entry storage.OutboxEntry
+			if err := it.Item().Value(func(val []byte) error {
+				return deserialize(val, &entry)
+			}); err != nil {
+				return err
+			}
+			entries = append(entries, &entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DeleteOutbox implements storage.Outbox.
+func (b *BadgerStorage) DeleteOutbox(ctx context.Context, id string) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(outboxKey(id))
+	})
+}
+
+// SaveDedupe implements storage.DedupeStore. Records are always stored as
+// JSON, and expire with their ExpiresAt.
+func (b *BadgerStorage) SaveDedupe(ctx context.Context, record *storage.DedupeRecord) error {
+	data, err := serialize(record)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(record.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry(dedupeKey(record.Key), data).WithTTL(ttl))
+	})
+}
+
+// GetDedupe implements storage.DedupeStore.
+func (b *BadgerStorage) GetDedupe(ctx context.Context, key string) (*storage.DedupeRecord, error) {
+	var record storage.DedupeRecord
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(dedupeKey(key))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			return deserialize(val, &record)
+		})
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, &storage.NotFoundError{EntityType: "dedupe", ID: key}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SaveResource implements storage.ResourceStore. Records are always stored as
+// JSON.
+func (b *BadgerStorage) SaveResource(ctx context.Context, record *storage.ResourceRecord) error {
+	data, err := serialize(record)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(resourceKey(record.Kind, record.Name), data)
+	})
+}
+
+// DeleteResource implements storage.ResourceStore.
+func (b *BadgerStorage) DeleteResource(ctx context.Context, kind, name string) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(resourceKey(kind, name))
+	})
+}
+
+// ListResources implements storage.ResourceStore.
+func (b *BadgerStorage) ListResources(ctx context.Context, kind string) ([]*storage.ResourceRecord, error) {
+	var records []*storage.ResourceRecord
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = resourceKey(kind, "")
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var record storage.ResourceRecord
+			if err := it.Item().Value(func(val []byte) error {
+				return deserialize(val, &record)
+			}); err != nil {
+				return err
+			}
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 // Close closes the Badger database.
 func (b *BadgerStorage) Close() error {
 	// Run garbage collection before closing
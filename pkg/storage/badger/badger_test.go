@@ -2,6 +2,7 @@ package badger
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -442,3 +443,59 @@ func TestBadgerStorage_UpdateWorkflow(t *testing.T) {
 		t.Error("Expected StartedAt to be set")
 	}
 }
+
+func TestBadgerStorage_ScanWorkflows_AcrossChunks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Each workflow also has a task key, so a full scan reads two keys per
+	// workflow and has to continue over several chunks.
+	const n = scanChunkSize + 50
+	for i := 0; i < n; i++ {
+		wf := &storage.WorkflowState{
+			ID:        fmt.Sprintf("wf-%04d", i),
+			Name:      "workflow",
+			Status:    "pending",
+			CreatedAt: time.Now(),
+		}
+		if err := db.SaveWorkflow(ctx, wf); err != nil {
+			t.Fatalf("SaveWorkflow failed: %v", err)
+		}
+		if err := db.SaveTask(ctx, wf.ID, &storage.TaskState{ID: "t", Status: "pending"}); err != nil {
+			t.Fatalf("SaveTask failed: %v", err)
+		}
+	}
+
+	for _, filter := range []*storage.WorkflowFilter{nil, {Status: []string{"pending"}}} {
+		var ids []string
+		err := db.ScanWorkflows(ctx, filter, func(wf *storage.WorkflowState) error {
+			ids = append(ids, wf.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ScanWorkflows failed: %v", err)
+		}
+		if len(ids) != n {
+			t.Fatalf("expected %d workflows, got %d", n, len(ids))
+		}
+		for i, id := range ids {
+			if want := fmt.Sprintf("wf-%04d", i); id != want {
+				t.Fatalf("workflow %d = %s, want %s", i, id, want)
+			}
+		}
+	}
+
+	var ids []string
+	err := db.ScanWorkflows(ctx, &storage.WorkflowFilter{Offset: scanChunkSize - 1, Limit: 3}, func(wf *storage.WorkflowState) error {
+		ids = append(ids, wf.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanWorkflows failed: %v", err)
+	}
+	if want := fmt.Sprintf("wf-%04d", scanChunkSize-1); len(ids) != 3 || ids[0] != want {
+		t.Fatalf("expected 3 workflows from %s, got %v", want, ids)
+	}
+}
@@ -0,0 +1,567 @@
+package badger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+	storagepbv1 "github.com/goclaw/goclaw/pkg/storage/pb/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Record encodings.
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// protobufMarker prefixes protobuf-encoded records. JSON records start with
+// '{', so the first byte tells the encodings apart and both can be read
+// whatever the configured encoding.
+const protobufMarker byte = 0x01
+
+// encodingKey records the encoding every record was last migrated to.
+var encodingKey = []byte("meta:encoding")
+
+// serialize encodes a *storage.WorkflowState or *storage.TaskState in the
+// configured encoding.
+func (b *BadgerStorage) serialize(v interface{}) ([]byte, error) {
+	if b.encoding != EncodingProtobuf {
+		return serialize(v)
+	}
+
+	var msg proto.Message
+	var err error
+	switch v := v.(type) {
+	case *storage.WorkflowState:
+		msg, err = workflowToProto(v)
+	case *storage.TaskState:
+		msg, err = taskToProto(v)
+	default:
+		err = fmt.Errorf("cannot encode %T as protobuf", v)
+	}
+	if err != nil {
+		return nil, &storage.SerializationError{Operation: "marshal", Cause: err}
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, &storage.SerializationError{Operation: "marshal", Cause: err}
+	}
+	return append([]byte{protobufMarker}, data...), nil
+}
+
+// decodeRecord decodes a workflow or task record in either encoding.
+func decodeRecord(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] != protobufMarker {
+		return deserialize(data, v)
+	}
+
+	var err error
+	switch v := v.(type) {
+	case *storage.WorkflowState:
+		var msg storagepbv1.WorkflowState
+		if err = proto.Unmarshal(data[1:], &msg); err == nil {
+			err = workflowFromProto(&msg, v)
+		}
+	case *storage.TaskState:
+		var msg storagepbv1.TaskState
+		if err = proto.Unmarshal(data[1:], &msg); err == nil {
+			err = taskFromProto(&msg, v)
+		}
+	default:
+		err = fmt.Errorf("cannot decode protobuf into %T", v)
+	}
+	if err != nil {
+		return &storage.SerializationError{Operation: "unmarshal", Cause: err}
+	}
+	return nil
+}
+
+// migrateEncoding rewrites the workflow and task records that are not in the
+// configured encoding and returns how many it rewrote. It is skipped once the
+// database has been migrated to the configured encoding.
+func (b *BadgerStorage) migrateEncoding() (int, error) {
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(encodingKey)
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			if string(val) != b.encoding {
+				return badger.ErrKeyNotFound
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return 0, nil
+	}
+	if err != badger.ErrKeyNotFound {
+		return 0, err
+	}
+
+	wb := b.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	migrated := 0
+	err = b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte("workflow:")
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+			if strings.Contains(key, ":index:") {
+				continue
+			}
+
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if len(val) == 0 || (val[0] == protobufMarker) == (b.encoding == EncodingProtobuf) {
+				continue
+			}
+
+			var record interface{} = &storage.WorkflowState{}
+			if strings.Contains(key, ":task:") {
+				record = &storage.TaskState{}
+			}
+			if err := decodeRecord(val, record); err != nil {
+				return fmt.Errorf("failed to migrate %s: %w", key, err)
+			}
+			data, err := b.serialize(record)
+			if err != nil {
+				return fmt.Errorf("failed to migrate %s: %w", key, err)
+			}
+			if err := wb.Set(item.KeyCopy(nil), data); err != nil {
+				return err
+			}
+			migrated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := wb.Set(encodingKey, []byte(b.encoding)); err != nil {
+		return 0, err
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, err
+	}
+	return migrated, nil
+}
+
+func workflowToProto(wf *storage.WorkflowState) (*storagepbv1.WorkflowState, error) {
+	msg := &storagepbv1.WorkflowState{
+		Id:            wf.ID,
+		Name:          wf.Name,
+		Description:   wf.Description,
+		Status:        wf.Status,
+		Metadata:      wf.Metadata,
+		CreatedAt:     timestamppb.New(wf.CreatedAt),
+		StartedAt:     timeToProto(wf.StartedAt),
+		CompletedAt:   timeToProto(wf.CompletedAt),
+		Error:         wf.Error,
+		Priority:      int32(wf.Priority),
+		Deadline:      timeToProto(wf.Deadline),
+		GangLayers:    wf.GangLayers,
+		SlaDeadline:   timeToProto(wf.SLADeadline),
+		SlaBreachedAt: timeToProto(wf.SLABreachedAt),
+		Usage:         wf.Usage,
+		RequestId:     wf.RequestID,
+		CallbackUrl:   wf.CallbackURL,
+		Version:       wf.Version,
+		Values:        wf.Values,
+	}
+	if wf.Budget != nil {
+		msg.Budget = &storagepbv1.Budget{
+			Tokens:      wf.Budget.Tokens,
+			Cost:        wf.Budget.Cost,
+			TaskSeconds: wf.Budget.TaskSeconds,
+		}
+	}
+	for i := range wf.Tasks {
+		def, err := taskDefinitionToProto(&wf.Tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		msg.Tasks = append(msg.Tasks, def)
+	}
+	if wf.TaskStatus != nil {
+		msg.TaskStatus = make(map[string]*storagepbv1.TaskState, len(wf.TaskStatus))
+		for id, task := range wf.TaskStatus {
+			taskMsg, err := taskToProto(task)
+			if err != nil {
+				return nil, err
+			}
+			msg.TaskStatus[id] = taskMsg
+		}
+	}
+	for i := range wf.Journal {
+		entry, err := journalEntryToProto(&wf.Journal[i])
+		if err != nil {
+			return nil, err
+		}
+		msg.Journal = append(msg.Journal, entry)
+	}
+	return msg, nil
+}
+
+func workflowFromProto(msg *storagepbv1.WorkflowState, wf *storage.WorkflowState) error {
+	*wf = storage.WorkflowState{
+		ID:            msg.Id,
+		Name:          msg.Name,
+		Description:   msg.Description,
+		Status:        msg.Status,
+		Metadata:      msg.Metadata,
+		CreatedAt:     timeFromProto(msg.CreatedAt),
+		StartedAt:     optionalTimeFromProto(msg.StartedAt),
+		CompletedAt:   optionalTimeFromProto(msg.CompletedAt),
+		Error:         msg.Error,
+		Priority:      int(msg.Priority),
+		Deadline:      optionalTimeFromProto(msg.Deadline),
+		GangLayers:    msg.GangLayers,
+		SLADeadline:   optionalTimeFromProto(msg.SlaDeadline),
+		SLABreachedAt: optionalTimeFromProto(msg.SlaBreachedAt),
+		Usage:         msg.Usage,
+		RequestID:     msg.RequestId,
+		CallbackURL:   msg.CallbackUrl,
+		Version:       msg.Version,
+		Values:        msg.Values,
+	}
+	if msg.Budget != nil {
+		wf.Budget = &models.Budget{
+			Tokens:      msg.Budget.Tokens,
+			Cost:        msg.Budget.Cost,
+			TaskSeconds: msg.Budget.TaskSeconds,
+		}
+	}
+	for _, def := range msg.Tasks {
+		task, err := taskDefinitionFromProto(def)
+		if err != nil {
+			return err
+		}
+		wf.Tasks = append(wf.Tasks, task)
+	}
+	// Protobuf does not tell an empty map from a missing one; the engine
+	// expects the task map to be writable.
+	wf.TaskStatus = make(map[string]*storage.TaskState, len(msg.TaskStatus))
+	for id, taskMsg := range msg.TaskStatus {
+		var task storage.TaskState
+		if err := taskFromProto(taskMsg, &task); err != nil {
+			return err
+		}
+		wf.TaskStatus[id] = &task
+	}
+	for _, entryMsg := range msg.Journal {
+		var entry storage.JournalEntry
+		if err := journalEntryFromProto(entryMsg, &entry); err != nil {
+			return err
+		}
+		wf.Journal = append(wf.Journal, entry)
+	}
+	return nil
+}
+
+func journalEntryToProto(entry *storage.JournalEntry) (*storagepbv1.JournalEntry, error) {
+	msg := &storagepbv1.JournalEntry{
+		Seq:    int32(entry.Seq),
+		TaskId: entry.TaskID,
+		From:   entry.From,
+		To:     entry.To,
+		Error:  entry.Error,
+		At:     timestamppb.New(entry.At),
+	}
+	if entry.Inputs != nil {
+		inputs, err := json.Marshal(entry.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("journal entry %d inputs: %w", entry.Seq, err)
+		}
+		msg.InputsJson = inputs
+	}
+	if entry.Output != nil {
+		output, err := json.Marshal(entry.Output)
+		if err != nil {
+			return nil, fmt.Errorf("journal entry %d output: %w", entry.Seq, err)
+		}
+		msg.OutputJson = output
+	}
+	for _, decision := range entry.Decisions {
+		msg.Decisions = append(msg.Decisions, &storagepbv1.JournalDecision{
+			Kind:      decision.Kind,
+			ValueJson: decision.Value,
+		})
+	}
+	return msg, nil
+}
+
+func journalEntryFromProto(msg *storagepbv1.JournalEntry, entry *storage.JournalEntry) error {
+	*entry = storage.JournalEntry{
+		Seq:    int(msg.Seq),
+		TaskID: msg.TaskId,
+		From:   msg.From,
+		To:     msg.To,
+		Error:  msg.Error,
+		At:     timeFromProto(msg.At),
+	}
+	if len(msg.InputsJson) > 0 {
+		if err := json.Unmarshal(msg.InputsJson, &entry.Inputs); err != nil {
+			return fmt.Errorf("journal entry %d inputs: %w", msg.Seq, err)
+		}
+	}
+	if len(msg.OutputJson) > 0 {
+		if err := json.Unmarshal(msg.OutputJson, &entry.Output); err != nil {
+			return fmt.Errorf("journal entry %d output: %w", msg.Seq, err)
+		}
+	}
+	for _, decision := range msg.Decisions {
+		entry.Decisions = append(entry.Decisions, storage.JournalDecision{
+			Kind:  decision.Kind,
+			Value: decision.ValueJson,
+		})
+	}
+	return nil
+}
+
+func taskDefinitionToProto(def *models.TaskDefinition) (*storagepbv1.TaskDefinition, error) {
+	msg := &storagepbv1.TaskDefinition{
+		Id:               def.ID,
+		Name:             def.Name,
+		Type:             def.Type,
+		DependsOn:        def.DependsOn,
+		Timeout:          int32(def.Timeout),
+		Retries:          int32(def.Retries),
+		DedupeKey:        def.DedupeKey,
+		Preemptible:      def.Preemptible,
+		Deadline:         int32(def.Deadline),
+		Gang:             def.Gang,
+		AffinityKey:      def.AffinityKey,
+		HeartbeatTimeout: int32(def.HeartbeatTimeout),
+		StallPolicy:      def.StallPolicy,
+		Env:              def.Env,
+		Secrets:          def.Secrets,
+		Locks:            def.Locks,
+		RateLimits:       def.RateLimits,
+		When:             def.When,
+		RetryIf:          def.RetryIf,
+		Sensitive:        def.Sensitive,
+	}
+	if def.Config != nil {
+		config, err := json.Marshal(def.Config)
+		if err != nil {
+			return nil, fmt.Errorf("task %s config: %w", def.ID, err)
+		}
+		msg.ConfigJson = config
+	}
+	if def.OutputSchema != nil {
+		schema, err := json.Marshal(def.OutputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("task %s output schema: %w", def.ID, err)
+		}
+		msg.OutputSchemaJson = schema
+	}
+	if def.Resources != nil {
+		msg.Resources = &storagepbv1.TaskResources{
+			Cpu:      def.Resources.CPU,
+			MemoryMb: def.Resources.MemoryMB,
+			Gpu:      int32(def.Resources.GPU),
+		}
+	}
+	msg.Container = containerToProto(def.Container)
+	if comp := def.Compensation; comp != nil {
+		msg.Compensation = &storagepbv1.TaskCompensation{
+			Type:      comp.Type,
+			Container: containerToProto(comp.Container),
+			Timeout:   int32(comp.Timeout),
+		}
+		if comp.Config != nil {
+			config, err := json.Marshal(comp.Config)
+			if err != nil {
+				return nil, fmt.Errorf("task %s compensation config: %w", def.ID, err)
+			}
+			msg.Compensation.ConfigJson = config
+		}
+	}
+	return msg, nil
+}
+
+func containerToProto(spec *models.ContainerSpec) *storagepbv1.ContainerSpec {
+	if spec == nil {
+		return nil
+	}
+	return &storagepbv1.ContainerSpec{
+		Image:   spec.Image,
+		Command: spec.Command,
+		Args:    spec.Args,
+		Env:     spec.Env,
+	}
+}
+
+func containerFromProto(msg *storagepbv1.ContainerSpec) *models.ContainerSpec {
+	if msg == nil {
+		return nil
+	}
+	return &models.ContainerSpec{
+		Image:   msg.Image,
+		Command: msg.Command,
+		Args:    msg.Args,
+		Env:     msg.Env,
+	}
+}
+
+func taskDefinitionFromProto(msg *storagepbv1.TaskDefinition) (models.TaskDefinition, error) {
+	def := models.TaskDefinition{
+		ID:               msg.Id,
+		Name:             msg.Name,
+		Type:             msg.Type,
+		DependsOn:        msg.DependsOn,
+		Timeout:          int(msg.Timeout),
+		Retries:          int(msg.Retries),
+		DedupeKey:        msg.DedupeKey,
+		Preemptible:      msg.Preemptible,
+		Deadline:         int(msg.Deadline),
+		Gang:             msg.Gang,
+		AffinityKey:      msg.AffinityKey,
+		HeartbeatTimeout: int(msg.HeartbeatTimeout),
+		StallPolicy:      msg.StallPolicy,
+		Env:              msg.Env,
+		Secrets:          msg.Secrets,
+		Locks:            msg.Locks,
+		RateLimits:       msg.RateLimits,
+		When:             msg.When,
+		RetryIf:          msg.RetryIf,
+		Sensitive:        msg.Sensitive,
+	}
+	if len(msg.ConfigJson) > 0 {
+		if err := json.Unmarshal(msg.ConfigJson, &def.Config); err != nil {
+			return def, fmt.Errorf("task %s config: %w", msg.Id, err)
+		}
+	}
+	if len(msg.OutputSchemaJson) > 0 {
+		if err := json.Unmarshal(msg.OutputSchemaJson, &def.OutputSchema); err != nil {
+			return def, fmt.Errorf("task %s output schema: %w", msg.Id, err)
+		}
+	}
+	if msg.Resources != nil {
+		def.Resources = &models.TaskResources{
+			CPU:      msg.Resources.Cpu,
+			MemoryMB: msg.Resources.MemoryMb,
+			GPU:      int(msg.Resources.Gpu),
+		}
+	}
+	def.Container = containerFromProto(msg.Container)
+	if comp := msg.Compensation; comp != nil {
+		def.Compensation = &models.TaskCompensation{
+			Type:      comp.Type,
+			Container: containerFromProto(comp.Container),
+			Timeout:   int(comp.Timeout),
+		}
+		if len(comp.ConfigJson) > 0 {
+			if err := json.Unmarshal(comp.ConfigJson, &def.Compensation.Config); err != nil {
+				return def, fmt.Errorf("task %s compensation config: %w", msg.Id, err)
+			}
+		}
+	}
+	return def, nil
+}
+
+func taskToProto(task *storage.TaskState) (*storagepbv1.TaskState, error) {
+	msg := &storagepbv1.TaskState{
+		Id:             task.ID,
+		Name:           task.Name,
+		Status:         task.Status,
+		StartedAt:      timeToProto(task.StartedAt),
+		CompletedAt:    timeToProto(task.CompletedAt),
+		Error:          task.Error,
+		DedupeKey:      task.DedupeKey,
+		DedupedFrom:    task.DedupedFrom,
+		Preemptions:    int32(task.Preemptions),
+		DeadlineMissed: task.DeadlineMissed,
+		Stalls:         int32(task.Stalls),
+		Skipped:        task.Skipped,
+		Sensitive:      task.Sensitive,
+	}
+	if task.Result != nil {
+		result, err := json.Marshal(task.Result)
+		if err != nil {
+			return nil, fmt.Errorf("task %s result: %w", task.ID, err)
+		}
+		msg.ResultJson = result
+	}
+	for _, input := range task.Inputs {
+		msg.Inputs = append(msg.Inputs, &storagepbv1.TaskInput{
+			WorkflowId: input.WorkflowID,
+			TaskId:     input.TaskID,
+			Artifact:   input.Artifact,
+		})
+	}
+	return msg, nil
+}
+
+func taskFromProto(msg *storagepbv1.TaskState, task *storage.TaskState) error {
+	*task = storage.TaskState{
+		ID:             msg.Id,
+		Name:           msg.Name,
+		Status:         msg.Status,
+		StartedAt:      optionalTimeFromProto(msg.StartedAt),
+		CompletedAt:    optionalTimeFromProto(msg.CompletedAt),
+		Error:          msg.Error,
+		DedupeKey:      msg.DedupeKey,
+		DedupedFrom:    msg.DedupedFrom,
+		Preemptions:    int(msg.Preemptions),
+		DeadlineMissed: msg.DeadlineMissed,
+		Stalls:         int(msg.Stalls),
+		Skipped:        msg.Skipped,
+		Sensitive:      msg.Sensitive,
+	}
+	if len(msg.ResultJson) > 0 {
+		if err := json.Unmarshal(msg.ResultJson, &task.Result); err != nil {
+			return fmt.Errorf("task %s result: %w", msg.Id, err)
+		}
+	}
+	for _, input := range msg.Inputs {
+		task.Inputs = append(task.Inputs, storage.TaskInput{
+			WorkflowID: input.WorkflowId,
+			TaskID:     input.TaskId,
+			Artifact:   input.Artifact,
+		})
+	}
+	return nil
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timeFromProto converts ts, mapping the zero time back to time.Time{}.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	t := ts.AsTime()
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t
+}
+
+func optionalTimeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := timeFromProto(ts)
+	return &t
+}
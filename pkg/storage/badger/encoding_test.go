@@ -0,0 +1,184 @@
+package badger
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// TestBadgerStorageSuite_Protobuf runs the full storage test suite against
+// BadgerStorage with protobuf encoding.
+func TestBadgerStorageSuite_Protobuf(t *testing.T) {
+	suite := &storage.StorageTestSuite{
+		NewStorage: func(t *testing.T) storage.Storage {
+			db, err := NewBadgerStorage(&Config{
+				Path:              t.TempDir(),
+				ValueLogFileSize:  1 << 20,
+				NumVersionsToKeep: 1,
+				Encoding:          EncodingProtobuf,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create BadgerStorage: %v", err)
+			}
+			return db
+		},
+	}
+
+	suite.RunAllTests(t)
+}
+
+func sampleWorkflow() *storage.WorkflowState {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	started := created.Add(time.Second)
+	deadline := created.Add(time.Hour)
+	return &storage.WorkflowState{
+		ID:          "wf-enc",
+		Name:        "encoding",
+		Description: "all fields",
+		Status:      "running",
+		Tasks: []models.TaskDefinition{
+			{ID: "a", Name: "A", Type: "function", Config: map[string]interface{}{"url": "https://example.com", "n": float64(3)}, Retries: 2,
+				OutputSchema: map[string]interface{}{"type": "object", "required": []interface{}{"rows"}}},
+			{ID: "b", Name: "B", Type: "function", DependsOn: []string{"a"}, Gang: "g", Deadline: 30, Locks: []string{"billing-db"}, RateLimits: []string{"github-api"},
+				When: `tasks.a.result.rows > 0`, RetryIf: `error.contains("503")`, Resources: &models.TaskResources{CPU: 0.5, MemoryMB: 512, GPU: 1}},
+			{ID: "c", Name: "C", Type: "container", DependsOn: []string{"b"},
+				Container: &models.ContainerSpec{Image: "alpine:3", Args: []string{"echo", "hi"}, Env: map[string]string{"MODE": "fast"}},
+				Compensation: &models.TaskCompensation{Type: "container", Config: map[string]interface{}{"order": "${{ tasks.a.result.rows }}"}, Timeout: 60,
+					Container: &models.ContainerSpec{Image: "alpine:3", Command: []string{"sh", "-c", "rollback"}}}},
+		},
+		TaskStatus: map[string]*storage.TaskState{
+			"a": {ID: "a", Name: "A", Status: "completed", StartedAt: &started, CompletedAt: &started,
+				Result: map[string]interface{}{"rows": float64(42)}, Preemptions: 1, Skipped: true,
+				Inputs: []storage.TaskInput{{WorkflowID: "wf-0", TaskID: "load", Artifact: "rows.csv"}}},
+		},
+		Metadata:   map[string]string{"team": "data"},
+		CreatedAt:  created,
+		StartedAt:  &started,
+		Priority:   7,
+		Deadline:   &deadline,
+		GangLayers: true,
+		Journal: []storage.JournalEntry{
+			{Seq: 1, TaskID: "a", From: "pending", To: "scheduled", At: started},
+			{Seq: 2, TaskID: "a", From: "running", To: "failed", Error: "boom", At: started},
+			{Seq: 3, TaskID: "a", From: "running", To: "completed", At: started,
+				Inputs:    map[string]any{"load": "rows.csv"},
+				Output:    map[string]any{"rows": float64(42)},
+				Decisions: []storage.JournalDecision{{Kind: "random", Value: json.RawMessage(`0.25`)}}},
+		},
+		SLADeadline:   &deadline,
+		SLABreachedAt: &started,
+		Usage:         map[string]float64{"task_seconds:default": 1.5, "llm_tokens": 1200},
+		RequestID:     "req-1",
+		CallbackURL:   "https://example.com/done",
+		Budget:        &models.Budget{Tokens: 50000, Cost: 2.5},
+		Version:       3,
+		Values:        map[string]string{"schema": "v2"},
+	}
+}
+
+func TestEncoding_ProtobufRoundTrip(t *testing.T) {
+	b := &BadgerStorage{encoding: EncodingProtobuf}
+	wf := sampleWorkflow()
+
+	data, err := b.serialize(wf)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+	jsonData, _ := json.Marshal(wf)
+	if data[0] != protobufMarker || len(data) >= len(jsonData) {
+		t.Fatalf("expected a marked record smaller than JSON (%d bytes), got %d bytes", len(jsonData), len(data))
+	}
+
+	var decoded storage.WorkflowState
+	if err := decodeRecord(data, &decoded); err != nil {
+		t.Fatalf("decodeRecord failed: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, wf) {
+		t.Fatalf("round trip mismatch:\ngot  %+v\nwant %+v", decoded, *wf)
+	}
+
+	var fromJSON storage.WorkflowState
+	if err := decodeRecord(jsonData, &fromJSON); err != nil || fromJSON.ID != wf.ID {
+		t.Fatalf("expected JSON records to stay readable, got %+v, %v", fromJSON, err)
+	}
+}
+
+func TestEncoding_MigratesExistingRecords(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	open := func(encoding string) *BadgerStorage {
+		t.Helper()
+		db, err := NewBadgerStorage(&Config{Path: dir, ValueLogFileSize: 1 << 20, NumVersionsToKeep: 1, Encoding: encoding})
+		if err != nil {
+			t.Fatalf("NewBadgerStorage(%s) failed: %v", encoding, err)
+		}
+		return db
+	}
+	firstByte := func(db *BadgerStorage, key []byte) byte {
+		t.Helper()
+		var first byte
+		err := db.db.View(func(txn *badger.Txn) error {
+			item, err := txn.Get(key)
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				first = val[0]
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", key, err)
+		}
+		return first
+	}
+
+	db := open(EncodingJSON)
+	wf := sampleWorkflow()
+	wf.Version = 0
+	if err := db.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if err := db.SaveTask(ctx, wf.ID, wf.TaskStatus["a"]); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	db.Close()
+
+	db = open(EncodingProtobuf)
+	if firstByte(db, workflowKey(wf.ID)) != protobufMarker || firstByte(db, taskKey(wf.ID, "a")) != protobufMarker {
+		t.Fatal("expected JSON records to be migrated to protobuf")
+	}
+	if migrated, err := db.migrateEncoding(); err != nil || migrated != 0 {
+		t.Fatalf("expected no second migration, got %d, %v", migrated, err)
+	}
+	got, err := db.GetWorkflow(ctx, wf.ID)
+	if err != nil || !reflect.DeepEqual(got, wf) {
+		t.Fatalf("migrated workflow mismatch: %+v, %v", got, err)
+	}
+	workflows, total, err := db.ListWorkflows(ctx, &storage.WorkflowFilter{Status: []string{"running"}})
+	if err != nil || total != 1 || workflows[0].ID != wf.ID {
+		t.Fatalf("expected the migrated workflow in listings, got %d, %v", total, err)
+	}
+	db.Close()
+
+	db = open(EncodingJSON)
+	defer db.Close()
+	if firstByte(db, workflowKey(wf.ID)) != '{' {
+		t.Fatal("expected records to be migrated back to JSON")
+	}
+	if task, err := db.GetTask(ctx, wf.ID, "a"); err != nil || task.Status != "completed" {
+		t.Fatalf("migrated task mismatch: %+v, %v", task, err)
+	}
+}
+
+func TestNewBadgerStorage_UnknownEncoding(t *testing.T) {
+	if _, err := NewBadgerStorage(&Config{Path: t.TempDir(), ValueLogFileSize: 1 << 20, Encoding: "xml"}); err == nil {
+		t.Fatal("expected unknown encoding to be rejected")
+	}
+}
@@ -0,0 +1,428 @@
+// Package cache provides a read-through LRU cache in front of a storage
+// backend, so that clients polling workflow and task status do not hit the
+// backend for records that have not changed.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// CachedStorage wraps a Storage and caches the results of GetWorkflow and
+// GetTask. Entries are invalidated by writes made through it and by
+// Invalidate, which the engine calls on every state change it emits.
+// Listings are not cached.
+type CachedStorage struct {
+	storage.Storage
+
+	mu       sync.Mutex
+	maxSize  int
+	items    map[string]*list.Element
+	eviction *list.List
+	hits     int64
+	misses   int64
+}
+
+// cacheItem is an LRU entry. An item without a value is a placeholder for a
+// read in progress; invalidating the key removes it, which tells the reader
+// that what it read may already be stale.
+type cacheItem struct {
+	key      string
+	workflow *storage.WorkflowState
+	task     *storage.TaskState
+	loaded   bool
+}
+
+// NewCachedStorage wraps store with a cache of at most maxSize records.
+func NewCachedStorage(store storage.Storage, maxSize int) *CachedStorage {
+	return &CachedStorage{
+		Storage:  store,
+		maxSize:  max(maxSize, 1),
+		items:    make(map[string]*list.Element),
+		eviction: list.New(),
+	}
+}
+
+func workflowKey(id string) string {
+	return "workflow:" + id
+}
+
+func taskKey(workflowID, taskID string) string {
+	return "task:" + workflowID + ":" + taskID
+}
+
+// GetWorkflow returns the workflow from the cache, reading it from the
+// wrapped storage on a miss.
+func (c *CachedStorage) GetWorkflow(ctx context.Context, id string) (*storage.WorkflowState, error) {
+	key := workflowKey(id)
+	item, placeholder := c.lookup(key)
+	if item != nil {
+		return cloneWorkflow(item.workflow), nil
+	}
+	wf, err := c.Storage.GetWorkflow(ctx, id)
+	if err != nil {
+		c.drop(placeholder)
+		return nil, err
+	}
+	c.fill(placeholder, &cacheItem{workflow: cloneWorkflow(wf)})
+	return wf, nil
+}
+
+// GetTask returns the task from the cache, reading it from the wrapped
+// storage on a miss.
+func (c *CachedStorage) GetTask(ctx context.Context, workflowID, taskID string) (*storage.TaskState, error) {
+	key := taskKey(workflowID, taskID)
+	item, placeholder := c.lookup(key)
+	if item != nil {
+		task := *item.task
+		return &task, nil
+	}
+	task, err := c.Storage.GetTask(ctx, workflowID, taskID)
+	if err != nil {
+		c.drop(placeholder)
+		return nil, err
+	}
+	copied := *task
+	c.fill(placeholder, &cacheItem{task: &copied})
+	return task, nil
+}
+
+// SaveWorkflow saves the workflow and invalidates its cached record.
+func (c *CachedStorage) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	defer c.invalidate(workflowKey(wf.ID))
+	return c.Storage.SaveWorkflow(ctx, wf)
+}
+
+// DeleteWorkflow deletes the workflow and invalidates it and its tasks.
+func (c *CachedStorage) DeleteWorkflow(ctx context.Context, id string) error {
+	defer c.Invalidate(id, "")
+	return c.Storage.DeleteWorkflow(ctx, id)
+}
+
+// SaveTask saves the task and invalidates it and its workflow, whose record
+// may embed the task's status.
+func (c *CachedStorage) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	defer c.invalidate(workflowKey(workflowID), taskKey(workflowID, task.ID))
+	return c.Storage.SaveTask(ctx, workflowID, task)
+}
+
+// SaveTasks implements storage.TaskBatchSaver, falling back to SaveTask when
+// the wrapped storage cannot save batches.
+func (c *CachedStorage) SaveTasks(ctx context.Context, workflowID string, tasks []*storage.TaskState) error {
+	keys := make([]string, 0, len(tasks)+1)
+	keys = append(keys, workflowKey(workflowID))
+	for _, task := range tasks {
+		keys = append(keys, taskKey(workflowID, task.ID))
+	}
+	defer c.invalidate(keys...)
+
+	if saver, ok := c.Storage.(storage.TaskBatchSaver); ok {
+		return saver.SaveTasks(ctx, workflowID, tasks)
+	}
+	for _, task := range tasks {
+		if err := c.Storage.SaveTask(ctx, workflowID, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BeginTx implements storage.Transactional. The transaction buffers its
+// writes and applies them with storage.WriteBatch on the wrapped storage, so
+// they are atomic when that storage is transactional. The written records are
+// invalidated on Commit.
+func (c *CachedStorage) BeginTx(ctx context.Context) (storage.Tx, error) {
+	return &cachedTx{c: c, ctx: ctx}, nil
+}
+
+// cachedTx is the storage.Tx of a CachedStorage.
+type cachedTx struct {
+	c      *CachedStorage
+	ctx    context.Context
+	writes []func(storage.Writer) error
+	keys   []string
+	// saved maps the caller's workflows to the copies that get their new
+	// versions on Commit.
+	saved map[*storage.WorkflowState]*storage.WorkflowState
+	done  bool
+}
+
+func (t *cachedTx) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	copied := cloneWorkflow(wf)
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return w.SaveWorkflow(ctx, copied)
+	})
+	t.keys = append(t.keys, workflowKey(wf.ID))
+	if t.saved == nil {
+		t.saved = make(map[*storage.WorkflowState]*storage.WorkflowState)
+	}
+	t.saved[wf] = copied
+	return nil
+}
+
+func (t *cachedTx) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	copied := *task
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return w.SaveTask(ctx, workflowID, &copied)
+	})
+	t.keys = append(t.keys, workflowKey(workflowID), taskKey(workflowID, task.ID))
+	return nil
+}
+
+// SaveOutbox implements storage.OutboxWriter.
+func (t *cachedTx) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	copied := *entry
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return storage.SaveOutbox(ctx, w, &copied)
+	})
+	return nil
+}
+
+func (t *cachedTx) Commit() error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	t.done = true
+	defer t.c.invalidate(t.keys...)
+
+	err := storage.WriteBatch(t.ctx, t.c.Storage, func(w storage.Writer) error {
+		for _, write := range t.writes {
+			if err := write(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for wf, copied := range t.saved {
+		wf.Version = copied.Version
+	}
+	return nil
+}
+
+func (t *cachedTx) Rollback() error {
+	t.done = true
+	t.writes = nil
+	return nil
+}
+
+// ScanWorkflows implements storage.WorkflowScanner by scanning the wrapped
+// storage. Like listings, scans bypass the cache.
+func (c *CachedStorage) ScanWorkflows(ctx context.Context, filter *storage.WorkflowFilter, fn func(*storage.WorkflowState) error) error {
+	return storage.ScanWorkflows(ctx, c.Storage, filter, fn)
+}
+
+// Snapshot implements storage.Snapshotter by snapshotting the wrapped
+// storage, bypassing the cache.
+func (c *CachedStorage) Snapshot(ctx context.Context, fn func(wf *storage.WorkflowState, tasks []*storage.TaskState) error) error {
+	return storage.Snapshot(ctx, c.Storage, fn)
+}
+
+// SaveOutbox implements storage.Outbox with the outbox of the wrapped
+// storage. Entries carry no task data, so they bypass the cache.
+func (c *CachedStorage) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	return storage.SaveOutbox(ctx, c.Storage, entry)
+}
+
+// ListOutbox implements storage.Outbox.
+func (c *CachedStorage) ListOutbox(ctx context.Context, limit int) ([]*storage.OutboxEntry, error) {
+	return storage.ListOutbox(ctx, c.Storage, limit)
+}
+
+// DeleteOutbox implements storage.Outbox.
+func (c *CachedStorage) DeleteOutbox(ctx context.Context, id string) error {
+	return storage.DeleteOutbox(ctx, c.Storage, id)
+}
+
+// SaveDedupe implements storage.DedupeStore with the records of the wrapped
+// storage, which bypass the cache.
+func (c *CachedStorage) SaveDedupe(ctx context.Context, record *storage.DedupeRecord) error {
+	store, ok := c.Storage.(storage.DedupeStore)
+	if !ok {
+		return nil
+	}
+	return store.SaveDedupe(ctx, record)
+}
+
+// GetDedupe implements storage.DedupeStore.
+func (c *CachedStorage) GetDedupe(ctx context.Context, key string) (*storage.DedupeRecord, error) {
+	store, ok := c.Storage.(storage.DedupeStore)
+	if !ok {
+		return nil, &storage.NotFoundError{EntityType: "dedupe", ID: key}
+	}
+	return store.GetDedupe(ctx, key)
+}
+
+// SaveResource implements storage.ResourceStore with the records of the
+// wrapped storage, which bypass the cache.
+func (c *CachedStorage) SaveResource(ctx context.Context, record *storage.ResourceRecord) error {
+	store, ok := c.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil
+	}
+	return store.SaveResource(ctx, record)
+}
+
+// DeleteResource implements storage.ResourceStore.
+func (c *CachedStorage) DeleteResource(ctx context.Context, kind, name string) error {
+	store, ok := c.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil
+	}
+	return store.DeleteResource(ctx, kind, name)
+}
+
+// ListResources implements storage.ResourceStore.
+func (c *CachedStorage) ListResources(ctx context.Context, kind string) ([]*storage.ResourceRecord, error) {
+	store, ok := c.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil, nil
+	}
+	return store.ListResources(ctx, kind)
+}
+
+// Invalidate implements storage.Invalidator. An empty taskID invalidates the
+// workflow and all of its cached tasks.
+func (c *CachedStorage) Invalidate(workflowID, taskID string) {
+	if taskID != "" {
+		c.invalidate(workflowKey(workflowID), taskKey(workflowID, taskID))
+		return
+	}
+
+	prefix := taskKey(workflowID, "")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(workflowKey(workflowID))
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(key)
+		}
+	}
+}
+
+// Len returns the number of cached records.
+func (c *CachedStorage) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// HitRate returns the cache hit rate (0.0-1.0) and total lookups.
+func (c *CachedStorage) HitRate() (rate float64, total int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total = c.hits + c.misses
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(c.hits) / float64(total), total
+}
+
+// lookup returns the cached item for key. On a miss it returns a placeholder
+// to fill once the record is read, or nil if another read of key is already
+// in progress.
+func (c *CachedStorage) lookup(key string) (*cacheItem, *list.Element) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		item := elem.Value.(*cacheItem)
+		if item.loaded {
+			c.eviction.MoveToFront(elem)
+			c.hits++
+			return item, nil
+		}
+		c.misses++
+		return nil, nil
+	}
+	c.misses++
+
+	if c.eviction.Len() >= c.maxSize {
+		c.evictOldest()
+	}
+	elem := c.eviction.PushFront(&cacheItem{key: key})
+	c.items[key] = elem
+	return nil, elem
+}
+
+// fill stores value in placeholder unless the key was invalidated while it
+// was being read.
+func (c *CachedStorage) fill(placeholder *list.Element, value *cacheItem) {
+	if placeholder == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := placeholder.Value.(*cacheItem)
+	if c.items[item.key] != placeholder {
+		return
+	}
+	item.workflow, item.task, item.loaded = value.workflow, value.task, true
+}
+
+// drop removes a placeholder whose read failed.
+func (c *CachedStorage) drop(placeholder *list.Element) {
+	if placeholder == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key := placeholder.Value.(*cacheItem).key; c.items[key] == placeholder {
+		c.remove(key)
+	}
+}
+
+func (c *CachedStorage) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		c.remove(key)
+	}
+}
+
+func (c *CachedStorage) remove(key string) {
+	if elem, ok := c.items[key]; ok {
+		c.eviction.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+func (c *CachedStorage) evictOldest() {
+	back := c.eviction.Back()
+	if back == nil {
+		return
+	}
+	c.eviction.Remove(back)
+	delete(c.items, back.Value.(*cacheItem).key)
+}
+
+// cloneWorkflow copies wf deeply enough that callers cannot modify the cached
+// record.
+func cloneWorkflow(wf *storage.WorkflowState) *storage.WorkflowState {
+	copied := *wf
+	if wf.TaskStatus != nil {
+		copied.TaskStatus = make(map[string]*storage.TaskState, len(wf.TaskStatus))
+		for k, v := range wf.TaskStatus {
+			taskCopy := *v
+			copied.TaskStatus[k] = &taskCopy
+		}
+	}
+	copied.Journal = append([]storage.JournalEntry(nil), wf.Journal...)
+	return &copied
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+// TestCachedStorageSuite runs the full storage test suite against a cached
+// memory storage.
+func TestCachedStorageSuite(t *testing.T) {
+	suite := &storage.StorageTestSuite{
+		NewStorage: func(t *testing.T) storage.Storage {
+			return NewCachedStorage(memory.NewMemoryStorage(), 16)
+		},
+	}
+
+	suite.RunAllTests(t)
+}
+
+// countingStorage counts reads that reach the backend.
+type countingStorage struct {
+	*memory.MemoryStorage
+
+	mu    sync.Mutex
+	reads int
+}
+
+func (s *countingStorage) GetWorkflow(ctx context.Context, id string) (*storage.WorkflowState, error) {
+	s.mu.Lock()
+	s.reads++
+	s.mu.Unlock()
+	return s.MemoryStorage.GetWorkflow(ctx, id)
+}
+
+func (s *countingStorage) GetTask(ctx context.Context, workflowID, taskID string) (*storage.TaskState, error) {
+	s.mu.Lock()
+	s.reads++
+	s.mu.Unlock()
+	return s.MemoryStorage.GetTask(ctx, workflowID, taskID)
+}
+
+func (s *countingStorage) readCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reads
+}
+
+func TestCachedStorage_ReadThroughAndInvalidation(t *testing.T) {
+	backend := &countingStorage{MemoryStorage: memory.NewMemoryStorage()}
+	c := NewCachedStorage(backend, 16)
+	ctx := context.Background()
+
+	wf := &storage.WorkflowState{ID: "wf-1", Name: "cached", Status: "running", CreatedAt: time.Now()}
+	if err := c.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if err := c.SaveTask(ctx, "wf-1", &storage.TaskState{ID: "t1", Status: "running"}); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		got, err := c.GetWorkflow(ctx, "wf-1")
+		if err != nil {
+			t.Fatalf("GetWorkflow failed: %v", err)
+		}
+		got.Status = "mutated by caller"
+		if _, err := c.GetTask(ctx, "wf-1", "t1"); err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+	}
+	if reads := backend.readCount(); reads != 2 {
+		t.Fatalf("expected 2 backend reads, got %d", reads)
+	}
+	if rate, total := c.HitRate(); total != 10 || rate != 0.8 {
+		t.Fatalf("hit rate = %v of %d, want 0.8 of 10", rate, total)
+	}
+	if got, _ := c.GetWorkflow(ctx, "wf-1"); got.Status != "running" {
+		t.Fatalf("cached record was modified by a caller: %s", got.Status)
+	}
+
+	if err := c.SaveTasks(ctx, "wf-1", []*storage.TaskState{{ID: "t1", Status: "completed"}}); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+	if task, _ := c.GetTask(ctx, "wf-1", "t1"); task.Status != "completed" {
+		t.Fatalf("task status = %s after write, want completed", task.Status)
+	}
+
+	// A change made behind the cache's back shows up after Invalidate.
+	if _, err := c.GetWorkflow(ctx, "wf-1"); err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	if err := backend.SaveWorkflow(ctx, &storage.WorkflowState{ID: "wf-1", Status: "completed", CreatedAt: wf.CreatedAt}); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if got, _ := c.GetWorkflow(ctx, "wf-1"); got.Status != "running" {
+		t.Fatalf("expected the stale cached status, got %s", got.Status)
+	}
+	c.Invalidate("wf-1", "")
+	if got, _ := c.GetWorkflow(ctx, "wf-1"); got.Status != "completed" {
+		t.Fatalf("status = %s after Invalidate, want completed", got.Status)
+	}
+
+	if err := c.DeleteWorkflow(ctx, "wf-1"); err != nil {
+		t.Fatalf("DeleteWorkflow failed: %v", err)
+	}
+	if _, err := c.GetWorkflow(ctx, "wf-1"); err == nil {
+		t.Fatal("expected deleted workflow to be gone")
+	}
+	if _, err := c.GetTask(ctx, "wf-1", "t1"); err == nil {
+		t.Fatal("expected task of deleted workflow to be gone")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("expected failed reads not to be cached, got %d entries", c.Len())
+	}
+}
+
+func TestCachedStorage_Eviction(t *testing.T) {
+	backend := &countingStorage{MemoryStorage: memory.NewMemoryStorage()}
+	c := NewCachedStorage(backend, 2)
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := backend.SaveWorkflow(ctx, &storage.WorkflowState{ID: id, Status: "running"}); err != nil {
+			t.Fatalf("SaveWorkflow failed: %v", err)
+		}
+	}
+
+	for _, id := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := c.GetWorkflow(ctx, id); err != nil {
+			t.Fatalf("GetWorkflow(%s) failed: %v", id, err)
+		}
+	}
+	// a, b, c miss; "c" evicts "b" as the least recently used; "b" misses again.
+	if reads := backend.readCount(); reads != 4 {
+		t.Fatalf("expected 4 backend reads, got %d", reads)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 cached records, got %d", c.Len())
+	}
+}
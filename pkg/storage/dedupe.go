@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// DedupeRecord is the completed execution of a task dedupe key, shared with
+// the tasks of later workflow runs that have the same key until it expires.
+type DedupeRecord struct {
+	// Key is the dedupe key.
+	Key string `json:"key"`
+
+	// WorkflowID and TaskID identify the task that executed.
+	WorkflowID string `json:"workflow_id"`
+	TaskID     string `json:"task_id"`
+
+	// Result is the result the task set, if any.
+	Result any `json:"result,omitempty"`
+
+	CompletedAt time.Time `json:"completed_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// DedupeStore is implemented by storages that keep the executions of dedupe
+// keys, so that tasks sharing a key execute once across workflow runs and
+// not only within one.
+type DedupeStore interface {
+	// SaveDedupe saves record, replacing the record of its key.
+	SaveDedupe(ctx context.Context, record *DedupeRecord) error
+
+	// GetDedupe returns the unexpired record of key, or a NotFoundError.
+	GetDedupe(ctx context.Context, key string) (*DedupeRecord, error)
+}
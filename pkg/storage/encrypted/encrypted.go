@@ -0,0 +1,501 @@
+// Package encrypted provides a storage decorator that envelope-encrypts the
+// configs and results of sensitive tasks before they reach the backend, and
+// decrypts them when they are read back.
+package encrypted
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+)
+
+// EncryptedStorage wraps a Storage and seals the config, including the
+// compensation's, of every task definition marked sensitive and the result
+// of every sensitive task state. Each value gets its own data key, bound to
+// its workflow, task and field, so sealed values cannot be moved between
+// records. Sealed values are opened on every read except Snapshot, so
+// snapshots of the storage stay encrypted.
+type EncryptedStorage struct {
+	storage.Storage
+
+	sealer *Sealer
+}
+
+// NewEncryptedStorage wraps store, sealing sensitive values with sealer.
+func NewEncryptedStorage(store storage.Storage, sealer *Sealer) *EncryptedStorage {
+	return &EncryptedStorage{Storage: store, sealer: sealer}
+}
+
+// SaveWorkflow seals the workflow's sensitive values and saves it. wf itself
+// is left unsealed and gets the saved Version.
+func (s *EncryptedStorage) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	sealed, err := s.sealWorkflow(wf)
+	if err != nil {
+		return err
+	}
+	if err := s.Storage.SaveWorkflow(ctx, sealed); err != nil {
+		return err
+	}
+	wf.Version = sealed.Version
+	return nil
+}
+
+// GetWorkflow reads the workflow and opens its sealed values.
+func (s *EncryptedStorage) GetWorkflow(ctx context.Context, id string) (*storage.WorkflowState, error) {
+	wf, err := s.Storage.GetWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.openWorkflow(wf)
+}
+
+// ListWorkflows lists the workflows and opens their sealed values.
+func (s *EncryptedStorage) ListWorkflows(ctx context.Context, filter *storage.WorkflowFilter) ([]*storage.WorkflowState, int, error) {
+	workflows, total, err := s.Storage.ListWorkflows(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opened := make([]*storage.WorkflowState, len(workflows))
+	for i, wf := range workflows {
+		if opened[i], err = s.openWorkflow(wf); err != nil {
+			return nil, 0, err
+		}
+	}
+	return opened, total, nil
+}
+
+// SaveTask seals the task's result if it is sensitive and saves it.
+func (s *EncryptedStorage) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	sealed, err := s.sealTask(workflowID, task)
+	if err != nil {
+		return err
+	}
+	return s.Storage.SaveTask(ctx, workflowID, sealed)
+}
+
+// GetTask reads the task and opens its sealed result.
+func (s *EncryptedStorage) GetTask(ctx context.Context, workflowID, taskID string) (*storage.TaskState, error) {
+	task, err := s.Storage.GetTask(ctx, workflowID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.openTask(workflowID, task)
+}
+
+// ListTasks lists the tasks and opens their sealed results.
+func (s *EncryptedStorage) ListTasks(ctx context.Context, workflowID string) ([]*storage.TaskState, error) {
+	tasks, err := s.Storage.ListTasks(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	opened := make([]*storage.TaskState, len(tasks))
+	for i, task := range tasks {
+		if opened[i], err = s.openTask(workflowID, task); err != nil {
+			return nil, err
+		}
+	}
+	return opened, nil
+}
+
+// SaveTasks implements storage.TaskBatchSaver, falling back to SaveTask when
+// the wrapped storage cannot save batches.
+func (s *EncryptedStorage) SaveTasks(ctx context.Context, workflowID string, tasks []*storage.TaskState) error {
+	sealed := make([]*storage.TaskState, len(tasks))
+	for i, task := range tasks {
+		var err error
+		if sealed[i], err = s.sealTask(workflowID, task); err != nil {
+			return err
+		}
+	}
+	if saver, ok := s.Storage.(storage.TaskBatchSaver); ok {
+		return saver.SaveTasks(ctx, workflowID, sealed)
+	}
+	for _, task := range sealed {
+		if err := s.Storage.SaveTask(ctx, workflowID, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BeginTx implements storage.Transactional. Writes are sealed as they are
+// made, buffered, and applied with storage.WriteBatch on the wrapped
+// storage on Commit, so they are atomic when that storage is transactional.
+func (s *EncryptedStorage) BeginTx(ctx context.Context) (storage.Tx, error) {
+	return &encryptedTx{s: s, ctx: ctx}, nil
+}
+
+// encryptedTx is the storage.Tx of an EncryptedStorage.
+type encryptedTx struct {
+	s      *EncryptedStorage
+	ctx    context.Context
+	writes []func(storage.Writer) error
+	// saved maps the caller's workflows to the sealed copies that get their
+	// new versions on Commit.
+	saved map[*storage.WorkflowState]*storage.WorkflowState
+	done  bool
+}
+
+func (t *encryptedTx) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	sealed, err := t.s.sealWorkflow(wf)
+	if err != nil {
+		return err
+	}
+	if sealed == wf {
+		copied := *wf
+		sealed = &copied
+	}
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return w.SaveWorkflow(ctx, sealed)
+	})
+	if t.saved == nil {
+		t.saved = make(map[*storage.WorkflowState]*storage.WorkflowState)
+	}
+	t.saved[wf] = sealed
+	return nil
+}
+
+func (t *encryptedTx) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	sealed, err := t.s.sealTask(workflowID, task)
+	if err != nil {
+		return err
+	}
+	copied := *sealed
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return w.SaveTask(ctx, workflowID, &copied)
+	})
+	return nil
+}
+
+// SaveOutbox implements storage.OutboxWriter.
+func (t *encryptedTx) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	copied := *entry
+	t.writes = append(t.writes, func(w storage.Writer) error {
+		return storage.SaveOutbox(ctx, w, &copied)
+	})
+	return nil
+}
+
+func (t *encryptedTx) Commit() error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	t.done = true
+	err := storage.WriteBatch(t.ctx, t.s.Storage, func(w storage.Writer) error {
+		for _, write := range t.writes {
+			if err := write(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for wf, sealed := range t.saved {
+		wf.Version = sealed.Version
+	}
+	return nil
+}
+
+func (t *encryptedTx) Rollback() error {
+	t.done = true
+	t.writes = nil
+	return nil
+}
+
+// ScanWorkflows implements storage.WorkflowScanner, opening the sealed
+// values of each workflow visited.
+func (s *EncryptedStorage) ScanWorkflows(ctx context.Context, filter *storage.WorkflowFilter, fn func(*storage.WorkflowState) error) error {
+	return storage.ScanWorkflows(ctx, s.Storage, filter, func(wf *storage.WorkflowState) error {
+		opened, err := s.openWorkflow(wf)
+		if err != nil {
+			return err
+		}
+		return fn(opened)
+	})
+}
+
+// Snapshot implements storage.Snapshotter by snapshotting the wrapped
+// storage. Sensitive values are left sealed, and saving them back through
+// an EncryptedStorage keeps them as they are.
+func (s *EncryptedStorage) Snapshot(ctx context.Context, fn func(wf *storage.WorkflowState, tasks []*storage.TaskState) error) error {
+	return storage.Snapshot(ctx, s.Storage, fn)
+}
+
+// SaveOutbox implements storage.Outbox with the outbox of the wrapped
+// storage. Entries carry no task data, so they bypass the encryption.
+func (s *EncryptedStorage) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	return storage.SaveOutbox(ctx, s.Storage, entry)
+}
+
+// ListOutbox implements storage.Outbox.
+func (s *EncryptedStorage) ListOutbox(ctx context.Context, limit int) ([]*storage.OutboxEntry, error) {
+	return storage.ListOutbox(ctx, s.Storage, limit)
+}
+
+// DeleteOutbox implements storage.Outbox.
+func (s *EncryptedStorage) DeleteOutbox(ctx context.Context, id string) error {
+	return storage.DeleteOutbox(ctx, s.Storage, id)
+}
+
+// SaveDedupe implements storage.DedupeStore with the records of the wrapped
+// storage. The engine does not record sensitive tasks, so records are not
+// sealed.
+func (s *EncryptedStorage) SaveDedupe(ctx context.Context, record *storage.DedupeRecord) error {
+	store, ok := s.Storage.(storage.DedupeStore)
+	if !ok {
+		return nil
+	}
+	return store.SaveDedupe(ctx, record)
+}
+
+// GetDedupe implements storage.DedupeStore.
+func (s *EncryptedStorage) GetDedupe(ctx context.Context, key string) (*storage.DedupeRecord, error) {
+	store, ok := s.Storage.(storage.DedupeStore)
+	if !ok {
+		return nil, &storage.NotFoundError{EntityType: "dedupe", ID: key}
+	}
+	return store.GetDedupe(ctx, key)
+}
+
+// SaveResource implements storage.ResourceStore with the records of the
+// wrapped storage. Managed resources hold no task values, so records are not
+// sealed.
+func (s *EncryptedStorage) SaveResource(ctx context.Context, record *storage.ResourceRecord) error {
+	store, ok := s.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil
+	}
+	return store.SaveResource(ctx, record)
+}
+
+// DeleteResource implements storage.ResourceStore.
+func (s *EncryptedStorage) DeleteResource(ctx context.Context, kind, name string) error {
+	store, ok := s.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil
+	}
+	return store.DeleteResource(ctx, kind, name)
+}
+
+// ListResources implements storage.ResourceStore.
+func (s *EncryptedStorage) ListResources(ctx context.Context, kind string) ([]*storage.ResourceRecord, error) {
+	store, ok := s.Storage.(storage.ResourceStore)
+	if !ok {
+		return nil, nil
+	}
+	return store.ListResources(ctx, kind)
+}
+
+// Invalidate implements storage.Invalidator for a wrapped caching storage.
+func (s *EncryptedStorage) Invalidate(workflowID, taskID string) {
+	if invalidator, ok := s.Storage.(storage.Invalidator); ok {
+		invalidator.Invalidate(workflowID, taskID)
+	}
+}
+
+func configAAD(workflowID, taskID string) string {
+	return workflowID + "/" + taskID + "/config"
+}
+
+func compensationAAD(workflowID, taskID string) string {
+	return workflowID + "/" + taskID + "/compensation"
+}
+
+func resultAAD(workflowID, taskID string) string {
+	return workflowID + "/" + taskID + "/result"
+}
+
+// sealWorkflow returns wf with its sensitive values sealed, copying what it
+// changes; wf is returned itself if nothing needs sealing.
+func (s *EncryptedStorage) sealWorkflow(wf *storage.WorkflowState) (*storage.WorkflowState, error) {
+	copied := *wf
+	changed := false
+	for i, def := range wf.Tasks {
+		if !def.Sensitive {
+			continue
+		}
+		sealed, ok, err := s.sealDefinition(wf.ID, def)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if !changed {
+			copied.Tasks = slices.Clone(wf.Tasks)
+			changed = true
+		}
+		copied.Tasks[i] = sealed
+	}
+	statusCopied := false
+	for id, task := range wf.TaskStatus {
+		sealed, err := s.sealTask(wf.ID, task)
+		if err != nil {
+			return nil, err
+		}
+		if sealed == task {
+			continue
+		}
+		if !statusCopied {
+			copied.TaskStatus = make(map[string]*storage.TaskState, len(wf.TaskStatus))
+			for k, v := range wf.TaskStatus {
+				copied.TaskStatus[k] = v
+			}
+			statusCopied = true
+		}
+		copied.TaskStatus[id] = sealed
+	}
+	if !changed && !statusCopied {
+		return wf, nil
+	}
+	return &copied, nil
+}
+
+// sealDefinition returns def with its config and compensation config sealed,
+// and whether anything was sealed.
+func (s *EncryptedStorage) sealDefinition(workflowID string, def models.TaskDefinition) (models.TaskDefinition, bool, error) {
+	changed := false
+	if def.Config != nil && !isSealed(def.Config) {
+		sealed, err := s.sealer.seal(def.Config, configAAD(workflowID, def.ID))
+		if err != nil {
+			return def, false, fmt.Errorf("seal task %s config: %w", def.ID, err)
+		}
+		def.Config = sealed
+		changed = true
+	}
+	if def.Compensation != nil && def.Compensation.Config != nil && !isSealed(def.Compensation.Config) {
+		sealed, err := s.sealer.seal(def.Compensation.Config, compensationAAD(workflowID, def.ID))
+		if err != nil {
+			return def, false, fmt.Errorf("seal task %s compensation config: %w", def.ID, err)
+		}
+		compensation := *def.Compensation
+		compensation.Config = sealed
+		def.Compensation = &compensation
+		changed = true
+	}
+	return def, changed, nil
+}
+
+// sealTask returns a copy of task with its result sealed, or task itself if
+// it is not sensitive or its result is nil or already sealed.
+func (s *EncryptedStorage) sealTask(workflowID string, task *storage.TaskState) (*storage.TaskState, error) {
+	if !task.Sensitive || task.Result == nil || isSealed(task.Result) {
+		return task, nil
+	}
+	sealed, err := s.sealer.seal(task.Result, resultAAD(workflowID, task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("seal task %s result: %w", task.ID, err)
+	}
+	copied := *task
+	copied.Result = sealed
+	return &copied, nil
+}
+
+// openWorkflow returns wf with its sealed values opened, copying what it
+// changes so that records shared with the wrapped storage stay sealed.
+func (s *EncryptedStorage) openWorkflow(wf *storage.WorkflowState) (*storage.WorkflowState, error) {
+	copied := *wf
+	changed := false
+	for i, def := range wf.Tasks {
+		opened, ok, err := s.openDefinition(wf.ID, def)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if !changed {
+			copied.Tasks = slices.Clone(wf.Tasks)
+			changed = true
+		}
+		copied.Tasks[i] = opened
+	}
+	statusCopied := false
+	for id, task := range wf.TaskStatus {
+		opened, err := s.openTask(wf.ID, task)
+		if err != nil {
+			return nil, err
+		}
+		if opened == task {
+			continue
+		}
+		if !statusCopied {
+			copied.TaskStatus = make(map[string]*storage.TaskState, len(wf.TaskStatus))
+			for k, v := range wf.TaskStatus {
+				copied.TaskStatus[k] = v
+			}
+			statusCopied = true
+		}
+		copied.TaskStatus[id] = opened
+	}
+	if !changed && !statusCopied {
+		return wf, nil
+	}
+	return &copied, nil
+}
+
+// openDefinition returns def with its sealed configs opened, and whether
+// anything was sealed.
+func (s *EncryptedStorage) openDefinition(workflowID string, def models.TaskDefinition) (models.TaskDefinition, bool, error) {
+	changed := false
+	if isSealed(def.Config) {
+		opened, err := s.openConfig(def.Config, configAAD(workflowID, def.ID))
+		if err != nil {
+			return def, false, fmt.Errorf("open task %s config: %w", def.ID, err)
+		}
+		def.Config = opened
+		changed = true
+	}
+	if def.Compensation != nil && isSealed(def.Compensation.Config) {
+		opened, err := s.openConfig(def.Compensation.Config, compensationAAD(workflowID, def.ID))
+		if err != nil {
+			return def, false, fmt.Errorf("open task %s compensation config: %w", def.ID, err)
+		}
+		compensation := *def.Compensation
+		compensation.Config = opened
+		def.Compensation = &compensation
+		changed = true
+	}
+	return def, changed, nil
+}
+
+func (s *EncryptedStorage) openConfig(config map[string]interface{}, aad string) (map[string]interface{}, error) {
+	opened, err := s.sealer.open(config, aad)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := opened.(map[string]interface{})
+	if !ok && opened != nil {
+		return nil, fmt.Errorf("sealed config is not an object")
+	}
+	return m, nil
+}
+
+// openTask returns a copy of task with its result opened, or task itself if
+// its result is not sealed.
+func (s *EncryptedStorage) openTask(workflowID string, task *storage.TaskState) (*storage.TaskState, error) {
+	if !isSealed(task.Result) {
+		return task, nil
+	}
+	opened, err := s.sealer.open(task.Result, resultAAD(workflowID, task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("open task %s result: %w", task.ID, err)
+	}
+	copied := *task
+	copied.Result = opened
+	return &copied, nil
+}
@@ -0,0 +1,167 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/storage"
+	"github.com/goclaw/goclaw/pkg/storage/memory"
+)
+
+func newTestSealer(t *testing.T, fill byte) *Sealer {
+	t.Helper()
+	sealer, err := NewSealerFromBase64(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, 32)))
+	if err != nil {
+		t.Fatalf("NewSealerFromBase64 failed: %v", err)
+	}
+	return sealer
+}
+
+// TestEncryptedStorageSuite runs the full storage test suite against an
+// encrypted memory storage.
+func TestEncryptedStorageSuite(t *testing.T) {
+	suite := &storage.StorageTestSuite{
+		NewStorage: func(t *testing.T) storage.Storage {
+			return NewEncryptedStorage(memory.NewMemoryStorage(), newTestSealer(t, 1))
+		},
+	}
+
+	suite.RunAllTests(t)
+}
+
+func sensitiveWorkflow() *storage.WorkflowState {
+	return &storage.WorkflowState{
+		ID:        "wf-1",
+		Name:      "payout",
+		Status:    "running",
+		CreatedAt: time.Now(),
+		Tasks: []models.TaskDefinition{
+			{ID: "charge", Name: "charge", Type: "http", Sensitive: true,
+				Config:       map[string]interface{}{"api_key": "sk-live"},
+				Compensation: &models.TaskCompensation{Type: "http", Config: map[string]interface{}{"api_key": "sk-refund"}}},
+			{ID: "notify", Name: "notify", Type: "http", Config: map[string]interface{}{"channel": "#ops"}},
+		},
+		TaskStatus: map[string]*storage.TaskState{
+			"charge": {ID: "charge", Name: "charge", Status: "completed", Sensitive: true, Result: map[string]interface{}{"card": "4242"}},
+			"notify": {ID: "notify", Name: "notify", Status: "completed", Result: "sent"},
+		},
+	}
+}
+
+func TestEncryptedStorage_SealsSensitiveValuesAtRest(t *testing.T) {
+	backend := memory.NewMemoryStorage()
+	s := NewEncryptedStorage(backend, newTestSealer(t, 1))
+	ctx := context.Background()
+
+	wf := sensitiveWorkflow()
+	if err := s.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if wf.Tasks[0].Config["api_key"] != "sk-live" || wf.TaskStatus["charge"].Result.(map[string]interface{})["card"] != "4242" {
+		t.Fatal("SaveWorkflow sealed the caller's workflow")
+	}
+	if wf.Version == 0 {
+		t.Fatal("SaveWorkflow did not set the caller's version")
+	}
+
+	raw, err := backend.GetWorkflow(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("backend GetWorkflow failed: %v", err)
+	}
+	if !isSealed(raw.Tasks[0].Config) || !isSealed(raw.Tasks[0].Compensation.Config) || !isSealed(raw.TaskStatus["charge"].Result) {
+		t.Fatalf("sensitive values stored in the clear: %+v", raw.Tasks[0])
+	}
+	if raw.Tasks[1].Config["channel"] != "#ops" || raw.TaskStatus["notify"].Result != "sent" {
+		t.Fatal("non-sensitive values were sealed")
+	}
+
+	got, err := s.GetWorkflow(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	if got.Tasks[0].Config["api_key"] != "sk-live" || got.Tasks[0].Compensation.Config["api_key"] != "sk-refund" {
+		t.Fatalf("GetWorkflow configs = %+v", got.Tasks[0])
+	}
+	if got.TaskStatus["charge"].Result.(map[string]interface{})["card"] != "4242" {
+		t.Fatalf("GetWorkflow result = %v", got.TaskStatus["charge"].Result)
+	}
+
+	task := &storage.TaskState{ID: "charge", Name: "charge", Status: "completed", Sensitive: true, Result: "receipt-1"}
+	if err := s.SaveTask(ctx, "wf-1", task); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	rawTask, err := backend.GetTask(ctx, "wf-1", "charge")
+	if err != nil {
+		t.Fatalf("backend GetTask failed: %v", err)
+	}
+	if !isSealed(rawTask.Result) {
+		t.Fatalf("task result stored in the clear: %v", rawTask.Result)
+	}
+	tasks, err := s.ListTasks(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	for _, task := range tasks {
+		if task.ID == "charge" && task.Result != "receipt-1" {
+			t.Fatalf("ListTasks result = %v", task.Result)
+		}
+	}
+}
+
+func TestEncryptedStorage_WrongKeyOrRecordFailsToOpen(t *testing.T) {
+	backend := memory.NewMemoryStorage()
+	ctx := context.Background()
+	if err := NewEncryptedStorage(backend, newTestSealer(t, 1)).SaveWorkflow(ctx, sensitiveWorkflow()); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+
+	if _, err := NewEncryptedStorage(backend, newTestSealer(t, 2)).GetWorkflow(ctx, "wf-1"); err == nil {
+		t.Fatal("GetWorkflow with another master key succeeded")
+	}
+
+	// A sealed value copied into another record does not open there.
+	raw, err := backend.GetWorkflow(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("backend GetWorkflow failed: %v", err)
+	}
+	raw.ID = "wf-2"
+	raw.Version = 0
+	if err := backend.SaveWorkflow(ctx, raw); err != nil {
+		t.Fatalf("backend SaveWorkflow failed: %v", err)
+	}
+	if _, err := NewEncryptedStorage(backend, newTestSealer(t, 1)).GetWorkflow(ctx, "wf-2"); err == nil {
+		t.Fatal("GetWorkflow of a moved sealed value succeeded")
+	}
+}
+
+func TestEncryptedStorage_SnapshotStaysSealed(t *testing.T) {
+	s := NewEncryptedStorage(memory.NewMemoryStorage(), newTestSealer(t, 1))
+	ctx := context.Background()
+	if err := s.SaveWorkflow(ctx, sensitiveWorkflow()); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+
+	restored := NewEncryptedStorage(memory.NewMemoryStorage(), newTestSealer(t, 1))
+	err := s.Snapshot(ctx, func(wf *storage.WorkflowState, _ []*storage.TaskState) error {
+		if !isSealed(wf.Tasks[0].Config) {
+			t.Errorf("snapshot config is in the clear: %v", wf.Tasks[0].Config)
+		}
+		wf.Version = 0
+		return restored.SaveWorkflow(ctx, wf)
+	})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	got, err := restored.GetWorkflow(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("GetWorkflow of the restored workflow failed: %v", err)
+	}
+	if got.Tasks[0].Config["api_key"] != "sk-live" {
+		t.Fatalf("restored config = %v", got.Tasks[0].Config)
+	}
+}
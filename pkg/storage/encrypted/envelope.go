@@ -0,0 +1,171 @@
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// sealedKey is the key of the single entry of a sealed value's map.
+const sealedKey = "$sealed"
+
+// envelope is a sealed value: its JSON encoding encrypted with a fresh data
+// key, and the data key encrypted with the master key.
+type envelope struct {
+	// KeyID identifies the master key the data key is encrypted with.
+	KeyID string
+	// DataKey is the encrypted data key, prefixed with its nonce.
+	DataKey string
+	// Data is the encrypted value, prefixed with its nonce.
+	Data string
+}
+
+// Sealer envelope-encrypts values with a master key.
+type Sealer struct {
+	keyID  string
+	master cipher.AEAD
+}
+
+// NewSealer creates a Sealer for a 32-byte AES-256 master key.
+func NewSealer(masterKey []byte) (*Sealer, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(masterKey))
+	}
+	master, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(masterKey)
+	return &Sealer{keyID: hex.EncodeToString(sum[:4]), master: master}, nil
+}
+
+// NewSealerFromBase64 creates a Sealer for a base64 encoded master key, as
+// configured in storage.encryption.master_key.
+func NewSealerFromBase64(masterKey string) (*Sealer, error) {
+	key, err := base64.StdEncoding.DecodeString(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode master key: %w", err)
+	}
+	return NewSealer(key)
+}
+
+// seal encrypts value, bound to aad, into a JSON compatible map that
+// replaces it in the stored record.
+func (s *Sealer) seal(value any, aad string) (map[string]interface{}, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode sensitive value: %w", err)
+	}
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	sealedData, err := sealWith(data, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := sealWith(s.master, dataKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		sealedKey: map[string]interface{}{
+			"key_id":   s.keyID,
+			"data_key": base64.StdEncoding.EncodeToString(wrappedKey),
+			"data":     base64.StdEncoding.EncodeToString(sealedData),
+		},
+	}, nil
+}
+
+// open decrypts a value sealed with aad. Values that are not sealed are
+// returned unchanged.
+func (s *Sealer) open(value any, aad string) (any, error) {
+	env, ok := asEnvelope(value)
+	if !ok {
+		return value, nil
+	}
+	if env.KeyID != s.keyID {
+		return nil, fmt.Errorf("sensitive value is sealed with master key %s, not the configured %s", env.KeyID, s.keyID)
+	}
+	wrappedKey, err := base64.StdEncoding.DecodeString(env.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode data key: %w", err)
+	}
+	dataKey, err := openWith(s.master, wrappedKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	sealedData, err := base64.StdEncoding.DecodeString(env.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode sealed value: %w", err)
+	}
+	plaintext, err := openWith(data, sealedData, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt sensitive value: %w", err)
+	}
+	var opened any
+	if err := json.Unmarshal(plaintext, &opened); err != nil {
+		return nil, fmt.Errorf("decode sensitive value: %w", err)
+	}
+	return opened, nil
+}
+
+// asEnvelope returns the envelope of a sealed value.
+func asEnvelope(value any) (envelope, bool) {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return envelope{}, false
+	}
+	inner, ok := m[sealedKey].(map[string]interface{})
+	if !ok {
+		return envelope{}, false
+	}
+	var env envelope
+	env.KeyID, _ = inner["key_id"].(string)
+	env.DataKey, _ = inner["data_key"].(string)
+	env.Data, _ = inner["data"].(string)
+	return env, env.DataKey != "" && env.Data != ""
+}
+
+// isSealed reports whether value was sealed.
+func isSealed(value any) bool {
+	_, ok := asEnvelope(value)
+	return ok
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealWith(aead cipher.AEAD, plaintext []byte, aad string) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(aad)), nil
+}
+
+func openWith(aead cipher.AEAD, sealed []byte, aad string) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed value is truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(aad))
+}
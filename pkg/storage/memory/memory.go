@@ -3,6 +3,9 @@ package memory
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,6 +17,9 @@ type MemoryStorage struct {
 	mu        sync.RWMutex
 	workflows map[string]*storage.WorkflowState
 	tasks     map[string]map[string]*storage.TaskState // workflowID -> taskID -> TaskState
+	outbox    map[string]*storage.OutboxEntry
+	dedupes   map[string]*storage.DedupeRecord
+	resources map[string]map[string]json.RawMessage // kind -> name -> data
 }
 
 // NewMemoryStorage creates a new in-memory storage instance.
@@ -21,6 +27,9 @@ func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
 		workflows: make(map[string]*storage.WorkflowState),
 		tasks:     make(map[string]map[string]*storage.TaskState),
+		outbox:    make(map[string]*storage.OutboxEntry),
+		dedupes:   make(map[string]*storage.DedupeRecord),
+		resources: make(map[string]map[string]json.RawMessage),
 	}
 }
 
@@ -29,12 +38,39 @@ func (m *MemoryStorage) SaveWorkflow(ctx context.Context, wf *storage.WorkflowSt
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	version, err := storage.NextVersion(wf, m.versionLocked(wf.ID))
+	if err != nil {
+		return err
+	}
+	m.saveWorkflowLocked(wf, version)
+	return nil
+}
+
+// versionLocked returns the stored version of a workflow, 0 if there is none.
+// The caller holds m.mu.
+func (m *MemoryStorage) versionLocked(id string) int64 {
+	if wf, exists := m.workflows[id]; exists {
+		return wf.Version
+	}
+	return 0
+}
+
+// saveWorkflowLocked stores a copy of wf at version, which is set on wf. The
+// caller holds m.mu.
+func (m *MemoryStorage) saveWorkflowLocked(wf *storage.WorkflowState, version int64) {
+	wf.Version = version
+
 	// Check for duplicate on create (if workflow doesn't exist yet)
 	if _, exists := m.workflows[wf.ID]; !exists && wf.CreatedAt.IsZero() {
 		wf.CreatedAt = time.Now()
 	}
 
 	// Deep copy to avoid external modifications
+	m.workflows[wf.ID] = copyWorkflow(wf)
+}
+
+// copyWorkflow returns a copy of wf that shares no task states or journal.
+func copyWorkflow(wf *storage.WorkflowState) *storage.WorkflowState {
 	copied := *wf
 	if wf.TaskStatus != nil {
 		copied.TaskStatus = make(map[string]*storage.TaskState, len(wf.TaskStatus))
@@ -43,9 +79,8 @@ func (m *MemoryStorage) SaveWorkflow(ctx context.Context, wf *storage.WorkflowSt
 			copied.TaskStatus[k] = &taskCopy
 		}
 	}
-
-	m.workflows[wf.ID] = &copied
-	return nil
+	copied.Journal = append([]storage.JournalEntry(nil), wf.Journal...)
+	return &copied
 }
 
 // GetWorkflow retrieves a workflow by ID.
@@ -136,6 +171,89 @@ func (m *MemoryStorage) ListWorkflows(ctx context.Context, filter *storage.Workf
 	return result, total, nil
 }
 
+// ScanWorkflows implements storage.WorkflowScanner. Matching IDs are
+// collected up front; each workflow is copied when it is visited, and
+// workflows deleted in between are skipped.
+func (m *MemoryStorage) ScanWorkflows(ctx context.Context, filter *storage.WorkflowFilter, fn func(*storage.WorkflowState) error) error {
+	statusMap := make(map[string]bool)
+	offset, limit := 0, 0
+	if filter != nil {
+		for _, s := range filter.Status {
+			statusMap[s] = true
+		}
+		offset, limit = filter.Offset, filter.Limit
+	}
+
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.workflows))
+	for id, wf := range m.workflows {
+		if len(statusMap) == 0 || statusMap[wf.Status] {
+			ids = append(ids, id)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(ids)
+
+	if offset > len(ids) {
+		offset = len(ids)
+	}
+	ids = ids[offset:]
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		wf, err := m.GetWorkflow(ctx, id)
+		if err != nil {
+			continue
+		}
+		if err := fn(wf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshot implements storage.Snapshotter. Workflows and tasks are copied
+// under the read lock, in ID order, and visited after it is released.
+func (m *MemoryStorage) Snapshot(ctx context.Context, fn func(wf *storage.WorkflowState, tasks []*storage.TaskState) error) error {
+	type entry struct {
+		wf    *storage.WorkflowState
+		tasks []*storage.TaskState
+	}
+
+	m.mu.RLock()
+	entries := make([]entry, 0, len(m.workflows))
+	for id, wf := range m.workflows {
+		e := entry{wf: copyWorkflow(wf)}
+		taskIDs := make([]string, 0, len(m.tasks[id]))
+		for taskID := range m.tasks[id] {
+			taskIDs = append(taskIDs, taskID)
+		}
+		sort.Strings(taskIDs)
+		for _, taskID := range taskIDs {
+			copied := *m.tasks[id][taskID]
+			e.tasks = append(e.tasks, &copied)
+		}
+		entries = append(entries, e)
+	}
+	m.mu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].wf.ID < entries[j].wf.ID })
+
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(e.wf, e.tasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteWorkflow deletes a workflow and all its tasks.
 func (m *MemoryStorage) DeleteWorkflow(ctx context.Context, id string) error {
 	m.mu.Lock()
@@ -166,6 +284,13 @@ func (m *MemoryStorage) SaveTask(ctx context.Context, workflowID string, task *s
 		}
 	}
 
+	m.saveTaskLocked(workflowID, task)
+	return nil
+}
+
+// saveTaskLocked stores a copy of task for an existing workflow. The caller
+// holds m.mu.
+func (m *MemoryStorage) saveTaskLocked(workflowID string, task *storage.TaskState) {
 	// Initialize task map for workflow if needed
 	if m.tasks[workflowID] == nil {
 		m.tasks[workflowID] = make(map[string]*storage.TaskState)
@@ -180,7 +305,139 @@ func (m *MemoryStorage) SaveTask(ctx context.Context, workflowID string, task *s
 		m.workflows[workflowID].TaskStatus = make(map[string]*storage.TaskState)
 	}
 	m.workflows[workflowID].TaskStatus[task.ID] = &copied
+}
+
+// SaveTasks saves several task states of a workflow at once.
+func (m *MemoryStorage) SaveTasks(ctx context.Context, workflowID string, tasks []*storage.TaskState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wf, exists := m.workflows[workflowID]
+	if !exists {
+		return &storage.NotFoundError{
+			EntityType: "workflow",
+			ID:         workflowID,
+		}
+	}
+	if m.tasks[workflowID] == nil {
+		m.tasks[workflowID] = make(map[string]*storage.TaskState, len(tasks))
+	}
+	if wf.TaskStatus == nil {
+		wf.TaskStatus = make(map[string]*storage.TaskState, len(tasks))
+	}
+	for _, task := range tasks {
+		copied := *task
+		m.tasks[workflowID][task.ID] = &copied
+		wf.TaskStatus[task.ID] = &copied
+	}
+	return nil
+}
+
+// BeginTx implements storage.Transactional. The transaction buffers copies
+// of its writes and applies them under a single lock on Commit.
+func (m *MemoryStorage) BeginTx(ctx context.Context) (storage.Tx, error) {
+	return &memoryTx{m: m}, nil
+}
+
+// memoryTx is a storage.Tx that buffers writes until Commit.
+type memoryTx struct {
+	m    *MemoryStorage
+	ops  []txOp
+	done bool
+}
+
+// txOp is one buffered write: a workflow, a task when task is set, or an
+// outbox entry when outbox is set. saved is the caller's workflow, which gets
+// the new version on Commit.
+type txOp struct {
+	workflowID string
+	workflow   *storage.WorkflowState
+	saved      *storage.WorkflowState
+	task       *storage.TaskState
+	outbox     *storage.OutboxEntry
+}
+
+func (t *memoryTx) SaveWorkflow(ctx context.Context, wf *storage.WorkflowState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	t.ops = append(t.ops, txOp{workflowID: wf.ID, workflow: copyWorkflow(wf), saved: wf})
+	return nil
+}
+
+func (t *memoryTx) SaveTask(ctx context.Context, workflowID string, task *storage.TaskState) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	copied := *task
+	t.ops = append(t.ops, txOp{workflowID: workflowID, task: &copied})
+	return nil
+}
+
+func (t *memoryTx) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	t.ops = append(t.ops, txOp{outbox: copyOutboxEntry(entry)})
+	return nil
+}
+
+// Commit applies the buffered writes. If a workflow version conflicts, or a
+// task belongs to a workflow that neither exists nor is saved earlier in the
+// transaction, nothing is applied.
+func (t *memoryTx) Commit() error {
+	if t.done {
+		return storage.ErrTxDone
+	}
+	t.done = true
+
+	m := t.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := make(map[string]int64) // workflow ID -> version once saved
+	next := make([]int64, len(t.ops))
+	for i, op := range t.ops {
+		if op.outbox != nil {
+			continue
+		}
+		current, saved := versions[op.workflowID]
+		if !saved {
+			current = m.versionLocked(op.workflowID)
+		}
+		if op.task == nil {
+			version, err := storage.NextVersion(op.workflow, current)
+			if err != nil {
+				return err
+			}
+			versions[op.workflowID] = version
+			next[i] = version
+			continue
+		}
+		if _, exists := m.workflows[op.workflowID]; !exists && !saved {
+			return &storage.NotFoundError{
+				EntityType: "workflow",
+				ID:         op.workflowID,
+			}
+		}
+	}
+	for i, op := range t.ops {
+		switch {
+		case op.outbox != nil:
+			m.outbox[op.outbox.ID] = op.outbox
+		case op.task == nil:
+			m.saveWorkflowLocked(op.workflow, next[i])
+			op.saved.Version = next[i]
+		default:
+			m.saveTaskLocked(op.workflowID, op.task)
+		}
+	}
+	return nil
+}
 
+func (t *memoryTx) Rollback() error {
+	t.done = true
+	t.ops = nil
 	return nil
 }
 
@@ -232,6 +489,105 @@ func (m *MemoryStorage) ListTasks(ctx context.Context, workflowID string) ([]*st
 	return result, nil
 }
 
+// SaveOutbox implements storage.Outbox.
+func (m *MemoryStorage) SaveOutbox(ctx context.Context, entry *storage.OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outbox[entry.ID] = copyOutboxEntry(entry)
+	return nil
+}
+
+// ListOutbox implements storage.Outbox.
+func (m *MemoryStorage) ListOutbox(ctx context.Context, limit int) ([]*storage.OutboxEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]*storage.OutboxEntry, 0, len(m.outbox))
+	for _, entry := range m.outbox {
+		entries = append(entries, copyOutboxEntry(entry))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// DeleteOutbox implements storage.Outbox.
+func (m *MemoryStorage) DeleteOutbox(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.outbox, id)
+	return nil
+}
+
+// SaveDedupe implements storage.DedupeStore.
+func (m *MemoryStorage) SaveDedupe(ctx context.Context, record *storage.DedupeRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *record
+	m.dedupes[record.Key] = &copied
+	return nil
+}
+
+// GetDedupe implements storage.DedupeStore. Expired records are dropped as
+// they are read.
+func (m *MemoryStorage) GetDedupe(ctx context.Context, key string) (*storage.DedupeRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.dedupes[key]
+	if ok && !record.ExpiresAt.After(time.Now()) {
+		delete(m.dedupes, key)
+		ok = false
+	}
+	if !ok {
+		return nil, &storage.NotFoundError{EntityType: "dedupe", ID: key}
+	}
+	copied := *record
+	return &copied, nil
+}
+
+// SaveResource implements storage.ResourceStore.
+func (m *MemoryStorage) SaveResource(ctx context.Context, record *storage.ResourceRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.resources[record.Kind] == nil {
+		m.resources[record.Kind] = make(map[string]json.RawMessage)
+	}
+	m.resources[record.Kind][record.Name] = slices.Clone(record.Data)
+	return nil
+}
+
+// DeleteResource implements storage.ResourceStore.
+func (m *MemoryStorage) DeleteResource(ctx context.Context, kind, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.resources[kind], name)
+	return nil
+}
+
+// ListResources implements storage.ResourceStore.
+func (m *MemoryStorage) ListResources(ctx context.Context, kind string) ([]*storage.ResourceRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]*storage.ResourceRecord, 0, len(m.resources[kind]))
+	for name, data := range m.resources[kind] {
+		records = append(records, &storage.ResourceRecord{Kind: kind, Name: name, Data: slices.Clone(data)})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records, nil
+}
+
+// copyOutboxEntry returns a copy of entry that shares no payload or
+// delivered targets.
+func copyOutboxEntry(entry *storage.OutboxEntry) *storage.OutboxEntry {
+	copied := *entry
+	copied.Payload = append([]byte(nil), entry.Payload...)
+	copied.Delivered = append([]string(nil), entry.Delivered...)
+	return &copied
+}
+
 // Close closes the storage (no-op for memory storage).
 func (m *MemoryStorage) Close() error {
 	return nil
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OutboxEntry is a notification recorded in the same transaction as the
+// state change it reports, and delivered after the commit. Entries stay in
+// the outbox until they are delivered, so a crash right after a state change
+// does not lose its notification.
+type OutboxEntry struct {
+	// ID identifies the entry and orders it after the entries created
+	// before it. Receivers can deduplicate deliveries on it.
+	ID string `json:"id"`
+
+	// Type is the event type of the notification.
+	Type string `json:"type"`
+
+	// Payload is the JSON encoded event payload.
+	Payload json.RawMessage `json:"payload"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Attempts counts the failed deliveries, the last failing with
+	// LastError. The next one is due at NextAttemptAt.
+	Attempts      int       `json:"attempts,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+
+	// Delivered lists the targets that accepted the notification, so that
+	// retries only go to the others.
+	Delivered []string `json:"delivered,omitempty"`
+}
+
+// NewOutboxEntry returns an entry of a notification created at now, with an
+// ID ordering it after the entries created before.
+func NewOutboxEntry(eventType string, payload any, now time.Time) (*OutboxEntry, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, &SerializationError{Operation: "marshal", Cause: err}
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("generate outbox entry id: %w", err)
+	}
+	return &OutboxEntry{
+		ID:        fmt.Sprintf("%020d-%s", now.UnixNano(), hex.EncodeToString(suffix)),
+		Type:      eventType,
+		Payload:   data,
+		CreatedAt: now,
+	}, nil
+}
+
+// OutboxWriter is implemented by storages, and their transactions, that can
+// record outbox entries. Saving an entry again replaces it.
+type OutboxWriter interface {
+	SaveOutbox(ctx context.Context, entry *OutboxEntry) error
+}
+
+// Outbox is implemented by storages that hold an outbox of notifications.
+type Outbox interface {
+	OutboxWriter
+
+	// ListOutbox returns up to limit entries, all of them if limit is not
+	// positive, in ID order.
+	ListOutbox(ctx context.Context, limit int) ([]*OutboxEntry, error)
+
+	// DeleteOutbox deletes a delivered entry. Deleting a missing entry is
+	// not an error.
+	DeleteOutbox(ctx context.Context, id string) error
+}
+
+// ErrOutboxUnsupported is returned when writing outbox entries to a storage,
+// or a transaction, without an outbox.
+var ErrOutboxUnsupported = errors.New("storage: outbox not supported")
+
+// SaveOutbox saves entry with w, failing with ErrOutboxUnsupported unless w
+// implements OutboxWriter.
+func SaveOutbox(ctx context.Context, w Writer, entry *OutboxEntry) error {
+	ow, ok := w.(OutboxWriter)
+	if !ok {
+		return ErrOutboxUnsupported
+	}
+	return ow.SaveOutbox(ctx, entry)
+}
+
+// ListOutbox lists the outbox of s, failing with ErrOutboxUnsupported unless
+// s implements Outbox.
+func ListOutbox(ctx context.Context, s Storage, limit int) ([]*OutboxEntry, error) {
+	outbox, ok := s.(Outbox)
+	if !ok {
+		return nil, ErrOutboxUnsupported
+	}
+	return outbox.ListOutbox(ctx, limit)
+}
+
+// DeleteOutbox deletes an entry of the outbox of s, failing with
+// ErrOutboxUnsupported unless s implements Outbox.
+func DeleteOutbox(ctx context.Context, s Storage, id string) error {
+	outbox, ok := s.(Outbox)
+	if !ok {
+		return ErrOutboxUnsupported
+	}
+	return outbox.DeleteOutbox(ctx, id)
+}
@@ -0,0 +1,1367 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.27.3
+// source: goclaw/storage/v1/state.proto
+
+package storagepbv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Persisted workflow record, the protobuf encoding of storage.WorkflowState.
+type WorkflowState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Tasks         []*TaskDefinition      `protobuf:"bytes,5,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	TaskStatus    map[string]*TaskState  `protobuf:"bytes,6,rep,name=task_status,json=taskStatus,proto3" json:"task_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	Priority      int32                  `protobuf:"varint,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	GangLayers    bool                   `protobuf:"varint,14,opt,name=gang_layers,json=gangLayers,proto3" json:"gang_layers,omitempty"`
+	Journal       []*JournalEntry        `protobuf:"bytes,15,rep,name=journal,proto3" json:"journal,omitempty"`
+	SlaDeadline   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=sla_deadline,json=slaDeadline,proto3" json:"sla_deadline,omitempty"`
+	SlaBreachedAt *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=sla_breached_at,json=slaBreachedAt,proto3" json:"sla_breached_at,omitempty"`
+	Usage         map[string]float64     `protobuf:"bytes,18,rep,name=usage,proto3" json:"usage,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	RequestId     string                 `protobuf:"bytes,19,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Version       int64                  `protobuf:"varint,20,opt,name=version,proto3" json:"version,omitempty"`
+	Values        map[string]string      `protobuf:"bytes,21,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CallbackUrl   string                 `protobuf:"bytes,22,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	Budget        *Budget                `protobuf:"bytes,23,opt,name=budget,proto3" json:"budget,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowState) Reset() {
+	*x = WorkflowState{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowState) ProtoMessage() {}
+
+func (x *WorkflowState) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowState.ProtoReflect.Descriptor instead.
+func (*WorkflowState) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WorkflowState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetTasks() []*TaskDefinition {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetTaskStatus() map[string]*TaskState {
+	if x != nil {
+		return x.TaskStatus
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *WorkflowState) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetGangLayers() bool {
+	if x != nil {
+		return x.GangLayers
+	}
+	return false
+}
+
+func (x *WorkflowState) GetJournal() []*JournalEntry {
+	if x != nil {
+		return x.Journal
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetSlaDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SlaDeadline
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetSlaBreachedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SlaBreachedAt
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetUsage() map[string]float64 {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *WorkflowState) GetValues() map[string]string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *WorkflowState) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *WorkflowState) GetBudget() *Budget {
+	if x != nil {
+		return x.Budget
+	}
+	return nil
+}
+
+// Task definition as submitted with the workflow.
+type TaskDefinition struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type      string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	DependsOn []string               `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// JSON-encoded task configuration.
+	ConfigJson       []byte            `protobuf:"bytes,5,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	Timeout          int32             `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Retries          int32             `protobuf:"varint,7,opt,name=retries,proto3" json:"retries,omitempty"`
+	DedupeKey        string            `protobuf:"bytes,8,opt,name=dedupe_key,json=dedupeKey,proto3" json:"dedupe_key,omitempty"`
+	Preemptible      bool              `protobuf:"varint,9,opt,name=preemptible,proto3" json:"preemptible,omitempty"`
+	Deadline         int32             `protobuf:"varint,10,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	Gang             string            `protobuf:"bytes,11,opt,name=gang,proto3" json:"gang,omitempty"`
+	AffinityKey      string            `protobuf:"bytes,12,opt,name=affinity_key,json=affinityKey,proto3" json:"affinity_key,omitempty"`
+	Resources        *TaskResources    `protobuf:"bytes,13,opt,name=resources,proto3" json:"resources,omitempty"`
+	HeartbeatTimeout int32             `protobuf:"varint,14,opt,name=heartbeat_timeout,json=heartbeatTimeout,proto3" json:"heartbeat_timeout,omitempty"`
+	StallPolicy      string            `protobuf:"bytes,15,opt,name=stall_policy,json=stallPolicy,proto3" json:"stall_policy,omitempty"`
+	Env              []string          `protobuf:"bytes,16,rep,name=env,proto3" json:"env,omitempty"`
+	Secrets          map[string]string `protobuf:"bytes,17,rep,name=secrets,proto3" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Container        *ContainerSpec    `protobuf:"bytes,18,opt,name=container,proto3" json:"container,omitempty"`
+	Locks            []string          `protobuf:"bytes,19,rep,name=locks,proto3" json:"locks,omitempty"`
+	RateLimits       []string          `protobuf:"bytes,20,rep,name=rate_limits,json=rateLimits,proto3" json:"rate_limits,omitempty"`
+	// JSON-encoded JSON Schema of the task's result.
+	OutputSchemaJson []byte `protobuf:"bytes,21,opt,name=output_schema_json,json=outputSchemaJson,proto3" json:"output_schema_json,omitempty"`
+	// Expression deciding whether the task runs.
+	When string `protobuf:"bytes,22,opt,name=when,proto3" json:"when,omitempty"`
+	// Expression deciding whether a failed attempt is retried.
+	RetryIf      string            `protobuf:"bytes,23,opt,name=retry_if,json=retryIf,proto3" json:"retry_if,omitempty"`
+	Compensation *TaskCompensation `protobuf:"bytes,24,opt,name=compensation,proto3" json:"compensation,omitempty"`
+	// Whether the task's config and result are sensitive.
+	Sensitive     bool `protobuf:"varint,25,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskDefinition) Reset() {
+	*x = TaskDefinition{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskDefinition) ProtoMessage() {}
+
+func (x *TaskDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskDefinition.ProtoReflect.Descriptor instead.
+func (*TaskDefinition) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TaskDefinition) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetConfigJson() []byte {
+	if x != nil {
+		return x.ConfigJson
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *TaskDefinition) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *TaskDefinition) GetDedupeKey() string {
+	if x != nil {
+		return x.DedupeKey
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetPreemptible() bool {
+	if x != nil {
+		return x.Preemptible
+	}
+	return false
+}
+
+func (x *TaskDefinition) GetDeadline() int32 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *TaskDefinition) GetGang() string {
+	if x != nil {
+		return x.Gang
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetAffinityKey() string {
+	if x != nil {
+		return x.AffinityKey
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetResources() *TaskResources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetHeartbeatTimeout() int32 {
+	if x != nil {
+		return x.HeartbeatTimeout
+	}
+	return 0
+}
+
+func (x *TaskDefinition) GetStallPolicy() string {
+	if x != nil {
+		return x.StallPolicy
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetSecrets() map[string]string {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetContainer() *ContainerSpec {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetLocks() []string {
+	if x != nil {
+		return x.Locks
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetRateLimits() []string {
+	if x != nil {
+		return x.RateLimits
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetOutputSchemaJson() []byte {
+	if x != nil {
+		return x.OutputSchemaJson
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetWhen() string {
+	if x != nil {
+		return x.When
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetRetryIf() string {
+	if x != nil {
+		return x.RetryIf
+	}
+	return ""
+}
+
+func (x *TaskDefinition) GetCompensation() *TaskCompensation {
+	if x != nil {
+		return x.Compensation
+	}
+	return nil
+}
+
+func (x *TaskDefinition) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+// Work undoing a completed task.
+type TaskCompensation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// JSON-encoded compensation configuration.
+	ConfigJson    []byte         `protobuf:"bytes,2,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	Container     *ContainerSpec `protobuf:"bytes,3,opt,name=container,proto3" json:"container,omitempty"`
+	Timeout       int32          `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCompensation) Reset() {
+	*x = TaskCompensation{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCompensation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCompensation) ProtoMessage() {}
+
+func (x *TaskCompensation) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCompensation.ProtoReflect.Descriptor instead.
+func (*TaskCompensation) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TaskCompensation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TaskCompensation) GetConfigJson() []byte {
+	if x != nil {
+		return x.ConfigJson
+	}
+	return nil
+}
+
+func (x *TaskCompensation) GetContainer() *ContainerSpec {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
+func (x *TaskCompensation) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// Container a task runs as.
+type ContainerSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         string                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Command       []string               `protobuf:"bytes,2,rep,name=command,proto3" json:"command,omitempty"`
+	Args          []string               `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	Env           map[string]string      `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerSpec) Reset() {
+	*x = ContainerSpec{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerSpec) ProtoMessage() {}
+
+func (x *ContainerSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerSpec.ProtoReflect.Descriptor instead.
+func (*ContainerSpec) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ContainerSpec) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ContainerSpec) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+// Resources a task needs while it runs.
+type TaskResources struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpu           float64                `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	MemoryMb      int64                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	Gpu           int32                  `protobuf:"varint,3,opt,name=gpu,proto3" json:"gpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskResources) Reset() {
+	*x = TaskResources{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskResources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskResources) ProtoMessage() {}
+
+func (x *TaskResources) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskResources.ProtoReflect.Descriptor instead.
+func (*TaskResources) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaskResources) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *TaskResources) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *TaskResources) GetGpu() int32 {
+	if x != nil {
+		return x.Gpu
+	}
+	return 0
+}
+
+// Persisted task record, the protobuf encoding of storage.TaskState.
+type TaskState struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status      string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	StartedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Error       string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// JSON-encoded task result.
+	ResultJson     []byte       `protobuf:"bytes,7,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"`
+	DedupeKey      string       `protobuf:"bytes,8,opt,name=dedupe_key,json=dedupeKey,proto3" json:"dedupe_key,omitempty"`
+	DedupedFrom    string       `protobuf:"bytes,9,opt,name=deduped_from,json=dedupedFrom,proto3" json:"deduped_from,omitempty"`
+	Preemptions    int32        `protobuf:"varint,10,opt,name=preemptions,proto3" json:"preemptions,omitempty"`
+	DeadlineMissed bool         `protobuf:"varint,11,opt,name=deadline_missed,json=deadlineMissed,proto3" json:"deadline_missed,omitempty"`
+	Stalls         int32        `protobuf:"varint,12,opt,name=stalls,proto3" json:"stalls,omitempty"`
+	Inputs         []*TaskInput `protobuf:"bytes,13,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Skipped        bool         `protobuf:"varint,14,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Sensitive      bool         `protobuf:"varint,15,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TaskState) Reset() {
+	*x = TaskState{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskState) ProtoMessage() {}
+
+func (x *TaskState) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskState.ProtoReflect.Descriptor instead.
+func (*TaskState) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TaskState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TaskState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskState) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TaskState) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *TaskState) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskState) GetResultJson() []byte {
+	if x != nil {
+		return x.ResultJson
+	}
+	return nil
+}
+
+func (x *TaskState) GetDedupeKey() string {
+	if x != nil {
+		return x.DedupeKey
+	}
+	return ""
+}
+
+func (x *TaskState) GetDedupedFrom() string {
+	if x != nil {
+		return x.DedupedFrom
+	}
+	return ""
+}
+
+func (x *TaskState) GetPreemptions() int32 {
+	if x != nil {
+		return x.Preemptions
+	}
+	return 0
+}
+
+func (x *TaskState) GetDeadlineMissed() bool {
+	if x != nil {
+		return x.DeadlineMissed
+	}
+	return false
+}
+
+func (x *TaskState) GetStalls() int32 {
+	if x != nil {
+		return x.Stalls
+	}
+	return 0
+}
+
+func (x *TaskState) GetInputs() []*TaskInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *TaskState) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *TaskState) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+// An output of another task that a task read.
+type TaskInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Artifact      string                 `protobuf:"bytes,3,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskInput) Reset() {
+	*x = TaskInput{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskInput) ProtoMessage() {}
+
+func (x *TaskInput) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskInput.ProtoReflect.Descriptor instead.
+func (*TaskInput) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TaskInput) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *TaskInput) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskInput) GetArtifact() string {
+	if x != nil {
+		return x.Artifact
+	}
+	return ""
+}
+
+// One task state transition of a workflow run.
+type JournalEntry struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Seq    int32                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	TaskId string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	From   string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To     string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Error  string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	At     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	// JSON-encoded upstream results the ended attempt read, by task ID.
+	InputsJson []byte `protobuf:"bytes,7,opt,name=inputs_json,json=inputsJson,proto3" json:"inputs_json,omitempty"`
+	// JSON-encoded result the ended attempt set.
+	OutputJson    []byte             `protobuf:"bytes,8,opt,name=output_json,json=outputJson,proto3" json:"output_json,omitempty"`
+	Decisions     []*JournalDecision `protobuf:"bytes,9,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JournalEntry) Reset() {
+	*x = JournalEntry{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JournalEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry) ProtoMessage() {}
+
+func (x *JournalEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry.ProtoReflect.Descriptor instead.
+func (*JournalEntry) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *JournalEntry) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *JournalEntry) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetInputsJson() []byte {
+	if x != nil {
+		return x.InputsJson
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetOutputJson() []byte {
+	if x != nil {
+		return x.OutputJson
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetDecisions() []*JournalDecision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+// A nondeterministic decision a task attempt made.
+type JournalDecision struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// JSON-encoded value decided.
+	ValueJson     []byte `protobuf:"bytes,2,opt,name=value_json,json=valueJson,proto3" json:"value_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JournalDecision) Reset() {
+	*x = JournalDecision{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JournalDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalDecision) ProtoMessage() {}
+
+func (x *JournalDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalDecision.ProtoReflect.Descriptor instead.
+func (*JournalDecision) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *JournalDecision) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *JournalDecision) GetValueJson() []byte {
+	if x != nil {
+		return x.ValueJson
+	}
+	return nil
+}
+
+// Usage caps of a workflow run.
+type Budget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        float64                `protobuf:"fixed64,1,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Cost          float64                `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	TaskSeconds   float64                `protobuf:"fixed64,3,opt,name=task_seconds,json=taskSeconds,proto3" json:"task_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Budget) Reset() {
+	*x = Budget{}
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Budget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Budget) ProtoMessage() {}
+
+func (x *Budget) ProtoReflect() protoreflect.Message {
+	mi := &file_goclaw_storage_v1_state_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Budget.ProtoReflect.Descriptor instead.
+func (*Budget) Descriptor() ([]byte, []int) {
+	return file_goclaw_storage_v1_state_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Budget) GetTokens() float64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+func (x *Budget) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *Budget) GetTaskSeconds() float64 {
+	if x != nil {
+		return x.TaskSeconds
+	}
+	return 0
+}
+
+var File_goclaw_storage_v1_state_proto protoreflect.FileDescriptor
+
+const file_goclaw_storage_v1_state_proto_rawDesc = "" +
+	"\n" +
+	"\x1dgoclaw/storage/v1/state.proto\x12\x11goclaw.storage.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xea\n" +
+	"\n" +
+	"\rWorkflowState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x127\n" +
+	"\x05tasks\x18\x05 \x03(\v2!.goclaw.storage.v1.TaskDefinitionR\x05tasks\x12Q\n" +
+	"\vtask_status\x18\x06 \x03(\v20.goclaw.storage.v1.WorkflowState.TaskStatusEntryR\n" +
+	"taskStatus\x12J\n" +
+	"\bmetadata\x18\a \x03(\v2..goclaw.storage.v1.WorkflowState.MetadataEntryR\bmetadata\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1a\n" +
+	"\bpriority\x18\f \x01(\x05R\bpriority\x126\n" +
+	"\bdeadline\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x12\x1f\n" +
+	"\vgang_layers\x18\x0e \x01(\bR\n" +
+	"gangLayers\x129\n" +
+	"\ajournal\x18\x0f \x03(\v2\x1f.goclaw.storage.v1.JournalEntryR\ajournal\x12=\n" +
+	"\fsla_deadline\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\vslaDeadline\x12B\n" +
+	"\x0fsla_breached_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\rslaBreachedAt\x12A\n" +
+	"\x05usage\x18\x12 \x03(\v2+.goclaw.storage.v1.WorkflowState.UsageEntryR\x05usage\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x13 \x01(\tR\trequestId\x12\x18\n" +
+	"\aversion\x18\x14 \x01(\x03R\aversion\x12D\n" +
+	"\x06values\x18\x15 \x03(\v2,.goclaw.storage.v1.WorkflowState.ValuesEntryR\x06values\x12!\n" +
+	"\fcallback_url\x18\x16 \x01(\tR\vcallbackUrl\x121\n" +
+	"\x06budget\x18\x17 \x01(\v2\x19.goclaw.storage.v1.BudgetR\x06budget\x1a[\n" +
+	"\x0fTaskStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
+	"\x05value\x18\x02 \x01(\v2\x1c.goclaw.storage.v1.TaskStateR\x05value:\x028\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"UsageEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb3\a\n" +
+	"\x0eTaskDefinition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x04 \x03(\tR\tdependsOn\x12\x1f\n" +
+	"\vconfig_json\x18\x05 \x01(\fR\n" +
+	"configJson\x12\x18\n" +
+	"\atimeout\x18\x06 \x01(\x05R\atimeout\x12\x18\n" +
+	"\aretries\x18\a \x01(\x05R\aretries\x12\x1d\n" +
+	"\n" +
+	"dedupe_key\x18\b \x01(\tR\tdedupeKey\x12 \n" +
+	"\vpreemptible\x18\t \x01(\bR\vpreemptible\x12\x1a\n" +
+	"\bdeadline\x18\n" +
+	" \x01(\x05R\bdeadline\x12\x12\n" +
+	"\x04gang\x18\v \x01(\tR\x04gang\x12!\n" +
+	"\faffinity_key\x18\f \x01(\tR\vaffinityKey\x12>\n" +
+	"\tresources\x18\r \x01(\v2 .goclaw.storage.v1.TaskResourcesR\tresources\x12+\n" +
+	"\x11heartbeat_timeout\x18\x0e \x01(\x05R\x10heartbeatTimeout\x12!\n" +
+	"\fstall_policy\x18\x0f \x01(\tR\vstallPolicy\x12\x10\n" +
+	"\x03env\x18\x10 \x03(\tR\x03env\x12H\n" +
+	"\asecrets\x18\x11 \x03(\v2..goclaw.storage.v1.TaskDefinition.SecretsEntryR\asecrets\x12>\n" +
+	"\tcontainer\x18\x12 \x01(\v2 .goclaw.storage.v1.ContainerSpecR\tcontainer\x12\x14\n" +
+	"\x05locks\x18\x13 \x03(\tR\x05locks\x12\x1f\n" +
+	"\vrate_limits\x18\x14 \x03(\tR\n" +
+	"rateLimits\x12,\n" +
+	"\x12output_schema_json\x18\x15 \x01(\fR\x10outputSchemaJson\x12\x12\n" +
+	"\x04when\x18\x16 \x01(\tR\x04when\x12\x19\n" +
+	"\bretry_if\x18\x17 \x01(\tR\aretryIf\x12G\n" +
+	"\fcompensation\x18\x18 \x01(\v2#.goclaw.storage.v1.TaskCompensationR\fcompensation\x12\x1c\n" +
+	"\tsensitive\x18\x19 \x01(\bR\tsensitive\x1a:\n" +
+	"\fSecretsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa1\x01\n" +
+	"\x10TaskCompensation\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vconfig_json\x18\x02 \x01(\fR\n" +
+	"configJson\x12>\n" +
+	"\tcontainer\x18\x03 \x01(\v2 .goclaw.storage.v1.ContainerSpecR\tcontainer\x12\x18\n" +
+	"\atimeout\x18\x04 \x01(\x05R\atimeout\"\xc8\x01\n" +
+	"\rContainerSpec\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12\x18\n" +
+	"\acommand\x18\x02 \x03(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x03 \x03(\tR\x04args\x12;\n" +
+	"\x03env\x18\x04 \x03(\v2).goclaw.storage.v1.ContainerSpec.EnvEntryR\x03env\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
+	"\rTaskResources\x12\x10\n" +
+	"\x03cpu\x18\x01 \x01(\x01R\x03cpu\x12\x1b\n" +
+	"\tmemory_mb\x18\x02 \x01(\x03R\bmemoryMb\x12\x10\n" +
+	"\x03gpu\x18\x03 \x01(\x05R\x03gpu\"\x8b\x04\n" +
+	"\tTaskState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1f\n" +
+	"\vresult_json\x18\a \x01(\fR\n" +
+	"resultJson\x12\x1d\n" +
+	"\n" +
+	"dedupe_key\x18\b \x01(\tR\tdedupeKey\x12!\n" +
+	"\fdeduped_from\x18\t \x01(\tR\vdedupedFrom\x12 \n" +
+	"\vpreemptions\x18\n" +
+	" \x01(\x05R\vpreemptions\x12'\n" +
+	"\x0fdeadline_missed\x18\v \x01(\bR\x0edeadlineMissed\x12\x16\n" +
+	"\x06stalls\x18\f \x01(\x05R\x06stalls\x124\n" +
+	"\x06inputs\x18\r \x03(\v2\x1c.goclaw.storage.v1.TaskInputR\x06inputs\x12\x18\n" +
+	"\askipped\x18\x0e \x01(\bR\askipped\x12\x1c\n" +
+	"\tsensitive\x18\x0f \x01(\bR\tsensitive\"a\n" +
+	"\tTaskInput\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bartifact\x18\x03 \x01(\tR\bartifact\"\xa3\x02\n" +
+	"\fJournalEntry\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x05R\x03seq\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x1f\n" +
+	"\vinputs_json\x18\a \x01(\fR\n" +
+	"inputsJson\x12\x1f\n" +
+	"\voutput_json\x18\b \x01(\fR\n" +
+	"outputJson\x12@\n" +
+	"\tdecisions\x18\t \x03(\v2\".goclaw.storage.v1.JournalDecisionR\tdecisions\"D\n" +
+	"\x0fJournalDecision\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1d\n" +
+	"\n" +
+	"value_json\x18\x02 \x01(\fR\tvalueJson\"W\n" +
+	"\x06Budget\x12\x16\n" +
+	"\x06tokens\x18\x01 \x01(\x01R\x06tokens\x12\x12\n" +
+	"\x04cost\x18\x02 \x01(\x01R\x04cost\x12!\n" +
+	"\ftask_seconds\x18\x03 \x01(\x01R\vtaskSecondsB8Z6github.com/goclaw/goclaw/pkg/storage/pb/v1;storagepbv1b\x06proto3"
+
+var (
+	file_goclaw_storage_v1_state_proto_rawDescOnce sync.Once
+	file_goclaw_storage_v1_state_proto_rawDescData []byte
+)
+
+func file_goclaw_storage_v1_state_proto_rawDescGZIP() []byte {
+	file_goclaw_storage_v1_state_proto_rawDescOnce.Do(func() {
+		file_goclaw_storage_v1_state_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goclaw_storage_v1_state_proto_rawDesc), len(file_goclaw_storage_v1_state_proto_rawDesc)))
+	})
+	return file_goclaw_storage_v1_state_proto_rawDescData
+}
+
+var file_goclaw_storage_v1_state_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_goclaw_storage_v1_state_proto_goTypes = []any{
+	(*WorkflowState)(nil),         // 0: goclaw.storage.v1.WorkflowState
+	(*TaskDefinition)(nil),        // 1: goclaw.storage.v1.TaskDefinition
+	(*TaskCompensation)(nil),      // 2: goclaw.storage.v1.TaskCompensation
+	(*ContainerSpec)(nil),         // 3: goclaw.storage.v1.ContainerSpec
+	(*TaskResources)(nil),         // 4: goclaw.storage.v1.TaskResources
+	(*TaskState)(nil),             // 5: goclaw.storage.v1.TaskState
+	(*TaskInput)(nil),             // 6: goclaw.storage.v1.TaskInput
+	(*JournalEntry)(nil),          // 7: goclaw.storage.v1.JournalEntry
+	(*JournalDecision)(nil),       // 8: goclaw.storage.v1.JournalDecision
+	(*Budget)(nil),                // 9: goclaw.storage.v1.Budget
+	nil,                           // 10: goclaw.storage.v1.WorkflowState.TaskStatusEntry
+	nil,                           // 11: goclaw.storage.v1.WorkflowState.MetadataEntry
+	nil,                           // 12: goclaw.storage.v1.WorkflowState.UsageEntry
+	nil,                           // 13: goclaw.storage.v1.WorkflowState.ValuesEntry
+	nil,                           // 14: goclaw.storage.v1.TaskDefinition.SecretsEntry
+	nil,                           // 15: goclaw.storage.v1.ContainerSpec.EnvEntry
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_goclaw_storage_v1_state_proto_depIdxs = []int32{
+	1,  // 0: goclaw.storage.v1.WorkflowState.tasks:type_name -> goclaw.storage.v1.TaskDefinition
+	10, // 1: goclaw.storage.v1.WorkflowState.task_status:type_name -> goclaw.storage.v1.WorkflowState.TaskStatusEntry
+	11, // 2: goclaw.storage.v1.WorkflowState.metadata:type_name -> goclaw.storage.v1.WorkflowState.MetadataEntry
+	16, // 3: goclaw.storage.v1.WorkflowState.created_at:type_name -> google.protobuf.Timestamp
+	16, // 4: goclaw.storage.v1.WorkflowState.started_at:type_name -> google.protobuf.Timestamp
+	16, // 5: goclaw.storage.v1.WorkflowState.completed_at:type_name -> google.protobuf.Timestamp
+	16, // 6: goclaw.storage.v1.WorkflowState.deadline:type_name -> google.protobuf.Timestamp
+	7,  // 7: goclaw.storage.v1.WorkflowState.journal:type_name -> goclaw.storage.v1.JournalEntry
+	16, // 8: goclaw.storage.v1.WorkflowState.sla_deadline:type_name -> google.protobuf.Timestamp
+	16, // 9: goclaw.storage.v1.WorkflowState.sla_breached_at:type_name -> google.protobuf.Timestamp
+	12, // 10: goclaw.storage.v1.WorkflowState.usage:type_name -> goclaw.storage.v1.WorkflowState.UsageEntry
+	13, // 11: goclaw.storage.v1.WorkflowState.values:type_name -> goclaw.storage.v1.WorkflowState.ValuesEntry
+	9,  // 12: goclaw.storage.v1.WorkflowState.budget:type_name -> goclaw.storage.v1.Budget
+	4,  // 13: goclaw.storage.v1.TaskDefinition.resources:type_name -> goclaw.storage.v1.TaskResources
+	14, // 14: goclaw.storage.v1.TaskDefinition.secrets:type_name -> goclaw.storage.v1.TaskDefinition.SecretsEntry
+	3,  // 15: goclaw.storage.v1.TaskDefinition.container:type_name -> goclaw.storage.v1.ContainerSpec
+	2,  // 16: goclaw.storage.v1.TaskDefinition.compensation:type_name -> goclaw.storage.v1.TaskCompensation
+	3,  // 17: goclaw.storage.v1.TaskCompensation.container:type_name -> goclaw.storage.v1.ContainerSpec
+	15, // 18: goclaw.storage.v1.ContainerSpec.env:type_name -> goclaw.storage.v1.ContainerSpec.EnvEntry
+	16, // 19: goclaw.storage.v1.TaskState.started_at:type_name -> google.protobuf.Timestamp
+	16, // 20: goclaw.storage.v1.TaskState.completed_at:type_name -> google.protobuf.Timestamp
+	6,  // 21: goclaw.storage.v1.TaskState.inputs:type_name -> goclaw.storage.v1.TaskInput
+	16, // 22: goclaw.storage.v1.JournalEntry.at:type_name -> google.protobuf.Timestamp
+	8,  // 23: goclaw.storage.v1.JournalEntry.decisions:type_name -> goclaw.storage.v1.JournalDecision
+	5,  // 24: goclaw.storage.v1.WorkflowState.TaskStatusEntry.value:type_name -> goclaw.storage.v1.TaskState
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_goclaw_storage_v1_state_proto_init() }
+func file_goclaw_storage_v1_state_proto_init() {
+	if File_goclaw_storage_v1_state_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goclaw_storage_v1_state_proto_rawDesc), len(file_goclaw_storage_v1_state_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_goclaw_storage_v1_state_proto_goTypes,
+		DependencyIndexes: file_goclaw_storage_v1_state_proto_depIdxs,
+		MessageInfos:      file_goclaw_storage_v1_state_proto_msgTypes,
+	}.Build()
+	File_goclaw_storage_v1_state_proto = out.File
+	file_goclaw_storage_v1_state_proto_goTypes = nil
+	file_goclaw_storage_v1_state_proto_depIdxs = nil
+}
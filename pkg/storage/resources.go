@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ResourceRecord is a resource managed through the API, such as a schedule
+// or the tenants a tool is enabled for, stored so that it survives restarts.
+type ResourceRecord struct {
+	// Kind is the kind of the resource, and Name identifies it within its
+	// kind.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Data is the JSON encoded resource.
+	Data json.RawMessage `json:"data"`
+}
+
+// ResourceStore is implemented by storages that keep managed resources.
+type ResourceStore interface {
+	// SaveResource saves record, replacing the record of its kind and name.
+	SaveResource(ctx context.Context, record *ResourceRecord) error
+
+	// DeleteResource deletes the record of kind and name. Deleting a missing
+	// record is not an error.
+	DeleteResource(ctx context.Context, kind, name string) error
+
+	// ListResources returns the records of kind in name order.
+	ListResources(ctx context.Context, kind string) ([]*ResourceRecord, error)
+}
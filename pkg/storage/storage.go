@@ -3,15 +3,19 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/goclaw/goclaw/pkg/api/models"
+	"github.com/goclaw/goclaw/pkg/errs"
 )
 
 // Storage defines the interface for persistent storage operations.
 type Storage interface {
-	// Workflow operations
+	// Workflow operations. SaveWorkflow compares and swaps on
+	// WorkflowState.Version.
 	SaveWorkflow(ctx context.Context, wf *WorkflowState) error
 	GetWorkflow(ctx context.Context, id string) (*WorkflowState, error)
 	ListWorkflows(ctx context.Context, filter *WorkflowFilter) ([]*WorkflowState, int, error)
@@ -26,30 +30,224 @@ type Storage interface {
 	Close() error
 }
 
+// TaskBatchSaver is implemented by storages that can save several task states
+// of a workflow in a single commit. The engine uses it to group buffered task
+// transitions; storages without it get one SaveTask call per task.
+type TaskBatchSaver interface {
+	SaveTasks(ctx context.Context, workflowID string, tasks []*TaskState) error
+}
+
+// WorkflowScanner is implemented by storages that can visit the workflows
+// matching a filter one at a time, without loading the whole listing or
+// counting it. The filter's Offset and Limit apply, workflows are visited in
+// a stable order, and fn returning an error stops the scan with it.
+type WorkflowScanner interface {
+	ScanWorkflows(ctx context.Context, filter *WorkflowFilter, fn func(*WorkflowState) error) error
+}
+
+// ScanWorkflows visits the workflows of s matching filter, using
+// WorkflowScanner when s implements it and a single ListWorkflows call
+// otherwise.
+func ScanWorkflows(ctx context.Context, s Storage, filter *WorkflowFilter, fn func(*WorkflowState) error) error {
+	if scanner, ok := s.(WorkflowScanner); ok {
+		return scanner.ScanWorkflows(ctx, filter, fn)
+	}
+	workflows, _, err := s.ListWorkflows(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, wf := range workflows {
+		if err := fn(wf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshotter is implemented by storages that can visit every workflow with
+// its tasks as of a single point in time, so that writes made during the
+// visit are not seen. Workflows are visited in a stable order, and fn
+// returning an error stops the visit with it.
+type Snapshotter interface {
+	Snapshot(ctx context.Context, fn func(wf *WorkflowState, tasks []*TaskState) error) error
+}
+
+// Snapshot visits every workflow of s with its tasks, using Snapshotter when
+// s implements it. Other storages are read one workflow at a time, so the
+// visit is only consistent if s is not written meanwhile.
+func Snapshot(ctx context.Context, s Storage, fn func(wf *WorkflowState, tasks []*TaskState) error) error {
+	if snapshotter, ok := s.(Snapshotter); ok {
+		return snapshotter.Snapshot(ctx, fn)
+	}
+	return ScanWorkflows(ctx, s, nil, func(wf *WorkflowState) error {
+		tasks, err := s.ListTasks(ctx, wf.ID)
+		if err != nil {
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				return err
+			}
+		}
+		return fn(wf, tasks)
+	})
+}
+
+// Writer is the write side of Storage, as seen by a transaction.
+type Writer interface {
+	SaveWorkflow(ctx context.Context, wf *WorkflowState) error
+	SaveTask(ctx context.Context, workflowID string, task *TaskState) error
+}
+
+// Tx is a storage transaction. Its writes become visible together on Commit
+// and are discarded by Rollback. Rollback after Commit is a no-op, so it can
+// be deferred. A Tx is not safe for concurrent use.
+//
+// Workflow versions are checked as in SaveWorkflow, and saved workflows get
+// their new Version on Commit, so a workflow is saved at most once per
+// transaction.
+type Tx interface {
+	Writer
+	Commit() error
+	Rollback() error
+}
+
+// ErrTxDone is returned by a Tx used after Commit or Rollback.
+var ErrTxDone = errors.New("storage: transaction already committed or rolled back")
+
+// Transactional is implemented by storages that can apply several writes
+// atomically. Callers usually go through WriteBatch instead.
+type Transactional interface {
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+// WriteBatch runs fn with a transaction of s, committing it if fn returns nil
+// and rolling it back otherwise. Storages that do not implement Transactional
+// get fn's writes directly, one at a time.
+func WriteBatch(ctx context.Context, s Storage, fn func(w Writer) error) error {
+	txs, ok := s.(Transactional)
+	if !ok {
+		return fn(s)
+	}
+	tx, err := txs.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Invalidator is implemented by caching storages. The engine calls Invalidate
+// whenever it emits a workflow or task state change, with an empty taskID for
+// workflow changes.
+type Invalidator interface {
+	Invalidate(workflowID, taskID string)
+}
+
 // WorkflowState represents the persisted state of a workflow.
+//
+// Version is the revision of the record. Every save increments it and sets it
+// on the saved WorkflowState. A workflow with a non-zero Version is only saved
+// if the stored record still has that version, otherwise SaveWorkflow fails
+// with a VersionConflictError; this keeps concurrent read-modify-write cycles,
+// such as a cancel and a transition on different nodes, from silently
+// overwriting each other. A zero Version saves unconditionally.
 type WorkflowState struct {
-	ID          string                  `json:"id"`
-	Name        string                  `json:"name"`
-	Description string                  `json:"description"`
-	Status      string                  `json:"status"`
-	Tasks       []models.TaskDefinition `json:"tasks"`
-	TaskStatus  map[string]*TaskState   `json:"task_status"`
-	Metadata    map[string]string       `json:"metadata"`
-	CreatedAt   time.Time               `json:"created_at"`
-	StartedAt   *time.Time              `json:"started_at,omitempty"`
-	CompletedAt *time.Time              `json:"completed_at,omitempty"`
-	Error       string                  `json:"error,omitempty"`
+	ID            string                  `json:"id"`
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description"`
+	Status        string                  `json:"status"`
+	Tasks         []models.TaskDefinition `json:"tasks"`
+	TaskStatus    map[string]*TaskState   `json:"task_status"`
+	Metadata      map[string]string       `json:"metadata"`
+	CreatedAt     time.Time               `json:"created_at"`
+	StartedAt     *time.Time              `json:"started_at,omitempty"`
+	CompletedAt   *time.Time              `json:"completed_at,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+	Priority      int                     `json:"priority,omitempty"`
+	Deadline      *time.Time              `json:"deadline,omitempty"`
+	GangLayers    bool                    `json:"gang_layers,omitempty"`
+	Journal       []JournalEntry          `json:"journal,omitempty"`
+	SLADeadline   *time.Time              `json:"sla_deadline,omitempty"`
+	SLABreachedAt *time.Time              `json:"sla_breached_at,omitempty"`
+	Usage         map[string]float64      `json:"usage,omitempty"`
+	Values        map[string]string       `json:"values,omitempty"`
+	RequestID     string                  `json:"request_id,omitempty"`
+	CallbackURL   string                  `json:"callback_url,omitempty"`
+	Budget        *models.Budget          `json:"budget,omitempty"`
+	Version       int64                   `json:"version,omitempty"`
+}
+
+// NextVersion returns the version wf is saved with when the stored record has
+// version current (0 if there is none), or a VersionConflictError if wf was
+// read at another version.
+func NextVersion(wf *WorkflowState, current int64) (int64, error) {
+	if wf.Version != 0 && wf.Version != current {
+		return 0, &VersionConflictError{ID: wf.ID, Expected: wf.Version, Actual: current}
+	}
+	return current + 1, nil
+}
+
+// JournalEntry records one task state transition of a workflow run, in the
+// order the engine observed it. The journal of a finished run is enough to
+// replay its scheduling with stubbed executors, and its task attempts with
+// what they read, set and decided fed back.
+type JournalEntry struct {
+	Seq    int       `json:"seq"`
+	TaskID string    `json:"task_id"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+
+	// Inputs, Output and Decisions are recorded on the entries ending a
+	// task attempt, the transitions from running: the upstream results the
+	// attempt read by task ID, the result it set when it succeeded, and the
+	// nondeterministic decisions it made, in order. Values of sensitive
+	// tasks are recorded as models.RedactedValue.
+	Inputs    map[string]any    `json:"inputs,omitempty"`
+	Output    any               `json:"output,omitempty"`
+	Decisions []JournalDecision `json:"decisions,omitempty"`
+}
+
+// JournalDecision is a nondeterministic decision a task attempt made, such
+// as reading the time, so that a replay of the attempt can make it again.
+type JournalDecision struct {
+	// Kind names what was decided, e.g. "time", "random" or
+	// "model_route:<route>".
+	Kind string `json:"kind"`
+
+	// Value is the JSON encoding of the value decided.
+	Value json.RawMessage `json:"value"`
 }
 
 // TaskState represents the persisted state of a task.
 type TaskState struct {
-	ID          string      `json:"id"`
-	Name        string      `json:"name"`
-	Status      string      `json:"status"`
-	StartedAt   *time.Time  `json:"started_at,omitempty"`
-	CompletedAt *time.Time  `json:"completed_at,omitempty"`
-	Error       string      `json:"error,omitempty"`
-	Result      interface{} `json:"result,omitempty"`
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Status         string      `json:"status"`
+	StartedAt      *time.Time  `json:"started_at,omitempty"`
+	CompletedAt    *time.Time  `json:"completed_at,omitempty"`
+	Error          string      `json:"error,omitempty"`
+	Result         interface{} `json:"result,omitempty"`
+	DedupeKey      string      `json:"dedupe_key,omitempty"`
+	DedupedFrom    string      `json:"deduped_from,omitempty"`
+	Preemptions    int         `json:"preemptions,omitempty"`
+	DeadlineMissed bool        `json:"deadline_missed,omitempty"`
+	Stalls         int         `json:"stalls,omitempty"`
+	Skipped        bool        `json:"skipped,omitempty"`
+	Inputs         []TaskInput `json:"inputs,omitempty"`
+	// Sensitive is copied from the task's definition, so that its result is
+	// sealed when it is saved on its own.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// TaskInput is an output of another task that a task read.
+type TaskInput struct {
+	WorkflowID string `json:"workflow_id"`
+	TaskID     string `json:"task_id"`
+	Artifact   string `json:"artifact,omitempty"`
 }
 
 // WorkflowFilter defines filtering options for listing workflows.
@@ -69,6 +267,9 @@ func (e *NotFoundError) Error() string {
 	return fmt.Sprintf("%s not found: %s", e.EntityType, e.ID)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *NotFoundError) ErrorCode() errs.Code { return errs.NotFound }
+
 // DuplicateKeyError indicates that an entity with the given ID already exists.
 type DuplicateKeyError struct {
 	EntityType string
@@ -79,6 +280,24 @@ func (e *DuplicateKeyError) Error() string {
 	return fmt.Sprintf("%s already exists: %s", e.EntityType, e.ID)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *DuplicateKeyError) ErrorCode() errs.Code { return errs.AlreadyExists }
+
+// VersionConflictError indicates that a workflow was changed since it was
+// read.
+type VersionConflictError struct {
+	ID       string
+	Expected int64
+	Actual   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("workflow %s was modified concurrently: expected version %d, found %d", e.ID, e.Expected, e.Actual)
+}
+
+// ErrorCode implements errs.Coder.
+func (e *VersionConflictError) ErrorCode() errs.Code { return errs.Conflict }
+
 // StorageUnavailableError indicates that the storage backend is unavailable.
 type StorageUnavailableError struct {
 	Cause error
@@ -88,6 +307,9 @@ func (e *StorageUnavailableError) Error() string {
 	return fmt.Sprintf("storage unavailable: %v", e.Cause)
 }
 
+// ErrorCode implements errs.Coder.
+func (e *StorageUnavailableError) ErrorCode() errs.Code { return errs.ServiceUnavailable }
+
 // SerializationError indicates a failure in data serialization/deserialization.
 type SerializationError struct {
 	Operation string
@@ -2,7 +2,10 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,8 +21,16 @@ type StorageTestSuite struct {
 func (s *StorageTestSuite) RunAllTests(t *testing.T) {
 	t.Run("WorkflowCRUD", s.TestWorkflowCRUD)
 	t.Run("TaskPersistence", s.TestTaskPersistence)
+	t.Run("TaskBatchSave", s.TestTaskBatchSave)
+	t.Run("Transactions", s.TestTransactions)
+	t.Run("WorkflowVersioning", s.TestWorkflowVersioning)
 	t.Run("ListWorkflowsWithFilter", s.TestListWorkflowsWithFilter)
 	t.Run("ListWorkflowsWithPagination", s.TestListWorkflowsWithPagination)
+	t.Run("ScanWorkflows", s.TestScanWorkflows)
+	t.Run("Snapshot", s.TestSnapshot)
+	t.Run("Outbox", s.TestOutbox)
+	t.Run("Dedupe", s.TestDedupe)
+	t.Run("Resources", s.TestResources)
 	t.Run("DeleteWorkflowCascade", s.TestDeleteWorkflowCascade)
 	t.Run("ConcurrentAccess", s.TestConcurrentAccess)
 	t.Run("ErrorHandling", s.TestErrorHandling)
@@ -112,6 +123,379 @@ func (s *StorageTestSuite) TestWorkflowCRUD(t *testing.T) {
 	}
 }
 
+// TestTaskBatchSave tests SaveTasks on storages implementing TaskBatchSaver.
+func (s *StorageTestSuite) TestTaskBatchSave(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	saver, ok := store.(TaskBatchSaver)
+	if !ok {
+		t.Skip("storage does not implement TaskBatchSaver")
+	}
+	ctx := context.Background()
+
+	if err := saver.SaveTasks(ctx, "missing", []*TaskState{{ID: "task-1"}}); err == nil {
+		t.Fatal("expected error saving tasks of a missing workflow")
+	}
+
+	wf := &WorkflowState{ID: "wf-batch", Name: "Batch", Status: "running", CreatedAt: time.Now()}
+	if err := store.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	tasks := []*TaskState{
+		{ID: "task-1", Name: "Task 1", Status: "completed"},
+		{ID: "task-2", Name: "Task 2", Status: "running"},
+	}
+	if err := saver.SaveTasks(ctx, wf.ID, tasks); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	saved, err := store.ListTasks(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(saved) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(saved))
+	}
+	for _, task := range saved {
+		want := tasks[0]
+		if task.ID == "task-2" {
+			want = tasks[1]
+		}
+		if task.ID != want.ID || task.Status != want.Status {
+			t.Errorf("expected %s %s, got %s %s", want.ID, want.Status, task.ID, task.Status)
+		}
+	}
+}
+
+// TestTransactions tests BeginTx on storages implementing Transactional.
+func (s *StorageTestSuite) TestTransactions(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	txs, ok := store.(Transactional)
+	if !ok {
+		t.Skip("storage does not implement Transactional")
+	}
+	ctx := context.Background()
+
+	// A task can be saved for a workflow saved earlier in the transaction,
+	// and nothing is visible before Commit.
+	tx, err := txs.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	wf := &WorkflowState{ID: "wf-tx", Name: "Tx", Status: "pending", CreatedAt: time.Now()}
+	if err := tx.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if err := tx.SaveTask(ctx, wf.ID, &TaskState{ID: "task-1", Status: "pending"}); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	if _, err := store.GetWorkflow(ctx, wf.ID); err == nil {
+		t.Fatal("expected workflow to be invisible before Commit")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback after Commit failed: %v", err)
+	}
+	if _, err := store.GetWorkflow(ctx, wf.ID); err != nil {
+		t.Fatalf("GetWorkflow after Commit failed: %v", err)
+	}
+	if task, err := store.GetTask(ctx, wf.ID, "task-1"); err != nil || task.Status != "pending" {
+		t.Fatalf("expected committed pending task, got %+v, %v", task, err)
+	}
+
+	// Rolled back writes are discarded.
+	tx, err = txs.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if err := tx.SaveTask(ctx, wf.ID, &TaskState{ID: "task-1", Status: "running"}); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	if err := tx.SaveWorkflow(ctx, &WorkflowState{ID: "wf-tx-rolled-back", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if task, err := store.GetTask(ctx, wf.ID, "task-1"); err != nil || task.Status != "pending" {
+		t.Fatalf("expected rolled back task to stay pending, got %+v, %v", task, err)
+	}
+	if _, err := store.GetWorkflow(ctx, "wf-tx-rolled-back"); err == nil {
+		t.Fatal("expected rolled back workflow to be discarded")
+	}
+
+	// WriteBatch applies nothing when a write fails.
+	err = WriteBatch(ctx, store, func(w Writer) error {
+		if err := w.SaveWorkflow(ctx, &WorkflowState{ID: "wf-tx-partial", CreatedAt: time.Now()}); err != nil {
+			return err
+		}
+		return w.SaveTask(ctx, "missing", &TaskState{ID: "task-1"})
+	})
+	if err == nil {
+		t.Fatal("expected error saving a task of a missing workflow")
+	}
+	if _, err := store.GetWorkflow(ctx, "wf-tx-partial"); err == nil {
+		t.Fatal("expected failed batch to leave no workflow behind")
+	}
+}
+
+// TestOutbox tests that outbox entries commit and roll back with the state
+// changes written alongside them, and are listed in ID order.
+func (s *StorageTestSuite) TestOutbox(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	if _, ok := store.(Outbox); !ok {
+		t.Skip("storage does not implement Outbox")
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	first, err := NewOutboxEntry("workflow.state_changed", map[string]string{"workflow_id": "wf-outbox"}, now)
+	if err != nil {
+		t.Fatalf("NewOutboxEntry failed: %v", err)
+	}
+	err = WriteBatch(ctx, store, func(w Writer) error {
+		if err := w.SaveWorkflow(ctx, &WorkflowState{ID: "wf-outbox", Status: "running", CreatedAt: now}); err != nil {
+			return err
+		}
+		return SaveOutbox(ctx, w, first)
+	})
+	if err != nil {
+		t.Fatalf("WriteBatch failed: %v", err)
+	}
+
+	// A failed batch leaves no entry behind.
+	discarded, _ := NewOutboxEntry("workflow.state_changed", nil, now.Add(time.Millisecond))
+	err = WriteBatch(ctx, store, func(w Writer) error {
+		if err := SaveOutbox(ctx, w, discarded); err != nil {
+			return err
+		}
+		return w.SaveTask(ctx, "missing", &TaskState{ID: "task-1"})
+	})
+	if err == nil {
+		t.Fatal("expected error saving a task of a missing workflow")
+	}
+
+	second, _ := NewOutboxEntry("workflow.sla_breached", nil, now.Add(2*time.Millisecond))
+	if err := SaveOutbox(ctx, store, second); err != nil {
+		t.Fatalf("SaveOutbox failed: %v", err)
+	}
+
+	entries, err := ListOutbox(ctx, store, 0)
+	if err != nil {
+		t.Fatalf("ListOutbox failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != first.ID || entries[1].ID != second.ID {
+		t.Fatalf("expected the two committed entries in order, got %+v", entries)
+	}
+	if entries[0].Type != "workflow.state_changed" || string(entries[0].Payload) != `{"workflow_id":"wf-outbox"}` {
+		t.Fatalf("unexpected entry %+v", entries[0])
+	}
+	if entries, _ := ListOutbox(ctx, store, 1); len(entries) != 1 || entries[0].ID != first.ID {
+		t.Fatalf("expected the first entry only, got %+v", entries)
+	}
+
+	// Saving an entry again replaces it.
+	first.Attempts = 1
+	first.LastError = "connection refused"
+	if err := SaveOutbox(ctx, store, first); err != nil {
+		t.Fatalf("SaveOutbox failed: %v", err)
+	}
+	if err := DeleteOutbox(ctx, store, second.ID); err != nil {
+		t.Fatalf("DeleteOutbox failed: %v", err)
+	}
+	if err := DeleteOutbox(ctx, store, second.ID); err != nil {
+		t.Fatalf("DeleteOutbox of a deleted entry failed: %v", err)
+	}
+	entries, err = ListOutbox(ctx, store, 0)
+	if err != nil {
+		t.Fatalf("ListOutbox failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Attempts != 1 || entries[0].LastError != "connection refused" {
+		t.Fatalf("expected the retried entry only, got %+v", entries)
+	}
+}
+
+// TestDedupe tests the records of dedupe keys of storages implementing
+// DedupeStore.
+func (s *StorageTestSuite) TestDedupe(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	dedupes, ok := store.(DedupeStore)
+	if !ok {
+		t.Skip("storage does not implement DedupeStore")
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	var notFound *NotFoundError
+	if _, err := dedupes.GetDedupe(ctx, "fetch:users"); !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError for a missing key, got %v", err)
+	}
+	record := &DedupeRecord{
+		Key:         "fetch:users",
+		WorkflowID:  "wf-1",
+		TaskID:      "fetch",
+		Result:      map[string]any{"count": float64(3)},
+		CompletedAt: now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	if err := dedupes.SaveDedupe(ctx, record); err != nil {
+		t.Fatalf("SaveDedupe failed: %v", err)
+	}
+	got, err := dedupes.GetDedupe(ctx, "fetch:users")
+	if err != nil {
+		t.Fatalf("GetDedupe failed: %v", err)
+	}
+	if got.WorkflowID != "wf-1" || got.TaskID != "fetch" || got.Result.(map[string]any)["count"] != float64(3) {
+		t.Fatalf("unexpected record %+v", got)
+	}
+
+	expired := &DedupeRecord{Key: "fetch:orders", WorkflowID: "wf-1", TaskID: "orders", CompletedAt: now, ExpiresAt: now.Add(-time.Second)}
+	if err := dedupes.SaveDedupe(ctx, expired); err != nil {
+		t.Fatalf("SaveDedupe failed: %v", err)
+	}
+	if _, err := dedupes.GetDedupe(ctx, "fetch:orders"); !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError for an expired key, got %v", err)
+	}
+}
+
+// TestResources tests the managed resources of storages implementing
+// ResourceStore.
+func (s *StorageTestSuite) TestResources(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	resources, ok := store.(ResourceStore)
+	if !ok {
+		t.Skip("storage does not implement ResourceStore")
+	}
+	ctx := context.Background()
+
+	for _, record := range []*ResourceRecord{
+		{Kind: "schedule", Name: "nightly", Data: json.RawMessage(`{"cron":"0 0 * * *"}`)},
+		{Kind: "schedule", Name: "hourly", Data: json.RawMessage(`{"cron":"0 * * * *"}`)},
+		{Kind: "schedules", Name: "other-kind", Data: json.RawMessage(`{}`)},
+		{Kind: "schedule", Name: "nightly", Data: json.RawMessage(`{"cron":"0 1 * * *"}`)},
+	} {
+		if err := resources.SaveResource(ctx, record); err != nil {
+			t.Fatalf("SaveResource failed: %v", err)
+		}
+	}
+	records, err := resources.ListResources(ctx, "schedule")
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+	if len(records) != 2 || records[0].Name != "hourly" || records[1].Name != "nightly" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if string(records[1].Data) != `{"cron":"0 1 * * *"}` || records[1].Kind != "schedule" {
+		t.Fatalf("unexpected record %+v", records[1])
+	}
+
+	if err := resources.DeleteResource(ctx, "schedule", "hourly"); err != nil {
+		t.Fatalf("DeleteResource failed: %v", err)
+	}
+	if err := resources.DeleteResource(ctx, "schedule", "missing"); err != nil {
+		t.Fatalf("DeleteResource of a missing record failed: %v", err)
+	}
+	if records, err = resources.ListResources(ctx, "schedule"); err != nil || len(records) != 1 || records[0].Name != "nightly" {
+		t.Fatalf("ListResources after delete = %+v, %v", records, err)
+	}
+}
+
+// TestWorkflowVersioning tests the compare-and-swap semantics of
+// SaveWorkflow on WorkflowState.Version.
+func (s *StorageTestSuite) TestWorkflowVersioning(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	wf := &WorkflowState{ID: "wf-version", Name: "Version", Status: "pending", CreatedAt: time.Now()}
+	if err := store.SaveWorkflow(ctx, wf); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if wf.Version != 1 {
+		t.Fatalf("expected version 1 after create, got %d", wf.Version)
+	}
+
+	first, err := store.GetWorkflow(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	second, err := store.GetWorkflow(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	first.Status = "running"
+	if err := store.SaveWorkflow(ctx, first); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	if first.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %d", first.Version)
+	}
+
+	// The second copy was read at version 1 and must not overwrite the first
+	// update.
+	second.Status = "cancelled"
+	err = store.SaveWorkflow(ctx, second)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected VersionConflictError, got %v", err)
+	}
+	if conflict.Expected != 1 || conflict.Actual != 2 {
+		t.Errorf("expected conflict between versions 1 and 2, got %d and %d", conflict.Expected, conflict.Actual)
+	}
+	stored, err := store.GetWorkflow(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	if stored.Status != "running" || stored.Version != 2 {
+		t.Fatalf("expected running at version 2, got %s at %d", stored.Status, stored.Version)
+	}
+
+	// A zero version saves unconditionally.
+	if err := store.SaveWorkflow(ctx, &WorkflowState{ID: wf.ID, Status: "failed", CreatedAt: wf.CreatedAt}); err != nil {
+		t.Fatalf("unconditional SaveWorkflow failed: %v", err)
+	}
+
+	txs, ok := store.(Transactional)
+	if !ok {
+		return
+	}
+	// Transactions check versions too and set them on Commit.
+	tx, err := txs.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer tx.Rollback()
+	if err := tx.SaveWorkflow(ctx, first); err == nil {
+		if err := tx.Commit(); err == nil {
+			t.Fatal("expected stale transactional save to fail")
+		}
+	}
+	latest, err := store.GetWorkflow(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	err = WriteBatch(ctx, store, func(w Writer) error {
+		return w.SaveWorkflow(ctx, latest)
+	})
+	if err != nil {
+		t.Fatalf("WriteBatch failed: %v", err)
+	}
+	if latest.Version != 4 {
+		t.Fatalf("expected version 4 after commit, got %d", latest.Version)
+	}
+}
+
 // TestTaskPersistence tests task save and retrieval.
 func (s *StorageTestSuite) TestTaskPersistence(t *testing.T) {
 	store := s.NewStorage(t)
@@ -279,6 +663,121 @@ func (s *StorageTestSuite) TestListWorkflowsWithPagination(t *testing.T) {
 	}
 }
 
+// TestScanWorkflows tests visiting workflows through WorkflowScanner.
+func (s *StorageTestSuite) TestScanWorkflows(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	scanner, ok := store.(WorkflowScanner)
+	if !ok {
+		t.Skip("storage does not implement WorkflowScanner")
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 6; i++ {
+		status := "running"
+		if i%3 == 2 {
+			status = "completed"
+		}
+		wf := &WorkflowState{
+			ID:         "wf-scan-" + string(rune('a'+i)),
+			Name:       "Scan",
+			Status:     status,
+			TaskStatus: map[string]*TaskState{},
+			CreatedAt:  time.Now(),
+		}
+		if err := store.SaveWorkflow(ctx, wf); err != nil {
+			t.Fatalf("SaveWorkflow failed: %v", err)
+		}
+		if err := store.SaveTask(ctx, wf.ID, &TaskState{ID: "task-1", Status: status}); err != nil {
+			t.Fatalf("SaveTask failed: %v", err)
+		}
+	}
+
+	scan := func(filter *WorkflowFilter) []string {
+		t.Helper()
+		var ids []string
+		err := scanner.ScanWorkflows(ctx, filter, func(wf *WorkflowState) error {
+			if filter != nil && len(filter.Status) > 0 && wf.Status != filter.Status[0] {
+				t.Errorf("workflow %s has status %s, want %s", wf.ID, wf.Status, filter.Status[0])
+			}
+			ids = append(ids, wf.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ScanWorkflows failed: %v", err)
+		}
+		return ids
+	}
+
+	all := scan(nil)
+	if len(all) != 6 {
+		t.Fatalf("expected 6 workflows, got %v", all)
+	}
+	running := scan(&WorkflowFilter{Status: []string{"running"}})
+	if len(running) != 4 {
+		t.Fatalf("expected 4 running workflows, got %v", running)
+	}
+	page := scan(&WorkflowFilter{Status: []string{"running"}, Offset: 1, Limit: 2})
+	if len(page) != 2 || page[0] != running[1] || page[1] != running[2] {
+		t.Fatalf("expected %v, got %v", running[1:3], page)
+	}
+
+	stop := errors.New("stop")
+	visited := 0
+	err := scanner.ScanWorkflows(ctx, nil, func(*WorkflowState) error {
+		visited++
+		return stop
+	})
+	if err != stop || visited != 1 {
+		t.Fatalf("expected the scan to stop at the first error, got %v after %d", err, visited)
+	}
+}
+
+// TestSnapshot tests visiting workflows and their tasks through Snapshot.
+func (s *StorageTestSuite) TestSnapshot(t *testing.T) {
+	store := s.NewStorage(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		wf := &WorkflowState{
+			ID:         "wf-snap-" + string(rune('a'+i)),
+			Name:       "Snapshot",
+			Status:     "completed",
+			TaskStatus: map[string]*TaskState{},
+			CreatedAt:  time.Now(),
+		}
+		if err := store.SaveWorkflow(ctx, wf); err != nil {
+			t.Fatalf("SaveWorkflow failed: %v", err)
+		}
+		for j := 0; j <= i; j++ {
+			task := &TaskState{ID: "task-" + string(rune('1'+j)), Status: "completed"}
+			if err := store.SaveTask(ctx, wf.ID, task); err != nil {
+				t.Fatalf("SaveTask failed: %v", err)
+			}
+		}
+	}
+
+	tasks := make(map[string]int)
+	err := Snapshot(ctx, store, func(wf *WorkflowState, ts []*TaskState) error {
+		tasks[wf.ID] = len(ts)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	want := map[string]int{"wf-snap-a": 1, "wf-snap-b": 2, "wf-snap-c": 3}
+	if len(tasks) != len(want) {
+		t.Fatalf("expected %v, got %v", want, tasks)
+	}
+	for id, n := range want {
+		if tasks[id] != n {
+			t.Errorf("workflow %s: expected %d tasks, got %d", id, n, tasks[id])
+		}
+	}
+}
+
 // TestDeleteWorkflowCascade tests that deleting a workflow also deletes its tasks.
 func (s *StorageTestSuite) TestDeleteWorkflowCascade(t *testing.T) {
 	store := s.NewStorage(t)
@@ -351,9 +850,11 @@ func (s *StorageTestSuite) TestConcurrentAccess(t *testing.T) {
 		t.Fatalf("SaveWorkflow failed: %v", err)
 	}
 
-	// Concurrent updates
+	// Concurrent read-modify-write cycles: each either wins or fails with a
+	// version conflict, and no update is silently lost.
 	var wg sync.WaitGroup
-	errors := make(chan error, 10)
+	errCh := make(chan error, 10)
+	var saved atomic.Int64
 
 	for i := 0; i < 10; i++ {
 		wg.Add(1)
@@ -363,7 +864,7 @@ func (s *StorageTestSuite) TestConcurrentAccess(t *testing.T) {
 			// Read
 			retrieved, err := store.GetWorkflow(ctx, "wf-concurrent")
 			if err != nil {
-				errors <- err
+				errCh <- err
 				return
 			}
 
@@ -371,24 +872,35 @@ func (s *StorageTestSuite) TestConcurrentAccess(t *testing.T) {
 			retrieved.Metadata = map[string]string{"iteration": string(rune('0' + idx))}
 
 			// Write
-			if err := store.SaveWorkflow(ctx, retrieved); err != nil {
-				errors <- err
+			err = store.SaveWorkflow(ctx, retrieved)
+			var conflict *VersionConflictError
+			switch {
+			case err == nil:
+				saved.Add(1)
+			case !errors.As(err, &conflict):
+				errCh <- err
 			}
 		}(i)
 	}
 
 	wg.Wait()
-	close(errors)
+	close(errCh)
 
 	// Check for errors
-	for err := range errors {
+	for err := range errCh {
 		t.Errorf("concurrent operation failed: %v", err)
 	}
 
-	// Verify workflow still exists
-	_, err = store.GetWorkflow(ctx, "wf-concurrent")
+	// Every successful save bumped the version once
+	final, err := store.GetWorkflow(ctx, "wf-concurrent")
 	if err != nil {
-		t.Errorf("GetWorkflow after concurrent updates failed: %v", err)
+		t.Fatalf("GetWorkflow after concurrent updates failed: %v", err)
+	}
+	if saved.Load() == 0 {
+		t.Fatal("expected at least one concurrent update to succeed")
+	}
+	if final.Version != 1+saved.Load() {
+		t.Errorf("expected version %d after %d updates, got %d", 1+saved.Load(), saved.Load(), final.Version)
 	}
 }
 
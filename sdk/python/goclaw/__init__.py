@@ -0,0 +1,18 @@
+"""Python client for the Goclaw gRPC and HTTP APIs.
+
+Client wraps the generated gRPC stubs of the goclaw.v1 package, and
+RestClient calls the HTTP API. Both raise the errors of the underlying
+transport: grpc.RpcError for gRPC calls and APIError for HTTP calls.
+"""
+
+from importlib.metadata import PackageNotFoundError, version
+
+from goclaw.client import Client
+from goclaw.rest import APIError, RestClient
+
+try:
+    __version__ = version("goclaw")
+except PackageNotFoundError:  # running from a source checkout
+    __version__ = "0.0.0"
+
+__all__ = ["APIError", "Client", "RestClient", "__version__"]
@@ -0,0 +1,193 @@
+"""gRPC client for the Goclaw services."""
+
+from __future__ import annotations
+
+from typing import Iterator, Optional, Sequence
+
+import grpc
+from google.protobuf import field_mask_pb2
+
+from goclaw.v1 import (
+    admin_pb2_grpc,
+    batch_pb2_grpc,
+    saga_batch_pb2_grpc,
+    saga_pb2_grpc,
+    signal_pb2_grpc,
+    streaming_pb2,
+    streaming_pb2_grpc,
+    workflow_pb2,
+    workflow_pb2_grpc,
+)
+
+_TERMINAL_STATUSES = frozenset(
+    {
+        workflow_pb2.WORKFLOW_STATUS_COMPLETED,
+        workflow_pb2.WORKFLOW_STATUS_FAILED,
+        workflow_pb2.WORKFLOW_STATUS_CANCELLED,
+    }
+)
+
+
+class Client:
+    """Client of the Goclaw gRPC services.
+
+    The convenience methods cover the common workflow calls. Every service
+    is also available as a generated stub, such as ``client.saga`` or
+    ``client.admin``, whose calls carry the same token.
+    """
+
+    def __init__(
+        self,
+        address: str,
+        *,
+        token: Optional[str] = None,
+        tls: bool = False,
+        root_certificates: Optional[bytes] = None,
+        private_key: Optional[bytes] = None,
+        certificate_chain: Optional[bytes] = None,
+        timeout: Optional[float] = 30.0,
+        options: Optional[Sequence[tuple]] = None,
+    ) -> None:
+        """Connects to the server at address (host:port).
+
+        token is sent as a bearer token with every call, for servers with
+        authentication enabled. With tls, the server is verified against
+        root_certificates, or the system roots, and private_key and
+        certificate_chain identify the client to servers requiring mTLS.
+        timeout is the default deadline of unary calls in seconds; streams
+        have none.
+        """
+        if tls or root_certificates or certificate_chain:
+            credentials = grpc.ssl_channel_credentials(
+                root_certificates=root_certificates,
+                private_key=private_key,
+                certificate_chain=certificate_chain,
+            )
+            channel = grpc.secure_channel(address, credentials, options=options)
+        else:
+            channel = grpc.insecure_channel(address, options=options)
+        self._channel = channel
+        if token:
+            channel = grpc.intercept_channel(channel, _TokenInterceptor(token))
+        self.timeout = timeout
+
+        self.workflow = workflow_pb2_grpc.WorkflowServiceStub(channel)
+        self.streaming = streaming_pb2_grpc.StreamingServiceStub(channel)
+        self.batch = batch_pb2_grpc.BatchServiceStub(channel)
+        self.saga = saga_pb2_grpc.SagaServiceStub(channel)
+        self.saga_batch = saga_batch_pb2_grpc.SagaBatchServiceStub(channel)
+        self.signal = signal_pb2_grpc.SignalServiceStub(channel)
+        self.admin = admin_pb2_grpc.AdminServiceStub(channel)
+
+    def close(self) -> None:
+        """Closes the connection."""
+        self._channel.close()
+
+    def __enter__(self) -> "Client":
+        return self
+
+    def __exit__(self, *exc) -> None:
+        self.close()
+
+    def submit_workflow(
+        self, request: workflow_pb2.SubmitWorkflowRequest
+    ) -> workflow_pb2.SubmitWorkflowResponse:
+        """Submits a workflow."""
+        return self.workflow.SubmitWorkflow(request, timeout=self.timeout)
+
+    def get_workflow(
+        self, workflow_id: str, fields: Sequence[str] = ()
+    ) -> workflow_pb2.GetWorkflowStatusResponse:
+        """Returns the status of a workflow, limited to fields if given."""
+        request = workflow_pb2.GetWorkflowStatusRequest(workflow_id=workflow_id)
+        if fields:
+            request.field_mask.CopyFrom(field_mask_pb2.FieldMask(paths=list(fields)))
+        return self.workflow.GetWorkflowStatus(request, timeout=self.timeout)
+
+    def list_workflows(
+        self, status: int = workflow_pb2.WORKFLOW_STATUS_UNSPECIFIED
+    ) -> Iterator[workflow_pb2.WorkflowSummary]:
+        """Yields every workflow, or those with status, without paging."""
+        request = workflow_pb2.ListWorkflowsStreamRequest(status_filter=status)
+        return self.workflow.ListWorkflowsStream(request)
+
+    def cancel_workflow(
+        self, workflow_id: str, force: bool = False
+    ) -> workflow_pb2.CancelWorkflowResponse:
+        """Cancels a workflow."""
+        request = workflow_pb2.CancelWorkflowRequest(workflow_id=workflow_id, force=force)
+        return self.workflow.CancelWorkflow(request, timeout=self.timeout)
+
+    def get_task_result(
+        self, workflow_id: str, task_id: str
+    ) -> workflow_pb2.GetTaskResultResponse:
+        """Returns the result of a task."""
+        request = workflow_pb2.GetTaskResultRequest(workflow_id=workflow_id, task_id=task_id)
+        return self.workflow.GetTaskResult(request, timeout=self.timeout)
+
+    def watch_workflow(
+        self, workflow_id: str, resume_from_sequence: int = 0
+    ) -> Iterator[streaming_pb2.WorkflowStatusUpdate]:
+        """Yields the status updates of a workflow as they happen.
+
+        Pass the sequence number of the last update seen as
+        resume_from_sequence to resume a broken stream without gaps.
+        """
+        request = streaming_pb2.WatchWorkflowRequest(
+            workflow_id=workflow_id, resume_from_sequence=resume_from_sequence
+        )
+        return self.streaming.WatchWorkflow(request)
+
+    def wait_for_completion(
+        self, workflow_id: str, timeout: Optional[float] = None
+    ) -> workflow_pb2.GetWorkflowStatusResponse:
+        """Waits until a workflow completes, fails or is cancelled, and
+        returns its final status.
+
+        Raises grpc.RpcError with DEADLINE_EXCEEDED if timeout seconds pass
+        first.
+        """
+        request = streaming_pb2.WatchWorkflowRequest(workflow_id=workflow_id)
+        for update in self.streaming.WatchWorkflow(request, timeout=timeout):
+            if update.status in _TERMINAL_STATUSES:
+                return self.get_workflow(workflow_id)
+        raise RuntimeError(f"watch of workflow {workflow_id} ended before it completed")
+
+
+class _CallDetails(grpc.ClientCallDetails):
+    def __init__(self, details: grpc.ClientCallDetails, metadata: list) -> None:
+        self.method = details.method
+        self.timeout = details.timeout
+        self.metadata = metadata
+        self.credentials = details.credentials
+        self.wait_for_ready = details.wait_for_ready
+        self.compression = details.compression
+
+
+class _TokenInterceptor(
+    grpc.UnaryUnaryClientInterceptor,
+    grpc.UnaryStreamClientInterceptor,
+    grpc.StreamUnaryClientInterceptor,
+    grpc.StreamStreamClientInterceptor,
+):
+    """Adds the bearer token to the metadata of every call."""
+
+    def __init__(self, token: str) -> None:
+        self._header = ("authorization", f"Bearer {token}")
+
+    def _details(self, details: grpc.ClientCallDetails) -> grpc.ClientCallDetails:
+        metadata = list(details.metadata or [])
+        metadata.append(self._header)
+        return _CallDetails(details, metadata)
+
+    def intercept_unary_unary(self, continuation, details, request):
+        return continuation(self._details(details), request)
+
+    def intercept_unary_stream(self, continuation, details, request):
+        return continuation(self._details(details), request)
+
+    def intercept_stream_unary(self, continuation, details, request_iterator):
+        return continuation(self._details(details), request_iterator)
+
+    def intercept_stream_stream(self, continuation, details, request_iterator):
+        return continuation(self._details(details), request_iterator)
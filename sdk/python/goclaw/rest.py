@@ -0,0 +1,182 @@
+"""HTTP client for the Goclaw REST API.
+
+The request and response bodies are the JSON documents described by the
+OpenAPI document the server publishes at /openapi.json.
+"""
+
+from __future__ import annotations
+
+import json
+import ssl
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, Mapping, Optional, Sequence
+
+
+class APIError(Exception):
+    """An error response of the HTTP API.
+
+    code is one of the stable error codes of the API, such as NOT_FOUND or
+    RATE_LIMITED, and retryable tells whether the call may succeed if
+    repeated.
+    """
+
+    def __init__(
+        self,
+        status: int,
+        code: str,
+        message: str,
+        retryable: bool = False,
+        request_id: str = "",
+        details: Optional[Dict[str, Any]] = None,
+    ) -> None:
+        super().__init__(f"{status} {code}: {message}")
+        self.status = status
+        self.code = code
+        self.message = message
+        self.retryable = retryable
+        self.request_id = request_id
+        self.details = details or {}
+
+
+class RestClient:
+    """Client of the Goclaw HTTP API at base_url, such as
+    http://localhost:8080.
+
+    headers are sent with every request, and context configures TLS, for
+    example the client certificate of servers requiring mTLS.
+    """
+
+    def __init__(
+        self,
+        base_url: str,
+        *,
+        headers: Optional[Mapping[str, str]] = None,
+        timeout: Optional[float] = 30.0,
+        context: Optional[ssl.SSLContext] = None,
+    ) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.headers = dict(headers or {})
+        self.timeout = timeout
+        self.context = context
+
+    def submit_workflow(self, workflow: Mapping[str, Any]) -> Dict[str, Any]:
+        """Submits a workflow and returns its ID and initial status."""
+        return self.request("POST", "/api/v1/workflows", body=workflow)
+
+    def execute_workflow(self, workflow: Mapping[str, Any], timeout: float = 30.0) -> Dict[str, Any]:
+        """Runs a workflow and returns the results of its tasks once it
+        finished. Runs taking longer than timeout seconds are cancelled and
+        raise APIError with status 504."""
+        return self.request(
+            "POST",
+            "/api/v1/workflows:execute",
+            body=workflow,
+            query={"timeout": f"{timeout:g}s"},
+            timeout=timeout + 10,
+        )
+
+    def plan_workflow(self, plan: Mapping[str, Any]) -> Dict[str, Any]:
+        """Submits a planner task whose result proposes a workflow, and
+        returns the ID of the planner workflow. The proposed workflow is
+        stored in the planned status and runs once approved."""
+        return self.request("POST", "/api/v1/workflows:plan", body=plan)
+
+    def get_workflow(self, workflow_id: str, view: str = "full") -> Dict[str, Any]:
+        """Returns the status of a workflow. With view "summary", task counts
+        replace the task list."""
+        return self.request("GET", f"/api/v1/workflows/{_quote(workflow_id)}", query={"view": view})
+
+    def wait_workflow(self, workflow_id: str, timeout: float = 60.0) -> Dict[str, Any]:
+        """Waits up to timeout seconds for a workflow to complete, fail or
+        be cancelled, and returns its status, which is not final if the
+        timeout elapsed first."""
+        return self.request(
+            "GET",
+            f"/api/v1/workflows/{_quote(workflow_id)}/wait",
+            query={"timeout": f"{timeout:g}s"},
+            timeout=timeout + 10,
+        )
+
+    def list_workflows(
+        self, status: Optional[str] = None, limit: int = 10, offset: int = 0
+    ) -> Dict[str, Any]:
+        """Returns a page of workflow summaries."""
+        query: Dict[str, Any] = {"limit": limit, "offset": offset}
+        if status:
+            query["status"] = status
+        return self.request("GET", "/api/v1/workflows", query=query)
+
+    def cancel_workflow(self, workflow_id: str) -> Dict[str, Any]:
+        """Cancels a workflow."""
+        return self.request("POST", f"/api/v1/workflows/{_quote(workflow_id)}/cancel")
+
+    def approve_workflow(self, workflow_id: str) -> Dict[str, Any]:
+        """Starts a planned workflow and returns its status. A plan is
+        rejected by cancelling it."""
+        return self.request("POST", f"/api/v1/workflows/{_quote(workflow_id)}/approve")
+
+    def append_tasks(self, workflow_id: str, tasks: Sequence[Mapping[str, Any]]) -> Dict[str, Any]:
+        """Appends tasks to a running workflow and returns its status."""
+        return self.request(
+            "PATCH", f"/api/v1/workflows/{_quote(workflow_id)}/tasks", body={"tasks": list(tasks)}
+        )
+
+    def get_task_result(self, workflow_id: str, task_id: str) -> Dict[str, Any]:
+        """Returns the result of a task."""
+        return self.request(
+            "GET", f"/api/v1/workflows/{_quote(workflow_id)}/tasks/{_quote(task_id)}/result"
+        )
+
+    def request(
+        self,
+        method: str,
+        path: str,
+        *,
+        body: Any = None,
+        query: Optional[Mapping[str, Any]] = None,
+        timeout: Optional[float] = None,
+    ) -> Any:
+        """Calls any JSON endpoint of the API and returns the decoded
+        response, or None for an empty one. timeout overrides the timeout of
+        the client.
+
+        Raises APIError for error responses.
+        """
+        url = self.base_url + path
+        if query:
+            url += "?" + urllib.parse.urlencode(query)
+        headers = {"Accept": "application/json", **self.headers}
+        data = None
+        if body is not None:
+            data = json.dumps(body).encode()
+            headers["Content-Type"] = "application/json"
+        req = urllib.request.Request(url, data=data, headers=headers, method=method)
+        try:
+            with urllib.request.urlopen(
+                req, timeout=timeout or self.timeout, context=self.context
+            ) as resp:
+                payload = resp.read()
+        except urllib.error.HTTPError as err:
+            raise _api_error(err.code, err.read()) from None
+        return json.loads(payload) if payload else None
+
+
+def _quote(segment: str) -> str:
+    return urllib.parse.quote(segment, safe="")
+
+
+def _api_error(status: int, payload: bytes) -> APIError:
+    try:
+        detail = json.loads(payload)["error"]
+    except (ValueError, KeyError, TypeError):
+        return APIError(status, "UNKNOWN", payload.decode(errors="replace").strip())
+    return APIError(
+        status,
+        detail.get("code", "UNKNOWN"),
+        detail.get("message", ""),
+        retryable=detail.get("retryable", False),
+        request_id=detail.get("request_id", ""),
+        details=detail.get("details"),
+    )
@@ -0,0 +1,147 @@
+import type { MessageInitShape } from "@bufbuild/protobuf";
+import {
+  createClient,
+  type CallOptions,
+  type Client,
+  type Interceptor,
+  type Transport,
+} from "@connectrpc/connect";
+
+import { AdminService } from "./gen/goclaw/v1/admin_pb.js";
+import { BatchService } from "./gen/goclaw/v1/batch_pb.js";
+import { SagaBatchService } from "./gen/goclaw/v1/saga_batch_pb.js";
+import { SagaService } from "./gen/goclaw/v1/saga_pb.js";
+import { SignalService } from "./gen/goclaw/v1/signal_pb.js";
+import {
+  StreamingService,
+  type WorkflowStatusUpdate,
+} from "./gen/goclaw/v1/streaming_pb.js";
+import {
+  WorkflowService,
+  WorkflowStatus,
+  type CancelWorkflowResponse,
+  type GetTaskResultResponse,
+  type GetWorkflowStatusResponse,
+  type SubmitWorkflowRequestSchema,
+  type SubmitWorkflowResponse,
+  type WorkflowSummary,
+} from "./gen/goclaw/v1/workflow_pb.js";
+
+const terminalStatuses = new Set<WorkflowStatus>([
+  WorkflowStatus.COMPLETED,
+  WorkflowStatus.FAILED,
+  WorkflowStatus.CANCELLED,
+]);
+
+/**
+ * Client of the Goclaw gRPC services over a Connect transport: a gRPC-Web
+ * transport in browsers, or a gRPC transport in Node.js.
+ *
+ * The methods cover the common workflow calls. Every service is also
+ * available as a generated client, such as `client.saga` or `client.admin`.
+ */
+export class GoclawClient {
+  readonly workflow: Client<typeof WorkflowService>;
+  readonly streaming: Client<typeof StreamingService>;
+  readonly batch: Client<typeof BatchService>;
+  readonly saga: Client<typeof SagaService>;
+  readonly sagaBatch: Client<typeof SagaBatchService>;
+  readonly signal: Client<typeof SignalService>;
+  readonly admin: Client<typeof AdminService>;
+
+  constructor(transport: Transport) {
+    this.workflow = createClient(WorkflowService, transport);
+    this.streaming = createClient(StreamingService, transport);
+    this.batch = createClient(BatchService, transport);
+    this.saga = createClient(SagaService, transport);
+    this.sagaBatch = createClient(SagaBatchService, transport);
+    this.signal = createClient(SignalService, transport);
+    this.admin = createClient(AdminService, transport);
+  }
+
+  /** Submits a workflow. */
+  submitWorkflow(
+    request: MessageInitShape<typeof SubmitWorkflowRequestSchema>,
+    options?: CallOptions,
+  ): Promise<SubmitWorkflowResponse> {
+    return this.workflow.submitWorkflow(request, options);
+  }
+
+  /** Returns the status of a workflow, limited to fields if given. */
+  getWorkflow(
+    workflowId: string,
+    fields: string[] = [],
+    options?: CallOptions,
+  ): Promise<GetWorkflowStatusResponse> {
+    return this.workflow.getWorkflowStatus(
+      { workflowId, fieldMask: fields.length > 0 ? { paths: fields } : undefined },
+      options,
+    );
+  }
+
+  /** Yields every workflow, or those with status, without paging. */
+  listWorkflows(
+    status: WorkflowStatus = WorkflowStatus.UNSPECIFIED,
+    options?: CallOptions,
+  ): AsyncIterable<WorkflowSummary> {
+    return this.workflow.listWorkflowsStream({ statusFilter: status }, options);
+  }
+
+  /** Cancels a workflow. */
+  cancelWorkflow(
+    workflowId: string,
+    force = false,
+    options?: CallOptions,
+  ): Promise<CancelWorkflowResponse> {
+    return this.workflow.cancelWorkflow({ workflowId, force }, options);
+  }
+
+  /** Returns the result of a task. */
+  getTaskResult(
+    workflowId: string,
+    taskId: string,
+    options?: CallOptions,
+  ): Promise<GetTaskResultResponse> {
+    return this.workflow.getTaskResult({ workflowId, taskId }, options);
+  }
+
+  /**
+   * Yields the status updates of a workflow as they happen. Pass the
+   * sequence number of the last update seen as resumeFromSequence to resume
+   * a broken stream without gaps.
+   */
+  watchWorkflow(
+    workflowId: string,
+    resumeFromSequence = 0n,
+    options?: CallOptions,
+  ): AsyncIterable<WorkflowStatusUpdate> {
+    return this.streaming.watchWorkflow({ workflowId, resumeFromSequence }, options);
+  }
+
+  /**
+   * Waits until a workflow completes, fails or is cancelled, and returns its
+   * final status. Use options.timeoutMs or options.signal to stop waiting.
+   */
+  async waitForCompletion(
+    workflowId: string,
+    options?: CallOptions,
+  ): Promise<GetWorkflowStatusResponse> {
+    for await (const update of this.streaming.watchWorkflow({ workflowId }, options)) {
+      if (terminalStatuses.has(update.status)) {
+        return this.getWorkflow(workflowId, [], { signal: options?.signal });
+      }
+    }
+    throw new Error(`watch of workflow ${workflowId} ended before it completed`);
+  }
+}
+
+/**
+ * Returns an interceptor sending token as a bearer token with every call,
+ * for servers with authentication enabled.
+ */
+export function bearerToken(token: string): Interceptor {
+  return (next) => (req) => {
+    req.header.set("authorization", `Bearer ${token}`);
+    return next(req);
+  };
+}
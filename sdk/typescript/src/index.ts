@@ -0,0 +1,3 @@
+export { GoclawClient, bearerToken } from "./client.js";
+export { ApiError, RestClient, type JsonObject, type RestClientOptions } from "./rest.js";
+export { WorkflowStatus } from "./gen/goclaw/v1/workflow_pb.js";
@@ -0,0 +1,189 @@
+/**
+ * A JSON document of the HTTP API, as described by the OpenAPI document the
+ * server publishes at /openapi.json.
+ */
+export type JsonObject = Record<string, any>;
+
+/**
+ * An error response of the HTTP API. code is one of the stable error codes
+ * of the API, such as NOT_FOUND or RATE_LIMITED, and retryable tells whether
+ * the call may succeed if repeated.
+ */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly code: string,
+    message: string,
+    readonly retryable = false,
+    readonly requestId = "",
+    readonly details: JsonObject = {},
+  ) {
+    super(`${status} ${code}: ${message}`);
+    this.name = "ApiError";
+  }
+}
+
+/** Options of a RestClient. */
+export interface RestClientOptions {
+  /** Headers sent with every request. */
+  headers?: Record<string, string>;
+  /** The fetch implementation, globalThis.fetch by default. */
+  fetch?: typeof fetch;
+}
+
+/**
+ * Client of the Goclaw HTTP API at baseUrl, such as http://localhost:8080.
+ */
+export class RestClient {
+  private readonly baseUrl: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetch: typeof fetch;
+
+  constructor(baseUrl: string, options: RestClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/+$/, "");
+    this.headers = options.headers ?? {};
+    this.fetch = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  /** Submits a workflow and returns its ID and initial status. */
+  submitWorkflow(workflow: JsonObject, init?: RequestInit): Promise<JsonObject> {
+    return this.request("POST", "/api/v1/workflows", { body: workflow, init });
+  }
+
+  /**
+   * Runs a workflow and returns the results of its tasks once it finished.
+   * Runs taking longer than timeout (a duration such as "10s", at most "5m")
+   * are cancelled and reject with a 504 ApiError.
+   */
+  executeWorkflow(workflow: JsonObject, timeout = "30s", init?: RequestInit): Promise<JsonObject> {
+    return this.request("POST", "/api/v1/workflows:execute", { body: workflow, query: { timeout }, init });
+  }
+
+  /**
+   * Submits a planner task whose result proposes a workflow, and returns the
+   * ID of the planner workflow. The proposed workflow is stored in the planned
+   * status and runs once approved.
+   */
+  planWorkflow(plan: JsonObject, init?: RequestInit): Promise<JsonObject> {
+    return this.request("POST", "/api/v1/workflows:plan", { body: plan, init });
+  }
+
+  /**
+   * Returns the status of a workflow. With view "summary", task counts
+   * replace the task list.
+   */
+  getWorkflow(workflowId: string, view = "full", init?: RequestInit): Promise<JsonObject> {
+    return this.request("GET", `/api/v1/workflows/${encodeURIComponent(workflowId)}`, {
+      query: { view },
+      init,
+    });
+  }
+
+  /**
+   * Waits up to timeout (a duration such as "60s", at most "5m") for a
+   * workflow to complete, fail or be cancelled, and returns its status, which
+   * is not final if the timeout elapsed first.
+   */
+  waitWorkflow(workflowId: string, timeout = "60s", init?: RequestInit): Promise<JsonObject> {
+    return this.request("GET", `/api/v1/workflows/${encodeURIComponent(workflowId)}/wait`, {
+      query: { timeout },
+      init,
+    });
+  }
+
+  /** Returns a page of workflow summaries. */
+  listWorkflows(
+    query: { status?: string; limit?: number; offset?: number } = {},
+    init?: RequestInit,
+  ): Promise<JsonObject> {
+    return this.request("GET", "/api/v1/workflows", { query, init });
+  }
+
+  /** Cancels a workflow. */
+  cancelWorkflow(workflowId: string, init?: RequestInit): Promise<JsonObject> {
+    return this.request("POST", `/api/v1/workflows/${encodeURIComponent(workflowId)}/cancel`, {
+      init,
+    });
+  }
+
+  /** Starts a planned workflow and returns its status. A plan is rejected by cancelling it. */
+  approveWorkflow(workflowId: string, init?: RequestInit): Promise<JsonObject> {
+    return this.request("POST", `/api/v1/workflows/${encodeURIComponent(workflowId)}/approve`, {
+      init,
+    });
+  }
+
+  /** Appends tasks to a running workflow and returns its status. */
+  appendTasks(workflowId: string, tasks: JsonObject[], init?: RequestInit): Promise<JsonObject> {
+    return this.request("PATCH", `/api/v1/workflows/${encodeURIComponent(workflowId)}/tasks`, {
+      body: { tasks },
+      init,
+    });
+  }
+
+  /** Returns the result of a task. */
+  getTaskResult(workflowId: string, taskId: string, init?: RequestInit): Promise<JsonObject> {
+    const path = `/api/v1/workflows/${encodeURIComponent(workflowId)}/tasks/${encodeURIComponent(taskId)}/result`;
+    return this.request("GET", path, { init });
+  }
+
+  /**
+   * Calls any JSON endpoint of the API and returns the decoded response, or
+   * undefined for an empty one. Rejects with ApiError for error responses.
+   */
+  async request<T = JsonObject>(
+    method: string,
+    path: string,
+    options: {
+      body?: unknown;
+      query?: Record<string, string | number | boolean | undefined>;
+      init?: RequestInit;
+    } = {},
+  ): Promise<T> {
+    let url = this.baseUrl + path;
+    if (options.query) {
+      const params = new URLSearchParams();
+      for (const [key, value] of Object.entries(options.query)) {
+        if (value !== undefined) {
+          params.set(key, String(value));
+        }
+      }
+      const search = params.toString();
+      if (search) {
+        url += `?${search}`;
+      }
+    }
+    const headers: Record<string, string> = { Accept: "application/json", ...this.headers };
+    let body: string | undefined;
+    if (options.body !== undefined) {
+      body = JSON.stringify(options.body);
+      headers["Content-Type"] = "application/json";
+    }
+    const resp = await this.fetch(url, { ...options.init, method, headers, body });
+    const text = await resp.text();
+    if (!resp.ok) {
+      throw apiError(resp.status, text);
+    }
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+}
+
+function apiError(status: number, text: string): ApiError {
+  let detail: JsonObject | undefined;
+  try {
+    detail = JSON.parse(text)?.error;
+  } catch {
+    // Not an API error document, e.g. from a proxy.
+  }
+  if (!detail || typeof detail !== "object") {
+    return new ApiError(status, "UNKNOWN", text.trim());
+  }
+  return new ApiError(
+    status,
+    detail.code ?? "UNKNOWN",
+    detail.message ?? "",
+    detail.retryable ?? false,
+    detail.request_id ?? "",
+    detail.details ?? {},
+  );
+}
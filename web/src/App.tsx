@@ -5,8 +5,11 @@ import { AppShell } from "./layouts/AppShell";
 import { useThemeStore } from "./stores/theme";
 import { AdminPage } from "./pages/Admin";
 import { DashboardPage } from "./pages/Dashboard";
+import { LanesPage } from "./pages/Lanes";
 import { MetricsPage } from "./pages/Metrics";
 import { NotFoundPage } from "./pages/NotFound";
+import { SagaDetailPage } from "./pages/SagaDetail";
+import { SagasPage } from "./pages/Sagas";
 import { WorkflowDetailPage } from "./pages/WorkflowDetail";
 import { WorkflowsPage } from "./pages/Workflows";
 
@@ -38,6 +41,9 @@ export default function App() {
           <Route index element={<DashboardPage />} />
           <Route path="workflows" element={<WorkflowsPage />} />
           <Route path="workflows/:id" element={<WorkflowDetailPage />} />
+          <Route path="sagas" element={<SagasPage />} />
+          <Route path="sagas/:id" element={<SagaDetailPage />} />
+          <Route path="lanes" element={<LanesPage />} />
           <Route path="metrics" element={<MetricsPage />} />
           <Route path="admin" element={<AdminPage />} />
           <Route path="dashboard" element={<Navigate to="/" replace />} />
@@ -0,0 +1,13 @@
+import { requestJSON } from "./client";
+import type { LaneListResponse, LaneStatsSnapshot } from "../types/api";
+
+export async function listLanes(signal?: AbortSignal): Promise<LaneListResponse> {
+  return requestJSON<LaneListResponse>("/api/v1/lanes", { method: "GET", signal });
+}
+
+export async function getLane(name: string, signal?: AbortSignal): Promise<LaneStatsSnapshot> {
+  return requestJSON<LaneStatsSnapshot>(`/api/v1/lanes/${encodeURIComponent(name)}`, {
+    method: "GET",
+    signal,
+  });
+}
@@ -0,0 +1,94 @@
+import { requestJSON } from "./client";
+import type {
+  SagaActionResponse,
+  SagaArchive,
+  SagaArchiveListResponse,
+  SagaCompensationPreview,
+  SagaDetail,
+  SagaListResponse,
+  SagaState,
+} from "../types/api";
+
+export type ListSagaParams = {
+  state?: SagaState;
+  limit?: number;
+  offset?: number;
+};
+
+export async function listSagas(
+  params: ListSagaParams = {},
+  signal?: AbortSignal
+): Promise<SagaListResponse> {
+  return requestJSON<SagaListResponse>("/api/v1/sagas", {
+    method: "GET",
+    signal,
+    query: {
+      state: params.state,
+      limit: params.limit ?? 20,
+      offset: params.offset ?? 0,
+    },
+  });
+}
+
+export async function getSaga(id: string, signal?: AbortSignal): Promise<SagaDetail> {
+  return requestJSON<SagaDetail>(`/api/v1/sagas/${encodeURIComponent(id)}`, {
+    method: "GET",
+    signal,
+  });
+}
+
+export async function listArchivedSagas(
+  params: ListSagaParams = {},
+  signal?: AbortSignal
+): Promise<SagaArchiveListResponse> {
+  return requestJSON<SagaArchiveListResponse>("/api/v1/sagas/archive", {
+    method: "GET",
+    signal,
+    query: {
+      state: params.state,
+      limit: params.limit ?? 20,
+      offset: params.offset ?? 0,
+    },
+  });
+}
+
+export async function getArchivedSaga(id: string, signal?: AbortSignal): Promise<SagaArchive> {
+  return requestJSON<SagaArchive>(`/api/v1/sagas/${encodeURIComponent(id)}/archive`, {
+    method: "GET",
+    signal,
+  });
+}
+
+export async function compensateSaga(
+  id: string,
+  reason?: string,
+  signal?: AbortSignal
+): Promise<SagaActionResponse> {
+  return requestJSON<SagaActionResponse>(`/api/v1/sagas/${encodeURIComponent(id)}/compensate`, {
+    method: "POST",
+    body: reason ? { reason } : {},
+    signal,
+  });
+}
+
+export async function previewSagaCompensation(
+  id: string,
+  signal?: AbortSignal
+): Promise<SagaCompensationPreview> {
+  return requestJSON<SagaCompensationPreview>(
+    `/api/v1/sagas/${encodeURIComponent(id)}/compensate:preview`,
+    {
+      method: "POST",
+      body: {},
+      signal,
+    }
+  );
+}
+
+export async function recoverSaga(id: string, signal?: AbortSignal): Promise<SagaActionResponse> {
+  return requestJSON<SagaActionResponse>(`/api/v1/sagas/${encodeURIComponent(id)}/recover`, {
+    method: "POST",
+    body: {},
+    signal,
+  });
+}
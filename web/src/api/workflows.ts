@@ -3,6 +3,7 @@ import type {
   SubmitWorkflowRequest,
   SubmitWorkflowResponse,
   TaskResultResponse,
+  TemplateDiff,
   WorkflowDetail,
   WorkflowListResponse,
   WorkflowState,
@@ -59,6 +60,38 @@ export async function cancelWorkflow(
   });
 }
 
+export async function retryWorkflow(
+  id: string,
+  signal?: AbortSignal
+): Promise<SubmitWorkflowResponse> {
+  return requestJSON<SubmitWorkflowResponse>(`/api/v1/workflows/${encodeURIComponent(id)}/retry`, {
+    method: "POST",
+    signal,
+  });
+}
+
+export async function approveWorkflow(id: string, signal?: AbortSignal): Promise<WorkflowDetail> {
+  return requestJSON<WorkflowDetail>(`/api/v1/workflows/${encodeURIComponent(id)}/approve`, {
+    method: "POST",
+    signal,
+  });
+}
+
+export async function diffTemplate(
+  template: string,
+  workflowID: string,
+  signal?: AbortSignal
+): Promise<TemplateDiff> {
+  return requestJSON<TemplateDiff>(
+    `/api/v1/manage/templates/${encodeURIComponent(template)}/diff`,
+    {
+      method: "GET",
+      signal,
+      query: { workflow: workflowID },
+    }
+  );
+}
+
 export async function getTaskResult(
   workflowID: string,
   taskID: string,
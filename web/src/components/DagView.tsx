@@ -15,10 +15,12 @@ import {
 } from "@xyflow/react";
 import { useEffect, useMemo, useState } from "react";
 
-import type { WorkflowState, WorkflowTask } from "../types/api";
+import type { TaskEventLogEntry, WorkflowState, WorkflowTask } from "../types/api";
+import { TaskDetailPanel } from "./TaskDetailPanel";
 
 type DagViewProps = {
   tasks: WorkflowTask[];
+  taskEvents?: Record<string, TaskEventLogEntry[]>;
 };
 
 type TaskNodeData = {
@@ -47,24 +49,6 @@ function nodeClass(status: WorkflowState) {
   }
 }
 
-function statusIcon(status: WorkflowState) {
-  switch (status) {
-    case "running":
-      return "o";
-    case "completed":
-      return "v";
-    case "failed":
-      return "x";
-    case "cancelled":
-      return "!";
-    case "pending":
-      return "...";
-    case "scheduled":
-    default:
-      return "~";
-  }
-}
-
 function createLayout(tasks: WorkflowTask[]) {
   const hasDependencies = tasks.some((task) => (task.depends_on?.length ?? 0) > 0);
 
@@ -140,7 +124,7 @@ function styleForEdge(edge: Edge, taskByID: Record<string, WorkflowTask>) {
   };
 }
 
-function DagCanvas({ tasks }: DagViewProps) {
+function DagCanvas({ tasks, taskEvents = {} }: DagViewProps) {
   const taskByID = useMemo<Record<string, WorkflowTask>>(
     () =>
       tasks.reduce<Record<string, WorkflowTask>>((acc, item) => {
@@ -231,24 +215,8 @@ function DagCanvas({ tasks }: DagViewProps) {
             Click a node to inspect task details.
           </p>
         ) : (
-          <div className="mt-3 space-y-3 text-sm">
-            <p className="font-semibold">{selectedTask.name}</p>
-            <p className="font-mono text-xs text-[var(--ui-muted)]">{selectedTask.id}</p>
-            <p className="inline-flex items-center gap-2 rounded border border-[var(--ui-border)] px-2 py-1 text-xs">
-              <span>{statusIcon(selectedTask.status)}</span>
-              <span className="capitalize">{selectedTask.status}</span>
-            </p>
-            <p className="text-xs text-[var(--ui-muted)]">
-              Dependencies:{" "}
-              {(selectedTask.depends_on ?? []).length === 0
-                ? "None"
-                : (selectedTask.depends_on ?? []).join(", ")}
-            </p>
-            {selectedTask.error ? (
-              <p className="rounded border border-red-300/50 bg-red-50/60 p-2 text-xs text-red-700 dark:border-red-500/50 dark:bg-red-900/20 dark:text-red-200">
-                {selectedTask.error}
-              </p>
-            ) : null}
+          <div className="mt-3">
+            <TaskDetailPanel task={selectedTask} events={taskEvents[selectedTask.id]} />
           </div>
         )}
       </aside>
@@ -0,0 +1,75 @@
+import {
+  CartesianGrid,
+  Legend,
+  Line,
+  LineChart,
+  ResponsiveContainer,
+  Tooltip,
+  XAxis,
+  YAxis,
+} from "recharts";
+
+import type { LaneHistoryPoint } from "../stores/lanes";
+
+export type LaneTrendSeries = {
+  key: Exclude<keyof LaneHistoryPoint, "timestamp">;
+  label: string;
+  color: string;
+};
+
+type LaneTrendChartProps = {
+  title: string;
+  data: LaneHistoryPoint[];
+  series: LaneTrendSeries[];
+  unit?: string;
+};
+
+function formatTimeTick(value: number) {
+  return new Date(value).toLocaleTimeString([], {
+    hour: "2-digit",
+    minute: "2-digit",
+    second: "2-digit",
+  });
+}
+
+export function LaneTrendChart({ title, data, series, unit = "" }: LaneTrendChartProps) {
+  return (
+    <section className="rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] p-4">
+      <h3 className="mb-3 text-sm font-semibold uppercase tracking-wide text-[var(--ui-muted)]">
+        {title}
+      </h3>
+      <div className="h-56">
+        <ResponsiveContainer width="100%" height="100%">
+          <LineChart data={data}>
+            <CartesianGrid strokeDasharray="3 3" />
+            <XAxis
+              dataKey="timestamp"
+              type="number"
+              domain={["dataMin", "dataMax"]}
+              tickFormatter={formatTimeTick}
+              minTickGap={16}
+            />
+            <YAxis />
+            <Tooltip
+              labelFormatter={(value) => new Date(Number(value)).toLocaleString()}
+              formatter={(value: number, name: string) => [`${value.toFixed(2)}${unit}`, name]}
+            />
+            <Legend />
+            {series.map((item) => (
+              <Line
+                key={item.key}
+                type="monotone"
+                dataKey={item.key}
+                name={item.label}
+                stroke={item.color}
+                strokeWidth={2}
+                dot={false}
+                isAnimationActive={false}
+              />
+            ))}
+          </LineChart>
+        </ResponsiveContainer>
+      </div>
+    </section>
+  );
+}
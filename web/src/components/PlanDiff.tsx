@@ -0,0 +1,61 @@
+import { useEffect, useState } from "react";
+
+import { diffTemplate } from "../api/workflows";
+import type { TemplateDiff } from "../types/api";
+
+type PlanDiffProps = {
+  template: string;
+  workflowID: string;
+};
+
+// PlanDiff shows how the tasks of a planned workflow differ from those of the
+// template the plan was requested against.
+export function PlanDiff({ template, workflowID }: PlanDiffProps) {
+  const [diff, setDiff] = useState<TemplateDiff | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    const controller = new AbortController();
+    setDiff(null);
+    setError(null);
+    diffTemplate(template, workflowID, controller.signal)
+      .then(setDiff)
+      .catch((err: Error) => {
+        if (!controller.signal.aborted) {
+          setError(err.message);
+        }
+      });
+    return () => controller.abort();
+  }, [template, workflowID]);
+
+  if (error) {
+    return <p className="text-xs text-red-600 dark:text-red-300">Diff unavailable: {error}</p>;
+  }
+  if (!diff) {
+    return <p className="text-xs text-[var(--ui-muted)]">Comparing with template {template}...</p>;
+  }
+  if (diff.added.length === 0 && diff.removed.length === 0 && diff.changed.length === 0) {
+    return <p className="text-xs text-[var(--ui-muted)]">Same tasks as template {template}.</p>;
+  }
+
+  return (
+    <div className="space-y-1 font-mono text-xs">
+      <p className="font-sans text-[var(--ui-muted)]">Compared with template {template}:</p>
+      {diff.added.map((id) => (
+        <p key={`+${id}`} className="text-emerald-700 dark:text-emerald-300">
+          + {id}
+        </p>
+      ))}
+      {diff.removed.map((id) => (
+        <p key={`-${id}`} className="text-red-700 dark:text-red-300">
+          - {id}
+        </p>
+      ))}
+      {diff.changed.map((task) => (
+        <p key={`~${task.id}`} className="text-amber-700 dark:text-amber-300">
+          ~ {task.id} ({task.fields.join(", ")})
+        </p>
+      ))}
+    </div>
+  );
+}
@@ -0,0 +1,93 @@
+import type { SagaDetail } from "../types/api";
+
+type SagaTimelineProps = {
+  saga: SagaDetail;
+};
+
+type StepTone = "completed" | "failed" | "compensated";
+
+const TONE_CLASS: Record<StepTone, string> = {
+  completed:
+    "border-emerald-300 bg-emerald-50 text-emerald-800 dark:border-emerald-500/60 dark:bg-emerald-900/30 dark:text-emerald-100",
+  failed:
+    "border-red-300 bg-red-50 text-red-800 dark:border-red-500/60 dark:bg-red-900/30 dark:text-red-100",
+  compensated:
+    "border-amber-300 bg-amber-50 text-amber-800 dark:border-amber-500/60 dark:bg-amber-900/30 dark:text-amber-100",
+};
+
+function StepList({
+  label,
+  steps,
+  tone,
+  empty,
+}: {
+  label: string;
+  steps: { id: string; tone: StepTone }[];
+  tone: StepTone;
+  empty: string;
+}) {
+  return (
+    <div className="space-y-2">
+      <h3 className="text-xs font-semibold uppercase tracking-wide text-[var(--ui-muted)]">
+        {label}
+      </h3>
+      {steps.length === 0 ? (
+        <p className="text-xs text-[var(--ui-muted)]">{empty}</p>
+      ) : (
+        <ol aria-label={label} className="flex flex-wrap items-center gap-2">
+          {steps.map((step, index) => (
+            <li
+              key={`${tone}-${step.id}`}
+              data-tone={step.tone}
+              className={`flex items-center gap-2 rounded-md border px-2.5 py-1 text-xs font-medium ${
+                TONE_CLASS[step.tone]
+              }`}
+            >
+              <span className="font-mono text-[10px] opacity-70">{index + 1}</span>
+              {step.id}
+            </li>
+          ))}
+        </ol>
+      )}
+    </div>
+  );
+}
+
+export function SagaTimeline({ saga }: SagaTimelineProps) {
+  const forward: { id: string; tone: StepTone }[] = (saga.completed_steps ?? []).map((id) => ({
+    id,
+    tone: "completed",
+  }));
+  if (saga.failed_step) {
+    forward.push({ id: saga.failed_step, tone: "failed" });
+  }
+  const compensation: { id: string; tone: StepTone }[] = (saga.compensated_steps ?? []).map(
+    (id) => ({ id, tone: "compensated" })
+  );
+
+  return (
+    <div className="space-y-4">
+      <StepList
+        label="Execution order"
+        steps={forward}
+        tone="completed"
+        empty="No steps have completed yet."
+      />
+      <StepList
+        label="Compensation order"
+        steps={compensation}
+        tone="compensated"
+        empty={
+          saga.state === "compensating" || saga.state === "pending-compensation"
+            ? "Compensation has not finished any step yet."
+            : "No steps were compensated."
+        }
+      />
+      {saga.failure_reason ? (
+        <p className="rounded border border-red-300/50 bg-red-50/60 p-2 text-xs text-red-700 dark:border-red-500/50 dark:bg-red-900/20 dark:text-red-200">
+          {saga.failure_reason}
+        </p>
+      ) : null}
+    </div>
+  );
+}
@@ -7,13 +7,20 @@ type StatusBadgeProps = {
 function colorClass(status: string) {
   switch (status) {
     case "running":
+    case "recovering":
       return "bg-blue-100 text-blue-700 ring-blue-300 dark:bg-blue-900/40 dark:text-blue-200";
     case "completed":
       return "bg-emerald-100 text-emerald-700 ring-emerald-300 dark:bg-emerald-900/40 dark:text-emerald-200";
     case "failed":
+    case "compensation-failed":
       return "bg-red-100 text-red-700 ring-red-300 dark:bg-red-900/40 dark:text-red-200";
     case "cancelled":
+    case "compensating":
+    case "pending-compensation":
+    case "compensated":
       return "bg-amber-100 text-amber-700 ring-amber-300 dark:bg-amber-900/40 dark:text-amber-200";
+    case "planned":
+      return "bg-violet-100 text-violet-700 ring-violet-300 dark:bg-violet-900/40 dark:text-violet-200";
     case "scheduled":
       return "bg-slate-100 text-slate-700 ring-slate-300 dark:bg-slate-700/50 dark:text-slate-200";
     case "pending":
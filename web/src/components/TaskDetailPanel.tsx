@@ -0,0 +1,131 @@
+import type { ReactNode } from "react";
+
+import type { TaskEventLogEntry, WorkflowTask } from "../types/api";
+import { StatusBadge } from "./StatusBadge";
+
+type TaskDetailPanelProps = {
+  task: WorkflowTask;
+  events?: TaskEventLogEntry[];
+  // output is what the task streamed so far, shown while it runs.
+  output?: string;
+};
+
+function formatTime(value?: string | null) {
+  if (!value) {
+    return "-";
+  }
+  const date = new Date(value);
+  return Number.isNaN(date.getTime()) ? value : date.toLocaleTimeString();
+}
+
+function JsonBlock({ value, empty }: { value: unknown; empty: string }) {
+  if (value === undefined || value === null) {
+    return <p className="text-xs text-[var(--ui-muted)]">{empty}</p>;
+  }
+  return (
+    <pre className="max-h-48 overflow-auto rounded-md border border-[var(--ui-border)] bg-black/5 p-2 text-xs dark:bg-white/5">
+      {JSON.stringify(value, null, 2)}
+    </pre>
+  );
+}
+
+function Section({ title, children }: { title: string; children: ReactNode }) {
+  return (
+    <div className="space-y-1">
+      <h4 className="text-xs font-semibold uppercase tracking-wide text-[var(--ui-muted)]">
+        {title}
+      </h4>
+      {children}
+    </div>
+  );
+}
+
+export function TaskDetailPanel({ task, events = [], output }: TaskDetailPanelProps) {
+  const dependencies = task.depends_on ?? [];
+  const hasConfig = task.config && Object.keys(task.config).length > 0;
+
+  return (
+    <div className="space-y-3 text-sm">
+      <div className="flex items-start justify-between gap-2">
+        <div>
+          <p className="font-semibold">{task.name}</p>
+          <p className="font-mono text-xs text-[var(--ui-muted)]">{task.id}</p>
+        </div>
+        <StatusBadge status={task.status} />
+      </div>
+
+      <dl className="grid grid-cols-2 gap-x-3 gap-y-1 text-xs">
+        <dt className="text-[var(--ui-muted)]">Type</dt>
+        <dd>{task.type || "-"}</dd>
+        <dt className="text-[var(--ui-muted)]">Retries</dt>
+        <dd>{task.retries ?? 0}</dd>
+        <dt className="text-[var(--ui-muted)]">Timeout</dt>
+        <dd>{task.timeout ? `${task.timeout} s` : "-"}</dd>
+        <dt className="text-[var(--ui-muted)]">Started</dt>
+        <dd>{formatTime(task.started_at)}</dd>
+        <dt className="text-[var(--ui-muted)]">Completed</dt>
+        <dd>{formatTime(task.completed_at)}</dd>
+        <dt className="text-[var(--ui-muted)]">Dependencies</dt>
+        <dd className="break-all">{dependencies.length === 0 ? "None" : dependencies.join(", ")}</dd>
+        {task.dedupe_key ? (
+          <>
+            <dt className="text-[var(--ui-muted)]">Dedupe key</dt>
+            <dd className="break-all font-mono">{task.dedupe_key}</dd>
+          </>
+        ) : null}
+        {task.preemptions ? (
+          <>
+            <dt className="text-[var(--ui-muted)]">Preemptions</dt>
+            <dd>{task.preemptions}</dd>
+          </>
+        ) : null}
+        {task.deduped_from ? (
+          <>
+            <dt className="text-[var(--ui-muted)]">Shared result of</dt>
+            <dd className="break-all font-mono">{task.deduped_from}</dd>
+          </>
+        ) : null}
+      </dl>
+
+      <Section title="Inputs">
+        <JsonBlock value={hasConfig ? task.config : undefined} empty="No input configuration" />
+      </Section>
+
+      {output && task.status === "running" ? (
+        <Section title="Live output">
+          <pre className="max-h-48 overflow-auto whitespace-pre-wrap rounded-md border border-[var(--ui-border)] bg-black/5 p-2 text-xs dark:bg-white/5">
+            {output}
+          </pre>
+        </Section>
+      ) : null}
+
+      <Section title="Output">
+        {task.error ? (
+          <p className="rounded border border-red-300/50 bg-red-50/60 p-2 text-xs text-red-700 dark:border-red-500/50 dark:bg-red-900/20 dark:text-red-200">
+            {task.error}
+          </p>
+        ) : null}
+        <JsonBlock value={task.result} empty="No result data" />
+      </Section>
+
+      <Section title="Log">
+        {events.length === 0 ? (
+          <p className="text-xs text-[var(--ui-muted)]">No state changes observed yet.</p>
+        ) : (
+          <ol className="max-h-48 space-y-1 overflow-auto font-mono text-xs">
+            {events.map((entry, index) => (
+              <li key={`${entry.timestamp}-${index}`}>
+                <span className="text-[var(--ui-muted)]">{formatTime(entry.timestamp)}</span>{" "}
+                {entry.old_state ? `${entry.old_state} -> ` : ""}
+                {entry.new_state}
+                {entry.error ? (
+                  <span className="text-red-600 dark:text-red-300"> ({entry.error})</span>
+                ) : null}
+              </li>
+            ))}
+          </ol>
+        )}
+      </Section>
+    </div>
+  );
+}
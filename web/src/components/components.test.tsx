@@ -1,7 +1,9 @@
 import { render, screen } from "@testing-library/react";
 
 import { DagView } from "./DagView";
+import { SagaTimeline } from "./SagaTimeline";
 import { StatusBadge } from "./StatusBadge";
+import { TaskDetailPanel } from "./TaskDetailPanel";
 import { ThroughputChart } from "./ThroughputChart";
 
 describe("StatusBadge", () => {
@@ -54,3 +56,66 @@ describe("DagView", () => {
     expect(screen.getByText("Task Details")).toBeInTheDocument();
   });
 });
+
+describe("TaskDetailPanel", () => {
+  it("renders inputs, output, retries and the event log", () => {
+    render(
+      <TaskDetailPanel
+        task={{
+          id: "task-1",
+          name: "Fetch",
+          status: "failed",
+          type: "http",
+          retries: 3,
+          config: { url: "https://example.com" },
+          error: "connection refused",
+        }}
+        events={[
+          {
+            timestamp: new Date().toISOString(),
+            old_state: "running",
+            new_state: "failed",
+            error: "connection refused",
+          },
+        ]}
+      />
+    );
+
+    expect(screen.getByText("Retries").nextSibling?.textContent).toBe("3");
+    expect(screen.getByText(/https:\/\/example.com/)).toBeInTheDocument();
+    expect(screen.getAllByText(/connection refused/)).toHaveLength(2);
+    expect(screen.getByText(/running -> /)).toBeInTheDocument();
+  });
+});
+
+describe("SagaTimeline", () => {
+  it("renders execution and compensation order", () => {
+    render(
+      <SagaTimeline
+        saga={{
+          saga_id: "saga-1",
+          name: "Order",
+          state: "compensated",
+          completed_steps: ["reserve", "ship"],
+          compensated_steps: ["ship", "reserve"],
+          failed_step: "charge",
+          failure_reason: "card declined",
+          created_at: new Date().toISOString(),
+          updated_at: new Date().toISOString(),
+        }}
+      />
+    );
+
+    const execution = screen.getByRole("list", { name: "Execution order" });
+    expect(Array.from(execution.querySelectorAll("li")).map((item) => item.dataset.tone)).toEqual([
+      "completed",
+      "completed",
+      "failed",
+    ]);
+    expect(execution.textContent).toBe("1reserve2ship3charge");
+
+    const compensation = screen.getByRole("list", { name: "Compensation order" });
+    expect(compensation.textContent).toBe("1ship2reserve");
+    expect(screen.getByText("card declined")).toBeInTheDocument();
+  });
+});
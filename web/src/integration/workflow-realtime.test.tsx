@@ -12,6 +12,7 @@ vi.mock("../api/workflows", () => ({
   getWorkflow: vi.fn(),
   submitWorkflow: vi.fn(),
   cancelWorkflow: vi.fn(),
+  retryWorkflow: vi.fn(),
 }));
 
 vi.mock("../stores/websocket", () => ({
@@ -13,6 +13,8 @@ type NavItem = {
 const NAV_ITEMS: NavItem[] = [
   { path: "/", label: "Dashboard", icon: "DB" },
   { path: "/workflows", label: "Workflows", icon: "WF" },
+  { path: "/sagas", label: "Sagas", icon: "SG" },
+  { path: "/lanes", label: "Lanes", icon: "LN" },
   { path: "/metrics", label: "Metrics", icon: "MX" },
   { path: "/admin", label: "Admin", icon: "AD" },
 ];
@@ -0,0 +1,166 @@
+import { useEffect, useState } from "react";
+
+import { EmptyState } from "../components/common/EmptyState";
+import { ErrorState } from "../components/common/ErrorState";
+import { Loading } from "../components/common/Loading";
+import { LaneTrendChart } from "../components/LaneTrendChart";
+import { useLaneStore } from "../stores/lanes";
+
+const POLL_INTERVAL_MS = 2000;
+
+function formatRateLimit(rate: number, tokens: number) {
+  if (rate <= 0) {
+    return "unlimited";
+  }
+  return `${rate.toFixed(1)}/s · ${Math.floor(tokens)} tokens`;
+}
+
+function formatMS(value: number) {
+  if (value < 1) {
+    return `${(value * 1000).toFixed(0)} µs`;
+  }
+  if (value < 1000) {
+    return `${value.toFixed(1)} ms`;
+  }
+  return `${(value / 1000).toFixed(2)} s`;
+}
+
+export function LanesPage() {
+  const lanes = useLaneStore((state) => state.lanes);
+  const history = useLaneStore((state) => state.history);
+  const loading = useLaneStore((state) => state.loading);
+  const error = useLaneStore((state) => state.error);
+  const loadLanes = useLaneStore((state) => state.loadLanes);
+  const [selected, setSelected] = useState<string | null>(null);
+
+  useEffect(() => {
+    void loadLanes();
+    const timer = window.setInterval(() => {
+      void loadLanes();
+    }, POLL_INTERVAL_MS);
+    return () => window.clearInterval(timer);
+  }, [loadLanes]);
+
+  const selectedName =
+    selected && lanes.some((lane) => lane.name === selected) ? selected : lanes[0]?.name;
+  const selectedHistory = selectedName ? (history[selectedName] ?? []) : [];
+  const latest = selectedHistory[selectedHistory.length - 1];
+
+  return (
+    <section className="space-y-4">
+      <header>
+        <h1 className="text-2xl font-semibold tracking-tight">Lanes</h1>
+        <p className="mt-1 text-sm text-[var(--ui-muted)]">
+          Queue depth, concurrency, throughput and backpressure per lane.
+        </p>
+      </header>
+
+      {loading ? <Loading label="Loading lanes..." skeletonRows={3} /> : null}
+      {!loading && error ? <ErrorState message={error} onRetry={() => void loadLanes()} /> : null}
+      {!loading && !error && lanes.length === 0 ? (
+        <EmptyState title="No lanes" description="The engine has not registered any lanes yet." />
+      ) : null}
+
+      {lanes.length > 0 ? (
+        <div className="overflow-x-auto rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)]">
+          <table className="min-w-full divide-y divide-[var(--ui-border)] text-sm">
+            <thead>
+              <tr className="text-left text-xs uppercase tracking-wide text-[var(--ui-muted)]">
+                <th className="px-4 py-3">Lane</th>
+                <th className="px-4 py-3">Queue</th>
+                <th className="px-4 py-3">Running</th>
+                <th className="px-4 py-3">Throughput</th>
+                <th className="px-4 py-3">Wait p50 / p95 / p99</th>
+                <th className="px-4 py-3">Dropped</th>
+                <th className="px-4 py-3">Rejected</th>
+                <th className="px-4 py-3">Rate limit</th>
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-[var(--ui-border)]">
+              {lanes.map((lane) => {
+                const points = history[lane.name] ?? [];
+                const throughput = points[points.length - 1]?.throughput ?? 0;
+                return (
+                  <tr
+                    key={lane.name}
+                    className={`cursor-pointer hover:bg-black/5 dark:hover:bg-white/5 ${
+                      lane.name === selectedName ? "bg-black/5 dark:bg-white/5" : ""
+                    }`}
+                    onClick={() => setSelected(lane.name)}
+                  >
+                    <td className="px-4 py-3 font-semibold">{lane.name}</td>
+                    <td className="px-4 py-3">
+                      {lane.pending} / {lane.capacity}
+                    </td>
+                    <td className="px-4 py-3">
+                      {lane.running} / {lane.max_concurrency}
+                    </td>
+                    <td className="px-4 py-3">{throughput.toFixed(2)}/s</td>
+                    <td className="px-4 py-3 text-[var(--ui-muted)]">
+                      {formatMS(lane.wait_p50_ms)} / {formatMS(lane.wait_p95_ms)} /{" "}
+                      {formatMS(lane.wait_p99_ms)}
+                    </td>
+                    <td className="px-4 py-3">{lane.dropped}</td>
+                    <td className="px-4 py-3">{lane.rejected}</td>
+                    <td className="px-4 py-3 text-[var(--ui-muted)]">
+                      {formatRateLimit(lane.rate_limit, lane.rate_limit_tokens)}
+                      {lane.throttled > 0 ? ` · ${lane.throttled} throttled` : ""}
+                    </td>
+                  </tr>
+                );
+              })}
+            </tbody>
+          </table>
+        </div>
+      ) : null}
+
+      {selectedName ? (
+        <div className="space-y-3">
+          <h2 className="text-lg font-semibold">
+            {selectedName}
+            {latest ? (
+              <span className="ml-2 text-sm font-normal text-[var(--ui-muted)]">
+                {selectedHistory.length} samples, every {POLL_INTERVAL_MS / 1000}s
+              </span>
+            ) : null}
+          </h2>
+          <div className="grid gap-3 xl:grid-cols-2">
+            <LaneTrendChart
+              title="Queue depth and concurrency"
+              data={selectedHistory}
+              series={[
+                { key: "pending", label: "pending", color: "#1d4ed8" },
+                { key: "running", label: "running", color: "#0f766e" },
+              ]}
+            />
+            <LaneTrendChart
+              title="Throughput"
+              data={selectedHistory}
+              unit="/s"
+              series={[{ key: "throughput", label: "tasks/s", color: "#9333ea" }]}
+            />
+            <LaneTrendChart
+              title="Queue wait time"
+              data={selectedHistory}
+              unit=" ms"
+              series={[
+                { key: "waitP50", label: "p50", color: "#0e7490" },
+                { key: "waitP95", label: "p95", color: "#b45309" },
+                { key: "waitP99", label: "p99", color: "#be123c" },
+              ]}
+            />
+            <LaneTrendChart
+              title="Backpressure"
+              data={selectedHistory}
+              series={[
+                { key: "dropped", label: "dropped", color: "#be123c" },
+                { key: "rejected", label: "rejected", color: "#9a3412" },
+                { key: "throttled", label: "throttled", color: "#4d7c0f" },
+              ]}
+            />
+          </div>
+        </div>
+      ) : null}
+    </section>
+  );
+}
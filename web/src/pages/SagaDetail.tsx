@@ -0,0 +1,168 @@
+import { useEffect, useMemo, useState } from "react";
+import { useParams } from "react-router-dom";
+
+import { ErrorState } from "../components/common/ErrorState";
+import { Loading } from "../components/common/Loading";
+import { SagaTimeline } from "../components/SagaTimeline";
+import { StatusBadge } from "../components/StatusBadge";
+import { useSagaStore } from "../stores/sagas";
+
+const NON_TERMINAL = new Set([
+  "created",
+  "running",
+  "compensating",
+  "pending-compensation",
+  "recovering",
+]);
+
+export function SagaDetailPage() {
+  const { id = "" } = useParams();
+  const saga = useSagaStore((state) => state.selectedSaga);
+  const loading = useSagaStore((state) => state.loadingDetail);
+  const error = useSagaStore((state) => state.error);
+  const loadSagaDetail = useSagaStore((state) => state.loadSagaDetail);
+  const compensateSagaByID = useSagaStore((state) => state.compensateSagaByID);
+  const recoverSagaByID = useSagaStore((state) => state.recoverSagaByID);
+  const [reason, setReason] = useState("");
+  const [pendingAction, setPendingAction] = useState<"compensate" | "recover" | null>(null);
+  const [actionError, setActionError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (!id) {
+      return;
+    }
+    void loadSagaDetail(id);
+  }, [id, loadSagaDetail]);
+
+  const current = saga && saga.saga_id === id ? saga : null;
+
+  useEffect(() => {
+    if (!current || !NON_TERMINAL.has(current.state)) {
+      return;
+    }
+    const timer = window.setInterval(() => {
+      void loadSagaDetail(id);
+    }, 2000);
+    return () => window.clearInterval(timer);
+  }, [current, id, loadSagaDetail]);
+
+  const canCompensate = useMemo(
+    () => Boolean(current && current.state === "pending-compensation"),
+    [current]
+  );
+  const canRecover = useMemo(() => Boolean(current && NON_TERMINAL.has(current.state)), [current]);
+
+  const runAction = async (action: "compensate" | "recover") => {
+    if (!id) {
+      return;
+    }
+    if (action === "recover" && !window.confirm("Resume this saga from its latest checkpoint?")) {
+      return;
+    }
+    setActionError(null);
+    setPendingAction(action);
+    try {
+      if (action === "compensate") {
+        await compensateSagaByID(id, reason.trim() || undefined);
+        setReason("");
+      } else {
+        await recoverSagaByID(id);
+      }
+    } catch (err) {
+      setActionError((err as Error).message);
+    } finally {
+      setPendingAction(null);
+    }
+  };
+
+  return (
+    <section className="space-y-4">
+      <header className="rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] p-4">
+        <div className="flex flex-wrap items-start justify-between gap-3">
+          <div>
+            <h1 className="text-xl font-semibold tracking-tight">
+              {current?.name || "Saga Detail"}
+            </h1>
+            <p className="mt-1 break-all text-xs text-[var(--ui-muted)]">ID: {id || "-"}</p>
+          </div>
+          <div className="flex items-center gap-2">
+            {current ? <StatusBadge status={current.state} /> : null}
+            {canRecover ? (
+              <button
+                type="button"
+                disabled={pendingAction !== null}
+                onClick={() => void runAction("recover")}
+                className="rounded-md border border-[var(--ui-border)] px-3 py-1.5 text-xs font-semibold hover:bg-black/5 disabled:opacity-50 dark:hover:bg-white/5"
+              >
+                Recover from Checkpoint
+              </button>
+            ) : null}
+          </div>
+        </div>
+
+        {canCompensate ? (
+          <div className="mt-3 flex flex-wrap items-center gap-2">
+            <input
+              value={reason}
+              onChange={(event) => setReason(event.target.value)}
+              placeholder="Compensation reason (optional)"
+              className="min-w-64 flex-1 rounded-md border border-[var(--ui-border)] bg-transparent px-3 py-1.5 text-sm"
+            />
+            <button
+              type="button"
+              disabled={pendingAction !== null}
+              onClick={() => void runAction("compensate")}
+              className="rounded-md border border-red-300 px-3 py-1.5 text-xs font-semibold text-red-700 hover:bg-red-50 disabled:opacity-50 dark:border-red-500/60 dark:text-red-200 dark:hover:bg-red-900/40"
+            >
+              Compensate
+            </button>
+          </div>
+        ) : null}
+
+        {actionError ? (
+          <p className="mt-3 rounded border border-red-300/50 bg-red-50/60 p-2 text-xs text-red-700 dark:border-red-500/50 dark:bg-red-900/20 dark:text-red-200">
+            {actionError}
+          </p>
+        ) : null}
+
+        {current ? (
+          <div className="mt-3 grid gap-2 text-sm text-[var(--ui-muted)] md:grid-cols-3">
+            <p>Created: {new Date(current.created_at).toLocaleString()}</p>
+            <p>
+              Started: {current.started_at ? new Date(current.started_at).toLocaleString() : "-"}
+            </p>
+            <p>
+              Completed:{" "}
+              {current.completed_at ? new Date(current.completed_at).toLocaleString() : "-"}
+            </p>
+          </div>
+        ) : null}
+      </header>
+
+      {loading && !current ? <Loading label="Loading saga detail..." skeletonRows={4} /> : null}
+      {!loading && error ? (
+        <ErrorState message={error} onRetry={() => void loadSagaDetail(id)} />
+      ) : null}
+
+      {current ? (
+        <div className="grid gap-4 lg:grid-cols-[minmax(0,1fr)_360px]">
+          <div className="rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] p-4">
+            <SagaTimeline saga={current} />
+          </div>
+          <div className="space-y-2 rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] p-4">
+            <h3 className="text-xs font-semibold uppercase tracking-wide text-[var(--ui-muted)]">
+              Step results
+            </h3>
+            {current.step_results && Object.keys(current.step_results).length > 0 ? (
+              <pre className="max-h-96 overflow-auto rounded-md border border-[var(--ui-border)] bg-black/5 p-3 text-xs dark:bg-white/5">
+                {JSON.stringify(current.step_results, null, 2)}
+              </pre>
+            ) : (
+              <p className="text-xs text-[var(--ui-muted)]">No step results recorded.</p>
+            )}
+          </div>
+        </div>
+      ) : null}
+    </section>
+  );
+}
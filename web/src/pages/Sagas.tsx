@@ -0,0 +1,151 @@
+import { useEffect, useMemo } from "react";
+import { Link, useNavigate } from "react-router-dom";
+
+import { EmptyState } from "../components/common/EmptyState";
+import { ErrorState } from "../components/common/ErrorState";
+import { Loading } from "../components/common/Loading";
+import { StatusBadge } from "../components/StatusBadge";
+import { useSagaStore } from "../stores/sagas";
+
+const NON_TERMINAL = new Set([
+  "created",
+  "running",
+  "compensating",
+  "pending-compensation",
+  "recovering",
+]);
+
+export function SagasPage() {
+  const navigate = useNavigate();
+  const sagas = useSagaStore((state) => state.sagas);
+  const total = useSagaStore((state) => state.total);
+  const limit = useSagaStore((state) => state.limit);
+  const offset = useSagaStore((state) => state.offset);
+  const loading = useSagaStore((state) => state.loadingList);
+  const error = useSagaStore((state) => state.error);
+  const stateFilter = useSagaStore((state) => state.stateFilter);
+  const loadSagas = useSagaStore((state) => state.loadSagas);
+  const setPage = useSagaStore((state) => state.setPage);
+  const setStateFilter = useSagaStore((state) => state.setStateFilter);
+
+  useEffect(() => {
+    void loadSagas();
+  }, [loadSagas, offset, stateFilter]);
+
+  useEffect(() => {
+    if (!sagas.some((item) => NON_TERMINAL.has(item.state))) {
+      return;
+    }
+    const timer = window.setInterval(() => {
+      void loadSagas();
+    }, 2000);
+    return () => window.clearInterval(timer);
+  }, [sagas, loadSagas]);
+
+  const pageIndex = useMemo(() => Math.floor(offset / limit), [offset, limit]);
+  const hasPrev = offset > 0;
+  const hasNext = offset + limit < total;
+
+  return (
+    <section className="space-y-4">
+      <header>
+        <h1 className="text-2xl font-semibold tracking-tight">Sagas</h1>
+        <p className="mt-1 text-sm text-[var(--ui-muted)]">
+          Track saga instances, their compensations and checkpoint recovery.
+        </p>
+      </header>
+
+      <div className="rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] p-3">
+        <select
+          className="w-full rounded-md border border-[var(--ui-border)] bg-transparent px-3 py-2 text-sm md:w-64"
+          value={stateFilter}
+          onChange={(event) => setStateFilter(event.target.value as typeof stateFilter)}
+        >
+          <option value="all">All states</option>
+          <option value="created">Created</option>
+          <option value="running">Running</option>
+          <option value="completed">Completed</option>
+          <option value="compensating">Compensating</option>
+          <option value="pending-compensation">Pending compensation</option>
+          <option value="compensated">Compensated</option>
+          <option value="compensation-failed">Compensation failed</option>
+          <option value="recovering">Recovering</option>
+        </select>
+      </div>
+
+      {loading ? <Loading label="Loading sagas..." skeletonRows={5} /> : null}
+      {!loading && error ? <ErrorState message={error} onRetry={() => void loadSagas()} /> : null}
+      {!loading && !error && sagas.length === 0 ? (
+        <EmptyState
+          title="No sagas found"
+          description="Try another state filter, or submit a saga through the API."
+        />
+      ) : null}
+
+      {!loading && !error && sagas.length > 0 ? (
+        <div className="overflow-x-auto rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)]">
+          <table className="min-w-full divide-y divide-[var(--ui-border)] text-sm">
+            <thead>
+              <tr className="text-left text-xs uppercase tracking-wide text-[var(--ui-muted)]">
+                <th className="px-4 py-3">ID</th>
+                <th className="px-4 py-3">Name</th>
+                <th className="px-4 py-3">State</th>
+                <th className="px-4 py-3">Created</th>
+                <th className="px-4 py-3">Completed</th>
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-[var(--ui-border)]">
+              {sagas.map((saga) => (
+                <tr
+                  key={saga.saga_id}
+                  className="cursor-pointer hover:bg-black/5 dark:hover:bg-white/5"
+                  onClick={() => navigate(`/sagas/${saga.saga_id}`)}
+                >
+                  <td className="px-4 py-3 font-mono text-xs">
+                    <Link to={`/sagas/${saga.saga_id}`} className="hover:underline">
+                      {saga.saga_id}
+                    </Link>
+                  </td>
+                  <td className="px-4 py-3 font-medium">{saga.name}</td>
+                  <td className="px-4 py-3">
+                    <StatusBadge status={saga.state} />
+                  </td>
+                  <td className="px-4 py-3 text-[var(--ui-muted)]">
+                    {new Date(saga.created_at).toLocaleString()}
+                  </td>
+                  <td className="px-4 py-3 text-[var(--ui-muted)]">
+                    {saga.completed_at ? new Date(saga.completed_at).toLocaleString() : "-"}
+                  </td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      ) : null}
+
+      <footer className="flex items-center justify-between rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)] px-4 py-3 text-sm">
+        <p className="text-[var(--ui-muted)]">
+          Page {pageIndex + 1} · {total} total
+        </p>
+        <div className="flex gap-2">
+          <button
+            type="button"
+            disabled={!hasPrev}
+            onClick={() => setPage(pageIndex - 1)}
+            className="rounded-md border border-[var(--ui-border)] px-3 py-1.5 disabled:opacity-50"
+          >
+            Previous
+          </button>
+          <button
+            type="button"
+            disabled={!hasNext}
+            onClick={() => setPage(pageIndex + 1)}
+            className="rounded-md border border-[var(--ui-border)] px-3 py-1.5 disabled:opacity-50"
+          >
+            Next
+          </button>
+        </div>
+      </footer>
+    </section>
+  );
+}
@@ -1,15 +1,20 @@
 import { Fragment, useEffect, useMemo, useState } from "react";
-import { useParams } from "react-router-dom";
+import { useNavigate, useParams } from "react-router-dom";
 
 import { EmptyState } from "../components/common/EmptyState";
 import { ErrorState } from "../components/common/ErrorState";
 import { Loading } from "../components/common/Loading";
 import { DagView } from "../components/DagView";
+import { PlanDiff } from "../components/PlanDiff";
 import { StatusBadge } from "../components/StatusBadge";
+import { TaskDetailPanel } from "../components/TaskDetailPanel";
 import { useWorkflowStore } from "../stores/workflows";
 import { useWebSocketStore } from "../stores/websocket";
 
 const NON_TERMINAL = new Set(["pending", "scheduled", "running"]);
+const RETRYABLE = new Set(["failed", "cancelled"]);
+const PLAN_TEMPLATE = "goclaw.io/plan-template";
+const PLANNED_WORKFLOW = "goclaw.io/planned-workflow";
 
 function formatDuration(start?: string | null, end?: string | null) {
   if (!start || !end) {
@@ -27,15 +32,21 @@ function formatDuration(start?: string | null, end?: string | null) {
 
 export function WorkflowDetailPage() {
   const { id = "" } = useParams();
+  const navigate = useNavigate();
   const workflow = useWorkflowStore((state) => state.selectedWorkflow);
+  const taskEvents = useWorkflowStore((state) => state.taskEvents);
+  const taskOutputs = useWorkflowStore((state) => state.taskOutputs);
   const loading = useWorkflowStore((state) => state.loadingDetail);
   const error = useWorkflowStore((state) => state.error);
   const loadWorkflowDetail = useWorkflowStore((state) => state.loadWorkflowDetail);
   const cancelWorkflowByID = useWorkflowStore((state) => state.cancelWorkflowByID);
+  const retryWorkflowByID = useWorkflowStore((state) => state.retryWorkflowByID);
+  const approveWorkflowByID = useWorkflowStore((state) => state.approveWorkflowByID);
   const subscribeWorkflow = useWebSocketStore((state) => state.subscribeWorkflow);
   const unsubscribeWorkflow = useWebSocketStore((state) => state.unsubscribeWorkflow);
   const [expandedTaskID, setExpandedTaskID] = useState<string | null>(null);
   const [activeTab, setActiveTab] = useState<"tasks" | "dag">("tasks");
+  const [actionError, setActionError] = useState<string | null>(null);
 
   useEffect(() => {
     if (!id) {
@@ -69,6 +80,43 @@ export function WorkflowDetailPage() {
     [workflow, id]
   );
 
+  const canRetry = useMemo(
+    () => Boolean(workflow && RETRYABLE.has(workflow.status) && id),
+    [workflow, id]
+  );
+
+  const awaitingApproval = workflow?.status === "planned";
+  const planTemplate = workflow?.metadata?.[PLAN_TEMPLATE];
+  const plannedWorkflowID = workflow?.metadata?.[PLANNED_WORKFLOW];
+
+  const onApprove = async () => {
+    if (!id || !awaitingApproval) {
+      return;
+    }
+    setActionError(null);
+    try {
+      await approveWorkflowByID(id);
+    } catch (err) {
+      setActionError((err as Error).message);
+    }
+  };
+
+  const onReject = async () => {
+    if (!id || !awaitingApproval) {
+      return;
+    }
+    const confirmed = window.confirm("Reject this plan?");
+    if (!confirmed) {
+      return;
+    }
+    setActionError(null);
+    try {
+      await cancelWorkflowByID(id);
+    } catch (err) {
+      setActionError((err as Error).message);
+    }
+  };
+
   const onCancel = async () => {
     if (!id || !canCancel) {
       return;
@@ -77,7 +125,25 @@ export function WorkflowDetailPage() {
     if (!confirmed) {
       return;
     }
-    await cancelWorkflowByID(id);
+    setActionError(null);
+    try {
+      await cancelWorkflowByID(id);
+    } catch (err) {
+      setActionError((err as Error).message);
+    }
+  };
+
+  const onRetry = async () => {
+    if (!id || !canRetry) {
+      return;
+    }
+    setActionError(null);
+    try {
+      const retriedID = await retryWorkflowByID(id);
+      navigate(`/workflows/${encodeURIComponent(retriedID)}`);
+    } catch (err) {
+      setActionError((err as Error).message);
+    }
   };
 
   return (
@@ -92,6 +158,24 @@ export function WorkflowDetailPage() {
           </div>
           <div className="flex items-center gap-2">
             {workflow ? <StatusBadge status={workflow.status} /> : null}
+            {awaitingApproval ? (
+              <>
+                <button
+                  type="button"
+                  onClick={() => void onApprove()}
+                  className="rounded-md border border-emerald-300 px-3 py-1.5 text-xs font-semibold text-emerald-700 hover:bg-emerald-50 dark:border-emerald-500/60 dark:text-emerald-200 dark:hover:bg-emerald-900/40"
+                >
+                  Approve Plan
+                </button>
+                <button
+                  type="button"
+                  onClick={() => void onReject()}
+                  className="rounded-md border border-red-300 px-3 py-1.5 text-xs font-semibold text-red-700 hover:bg-red-50 dark:border-red-500/60 dark:text-red-200 dark:hover:bg-red-900/40"
+                >
+                  Reject Plan
+                </button>
+              </>
+            ) : null}
             {canCancel ? (
               <button
                 type="button"
@@ -101,9 +185,24 @@ export function WorkflowDetailPage() {
                 Cancel Workflow
               </button>
             ) : null}
+            {canRetry ? (
+              <button
+                type="button"
+                onClick={() => void onRetry()}
+                className="rounded-md border border-[var(--ui-border)] px-3 py-1.5 text-xs font-semibold hover:bg-black/5 dark:hover:bg-white/5"
+              >
+                Retry Workflow
+              </button>
+            ) : null}
           </div>
         </div>
 
+        {actionError ? (
+          <p className="mt-3 rounded border border-red-300/50 bg-red-50/60 p-2 text-xs text-red-700 dark:border-red-500/50 dark:bg-red-900/20 dark:text-red-200">
+            {actionError}
+          </p>
+        ) : null}
+
         {workflow ? (
           <div className="mt-3 grid gap-2 text-sm text-[var(--ui-muted)] md:grid-cols-3">
             <p>Created: {new Date(workflow.created_at).toLocaleString()}</p>
@@ -117,6 +216,28 @@ export function WorkflowDetailPage() {
           </div>
         ) : null}
 
+        {awaitingApproval ? (
+          <div className="mt-3 rounded-md border border-violet-300/60 bg-violet-50/60 p-3 dark:border-violet-500/40 dark:bg-violet-900/20">
+            <p className="mb-2 text-sm">
+              This workflow was proposed by a planner and runs only once approved. Review its tasks
+              and DAG below.
+            </p>
+            {planTemplate ? <PlanDiff template={planTemplate} workflowID={id} /> : null}
+          </div>
+        ) : null}
+
+        {plannedWorkflowID ? (
+          <p className="mt-3 text-sm">
+            <button
+              type="button"
+              onClick={() => navigate(`/workflows/${encodeURIComponent(plannedWorkflowID)}`)}
+              className="font-semibold text-[var(--ui-accent)] hover:underline"
+            >
+              Review the proposed workflow
+            </button>
+          </p>
+        ) : null}
+
         {workflow?.metadata ? (
           <pre className="mt-3 overflow-auto rounded-md border border-[var(--ui-border)] bg-black/5 p-3 text-xs dark:bg-white/5">
             {JSON.stringify(workflow.metadata, null, 2)}
@@ -162,7 +283,9 @@ export function WorkflowDetailPage() {
             </button>
           </div>
 
-          {activeTab === "dag" ? <DagView tasks={workflow.tasks} /> : null}
+          {activeTab === "dag" ? (
+            <DagView tasks={workflow.tasks} taskEvents={taskEvents} />
+          ) : null}
 
           {activeTab === "tasks" ? (
             <div className="overflow-x-auto rounded-xl border border-[var(--ui-border)] bg-[var(--ui-panel)]">
@@ -200,13 +323,9 @@ export function WorkflowDetailPage() {
                       {expandedTaskID === task.id ? (
                         <tr>
                           <td className="px-4 pb-4" colSpan={5}>
-                            <pre className="overflow-auto rounded-md border border-[var(--ui-border)] bg-black/5 p-3 text-xs dark:bg-white/5">
-                              {JSON.stringify(
-                                task.result ?? { message: "No result data" },
-                                null,
-                                2
-                              )}
-                            </pre>
+                            <div className="rounded-md border border-[var(--ui-border)] p-3">
+                              <TaskDetailPanel task={task} events={taskEvents[task.id]} output={taskOutputs[task.id]} />
+                            </div>
                           </td>
                         </tr>
                       ) : null}
@@ -69,6 +69,7 @@ export function WorkflowsPage() {
           onChange={(event) => setStatusFilter(event.target.value as typeof statusFilter)}
         >
           <option value="all">All statuses</option>
+          <option value="planned">Awaiting approval</option>
           <option value="pending">Pending</option>
           <option value="scheduled">Scheduled</option>
           <option value="running">Running</option>
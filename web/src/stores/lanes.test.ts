@@ -0,0 +1,121 @@
+vi.mock("../api/lanes", () => ({
+  listLanes: vi.fn(),
+}));
+
+import { listLanes } from "../api/lanes";
+import type { LaneStatsSnapshot } from "../types/api";
+import { appendLaneHistory, LANE_HISTORY_LIMIT, useLaneStore } from "./lanes";
+
+const mockedListLanes = vi.mocked(listLanes);
+
+function snapshot(overrides: Partial<LaneStatsSnapshot> = {}): LaneStatsSnapshot {
+  return {
+    name: "default",
+    pending: 0,
+    running: 0,
+    completed: 0,
+    failed: 0,
+    dropped: 0,
+    accepted: 0,
+    rejected: 0,
+    redirected: 0,
+    capacity: 100,
+    max_concurrency: 4,
+    utilization: 0,
+    wait_time_ms: 0,
+    wait_p50_ms: 0,
+    wait_p95_ms: 0,
+    wait_p99_ms: 0,
+    process_time_ms: 0,
+    rate_limit: 0,
+    rate_limit_tokens: 0,
+    throttled: 0,
+    deadline_missed: 0,
+    ...overrides,
+  };
+}
+
+describe("appendLaneHistory", () => {
+  it("derives throughput and drops from successive snapshots", () => {
+    const first = appendLaneHistory({}, [], null, [snapshot({ completed: 10 })], 1000);
+    expect(first.default[0]).toMatchObject({ throughput: 0, dropped: 0 });
+
+    const second = appendLaneHistory(
+      first,
+      [snapshot({ completed: 10 })],
+      1000,
+      [
+        snapshot({
+          completed: 16,
+          failed: 4,
+          dropped: 3,
+          rejected: 1,
+          throttled: 2,
+          wait_p99_ms: 12,
+        }),
+      ],
+      3000
+    );
+    expect(second.default).toHaveLength(2);
+    expect(second.default[1]).toMatchObject({
+      throughput: 5,
+      dropped: 3,
+      rejected: 1,
+      throttled: 2,
+      waitP99: 12,
+    });
+  });
+
+  it("caps history and forgets removed lanes", () => {
+    let history = appendLaneHistory({}, [], null, [snapshot(), snapshot({ name: "io" })], 0);
+    for (let i = 1; i <= LANE_HISTORY_LIMIT + 5; i++) {
+      history = appendLaneHistory(history, [snapshot()], i * 1000, [snapshot()], (i + 1) * 1000);
+    }
+    expect(history.default).toHaveLength(LANE_HISTORY_LIMIT);
+    expect(history.io).toBeUndefined();
+  });
+});
+
+describe("lane store", () => {
+  beforeEach(() => {
+    mockedListLanes.mockReset();
+    useLaneStore.setState({
+      lanes: [],
+      history: {},
+      collectedAt: null,
+      loading: false,
+      error: null,
+    });
+  });
+
+  it("loads lanes and records history", async () => {
+    mockedListLanes.mockResolvedValueOnce({
+      lanes: [snapshot({ completed: 2 })],
+      count: 1,
+      collected_at: "2026-01-01T00:00:00Z",
+    });
+    mockedListLanes.mockResolvedValueOnce({
+      lanes: [snapshot({ completed: 6 })],
+      count: 1,
+      collected_at: "2026-01-01T00:00:02Z",
+    });
+
+    await useLaneStore.getState().loadLanes();
+    await useLaneStore.getState().loadLanes();
+
+    const state = useLaneStore.getState();
+    expect(state.lanes[0].completed).toBe(6);
+    expect(state.history.default.map((point) => point.throughput)).toEqual([0, 2]);
+  });
+
+  it("keeps the last snapshot when a poll fails", async () => {
+    useLaneStore.setState({ lanes: [snapshot()], collectedAt: 1000 });
+    mockedListLanes.mockRejectedValue(new Error("network down"));
+
+    await useLaneStore.getState().loadLanes();
+
+    const state = useLaneStore.getState();
+    expect(state.error).toBe("network down");
+    expect(state.lanes).toHaveLength(1);
+  });
+});
@@ -0,0 +1,92 @@
+import { create } from "zustand";
+
+import { listLanes } from "../api/lanes";
+import type { LaneStatsSnapshot } from "../types/api";
+
+export type LaneHistoryPoint = {
+  timestamp: number;
+  pending: number;
+  running: number;
+  throughput: number;
+  dropped: number;
+  rejected: number;
+  throttled: number;
+  waitP50: number;
+  waitP95: number;
+  waitP99: number;
+};
+
+type LaneStoreState = {
+  lanes: LaneStatsSnapshot[];
+  history: Record<string, LaneHistoryPoint[]>;
+  collectedAt: number | null;
+  loading: boolean;
+  error: string | null;
+  loadLanes: () => Promise<void>;
+};
+
+export const LANE_HISTORY_LIMIT = 150;
+
+function perSecond(current: number, previous: number, seconds: number) {
+  if (seconds <= 0 || current < previous) {
+    return 0;
+  }
+  return (current - previous) / seconds;
+}
+
+export function appendLaneHistory(
+  history: Record<string, LaneHistoryPoint[]>,
+  previous: LaneStatsSnapshot[],
+  previousAt: number | null,
+  lanes: LaneStatsSnapshot[],
+  collectedAt: number
+): Record<string, LaneHistoryPoint[]> {
+  const seconds = previousAt === null ? 0 : (collectedAt - previousAt) / 1000;
+  const byName = new Map(previous.map((lane) => [lane.name, lane]));
+  const next: Record<string, LaneHistoryPoint[]> = {};
+
+  for (const lane of lanes) {
+    const prior = byName.get(lane.name);
+    const point: LaneHistoryPoint = {
+      timestamp: collectedAt,
+      pending: lane.pending,
+      running: lane.running,
+      throughput: prior
+        ? perSecond(lane.completed + lane.failed, prior.completed + prior.failed, seconds)
+        : 0,
+      dropped: prior ? Math.max(lane.dropped - prior.dropped, 0) : 0,
+      rejected: prior ? Math.max(lane.rejected - prior.rejected, 0) : 0,
+      throttled: prior ? Math.max(lane.throttled - prior.throttled, 0) : 0,
+      waitP50: lane.wait_p50_ms,
+      waitP95: lane.wait_p95_ms,
+      waitP99: lane.wait_p99_ms,
+    };
+    next[lane.name] = [...(history[lane.name] ?? []), point].slice(-LANE_HISTORY_LIMIT);
+  }
+  return next;
+}
+
+export const useLaneStore = create<LaneStoreState>((set, get) => ({
+  lanes: [],
+  history: {},
+  collectedAt: null,
+  loading: false,
+  error: null,
+  loadLanes: async () => {
+    set({ loading: get().collectedAt === null, error: null });
+    try {
+      const response = await listLanes();
+      const collectedAt = new Date(response.collected_at).getTime();
+      const { history, lanes, collectedAt: previousAt } = get();
+      set({
+        lanes: response.lanes,
+        history: appendLaneHistory(history, lanes, previousAt, response.lanes, collectedAt),
+        collectedAt,
+        loading: false,
+        error: null,
+      });
+    } catch (err) {
+      set({ loading: false, error: (err as Error).message });
+    }
+  },
+}));
@@ -0,0 +1,106 @@
+vi.mock("../api/sagas", () => ({
+  listSagas: vi.fn(),
+  getSaga: vi.fn(),
+  compensateSaga: vi.fn(),
+  recoverSaga: vi.fn(),
+}));
+
+import { compensateSaga, getSaga, listSagas, recoverSaga } from "../api/sagas";
+import type { SagaDetail, SagaSummary } from "../types/api";
+import { useSagaStore } from "./sagas";
+
+const mockedListSagas = vi.mocked(listSagas);
+const mockedGetSaga = vi.mocked(getSaga);
+const mockedCompensateSaga = vi.mocked(compensateSaga);
+const mockedRecoverSaga = vi.mocked(recoverSaga);
+
+const baseSaga: SagaSummary = {
+  saga_id: "saga-1",
+  name: "Order",
+  state: "pending-compensation",
+  created_at: new Date().toISOString(),
+};
+
+const baseDetail: SagaDetail = {
+  saga_id: "saga-1",
+  name: "Order",
+  state: "pending-compensation",
+  completed_steps: ["reserve"],
+  compensated_steps: [],
+  failed_step: "charge",
+  created_at: new Date().toISOString(),
+  updated_at: new Date().toISOString(),
+};
+
+describe("saga store", () => {
+  beforeEach(() => {
+    mockedListSagas.mockReset();
+    mockedGetSaga.mockReset();
+    mockedCompensateSaga.mockReset();
+    mockedRecoverSaga.mockReset();
+    useSagaStore.setState({
+      sagas: [],
+      total: 0,
+      limit: 20,
+      offset: 0,
+      stateFilter: "all",
+      selectedSaga: null,
+      loadingList: false,
+      loadingDetail: false,
+      error: null,
+    });
+  });
+
+  it("loads sagas filtered by state", async () => {
+    mockedListSagas.mockResolvedValue({ items: [baseSaga], total: 1, limit: 20, offset: 0 });
+
+    useSagaStore.getState().setStateFilter("pending-compensation");
+    await useSagaStore.getState().loadSagas();
+
+    const next = useSagaStore.getState();
+    expect(mockedListSagas).toHaveBeenCalledWith({
+      limit: 20,
+      offset: 0,
+      state: "pending-compensation",
+    });
+    expect(next.sagas).toHaveLength(1);
+    expect(next.total).toBe(1);
+  });
+
+  it("records list errors", async () => {
+    mockedListSagas.mockRejectedValue(new Error("saga orchestrator unavailable"));
+
+    await useSagaStore.getState().loadSagas();
+
+    expect(useSagaStore.getState().error).toBe("saga orchestrator unavailable");
+    expect(useSagaStore.getState().loadingList).toBe(false);
+  });
+
+  it("compensates a saga and refreshes detail and list", async () => {
+    useSagaStore.setState({ selectedSaga: baseDetail });
+    mockedCompensateSaga.mockResolvedValue({ saga_id: "saga-1", state: "compensated" });
+    mockedGetSaga.mockResolvedValue({
+      ...baseDetail,
+      state: "compensated",
+      compensated_steps: ["reserve"],
+    });
+    mockedListSagas.mockResolvedValue({ items: [], total: 0, limit: 20, offset: 0 });
+
+    const state = await useSagaStore.getState().compensateSagaByID("saga-1", "manual");
+
+    expect(state).toBe("compensated");
+    expect(mockedCompensateSaga).toHaveBeenCalledWith("saga-1", "manual");
+    expect(mockedGetSaga).toHaveBeenCalledWith("saga-1");
+    expect(useSagaStore.getState().selectedSaga?.compensated_steps).toEqual(["reserve"]);
+    expect(mockedListSagas).toHaveBeenCalledTimes(1);
+  });
+
+  it("propagates recovery errors", async () => {
+    mockedRecoverSaga.mockRejectedValue(new Error("checkpoint not found"));
+
+    await expect(useSagaStore.getState().recoverSagaByID("saga-1")).rejects.toThrow(
+      "checkpoint not found"
+    );
+    expect(mockedListSagas).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,83 @@
+import { create } from "zustand";
+
+import { compensateSaga, getSaga, listSagas, recoverSaga } from "../api/sagas";
+import type { SagaDetail, SagaListResponse, SagaState, SagaSummary } from "../types/api";
+
+type SagaStoreState = {
+  sagas: SagaSummary[];
+  total: number;
+  limit: number;
+  offset: number;
+  stateFilter: SagaState | "all";
+  selectedSaga: SagaDetail | null;
+  loadingList: boolean;
+  loadingDetail: boolean;
+  error: string | null;
+  setStateFilter: (state: SagaState | "all") => void;
+  setPage: (pageIndex: number) => void;
+  loadSagas: () => Promise<void>;
+  loadSagaDetail: (sagaID: string) => Promise<void>;
+  compensateSagaByID: (sagaID: string, reason?: string) => Promise<SagaState>;
+  recoverSagaByID: (sagaID: string) => Promise<SagaState>;
+};
+
+export const useSagaStore = create<SagaStoreState>((set, get) => {
+  const refresh = async (sagaID: string) => {
+    if (get().selectedSaga?.saga_id === sagaID) {
+      await get().loadSagaDetail(sagaID);
+    }
+    await get().loadSagas();
+  };
+
+  return {
+    sagas: [],
+    total: 0,
+    limit: 20,
+    offset: 0,
+    stateFilter: "all",
+    selectedSaga: null,
+    loadingList: false,
+    loadingDetail: false,
+    error: null,
+    setStateFilter: (stateFilter) => set({ stateFilter, offset: 0 }),
+    setPage: (pageIndex) => set({ offset: Math.max(pageIndex, 0) * get().limit }),
+    loadSagas: async () => {
+      set({ loadingList: true, error: null });
+      try {
+        const stateFilter = get().stateFilter;
+        const response: SagaListResponse = await listSagas({
+          limit: get().limit,
+          offset: get().offset,
+          state: stateFilter === "all" ? undefined : stateFilter,
+        });
+        set({
+          sagas: response.items,
+          total: response.total,
+          loadingList: false,
+          error: null,
+        });
+      } catch (err) {
+        set({ loadingList: false, error: (err as Error).message });
+      }
+    },
+    loadSagaDetail: async (sagaID) => {
+      set({ loadingDetail: true, error: null });
+      try {
+        const saga = await getSaga(sagaID);
+        set({ selectedSaga: saga, loadingDetail: false, error: null });
+      } catch (err) {
+        set({ loadingDetail: false, error: (err as Error).message });
+      }
+    },
+    compensateSagaByID: async (sagaID, reason) => {
+      const response = await compensateSaga(sagaID, reason);
+      await refresh(sagaID);
+      return response.state;
+    },
+    recoverSagaByID: async (sagaID) => {
+      const response = await recoverSaga(sagaID);
+      await refresh(sagaID);
+      return response.state;
+    },
+  };
+});
@@ -3,6 +3,7 @@ import type { WebSocketEventMessage } from "../lib/websocket";
 const wsMock = vi.hoisted(() => ({
   workflowStateHandler: null as ((event: WebSocketEventMessage) => void) | null,
   taskStateHandler: null as ((event: WebSocketEventMessage) => void) | null,
+  taskOutputHandler: null as ((event: WebSocketEventMessage) => void) | null,
 }));
 
 vi.mock("../api/workflows", () => ({
@@ -10,6 +11,8 @@ vi.mock("../api/workflows", () => ({
   getWorkflow: vi.fn(),
   submitWorkflow: vi.fn(),
   cancelWorkflow: vi.fn(),
+  retryWorkflow: vi.fn(),
+  approveWorkflow: vi.fn(),
 }));
 
 vi.mock("./websocket", () => ({
@@ -22,18 +25,23 @@ vi.mock("./websocket", () => ({
         if (eventType === "task.state_changed") {
           wsMock.taskStateHandler = handler;
         }
+        if (eventType === "task.output") {
+          wsMock.taskOutputHandler = handler;
+        }
         return () => {};
       },
     }),
   },
 }));
 
-import { listWorkflows, submitWorkflow } from "../api/workflows";
+import { approveWorkflow, listWorkflows, retryWorkflow, submitWorkflow } from "../api/workflows";
 import type { WorkflowDetail, WorkflowSummary } from "../types/api";
 import { useWorkflowStore } from "./workflows";
 
 const mockedListWorkflows = vi.mocked(listWorkflows);
 const mockedSubmitWorkflow = vi.mocked(submitWorkflow);
+const mockedRetryWorkflow = vi.mocked(retryWorkflow);
+const mockedApproveWorkflow = vi.mocked(approveWorkflow);
 
 const baseWorkflow: WorkflowSummary = {
   id: "wf-1",
@@ -61,6 +69,7 @@ describe("workflow store", () => {
   beforeEach(() => {
     mockedListWorkflows.mockReset();
     mockedSubmitWorkflow.mockReset();
+    mockedRetryWorkflow.mockReset();
     useWorkflowStore.setState({
       workflows: [],
       total: 0,
@@ -69,6 +78,8 @@ describe("workflow store", () => {
       search: "",
       statusFilter: "all",
       selectedWorkflow: null,
+      taskEvents: {},
+      taskOutputs: {},
       loadingList: false,
       loadingDetail: false,
       error: null,
@@ -149,4 +160,83 @@ describe("workflow store", () => {
     expect(state.selectedWorkflow?.tasks[0].status).toBe("completed");
     expect(state.selectedWorkflow?.tasks[0].result).toEqual({ ok: true });
   });
+
+  it("records task state changes in the task event log", () => {
+    useWorkflowStore.setState({ selectedWorkflow: baseDetail });
+
+    for (const [oldState, newState] of [
+      ["pending", "running"],
+      ["running", "failed"],
+    ]) {
+      wsMock.taskStateHandler?.({
+        type: "task.state_changed",
+        timestamp: new Date().toISOString(),
+        payload: {
+          workflow_id: "wf-1",
+          task_id: "task-1",
+          old_state: oldState,
+          new_state: newState,
+          error: newState === "failed" ? "boom" : undefined,
+        },
+      });
+    }
+
+    const events = useWorkflowStore.getState().taskEvents["task-1"];
+    expect(events).toHaveLength(2);
+    expect(events[0]).toMatchObject({ old_state: "pending", new_state: "running" });
+    expect(events[1]).toMatchObject({ new_state: "failed", error: "boom" });
+  });
+
+  it("accumulates streamed task output and restarts it with each attempt", () => {
+    useWorkflowStore.setState({ selectedWorkflow: baseDetail });
+
+    const stream = (seq: number, chunk: string) =>
+      wsMock.taskOutputHandler?.({
+        type: "task.output",
+        timestamp: new Date().toISOString(),
+        payload: { workflow_id: "wf-1", task_id: "task-1", seq, chunk },
+      });
+
+    stream(1, "Hel");
+    stream(2, "lo");
+    expect(useWorkflowStore.getState().taskOutputs["task-1"]).toBe("Hello");
+
+    stream(1, "Retry");
+    expect(useWorkflowStore.getState().taskOutputs["task-1"]).toBe("Retry");
+  });
+
+  it("retries a workflow and returns the new workflow ID", async () => {
+    mockedRetryWorkflow.mockResolvedValue({
+      id: "wf-2",
+      name: "Workflow 1",
+      status: "pending",
+    });
+    mockedListWorkflows.mockResolvedValue({
+      workflows: [baseWorkflow],
+      total: 1,
+      limit: 20,
+      offset: 0,
+    });
+
+    const workflowID = await useWorkflowStore.getState().retryWorkflowByID("wf-1");
+
+    expect(workflowID).toBe("wf-2");
+    expect(mockedRetryWorkflow).toHaveBeenCalledWith("wf-1");
+    expect(mockedListWorkflows).toHaveBeenCalledTimes(1);
+  });
+
+  it("approves a planned workflow and reloads the list", async () => {
+    mockedApproveWorkflow.mockResolvedValue({ ...baseDetail, status: "pending" });
+    mockedListWorkflows.mockResolvedValue({
+      workflows: [baseWorkflow],
+      total: 1,
+      limit: 20,
+      offset: 0,
+    });
+
+    await useWorkflowStore.getState().approveWorkflowByID("wf-1");
+
+    expect(mockedApproveWorkflow).toHaveBeenCalledWith("wf-1");
+    expect(mockedListWorkflows).toHaveBeenCalledTimes(1);
+  });
 });
@@ -1,8 +1,16 @@
 import { create } from "zustand";
 
-import { cancelWorkflow, getWorkflow, listWorkflows, submitWorkflow } from "../api/workflows";
+import {
+  approveWorkflow,
+  cancelWorkflow,
+  getWorkflow,
+  listWorkflows,
+  retryWorkflow,
+  submitWorkflow,
+} from "../api/workflows";
 import type {
   SubmitWorkflowRequest,
+  TaskEventLogEntry,
   WorkflowDetail,
   WorkflowListResponse,
   WorkflowState,
@@ -19,6 +27,10 @@ type WorkflowStoreState = {
   search: string;
   statusFilter: WorkflowState | "all";
   selectedWorkflow: WorkflowDetail | null;
+  taskEvents: Record<string, TaskEventLogEntry[]>;
+  // taskOutputs holds the output running tasks of the selected workflow
+  // stream, by task ID.
+  taskOutputs: Record<string, string>;
   loadingList: boolean;
   loadingDetail: boolean;
   error: string | null;
@@ -29,8 +41,13 @@ type WorkflowStoreState = {
   loadWorkflowDetail: (workflowID: string) => Promise<void>;
   submitWorkflowJSON: (json: string) => Promise<string>;
   cancelWorkflowByID: (workflowID: string) => Promise<void>;
+  retryWorkflowByID: (workflowID: string) => Promise<string>;
+  approveWorkflowByID: (workflowID: string) => Promise<void>;
 };
 
+const MAX_TASK_EVENTS = 50;
+const MAX_TASK_OUTPUT = 64 * 1024;
+
 let subscribedToWS = false;
 
 function applyWorkflowStateEvent(state: WorkflowStoreState, event: WebSocketEventMessage) {
@@ -74,15 +91,40 @@ function applyTaskStateEvent(state: WorkflowStoreState, event: WebSocketEventMes
       : task
   );
 
+  const entry: TaskEventLogEntry = {
+    timestamp: String(payload.updated_at ?? event.timestamp),
+    old_state: payload.old_state ? String(payload.old_state) : undefined,
+    new_state: nextState,
+    error: payload.error ? String(payload.error) : undefined,
+  };
+  const taskEvents = {
+    ...state.taskEvents,
+    [taskID]: [...(state.taskEvents[taskID] ?? []), entry].slice(-MAX_TASK_EVENTS),
+  };
+
   return {
     ...state,
     selectedWorkflow: {
       ...state.selectedWorkflow,
       tasks,
     },
+    taskEvents,
   };
 }
 
+function applyTaskOutputEvent(state: WorkflowStoreState, event: WebSocketEventMessage) {
+  const payload = event.payload as Record<string, unknown>;
+  const workflowID = String(payload.workflow_id ?? "");
+  const taskID = String(payload.task_id ?? "");
+  if (!taskID || !state.selectedWorkflow || state.selectedWorkflow.id !== workflowID) {
+    return state;
+  }
+  // A new attempt starts its output over.
+  const previous = Number(payload.seq) === 1 ? "" : (state.taskOutputs[taskID] ?? "");
+  const output = (previous + String(payload.chunk ?? "")).slice(-MAX_TASK_OUTPUT);
+  return { ...state, taskOutputs: { ...state.taskOutputs, [taskID]: output } };
+}
+
 function registerWebSocketBridge(
   set: (updater: (state: WorkflowStoreState) => WorkflowStoreState) => void
 ) {
@@ -98,6 +140,9 @@ function registerWebSocketBridge(
   ws.onEvent("task.state_changed", (event) => {
     set((state) => applyTaskStateEvent(state, event));
   });
+  ws.onEvent("task.output", (event) => {
+    set((state) => applyTaskOutputEvent(state, event));
+  });
 }
 
 export const useWorkflowStore = create<WorkflowStoreState>((set, get) => {
@@ -111,6 +156,8 @@ export const useWorkflowStore = create<WorkflowStoreState>((set, get) => {
     search: "",
     statusFilter: "all",
     selectedWorkflow: null,
+    taskEvents: {},
+    taskOutputs: {},
     loadingList: false,
     loadingDetail: false,
     error: null,
@@ -141,7 +188,10 @@ export const useWorkflowStore = create<WorkflowStoreState>((set, get) => {
       set({ loadingDetail: true, error: null });
       try {
         const workflow = await getWorkflow(workflowID);
-        set({ selectedWorkflow: workflow, loadingDetail: false, error: null });
+        const same = get().selectedWorkflow?.id === workflowID;
+        const taskEvents = same ? get().taskEvents : {};
+        const taskOutputs = same ? get().taskOutputs : {};
+        set({ selectedWorkflow: workflow, taskEvents, taskOutputs, loadingDetail: false, error: null });
       } catch (err) {
         set({ loadingDetail: false, error: (err as Error).message });
       }
@@ -157,6 +207,13 @@ export const useWorkflowStore = create<WorkflowStoreState>((set, get) => {
       await get().loadWorkflows();
       return response.id;
     },
+    approveWorkflowByID: async (workflowID) => {
+      await approveWorkflow(workflowID);
+      if (get().selectedWorkflow?.id === workflowID) {
+        await get().loadWorkflowDetail(workflowID);
+      }
+      await get().loadWorkflows();
+    },
     cancelWorkflowByID: async (workflowID) => {
       await cancelWorkflow(workflowID);
       if (get().selectedWorkflow?.id === workflowID) {
@@ -164,5 +221,10 @@ export const useWorkflowStore = create<WorkflowStoreState>((set, get) => {
       }
       await get().loadWorkflows();
     },
+    retryWorkflowByID: async (workflowID) => {
+      const response = await retryWorkflow(workflowID);
+      await get().loadWorkflows();
+      return response.id;
+    },
   };
 });
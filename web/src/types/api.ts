@@ -1,4 +1,5 @@
 export type WorkflowState =
+  | "planned"
   | "pending"
   | "scheduled"
   | "running"
@@ -13,17 +14,79 @@ export interface WorkflowSummary {
   created_at: string;
   completed_at?: string | null;
   task_count: number;
+  sla?: SLAStatus;
+  cost?: RunCost;
+}
+
+export interface SLAStatus {
+  deadline: string;
+  state: "pending" | "met" | "unmet" | "breached";
+  breached_at?: string | null;
+}
+
+export interface RunCost {
+  tenant?: string;
+  usage?: Record<string, number>;
+  total: number;
+  currency: string;
+}
+
+export interface Budget {
+  tokens?: number;
+  cost?: number;
+  task_seconds?: number;
+}
+
+export interface CostGroup {
+  key: string;
+  runs: number;
+  usage: Record<string, number>;
+  total: number;
+}
+
+export interface CostReport {
+  group_by: "tenant" | "workflow";
+  from?: string | null;
+  to?: string | null;
+  currency: string;
+  groups: CostGroup[];
+  total: number;
 }
 
 export interface WorkflowTask {
   id: string;
   name: string;
   status: WorkflowState;
+  type?: string;
   depends_on?: string[];
+  config?: Record<string, unknown>;
+  timeout?: number;
+  retries?: number;
   started_at?: string | null;
   completed_at?: string | null;
   error?: string;
   result?: unknown;
+  dedupe_key?: string;
+  deduped_from?: string;
+  preemptions?: number;
+  deadline_missed?: boolean;
+  stalls?: number;
+  skipped?: boolean;
+  inputs?: TaskInput[];
+  sensitive?: boolean;
+}
+
+export interface TaskInput {
+  workflow_id: string;
+  task_id: string;
+  artifact?: string;
+}
+
+export interface TaskEventLogEntry {
+  timestamp: string;
+  old_state?: string;
+  new_state: WorkflowState;
+  error?: string;
 }
 
 export interface WorkflowDetail {
@@ -36,6 +99,40 @@ export interface WorkflowDetail {
   tasks: WorkflowTask[];
   metadata?: Record<string, string>;
   error?: string;
+  deadline?: string | null;
+  request_id?: string;
+  sla?: SLAStatus;
+  cost?: RunCost;
+  budget?: Budget;
+  simulation?: SimulationReport;
+}
+
+export interface SimulatedLayer {
+  index: number;
+  tasks: string[];
+  start_ms: number;
+  duration_ms: number;
+  resources?: TaskResources;
+}
+
+export interface SimulatedTask {
+  id: string;
+  name: string;
+  lane: string;
+  layer: number;
+  start_ms: number;
+  duration_ms: number;
+  estimate: "history" | "lane" | "none";
+  samples?: number;
+  resources?: TaskResources;
+}
+
+export interface SimulationReport {
+  layers: SimulatedLayer[];
+  tasks: SimulatedTask[];
+  critical_path: string[];
+  estimated_duration_ms: number;
+  peak_resources?: TaskResources;
 }
 
 export interface WorkflowListResponse {
@@ -48,11 +145,49 @@ export interface WorkflowListResponse {
 export interface SubmitTaskDefinition {
   id: string;
   name: string;
-  type: "http" | "script" | "function";
+  type: "http" | "script" | "function" | "container";
   depends_on?: string[];
   config?: Record<string, unknown>;
   timeout?: number;
   retries?: number;
+  dedupe_key?: string;
+  preemptible?: boolean;
+  deadline?: number;
+  gang?: string;
+  affinity_key?: string;
+  locks?: string[];
+  rate_limits?: string[];
+  output_schema?: Record<string, unknown>;
+  when?: string;
+  retry_if?: string;
+  compensation?: TaskCompensation;
+  heartbeat_timeout?: number;
+  stall_policy?: "mark" | "retry" | "fail";
+  env?: string[];
+  secrets?: Record<string, string>;
+  resources?: TaskResources;
+  container?: ContainerSpec;
+  sensitive?: boolean;
+}
+
+export interface TaskCompensation {
+  type?: "http" | "script" | "function" | "container";
+  config?: Record<string, unknown>;
+  container?: ContainerSpec;
+  timeout?: number;
+}
+
+export interface ContainerSpec {
+  image: string;
+  command?: string[];
+  args?: string[];
+  env?: Record<string, string>;
+}
+
+export interface TaskResources {
+  cpu?: number;
+  memory_mb?: number;
+  gpu?: number;
 }
 
 export interface SubmitWorkflowRequest {
@@ -60,6 +195,14 @@ export interface SubmitWorkflowRequest {
   description?: string;
   tasks: SubmitTaskDefinition[];
   metadata?: Record<string, string>;
+  priority?: number;
+  deadline?: number;
+  gang_layers?: boolean;
+  sla?: {
+    max_duration?: number;
+    finish_by?: string;
+  };
+  simulate?: boolean;
 }
 
 export interface SubmitWorkflowResponse {
@@ -69,6 +212,19 @@ export interface SubmitWorkflowResponse {
   message?: string;
 }
 
+export interface TaskDiff {
+  id: string;
+  fields: string[];
+}
+
+export interface TemplateDiff {
+  template: string;
+  workflow_id: string;
+  added: string[];
+  removed: string[];
+  changed: TaskDiff[];
+}
+
 export interface TaskResultResponse {
   workflow_id: string;
   task_id: string;
@@ -78,6 +234,95 @@ export interface TaskResultResponse {
   completed_at?: string | null;
 }
 
+export type SagaState =
+  | "created"
+  | "running"
+  | "completed"
+  | "compensating"
+  | "pending-compensation"
+  | "compensated"
+  | "compensation-failed"
+  | "recovering";
+
+export interface SagaSummary {
+  saga_id: string;
+  name: string;
+  state: SagaState;
+  created_at: string;
+  completed_at?: string | null;
+}
+
+export interface SagaListResponse {
+  items: SagaSummary[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface SagaDetail {
+  saga_id: string;
+  name: string;
+  state: SagaState;
+  completed_steps: string[] | null;
+  compensated_steps: string[] | null;
+  failed_step?: string;
+  failure_reason?: string;
+  step_results?: Record<string, unknown>;
+  created_at: string;
+  updated_at: string;
+  started_at?: string | null;
+  completed_at?: string | null;
+  archived?: boolean;
+}
+
+export interface SagaWALEntry {
+  sequence: number;
+  step_id?: string;
+  type: string;
+  data?: string;
+  timestamp: string;
+}
+
+export interface SagaArchive extends SagaDetail {
+  wal: SagaWALEntry[];
+  archived_at: string;
+}
+
+export interface SagaArchiveSummary extends SagaSummary {
+  archived_at: string;
+}
+
+export interface SagaArchiveListResponse {
+  items: SagaArchiveSummary[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface SagaCompensationStep {
+  step_id: string;
+  stage: number;
+  policy: "auto" | "manual" | "skip";
+  timeout_ms: number;
+  skip_reason?: string;
+}
+
+export interface SagaCompensationPreview {
+  saga_id: string;
+  state: SagaState;
+  policy: "auto" | "manual" | "skip";
+  failed_step?: string;
+  can_trigger: boolean;
+  max_retries: number;
+  steps: SagaCompensationStep[];
+  skipped: SagaCompensationStep[];
+}
+
+export interface SagaActionResponse {
+  saga_id: string;
+  state: SagaState;
+}
+
 export interface EngineStatus {
   state: "idle" | "running" | "stopped" | "error" | "unknown";
   uptime?: string;
@@ -95,6 +340,97 @@ export interface LaneStats {
   error_rate: number;
 }
 
+export interface LaneStatsSnapshot {
+  name: string;
+  pending: number;
+  running: number;
+  completed: number;
+  failed: number;
+  dropped: number;
+  accepted: number;
+  rejected: number;
+  redirected: number;
+  capacity: number;
+  max_concurrency: number;
+  utilization: number;
+  wait_time_ms: number;
+  wait_p50_ms: number;
+  wait_p95_ms: number;
+  wait_p99_ms: number;
+  process_time_ms: number;
+  rate_limit: number;
+  rate_limit_tokens: number;
+  throttled: number;
+  deadline_missed: number;
+  resources?: TaskResources;
+  resources_in_use?: TaskResources;
+}
+
+export interface LaneListResponse {
+  lanes: LaneStatsSnapshot[];
+  count: number;
+  collected_at: string;
+}
+
+export interface DurationStats {
+  workflow: string;
+  task?: string;
+  samples: number;
+  mean_ms: number;
+  stddev_ms: number;
+  p50_ms: number;
+  p95_ms: number;
+  p99_ms: number;
+  anomalies: number;
+}
+
+export interface DurationAnomaly {
+  workflow_id: string;
+  workflow: string;
+  task?: string;
+  duration_ms: number;
+  mean_ms: number;
+  stddev_ms: number;
+  z_score: number;
+  percentile: number;
+  reason: "z_score" | "percentile";
+  detected_at: string;
+}
+
+export interface InsightsResponse {
+  durations: DurationStats[];
+  anomalies: DurationAnomaly[];
+  collected_at: string;
+}
+
+export interface Artifact {
+  workflow_id: string;
+  task_id: string;
+  name: string;
+  size: number;
+  sha256: string;
+  content_type?: string;
+  created_at: string;
+  expires_at?: string;
+}
+
+export interface ArtifactListResponse {
+  artifacts: Artifact[];
+  count: number;
+}
+
+export interface TaskLogLine {
+  seq: number;
+  time: string;
+  stream: "stdout" | "stderr";
+  text: string;
+}
+
+export interface TaskLogsResponse {
+  lines: TaskLogLine[];
+  next: number;
+}
+
 export interface AdminDebugInfo {
   generated_at: string;
   goroutines?: string;